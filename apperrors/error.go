@@ -192,6 +192,12 @@ const (
 	// ErrExhaustedAccount indicates that all possible addresses for an account
 	// have been derived and no more can be created.
 	ErrExhaustedAccount
+
+	// ErrChainFault describes chain data provided by the consensus RPC server
+	// which failed validation, such as block headers with insufficient proof
+	// of work or which do not connect to their parents.  The data is not
+	// recorded.
+	ErrChainFault
 )
 
 // E describes an application-level error.  An error code is provided to
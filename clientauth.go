@@ -0,0 +1,194 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// clientCertPerms maps client certificates, identified by their subject common
+// name or by the SHA-256 fingerprint of the DER certificate, to whether they
+// are restricted to read-only methods.  A nil map grants every certificate
+// signed by the client CA full permission.
+type clientCertPerms struct {
+	byName        map[string]bool
+	byFingerprint map[string]bool
+}
+
+// errUnknownClientCert describes a client certificate signed by the client CA
+// but missing from the permission file.
+var errUnknownClientCert = errors.New("client certificate has no permissions")
+
+// loadClientCertPerms reads a client certificate permission file.  Each line
+// names a certificate by its subject common name, or by "sha256:" followed by
+// the hex fingerprint of the certificate, followed by the permission "full"
+// or "readonly".  Blank lines and lines beginning with '#' are ignored.
+func loadClientCertPerms(path string) (*clientCertPerms, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	perms := &clientCertPerms{
+		byName:        make(map[string]bool),
+		byFingerprint: make(map[string]bool),
+	}
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected certificate and "+
+				"permission", path, line)
+		}
+		// Common names may contain spaces, so the permission is the last
+		// field.
+		id := strings.TrimSpace(strings.TrimSuffix(text, fields[len(fields)-1]))
+		var readOnly bool
+		switch fields[len(fields)-1] {
+		case "full":
+		case "readonly":
+			readOnly = true
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown permission %q", path,
+				line, fields[len(fields)-1])
+		}
+		if strings.HasPrefix(id, "sha256:") {
+			fp := strings.ToLower(strings.TrimPrefix(id, "sha256:"))
+			b, err := hex.DecodeString(fp)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("%s:%d: invalid certificate "+
+					"fingerprint", path, line)
+			}
+			perms.byFingerprint[fp] = readOnly
+			continue
+		}
+		perms.byName[id] = readOnly
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+// AuthorizeClient returns whether the client presenting cert is restricted to
+// read-only methods.  Certificates matched by fingerprint take precedence over
+// those matched by common name, and certificates matching neither are
+// rejected.  This implements the legacyrpc.ClientAuthorizer interface.
+func (p *clientCertPerms) AuthorizeClient(cert *x509.Certificate) (readOnly bool, err error) {
+	if p == nil {
+		return false, nil
+	}
+	fp := sha256.Sum256(cert.Raw)
+	if readOnly, ok := p.byFingerprint[hex.EncodeToString(fp[:])]; ok {
+		return readOnly, nil
+	}
+	if readOnly, ok := p.byName[cert.Subject.CommonName]; ok {
+		return readOnly, nil
+	}
+	return false, errUnknownClientCert
+}
+
+// clientTLSConfig adds the verification of client certificates signed by the
+// root certificates of the client CA file to the TLS configuration of the RPC
+// servers.  The configuration is unmodified when the client CA file is unset.
+func clientTLSConfig(tlsConfig *tls.Config) error {
+	if cfg.ClientCAFile == "" {
+		return nil
+	}
+	certs, err := ioutil.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certs) {
+		return fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return nil
+}
+
+// readOnlyGRPCMethods are the gRPC methods which may be invoked by clients
+// with read-only certificates.  None of these modify the wallet or reveal
+// private keys.
+var readOnlyGRPCMethods = map[string]bool{
+	"/walletrpc.VersionService/Version":                    true,
+	"/walletrpc.WalletService/Ping":                        true,
+	"/walletrpc.WalletService/Network":                     true,
+	"/walletrpc.WalletService/AccountNumber":               true,
+	"/walletrpc.WalletService/Accounts":                    true,
+	"/walletrpc.WalletService/Balance":                     true,
+	"/walletrpc.WalletService/GetTransaction":              true,
+	"/walletrpc.WalletService/GetTransactions":             true,
+	"/walletrpc.WalletService/GetTransactionsStream":       true,
+	"/walletrpc.WalletService/GetTickets":                  true,
+	"/walletrpc.WalletService/TicketPrice":                 true,
+	"/walletrpc.WalletService/StakeInfo":                   true,
+	"/walletrpc.WalletService/BlockInfo":                   true,
+	"/walletrpc.WalletService/BestBlock":                   true,
+	"/walletrpc.WalletService/TransactionNotifications":    true,
+	"/walletrpc.WalletService/AccountNotifications":        true,
+	"/walletrpc.WalletService/ConfirmationNotifications":   true,
+	"/walletrpc.WalletService/MainTipChangedNotifications": true,
+	"/walletrpc.WalletService/ValidateAddress":             true,
+	"/walletrpc.WalletService/CommittedTickets":            true,
+	"/walletrpc.WalletLoaderService/WalletExists":          true,
+	"/walletrpc.AgendaService/Agendas":                     true,
+	"/walletrpc.VotingService/VoteChoices":                 true,
+	"/walletrpc.TicketService/TicketNotifications":         true,
+	"/walletrpc.MessageVerificationService/VerifyMessage":  true,
+	"/walletrpc.DecodeMessageService/DecodeRawTransaction": true,
+}
+
+// authorizeGRPCClient checks the permission of the client certificate of a
+// gRPC request, returning a PermissionDenied error if the certificate is
+// unknown or restricted to read-only methods and method modifies the wallet.
+// Requests are not checked when client certificates are not required, nor
+// when made over unix domain sockets, which are served without TLS.
+func authorizeGRPCClient(ctx context.Context, perms *clientCertPerms, method string) error {
+	if cfg.ClientCAFile == "" {
+		return nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "unknown peer")
+	}
+	if isUnixAddr(p.Addr) {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return status.Errorf(codes.Unauthenticated, "no client certificate")
+	}
+	readOnly, err := perms.AuthorizeClient(info.State.PeerCertificates[0])
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	if readOnly && !readOnlyGRPCMethods[method] {
+		return status.Errorf(codes.PermissionDenied,
+			"method unavailable to read-only clients")
+	}
+	return nil
+}
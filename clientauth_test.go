@@ -0,0 +1,123 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePermsFile(t *testing.T, dir, contents string) string {
+	path := filepath.Join(dir, "clientperms")
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClientCertPerms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientauth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert := func(name string, raw byte) *x509.Certificate {
+		return &x509.Certificate{
+			Raw:     []byte{raw},
+			Subject: pkix.Name{CommonName: name},
+		}
+	}
+	fingerprint := func(c *x509.Certificate) string {
+		fp := sha256.Sum256(c.Raw)
+		return hex.EncodeToString(fp[:])
+	}
+	pinned := cert("monitor", 1)
+
+	path := writePermsFile(t, dir, `
+# Comments and blank lines are ignored.
+admin full
+monitor readonly
+build server   readonly
+sha256:`+fingerprint(pinned)+` full
+`)
+	perms, err := loadClientCertPerms(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		readOnly bool
+		unknown  bool
+	}{
+		{"full", cert("admin", 2), false, false},
+		{"read-only", cert("monitor", 2), true, false},
+		{"name with spaces", cert("build server", 2), true, false},
+		{"fingerprint before name", pinned, false, false},
+		{"unknown", cert("guest", 2), false, true},
+	}
+	for _, test := range tests {
+		readOnly, err := perms.AuthorizeClient(test.cert)
+		if test.unknown {
+			if err != errUnknownClientCert {
+				t.Errorf("%s: error %v, want %v", test.name, err,
+					errUnknownClientCert)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if readOnly != test.readOnly {
+			t.Errorf("%s: read-only %v, want %v", test.name, readOnly,
+				test.readOnly)
+		}
+	}
+
+	// Without a permission file, every certificate has full permission.
+	var none *clientCertPerms
+	readOnly, err := none.AuthorizeClient(cert("guest", 2))
+	if err != nil || readOnly {
+		t.Errorf("certificate without permission file: read-only %v, "+
+			"error %v", readOnly, err)
+	}
+}
+
+func TestLoadInvalidClientCertPerms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientauth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"missing permission", "admin\n"},
+		{"unknown permission", "admin write\n"},
+		{"invalid fingerprint", "sha256:abcd full\n"},
+		{"non-hex fingerprint", "sha256:" + strings.Repeat("zz", 32) + " full\n"},
+	}
+	for _, test := range tests {
+		path := writePermsFile(t, dir, test.contents)
+		if _, err := loadClientCertPerms(path); err == nil {
+			t.Errorf("%s: loaded invalid permission file", test.name)
+		}
+	}
+	if _, err := loadClientCertPerms(filepath.Join(dir, "missing")); err == nil {
+		t.Error("loaded missing permission file")
+	}
+}
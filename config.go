@@ -9,19 +9,24 @@ package main
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/eventbus"
 	"github.com/HcashOrg/hcwallet/internal/cfgutil"
 	"github.com/HcashOrg/hcwallet/netparams"
 	"github.com/HcashOrg/hcwallet/ticketbuyer"
 	"github.com/HcashOrg/hcwallet/wallet"
 	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/walletdb"
 	"github.com/btcsuite/btclog"
 	flags "github.com/jessevdk/go-flags"
 )
@@ -32,8 +37,13 @@ const (
 	defaultLogLevel            = "info"
 	defaultLogDirname          = "logs"
 	defaultLogFilename         = "hcwallet.log"
+	defaultLogFormat           = "text"
+	defaultLogMaxSize          = 10 * 1024 // KB
+	defaultLogMaxRolls         = 3
 	defaultRPCMaxClients       = 10
 	defaultRPCMaxWebsockets    = 25
+	defaultRPCMaxClientReqs    = 20
+	defaultUnixSocketPerm      = "0600"
 	defaultEnableTicketBuyer   = false
 	defaultEnableOmni          = false
 	defaultEnableVoting        = false
@@ -46,8 +56,13 @@ const (
 	defaultPass                = ""
 	defaultPromptPublicPass    = false
 	defaultAddrIdxScanLen      = wallet.DefaultGapLimit
+	defaultGapLimit            = wallet.DefaultGapLimit
+	defaultGapLimitLookahead   = wallet.DefaultGapLimit
 	defaultStakePoolColdExtKey = ""
 	defaultAllowHighFees       = false
+	defaultDBDriver            = "bdb"
+	defaultReplicaInterval     = 5 * time.Second
+	defaultNATSSubject         = "hcwallet"
 
 	// ticket buyer options
 	defaultMaxFee                    hcutil.Amount = 1e6
@@ -66,6 +81,10 @@ const (
 	defaultPriceTarget                             = 0
 	defaultBalanceToMaintainAbsolute               = 0
 	defaultBalanceToMaintainRelative               = 0.3
+	defaultHealthMaxBlocksBehind                   = 6
+	defaultWebhookConfs                            = 6
+	defaultWebhookMaxAttempts                      = 20
+	defaultFiatCurrency                            = "USD"
 
 	walletDbName = "wallet.db"
 )
@@ -86,18 +105,26 @@ type config struct {
 	Create             bool                    `long:"create" description:"Create the wallet if it does not exist"`
 	CreateTemp         bool                    `long:"createtemp" description:"Create a temporary simulation wallet (pass=password) in the data directory indicated; must call with --datadir"`
 	CreateWatchingOnly bool                    `long:"createwatchingonly" description:"Create the wallet and instantiate it as watching only with an HD extended pubkey"`
+	MigrateFrom        string                  `long:"migratefrom" description:"Path to a seed and account metadata export of a dcrwallet or btcwallet style HD wallet to recreate with --create"`
+	EncryptDB          bool                    `long:"encryptdb" description:"Encrypt the database of created wallets with the public passphrase, which is then required to open the wallet"`
+	DBDriver           string                  `long:"dbdriver" description:"Database driver of the wallet database {bdb, sqlite}; sqlite requires building with -tags sqlite"`
+	CheckDB            bool                    `long:"checkdb" description:"Verify the integrity of the wallet database, compact it if no inconsistencies are found, and exit"`
 	AppDataDir         *cfgutil.ExplicitString `short:"A" long:"appdata" description:"Application data directory for wallet config, databases and logs"`
 	TestNet            bool                    `long:"testnet" description:"Use the test network"`
 	SimNet             bool                    `long:"simnet" description:"Use the simulation test network"`
 	NoInitialLoad      bool                    `long:"noinitialload" description:"Defer wallet creation/opening on startup and enable loading wallets over RPC"`
 	DebugLevel         string                  `short:"d" long:"debuglevel" description:"Logging level {trace, debug, info, warn, error, critical}"`
 	LogDir             *cfgutil.ExplicitString `long:"logdir" description:"Directory to log output."`
+	LogFormat          string                  `long:"logformat" description:"Log output format {text, json}"`
+	LogMaxSize         int64                   `long:"logmaxsize" description:"Size in kilobytes after which the log file is rolled"`
+	LogMaxRolls        int                     `long:"logmaxrolls" description:"Maximum number of rolled log files to keep"`
 	Profile            []string                `long:"profile" description:"Enable HTTP profiling this interface/port"`
 	MemProfile         string                  `long:"memprofile" description:"Write mem profile to the specified file"`
 	RollbackTest       bool                    `long:"rollbacktest" description:"Rollback testing is a simnet testing mode that eventually stops wallet and examines wtxmgr database integrity"`
 	AutomaticRepair    bool                    `long:"automaticrepair" description:"Attempt to repair the wallet automatically if a database inconsistency is found"`
 
 	// Wallet options
+	Wallets             []string             `long:"wallet" description:"Name of an additional wallet to load from <appdata>/wallets/<name> and serve at the /wallet/<name> JSON-RPC path (may be repeated); omni is only enabled for the default wallet"`
 	WalletPass          string               `long:"walletpass" default-mask:"-" description:"The public wallet password -- Only required if the wallet was created with one"`
 	PromptPass          bool                 `long:"promptpass" description:"The private wallet password is prompted for at start up, so the wallet starts unlocked without a time limit"`
 	Pass                string               `long:"pass" description:"The private wallet passphrase"`
@@ -105,18 +132,49 @@ type config struct {
 	DisallowFree        bool                 `long:"disallowfree" description:"Force transactions to always include a fee"`
 	EnableTicketBuyer   bool                 `long:"enableticketbuyer" description:"Enable the automatic ticket buyer"`
 	EnableOmni          bool                 `long:"enableomni" description:"Enable the automatic ticket buyer"`
+	OmniPendingExpiry   int32                `long:"omnipendingexpiry" description:"Reverse pending omni balance changes of transactions which remain unmined after this many blocks (0 to only reverse those of transactions removed from the wallet)"`
 	EnableVoting        bool                 `long:"enablevoting" description:"Enable creation of votes and revocations for owned tickets"`
+	VoteDelay           time.Duration        `long:"votedelay" description:"Publish each vote after a random delay of up to this duration (e.g. 5s, at most 10s), so that the propagation timing and order of votes does not identify the wallet (0 to publish immediately)"`
+	SkipStaleVotes      bool                 `long:"skipstalevotes" description:"Do not create votes while the stake version of the majority of recent blocks is newer than the wallet's vote version"`
 	ReuseAddresses      bool                 `long:"reuseaddresses" description:"Reuse addresses for ticket purchase to cut down on address overuse"`
+	AddressReusePolicy  string               `long:"addressreusepolicy" description:"Handling of external addresses paid by more than one transaction {ignore, warn, flag, refuse}; each policy includes the previous ones: warn logs reuse, flag marks reused addresses in listunspent and listreceivedbyaddress, and refuse never returns a paid address from getaccountaddress"`
 	PurchaseAccount     string               `long:"purchaseaccount" description:"Name of the account to buy tickets from"`
+	TicketDenominations bool                 `long:"ticketdenominations" description:"Split and merge the outputs of purchaseaccount, such as ticket change and vote rewards, into outputs which each fund a single ticket at the current price as blocks are connected (requires an unlocked wallet)"`
 	TicketAddress       *cfgutil.AddressFlag `long:"ticketaddress" description:"Send all ticket outputs to this address (P2PKH or P2SH only)"`
 	SubsidyAddress      *cfgutil.AddressFlag `long:"subsidyaddress" description:"Send all stake subsidy to this address (P2PKH or P2SH only)"`
 	PoolAddress         *cfgutil.AddressFlag `long:"pooladdress" description:"The ticket pool address where ticket fees will go to"`
 	PoolFees            float64              `long:"poolfees" description:"The per-ticket fee mandated by the ticket pool as a percent (e.g. 1.00 for 1.00% fee)"`
-	AddrIdxScanLen      int                  `long:"addridxscanlen" description:"The width of the scan for last used addresses on wallet restore and start up"`
+	AddrIdxScanLen      int                  `long:"addridxscanlen" description:"DEPRECATED -- use gaplimit"`
+	GapLimit            int                  `long:"gaplimit" description:"Number of unused addresses past the last used address of each account branch to scan for on wallet restore and start up, and to watch for transactions"`
+	GapLimitLookahead   uint32               `long:"gaplimitlookahead" description:"Number of addresses beyond the gap limit to watch for transactions, finding funds sent to addresses derived far ahead from an account xpub"`
 	StakePoolColdExtKey string               `long:"stakepoolcoldextkey" description:"Enables the wallet as a stake pool with an extended key in the format of \"xpub...:index\" to derive cold wallet addresses to send fees to"`
-	AllowHighFees       bool                 `long:"allowhighfees" description:"Force the RPC client to use the 'allowHighFees' flag when sending transactions"`
+	AllowHighFees       bool                 `long:"allowhighfees" description:"Default for the 'allowHighFees' flag when sending transactions; may be overridden by individual RPC requests"`
+	SkipPreflight       bool                 `long:"skippreflight" description:"Send transactions to the consensus RPC server without first validating their scripts against the outputs they spend"`
 	RelayFee            *cfgutil.AmountFlag  `long:"txfee" description:"Sets the wallet's tx fee per kb"`
+	MaxTxInputs         int                  `long:"maxtxinputs" description:"Maximum number of inputs spent by an authored transaction (0 for no limit)"`
+	MaxTxSize           int                  `long:"maxtxsize" description:"Maximum estimated size in bytes of an authored transaction (0 for no limit)"`
+	SplitTxs            bool                 `long:"splittxs" description:"Split consolidations which exceed --maxtxinputs or --maxtxsize into multiple transactions instead of failing"`
+	ConsolidateDust     bool                 `long:"consolidatedust" description:"Automatically consolidate the dust outputs of an account holding more than dustconsolidatecount outputs below dustthreshold while mempool fees are low (requires an unlocked wallet)"`
+	DustThreshold       *cfgutil.AmountFlag  `long:"dustthreshold" description:"Value below which an output is consolidated as dust"`
+	DustMinCount        int                  `long:"dustconsolidatecount" description:"Number of dust outputs an account must exceed before they are consolidated"`
+	DustMaxFeeRate      *cfgutil.AmountFlag  `long:"dustconsolidatemaxfeerate" description:"Highest median mempool fee rate per kb at which dust is consolidated (0 for the relay fee)"`
+	RescanBlocksPerSec  int                  `long:"rescanblockspersec" description:"Maximum average number of blocks rescanned per second (0 for no limit)"`
+	RescanPauseRPCLoad  int                  `long:"rescanpauserpcload" description:"Pause rescans while at least this many RPC requests are being handled (0 to never pause)"`
+	RescanBatchSize     int                  `long:"rescanbatchsize" description:"Number of blocks requested from hcd in each rescan batch, or in the first batch with --rescanadaptivebatches (0 for the default of 2000)"`
+	RescanAdaptive      bool                 `long:"rescanadaptivebatches" description:"Resize rescan batches from the time taken to rescan and the number of transactions discovered in the previous batch"`
+	BalanceSnapshots    int32                `long:"balancesnapshots" description:"Record a snapshot of the account balances at each block whose height is a multiple of this number, queried by getbalanceatheight and getbalanceathash (0 to disable)"`
+	PruneHistory        int32                `long:"prunehistory" description:"Periodically prune fully spent regular transactions mined at least this many blocks below the tip (0 to keep all history, minimum 1024)"`
+	VerifyCredits       bool                 `long:"verifycredits" description:"Paranoid mode: verify the merkle inclusion of each mined transaction relevant to the wallet in its block fetched from hcd before recording the transaction as mined"`
+	AutoRefund          bool                 `long:"autorefund" description:"Automatically sign and publish refunds of imported scripts with timelocked refund paths paying wallet keys once the timelock matures (requires an unlocked wallet)"`
+	DisableAutoRevoke   bool                 `long:"disableautorevoke" description:"Do not automatically revoke missed and expired tickets as blocks are connected; the revoketickets RPC must be used instead"`
 	TicketFee           *cfgutil.AmountFlag  `long:"ticketfee" description:"Sets the wallet's ticket fee per kb"`
+	WebhookURLs         []string             `long:"webhookurl" description:"POST a JSON notification to this URL when a credit to a wallet address, or a validated omni simple send, reaches webhookconfs confirmations (may be repeated)"`
+	WebhookSecret       string               `long:"webhooksecret" default-mask:"-" description:"Key of the HMAC-SHA256 signature of each webhook notification, sent in the X-Hcwallet-Signature header"`
+	WebhookConfs        int32                `long:"webhookconfs" description:"Number of confirmations a transaction must have before webhook notifications are sent"`
+	WebhookMaxAttempts  uint32               `long:"webhookmaxattempts" description:"Number of attempts to deliver a webhook notification before giving up (0 to retry until delivered)"`
+	FiatPriceURLs       []string             `long:"fiatpriceurl" description:"URL queried for the daily fiat price of a coin when valuing balances and transactions; {currency} and {date} are replaced by the currency code and the UTC date (YYYY-MM-DD) (may be repeated, queried in order until one responds)"`
+	FiatPriceJSONPath   string               `long:"fiatpricejsonpath" description:"Dot-separated path of the numeric price in the JSON responses of fiatpriceurl, in which {currency} is replaced by the currency code"`
+	FiatCurrency        string               `long:"fiatcurrency" description:"Default currency code of fiat valuations"`
 
 	// RPC client options
 	RPCConnect       string                  `short:"c" long:"rpcconnect" description:"Hostname/IP and port of hcd RPC server to connect to"`
@@ -141,14 +199,33 @@ type config struct {
 	TLSCurve               *cfgutil.CurveFlag      `long:"tlscurve" description:"Curve to use when generating TLS keypairs"`
 	OneTimeTLSKey          bool                    `long:"onetimetlskey" description:"Generate a new TLS certpair at startup, but only write the certificate to disk"`
 	DisableServerTLS       bool                    `long:"noservertls" description:"Disable TLS for the RPC servers -- NOTE: This is only allowed if the RPC server is bound to localhost"`
+	ClientCAFile           string                  `long:"clientcafile" description:"Require RPC clients to present TLS certificates signed by a root certificate in this file"`
+	ClientCertPerms        string                  `long:"clientcertperms" description:"File mapping client certificate common names or sha256:<fingerprint> to full or readonly permissions (requires clientcafile; all client certificates have full permission if unset)"`
 	GRPCListeners          []string                `long:"grpclisten" description:"Listen for gRPC connections on this interface/port"`
 	LegacyRPCListeners     []string                `long:"rpclisten" description:"Listen for legacy JSON-RPC connections on this interface/port"`
+	GRPCUnixListeners      []string                `long:"grpclistenunix" description:"Listen for gRPC connections without TLS on this unix domain socket path"`
+	LegacyRPCUnixListeners []string                `long:"rpclistenunix" description:"Listen for legacy JSON-RPC connections without TLS on this unix domain socket path"`
+	UnixSocketPerm         string                  `long:"unixsocketperm" description:"Octal file permissions of unix domain sockets"`
 	NoGRPC                 bool                    `long:"nogrpc" description:"Disable the gRPC server"`
 	NoLegacyRPC            bool                    `long:"nolegacyrpc" description:"Disable the legacy JSON-RPC server"`
 	LegacyRPCMaxClients    int64                   `long:"rpcmaxclients" description:"Max number of legacy JSON-RPC clients for standard connections"`
 	LegacyRPCMaxWebsockets int64                   `long:"rpcmaxwebsockets" description:"Max number of legacy JSON-RPC websocket connections"`
+	LegacyRPCMaxClientReqs int                     `long:"rpcmaxclientrequests" description:"Max number of outstanding requests of each legacy JSON-RPC websocket client (0 for no limit)"`
+	LegacyRPCIdleTimeout   time.Duration           `long:"rpcidletimeout" description:"Disconnect legacy JSON-RPC websocket clients which make no requests for this duration (0 to never disconnect)"`
 	Username               string                  `short:"u" long:"username" description:"Username for legacy JSON-RPC and hcd authentication (if hcusername is unset)"`
 	Password               string                  `short:"P" long:"password" default-mask:"-" description:"Password for legacy JSON-RPC and hcd authentication (if hcpassword is unset)"`
+	HealthListeners        []string                `long:"healthlisten" description:"Listen for unauthenticated HTTP health checks on this interface/port"`
+	HealthMaxBlocksBehind  int                     `long:"healthmaxblocksbehind" description:"Maximum number of blocks the wallet may be behind hcd while reporting ready to health checks"`
+
+	// Replication options
+	AllowReplicas   bool          `long:"allowreplicas" description:"Serve the wallet database journal and snapshots to replica wallets over the legacy JSON-RPC server"`
+	Replicate       string        `long:"replicate" description:"Run as a read-only replica of the wallet served by the primary legacy JSON-RPC server at this URL"`
+	ReplicaCAFile   string        `long:"replicacafile" description:"File containing root certificates to authenticate the TLS connection with the primary wallet"`
+	ReplicaInterval time.Duration `long:"replicainterval" description:"Minimum time between replicated snapshots of the primary wallet, and between retries of failed replication attempts"`
+
+	// Event bus options
+	NATSURL     string `long:"natsurl" description:"Publish wallet events to the NATS server at this URL (nats://[user:pass@]host[:port], or tls:// to require TLS)"`
+	NATSSubject string `long:"natssubject" description:"Prefix of the subjects wallet events are published to"`
 
 	// IPC options
 	PipeTx            *uint `long:"pipetx" description:"File descriptor or handle of write end pipe to enable child -> parent process communication"`
@@ -158,12 +235,18 @@ type config struct {
 	TBOpts ticketBuyerOptions `group:"Ticket Buyer Options" namespace:"ticketbuyer"`
 	tbCfg  ticketbuyer.Config
 
+	unixSocketPerm os.FileMode
+
 	// Deprecated options
 	DataDir      *cfgutil.ExplicitString `short:"b" long:"datadir" default-mask:"-" description:"DEPRECATED -- use appdata instead"`
 	PruneTickets bool                    `long:"prunetickets" description:"DEPRECATED -- old tickets are always pruned"`
 
 	// Added to assist postquantum functionality
 	createPass string
+
+	// Offline subcommand selected on the command line, if any
+	offlineCmd  string
+	offlineCmds offlineCommands
 }
 
 type ticketBuyerOptions struct {
@@ -336,7 +419,11 @@ func loadConfig() (*config, []string, error) {
 		ConfigFile:             cfgutil.NewExplicitString(defaultConfigFile),
 		AppDataDir:             cfgutil.NewExplicitString(defaultAppDataDir),
 		LogDir:                 cfgutil.NewExplicitString(defaultLogDir),
+		LogFormat:              defaultLogFormat,
+		LogMaxSize:             defaultLogMaxSize,
+		LogMaxRolls:            defaultLogMaxRolls,
 		WalletPass:             wallet.InsecurePubPassphrase,
+		DBDriver:               defaultDBDriver,
 		CAFile:                 cfgutil.NewExplicitString(""),
 		PromptPass:             defaultPromptPass,
 		Pass:                   defaultPass,
@@ -346,17 +433,32 @@ func loadConfig() (*config, []string, error) {
 		TLSCurve:               cfgutil.NewCurveFlag(cfgutil.CurveP521),
 		LegacyRPCMaxClients:    defaultRPCMaxClients,
 		LegacyRPCMaxWebsockets: defaultRPCMaxWebsockets,
+		LegacyRPCMaxClientReqs: defaultRPCMaxClientReqs,
+		UnixSocketPerm:         defaultUnixSocketPerm,
 		EnableTicketBuyer:      defaultEnableTicketBuyer,
 		EnableOmni:             defaultEnableOmni,
 		EnableVoting:           defaultEnableVoting,
 		ReuseAddresses:         defaultReuseAddresses,
+		AddressReusePolicy:     string(wallet.AddressReuseWarn),
+		DustThreshold:          cfgutil.NewAmountFlag(wallet.DefaultDustThreshold),
+		DustMinCount:           wallet.DefaultDustMinCount,
+		DustMaxFeeRate:         cfgutil.NewAmountFlag(0),
 		RollbackTest:           defaultRollbackTest,
 		PruneTickets:           defaultPruneTickets,
 		PurchaseAccount:        defaultPurchaseAccount,
 		AutomaticRepair:        defaultAutomaticRepair,
 		AddrIdxScanLen:         defaultAddrIdxScanLen,
+		GapLimit:               defaultGapLimit,
+		GapLimitLookahead:      defaultGapLimitLookahead,
 		StakePoolColdExtKey:    defaultStakePoolColdExtKey,
 		AllowHighFees:          defaultAllowHighFees,
+		HealthMaxBlocksBehind:  defaultHealthMaxBlocksBehind,
+		WebhookConfs:           defaultWebhookConfs,
+		WebhookMaxAttempts:     defaultWebhookMaxAttempts,
+		FiatPriceJSONPath:      wallet.DefaultPriceJSONPath,
+		FiatCurrency:           defaultFiatCurrency,
+		ReplicaInterval:        defaultReplicaInterval,
+		NATSSubject:            defaultNATSSubject,
 		RelayFee:               cfgutil.NewAmountFlag(txrules.DefaultRelayFeePerKb),
 		TicketFee:              cfgutil.NewAmountFlag(txrules.DefaultRelayFeePerKb),
 		TicketAddress:          cfgutil.NewAddressFlag(nil),
@@ -393,7 +495,11 @@ func loadConfig() (*config, []string, error) {
 	// file or the version flag was specified.
 	preCfg := cfg
 	preParser := flags.NewParser(&preCfg, flags.Default)
-	_, err := preParser.Parse()
+	err := addOfflineCommands(preParser, &preCfg.offlineCmds)
+	if err != nil {
+		return loadConfigError(err)
+	}
+	_, err = preParser.Parse()
 	if err != nil {
 		e, ok := err.(*flags.Error)
 		if ok && e.Type == flags.ErrHelp {
@@ -422,6 +528,10 @@ func loadConfig() (*config, []string, error) {
 	// Load additional config from file.
 	var configFileError error
 	parser := flags.NewParser(&cfg, flags.Default)
+	err = addOfflineCommands(parser, &cfg.offlineCmds)
+	if err != nil {
+		return loadConfigError(err)
+	}
 	configFilePath := preCfg.ConfigFile.Value
 	if preCfg.ConfigFile.ExplicitlySet() {
 		configFilePath = cleanAndExpandPath(configFilePath)
@@ -453,6 +563,19 @@ func loadConfig() (*config, []string, error) {
 		return loadConfigError(err)
 	}
 
+	// The create subcommand is equivalent to the --create option.
+	if parser.Active != nil {
+		cfg.offlineCmd = parser.Active.Name
+		err := checkOfflineCommand(&cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return loadConfigError(err)
+		}
+		if cfg.offlineCmd == "create" {
+			cfg.Create = true
+		}
+	}
+
 	// If an alternate data directory was specified, and paths with defaults
 	// relative to the data dir are unchanged, modify each path to be
 	// relative to the new data dir.
@@ -499,9 +622,27 @@ func loadConfig() (*config, []string, error) {
 		os.Exit(0)
 	}
 
+	switch cfg.LogFormat {
+	case "text":
+	case "json":
+		logJSON = true
+	default:
+		err := fmt.Errorf("%s: invalid log format %q -- must be text or json",
+			"loadConfig", cfg.LogFormat)
+		fmt.Fprintln(os.Stderr, err)
+		return loadConfigError(err)
+	}
+	if cfg.LogMaxSize <= 0 || cfg.LogMaxRolls <= 0 {
+		err := fmt.Errorf("%s: logmaxsize and logmaxrolls must be positive",
+			"loadConfig")
+		fmt.Fprintln(os.Stderr, err)
+		return loadConfigError(err)
+	}
+
 	// Initialize log rotation.  After log rotation has been initialized, the
 	// logger variables may be used.
-	initLogRotator(filepath.Join(cfg.LogDir.Value, defaultLogFilename))
+	initLogRotator(filepath.Join(cfg.LogDir.Value, defaultLogFilename),
+		cfg.LogMaxSize, cfg.LogMaxRolls)
 
 	// Parse, validate, and set debug log level(s).
 	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
@@ -667,6 +808,52 @@ func loadConfig() (*config, []string, error) {
 		return loadConfigError(err)
 	}
 
+	// The wallet export to migrate from is only read when creating a new
+	// wallet from a seed.
+	if cfg.MigrateFrom != "" {
+		if !cfg.Create || cfg.CreateTemp || cfg.CreateWatchingOnly {
+			err := fmt.Errorf("The --migratefrom option may only be " +
+				"used with --create.")
+			fmt.Fprintln(os.Stderr, err)
+			return loadConfigError(err)
+		}
+		cfg.MigrateFrom = cleanAndExpandPath(cfg.MigrateFrom)
+	}
+
+	// The sqlite driver is only registered by builds with the sqlite tag.
+	drivers := walletdb.SupportedDrivers()
+	sort.Strings(drivers)
+	driverSupported := false
+	for _, d := range drivers {
+		if d == cfg.DBDriver {
+			driverSupported = true
+			break
+		}
+	}
+	if !driverSupported {
+		err := fmt.Errorf("Unsupported database driver %q, must be one of "+
+			"%s (the sqlite driver requires building with -tags sqlite).",
+			cfg.DBDriver, strings.Join(drivers, ", "))
+		fmt.Fprintln(os.Stderr, err)
+		return loadConfigError(err)
+	}
+
+	// Simulation wallets are created with the insecure default public
+	// passphrase, which can not protect an encrypted database.
+	if cfg.EncryptDB && cfg.CreateTemp {
+		err := fmt.Errorf("The --encryptdb option may not be used with " +
+			"--createtemp.")
+		fmt.Fprintln(os.Stderr, err)
+		return loadConfigError(err)
+	}
+
+	if cfg.CheckDB && (cfg.Create || cfg.CreateTemp || cfg.CreateWatchingOnly) {
+		err := fmt.Errorf("The --checkdb option may not be used when " +
+			"creating a wallet.")
+		fmt.Fprintln(os.Stderr, err)
+		return loadConfigError(err)
+	}
+
 	if cfg.CreateTemp {
 		tempWalletExists := false
 
@@ -720,7 +907,39 @@ func loadConfig() (*config, []string, error) {
 
 		// Created successfully, so exit now with success.
 		os.Exit(0)
-	} else if !dbFileExists && !cfg.NoInitialLoad {
+	} else if cfg.CheckDB {
+		if !dbFileExists {
+			err := fmt.Errorf("The wallet database file `%v` does "+
+				"not exist.", dbPath)
+			fmt.Fprintln(os.Stderr, err)
+			return loadConfigError(err)
+		}
+
+		err = checkWalletDB(cfg.DBDriver, dbPath, []byte(cfg.WalletPass))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Wallet database check failed:", err)
+			return loadConfigError(err)
+		}
+
+		// Checked and compacted successfully, so exit now with success.
+		os.Exit(0)
+	} else if cfg.offlineCmd == "sign" || cfg.offlineCmd == "dump" {
+		if !dbFileExists {
+			err := fmt.Errorf("The wallet database file `%v` does "+
+				"not exist.", dbPath)
+			fmt.Fprintln(os.Stderr, err)
+			return loadConfigError(err)
+		}
+
+		err = runOfflineCommand(&cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to %s: %v\n", cfg.offlineCmd, err)
+			return loadConfigError(err)
+		}
+
+		// The command completed successfully, so exit now with success.
+		os.Exit(0)
+	} else if !dbFileExists && !cfg.NoInitialLoad && cfg.Replicate == "" {
 		err := fmt.Errorf("The wallet does not exist.  Run with the " +
 			"--create option to initialize and create it.")
 		fmt.Fprintln(os.Stderr, err)
@@ -738,6 +957,166 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	if cfg.MaxTxInputs < 0 || cfg.MaxTxSize < 0 {
+		err := fmt.Errorf("maxtxinputs and maxtxsize cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.LegacyRPCMaxClientReqs < 0 || cfg.LegacyRPCIdleTimeout < 0 {
+		err := fmt.Errorf("rpcmaxclientrequests and rpcidletimeout cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.BalanceSnapshots < 0 {
+		err := fmt.Errorf("balancesnapshots cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.PruneHistory != 0 && cfg.PruneHistory < wallet.MinHistoryPruneDepth {
+		err := fmt.Errorf("prunehistory must be 0 or at least %d",
+			wallet.MinHistoryPruneDepth)
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	// Votes must be mined in the block following the one voted on, so they
+	// may only be delayed for a few seconds.
+	if cfg.VoteDelay < 0 || cfg.VoteDelay > wallet.MaxVoteDelay {
+		err := fmt.Errorf("votedelay must be between 0 and %v",
+			wallet.MaxVoteDelay)
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.OmniPendingExpiry < 0 {
+		err := fmt.Errorf("omnipendingexpiry cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	for _, u := range cfg.WebhookURLs {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+			parsed.Host == "" {
+			err := fmt.Errorf("invalid webhookurl %q", u)
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+	}
+	if cfg.DustThreshold.Amount <= 0 || cfg.DustMinCount < 0 ||
+		cfg.DustMaxFeeRate.Amount < 0 {
+		err := fmt.Errorf("dustthreshold must be positive, and " +
+			"dustconsolidatecount and dustconsolidatemaxfeerate cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	switch wallet.AddressReusePolicy(cfg.AddressReusePolicy) {
+	case wallet.AddressReuseIgnore, wallet.AddressReuseWarn, wallet.AddressReuseFlag:
+	case wallet.AddressReuseRefuse:
+		if cfg.ReuseAddresses {
+			err := fmt.Errorf("reuseaddresses may not be used with " +
+				"addressreusepolicy=refuse")
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+	default:
+		err := fmt.Errorf("invalid addressreusepolicy %q", cfg.AddressReusePolicy)
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	for _, u := range cfg.FiatPriceURLs {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+			parsed.Host == "" {
+			err := fmt.Errorf("invalid fiatpriceurl %q", u)
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+	}
+	if cfg.NATSURL != "" {
+		_, err := eventbus.ParseNATSURL(cfg.NATSURL)
+		if err != nil {
+			err := fmt.Errorf("invalid natsurl: %v", err)
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+		if cfg.NATSSubject == "" || strings.ContainsAny(cfg.NATSSubject, " \t*>") {
+			err := fmt.Errorf("invalid natssubject %q", cfg.NATSSubject)
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+	}
+
+	if cfg.WebhookConfs < 1 {
+		err := fmt.Errorf("webhookconfs must be positive")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.RescanBlocksPerSec < 0 || cfg.RescanPauseRPCLoad < 0 || cfg.RescanBatchSize < 0 {
+		err := fmt.Errorf("rescanblockspersec, rescanpauserpcload and rescanbatchsize cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	for _, name := range cfg.Wallets {
+		if name == "" || name == "." || name == ".." ||
+			strings.ContainsAny(name, `/\`) {
+			err := fmt.Errorf("invalid wallet name %q", name)
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+	}
+
+	// The gap limit was previously set with addridxscanlen.
+	if cfg.AddrIdxScanLen != defaultAddrIdxScanLen && cfg.GapLimit == defaultGapLimit {
+		cfg.GapLimit = cfg.AddrIdxScanLen
+	}
+	if cfg.GapLimit < 1 {
+		err := fmt.Errorf("gaplimit must be positive")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.HealthMaxBlocksBehind < 0 {
+		err := fmt.Errorf("healthmaxblocksbehind cannot be negative")
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
+	if cfg.Replicate != "" {
+		err := validateReplicaConfig(&cfg)
+		if err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err.Error())
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return loadConfigError(err)
+		}
+	}
+
 	if cfg.RPCConnect == "" {
 		cfg.RPCConnect = net.JoinHostPort("localhost", activeNet.JSONRPCClientPort)
 	}
@@ -798,14 +1177,23 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
-	// Default to localhost listen addresses if no listeners were manually
-	// specified.  When the RPC server is configured to be disabled, remove all
-	// listeners so it is not started.
+	// Default to localhost listen addresses if no listeners, including unix
+	// domain socket listeners, were manually specified.  When the RPC server
+	// is configured to be disabled, remove all listeners so it is not
+	// started.
 	localhostAddrs, err := net.LookupHost("localhost")
 	if err != nil {
 		return loadConfigError(err)
 	}
-	if len(cfg.GRPCListeners) == 0 && !cfg.NoGRPC {
+	if cfg.NoGRPC {
+		cfg.GRPCUnixListeners = nil
+	}
+	if cfg.NoLegacyRPC {
+		cfg.LegacyRPCUnixListeners = nil
+	}
+	if len(cfg.GRPCListeners) == 0 && len(cfg.GRPCUnixListeners) != 0 {
+		// Only serve unix domain sockets.
+	} else if len(cfg.GRPCListeners) == 0 && !cfg.NoGRPC {
 		cfg.GRPCListeners = make([]string, 0, len(localhostAddrs))
 		for _, addr := range localhostAddrs {
 			cfg.GRPCListeners = append(cfg.GRPCListeners,
@@ -814,7 +1202,9 @@ func loadConfig() (*config, []string, error) {
 	} else if cfg.NoGRPC {
 		cfg.GRPCListeners = nil
 	}
-	if len(cfg.LegacyRPCListeners) == 0 && !cfg.NoLegacyRPC {
+	if len(cfg.LegacyRPCListeners) == 0 && len(cfg.LegacyRPCUnixListeners) != 0 {
+		// Only serve unix domain sockets.
+	} else if len(cfg.LegacyRPCListeners) == 0 && !cfg.NoLegacyRPC {
 		cfg.LegacyRPCListeners = make([]string, 0, len(localhostAddrs))
 		for _, addr := range localhostAddrs {
 			cfg.LegacyRPCListeners = append(cfg.LegacyRPCListeners,
@@ -841,6 +1231,34 @@ func loadConfig() (*config, []string, error) {
 		return loadConfigError(err)
 	}
 
+	perm, err := strconv.ParseUint(cfg.UnixSocketPerm, 8, 32)
+	if err != nil || os.FileMode(perm)&^os.ModePerm != 0 {
+		err := fmt.Errorf("%s: invalid unixsocketperm %q", funcName,
+			cfg.UnixSocketPerm)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+	cfg.unixSocketPerm = os.FileMode(perm)
+	for i := range cfg.GRPCUnixListeners {
+		cfg.GRPCUnixListeners[i] = cleanAndExpandPath(cfg.GRPCUnixListeners[i])
+	}
+	for i := range cfg.LegacyRPCUnixListeners {
+		cfg.LegacyRPCUnixListeners[i] = cleanAndExpandPath(cfg.LegacyRPCUnixListeners[i])
+	}
+	seenPaths := make(map[string]struct{})
+	for _, paths := range [][]string{cfg.GRPCUnixListeners, cfg.LegacyRPCUnixListeners} {
+		for _, path := range paths {
+			if _, seen := seenPaths[path]; seen {
+				err := fmt.Errorf("Unix domain socket `%s` may only "+
+					"be listened on once", path)
+				fmt.Fprintln(os.Stderr, err)
+				return loadConfigError(err)
+			}
+			seenPaths[path] = struct{}{}
+		}
+	}
+
 	// Both RPC servers may not listen on the same interface/port, with the
 	// exception of listeners using port 0.
 	if len(cfg.LegacyRPCListeners) > 0 && len(cfg.GRPCListeners) > 0 {
@@ -860,6 +1278,23 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	// Client certificates are only presented over TLS, and only mapped to
+	// permissions when required.
+	if cfg.ClientCAFile != "" && cfg.DisableServerTLS {
+		err := fmt.Errorf("%s: clientcafile may not be used with noservertls",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+	if cfg.ClientCertPerms != "" && cfg.ClientCAFile == "" {
+		err := fmt.Errorf("%s: clientcertperms requires clientcafile",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return loadConfigError(err)
+	}
+
 	// Only allow server TLS to be disabled if the RPC server is bound to
 	// localhost addresses.
 	if cfg.DisableServerTLS {
@@ -890,6 +1325,15 @@ func loadConfig() (*config, []string, error) {
 	cfg.CAFile.Value = cleanAndExpandPath(cfg.CAFile.Value)
 	cfg.RPCCert.Value = cleanAndExpandPath(cfg.RPCCert.Value)
 	cfg.RPCKey.Value = cleanAndExpandPath(cfg.RPCKey.Value)
+	if cfg.ReplicaCAFile != "" {
+		cfg.ReplicaCAFile = cleanAndExpandPath(cfg.ReplicaCAFile)
+	}
+	if cfg.ClientCAFile != "" {
+		cfg.ClientCAFile = cleanAndExpandPath(cfg.ClientCAFile)
+	}
+	if cfg.ClientCertPerms != "" {
+		cfg.ClientCertPerms = cleanAndExpandPath(cfg.ClientCertPerms)
+	}
 
 	// If the hcd username or password are unset, use the same auth as for
 	// the client.  The two settings were previously shared for hcd and
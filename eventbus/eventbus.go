@@ -0,0 +1,296 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package eventbus publishes wallet events to a NATS server, so downstream
+// consumers may subscribe to them without holding RPC connections to the
+// wallet.
+//
+// Events are published as JSON to subjects beginning with a configured prefix:
+//
+//	<prefix>.tx             a transaction relevant to the wallet was added,
+//	                        either unmined or mined in an attached block
+//	<prefix>.blockattached  a block was attached to the main chain
+//	<prefix>.blockdetached  a block was detached from the main chain
+//	<prefix>.vote           the wallet sent a vote for a winning ticket
+//	<prefix>.omnitx         a mined omni transaction was processed
+//
+// Delivery is best effort: events are dropped while the server is unreachable
+// or when publishing falls behind.
+package eventbus
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/wallet"
+)
+
+// queueSize is the number of events which may be waiting to be published
+// before further events are dropped.
+const queueSize = 1024
+
+// Subjects of published events, relative to the bus prefix.
+const (
+	SubjectTx            = "tx"
+	SubjectBlockAttached = "blockattached"
+	SubjectBlockDetached = "blockdetached"
+	SubjectVote          = "vote"
+	SubjectOmniTx        = "omnitx"
+)
+
+// TxInput describes a transaction input debiting a wallet account.
+type TxInput struct {
+	Index   uint32  `json:"index"`
+	Account uint32  `json:"account"`
+	Amount  float64 `json:"amount"`
+}
+
+// TxOutput describes a transaction output controlled by the wallet.
+type TxOutput struct {
+	Index    uint32  `json:"index"`
+	Account  uint32  `json:"account"`
+	Address  string  `json:"address,omitempty"`
+	Internal bool    `json:"internal"`
+	Amount   float64 `json:"amount"`
+}
+
+// TxEvent is published to SubjectTx.
+type TxEvent struct {
+	TxID        string     `json:"txid"`
+	Type        string     `json:"type"`
+	BlockHash   string     `json:"blockhash,omitempty"`
+	BlockHeight int32      `json:"blockheight"` // -1 if unmined
+	Fee         float64    `json:"fee"`
+	Timestamp   int64      `json:"timestamp"`
+	Inputs      []TxInput  `json:"inputs"`
+	Outputs     []TxOutput `json:"outputs"`
+}
+
+// BlockEvent is published to SubjectBlockAttached and SubjectBlockDetached.
+// The height and time are only known for attached blocks.
+type BlockEvent struct {
+	Hash   string `json:"hash"`
+	Height int32  `json:"height,omitempty"`
+	Time   int64  `json:"time,omitempty"`
+}
+
+// VoteEvent is published to SubjectVote.
+type VoteEvent struct {
+	Ticket      string `json:"ticket"`
+	Vote        string `json:"vote"`
+	BlockHash   string `json:"blockhash"`
+	BlockHeight int32  `json:"blockheight"`
+	VoteBits    uint16 `json:"votebits"`
+}
+
+// OmniTxEvent is published to SubjectOmniTx.
+type OmniTxEvent struct {
+	TxID        string `json:"txid"`
+	Sender      string `json:"sender"`
+	Receiver    string `json:"receiver,omitempty"`
+	BlockHash   string `json:"blockhash"`
+	BlockHeight int32  `json:"blockheight"`
+}
+
+type event struct {
+	subject string
+	payload interface{}
+}
+
+// Bus publishes the events of a wallet.
+type Bus struct {
+	wallet    *wallet.Wallet
+	publisher *NATSPublisher
+	prefix    string
+
+	events chan event
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New returns a bus publishing the events of w with publisher p to subjects
+// beginning with prefix.
+func New(w *wallet.Wallet, p *NATSPublisher, prefix string) *Bus {
+	return &Bus{
+		wallet:    w,
+		publisher: p,
+		prefix:    prefix,
+		events:    make(chan event, queueSize),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start begins publishing the wallet's events.
+func (b *Bus) Start() {
+	ntfns := b.wallet.NtfnServer
+	txs := ntfns.TransactionNotifications()
+	votes := ntfns.VoteNotifications()
+	omniTxs := ntfns.OmniTransactionNotifications()
+
+	b.wg.Add(2)
+	go func() {
+		defer b.wg.Done()
+		defer txs.Done()
+		defer votes.Done()
+		defer omniTxs.Done()
+		b.receive(txs.C, votes.C, omniTxs.C)
+	}()
+	go b.publish()
+
+	log.Infof("Publishing wallet events to subjects %s.*", b.prefix)
+}
+
+// Stop stops publishing events and waits for the bus goroutines to finish.
+func (b *Bus) Stop() {
+	close(b.quit)
+	b.wg.Wait()
+}
+
+// receive converts wallet notifications to events and queues them.  Events are
+// dropped rather than waiting for the queue so that the bus keeps up with the
+// notification server, which disconnects clients that fall behind.
+func (b *Bus) receive(txs <-chan *wallet.TransactionNotifications,
+	votes <-chan *wallet.VoteNotification, omniTxs <-chan *wallet.OmniTransactionNotification) {
+
+	for {
+		select {
+		case n, ok := <-txs:
+			if !ok {
+				log.Errorf("Wallet disconnected the event bus")
+				return
+			}
+			for _, hash := range n.DetachedBlocks {
+				b.queue(SubjectBlockDetached, &BlockEvent{Hash: hash.String()})
+			}
+			for i := range n.AttachedBlocks {
+				block := &n.AttachedBlocks[i]
+				blockHash := block.Header.BlockHash()
+				b.queue(SubjectBlockAttached, &BlockEvent{
+					Hash:   blockHash.String(),
+					Height: int32(block.Header.Height),
+					Time:   block.Header.Timestamp.Unix(),
+				})
+				for j := range block.Transactions {
+					b.queue(SubjectTx, txEvent(&block.Transactions[j],
+						&blockHash, int32(block.Header.Height)))
+				}
+			}
+			for i := range n.UnminedTransactions {
+				b.queue(SubjectTx, txEvent(&n.UnminedTransactions[i], nil, -1))
+			}
+		case n, ok := <-votes:
+			if !ok {
+				log.Errorf("Wallet disconnected the event bus")
+				return
+			}
+			b.queue(SubjectVote, &VoteEvent{
+				Ticket:      n.Ticket.String(),
+				Vote:        n.Vote.String(),
+				BlockHash:   n.BlockHash.String(),
+				BlockHeight: n.BlockHeight,
+				VoteBits:    n.VoteBits,
+			})
+		case n, ok := <-omniTxs:
+			if !ok {
+				log.Errorf("Wallet disconnected the event bus")
+				return
+			}
+			b.queue(SubjectOmniTx, &OmniTxEvent{
+				TxID:        n.Hash.String(),
+				Sender:      n.Sender,
+				Receiver:    n.Receiver,
+				BlockHash:   n.BlockHash.String(),
+				BlockHeight: n.BlockHeight,
+			})
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+func (b *Bus) queue(subject string, payload interface{}) {
+	select {
+	case b.events <- event{subject: b.prefix + "." + subject, payload: payload}:
+	default:
+		log.Warnf("Event queue is full, dropping %s event", subject)
+	}
+}
+
+// publish publishes queued events until the bus is stopped.
+func (b *Bus) publish() {
+	defer b.wg.Done()
+	for {
+		select {
+		case e := <-b.events:
+			data, err := json.Marshal(e.payload)
+			if err != nil {
+				log.Errorf("Failed to encode %s event: %v", e.subject, err)
+				continue
+			}
+			err = b.publisher.Publish(e.subject, data)
+			switch err {
+			case nil:
+			case ErrNotConnected, ErrClosed:
+				log.Debugf("Dropped %s event: %v", e.subject, err)
+			default:
+				log.Warnf("Failed to publish %s event: %v", e.subject, err)
+			}
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+func txEvent(s *wallet.TransactionSummary, blockHash *chainhash.Hash, height int32) *TxEvent {
+	e := &TxEvent{
+		TxID:        s.Hash.String(),
+		Type:        txTypeString(s.Type),
+		BlockHeight: height,
+		Fee:         s.Fee.ToCoin(),
+		Timestamp:   s.Timestamp,
+		Inputs:      make([]TxInput, 0, len(s.MyInputs)),
+		Outputs:     make([]TxOutput, 0, len(s.MyOutputs)),
+	}
+	if blockHash != nil {
+		e.BlockHash = blockHash.String()
+	}
+	for _, in := range s.MyInputs {
+		e.Inputs = append(e.Inputs, TxInput{
+			Index:   in.Index,
+			Account: in.PreviousAccount,
+			Amount:  in.PreviousAmount.ToCoin(),
+		})
+	}
+	for _, out := range s.MyOutputs {
+		o := TxOutput{
+			Index:    out.Index,
+			Account:  out.Account,
+			Internal: out.Internal,
+			Amount:   out.Amount.ToCoin(),
+		}
+		if out.Address != nil {
+			o.Address = out.Address.EncodeAddress()
+		}
+		e.Outputs = append(e.Outputs, o)
+	}
+	return e
+}
+
+func txTypeString(t wallet.TransactionType) string {
+	switch t {
+	case wallet.TransactionTypeRegular:
+		return "regular"
+	case wallet.TransactionTypeCoinbase:
+		return "coinbase"
+	case wallet.TransactionTypeTicketPurchase:
+		return "ticket"
+	case wallet.TransactionTypeVote:
+		return "vote"
+	case wallet.TransactionTypeRevocation:
+		return "revocation"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package eventbus
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	natsDefaultPort = "4222"
+
+	// natsTimeout limits dialing, the connection handshake, and each write.
+	natsTimeout = 10 * time.Second
+
+	// natsReconnectDelay is the minimum time between connection attempts.
+	// Messages published while disconnected and within the delay of the
+	// last attempt are dropped.
+	natsReconnectDelay = 5 * time.Second
+)
+
+// ErrNotConnected is returned when publishing a message while the publisher
+// is disconnected from the NATS server and waiting to reconnect.
+var ErrNotConnected = errors.New("not connected to NATS server")
+
+// ErrClosed is returned when publishing a message with a closed publisher.
+var ErrClosed = errors.New("NATS publisher is closed")
+
+// NATSPublisher publishes messages to a NATS server using the NATS client
+// protocol.  It only implements publishing, connects when the first message
+// is published, and reconnects after the connection is lost.  It is safe for
+// concurrent use.
+type NATSPublisher struct {
+	url  *url.URL
+	name string
+
+	mu       sync.Mutex
+	conn     net.Conn
+	w        *bufio.Writer
+	lastDial time.Time
+	closed   bool
+}
+
+// NewNATSPublisher returns a publisher to the NATS server at the URL, which
+// has the form nats://[user:password@]host[:port], or tls://... to require
+// TLS.  name identifies the client to the server.
+func NewNATSPublisher(rawurl, name string) (*NATSPublisher, error) {
+	u, err := ParseNATSURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return &NATSPublisher{url: u, name: name}, nil
+}
+
+// ParseNATSURL parses and checks the URL of a NATS server.
+func ParseNATSURL(rawurl string) (*url.URL, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported NATS URL scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("NATS URL has no host")
+	}
+	return u, nil
+}
+
+// dial connects and performs the handshake with the server.  It must be called
+// with the mutex held.
+func (p *NATSPublisher) dial() error {
+	addr := p.url.Host
+	if p.url.Port() == "" {
+		addr = net.JoinHostPort(p.url.Hostname(), natsDefaultPort)
+	}
+	conn, err := net.DialTimeout("tcp", addr, natsTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(natsTimeout))
+	r := bufio.NewReader(conn)
+
+	// The server begins by describing itself and whether TLS is required.
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS server greeting %q",
+			strings.TrimSpace(line))
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	err = json.Unmarshal([]byte(line[len("INFO "):]), &info)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("invalid NATS server info: %v", err)
+	}
+	if info.TLSRequired || p.url.Scheme == "tls" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: p.url.Hostname()})
+		err := tlsConn.Handshake()
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		conn = tlsConn
+		r = bufio.NewReader(conn)
+	}
+
+	connect := struct {
+		Verbose  bool   `json:"verbose"`
+		Pedantic bool   `json:"pedantic"`
+		Name     string `json:"name"`
+		Lang     string `json:"lang"`
+		Protocol int    `json:"protocol"`
+		User     string `json:"user,omitempty"`
+		Pass     string `json:"pass,omitempty"`
+	}{Name: p.name, Lang: "go"}
+	if p.url.User != nil {
+		connect.User = p.url.User.Username()
+		connect.Pass, _ = p.url.User.Password()
+	}
+	connectJSON, err := json.Marshal(&connect)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	_, err = fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connectJSON)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	// The connection is established when the server answers the ping.
+	// Authorization failures are reported with -ERR.
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+		case strings.HasPrefix(line, "-ERR"):
+			conn.Close()
+			return fmt.Errorf("NATS server error: %s",
+				strings.TrimSpace(line[len("-ERR"):]))
+		default:
+			continue
+		}
+		break
+	}
+	conn.SetDeadline(time.Time{})
+
+	p.conn = conn
+	p.w = bufio.NewWriter(conn)
+	go p.readLoop(conn, r)
+	log.Infof("Connected to NATS server %s", addr)
+	return nil
+}
+
+// readLoop answers the server's keepalive pings and detects the loss of the
+// connection.
+func (p *NATSPublisher) readLoop(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			p.mu.Lock()
+			if p.conn == conn {
+				if !p.closed {
+					log.Warnf("Lost connection to NATS server: %v", err)
+				}
+				p.disconnect()
+			}
+			p.mu.Unlock()
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PING":
+			p.mu.Lock()
+			if p.conn == conn {
+				err := p.write(func() { p.w.WriteString("PONG\r\n") })
+				if err != nil {
+					log.Warnf("Lost connection to NATS server: %v", err)
+				}
+			}
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			log.Errorf("NATS server error: %s",
+				strings.TrimSpace(line[len("-ERR"):]))
+		}
+	}
+}
+
+// write writes to the connection with a deadline, disconnecting on errors.  It
+// must be called with the mutex held and a connection established.
+func (p *NATSPublisher) write(fn func()) error {
+	p.conn.SetWriteDeadline(time.Now().Add(natsTimeout))
+	fn()
+	err := p.w.Flush()
+	if err != nil {
+		p.disconnect()
+	}
+	return err
+}
+
+// disconnect closes the connection.  It must be called with the mutex held.
+func (p *NATSPublisher) disconnect() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+		p.w = nil
+	}
+}
+
+// Publish publishes a message to a subject, connecting to the server if
+// necessary.  Messages are not buffered while disconnected: ErrNotConnected
+// is returned when the last connection attempt was too recent to try again.
+func (p *NATSPublisher) Publish(subject string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+	if p.conn == nil {
+		if time.Since(p.lastDial) < natsReconnectDelay {
+			return ErrNotConnected
+		}
+		p.lastDial = time.Now()
+		err := p.dial()
+		if err != nil {
+			return err
+		}
+	}
+	return p.write(func() {
+		fmt.Fprintf(p.w, "PUB %s %d\r\n", subject, len(data))
+		p.w.Write(data)
+		p.w.WriteString("\r\n")
+	})
+}
+
+// Close closes the connection to the server.  Later messages are not
+// published.
+func (p *NATSPublisher) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.disconnect()
+	p.mu.Unlock()
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sync"
+
+	"github.com/HcashOrg/hcwallet/eventbus"
+	ldr "github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+)
+
+// startEventBus publishes the events of each wallet of the collection to the
+// configured NATS server once the wallet is loaded.  The events of named
+// wallets are published under the wallet name.  Publishing stops when the
+// returned function is called.
+func startEventBus(loaders *ldr.Collection) (stop func(), err error) {
+	publisher, err := eventbus.NewNATSPublisher(cfg.NATSURL, "hcwallet")
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var buses []*eventbus.Bus
+	stopped := false
+	for _, name := range loaders.Names() {
+		prefix := cfg.NATSSubject
+		if name != "" {
+			prefix += "." + name
+		}
+		l, _ := loaders.Loader(name)
+		l.RunAfterLoad(func(w *wallet.Wallet) {
+			mu.Lock()
+			defer mu.Unlock()
+			if stopped {
+				return
+			}
+			bus := eventbus.New(w, publisher, prefix)
+			bus.Start()
+			buses = append(buses, bus)
+		})
+	}
+
+	return func() {
+		mu.Lock()
+		stopped = true
+		for _, bus := range buses {
+			bus.Stop()
+		}
+		mu.Unlock()
+		publisher.Close()
+	}, nil
+}
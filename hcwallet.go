@@ -33,6 +33,7 @@ import (
 	"github.com/HcashOrg/hcwallet/rpc/legacyrpc"
 	"github.com/HcashOrg/hcwallet/rpc/rpcserver"
 	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
 )
 
 var (
@@ -126,12 +127,39 @@ func walletMain() error {
 		TicketFee:           cfg.TicketFee.ToCoin(),
 	}
 
-	loader := ldr.NewLoader(activeNet.Params, dbDir, stakeOptions,
-		cfg.AddrIdxScanLen, cfg.AllowHighFees, cfg.RelayFee.ToCoin(), cfg.EnableOmni)
+	newLoader := func(dbDir string, enableOmni bool) *ldr.Loader {
+		l := ldr.NewLoader(activeNet.Params, dbDir, stakeOptions,
+			cfg.GapLimit, cfg.AllowHighFees, txauthor.TxLimits{
+				MaxInputs:        cfg.MaxTxInputs,
+				MaxSerializeSize: cfg.MaxTxSize,
+			}, cfg.SplitTxs,
+			cfg.RelayFee.ToCoin(), enableOmni)
+		l.SetDatabaseEncryption(cfg.EncryptDB)
+		l.SetDatabaseDriver(cfg.DBDriver)
+		l.SetGapLimitLookahead(cfg.GapLimitLookahead)
+		l.SetSkipPreflight(cfg.SkipPreflight)
+		return l
+	}
+	loader := newLoader(dbDir, cfg.EnableOmni)
+
+	// Additional named wallets are each loaded from their own application
+	// data directory and served by the RPC servers alongside the default
+	// wallet.  The process runs a single omni engine, whose state belongs to
+	// the default wallet, so omni is never enabled for named wallets.
+	walletLoaders := ldr.NewCollection(loader)
+	for _, name := range cfg.Wallets {
+		appDataDir := namedWalletAppDataDir(cfg.AppDataDir.Value, name)
+		err := walletLoaders.Add(name, newLoader(networkDir(appDataDir, activeNet.Params), false))
+		if err != nil {
+			log.Errorf("Unable to add wallet: %v", err)
+			return err
+		}
+	}
 
 	passphrase := []byte{}
+	var walletPass []byte
 	if !cfg.NoInitialLoad {
-		walletPass := []byte(cfg.WalletPass)
+		walletPass = []byte(cfg.WalletPass)
 		defer zero.Bytes(walletPass)
 
 		if cfg.PromptPublicPass {
@@ -146,7 +174,10 @@ func walletMain() error {
 				break
 			}
 		}
+	}
 
+	// Replica wallets are opened with the passphrases of the primary wallet.
+	if !cfg.NoInitialLoad {
 		if cfg.Pass == "" {
 			os.Stdout.Sync()
 			for {
@@ -161,7 +192,10 @@ func walletMain() error {
 		} else {
 			passphrase = []byte(cfg.Pass)
 		}
-
+	}
+	// Replica wallets are loaded by the follower once the database has
+	// been replicated.
+	if !cfg.NoInitialLoad && cfg.Replicate == "" {
 		// Load the wallet database.  It must have been created already
 		// or this will return an appropriate error.
 		w, err := loader.OpenExistingWallet(walletPass, passphrase)
@@ -171,6 +205,14 @@ func walletMain() error {
 		}
 		w.SetInitiallyUnlocked(true)
 
+		for _, name := range cfg.Wallets {
+			l, _ := walletLoaders.Loader(name)
+			_, err := l.OpenExistingWallet(walletPass, passphrase)
+			if err != nil {
+				log.Errorf("Open of wallet %q failed: %v", name, err)
+				return err
+			}
+		}
 	}
 
 	netName := "main"
@@ -186,24 +228,90 @@ func walletMain() error {
 	// Create and start HTTP server to serve wallet client connections.
 	// This will be updated with the wallet and chain server RPC client
 	// created below after each is created.
-	rpcs, legacyRPCServer, err := startRPCServers(loader)
+	rpcs, legacyRPCServer, err := startRPCServers(walletLoaders)
 	if err != nil {
 		log.Errorf("Unable to create RPC servers: %v", err)
 		return err
 	}
 
+	// Start the unauthenticated health check servers if enabled.
+	if len(cfg.HealthListeners) > 0 {
+		startHealthServers(loader)
+	}
+
 	// Create and start chain RPC client so it's ready to connect to
 	// the wallet when loaded later.
-	if !cfg.NoInitialLoad {
+	if !cfg.NoInitialLoad && cfg.Replicate == "" {
 		go rpcClientConnectLoop(passphrase, legacyRPCServer, loader)
+
+		// Named wallets are synchronized by their own chain client, as
+		// notifications are delivered to a single wallet per client.
+		// They do not run the ticket buyer.
+		for _, name := range cfg.Wallets {
+			l, _ := walletLoaders.Loader(name)
+			go rpcClientConnectLoop(nil, nil, l)
+		}
+	}
+
+	for _, name := range walletLoaders.Names() {
+		l, _ := walletLoaders.Loader(name)
+		l.RunAfterLoad(func(w *wallet.Wallet) {
+			w.SetRescanLimits(wallet.RescanLimits{
+				BlocksPerSecond: cfg.RescanBlocksPerSec,
+				PauseRPCLoad:    cfg.RescanPauseRPCLoad,
+				BlocksPerBatch:  cfg.RescanBatchSize,
+				AdaptiveBatches: cfg.RescanAdaptive,
+			})
+			w.SetOmniPendingExpiry(cfg.OmniPendingExpiry)
+			w.SetBalanceSnapshotInterval(cfg.BalanceSnapshots)
+			w.SetHistoryPruneDepth(cfg.PruneHistory)
+			w.SetVerifyCredits(cfg.VerifyCredits)
+			w.SetAutoRefund(cfg.AutoRefund)
+			w.SetAutoRevoke(!cfg.DisableAutoRevoke)
+			w.SetVoteDelay(cfg.VoteDelay)
+			w.SetSkipStaleVotes(cfg.SkipStaleVotes)
+			w.SetAddressReusePolicy(wallet.AddressReusePolicy(cfg.AddressReusePolicy))
+			w.SetDustConsolidation(wallet.DustConsolidationPolicy{
+				Automatic:  cfg.ConsolidateDust,
+				Threshold:  cfg.DustThreshold.Amount,
+				MinCount:   cfg.DustMinCount,
+				MaxFeeRate: cfg.DustMaxFeeRate.Amount,
+			})
+			if cfg.TicketDenominations {
+				account, err := w.AccountNumber(cfg.PurchaseAccount)
+				if err != nil {
+					log.Errorf("Unable to denominate outputs of purchase "+
+						"account %q: %v", cfg.PurchaseAccount, err)
+				} else {
+					w.SetTicketDenominations(wallet.TicketDenominationPolicy{
+						Enabled: true,
+						Account: account,
+					})
+				}
+			}
+			if len(cfg.FiatPriceURLs) != 0 {
+				w.SetPriceSource(wallet.NewHTTPPriceSource(
+					cfg.FiatPriceURLs, cfg.FiatPriceJSONPath,
+					cfg.FiatCurrency))
+			}
+			if len(cfg.WebhookURLs) != 0 {
+				w.SetWebhooks(&wallet.WebhookConfig{
+					URLs:          cfg.WebhookURLs,
+					Secret:        []byte(cfg.WebhookSecret),
+					Confirmations: cfg.WebhookConfs,
+					MaxAttempts:   cfg.WebhookMaxAttempts,
+				})
+			}
+		})
 	}
 
-	// Start wallet and voting gRPC services after a wallet is loaded if the
-	// gRPC server was created.
+	// Start wallet, voting and ticket gRPC services after a wallet is loaded
+	// if the gRPC server was created.
 	if rpcs != nil {
 		loader.RunAfterLoad(func(w *wallet.Wallet) {
 			rpcserver.StartWalletService(rpcs, w)
 			rpcserver.StartVotingService(rpcs, w)
+			rpcserver.StartTicketService(rpcs, w)
 		})
 	}
 
@@ -216,16 +324,11 @@ func walletMain() error {
 			req := omnilib.Request{
 				Method: "omni_stop",
 			}
-			bytes, err := json.Marshal(req)
-			if err == nil {
-				strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-				var response hcjson.Response
-				err = json.Unmarshal([]byte(strRsp), &response)
-				if err != nil {
-					log.Errorf("Failed to close omni core: %v", err)
-				}
-				log.Warn("Stopping omni core...")
+			_, err := omnilib.Send(&req)
+			if err != nil {
+				log.Errorf("Failed to close omni core: %v", err)
 			}
+			log.Warn("Stopping omni core...")
 
 		})
 	}
@@ -236,11 +339,37 @@ func walletMain() error {
 		// The only possible err here is ErrTicketBuyerStopped, which can be
 		// safely ignored.
 		_ = loader.StopTicketPurchase()
-		err := loader.UnloadWallet()
-		if err != nil && err != ldr.ErrWalletNotLoaded {
-			log.Errorf("Failed to close wallet: %v", err)
+		for _, name := range walletLoaders.Names() {
+			l, _ := walletLoaders.Loader(name)
+			err := l.UnloadWallet()
+			if err != nil && err != ldr.ErrWalletNotLoaded {
+				log.Errorf("Failed to close wallet: %v", err)
+			}
 		}
 	})
+
+	// Wallet events are published until the wallet is closed.
+	if cfg.NATSURL != "" {
+		stopEventBus, err := startEventBus(walletLoaders)
+		if err != nil {
+			log.Errorf("Unable to start the event bus: %v", err)
+			return err
+		}
+		addInterruptHandler(stopEventBus)
+	}
+
+	// Replica wallets are not synced with hcd until they are promoted, but
+	// replaced by snapshots of the primary wallet.  Replication is stopped
+	// before the wallet is closed.
+	if cfg.Replicate != "" {
+		stopReplica, err := startReplica(loader, walletPass, passphrase,
+			legacyRPCServer)
+		if err != nil {
+			log.Errorf("Unable to start replication: %v", err)
+			return err
+		}
+		addInterruptHandler(stopReplica)
+	}
 	if rpcs != nil {
 		addInterruptHandler(func() {
 			// TODO: Does this need to wait for the grpc server to
@@ -353,11 +482,12 @@ func startPromptPass(w *wallet.Wallet) []byte {
 
 // rpcClientConnectLoop continuously attempts a connection to the consensus RPC
 // server.  When a connection is established, the client is used to sync the
-// loaded wallet, either immediately or when loaded at a later time.
+// loaded wallet, either immediately or when loaded at a later time, and the
+// connection is reattempted whenever it is lost.
 //
 // The legacy RPC is optional.  If set, the connected RPC client will be
 // associated with the server for RPC passthrough and to enable additional
-// methods.
+// methods.  The ticket buyer is only started when passphrase is non-nil.
 func rpcClientConnectLoop(passphrase []byte, legacyRPCServer *legacyrpc.Server, loader *ldr.Loader) {
 	certs := readCAFile()
 
@@ -381,7 +511,7 @@ func rpcClientConnectLoop(passphrase []byte, legacyRPCServer *legacyrpc.Server,
 				legacyRPCServer.SetChainServer(chainClient)
 			}
 			loader.SetChainClient(chainClient.Client)
-			if cfg.EnableTicketBuyer {
+			if cfg.EnableTicketBuyer && passphrase != nil {
 				err = loader.StartTicketPurchase(passphrase, &cfg.tbCfg)
 				if err != nil {
 					log.Errorf("Unable to start ticket buyer: %v", err)
@@ -0,0 +1,58 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	ldr "github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/rpc/legacyrpc"
+)
+
+// startHealthServers starts HTTP servers on each of the health listen
+// addresses.  The servers are unauthenticated, so they do not require the RPC
+// credentials and never report whether the wallet is unlocked.
+//
+// /health/live responds whenever the process is running.  /health/ready
+// responds with the result of the gethealth RPC, with status 200 only when the
+// wallet is ready, that is, no component is unavailable and the wallet is no
+// more than the configured number of blocks behind hcd.
+func startHealthServers(loader *ldr.Loader) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health/live", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthStatus(w, map[string]bool{"live": true}, http.StatusOK)
+	})
+	mux.HandleFunc("/health/ready", func(w http.ResponseWriter, r *http.Request) {
+		result := legacyrpc.CheckHealth(loader, cfg.HealthMaxBlocksBehind,
+			false)
+		code := http.StatusOK
+		if !result.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealthStatus(w, result, code)
+	})
+
+	for _, listenAddr := range cfg.HealthListeners {
+		listenAddr := listenAddr // copy for closure
+		go func() {
+			log.Infof("Starting health server on %s", listenAddr)
+			err := http.ListenAndServe(listenAddr, mux)
+			if err != nil {
+				fatalf("Unable to run health server: %v", err)
+			}
+		}()
+	}
+}
+
+func writeHealthStatus(w http.ResponseWriter, status interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(status)
+	if err != nil {
+		log.Warnf("Failed to write health status: %v", err)
+	}
+}
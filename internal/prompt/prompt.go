@@ -11,10 +11,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletseed"
 	"golang.org/x/crypto/ssh/terminal"
 )
@@ -239,17 +242,17 @@ func PublicPass(reader *bufio.Reader, privPass []byte,
 // yes, a the user is prompted for it.  All prompts are repeated until the user
 // enters a valid response. The bool returned indicates if the wallet was
 // restored from a given seed or not.
-func Seed(reader *bufio.Reader) ([]byte, error) {
+func Seed(reader *bufio.Reader) ([]byte, bool, error) {
 	// Ascertain the wallet generation seed.
 	useUserSeed, err := promptListBool(reader, "Do you have an "+
 		"existing wallet seed you want to use?", "no")
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if !useUserSeed {
 		seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		seedStrSplit := walletseed.EncodeMnemonicSlice(seed)
@@ -276,7 +279,7 @@ func Seed(reader *bufio.Reader) ([]byte, error) {
 				`and secure location, enter "OK" to continue: `)
 			confirmSeed, err := reader.ReadString('\n')
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			confirmSeed = strings.TrimSpace(confirmSeed)
 			confirmSeed = strings.Trim(confirmSeed, `"`)
@@ -285,9 +288,16 @@ func Seed(reader *bufio.Reader) ([]byte, error) {
 			}
 		}
 
-		return seed, nil
+		return seed, false, nil
 	}
 
+	seed, err := ExistingSeed(reader)
+	return seed, err == nil, err
+}
+
+// ExistingSeed prompts the user for the mnemonic or hex encoding of an existing
+// wallet seed.  The prompt is repeated until the user enters a valid seed.
+func ExistingSeed(reader *bufio.Reader) ([]byte, error) {
 	for {
 		fmt.Print("Enter existing wallet seed " +
 			"(followed by a blank line): ")
@@ -309,6 +319,7 @@ func Seed(reader *bufio.Reader) ([]byte, error) {
 		wordCount := strings.Count(seedStrTrimmed, " ") + 1
 
 		var seed []byte
+		var err error
 		if wordCount == 1 {
 			if len(seedStrTrimmed)%2 != 0 {
 				seedStrTrimmed = "0" + seedStrTrimmed
@@ -339,6 +350,40 @@ func Seed(reader *bufio.Reader) ([]byte, error) {
 	}
 }
 
+// Birthday prompts for the birthday of a wallet restored from an existing
+// seed, either as the height of a block mined before the first use of the seed
+// or as the date the seed was created.  A nil birthday is returned when the
+// user leaves the response blank, in which case the wallet is synced from the
+// genesis block.  The prompt is repeated until the user enters a valid
+// response.
+func Birthday(reader *bufio.Reader) (*udb.Birthday, error) {
+	for {
+		fmt.Print("Enter the block height or date (YYYY-MM-DD) when the " +
+			"seed was created, or leave blank to sync from the genesis " +
+			"block: ")
+		reply, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		reply = strings.TrimSpace(reply)
+		if reply == "" {
+			return nil, nil
+		}
+
+		height, err := strconv.ParseInt(reply, 10, 32)
+		if err == nil && height >= 0 {
+			return &udb.Birthday{Height: int32(height)}, nil
+		}
+		date, err := time.Parse("2006-01-02", reply)
+		if err == nil {
+			return &udb.Birthday{Time: date, Height: -1}, nil
+		}
+
+		fmt.Println("Invalid birthday.  Must be a non-negative block " +
+			"height or a date formatted as YYYY-MM-DD")
+	}
+}
+
 // Setup prompts for, from a buffered reader, the private and/or public
 // encryption passphrases to secure a wallet and a previously derived wallet
 // seed to use, if any.  privPass and pubPass will always be non-nil values
@@ -354,6 +399,23 @@ func Seed(reader *bufio.Reader) ([]byte, error) {
 // option of using this passphrase if public data encryption is enabled,
 // otherwise a user-specified passphrase will be prompted for.
 func Setup(r *bufio.Reader, insecurePubPass, walletPass, configPubPass []byte) (privPass, pubPass, seed []byte, err error) {
+	privPass, pubPass, err = Passphrases(r, insecurePubPass, walletPass, configPubPass)
+	if err != nil {
+		return
+	}
+
+	// Ascertain the wallet generation seed.  This will either be an
+	// automatically generated value the user has already confirmed or a
+	// value the user has entered which has already been validated.
+	seed, _, err = Seed(r)
+
+	return
+}
+
+// Passphrases prompts for, from a buffered reader, the private and/or public
+// encryption passphrases to secure a wallet.  It performs the passphrase steps
+// of Setup for callers which obtain the wallet seed by other means.
+func Passphrases(r *bufio.Reader, insecurePubPass, walletPass, configPubPass []byte) (privPass, pubPass []byte, err error) {
 	// Hcd: no legacy keystore restore is needed (first HC wallet
 	// version did not use the legacy keystore from earlier versions of
 	// btcwallet).
@@ -375,14 +437,6 @@ func Setup(r *bufio.Reader, insecurePubPass, walletPass, configPubPass []byte) (
 	// specified by the user or the default hard-coded public passphrase if
 	// the user does not want the additional public data encryption.
 	pubPass, err = PublicPass(r, privPass, insecurePubPass, configPubPass)
-	if err != nil {
-		return
-	}
-
-	// Ascertain the wallet generation seed.  This will either be an
-	// automatically generated value the user has already confirmed or a
-	// value the user has entered which has already been validated.
-	seed, err = Seed(r)
 
 	return
 }
@@ -9,6 +9,24 @@
 package rpchelp
 
 var helpDescsEnUS = map[string]string{
+	// AbandonTransactionCmd help.
+	"abandontransaction--synopsis": "Removes an unmined transaction, and any unmined transactions spending its outputs, from the wallet.\n" +
+		"Outputs spent by the removed transactions become spendable again, and their pending omni balance changes are reversed. Mined transactions can not be abandoned.",
+	"abandontransaction-txhash": "Hash of the unmined transaction to abandon",
+
+	// BumpFeeCmd help.
+	"bumpfee--synopsis": "Replaces an unmined wallet transaction with a transaction spending the same inputs to the same destinations and paying a higher fee, deducted from its change output.\n" +
+		"hcd does not replace transactions in its mempool, so the transaction may only be replaced after it has left hcd's mempool, for example by expiring or being evicted; an error is returned while it remains there.\n" +
+		"The original transaction is removed from the wallet and recorded as replaced. The consensus server must accept the replacement, or the wallet is left unchanged.",
+	"bumpfee-txhash":  "Hash of the unmined transaction to replace",
+	"bumpfee-feerate": "Fee per kB of the replacement (defaults to the relay fee); the fee always increases by at least the relay fee for the transaction's size",
+
+	// BumpFeeResult help.
+	"bumpfeeresult-txid":     "Hash of the replacement transaction",
+	"bumpfeeresult-origtxid": "Hash of the replaced transaction",
+	"bumpfeeresult-origfee":  "Fee paid by the replaced transaction",
+	"bumpfeeresult-fee":      "Fee paid by the replacement transaction",
+
 	// AccountAddressIndexCmd help.
 	"accountaddressindex--synopsis": "Get the current address index for some account branch",
 	"accountaddressindex-account":   "String for the account",
@@ -28,12 +46,69 @@ var helpDescsEnUS = map[string]string{
 	"addmultisigaddress-nrequired": "The number of signatures required to redeem outputs paid to this address",
 	"addmultisigaddress--result0":  "The imported pay-to-script-hash address",
 
+	// CancelTransactionDraftCmd help.
+	"canceltransactiondraft--synopsis": "Removes a transaction draft created by createtransactiondraft, releasing the outputs reserved by it.",
+	"canceltransactiondraft-draftid":   "The id of the transaction draft",
+
+	// CheckAddressReuseCmd help.
+	"checkaddressreuse--synopsis": "Reports external addresses paid by more than one transaction recorded by the wallet.\n" +
+		"With a positive lookahead, the main chain is also scanned for payments to the next addresses of each account which the wallet has not yet returned, which suggests a leaked extended public key or a cloned wallet.\n" +
+		"The scanned addresses are added to the transaction filter, so later payments to them are recorded by the wallet.",
+	"checkaddressreuse-lookahead":   "Number of unreturned external addresses of each account to scan the main chain for (0 skips the scan)",
+	"checkaddressreuse-startheight": "Main chain height to begin scanning from",
+
+	// CheckAddressReuseResult help.
+	"checkaddressreuseresult-reused":             "External addresses paid by more than one transaction",
+	"checkaddressreuseresult-usedbeforereturned": "External addresses paid before the wallet returned them",
+
+	// AddressUseResult help.
+	"addressuseresult-address":      "The external address",
+	"addressuseresult-account":      "The account of the address",
+	"addressuseresult-index":        "The child index of the address in the account's external branch",
+	"addressuseresult-transactions": "Hashes of the transactions paying to the address",
+
+	// CommitTransactionDraftCmd help.
+	"committransactiondraft--synopsis": "Signs and publishes the transaction of a draft created by createtransactiondraft, removing the draft.\n" +
+		"The draft remains, and its outputs reserved, if the transaction can not be signed or published.\n" +
+		"Requires the wallet to be unlocked.",
+	"committransactiondraft-draftid":  "The id of the transaction draft",
+	"committransactiondraft--result0": "The hash of the published transaction, which is the draft id",
+
+	// CompactDBCmd help.
+	"compactdb--synopsis": "Checks the integrity of the wallet database and, if no inconsistencies are found, rewrites the database without the space held by deleted data.\n" +
+		"Database access is blocked while compacting.",
+
+	// CompactDBResult help.
+	"compactdbresult-issues":    "Descriptions of each inconsistency found between the buckets of the transaction store",
+	"compactdbresult-compacted": "Whether the database was compacted, which is skipped when any inconsistency is found",
+
 	// ConsolidateCmd help.
-	"consolidate--synopsis": "Consolidate n many UTXOs into a single output in the wallet.",
-	"consolidate-inputs":    "Number of UTXOs to consolidate as inputs",
-	"consolidate-account":   "Optional: Account from which unspent outputs are picked. When no address specified, also the account used to obtain an output address.",
-	"consolidate-address":   "Optional: Address to pay.  Default is obtained via getnewaddress from the account's address pool.",
-	"consolidate--result0":  "Transaction hash for the consolidation transaction",
+	"consolidate--synopsis": "Consolidate n many UTXOs of an account into a single output in the wallet.\n" +
+		"The consolidation is split into multiple transactions when splittxs is enabled and a single transaction would exceed maxtxinputs or maxtxsize.\n" +
+		"With result version 1, an error after some transactions were published is returned with the hashes of the published transactions in its message.",
+	"consolidate-inputs":  "Number of UTXOs to consolidate as inputs",
+	"consolidate-account": "Optional: Account from which unspent outputs are picked. When no address specified, also the account used to obtain an output address.",
+	"consolidate-address": "Optional: Address to pay.  Default is obtained via getnewaddress from the account's address pool.",
+	"consolidate-feerate": "Optional: Fee per kB paid by the transactions (default: the relay fee of the account)",
+	"consolidate-maxfee":  "Optional: Highest fee paid by a single transaction; the consolidation fails if it would pay more",
+	"consolidate--condition0": "resultversion=1 and a single transaction was created",
+	"consolidate--condition1": "resultversion=1 and the consolidation was split into multiple transactions",
+	"consolidate--condition2": "resultversion=2",
+	"consolidate--result0":    "Transaction hash for the consolidation transaction",
+	"consolidate--result1":    "Transaction hashes for each consolidation transaction",
+
+	// ConsolidateResult help.
+	"consolidateresult-transactions": "The published consolidation transactions",
+	"consolidateresult-inputs":       "The total number of consumed UTXOs",
+	"consolidateresult-fee":          "The total fee paid",
+	"consolidateresult-amount":       "The total value of the consolidated outputs",
+	"consolidateresult-error":        "The error which stopped the consolidation after the listed transactions were published, if any",
+
+	// ConsolidateTxResult help.
+	"consolidatetxresult-txid":   "The hash of the transaction",
+	"consolidatetxresult-inputs": "The number of UTXOs consumed by the transaction",
+	"consolidatetxresult-fee":    "The fee paid by the transaction",
+	"consolidatetxresult-amount": "The value of the consolidated output",
 
 	// CreateMultisigCmd help.
 	"createmultisig--synopsis": "Generate a multisig address and redeem script.",
@@ -44,11 +119,129 @@ var helpDescsEnUS = map[string]string{
 	"createmultisigresult-address":      "The generated pay-to-script-hash address",
 	"createmultisigresult-redeemScript": "The script required to redeem outputs paid to the multisig address",
 
+	// CreateTransactionDraftCmd help.
+	"createtransactiondraft--synopsis": "Authors an unsigned transaction paying each address and records it as a transaction draft.\n" +
+		"The inputs of the draft are selected and reserved in a single step, and are never selected for other transactions until the draft is committed with committransactiondraft or canceled with canceltransactiondraft.\n" +
+		"Reservations are saved in the wallet database and persist across restarts.",
+	"createtransactiondraft-amounts":        "Pairs of payment addresses and the output amount to pay each",
+	"createtransactiondraft-amounts--desc":  "JSON object using payment addresses as keys and output amounts valued in HC to send to each address",
+	"createtransactiondraft-amounts--key":   "Address to pay",
+	"createtransactiondraft-amounts--value": "Amount to send to the payment address valued in HC",
+	"createtransactiondraft-account":        "The account to spend outputs of",
+	"createtransactiondraft-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent",
+
+	// CreateTransactionDraftResult help.
+	"createtransactiondraftresult-draftid":     "The id of the transaction draft, which is also the hash of the transaction once it is signed",
+	"createtransactiondraftresult-hex":         "The serialized unsigned transaction",
+	"createtransactiondraftresult-fee":         "The fee paid by the transaction",
+	"createtransactiondraftresult-changeindex": "The output index of the change output, or -1 if there is no change",
+
 	// DumpPrivKeyCmd help.
 	"dumpprivkey--synopsis": "Returns the private key in WIF encoding that controls some wallet address.",
 	"dumpprivkey-address":   "The address to return a private key for",
 	"dumpprivkey--result0":  "The WIF-encoded private key",
 
+	// ExportAccountCmd help.
+	"exportaccount--synopsis": "Returns the BIP0044 extended key of an account, which importaccount uses to recreate the account at the same account number in another wallet.",
+	"exportaccount-account":   "The name of the account to export",
+	"exportaccount-private":   "Export the extended private key rather than the extended public key (requires an unlocked wallet, and is required for bliss accounts)",
+	"exportaccount--result0":  "The extended key of the account",
+
+	// ExportTransactionsCmd help.
+	"exporttransactions--synopsis": "Exports the effect of every transaction recorded by the wallet on the balance of each account it affects, with running account balances, for accounting and tax reporting.\n" +
+		"Entries are ordered by block, followed by unmined transactions, and running balances do not include transactions removed by history pruning.\n" +
+		"Categories are send, receive, transfer, coinbase, ticket, vote (whose amount is the staking income of the ticket), and revocation.",
+	"exporttransactions-account":     "Only export the entries of this account (running balances are unaffected)",
+	"exporttransactions-format":      "The export format (csv or json)",
+	"exporttransactions-fiat":        "Value each entry in the currency of the configured price source (requires fiatpriceurl)",
+	"exporttransactions--condition0": "format is csv",
+	"exporttransactions--condition1": "format is json",
+	"exporttransactions--result0":    "CSV with a header row and the columns of the JSON result, with times in RFC 3339 format",
+
+	// ExportedTransactionResult help.
+	"exportedtransactionresult-txid":         "The hash of the transaction",
+	"exportedtransactionresult-blockheight":  "The height of the block mining the transaction, or -1 if unmined",
+	"exportedtransactionresult-time":         "The time of the block mining the transaction, or the time the transaction was received if unmined",
+	"exportedtransactionresult-txtype":       "The transaction type (regular, ticket, vote, or revocation)",
+	"exportedtransactionresult-category":     "The classification of the effect on the account",
+	"exportedtransactionresult-account":      "The account affected by the transaction",
+	"exportedtransactionresult-amount":       "The change of the account balance excluding the fee",
+	"exportedtransactionresult-fee":          "The fee paid by the account",
+	"exportedtransactionresult-net":          "The change of the account balance including the fee",
+	"exportedtransactionresult-balance":      "The running balance of the account after the transaction",
+	"exportedtransactionresult-fiatcurrency": "The fiat currency of the valuation",
+	"exportedtransactionresult-fiatprice":    "The price of a coin at the time of the transaction",
+	"exportedtransactionresult-fiatvalue":    "The fiat value of the net change of the account balance",
+
+	// ExportVoteChoicesCmd help.
+	"exportvotechoices--synopsis": "Returns the configured vote choices for the latest supported stake agendas in the form accepted by importvotechoices.",
+
+	// ExportVoteChoicesResult help.
+	"exportvotechoicesresult-version": "The latest stake version supported by the software and the version of the included agendas",
+	"exportvotechoicesresult-choices": "The configured choice of each agenda, including abstaining votes",
+
+	// AgendaChoice help.
+	"agendachoice-agendaid": "The ID for the agenda the choice concerns",
+	"agendachoice-choiceid": "The ID of the choice for this agenda",
+
+	// DecodeWalletTransactionCmd help.
+	"decodewallettransaction--synopsis": "Decodes a hex-encoded transaction and annotates its inputs and outputs with the wallet's knowledge of the addresses they pay.\n" +
+		"Previous outputs are only described when the spent transaction is recorded by the wallet, and the omni engine's decoding is included when omni is enabled and the transaction carries an omni payload.",
+	"decodewallettransaction-hextx": "The hex-encoded transaction",
+
+	// DecodeWalletTransactionResult help.
+	"decodewallettransactionresult-txid":     "The hash of the transaction",
+	"decodewallettransactionresult-version":  "The transaction version",
+	"decodewallettransactionresult-locktime": "The transaction lock time",
+	"decodewallettransactionresult-expiry":   "The height after which the transaction may not be mined",
+	"decodewallettransactionresult-type":     "The transaction type (regular, ticket, vote, or revocation)",
+	"decodewallettransactionresult-vin":      "The annotated transaction inputs",
+	"decodewallettransactionresult-vout":     "The annotated transaction outputs",
+	"decodewallettransactionresult-omni":     "The omni engine's decoding of the transaction, if any",
+
+	// WalletTxInputResult help.
+	"wallettxinputresult-txid":      "The hash of the previous transaction",
+	"wallettxinputresult-vout":      "The output index of the previous transaction",
+	"wallettxinputresult-tree":      "The tree of the previous transaction",
+	"wallettxinputresult-sequence":  "The input sequence number",
+	"wallettxinputresult-amountin":  "The value of the previous output as committed to by the input",
+	"wallettxinputresult-stakebase": "Whether the input is the stakebase of a vote",
+	"wallettxinputresult-prevout":   "The previous output, if the previous transaction is recorded by the wallet",
+
+	// WalletTxOutputResult help.
+	"wallettxoutputresult-value":      "The output value",
+	"wallettxoutputresult-n":          "The output index",
+	"wallettxoutputresult-scripttype": "The type of the output script",
+	"wallettxoutputresult-addresses":  "The addresses paid by the output, or the committed address of a ticket commitment",
+	"wallettxoutputresult-mine":       "Whether the output pays a wallet address",
+	"wallettxoutputresult-account":    "The account of the wallet address",
+	"wallettxoutputresult-label":      "The name of the additional external branch the wallet address is derived from",
+	"wallettxoutputresult-change":     "Whether the output pays an internal (change) address",
+	"wallettxoutputresult-data":       "The hex-encoded data carried by an OP_RETURN output",
+	"wallettxoutputresult-spent":      "Whether the wallet output has been spent, only set by verbose gettransaction",
+	"wallettxoutputresult-spentby":    "The hash of the transaction spending the wallet output",
+
+	// DelegatedTicketsCmd help.
+	"delegatedtickets--synopsis": "Returns the tickets recorded by the wallet whose voting rights are delegated to an address controlled by another wallet.",
+
+	// DelegatedTicketResult help.
+	"delegatedticketresult-ticket":        "The hash of the ticket purchase transaction",
+	"delegatedticketresult-votingaddress": "The address with the voting rights of the ticket",
+	"delegatedticketresult-price":         "The amount of coins paid for the ticket",
+	"delegatedticketresult-status":        "The ticket status (unmined, immature, live, voted, missed, expired, revoked, or unknown)",
+	"delegatedticketresult-spentby":       "The hash of the vote or revocation spending the ticket, if any",
+
+	// GenerateTicketProofCmd help.
+	"generateticketproof--synopsis": "Signs a challenge with the key of a commitment address or the voting address of a ticket, proving ownership of the ticket without revealing keys.\n" +
+		"Commitment addresses are preferred, as they remain controlled by the ticket owner when voting is delegated to a stake pool.\n" +
+		"Requires the wallet to be unlocked.",
+	"generateticketproof-tickethash": "The hash of the ticket",
+	"generateticketproof-challenge":  "The challenge to sign, chosen by the verifier",
+
+	// GenerateTicketProofResult help.
+	"generateticketproofresult-address":   "The commitment or voting address of the ticket which signed the challenge",
+	"generateticketproofresult-signature": "The signature encoded as a base64 string",
+
 	// GenerateVote help.
 	"generatevote--synopsis":   "Returns the vote transaction encoded as a hexadecimal string",
 	"generatevote-blockhash":   "Block hash for the ticket",
@@ -69,20 +262,153 @@ var helpDescsEnUS = map[string]string{
 	"getaccountaddress-account":  "The account of the returned address",
 	"getaccountaddress--result0": "The unused address for 'account'",
 
+	// GetAccountDefaultAddressCmd help.
+	"getaccountdefaultaddress--synopsis": "Returns the default address of an account, which is used as the change and sending address of transactions from the account which do not specify one.\n" +
+		"This is the address set by setaccountdefaultaddress, or the first address of the account's external branch.",
+	"getaccountdefaultaddress-account":  "The account of the returned address",
+	"getaccountdefaultaddress--result0": "The default address of 'account'",
+
+	// SetAccountDefaultAddressCmd help.
+	"setaccountdefaultaddress--synopsis": "Sets the default address of an account, which is used as the change and sending address of transactions from the account which do not specify one.",
+	"setaccountdefaultaddress-account":   "The account to configure",
+	"setaccountdefaultaddress-address":   "An address of the account, or omitted to use the first address of the account's external branch",
+
 	// GetAddressesByAccountCmd help.
 	"getaddressesbyaccount--synopsis": "DEPRECATED -- Returns all addresses strings controlled by a single account.",
 	"getaddressesbyaccount-account":   "Account name to fetch addresses for",
 	"getaddressesbyaccount--result0":  "All addresses controlled by 'account'",
 
+	// GetAddressForInvoiceCmd help.
+	"getaddressforinvoice--synopsis": "Returns the address of an account which receives payments for an invoice.\n" +
+		"The address is derived deterministically from the invoice id under a dedicated account branch, and the mapping is recorded so payments can be looked up with getinvoicepayments.\n" +
+		"Requesting the address of an invoice again returns the same address. Invoice addresses are only supported by ECDSA accounts.",
+	"getaddressforinvoice-invoiceid": "The unique id of the invoice",
+	"getaddressforinvoice-account":   "The account the address belongs to",
+	"getaddressforinvoice--result0":  "The payment address of the invoice",
+
+	// GetInvoicePaymentsCmd help.
+	"getinvoicepayments--synopsis": "Returns the payments received by the address derived for an invoice by getaddressforinvoice.",
+	"getinvoicepayments-invoiceid": "The unique id of the invoice",
+	"getinvoicepayments-minconf":   "Minimum number of confirmations of payments included in the received amount",
+
+	// GetInvoicePaymentsResult help.
+	"getinvoicepaymentsresult-invoiceid": "The unique id of the invoice",
+	"getinvoicepaymentsresult-address":   "The payment address of the invoice",
+	"getinvoicepaymentsresult-account":   "The account the address belongs to",
+	"getinvoicepaymentsresult-created":   "The Unix time the address was derived",
+	"getinvoicepaymentsresult-received":  "The total amount of payments with at least minconf confirmations",
+	"getinvoicepaymentsresult-payments":  "Every transaction paying the address, including those with fewer than minconf confirmations",
+
+	// InvoicePaymentResult help.
+	"invoicepaymentresult-txid":          "The hash of the transaction",
+	"invoicepaymentresult-amount":        "The amount paid to the invoice address by the transaction",
+	"invoicepaymentresult-blockhash":     "The hash of the block the transaction is mined in, or empty if unmined",
+	"invoicepaymentresult-blockheight":   "The height of the block the transaction is mined in, or -1 if unmined",
+	"invoicepaymentresult-confirmations": "The number of confirmations of the transaction",
+
+	// GetAuditLogCmd help.
+	"getauditlog--synopsis": "Returns a page of the append-only audit log of balance-affecting events.\n" +
+		"Entries are returned in the order they were recorded. Pass the returned 'next' value as 'from' to fetch the following page.",
+	"getauditlog-from":  "The sequence number of the first entry to return",
+	"getauditlog-count": "The maximum number of entries to return",
+
+	// GetAuditLogResult help.
+	"getauditlogresult-entries": "The audit log entries",
+	"getauditlogresult-next":    "The sequence number to request the following page with",
+
+	// AuditLogEntry help.
+	"auditlogentry-seq":         "The sequence number of the entry",
+	"auditlogentry-time":        "The time the entry was recorded, as a Unix timestamp",
+	"auditlogentry-event":       "The event (credit, debit, votereward, revocation, rollback, abandon, or prune)",
+	"auditlogentry-txid":        "The hash of the crediting, debiting, or removed transaction, omitted for rollbacks",
+	"auditlogentry-amount":      "The total amount credited or debited by the transaction, or the net amount credited by a removed transaction (negative if it debited more)",
+	"auditlogentry-blockhash":   "The hash of the block the transaction is mined in, omitted if unmined",
+	"auditlogentry-blockheight": "The height of the block the transaction is mined in (-1 if unmined), the first removed height of a rollback, or the height the history was pruned at",
+	"auditlogentry-trigger":     "The notification or wallet operation which caused the event",
+
+	// GetHealthCmd help.
+	"gethealth--synopsis": "Reports the health of the wallet and the services it depends on.\n" +
+		"Each check and the overall result have a status code: 0 (ok), 1 (degraded, the wallet continues to serve most requests), or 2 (unavailable, the wallet should be restarted or alerted on).\n" +
+		"The overall status is the most severe status of all checks.",
+	"gethealth-maxblocksbehind": "Maximum number of blocks the wallet may be behind hcd while reporting ready",
+
+	// GetHealthResult help.
+	"gethealthresult-status":         "The overall status (ok, degraded, or unavailable)",
+	"gethealthresult-code":           "The overall status code",
+	"gethealthresult-ready":          "Whether the wallet is loaded, not unavailable, and synced to hcd",
+	"gethealthresult-walletloaded":   "Whether a wallet is loaded",
+	"gethealthresult-chainconnected": "Whether hcd answered a request for its best block",
+	"gethealthresult-walletheight":   "The height of the wallet's main chain tip",
+	"gethealthresult-chainheight":    "The height of hcd's best block",
+	"gethealthresult-blocksbehind":   "The number of blocks the wallet is behind hcd, or -1 if unknown",
+	"gethealthresult-dbreadable":     "Whether the wallet database could be read",
+	"gethealthresult-unlocked":       "Whether the wallet is unlocked (omitted by the unauthenticated /health/ready endpoint)",
+	"gethealthresult-omniresponsive": "Whether the omni engine answered a request (false if omni is disabled)",
+	"gethealthresult-checks":         "The results of the individual checks",
+
+	// HealthCheckResult help.
+	"healthcheckresult-name":   "The checked component (wallet, chain, sync, database, unlocked, or omni)",
+	"healthcheckresult-status": "The status of the component (ok, degraded, or unavailable)",
+	"healthcheckresult-code":   "The status code of the component",
+	"healthcheckresult-detail": "A description of the problem, if any",
+
 	// GetBalanceCmd help.
 	"getbalance--synopsis":   "Calculates and returns the balance of one or all accounts.",
 	"getbalance-minconf":     "Minimum number of block confirmations required before an unspent output's value is included in the balance",
 	"getbalance-account":     "DEPRECATED -- The account name to query the balance for, or \"*\" to consider all accounts (default=\"*\")",
 	"getbalance-balancetype": "The type of balance to return, 'spendable', 'locked', 'all', or 'fullscan'",
-	"getbalance--condition0": "account != \"*\"",
-	"getbalance--condition1": "account = \"*\"",
-	"getbalance--result0":    "The balance of 'account' valued in HC",
-	"getbalance--result1":    "The balance of all accounts valued in HC",
+	"getbalance-currency":    "Also value the total balances in this fiat currency at the current price (requires fiatpriceurl; an empty string selects the configured fiatcurrency)",
+
+	// GetBalanceResult help.
+	"getbalanceresult-balances":                     "The balances of the account, or of every account",
+	"getbalanceresult-blockhash":                    "The hash of the block the balances were calculated at",
+	"getbalanceresult-totalimmaturecoinbaserewards": "The total immature coinbase rewards of all accounts",
+	"getbalanceresult-totalimmaturestakegeneration": "The total immature stake generation of all accounts",
+	"getbalanceresult-totallockedbytickets":         "The total value locked by tickets of all accounts",
+	"getbalanceresult-totalspendable":               "The total spendable balance of all accounts",
+	"getbalanceresult-cumulativetotal":              "The total balance of all accounts",
+	"getbalanceresult-totalunconfirmed":             "The total unconfirmed balance of all accounts",
+	"getbalanceresult-totalvotingauthority":         "The total value of tickets with voting authority of all accounts",
+	"getbalanceresult-cumulativefiat":               "The fiat valuation of the total balance of all accounts, if a currency was requested",
+
+	// GetBalanceAtHashCmd help.
+	"getbalanceathash--synopsis": "Returns the balances of one or all accounts recorded by the latest balance snapshot at or below the height of a main chain block.\n" +
+		"Snapshots are only recorded when the balancesnapshots option is set, as blocks are connected at heights which are multiples of it.",
+	"getbalanceathash-blockhash": "The hash of the main chain block",
+	"getbalanceathash-account":   "The account name to query the balance for, or \"*\" to consider all accounts",
+
+	// GetBalanceAtHeightCmd help.
+	"getbalanceatheight--synopsis": "Returns the balances of one or all accounts recorded by the latest balance snapshot at or below a main chain height.\n" +
+		"Snapshots are only recorded when the balancesnapshots option is set, as blocks are connected at heights which are multiples of it.",
+	"getbalanceatheight-height":  "The main chain height",
+	"getbalanceatheight-account": "The account name to query the balance for, or \"*\" to consider all accounts",
+
+	// GetBalanceAtResult help.
+	"getbalanceatresult-height":                       "The height of the block the snapshot was recorded at, which is below the requested block when no snapshot was recorded there",
+	"getbalanceatresult-blockhash":                    "The hash of the block the snapshot was recorded at",
+	"getbalanceatresult-balances":                     "The mined balances of the account, or of every account holding mined outputs",
+	"getbalanceatresult-totalimmaturecoinbaserewards": "The total immature coinbase rewards of the accounts",
+	"getbalanceatresult-totalimmaturestakegeneration": "The total immature stake generation of the accounts",
+	"getbalanceatresult-totallockedbytickets":         "The total value locked by tickets of the accounts",
+	"getbalanceatresult-totalspendable":               "The total spendable balance of the accounts",
+	"getbalanceatresult-cumulativetotal":              "The total balance of the accounts",
+	"getbalanceatresult-totalvotingauthority":         "The total value of tickets with voting authority of the accounts",
+
+	// GetAccountBalanceResult help.
+	"getaccountbalanceresult-accountname":             "The name of the account",
+	"getaccountbalanceresult-immaturecoinbaserewards": "Immature coinbase rewards",
+	"getaccountbalanceresult-immaturestakegeneration": "Immature stake generation (votes and revocations)",
+	"getaccountbalanceresult-lockedbytickets":         "The value locked by tickets",
+	"getaccountbalanceresult-spendable":               "The spendable balance",
+	"getaccountbalanceresult-total":                   "The total balance",
+	"getaccountbalanceresult-unconfirmed":             "The unconfirmed balance",
+	"getaccountbalanceresult-votingauthority":         "The value of tickets with voting authority",
+	"getaccountbalanceresult-fiat":                    "The fiat valuation of the total balance, if a currency was requested",
+
+	// FiatValuationResult help.
+	"fiatvaluationresult-currency": "The fiat currency code",
+	"fiatvaluationresult-price":    "The price of a coin",
+	"fiatvaluationresult-value":    "The fiat value of the amount",
 
 	// GetBestBlockHashCmd help.
 	"getbestblockhash--synopsis": "Returns the hash of the newest block in the best chain that wallet has finished syncing with.",
@@ -93,7 +419,11 @@ var helpDescsEnUS = map[string]string{
 	"getblockcount--result0":  "The blockchain height of the most recent synced-to block",
 
 	// GetInfoCmd help.
-	"getinfo--synopsis": "Returns a JSON object containing various state info.",
+	"getinfo--synopsis": "Returns a JSON object containing various state info.\n" +
+		"Fields describing the consensus server are zero when hcd is unreachable.\n" +
+		"With result version 2, these fields are null when hcd is unreachable, and the wallet sync progress is included.",
+	"getinfo--condition0": "resultversion=1",
+	"getinfo--condition1": "resultversion=2",
 
 	// GetMasterPubkey help.
 	"getmasterpubkey--synopsis": "Requests the master pubkey from the wallet.",
@@ -118,24 +448,104 @@ var helpDescsEnUS = map[string]string{
 	"getmultisigoutinforesult-redeemscript": "Hex of the redeeming script.",
 	"getmultisigoutinforesult-address":      "Script address.",
 
+	// GetRecoveryState help.
+	"getrecoverystate--synopsis": "Returns how many automatic rescans were started to recover from errors processing consensus server notifications, and the most recent errors.",
+
+	// GetRecoveryStateResult help.
+	"getrecoverystateresult-autorescans": "Number of automatic rescans started since the wallet was loaded",
+	"getrecoverystateresult-errors":      "Number of errors processing notifications since the wallet was loaded",
+	"getrecoverystateresult-backoff":     "Minimum number of seconds between automatic rescans, doubling after each rescan",
+	"getrecoverystateresult-pending":     "Whether a rescan is scheduled to start once the backoff expires",
+	"getrecoverystateresult-halted":      "Whether automatic rescans are disabled after an error describing inconsistent wallet data or invalid chain data from the consensus server",
+	"getrecoverystateresult-events":      "The most recent notification errors, oldest first",
+
+	// RecoveryEventResult help.
+	"recoveryeventresult-time":         "Unix time of the error",
+	"recoveryeventresult-notification": "The notification being processed",
+	"recoveryeventresult-error":        "The error",
+	"recoveryeventresult-transient":    "Whether the error may be resolved by rescanning",
+	"recoveryeventresult-action":       "The response of the wallet (\"rescan\", \"deferred\", \"skipped\", or \"halted\")",
+
+	// GetReplicationInfo help.
+	"getreplicationinfo--synopsis": "Returns whether the wallet is a primary or a read-only replica, and how far a replica lags behind its primary.",
+
+	// GetReplicationInfoResult help.
+	"getreplicationinforesult-mode":           "Either \"primary\" or \"follower\" for read-only replicas",
+	"getreplicationinforesult-height":         "Main chain height of the wallet",
+	"getreplicationinforesult-primary":        "URL of the primary wallet's RPC server (followers only)",
+	"getreplicationinforesult-snapshottime":   "Unix time the latest replicated snapshot was created by the primary",
+	"getreplicationinforesult-snapshotheight": "Main chain height of the primary when the latest replicated snapshot was created",
+	"getreplicationinforesult-lag":            "Seconds since the primary committed the earliest change which is not yet replicated (0 for primaries and caught up replicas, -1 when no snapshot has been replicated)",
+	"getreplicationinforesult-snapshots":      "Number of snapshots which replaced the replica database",
+	"getreplicationinforesult-applied":        "Number of commits of the primary applied to the replica database without a snapshot",
+	"getreplicationinforesult-lastattempt":    "Unix time of the latest replication attempt",
+	"getreplicationinforesult-lasterror":      "Error of the latest replication attempt, if it failed",
+
+	// PromoteReplicaCmd help.
+	"promotereplica--synopsis": "Stops replicating the primary wallet and serves the replica as a primary wallet which may be modified and is synced with the consensus server.\n" +
+		"The latest replicated database is kept.",
+
+	// GetRescanInfo help.
+	"getrescaninfo--synopsis": "Returns whether a rescan is running and how it is being throttled by the configured rescan limits.",
+
+	// GetRescanInfoResult help.
+	"getrescaninforesult-scanning":        "Whether a rescan is in progress",
+	"getrescaninforesult-blockspersecond": "Maximum average number of blocks rescanned per second (0 for no limit)",
+	"getrescaninforesult-pauserpcload":    "Number of RPC requests in progress which pauses a rescan (0 to never pause)",
+	"getrescaninforesult-rpcload":         "Number of RPC requests currently being handled, including this one",
+	"getrescaninforesult-ratelimited":     "Whether the rescan is waiting to stay within the blocks per second limit",
+	"getrescaninforesult-paused":          "Whether the rescan is paused until the RPC load drops",
+	"getrescaninforesult-blocksperbatch":  "Configured number of blocks requested in each batch, or in the first batch of adaptive rescans (0 for the default of 2000)",
+	"getrescaninforesult-adaptivebatches": "Whether batches are resized from the time taken and transactions discovered in the previous batch",
+	"getrescaninforesult-batchsize":       "Number of blocks requested in the current batch (0 when not scanning)",
+
+	// GetSpendableConfsCmd help.
+	"getspendableconfs--synopsis": "Returns the confirmations an account requires before its outputs are considered spendable by balances and input selection.",
+	"getspendableconfs-account":   "The account to query",
+
+	// GetSpendableConfsResult help.
+	"getspendableconfsresult-account":          "The account",
+	"getspendableconfsresult-regular":          "The confirmations required of every output (0 for only the requested minimum)",
+	"getspendableconfsresult-coinbase":         "The confirmations required of coinbase, vote and revocation outputs (0 for only the chain maturity)",
+	"getspendableconfsresult-coinbasematurity": "The confirmations coinbase, vote and revocation outputs of the account must reach, including the chain maturity",
+
 	// GetStakeInfo help.
-	"getstakeinfo--synopsis": "Returns statistics about staking from the wallet.",
+	"getstakeinfo--synopsis": "Returns statistics about staking from the wallet.\n" +
+		"When an account is provided, the ticket statistics only include tickets funded by the account.\n" +
+		"Tickets purchased before funding accounts were recorded are only included when they were mined and spent outputs recording their account.",
+	"getstakeinfo-account": "Only include tickets funded by this account",
 
 	// GetStakeInfoResult help.
-	"getstakeinforesult-blockheight":      "Current block height for stake info.",
-	"getstakeinforesult-poolsize":         "Number of live tickets in the ticket pool.",
-	"getstakeinforesult-difficulty":       "Current stake difficulty.",
-	"getstakeinforesult-allmempooltix":    "Number of tickets currently in the mempool",
-	"getstakeinforesult-ownmempooltix":    "Number of tickets submitted by this wallet currently in mempool",
-	"getstakeinforesult-immature":         "Number of tickets from this wallet that are in the blockchain but which are not yet mature",
-	"getstakeinforesult-live":             "Number of mature, active tickets owned by this wallet",
-	"getstakeinforesult-proportionlive":   "(Live / PoolSize)",
-	"getstakeinforesult-voted":            "Number of votes cast by this wallet",
-	"getstakeinforesult-totalsubsidy":     "Total amount of coins earned by stake mining",
-	"getstakeinforesult-missed":           "Number of missed tickets (failure to vote, not including expired)",
-	"getstakeinforesult-proportionmissed": "(Missed / (Missed + Voted))",
-	"getstakeinforesult-revoked":          "Number of missed tickets that were missed and then revoked",
-	"getstakeinforesult-expired":          "Number of tickets that have expired",
+	"getstakeinforesult-blockheight":         "Current block height for stake info.",
+	"getstakeinforesult-poolsize":            "Number of live tickets in the ticket pool.",
+	"getstakeinforesult-difficulty":          "Current stake difficulty.",
+	"getstakeinforesult-allmempooltix":       "Number of tickets currently in the mempool",
+	"getstakeinforesult-ownmempooltix":       "Number of tickets submitted by this wallet currently in mempool",
+	"getstakeinforesult-immature":            "Number of tickets from this wallet that are in the blockchain but which are not yet mature",
+	"getstakeinforesult-live":                "Number of mature, active tickets owned by this wallet",
+	"getstakeinforesult-proportionlive":      "(Live / PoolSize)",
+	"getstakeinforesult-voted":               "Number of votes cast by this wallet",
+	"getstakeinforesult-totalsubsidy":        "Total amount of coins earned by stake mining",
+	"getstakeinforesult-missed":              "Number of missed tickets (failure to vote, not including expired)",
+	"getstakeinforesult-proportionmissed":    "(Missed / (Missed + Voted))",
+	"getstakeinforesult-revoked":             "Number of missed tickets that were missed and then revoked",
+	"getstakeinforesult-expired":             "Number of tickets that have expired",
+	"getstakeinforesult-networkstakeversion": "Highest stake version of the blocks connected since the wallet was started",
+	"getstakeinforesult-voteversionoutdated": "Whether the stake version of the majority of recent blocks is newer than the vote version of the wallet",
+
+	// GetStakeRewards help.
+	"getstakerewards--synopsis": "Returns the votes, missed tickets, revocations and stake subsidy of wallet tickets, grouped by period.\n" +
+		"Periods begin at midnight UTC and weeks begin on Monday.  Periods without any votes or revocations are omitted.",
+	"getstakerewards-period": "The length of each period (day, week, or month)",
+	"getstakerewards-since":  "Only include rewards mined at or after this Unix time",
+	"getstakerewards-until":  "Only include rewards mined before this Unix time",
+
+	// GetStakeRewardsResult help.
+	"getstakerewardsresult-start":        "Unix time of the beginning of the period",
+	"getstakerewardsresult-voted":        "Number of votes cast by wallet tickets",
+	"getstakerewardsresult-missed":       "Number of revoked tickets which were missed rather than expired",
+	"getstakerewardsresult-revoked":      "Number of wallet tickets revoked",
+	"getstakerewardsresult-totalsubsidy": "Total amount of coins earned by votes",
 
 	// GetTickets help.
 	"gettickets--synopsis":       "Returning the hashes of the tickets currently owned by wallet.",
@@ -179,10 +589,30 @@ var helpDescsEnUS = map[string]string{
 	"infowalletresult-keypoolsize":     "Unset",
 	"infowalletresult-keypoololdest":   "Unset",
 
+	// GetInfoResult help.
+	"getinforesult-version":         "The version of the server, or null when hcd is unreachable",
+	"getinforesult-protocolversion": "The latest supported protocol version, or null when hcd is unreachable",
+	"getinforesult-walletversion":   "The version of the address manager database",
+	"getinforesult-balance":         "The balance of all accounts calculated with one block confirmation",
+	"getinforesult-blocks":          "The number of blocks processed by hcd, or null when hcd is unreachable",
+	"getinforesult-timeoffset":      "The time offset, or null when hcd is unreachable",
+	"getinforesult-connections":     "The number of connected peers, or null when hcd is unreachable",
+	"getinforesult-proxy":           "The proxy used by the server, or null when hcd is unreachable",
+	"getinforesult-difficulty":      "The current target difficulty, or null when hcd is unreachable",
+	"getinforesult-testnet":         "Whether or not the wallet is using a test network",
+	"getinforesult-unlocked":        "Whether the wallet is unlocked",
+	"getinforesult-paytxfee":        "The fee per kB of the serialized tx size used each time more fee is required for an authored transaction",
+	"getinforesult-relayfee":        "The minimum relay fee of hcd for non-free transactions in HC/KB, or null when hcd is unreachable",
+	"getinforesult-errors":          "Any current errors of hcd, or null when hcd is unreachable",
+	"getinforesult-syncheight":      "The height of the most recent block the wallet has synced to",
+	"getinforesult-headerheight":    "The height of the best block known to hcd, or null when hcd is unreachable",
+	"getinforesult-progress":        "The wallet sync progress, from 0 to 1, as the sync height divided by the header height, or null when hcd is unreachable",
+
 	// GetNewAddressCmd help.
 	"getnewaddress--synopsis": "Generates and returns a new payment address.",
 	"getnewaddress-account":   "Account name the new address will belong to (default=\"default\")",
 	"getnewaddress-gappolicy": `String defining the policy to use when the BIP0044 gap limit would be violated, may be "error", "ignore", or "wrap"`,
+	"getnewaddress-branch":    "Name of an external branch of the account created by createexternalbranch to return the address from (default: the account's BIP0044 external branch)",
 	"getnewaddress--result0":  "The payment address",
 
 	// GetRawChangeAddressCmd help.
@@ -206,6 +636,8 @@ var helpDescsEnUS = map[string]string{
 	"gettransaction--synopsis":        "Returns a JSON object with details regarding a transaction relevant to this wallet.",
 	"gettransaction-txid":             "Hash of the transaction to query",
 	"gettransaction-includewatchonly": "Also consider transactions involving watched addresses",
+	"gettransaction-verbose":          "Also include the decoded inputs and outputs with their addresses, accounts, and spentness",
+	"gettransaction-currency":         "Also value the amount in this fiat currency at the price when the transaction was mined, or received if unmined (requires fiatpriceurl; an empty string selects the configured fiatcurrency)",
 
 	// HelpCmd help.
 	"help--synopsis":   "Returns a list of all commands or help for a specified command.",
@@ -228,6 +660,9 @@ var helpDescsEnUS = map[string]string{
 	"gettransactionresult-timereceived":    "The earliest Unix time this transaction was known to exist",
 	"gettransactionresult-details":         "Additional details for each recorded wallet credit and debit",
 	"gettransactionresult-hex":             "The transaction encoded as a hexadecimal string",
+	"gettransactionresult-fiat":            "The fiat valuation of the amount, if a currency was requested",
+	"gettransactionresult-vin":             "The transaction inputs, if verbose",
+	"gettransactionresult-vout":            "The transaction outputs, if verbose",
 
 	// GetTransactionDetailsResult help.
 	"gettransactiondetailsresult-account":           "DEPRECATED -- Unset",
@@ -238,6 +673,86 @@ var helpDescsEnUS = map[string]string{
 	"gettransactiondetailsresult-vout":              "The transaction output index",
 	"gettransactiondetailsresult-involveswatchonly": "Unset",
 
+	// CreateExternalBranchCmd help.
+	"createexternalbranch--synopsis": "Creates an additional external branch of an account, used to segregate the addresses given out for a purpose (such as donations or invoices) from the account's other receiving addresses.\n" +
+		"Addresses of the branch are returned by getnewaddress with the branch name. Branches are only supported by ECDSA accounts and are not discovered when restoring a wallet from its seed.",
+	"createexternalbranch-account":  "The name of the account",
+	"createexternalbranch-branch":   "The name of the new branch, unique to the account",
+	"createexternalbranch--result0": "The BIP0044 branch number of the new branch",
+
+	// ListExternalBranchesCmd help.
+	"listexternalbranches--synopsis": "Lists the additional external branches of an account created by createexternalbranch.",
+	"listexternalbranches-account":   "The name of the account",
+
+	// ExternalBranchResult help.
+	"externalbranchresult-branch":       "The BIP0044 branch number",
+	"externalbranchresult-name":         "The name of the branch",
+	"externalbranchresult-lastused":     "The index of the last used address of the branch, or -1 if none are used",
+	"externalbranchresult-lastreturned": "The index of the last address of the branch returned by the wallet, or -1 if none were returned",
+
+	// CreateMultisigAccountCmd help.
+	"createmultisigaccount--synopsis": "Creates an m-of-n multisig account shared with cosigners identified by their extended public keys.\n" +
+		"The wallet's keys of the account are derived from a new external branch of an account, and the returned extended public key of the branch must be recorded by each cosigner.\n" +
+		"Each address index pays to a P2SH multisig script of the child keys at that index of every extended public key, sorted as described by BIP0067.",
+	"createmultisigaccount-name":      "The name of the multisig account",
+	"createmultisigaccount-nrequired": "The number of signatures required to spend outputs of the account",
+	"createmultisigaccount-cosigners": "The extended public keys of the cosigners, excluding the wallet's key",
+	"createmultisigaccount-account":   "The account from which the wallet's keys are derived",
+
+	// CreateMultisigAccountResult help.
+	"createmultisigaccountresult-name":      "The name of the multisig account",
+	"createmultisigaccountresult-xpub":      "The extended public key of the wallet which the cosigners must record",
+	"createmultisigaccountresult-nrequired": "The number of signatures required to spend outputs of the account",
+	"createmultisigaccountresult-nkeys":     "The total number of keys, including the wallet's key",
+
+	// GetNewMultisigAddressCmd help.
+	"getnewmultisigaddress--synopsis": "Derives the next P2SH address of a multisig account created by createmultisigaccount. The wallet must be unlocked to record the redeem script.",
+	"getnewmultisigaddress-name":      "The name of the multisig account",
+	"getnewmultisigaddress--result0":  "The P2SH address",
+
+	// GetMultisigAccountInfoCmd help.
+	"getmultisigaccountinfo--synopsis": "Returns the keys, derived addresses, and balance of a multisig account created by createmultisigaccount.",
+	"getmultisigaccountinfo-name":      "The name of the multisig account",
+
+	// GetMultisigAccountInfoResult help.
+	"getmultisigaccountinforesult-name":      "The name of the multisig account",
+	"getmultisigaccountinforesult-account":   "The account from which the wallet's keys are derived",
+	"getmultisigaccountinforesult-nrequired": "The number of signatures required to spend outputs of the account",
+	"getmultisigaccountinforesult-nkeys":     "The total number of keys, including the wallet's key",
+	"getmultisigaccountinforesult-xpub":      "The extended public key of the wallet which the cosigners must record",
+	"getmultisigaccountinforesult-cosigners": "The extended public keys of the cosigners",
+	"getmultisigaccountinforesult-created":   "The Unix time the account was created",
+	"getmultisigaccountinforesult-balance":   "The total amount of unspent outputs of the account",
+	"getmultisigaccountinforesult-addresses": "The derived addresses of the account",
+
+	// MultisigAccountAddressResult help.
+	"multisigaccountaddressresult-index":        "The address index",
+	"multisigaccountaddressresult-address":      "The P2SH address",
+	"multisigaccountaddressresult-redeemscript": "The hex-encoded redeem script",
+	"multisigaccountaddressresult-balance":      "The total amount of unspent outputs paying the address",
+
+	// CreateMultisigSpendCmd help.
+	"createmultisigspend--synopsis": "Creates a transaction spending unspent outputs of a multisig account and signs it with the wallet's keys. Change is paid to a new address of the account.\n" +
+		"Transactions which are not complete must be signed by further cosigners with signrawtransaction before they are published.",
+	"createmultisigspend-name":           "The name of the multisig account",
+	"createmultisigspend-amounts":        "Pairs of payment addresses and the output amount to pay each",
+	"createmultisigspend-amounts--desc":  "JSON object using payment addresses as keys and output amounts valued in HC to send to each address",
+	"createmultisigspend-amounts--key":   "Address to pay",
+	"createmultisigspend-amounts--value": "Amount to send to the payment address valued in HC",
+
+	// CreateMultisigSpendResult help.
+	"createmultisigspendresult-hex":      "The hex-encoded transaction",
+	"createmultisigspendresult-fee":      "The fee paid by the transaction",
+	"createmultisigspendresult-complete": "Whether the transaction holds the required signatures of every input",
+
+	// ImportAccountCmd help.
+	"importaccount--synopsis": "Recreates an account from an extended key returned by exportaccount. The key's account number must be the next account number of the wallet. Extended private keys require an unlocked wallet, while extended public keys may only be imported by watching-only wallets.",
+	"importaccount-account":   "The name of the new account",
+	"importaccount-key":       "The extended private or public key of the account",
+	"importaccount-rescan":    "Rescan the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the account",
+	"importaccount-scanfrom":  "Block number for where to start rescan from",
+	"importaccount--result0":  "The account number of the new account",
+
 	// ImportPrivKeyCmd help.
 	"importprivkey--synopsis": "Imports a WIF-encoded private key to the 'imported' account.",
 	"importprivkey-privkey":   "The WIF-encoded private key",
@@ -251,6 +766,11 @@ var helpDescsEnUS = map[string]string{
 	"importscript-rescan":    "Rescansfdsfd the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the imported key",
 	"importscript-scanfrom":  "Block number for where to start rescan from",
 
+	// ImportVoteChoicesCmd help.
+	"importvotechoices--synopsis": "Sets the vote choices of multiple agendas of the latest supported stake version, such as those returned by exportvotechoices. No choice is set if any of them is invalid.",
+	"importvotechoices-choices":   "The choices to set for each agenda",
+	"importvotechoices-version":   "The stake version the choices were exported for, which must be the latest supported version when set",
+
 	// KeypoolRefillCmd help.
 	"keypoolrefill--synopsis": "DEPRECATED -- This request does nothing since no keypool is maintained.",
 	"keypoolrefill-newsize":   "Unused",
@@ -294,12 +814,14 @@ var helpDescsEnUS = map[string]string{
 	"listreceivedbyaddressresult-confirmations":     "Number of block confirmations of the most recent transaction relevant to the address",
 	"listreceivedbyaddressresult-txids":             "Transaction hashes of all transactions involving this address",
 	"listreceivedbyaddressresult-involvesWatchonly": "Unset",
+	"listreceivedbyaddressresult-reused":            "Whether the external address was paid by more than one transaction (only set when addressreusepolicy is flag or refuse)",
 
 	// ListSinceBlockCmd help.
 	"listsinceblock--synopsis":           "Returns a JSON array of objects listing details of all wallet transactions after some block.",
 	"listsinceblock-blockhash":           "Hash of the parent block of the first block to consider transactions from, or unset to list all transactions",
 	"listsinceblock-targetconfirmations": "Minimum number of block confirmations of the last block in the result object.  Must be 1 or greater.  Note: The transactions array in the result object is not affected by this parameter",
 	"listsinceblock-includewatchonly":    "Unused",
+	"listsinceblock-account":             "Only list transactions which credit or debit this account",
 	"listsinceblock--condition0":         "blockhash specified",
 	"listsinceblock--condition1":         "no blockhash specified",
 	"listsinceblock--result0":            "Lists all transactions, including unmined transactions, since the specified block",
@@ -336,25 +858,47 @@ var helpDescsEnUS = map[string]string{
 	"listtransactions-count":            "Maximum number of transactions to create results from",
 	"listtransactions-from":             "Number of transactions to skip before results are created",
 	"listtransactions-includewatchonly": "Unused",
+	"listtransactions-cursor":           "Return a page of at most count transactions following this cursor from a previous page, or the newest transactions for the empty string, instead of using from",
+	"listtransactions--condition0":      "cursor unset",
+	"listtransactions--condition1":      "cursor set",
+
+	// ListTransactionsPageResult help.
+	"listtransactionspageresult-transactions": "Verbose details of the transactions of the page, newest first",
+	"listtransactionspageresult-cursor":       "The cursor of the following page, unset after the last page",
 
 	// ListUnspentCmd help.
-	"listunspent--synopsis": "Returns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.",
-	"listunspent-minconf":   "Minimum number of block confirmations required before a transaction output is considered",
-	"listunspent-maxconf":   "Maximum number of block confirmations required before a transaction output is excluded",
-	"listunspent-addresses": "If set, limits the returned details to unspent outputs received by any of these payment addresses",
+	"listunspent--synopsis":     "Returns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.",
+	"listunspent-minconf":       "Minimum number of block confirmations required before a transaction output is considered",
+	"listunspent-maxconf":       "Maximum number of block confirmations required before a transaction output is excluded",
+	"listunspent-addresses":     "If set, limits the returned details to unspent outputs received by any of these payment addresses",
+	"listunspent-includelocked": "Also include outputs locked by lockunspent or transaction drafts, and immature outputs",
+	"listunspent-cursor":        "Return a page of at most count outputs following this cursor from a previous page, or the first outputs for the empty string",
+	"listunspent-count":         "Maximum number of outputs returned in a page (only used with cursor)",
+	"listunspent--condition0":   "cursor unset",
+	"listunspent--condition1":   "cursor set",
+
+	// ListUnspentPageResult help.
+	"listunspentpageresult-unspent": "The unspent outputs of the page, in a stable order of their outpoints which outputs keep when they are mined",
+	"listunspentpageresult-cursor":  "The cursor of the following page, unset after the last page",
 
 	// ListUnspentResult help.
-	"listunspentresult-txid":          "The transaction hash of the referenced output",
-	"listunspentresult-vout":          "The output index of the referenced output",
-	"listunspentresult-address":       "The payment address that received the output",
-	"listunspentresult-account":       "The account associated with the receiving payment address",
-	"listunspentresult-scriptPubKey":  "The output script encoded as a hexadecimal string",
-	"listunspentresult-redeemScript":  "Unset",
-	"listunspentresult-amount":        "The amount of the output valued in HC",
-	"listunspentresult-confirmations": "The number of block confirmations of the transaction",
-	"listunspentresult-spendable":     "Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses)",
-	"listunspentresult-txtype":        "The type of the transaction",
-	"listunspentresult-tree":          "The tree the transaction comes from",
+	"listunspentresult-txid":             "The transaction hash of the referenced output",
+	"listunspentresult-vout":             "The output index of the referenced output",
+	"listunspentresult-address":          "The payment address that received the output",
+	"listunspentresult-account":          "The account associated with the receiving payment address",
+	"listunspentresult-scriptPubKey":     "The output script encoded as a hexadecimal string",
+	"listunspentresult-redeemScript":     "The redeem script of a P2SH output, if the script is known and the wallet is unlocked",
+	"listunspentresult-amount":           "The amount of the output valued in HC",
+	"listunspentresult-confirmations":    "The number of block confirmations of the transaction",
+	"listunspentresult-spendable":        "Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses)",
+	"listunspentresult-reused":           "Whether the external address was paid by more than one transaction (only set when addressreusepolicy is flag or refuse)",
+	"listunspentresult-txtype":           "The type of the transaction",
+	"listunspentresult-tree":             "The tree the transaction comes from",
+	"listunspentresult-accountnumber":    "The number of the account associated with the receiving payment address",
+	"listunspentresult-scriptclass":      "The class of the output script",
+	"listunspentresult-locked":           "Whether the output is locked by lockunspent or reserved by a transaction draft",
+	"listunspentresult-ticketlocked":     "Whether the output is the stake submission of a ticket, locked until the ticket is voted or revoked",
+	"listunspentresult-blockstomaturity": "The number of blocks until an immature coinbase or stake output may be spent",
 
 	// LockUnspentCmd help.
 	"lockunspent--synopsis": "Locks or unlocks an unspent output.\n" +
@@ -368,13 +912,14 @@ var helpDescsEnUS = map[string]string{
 	// SendFromCmd help.
 	"sendfrom--synopsis": "DEPRECATED -- Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
-	"sendfrom-fromaccount": "Account to pick unspent outputs from",
-	"sendfrom-toaddress":   "Address to pay",
-	"sendfrom-amount":      "Amount to send to the payment address valued in HC",
-	"sendfrom-minconf":     "Minimum number of block confirmations required before a transaction output is eligible to be spent",
-	"sendfrom-comment":     "Unused",
-	"sendfrom-commentto":   "Unused",
-	"sendfrom--result0":    "The transaction hash of the sent transaction",
+	"sendfrom-fromaccount":    "Account to pick unspent outputs from",
+	"sendfrom-toaddress":      "Address to pay",
+	"sendfrom-amount":         "Amount to send to the payment address valued in HC",
+	"sendfrom-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent",
+	"sendfrom-comment":        "Unused",
+	"sendfrom-commentto":      "Unused",
+	"sendfrom-idempotencykey": "Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error",
+	"sendfrom--result0":       "The transaction hash of the sent transaction",
 
 	// SendManyCmd help.
 	"sendmany--synopsis": "Authors, signs, and sends a transaction that outputs to many payment addresses.\n" +
@@ -386,6 +931,7 @@ var helpDescsEnUS = map[string]string{
 	"sendmany-amounts--value": "Amount to send to the payment address valued in HC",
 	"sendmany-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent",
 	"sendmany-comment":        "Unused",
+	"sendmany-idempotencykey": "Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error",
 	"sendmany--result0":       "The transaction hash of the sent transaction",
 
 	// SendManyV2Cmd help.
@@ -398,17 +944,28 @@ var helpDescsEnUS = map[string]string{
 	"sendmanyv2-amounts--value": "Amount to send to the payment address valued in HC",
 	"sendmanyv2-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent",
 	"sendmanyv2-changeaddr":     "change addr, if not set, use account first first addr",
+	"sendmanyv2-idempotencykey": "Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error",
 	"sendmanyv2--result0":       "The transaction hash of the sent transaction",
 
+	// SendRawTransactionCmd help.
+	"sendrawtransaction--synopsis": "Submits a serialized transaction to hcd after checking it does not conflict with the wallet.\n" +
+		"Transactions spending outpoints which are locked or reserved by the wallet, or double spending unmined wallet transactions, are rejected.\n" +
+		"Transactions relevant to the wallet are recorded by the wallet before they are sent.\n" +
+		"Unless the wallet was started with --skippreflight, the scripts of each input are first validated against the outputs they spend, and invalid transactions error with code -25 instead of being sent.",
+	"sendrawtransaction-hextx":         "Serialized transaction to send, encoded as a hexadecimal string",
+	"sendrawtransaction-allowhighfees": "Allow the transaction to pay a fee above the high fee limit of hcd",
+	"sendrawtransaction--result0":      "The hash of the sent transaction",
+
 	// SendToAddressCmd help.
 	"sendtoaddress--synopsis": "Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"Unlike sendfrom, outputs are always chosen from the default account.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
-	"sendtoaddress-address":   "Address to pay",
-	"sendtoaddress-amount":    "Amount to send to the payment address valued in HC",
-	"sendtoaddress-comment":   "Unused",
-	"sendtoaddress-commentto": "Unused",
-	"sendtoaddress--result0":  "The transaction hash of the sent transaction",
+	"sendtoaddress-address":        "Address to pay",
+	"sendtoaddress-amount":         "Amount to send to the payment address valued in HC",
+	"sendtoaddress-comment":        "Unused",
+	"sendtoaddress-commentto":      "Unused",
+	"sendtoaddress-idempotencykey": "Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error",
+	"sendtoaddress--result0":       "The transaction hash of the sent transaction",
 	// SendFromaddressToAddressCmd help.
 	"sendfromaddresstoaddress--synopsis": "Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"Unlike sendfrom, outputs are always chosen from the default account.\n" +
@@ -417,6 +974,39 @@ var helpDescsEnUS = map[string]string{
 	"sendfromaddresstoaddress-address":     "Address to pay",
 	"sendfromaddresstoaddress-amount":      "Amount to send to the payment address valued in HC",
 	"sendfromaddresstoaddress--result0":    "The transaction hash of the sent transaction",
+	// SendBatchCmd help.
+	"sendbatch--synopsis": "Pays many addresses with as few transactions as the transaction size limits allow.\n" +
+		"Payments are assigned in order to transactions sized by estimating their signed size, each returning change to the account.\n" +
+		"In atomic mode, every transaction is created before any is published, so none is published unless every payment can be funded. The batch is not atomic once publishing begins: if the consensus server rejects a transaction, the earlier transactions remain published. Otherwise, transactions are published as they are created and an error stops the batch.\n" +
+		"An error is only returned when no transaction was published. Once a transaction is published, the error field of the result describes why any remaining payments were not made.",
+	"sendbatch-fromaccount": "The account funding the transactions",
+	"sendbatch-payments":    "CSV records of an address and an amount valued in HC, one payment per line; blank lines and lines beginning with '#' are ignored",
+	"sendbatch-minconf":     "Minimum number of block confirmations required of spent outputs",
+	"sendbatch-atomic":      "Create every transaction before publishing any; transactions published before the consensus server rejects a later one remain published",
+
+	// SendBatchResult help.
+	"sendbatchresult-txids":   "The hashes of the published transactions, in the order they were published",
+	"sendbatchresult-fee":     "The total fee paid by the published transactions",
+	"sendbatchresult-outputs": "The output paying each payment, in the order of the payments",
+	"sendbatchresult-error":   "Why the remaining payments were not made, if the batch stopped after publishing transactions",
+
+	// SendBatchOutput help.
+	"sendbatchoutput-address": "The payment address",
+	"sendbatchoutput-amount":  "The payment amount",
+	"sendbatchoutput-txid":    "The hash of the transaction paying the payment, or empty if the payment was not made",
+	"sendbatchoutput-vout":    "The index of the output paying the payment",
+
+	// SendDataCmd help.
+	"senddata--synopsis": "Authors, signs, and sends a transaction with an OP_RETURN output carrying arbitrary data.\n" +
+		"The data may not exceed 1024 bytes, the largest OP_RETURN payload relayed by hcd. A change output returns the remaining input value, less the fee, to the account.",
+	"senddata-data":    "The hex-encoded data",
+	"senddata-account": "The account funding the transaction",
+	"senddata-minconf": "Minimum number of block confirmations required of spent outputs",
+
+	// SendDataResult help.
+	"senddataresult-txid": "The hash of the sent transaction",
+	"senddataresult-fee":  "The fee paid by the transaction",
+
 	// SendToMultisigCmd help.
 	"sendtomultisig--synopsis": "Authors, signs, and sends a transaction that outputs some amount to a multisig address.\n" +
 		"Unlike sendfrom, outputs are always chosen from the default account.\n" +
@@ -441,6 +1031,7 @@ var helpDescsEnUS = map[string]string{
 	// SetTxFeeCmd help.
 	"settxfee--synopsis": "Modify the fee per kB of the serialized tx size used each time more fee is required for an authored transaction.",
 	"settxfee-amount":    "The new fee per kB of the serialized tx size valued in HC",
+	"settxfee-account":   "Set the fee for this account only rather than the wallet's default fee",
 	"settxfee--result0":  "The boolean 'true'",
 
 	// SetVoteChoice help.
@@ -452,20 +1043,39 @@ var helpDescsEnUS = map[string]string{
 	"signmessage--synopsis": "Signs a message using the private key of a payment address.",
 	"signmessage-address":   "Payment address of private key used to sign the message with",
 	"signmessage-message":   "Message to sign",
-	"signmessage--result0":  "The signed message encoded as a base64 string",
+	"signmessage--result0":  "The signed message encoded as a base64 string (signatures by bliss addresses use an extended format which includes the bliss public key)",
+
+	// SignMessageWithAccountCmd help.
+	"signmessagewithaccount--synopsis": "Signs a message with the account proof key of an account, proving control of the whole account rather than a single address.\n" +
+		"The proof key is derived from the account extended key at branch 2, index 0, which is never used for addresses.\n" +
+		"Requires an unlocked wallet, and only secp256k1 accounts sign account proofs.",
+	"signmessagewithaccount-account": "The account signing the message",
+	"signmessagewithaccount-message": "Message to sign",
+
+	// SignMessageWithAccountResult help.
+	"signmessagewithaccountresult-xpub":      "The extended public key of the account, which verifies the proof",
+	"signmessagewithaccountresult-signature": "The signed message encoded as a base64 string",
 
 	// SignRawTransactionCmd help.
 	"signrawtransaction--synopsis": "Signs transaction inputs using private keys from this wallet and request.\n" +
 		"The valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.",
 	"signrawtransaction-rawtx":    "Unsigned or partially unsigned transaction to sign encoded as a hexadecimal string",
-	"signrawtransaction-inputs":   "Additional data regarding inputs that this wallet may not be tracking",
-	"signrawtransaction-privkeys": "Additional WIF-encoded private keys to use when creating signatures",
+	"signrawtransaction-inputs":   "Additional data regarding inputs that this wallet may not be tracking, optionally including the amount of each previous output which is checked against the wallet and blockchain",
+	"signrawtransaction-privkeys": "Additional WIF-encoded secp256k1, edwards, schnorr or bliss private keys to use when creating signatures",
 	"signrawtransaction-flags":    "Sighash flags",
 
 	// SignRawTransactionResult help.
 	"signrawtransactionresult-hex":      "The resulting transaction encoded as a hexadecimal string",
 	"signrawtransactionresult-complete": "Whether all input signatures have been created",
 	"signrawtransactionresult-errors":   "Script verification errors (if exists)",
+	"signrawtransactionresult-fee":      "The fee paid by the transaction, when the value of every input is known",
+	"signrawtransactionresult-inputs":   "The value of each input of the transaction",
+
+	// SignRawTransactionInput help.
+	"signrawtransactioninput-txid":   "The transaction hash of the referenced previous output",
+	"signrawtransactioninput-vout":   "The output index of the referenced previous output",
+	"signrawtransactioninput-amount": "The value of the previous output, omitted when unknown",
+	"signrawtransactioninput-source": "Where the value was determined from: wallet or chain (verified), request or transaction (unverified), or stakebase",
 
 	// SignRawTransactionError help.
 	"signrawtransactionerror-error":     "Verification or signing error related to the input",
@@ -475,15 +1085,17 @@ var helpDescsEnUS = map[string]string{
 	"signrawtransactionerror-vout":      "The output index of the referenced previous output",
 
 	// SignRawTransactions help.
-	"signrawtransactions--synopsis": "Signs transaction inputs using private keys from this wallet and request for a list of transactions.\n",
-	"signrawtransactions-send":      "Set true to send the transactions after signing.",
-	"signrawtransactions-rawtxs":    "A list of transactions to sign (and optionally send).",
+	"signrawtransactions--synopsis":     "Signs transaction inputs using private keys from this wallet and request for a list of transactions.\n",
+	"signrawtransactions-send":          "Set true to send the transactions after signing.",
+	"signrawtransactions-rawtxs":        "A list of transactions to sign (and optionally send).",
+	"signrawtransactions-allowhighfees": "Allow sending transactions with high fees (default is the wallet's --allowhighfees setting).",
 
 	// SignRawTransactionsResults help.
-	"signrawtransactionsresult-results": "Returned values from the signrawtransactions command.",
-	"signedtransaction-txhash":          "The hash of the signed tx.",
-	"signedtransaction-sent":            "Tells if the transaction was sent.",
-	"signedtransaction-signingresult":   "Success or failure of signing.",
+	"signrawtransactionsresult-results":       "Returned values from the signrawtransactions command.",
+	"signrawtransactionsresult-allowhighfees": "Whether high fees were allowed when sending the transactions.",
+	"signedtransaction-txhash":                "The hash of the signed tx.",
+	"signedtransaction-sent":                  "Tells if the transaction was sent.",
+	"signedtransaction-signingresult":         "Success or failure of signing.",
 
 	// ValidateAddressCmd help.
 	"validateaddress--synopsis": "Verify that an address is valid.\n" +
@@ -509,15 +1121,43 @@ var helpDescsEnUS = map[string]string{
 	"validateaddresswalletresult-script":       "The class of redeem script for a multisig address",
 	"validateaddresswalletresult-sigsrequired": "The number of required signatures to redeem outputs to the multisig address",
 
+	// VerifyAccountProofCmd help.
+	"verifyaccountproof--synopsis": "Verify a message was signed by signmessagewithaccount with the account of an extended public key.",
+	"verifyaccountproof-xpub":      "The extended public key of the account",
+	"verifyaccountproof-signature": "The signature to verify",
+	"verifyaccountproof-message":   "The message to verify",
+	"verifyaccountproof--result0":  "Whether the message was signed by the account of 'xpub'",
+
 	// VerifyMessageCmd help.
 	"verifymessage--synopsis": "Verify a message was signed with the associated private key of some address.",
 	"verifymessage-address":   "Address used to sign message",
-	"verifymessage-signature": "The signature to verify",
+	"verifymessage-signature": "The signature to verify, in the extended format for bliss addresses",
 	"verifymessage-message":   "The message to verify",
 	"verifymessage--result0":  "Whether the message was signed with the private key of 'address'",
 
+	// VerifySeedBackupCmd help.
+	"verifyseedbackup--synopsis": "Returns the seed word positions of the wallet's seed backup challenge, and when words are provided, checks them against the seed the wallet was created from without revealing it.\n" +
+		"The seed backup is recorded as verified once all words match.\n" +
+		"Checking is intentionally slow, does not reveal which words did not match, and requires the wallet to be unlocked.",
+	"verifyseedbackup-words": "Mnemonic seed words at each of the challenge positions, in order (omit to only return the challenge)",
+
+	// VerifySeedBackupResult help.
+	"verifyseedbackupresult-positions": "One-based positions of the seed words checked by the challenge",
+	"verifyseedbackupresult-verified":  "Whether a backup of the seed has been verified",
+	"verifyseedbackupresult-match":     "Whether the provided words match the seed (only when words are provided)",
+
+	// VerifyTicketProofCmd help.
+	"verifyticketproof--synopsis": "Verify a challenge was signed by generateticketproof with the key of a commitment or voting address of a ticket.\n" +
+		"Tickets not recorded by the wallet are queried from the consensus server.",
+	"verifyticketproof-tickethash": "The hash of the ticket",
+	"verifyticketproof-address":    "The address which signed the challenge",
+	"verifyticketproof-signature":  "The signature to verify",
+	"verifyticketproof-challenge":  "The challenge to verify",
+	"verifyticketproof--result0":   "Whether 'address' is a commitment or voting address of the ticket and signed the challenge",
+
 	// Version help
-	"version--synopsis":       "Returns application and API versions (semver) keyed by their names",
+	"version--synopsis": "Returns application and API versions (semver) keyed by their names.\n" +
+		"The hcwalletresultversion major version is the latest result version, which clients may request with the resultversion member of request objects to receive extended result layouts (results default to the legacy layouts of version 1)",
 	"version--result0--desc":  "Version objects keyed by the program or API name",
 	"version--result0--key":   "Program or API name",
 	"version--result0--value": "Object containing the semantic version",
@@ -538,7 +1178,8 @@ var helpDescsEnUS = map[string]string{
 	// CreateNewAccountCmd help.
 	"createnewaccount--synopsis": "Creates a new account.\n" +
 		"The wallet must be unlocked for this request to succeed.",
-	"createnewaccount-account": "Name of the new account",
+	"createnewaccount-account":     "Name of the new account",
+	"createnewaccount-accounttype": "The type of the new account",
 
 	// ExportWatchingWalletCmd help.
 	"exportwatchingwallet--synopsis": "Creates and returns a duplicate of the wallet database without any private keys to be used as a watching-only wallet.",
@@ -564,8 +1205,15 @@ var helpDescsEnUS = map[string]string{
 	"listaddresstransactions-account":   "Unused (must be unset or \"*\")",
 
 	// ListAllTransactionsCmd help.
-	"listalltransactions--synopsis": "Returns a JSON array of objects in the same format as 'listtransactions' without limiting the number of returned objects.",
-	"listalltransactions-account":   "Unused (must be unset or \"*\")",
+	"listalltransactions--synopsis":   "Returns a JSON array of objects in the same format as 'listtransactions' without limiting the number of returned objects, unless a cursor is used to page through them.",
+	"listalltransactions-account":     "Unused (must be unset or \"*\")",
+	"listalltransactions-cursor":      "Return a page of at most count transactions following this cursor from a previous page, or the newest transactions for the empty string",
+	"listalltransactions-count":       "Maximum number of transactions returned in a page (only used with cursor)",
+	"listalltransactions--condition0": "cursor unset",
+	"listalltransactions--condition1": "cursor set",
+
+	// NotifyTipChangesCmd help.
+	"notifytipchanges--synopsis": "Send a tipchanged notification, with the hashes of attached and detached blocks and the new main chain height, each time the wallet processes a change to the main chain tip (websocket clients only).",
 
 	// RedeemMultiSigout help.
 	"redeemmultisigout--synopsis": "Takes the input and constructs a P2PKH paying to the specified address.",
@@ -586,31 +1234,76 @@ var helpDescsEnUS = map[string]string{
 
 	// RescanWallet help.
 	"rescanwallet--synopsis":   "Rescan the block chain for wallet data, blocking until the rescan completes or exits with an error",
-	"rescanwallet-beginheight": "The height of the first block to begin the rescan from",
+	"rescanwallet-beginheight": "The height of the first block to begin the rescan from (0 begins at the wallet birthday when the wallet has one)",
+	"rescanwallet--result0":    "The height of the block the rescan began from, which is the wallet birthday height when beginheight is 0 and the wallet has a birthday",
+
+	// ReserveOutputsCmd help.
+	"reserveoutputs--synopsis": "Selects spendable outputs of an account totaling at least an amount and locks them in a single step, so concurrent requests never reserve the same outputs.\n" +
+		"Outputs are selected largest first, and locked outputs are never selected.\n" +
+		"Reserved outputs are unlocked when the expiry elapses, or earlier with lockunspent.",
+	"reserveoutputs-amount":  "The minimum total amount of the reserved outputs",
+	"reserveoutputs-account": "The account to reserve outputs of",
+	"reserveoutputs-minconf": "Minimum number of block confirmations of the reserved outputs",
+	"reserveoutputs-expiry":  "Number of seconds until the outputs are unlocked (0 to keep them locked until unlocked with lockunspent)",
+
+	// ReserveOutputsResult help.
+	"reserveoutputsresult-outputs": "The reserved outputs",
+	"reserveoutputsresult-total":   "The total amount of the reserved outputs",
+	"reserveoutputsresult-expires": "The Unix time when the outputs are unlocked, or 0 if they remain locked",
+
+	// ReservedOutput help.
+	"reservedoutput-txid":         "The hash of the transaction of the output",
+	"reservedoutput-vout":         "The index of the output",
+	"reservedoutput-tree":         "The tree of the transaction of the output",
+	"reservedoutput-address":      "The address paid by the output",
+	"reservedoutput-scriptPubKey": "The output script of the output",
+	"reservedoutput-amount":       "The amount of the output",
 
 	// RevokeTickets help.
-	"revoketickets--synopsis": "Requests the wallet create revovactions for any previously missed tickets.  Wallet must be unlocked.",
+	"revoketickets--synopsis":           "Requests the wallet create revocations for previously missed and expired tickets, returning each revoked ticket with the hash of its revocation.  Wallet must be unlocked unless dryrun is set.",
+	"revoketickets-allowhighfees":       "Allow sending revocations with high fees (default=true, as revocation fees are paid from the ticket).",
+	"revoketickets-feerate":             "Fee per kB paid by each revocation (default is the relay fee)",
+	"revoketickets-tickets":             "Hashes of the missed or expired tickets to revoke (default is every unrevoked missed or expired ticket)",
+	"revoketickets-dryrun":              "Return the revocations which would be created without signing or publishing them",
+	"revoketicketsresult-allowhighfees": "Whether high fees were allowed when sending the revocations.",
+	"revoketicketsresult-dryrun":        "Whether the revocations were only created without being signed or published",
+	"revoketicketsresult-revocations":   "The revoked tickets and their revocations",
+	"revokedticketresult-ticket":        "The hash of the revoked ticket",
+	"revokedticketresult-revocation":    "The hash of the revocation transaction",
 
 	// RenameAccountCmd help.
 	"renameaccount--synopsis":  "Renames an account.",
 	"renameaccount-oldaccount": "The old account name to rename",
 	"renameaccount-newaccount": "The new name for the account",
 
+	// StopNotifyTipChangesCmd help.
+	"stopnotifytipchanges--synopsis": "Stop sending tipchanged notifications requested by notifytipchanges (websocket clients only).",
+
 	// WalletIsLockedCmd help.
 	"walletislocked--synopsis": "Returns whether or not the wallet is locked.",
 	"walletislocked--result0":  "Whether the wallet is locked",
 
 	// WalletInfoCmd help.
-	"walletinfo--synopsis":              "Returns global information about the wallet",
-	"walletinforesult-daemonconnected":  "Whether or not the wallet is currently connected to the daemon RPC",
-	"walletinforesult-unlocked":         "Whether or not the wallet is unlocked",
-	"walletinforesult-txfee":            "Transaction fee per kB of the serialized tx size in coins",
-	"walletinforesult-ticketfee":        "Ticket fee per kB of the serialized tx size in coins",
-	"walletinforesult-ticketpurchasing": "Whether or not the wallet is currently purchasing tickets",
-	"walletinforesult-votebits":         "Vote bits setting",
-	"walletinforesult-votebitsextended": "Extended vote bits setting",
-	"walletinforesult-voteversion":      "Version of votes that will be generated",
-	"walletinforesult-voting":           "Whether or not the wallet is currently voting tickets",
+	"walletinfo--synopsis":                 "Returns global information about the wallet",
+	"walletinforesult-daemonconnected":     "Whether or not the wallet is currently connected to the daemon RPC",
+	"walletinforesult-unlocked":            "Whether or not the wallet is unlocked",
+	"walletinforesult-txfee":               "Transaction fee per kB of the serialized tx size in coins",
+	"walletinforesult-ticketfee":           "Ticket fee per kB of the serialized tx size in coins",
+	"walletinforesult-ticketpurchasing":    "Whether or not the wallet is currently purchasing tickets",
+	"walletinforesult-votebits":            "Vote bits setting",
+	"walletinforesult-votebitsextended":    "Extended vote bits setting",
+	"walletinforesult-voteversion":         "Version of votes that will be generated",
+	"walletinforesult-voting":              "Whether or not the wallet is currently voting tickets",
+	"walletinforesult-rescanning":          "Whether or not a rescan is running",
+	"walletinforesult-rescanprogress":      "The fraction of the blocks from the rescan start height through the main chain tip which have been rescanned (only when rescanning)",
+	"walletinforesult-omnienabled":         "Whether or not omni is enabled",
+	"walletinforesult-omniwaterline":       "The height of the last block processed by omni (only when omni is enabled and responding)",
+	"walletinforesult-accounts":            "The number of accounts, including the imported account",
+	"walletinforesult-watchedaddresses":    "The number of active addresses watched for relevant transactions",
+	"walletinforesult-birthdayheight":      "The height of the wallet birthday block, or 0 when the birthday is unset or unresolved",
+	"walletinforesult-networkstakeversion": "Highest stake version of the blocks connected since the wallet was started",
+	"walletinforesult-voteversionoutdated": "Whether the stake version of the majority of recent blocks is newer than the vote version of the wallet",
+	"walletinforesult-skipstalevotes":      "Whether votes are not created while the vote version is outdated",
 
 	// TODO Alphabetize
 
@@ -626,8 +1319,48 @@ var helpDescsEnUS = map[string]string{
 	"stakepooluserinfo--synopsis": "Get user info for stakepool",
 	"stakepooluserinfo-user":      "The id of the user to be looked up",
 
-	"stakepooluserinforesult-invalid": "A list of invalid tickets that the user has added",
-	"stakepooluserinforesult-tickets": "A list of valid tickets that the user has added",
+	"stakepooluserinforesult-invalid":        "A list of invalid tickets that the user has added",
+	"stakepooluserinforesult-tickets":        "A list of valid tickets that the user has added",
+	"stakepooluserinforesult-invaliddetails": "Why each invalid ticket was rejected, in the same order as invalid",
+
+	// SetPoolFeeExemptionCmd help.
+	"setpoolfeeexemption--synopsis": "Exempts a stake pool user from the pool fee check, or removes the exemption.\n" +
+		"Tickets of exempt users which commit less than the required pool fee are accepted. Use reevaluatepooltickets to readmit previously rejected tickets.",
+	"setpoolfeeexemption-user":   "The voting address identifying the stake pool user",
+	"setpoolfeeexemption-exempt": "Whether the user is exempt from the pool fee check",
+
+	// ListPoolFeeExemptionsCmd help.
+	"listpoolfeeexemptions--synopsis": "Lists the stake pool users exempt from the pool fee check.",
+	"listpoolfeeexemptions--result0":  "The voting addresses of the exempt users",
+
+	// ReevaluatePoolTicketsCmd help.
+	"reevaluatepooltickets--synopsis": "Evaluates the rejected tickets of a stake pool user again, readmitting tickets which are now acceptable, such as after the user was exempted from the pool fee check.\n" +
+		"The rejection reasons of the remaining tickets are updated.",
+	"reevaluatepooltickets-user":     "The voting address identifying the stake pool user",
+	"reevaluatepooltickets--result0": "The hashes of the readmitted tickets",
+
+	// SyncAccountAddressesCmd help.
+	"syncaccountaddresses--synopsis": "Extends the addresses watched for transactions to the gap limit and lookahead past the last used address of every account branch,\n" +
+		"and returns the used, returned, and watched child indexes of each account. Addresses are watched ahead when usage approaches the last watched address.",
+
+	// SyncAccountAddressesResult help.
+	"syncaccountaddressesresult-account":       "The name of the account",
+	"syncaccountaddressesresult-accountnumber": "The number of the account",
+	"syncaccountaddressesresult-external":      "The child indexes of the external branch",
+	"syncaccountaddressesresult-internal":      "The child indexes of the internal (change) branch",
+
+	// AddressBranchUsage help.
+	"addressbranchusage-lastused":     "The index of the last used address, or -1 if none are used",
+	"addressbranchusage-lastreturned": "The index of the last address returned by the wallet, or -1 if none were returned",
+	"addressbranchusage-lastwatched":  "The index of the last address watched for transactions, or -1 if none are watched",
+
+	// InvalidPoolUserTicket help.
+	"invalidpooluserticket-ticket":       "The hash of the rejected ticket",
+	"invalidpooluserticket-reason":       "Why the ticket was rejected (\"feetoolow\", \"unknowncommitmentaddress\", \"parsefailure\", or \"unknown\" for tickets rejected before reasons were recorded)",
+	"invalidpooluserticket-ticketheight": "The height of the block which mined the rejected ticket",
+	"invalidpooluserticket-found":        "The pool fee committed by the ticket, for tickets with fees too low",
+	"invalidpooluserticket-required":     "The pool fee required of the ticket, for tickets with fees too low",
+	"invalidpooluserticket-detail":       "The commitment address of tickets with unknown commitment addresses, or the error of tickets which failed to parse",
 
 	"pooluserticket-spentbyheight": "The height in which the ticket was spent",
 	"pooluserticket-spentby":       "The vote in which the ticket was spent",
@@ -644,14 +1377,830 @@ var helpDescsEnUS = map[string]string{
 	"scriptinfo-address":      "The script address",
 	"scriptinfo-hash160":      "The script hash",
 
+	// ListRefundableScriptsCmd help.
+	"listrefundablescripts--synopsis": "Lists the imported scripts with an OP_CHECKLOCKTIMEVERIFY or OP_CHECKSEQUENCEVERIFY refund path paying a wallet key, such as atomic swap contracts, and their unspent outputs.",
+	"listrefundablescripts--result0":  "The refundable scripts",
+
+	// RefundableScriptResult help.
+	"refundablescriptresult-address":       "The P2SH address of the script",
+	"refundablescriptresult-redeemscript":  "The hex-encoded redeem script",
+	"refundablescriptresult-refundaddress": "The wallet address paid by the refund path",
+	"refundablescriptresult-locktime":      "The lock time of the refund path, as a block height or Unix time for absolute lock times, or an input sequence number for relative lock times",
+	"refundablescriptresult-relative":      "Whether the lock time is relative to the block of each output (OP_CHECKSEQUENCEVERIFY)",
+	"refundablescriptresult-outputs":       "The unspent outputs paying the script",
+
+	// RefundableOutputResult help.
+	"refundableoutputresult-txid":        "The hash of the transaction",
+	"refundableoutputresult-vout":        "The output index",
+	"refundableoutputresult-tree":        "The transaction tree",
+	"refundableoutputresult-amount":      "The output amount",
+	"refundableoutputresult-blockheight": "The height of the block mining the output, or -1 if unmined",
+	"refundableoutputresult-matured":     "Whether the output may be spent by the refund path in the next block",
+
+	// RefundScriptCmd help.
+	"refundscript--synopsis": "Spends every matured unspent output of a refundable script listed by listrefundablescripts by its refund path, paying the total less the relay fee, and publishes the transaction.\n" +
+		"The wallet must be unlocked to sign the refund.",
+	"refundscript-address":   "The P2SH address of the script",
+	"refundscript-toaddress": "The address to pay (default: a new internal address of the account of the refund key)",
+	"refundscript--result0":  "The hash of the published transaction",
+
+	// InitiateSwapCmd help.
+	"initiateswap--synopsis": "Generates a secret and publishes a transaction funding an atomic swap contract which pays the participant when redeemed with the secret, or may be refunded to the wallet after the lock time.\n" +
+		"The contract and contract transaction must be given to the participant to audit. The secret is recorded by the wallet and listed by listswaps.",
+	"initiateswap-address":  "The participant's secp256k1 pubkey hash address",
+	"initiateswap-amount":   "The amount paid to the contract",
+	"initiateswap-account":  "The account funding the contract and receiving refunds",
+	"initiateswap-locktime": "The lock time of the refund path, as a block height or Unix time (default: 48 hours from now)",
+
+	// ParticipateSwapCmd help.
+	"participateswap--synopsis": "Publishes a transaction funding an atomic swap contract which pays the initiator when redeemed with the preimage of the initiator's secret hash, or may be refunded to the wallet after the lock time.\n" +
+		"The secret revealed by the initiator's redemption of this contract is recorded by the wallet and listed by listswaps.",
+	"participateswap-address":    "The initiator's secp256k1 pubkey hash address",
+	"participateswap-amount":     "The amount paid to the contract",
+	"participateswap-secrethash": "The hex-encoded RIPEMD160 secret hash of the initiator's contract",
+	"participateswap-account":    "The account funding the contract and receiving refunds",
+	"participateswap-locktime":   "The lock time of the refund path, as a block height or Unix time (default: 24 hours from now)",
+
+	// AtomicSwapContractResult help.
+	"atomicswapcontractresult-contract":     "The hex-encoded contract script",
+	"atomicswapcontractresult-contractp2sh": "The P2SH address of the contract",
+	"atomicswapcontractresult-contracttx":   "The hex-encoded transaction funding the contract",
+	"atomicswapcontractresult-contracttxid": "The hash of the transaction funding the contract",
+	"atomicswapcontractresult-vout":         "The index of the contract output",
+	"atomicswapcontractresult-secrethash":   "The hex-encoded RIPEMD160 secret hash",
+	"atomicswapcontractresult-secret":       "The hex-encoded secret (initiateswap only)",
+	"atomicswapcontractresult-locktime":     "The lock time of the refund path",
+
+	// RedeemSwapCmd help.
+	"redeemswap--synopsis":  "Publishes a transaction redeeming the outputs of a counterparty's atomic swap contract paying a wallet key using the secret.",
+	"redeemswap-contract":   "The hex-encoded contract script",
+	"redeemswap-contracttx": "The hex-encoded transaction funding the contract",
+	"redeemswap-secret":     "The hex-encoded secret",
+	"redeemswap-toaddress":  "The address to pay (default: a new internal address of the account of the recipient key)",
+	"redeemswap--result0":   "The hash of the published transaction",
+
+	// RefundSwapCmd help.
+	"refundswap--synopsis":  "Publishes a transaction refunding the outputs of an atomic swap contract to a wallet key after the contract's lock time.",
+	"refundswap-contract":   "The hex-encoded contract script",
+	"refundswap-contracttx": "The hex-encoded transaction funding the contract",
+	"refundswap-toaddress":  "The address to pay (default: a new internal address of the account of the refund key)",
+	"refundswap--result0":   "The hash of the published transaction",
+
+	// ListSwapsCmd help.
+	"listswaps--synopsis": "Lists the atomic swap contracts funded by the wallet with initiateswap and participateswap, and the secret of each swap when known.",
+	"listswaps--result0":  "The atomic swaps",
+
+	// AtomicSwapResult help.
+	"atomicswapresult-contractp2sh": "The P2SH address of the contract",
+	"atomicswapresult-contract":     "The hex-encoded contract script",
+	"atomicswapresult-contracttxid": "The hash of the transaction funding the contract",
+	"atomicswapresult-vout":         "The index of the contract output",
+	"atomicswapresult-initiator":    "Whether the wallet initiated the swap",
+	"atomicswapresult-secrethash":   "The hex-encoded RIPEMD160 secret hash",
+	"atomicswapresult-secret":       "The hex-encoded secret, if known (secrets of initiated swaps which are not yet redeemed require the wallet to be unlocked)",
+	"atomicswapresult-locktime":     "The lock time of the refund path",
+	"atomicswapresult-created":      "The Unix time the contract was funded",
+
+	// ListStaleOmniPendingCmd help.
+	"liststaleomnipending--synopsis": "Lists the pending omni balance changes recorded by the wallet for transactions which have been mined, are no longer recorded by the wallet, or remain unmined after the pending expiry.\n" +
+		"Stale entries are removed as blocks are connected, and the balance changes of transactions which will never be mined are reversed.\n" +
+		"Entries are only listed before the next block is connected, when the omni engine failed to reverse them, or when the expiry differs from the configured one.",
+	"liststaleomnipending-expiry":   "Number of blocks after which a pending entry of an unmined transaction is stale (default: the configured omnipendingexpiry, 0 to never consider unmined transactions stale)",
+	"liststaleomnipending--result0": "The stale pending omni entries",
+
+	// StaleOmniPendingResult help.
+	"staleomnipendingresult-txid":   "The hash of the transaction",
+	"staleomnipendingresult-height": "The main chain height when the pending entry was added",
+	"staleomnipendingresult-reason": "Why the entry is stale (mined, removed, or expired)",
+
+	// OmniGetdustthresholdCmd help.
+	"omni_getdustthreshold--synopsis": "Returns the minimum value of an output paying an address which is relayed under the relay fee of the sending account.\n" +
+		"Omni reference outputs pay this value to the recipient of a transaction.",
+	"omni_getdustthreshold-address":     "The address paid by the output (default: the threshold of a pay-to-pubkey-hash output)",
+	"omni_getdustthreshold-fromaddress": "The address sending the transaction, whose account's relay fee is used (default: the relay fee of the default account)",
+
+	// OmniGetdustthresholdResult help.
+	"omnigetdustthresholdresult-threshold":  "The dust threshold valued in HC",
+	"omnigetdustthresholdresult-relayfee":   "The relay fee per kB the threshold is calculated with",
+	"omnigetdustthresholdresult-scriptsize": "The size of the output script paying the address",
+
+	// OmniEstimatefeeCmd help.
+	"omni_estimatefee--synopsis": "Estimates the fee of an omni transaction sending a payload.\n" +
+		"The transaction is constructed as by the wallet's omni sends, with a reference output paying the recipient (or the sending address when no recipient is provided), the payload output, and inputs funded by the sending address, but it is neither signed nor published.",
+	"omni_estimatefee-fromaddress":   "The address to send from",
+	"omni_estimatefee-payload":       "The hex-encoded omni payload of the transaction",
+	"omni_estimatefee-toaddress":     "The address of the recipient (default: the sending address)",
+	"omni_estimatefee-changeaddress": "The address receiving change (default: the default address of the sending account)",
+
+	// OmniEstimatefeeResult help.
+	"omniestimatefeeresult-type":            "The omni transaction type of the payload",
+	"omniestimatefeeresult-fee":             "The estimated fee valued in HC",
+	"omniestimatefeeresult-relayfee":        "The relay fee per kB of the sending account",
+	"omniestimatefeeresult-size":            "The estimated size of the signed transaction in bytes",
+	"omniestimatefeeresult-inputs":          "The number of inputs selected to fund the transaction",
+	"omniestimatefeeresult-totalinput":      "The total value of the selected inputs valued in HC",
+	"omniestimatefeeresult-referenceamount": "The value of the reference output valued in HC",
+	"omniestimatefeeresult-change":          "The value of the change output valued in HC, or 0 if there is no change",
+
+	// OmniSendCmd help.
+	"omni_send--synopsis":       "Creates and publishes a simple send transaction of omni tokens.",
+	"omni_send-fromaddress":     "The address to send from",
+	"omni_send-toaddress":       "The address of the recipient",
+	"omni_send-propertyid":      "The identifier of the tokens to send",
+	"omni_send-amount":          "The amount of tokens to send",
+	"omni_send-redeemaddress":   "An address that can spend the transaction dust (default: the sending address)",
+	"omni_send-referenceamount": "The value of the reference output paying the recipient valued in HC (default: the dust threshold)",
+	"omni_send--result0":        "The hash of the published transaction",
+
+	// OmniSenddexsellCmd help.
+	"omni_senddexsell--synopsis":         "Places, updates or cancels a sell offer on the distributed OMNI/HC exchange.",
+	"omni_senddexsell-fromaddress":       "The address to send from",
+	"omni_senddexsell-propertyidforsale": "The identifier of the tokens to list for sale (1 for OMNI, 2 for TOMNI)",
+	"omni_senddexsell-amountforsale":     "The amount of tokens to list for sale",
+	"omni_senddexsell-amountdesired":     "The amount desired valued in HC",
+	"omni_senddexsell-paymentwindow":     "The number of blocks a buyer has to pay after accepting the offer",
+	"omni_senddexsell-minacceptfee":      "The minimum fee a buyer has to pay to accept the offer",
+	"omni_senddexsell-action":            "The action to take (1 for new offers, 2 to update, 3 to cancel)",
+	"omni_senddexsell--result0":          "The hash of the published transaction",
+
+	// OmniSenddexacceptCmd help.
+	"omni_senddexaccept--synopsis":   "Accepts an offer on the distributed OMNI/HC exchange.",
+	"omni_senddexaccept-fromaddress": "The address to send from",
+	"omni_senddexaccept-toaddress":   "The address of the seller",
+	"omni_senddexaccept-propertyid":  "The identifier of the tokens to purchase",
+	"omni_senddexaccept-amount":      "The amount of tokens to accept",
+	"omni_senddexaccept-override":    "Override the minimum accept fee and payment window checks",
+	"omni_senddexaccept--result0":    "The hash of the published transaction",
+
+	// OmniSendissuancecrowdsaleCmd help.
+	"omni_sendissuancecrowdsale--synopsis":         "Creates new tokens issued by a crowdsale.",
+	"omni_sendissuancecrowdsale-fromaddress":       "The address to send from",
+	"omni_sendissuancecrowdsale-ecosystem":         "The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_sendissuancecrowdsale-typo":              "The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)",
+	"omni_sendissuancecrowdsale-previousid":        "The identifier of a predecessor token (0 for new crowdsales)",
+	"omni_sendissuancecrowdsale-category":          "The category of the new tokens (can be empty)",
+	"omni_sendissuancecrowdsale-subcategory":       "The subcategory of the new tokens (can be empty)",
+	"omni_sendissuancecrowdsale-name":              "The name of the new tokens",
+	"omni_sendissuancecrowdsale-url":               "A URL with further information about the new tokens (can be empty)",
+	"omni_sendissuancecrowdsale-data":              "A description of the new tokens (can be empty)",
+	"omni_sendissuancecrowdsale-propertyiddesired": "The identifier of the tokens eligible to participate in the crowdsale",
+	"omni_sendissuancecrowdsale-tokensperunit":     "The amount of tokens granted per unit invested in the crowdsale",
+	"omni_sendissuancecrowdsale-deadline":          "The Unix time of the crowdsale deadline",
+	"omni_sendissuancecrowdsale-earlybonus":        "The early bird bonus for participants in percent per week",
+	"omni_sendissuancecrowdsale-issuerpercentage":  "The percentage of tokens granted to the issuer",
+	"omni_sendissuancecrowdsale--result0":          "The hash of the published transaction",
+
+	// OmniSendissuancefixedCmd help.
+	"omni_sendissuancefixed--synopsis":   "Creates new tokens with a fixed supply.",
+	"omni_sendissuancefixed-fromaddress": "The address to send from",
+	"omni_sendissuancefixed-ecosystem":   "The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_sendissuancefixed-typo":        "The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)",
+	"omni_sendissuancefixed-previousid":  "The identifier of a predecessor token (0 for new tokens)",
+	"omni_sendissuancefixed-category":    "The category of the new tokens (can be empty)",
+	"omni_sendissuancefixed-subcategory": "The subcategory of the new tokens (can be empty)",
+	"omni_sendissuancefixed-name":        "The name of the new tokens",
+	"omni_sendissuancefixed-url":         "A URL with further information about the new tokens (can be empty)",
+	"omni_sendissuancefixed-data":        "A description of the new tokens (can be empty)",
+	"omni_sendissuancefixed-amount":      "The number of tokens to create",
+	"omni_sendissuancefixed--result0":    "The hash of the published transaction",
+
+	// OmniSendissuancemanagedCmd help.
+	"omni_sendissuancemanaged--synopsis":   "Creates new tokens with a supply managed by the issuer.",
+	"omni_sendissuancemanaged-fromaddress": "The address to send from",
+	"omni_sendissuancemanaged-ecosystem":   "The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_sendissuancemanaged-typo":        "The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)",
+	"omni_sendissuancemanaged-previousid":  "The identifier of a predecessor token (0 for new tokens)",
+	"omni_sendissuancemanaged-category":    "The category of the new tokens (can be empty)",
+	"omni_sendissuancemanaged-subcategory": "The subcategory of the new tokens (can be empty)",
+	"omni_sendissuancemanaged-name":        "The name of the new tokens",
+	"omni_sendissuancemanaged-url":         "A URL with further information about the new tokens (can be empty)",
+	"omni_sendissuancemanaged-data":        "A description of the new tokens (can be empty)",
+	"omni_sendissuancemanaged--result0":    "The hash of the published transaction",
+
+	// OmniSendstoCmd help.
+	"omni_sendsto--synopsis":            "Distributes tokens to the holders of a property in proportion to their holdings.",
+	"omni_sendsto-fromaddress":          "The address to send from",
+	"omni_sendsto-propertyid":           "The identifier of the tokens to distribute",
+	"omni_sendsto-amount":               "The amount of tokens to distribute",
+	"omni_sendsto-redeemaddress":        "An address that can spend the transaction dust (default: the sending address)",
+	"omni_sendsto-distributionproperty": "The identifier of the property whose holders receive the tokens (default: the distributed property)",
+	"omni_sendsto--result0":             "The hash of the published transaction",
+
+	// OmniSendgrantCmd help.
+	"omni_sendgrant--synopsis":   "Issues new units of managed tokens.",
+	"omni_sendgrant-fromaddress": "The address to send from",
+	"omni_sendgrant-toaddress":   "The address receiving the tokens (empty for the sending address)",
+	"omni_sendgrant-propertyid":  "The identifier of the tokens to grant",
+	"omni_sendgrant-amount":      "The amount of tokens to create",
+	"omni_sendgrant-memo":        "A note attached to the transaction",
+	"omni_sendgrant--result0":    "The hash of the published transaction",
+
+	// OmniSendrevokeCmd help.
+	"omni_sendrevoke--synopsis":   "Revokes units of managed tokens.",
+	"omni_sendrevoke-fromaddress": "The address to send from",
+	"omni_sendrevoke-propertyid":  "The identifier of the tokens to revoke",
+	"omni_sendrevoke-amount":      "The amount of tokens to revoke",
+	"omni_sendrevoke-memo":        "A note attached to the transaction",
+	"omni_sendrevoke--result0":    "The hash of the published transaction",
+
+	// OmniSendclosecrowdsaleCmd help.
+	"omni_sendclosecrowdsale--synopsis":   "Closes a crowdsale before its deadline.",
+	"omni_sendclosecrowdsale-fromaddress": "The address of the crowdsale issuer",
+	"omni_sendclosecrowdsale-propertyid":  "The identifier of the crowdsale to close",
+	"omni_sendclosecrowdsale--result0":    "The hash of the published transaction",
+
+	// OmniSendtradeCmd help.
+	"omni_sendtrade--synopsis":         "Places a trade offer on the distributed token exchange.",
+	"omni_sendtrade-fromaddress":       "The address to trade with",
+	"omni_sendtrade-propertyidforsale": "The identifier of the tokens to list for sale",
+	"omni_sendtrade-amountforsale":     "The amount of tokens to list for sale",
+	"omni_sendtrade-propertiddesired":  "The identifier of the tokens desired in exchange",
+	"omni_sendtrade-amountdesired":     "The amount of tokens desired in exchange",
+	"omni_sendtrade--result0":          "The hash of the published transaction",
+
+	// OmniSendcanceltradesbypriceCmd help.
+	"omni_sendcanceltradesbyprice--synopsis":         "Cancels the offers on the distributed token exchange with the given currency pair and price.",
+	"omni_sendcanceltradesbyprice-fromaddress":       "The address to trade with",
+	"omni_sendcanceltradesbyprice-propertyidforsale": "The identifier of the tokens listed for sale",
+	"omni_sendcanceltradesbyprice-amountforsale":     "The amount of tokens listed for sale",
+	"omni_sendcanceltradesbyprice-propertiddesired":  "The identifier of the tokens desired in exchange",
+	"omni_sendcanceltradesbyprice-amountdesired":     "The amount of tokens desired in exchange",
+	"omni_sendcanceltradesbyprice--result0":          "The hash of the published transaction",
+
+	// OmniSendcanceltradesbypairCmd help.
+	"omni_sendcanceltradesbypair--synopsis":         "Cancels all offers on the distributed token exchange with the given currency pair.",
+	"omni_sendcanceltradesbypair-fromaddress":       "The address to trade with",
+	"omni_sendcanceltradesbypair-propertyidforsale": "The identifier of the tokens listed for sale",
+	"omni_sendcanceltradesbypair-propertiddesired":  "The identifier of the tokens desired in exchange",
+	"omni_sendcanceltradesbypair--result0":          "The hash of the published transaction",
+
+	// OmniSendcancelalltradesCmd help.
+	"omni_sendcancelalltrades--synopsis":   "Cancels all offers of an address on the distributed token exchange.",
+	"omni_sendcancelalltrades-fromaddress": "The address to trade with",
+	"omni_sendcancelalltrades-ecosystem":   "The ecosystem of the offers to cancel (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_sendcancelalltrades--result0":    "The hash of the published transaction",
+
+	// OmniSendchangeissuerCmd help.
+	"omni_sendchangeissuer--synopsis":   "Transfers administrative control of tokens to another address.",
+	"omni_sendchangeissuer-fromaddress": "The address of the current issuer",
+	"omni_sendchangeissuer-toaddress":   "The address receiving administrative control",
+	"omni_sendchangeissuer-propertyid":  "The identifier of the tokens",
+	"omni_sendchangeissuer--result0":    "The hash of the published transaction",
+
+	// OmniSendallCmd help.
+	"omni_sendall--synopsis":       "Transfers all tokens of an ecosystem held by an address.",
+	"omni_sendall-fromaddress":     "The address to send from",
+	"omni_sendall-toaddress":       "The address of the recipient",
+	"omni_sendall-ecosystem":       "The ecosystem of the tokens to send (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_sendall-redeemaddress":   "An address that can spend the transaction dust (default: the sending address)",
+	"omni_sendall-referenceamount": "The value of the reference output paying the recipient valued in HC (default: the dust threshold)",
+	"omni_sendall--result0":        "The hash of the published transaction",
+
+	// OmniSendenablefreezingCmd help.
+	"omni_sendenablefreezing--synopsis":   "Enables address freezing for managed tokens.",
+	"omni_sendenablefreezing-fromaddress": "The address of the issuer",
+	"omni_sendenablefreezing-propertyid":  "The identifier of the tokens",
+	"omni_sendenablefreezing--result0":    "The hash of the published transaction",
+
+	// OmniSenddisablefreezingCmd help.
+	"omni_senddisablefreezing--synopsis":   "Disables address freezing for managed tokens, unfreezing all frozen addresses.",
+	"omni_senddisablefreezing-fromaddress": "The address of the issuer",
+	"omni_senddisablefreezing-propertyid":  "The identifier of the tokens",
+	"omni_senddisablefreezing--result0":    "The hash of the published transaction",
+
+	// OmniSendfreezeCmd help.
+	"omni_sendfreeze--synopsis":   "Freezes an address for managed tokens with freezing enabled.",
+	"omni_sendfreeze-fromaddress": "The address of the issuer",
+	"omni_sendfreeze-toaddress":   "The address to freeze",
+	"omni_sendfreeze-propertyid":  "The identifier of the tokens to freeze",
+	"omni_sendfreeze-amount":      "Unused, frozen addresses can not transact the tokens",
+	"omni_sendfreeze--result0":    "The hash of the published transaction",
+
+	// OmniSendunfreezeCmd help.
+	"omni_sendunfreeze--synopsis":   "Unfreezes an address for managed tokens with freezing enabled.",
+	"omni_sendunfreeze-fromaddress": "The address of the issuer",
+	"omni_sendunfreeze-toaddress":   "The address to unfreeze",
+	"omni_sendunfreeze-propertyid":  "The identifier of the tokens to unfreeze",
+	"omni_sendunfreeze-amount":      "Unused",
+	"omni_sendunfreeze--result0":    "The hash of the published transaction",
+
+	// OmniSendrawtxCmd help.
+	"omni_sendrawtx--synopsis":        "Publishes a raw omni transaction.",
+	"omni_sendrawtx-fromaddress":      "The address to send from",
+	"omni_sendrawtx-rawtransaction":   "The hex-encoded raw transaction",
+	"omni_sendrawtx-referenceaddress": "A reference address (default: none)",
+	"omni_sendrawtx-redeemaddress":    "An address that can spend the transaction dust (default: the sending address)",
+	"omni_sendrawtx-referenceamount":  "The value of the reference output valued in HC (default: the dust threshold)",
+	"omni_sendrawtx--result0":         "The hash of the published transaction",
+
+	// OmniFundedSendCmd help.
+	"omni_funded_send--synopsis":   "Creates and publishes a simple send transaction of omni tokens whose fee is paid by another address.",
+	"omni_funded_send-fromaddress": "The address to send from",
+	"omni_funded_send-toaddress":   "The address of the recipient",
+	"omni_funded_send-propertyid":  "The identifier of the tokens to send",
+	"omni_funded_send-amount":      "The amount of tokens to send",
+	"omni_funded_send-feeaddress":  "The address paying the transaction fee and receiving change",
+	"omni_funded_send--result0":    "The hash of the published transaction",
+
+	// OmniFundedSendallCmd help.
+	"omni_funded_sendall--synopsis":   "Transfers all tokens of an ecosystem held by an address, with the fee paid by another address.",
+	"omni_funded_sendall-fromaddress": "The address to send from",
+	"omni_funded_sendall-toaddress":   "The address of the recipient",
+	"omni_funded_sendall-ecosystem":   "The ecosystem of the tokens to send (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_funded_sendall-feeaddress":  "The address paying the transaction fee and receiving change",
+	"omni_funded_sendall--result0":    "The hash of the published transaction",
+
+	// OmniGetinfoCmd help.
+	"omni_getinfo--synopsis":       "Returns the state of the omni engine, including its version and the last processed block.",
+	"omni_getinfo--result0--desc":  "JSON object describing the omni engine",
+	"omni_getinfo--result0--key":   "The name of the state",
+	"omni_getinfo--result0--value": "The value of the state",
+
+	// OmniGetbalanceCmd help.
+	"omni_getbalance--synopsis":       "Returns the token balance of an address.",
+	"omni_getbalance-address":         "The address",
+	"omni_getbalance-propertyid":      "The identifier of the tokens",
+	"omni_getbalance--result0--desc":  "JSON object with the available, reserved and frozen balances",
+	"omni_getbalance--result0--key":   "The kind of balance (balance, reserved, or frozen)",
+	"omni_getbalance--result0--value": "The balance as a string",
+
+	// OmniGetallbalancesforidCmd help.
+	"omni_getallbalancesforid--synopsis":       "Returns the balances of every address holding tokens.",
+	"omni_getallbalancesforid-propertyid":      "The identifier of the tokens",
+	"omni_getallbalancesforid--result0--desc":  "JSON object with an address and its available, reserved and frozen balances",
+	"omni_getallbalancesforid--result0--key":   "The name of the field",
+	"omni_getallbalancesforid--result0--value": "The value of the field",
+
+	// OmniGetallbalancesforaddressCmd help.
+	"omni_getallbalancesforaddress--synopsis":       "Returns the balances of every token held by an address.",
+	"omni_getallbalancesforaddress-address":         "The address",
+	"omni_getallbalancesforaddress--result0--desc":  "JSON object with a property identifier, its name, and its available, reserved and frozen balances",
+	"omni_getallbalancesforaddress--result0--key":   "The name of the field",
+	"omni_getallbalancesforaddress--result0--value": "The value of the field",
+
+	// OmniGetwalletbalancesCmd help.
+	"omni_getwalletbalances--synopsis":       "Returns the total balances of every token held by the wallet's addresses.",
+	"omni_getwalletbalances--result0--desc":  "JSON object with a property identifier, its name, and its available, reserved and frozen balances",
+	"omni_getwalletbalances--result0--key":   "The name of the field",
+	"omni_getwalletbalances--result0--value": "The value of the field",
+
+	// OmniGetwalletaddressbalancesCmd help.
+	"omni_getwalletaddressbalances--synopsis":       "Returns the token balances of every wallet address.",
+	"omni_getwalletaddressbalances--result0--desc":  "JSON object with an address and the balances of every token it holds",
+	"omni_getwalletaddressbalances--result0--key":   "The name of the field",
+	"omni_getwalletaddressbalances--result0--value": "The value of the field",
+
+	// OmniGettransactionCmd help.
+	"omni_gettransaction--synopsis":       "Returns detailed information about an omni transaction.",
+	"omni_gettransaction-txid":            "The hash of the transaction",
+	"omni_gettransaction--result0--desc":  "JSON object describing the transaction, including its sender, reference address, validity and type specific fields",
+	"omni_gettransaction--result0--key":   "The name of the field",
+	"omni_gettransaction--result0--value": "The value of the field",
+
+	// OmniListtransactionsCmd help.
+	"omni_listtransactions--synopsis":       "Lists omni transactions.",
+	"omni_listtransactions-txid":            "The address to filter transactions by (default: \"*\" for all addresses)",
+	"omni_listtransactions-count":           "The maximum number of transactions to list (default: 10)",
+	"omni_listtransactions-skip":            "The number of transactions to skip (default: 0)",
+	"omni_listtransactions-startblock":      "The first block to search (default: 0)",
+	"omni_listtransactions-endblock":        "The last block to search (default: 999999999)",
+	"omni_listtransactions--result0--desc":  "JSON object describing a transaction, as returned by omni_gettransaction",
+	"omni_listtransactions--result0--key":   "The name of the field",
+	"omni_listtransactions--result0--value": "The value of the field",
+
+	// OmniListwallettransactionsCmd help.
+	"omni_listwallettransactions--synopsis":       "Lists the omni transactions of the wallet's addresses.",
+	"omni_listwallettransactions-addrlist":        "Unused, the transactions of every wallet address are listed",
+	"omni_listwallettransactions-count":           "The maximum number of transactions to list (default: 10)",
+	"omni_listwallettransactions-skip":            "The number of transactions to skip (default: 0)",
+	"omni_listwallettransactions-startblock":      "The first block to search (default: 0)",
+	"omni_listwallettransactions-endblock":        "The last block to search (default: 999999999)",
+	"omni_listwallettransactions--result0--desc":  "JSON object describing a transaction, as returned by omni_gettransaction",
+	"omni_listwallettransactions--result0--key":   "The name of the field",
+	"omni_listwallettransactions--result0--value": "The value of the field",
+
+	// OmniListblocktransactionsCmd help.
+	"omni_listblocktransactions--synopsis": "Lists the omni transactions of the wallet's addresses in a block.",
+	"omni_listblocktransactions-height":    "The height of the block",
+	"omni_listblocktransactions--result0":  "The hashes of the transactions",
+
+	// OmniListpendingtransactionsCmd help.
+	"omni_listpendingtransactions--synopsis": "Lists unconfirmed omni transactions.\n" +
+		"The validity of pending transactions is uncertain, and they should be considered invalid until they are mined.",
+	"omni_listpendingtransactions-address":         "The address to filter transactions by (default: every wallet address)",
+	"omni_listpendingtransactions--result0--desc":  "JSON object describing a transaction, as returned by omni_gettransaction",
+	"omni_listpendingtransactions--result0--key":   "The name of the field",
+	"omni_listpendingtransactions--result0--value": "The value of the field",
+
+	// OmniGetactivedexsellsCmd help.
+	"omni_getactivedexsells--synopsis":       "Lists the active offers on the distributed OMNI/HC exchange.",
+	"omni_getactivedexsells--result0--desc":  "JSON object describing an offer and the accepts of it",
+	"omni_getactivedexsells--result0--key":   "The name of the field",
+	"omni_getactivedexsells--result0--value": "The value of the field",
+
+	// OmniListpropertiesCmd help.
+	"omni_listproperties--synopsis":       "Lists all tokens and smart properties.",
+	"omni_listproperties--result0--desc":  "JSON object with a property identifier, its name, category, URL, data and divisibility",
+	"omni_listproperties--result0--key":   "The name of the field",
+	"omni_listproperties--result0--value": "The value of the field",
+
+	// OmniGetpropertyCmd help.
+	"omni_getproperty--synopsis":       "Returns details of tokens or a smart property.",
+	"omni_getproperty-propertyid":      "The identifier of the tokens or property",
+	"omni_getproperty-currentheight":   "Unused, the height of the main chain tip is used",
+	"omni_getproperty--result0--desc":  "JSON object describing the property, including its issuer, creation transaction and total tokens",
+	"omni_getproperty--result0--key":   "The name of the field",
+	"omni_getproperty--result0--value": "The value of the field",
+
+	// OmniGetactivecrowdsalesCmd help.
+	"omni_getactivecrowdsales--synopsis":       "Lists the active crowdsales.",
+	"omni_getactivecrowdsales--result0--desc":  "JSON object describing a crowdsale, including its issuer, desired property, rate and deadline",
+	"omni_getactivecrowdsales--result0--key":   "The name of the field",
+	"omni_getactivecrowdsales--result0--value": "The value of the field",
+
+	// OmniGetcrowdsaleCmd help.
+	"omni_getcrowdsale--synopsis":       "Returns information about a crowdsale.",
+	"omni_getcrowdsale-propertyid":      "The identifier of the crowdsale",
+	"omni_getcrowdsale-verbose":         "List the participants of the crowdsale (default: false)",
+	"omni_getcrowdsale--result0--desc":  "JSON object describing the crowdsale, including its issuer, desired property, rate, deadline and raised tokens",
+	"omni_getcrowdsale--result0--key":   "The name of the field",
+	"omni_getcrowdsale--result0--value": "The value of the field",
+
+	// OmniGetgrantsCmd help.
+	"omni_getgrants--synopsis":       "Returns the grants and revocations of managed tokens.",
+	"omni_getgrants-propertyid":      "The identifier of the managed tokens",
+	"omni_getgrants--result0--desc":  "JSON object describing the tokens, their issuer, total tokens, and the list of grants and revocations",
+	"omni_getgrants--result0--key":   "The name of the field",
+	"omni_getgrants--result0--value": "The value of the field",
+
+	// OmniGetstoCmd help.
+	"omni_getsto--synopsis":       "Returns information and recipients of a send to owners transaction.",
+	"omni_getsto-txid":            "The hash of the transaction",
+	"omni_getsto-recipientfilter": "The recipients to list (default: the wallet's addresses, \"*\" for all)",
+	"omni_getsto--result0--desc":  "JSON object describing the transaction and its recipients",
+	"omni_getsto--result0--key":   "The name of the field",
+	"omni_getsto--result0--value": "The value of the field",
+
+	// OmniGettradeCmd help.
+	"omni_gettrade--synopsis":       "Returns information about an order on the distributed token exchange.",
+	"omni_gettrade-txid":            "The hash of the order",
+	"omni_gettrade--result0--desc":  "JSON object describing the order, its status and its matches",
+	"omni_gettrade--result0--key":   "The name of the field",
+	"omni_gettrade--result0--value": "The value of the field",
+
+	// OmniGetorderbookCmd help.
+	"omni_getorderbook--synopsis":         "Lists the active offers on the distributed token exchange.",
+	"omni_getorderbook-salepropertyid":    "The identifier of the tokens for sale",
+	"omni_getorderbook-desiredpropertyid": "The identifier of the tokens desired (default: any)",
+	"omni_getorderbook--result0--desc":    "JSON object describing an order, as returned by omni_gettrade",
+	"omni_getorderbook--result0--key":     "The name of the field",
+	"omni_getorderbook--result0--value":   "The value of the field",
+
+	// OmniGettradehistoryforpairCmd help.
+	"omni_gettradehistoryforpair--synopsis":        "Lists the trades of a currency pair on the distributed token exchange.",
+	"omni_gettradehistoryforpair-firstpropertyid":  "The identifier of the first side of the traded pair",
+	"omni_gettradehistoryforpair-secondpropertyid": "The identifier of the second side of the traded pair",
+	"omni_gettradehistoryforpair-count":            "The maximum number of trades to list (default: 10)",
+	"omni_gettradehistoryforpair--result0--desc":   "JSON object describing a trade, including the amounts sold and bought by each side",
+	"omni_gettradehistoryforpair--result0--key":    "The name of the field",
+	"omni_gettradehistoryforpair--result0--value":  "The value of the field",
+
+	// OmniGettradehistoryforaddressCmd help.
+	"omni_gettradehistoryforaddress--synopsis":       "Lists the orders of an address on the distributed token exchange.",
+	"omni_gettradehistoryforaddress-address":         "The address",
+	"omni_gettradehistoryforaddress-count":           "The maximum number of orders to list (default: 10)",
+	"omni_gettradehistoryforaddress-propertyid":      "The identifier of the traded tokens to filter orders by (default: no filter)",
+	"omni_gettradehistoryforaddress--result0--desc":  "JSON object describing an order, as returned by omni_gettrade",
+	"omni_gettradehistoryforaddress--result0--key":   "The name of the field",
+	"omni_gettradehistoryforaddress--result0--value": "The value of the field",
+
+	// OmniGetactivationsCmd help.
+	"omni_getactivations--synopsis":       "Returns the pending and completed feature activations.",
+	"omni_getactivations--result0--desc":  "JSON object with the lists of pending and completed activations",
+	"omni_getactivations--result0--key":   "The kind of activation (pendingactivations or completedactivations)",
+	"omni_getactivations--result0--value": "The activations",
+
+	// OmniGetpayloadCmd help.
+	"omni_getpayload--synopsis":       "Returns the payload of an omni transaction.",
+	"omni_getpayload-txhash":          "The hash of the transaction",
+	"omni_getpayload--result0--desc":  "JSON object with the hex-encoded payload and its size",
+	"omni_getpayload--result0--key":   "The name of the field (payload or payloadsize)",
+	"omni_getpayload--result0--value": "The value of the field",
+
+	// OmniGetseedblocksCmd help.
+	"omni_getseedblocks--synopsis":  "Returns the blocks containing omni transactions in a range of heights.",
+	"omni_getseedblocks-startblock": "The first block to search (inclusive)",
+	"omni_getseedblocks-endblock":   "The last block to search (inclusive)",
+	"omni_getseedblocks--result0":   "The heights of the blocks",
+
+	// OmniGetcurrentconsensushashCmd help.
+	"omni_getcurrentconsensushash--synopsis":       "Returns the consensus hash of the omni state at the last processed block.",
+	"omni_getcurrentconsensushash--result0--desc":  "JSON object with the block height, block hash and consensus hash",
+	"omni_getcurrentconsensushash--result0--key":   "The name of the field",
+	"omni_getcurrentconsensushash--result0--value": "The value of the field",
+
+	// OmniDecodetransactionCmd help.
+	"omni_decodetransaction--synopsis":       "Decodes an omni transaction.",
+	"omni_decodetransaction-rawtx":           "The hex-encoded raw transaction",
+	"omni_decodetransaction-prevtxs":         "A JSON array of the outputs spent by the transaction (default: none)",
+	"omni_decodetransaction-height":          "The height to decode the transaction at (default: 0 for the chain height)",
+	"omni_decodetransaction--result0--desc":  "JSON object describing the transaction, as returned by omni_gettransaction",
+	"omni_decodetransaction--result0--key":   "The name of the field",
+	"omni_decodetransaction--result0--value": "The value of the field",
+
+	// OmniCreaterawtxOpreturnCmd help.
+	"omni_createrawtx_opreturn--synopsis": "Adds a payload output to a transaction.",
+	"omni_createrawtx_opreturn-rawtx":     "The hex-encoded raw transaction to extend",
+	"omni_createrawtx_opreturn-payload":   "The hex-encoded payload to add",
+	"omni_createrawtx_opreturn--result0":  "The hex-encoded modified transaction",
+
+	// OmniCreaterawtxMultisigCmd help.
+	"omni_createrawtx_multisig--synopsis":         "Adds a payload with bare multisig encoding to a transaction.",
+	"omni_createrawtx_multisig-rawtx":             "The hex-encoded raw transaction to extend (empty to create a new transaction)",
+	"omni_createrawtx_multisig-addpayload":        "The hex-encoded payload to add",
+	"omni_createrawtx_multisig-seed":              "The seed of the obfuscation",
+	"omni_createrawtx_multisig-redemptionpayload": "A public key or address which can redeem the dust",
+	"omni_createrawtx_multisig--result0":          "The hex-encoded modified transaction",
+
+	// OmniCreaterawtxInputCmd help.
+	"omni_createrawtx_input--synopsis": "Adds an input to a transaction.",
+	"omni_createrawtx_input-rawtx":     "The hex-encoded raw transaction to extend (empty to create a new transaction)",
+	"omni_createrawtx_input-txid":      "The hash of the transaction whose output is spent",
+	"omni_createrawtx_input-n":         "The index of the spent output",
+	"omni_createrawtx_input--result0":  "The hex-encoded modified transaction",
+
+	// OmniCreaterawtxReferenceCmd help.
+	"omni_createrawtx_reference--synopsis":   "Adds a reference output paying the recipient to a transaction.",
+	"omni_createrawtx_reference-rawtx":       "The hex-encoded raw transaction to extend (empty to create a new transaction)",
+	"omni_createrawtx_reference-destination": "The address of the recipient",
+	"omni_createrawtx_reference-amount":      "The value of the output in atoms, which may not be below the dust threshold of the default account's relay fee (default: the dust threshold)",
+	"omni_createrawtx_reference--result0":    "The hex-encoded modified transaction",
+
+	// OmniCreaterawtxChangeCmd help.
+	"omni_createrawtx_change--synopsis":   "Adds the inputs and a change output to a transaction.",
+	"omni_createrawtx_change-rawtx":       "The hex-encoded raw transaction to extend (empty to create a new transaction)",
+	"omni_createrawtx_change-prevtxs":     "A JSON array of the outputs to spend, as objects with the txid and vout of the output",
+	"omni_createrawtx_change-destination": "The address receiving the change",
+	"omni_createrawtx_change-fee":         "The value of the change output in atoms",
+	"omni_createrawtx_change-position":    "Unused, the change output is added last",
+	"omni_createrawtx_change--result0":    "The hex-encoded modified transaction",
+
+	// OmniCreatepayloadSimplesendCmd help.
+	"omni_createpayload_simplesend--synopsis":  "Creates the payload of a simple send transaction.",
+	"omni_createpayload_simplesend-propertyid": "The identifier of the tokens to send",
+	"omni_createpayload_simplesend-amount":     "The amount of tokens to send",
+	"omni_createpayload_simplesend--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadSendallCmd help.
+	"omni_createpayload_sendall--synopsis": "Creates the payload of a send all transaction.",
+	"omni_createpayload_sendall--result0":  "The hex-encoded payload",
+
+	// OmniCreatepayloadDexsellCmd help.
+	"omni_createpayload_dexsell--synopsis":         "Creates the payload of an offer on the distributed OMNI/HC exchange.",
+	"omni_createpayload_dexsell-propertyidforsale": "The identifier of the tokens to list for sale (1 for OMNI, 2 for TOMNI)",
+	"omni_createpayload_dexsell-amountforsale":     "The amount of tokens to list for sale",
+	"omni_createpayload_dexsell-amountdesired":     "The amount desired valued in HC",
+	"omni_createpayload_dexsell-paymentwindow":     "The number of blocks a buyer has to pay after accepting the offer",
+	"omni_createpayload_dexsell-minacceptfee":      "The minimum fee a buyer has to pay to accept the offer",
+	"omni_createpayload_dexsell-action":            "The action to take (1 for new offers, 2 to update, 3 to cancel)",
+	"omni_createpayload_dexsell--result0":          "The hex-encoded payload",
+
+	// OmniCreatepayloadDexacceptCmd help.
+	"omni_createpayload_dexaccept--synopsis":  "Creates the payload of an accept of an offer on the distributed OMNI/HC exchange.",
+	"omni_createpayload_dexaccept-propertyid": "The identifier of the tokens to purchase",
+	"omni_createpayload_dexaccept-amount":     "The amount of tokens to accept",
+	"omni_createpayload_dexaccept--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadStoCmd help.
+	"omni_createpayload_sto--synopsis":            "Creates the payload of a send to owners transaction.",
+	"omni_createpayload_sto-propertyid":           "The identifier of the tokens to distribute",
+	"omni_createpayload_sto-amount":               "The amount of tokens to distribute",
+	"omni_createpayload_sto-distributionproperty": "The identifier of the property whose holders receive the tokens (default: the distributed property)",
+	"omni_createpayload_sto--result0":             "The hex-encoded payload",
+
+	// OmniCreatepayloadIssuancefixedCmd help.
+	"omni_createpayload_issuancefixed--synopsis":   "Creates the payload of an issuance of tokens with a fixed supply.",
+	"omni_createpayload_issuancefixed-ecosystem":   "The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_createpayload_issuancefixed-typo":        "The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)",
+	"omni_createpayload_issuancefixed-previousid":  "The identifier of a predecessor token (0 for new tokens)",
+	"omni_createpayload_issuancefixed-category":    "The category of the new tokens (can be empty)",
+	"omni_createpayload_issuancefixed-subcategory": "The subcategory of the new tokens (can be empty)",
+	"omni_createpayload_issuancefixed-name":        "The name of the new tokens",
+	"omni_createpayload_issuancefixed-url":         "A URL with further information about the new tokens (can be empty)",
+	"omni_createpayload_issuancefixed-data":        "A description of the new tokens (can be empty)",
+	"omni_createpayload_issuancefixed-amount":      "The number of tokens to create",
+	"omni_createpayload_issuancefixed--result0":    "The hex-encoded payload",
+
+	// OmniCreatepayloadIssuancecrowdsaleCmd help.
+	"omni_createpayload_issuancecrowdsale--synopsis":         "Creates the payload of an issuance of tokens by a crowdsale.",
+	"omni_createpayload_issuancecrowdsale-ecosystem":         "The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_createpayload_issuancecrowdsale-typo":              "The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)",
+	"omni_createpayload_issuancecrowdsale-previousid":        "The identifier of a predecessor token (0 for new crowdsales)",
+	"omni_createpayload_issuancecrowdsale-category":          "The category of the new tokens (can be empty)",
+	"omni_createpayload_issuancecrowdsale-subcategory":       "The subcategory of the new tokens (can be empty)",
+	"omni_createpayload_issuancecrowdsale-name":              "The name of the new tokens",
+	"omni_createpayload_issuancecrowdsale-url":               "A URL with further information about the new tokens (can be empty)",
+	"omni_createpayload_issuancecrowdsale-data":              "A description of the new tokens (can be empty)",
+	"omni_createpayload_issuancecrowdsale-propertyiddesired": "The identifier of the tokens eligible to participate in the crowdsale",
+	"omni_createpayload_issuancecrowdsale-tokensperunit":     "The amount of tokens granted per unit invested in the crowdsale",
+	"omni_createpayload_issuancecrowdsale-deadline":          "The Unix time of the crowdsale deadline",
+	"omni_createpayload_issuancecrowdsale-earlybonus":        "The early bird bonus for participants in percent per week",
+	"omni_createpayload_issuancecrowdsale-issuerpercentage":  "The percentage of tokens granted to the issuer",
+	"omni_createpayload_issuancecrowdsale--result0":          "The hex-encoded payload",
+
+	// OmniCreatepayloadIssuancemanagedCmd help.
+	"omni_createpayload_issuancemanaged--synopsis":   "Creates the payload of an issuance of tokens with a supply managed by the issuer.",
+	"omni_createpayload_issuancemanaged-ecosystem":   "The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_createpayload_issuancemanaged-typo":        "The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)",
+	"omni_createpayload_issuancemanaged-previousid":  "The identifier of a predecessor token (0 for new tokens)",
+	"omni_createpayload_issuancemanaged-category":    "The category of the new tokens (can be empty)",
+	"omni_createpayload_issuancemanaged-subcategory": "The subcategory of the new tokens (can be empty)",
+	"omni_createpayload_issuancemanaged-name":        "The name of the new tokens",
+	"omni_createpayload_issuancemanaged-url":         "A URL with further information about the new tokens (can be empty)",
+	"omni_createpayload_issuancemanaged-data":        "A description of the new tokens (can be empty)",
+	"omni_createpayload_issuancemanaged--result0":    "The hex-encoded payload",
+
+	// OmniCreatepayloadClosecrowdsaleCmd help.
+	"omni_createpayload_closecrowdsale--synopsis": "Creates the payload of a transaction closing a crowdsale.",
+	"omni_createpayload_closecrowdsale--result0":  "The hex-encoded payload",
+
+	// OmniCreatepayloadGrantCmd help.
+	"omni_createpayload_grant--synopsis":  "Creates the payload of an issuance of new units of managed tokens.",
+	"omni_createpayload_grant-propertyid": "The identifier of the tokens to grant",
+	"omni_createpayload_grant-amount":     "The amount of tokens to create",
+	"omni_createpayload_grant-memo":       "A note attached to the transaction",
+	"omni_createpayload_grant--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadRevokeCmd help.
+	"omni_createpayload_revoke--synopsis":  "Creates the payload of a revocation of units of managed tokens.",
+	"omni_createpayload_revoke-propertyid": "The identifier of the tokens to revoke",
+	"omni_createpayload_revoke-amount":     "The amount of tokens to revoke",
+	"omni_createpayload_revoke-memo":       "A note attached to the transaction",
+	"omni_createpayload_revoke--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadChangeissuerCmd help.
+	"omni_createpayload_changeissuer--synopsis":  "Creates the payload of a transfer of administrative control of tokens.",
+	"omni_createpayload_changeissuer-propertyid": "The identifier of the tokens",
+	"omni_createpayload_changeissuer--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadTradeCmd help.
+	"omni_createpayload_trade--synopsis":         "Creates the payload of a trade offer on the distributed token exchange.",
+	"omni_createpayload_trade-propertyidforsale": "The identifier of the tokens to list for sale",
+	"omni_createpayload_trade-amountforsale":     "The amount of tokens to list for sale",
+	"omni_createpayload_trade-propertyiddesired": "The identifier of the tokens desired in exchange",
+	"omni_createpayload_trade-amountdesired":     "The amount of tokens desired in exchange",
+	"omni_createpayload_trade--result0":          "The hex-encoded payload",
+
+	// OmniCreatepayloadCanceltradesbypriceCmd help.
+	"omni_createpayload_canceltradesbyprice--synopsis":         "Creates the payload of a cancellation of offers with the given currency pair and price.",
+	"omni_createpayload_canceltradesbyprice-propertyidforsale": "The identifier of the tokens listed for sale",
+	"omni_createpayload_canceltradesbyprice-amountforsale":     "The amount of tokens listed for sale",
+	"omni_createpayload_canceltradesbyprice-propertyiddesired": "The identifier of the tokens desired in exchange",
+	"omni_createpayload_canceltradesbyprice-amountdesired":     "The amount of tokens desired in exchange",
+	"omni_createpayload_canceltradesbyprice--result0":          "The hex-encoded payload",
+
+	// OmniCreatepayloadCanceltradesbypairCmd help.
+	"omni_createpayload_canceltradesbypair--synopsis":         "Creates the payload of a cancellation of all offers with the given currency pair.",
+	"omni_createpayload_canceltradesbypair-propertyidforsale": "The identifier of the tokens listed for sale",
+	"omni_createpayload_canceltradesbypair-propertyiddesired": "The identifier of the tokens desired in exchange",
+	"omni_createpayload_canceltradesbypair--result0":          "The hex-encoded payload",
+
+	// OmniCreatepayloadCancelalltradesCmd help.
+	"omni_createpayload_cancelalltrades--synopsis": "Creates the payload of a cancellation of all offers of an address.",
+	"omni_createpayload_cancelalltrades--result0":  "The hex-encoded payload",
+
+	// OmniCreatepayloadEnablefreezingCmd help.
+	"omni_createpayload_enablefreezing--synopsis":  "Creates the payload of a transaction enabling address freezing for managed tokens.",
+	"omni_createpayload_enablefreezing-propertyid": "The identifier of the tokens",
+	"omni_createpayload_enablefreezing--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadDisablefreezingCmd help.
+	"omni_createpayload_disablefreezing--synopsis":  "Creates the payload of a transaction disabling address freezing for managed tokens.",
+	"omni_createpayload_disablefreezing-propertyid": "The identifier of the tokens",
+	"omni_createpayload_disablefreezing--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadFreezeCmd help.
+	"omni_createpayload_freeze--synopsis":  "Creates the payload of a transaction freezing an address.",
+	"omni_createpayload_freeze-toaddress":  "The address to freeze",
+	"omni_createpayload_freeze-propertyid": "The identifier of the managed tokens with freezing enabled",
+	"omni_createpayload_freeze-amount":     "Unused, frozen addresses can not transact the tokens",
+	"omni_createpayload_freeze--result0":   "The hex-encoded payload",
+
+	// OmniCreatepayloadUnfreezeCmd help.
+	"omni_createpayload_unfreeze--synopsis":  "Creates the payload of a transaction unfreezing an address.",
+	"omni_createpayload_unfreeze-toaddress":  "The address to unfreeze",
+	"omni_createpayload_unfreeze-propertyid": "The identifier of the managed tokens with freezing enabled",
+	"omni_createpayload_unfreeze-amount":     "Unused",
+	"omni_createpayload_unfreeze--result0":   "The hex-encoded payload",
+
+	// OmniGetfeecacheCmd help.
+	"omni_getfeecache--synopsis":       "Returns the fees cached for distribution.",
+	"omni_getfeecache-propertyid":      "The identifier of the tokens to filter the cache by (0 for all)",
+	"omni_getfeecache--result0--desc":  "JSON object with a property identifier and its cached fees",
+	"omni_getfeecache--result0--key":   "The name of the field",
+	"omni_getfeecache--result0--value": "The value of the field",
+
+	// OmniGetfeetriggerCmd help.
+	"omni_getfeetrigger--synopsis":       "Returns the amounts of cached fees which trigger a distribution.",
+	"omni_getfeetrigger--result0--desc":  "JSON object with a property identifier and its fee trigger",
+	"omni_getfeetrigger--result0--key":   "The name of the field",
+	"omni_getfeetrigger--result0--value": "The value of the field",
+
+	// OmniGetfeeshareCmd help.
+	"omni_getfeeshare--synopsis":       "Returns the share of distributed fees an address would receive.",
+	"omni_getfeeshare-address":         "The address to filter shares by (default: every address)",
+	"omni_getfeeshare-ecosystem":       "The ecosystem of the fees (1 for the main ecosystem, 2 for the test ecosystem)",
+	"omni_getfeeshare--result0--desc":  "JSON object with an address and its share of the fees in percent",
+	"omni_getfeeshare--result0--key":   "The name of the field",
+	"omni_getfeeshare--result0--value": "The value of the field",
+
+	// OmniGetfeedistributionCmd help.
+	"omni_getfeedistribution--synopsis":       "Returns the details of a fee distribution.",
+	"omni_getfeedistribution-distributionid":  "The identifier of the distribution",
+	"omni_getfeedistribution--result0--desc":  "JSON object describing the distribution and its recipients",
+	"omni_getfeedistribution--result0--key":   "The name of the field",
+	"omni_getfeedistribution--result0--value": "The value of the field",
+
+	// OmniGetfeedistributionsCmd help.
+	"omni_getfeedistributions--synopsis":       "Returns the details of the fee distributions of tokens.",
+	"omni_getfeedistributions-propertyid":      "The identifier of the distributed tokens",
+	"omni_getfeedistributions--result0--desc":  "JSON object describing a distribution and its recipients",
+	"omni_getfeedistributions--result0--key":   "The name of the field",
+	"omni_getfeedistributions--result0--value": "The value of the field",
+
+	// OmniSetautocommitCmd help.
+	"omni_setautocommit--synopsis":  "Sets whether the omni engine automatically commits and publishes the transactions it creates.",
+	"omni_setautocommit-autocommit": "Whether transactions are automatically committed",
+	"omni_setautocommit--result0":   "The new value of the flag",
+
+	// OmniRollBackCmd help.
+	"omni_rollback--synopsis": "Rolls the omni state back to a block height.",
+	"omni_rollback-height":    "The height to roll back to",
+	"omni_rollback-hashs":     "Unused",
+	"omni_rollback--result0":  "An empty string",
+
+	// OmniGetBlockInfoCmd help.
+	"omni_getblockinfo--synopsis":       "Returns the omni state of a block.",
+	"omni_getblockinfo-height":          "The height of the block",
+	"omni_getblockinfo--result0--desc":  "JSON object describing the omni state of the block",
+	"omni_getblockinfo--result0--key":   "The name of the field",
+	"omni_getblockinfo--result0--value": "The value of the field",
+
+	// OmniCreateAgreementCmd help.
+	"omni_createagreement--synopsis":              "Creates an agreement with the omni engine.",
+	"omni_createagreement-agttype":                "The type of the agreement",
+	"omni_createagreement-agtcontent":             "The content of the agreement",
+	"omni_createagreement-agtnonencryptedcontent": "The part of the content which is not encrypted",
+	"omni_createagreement-agtkey":                 "The key encrypting the content",
+	"omni_createagreement-agtencryptedcontent":    "The encrypted part of the content",
+	"omni_createagreement--result0--desc":         "JSON object describing the created agreement",
+	"omni_createagreement--result0--key":          "The name of the field",
+	"omni_createagreement--result0--value":        "The value of the field",
+
+	// OmniSendAgreementCmd help.
+	"omni_sendagreement--synopsis":              "Creates and publishes a transaction sending an agreement to an address.",
+	"omni_sendagreement-fromaddress":            "The address to send from",
+	"omni_sendagreement-toaddress":              "The address of the recipient",
+	"omni_sendagreement-agtid":                  "The identifier of the agreement",
+	"omni_sendagreement-agtnonencryptedcontent": "The part of the content which is not encrypted",
+	"omni_sendagreement-agtkey":                 "The key encrypting the content",
+	"omni_sendagreement-agtencryptedcontent":    "The encrypted part of the content",
+	"omni_sendagreement--result0":               "The hash of the published transaction",
+
+	// GetStraightPubKeyCmd help.
+	"getstraightpubkey--synopsis":            "Returns the public key of a wallet pay-to-pubkey-hash address encoded as a pay-to-pubkey address.",
+	"getstraightpubkey-srcaddress":           "The pay-to-pubkey-hash address",
+	"getstraightpubkeyresult-StraightPubKey": "The pay-to-pubkey address of the public key",
+
 	// TicketsForAddressCmd help.
 	"ticketsforaddress--synopsis": "Request all the tickets for an address.",
 	"ticketsforaddress-address":   "Address to look for.",
 	"ticketsforaddress--result0":  "Tickets owned by the specified address.",
 
+	// TriggerConsolidationCmd help.
+	"triggerconsolidation--synopsis": "Consolidates the dust outputs of an account, valued below the configured dustthreshold, into a new internal address of the account.\n" +
+		"Unless forced, dust is only consolidated when the account holds more than dustconsolidatecount dust outputs and the median mempool fee rate does not exceed dustconsolidatemaxfeerate.\n" +
+		"Requires the wallet to be unlocked.",
+	"triggerconsolidation-account":  "Account whose dust outputs are consolidated",
+	"triggerconsolidation-force":    "Consolidate every dust output regardless of their number and the mempool fee rate",
+	"triggerconsolidation--result0": "Hashes of the consolidation transactions, empty if the account does not qualify for consolidation",
+
+	// PruneWalletHistoryCmd help.
+	"prunewallethistory--synopsis": "Removes the records of fully spent regular transactions mined at least depth blocks below the main chain tip, recording their totals in a history checkpoint.\n" +
+		"Tickets, votes, revocations, transactions funding tickets, and transactions with multisig outputs are never pruned.\n" +
+		"Pruned transactions no longer appear in the transaction history.",
+	"prunewallethistory-depth": "Number of blocks below the main chain tip at and below which transactions are pruned (default: the configured prunehistory depth, minimum 1024)",
+
+	// PruneWalletHistoryResult help.
+	"prunewallethistoryresult-height":       "The block height of the history checkpoint",
+	"prunewallethistoryresult-transactions": "The number of transactions pruned at and below the checkpoint height",
+	"prunewallethistoryresult-credits":      "The total amount of the credits of the pruned transactions",
+	"prunewallethistoryresult-debits":       "The total amount of the debits of the pruned transactions",
+
 	// PurchaseTicketCmd help.
-	"purchaseticket--synopsis":     "Purchase ticket using available funds.",
-	"purchaseticket--result0":      "Hash of the resulting ticket",
+	"purchaseticket--synopsis": "Purchase tickets using available funds.\n" +
+		"A split transaction is published to fund each ticket with an output of the exact ticket cost.\n" +
+		"Tickets which fail to be purchased after the split transaction is published are reported in the result rather than as an error.",
 	"purchaseticket-spendlimit":    "Limit on the amount to spend on ticket",
 	"purchaseticket-fromaccount":   "The account to use for purchase (default=\"default\")",
 	"purchaseticket-minconf":       "Minimum number of block confirmations required",
@@ -661,6 +2210,17 @@ var helpDescsEnUS = map[string]string{
 	"purchaseticket-poolfees":      "The amount of fees to pay to the stake pool",
 	"purchaseticket-expiry":        "Height at which the purchase tickets expire",
 	"purchaseticket-comment":       "Unused",
+	"purchaseticket-ticketfee":     "The ticket fee in coins per kB (default is the wallet's ticket fee)",
+
+	// PurchaseTicketResult help.
+	"purchaseticketresult-splittx":    "The hash of the split transaction funding the tickets",
+	"purchaseticketresult-tickets":    "The hashes of the published tickets",
+	"purchaseticketresult-failures":   "Each ticket which was not purchased",
+	"purchaseticketresult-totalspent": "The amount spent by the published tickets, including ticket and stake pool fees, and the fee of the split transaction",
+
+	// PurchaseTicketFailure help.
+	"purchaseticketfailure-index": "The zero-based index of the ticket among the requested tickets",
+	"purchaseticketfailure-error": "The reason the ticket was not purchased",
 
 	// SendToSSRtxCmd help.
 	"sendtossrtx--synopsis":   "Send to SS Revocation transaction",
@@ -668,28 +2228,34 @@ var helpDescsEnUS = map[string]string{
 	"sendtossrtx-comment":     "Unused",
 	"sendtossrtx-tickethash":  "Hash of the ticket to be revoked",
 	"sendtossrtx-fromaccount": "The account to spend a stake ticket from (default=\"default\")",
+	"sendtossrtx-allowhighfees": "Allow sending the revocation with high fees (default=true, as revocation fees are paid from the ticket).",
 
 	// SendToSStxCmd help.
-	"sendtosstx--synopsis":      "Send to SStx",
-	"sendtosstx--result0":       "txid of the resulting transaction",
-	"sendtosstx-comment":        "Unused",
-	"sendtosstx-minconf":        "Minimum number of block confirmations required",
-	"sendtosstx-couts":          "Couts for the tx",
-	"sendtosstx-inputs":         "Inputs for the tx",
-	"sendtosstx-amounts--desc":  "Unused",
-	"sendtosstx-amounts--value": "Value",
-	"sendtosstx-amounts--key":   "Key",
-	"sendtosstx-amounts":        "Amounts to send",
-	"sendtosstx-account":        "The account to use (default=\"default\")",
-	"sendtosstx-fromaccount":    "The account sent from",
-	"sstxcommitout-changeamt":   "Change amount",
-	"sstxcommitout-changeaddr":  "Change address to use",
-	"sstxcommitout-commitamt":   "Amount to commit",
-	"sstxcommitout-addr":        "Address to use",
-	"sstxinput-amt":             "Amount",
-	"sstxinput-tree":            "Input tree",
-	"sstxinput-vout":            "Vout for the input tx",
-	"sstxinput-txid":            "Txid to use",
+	"sendtosstx--synopsis":           "Send to SStx",
+	"sendtosstx-allowhighfees":       "Allow sending the transaction with high fees (default is the wallet's --allowhighfees setting).",
+	"sendtosstx--condition0":         "resultversion=1",
+	"sendtosstx--condition1":         "resultversion=2",
+	"sendtosstx--result0":            "txid of the resulting transaction",
+	"sendtosstxresult-txhash":        "txid of the resulting transaction",
+	"sendtosstxresult-allowhighfees": "Whether high fees were allowed when sending the transaction.",
+	"sendtosstx-comment":             "Unused",
+	"sendtosstx-minconf":             "Minimum number of block confirmations required",
+	"sendtosstx-couts":               "Couts for the tx",
+	"sendtosstx-inputs":              "Inputs for the tx",
+	"sendtosstx-amounts--desc":       "Unused",
+	"sendtosstx-amounts--value":      "Value",
+	"sendtosstx-amounts--key":        "Key",
+	"sendtosstx-amounts":             "Amounts to send",
+	"sendtosstx-account":             "The account to use (default=\"default\")",
+	"sendtosstx-fromaccount":         "The account sent from",
+	"sstxcommitout-changeamt":        "Change amount",
+	"sstxcommitout-changeaddr":       "Change address to use",
+	"sstxcommitout-commitamt":        "Amount to commit",
+	"sstxcommitout-addr":             "Address to use",
+	"sstxinput-amt":                  "Amount",
+	"sstxinput-tree":                 "Input tree",
+	"sstxinput-vout":                 "Vout for the input tx",
+	"sstxinput-txid":                 "Txid to use",
 
 	// SendToSSGenCmd help.
 	"sendtossgen--synopsis":   "Generate a vote tx",
@@ -701,9 +2267,30 @@ var helpDescsEnUS = map[string]string{
 	"sendtossgen-tickethash":  "Hash of the ticket used for vote",
 	"sendtossgen-fromaccount": "The account to use (default=\"default\")",
 
+	// SetLogLevelCmd help.
+	"setloglevel--synopsis": "Changes the logging level of all or individual subsystems without restarting the wallet.",
+	"setloglevel-levelspec": "The level for all subsystems, e.g. 'debug', or comma-separated subsystem=level pairs, e.g. 'CHNS=debug,WLLT=trace'.\n" +
+		"Levels are {trace, debug, info, warn, error, critical, off}",
+	"setloglevel--result0--desc":  "JSON object with subsystems as keys and their logging levels as values",
+	"setloglevel--result0--key":   "The subsystem",
+	"setloglevel--result0--value": "The logging level of the subsystem",
+
+	// SetLogRotationCmd help.
+	"setlogrotation--synopsis": "Changes the log file rotation.  The current log file is kept and rolled once it reaches the new size.",
+	"setlogrotation-maxsize":   "The size in kilobytes after which the log file is rolled",
+	"setlogrotation-maxrolls":  "The maximum number of rolled log files to keep",
+
+	// SetSpendableConfsCmd help.
+	"setspendableconfs--synopsis": "Sets the confirmations an account requires before its outputs are considered spendable by balances and input selection.\n" +
+		"These only add to the requested minimum confirmations and the maturities of the chain. Setting both to 0 removes the requirements.",
+	"setspendableconfs-account":  "The account to configure",
+	"setspendableconfs-regular":  "The confirmations required of every output",
+	"setspendableconfs-coinbase": "The confirmations required of coinbase, vote and revocation outputs",
+
 	// SetTicketFeeCmd help.
 	"setticketfee--synopsis": "Modify the fee per kB of the serialized tx size used each time more fee is required for an authored stake transaction.",
 	"setticketfee-fee":       "The new fee per kB of the serialized tx size valued in HC",
+	"setticketfee-account":   "Set the fee for tickets purchased from this account only rather than the wallet's default fee",
 	"setticketfee--result0":  "The boolean 'true'",
 
 	// GetTicketFeeCmd help.
@@ -17,6 +17,10 @@ var (
 	returnsString      = []interface{}{(*string)(nil)}
 	returnsStringArray = []interface{}{(*[]string)(nil)}
 	returnsLTRArray    = []interface{}{(*[]hcjson.ListTransactionsResult)(nil)}
+
+	// Omni engine results are passed through as returned by the engine.
+	returnsObject      = []interface{}{(*map[string]interface{})(nil)}
+	returnsObjectArray = []interface{}{(*[]map[string]interface{})(nil)}
 )
 
 // Methods contains all methods and result types that help is generated for,
@@ -25,19 +29,29 @@ var Methods = []struct {
 	Method      string
 	ResultTypes []interface{}
 }{
+	{"abandontransaction", nil},
 	{"accountaddressindex", []interface{}{(*int)(nil)}},
 	{"accountsyncaddressindex", nil},
 	{"addmultisigaddress", returnsString},
-	{"consolidate", returnsString},
+	{"bumpfee", []interface{}{(*hcjson.BumpFeeResult)(nil)}},
+	{"canceltransactiondraft", nil},
+	{"checkaddressreuse", []interface{}{(*hcjson.CheckAddressReuseResult)(nil)}},
+	{"committransactiondraft", returnsString},
+	{"compactdb", []interface{}{(*hcjson.CompactDBResult)(nil)}},
+	{"consolidate", []interface{}{returnsString[0], returnsStringArray[0], (*hcjson.ConsolidateResult)(nil)}},
 	{"createmultisig", []interface{}{(*hcjson.CreateMultiSigResult)(nil)}},
+	{"createtransactiondraft", []interface{}{(*hcjson.CreateTransactionDraftResult)(nil)}},
 	{"dumpprivkey", returnsString},
+	{"exporttransactions", append(returnsString, (*[]hcjson.ExportedTransactionResult)(nil))},
+	{"exportvotechoices", []interface{}{(*hcjson.ExportVoteChoicesResult)(nil)}},
 	{"getaccount", returnsString},
 	{"getaccountaddress", returnsString},
+	{"getaccountdefaultaddress", returnsString},
 	{"getaddressesbyaccount", returnsStringArray},
-	{"getbalance", append(returnsNumber, returnsNumber[0])},
+	{"getbalance", []interface{}{(*hcjson.GetBalanceResult)(nil)}},
 	{"getbestblockhash", returnsString},
 	{"getblockcount", returnsNumber},
-	{"getinfo", []interface{}{(*hcjson.InfoWalletResult)(nil)}},
+	{"getinfo", []interface{}{(*hcjson.InfoWalletResult)(nil), (*hcjson.GetInfoResult)(nil)}},
 	{"getmasterpubkey", []interface{}{(*string)(nil)}},
 	{"getmultisigoutinfo", []interface{}{(*hcjson.GetMultisigOutInfoResult)(nil)}},
 	{"getnewaddress", returnsString},
@@ -50,32 +64,43 @@ var Methods = []struct {
 	{"help", append(returnsString, returnsString[0])},
 	{"importprivkey", nil},
 	{"importscript", nil},
+	{"importvotechoices", nil},
 	{"keypoolrefill", nil},
 	{"listaccounts", []interface{}{(*map[string]float64)(nil)}},
 	{"listlockunspent", []interface{}{(*[]hcjson.TransactionInput)(nil)}},
 	{"listreceivedbyaccount", []interface{}{(*[]hcjson.ListReceivedByAccountResult)(nil)}},
 	{"listreceivedbyaddress", []interface{}{(*[]hcjson.ListReceivedByAddressResult)(nil)}},
 	{"listsinceblock", []interface{}{(*hcjson.ListSinceBlockResult)(nil)}},
-	{"listtransactions", returnsLTRArray},
-	{"listunspent", []interface{}{(*hcjson.ListUnspentResult)(nil)}},
+	{"liststaleomnipending", []interface{}{(*[]hcjson.StaleOmniPendingResult)(nil)}},
+	{"listtransactions", []interface{}{(*[]hcjson.ListTransactionsResult)(nil), (*hcjson.ListTransactionsPageResult)(nil)}},
+	{"listunspent", []interface{}{(*hcjson.ListUnspentResult)(nil), (*hcjson.ListUnspentPageResult)(nil)}},
 	{"lockunspent", returnsBool},
+	{"omni_estimatefee", []interface{}{(*hcjson.OmniEstimatefeeResult)(nil)}},
+	{"omni_getdustthreshold", []interface{}{(*hcjson.OmniGetdustthresholdResult)(nil)}},
 	{"redeemmultisigout", []interface{}{(*hcjson.RedeemMultiSigOutResult)(nil)}},
 	{"redeemmultisigouts", []interface{}{(*hcjson.RedeemMultiSigOutResult)(nil)}},
-	{"rescanwallet", nil},
-	{"revoketickets", nil},
+	{"rescanwallet", []interface{}{(*int32)(nil)}},
+	{"reserveoutputs", []interface{}{(*hcjson.ReserveOutputsResult)(nil)}},
+	{"revoketickets", []interface{}{(*hcjson.RevokeTicketsResult)(nil)}},
 	{"sendfrom", returnsString},
 	{"sendmany", returnsString},
 	{"sendmanyv2", returnsString},
+	{"sendbatch", []interface{}{(*hcjson.SendBatchResult)(nil)}},
+	{"senddata", []interface{}{(*hcjson.SendDataResult)(nil)}},
+	{"sendrawtransaction", returnsString},
 	{"sendtoaddress", returnsString},
 	{"sendfromaddresstoaddress", returnsString},
 	{"sendtomultisig", returnsString},
 	{"settxfee", returnsBool},
 	{"setvotechoice", nil},
 	{"signmessage", returnsString},
+	{"signmessagewithaccount", []interface{}{(*hcjson.SignMessageWithAccountResult)(nil)}},
 	{"signrawtransaction", []interface{}{(*hcjson.SignRawTransactionResult)(nil)}},
 	{"signrawtransactions", []interface{}{(*hcjson.SignRawTransactionsResult)(nil)}},
 	{"validateaddress", []interface{}{(*hcjson.ValidateAddressWalletResult)(nil)}},
+	{"verifyaccountproof", returnsBool},
 	{"verifymessage", returnsBool},
+	{"verifyseedbackup", []interface{}{(*hcjson.VerifySeedBackupResult)(nil)}},
 	{"version", []interface{}{(*map[string]hcjson.VersionResult)(nil)}},
 	{"walletlock", nil},
 	{"walletpassphrase", nil},
@@ -85,25 +110,154 @@ var Methods = []struct {
 	{"getbestblock", []interface{}{(*hcjson.GetBestBlockResult)(nil)}},
 	{"getunconfirmedbalance", returnsNumber},
 	{"listaddresstransactions", returnsLTRArray},
-	{"listalltransactions", returnsLTRArray},
+	{"listalltransactions", []interface{}{(*[]hcjson.ListTransactionsResult)(nil), (*hcjson.ListTransactionsPageResult)(nil)}},
+	{"notifytipchanges", nil},
 	{"renameaccount", nil},
+	{"stopnotifytipchanges", nil},
 	{"walletislocked", returnsBool},
 	{"walletinfo", []interface{}{(*hcjson.WalletInfoResult)(nil)}},
 
 	// TODO Alphabetize
-	{"purchaseticket", returnsString},
+	{"prunewallethistory", []interface{}{(*hcjson.PruneWalletHistoryResult)(nil)}},
+	{"purchaseticket", []interface{}{(*hcjson.PurchaseTicketResult)(nil)}},
 	{"sendtossrtx", returnsString},
-	{"sendtosstx", returnsString},
+	{"sendtosstx", []interface{}{returnsString[0], (*hcjson.SendToSStxResult)(nil)}},
 	{"sendtossgen", returnsString},
 	{"generatevote", []interface{}{(*hcjson.GenerateVoteResult)(nil)}},
+	{"generateticketproof", []interface{}{(*hcjson.GenerateTicketProofResult)(nil)}},
+	{"verifyticketproof", returnsBool},
+	{"decodewallettransaction", []interface{}{(*hcjson.DecodeWalletTransactionResult)(nil)}},
+	{"delegatedtickets", []interface{}{(*[]hcjson.DelegatedTicketResult)(nil)}},
+	{"exportaccount", returnsString},
+	{"getreplicationinfo", []interface{}{(*hcjson.GetReplicationInfoResult)(nil)}},
+	{"promotereplica", nil},
+	{"getaddressforinvoice", returnsString},
+	{"getauditlog", []interface{}{(*hcjson.GetAuditLogResult)(nil)}},
+	{"getbalanceathash", []interface{}{(*hcjson.GetBalanceAtResult)(nil)}},
+	{"getbalanceatheight", []interface{}{(*hcjson.GetBalanceAtResult)(nil)}},
+	{"getinvoicepayments", []interface{}{(*hcjson.GetInvoicePaymentsResult)(nil)}},
+	{"gethealth", []interface{}{(*hcjson.GetHealthResult)(nil)}},
+	{"getrecoverystate", []interface{}{(*hcjson.GetRecoveryStateResult)(nil)}},
+	{"getrescaninfo", []interface{}{(*hcjson.GetRescanInfoResult)(nil)}},
+	{"getspendableconfs", []interface{}{(*hcjson.GetSpendableConfsResult)(nil)}},
 	{"getstakeinfo", []interface{}{(*hcjson.GetStakeInfoResult)(nil)}},
+	{"getstakerewards", []interface{}{(*[]hcjson.GetStakeRewardsResult)(nil)}},
 	{"getticketfee", returnsNumber},
+	{"setloglevel", []interface{}{(*map[string]string)(nil)}},
+	{"setlogrotation", nil},
+	{"setspendableconfs", nil},
 	{"setticketfee", returnsBool},
 	{"getwalletfee", returnsNumber},
+	{"importaccount", []interface{}{(*uint32)(nil)}},
+	{"createexternalbranch", []interface{}{(*uint32)(nil)}},
+	{"listexternalbranches", []interface{}{(*[]hcjson.ExternalBranchResult)(nil)}},
+	{"createmultisigaccount", []interface{}{(*hcjson.CreateMultisigAccountResult)(nil)}},
+	{"getnewmultisigaddress", []interface{}{(*string)(nil)}},
+	{"getmultisigaccountinfo", []interface{}{(*hcjson.GetMultisigAccountInfoResult)(nil)}},
+	{"createmultisigspend", []interface{}{(*hcjson.CreateMultisigSpendResult)(nil)}},
 	{"addticket", nil},
 	{"listscripts", []interface{}{(*hcjson.ListScriptsResult)(nil)}},
+	{"listrefundablescripts", []interface{}{(*[]hcjson.RefundableScriptResult)(nil)}},
+	{"refundscript", returnsString},
+	{"initiateswap", []interface{}{(*hcjson.AtomicSwapContractResult)(nil)}},
+	{"participateswap", []interface{}{(*hcjson.AtomicSwapContractResult)(nil)}},
+	{"redeemswap", returnsString},
+	{"refundswap", returnsString},
+	{"listswaps", []interface{}{(*[]hcjson.AtomicSwapResult)(nil)}},
 	{"stakepooluserinfo", []interface{}{(*hcjson.StakePoolUserInfoResult)(nil)}},
+	{"listpoolfeeexemptions", returnsStringArray},
+	{"reevaluatepooltickets", returnsStringArray},
+	{"setpoolfeeexemption", nil},
+	{"setaccountdefaultaddress", nil},
+	{"syncaccountaddresses", []interface{}{(*[]hcjson.SyncAccountAddressesResult)(nil)}},
 	{"ticketsforaddress", returnsBool},
+	{"triggerconsolidation", returnsStringArray},
+	{"omni_send", returnsString},
+	{"omni_senddexsell", returnsString},
+	{"omni_senddexaccept", returnsString},
+	{"omni_sendissuancecrowdsale", returnsString},
+	{"omni_sendissuancefixed", returnsString},
+	{"omni_sendissuancemanaged", returnsString},
+	{"omni_sendsto", returnsString},
+	{"omni_sendgrant", returnsString},
+	{"omni_sendrevoke", returnsString},
+	{"omni_sendclosecrowdsale", returnsString},
+	{"omni_sendtrade", returnsString},
+	{"omni_sendcanceltradesbyprice", returnsString},
+	{"omni_sendcanceltradesbypair", returnsString},
+	{"omni_sendcancelalltrades", returnsString},
+	{"omni_sendchangeissuer", returnsString},
+	{"omni_sendall", returnsString},
+	{"omni_sendenablefreezing", returnsString},
+	{"omni_senddisablefreezing", returnsString},
+	{"omni_sendfreeze", returnsString},
+	{"omni_sendunfreeze", returnsString},
+	{"omni_sendrawtx", returnsString},
+	{"omni_funded_send", returnsString},
+	{"omni_funded_sendall", returnsString},
+	{"omni_getinfo", returnsObject},
+	{"omni_getbalance", returnsObject},
+	{"omni_getallbalancesforid", returnsObjectArray},
+	{"omni_getallbalancesforaddress", returnsObjectArray},
+	{"omni_getwalletbalances", returnsObjectArray},
+	{"omni_getwalletaddressbalances", returnsObjectArray},
+	{"omni_gettransaction", returnsObject},
+	{"omni_listtransactions", returnsObjectArray},
+	{"omni_listwallettransactions", returnsObjectArray},
+	{"omni_listblocktransactions", returnsStringArray},
+	{"omni_listpendingtransactions", returnsObjectArray},
+	{"omni_getactivedexsells", returnsObjectArray},
+	{"omni_listproperties", returnsObjectArray},
+	{"omni_getproperty", returnsObject},
+	{"omni_getactivecrowdsales", returnsObjectArray},
+	{"omni_getcrowdsale", returnsObject},
+	{"omni_getgrants", returnsObject},
+	{"omni_getsto", returnsObject},
+	{"omni_gettrade", returnsObject},
+	{"omni_getorderbook", returnsObjectArray},
+	{"omni_gettradehistoryforpair", returnsObjectArray},
+	{"omni_gettradehistoryforaddress", returnsObjectArray},
+	{"omni_getactivations", returnsObject},
+	{"omni_getpayload", returnsObject},
+	{"omni_getseedblocks", []interface{}{(*[]int64)(nil)}},
+	{"omni_getcurrentconsensushash", returnsObject},
+	{"omni_decodetransaction", returnsObject},
+	{"omni_createrawtx_opreturn", returnsString},
+	{"omni_createrawtx_multisig", returnsString},
+	{"omni_createrawtx_input", returnsString},
+	{"omni_createrawtx_reference", returnsString},
+	{"omni_createrawtx_change", returnsString},
+	{"omni_createpayload_simplesend", returnsString},
+	{"omni_createpayload_sendall", returnsString},
+	{"omni_createpayload_dexsell", returnsString},
+	{"omni_createpayload_dexaccept", returnsString},
+	{"omni_createpayload_sto", returnsString},
+	{"omni_createpayload_issuancefixed", returnsString},
+	{"omni_createpayload_issuancecrowdsale", returnsString},
+	{"omni_createpayload_issuancemanaged", returnsString},
+	{"omni_createpayload_closecrowdsale", returnsString},
+	{"omni_createpayload_grant", returnsString},
+	{"omni_createpayload_revoke", returnsString},
+	{"omni_createpayload_changeissuer", returnsString},
+	{"omni_createpayload_trade", returnsString},
+	{"omni_createpayload_canceltradesbyprice", returnsString},
+	{"omni_createpayload_canceltradesbypair", returnsString},
+	{"omni_createpayload_cancelalltrades", returnsString},
+	{"omni_createpayload_enablefreezing", returnsString},
+	{"omni_createpayload_disablefreezing", returnsString},
+	{"omni_createpayload_freeze", returnsString},
+	{"omni_createpayload_unfreeze", returnsString},
+	{"omni_getfeecache", returnsObjectArray},
+	{"omni_getfeetrigger", returnsObjectArray},
+	{"omni_getfeeshare", returnsObjectArray},
+	{"omni_getfeedistribution", returnsObject},
+	{"omni_getfeedistributions", returnsObjectArray},
+	{"omni_setautocommit", returnsBool},
+	{"omni_rollback", returnsString},
+	{"omni_getblockinfo", returnsObject},
+	{"omni_createagreement", returnsObject},
+	{"omni_sendagreement", returnsString},
+	{"getstraightpubkey", []interface{}{(*hcjson.GetStraightPubKeyResult)(nil)}},
 }
 
 // HelpDescs contains the locale-specific help strings along with the locale.
@@ -0,0 +1,411 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package testhelpers provides implementations of the dependencies of the
+// wallet for use in tests which do not run hcd.
+package testhelpers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/HcashOrg/bitset"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+// ErrNotFound is returned by MockChainClient when queried for a block,
+// transaction, or output which it was not given.
+var ErrNotFound = errors.New("not found")
+
+// MockChainClient is an in-memory implementation of the wallet.ChainClient
+// and wallet.ConsensusClient interfaces.  Tests populate its exported fields
+// with the chain state the wallet should observe, and inspect the transactions
+// and filters recorded from the wallet's requests.  All methods are safe for
+// concurrent access, but the fields must not be modified while the wallet is
+// using the client.
+type MockChainClient struct {
+	// BlockHashes are the main chain block hashes, indexed by height.
+	BlockHashes []chainhash.Hash
+
+	// Blocks are returned by GetBlock and GetRawTransaction.
+	Blocks map[chainhash.Hash]*wire.MsgBlock
+
+	// StakeDifficulty is returned by GetStakeDifficulty.
+	StakeDifficulty *hcjson.GetStakeDifficultyResult
+
+	// FeeInfo is returned by TxFeeInfo.
+	FeeInfo *hcjson.TxFeeInfoResult
+
+	// Headers is returned by GetHeaders.
+	Headers *hcjson.GetHeadersResult
+
+	// Mempool holds the hashes of mempool transactions by transaction type.
+	// Transactions of every type are returned for hcjson.GRMAll.
+	Mempool map[hcjson.GetRawMempoolTxTypeCmd][]*chainhash.Hash
+
+	// Transactions and TxOuts are returned by GetRawTransactionVerbose and
+	// GetTxOut.
+	Transactions map[chainhash.Hash]*hcjson.TxRawResult
+	TxOuts       map[wire.OutPoint]*hcjson.GetTxOutResult
+
+	// UsedAddresses are the encoded addresses reported as used by
+	// ExistsAddresses.
+	UsedAddresses map[string]bool
+
+	// LiveTickets, ExpiredTickets, and MissedTickets hold the state of
+	// tickets reported by the Exists*Ticket(s) methods.
+	LiveTickets    map[chainhash.Hash]bool
+	ExpiredTickets map[chainhash.Hash]bool
+	MissedTickets  map[chainhash.Hash]bool
+
+	// RawRequestFunc handles RawRequest.  RawRequest errors when it is nil.
+	RawRequestFunc func(method string, params []json.RawMessage) (json.RawMessage, error)
+
+	// SendErr, when non-nil, is returned by SendRawTransaction instead of
+	// recording the transaction once SendErrAfter transactions have been
+	// recorded.
+	SendErr      error
+	SendErrAfter int
+
+	mu              sync.Mutex
+	sent            []*wire.MsgTx
+	filterAddrs     map[string]bool
+	filterOutPoints map[wire.OutPoint]bool
+	blocksNotified  bool
+	rescannedBlocks []chainhash.Hash
+
+	ntfns       chan interface{}
+	votingNtfns chan interface{}
+	stopped     bool
+}
+
+// NewMockChainClient returns a MockChainClient with empty chain state.
+func NewMockChainClient() *MockChainClient {
+	return &MockChainClient{
+		Blocks:          make(map[chainhash.Hash]*wire.MsgBlock),
+		Mempool:         make(map[hcjson.GetRawMempoolTxTypeCmd][]*chainhash.Hash),
+		Transactions:    make(map[chainhash.Hash]*hcjson.TxRawResult),
+		TxOuts:          make(map[wire.OutPoint]*hcjson.GetTxOutResult),
+		UsedAddresses:   make(map[string]bool),
+		LiveTickets:     make(map[chainhash.Hash]bool),
+		ExpiredTickets:  make(map[chainhash.Hash]bool),
+		MissedTickets:   make(map[chainhash.Hash]bool),
+		filterAddrs:     make(map[string]bool),
+		filterOutPoints: make(map[wire.OutPoint]bool),
+		ntfns:           make(chan interface{}),
+		votingNtfns:     make(chan interface{}),
+	}
+}
+
+// Disconnected returns whether the client has been stopped.
+func (c *MockChainClient) Disconnected() bool {
+	return c.Stopped()
+}
+
+// GetBestBlock returns the last of the BlockHashes.
+func (c *MockChainClient) GetBestBlock() (*chainhash.Hash, int64, error) {
+	if len(c.BlockHashes) == 0 {
+		return nil, 0, fmt.Errorf("best block %v", ErrNotFound)
+	}
+	height := int64(len(c.BlockHashes) - 1)
+	hash := c.BlockHashes[height]
+	return &hash, height, nil
+}
+
+// GetBlock returns a block from the Blocks field.
+func (c *MockChainClient) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	block, ok := c.Blocks[*blockHash]
+	if !ok {
+		return nil, fmt.Errorf("block %v %v", blockHash, ErrNotFound)
+	}
+	return block, nil
+}
+
+// GetBlockHash returns the main chain block hash at a height.
+func (c *MockChainClient) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	if blockHeight < 0 || blockHeight >= int64(len(c.BlockHashes)) {
+		return nil, fmt.Errorf("block at height %d %v", blockHeight, ErrNotFound)
+	}
+	hash := c.BlockHashes[blockHeight]
+	return &hash, nil
+}
+
+// GetHeaders returns the Headers field, or no headers if it is nil.
+func (c *MockChainClient) GetHeaders(blockLocators []chainhash.Hash, hashStop *chainhash.Hash) (*hcjson.GetHeadersResult, error) {
+	if c.Headers == nil {
+		return &hcjson.GetHeadersResult{}, nil
+	}
+	return c.Headers, nil
+}
+
+// GetRawMempool returns the hashes of mempool transactions of a type.
+func (c *MockChainClient) GetRawMempool(txType hcjson.GetRawMempoolTxTypeCmd) ([]*chainhash.Hash, error) {
+	if txType != hcjson.GRMAll {
+		return c.Mempool[txType], nil
+	}
+	var hashes []*chainhash.Hash
+	for _, typeHashes := range c.Mempool {
+		hashes = append(hashes, typeHashes...)
+	}
+	return hashes, nil
+}
+
+// GetRawTransaction returns a transaction of any of the Blocks.
+func (c *MockChainClient) GetRawTransaction(txHash *chainhash.Hash) (*hcutil.Tx, error) {
+	for _, block := range c.Blocks {
+		for _, tx := range block.Transactions {
+			if tx.TxHash() == *txHash {
+				return hcutil.NewTx(tx), nil
+			}
+		}
+		for _, tx := range block.STransactions {
+			if tx.TxHash() == *txHash {
+				return hcutil.NewTx(tx), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("transaction %v %v", txHash, ErrNotFound)
+}
+
+// GetRawTransactionVerbose returns a transaction from the Transactions field.
+func (c *MockChainClient) GetRawTransactionVerbose(txHash *chainhash.Hash) (*hcjson.TxRawResult, error) {
+	tx, ok := c.Transactions[*txHash]
+	if !ok {
+		return nil, fmt.Errorf("transaction %v %v", txHash, ErrNotFound)
+	}
+	return tx, nil
+}
+
+// GetStakeDifficulty returns the StakeDifficulty field.
+func (c *MockChainClient) GetStakeDifficulty() (*hcjson.GetStakeDifficultyResult, error) {
+	if c.StakeDifficulty == nil {
+		return nil, fmt.Errorf("stake difficulty %v", ErrNotFound)
+	}
+	return c.StakeDifficulty, nil
+}
+
+// GetTxOut returns an output from the TxOuts field, or nil if the output is
+// not unspent, as hcd does.
+func (c *MockChainClient) GetTxOut(txHash *chainhash.Hash, index uint32, mempool bool) (*hcjson.GetTxOutResult, error) {
+	for op, txOut := range c.TxOuts {
+		if op.Hash == *txHash && op.Index == index {
+			return txOut, nil
+		}
+	}
+	return nil, nil
+}
+
+// SendRawTransaction records a published transaction and returns its hash.
+func (c *MockChainClient) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.SendErr != nil && len(c.sent) >= c.SendErrAfter {
+		return nil, c.SendErr
+	}
+	c.sent = append(c.sent, tx)
+	hash := tx.TxHash()
+	return &hash, nil
+}
+
+// SentTransactions returns the transactions published with
+// SendRawTransaction.
+func (c *MockChainClient) SentTransactions() []*wire.MsgTx {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*wire.MsgTx(nil), c.sent...)
+}
+
+// TxFeeInfo returns the FeeInfo field.
+func (c *MockChainClient) TxFeeInfo(blocks *uint32, start *uint32, end *uint32) (*hcjson.TxFeeInfoResult, error) {
+	if c.FeeInfo == nil {
+		return nil, fmt.Errorf("fee info %v", ErrNotFound)
+	}
+	return c.FeeInfo, nil
+}
+
+// RawRequest calls RawRequestFunc.
+func (c *MockChainClient) RawRequest(method string, params []json.RawMessage) (json.RawMessage, error) {
+	if c.RawRequestFunc == nil {
+		return nil, fmt.Errorf("method %s %v", method, ErrNotFound)
+	}
+	return c.RawRequestFunc(method, params)
+}
+
+// ExistsAddress returns whether an address is in UsedAddresses.
+func (c *MockChainClient) ExistsAddress(address hcutil.Address) (bool, error) {
+	return c.UsedAddresses[address.EncodeAddress()], nil
+}
+
+// ExistsAddresses returns a hex-encoded bitset describing which of the
+// addresses are in UsedAddresses.
+func (c *MockChainClient) ExistsAddresses(addresses []hcutil.Address) (string, error) {
+	bits := bitset.NewBytes(len(addresses))
+	for i, addr := range addresses {
+		bits.SetBool(i, c.UsedAddresses[addr.EncodeAddress()])
+	}
+	return hex.EncodeToString(bits), nil
+}
+
+// ExistsLiveTicket returns whether a ticket is in LiveTickets.
+func (c *MockChainClient) ExistsLiveTicket(hash *chainhash.Hash) (bool, error) {
+	return c.LiveTickets[*hash], nil
+}
+
+// ExistsLiveTickets returns a hex-encoded bitset describing which of the
+// tickets are in LiveTickets.
+func (c *MockChainClient) ExistsLiveTickets(hashes []*chainhash.Hash) (string, error) {
+	return ticketBits(hashes, c.LiveTickets), nil
+}
+
+// ExistsExpiredTickets returns a hex-encoded bitset describing which of the
+// tickets are in ExpiredTickets.
+func (c *MockChainClient) ExistsExpiredTickets(hashes []*chainhash.Hash) (string, error) {
+	return ticketBits(hashes, c.ExpiredTickets), nil
+}
+
+// ExistsMissedTickets returns a hex-encoded bitset describing which of the
+// tickets are in MissedTickets.
+func (c *MockChainClient) ExistsMissedTickets(hashes []*chainhash.Hash) (string, error) {
+	return ticketBits(hashes, c.MissedTickets), nil
+}
+
+func ticketBits(hashes []*chainhash.Hash, set map[chainhash.Hash]bool) string {
+	bits := bitset.NewBytes(len(hashes))
+	for i, hash := range hashes {
+		bits.SetBool(i, set[*hash])
+	}
+	return hex.EncodeToString(bits)
+}
+
+// LoadTxFilter records the addresses and outpoints of the transaction filter.
+// The recorded filter is cleared when reload is set.
+func (c *MockChainClient) LoadTxFilter(reload bool, addresses []hcutil.Address, outPoints []wire.OutPoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if reload {
+		c.filterAddrs = make(map[string]bool)
+		c.filterOutPoints = make(map[wire.OutPoint]bool)
+	}
+	for _, addr := range addresses {
+		c.filterAddrs[addr.EncodeAddress()] = true
+	}
+	for _, op := range outPoints {
+		c.filterOutPoints[op] = true
+	}
+	return nil
+}
+
+// FilterContainsAddress returns whether an address was loaded into the
+// transaction filter.
+func (c *MockChainClient) FilterContainsAddress(addr hcutil.Address) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filterAddrs[addr.EncodeAddress()]
+}
+
+// FilterContainsOutPoint returns whether an outpoint was loaded into the
+// transaction filter.
+func (c *MockChainClient) FilterContainsOutPoint(op *wire.OutPoint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filterOutPoints[*op]
+}
+
+// NotifyBlocks records that block notifications were requested.
+func (c *MockChainClient) NotifyBlocks() error {
+	c.mu.Lock()
+	c.blocksNotified = true
+	c.mu.Unlock()
+	return nil
+}
+
+// BlocksNotified returns whether NotifyBlocks was called.
+func (c *MockChainClient) BlocksNotified() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blocksNotified
+}
+
+// Rescan records the rescanned blocks and returns no discovered data.
+func (c *MockChainClient) Rescan(blockHashes []chainhash.Hash) (*hcjson.RescanResult, error) {
+	c.mu.Lock()
+	c.rescannedBlocks = append(c.rescannedBlocks, blockHashes...)
+	c.mu.Unlock()
+	return &hcjson.RescanResult{}, nil
+}
+
+// RescannedBlocks returns the blocks passed to Rescan.
+func (c *MockChainClient) RescannedBlocks() []chainhash.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]chainhash.Hash(nil), c.rescannedBlocks...)
+}
+
+// SetParams does nothing, as the mock client sends no omni notifications.
+func (c *MockChainClient) SetParams(enableOmni bool) error {
+	return nil
+}
+
+// Notifications returns the channel of chain notifications, which are sent by
+// the test with SendNotification.  It is closed by Stop.
+func (c *MockChainClient) Notifications() <-chan interface{} {
+	return c.ntfns
+}
+
+// NotificationsVoting returns the channel of voting notifications, which are
+// sent by the test with SendVotingNotification.  It is closed by Stop.
+func (c *MockChainClient) NotificationsVoting() <-chan interface{} {
+	return c.votingNtfns
+}
+
+// SendNotification blocks until the wallet receives a chain notification,
+// such as a chain.BlockConnected.
+func (c *MockChainClient) SendNotification(n interface{}) {
+	c.ntfns <- n
+}
+
+// SendVotingNotification blocks until the wallet receives a voting
+// notification, such as a chain.WinningTickets.
+func (c *MockChainClient) SendVotingNotification(n interface{}) {
+	c.votingNtfns <- n
+}
+
+// NotifyWinningTickets does nothing, as winning tickets are sent by the test.
+func (c *MockChainClient) NotifyWinningTickets() error {
+	return nil
+}
+
+// NotifySpentAndMissedTickets does nothing, as the mock client sends no spent
+// and missed ticket notifications.
+func (c *MockChainClient) NotifySpentAndMissedTickets() error {
+	return nil
+}
+
+// Stop closes the notification channels, ending the wallet's handling of
+// notifications.  Stop may be called more than once.
+func (c *MockChainClient) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.stopped {
+		c.stopped = true
+		close(c.ntfns)
+		close(c.votingNtfns)
+	}
+}
+
+// Stopped returns whether Stop was called.
+func (c *MockChainClient) Stopped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopped
+}
+
+// WaitForShutdown returns immediately, as the mock client has no goroutines.
+func (c *MockChainClient) WaitForShutdown() {}
@@ -0,0 +1,69 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+func TestMockTicketBits(t *testing.T) {
+	c := NewMockChainClient()
+	hashes := make([]*chainhash.Hash, 10)
+	for i := range hashes {
+		hashes[i] = &chainhash.Hash{byte(i)}
+	}
+	c.LiveTickets[*hashes[1]] = true
+	c.LiveTickets[*hashes[9]] = true
+	c.MissedTickets[*hashes[0]] = true
+
+	live, err := c.ExistsLiveTickets(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live != "0202" {
+		t.Errorf("live tickets bitset %q, expected %q", live, "0202")
+	}
+	missed, err := c.ExistsMissedTickets(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missed != "0100" {
+		t.Errorf("missed tickets bitset %q, expected %q", missed, "0100")
+	}
+	exists, err := c.ExistsLiveTicket(hashes[9])
+	if err != nil || !exists {
+		t.Errorf("ticket %v not reported live", hashes[9])
+	}
+}
+
+func TestMockTxFilter(t *testing.T) {
+	c := NewMockChainClient()
+	addr, err := hcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.SimNetParams, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wire.OutPoint{Index: 1}
+
+	err = c.LoadTxFilter(false, []hcutil.Address{addr}, []wire.OutPoint{op})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.FilterContainsAddress(addr) || !c.FilterContainsOutPoint(&op) {
+		t.Fatal("filter does not contain loaded address and outpoint")
+	}
+	err = c.LoadTxFilter(true, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.FilterContainsAddress(addr) || c.FilterContainsOutPoint(&op) {
+		t.Fatal("filter was not cleared by reload")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultWalletName is the name of the wallet loaded from the application data
+// directory, which serves RPC requests that do not name a wallet.
+const DefaultWalletName = ""
+
+// Collection is a set of loaders, each loading an isolated wallet with its own
+// database directory, keyed by wallet name.  It allows a single process to
+// serve several wallets.
+//
+// Collection is safe for concurrent access.
+type Collection struct {
+	loaders map[string]*Loader
+	mu      sync.Mutex
+}
+
+// NewCollection creates a collection holding the loader of the default wallet.
+func NewCollection(defaultLoader *Loader) *Collection {
+	return &Collection{
+		loaders: map[string]*Loader{DefaultWalletName: defaultLoader},
+	}
+}
+
+// Add adds the loader of a named wallet to the collection.  Wallet names must
+// be unique.
+func (c *Collection) Add(name string, l *Loader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.loaders[name]; ok {
+		return fmt.Errorf("wallet %q is already in the collection", name)
+	}
+	c.loaders[name] = l
+	return nil
+}
+
+// Loader returns the loader of the named wallet and whether the wallet is part
+// of the collection.  The default wallet is named DefaultWalletName.
+func (c *Collection) Loader(name string) (*Loader, bool) {
+	c.mu.Lock()
+	l, ok := c.loaders[name]
+	c.mu.Unlock()
+	return l, ok
+}
+
+// Default returns the loader of the default wallet.
+func (c *Collection) Default() *Loader {
+	l, _ := c.Loader(DefaultWalletName)
+	return l
+}
+
+// Names returns the sorted names of all wallets in the collection, including
+// the default wallet.
+func (c *Collection) Names() []string {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.loaders))
+	for name := range c.loaders {
+		names = append(names, name)
+	}
+	c.mu.Unlock()
+	sort.Strings(names)
+	return names
+}
@@ -31,4 +31,13 @@ var (
 	// ErrNoChainClient describes the error condition of attempting to start
 	// ticketbuyer when no chain client is available.
 	ErrNoChainClient = errors.New("chain client not available")
+
+	// ErrInsecureDBEncryption describes the error condition of attempting to
+	// create a wallet with an encrypted database without choosing a public
+	// passphrase.
+	ErrInsecureDBEncryption = errors.New("database encryption requires a public passphrase")
+
+	// ErrPromoted describes the error condition of attempting to promote a
+	// replica wallet which has already been promoted.
+	ErrPromoted = errors.New("replica already promoted")
 )
@@ -0,0 +1,440 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SnapshotPath is the path at which a primary wallet's legacy RPC server
+// serves snapshots of its database to replica followers.
+const SnapshotPath = "/replication/snapshot"
+
+// Headers of snapshot responses.  The journal headers describe the position of
+// the primary's journal before the snapshot was created, so changes committed
+// while it is created are replicated by the next snapshot.  The digest is sent
+// as a trailer after the database, so followers can detect snapshots which
+// were cut short.
+const (
+	SnapshotTimeHeader       = "X-Snapshot-Time"
+	SnapshotHeightHeader     = "X-Snapshot-Height"
+	SnapshotDriverHeader     = "X-Snapshot-Driver"
+	SnapshotJournalIDHeader  = "X-Snapshot-Journal-Id"
+	SnapshotJournalSeqHeader = "X-Snapshot-Journal-Seq"
+	SnapshotDigestHeader     = "X-Snapshot-Digest"
+)
+
+// FollowerStatus describes the replication progress of a Follower.
+type FollowerStatus struct {
+	// Primary is the URL of the primary wallet's legacy RPC server.
+	Primary string
+
+	// SnapshotTime is when the primary created the latest snapshot of its
+	// database, and SnapshotHeight was its main chain height at the time.
+	// SnapshotTime is the zero time until the first snapshot is received.
+	SnapshotTime   time.Time
+	SnapshotHeight int32
+
+	// Snapshots is the number of snapshots which replaced the database.
+	// Snapshots identical to the replica are not counted.
+	Snapshots uint64
+
+	// Applied is the number of commits of the primary whose changes were
+	// applied to the replica without a snapshot.
+	Applied uint64
+
+	// Journal is the position of the primary's journal replicated by the
+	// replica, and Pending the time of the earliest commit of the
+	// primary which is not yet replicated.  Pending is the zero time when
+	// the replica is caught up with the primary.
+	Journal JournalPosition
+	Pending time.Time
+
+	// LastAttempt is the time of the latest replication attempt, and
+	// LastErr its error, if any.
+	LastAttempt time.Time
+	LastErr     error
+}
+
+// Follower maintains a read-only replica of a primary wallet by tailing the
+// primary's journal and applying the changes of each commit to the loader's
+// wallet database.  The database is replaced with a snapshot of the primary's
+// when the replica is created, and when the primary no longer retains the
+// changes the replica is missing.
+type Follower struct {
+	loader         *Loader
+	primary        string
+	username       string
+	password       string
+	client         *http.Client
+	interval       time.Duration
+	pubPassphrase  []byte
+	privPassphrase []byte
+
+	// digest is the SHA-256 digest of the database served by the loader.
+	// It is only accessed by Run.
+	digest []byte
+
+	// promote is closed to stop Run for a promotion, and stopped is closed
+	// when Run returns.
+	promote chan struct{}
+	stopped chan struct{}
+
+	mu           sync.Mutex
+	status       FollowerStatus
+	promoted     bool
+	afterPromote []func()
+}
+
+// NewFollower creates a Follower replicating the wallet of the primary legacy
+// RPC server at the URL primary into the loader's wallet database, at most
+// once every interval.  The loader's wallet is opened with the passphrases of
+// the primary wallet and must not be loaded by other means.
+func NewFollower(l *Loader, primary, username, password string, tlsConfig *tls.Config,
+	interval time.Duration, pubPassphrase, privPassphrase []byte) *Follower {
+
+	f := &Follower{
+		loader:   l,
+		primary:  primary,
+		username: username,
+		password: password,
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		interval:       interval,
+		pubPassphrase:  pubPassphrase,
+		privPassphrase: privPassphrase,
+		promote:        make(chan struct{}),
+		stopped:        make(chan struct{}),
+		status:         FollowerStatus{Primary: primary},
+	}
+	l.mu.Lock()
+	l.follower = f
+	l.mu.Unlock()
+	return f
+}
+
+// Follower returns the Follower replicating into the loader's wallet database,
+// or nil if the loader's wallet is not a replica or has been promoted.
+func (l *Loader) Follower() *Follower {
+	l.mu.Lock()
+	f := l.follower
+	l.mu.Unlock()
+	return f
+}
+
+// Status returns the replication progress of the follower.
+func (f *Follower) Status() FollowerStatus {
+	f.mu.Lock()
+	status := f.status
+	f.mu.Unlock()
+	return status
+}
+
+// Run replicates the primary wallet until quit is closed or the replica is
+// promoted, which also cancels any snapshot being received.  A previously
+// replicated database is served until the first snapshot is received.
+func (f *Follower) Run(quit <-chan struct{}) {
+	defer close(f.stopped)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-quit:
+		case <-f.promote:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	exists, err := f.loader.WalletExists()
+	if err == nil && exists {
+		_, err = f.loader.OpenExistingWallet(f.pubPassphrase, f.privPassphrase)
+	}
+	if err != nil {
+		log.Errorf("Failed to open replicated wallet: %v", err)
+	}
+
+	var lastSnapshot time.Time
+	for {
+		status, err := f.tail(ctx)
+		_, loaded := f.loader.LoadedWallet()
+		switch {
+		case err != nil || status.Pending == 0:
+		case loaded && len(status.Entries) != 0:
+			err = f.apply(status)
+		default:
+			// Snapshots are replicated at most once per interval.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(f.interval - time.Since(lastSnapshot)):
+			}
+			lastSnapshot = time.Now()
+			err = f.replicate(ctx, status.JournalPosition)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to replicate wallet from %s: %v", f.primary, err)
+		}
+		f.mu.Lock()
+		f.status.LastAttempt = time.Now()
+		f.status.LastErr = err
+		f.mu.Unlock()
+		if err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// get makes an authenticated GET request for the path of the primary.
+func (f *Follower) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", f.primary+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(f.username, f.password)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("primary responded with status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// tail waits for the primary's journal to advance past the replicated position,
+// and records when the earliest commit after it was made.  Entries which do
+// not continue the replicated position are discarded.
+func (f *Follower) tail(ctx context.Context) (*JournalStatus, error) {
+	f.mu.Lock()
+	pos := f.status.Journal
+	f.mu.Unlock()
+
+	query := url.Values{}
+	query.Set("id", pos.ID)
+	query.Set("seq", strconv.FormatUint(pos.Seq, 10))
+	resp, err := f.get(ctx, JournalPath+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	status := new(JournalStatus)
+	err = json.NewDecoder(resp.Body).Decode(status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid journal status: %v", err)
+	}
+	if len(status.Entries) != 0 && (status.ID != pos.ID ||
+		status.Entries[0].Seq != pos.Seq+1) {
+		status.Entries = nil
+	}
+
+	f.mu.Lock()
+	f.status.Pending = time.Time{}
+	if status.Pending != 0 {
+		f.status.Pending = time.Unix(status.Pending, 0)
+	}
+	f.mu.Unlock()
+	return status, nil
+}
+
+// apply applies the changes of the journal entries of status to the replica.
+// The replica is replaced by the next snapshot when the changes fail to apply.
+func (f *Follower) apply(status *JournalStatus) error {
+	entries := status.Entries
+	_, err := f.loader.ApplyJournal(entries, f.pubPassphrase, f.privPassphrase)
+	if err != nil {
+		f.mu.Lock()
+		f.status.Journal = JournalPosition{}
+		f.mu.Unlock()
+		return err
+	}
+
+	// The database no longer matches the digest of a snapshot.
+	f.digest = nil
+
+	last := entries[len(entries)-1]
+	f.mu.Lock()
+	f.status.Applied += uint64(len(entries))
+	f.status.Journal = JournalPosition{ID: status.ID, Seq: last.Seq}
+	if last.Seq >= status.Seq {
+		f.status.Pending = time.Time{}
+	}
+	f.mu.Unlock()
+	log.Debugf("Applied %d commits of %s up to journal position %d",
+		len(entries), f.primary, last.Seq)
+	return nil
+}
+
+// replicate downloads a snapshot of the primary's database and replaces the
+// loader's wallet with it, unless the snapshot is identical to the database
+// already served.  The replica is caught up when the snapshot replicates the
+// latest known position of the primary's journal.
+func (f *Follower) replicate(ctx context.Context, latest JournalPosition) error {
+	resp, err := f.get(ctx, SnapshotPath)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	snapshotTime, err := strconv.ParseInt(resp.Header.Get(SnapshotTimeHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot time: %v", err)
+	}
+	snapshotHeight, err := strconv.ParseInt(resp.Header.Get(SnapshotHeightHeader), 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot height: %v", err)
+	}
+	journalSeq, err := strconv.ParseUint(resp.Header.Get(SnapshotJournalSeqHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot journal position: %v", err)
+	}
+	journal := JournalPosition{
+		ID:  resp.Header.Get(SnapshotJournalIDHeader),
+		Seq: journalSeq,
+	}
+	driver := f.loader.DatabaseDriver()
+	if d := resp.Header.Get(SnapshotDriverHeader); d != driver {
+		return fmt.Errorf("primary database driver %q does not match "+
+			"replica database driver %q", d, driver)
+	}
+
+	err = os.MkdirAll(f.loader.dbDirPath, 0700)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(f.loader.dbDirPath, walletDbName+".replica")
+	digest, err := writeSnapshot(path, resp)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	_, loaded := f.loader.LoadedWallet()
+	if loaded && bytes.Equal(digest, f.digest) {
+		os.Remove(path)
+	} else {
+		_, err = f.loader.ReplaceWallet(path, f.pubPassphrase, f.privPassphrase)
+		if err != nil {
+			os.Remove(path)
+			return err
+		}
+		f.digest = digest
+		f.mu.Lock()
+		f.status.Snapshots++
+		f.mu.Unlock()
+		log.Infof("Replicated wallet snapshot at height %d from %s",
+			snapshotHeight, f.primary)
+	}
+
+	f.mu.Lock()
+	f.status.SnapshotTime = time.Unix(snapshotTime, 0)
+	f.status.SnapshotHeight = int32(snapshotHeight)
+	f.status.Journal = journal
+	if journal.ID == latest.ID && journal.Seq >= latest.Seq {
+		f.status.Pending = time.Time{}
+	}
+	f.mu.Unlock()
+	return nil
+}
+
+// RunAfterPromote adds a function to be executed when the replica is promoted.
+// Functions are executed by Promote in the order they are added.
+func (f *Follower) RunAfterPromote(fn func()) {
+	f.mu.Lock()
+	f.afterPromote = append(f.afterPromote, fn)
+	f.mu.Unlock()
+}
+
+// Promote stops replication and makes the loader's wallet a primary wallet,
+// after which the loader no longer has a follower.  Any snapshot being
+// received is canceled, and the latest replicated database is kept.  Run must
+// have been started, and Promote waits for it to return.
+func (f *Follower) Promote() error {
+	f.mu.Lock()
+	if f.promoted {
+		f.mu.Unlock()
+		return ErrPromoted
+	}
+	f.promoted = true
+	fns := f.afterPromote
+	f.afterPromote = nil
+	f.mu.Unlock()
+
+	close(f.promote)
+	<-f.stopped
+
+	// A failed replacement leaves the replicated database unloaded.
+	if _, ok := f.loader.LoadedWallet(); !ok {
+		_, err := f.loader.OpenExistingWallet(f.pubPassphrase, f.privPassphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.loader.mu.Lock()
+	f.loader.follower = nil
+	f.loader.mu.Unlock()
+	log.Infof("Promoted replica of %s to a primary wallet", f.primary)
+
+	for _, fn := range fns {
+		fn()
+	}
+	return nil
+}
+
+// writeSnapshot writes the snapshot in the body of resp to a file at path and
+// returns its digest after checking it against the digest trailer.
+func writeSnapshot(path string, resp *http.Response) ([]byte, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, h), resp.Body)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	digest := h.Sum(nil)
+	trailer, err := hex.DecodeString(resp.Trailer.Get(SnapshotDigestHeader))
+	if err != nil || !bytes.Equal(trailer, digest) {
+		return nil, errors.New("snapshot does not match its digest")
+	}
+	return digest, nil
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// testPrimary serves the journal and snapshots of the loader's wallet like the
+// legacy RPC server of a primary wallet.
+func testPrimary(l *Loader) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(SnapshotPath, func(w http.ResponseWriter, r *http.Request) {
+		pos := l.Journal().Position()
+		h := w.Header()
+		h.Set(SnapshotTimeHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		h.Set(SnapshotHeightHeader, "0")
+		h.Set(SnapshotDriverHeader, l.DatabaseDriver())
+		h.Set(SnapshotJournalIDHeader, pos.ID)
+		h.Set(SnapshotJournalSeqHeader, strconv.FormatUint(pos.Seq, 10))
+		h.Set("Trailer", SnapshotDigestHeader)
+		digest := sha256.New()
+		if l.CopyDatabase(io.MultiWriter(w, digest)) != nil {
+			return
+		}
+		h.Set(SnapshotDigestHeader, hex.EncodeToString(digest.Sum(nil)))
+	})
+	mux.HandleFunc(JournalPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		seq, _ := strconv.ParseUint(query.Get("seq"), 10, 64)
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+		s := l.Journal().Wait(ctx, JournalPosition{ID: query.Get("id"), Seq: seq})
+		json.NewEncoder(w).Encode(&s)
+	})
+	return httptest.NewServer(mux)
+}
+
+// testReplica returns a loader without a wallet in a temporary directory.
+func testReplica(t *testing.T) (*Loader, func()) {
+	dir, err := ioutil.TempDir("", "replicatest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLoader(&chaincfg.SimNetParams, dir, &StakeOptions{}, 0, false,
+		txauthor.TxLimits{}, false, 0.001, false)
+	return l, func() {
+		l.UnloadWallet()
+		os.RemoveAll(dir)
+	}
+}
+
+// waitFor polls cond until it returns true, failing the test if it does not
+// within ten seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// caughtUp returns whether the follower replicated the latest commit of the
+// primary.
+func caughtUp(f *Follower, primary *Loader) bool {
+	s := f.Status()
+	_, loaded := f.loader.LoadedWallet()
+	return loaded && s.Journal == primary.Journal().Position() && s.Pending.IsZero()
+}
+
+func TestReplaceWalletWaitsForHolds(t *testing.T) {
+	primary, teardown := testLoader(t)
+	defer teardown()
+	replica, teardownReplica := testReplica(t)
+	defer teardownReplica()
+
+	snapshot := func() string {
+		path := filepath.Join(replica.dbDirPath, walletDbName+".replica")
+		err := os.MkdirAll(replica.dbDirPath, 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		err = primary.CopyDatabase(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	w, err := replica.ReplaceWallet(snapshot(), testPubPass, testPrivPass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := replica.HoldWallet()
+	replaced := make(chan error)
+	path := snapshot()
+	go func() {
+		_, err := replica.ReplaceWallet(path, testPubPass, testPrivPass)
+		replaced <- err
+	}()
+	select {
+	case <-replaced:
+		t.Fatal("wallet was replaced while it was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if loaded, _ := replica.LoadedWallet(); loaded != w {
+		t.Fatal("held wallet was unloaded")
+	}
+
+	release()
+	select {
+	case err := <-replaced:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("wallet was not replaced after it was released")
+	}
+	if loaded, _ := replica.LoadedWallet(); loaded == nil || loaded == w {
+		t.Fatal("wallet was not replaced")
+	}
+}
+
+func TestFollowerTailsJournal(t *testing.T) {
+	primary, teardown := testLoader(t)
+	defer teardown()
+	replica, teardownReplica := testReplica(t)
+	defer teardownReplica()
+	server := testPrimary(primary)
+	defer server.Close()
+
+	f := NewFollower(replica, server.URL, "user", "pass", nil,
+		10*time.Millisecond, testPubPass, testPrivPass)
+	quit := make(chan struct{})
+	go f.Run(quit)
+	defer func() {
+		close(quit)
+		<-f.stopped
+	}()
+	waitFor(t, "the first snapshot", func() bool { return caughtUp(f, primary) })
+
+	// Changes committed by the primary are replicated.
+	err := walletdb.Update(primary.db, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("replicated"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the replicated commit", func() bool { return caughtUp(f, primary) })
+	if s := f.Status(); s.Snapshots != 1 || s.Applied == 0 || s.LastErr != nil {
+		t.Errorf("%d snapshots and %d commits replicated, last error %v; "+
+			"want the commit applied without a snapshot", s.Snapshots,
+			s.Applied, s.LastErr)
+	}
+
+	release := replica.HoldWallet()
+	replica.mu.Lock()
+	db := replica.db
+	replica.mu.Unlock()
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		if tx.ReadBucket([]byte("replicated")) == nil {
+			t.Error("replica does not contain the committed change")
+		}
+		return nil
+	})
+	release()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFollowerPromote(t *testing.T) {
+	primary, teardown := testLoader(t)
+	defer teardown()
+	replica, teardownReplica := testReplica(t)
+	defer teardownReplica()
+	server := testPrimary(primary)
+	defer server.Close()
+
+	f := NewFollower(replica, server.URL, "user", "pass", nil,
+		10*time.Millisecond, testPubPass, testPrivPass)
+	var promoted bool
+	f.RunAfterPromote(func() { promoted = true })
+	go f.Run(make(chan struct{}))
+	waitFor(t, "the first snapshot", func() bool { return caughtUp(f, primary) })
+
+	err := f.Promote()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-f.stopped:
+	default:
+		t.Fatal("replication was not stopped")
+	}
+	if !promoted {
+		t.Error("promotion functions were not executed")
+	}
+	if replica.Follower() != nil {
+		t.Error("promoted loader has a follower")
+	}
+	if _, ok := replica.LoadedWallet(); !ok {
+		t.Error("promoted wallet is not loaded")
+	}
+	if err := f.Promote(); err != ErrPromoted {
+		t.Errorf("promoting twice returned %v, want ErrPromoted", err)
+	}
+}
@@ -0,0 +1,498 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// JournalPath is the path at which a primary wallet's legacy RPC server
+// serves its journal to replica followers.
+const JournalPath = "/replication/journal"
+
+// JournalWait is the longest time a journal request waits for the primary's
+// journal to advance before it is answered with the current position.
+const JournalWait = 30 * time.Second
+
+// journalTimes is the number of recent commits whose times are recorded by a
+// journal.
+const journalTimes = 1024
+
+// journalBytes limits the size of the changes of the recent commits retained by
+// a journal, and journalResponseBytes the size of the changes returned to a
+// follower at once.  Followers behind the retained changes are replicated with
+// a snapshot instead.
+const (
+	journalBytes         = 32 << 20
+	journalResponseBytes = 4 << 20
+)
+
+// Operations of journal changes.
+const (
+	JournalPut          = "put"
+	JournalDelete       = "delete"
+	JournalCreateBucket = "createbucket"
+	JournalDeleteBucket = "deletebucket"
+)
+
+// JournalPosition identifies the commit of a write transaction to a wallet
+// database.  The ID distinguishes the journals of different processes, as the
+// sequence number of the first commit of every journal is 1.  Seq is zero
+// before the first commit.
+type JournalPosition struct {
+	ID  string `json:"id"`
+	Seq uint64 `json:"seq"`
+}
+
+// JournalStatus is the response to journal requests of replica followers.
+type JournalStatus struct {
+	JournalPosition
+
+	// Pending is the Unix time of the earliest commit after the requested
+	// position, or zero when the requested position is current.
+	Pending int64 `json:"pending"`
+
+	// Entries are the changes committed after the requested position, in
+	// commit order, when they are retained by the journal.  Entries may end
+	// before the current position when the changes are large.
+	Entries []JournalEntry `json:"entries,omitempty"`
+}
+
+// JournalEntry describes the changes of a committed write transaction.
+type JournalEntry struct {
+	Seq     uint64          `json:"seq"`
+	Time    int64           `json:"time"`
+	Changes []JournalChange `json:"changes"`
+}
+
+// JournalChange is a single change of a committed write transaction.  Bucket
+// is the path of the changed bucket, which is empty for changes of top-level
+// buckets, and Key is the changed key or the name of the created or deleted
+// bucket.
+type JournalChange struct {
+	Op     string   `json:"op"`
+	Bucket [][]byte `json:"bucket,omitempty"`
+	Key    []byte   `json:"key"`
+	Value  []byte   `json:"value,omitempty"`
+}
+
+func (c *JournalChange) size() int {
+	n := len(c.Key) + len(c.Value)
+	for _, b := range c.Bucket {
+		n += len(b)
+	}
+	return n
+}
+
+// Journal records the commits of write transactions to the databases of the
+// wallets opened by a loader.  Followers tail the journal of their primary to
+// replicate each change soon after it is committed.
+type Journal struct {
+	id      string
+	created time.Time
+
+	mu      sync.Mutex
+	seq     uint64
+	times   []time.Time // Times of the commits up to and including seq
+	entries []journalEntry
+	size    int // Size of the changes of entries
+	changed chan struct{}
+}
+
+type journalEntry struct {
+	JournalEntry
+	size int
+}
+
+func newJournal() *Journal {
+	var id [8]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		panic(err)
+	}
+	return &Journal{
+		id:      hex.EncodeToString(id[:]),
+		created: time.Now(),
+		changed: make(chan struct{}),
+	}
+}
+
+// record records a commit with its changes and wakes all waiting requests.
+// The changes of the oldest commits are discarded when the retained changes
+// exceed journalBytes.
+func (j *Journal) record(changes []JournalChange) {
+	size := 0
+	for i := range changes {
+		size += changes[i].size()
+	}
+
+	j.mu.Lock()
+	j.seq++
+	now := time.Now()
+	if len(j.times) == journalTimes {
+		copy(j.times, j.times[1:])
+		j.times = j.times[:journalTimes-1]
+	}
+	j.times = append(j.times, now)
+	j.entries = append(j.entries, journalEntry{
+		JournalEntry: JournalEntry{Seq: j.seq, Time: now.Unix(), Changes: changes},
+		size:         size,
+	})
+	j.size += size
+	drop := 0
+	for len(j.entries)-drop > journalTimes || j.size > journalBytes {
+		j.size -= j.entries[drop].size
+		j.entries[drop] = journalEntry{}
+		drop++
+	}
+	j.entries = j.entries[drop:]
+	close(j.changed)
+	j.changed = make(chan struct{})
+	j.mu.Unlock()
+}
+
+// Position returns the position of the latest commit.
+func (j *Journal) Position() JournalPosition {
+	j.mu.Lock()
+	seq := j.seq
+	j.mu.Unlock()
+	return JournalPosition{ID: j.id, Seq: seq}
+}
+
+// status describes the journal relative to pos.  Requires mu to be locked.
+func (j *Journal) status(pos JournalPosition) JournalStatus {
+	s := JournalStatus{JournalPosition: JournalPosition{ID: j.id, Seq: j.seq}}
+	if pos == s.JournalPosition {
+		return s
+	}
+	// Positions of other journals, or older than the recorded commit
+	// times, are pending since the oldest recorded commit, or since the
+	// journal was created when nothing was committed since.
+	first := j.seq - uint64(len(j.times)) + 1
+	switch {
+	case pos.ID == j.id && pos.Seq+1 >= first && pos.Seq < j.seq:
+		s.Pending = j.times[pos.Seq+1-first].Unix()
+	case len(j.times) != 0:
+		s.Pending = j.times[0].Unix()
+	default:
+		s.Pending = j.created.Unix()
+	}
+
+	// Changes are returned when all changes after the position are
+	// retained.
+	if pos.ID != j.id || len(j.entries) == 0 || pos.Seq+1 < j.entries[0].Seq ||
+		pos.Seq >= j.seq {
+		return s
+	}
+	size := 0
+	for _, e := range j.entries[pos.Seq+1-j.entries[0].Seq:] {
+		if len(s.Entries) != 0 && size+e.size > journalResponseBytes {
+			break
+		}
+		size += e.size
+		s.Entries = append(s.Entries, e.JournalEntry)
+	}
+	return s
+}
+
+// Wait waits for a commit after pos and describes the journal relative to
+// pos.  It returns immediately when pos is not the latest position of the
+// journal, and returns the current position when ctx is done.
+func (j *Journal) Wait(ctx context.Context, pos JournalPosition) JournalStatus {
+	j.mu.Lock()
+	s := j.status(pos)
+	changed := j.changed
+	j.mu.Unlock()
+	if s.Pending != 0 {
+		return s
+	}
+
+	select {
+	case <-changed:
+	case <-ctx.Done():
+	}
+	j.mu.Lock()
+	s = j.status(pos)
+	j.mu.Unlock()
+	return s
+}
+
+// journalDB records the commits of write transactions to a wallet database
+// in a journal.
+type journalDB struct {
+	walletdb.DB
+	journal *Journal
+}
+
+func (db *journalDB) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	tx, err := db.DB.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+	return &journalTx{ReadWriteTx: tx, journal: db.journal}, nil
+}
+
+// Compact compacts the journaled database, if it is able to.
+func (db *journalDB) Compact() error {
+	return walletdb.Compact(db.DB)
+}
+
+type journalTx struct {
+	walletdb.ReadWriteTx
+	journal *Journal
+	changes []JournalChange
+}
+
+// change records a change made by the transaction.  Keys and values are
+// copied, as they are only valid until the transaction ends.
+func (tx *journalTx) change(op string, bucket [][]byte, key, value []byte) {
+	c := JournalChange{
+		Op:     op,
+		Bucket: bucket,
+		Key:    append([]byte{}, key...),
+	}
+	if value != nil {
+		c.Value = append([]byte{}, value...)
+	}
+	tx.changes = append(tx.changes, c)
+}
+
+// Err returns the error recorded by the journaled transaction, if it records
+// errors.
+func (tx *journalTx) Err() error {
+	if r, ok := tx.ReadWriteTx.(walletdb.ErrRecorder); ok {
+		return r.Err()
+	}
+	return nil
+}
+
+// Unwrap returns the journaled transaction.
+//
+// This function is part of the edb.Unwrapper interface implementation.
+func (tx *journalTx) Unwrap() walletdb.ReadWriteTx {
+	return tx.ReadWriteTx
+}
+
+func (tx *journalTx) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	b := tx.ReadWriteTx.ReadWriteBucket(key)
+	if b == nil {
+		return nil
+	}
+	return &journalBucket{ReadWriteBucket: b, tx: tx, path: [][]byte{copyKey(key)}}
+}
+
+func (tx *journalTx) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	b, err := tx.ReadWriteTx.CreateTopLevelBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	tx.change(JournalCreateBucket, nil, key, nil)
+	return &journalBucket{ReadWriteBucket: b, tx: tx, path: [][]byte{copyKey(key)}}, nil
+}
+
+func (tx *journalTx) DeleteTopLevelBucket(key []byte) error {
+	err := tx.ReadWriteTx.DeleteTopLevelBucket(key)
+	if err == nil {
+		tx.change(JournalDeleteBucket, nil, key, nil)
+	}
+	return err
+}
+
+func (tx *journalTx) Commit() error {
+	err := tx.ReadWriteTx.Commit()
+	if err == nil {
+		tx.journal.record(tx.changes)
+	}
+	return err
+}
+
+func copyKey(key []byte) []byte {
+	return append([]byte{}, key...)
+}
+
+// journalBucket records the changes to a bucket of a journaled transaction.
+type journalBucket struct {
+	walletdb.ReadWriteBucket
+	tx   *journalTx
+	path [][]byte
+}
+
+// nested returns the journaled bucket of the nested bucket b named key.
+func (b *journalBucket) nested(nb walletdb.ReadWriteBucket, key []byte) *journalBucket {
+	path := make([][]byte, len(b.path)+1)
+	copy(path, b.path)
+	path[len(b.path)] = copyKey(key)
+	return &journalBucket{ReadWriteBucket: nb, tx: b.tx, path: path}
+}
+
+func (b *journalBucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	nb := b.ReadWriteBucket.NestedReadWriteBucket(key)
+	if nb == nil {
+		return nil
+	}
+	return b.nested(nb, key)
+}
+
+func (b *journalBucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	nb, err := b.ReadWriteBucket.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	b.tx.change(JournalCreateBucket, b.path, key, nil)
+	return b.nested(nb, key), nil
+}
+
+func (b *journalBucket) CreateBucketIfNotExists(key []byte) (walletdb.ReadWriteBucket, error) {
+	nb, err := b.ReadWriteBucket.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, err
+	}
+	b.tx.change(JournalCreateBucket, b.path, key, nil)
+	return b.nested(nb, key), nil
+}
+
+func (b *journalBucket) DeleteNestedBucket(key []byte) error {
+	err := b.ReadWriteBucket.DeleteNestedBucket(key)
+	if err == nil {
+		b.tx.change(JournalDeleteBucket, b.path, key, nil)
+	}
+	return err
+}
+
+func (b *journalBucket) Put(key, value []byte) error {
+	err := b.ReadWriteBucket.Put(key, value)
+	if err == nil {
+		b.tx.change(JournalPut, b.path, key, value)
+	}
+	return err
+}
+
+func (b *journalBucket) Delete(key []byte) error {
+	err := b.ReadWriteBucket.Delete(key)
+	if err == nil {
+		b.tx.change(JournalDelete, b.path, key, nil)
+	}
+	return err
+}
+
+func (b *journalBucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	return &journalCursor{ReadWriteCursor: b.ReadWriteBucket.ReadWriteCursor(), bucket: b}
+}
+
+// journalCursor records the deletions of a cursor of a journaled bucket.  The
+// key of the cursor's position is remembered, as deleting does not return it.
+type journalCursor struct {
+	walletdb.ReadWriteCursor
+	bucket *journalBucket
+	key    []byte
+}
+
+func (c *journalCursor) at(k, v []byte) ([]byte, []byte) {
+	c.key = k
+	return k, v
+}
+
+func (c *journalCursor) First() (key, value []byte) {
+	return c.at(c.ReadWriteCursor.First())
+}
+
+func (c *journalCursor) Last() (key, value []byte) {
+	return c.at(c.ReadWriteCursor.Last())
+}
+
+func (c *journalCursor) Next() (key, value []byte) {
+	return c.at(c.ReadWriteCursor.Next())
+}
+
+func (c *journalCursor) Prev() (key, value []byte) {
+	return c.at(c.ReadWriteCursor.Prev())
+}
+
+func (c *journalCursor) Seek(seek []byte) (key, value []byte) {
+	return c.at(c.ReadWriteCursor.Seek(seek))
+}
+
+func (c *journalCursor) Delete() error {
+	err := c.ReadWriteCursor.Delete()
+	if err == nil && c.key != nil {
+		c.bucket.tx.change(JournalDelete, c.bucket.path, c.key, nil)
+	}
+	return err
+}
+
+// applyJournal commits the changes of journal entries to a database in a
+// single transaction.  Changes are applied idempotently, so entries committed
+// by the primary while a snapshot of its database was created may be applied
+// to the snapshot.
+func applyJournal(db walletdb.DB, entries []JournalEntry) error {
+	return walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		for _, e := range entries {
+			for i := range e.Changes {
+				err := applyJournalChange(tx, &e.Changes[i])
+				if err != nil {
+					return fmt.Errorf("journal entry %d: %v", e.Seq, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func applyJournalChange(tx walletdb.ReadWriteTx, c *JournalChange) error {
+	if len(c.Bucket) == 0 {
+		switch c.Op {
+		case JournalCreateBucket:
+			if tx.ReadWriteBucket(c.Key) != nil {
+				return nil
+			}
+			_, err := tx.CreateTopLevelBucket(c.Key)
+			return err
+		case JournalDeleteBucket:
+			if tx.ReadWriteBucket(c.Key) == nil {
+				return nil
+			}
+			return tx.DeleteTopLevelBucket(c.Key)
+		}
+		return fmt.Errorf("invalid %s change of a top-level bucket", c.Op)
+	}
+
+	b := tx.ReadWriteBucket(c.Bucket[0])
+	for _, name := range c.Bucket[1:] {
+		if b == nil {
+			break
+		}
+		b = b.NestedReadWriteBucket(name)
+	}
+	if b == nil {
+		return errors.New("bucket of change does not exist")
+	}
+	switch c.Op {
+	case JournalPut:
+		value := c.Value
+		if value == nil {
+			value = []byte{}
+		}
+		return b.Put(c.Key, value)
+	case JournalDelete:
+		return b.Delete(c.Key)
+	case JournalCreateBucket:
+		_, err := b.CreateBucketIfNotExists(c.Key)
+		return err
+	case JournalDeleteBucket:
+		if b.NestedReadWriteBucket(c.Key) == nil {
+			return nil
+		}
+		return b.DeleteNestedBucket(c.Key)
+	}
+	return fmt.Errorf("unknown journal operation %q", c.Op)
+}
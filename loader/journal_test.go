@@ -0,0 +1,227 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestJournalWait(t *testing.T) {
+	j := newJournal()
+	ctx := context.Background()
+
+	// Nothing is pending for the position of a new journal, and waiting
+	// for a commit ends when the context is done.
+	pos := j.Position()
+	if pos.Seq != 0 {
+		t.Fatalf("new journal at sequence %d", pos.Seq)
+	}
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	s := j.Wait(timeout, pos)
+	cancel()
+	if s.JournalPosition != pos || s.Pending != 0 {
+		t.Errorf("status %+v after waiting at the latest position", s)
+	}
+
+	// Positions of other journals are pending since the journal was
+	// created.
+	s = j.Wait(ctx, JournalPosition{ID: "other"})
+	if s.JournalPosition != pos || s.Pending != j.created.Unix() {
+		t.Errorf("status %+v for another journal, want pending since "+
+			"creation", s)
+	}
+
+	// Waiting at the latest position ends with the next commit.
+	done := make(chan JournalStatus)
+	go func() { done <- j.Wait(ctx, pos) }()
+	time.Sleep(10 * time.Millisecond)
+	before := time.Now().Unix()
+	j.record(nil)
+	select {
+	case s = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("commit did not end the wait")
+	}
+	if s.ID != pos.ID || s.Seq != 1 || s.Pending < before {
+		t.Errorf("status %+v after a commit", s)
+	}
+
+	// Positions behind the journal are pending since the earliest commit
+	// after them, and the oldest recorded commit when it is older.
+	for i := 0; i < journalTimes+1; i++ {
+		j.record(nil)
+	}
+	j.times[journalTimes-1] = time.Unix(1000, 0)
+	j.times[0] = time.Unix(10, 0)
+	latest := j.Position()
+	s = j.Wait(ctx, JournalPosition{ID: pos.ID, Seq: latest.Seq - 1})
+	if s.JournalPosition != latest || s.Pending != 1000 {
+		t.Errorf("status %+v one commit behind, want pending since 1000", s)
+	}
+	s = j.Wait(ctx, JournalPosition{ID: pos.ID, Seq: 1})
+	if s.Pending != 10 {
+		t.Errorf("status %+v behind the recorded commits, want pending "+
+			"since 10", s)
+	}
+}
+
+func TestJournalDB(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+
+	// The wallet database records each committed write transaction.
+	pos := l.Journal().Position()
+	err := walletdb.Update(l.db, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("journaltest"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Journal().Position(); got.Seq != pos.Seq+1 {
+		t.Fatalf("journal at sequence %d after a commit, want %d",
+			got.Seq, pos.Seq+1)
+	}
+
+	// Rolled back transactions are not recorded.
+	errRollback := errors.New("rollback")
+	err = walletdb.Update(l.db, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("rolledback"))
+		if err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatal(err)
+	}
+	if got := l.Journal().Position(); got.Seq != pos.Seq+1 {
+		t.Fatalf("journal at sequence %d after a rollback, want %d",
+			got.Seq, pos.Seq+1)
+	}
+}
+
+func TestJournalEntries(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+	l.mu.Lock()
+	db := l.db
+	l.mu.Unlock()
+
+	// Each change of a committed transaction is recorded, including the
+	// deletions of cursors.
+	pos := l.Journal().Position()
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("journaltest"))
+		if err != nil {
+			return err
+		}
+		nested, err := top.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		for _, k := range []string{"a", "b", "c"} {
+			err = nested.Put([]byte(k), []byte("value "+k))
+			if err != nil {
+				return err
+			}
+		}
+		err = nested.Delete([]byte("a"))
+		if err != nil {
+			return err
+		}
+		c := tx.ReadWriteBucket([]byte("journaltest")).
+			NestedReadWriteBucket([]byte("nested")).ReadWriteCursor()
+		c.Seek([]byte("c"))
+		return c.Delete()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := l.Journal().Wait(context.Background(), pos)
+	if len(s.Entries) != 1 || s.Entries[0].Seq != pos.Seq+1 {
+		t.Fatalf("journal entries %+v after a commit", s.Entries)
+	}
+	path := [][]byte{[]byte("journaltest"), []byte("nested")}
+	want := []JournalChange{
+		{Op: JournalCreateBucket, Key: []byte("journaltest")},
+		{Op: JournalCreateBucket, Bucket: path[:1], Key: []byte("nested")},
+		{Op: JournalPut, Bucket: path, Key: []byte("a"), Value: []byte("value a")},
+		{Op: JournalPut, Bucket: path, Key: []byte("b"), Value: []byte("value b")},
+		{Op: JournalPut, Bucket: path, Key: []byte("c"), Value: []byte("value c")},
+		{Op: JournalDelete, Bucket: path, Key: []byte("a")},
+		{Op: JournalDelete, Bucket: path, Key: []byte("c")},
+	}
+	changes := s.Entries[0].Changes
+	if len(changes) != len(want) {
+		t.Fatalf("recorded %d changes, want %d", len(changes), len(want))
+	}
+	for i := range want {
+		c, w := &changes[i], &want[i]
+		equal := c.Op == w.Op && len(c.Bucket) == len(w.Bucket) &&
+			bytes.Equal(c.Key, w.Key) && bytes.Equal(c.Value, w.Value)
+		for j := 0; equal && j < len(w.Bucket); j++ {
+			equal = bytes.Equal(c.Bucket[j], w.Bucket[j])
+		}
+		if !equal {
+			t.Errorf("change %d is %+v, want %+v", i, *c, *w)
+		}
+	}
+
+	// The changes apply to another database, and applying them again
+	// leaves it unchanged.
+	replica, teardownReplica := testLoader(t)
+	defer teardownReplica()
+	replica.mu.Lock()
+	replicaDB := replica.db
+	replica.mu.Unlock()
+	for i := 0; i < 2; i++ {
+		err = applyJournal(replicaDB, s.Entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = walletdb.View(replicaDB, func(tx walletdb.ReadTx) error {
+		var keys []string
+		err := tx.ReadBucket([]byte("journaltest")).NestedReadBucket([]byte("nested")).
+			ForEach(func(k, v []byte) error {
+				if string(v) != "value "+string(k) {
+					t.Errorf("applied value %q of key %q", v, k)
+				}
+				keys = append(keys, string(k))
+				return nil
+			})
+		if len(keys) != 1 || keys[0] != "b" {
+			t.Errorf("applied keys %q, want [b]", keys)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes of commits the journal no longer retains are not returned.
+	j := newJournal()
+	big := []JournalChange{{Op: JournalPut, Bucket: path, Key: []byte("k"),
+		Value: make([]byte, journalBytes/2+1)}}
+	j.record(big)
+	j.record(nil)
+	s = j.Wait(context.Background(), JournalPosition{ID: j.id, Seq: 1})
+	if len(s.Entries) != 1 || s.Entries[0].Seq != 2 {
+		t.Errorf("journal entries %+v after the second commit", s.Entries)
+	}
+	j.record(big)
+	s = j.Wait(context.Background(), JournalPosition{ID: j.id})
+	if s.Pending == 0 || len(s.Entries) != 0 {
+		t.Errorf("status %+v before discarded changes, want pending "+
+			"without entries", s)
+	}
+}
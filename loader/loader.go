@@ -6,6 +6,7 @@ package loader
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -15,8 +16,11 @@ import (
 	hcrpcclient "github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/ticketbuyer"
 	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
 	_ "github.com/HcashOrg/hcwallet/walletdb/bdb" // driver loaded during init
+	"github.com/HcashOrg/hcwallet/walletdb/edb"
 )
 
 const (
@@ -40,11 +44,25 @@ type Loader struct {
 	mu          sync.Mutex
 
 	purchaseManager *ticketbuyer.PurchaseManager
+	purchasePass    []byte
 	ntfnClient      wallet.MainTipChangedNotificationsClient
 	stakeOptions    *StakeOptions
 	addrIdxScanLen  int
+	gapLookahead    uint32
 	allowHighFees   bool
+	skipPreflight   bool
+	txLimits        txauthor.TxLimits
+	splitTxs        bool
 	relayFee        float64
+	encryptDB       bool
+	dbDriver        string
+	follower        *Follower
+	journal         *Journal
+
+	// replaceMu is held for reading while RPC requests use the loaded
+	// wallet, and for writing while ReplaceWallet replaces it, so
+	// replacements wait for in-flight requests to finish.
+	replaceMu sync.RWMutex
 
 	//omini
 	enableOmni bool
@@ -65,7 +83,8 @@ type StakeOptions struct {
 
 // NewLoader constructs a Loader.
 func NewLoader(chainParams *chaincfg.Params, dbDirPath string, stakeOptions *StakeOptions, addrIdxScanLen int,
-	allowHighFees bool, relayFee float64, enableOmni bool) *Loader {
+	allowHighFees bool, txLimits txauthor.TxLimits, splitTxs bool, relayFee float64,
+	enableOmni bool) *Loader {
 
 	return &Loader{
 		chainParams:    chainParams,
@@ -73,8 +92,12 @@ func NewLoader(chainParams *chaincfg.Params, dbDirPath string, stakeOptions *Sta
 		stakeOptions:   stakeOptions,
 		addrIdxScanLen: addrIdxScanLen,
 		allowHighFees:  allowHighFees,
+		txLimits:       txLimits,
+		splitTxs:       splitTxs,
 		relayFee:       relayFee,
 		enableOmni:     enableOmni,
+		dbDriver:       "bdb",
+		journal:        newJournal(),
 	}
 }
 
@@ -107,8 +130,9 @@ func (l *Loader) RunAfterLoad(fn func(*wallet.Wallet)) {
 
 // CreateNewWallet creates a new wallet using the provided public and private
 // passphrases.  The seed is optional.  If non-nil, addresses are derived from
-// this seed.  If nil, a secure random seed is generated.
-func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte) (w *wallet.Wallet, err error) {
+// this seed.  If nil, a secure random seed is generated.  The birthday is also
+// optional.  If non-nil, syncing the wallet skips blocks mined before it.
+func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte, birthday *udb.Birthday) (w *wallet.Wallet, err error) {
 	defer l.mu.Unlock()
 	l.mu.Lock()
 
@@ -155,12 +179,25 @@ func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte) (w
 	if err != nil {
 		return nil, err
 	}
-	db, err := walletdb.Create("bdb", dbPath)
+	db, err := walletdb.Create(l.dbDriver, dbPath)
 	if err != nil {
 		return nil, err
 	}
+	if l.encryptDB {
+		if string(pubPassphrase) == wallet.InsecurePubPassphrase {
+			db.Close()
+			return nil, ErrInsecureDBEncryption
+		}
+		encDB, err := edb.Create(db, pubPassphrase)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		db = encDB
+	}
 
 	// Initialize the newly created database for the wallet before opening.
+	db = &journalDB{DB: db, journal: l.journal}
 	err = wallet.Create(db, pubPassphrase, privPassphrase, seed, l.chainParams)
 	if err != nil {
 		return nil, err
@@ -171,10 +208,18 @@ func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte) (w
 	w, err = wallet.Open(db, pubPassphrase, privPassphrase, so.VotingEnabled, so.AddressReuse,
 		so.TicketAddress, so.SubsidyAddress, so.PoolAddress, so.PoolFees, so.TicketFee,
 		l.addrIdxScanLen, so.StakePoolColdExtKey, l.allowHighFees,
-		l.relayFee, l.enableOmni, l.chainParams)
+		l.txLimits, l.splitTxs, l.relayFee, l.enableOmni, l.chainParams)
 	if err != nil {
 		return nil, err
 	}
+	w.SetGapLimitLookahead(l.gapLookahead)
+	w.SetSkipPreflight(l.skipPreflight)
+	if birthday != nil {
+		err = w.SetBirthday(birthday)
+		if err != nil {
+			return nil, err
+		}
+	}
 	w.Start()
 
 	l.onLoaded(w, db)
@@ -193,9 +238,15 @@ func (l *Loader) OpenExistingWallet(pubPassphrase []byte, privPassphrase []byte)
 		return nil, ErrWalletLoaded
 	}
 
+	return l.openWallet(pubPassphrase, privPassphrase)
+}
+
+// openWallet opens the wallet from the loader's wallet database path.  See
+// OpenExistingWallet for details.  Requires mutex to be locked.
+func (l *Loader) openWallet(pubPassphrase []byte, privPassphrase []byte) (w *wallet.Wallet, rerr error) {
 	// Open the database using the boltdb backend.
 	dbPath := filepath.Join(l.dbDirPath, walletDbName)
-	db, err := walletdb.Open("bdb", dbPath)
+	db, err := walletdb.Open(l.dbDriver, dbPath)
 	if err != nil {
 		log.Errorf("Failed to open database: %v", err)
 		return nil, err
@@ -210,14 +261,38 @@ func (l *Loader) OpenExistingWallet(pubPassphrase []byte, privPassphrase []byte)
 		}
 	}()
 
+	// Decrypt the database with the public passphrase when it was created
+	// with encryption enabled.
+	encrypted, err := edb.IsEncrypted(db)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted {
+		encDB, err := edb.Open(db, pubPassphrase)
+		if err != nil {
+			log.Errorf("Failed to decrypt database: %v", err)
+			return nil, err
+		}
+		db = encDB
+	}
+	db = &journalDB{DB: db, journal: l.journal}
+
+	return l.loadWallet(db, pubPassphrase, privPassphrase)
+}
+
+// loadWallet opens and starts the wallet of an opened wallet database.  The
+// database is not closed on errors.  Requires mutex to be locked.
+func (l *Loader) loadWallet(db walletdb.DB, pubPassphrase []byte, privPassphrase []byte) (*wallet.Wallet, error) {
 	so := l.stakeOptions
-	w, err = wallet.Open(db, pubPassphrase, privPassphrase, so.VotingEnabled, so.AddressReuse,
+	w, err := wallet.Open(db, pubPassphrase, privPassphrase, so.VotingEnabled, so.AddressReuse,
 		so.TicketAddress, so.SubsidyAddress, so.PoolAddress, so.PoolFees, so.TicketFee,
 		l.addrIdxScanLen, so.StakePoolColdExtKey, l.allowHighFees,
-		l.relayFee, l.enableOmni, l.chainParams)
+		l.txLimits, l.splitTxs, l.relayFee, l.enableOmni, l.chainParams)
 	if err != nil {
 		return nil, err
 	}
+	w.SetGapLimitLookahead(l.gapLookahead)
+	w.SetSkipPreflight(l.skipPreflight)
 
 	w.Start()
 	l.onLoaded(w, db)
@@ -253,6 +328,12 @@ func (l *Loader) UnloadWallet() error {
 		return ErrWalletNotLoaded
 	}
 
+	return l.unloadWallet()
+}
+
+// unloadWallet stops the loaded wallet and closes the wallet database.
+// Requires mutex to be locked and a wallet to be loaded.
+func (l *Loader) unloadWallet() error {
 	// Ignore err already stopped.
 	l.stopTicketPurchase()
 
@@ -268,6 +349,90 @@ func (l *Loader) UnloadWallet() error {
 	return nil
 }
 
+// ReplaceWallet moves the wallet database file at path to the loader's wallet
+// database path and opens it with the passphrases, first unloading the loaded
+// wallet, if any.  The wallet is not replaced until all holds of HoldWallet are
+// released, and new holds wait for the replacement to finish.
+func (l *Loader) ReplaceWallet(path string, pubPassphrase, privPassphrase []byte) (*wallet.Wallet, error) {
+	defer l.replaceMu.Unlock()
+	l.replaceMu.Lock()
+	defer l.mu.Unlock()
+	l.mu.Lock()
+
+	if l.wallet != nil {
+		err := l.unloadWallet()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := os.Rename(path, filepath.Join(l.dbDirPath, walletDbName))
+	if err != nil {
+		return nil, err
+	}
+	return l.openWallet(pubPassphrase, privPassphrase)
+}
+
+// ApplyJournal commits the changes of journal entries of a primary wallet to
+// the loaded wallet's database and reopens the wallet with the passphrases, so
+// the wallet's state reflects the changes.  No changes are committed when any
+// of them fails to apply.  Like ReplaceWallet, the changes are not applied
+// until all holds of HoldWallet are released.  The wallet is left unloaded
+// when it fails to reopen.
+func (l *Loader) ApplyJournal(entries []JournalEntry, pubPassphrase, privPassphrase []byte) (*wallet.Wallet, error) {
+	defer l.replaceMu.Unlock()
+	l.replaceMu.Lock()
+	defer l.mu.Unlock()
+	l.mu.Lock()
+
+	if l.wallet == nil {
+		return nil, ErrWalletNotLoaded
+	}
+
+	// Ignore err already stopped.
+	l.stopTicketPurchase()
+
+	l.wallet.Stop()
+	l.wallet.WaitForShutdown()
+	db := l.db
+	l.wallet = nil
+	l.db = nil
+
+	applyErr := applyJournal(db, entries)
+	w, err := l.loadWallet(db, pubPassphrase, privPassphrase)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return w, applyErr
+}
+
+// HoldWallet prevents the loaded wallet from being replaced by ReplaceWallet
+// until the returned function is called.  Holds must not be nested, as a
+// waiting replacement blocks new holds.
+func (l *Loader) HoldWallet() (release func()) {
+	l.replaceMu.RLock()
+	return l.replaceMu.RUnlock
+}
+
+// Journal returns the journal recording the commits to the databases of the
+// loader's wallets.
+func (l *Loader) Journal() *Journal {
+	return l.journal
+}
+
+// CopyDatabase writes a consistent copy of the loaded wallet's database to w.
+// Encrypted databases are copied without being decrypted.
+func (l *Loader) CopyDatabase(w io.Writer) error {
+	l.mu.Lock()
+	db := l.db
+	l.mu.Unlock()
+	if db == nil {
+		return ErrWalletNotLoaded
+	}
+	return db.Copy(w)
+}
+
 // SetChainClient sets the chain server client.
 func (l *Loader) SetChainClient(chainClient *hcrpcclient.Client) {
 	l.mu.Lock()
@@ -275,11 +440,82 @@ func (l *Loader) SetChainClient(chainClient *hcrpcclient.Client) {
 	l.mu.Unlock()
 }
 
+// ReplaceChainClient sets the chain server client while the wallet runs.  The
+// ticket purchaser queries the chain server, so a running purchaser is stopped
+// and, unless chainClient is nil, restarted with its current configuration to
+// query the new client.  If the purchaser can not be restarted, ticket
+// purchasing remains stopped and the error is returned.
+func (l *Loader) ReplaceChainClient(chainClient *hcrpcclient.Client) error {
+	defer l.mu.Unlock()
+	l.mu.Lock()
+
+	l.chainClient = chainClient
+	if l.purchaseManager == nil {
+		return nil
+	}
+
+	cfg, err := l.purchaseManager.Purchaser().Config()
+	passphrase := l.purchasePass
+	l.stopTicketPurchase()
+	if err != nil || chainClient == nil {
+		return err
+	}
+	return l.startTicketPurchase(passphrase, cfg)
+}
+
+// SetDatabaseEncryption sets whether wallets created by the loader encrypt
+// their database with the public passphrase.  Existing wallets are opened
+// according to how they were created.
+func (l *Loader) SetDatabaseEncryption(encrypt bool) {
+	l.mu.Lock()
+	l.encryptDB = encrypt
+	l.mu.Unlock()
+}
+
+// DatabaseDriver returns the driver of the loader's wallet database.
+func (l *Loader) DatabaseDriver() string {
+	l.mu.Lock()
+	driver := l.dbDriver
+	l.mu.Unlock()
+	return driver
+}
+
+// SetGapLimitLookahead sets the number of addresses of each account branch
+// watched for transactions beyond the gap limit by wallets opened by the
+// loader.
+func (l *Loader) SetGapLimitLookahead(n uint32) {
+	l.mu.Lock()
+	l.gapLookahead = n
+	l.mu.Unlock()
+}
+
+// SetSkipPreflight sets whether wallets opened by the loader send transactions
+// without first validating their scripts.
+func (l *Loader) SetSkipPreflight(skip bool) {
+	l.mu.Lock()
+	l.skipPreflight = skip
+	l.mu.Unlock()
+}
+
+// SetDatabaseDriver sets the walletdb driver used to create and open the wallet
+// database.  The default driver is bdb.
+func (l *Loader) SetDatabaseDriver(driver string) {
+	l.mu.Lock()
+	l.dbDriver = driver
+	l.mu.Unlock()
+}
+
 // StartTicketPurchase launches the ticketbuyer to start purchasing tickets.
 func (l *Loader) StartTicketPurchase(passphrase []byte, ticketbuyerCfg *ticketbuyer.Config) error {
 	defer l.mu.Unlock()
 	l.mu.Lock()
 
+	return l.startTicketPurchase(passphrase, ticketbuyerCfg)
+}
+
+// startTicketPurchase starts the ticket purchaser.  It must be called with the
+// mutex lock held.
+func (l *Loader) startTicketPurchase(passphrase []byte, ticketbuyerCfg *ticketbuyer.Config) error {
 	// Already running?
 	if l.purchaseManager != nil {
 		return ErrTicketBuyerStarted
@@ -302,6 +538,7 @@ func (l *Loader) StartTicketPurchase(passphrase []byte, ticketbuyerCfg *ticketbu
 	pm := ticketbuyer.NewPurchaseManager(w, p, n.C, passphrase)
 	l.ntfnClient = n
 	l.purchaseManager = pm
+	l.purchasePass = passphrase
 	pm.Start()
 	l.wallet.SetTicketPurchasingEnabled(true)
 	return nil
@@ -318,6 +555,7 @@ func (l *Loader) stopTicketPurchase() error {
 	l.purchaseManager.Stop()
 	l.purchaseManager.WaitForShutdown()
 	l.purchaseManager = nil
+	l.purchasePass = nil
 	l.wallet.SetTicketPurchasingEnabled(false)
 	return nil
 }
@@ -0,0 +1,163 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcrpcclient"
+	"github.com/HcashOrg/hcwallet/ticketbuyer"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	_ "github.com/HcashOrg/hcwallet/walletdb/bdb"
+)
+
+var (
+	testPubPass  = []byte("public")
+	testPrivPass = []byte("private")
+	testSeed     = []byte{
+		0xb4, 0x6b, 0xc6, 0x50, 0x2a, 0x30, 0xbe, 0xb9, 0x2f, 0x0a,
+		0xeb, 0xc7, 0x76, 0x40, 0x3c, 0x3d, 0xbf, 0x11, 0xbf, 0xb6,
+		0x83, 0x05, 0x96, 0x7c, 0x36, 0xda, 0xc9, 0xef, 0x8d, 0x64,
+		0x15, 0x67,
+	}
+)
+
+// testLoader returns a loader with a simnet wallet created in a temporary
+// directory.  The returned function unloads the wallet and removes its files.
+func testLoader(t *testing.T) (*Loader, func()) {
+	dir, err := ioutil.TempDir("", "loadertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLoader(&chaincfg.SimNetParams, dir, &StakeOptions{}, 0, false,
+		txauthor.TxLimits{}, false, 0.001, false)
+	_, err = l.CreateNewWallet(testPubPass, testPrivPass, testSeed, nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return l, func() {
+		l.UnloadWallet()
+		os.RemoveAll(dir)
+	}
+}
+
+// testChainClient returns a chain server client which does not connect until
+// a request is made.
+func testChainClient(t *testing.T) *hcrpcclient.Client {
+	c, err := hcrpcclient.New(&hcrpcclient.ConnConfig{
+		Host:         "127.0.0.1:0",
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestReplaceChainClient(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+
+	// Replacing the client without a running ticket buyer only changes the
+	// client.
+	err := l.ReplaceChainClient(testChainClient(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.PurchaseManager() != nil {
+		t.Fatal("ticket buyer was started by replacing the client")
+	}
+
+	cfg := &ticketbuyer.Config{
+		AccountName: "default",
+		MaxPerBlock: 3,
+	}
+	err = l.StartTicketPurchase(testPrivPass, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm := l.PurchaseManager()
+
+	// Attaching a new client restarts the ticket buyer with its
+	// configuration.
+	err = l.ReplaceChainClient(testChainClient(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted := l.PurchaseManager()
+	if restarted == nil || restarted == pm {
+		t.Fatal("ticket buyer was not restarted with the new client")
+	}
+	restartedCfg, err := restarted.Purchaser().Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restartedCfg.AccountName != cfg.AccountName ||
+		restartedCfg.MaxPerBlock != cfg.MaxPerBlock {
+		t.Errorf("restarted ticket buyer has configuration %+v, want %+v",
+			restartedCfg, cfg)
+	}
+	if w, _ := l.LoadedWallet(); !w.TicketPurchasingEnabled() {
+		t.Error("ticket purchasing is disabled after restarting the buyer")
+	}
+
+	// Detaching the client stops the ticket buyer.
+	err = l.ReplaceChainClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.PurchaseManager() != nil {
+		t.Error("ticket buyer is running without a client")
+	}
+	if err := l.StopTicketPurchase(); err != ErrTicketBuyerStopped {
+		t.Errorf("stopping the detached ticket buyer returned %v, want %v",
+			err, ErrTicketBuyerStopped)
+	}
+}
+
+func TestChangePublicPassphraseEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loadertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	l := NewLoader(&chaincfg.SimNetParams, dir, &StakeOptions{}, 0, false,
+		txauthor.TxLimits{}, false, 0.001, false)
+	l.SetDatabaseEncryption(true)
+	// Encryption requires a public passphrase other than the default.
+	pubPass := []byte("oldpublic")
+	w, err := l.CreateNewWallet(pubPass, testPrivPass, testSeed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The database is accessed through the loader's journal, which must be
+	// unwrapped to reencrypt the master key.
+	newPubPass := []byte("newpublic")
+	err = w.ChangePublicPassphrase(pubPass, newPubPass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.UnloadWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = l.OpenExistingWallet(pubPass, testPrivPass)
+	if err == nil {
+		l.UnloadWallet()
+		t.Fatal("wallet opened with the old public passphrase")
+	}
+	_, err = l.OpenExistingWallet(newPubPass, testPrivPass)
+	if err != nil {
+		t.Fatalf("wallet did not open with the new public passphrase: %v", err)
+	}
+	l.UnloadWallet()
+}
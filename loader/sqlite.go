@@ -0,0 +1,12 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build sqlite
+// +build sqlite
+
+package loader
+
+import (
+	_ "github.com/HcashOrg/hcwallet/walletdb/sqlite" // driver loaded during init
+)
@@ -7,14 +7,19 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/btcsuite/btclog"
 	hcrpcclient "github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/chain"
+	"github.com/HcashOrg/hcwallet/eventbus"
 	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/omnilib"
 	"github.com/HcashOrg/hcwallet/rpc/legacyrpc"
 	"github.com/HcashOrg/hcwallet/rpc/rpcserver"
 	"github.com/HcashOrg/hcwallet/ticketbuyer"
@@ -24,15 +29,59 @@ import (
 )
 
 // logWriter implements an io.Writer that outputs to both standard output and
-// the write-end pipe of an initialized log rotator.
+// the write-end pipe of an initialized log rotator.  Lines are reformatted as
+// JSON objects when JSON log output is enabled.
 type logWriter struct{}
 
 func (logWriter) Write(p []byte) (n int, err error) {
-	os.Stdout.Write(p)
-	logRotator.Write(p)
+	logMtx.Lock()
+	defer logMtx.Unlock()
+
+	out := p
+	if logJSON {
+		out = jsonLogLine(p)
+	}
+	os.Stdout.Write(out)
+	logRotator.Write(out)
 	return len(p), nil
 }
 
+// jsonLogEntry is a log line of JSON log output.
+type jsonLogEntry struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	Message   string `json:"message"`
+}
+
+// jsonLogLine reformats a line written by the logging backend, which begins
+// with a "2006-01-02 15:04:05.000 [LVL] SUBS: " header, as a JSON object.
+// Lines without the header are logged with only the message field.
+func jsonLogLine(p []byte) []byte {
+	const timeLen = len("2006-01-02 15:04:05.000")
+	var e jsonLogEntry
+	line := bytes.TrimSuffix(p, []byte{'\n'})
+	rest := line
+	if len(rest) > timeLen+len(" [LVL] ") && rest[timeLen+1] == '[' {
+		e.Time = string(rest[:timeLen])
+		rest = rest[timeLen+2:]
+		if end := bytes.IndexByte(rest, ']'); end != -1 {
+			e.Level = string(rest[:end])
+			rest = bytes.TrimPrefix(rest[end+1:], []byte{' '})
+		}
+		if end := bytes.Index(rest, []byte(": ")); end != -1 {
+			e.Subsystem = string(rest[:end])
+			rest = rest[end+2:]
+		}
+	}
+	e.Message = string(rest)
+	out, err := json.Marshal(&e)
+	if err != nil {
+		return p
+	}
+	return append(out, '\n')
+}
+
 // Loggers per subsystem.  A single backend logger is created and all subsytem
 // loggers created from it will write to the backend.  When adding new
 // subsystems, add the subsystem logger variable here and to the
@@ -51,6 +100,16 @@ var (
 	// application shutdown.
 	logRotator *rotator.Rotator
 
+	// logFile is the file written by the log rotator.
+	logFile string
+
+	// logJSON enables formatting log lines as JSON objects.
+	logJSON bool
+
+	// logMtx protects the log rotator and output format, which may be
+	// changed by RPC clients while loggers are writing.
+	logMtx sync.Mutex
+
 	log          = backendLog.Logger("HCW")
 	loaderLog    = backendLog.Logger("LODR")
 	walletLog    = backendLog.Logger("WLLT")
@@ -58,6 +117,8 @@ var (
 	chainLog     = backendLog.Logger("CHNS")
 	grpcLog      = backendLog.Logger("GRPC")
 	legacyRPCLog = backendLog.Logger("RPCS")
+	omniLog      = backendLog.Logger("OMNI")
+	evbsLog      = backendLog.Logger("EVBS")
 )
 
 // Initialize package-global logger variables.
@@ -70,6 +131,8 @@ func init() {
 	hcrpcclient.UseLogger(chainLog)
 	rpcserver.UseLogger(grpcLog)
 	legacyrpc.UseLogger(legacyRPCLog)
+	omnilib.UseLogger(omniLog)
+	eventbus.UseLogger(evbsLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -81,25 +144,48 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"CHNS": chainLog,
 	"GRPC": grpcLog,
 	"RPCS": legacyRPCLog,
+	"OMNI": omniLog,
+	"EVBS": evbsLog,
 }
 
-// initLogRotator initializes the logging rotater to write logs to logFile and
-// create roll files in the same directory.  It must be called before the
-// package-global log rotater variables are used.
-func initLogRotator(logFile string) {
-	logDir, _ := filepath.Split(logFile)
+// initLogRotator initializes the logging rotater to write logs to file and
+// create roll files in the same directory.  Files are rolled after reaching
+// maxSizeKB kilobytes, keeping at most maxRolls roll files.  It must be called
+// before the package-global log rotater variables are used.
+func initLogRotator(file string, maxSizeKB int64, maxRolls int) {
+	logDir, _ := filepath.Split(file)
 	err := os.MkdirAll(logDir, 0700)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create log directory: %v\n", err)
 		os.Exit(1)
 	}
-	r, err := rotator.New(logFile, 10*1024, false, 3)
+	r, err := rotator.New(file, maxSizeKB, false, maxRolls)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create file rotator: %v\n", err)
 		os.Exit(1)
 	}
 
 	logRotator = r
+	logFile = file
+}
+
+// setLogRotation replaces the log rotator with one rolling the log file after
+// reaching maxSizeKB kilobytes and keeping at most maxRolls roll files.
+func setLogRotation(maxSizeKB int64, maxRolls int) error {
+	if maxSizeKB <= 0 || maxRolls <= 0 {
+		return fmt.Errorf("log rotation size and number of rolls must be positive")
+	}
+
+	logMtx.Lock()
+	defer logMtx.Unlock()
+
+	r, err := rotator.New(logFile, maxSizeKB, false, maxRolls)
+	if err != nil {
+		return err
+	}
+	logRotator.Close()
+	logRotator = r
+	return nil
 }
 
 // setLogLevel sets the logging level for provided subsystem.  Invalid
@@ -128,6 +214,31 @@ func setLogLevels(logLevel string) {
 	}
 }
 
+// logLevels returns the current logging level of each subsystem.
+func logLevels() map[string]string {
+	levels := make(map[string]string, len(subsystemLoggers))
+	for subsystemID, logger := range subsystemLoggers {
+		levels[subsystemID] = logger.Level().String()
+	}
+	return levels
+}
+
+// logControl implements the legacyrpc.LogControl interface to change the
+// logging configuration of the process over RPC.
+type logControl struct{}
+
+func (logControl) SetLogLevels(levelSpec string) (map[string]string, error) {
+	err := parseAndSetDebugLevels(levelSpec)
+	if err != nil {
+		return nil, err
+	}
+	return logLevels(), nil
+}
+
+func (logControl) SetLogRotation(maxSizeKB int64, maxRolls int) error {
+	return setLogRotation(maxSizeKB, maxRolls)
+}
+
 // fatalf logs a message, flushes the logger, and finally exit the process with
 // a non-zero return code.
 func fatalf(format string, args ...interface{}) {
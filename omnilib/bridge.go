@@ -0,0 +1,181 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package omnilib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/HcashOrg/hcd/hcjson"
+)
+
+const (
+	// bridgeAttempts is the number of times a read-only request is handed
+	// to the omni engine before a missing or malformed response is reported
+	// as a failure.
+	bridgeAttempts = 3
+
+	// bridgeRetryDelay is multiplied by the attempt number to determine how
+	// long to wait before retrying a request.
+	bridgeRetryDelay = 100 * time.Millisecond
+)
+
+// bridgeFailures counts the requests which could not be completed because the
+// omni engine did not return a usable response.  It must be accessed
+// atomically.
+var bridgeFailures uint64
+
+// BridgeFailures returns the number of omni engine requests that have failed
+// since the process started.
+func BridgeFailures() uint64 {
+	return atomic.LoadUint64(&bridgeFailures)
+}
+
+// probing is set while a Probe is waiting for the omni engine.  It must be
+// accessed atomically.
+var probing uint32
+
+// Probe checks that the omni engine answers a request within timeout.  Only one
+// probe waits for the engine at a time, so an engine which hangs does not
+// accumulate blocked requests; while an earlier probe is still waiting, the
+// engine is reported as unresponsive without sending another request.
+func Probe(timeout time.Duration) error {
+	if !atomic.CompareAndSwapUint32(&probing, 0, 1) {
+		return errors.New("omni engine has not answered an earlier probe")
+	}
+	c := make(chan error, 1)
+	go func() {
+		_, err := SendCmd(hcjson.NewOmniGetinfoCmd())
+		atomic.StoreUint32(&probing, 0)
+		c <- err
+	}()
+	select {
+	case err := <-c:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("omni engine did not answer within %v", timeout)
+	}
+}
+
+// BridgeError describes a request which did not receive a usable response
+// from the omni engine.  Errors reported by the engine itself are returned as
+// *hcjson.RPCError instead.
+type BridgeError struct {
+	Method string
+	Err    error
+}
+
+// Error satisfies the error interface.
+func (e *BridgeError) Error() string {
+	return fmt.Sprintf("omni bridge: %s: %v", e.Method, e.Err)
+}
+
+// IsBridgeError returns whether err is a *BridgeError.
+func IsBridgeError(err error) bool {
+	_, ok := err.(*BridgeError)
+	return ok
+}
+
+// SendCmd marshals a registered hcjson command, sends it to the omni engine
+// and returns the result.
+func SendCmd(cmd interface{}) (json.RawMessage, error) {
+	method, err := hcjson.CmdMethod(cmd)
+	if err != nil {
+		return nil, err
+	}
+	marshalled, err := hcjson.MarshalCmd(1, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return send(method, marshalled)
+}
+
+// Send sends req to the omni engine and returns the result.
+func Send(req *Request) (json.RawMessage, error) {
+	marshalled, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return send(req.Method, marshalled)
+}
+
+// readOnlyMethodPrefixes and readOnlyMethods describe the omni engine methods
+// which only read the engine's state or build payloads and transactions without
+// recording them, so they can be sent again after a failed attempt.
+var (
+	readOnlyMethodPrefixes = []string{
+		"omni_get",
+		"omni_list",
+		"omni_createpayload_",
+		"omni_createrawtx_",
+	}
+	readOnlyMethods = map[string]bool{
+		"omni_decodetransaction": true,
+		"omni_estimatefee":       true,
+		"omni_readalltxhash":     true,
+	}
+)
+
+// retryable returns whether a request for method may be retried.  Any other
+// request may have been applied by the engine even though no usable response
+// was returned, and retrying it could send a transaction or process a block
+// twice.
+func retryable(method string) bool {
+	if readOnlyMethods[method] {
+		return true
+	}
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// send hands a marshalled request to the omni engine.  Read-only requests which
+// receive no response or a response that can not be decoded are retried, while
+// other requests are only attempted once.  Errors returned by the engine are
+// passed through to the caller.
+func send(method string, marshalled []byte) (json.RawMessage, error) {
+	attempts := 1
+	if retryable(method) {
+		attempts = bridgeAttempts
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			log.Debugf("Retrying omni request %s (attempt %d/%d)",
+				method, attempt, attempts)
+			time.Sleep(bridgeRetryDelay * time.Duration(attempt-1))
+		}
+
+		strRsp := JsonCmdReqHcToOm(string(marshalled))
+		if strRsp == "" {
+			err = fmt.Errorf("empty response")
+			continue
+		}
+		var response hcjson.Response
+		err = json.Unmarshal([]byte(strRsp), &response)
+		if err != nil {
+			err = fmt.Errorf("malformed response: %v", err)
+			continue
+		}
+		if response.Error != nil {
+			log.Debugf("Omni request %s returned error: %v", method,
+				response.Error)
+			return nil, response.Error
+		}
+		return response.Result, nil
+	}
+
+	failures := atomic.AddUint64(&bridgeFailures, 1)
+	log.Errorf("Omni request %s failed after %d attempts (%d failures "+
+		"total): %v", method, attempts, failures, err)
+	return nil, &BridgeError{Method: method, Err: err}
+}
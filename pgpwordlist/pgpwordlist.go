@@ -41,16 +41,26 @@ func DecodeMnemonics(words []string) ([]byte, error) {
 		if w == "" {
 			continue
 		}
-		b, ok := wordIndexes[strings.ToLower(w)]
-		if !ok {
-			return nil, fmt.Errorf("word %v is not in the PGP word list", w)
+		b, err := MnemonicToByte(w, idx)
+		if err != nil {
+			return nil, err
 		}
-		if int(b%2) != idx%2 {
-			return nil, fmt.Errorf("word %v is not valid at position %v, "+
-				"check for missing words", w, idx)
-		}
-		decoded[idx] = byte(b / 2)
+		decoded[idx] = b
 		idx++
 	}
 	return decoded[:idx], nil
 }
+
+// MnemonicToByte returns the byte encoded by the PGP word w when found at
+// index.  It is the inverse of ByteToMnemonic.
+func MnemonicToByte(w string, index int) (byte, error) {
+	b, ok := wordIndexes[strings.ToLower(strings.TrimSpace(w))]
+	if !ok {
+		return 0, fmt.Errorf("word %v is not in the PGP word list", w)
+	}
+	if int(b%2) != index%2 {
+		return 0, fmt.Errorf("word %v is not valid at position %v, "+
+			"check for missing words", w, index)
+	}
+	return byte(b / 2), nil
+}
@@ -58,3 +58,21 @@ func TestEncodeMnemonics(t *testing.T) {
 		}
 	}
 }
+
+func TestMnemonicToByte(t *testing.T) {
+	for _, test := range tests {
+		mnemonicsSlice := strings.Split(test.mnemonics, " ")
+		for i, w := range mnemonicsSlice {
+			b, err := MnemonicToByte(w, i)
+			assert.NoError(t, err)
+			assert.Equal(t, test.data[i], b)
+
+			// Words of the even and odd lists are never valid at
+			// the other position parity.
+			_, err = MnemonicToByte(w, i+1)
+			assert.Error(t, err)
+		}
+	}
+	_, err := MnemonicToByte("notaword", 0)
+	assert.Error(t, err)
+}
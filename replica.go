@@ -0,0 +1,117 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+
+	ldr "github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/rpc/legacyrpc"
+)
+
+// validateReplicaConfig checks the options of a replica wallet, normalizing
+// the URL of the primary.
+func validateReplicaConfig(cfg *config) error {
+	u, err := url.Parse(cfg.Replicate)
+	if err != nil {
+		return fmt.Errorf("invalid replicate URL: %v", err)
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return errors.New("the replicate URL may only use plain " +
+				"HTTP for primaries on localhost")
+		}
+	default:
+		return fmt.Errorf("replicate URL scheme %q is not http or https",
+			u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("replicate URL has no host")
+	}
+	cfg.Replicate = strings.TrimSuffix(u.Scheme+"://"+u.Host+u.Path, "/")
+
+	switch {
+	case cfg.Username == "" || cfg.Password == "":
+		return errors.New("replicate requires the username and password " +
+			"of the primary wallet")
+	case cfg.ReplicaInterval <= 0:
+		return errors.New("replicainterval must be positive")
+	case cfg.NoInitialLoad:
+		return errors.New("replicate may not be used with noinitialload")
+	case len(cfg.Wallets) != 0:
+		return errors.New("replicate may not be used with wallet")
+	case cfg.EnableOmni:
+		return errors.New("replicate may not be used with enableomni")
+	case cfg.EnableTicketBuyer:
+		return errors.New("replicate may not be used with enableticketbuyer")
+	}
+	return nil
+}
+
+// replicaTLSConfig returns the TLS configuration to authenticate the primary
+// wallet with the root certificates of the replica CA file, or the system
+// roots if unset.
+func replicaTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.ReplicaCAFile == "" {
+		return tlsConfig, nil
+	}
+	certs, err := ioutil.ReadFile(cfg.ReplicaCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certs) {
+		return nil, fmt.Errorf("no certificates found in %s",
+			cfg.ReplicaCAFile)
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// startReplica starts replicating the primary wallet into the database of
+// the loader.  Replication stops when the returned function is called, which
+// blocks until any replaced wallet is opened.  When the replica is promoted,
+// the legacy RPC server serves methods modifying the wallet, and the wallet is
+// synced with hcd.
+func startReplica(loader *ldr.Loader, pubPassphrase, privPassphrase []byte,
+	legacyRPCServer *legacyrpc.Server) (stop func(), err error) {
+	tlsConfig, err := replicaTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	follower := ldr.NewFollower(loader, cfg.Replicate, cfg.Username,
+		cfg.Password, tlsConfig, cfg.ReplicaInterval, pubPassphrase,
+		privPassphrase)
+	follower.RunAfterPromote(func() {
+		if legacyRPCServer != nil {
+			legacyRPCServer.SetReadOnly(false)
+		}
+		go rpcClientConnectLoop(nil, legacyRPCServer, loader)
+	})
+
+	log.Infof("Replicating wallet from %s at most every %v", cfg.Replicate,
+		cfg.ReplicaInterval)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		follower.Run(quit)
+		close(done)
+	}()
+	return func() {
+		close(quit)
+		<-done
+	}, nil
+}
@@ -4,6 +4,11 @@
 
 package legacyrpc
 
+import (
+	"crypto/x509"
+	"time"
+)
+
 // Options contains the required options for running the legacy RPC server.
 type Options struct {
 	Username string
@@ -11,4 +16,53 @@ type Options struct {
 
 	MaxPOSTClients      int64
 	MaxWebsocketClients int64
+
+	// MaxClientRequests limits the number of requests each websocket
+	// client may have outstanding.  Requests over the limit are answered
+	// with an error without being handled.  Zero disables the limit.
+	MaxClientRequests int
+
+	// IdleTimeout disconnects websocket clients which make no requests
+	// for the duration while none of their requests are outstanding.
+	// Zero disables the timeout.
+	IdleTimeout time.Duration
+
+	// ReadOnly rejects requests for methods which modify the wallet, for
+	// serving replica wallets.
+	ReadOnly bool
+
+	// ServeSnapshots enables serving database snapshots of the default
+	// wallet to replica followers.
+	ServeSnapshots bool
+
+	// ClientAuth authorizes the TLS client certificates of clients.  When
+	// set, clients must present a verified certificate in addition to the
+	// HTTP basic authentication.
+	ClientAuth ClientAuthorizer
+
+	// LogControl changes the logging configuration of the process for the
+	// setloglevel and setlogrotation methods.  These methods are
+	// unavailable if nil.
+	LogControl LogControl
+}
+
+// ClientAuthorizer maps the TLS client certificates of authenticated clients
+// to permissions.
+type ClientAuthorizer interface {
+	// AuthorizeClient returns whether the client presenting the verified
+	// certificate is restricted to the read-only methods, or an error if
+	// the certificate is not permitted to make requests.
+	AuthorizeClient(cert *x509.Certificate) (readOnly bool, err error)
+}
+
+// LogControl changes the logging configuration of the process.
+type LogControl interface {
+	// SetLogLevels sets subsystem logging levels from a level
+	// specification, in the format of the debuglevel option, and returns
+	// the resulting level of each subsystem.
+	SetLogLevels(levelSpec string) (map[string]string, error)
+
+	// SetLogRotation sets the size in kilobytes after which the log file
+	// is rolled and the maximum number of rolled files to keep.
+	SetLogRotation(maxSizeKB int64, maxRolls int) error
 }
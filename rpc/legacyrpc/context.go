@@ -5,7 +5,11 @@
 
 package legacyrpc
 
-import "context"
+import (
+	"context"
+
+	"github.com/HcashOrg/hcwallet/loader"
+)
 
 type contextKey string
 
@@ -20,3 +24,55 @@ func remoteAddr(ctx context.Context) string {
 	}
 	return v.(string)
 }
+
+func withWalletLoader(parent context.Context, l *loader.Loader) context.Context {
+	return context.WithValue(parent, contextKey("wallet-loader"), l)
+}
+
+// walletLoader returns the loader of the wallet named by the request, or nil
+// when the request did not name a wallet and is served by the default wallet.
+func walletLoader(ctx context.Context) *loader.Loader {
+	v := ctx.Value(contextKey("wallet-loader"))
+	if v == nil {
+		return nil
+	}
+	return v.(*loader.Loader)
+}
+
+func withReadOnlyClient(parent context.Context) context.Context {
+	return context.WithValue(parent, contextKey("read-only-client"), true)
+}
+
+// readOnlyClient returns whether the client certificate of the request is
+// restricted to the read-only methods.
+func readOnlyClient(ctx context.Context) bool {
+	return ctx.Value(contextKey("read-only-client")) != nil
+}
+
+// readOnlyClientMethods are the methods served to clients authenticated by a
+// read-only certificate.  These are the methods of read-only replicas, except
+// for promoting the replica.
+var readOnlyClientMethods = func() map[string]bool {
+	methods := make(map[string]bool, len(readOnlyMethods))
+	for method := range readOnlyMethods {
+		if method == "promotereplica" {
+			continue
+		}
+		methods[method] = true
+	}
+	return methods
+}()
+
+func withResultVersion(parent context.Context, version int) context.Context {
+	return context.WithValue(parent, contextKey("result-version"), version)
+}
+
+// resultVersion returns the result version requested by the client, or the
+// legacy result version if none was requested.
+func resultVersion(ctx context.Context) int {
+	v := ctx.Value(contextKey("result-version"))
+	if v == nil {
+		return resultVersionLegacy
+	}
+	return v.(int)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+)
+
+func TestReadOnlyClientMethods(t *testing.T) {
+	dir, err := ioutil.TempDir("", "legacyrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &Server{
+		walletLoader: loader.NewLoader(&chaincfg.SimNetParams, dir, nil, 0,
+			false, txauthor.TxLimits{}, false, 0, false),
+	}
+	ctx := withReadOnlyClient(context.Background())
+
+	tests := []struct {
+		method string
+		params string
+	}{
+		{"exportaccount", `["default", true]`},
+		{"exportaccount", `["default"]`},
+		{"setloglevel", `["debug"]`},
+		{"setlogrotation", `[]`},
+		{"promotereplica", `[]`},
+		{"sendtoaddress", `["Ssaddr", 1]`},
+	}
+	for _, test := range tests {
+		req := &hcjson.Request{
+			Jsonrpc: "1.0",
+			Method:  test.method,
+			Params:  []json.RawMessage{json.RawMessage(test.params)},
+		}
+		_, jsonErr := s.handlerClosure(ctx, req)()
+		if jsonErr == nil || *jsonErr != ErrReadOnlyClient {
+			t.Errorf("%s %s: expected read-only client error, got %v",
+				test.method, test.params, jsonErr)
+		}
+	}
+
+	// Replicas hold the private passphrase of their primary, so methods
+	// revealing private keys or changing the logging of the process are not
+	// served by the replicas themselves either.
+	for _, method := range []string{"exportaccount", "setloglevel", "setlogrotation"} {
+		if readOnlyMethods[method] {
+			t.Errorf("%s is served by read-only replicas", method)
+		}
+	}
+	if !readOnlyClientMethods["getbalance"] {
+		t.Errorf("getbalance is not served to read-only clients")
+	}
+}
@@ -96,4 +96,29 @@ var (
 		Code:    hcjson.ErrRPCWallet,
 		Message: "RPC function disabled on MainNet wallets for security purposes",
 	}
+
+	ErrReadOnlyReplica = hcjson.RPCError{
+		Code:    hcjson.ErrRPCWallet,
+		Message: "Method unavailable on read-only replica wallets",
+	}
+
+	ErrNotReplica = hcjson.RPCError{
+		Code:    hcjson.ErrRPCWallet,
+		Message: "Wallet is not a replica",
+	}
+
+	ErrReadOnlyClient = hcjson.RPCError{
+		Code:    hcjson.ErrRPCWallet,
+		Message: "Method unavailable to read-only clients",
+	}
+
+	ErrWebsocketOnly = hcjson.RPCError{
+		Code:    hcjson.ErrRPCInvalidRequest.Code,
+		Message: "Method only available to websocket clients",
+	}
+
+	ErrTooManyRequests = hcjson.RPCError{
+		Code:    hcjson.ErrRPCMisc,
+		Message: "Too many outstanding requests from client",
+	}
 )
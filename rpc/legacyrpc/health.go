@@ -0,0 +1,77 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+)
+
+// CheckHealth checks the health of the wallet loaded by l and the services it
+// depends on.  The wallet is ready to serve requests when it is loaded, not
+// unavailable, and at most maxBlocksBehind blocks behind hcd.  A wallet which
+// is not loaded is reported as unavailable.
+//
+// The lock state of the wallet is only reported when includeLockState is true.
+// Otherwise it is omitted and does not contribute to the status, so the result
+// may be served to unauthenticated clients.
+func CheckHealth(l *loader.Loader, maxBlocksBehind int, includeLockState bool) *hcjson.GetHealthResult {
+	w, ok := l.LoadedWallet()
+	if !ok {
+		return &hcjson.GetHealthResult{
+			Status:       wallet.HealthUnavailable.String(),
+			Code:         int(wallet.HealthUnavailable),
+			BlocksBehind: -1,
+			Checks: []hcjson.HealthCheckResult{{
+				Name:   "wallet",
+				Status: wallet.HealthUnavailable.String(),
+				Code:   int(wallet.HealthUnavailable),
+				Detail: "wallet is not loaded",
+			}},
+		}
+	}
+
+	report := w.CheckHealth(int64(maxBlocksBehind))
+	result := &hcjson.GetHealthResult{
+		WalletLoaded:   true,
+		ChainConnected: report.ChainConnected,
+		WalletHeight:   report.WalletHeight,
+		ChainHeight:    report.ChainHeight,
+		BlocksBehind:   report.BlocksBehind,
+		DBReadable:     report.DBReadable,
+		OmniResponsive: report.OmniResponsive,
+		Checks:         make([]hcjson.HealthCheckResult, 0, len(report.Checks)),
+	}
+	if includeLockState {
+		result.Unlocked = &report.Unlocked
+	}
+	status := wallet.HealthOK
+	for _, c := range report.Checks {
+		if c.Name == "unlocked" && !includeLockState {
+			continue
+		}
+		if c.Status > status {
+			status = c.Status
+		}
+		result.Checks = append(result.Checks, hcjson.HealthCheckResult{
+			Name:   c.Name,
+			Status: c.Status.String(),
+			Code:   int(c.Status),
+			Detail: c.Detail,
+		})
+	}
+	result.Status = status.String()
+	result.Code = int(status)
+	result.Ready = status != wallet.HealthUnavailable && report.Synced
+	return result
+}
+
+// getHealth handles a gethealth request by reporting the health of the wallet
+// and the services it depends on.  It is served even when no wallet is loaded.
+func getHealth(icmd interface{}, l *loader.Loader) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetHealthCmd)
+	return CheckHealth(l, *cmd.MaxBlocksBehind, true), nil
+}
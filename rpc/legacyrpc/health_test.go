@@ -0,0 +1,53 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+)
+
+func TestCheckHealthLockState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "legacyrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := loader.NewLoader(&chaincfg.SimNetParams, dir, &loader.StakeOptions{},
+		0, false, txauthor.TxLimits{}, false, 0.001, false)
+	_, err = l.CreateNewWallet([]byte("public"), []byte("private"),
+		bytes.Repeat([]byte{1}, 32), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.UnloadWallet()
+
+	hasUnlockedCheck := func(includeLockState bool) bool {
+		result := CheckHealth(l, 6, includeLockState)
+		if (result.Unlocked != nil) != includeLockState {
+			t.Errorf("include lock state %v: unlocked reported as %v",
+				includeLockState, result.Unlocked)
+		}
+		for _, c := range result.Checks {
+			if c.Name == "unlocked" {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasUnlockedCheck(true) {
+		t.Error("lock state is not checked")
+	}
+	if hasUnlockedCheck(false) {
+		t.Error("lock state is checked when it must not be reported")
+	}
+}
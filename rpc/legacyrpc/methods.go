@@ -10,11 +10,14 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,16 +33,18 @@ import (
 	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/loader"
 	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
 	"github.com/HcashOrg/hcwallet/wallet/txrules"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 )
 
 // API version constants
 const (
-	jsonrpcSemverString = "4.1.0"
-	jsonrpcSemverMajor  = 4
-	jsonrpcSemverMinor  = 1
+	jsonrpcSemverString = "7.23.0"
+	jsonrpcSemverMajor  = 7
+	jsonrpcSemverMinor  = 23
 	jsonrpcSemverPatch  = 0
 )
 
@@ -69,9 +74,21 @@ type requestHandler func(interface{}, *wallet.Wallet) (interface{}, error)
 // requestHandlerChain is a requestHandler that also takes a parameter for
 type requestHandlerChainRequired func(interface{}, *wallet.Wallet, *hcrpcclient.Client) (interface{}, error)
 
+// requestHandlerLoaderRequired is a handler function taking the loader of the
+// wallet serving the request.  These handlers are called even when the wallet
+// is not loaded.
+type requestHandlerLoaderRequired func(interface{}, *loader.Loader) (interface{}, error)
+
+// requestHandlerLogControlRequired is a handler function changing the logging
+// configuration of the process.  These handlers are called even when the
+// wallet is not loaded.
+type requestHandlerLogControlRequired func(interface{}, LogControl) (interface{}, error)
+
 type LegacyRpcHandler struct {
-	handler          requestHandler
-	handlerWithChain requestHandlerChainRequired
+	handler           requestHandler
+	handlerWithChain  requestHandlerChainRequired
+	handlerWithLoader requestHandlerLoaderRequired
+	handlerWithLogs   requestHandlerLogControlRequired
 
 	// Function variables cannot be compared against anything but nil, so
 	// use a boolean to record whether help generation is necessary.  This
@@ -87,52 +104,101 @@ type LegacyRpcHandler struct {
 func init() {
 	rpcHandlers = map[string]LegacyRpcHandler{
 		// Reference implementation wallet methods (implemented)
+		"abandontransaction":       {handler: abandonTransaction},
 		"accountaddressindex":      {handler: accountAddressIndex},
 		"accountsyncaddressindex":  {handler: accountSyncAddressIndex},
 		"addmultisigaddress":       {handlerWithChain: addMultiSigAddress},
 		"addticket":                {handler: addTicket},
+		"bumpfee":                  {handlerWithChain: bumpFee},
+		"canceltransactiondraft":   {handler: cancelTransactionDraft},
+		"checkaddressreuse":        {handlerWithChain: checkAddressReuse},
+		"committransactiondraft":   {handler: commitTransactionDraft},
+		"compactdb":                {handler: compactDB},
 		"consolidate":              {handler: consolidate},
+		"createexternalbranch":     {handler: createExternalBranch},
 		"createmultisig":           {handler: createMultiSig},
+		"createmultisigaccount":    {handler: createMultisigAccount},
+		"createmultisigspend":      {handler: createMultisigSpend},
+		"createtransactiondraft":   {handler: createTransactionDraft},
+		"decodewallettransaction":  {handler: decodeWalletTransaction},
+		"delegatedtickets":         {handlerWithChain: delegatedTickets},
 		"dumpprivkey":              {handler: dumpPrivKey},
+		"exportaccount":            {handler: exportAccount},
+		"exporttransactions":       {handler: exportTransactions},
+		"exportvotechoices":        {handler: exportVoteChoices},
+		"generateticketproof":      {handler: generateTicketProof},
 		"generatevote":             {handler: generateVote},
 		"getaccount":               {handler: getAccount},
 		"getaccountaddress":        {handler: getAccountAddress},
+		"getaccountdefaultaddress": {handler: getAccountDefaultAddress},
 		"getaddressesbyaccount":    {handler: getAddressesByAccount},
+		"getaddressforinvoice":     {handler: getAddressForInvoice},
+		"getauditlog":              {handler: getAuditLog},
 		"getbalance":               {handler: getBalance},
+		"getbalanceathash":         {handler: getBalanceAtHash},
+		"getbalanceatheight":       {handler: getBalanceAtHeight},
 		"getbestblockhash":         {handler: getBestBlockHash},
 		"getblockcount":            {handler: getBlockCount},
-		"getinfo":                  {handlerWithChain: getInfo},
+		"gethealth":                {handlerWithLoader: getHealth},
+		"getinfo":                  {handler: getInfo},
+		"getinvoicepayments":       {handler: getInvoicePayments},
 		"getmasterpubkey":          {handler: getMasterPubkey},
+		"getmultisigaccountinfo":   {handler: getMultisigAccountInfo},
 		"getmultisigoutinfo":       {handlerWithChain: getMultisigOutInfo},
 		"getnewaddress":            {handler: getNewAddress},
+		"getnewmultisigaddress":    {handler: getNewMultisigAddress},
 		"getrawchangeaddress":      {handler: getRawChangeAddress},
 		"getreceivedbyaccount":     {handler: getReceivedByAccount},
 		"getreceivedbyaddress":     {handler: getReceivedByAddress},
+		"getrecoverystate":         {handler: getRecoveryState},
+		"getreplicationinfo":       {handlerWithLoader: getReplicationInfo},
+		"getrescaninfo":            {handler: getRescanInfo},
+		"getspendableconfs":        {handler: getSpendableConfs},
 		"getstakeinfo":             {handlerWithChain: getStakeInfo},
+		"getstakerewards":          {handler: getStakeRewards},
 		"getticketfee":             {handler: getTicketFee},
 		"gettickets":               {handlerWithChain: getTickets},
 		"gettransaction":           {handler: getTransaction},
 		"getvotechoices":           {handler: getVoteChoices},
 		"getwalletfee":             {handler: getWalletFee},
 		"help":                     {handler: helpNoChainRPC, handlerWithChain: helpWithChainRPC},
+		"importaccount":            {handlerWithChain: importAccount},
 		"importprivkey":            {handlerWithChain: importPrivKey},
 		"importscript":             {handlerWithChain: importScript},
+		"importvotechoices":        {handler: importVoteChoices},
+		"initiateswap":             {handler: initiateSwap},
 		"keypoolrefill":            {handler: keypoolRefill},
 		"listaccounts":             {handler: listAccounts},
+		"listexternalbranches":     {handler: listExternalBranches},
 		"listlockunspent":          {handler: listLockUnspent},
+		"listpoolfeeexemptions":    {handler: listPoolFeeExemptions},
 		"listreceivedbyaccount":    {handler: listReceivedByAccount},
 		"listreceivedbyaddress":    {handler: listReceivedByAddress},
 		"listsinceblock":           {handlerWithChain: listSinceBlock},
+		"listrefundablescripts":    {handler: listRefundableScripts},
 		"listscripts":              {handler: listScripts},
+		"listswaps":                {handler: listSwaps},
+		"liststaleomnipending":     {handler: listStaleOmniPending},
 		"listtransactions":         {handler: listTransactions},
 		"listunspent":              {handler: listUnspent},
 		"lockunspent":              {handler: lockUnspent},
+		"participateswap":          {handler: participateSwap},
+		"promotereplica":           {handlerWithLoader: promoteReplica},
+		"prunewallethistory":       {handler: pruneWalletHistory},
 		"purchaseticket":           {handler: purchaseTicket},
+		"redeemswap":               {handler: redeemSwap},
+		"reevaluatepooltickets":    {handler: reevaluatePoolTickets},
+		"refundscript":             {handler: refundScript},
+		"refundswap":               {handler: refundSwap},
 		"rescanwallet":             {handlerWithChain: rescanWallet},
+		"reserveoutputs":           {handler: reserveOutputs},
 		"revoketickets":            {handlerWithChain: revokeTickets},
 		"sendfrom":                 {handlerWithChain: sendFrom},
+		"sendbatch":                {handler: sendBatch},
 		"sendmany":                 {handler: sendMany},
 		"sendmanyv2":               {handler: sendManyV2},
+		"senddata":                 {handler: sendData},
+		"sendrawtransaction":       {handlerWithChain: sendRawTransaction},
 		"sendtoaddress":            {handler: sendToAddress},
 		"sendfromaddresstoaddress": {handler: sendFromAddressToAddress},
 		"getstraightpubkey":        {handlerWithChain: getStraightPubKey},
@@ -140,18 +206,29 @@ func init() {
 		"sendtosstx":               {handlerWithChain: sendToSStx},
 		"sendtossgen":              {handler: sendToSSGen},
 		"sendtossrtx":              {handlerWithChain: sendToSSRtx},
+		"setaccountdefaultaddress": {handler: setAccountDefaultAddress},
+		"setloglevel":              {handlerWithLogs: setLogLevel},
+		"setlogrotation":           {handlerWithLogs: setLogRotation},
+		"setpoolfeeexemption":      {handler: setPoolFeeExemption},
+		"setspendableconfs":        {handler: setSpendableConfs},
 		"setticketfee":             {handler: setTicketFee},
 		"settxfee":                 {handler: setTxFee},
 		"setvotechoice":            {handler: setVoteChoice},
 		"signmessage":              {handler: signMessage},
+		"signmessagewithaccount":   {handler: signMessageWithAccount},
 		"signrawtransaction":       {handler: signRawTransactionNoChainRPC, handlerWithChain: signRawTransaction},
 		"signrawtransactions":      {handlerWithChain: signRawTransactions},
 		"redeemmultisigout":        {handlerWithChain: redeemMultiSigOut},
 		"redeemmultisigouts":       {handlerWithChain: redeemMultiSigOuts},
 		"stakepooluserinfo":        {handler: stakePoolUserInfo},
+		"syncaccountaddresses":     {handler: syncAccountAddresses},
 		"ticketsforaddress":        {handler: ticketsForAddress},
+		"triggerconsolidation":     {handler: triggerConsolidation},
 		"validateaddress":          {handler: validateAddress},
+		"verifyaccountproof":       {handler: verifyAccountProof},
 		"verifymessage":            {handler: verifyMessage},
+		"verifyseedbackup":         {handler: verifySeedBackup},
+		"verifyticketproof":        {handler: verifyTicketProof},
 		"version":                  {handler: versionNoChainRPC, handlerWithChain: versionWithChainRPC},
 		"walletinfo":               {handlerWithChain: walletInfo},
 		"walletlock":               {handler: walletLock},
@@ -183,6 +260,11 @@ func init() {
 		"listalltransactions":     {handler: listAllTransactions},
 		"renameaccount":           {handler: renameAccount},
 		"walletislocked":          {handler: walletIsLocked},
+
+		// Websocket-only notification requests, which are handled by the
+		// websocket server before reaching these handlers.
+		"notifytipchanges":     {handler: websocketOnly},
+		"stopnotifytipchanges": {handler: websocketOnly},
 	}
 
 	for k, v := range getOminiMethod() {
@@ -199,6 +281,12 @@ func unimplemented(interface{}, *wallet.Wallet) (interface{}, error) {
 	}
 }
 
+// websocketOnly handles a websocket-only request made over HTTP POST with the
+// appropriate error.
+func websocketOnly(interface{}, *wallet.Wallet) (interface{}, error) {
+	return nil, &ErrWebsocketOnly
+}
+
 // unsupported handles a standard bitcoind RPC request which is
 // unsupported by hcwallet due to design differences.
 func unsupported(interface{}, *wallet.Wallet) (interface{}, error) {
@@ -217,8 +305,34 @@ type lazyHandler func() (interface{}, *hcjson.RPCError)
 // returning a closure that will execute it with the (required) wallet and
 // (optional) consensus RPC server.  If no handlers are found and the
 // chainClient is not nil, the returned handler performs RPC passthrough.
-func lazyApplyHandler(request *hcjson.Request, w *wallet.Wallet, chainClient *hcrpcclient.Client) lazyHandler {
+func lazyApplyHandler(request *hcjson.Request, w *wallet.Wallet, chainClient *hcrpcclient.Client, l *loader.Loader, lc LogControl) lazyHandler {
 	handlerData, ok := rpcHandlers[request.Method]
+	if ok && handlerData.handlerWithLogs != nil && lc != nil {
+		return func() (interface{}, *hcjson.RPCError) {
+			cmd, err := hcjson.UnmarshalCmd(request)
+			if err != nil {
+				return nil, hcjson.ErrRPCInvalidRequest
+			}
+			resp, err := handlerData.handlerWithLogs(cmd, lc)
+			if err != nil {
+				return nil, jsonError(err)
+			}
+			return resp, nil
+		}
+	}
+	if ok && handlerData.handlerWithLoader != nil && l != nil {
+		return func() (interface{}, *hcjson.RPCError) {
+			cmd, err := hcjson.UnmarshalCmd(request)
+			if err != nil {
+				return nil, hcjson.ErrRPCInvalidRequest
+			}
+			resp, err := handlerData.handlerWithLoader(cmd, l)
+			if err != nil {
+				return nil, jsonError(err)
+			}
+			return resp, nil
+		}
+	}
 	if ok && handlerData.handlerWithChain != nil && w != nil && chainClient != nil {
 		return func() (interface{}, *hcjson.RPCError) {
 			cmd, err := hcjson.UnmarshalCmd(request)
@@ -307,6 +421,8 @@ func jsonError(err error) *hcjson.RPCError {
 		code = hcjson.ErrRPCInvalidParameter
 	case ParseError:
 		code = hcjson.ErrRPCParse.Code
+	case *wallet.ScriptValidationError:
+		code = hcjson.ErrRPCVerify
 	case apperrors.E:
 		switch e.ErrorCode {
 		case apperrors.ErrWrongPassphrase:
@@ -319,6 +435,98 @@ func jsonError(err error) *hcjson.RPCError {
 	}
 }
 
+// allowHighFees returns the fee checking mode to use when sending a
+// transaction for a request.  The request's own setting takes precedence over
+// the wallet's configured default.
+func allowHighFees(w *wallet.Wallet, requested *bool) bool {
+	if requested != nil {
+		return *requested
+	}
+	return w.AllowHighFees()
+}
+
+// revocationAllowHighFees returns the fee checking mode to use when sending
+// revocations for a request.  The fee of a revocation is paid from the ticket
+// rather than chosen by the wallet, so high fees are allowed unless the
+// request disallows them.
+func revocationAllowHighFees(requested *bool) bool {
+	if requested != nil {
+		return *requested
+	}
+	return true
+}
+
+// bumpFee handles a bumpfee request by replacing an unmined transaction with
+// one paying a higher fee from its change.
+func bumpFee(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.BumpFeeCmd)
+
+	txHash, err := chainhash.NewHashFromStr(cmd.TxHash)
+	if err != nil {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+
+	var feeRate hcutil.Amount
+	if cmd.FeeRate != nil {
+		if *cmd.FeeRate <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		feeRate, err = hcutil.NewAmount(*cmd.FeeRate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := w.BumpFee(txHash, feeRate, chainClient)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrValueNoExists):
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCNoTxInfo,
+			Message: "No unmined transaction with this hash",
+		}
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+
+	return &hcjson.BumpFeeResult{
+		TxID:     res.Replacement.TxHash().String(),
+		OrigTxID: res.Original.String(),
+		OrigFee:  res.OldFee.ToCoin(),
+		Fee:      res.NewFee.ToCoin(),
+	}, nil
+}
+
+// abandonTransaction handles an abandontransaction request by removing an
+// unmined transaction and all unmined transactions spending from it from the
+// wallet.
+func abandonTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.AbandonTransactionCmd)
+
+	txHash, err := chainhash.NewHashFromStr(cmd.TxHash)
+	if err != nil {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+
+	err = w.AbandonTransaction(txHash)
+	if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCNoTxInfo,
+			Message: "No unmined transaction with this hash",
+		}
+	}
+	return nil, err
+}
+
 // accountAddressIndex returns the next address index for the passed
 // account and branch.
 func accountAddressIndex(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -472,8 +680,134 @@ func addTicket(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return nil, err
 }
 
-// consolidate handles a consolidate request by returning attempting to compress
-// as many inputs as given and then returning the txHash and error.
+// errNoTxDraft is the error returned when a transaction draft does not exist.
+var errNoTxDraft = &hcjson.RPCError{
+	Code:    hcjson.ErrRPCInvalidParameter,
+	Message: "No transaction draft with this id",
+}
+
+// cancelTransactionDraft handles a canceltransactiondraft request by removing
+// a transaction draft and releasing the outputs reserved by it.
+func cancelTransactionDraft(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.CancelTransactionDraftCmd)
+
+	hash, err := chainhash.NewHashFromStr(cmd.DraftID)
+	if err != nil {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCDecodeHexString,
+			Message: "Draft id decode failed: " + err.Error(),
+		}
+	}
+
+	err = w.CancelTransactionDraft(hash)
+	if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		return nil, errNoTxDraft
+	}
+	return nil, err
+}
+
+// checkAddressReuse handles a checkaddressreuse request by reporting external
+// addresses paid by more than one transaction.  When a lookahead is requested,
+// the main chain is also scanned for use of addresses the wallet has not yet
+// returned.
+func checkAddressReuse(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.CheckAddressReuseCmd)
+	if *cmd.StartHeight < 0 {
+		e := errors.New("start height must be non-negative")
+		return nil, InvalidParameterError{e}
+	}
+
+	report, err := w.AddressReuse(chainClient, *cmd.StartHeight, *cmd.Lookahead)
+	if err != nil {
+		return nil, err
+	}
+
+	results := func(uses []wallet.AddressUse) ([]hcjson.AddressUseResult, error) {
+		res := make([]hcjson.AddressUseResult, 0, len(uses))
+		for i := range uses {
+			u := &uses[i]
+			acctName, err := w.AccountName(u.Account)
+			if err != nil {
+				return nil, err
+			}
+			txs := make([]string, 0, len(u.Transactions))
+			for j := range u.Transactions {
+				txs = append(txs, u.Transactions[j].String())
+			}
+			res = append(res, hcjson.AddressUseResult{
+				Address:      u.Address.EncodeAddress(),
+				Account:      acctName,
+				Index:        u.Index,
+				Transactions: txs,
+			})
+		}
+		return res, nil
+	}
+	reused, err := results(report.Reused)
+	if err != nil {
+		return nil, err
+	}
+	usedBeforeReturned, err := results(report.UsedBeforeReturned)
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.CheckAddressReuseResult{
+		Reused:             reused,
+		UsedBeforeReturned: usedBeforeReturned,
+	}, nil
+}
+
+// commitTransactionDraft handles a committransactiondraft request by signing
+// and publishing the transaction of a draft.  The transaction hash is
+// returned.
+func commitTransactionDraft(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.CommitTransactionDraftCmd)
+
+	hash, err := chainhash.NewHashFromStr(cmd.DraftID)
+	if err != nil {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCDecodeHexString,
+			Message: "Draft id decode failed: " + err.Error(),
+		}
+	}
+
+	txHash, err := w.CommitTransactionDraft(hash)
+	if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		return nil, errNoTxDraft
+	}
+	if apperrors.IsError(err, apperrors.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	if err != nil {
+		return nil, err
+	}
+	return txHash.String(), nil
+}
+
+// compactDB handles a compactdb request by checking the integrity of the
+// wallet database and compacting it when no inconsistencies are found.
+func compactDB(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	issues, err := w.CheckDB()
+	if err != nil {
+		return nil, err
+	}
+	res := &hcjson.CompactDBResult{Issues: issues}
+	if len(issues) != 0 {
+		return res, nil
+	}
+	err = w.CompactDB()
+	if err != nil {
+		return nil, err
+	}
+	res.Issues = []string{}
+	res.Compacted = true
+	return res, nil
+}
+
+// consolidate handles a consolidate request by attempting to compress as many
+// inputs of an account as given, returning the published transactions with
+// the fees paid and the inputs consumed.  Legacy results are only the hashes of
+// the published transactions.
 func consolidate(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*hcjson.ConsolidateCmd)
 
@@ -498,14 +832,78 @@ func consolidate(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
-	// TODO In the future this should take the optional account and
-	// only consolidate UTXOs found within that account.
-	txHash, err := w.Consolidate(cmd.Inputs, account, changeAddr)
-	if err != nil {
+	var feeRate, maxFee hcutil.Amount
+	if cmd.FeeRate != nil {
+		feeRate, err = hcutil.NewAmount(*cmd.FeeRate)
+		if err != nil {
+			return nil, InvalidParameterError{err}
+		}
+	}
+	if cmd.MaxFee != nil {
+		maxFee, err = hcutil.NewAmount(*cmd.MaxFee)
+		if err != nil {
+			return nil, InvalidParameterError{err}
+		}
+	}
+
+	// Only the outputs of the account are consolidated.  Consolidations
+	// are split into multiple transactions when enabled by the splittxs
+	// option.
+	results, err := w.ConsolidateAccount(cmd.Inputs, account, changeAddr,
+		feeRate, maxFee)
+	switch {
+	case len(results) != 0:
+		// Some transactions were published before the error, and are
+		// reported along with it.
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case err != nil:
 		return nil, err
 	}
 
-	return txHash.String(), nil
+	// The legacy result is the hash of the transaction, or the hashes of
+	// each transaction when the consolidation was split.  Published hashes
+	// are included in the error message when a later transaction failed.
+	hashStrs := make([]string, len(results))
+	for i := range results {
+		hashStrs[i] = results[i].Hash.String()
+	}
+	var legacy interface{} = hashStrs
+	if len(hashStrs) == 1 {
+		legacy = hashStrs[0]
+	}
+	if err != nil {
+		legacy = &hcjson.RPCError{
+			Code: hcjson.ErrRPCWallet,
+			Message: fmt.Sprintf("%v (published consolidation "+
+				"transactions %s)", err, strings.Join(hashStrs, ", ")),
+		}
+	}
+
+	res := &hcjson.ConsolidateResult{
+		Transactions: make([]hcjson.ConsolidateTxResult, len(results)),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	var fee, amount hcutil.Amount
+	for i, r := range results {
+		res.Transactions[i] = hcjson.ConsolidateTxResult{
+			TxID:   r.Hash.String(),
+			Inputs: r.Inputs,
+			Fee:    r.Fee.ToCoin(),
+			Amount: r.Amount.ToCoin(),
+		}
+		res.Inputs += r.Inputs
+		fee += r.Fee
+		amount += r.Amount
+	}
+	res.Fee = fee.ToCoin()
+	res.Amount = amount.ToCoin()
+	return versionedResult{
+		resultVersionLegacy:   legacy,
+		resultVersionExtended: res,
+	}, nil
 }
 
 // createMultiSig handles an createmultisig request by returning a
@@ -530,118 +928,599 @@ func createMultiSig(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}, nil
 }
 
-// dumpPrivKey handles a dumpprivkey request with the private key
-// for a single address, or an appropiate error if the wallet
-// is locked.
-func dumpPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*hcjson.DumpPrivKeyCmd)
+// createTransactionDraft handles a createtransactiondraft request by authoring
+// an unsigned transaction paying each address and reserving its inputs until
+// the draft is committed or canceled.
+func createTransactionDraft(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.CreateTransactionDraftCmd)
 
-	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	account, err := w.AccountNumber(*cmd.Account)
 	if err != nil {
 		return nil, err
 	}
-
-	key, err := w.DumpWIFPrivateKey(addr)
-	if apperrors.IsError(err, apperrors.ErrLocked) {
-		// Address was found, but the private key isn't
-		// accessible.
-		return nil, &ErrWalletUnlockNeeded
+	minConf := int32(*cmd.MinConf)
+	if minConf < 0 {
+		return nil, ErrNeedPositiveMinconf
 	}
-	return key, err
-}
-
-// generateVote handles a generatevote request by constructing a signed
-// vote and returning it.
-func generateVote(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*hcjson.GenerateVoteCmd)
 
-	blockHash, err := chainhash.NewHashFromStr(cmd.BlockHash)
+	pairs := make(map[string]hcutil.Amount, len(cmd.Amounts))
+	for k, v := range cmd.Amounts {
+		amt, err := hcutil.NewAmount(v)
+		if err != nil {
+			return nil, err
+		}
+		if amt <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		pairs[k] = amt
+	}
+	if len(pairs) == 0 {
+		return nil, InvalidParameterError{errors.New("no outputs")}
+	}
+	outputs, err := makeOutputs(pairs, w.ChainParams())
 	if err != nil {
 		return nil, err
 	}
 
-	ticketHash, err := chainhash.NewHashFromStr(cmd.TicketHash)
+	atx, err := w.CreateTransactionDraft(outputs, account, minConf)
+	if _, ok := err.(txauthor.InsufficientFundsError); ok {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWalletInsufficientFunds,
+			Message: "Insufficient unreserved funds to create draft",
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var voteBitsExt []byte
-	voteBitsExt, err = hex.DecodeString(cmd.VoteBitsExt)
+	var buf bytes.Buffer
+	buf.Grow(atx.Tx.SerializeSize())
+	err = atx.Tx.Serialize(&buf)
 	if err != nil {
 		return nil, err
 	}
-	voteBits := stake.VoteBits{
-		Bits:         cmd.VoteBits,
-		ExtendedBits: voteBitsExt,
+	var outputTotal hcutil.Amount
+	for _, out := range atx.Tx.TxOut {
+		outputTotal += hcutil.Amount(out.Value)
 	}
+	return &hcjson.CreateTransactionDraftResult{
+		DraftID:     atx.Tx.TxHash().String(),
+		Hex:         hex.EncodeToString(buf.Bytes()),
+		Fee:         (atx.TotalInput - outputTotal).ToCoin(),
+		ChangeIndex: atx.ChangeIndex,
+	}, nil
+}
 
-	ssgentx, err := w.GenerateVoteTx(blockHash, int32(cmd.Height), ticketHash,
-		voteBits)
-	if err != nil {
-		return nil, err
+// txTypeString returns the transaction type reported by RPC results for a
+// stake transaction type.
+func txTypeString(txType stake.TxType) string {
+	switch txType {
+	case stake.TxTypeSStx:
+		return string(hcjson.LTTTTicket)
+	case stake.TxTypeSSGen:
+		return string(hcjson.LTTTVote)
+	case stake.TxTypeSSRtx:
+		return string(hcjson.LTTTRevocation)
+	default:
+		return string(hcjson.LTTTRegular)
 	}
+}
 
-	txHex := ""
-	if ssgentx != nil {
-		// Serialize the transaction and convert to hex string.
-		buf := bytes.NewBuffer(make([]byte, 0, ssgentx.SerializeSize()))
-		if err := ssgentx.Serialize(buf); err != nil {
-			return nil, err
+// walletTxOutputResult returns the JSON result describing an annotated
+// transaction output.
+func walletTxOutputResult(a *wallet.OutputAnnotation, n uint32) *hcjson.WalletTxOutputResult {
+	res := &hcjson.WalletTxOutputResult{
+		Value:      a.Amount.ToCoin(),
+		N:          n,
+		ScriptType: a.ScriptClass.String(),
+		Mine:       a.Mine,
+		Account:    a.AccountName,
+		Label:      a.Label,
+		Change:     a.Change,
+	}
+	for _, addr := range a.Addresses {
+		res.Addresses = append(res.Addresses, addr.EncodeAddress())
+	}
+	if len(a.Data) != 0 {
+		res.Data = hex.EncodeToString(a.Data)
+	}
+	if a.Spent {
+		res.Spent = &a.Spent
+		if a.SpentBy != nil {
+			res.SpentBy = a.SpentBy.String()
 		}
-		txHex = hex.EncodeToString(buf.Bytes())
 	}
+	return res
+}
 
-	resp := &hcjson.GenerateVoteResult{
-		Hex: txHex,
+// walletTxInputsOutputs returns the results of the annotated inputs and outputs
+// of a transaction.
+func walletTxInputsOutputs(atx *wallet.AnnotatedTx) ([]hcjson.WalletTxInputResult,
+	[]hcjson.WalletTxOutputResult) {
+
+	vin := make([]hcjson.WalletTxInputResult, len(atx.Inputs))
+	vout := make([]hcjson.WalletTxOutputResult, len(atx.Outputs))
+	for i := range atx.Inputs {
+		in := &atx.Inputs[i]
+		vin[i] = hcjson.WalletTxInputResult{
+			Txid:      in.PreviousOutPoint.Hash.String(),
+			Vout:      in.PreviousOutPoint.Index,
+			Tree:      in.PreviousOutPoint.Tree,
+			Sequence:  in.Sequence,
+			AmountIn:  in.ValueIn.ToCoin(),
+			Stakebase: in.Stakebase,
+		}
+		if in.PreviousOutput != nil {
+			vin[i].PrevOut = walletTxOutputResult(in.PreviousOutput,
+				in.PreviousOutPoint.Index)
+		}
 	}
-
-	return resp, nil
+	for i := range atx.Outputs {
+		vout[i] = *walletTxOutputResult(&atx.Outputs[i], uint32(i))
+	}
+	return vin, vout
 }
 
-// getAddressesByAccount handles a getaddressesbyaccount request by returning
-// all addresses for an account, or an error if the requested account does
-// not exist.
-func getAddressesByAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*hcjson.GetAddressesByAccountCmd)
+// decodeWalletTransaction handles a decodewallettransaction request by
+// decoding a raw transaction and annotating its inputs and outputs with the
+// wallet's knowledge of the addresses and accounts involved.
+func decodeWalletTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.DecodeWalletTransactionCmd)
 
-	account, err := w.AccountNumber(cmd.Account)
+	serializedTx, err := decodeHexStr(cmd.HexTx)
 	if err != nil {
 		return nil, err
 	}
-
-	if account == udb.ImportedAddrAccount {
-		return w.FetchImortedAccountAddress()
-	}
-	// Find the next child address indexes for the account.
-	endExt, endInt, err := w.BIP0044BranchNextIndexes(account)
+	var tx wire.MsgTx
+	err = tx.Deserialize(bytes.NewReader(serializedTx))
 	if err != nil {
-		return nil, err
-	}
-
-	// Nothing to do if we have no addresses.
-	if endExt+endInt == 0 {
-		return nil, nil
+		return nil, DeserializationError{errors.New("TX decode failed")}
 	}
 
-	// Derive the addresses.
-	addrsStr := make([]string, endInt+endExt)
-	addrsExt, err := w.AccountBranchAddressRange(account, udb.ExternalBranch, 0, endExt)
+	atx, err := w.AnnotateTransaction(&tx)
 	if err != nil {
 		return nil, err
 	}
-	for i := range addrsExt {
-		addrsStr[i] = addrsExt[i].EncodeAddress()
+
+	res := &hcjson.DecodeWalletTransactionResult{
+		Txid:     tx.TxHash().String(),
+		Version:  tx.Version,
+		LockTime: tx.LockTime,
+		Expiry:   tx.Expiry,
+		Type:     txTypeString(atx.Type),
+		Omni:     atx.Omni,
 	}
-	addrsInt, err := w.AccountBranchAddressRange(account, udb.InternalBranch, 0, endInt)
+	res.Vin, res.Vout = walletTxInputsOutputs(atx)
+	return res, nil
+}
+
+// delegatedTickets handles a delegatedtickets request by returning the wallet
+// tickets with voting rights delegated to another wallet.
+func delegatedTickets(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	tickets, err := w.DelegatedTickets(chainClient)
 	if err != nil {
 		return nil, err
 	}
-	for i := range addrsInt {
-		addrsStr[i+int(endExt)] = addrsInt[i].EncodeAddress()
-	}
-
-	return addrsStr, nil
-}
+
+	res := make([]hcjson.DelegatedTicketResult, len(tickets))
+	for i, t := range tickets {
+		res[i] = hcjson.DelegatedTicketResult{
+			Ticket: t.Hash.String(),
+			Price:  t.Price.ToCoin(),
+		}
+		if t.VotingAddress != nil {
+			res[i].VotingAddress = t.VotingAddress.EncodeAddress()
+		}
+		if t.SpenderHash != nil {
+			res[i].SpentBy = t.SpenderHash.String()
+		}
+		switch t.Status {
+		case wallet.TicketStatusUnmined:
+			res[i].Status = "unmined"
+		case wallet.TicketStatusImmature:
+			res[i].Status = "immature"
+		case wallet.TicketStatusLive:
+			res[i].Status = "live"
+		case wallet.TicketStatusVoted:
+			res[i].Status = "voted"
+		case wallet.TicketStatusMissed:
+			res[i].Status = "missed"
+		case wallet.TicketStatusExpired:
+			res[i].Status = "expired"
+		case wallet.TicketStatusRevoked:
+			res[i].Status = "revoked"
+		default:
+			res[i].Status = "unknown"
+		}
+	}
+	return res, nil
+}
+
+// dumpPrivKey handles a dumpprivkey request with the private key
+// for a single address, or an appropiate error if the wallet
+// is locked.
+func dumpPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.DumpPrivKeyCmd)
+
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := w.DumpWIFPrivateKey(addr)
+	if apperrors.IsError(err, apperrors.ErrLocked) {
+		// Address was found, but the private key isn't
+		// accessible.
+		return nil, &ErrWalletUnlockNeeded
+	}
+	return key, err
+}
+
+// exportAccount handles an exportaccount request by returning the extended
+// public or private key of an account, which may be used to recreate the
+// account in another wallet with importaccount.
+func exportAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ExportAccountCmd)
+
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	private := cmd.Private != nil && *cmd.Private
+	key, err := w.ExportAccount(account, private)
+	if apperrors.IsError(err, apperrors.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	return key, err
+}
+
+// exportTransactions handles an exporttransactions request by returning the
+// effects of the wallet's transactions on the balances of its accounts, as
+// either CSV or JSON.
+func exportTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ExportTransactionsCmd)
+
+	switch *cmd.Format {
+	case "csv", "json":
+	default:
+		return nil, InvalidParameterError{fmt.Errorf("unknown format %q (must be csv or json)", *cmd.Format)}
+	}
+	var account *uint32
+	if cmd.Account != nil {
+		acct, err := w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+		account = &acct
+	}
+
+	exports, err := w.ExportTransactions(account, *cmd.Fiat)
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := make([]hcjson.ExportedTransactionResult, len(exports))
+	for i := range exports {
+		e := &exports[i]
+		res[i] = hcjson.ExportedTransactionResult{
+			TxID:        e.Hash.String(),
+			BlockHeight: e.Height,
+			Time:        e.Time.Unix(),
+			TxType:      txTypeString(e.Type),
+			Category:    string(e.Category),
+			Account:     e.AccountName,
+			Amount:      e.Amount.ToCoin(),
+			Fee:         e.Fee.ToCoin(),
+			Net:         e.Net().ToCoin(),
+			Balance:     e.Balance.ToCoin(),
+		}
+		if *cmd.Fiat {
+			res[i].FiatCurrency = e.FiatCurrency
+			res[i].FiatPrice = &e.FiatPrice
+			res[i].FiatValue = &e.FiatValue
+		}
+	}
+	if *cmd.Format == "json" {
+		return res, nil
+	}
+
+	formatAmount := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 8, 64)
+	}
+	var buf bytes.Buffer
+	csvw := csv.NewWriter(&buf)
+	csvw.Write([]string{"txid", "blockheight", "time", "txtype", "category",
+		"account", "amount", "fee", "net", "balance", "fiatcurrency",
+		"fiatprice", "fiatvalue"})
+	for i := range res {
+		r := &res[i]
+		record := []string{
+			r.TxID,
+			strconv.FormatInt(int64(r.BlockHeight), 10),
+			time.Unix(r.Time, 0).UTC().Format(time.RFC3339),
+			r.TxType,
+			r.Category,
+			r.Account,
+			formatAmount(r.Amount),
+			formatAmount(r.Fee),
+			formatAmount(r.Net),
+			formatAmount(r.Balance),
+			r.FiatCurrency,
+			"",
+			"",
+		}
+		if r.FiatPrice != nil {
+			record[11] = strconv.FormatFloat(*r.FiatPrice, 'f', -1, 64)
+			record[12] = strconv.FormatFloat(*r.FiatValue, 'f', 2, 64)
+		}
+		csvw.Write(record)
+	}
+	csvw.Flush()
+	if err := csvw.Error(); err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+// exportVoteChoices handles an exportvotechoices request by returning the
+// configured vote choice of each agenda of the latest supported stake version
+// in the form accepted by importvotechoices.
+func exportVoteChoices(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	version, _ := wallet.CurrentAgendas(w.ChainParams())
+	choices, _, err := w.AgendaChoices()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &hcjson.ExportVoteChoicesResult{
+		Version: version,
+		Choices: make([]hcjson.AgendaChoice, len(choices)),
+	}
+	for i := range choices {
+		resp.Choices[i] = hcjson.AgendaChoice{
+			AgendaID: choices[i].AgendaID,
+			ChoiceID: choices[i].ChoiceID,
+		}
+	}
+	return resp, nil
+}
+
+// generateTicketProof handles the generateticketproof command by signing a
+// challenge with a commitment or voting address of a ticket.
+func generateTicketProof(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GenerateTicketProofCmd)
+
+	ticketHash, err := chainhash.NewHashFromStr(cmd.TicketHash)
+	if err != nil {
+		return nil, DeserializationError{err}
+	}
+	addr, sig, err := w.GenerateTicketProof(ticketHash, cmd.Challenge)
+	if apperrors.IsError(err, apperrors.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.GenerateTicketProofResult{
+		Address:   addr.EncodeAddress(),
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
+
+// generateVote handles a generatevote request by constructing a signed
+// vote and returning it.
+func generateVote(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GenerateVoteCmd)
+
+	blockHash, err := chainhash.NewHashFromStr(cmd.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketHash, err := chainhash.NewHashFromStr(cmd.TicketHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var voteBitsExt []byte
+	voteBitsExt, err = hex.DecodeString(cmd.VoteBitsExt)
+	if err != nil {
+		return nil, err
+	}
+	voteBits := stake.VoteBits{
+		Bits:         cmd.VoteBits,
+		ExtendedBits: voteBitsExt,
+	}
+
+	ssgentx, err := w.GenerateVoteTx(blockHash, int32(cmd.Height), ticketHash,
+		voteBits)
+	if err != nil {
+		return nil, err
+	}
+
+	txHex := ""
+	if ssgentx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, ssgentx.SerializeSize()))
+		if err := ssgentx.Serialize(buf); err != nil {
+			return nil, err
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	resp := &hcjson.GenerateVoteResult{
+		Hex: txHex,
+	}
+
+	return resp, nil
+}
+
+// getAddressesByAccount handles a getaddressesbyaccount request by returning
+// all addresses for an account, or an error if the requested account does
+// not exist.
+func getAddressesByAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetAddressesByAccountCmd)
+
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == udb.ImportedAddrAccount {
+		return w.FetchImortedAccountAddress()
+	}
+	// Find the next child address indexes for the account.
+	endExt, endInt, err := w.BIP0044BranchNextIndexes(account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing to do if we have no addresses.
+	if endExt+endInt == 0 {
+		return nil, nil
+	}
+
+	// Derive the addresses.
+	addrsStr := make([]string, endInt+endExt)
+	addrsExt, err := w.AccountBranchAddressRange(account, udb.ExternalBranch, 0, endExt)
+	if err != nil {
+		return nil, err
+	}
+	for i := range addrsExt {
+		addrsStr[i] = addrsExt[i].EncodeAddress()
+	}
+	addrsInt, err := w.AccountBranchAddressRange(account, udb.InternalBranch, 0, endInt)
+	if err != nil {
+		return nil, err
+	}
+	for i := range addrsInt {
+		addrsStr[i+int(endExt)] = addrsInt[i].EncodeAddress()
+	}
+
+	return addrsStr, nil
+}
+
+// getAccountDefaultAddress handles a getaccountdefaultaddress request by
+// returning the address used as the change and sending address of
+// transactions from an account which do not specify one.
+func getAccountDefaultAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetAccountDefaultAddressCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := w.AccountDefaultAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// getAddressForInvoice handles a getaddressforinvoice request by returning the
+// address of an account deterministically derived for an invoice id.
+func getAddressForInvoice(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetAddressForInvoiceCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := w.InvoiceAddress(account, cmd.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// getInvoicePayments handles a getinvoicepayments request by returning the
+// payments received by the address derived for an invoice id.
+func getInvoicePayments(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetInvoicePaymentsCmd)
+
+	payments, err := w.InvoicePayments(cmd.InvoiceID, int32(*cmd.MinConf))
+	if err != nil {
+		if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+			return nil, InvalidParameterError{err}
+		}
+		return nil, err
+	}
+	acctName, err := w.AccountName(payments.Invoice.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &hcjson.GetInvoicePaymentsResult{
+		InvoiceID: payments.Invoice.ID,
+		Address:   payments.Address.EncodeAddress(),
+		Account:   acctName,
+		Created:   payments.Invoice.Created.Unix(),
+		Received:  payments.Received.ToCoin(),
+		Payments:  make([]hcjson.InvoicePaymentResult, 0, len(payments.Payments)),
+	}
+	for i := range payments.Payments {
+		p := &payments.Payments[i]
+		var blockHash string
+		if p.BlockHeight != -1 {
+			blockHash = p.BlockHash.String()
+		}
+		result.Payments = append(result.Payments, hcjson.InvoicePaymentResult{
+			TxID:          p.TxHash.String(),
+			Amount:        p.Amount.ToCoin(),
+			BlockHash:     blockHash,
+			BlockHeight:   p.BlockHeight,
+			Confirmations: p.Confirmations,
+		})
+	}
+	return result, nil
+}
+
+// getAuditLog handles a getauditlog request by returning a page of the audit
+// log of balance-affecting events, beginning with the entry with sequence
+// number from.  The next sequence number to request is returned with the
+// entries.
+func getAuditLog(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetAuditLogCmd)
+	if *cmd.Count <= 0 {
+		return nil, InvalidParameterError{errors.New("count must be positive")}
+	}
+
+	entries, err := w.AuditLog(*cmd.From, *cmd.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &hcjson.GetAuditLogResult{
+		Entries: make([]hcjson.AuditLogEntry, 0, len(entries)),
+		Next:    *cmd.From,
+	}
+	for i := range entries {
+		e := &entries[i]
+		entry := hcjson.AuditLogEntry{
+			Seq:         e.Seq,
+			Time:        e.Time.Unix(),
+			Event:       e.Event.String(),
+			Amount:      e.Amount.ToCoin(),
+			BlockHeight: e.BlockHeight,
+			Trigger:     e.Trigger,
+		}
+		if e.Event != udb.AuditRollback {
+			entry.TxID = e.TxHash.String()
+		}
+		if e.BlockHash != (chainhash.Hash{}) {
+			entry.BlockHash = e.BlockHash.String()
+		}
+		result.Entries = append(result.Entries, entry)
+		result.Next = e.Seq + 1
+	}
+	return result, nil
+}
 
 // getBalance handles a getbalance request by returning the balance for an
 // account (wallet), or an error if the requested account does not
@@ -660,6 +1539,16 @@ func getBalance(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		accountName = *cmd.Account
 	}
 
+	var fiatPrice float64
+	var fiatCurrency string
+	if cmd.Currency != nil {
+		var err error
+		fiatPrice, fiatCurrency, err = fiatPriceAt(w, *cmd.Currency, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	blockHash, _ := w.MainChainTip()
 	result := hcjson.GetBalanceResult{
 		BlockHash: blockHash.String(),
@@ -705,6 +1594,9 @@ func getBalance(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 				Unconfirmed:             bal.Unconfirmed.ToCoin(),
 				VotingAuthority:         bal.VotingAuthority.ToCoin(),
 			}
+			if cmd.Currency != nil {
+				json.Fiat = fiatValuation(bal.Total, fiatCurrency, fiatPrice)
+			}
 			result.Balances = append(result.Balances, json)
 		}
 
@@ -715,6 +1607,9 @@ func getBalance(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		result.TotalUnconfirmed = totUnconfirmed.ToCoin()
 		result.TotalVotingAuthority = totVotingAuthority.ToCoin()
 		result.CumulativeTotal = cumTot.ToCoin()
+		if cmd.Currency != nil {
+			result.CumulativeFiat = fiatValuation(cumTot, fiatCurrency, fiatPrice)
+		}
 	} else {
 		account, err := w.AccountNumber(accountName)
 		if err != nil {
@@ -735,12 +1630,140 @@ func getBalance(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 			Unconfirmed:             bal.Unconfirmed.ToCoin(),
 			VotingAuthority:         bal.VotingAuthority.ToCoin(),
 		}
+		if cmd.Currency != nil {
+			json.Fiat = fiatValuation(bal.Total, fiatCurrency, fiatPrice)
+		}
 		result.Balances = append(result.Balances, json)
 	}
 
 	return result, nil
 }
 
+// getBalanceAtHash handles a getbalanceathash request by returning the latest
+// snapshot of the account balances at or below the height of a main chain
+// block.
+func getBalanceAtHash(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetBalanceAtHashCmd)
+	blockHash, err := chainhash.NewHashFromStr(cmd.BlockHash)
+	if err != nil {
+		return nil, DeserializationError{err}
+	}
+	snapshot, err := w.BalanceSnapshotAtHash(blockHash)
+	if err != nil {
+		return nil, balanceSnapshotError(err)
+	}
+	return balanceSnapshotResult(w, snapshot, *cmd.Account)
+}
+
+// getBalanceAtHeight handles a getbalanceatheight request by returning the
+// latest snapshot of the account balances at or below a main chain height.
+func getBalanceAtHeight(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetBalanceAtHeightCmd)
+	snapshot, err := w.BalanceSnapshotAtHeight(cmd.Height)
+	if err != nil {
+		return nil, balanceSnapshotError(err)
+	}
+	return balanceSnapshotResult(w, snapshot, *cmd.Account)
+}
+
+// balanceSnapshotError maps errors of balance snapshot queries to JSON-RPC
+// errors.
+func balanceSnapshotError(err error) error {
+	switch {
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrValueNoExists):
+		return &hcjson.RPCError{
+			Code:    hcjson.ErrRPCNoTxInfo,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
+
+// balanceSnapshotResult returns the balances of one account, or all accounts
+// for "*", recorded by a balance snapshot.
+func balanceSnapshotResult(w *wallet.Wallet, snapshot *udb.BalanceSnapshot, accountName string) (*hcjson.GetBalanceAtResult, error) {
+	balances := snapshot.Balances
+	if accountName != "*" {
+		account, err := w.AccountNumber(accountName)
+		if err != nil {
+			return nil, err
+		}
+		balances = []udb.Balances{{Account: account}}
+		for _, bal := range snapshot.Balances {
+			if bal.Account == account {
+				balances[0] = bal
+				break
+			}
+		}
+	}
+
+	result := &hcjson.GetBalanceAtResult{
+		Height:    snapshot.Height,
+		BlockHash: snapshot.BlockHash.String(),
+		Balances:  make([]hcjson.GetAccountBalanceResult, 0, len(balances)),
+	}
+	var (
+		totImmatureCoinbase hcutil.Amount
+		totImmatureStakegen hcutil.Amount
+		totLocked           hcutil.Amount
+		totSpendable        hcutil.Amount
+		totVotingAuthority  hcutil.Amount
+		cumTot              hcutil.Amount
+	)
+	for _, bal := range balances {
+		accountName, err := w.AccountName(bal.Account)
+		if err != nil {
+			return nil, err
+		}
+
+		totImmatureCoinbase += bal.ImmatureCoinbaseRewards
+		totImmatureStakegen += bal.ImmatureStakeGeneration
+		totLocked += bal.LockedByTickets
+		totSpendable += bal.Spendable
+		totVotingAuthority += bal.VotingAuthority
+		cumTot += bal.Total
+
+		result.Balances = append(result.Balances, hcjson.GetAccountBalanceResult{
+			AccountName:             accountName,
+			ImmatureCoinbaseRewards: bal.ImmatureCoinbaseRewards.ToCoin(),
+			ImmatureStakeGeneration: bal.ImmatureStakeGeneration.ToCoin(),
+			LockedByTickets:         bal.LockedByTickets.ToCoin(),
+			Spendable:               bal.Spendable.ToCoin(),
+			Total:                   bal.Total.ToCoin(),
+			VotingAuthority:         bal.VotingAuthority.ToCoin(),
+		})
+	}
+	result.TotalImmatureCoinbaseRewards = totImmatureCoinbase.ToCoin()
+	result.TotalImmatureStakeGeneration = totImmatureStakegen.ToCoin()
+	result.TotalLockedByTickets = totLocked.ToCoin()
+	result.TotalSpendable = totSpendable.ToCoin()
+	result.TotalVotingAuthority = totVotingAuthority.ToCoin()
+	result.CumulativeTotal = cumTot.ToCoin()
+	return result, nil
+}
+
+// fiatPriceAt returns the price of a coin in a currency (or the default
+// currency of the price source when empty) at a time, and the currency of the
+// price.
+func fiatPriceAt(w *wallet.Wallet, currency string, t time.Time) (float64, string, error) {
+	price, currency, err := w.FiatPrice(currency, t)
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return 0, "", InvalidParameterError{err}
+	}
+	return price, currency, err
+}
+
+// fiatValuation returns the valuation of an amount at a fiat price.
+func fiatValuation(amount hcutil.Amount, currency string, price float64) *hcjson.FiatValuationResult {
+	return &hcjson.FiatValuationResult{
+		Currency: currency,
+		Price:    price,
+		Value:    amount.ToCoin() * price,
+	}
+}
+
 // getBestBlock handles a getbestblock request by returning a JSON object
 // with the height and hash of the most recently processed block.
 func getBestBlock(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -767,16 +1790,12 @@ func getBlockCount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 }
 
 // getInfo handles a getinfo request by returning the a structure containing
-// information about the current state of hcwallet.
-// exist.
-func getInfo(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
-	// Call down to hcd for all of the information in this command known
-	// by them.
-	info, err := chainClient.GetInfo()
-	if err != nil {
-		return nil, err
-	}
-
+// information about the current state of hcwallet.  Fields describing the
+// consensus server are only set when hcd is reachable, so the wallet state is
+// returned even while hcd is down.  With the extended result layout, these
+// fields are null when hcd is unreachable, and the wallet sync progress is
+// included.
+func getInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	balances, err := w.CalculateAccountBalances(1)
 	if err != nil {
 		return nil, err
@@ -787,17 +1806,72 @@ func getInfo(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client
 		bal += balance.Spendable
 	}
 
-	info.WalletVersion = udb.DBVersion
-	info.Balance = bal.ToCoin()
-	info.KeypoolOldest = time.Now().Unix()
-	info.KeypoolSize = 0
-	info.PaytxFee = w.RelayFee().ToCoin()
+	// Call down to hcd for the information in this command known by it.
+	var chainInfo *hcjson.InfoWalletResult
+	if chainClient := walletRPCClient(w); chainClient != nil {
+		chainInfo, err = chainClient.GetInfo()
+		if err != nil {
+			log.Debugf("Unable to query hcd for getinfo: %v", err)
+			chainInfo = nil
+		}
+	}
+
+	testNet := w.ChainParams().Net != wire.MainNet
+	_, syncHeight := w.MainChainTip()
+	info := &hcjson.InfoWalletResult{
+		WalletVersion: udb.DBVersion,
+		Balance:       bal.ToCoin(),
+		TestNet:       testNet,
+		KeypoolOldest: time.Now().Unix(),
+		PaytxFee:      w.RelayFee().ToCoin(),
+	}
+	extended := &hcjson.GetInfoResult{
+		WalletVersion: udb.DBVersion,
+		Balance:       bal.ToCoin(),
+		TestNet:       testNet,
+		Unlocked:      !w.Locked(),
+		PaytxFee:      w.RelayFee().ToCoin(),
+		SyncHeight:    syncHeight,
+	}
 	// We don't set the following since they don't make much sense in the
 	// wallet architecture:
 	//  - unlocked_until
 	//  - errors
+	if chainInfo != nil {
+		info.Version = chainInfo.Version
+		info.ProtocolVersion = chainInfo.ProtocolVersion
+		info.Blocks = chainInfo.Blocks
+		info.TimeOffset = chainInfo.TimeOffset
+		info.Connections = chainInfo.Connections
+		info.Proxy = chainInfo.Proxy
+		info.Difficulty = chainInfo.Difficulty
+		info.TestNet = chainInfo.TestNet
+		info.RelayFee = chainInfo.RelayFee
+		info.Errors = chainInfo.Errors
+
+		extended.Version = &chainInfo.Version
+		extended.ProtocolVersion = &chainInfo.ProtocolVersion
+		extended.Blocks = &chainInfo.Blocks
+		extended.TimeOffset = &chainInfo.TimeOffset
+		extended.Connections = &chainInfo.Connections
+		extended.Proxy = &chainInfo.Proxy
+		extended.Difficulty = &chainInfo.Difficulty
+		extended.TestNet = chainInfo.TestNet
+		extended.RelayFee = &chainInfo.RelayFee
+		extended.Errors = &chainInfo.Errors
+		extended.HeaderHeight = &chainInfo.Blocks
+
+		progress := 1.0
+		if chainInfo.Blocks > 0 && syncHeight < chainInfo.Blocks {
+			progress = float64(syncHeight) / float64(chainInfo.Blocks)
+		}
+		extended.Progress = &progress
+	}
 
-	return info, nil
+	return versionedResult{
+		resultVersionLegacy:   info,
+		resultVersionExtended: extended,
+	}, nil
 }
 
 func decodeAddress(s string, params *chaincfg.Params) (hcutil.Address, error) {
@@ -913,6 +1987,42 @@ func getUnconfirmedBalance(icmd interface{}, w *wallet.Wallet) (interface{}, err
 	return (bals.Total - bals.Spendable).ToCoin(), nil
 }
 
+// importAccount handles an importaccount request by recreating an account
+// from its exported extended key and returning the new account number.
+func importAccount(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.ImportAccountCmd)
+
+	// The wildcard * is reserved by the rpc server with the special meaning
+	// of "all accounts", so disallow naming accounts to this string.
+	if cmd.Account == "*" {
+		return nil, &ErrReservedAccountName
+	}
+
+	rescan := true
+	if cmd.Rescan != nil {
+		rescan = *cmd.Rescan
+	}
+
+	scanFrom := int32(0)
+	if cmd.ScanFrom != nil {
+		scanFrom = int32(*cmd.ScanFrom)
+	}
+
+	account, err := w.ImportAccount(cmd.Account, cmd.Key)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+
+	if rescan {
+		w.RescanFromHeight(chainClient, scanFrom)
+	}
+
+	return account, nil
+}
+
 // importPrivKey handles an importprivkey request by parsing
 // a WIF-encoded private key and adding it to an account.
 func importPrivKey(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
@@ -938,79 +2048,294 @@ func importPrivKey(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.
 			Message: "Key is not intended for " + w.ChainParams().Name,
 		}
 	}
-
-	rescan := true
-	if cmd.Rescan != nil {
-		rescan = *cmd.Rescan
+
+	rescan := true
+	if cmd.Rescan != nil {
+		rescan = *cmd.Rescan
+	}
+
+	scanFrom := int32(0)
+	if cmd.ScanFrom != nil {
+		scanFrom = int32(*cmd.ScanFrom)
+	}
+
+	// Import the private key, handling any errors.
+	_, err = w.ImportPrivateKey(wif)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrDuplicateAddress):
+		// Do not return duplicate key errors to the client.
+		return nil, nil
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	}
+
+	if rescan {
+		w.RescanFromHeight(chainClient, scanFrom)
+	}
+
+	return nil, err
+}
+
+// importScript imports a redeem script for a P2SH output.
+func importScript(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.ImportScriptCmd)
+	rs, err := hex.DecodeString(cmd.Hex)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rs) == 0 {
+		return nil, fmt.Errorf("passed empty script")
+	}
+
+	rescan := true
+	if cmd.Rescan != nil {
+		rescan = *cmd.Rescan
+	}
+
+	scanFrom := 0
+	if cmd.ScanFrom != nil {
+		scanFrom = *cmd.ScanFrom
+	}
+
+	err = w.ImportScript(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	if rescan {
+		w.RescanFromHeight(chainClient, int32(scanFrom))
+	}
+
+	return nil, nil
+}
+
+// importVoteChoices handles an importvotechoices request by setting the vote
+// choices of agendas exported by exportvotechoices.  The choices are set
+// together, and none are set if any of them is invalid.
+func importVoteChoices(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ImportVoteChoicesCmd)
+
+	version, _ := wallet.CurrentAgendas(w.ChainParams())
+	if cmd.Version != nil && *cmd.Version != version {
+		return nil, InvalidParameterError{fmt.Errorf("vote choices are "+
+			"for stake version %d, but the latest supported version "+
+			"is %d", *cmd.Version, version)}
+	}
+
+	choices := make([]wallet.AgendaChoice, len(cmd.Choices))
+	for i := range cmd.Choices {
+		choices[i] = wallet.AgendaChoice{
+			AgendaID: cmd.Choices[i].AgendaID,
+			ChoiceID: cmd.Choices[i].ChoiceID,
+		}
+	}
+	_, err := w.SetAgendaChoices(choices...)
+	return nil, err
+}
+
+// keypoolRefill handles the keypoolrefill command. Since we handle the keypool
+// automatically this does nothing since refilling is never manually required.
+func keypoolRefill(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	return nil, nil
+}
+
+// createNewAccount handles a createnewaccount request by creating and
+// returning a new account. If the last account has no transaction history
+// as per BIP 0044 a new account cannot be created so an error will be returned.
+func createNewAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	return nil, fmt.Errorf("not support this function now")
+}
+
+// createExternalBranch handles a createexternalbranch request by creating an
+// additional, named external branch of an account and returning its branch
+// number.
+func createExternalBranch(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.CreateExternalBranchCmd)
+
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	return w.NewExternalBranch(account, cmd.Branch)
+}
+
+// listExternalBranches handles a listexternalbranches request by returning the
+// additional external branches of an account.
+func listExternalBranches(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ListExternalBranchesCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := w.ExternalBranches(account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Child indexes are reported as -1 when no child of the branch has been
+	// used or returned.
+	childIndex := func(child uint32) int64 {
+		if child == ^uint32(0) {
+			return -1
+		}
+		return int64(child)
+	}
+	result := make([]hcjson.ExternalBranchResult, 0, len(branches))
+	for i := range branches {
+		b := &branches[i]
+		result = append(result, hcjson.ExternalBranchResult{
+			Branch:       b.Branch,
+			Name:         b.BranchName,
+			LastUsed:     childIndex(b.LastUsedIndex),
+			LastReturned: childIndex(b.LastReturnedIndex),
+		})
+	}
+	return result, nil
+}
+
+// createMultisigAccount handles a createmultisigaccount request by creating a
+// multisig account shared with cosigners and returning the extended public key
+// of the wallet which the cosigners must record.
+func createMultisigAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.CreateMultisigAccountCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	a, err := w.CreateMultisigAccount(cmd.Name, account, cmd.NRequired,
+		cmd.Cosigners)
+	if err != nil {
+		if apperrors.IsError(err, apperrors.ErrInput) ||
+			apperrors.IsError(err, apperrors.ErrWrongNet) ||
+			apperrors.IsError(err, apperrors.ErrDuplicate) {
+			return nil, InvalidParameterError{err}
+		}
+		return nil, err
+	}
+	info, err := w.MultisigAccountInfo(a.Name)
+	if err != nil {
+		return nil, err
 	}
+	return &hcjson.CreateMultisigAccountResult{
+		Name:     a.Name,
+		Xpub:     info.Xpub,
+		Required: int(a.Required),
+		Keys:     len(a.Cosigners) + 1,
+	}, nil
+}
 
-	scanFrom := int32(0)
-	if cmd.ScanFrom != nil {
-		scanFrom = int32(*cmd.ScanFrom)
-	}
+// getNewMultisigAddress handles a getnewmultisigaddress request by deriving
+// the next P2SH address of a multisig account.
+func getNewMultisigAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetNewMultisigAddressCmd)
 
-	// Import the private key, handling any errors.
-	_, err = w.ImportPrivateKey(wif)
+	addr, err := w.NewMultisigAddress(cmd.Name)
 	switch {
-	case apperrors.IsError(err, apperrors.ErrDuplicateAddress):
-		// Do not return duplicate key errors to the client.
-		return nil, nil
+	case apperrors.IsError(err, apperrors.ErrValueNoExists):
+		return nil, InvalidParameterError{err}
 	case apperrors.IsError(err, apperrors.ErrLocked):
 		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
 	}
-
-	if rescan {
-		w.RescanFromHeight(chainClient, scanFrom)
-	}
-
-	return nil, err
+	return addr.EncodeAddress(), nil
 }
 
-// importScript imports a redeem script for a P2SH output.
-func importScript(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
-	cmd := icmd.(*hcjson.ImportScriptCmd)
-	rs, err := hex.DecodeString(cmd.Hex)
+// getMultisigAccountInfo handles a getmultisigaccountinfo request by returning
+// the keys, addresses, and balance of a multisig account.
+func getMultisigAccountInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetMultisigAccountInfoCmd)
+
+	info, err := w.MultisigAccountInfo(cmd.Name)
 	if err != nil {
+		if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+			return nil, InvalidParameterError{err}
+		}
 		return nil, err
 	}
-
-	if len(rs) == 0 {
-		return nil, fmt.Errorf("passed empty script")
+	acctName, err := w.AccountName(info.Account)
+	if err != nil {
+		return nil, err
 	}
 
-	rescan := true
-	if cmd.Rescan != nil {
-		rescan = *cmd.Rescan
+	result := &hcjson.GetMultisigAccountInfoResult{
+		Name:      info.Name,
+		Account:   acctName,
+		Required:  int(info.Required),
+		Keys:      len(info.Cosigners) + 1,
+		Xpub:      info.Xpub,
+		Cosigners: info.Cosigners,
+		Created:   info.Created.Unix(),
+		Balance:   info.Balance.ToCoin(),
+		Addresses: make([]hcjson.MultisigAccountAddressResult, 0, len(info.Addresses)),
+	}
+	for i := range info.Addresses {
+		a := &info.Addresses[i]
+		result.Addresses = append(result.Addresses, hcjson.MultisigAccountAddressResult{
+			Index:        a.Index,
+			Address:      a.Address.EncodeAddress(),
+			RedeemScript: hex.EncodeToString(a.RedeemScript),
+			Balance:      a.Balance.ToCoin(),
+		})
 	}
+	return result, nil
+}
 
-	scanFrom := 0
-	if cmd.ScanFrom != nil {
-		scanFrom = *cmd.ScanFrom
-	}
+// createMultisigSpend handles a createmultisigspend request by creating a
+// transaction spending the outputs of a multisig account, signed with the
+// wallet's keys.  Transactions which are not complete must be signed by
+// further cosigners using signrawtransaction.
+func createMultisigSpend(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.CreateMultisigSpendCmd)
 
-	err = w.ImportScript(rs)
+	pairs := make(map[string]hcutil.Amount, len(cmd.Amounts))
+	for k, v := range cmd.Amounts {
+		amt, err := hcutil.NewAmount(v)
+		if err != nil {
+			return nil, err
+		}
+		if amt <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		pairs[k] = amt
+	}
+	if len(pairs) == 0 {
+		return nil, InvalidParameterError{errors.New("no outputs")}
+	}
+	outputs, err := makeOutputs(pairs, w.ChainParams())
 	if err != nil {
 		return nil, err
 	}
 
-	if rescan {
-		w.RescanFromHeight(chainClient, int32(scanFrom))
+	spend, err := w.CreateMultisigSpend(cmd.Name, outputs)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrValueNoExists):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWalletInsufficientFunds,
+			Message: err.Error(),
+		}
+	case err != nil:
+		return nil, err
 	}
 
-	return nil, nil
-}
-
-// keypoolRefill handles the keypoolrefill command. Since we handle the keypool
-// automatically this does nothing since refilling is never manually required.
-func keypoolRefill(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	return nil, nil
-}
-
-// createNewAccount handles a createnewaccount request by creating and
-// returning a new account. If the last account has no transaction history
-// as per BIP 0044 a new account cannot be created so an error will be returned.
-func createNewAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	return nil, fmt.Errorf("not support this function now")
+	var buf bytes.Buffer
+	buf.Grow(spend.Tx.SerializeSize())
+	err = spend.Tx.Serialize(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.CreateMultisigSpendResult{
+		Hex:      hex.EncodeToString(buf.Bytes()),
+		Fee:      spend.Fee.ToCoin(),
+		Complete: spend.Complete,
+	}, nil
 }
 
 // renameAccount handles a renameaccount request by renaming an account.
@@ -1121,6 +2446,13 @@ func getNewAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cmd.Branch != nil && *cmd.Branch != "" {
+		branch, err := w.ExternalBranchNumber(account, *cmd.Branch)
+		if err != nil {
+			return nil, err
+		}
+		callOpts = append(callOpts, wallet.WithExternalBranch(branch))
+	}
 
 	addr, err := w.NewExternalAddress(account, callOpts...)
 	if err != nil {
@@ -1215,13 +2547,92 @@ func getMasterPubkey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return w.MasterPubKey(account)
 }
 
+// getRecoveryState handles a getrecoverystate request by returning how many
+// automatic rescans were started after errors processing notifications, and
+// the most recent errors which caused them.
+func getRecoveryState(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	state := w.RecoveryState()
+	events := make([]hcjson.RecoveryEventResult, 0, len(state.Events))
+	for i := range state.Events {
+		e := &state.Events[i]
+		events = append(events, hcjson.RecoveryEventResult{
+			Time:         e.Time.Unix(),
+			Notification: e.Notification,
+			Error:        e.Error,
+			Transient:    e.Transient,
+			Action:       string(e.Action),
+		})
+	}
+	return &hcjson.GetRecoveryStateResult{
+		AutoRescans: state.Rescans,
+		Errors:      state.Errors,
+		Backoff:     int64(state.Backoff / time.Second),
+		Pending:     state.Pending,
+		Halted:      state.Halted,
+		Events:      events,
+	}, nil
+}
+
+// getRescanInfo handles a getrescaninfo request by returning whether a rescan
+// is running, the configured rescan limits, and whether they are currently
+// holding the rescan back.
+func getRescanInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	limits := w.RescanLimits()
+	state := w.RescanState()
+	return &hcjson.GetRescanInfoResult{
+		Scanning:        state.Scanning,
+		BlocksPerSecond: limits.BlocksPerSecond,
+		PauseRPCLoad:    limits.PauseRPCLoad,
+		RPCLoad:         w.RPCLoad(),
+		RateLimited:     state.RateLimited,
+		Paused:          state.Paused,
+		BlocksPerBatch:  limits.BlocksPerBatch,
+		AdaptiveBatches: limits.AdaptiveBatches,
+		BatchSize:       state.BatchSize,
+	}, nil
+}
+
+// getSpendableConfs handles a getspendableconfs request by returning the
+// confirmations an account requires before its outputs are spendable.
+func getSpendableConfs(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetSpendableConfsCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	confs, err := w.AccountSpendableConfs(account)
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.GetSpendableConfsResult{
+		Account:          *cmd.Account,
+		Regular:          confs.Regular,
+		Coinbase:         confs.Coinbase,
+		CoinbaseMaturity: confs.CoinbaseMaturity(w.ChainParams()),
+	}, nil
+}
+
 // getStakeInfo gets a large amounts of information about the stake environment
 // and a number of statistics about local staking in the wallet.
 func getStakeInfo(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetStakeInfoCmd)
+
 	// Asynchronously query for the stake difficulty.
 	sdiffFuture := chainClient.GetStakeDifficultyAsync()
 
-	stakeInfo, err := w.StakeInfo(chainClient)
+	var stakeInfo *wallet.StakeInfoData
+	var err error
+	if cmd.Account != nil {
+		var account uint32
+		account, err = w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+		stakeInfo, err = w.AccountStakeInfo(chainClient, account)
+	} else {
+		stakeInfo, err = w.StakeInfo(chainClient)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1257,10 +2668,57 @@ func getStakeInfo(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.C
 		Revoked:          stakeInfo.Revoked,
 		Expired:          stakeInfo.Expired,
 	}
+	voteVersionStatus := w.VoteVersionStatus()
+	resp.NetworkStakeVersion = voteVersionStatus.NetworkVersion
+	resp.VoteVersionOutdated = voteVersionStatus.Outdated
 
 	return resp, nil
 }
 
+// getStakeRewards handles a getstakerewards request by returning the votes,
+// missed tickets, revocations and subsidy of wallet tickets grouped by period.
+func getStakeRewards(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.GetStakeRewardsCmd)
+
+	var period wallet.StakeRewardPeriod
+	switch *cmd.Period {
+	case "day":
+		period = wallet.StakeRewardsDaily
+	case "week":
+		period = wallet.StakeRewardsWeekly
+	case "month":
+		period = wallet.StakeRewardsMonthly
+	default:
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("Unknown period '%s'", *cmd.Period),
+		}
+	}
+	var since, until time.Time
+	if cmd.Since != nil {
+		since = time.Unix(*cmd.Since, 0)
+	}
+	if cmd.Until != nil {
+		until = time.Unix(*cmd.Until, 0)
+	}
+
+	rewards, err := w.StakeRewards(period, since, until)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]hcjson.GetStakeRewardsResult, len(rewards))
+	for i, r := range rewards {
+		res[i] = hcjson.GetStakeRewardsResult{
+			Start:        r.Start.Unix(),
+			Voted:        r.Voted,
+			Missed:       r.Missed,
+			Revoked:      r.Revoked,
+			TotalSubsidy: r.TotalSubsidy.ToCoin(),
+		}
+	}
+	return res, nil
+}
+
 // getTicketFee gets the currently set price per kb for tickets
 func getTicketFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return w.TicketFeeIncrement().ToCoin(), nil
@@ -1308,20 +2766,24 @@ func getTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 
 	_, tipHeight := w.MainChainTip()
 
-	// TODO: The serialized transaction is already in the DB, so
-	// reserializing can be avoided here.
-	var txBuf bytes.Buffer
-	txBuf.Grow(txd.MsgTx.SerializeSize())
-	err = txd.MsgTx.Serialize(&txBuf)
-	if err != nil {
-		return nil, err
+	// The serialized transaction is read from the DB by TxDetails, so it
+	// only needs to be reserialized if it is missing.
+	serializedTx := txd.SerializedTx
+	if serializedTx == nil {
+		var txBuf bytes.Buffer
+		txBuf.Grow(txd.MsgTx.SerializeSize())
+		err = txd.MsgTx.Serialize(&txBuf)
+		if err != nil {
+			return nil, err
+		}
+		serializedTx = txBuf.Bytes()
 	}
 
 	// TODO: Add a "generated" field to this result type.  "generated":true
 	// is only added if the transaction is a coinbase.
 	ret := hcjson.GetTransactionResult{
 		TxID:            cmd.Txid,
-		Hex:             hex.EncodeToString(txBuf.Bytes()),
+		Hex:             hex.EncodeToString(serializedTx),
 		Time:            txd.Received.Unix(),
 		TimeReceived:    txd.Received.Unix(),
 		WalletConflicts: []string{}, // Not saved
@@ -1361,6 +2823,18 @@ func getTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	ret.Amount = (creditTotal - debitTotal).ToCoin()
 	ret.Fee = negFeeF64
 
+	if cmd.Currency != nil {
+		t := txd.Received
+		if txd.Block.Height != -1 {
+			t = txd.Block.Time
+		}
+		price, currency, err := fiatPriceAt(w, *cmd.Currency, t)
+		if err != nil {
+			return nil, err
+		}
+		ret.Fiat = fiatValuation(creditTotal-debitTotal, currency, price)
+	}
+
 	details, err := w.ListTransactionDetails(txSha)
 	if err != nil {
 		return nil, err
@@ -1378,6 +2852,14 @@ func getTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
+	if *cmd.Verbose {
+		atx, err := w.AnnotateRecordedTransaction(txd)
+		if err != nil {
+			return nil, err
+		}
+		ret.Vin, ret.Vout = walletTxInputsOutputs(atx)
+	}
+
 	return ret, nil
 }
 
@@ -1544,14 +3026,17 @@ func listLockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 
 // listReceivedByAccount handles a listreceivedbyaccount request by returning
 // a slice of objects, each one containing:
-//  "account": the receiving account;
-//  "amount": total amount received by the account;
-//  "confirmations": number of confirmations of the most recent transaction.
+//
+//	"account": the receiving account;
+//	"amount": total amount received by the account;
+//	"confirmations": number of confirmations of the most recent transaction.
+//
 // It takes two parameters:
-//  "minconf": minimum number of confirmations to consider a transaction -
-//             default: one;
-//  "includeempty": whether or not to include addresses that have no transactions -
-//                  default: false.
+//
+//	"minconf": minimum number of confirmations to consider a transaction -
+//	           default: one;
+//	"includeempty": whether or not to include addresses that have no transactions -
+//	                default: false.
 func listReceivedByAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*hcjson.ListReceivedByAccountCmd)
 
@@ -1573,15 +3058,18 @@ func listReceivedByAccount(icmd interface{}, w *wallet.Wallet) (interface{}, err
 
 // listReceivedByAddress handles a listreceivedbyaddress request by returning
 // a slice of objects, each one containing:
-//  "account": the account of the receiving address;
-//  "address": the receiving address;
-//  "amount": total amount received by the address;
-//  "confirmations": number of confirmations of the most recent transaction.
+//
+//	"account": the account of the receiving address;
+//	"address": the receiving address;
+//	"amount": total amount received by the address;
+//	"confirmations": number of confirmations of the most recent transaction.
+//
 // It takes two parameters:
-//  "minconf": minimum number of confirmations to consider a transaction -
-//             default: one;
-//  "includeempty": whether or not to include addresses that have no transactions -
-//                  default: false.
+//
+//	"minconf": minimum number of confirmations to consider a transaction -
+//	           default: one;
+//	"includeempty": whether or not to include addresses that have no transactions -
+//	                default: false.
 func listReceivedByAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*hcjson.ListReceivedByAddressCmd)
 
@@ -1595,62 +3083,24 @@ func listReceivedByAddress(icmd interface{}, w *wallet.Wallet) (interface{}, err
 		tx []string
 	}
 
-	_, tipHeight := w.MainChainTip()
-
-	// Intermediate data for all addresses.
-	allAddrData := make(map[string]AddrData)
-	// Create an AddrData entry for each active address in the account.
-	// Otherwise we'll just get addresses from transactions later.
-	sortedAddrs, err := w.SortedActivePaymentAddresses()
+	minConf := int32(*cmd.MinConf)
+	received, err := w.ReceivedByAddresses(minConf)
 	if err != nil {
 		return nil, err
 	}
-	for _, address := range sortedAddrs {
-		// There might be duplicates, just overwrite them.
-		allAddrData[address] = AddrData{}
-	}
 
-	minConf := *cmd.MinConf
-	var endHeight int32
-	if minConf == 0 {
-		endHeight = -1
-	} else {
-		endHeight = tipHeight - int32(minConf) + 1
-	}
-	err = wallet.UnstableAPI(w).RangeTransactions(0, endHeight, func(details []udb.TxDetails) (bool, error) {
-		confirmations := confirms(details[0].Block.Height, tipHeight)
-		for _, tx := range details {
-			for _, cred := range tx.Credits {
-				pkVersion := tx.MsgTx.TxOut[cred.Index].Version
-				pkScript := tx.MsgTx.TxOut[cred.Index].PkScript
-				_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkVersion,
-					pkScript, w.ChainParams())
-				if err != nil {
-					// Non standard script, skip.
-					continue
-				}
-				for _, addr := range addrs {
-					addrStr := addr.EncodeAddress()
-					addrData, ok := allAddrData[addrStr]
-					if ok {
-						addrData.amount += cred.Amount
-						// Always overwrite confirmations with newer ones.
-						addrData.confirmations = confirmations
-					} else {
-						addrData = AddrData{
-							amount:        cred.Amount,
-							confirmations: confirmations,
-						}
-					}
-					addrData.tx = append(addrData.tx, tx.Hash.String())
-					allAddrData[addrStr] = addrData
-				}
-			}
+	// Intermediate data for all addresses.
+	allAddrData := make(map[string]AddrData, len(received))
+	for address, r := range received {
+		txIDs := make([]string, len(r.TxHashes))
+		for i := range r.TxHashes {
+			txIDs[i] = r.TxHashes[i].String()
+		}
+		allAddrData[address] = AddrData{
+			amount:        r.Amount,
+			confirmations: r.Confirmations,
+			tx:            txIDs,
 		}
-		return false, nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	// Massage address data into output format.
@@ -1666,6 +3116,19 @@ func listReceivedByAddress(icmd interface{}, w *wallet.Wallet) (interface{}, err
 		}
 		idx++
 	}
+	if w.FlagReusedAddresses() {
+		addrs := make([]string, len(ret))
+		for i := range ret {
+			addrs[i] = ret[i].Address
+		}
+		reused, err := w.ReusedAddresses(addrs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ret {
+			_, ret[i].Reused = reused[ret[i].Address]
+		}
+	}
 	return ret, nil
 }
 
@@ -1695,7 +3158,16 @@ func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient
 		start = int32(block.Height) + 1
 	}
 
-	txInfoList, err := w.ListSinceBlock(start, -1, tipHeight)
+	var account *uint32
+	if cmd.Account != nil {
+		acct, err := w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+		account = &acct
+	}
+
+	txInfoList, err := w.ListSinceBlock(start, -1, tipHeight, account)
 	if err != nil {
 		return nil, err
 	}
@@ -1713,6 +3185,39 @@ func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient
 	return res, nil
 }
 
+// listRefundableScripts handles a listrefundablescripts request by returning
+// the imported scripts with timelocked refund paths paying wallet keys and
+// their unspent outputs.
+func listRefundableScripts(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	scripts, err := w.RefundableScripts()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]hcjson.RefundableScriptResult, 0, len(scripts))
+	for _, s := range scripts {
+		outputs := make([]hcjson.RefundableOutputResult, 0, len(s.Outputs))
+		for _, out := range s.Outputs {
+			outputs = append(outputs, hcjson.RefundableOutputResult{
+				TxID:        out.OutPoint.Hash.String(),
+				Vout:        out.OutPoint.Index,
+				Tree:        out.OutPoint.Tree,
+				Amount:      out.Amount.ToCoin(),
+				BlockHeight: out.BlockHeight,
+				Matured:     out.Matured,
+			})
+		}
+		result = append(result, hcjson.RefundableScriptResult{
+			Address:       s.Address.EncodeAddress(),
+			RedeemScript:  hex.EncodeToString(s.Script),
+			RefundAddress: s.RefundAddress.EncodeAddress(),
+			LockTime:      s.LockTime,
+			Relative:      s.Relative,
+			Outputs:       outputs,
+		})
+	}
+	return result, nil
+}
+
 // listScripts handles a listscripts request by returning an
 // array of script details for all scripts in the wallet.
 func listScripts(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -1736,6 +3241,49 @@ func listScripts(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return &hcjson.ListScriptsResult{Scripts: listScriptsResultSIs}, nil
 }
 
+// listPoolFeeExemptions handles a listpoolfeeexemptions request by
+// returning the stake pool users exempt from the pool fee check.
+func listPoolFeeExemptions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	users, err := w.StakePoolFeeExemptions()
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, len(users))
+	for i, user := range users {
+		addrs[i] = user.EncodeAddress()
+	}
+	return addrs, nil
+}
+
+// listStaleOmniPending handles a liststaleomnipending request by returning
+// the pending omni entries added by the wallet which should no longer be
+// pending.
+func listStaleOmniPending(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ListStaleOmniPendingCmd)
+
+	expiry := w.OmniPendingExpiry()
+	if cmd.Expiry != nil {
+		if *cmd.Expiry < 0 {
+			return nil, InvalidParameterError{errors.New("expiry may not be negative")}
+		}
+		expiry = *cmd.Expiry
+	}
+
+	stale, err := w.StaleOmniPending(expiry)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]hcjson.StaleOmniPendingResult, len(stale))
+	for i := range stale {
+		res[i] = hcjson.StaleOmniPendingResult{
+			TxID:   stale[i].Hash.String(),
+			Height: stale[i].Height,
+			Reason: stale[i].Reason,
+		}
+	}
+	return res, nil
+}
+
 // listTransactions handles a listtransactions request by returning an
 // array of maps with details of sent and recevied wallet transactions.
 func listTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -1755,9 +3303,41 @@ func listTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
+	if cmd.Cursor != nil {
+		if *cmd.From != 0 {
+			e := errors.New("from may not be combined with cursor")
+			return nil, InvalidParameterError{e}
+		}
+		return listTransactionsPage(w, *cmd.Cursor, *cmd.Count)
+	}
+
 	return w.ListTransactions(*cmd.From, *cmd.Count)
 }
 
+// listTransactionsPage returns the listtransactions results of at most count
+// transactions following an opaque cursor, or of the newest transactions when
+// the cursor is empty, with the cursor of the following page.
+func listTransactionsPage(w *wallet.Wallet, cursor string, count int) (*hcjson.ListTransactionsPageResult, error) {
+	var after *wallet.TxCursor
+	if cursor != "" {
+		var err error
+		after, err = wallet.ParseTxCursor(cursor)
+		if err != nil {
+			return nil, InvalidParameterError{err}
+		}
+	}
+	it := w.IterateTransactions(after)
+	txs, err := it.Next(count)
+	if err != nil {
+		return nil, err
+	}
+	result := &hcjson.ListTransactionsPageResult{Transactions: txs}
+	if !it.Done() && it.Cursor() != nil {
+		result.Cursor = it.Cursor().String()
+	}
+	return result, nil
+}
+
 // listAddressTransactions handles a listaddresstransactions request by
 // returning an array of maps with details of spent and received wallet
 // transactions.  The form of the reply is identical to listtransactions,
@@ -1775,16 +3355,16 @@ func listAddressTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, e
 	}
 
 	// Decode addresses.
-	hash160Map := make(map[string]struct{})
+	addrs := make([]hcutil.Address, 0, len(cmd.Addresses))
 	for _, addrStr := range cmd.Addresses {
 		addr, err := decodeAddress(addrStr, w.ChainParams())
 		if err != nil {
 			return nil, err
 		}
-		hash160Map[string(addr.ScriptAddress())] = struct{}{}
+		addrs = append(addrs, addr)
 	}
 
-	return w.ListAddressTransactions(hash160Map)
+	return w.ListAddressTransactions(addrs)
 }
 
 // listAllTransactions handles a listalltransactions request by returning
@@ -1801,6 +3381,10 @@ func listAllTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error
 		}
 	}
 
+	if cmd.Cursor != nil {
+		return listTransactionsPage(w, *cmd.Cursor, *cmd.Count)
+	}
+
 	return w.ListAllTransactions()
 }
 
@@ -1820,8 +3404,66 @@ func listUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 			addresses[a.EncodeAddress()] = struct{}{}
 		}
 	}
-
-	return w.ListUnspent(int32(*cmd.MinConf), int32(*cmd.MaxConf), addresses)
+
+	if cmd.Cursor != nil {
+		var after *wallet.UnspentCursor
+		if *cmd.Cursor != "" {
+			var err error
+			after, err = wallet.ParseUnspentCursor(*cmd.Cursor)
+			if err != nil {
+				return nil, InvalidParameterError{err}
+			}
+		}
+		it := w.IterateUnspent(int32(*cmd.MinConf), int32(*cmd.MaxConf),
+			addresses, *cmd.IncludeLocked, after)
+		results, err := it.Next(*cmd.Count)
+		if err != nil {
+			return nil, err
+		}
+		err = flagReusedUnspent(w, results)
+		if err != nil {
+			return nil, err
+		}
+		result := &hcjson.ListUnspentPageResult{Unspent: results}
+		if result.Unspent == nil {
+			result.Unspent = []*hcjson.ListUnspentResult{}
+		}
+		if !it.Done() && it.Cursor() != nil {
+			result.Cursor = it.Cursor().String()
+		}
+		return result, nil
+	}
+
+	results, err := w.ListUnspent(int32(*cmd.MinConf), int32(*cmd.MaxConf), addresses,
+		*cmd.IncludeLocked)
+	if err != nil {
+		return nil, err
+	}
+	err = flagReusedUnspent(w, results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// flagReusedUnspent sets the reused field of listunspent results paying
+// reused addresses when reused addresses are flagged.
+func flagReusedUnspent(w *wallet.Wallet, results []*hcjson.ListUnspentResult) error {
+	if !w.FlagReusedAddresses() {
+		return nil
+	}
+	addrs := make([]string, len(results))
+	for i, r := range results {
+		addrs[i] = r.Address
+	}
+	reused, err := w.ReusedAddresses(addrs)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		_, r.Reused = reused[r.Address]
+	}
+	return nil
 }
 
 // lockUnspent handles the lockunspent command.
@@ -1848,6 +3490,35 @@ func lockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return true, nil
 }
 
+// pruneWalletHistory handles a prunewallethistory request by removing the
+// records of fully spent regular transactions mined below a depth.
+func pruneWalletHistory(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.PruneWalletHistoryCmd)
+
+	depth := w.HistoryPruneDepth()
+	if cmd.Depth != nil {
+		depth = *cmd.Depth
+	}
+	if depth == 0 {
+		return nil, InvalidParameterError{errors.New("no prune depth " +
+			"specified or configured")}
+	}
+
+	cp, err := w.PruneHistory(depth)
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.PruneWalletHistoryResult{
+		Height:       cp.Height,
+		Transactions: cp.Transactions,
+		Credits:      cp.Credits.ToCoin(),
+		Debits:       cp.Debits.ToCoin(),
+	}, nil
+}
+
 // purchaseTicket indicates to the wallet that a ticket should be purchased
 // using all currently available funds. If the ticket could not be purchased
 // because there are not enough eligible funds, an error will be returned.
@@ -1938,19 +3609,29 @@ func purchaseTicket(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
-	hashes, err := w.PurchaseTickets(0, spendLimit, minConf, ticketAddr,
+	res, err := w.PurchaseTickets(0, spendLimit, minConf, ticketAddr,
 		account, numTickets, poolAddr, poolFee, expiry, w.RelayFee(),
 		ticketFee)
 	if err != nil {
 		return nil, err
 	}
 
-	hashStrs := make([]string, len(hashes))
-	for i := range hashes {
-		hashStrs[i] = hashes[i].String()
+	result := &hcjson.PurchaseTicketResult{
+		SplitTx:    res.SplitTx.String(),
+		Tickets:    make([]string, len(res.TicketHashes)),
+		Failures:   make([]hcjson.PurchaseTicketFailure, len(res.Failures)),
+		TotalSpent: res.TotalSpent.ToCoin(),
 	}
-
-	return hashStrs, err
+	for i, hash := range res.TicketHashes {
+		result.Tickets[i] = hash.String()
+	}
+	for i, f := range res.Failures {
+		result.Failures[i] = hcjson.PurchaseTicketFailure{
+			Index: f.Index,
+			Error: f.Err.Error(),
+		}
+	}
+	return result, nil
 }
 
 // makeOutputs creates a slice of transaction outputs from a pair of address
@@ -1975,6 +3656,35 @@ func makeOutputs(pairs map[string]hcutil.Amount, chainParams *chaincfg.Params) (
 	return outputs, nil
 }
 
+// sendIdempotent calls send, unless idempotencyKey is set and a transaction
+// was already sent with the key, in which case the hash of the original
+// transaction is returned instead of sending another.  The key is recorded
+// with the JSON encoding of params, which describes the request, and may not
+// be reused for requests with different params.
+func sendIdempotent(w *wallet.Wallet, idempotencyKey *string, params []interface{}, send func() (string, error)) (string, error) {
+	if idempotencyKey == nil {
+		return send()
+	}
+	serializedParams, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	txHash, err := w.SendIdempotent(*idempotencyKey, serializedParams, func() (*chainhash.Hash, error) {
+		txid, err := send()
+		if err != nil {
+			return nil, err
+		}
+		return chainhash.NewHashFromStr(txid)
+	})
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return "", InvalidParameterError{err}
+	}
+	if err != nil {
+		return "", err
+	}
+	return txHash.String(), nil
+}
+
 // sendPairs creates and sends payment transactions.
 // It returns the transaction hash in string format upon success
 // All errors are returned in hcjson.RPCError format
@@ -1985,16 +3695,16 @@ func sendPairs(w *wallet.Wallet, amounts map[string]hcutil.Amount,
 		return "", err
 	}
 
+	var opts []wallet.SendOutputsCallOption
 	if len(payLoad) > 0 {
-		payloadOutput, err := w.MakeNulldataOutput(payLoad)
-		if err != nil {
-			return "", err
-		}
-		outputs = append(outputs, payloadOutput)
+		opts = append(opts, wallet.WithNulldata(payLoad))
 	}
 
-	txSha, err := w.SendOutputs(outputs, account, minconf, changeAddr, fromAddress)
+	txSha, err := w.SendOutputs(outputs, account, minconf, changeAddr, fromAddress, opts...)
 	if err != nil {
+		if apperrors.IsError(err, apperrors.ErrInput) {
+			return "", InvalidParameterError{err}
+		}
 		if err == txrules.ErrAmountNegative {
 			return "", ErrNeedPositiveAmount
 		}
@@ -2037,160 +3747,569 @@ func redeemMultiSigOut(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccli
 		account := uint32(udb.DefaultAccountNum)
 		addr, err = w.NewInternalAddress(account, wallet.WithGapPolicyWrap())
 		if err != nil {
-			return nil, err
+			return nil, err
+		}
+	}
+
+	// Lookup the multisignature output and get the amount
+	// along with the script for that transaction. Then,
+	// begin crafting a MsgTx.
+	hash, err := chainhash.NewHashFromStr(cmd.Hash)
+	if err != nil {
+		return nil, err
+	}
+	op := wire.OutPoint{
+		Hash:  *hash,
+		Index: cmd.Index,
+		Tree:  cmd.Tree,
+	}
+	p2shOutput, err := w.FetchP2SHMultiSigOutput(&op)
+	if err != nil {
+		return nil, err
+	}
+	sc := txscript.GetScriptClass(txscript.DefaultScriptVersion,
+		p2shOutput.RedeemScript)
+	if sc != txscript.MultiSigTy {
+		return nil, fmt.Errorf("invalid P2SH script: not multisig")
+	}
+	var msgTx wire.MsgTx
+	msgTx.AddTxIn(wire.NewTxIn(&op, nil))
+
+	// Calculate the fees required, and make sure we have enough.
+	// Then produce the txout.
+	account := uint32(udb.DefaultAccountNum)
+	size := wallet.EstimateTxSize(1, 1, account)
+	feeEst := wallet.FeeForSize(w.RelayFee(), size)
+	if feeEst >= p2shOutput.OutputAmount {
+		return nil, fmt.Errorf("multisig out amt is too small "+
+			"(have %v, %v fee suggested)", p2shOutput.OutputAmount, feeEst)
+	}
+	toReceive := p2shOutput.OutputAmount - feeEst
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create txout script: %s", err)
+	}
+	msgTx.AddTxOut(wire.NewTxOut(int64(toReceive), pkScript))
+
+	// Start creating the SignRawTransactionCmd.
+	outpointScript, err := txscript.PayToScriptHashScript(p2shOutput.P2SHAddress.Hash160()[:])
+	if err != nil {
+		return nil, err
+	}
+	outpointScriptStr := hex.EncodeToString(outpointScript)
+
+	rti := hcjson.RawTxInput{
+		Txid:         cmd.Hash,
+		Vout:         cmd.Index,
+		Tree:         cmd.Tree,
+		ScriptPubKey: outpointScriptStr,
+		RedeemScript: "",
+	}
+	rtis := []hcjson.RawTxInput{rti}
+
+	var buf bytes.Buffer
+	buf.Grow(msgTx.SerializeSize())
+	if err = msgTx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	txDataStr := hex.EncodeToString(buf.Bytes())
+	sigHashAll := "ALL"
+
+	srtc := &hcjson.SignRawTransactionCmd{
+		RawTx:    txDataStr,
+		Inputs:   &rtis,
+		PrivKeys: &[]string{},
+		Flags:    &sigHashAll,
+	}
+
+	// Sign it and give the results to the user.
+	signedTxResult, err := signRawTransaction(srtc, w, chainClient)
+	if signedTxResult == nil || err != nil {
+		return nil, err
+	}
+	srtTyped := signedTxResult.(hcjson.SignRawTransactionResult)
+	return hcjson.RedeemMultiSigOutResult{
+		Hex:      srtTyped.Hex,
+		Complete: srtTyped.Complete,
+		Errors:   srtTyped.Errors,
+	}, nil
+}
+
+// redeemMultisigOuts receives a script hash (in the form of a
+// script hash address), looks up all the unspent outpoints associated
+// with that address, then generates a list of partially signed
+// transactions spending to either an address specified or internal
+// addresses in this wallet.
+func redeemMultiSigOuts(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.RedeemMultiSigOutsCmd)
+
+	// Get all the multisignature outpoints that are unspent for this
+	// address.
+	addr, err := decodeAddress(cmd.FromScrAddress, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	p2shAddr, ok := addr.(*hcutil.AddressScriptHash)
+	if !ok {
+		return nil, errors.New("address is not P2SH")
+	}
+	msos, err := wallet.UnstableAPI(w).UnspentMultisigCreditsForAddress(p2shAddr)
+	if err != nil {
+		return nil, err
+	}
+	max := uint32(0xffffffff)
+	if cmd.Number != nil {
+		max = uint32(*cmd.Number)
+	}
+
+	itr := uint32(0)
+	len := math.Min(float64(max), float64(len(msos)))
+	rmsoResults := make([]hcjson.RedeemMultiSigOutResult, int(len))
+	for i, mso := range msos {
+		if itr >= max {
+			break
+		}
+
+		rmsoRequest := &hcjson.RedeemMultiSigOutCmd{
+			Hash:    mso.OutPoint.Hash.String(),
+			Index:   mso.OutPoint.Index,
+			Tree:    mso.OutPoint.Tree,
+			Address: cmd.ToAddress,
+		}
+		redeemResult, err := redeemMultiSigOut(rmsoRequest, w, chainClient)
+		if err != nil {
+			return nil, err
+		}
+		redeemResultTyped := redeemResult.(hcjson.RedeemMultiSigOutResult)
+		rmsoResults[i] = redeemResultTyped
+
+		itr++
+	}
+
+	return hcjson.RedeemMultiSigOutsResult{Results: rmsoResults}, nil
+}
+
+// reevaluatePoolTickets handles a reevaluatepooltickets request by
+// evaluating the rejected tickets of a stake pool user again, returning the
+// hashes of the readmitted tickets.
+func reevaluatePoolTickets(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ReevaluatePoolTicketsCmd)
+
+	userAddr, err := decodeAddress(cmd.User, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	readmitted, err := w.ReevaluateStakePoolTickets(userAddr)
+	if apperrors.IsError(err, apperrors.ErrInput) ||
+		apperrors.IsError(err, apperrors.ErrBadPoolUserAddr) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(readmitted))
+	for i := range readmitted {
+		hashes[i] = readmitted[i].String()
+	}
+	return hashes, nil
+}
+
+// refundScript handles a refundscript request by spending the matured outputs
+// of a refundable script by its timelocked refund path.
+func refundScript(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.RefundScriptCmd)
+
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	p2shAddr, ok := addr.(*hcutil.AddressScriptHash)
+	if !ok {
+		return nil, InvalidParameterError{errors.New("address is not a P2SH address")}
+	}
+	var to hcutil.Address
+	if cmd.ToAddress != nil {
+		to, err = decodeAddress(*cmd.ToAddress, w.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	txHash, err := w.RefundScript(p2shAddr, to)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrValueNoExists),
+		apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+	return txHash.String(), nil
+}
+
+// swapContractResult returns the result of the initiateswap and
+// participateswap requests.
+func swapContractResult(c *wallet.AtomicSwapContract) (*hcjson.AtomicSwapContractResult, error) {
+	var buf bytes.Buffer
+	buf.Grow(c.ContractTx.SerializeSize())
+	err := c.ContractTx.Serialize(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.AtomicSwapContractResult{
+		Contract:     hex.EncodeToString(c.Contract),
+		ContractP2SH: c.Address.EncodeAddress(),
+		ContractTx:   hex.EncodeToString(buf.Bytes()),
+		ContractTxID: c.ContractTx.TxHash().String(),
+		Vout:         c.ContractOut,
+		SecretHash:   hex.EncodeToString(c.SecretHash),
+		Secret:       hex.EncodeToString(c.Secret),
+		LockTime:     c.LockTime,
+	}, nil
+}
+
+// swapFundingParams parses the common parameters of the initiateswap and
+// participateswap requests.
+func swapFundingParams(w *wallet.Wallet, address string, amount float64,
+	accountName string, lockTime *int64, defaultLock time.Duration) (hcutil.Address, hcutil.Amount, uint32, int64, error) {
+
+	addr, err := decodeAddress(address, w.ChainParams())
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	amt, err := hcutil.NewAmount(amount)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if amt <= 0 {
+		return nil, 0, 0, 0, ErrNeedPositiveAmount
+	}
+	account, err := w.AccountNumber(accountName)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	lock := time.Now().Add(defaultLock).Unix()
+	if lockTime != nil {
+		lock = *lockTime
+	}
+	return addr, amt, account, lock, nil
+}
+
+// swapError maps errors of the atomic swap methods to RPC errors.
+func swapError(err error) error {
+	if _, ok := err.(txauthor.InsufficientFundsError); ok {
+		return &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWalletInsufficientFunds,
+			Message: err.Error(),
+		}
+	}
+	switch {
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return &ErrWalletUnlockNeeded
+	}
+	return err
+}
+
+// initiateSwap handles an initiateswap request by generating a secret and
+// funding an atomic swap contract paying the participant.
+func initiateSwap(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.InitiateSwapCmd)
+
+	addr, amount, account, lockTime, err := swapFundingParams(w, cmd.Address,
+		cmd.Amount, *cmd.Account, cmd.LockTime, wallet.DefaultInitiatorLockTime)
+	if err != nil {
+		return nil, err
+	}
+	c, err := w.InitiateSwap(account, addr, amount, lockTime)
+	if err != nil {
+		return nil, swapError(err)
+	}
+	return swapContractResult(c)
+}
+
+// participateSwap handles a participateswap request by funding an atomic swap
+// contract paying the initiator with the initiator's secret hash.
+func participateSwap(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ParticipateSwapCmd)
+
+	addr, amount, account, lockTime, err := swapFundingParams(w, cmd.Address,
+		cmd.Amount, *cmd.Account, cmd.LockTime, wallet.DefaultParticipantLockTime)
+	if err != nil {
+		return nil, err
+	}
+	secretHash, err := decodeHexStr(cmd.SecretHash)
+	if err != nil {
+		return nil, err
+	}
+	c, err := w.ParticipateSwap(account, addr, amount, secretHash, lockTime)
+	if err != nil {
+		return nil, swapError(err)
+	}
+	return swapContractResult(c)
+}
+
+// swapContractParams decodes the contract, contract transaction, and payment
+// address parameters of the redeemswap and refundswap requests.
+func swapContractParams(w *wallet.Wallet, contractHex, contractTxHex string,
+	toAddress *string) ([]byte, *wire.MsgTx, hcutil.Address, error) {
+
+	contract, err := decodeHexStr(contractHex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	serializedTx, err := decodeHexStr(contractTxHex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	contractTx := wire.NewMsgTx()
+	err = contractTx.Deserialize(bytes.NewReader(serializedTx))
+	if err != nil {
+		e := errors.New("TX decode failed")
+		return nil, nil, nil, DeserializationError{e}
+	}
+	var to hcutil.Address
+	if toAddress != nil {
+		to, err = decodeAddress(*toAddress, w.ChainParams())
+		if err != nil {
+			return nil, nil, nil, err
 		}
 	}
+	return contract, contractTx, to, nil
+}
 
-	// Lookup the multisignature output and get the amount
-	// along with the script for that transaction. Then,
-	// begin crafting a MsgTx.
-	hash, err := chainhash.NewHashFromStr(cmd.Hash)
+// redeemSwap handles a redeemswap request by redeeming an atomic swap
+// contract paying the wallet with the secret.
+func redeemSwap(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.RedeemSwapCmd)
+
+	contract, contractTx, to, err := swapContractParams(w, cmd.Contract,
+		cmd.ContractTx, cmd.ToAddress)
 	if err != nil {
 		return nil, err
 	}
-	op := wire.OutPoint{
-		Hash:  *hash,
-		Index: cmd.Index,
-		Tree:  cmd.Tree,
-	}
-	p2shOutput, err := w.FetchP2SHMultiSigOutput(&op)
+	secret, err := decodeHexStr(cmd.Secret)
 	if err != nil {
 		return nil, err
 	}
-	sc := txscript.GetScriptClass(txscript.DefaultScriptVersion,
-		p2shOutput.RedeemScript)
-	if sc != txscript.MultiSigTy {
-		return nil, fmt.Errorf("invalid P2SH script: not multisig")
+	txHash, err := w.RedeemSwap(contract, contractTx, secret, to)
+	if err != nil {
+		return nil, swapError(err)
 	}
-	var msgTx wire.MsgTx
-	msgTx.AddTxIn(wire.NewTxIn(&op, nil))
+	return txHash.String(), nil
+}
 
-	// Calculate the fees required, and make sure we have enough.
-	// Then produce the txout.
-	account := uint32(udb.DefaultAccountNum)
-	size := wallet.EstimateTxSize(1, 1, account)
-	feeEst := wallet.FeeForSize(w.RelayFee(), size)
-	if feeEst >= p2shOutput.OutputAmount {
-		return nil, fmt.Errorf("multisig out amt is too small "+
-			"(have %v, %v fee suggested)", p2shOutput.OutputAmount, feeEst)
+// refundSwap handles a refundswap request by refunding an atomic swap
+// contract after its lock time.
+func refundSwap(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.RefundSwapCmd)
+
+	contract, contractTx, to, err := swapContractParams(w, cmd.Contract,
+		cmd.ContractTx, cmd.ToAddress)
+	if err != nil {
+		return nil, err
 	}
-	toReceive := p2shOutput.OutputAmount - feeEst
-	pkScript, err := txscript.PayToAddrScript(addr)
+	txHash, err := w.RefundSwap(contract, contractTx, to)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create txout script: %s", err)
+		return nil, swapError(err)
 	}
-	msgTx.AddTxOut(wire.NewTxOut(int64(toReceive), pkScript))
+	return txHash.String(), nil
+}
 
-	// Start creating the SignRawTransactionCmd.
-	outpointScript, err := txscript.PayToScriptHashScript(p2shOutput.P2SHAddress.Hash160()[:])
+// listSwaps handles a listswaps request by returning the atomic swap
+// contracts funded by the wallet and the secrets known for each.
+func listSwaps(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	swaps, err := w.AtomicSwaps()
 	if err != nil {
 		return nil, err
 	}
-	outpointScriptStr := hex.EncodeToString(outpointScript)
-
-	rti := hcjson.RawTxInput{
-		Txid:         cmd.Hash,
-		Vout:         cmd.Index,
-		Tree:         cmd.Tree,
-		ScriptPubKey: outpointScriptStr,
-		RedeemScript: "",
+	result := make([]hcjson.AtomicSwapResult, 0, len(swaps))
+	for _, s := range swaps {
+		result = append(result, hcjson.AtomicSwapResult{
+			ContractP2SH: s.Address.EncodeAddress(),
+			Contract:     hex.EncodeToString(s.Contract),
+			ContractTxID: s.ContractTx.String(),
+			Vout:         s.ContractOut,
+			Initiator:    s.Initiator,
+			SecretHash:   hex.EncodeToString(s.SecretHash),
+			Secret:       hex.EncodeToString(s.Secret),
+			LockTime:     s.LockTime,
+			Created:      s.Created.Unix(),
+		})
 	}
-	rtis := []hcjson.RawTxInput{rti}
+	return result, nil
+}
 
-	var buf bytes.Buffer
-	buf.Grow(msgTx.SerializeSize())
-	if err = msgTx.Serialize(&buf); err != nil {
-		return nil, err
-	}
-	txDataStr := hex.EncodeToString(buf.Bytes())
-	sigHashAll := "ALL"
+// rescanWallet initiates a rescan of the block chain for wallet data, blocking
+// until the rescan completes or exits with an error.  A begin height of 0
+// rescans from the wallet birthday, so the height the rescan began from is
+// returned.
+func rescanWallet(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.RescanWalletCmd)
+	//	if *cmd.BeginHeight != 0 {
+	//		return nil, fmt.Errorf("not support sync from height != 0")
+	//	}
 
-	srtc := &hcjson.SignRawTransactionCmd{
-		RawTx:    txDataStr,
-		Inputs:   &rtis,
-		PrivKeys: &[]string{},
-		Flags:    &sigHashAll,
+	// Rescans from the genesis block begin at the wallet birthday instead.
+	beginHeight := int32(*cmd.BeginHeight)
+	if beginHeight == 0 {
+		var err error
+		beginHeight, err = w.BirthdayHeight()
+		if err != nil {
+			return nil, err
+		}
 	}
-
-	// Sign it and give the results to the user.
-	signedTxResult, err := signRawTransaction(srtc, w, chainClient)
-	if signedTxResult == nil || err != nil {
+	err := <-w.RescanFromHeight(chainClient, beginHeight)
+	if err != nil {
 		return nil, err
 	}
-	srtTyped := signedTxResult.(hcjson.SignRawTransactionResult)
-	return hcjson.RedeemMultiSigOutResult(srtTyped), nil
+	return beginHeight, nil
 }
 
-// redeemMultisigOuts receives a script hash (in the form of a
-// script hash address), looks up all the unspent outpoints associated
-// with that address, then generates a list of partially signed
-// transactions spending to either an address specified or internal
-// addresses in this wallet.
-func redeemMultiSigOuts(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
-	cmd := icmd.(*hcjson.RedeemMultiSigOutsCmd)
+// reserveOutputs handles a reserveoutputs request by selecting and locking
+// unspent outputs of an account totaling at least an amount in a single step.
+func reserveOutputs(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.ReserveOutputsCmd)
 
-	// Get all the multisignature outpoints that are unspent for this
-	// address.
-	addr, err := decodeAddress(cmd.FromScrAddress, w.ChainParams())
+	amount, err := hcutil.NewAmount(cmd.Amount)
 	if err != nil {
 		return nil, err
 	}
-	p2shAddr, ok := addr.(*hcutil.AddressScriptHash)
-	if !ok {
-		return nil, errors.New("address is not P2SH")
+	if amount <= 0 {
+		return nil, ErrNeedPositiveAmount
 	}
-	msos, err := wallet.UnstableAPI(w).UnspentMultisigCreditsForAddress(p2shAddr)
+	if *cmd.MinConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+	if *cmd.Expiry < 0 {
+		return nil, InvalidParameterError{errors.New("expiry may not be negative")}
+	}
+	account, err := w.AccountNumber(*cmd.Account)
 	if err != nil {
 		return nil, err
 	}
-	max := uint32(0xffffffff)
-	if cmd.Number != nil {
-		max = uint32(*cmd.Number)
-	}
 
-	itr := uint32(0)
-	len := math.Min(float64(max), float64(len(msos)))
-	rmsoResults := make([]hcjson.RedeemMultiSigOutResult, int(len))
-	for i, mso := range msos {
-		if itr >= max {
-			break
+	policy := wallet.OutputSelectionPolicy{
+		Account:               account,
+		RequiredConfirmations: int32(*cmd.MinConf),
+	}
+	expiry := time.Duration(*cmd.Expiry) * time.Second
+	outputs, expires, err := w.ReserveOutputs(amount, policy, expiry)
+	if _, ok := err.(txauthor.InsufficientFundsError); ok {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWalletInsufficientFunds,
+			Message: "Insufficient unlocked funds to reserve",
 		}
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		rmsoRequest := &hcjson.RedeemMultiSigOutCmd{
-			Hash:    mso.OutPoint.Hash.String(),
-			Index:   mso.OutPoint.Index,
-			Tree:    mso.OutPoint.Tree,
-			Address: cmd.ToAddress,
+	res := &hcjson.ReserveOutputsResult{
+		Outputs: make([]hcjson.ReservedOutput, len(outputs)),
+	}
+	var total hcutil.Amount
+	for i := range outputs {
+		output := &outputs[i]
+		var address string
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txscript.DefaultScriptVersion, output.PkScript, w.ChainParams())
+		if err == nil && len(addrs) > 0 {
+			address = addrs[0].EncodeAddress()
 		}
-		redeemResult, err := redeemMultiSigOut(rmsoRequest, w, chainClient)
-		if err != nil {
-			return nil, err
+		res.Outputs[i] = hcjson.ReservedOutput{
+			TxID:         output.Hash.String(),
+			Vout:         output.Index,
+			Tree:         output.Tree,
+			Address:      address,
+			ScriptPubKey: hex.EncodeToString(output.PkScript),
+			Amount:       output.Amount.ToCoin(),
 		}
-		redeemResultTyped := redeemResult.(hcjson.RedeemMultiSigOutResult)
-		rmsoResults[i] = redeemResultTyped
-
-		itr++
+		total += output.Amount
 	}
-
-	return hcjson.RedeemMultiSigOutsResult{Results: rmsoResults}, nil
-}
-
-// rescanWallet initiates a rescan of the block chain for wallet data, blocking
-// until the rescan completes or exits with an error.
-func rescanWallet(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
-	cmd := icmd.(*hcjson.RescanWalletCmd)
-	//	if *cmd.BeginHeight != 0 {
-	//		return nil, fmt.Errorf("not support sync from height != 0")
-	//	}
-	err := <-w.RescanFromHeight(chainClient, int32(*cmd.BeginHeight))
-	return nil, err
+	res.Total = total.ToCoin()
+	if !expires.IsZero() {
+		res.Expires = expires.Unix()
+	}
+	return res, nil
 }
 
 // revokeTickets initiates the wallet to issue revocations for any missing tickets that
-// not yet been revoked.
+// not yet been revoked, or only the requested tickets, returning each revoked
+// ticket with its revocation.  Dry runs return the revocations which would be
+// published without signing or publishing them.
 func revokeTickets(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
-	err := w.RevokeTickets(chainClient)
-	return nil, err
+	cmd := icmd.(*hcjson.RevokeTicketsCmd)
+	opts := &wallet.RevokeTicketsOptions{
+		AllowHighFees: revocationAllowHighFees(cmd.AllowHighFees),
+		DryRun:        cmd.DryRun != nil && *cmd.DryRun,
+	}
+	if cmd.FeeRate != nil {
+		feeRate, err := hcutil.NewAmount(*cmd.FeeRate)
+		if err != nil {
+			return nil, InvalidParameterError{err}
+		}
+		if feeRate < 0 {
+			return nil, InvalidParameterError{errors.New("negative fee rate")}
+		}
+		opts.FeeRate = feeRate
+	}
+	if cmd.Tickets != nil {
+		opts.Tickets = make([]chainhash.Hash, 0, len(*cmd.Tickets))
+		for _, s := range *cmd.Tickets {
+			hash, err := chainhash.NewHashFromStr(s)
+			if err != nil {
+				return nil, DeserializationError{err}
+			}
+			opts.Tickets = append(opts.Tickets, *hash)
+		}
+	}
+
+	revoked, err := w.RevokeTicketsWithOptions(chainClient, opts)
+	if err != nil {
+		if apperrors.IsError(err, apperrors.ErrInput) {
+			return nil, InvalidParameterError{err}
+		}
+		if apperrors.IsError(err, apperrors.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		return nil, err
+	}
+	res := &hcjson.RevokeTicketsResult{
+		AllowHighFees: opts.AllowHighFees,
+		DryRun:        opts.DryRun,
+		Revocations:   make([]hcjson.RevokedTicketResult, 0, len(revoked)),
+	}
+	for i := range revoked {
+		res.Revocations = append(res.Revocations, hcjson.RevokedTicketResult{
+			Ticket:     revoked[i].Ticket.String(),
+			Revocation: revoked[i].Revocation.String(),
+		})
+	}
+	return res, nil
+}
+
+// syncAccountAddresses handles a syncaccountaddresses request by extending the
+// addresses watched for transactions according to the gap limit policy and
+// returning the used, returned, and watched child indexes of every account
+// branch.
+func syncAccountAddresses(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	usage, err := w.SyncAccountAddresses()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]hcjson.SyncAccountAddressesResult, 0, len(usage))
+	for _, u := range usage {
+		result = append(result, hcjson.SyncAccountAddressesResult{
+			Account:       u.AccountName,
+			AccountNumber: u.Account,
+			External:      hcjson.AddressBranchUsage(u.External),
+			Internal:      hcjson.AddressBranchUsage(u.Internal),
+		})
+	}
+	return result, nil
 }
 
 // stakePoolUserInfo returns the ticket information for a given user from the
@@ -2237,6 +4356,16 @@ func stakePoolUserInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 
 		resp.InvalidTickets = append(resp.InvalidTickets, invalidTicket)
 	}
+	for _, r := range spui.Rejections {
+		resp.InvalidDetails = append(resp.InvalidDetails, hcjson.InvalidPoolUserTicket{
+			Ticket:       r.Ticket.String(),
+			Reason:       r.Reason.String(),
+			TicketHeight: r.Height,
+			Found:        r.Found.ToCoin(),
+			Required:     r.Required.ToCoin(),
+			Detail:       r.Detail,
+		})
+	}
 
 	return resp, nil
 }
@@ -2308,7 +4437,10 @@ func sendFrom(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Clien
 		cmd.ToAddress: amt,
 	}
 
-	return sendPairs(w, pairs, account, minConf, "", []byte{}, "")
+	params := []interface{}{"sendfrom", account, pairs, minConf}
+	return sendIdempotent(w, cmd.IdempotencyKey, params, func() (string, error) {
+		return sendPairs(w, pairs, account, minConf, "", []byte{}, "")
+	})
 }
 
 // sendMany handles a sendmany RPC request by creating a new transaction
@@ -2349,13 +4481,52 @@ func sendMany(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		pairs[k] = amt
 	}
 
-	return sendPairs(w, pairs, account, minConf, "", []byte{}, "")
+	params := []interface{}{"sendmany", account, pairs, minConf}
+	return sendIdempotent(w, cmd.IdempotencyKey, params, func() (string, error) {
+		return sendPairs(w, pairs, account, minConf, "", []byte{}, "")
+	})
+}
+
+// sendRawTransaction handles a sendrawtransaction request by publishing the
+// transaction through the wallet rather than passing the request through to
+// hcd.  Transactions which spend locked or reserved wallet outputs, or which
+// double spend unmined wallet transactions, are rejected, and transactions
+// relevant to the wallet are recorded before they are sent.  Transactions
+// failing the wallet's pre-flight script validation error with code
+// ErrRPCVerify rather than being rejected by hcd.
+func sendRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
+	cmd := icmd.(*hcjson.SendRawTransactionCmd)
+
+	serializedTx, err := decodeHexStr(cmd.HexTx)
+	if err != nil {
+		return nil, err
+	}
+	var tx wire.MsgTx
+	err = tx.Deserialize(bytes.NewReader(serializedTx))
+	if err != nil {
+		return nil, DeserializationError{errors.New("TX decode failed")}
+	}
+
+	allowHighFees := cmd.AllowHighFees != nil && *cmd.AllowHighFees
+	txHash, err := w.PublishRawTransaction(&tx, serializedTx, allowHighFees,
+		chainClient)
+	if apperrors.IsError(err, apperrors.ErrLocked) ||
+		apperrors.IsError(err, apperrors.ErrDoubleSpend) {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return txHash.String(), nil
 }
 
 // sendManyV2 handles a sendManyV2 RPC request by creating a new transaction
 // spending unspent transaction outputs for a wallet to any number of
 // payment addresses.  Leftover inputs not sent to the payment address
-// or a fee for the miner are sent back to a designated  address or first address of the default account
+// or a fee for the miner are sent back to a designated address or the default address of the account
 // in the wallet. Upon success, the TxID for the created transaction is returned.
 func sendManyV2(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*hcjson.SendManyV2Cmd)
@@ -2385,7 +4556,7 @@ func sendManyV2(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 
 	var changeAddr string
 	if cmd.ChangeAddr == nil {
-		addr, err := w.FirstAddr(account)
+		addr, err := w.AccountDefaultAddress(account)
 		if err != nil {
 			return nil, err
 		}
@@ -2394,7 +4565,10 @@ func sendManyV2(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		changeAddr = *cmd.ChangeAddr
 	}
 
-	return sendPairs(w, pairs, account, minConf, changeAddr, []byte{}, "")
+	params := []interface{}{"sendmanyv2", account, pairs, cmd.ChangeAddr, minConf}
+	return sendIdempotent(w, cmd.IdempotencyKey, params, func() (string, error) {
+		return sendPairs(w, pairs, account, minConf, changeAddr, []byte{}, "")
+	})
 }
 
 // sendToAddress handles a sendtoaddress RPC request by creating a new
@@ -2459,7 +4633,10 @@ func sendToAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 
 	// sendtoaddress always spends from the default account, this matches bitcoind
-	return sendPairs(w, pairs, account, 1, "", []byte{}, "")
+	params := []interface{}{"sendtoaddress", pairs}
+	return sendIdempotent(w, cmd.IdempotencyKey, params, func() (string, error) {
+		return sendPairs(w, pairs, account, 1, "", []byte{}, "")
+	})
 }
 
 // getStraightPubKey handles a getStraightPubKey RPC request by getting a straight public key
@@ -2499,10 +4676,159 @@ func getStraightPubKey(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccli
 			return nil, errors.New("only secp256k1 and bliss " +
 				"pubkeys are currently supported")
 		}
-		return result, nil
-	default:
-		return nil, errors.New("unknow error.")
+		return result, nil
+	default:
+		return nil, errors.New("unknow error.")
+	}
+}
+
+// parseBatchPayments parses CSV records of a payment address and amount,
+// returning the addresses, amounts, and transaction outputs of the payments in
+// order.  Blank lines and lines beginning with '#' are ignored.
+func parseBatchPayments(payments string, chainParams *chaincfg.Params) ([]string,
+	[]hcutil.Amount, []*wire.TxOut, error) {
+
+	r := csv.NewReader(strings.NewReader(payments))
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, nil, InvalidParameterError{err}
+	}
+	if len(records) == 0 {
+		return nil, nil, nil, InvalidParameterError{errors.New("no payments")}
+	}
+
+	addrs := make([]string, len(records))
+	amounts := make([]hcutil.Amount, len(records))
+	outputs := make([]*wire.TxOut, len(records))
+	for i, record := range records {
+		addr, err := decodeAddress(record[0], chainParams)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot create txout script: %s", err)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, nil, nil, InvalidParameterError{
+				fmt.Errorf("payment %d: invalid amount: %v", i, err)}
+		}
+		amt, err := hcutil.NewAmount(f)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if amt <= 0 {
+			return nil, nil, nil, ErrNeedPositiveAmount
+		}
+		addrs[i] = record[0]
+		amounts[i] = amt
+		outputs[i] = wire.NewTxOut(int64(amt), pkScript)
+	}
+	return addrs, amounts, outputs, nil
+}
+
+// sendBatch handles a sendbatch RPC request by paying each payment of a CSV
+// list with as few transactions as the transaction size limits allow.
+func sendBatch(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.SendBatchCmd)
+
+	account, err := w.AccountNumber(cmd.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+	if *cmd.MinConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+	addrs, amounts, outputs, err := parseBatchPayments(cmd.Payments,
+		w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := w.SendBatch(outputs, account, int32(*cmd.MinConf), *cmd.Atomic)
+	if _, ok := err.(txauthor.InsufficientFundsError); ok {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWalletInsufficientFunds,
+			Message: err.Error(),
+		}
+	}
+	switch {
+	case err == txrules.ErrAmountNegative:
+		return nil, ErrNeedPositiveAmount
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+
+	result := &hcjson.SendBatchResult{
+		TxIDs:   make([]string, len(res.Txs)),
+		Outputs: make([]hcjson.SendBatchOutput, len(res.Outputs)),
+	}
+	var fee hcutil.Amount
+	for i := range res.Txs {
+		result.TxIDs[i] = res.Txs[i].Hash.String()
+		fee += res.Txs[i].Fee
+	}
+	result.Fee = fee.ToCoin()
+	for i, a := range res.Outputs {
+		result.Outputs[i] = hcjson.SendBatchOutput{
+			Address: addrs[i],
+			Amount:  amounts[i].ToCoin(),
+		}
+		if a.Tx >= 0 {
+			result.Outputs[i].TxID = result.TxIDs[a.Tx]
+			result.Outputs[i].Vout = a.Vout
+		}
+	}
+	if res.Err != nil {
+		result.Error = res.Err.Error()
+	}
+	return result, nil
+}
+
+// sendData handles a senddata RPC request by creating and sending a
+// transaction with an OP_RETURN output carrying the data.
+func sendData(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.SendDataCmd)
+
+	data, err := decodeHexStr(cmd.Data)
+	if err != nil {
+		return nil, err
+	}
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	if *cmd.MinConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+
+	txHash, fee, err := w.SendData(account, data, int32(*cmd.MinConf))
+	if _, ok := err.(txauthor.InsufficientFundsError); ok {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWalletInsufficientFunds,
+			Message: err.Error(),
+		}
+	}
+	switch {
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
 	}
+	return &hcjson.SendDataResult{
+		TxID: txHash.String(),
+		Fee:  fee.ToCoin(),
+	}, nil
 }
 
 // sendToMultiSig handles a sendtomultisig RPC request by creating a new
@@ -2655,12 +4981,19 @@ func sendToSStx(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Cli
 		}
 	}
 
-	txSha, err := chainClient.SendRawTransaction(createdTx.MsgTx, w.AllowHighFees)
+	highFees := allowHighFees(w, cmd.AllowHighFees)
+	txSha, err := w.SendRawTransaction(createdTx.MsgTx, highFees, chainClient)
 	if err != nil {
 		return nil, err
 	}
 	log.Infof("Successfully sent SStx purchase transaction %v", txSha)
-	return txSha.String(), nil
+	return versionedResult{
+		resultVersionLegacy: txSha.String(),
+		resultVersionExtended: &hcjson.SendToSStxResult{
+			TxHash:        txSha.String(),
+			AllowHighFees: highFees,
+		},
+	}, nil
 }
 
 // sendToSSGen handles a sendtossgen RPC request by creating a new transaction
@@ -2736,7 +5069,8 @@ func sendToSSRtx(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Cl
 		}
 	}
 
-	txSha, err := chainClient.SendRawTransaction(createdTx.MsgTx, w.AllowHighFees)
+	txSha, err := w.SendRawTransaction(createdTx.MsgTx,
+		revocationAllowHighFees(cmd.AllowHighFees), chainClient)
 	if err != nil {
 		return nil, err
 	}
@@ -2744,6 +5078,91 @@ func sendToSSRtx(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Cl
 	return txSha.String(), nil
 }
 
+// setLogLevel handles a setloglevel request by changing the logging level of
+// all or individual subsystems without restarting the process.  The level
+// specification uses the format of the debuglevel option, e.g. "debug" or
+// "CHNS=debug,WLLT=trace".  The resulting level of each subsystem is
+// returned.
+func setLogLevel(icmd interface{}, lc LogControl) (interface{}, error) {
+	cmd := icmd.(*hcjson.SetLogLevelCmd)
+	levels, err := lc.SetLogLevels(cmd.LevelSpec)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	return levels, nil
+}
+
+// setLogRotation handles a setlogrotation request by changing the size after
+// which the log file is rolled and the number of rolled files kept.
+func setLogRotation(icmd interface{}, lc LogControl) (interface{}, error) {
+	cmd := icmd.(*hcjson.SetLogRotationCmd)
+	if cmd.MaxSize <= 0 || cmd.MaxRolls <= 0 {
+		return nil, InvalidParameterError{errors.New("maxsize and maxrolls must be positive")}
+	}
+	return nil, lc.SetLogRotation(cmd.MaxSize, cmd.MaxRolls)
+}
+
+// setAccountDefaultAddress handles a setaccountdefaultaddress request by
+// setting the default address of an account, or reverting the account to its
+// first address when no address is given.
+func setAccountDefaultAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.SetAccountDefaultAddressCmd)
+
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	var addr hcutil.Address
+	if cmd.Address != nil {
+		addr, err = decodeAddress(*cmd.Address, w.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = w.SetAccountDefaultAddress(account, addr)
+	if apperrors.IsError(err, apperrors.ErrInput) ||
+		apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+		return nil, InvalidParameterError{err}
+	}
+	return nil, err
+}
+
+// setPoolFeeExemption handles a setpoolfeeexemption request by
+// exempting a stake pool user from the pool fee check, or removing the
+// exemption.
+func setPoolFeeExemption(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.SetPoolFeeExemptionCmd)
+
+	userAddr, err := decodeAddress(cmd.User, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	err = w.SetStakePoolFeeExemption(userAddr, *cmd.Exempt)
+	if apperrors.IsError(err, apperrors.ErrBadPoolUserAddr) {
+		return nil, InvalidParameterError{err}
+	}
+	return nil, err
+}
+
+// setSpendableConfs handles a setspendableconfs request by setting the
+// confirmations an account requires before its outputs are spendable.
+func setSpendableConfs(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.SetSpendableConfsCmd)
+
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	err = w.SetAccountSpendableConfs(account, &udb.SpendableConfs{
+		Regular:  cmd.Regular,
+		Coinbase: *cmd.Coinbase,
+	})
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return nil, InvalidParameterError{err}
+	}
+	return nil, err
+}
+
 // setTicketFee sets the transaction fee per kilobyte added to tickets.
 func setTicketFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*hcjson.SetTicketFeeCmd)
@@ -2757,6 +5176,20 @@ func setTicketFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// When an account is specified, only set the fee used when purchasing
+	// tickets from that account.
+	if cmd.Account != nil {
+		account, err := w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+		err = w.SetAccountTicketFeeIncrement(account, incr)
+		if err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
 	w.SetTicketFeeIncrement(incr)
 
 	// A boolean true result is returned upon success.
@@ -2776,6 +5209,20 @@ func setTxFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// When an account is specified, only set the fee used when creating
+	// transactions for that account.
+	if cmd.Account != nil {
+		account, err := w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+		err = w.SetAccountRelayFee(account, relayFee)
+		if err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
 	w.SetRelayFee(relayFee)
 
 	// A boolean true result is returned upon success.
@@ -2809,6 +5256,32 @@ func signMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return base64.StdEncoding.EncodeToString(sig), nil
 }
 
+// signMessageWithAccount handles the signmessagewithaccount command by signing
+// a message with the account proof key of an account, proving ownership of the
+// whole account.
+func signMessageWithAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.SignMessageWithAccountCmd)
+
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	xpub, sig, err := w.SignMessageWithAccount(cmd.Message, account)
+	if apperrors.IsError(err, apperrors.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	if apperrors.IsError(err, apperrors.ErrInvalidAccount) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.SignMessageWithAccountResult{
+		XPub:      xpub,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
+
 func signRawTransactionNoChainRPC(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return signRawTransaction(icmd, w, nil)
 }
@@ -2859,6 +5332,7 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccl
 	// make sure that they match the blockchain if present.
 	inputs := make(map[wire.OutPoint][]byte)
 	scripts := make(map[string][]byte)
+	requestAmounts := make(map[wire.OutPoint]hcutil.Amount)
 	var cmdInputs []hcjson.RawTxInput
 	if cmd.Inputs != nil {
 		cmdInputs = *cmd.Inputs
@@ -2898,11 +5372,52 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccl
 			}
 			scripts[addr.String()] = redeemScript
 		}
-		inputs[wire.OutPoint{
+		op := wire.OutPoint{
 			Hash:  *inputSha,
 			Tree:  rti.Tree,
 			Index: rti.Vout,
-		}] = script
+		}
+		inputs[op] = script
+
+		if rti.Amount != nil {
+			amount, err := hcutil.NewAmount(*rti.Amount)
+			if err != nil {
+				return nil, InvalidParameterError{err}
+			}
+			if amount < 0 {
+				return nil, ErrNeedPositiveAmount
+			}
+			requestAmounts[op] = amount
+		}
+	}
+
+	// Previous output values are looked up in the wallet, and otherwise
+	// queried from hcd along with missing output scripts, so that amounts
+	// provided by the request and the transaction can be verified.
+	prevValues := make(map[wire.OutPoint]signInputValue)
+	valueRequests := make(map[wire.OutPoint]hcrpcclient.FutureGetTxOutResult)
+	for i, txIn := range tx.TxIn {
+		if i == 0 && *cmd.Flags == "ssgen" {
+			continue
+		}
+		op := txIn.PreviousOutPoint
+		prevTx, err := wallet.UnstableAPI(w).TxDetails(&op.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if prevTx != nil && int(op.Index) < len(prevTx.MsgTx.TxOut) {
+			prevValues[op] = signInputValue{
+				amount: hcutil.Amount(prevTx.MsgTx.TxOut[op.Index].Value),
+				source: "wallet",
+			}
+			continue
+		}
+		_, hasScript := inputs[op]
+		_, hasAmount := requestAmounts[op]
+		if hasScript && hasAmount && chainClient != nil {
+			valueRequests[op] = chainClient.GetTxOutAsync(&op.Hash,
+				op.Index, true)
+		}
 	}
 
 	// Now we go and look for any inputs that we were not provided by
@@ -2973,6 +5488,19 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccl
 				if err != nil {
 					return nil, DeserializationError{err}
 				}
+			case bliss.BSTypeBliss:
+				// Bliss keys sign both bliss P2PKH outputs and
+				// multisig scripts with bliss public keys, which
+				// are both looked up by the P2PKH encoding.
+				addr, err = hcutil.NewAddressBlissPubKey(
+					wif.SerializePubKey(),
+					w.ChainParams())
+				if err != nil {
+					return nil, DeserializationError{err}
+				}
+			default:
+				s := "unsupported private key signature algorithm"
+				return nil, DeserializationError{errors.New(s)}
 			}
 			keys[addr.EncodeAddress()] = wif
 		}
@@ -2996,6 +5524,36 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccl
 			return nil, err
 		}
 		inputs[outPoint] = script
+		if _, ok := prevValues[outPoint]; !ok {
+			amount, err := hcutil.NewAmount(result.Value)
+			if err != nil {
+				return nil, err
+			}
+			prevValues[outPoint] = signInputValue{amount: amount, source: "chain"}
+		}
+	}
+	for outPoint, resp := range valueRequests {
+		result, err := resp.Receive()
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+		amount, err := hcutil.NewAmount(result.Value)
+		if err != nil {
+			return nil, err
+		}
+		prevValues[outPoint] = signInputValue{amount: amount, source: "chain"}
+	}
+
+	// Amounts of the request and the transaction must match the values of
+	// previous outputs which are known.  Unverified amounts are only used
+	// when the previous output is unknown.
+	inputValues, err := signInputValues(tx, *cmd.Flags == "ssgen",
+		prevValues, requestAmounts)
+	if err != nil {
+		return nil, err
 	}
 
 	// All args collected. Now we can sign all the inputs that we can.
@@ -3028,16 +5586,102 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *hcrpccl
 		})
 	}
 
-	return hcjson.SignRawTransactionResult{
+	result := hcjson.SignRawTransactionResult{
 		Hex:      hex.EncodeToString(buf.Bytes()),
 		Complete: len(signErrors) == 0,
 		Errors:   signErrors,
-	}, nil
+		Inputs:   make([]hcjson.SignRawTransactionInput, len(tx.TxIn)),
+	}
+	var totalIn hcutil.Amount
+	allKnown := true
+	for i, txIn := range tx.TxIn {
+		result.Inputs[i] = hcjson.SignRawTransactionInput{
+			TxID: txIn.PreviousOutPoint.Hash.String(),
+			Vout: txIn.PreviousOutPoint.Index,
+		}
+		v, ok := inputValues[i]
+		if !ok {
+			allKnown = false
+			continue
+		}
+		amount := v.amount.ToCoin()
+		result.Inputs[i].Amount = &amount
+		result.Inputs[i].Source = v.source
+		totalIn += v.amount
+	}
+	if allKnown {
+		var totalOut hcutil.Amount
+		for _, txOut := range tx.TxOut {
+			totalOut += hcutil.Amount(txOut.Value)
+		}
+		fee := (totalIn - totalOut).ToCoin()
+		result.Fee = &fee
+	}
+	return result, nil
+}
+
+// signInputValue is the value of an input of a transaction signed by
+// signrawtransaction, and the source the value was determined from.
+type signInputValue struct {
+	amount hcutil.Amount
+	source string
+}
+
+// signInputValues determines the value of each input of a transaction signed
+// by signrawtransaction.  Values of previous outputs recorded by the wallet or
+// queried from hcd are preferred, and amounts provided by the request or set
+// in the transaction which do not match them are rejected.  The stakebase
+// input of a vote takes the value set in the transaction.  Transaction input
+// values of zero or wire.NullValueIn are treated as unset.  Inputs with no
+// known value are omitted.
+func signInputValues(tx *wire.MsgTx, stakebase bool, prevValues map[wire.OutPoint]signInputValue,
+	requestAmounts map[wire.OutPoint]hcutil.Amount) (map[int]signInputValue, error) {
+
+	values := make(map[int]signInputValue, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		op := txIn.PreviousOutPoint
+		if i == 0 && stakebase {
+			values[i] = signInputValue{
+				amount: hcutil.Amount(txIn.ValueIn),
+				source: "stakebase",
+			}
+			continue
+		}
+		hasValueIn := txIn.ValueIn != 0 && txIn.ValueIn != wire.NullValueIn
+		reqAmount, hasReqAmount := requestAmounts[op]
+		prev, ok := prevValues[op]
+		if !ok {
+			switch {
+			case hasReqAmount:
+				values[i] = signInputValue{amount: reqAmount, source: "request"}
+			case hasValueIn:
+				values[i] = signInputValue{
+					amount: hcutil.Amount(txIn.ValueIn),
+					source: "transaction",
+				}
+			}
+			continue
+		}
+		if hasReqAmount && reqAmount != prev.amount {
+			e := fmt.Errorf("amount %v of input %v does not match the "+
+				"previous output value %v", reqAmount, &op, prev.amount)
+			return nil, InvalidParameterError{e}
+		}
+		if hasValueIn && hcutil.Amount(txIn.ValueIn) != prev.amount {
+			e := fmt.Errorf("input value %v of input %v does not match "+
+				"the previous output value %v", hcutil.Amount(txIn.ValueIn),
+				&op, prev.amount)
+			return nil, InvalidParameterError{e}
+		}
+		values[i] = prev
+	}
+	return values, nil
 }
 
 // signRawTransactions handles the signrawtransactions command.
 func signRawTransactions(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client) (interface{}, error) {
 	cmd := icmd.(*hcjson.SignRawTransactionsCmd)
+	highFees := allowHighFees(w, cmd.AllowHighFees)
 
 	// Sign each transaction sequentially and record the results.
 	// Error out if we meet some unexpected failure.
@@ -3077,9 +5721,10 @@ func signRawTransactions(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcc
 				}
 				sent := false
 				hashStr := ""
-				hash, err := chainClient.SendRawTransaction(msgTx, w.AllowHighFees)
+				hash, err := w.SendRawTransaction(msgTx, highFees, chainClient)
 				// If sendrawtransaction errors out (blockchain rule
-				// issue, etc), continue onto the next transaction.
+				// issue, failed script validation, etc), continue onto
+				// the next transaction.
 				if err == nil {
 					sent = true
 					hashStr = hash.String()
@@ -3111,7 +5756,10 @@ func signRawTransactions(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcc
 		}
 	}
 
-	return &hcjson.SignRawTransactionsResult{Results: toReturn}, nil
+	return &hcjson.SignRawTransactionsResult{
+		Results:       toReturn,
+		AllowHighFees: highFees,
+	}, nil
 }
 
 // validateAddress handles the validateaddress command.
@@ -3233,12 +5881,15 @@ func verifyMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		return nil, err
 	}
 
-	// Addresses must have an associated secp256k1 private key and therefore
-	// must be P2PK or P2PKH (P2SH is not allowed).
+	// Addresses must have an associated secp256k1 or bliss private key and
+	// therefore must be P2PK or P2PKH (P2SH is not allowed).
 	switch a := addr.(type) {
 	case *hcutil.AddressSecpPubKey:
+	case *hcutil.AddressBlissPubKey:
 	case *hcutil.AddressPubKeyHash:
-		if a.DSA(a.Net()) != chainec.ECTypeSecp256k1 {
+		switch a.DSA(a.Net()) {
+		case chainec.ECTypeSecp256k1, bliss.BSTypeBliss:
+		default:
 			goto WrongAddrKind
 		}
 	default:
@@ -3254,7 +5905,128 @@ func verifyMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return valid, nil
 
 WrongAddrKind:
-	return nil, InvalidParameterError{errors.New("address must be secp256k1 P2PK or P2PKH")}
+	return nil, InvalidParameterError{errors.New("address must be secp256k1 or bliss P2PK or P2PKH")}
+}
+
+// triggerConsolidation handles a triggerconsolidation request by consolidating
+// the dust outputs of an account, returning the hashes of the published
+// transactions.  Unless forced, dust is only consolidated when the account
+// exceeds the configured number of dust outputs during a low-fee period.
+func triggerConsolidation(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.TriggerConsolidationCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	txHashes, err := w.ConsolidateDust(account, *cmd.Force)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+	hashStrs := make([]string, len(txHashes))
+	for i, txHash := range txHashes {
+		hashStrs[i] = txHash.String()
+	}
+	return hashStrs, nil
+}
+
+// verifyAccountProof handles the verifyaccountproof command by checking that a
+// message was signed by the account of an extended public key.
+func verifyAccountProof(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.VerifyAccountProofCmd)
+
+	sig, err := base64.StdEncoding.DecodeString(cmd.Signature)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	valid, err := wallet.VerifyAccountProof(cmd.Message, cmd.XPub, sig,
+		w.ChainParams())
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		// As with verifymessage, signatures which fail to verify are
+		// invalid rather than an error.
+		return false, nil
+	}
+	return valid, nil
+}
+
+// verifyTicketProof handles the verifyticketproof command by checking that a
+// challenge was signed by a commitment or voting address of a ticket.
+func verifyTicketProof(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.VerifyTicketProofCmd)
+
+	ticketHash, err := chainhash.NewHashFromStr(cmd.TicketHash)
+	if err != nil {
+		return nil, DeserializationError{err}
+	}
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(cmd.Signature)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	valid, err := w.VerifyTicketProof(ticketHash, cmd.Challenge, addr, sig)
+	if apperrors.IsError(err, apperrors.ErrInput) {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return valid, nil
+}
+
+// verifySeedBackup handles the verifyseedbackup command by returning the seed
+// word positions of the seed backup challenge, and when words are provided,
+// checking them against the seed the wallet was created from.
+func verifySeedBackup(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.VerifySeedBackupCmd)
+
+	c, err := w.SeedBackupChallenge()
+	if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		return nil, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWallet,
+			Message: "Wallet was not created with a seed backup challenge",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Positions are reported one-based, as users count seed words.
+	result := &hcjson.VerifySeedBackupResult{
+		Positions: make([]int, len(c.Positions)),
+		Verified:  c.Verified,
+	}
+	for i, pos := range c.Positions {
+		result.Positions[i] = pos + 1
+	}
+	if cmd.Words == nil {
+		return result, nil
+	}
+
+	match, err := w.VerifySeedBackup(*cmd.Words)
+	switch {
+	case apperrors.IsError(err, apperrors.ErrInput):
+		return nil, InvalidParameterError{err}
+	case apperrors.IsError(err, apperrors.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Match = &match
+	result.Verified = result.Verified || match
+	return result, nil
 }
 
 // versionWithChainRPC handles the version request when the RPC server has been
@@ -3293,6 +6065,10 @@ func version(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Client
 		Minor:         jsonrpcSemverMinor,
 		Patch:         jsonrpcSemverPatch,
 	}
+	resp["hcwalletresultversion"] = hcjson.VersionResult{
+		VersionString: strconv.Itoa(maxResultVersion),
+		Major:         maxResultVersion,
+	}
 	return resp, nil
 }
 
@@ -3318,7 +6094,16 @@ func walletInfo(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Cli
 	_ = binary.Read(bytes.NewBuffer(voteBits.ExtendedBits[0:4]), binary.LittleEndian, &voteVersion)
 	voting := w.VotingEnabled()
 
-	return &hcjson.WalletInfoResult{
+	accounts, addresses, err := w.AddressCounts()
+	if err != nil {
+		return nil, err
+	}
+	birthdayHeight, err := w.BirthdayHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &hcjson.WalletInfoResult{
 		DaemonConnected:  connected,
 		Unlocked:         unlocked,
 		TxFee:            fi.ToCoin(),
@@ -3328,7 +6113,38 @@ func walletInfo(icmd interface{}, w *wallet.Wallet, chainClient *hcrpcclient.Cli
 		VoteBitsExtended: hex.EncodeToString(voteBits.ExtendedBits),
 		VoteVersion:      voteVersion,
 		Voting:           voting,
-	}, nil
+		OmniEnabled:      w.EnableOmni(),
+		Accounts:         accounts,
+		WatchedAddresses: addresses,
+		BirthdayHeight:   birthdayHeight,
+	}
+	voteVersionStatus := w.VoteVersionStatus()
+	result.NetworkStakeVersion = voteVersionStatus.NetworkVersion
+	result.VoteVersionOutdated = voteVersionStatus.Outdated
+	result.SkipStaleVotes = voteVersionStatus.SkipStaleVotes
+
+	// Rescan progress is the fraction of the blocks from the rescan start
+	// through the main chain tip which have been rescanned.
+	if state := w.RescanState(); state.Scanning {
+		result.Rescanning = true
+		_, tipHeight := w.MainChainTip()
+		if blocks := tipHeight - state.StartHeight + 1; blocks > 0 {
+			scanned := state.ScannedThrough - state.StartHeight + 1
+			result.RescanProgress = float64(scanned) / float64(blocks)
+		}
+	}
+
+	// The waterline is omitted when omni does not respond.
+	if result.OmniEnabled {
+		waterline, err := w.OmniWaterline()
+		if err != nil {
+			log.Warnf("Unable to query omni waterline: %v", err)
+		} else {
+			result.OmniWaterline = &waterline
+		}
+	}
+
+	return result, nil
 }
 
 // walletIsLocked handles the walletislocked extension request by
@@ -0,0 +1,245 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/crypto/bliss"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestSignInputValues(t *testing.T) {
+	known := wire.OutPoint{Hash: chainhash.Hash{1}}
+	unknown := wire.OutPoint{Hash: chainhash.Hash{2}}
+	prevValues := map[wire.OutPoint]signInputValue{
+		known: {amount: 5e8, source: "wallet"},
+	}
+
+	tests := []struct {
+		name      string
+		stakebase bool
+		inputs    []*wire.TxIn
+		requested map[wire.OutPoint]hcutil.Amount
+		values    map[int]signInputValue
+		invalid   bool
+	}{{
+		name:   "known previous output",
+		inputs: []*wire.TxIn{wire.NewTxIn(&known, nil)},
+		values: map[int]signInputValue{0: {5e8, "wallet"}},
+	}, {
+		name:      "matching request amount",
+		inputs:    []*wire.TxIn{wire.NewTxIn(&known, nil)},
+		requested: map[wire.OutPoint]hcutil.Amount{known: 5e8},
+		values:    map[int]signInputValue{0: {5e8, "wallet"}},
+	}, {
+		name:      "mismatched request amount",
+		inputs:    []*wire.TxIn{wire.NewTxIn(&known, nil)},
+		requested: map[wire.OutPoint]hcutil.Amount{known: 4e8},
+		invalid:   true,
+	}, {
+		name:    "mismatched transaction input value",
+		inputs:  []*wire.TxIn{{PreviousOutPoint: known, ValueIn: 4e8}},
+		invalid: true,
+	}, {
+		name:      "unknown previous output with request amount",
+		inputs:    []*wire.TxIn{{PreviousOutPoint: unknown, ValueIn: 3e8}},
+		requested: map[wire.OutPoint]hcutil.Amount{unknown: 2e8},
+		values:    map[int]signInputValue{0: {2e8, "request"}},
+	}, {
+		name:   "unknown previous output with transaction input value",
+		inputs: []*wire.TxIn{{PreviousOutPoint: unknown, ValueIn: 3e8}},
+		values: map[int]signInputValue{0: {3e8, "transaction"}},
+	}, {
+		name:   "unknown previous output without value",
+		inputs: []*wire.TxIn{wire.NewTxIn(&unknown, nil)},
+		values: map[int]signInputValue{},
+	}, {
+		name:      "stakebase",
+		stakebase: true,
+		inputs: []*wire.TxIn{
+			{PreviousOutPoint: unknown, ValueIn: 1e8},
+			wire.NewTxIn(&known, nil),
+		},
+		values: map[int]signInputValue{
+			0: {1e8, "stakebase"},
+			1: {5e8, "wallet"},
+		},
+	}}
+	for _, test := range tests {
+		tx := wire.NewMsgTx()
+		for _, in := range test.inputs {
+			tx.AddTxIn(in)
+		}
+		values, err := signInputValues(tx, test.stakebase, prevValues,
+			test.requested)
+		if test.invalid {
+			if _, ok := err.(InvalidParameterError); !ok {
+				t.Errorf("%s: error %v, want an invalid parameter error",
+					test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(values) != len(test.values) {
+			t.Errorf("%s: %d input values, want %d", test.name,
+				len(values), len(test.values))
+			continue
+		}
+		for i, want := range test.values {
+			if values[i] != want {
+				t.Errorf("%s: input %d value %+v, want %+v", test.name, i,
+					values[i], want)
+			}
+		}
+	}
+}
+
+// signWithKey signs a transaction spending a single output of amount coins
+// paying pkScript with the key wif through the signrawtransaction handler.
+// The previous output is not recorded by the wallet, so the script and amount
+// are provided by the request.
+func signWithKey(t *testing.T, w *wallet.Wallet, wif *hcutil.WIF, pkScript []byte,
+	amount float64) hcjson.SignRawTransactionResult {
+
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []hcjson.RawTxInput{{
+		Txid:         tx.TxIn[0].PreviousOutPoint.Hash.String(),
+		ScriptPubKey: hex.EncodeToString(pkScript),
+		Amount:       &amount,
+	}}
+	keys := []string{wif.String()}
+	flags := "ALL"
+	res, err := signRawTransaction(hcjson.NewSignRawTransactionCmd(
+		hex.EncodeToString(buf.Bytes()), &inputs, &keys, &flags), w, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.(hcjson.SignRawTransactionResult)
+}
+
+func TestSignRawTransactionInputValues(t *testing.T) {
+	w, teardown := testOmniWallet(t)
+	defer teardown()
+
+	privKey, _ := chainec.Secp256k1.PrivKeyFromBytes(bytes.Repeat([]byte{1}, 32))
+	wif, err := hcutil.NewWIF(privKey, w.ChainParams(), chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := hcutil.NewAddressSecpPubKey(wif.SerializePubKey(), w.ChainParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr.AddressPubKeyHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := signWithKey(t, w, wif, pkScript, 3)
+	if !r.Complete || len(r.Errors) != 0 {
+		t.Errorf("signing was incomplete: %+v", r.Errors)
+	}
+	if len(r.Inputs) != 1 || r.Inputs[0].Amount == nil ||
+		*r.Inputs[0].Amount != 3 || r.Inputs[0].Source != "request" {
+		t.Errorf("input values %+v, want 3 coins from the request", r.Inputs)
+	}
+	if r.Fee == nil || *r.Fee != 2 {
+		t.Errorf("fee %v, want 2 coins", r.Fee)
+	}
+}
+
+func TestSignRawTransactionBlissKey(t *testing.T) {
+	w, teardown := testOmniWallet(t)
+	defer teardown()
+
+	// WIFs hold bliss keys as they are parsed from their serialization.
+	generated, _, err := bliss.Bliss.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey, _ := bliss.Bliss.PrivKeyFromBytes(generated.Serialize())
+	wif, err := hcutil.NewWIF(privKey, w.ChainParams(), bliss.BSTypeBliss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := hcutil.NewAddressBlissPubKey(wif.SerializePubKey(), w.ChainParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr.AddressPubKeyHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := signWithKey(t, w, wif, pkScript, 3)
+	if !r.Complete || len(r.Errors) != 0 {
+		t.Errorf("signing with a bliss key was incomplete: %+v", r.Errors)
+	}
+}
+
+func TestGetInfoWithoutConsensusServer(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+
+	s := &Server{walletLoader: l}
+	req := &hcjson.Request{Jsonrpc: "1.0", Method: "getinfo"}
+
+	res, jsonErr := s.handlerClosure(context.Background(), req)()
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	info, ok := res.(*hcjson.InfoWalletResult)
+	if !ok {
+		t.Fatalf("legacy result has type %T", res)
+	}
+	if info.WalletVersion != udb.DBVersion || !info.TestNet ||
+		info.Blocks != 0 || info.Version != 0 {
+		t.Errorf("legacy result %+v", info)
+	}
+
+	ctx := withResultVersion(context.Background(), resultVersionExtended)
+	res, jsonErr = s.handlerClosure(ctx, req)()
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	extended, ok := res.(*hcjson.GetInfoResult)
+	if !ok {
+		t.Fatalf("extended result has type %T", res)
+	}
+	w, _ := l.LoadedWallet()
+	if extended.WalletVersion != udb.DBVersion || !extended.TestNet ||
+		extended.Unlocked != !w.Locked() || extended.SyncHeight != 0 {
+		t.Errorf("extended result %+v", extended)
+	}
+	// Fields describing the consensus server are null without it.
+	if extended.Version != nil || extended.Blocks != nil ||
+		extended.Connections != nil || extended.RelayFee != nil ||
+		extended.HeaderHeight != nil || extended.Progress != nil {
+		t.Errorf("extended result describes a consensus server: %+v",
+			extended)
+	}
+}
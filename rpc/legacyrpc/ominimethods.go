@@ -8,6 +8,7 @@ package legacyrpc
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -20,16 +21,14 @@ import (
 	"github.com/HcashOrg/hcd/txscript"
 	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcwallet/apperrors"
+	h "github.com/HcashOrg/hcwallet/internal/helpers"
 	"github.com/HcashOrg/hcwallet/omnilib"
 	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
 	"github.com/HcashOrg/hcwallet/wallet/txrules"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 )
 
-const (
-	MininumAmount = 1000000
-)
-
 func getOminiMethod() map[string]LegacyRpcHandler {
 	return map[string]LegacyRpcHandler{
 
@@ -91,6 +90,8 @@ func getOminiMethod() map[string]LegacyRpcHandler {
 		"omni_createrawtx_multisig":              {handler: OmniCreaterawtxMultisig},
 		"omni_createrawtx_input":                 {handler: OmniCreaterawtxInput},
 		"omni_createrawtx_reference":             {handler: OmniCreaterawtxReference},
+		"omni_getdustthreshold":                  {handler: OmniGetdustthreshold},
+		"omni_estimatefee":                       {handler: OmniEstimatefee},
 		"omni_createrawtx_change":                {handler: OmniCreaterawtxChange},
 		"omni_createpayload_sendall":             {handler: OmniCreatepayloadSendall},
 		"omni_createpayload_dexsell":             {handler: OmniCreatepayloadDexsell},
@@ -169,22 +170,17 @@ func OmniSendAgreement(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 //add by ycj 20180915
 //commonly used cmd request
 func omni_cmdReq(icmd interface{}, w *wallet.Wallet) (json.RawMessage, error) {
-	byteCmd, err := hcjson.MarshalCmd(1, icmd)
-	if err != nil {
-		return nil, err
-	}
-
-	strRsp := omnilib.JsonCmdReqHcToOm(string(byteCmd))
+	return omnilib.SendCmd(icmd)
+}
 
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
+// omniPendingAdd records a transaction that has already been published with
+// the omni engine.  Failures are logged rather than returned since the
+// transaction can not be recalled.
+func omniPendingAdd(w *wallet.Wallet, cmd interface{}) {
+	err := w.OmniPendingAdd(cmd.(*hcjson.OmniPendingAddCmd))
 	if err != nil {
-		return nil, err
-	}
-	if response.Error != nil {
-		return nil, fmt.Errorf(response.Error.Message)
+		log.Errorf("Failed to add pending omni transaction: %v", err)
 	}
-	return response.Result, nil
 }
 
 //
@@ -193,18 +189,7 @@ func omni_getinfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 }
 
 func omni_createpayload_simplesend(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*hcjson.OmniCreatepayloadSimplesendCmd)
-	byteCmd, err := hcjson.MarshalCmd(1, cmd)
-	if err != nil {
-		return nil, err
-	}
-	strReq := string(byteCmd)
-	strRsp := omnilib.JsonCmdReqHcToOm(strReq)
-
-	var response hcjson.Response
-	_ = json.Unmarshal([]byte(strRsp), &response)
-
-	return response.Result, nil
+	return omni_cmdReq(icmd, w)
 }
 
 func omni_createpayload_issuancefixed(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -247,7 +232,7 @@ func sendIssuanceFixed(w *wallet.Wallet, payLoad []byte) (string, error) {
 	account := uint32(udb.DefaultAccountNum)
 
 	var changeAddr string
-	addr, err := w.FirstAddr(account)
+	addr, err := w.AccountDefaultAddress(account)
 	if err != nil {
 		return "", err
 	}
@@ -278,6 +263,9 @@ func OmniSendchangeissuer(icmd interface{}, w *wallet.Wallet) (interface{}, erro
 	}
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
 	addr, err := decodeAddress(omniSendchangeissuerCmd.Fromaddress, w.ChainParams())
 	if err != nil {
 		return nil, err
@@ -290,8 +278,12 @@ func OmniSendchangeissuer(icmd interface{}, w *wallet.Wallet) (interface{}, erro
 	if err != nil {
 		return nil, err
 	}
+	refAmount, err := omniReferenceAmount(w, omniSendchangeissuerCmd.Toaddress, omniSendchangeissuerCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniSendchangeissuerCmd.Toaddress: MininumAmount,
+		omniSendchangeissuerCmd.Toaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, omniSendchangeissuerCmd.Fromaddress, payLoad, omniSendchangeissuerCmd.Fromaddress)
 }
@@ -312,8 +304,15 @@ func OmniSendenablefreezing(icmd interface{}, w *wallet.Wallet) (interface{}, er
 
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
+	refAmount, err := omniReferenceAmount(w, omniSendenablefreezingCmd.Fromaddress, omniSendenablefreezingCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniSendenablefreezingCmd.Fromaddress: MininumAmount,
+		omniSendenablefreezingCmd.Fromaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, omniSendenablefreezingCmd.Fromaddress, payLoad, omniSendenablefreezingCmd.Fromaddress)
 }
@@ -333,8 +332,15 @@ func OmniSenddisablefreezing(icmd interface{}, w *wallet.Wallet) (interface{}, e
 	}
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
+	refAmount, err := omniReferenceAmount(w, omniSenddisablefreezingCmd.Fromaddress, omniSenddisablefreezingCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniSenddisablefreezingCmd.Fromaddress: MininumAmount,
+		omniSenddisablefreezingCmd.Fromaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, omniSenddisablefreezingCmd.Fromaddress, payLoad, omniSenddisablefreezingCmd.Fromaddress)
 }
@@ -358,8 +364,15 @@ func OmniSendfreeze(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
+	refAmount, err := omniReferenceAmount(w, omniSendfreezeCmd.Toaddress, omniSendfreezeCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniSendfreezeCmd.Toaddress: MininumAmount,
+		omniSendfreezeCmd.Toaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, "", payLoad, omniSendfreezeCmd.Fromaddress)
 }
@@ -383,8 +396,15 @@ func OmniSendunfreeze(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
+	refAmount, err := omniReferenceAmount(w, omniSendunfreezeCmd.Toaddress, omniSendunfreezeCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniSendunfreezeCmd.Toaddress: MininumAmount,
+		omniSendunfreezeCmd.Toaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, "", payLoad, omniSendunfreezeCmd.Fromaddress)
 }
@@ -412,8 +432,15 @@ func OmniFundedSend(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
+	refAmount, err := omniReferenceAmount(w, omniFundedSendCmd.Toaddress, omniFundedSendCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniFundedSendCmd.Toaddress: MininumAmount,
+		omniFundedSendCmd.Toaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, omniFundedSendCmd.Feeaddress, payLoad, omniFundedSendCmd.Fromaddress)
 }
@@ -441,8 +468,15 @@ func OmniFundedSendall(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 	}
 	hexStr := strings.Trim(string(ret), "\"")
 	payLoad, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, err
+	}
+	refAmount, err := omniReferenceAmount(w, omniFundedSendallCmd.Toaddress, omniFundedSendallCmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
 	pairs := map[string]hcutil.Amount{
-		omniFundedSendallCmd.Toaddress: MininumAmount,
+		omniFundedSendallCmd.Toaddress: refAmount,
 	}
 	return sendPairsWithPayLoad(w, pairs, account, 1, omniFundedSendallCmd.Feeaddress, payLoad, omniFundedSendallCmd.Fromaddress)
 }
@@ -485,13 +519,8 @@ func omniSend(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, newCmd)
-	if err != nil {
-		log.Errorf("omni send error %v", err)
-		return nil, err
-	}
 	//construct omni variables
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON))
+	omniPendingAdd(w, newCmd)
 	return final, err
 }
 
@@ -530,9 +559,13 @@ func omniSendToAddress(cmd *SendFromAddressToAddress, w *wallet.Wallet, payLoad
 	if err != nil {
 		return "", err
 	}
+	refAmount, err := omniReferenceAmount(w, cmd.ToAddress, cmd.FromAddress)
+	if err != nil {
+		return "", err
+	}
 	// Mock up map of address and amount pairs.
 	pairs := map[string]hcutil.Amount{
-		cmd.ToAddress: MininumAmount,
+		cmd.ToAddress: refAmount,
 	}
 
 	return sendPairsWithPayLoad(w, pairs, account, 1, cmd.ChangeAddress, payLoad, cmd.FromAddress)
@@ -550,20 +583,7 @@ func OmniGetwalletbalances(icmd interface{}, w *wallet.Wallet) (interface{}, err
 		Method: "omni_getwalletbalances",
 		Params: []interface{}{addresses},
 	}
-	bytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-	strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
-	if err != nil {
-		return nil, err
-	}
-	if response.Error != nil {
-		return nil, fmt.Errorf(response.Error.Message)
-	}
-	return response.Result, nil
+	return omnilib.Send(&req)
 }
 
 // OmniGetwalletaddressbalances Returns a list of all token balances for every wallet address.
@@ -578,20 +598,7 @@ func OmniGetwalletaddressbalances(icmd interface{}, w *wallet.Wallet) (interface
 		Method: "omni_getwalletaddressbalances",
 		Params: []interface{}{addresses},
 	}
-	bytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-	strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
-	if err != nil {
-		return nil, err
-	}
-	if response.Error != nil {
-		return nil, fmt.Errorf(response.Error.Message)
-	}
-	return response.Result, nil
+	return omnilib.Send(&req)
 }
 
 // OmniListblocktransactions Lists all Omni transactions in a block.
@@ -607,20 +614,7 @@ func OmniListblocktransactions(icmd interface{}, w *wallet.Wallet) (interface{},
 		Method: "omni_listblocktransactions",
 		Params: []interface{}{omniListblocktransactionsCmd.Height, addresses},
 	}
-	bytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-	strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
-	if err != nil {
-		return nil, err
-	}
-	if response.Error != nil {
-		return nil, fmt.Errorf(response.Error.Message)
-	}
-	return response.Result, nil
+	return omnilib.Send(&req)
 }
 
 // OmniListpendingtransactions Returns a list of unconfirmed Omni transactions, pending in the memory pool.,Note: the validity of pending transactions is uncertain, and the state of the memory pool may change at any moment. It is recommended to check transactions after confirmation, and pending transactions should be considered as invalid.
@@ -642,37 +636,117 @@ func OmniListpendingtransactions(icmd interface{}, w *wallet.Wallet) (interface{
 		Method: "omni_listpendingtransactions",
 		Params: []interface{}{addresses},
 	}
-	bytes, err := json.Marshal(req)
+	return omnilib.Send(&req)
+}
+
+// makeOmniOutputs returns the outputs of an omni transaction sent from
+// fromAddress, paying amounts and carrying payLoad in a nulldata output, and
+// the account which funds it.
+func makeOmniOutputs(w *wallet.Wallet, amounts map[string]hcutil.Amount, payLoad []byte, fromAddress string) ([]*wire.TxOut, uint32, error) {
+	outputs, err := makeOutputs(amounts, w.ChainParams())
+	if err != nil {
+		return nil, 0, err
+	}
+	payloadNullDataOutput, err := w.MakeNulldataOutput(payLoad)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	outputs = append(outputs, payloadNullDataOutput)
+	account, err := detechAccount(w, fromAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Reject outputs which would make the transaction unrelayable.
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, output := range outputs {
+		err = txrules.CheckOutput(output, relayFee)
+		if err != nil {
+			return nil, 0, InvalidParameterError{err}
+		}
+	}
+	return outputs, account, nil
+}
+
+// OmniEstimatefee estimates the fee of an omni transaction sending a payload
+// from an address, constructing the transaction as sendPairsWithPayLoad would
+// without signing or publishing it.
+func OmniEstimatefee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.OmniEstimatefeeCmd)
+	payLoad, err := decodeHexStr(cmd.Payload)
 	if err != nil {
 		return nil, err
 	}
-	strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
+	// Omni payloads begin with a 2 byte version followed by a 2 byte
+	// transaction type.
+	if len(payLoad) < 4 {
+		return nil, InvalidParameterError{errors.New("payload is too short")}
+	}
+	toAddress := cmd.Fromaddress
+	if cmd.Toaddress != nil {
+		toAddress = *cmd.Toaddress
+	}
+	var changeAddr string
+	if cmd.Changeaddress != nil {
+		_, err = decodeAddress(*cmd.Changeaddress, w.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+		changeAddr = *cmd.Changeaddress
+	}
+
+	refAmount, err := omniReferenceAmount(w, toAddress, cmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
+	pairs := map[string]hcutil.Amount{
+		toAddress: refAmount,
+	}
+	outputs, account, err := makeOmniOutputs(w, pairs, payLoad, cmd.Fromaddress)
+	if err != nil {
+		return nil, err
+	}
+	atx, err := w.EstimateOutputs(outputs, account, 1, changeAddr, cmd.Fromaddress)
+	if err != nil {
+		if _, ok := err.(txauthor.InsufficientAddressFundsError); ok {
+			return nil, &hcjson.RPCError{
+				Code:    hcjson.ErrRPCWalletInsufficientFunds,
+				Message: err.Error(),
+			}
+		}
+		return nil, err
+	}
+	relayFee, err := w.AccountRelayFee(account)
 	if err != nil {
 		return nil, err
 	}
-	if response.Error != nil {
-		return nil, fmt.Errorf(response.Error.Message)
+
+	var change hcutil.Amount
+	if atx.ChangeIndex >= 0 {
+		change = hcutil.Amount(atx.Tx.TxOut[atx.ChangeIndex].Value)
 	}
-	return response.Result, nil
+	fee := atx.TotalInput - h.SumOutputValues(atx.Tx.TxOut)
+	return &hcjson.OmniEstimatefeeResult{
+		Type:            binary.BigEndian.Uint16(payLoad[2:4]),
+		Fee:             fee.ToCoin(),
+		RelayFee:        relayFee.ToCoin(),
+		Size:            atx.EstimatedSignedSerializeSize,
+		Inputs:          len(atx.Tx.TxIn),
+		TotalInput:      atx.TotalInput.ToCoin(),
+		ReferenceAmount: refAmount.ToCoin(),
+		Change:          change.ToCoin(),
+	}, nil
 }
 
 // sendPairsWithPayLoad creates and sends payment transactions.
 // It returns the transaction hash in string format upon success
 // All errors are returned in hcjson.RPCError format
 func sendPairsWithPayLoad(w *wallet.Wallet, amounts map[string]hcutil.Amount, account uint32, minconf int32, changeAddr string, payLoad []byte, fromAddress string) (string, error) {
-	outputs, err := makeOutputs(amounts, w.ChainParams())
-	if err != nil {
-		return "", err
-	}
-	payloadNullDataOutput, err := w.MakeNulldataOutput(payLoad)
-	if err != nil {
-		return "", err
-	}
-
-	outputs = append(outputs, payloadNullDataOutput)
-	account, err = detechAccount(w, fromAddress)
+	outputs, account, err := makeOmniOutputs(w, amounts, payLoad, fromAddress)
 	if err != nil {
 		return "", err
 	}
@@ -681,6 +755,12 @@ func sendPairsWithPayLoad(w *wallet.Wallet, amounts map[string]hcutil.Amount, ac
 		if err == txrules.ErrAmountNegative {
 			return "", ErrNeedPositiveAmount
 		}
+		if _, ok := err.(txauthor.InsufficientAddressFundsError); ok {
+			return "", &hcjson.RPCError{
+				Code:    hcjson.ErrRPCWalletInsufficientFunds,
+				Message: err.Error(),
+			}
+		}
 		if apperrors.IsError(err, apperrors.ErrLocked) {
 			return "", &ErrWalletUnlockNeeded
 		}
@@ -754,7 +834,6 @@ func OmniSenddexsell(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		FromAddress:   omniSenddexsellCmd.Fromaddress,
 		ChangeAddress: omniSenddexsellCmd.Fromaddress,
 		ToAddress:     omniSenddexsellCmd.Fromaddress,
-		Amount:        MininumAmount,
 	}
 	txid, err := omniSendToAddress(cmd, w, payLoad)
 	if err != nil {
@@ -772,12 +851,8 @@ func OmniSenddexsell(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, newCmd)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(marshalledJSON))
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON)) //construct omni variables
+	//construct omni variables
+	omniPendingAdd(w, newCmd)
 
 	return txid, err
 
@@ -803,7 +878,6 @@ func OmniSenddexaccept(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 		FromAddress:   omniSenddexacceptCmd.Fromaddress,
 		ChangeAddress: omniSenddexacceptCmd.Fromaddress,
 		ToAddress:     omniSenddexacceptCmd.Toaddress,
-		Amount:        MininumAmount, // > Minacceptfee
 	}
 	txid, err := omniSendToAddress(cmd, w, payLoad)
 	if err != nil {
@@ -923,13 +997,8 @@ func OmniSendsto(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, newCmd)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(marshalledJSON))
 	//construct omni variables
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON))
+	omniPendingAdd(w, newCmd)
 	return final, err
 }
 
@@ -1087,12 +1156,8 @@ func OmniSendcanceltradesbyprice(icmd interface{}, w *wallet.Wallet) (interface{
 	if err != nil {
 		return nil, err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, newCmd)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(marshalledJSON))
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON)) //construct omni variables
+	//construct omni variables
+	omniPendingAdd(w, newCmd)
 
 	return txid, nil
 }
@@ -1143,12 +1208,8 @@ func OmniSendcanceltradesbypair(icmd interface{}, w *wallet.Wallet) (interface{}
 	if err != nil {
 		return nil, err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, newCmd)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(marshalledJSON))
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON)) //construct omni variables
+	//construct omni variables
+	omniPendingAdd(w, newCmd)
 
 	return txid, nil
 
@@ -1199,12 +1260,8 @@ func OmniSendcancelalltrades(icmd interface{}, w *wallet.Wallet) (interface{}, e
 	if err != nil {
 		return nil, err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, newCmd)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(marshalledJSON))
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON)) //construct omni variables
+	//construct omni variables
+	omniPendingAdd(w, newCmd)
 
 	return txid, nil
 }
@@ -1295,20 +1352,7 @@ func OmniListwallettransactions(icmd interface{}, w *wallet.Wallet) (interface{}
 		Params: []interface{}{addresses,cmd.Count, cmd.Skip, cmd.Startblock, cmd.Endblock},
 
 	}
-	bytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-	strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
-	if err != nil {
-		return nil, err
-	}
-	if response.Error != nil {
-		return nil, fmt.Errorf(response.Error.Message)
-	}
-	return response.Result, nil
+	return omnilib.Send(&req)
 }
 
 // OmniGetactivedexsells Returns currently active offers on the distributed exchange.
@@ -1524,8 +1568,20 @@ func OmniCreaterawtxReference(icmd interface{}, w *wallet.Wallet) (interface{},
 	if err != nil {
 		return nil, fmt.Errorf("cannot create txout script: %s", err)
 	}
-	var amount int64 = MininumAmount
+	// Reference outputs below the dust threshold would not be relayed.
+	// Transactions without a sending address are valued with the default
+	// account's relay fee, like omni_getdustthreshold.
+	dust, err := omniReferenceAmount(w, cmd.Destination, "")
+	if err != nil {
+		return nil, err
+	}
+	amount := int64(dust)
 	if cmd.Amount != nil {
+		if *cmd.Amount < amount {
+			e := fmt.Errorf("reference amount %v is below the dust "+
+				"threshold %v", hcutil.Amount(*cmd.Amount), dust)
+			return nil, InvalidParameterError{e}
+		}
 		amount = *cmd.Amount
 	}
 	tx.AddTxOut(wire.NewTxOut(amount, pkScript))
@@ -1537,6 +1593,43 @@ func OmniCreaterawtxReference(icmd interface{}, w *wallet.Wallet) (interface{},
 	return hex.EncodeToString(buf.Bytes()), nil
 }
 
+// OmniGetdustthreshold returns the minimum value of an output paying to an
+// address which is relayed under the relay fee of the sending account.  This
+// is the value of the reference outputs of omni transactions sent from the
+// sending address.  A pay-to-pubkey-hash output script is assumed when no
+// address is provided, and the default account's relay fee is used when no
+// sending address is provided.
+func OmniGetdustthreshold(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*hcjson.OmniGetdustthresholdCmd)
+
+	// Size of a pay-to-pubkey-hash output script.
+	scriptSize := 25
+	if cmd.Address != nil {
+		addr, err := decodeAddress(*cmd.Address, w.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		scriptSize = len(pkScript)
+	}
+	var fromAddress string
+	if cmd.FromAddress != nil {
+		fromAddress = *cmd.FromAddress
+	}
+	relayFee, err := omniRelayFee(w, fromAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &hcjson.OmniGetdustthresholdResult{
+		Threshold:  txrules.DustThreshold(scriptSize, relayFee).ToCoin(),
+		RelayFee:   relayFee.ToCoin(),
+		ScriptSize: scriptSize,
+	}, nil
+}
+
 // OmniCreaterawtxChange Adds a change output to the transaction.,The provided inputs are not added to the transaction, but only used to determine the change. It is assumed that the inputs were previously added, for example via `"createrawtransaction"`.,Optionally a position can be provided, where the change output should be inserted, starting with `0`. If the number of outputs is smaller than the position, then the change output is added to the end. Change outputs should be inserted before reference outputs, and as per default, the change output is added to the`first position.,If the change amount would be considered as dust, then no change output is added.
 // $ omnicore-cli "omni_createrawtx_change" \     "0100000001b15ee60431ef57ec682790dec5a3c0d83a0c360633ea8308fbf6d5fc10a779670400000000ffffffff025c0d00000 \     000000047512102f3e471222bb57a7d416c82bf81c627bfcd2bdc47f36e763ae69935bba4601ece21021580b888ff56feb27f17f \     08802ebed26258c23697d6a462d43fc13b565fda2dd52aeaa0a0000000000001976a914946cb2e08075bcbaf157e47bcb67eb2b2 \     339d24288ac00000000" \     "[{\"txid\":\"6779a710fcd5f6fb0883ea3306360c3ad8c0a3c5de902768ec57ef3104e65eb1\",\"vout\":4, \     \"scriptPubKey\":\"76a9147b25205fd98d462880a3e5b0541235831ae959e588ac\",\"value\":0.00068257}]" \     "1CE8bBr1dYZRMnpmyYsFEoexa1YoPz2mfB" 0.000035 1
 func OmniCreaterawtxChange(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -1580,7 +1673,6 @@ func OmniCreaterawtxChange(icmd interface{}, w *wallet.Wallet) (interface{}, err
 		return nil, fmt.Errorf("cannot create txout script: %s", err)
 	}
 
-	//MininumAmount
 	tx.AddTxOut(wire.NewTxOut(cmd.Fee, pkScript))
 	var buf bytes.Buffer
 	if err := tx.BtcEncode(&buf, wire.MaxBlockSizeVersion); err != nil {
@@ -1765,18 +1857,59 @@ func OmniSetautocommit(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 	return omni_cmdReq(icmd, w)
 }
 
-// detechAccount indicate the address is default or bliss
+// omniReferenceAmount returns the value of an omni reference output paying to
+// toAddress from a transaction sent from fromAddress.  This is the dust
+// threshold of the output script under the relay fee of the sending account,
+// as reference outputs only identify the recipient of the transaction.
+func omniReferenceAmount(w *wallet.Wallet, toAddress, fromAddress string) (hcutil.Amount, error) {
+	addr, err := decodeAddress(toAddress, w.ChainParams())
+	if err != nil {
+		return 0, err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return 0, err
+	}
+	relayFee, err := omniRelayFee(w, fromAddress)
+	if err != nil {
+		return 0, err
+	}
+	return txrules.DustThreshold(len(pkScript), relayFee), nil
+}
+
+// omniRelayFee returns the relay fee used to value the reference outputs of
+// omni transactions sent from fromAddress, which is the relay fee of the
+// account owning the address.  The default account's relay fee is used when
+// fromAddress is empty.
+func omniRelayFee(w *wallet.Wallet, fromAddress string) (hcutil.Amount, error) {
+	account := uint32(udb.DefaultAccountNum)
+	if fromAddress != "" {
+		var err error
+		account, err = detechAccount(w, fromAddress)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return w.AccountRelayFee(account)
+}
+
+// detechAccount returns the account which owns the sending address of an omni
+// transaction.
 func detechAccount(w *wallet.Wallet, addrStr string) (uint32, error) {
-	addr, err := hcutil.DecodeAddress(addrStr)
+	addr, err := decodeAddress(addrStr, w.ChainParams())
 	if err != nil {
-		return 0, nil
+		return 0, err
 	}
-	account := 0
-	acc_type := addr.DSA(w.ChainParams())
-	if acc_type == 4 {
-		account = 1
+	// The sending address must belong to the wallet, and its outputs are
+	// only selected from the account which owns it.
+	account, err := w.AccountOfAddress(addr)
+	if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+		return 0, &hcjson.RPCError{
+			Code:    hcjson.ErrRPCInvalidAddressOrKey,
+			Message: "sending address is not owned by the wallet",
+		}
 	}
-	return uint32(account), nil
+	return account, err
 }
 
 // getWalletAddress return all addresses in wallet
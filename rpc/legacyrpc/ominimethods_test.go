@@ -0,0 +1,193 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+// testOmniWallet returns a new simnet wallet and a function to unload it.
+func testOmniWallet(t *testing.T) (*wallet.Wallet, func()) {
+	dir, err := ioutil.TempDir("", "legacyrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := loader.NewLoader(&chaincfg.SimNetParams, dir, &loader.StakeOptions{},
+		20, false, txauthor.TxLimits{}, false, 0.001, false)
+	w, err := l.CreateNewWallet([]byte("public"), []byte("private"),
+		bytes.Repeat([]byte{1}, 32), nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return w, func() {
+		l.UnloadWallet()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestOmniDustThresholdAccountRelayFee(t *testing.T) {
+	w, teardown := testOmniWallet(t)
+	defer teardown()
+
+	relayFee := w.RelayFee() * 5
+	err := w.SetAccountRelayFee(udb.DefaultAccountNum, relayFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The transaction pays the sending address, which must be recorded by
+	// the address manager.
+	err = w.ExtendWatchedAddresses(udb.DefaultAccountNum, udb.ExternalBranch, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := w.NewExternalAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := from
+	reference, err := omniReferenceAmount(w, to.EncodeAddress(),
+		from.EncodeAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	toStr, fromStr := to.EncodeAddress(), from.EncodeAddress()
+	for _, fromAddress := range []*string{&fromStr, nil} {
+		res, err := OmniGetdustthreshold(hcjson.NewOmniGetdustthresholdCmd(
+			&toStr, fromAddress), w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := res.(*hcjson.OmniGetdustthresholdResult)
+		if r.RelayFee != relayFee.ToCoin() {
+			t.Errorf("dust threshold relay fee %v, want account relay fee %v",
+				r.RelayFee, relayFee.ToCoin())
+		}
+		threshold, err := hcutil.NewAmount(r.Threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if threshold != reference {
+			t.Errorf("dust threshold %v differs from reference amount %v",
+				threshold, reference)
+		}
+	}
+}
+
+func TestOmniCreaterawtxReference(t *testing.T) {
+	w, teardown := testOmniWallet(t)
+	defer teardown()
+
+	relayFee := w.RelayFee() * 5
+	err := w.SetAccountRelayFee(udb.DefaultAccountNum, relayFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.CurrentAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := addr.EncodeAddress()
+	dust, err := omniReferenceAmount(w, dest, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The reference output is valued with the default account's relay fee
+	// when no amount is provided, and larger amounts are kept.
+	for _, amount := range []*int64{nil, hcjson.Int64(int64(dust) + 1)} {
+		res, err := OmniCreaterawtxReference(hcjson.NewOmniCreaterawtxReferenceCmd(
+			"", dest, amount), w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serialized, err := hex.DecodeString(res.(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tx wire.MsgTx
+		err = tx.Deserialize(bytes.NewReader(serialized))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := int64(dust)
+		if amount != nil {
+			want = *amount
+		}
+		if len(tx.TxOut) != 1 || tx.TxOut[0].Value != want {
+			t.Errorf("reference outputs %v, want one output of %v",
+				tx.TxOut, hcutil.Amount(want))
+		}
+	}
+
+	// Amounts below the dust threshold are rejected.
+	_, err = OmniCreaterawtxReference(hcjson.NewOmniCreaterawtxReferenceCmd(
+		"", dest, hcjson.Int64(int64(dust)-1)), w)
+	if _, ok := err.(InvalidParameterError); !ok {
+		t.Errorf("below dust reference amount returned %v, want an "+
+			"invalid parameter error", err)
+	}
+}
+
+func TestOmniEstimatefeeErrors(t *testing.T) {
+	w, teardown := testOmniWallet(t)
+	defer teardown()
+
+	err := w.ExtendWatchedAddresses(udb.DefaultAccountNum, udb.ExternalBranch, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := w.NewExternalAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromStr := from.EncodeAddress()
+	// A simple send payload: version 0, type 0, property 1, amount 1.
+	const payload = "00000000000000010000000000000001"
+
+	// Payloads without a version and transaction type are rejected.
+	_, err = OmniEstimatefee(hcjson.NewOmniEstimatefeeCmd(fromStr, "0000",
+		nil, nil), w)
+	if _, ok := err.(InvalidParameterError); !ok {
+		t.Errorf("estimating a short payload returned %v, want "+
+			"InvalidParameterError", err)
+	}
+
+	// The sending address must be owned by the wallet.
+	foreign, err := hcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.SimNetParams, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = OmniEstimatefee(hcjson.NewOmniEstimatefeeCmd(
+		foreign.EncodeAddress(), payload, &fromStr, nil), w)
+	if e, ok := err.(*hcjson.RPCError); !ok || e.Code != hcjson.ErrRPCInvalidAddressOrKey {
+		t.Errorf("estimating from a foreign address returned %v, want "+
+			"ErrRPCInvalidAddressOrKey", err)
+	}
+
+	// An unfunded sending address reports insufficient funds.
+	_, err = OmniEstimatefee(hcjson.NewOmniEstimatefeeCmd(fromStr, payload,
+		nil, nil), w)
+	if e, ok := err.(*hcjson.RPCError); !ok || e.Code != hcjson.ErrRPCWalletInsufficientFunds {
+		t.Errorf("estimating from an unfunded address returned %v, want "+
+			"ErrRPCWalletInsufficientFunds", err)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/loader"
+)
+
+// readOnlyMethods are the methods served by read-only replica wallets.  None
+// of these modify the wallet database, which is only changed by replicating
+// the primary wallet.  Replicas hold the private passphrase of the primary, so
+// methods revealing private keys or changing the logging of the process are
+// not served either.
+var readOnlyMethods = map[string]bool{
+	"checkaddressreuse":        true,
+	"decodewallettransaction":  true,
+	"delegatedtickets":         true,
+	"exporttransactions":       true,
+	"exportvotechoices":        true,
+	"getaccount":               true,
+	"getaccountdefaultaddress": true,
+	"getaddressesbyaccount":    true,
+	"getauditlog":              true,
+	"getbalance":               true,
+	"getbalanceathash":         true,
+	"getbalanceatheight":       true,
+	"getbestblock":             true,
+	"getbestblockhash":         true,
+	"getblockcount":            true,
+	"gethealth":                true,
+	"getinfo":                  true,
+	"getinvoicepayments":       true,
+	"getmasterpubkey":          true,
+	"getmultisigaccountinfo":   true,
+	"getmultisigoutinfo":       true,
+	"getreceivedbyaccount":     true,
+	"getreceivedbyaddress":     true,
+	"getrecoverystate":         true,
+	"getreplicationinfo":       true,
+	"getrescaninfo":            true,
+	"getspendableconfs":        true,
+	"getstakeinfo":             true,
+	"getstakerewards":          true,
+	"getticketfee":             true,
+	"gettickets":               true,
+	"gettransaction":           true,
+	"getunconfirmedbalance":    true,
+	"getvotechoices":           true,
+	"getwalletfee":             true,
+	"help":                     true,
+	"listaccounts":             true,
+	"listaddresstransactions":  true,
+	"listexternalbranches":     true,
+	"listalltransactions":      true,
+	"listlockunspent":          true,
+	"listpoolfeeexemptions":    true,
+	"listreceivedbyaccount":    true,
+	"listreceivedbyaddress":    true,
+	"listrefundablescripts":    true,
+	"listsinceblock":           true,
+	"listswaps":                true,
+	"listscripts":              true,
+	"listtransactions":         true,
+	"listunspent":              true,
+	"promotereplica":           true,
+	"stakepooluserinfo":        true,
+	"ticketsforaddress":        true,
+	"validateaddress":          true,
+	"verifyaccountproof":       true,
+	"verifymessage":            true,
+	"verifyticketproof":        true,
+	"version":                  true,
+	"walletinfo":               true,
+	"walletislocked":           true,
+}
+
+// serveSnapshot writes a snapshot of the default wallet's database for a
+// replica follower.  The SHA-256 digest of the snapshot is sent as a trailer
+// once the snapshot is completely written.
+func (s *Server) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	wallet, ok := s.walletLoader.LoadedWallet()
+	if !ok {
+		http.Error(w, "503 Wallet Not Loaded.", http.StatusServiceUnavailable)
+		return
+	}
+	_, height := wallet.MainChainTip()
+	journal := s.walletLoader.Journal().Position()
+
+	h := w.Header()
+	h.Set("Content-Type", "application/octet-stream")
+	h.Set(loader.SnapshotTimeHeader, strconv.FormatInt(time.Now().Unix(), 10))
+	h.Set(loader.SnapshotHeightHeader, strconv.FormatInt(int64(height), 10))
+	h.Set(loader.SnapshotDriverHeader, s.walletLoader.DatabaseDriver())
+	h.Set(loader.SnapshotJournalIDHeader, journal.ID)
+	h.Set(loader.SnapshotJournalSeqHeader, strconv.FormatUint(journal.Seq, 10))
+	h.Set("Trailer", loader.SnapshotDigestHeader)
+
+	digest := sha256.New()
+	err := s.walletLoader.CopyDatabase(io.MultiWriter(w, digest))
+	if err != nil {
+		// The status was already written with the first write of the
+		// copy, so the missing digest trailer is the only indication
+		// of failure for the follower.
+		log.Errorf("Failed to write database snapshot for client %s: %v",
+			r.RemoteAddr, err)
+		return
+	}
+	h.Set(loader.SnapshotDigestHeader, hex.EncodeToString(digest.Sum(nil)))
+	log.Infof("Served database snapshot at height %d to client %s", height,
+		r.RemoteAddr)
+}
+
+// serveJournal responds to a replica follower tailing the default wallet's
+// journal once a change is committed after the position of the request, or
+// after loader.JournalWait when no change is committed.
+func (s *Server) serveJournal(w http.ResponseWriter, r *http.Request) {
+	var pos loader.JournalPosition
+	query := r.URL.Query()
+	pos.ID = query.Get("id")
+	if seq := query.Get("seq"); seq != "" {
+		var err error
+		pos.Seq, err = strconv.ParseUint(seq, 10, 64)
+		if err != nil {
+			http.Error(w, "400 Bad Request.", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), loader.JournalWait)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	status := s.walletLoader.Journal().Wait(ctx, pos)
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(&status)
+	if err != nil {
+		log.Warnf("Failed to write journal status to client %s: %v",
+			r.RemoteAddr, err)
+	}
+}
+
+// promoteReplica handles a promotereplica request by stopping replication and
+// serving the replica wallet as a primary wallet which may be modified.
+func promoteReplica(icmd interface{}, l *loader.Loader) (interface{}, error) {
+	f := l.Follower()
+	if f == nil {
+		return nil, &ErrNotReplica
+	}
+	err := f.Promote()
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// getReplicationInfo handles a getreplicationinfo request by describing
+// whether the wallet is a primary or a replica, and for replicas, how far the
+// replica lags behind its primary.
+func getReplicationInfo(icmd interface{}, l *loader.Loader) (interface{}, error) {
+	var height int32
+	if w, ok := l.LoadedWallet(); ok {
+		_, height = w.MainChainTip()
+	}
+
+	f := l.Follower()
+	if f == nil {
+		return &hcjson.GetReplicationInfoResult{
+			Mode:   "primary",
+			Height: height,
+		}, nil
+	}
+
+	status := f.Status()
+	result := &hcjson.GetReplicationInfoResult{
+		Mode:           "follower",
+		Height:         height,
+		Primary:        status.Primary,
+		SnapshotHeight: status.SnapshotHeight,
+		Lag:            -1,
+		Snapshots:      status.Snapshots,
+		Applied:        status.Applied,
+	}
+	if !status.SnapshotTime.IsZero() {
+		result.SnapshotTime = status.SnapshotTime.Unix()
+		result.Lag = 0
+		if !status.Pending.IsZero() {
+			result.Lag = int64(time.Since(status.Pending) / time.Second)
+		}
+	}
+	if !status.LastAttempt.IsZero() {
+		result.LastAttempt = status.LastAttempt.Unix()
+	}
+	if status.LastErr != nil {
+		result.LastError = status.LastErr.Error()
+	}
+	return result, nil
+}
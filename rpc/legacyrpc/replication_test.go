@@ -0,0 +1,71 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+)
+
+func TestServeJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "legacyrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := loader.NewLoader(&chaincfg.SimNetParams, dir, nil, 0, false,
+		txauthor.TxLimits{}, false, 0, false)
+	s := &Server{walletLoader: l, quit: make(chan struct{})}
+
+	// Followers which have not replicated the journal are answered
+	// immediately with the current position.
+	rec := httptest.NewRecorder()
+	s.serveJournal(rec, httptest.NewRequest("GET", loader.JournalPath, nil))
+	var status loader.JournalStatus
+	err = json.Unmarshal(rec.Body.Bytes(), &status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.JournalPosition != l.Journal().Position() || status.Pending == 0 {
+		t.Errorf("journal status %+v, want pending position %+v", status,
+			l.Journal().Position())
+	}
+
+	// Requests of followers at the current position end when the server
+	// quits.
+	close(s.quit)
+	pos := l.Journal().Position()
+	rec = httptest.NewRecorder()
+	s.serveJournal(rec, httptest.NewRequest("GET",
+		loader.JournalPath+"?id="+pos.ID+"&seq=0", nil))
+	status = loader.JournalStatus{}
+	err = json.Unmarshal(rec.Body.Bytes(), &status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.JournalPosition != pos || status.Pending != 0 {
+		t.Errorf("journal status %+v, want current position %+v", status, pos)
+	}
+
+	rec = httptest.NewRecorder()
+	s.serveJournal(rec, httptest.NewRequest("GET", loader.JournalPath+"?seq=x", nil))
+	if rec.Code != 400 {
+		t.Errorf("invalid sequence answered with status %d, want 400", rec.Code)
+	}
+
+	// Wallets which are not replicas are not promoted.
+	_, err = promoteReplica(nil, l)
+	if err != &ErrNotReplica {
+		t.Errorf("promoting a primary returned %v, want ErrNotReplica", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/hcjson"
+)
+
+// Result versions select the layouts of request results.  Clients which do not
+// request a result version receive the legacy layouts, so results may be
+// extended without breaking existing parsers.  Clients opt in to extended
+// layouts with the resultversion member of the JSON-RPC request object:
+//
+//	{"jsonrpc":"1.0","id":1,"method":"...","params":[],"resultversion":2}
+const (
+	// resultVersionLegacy selects the result layouts of hcwallet releases
+	// which preceded result versions.
+	resultVersionLegacy = 1
+
+	// resultVersionExtended selects result layouts which may include
+	// fields added since result versions were introduced.
+	resultVersionExtended = 2
+
+	// maxResultVersion is the latest supported result version.
+	maxResultVersion = resultVersionExtended
+)
+
+// requestOptions are the members of a JSON-RPC request object, in addition to
+// those of hcjson.Request, which modify how the request is handled.
+type requestOptions struct {
+	ResultVersion *int `json:"resultversion"`
+}
+
+// withRequestOptions returns a context with the options of the JSON-RPC
+// request object reqBytes.  Requests without options, or with options which
+// do not parse, use the defaults.
+func withRequestOptions(parent context.Context, reqBytes []byte) context.Context {
+	var opts requestOptions
+	if json.Unmarshal(reqBytes, &opts) != nil || opts.ResultVersion == nil {
+		return parent
+	}
+	return withResultVersion(parent, *opts.ResultVersion)
+}
+
+// checkResultVersion returns an error if a result version is not supported.
+func checkResultVersion(version int) *hcjson.RPCError {
+	if version < resultVersionLegacy || version > maxResultVersion {
+		return &hcjson.RPCError{
+			Code: hcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("unsupported result version %d "+
+				"(supported versions are %d through %d)", version,
+				resultVersionLegacy, maxResultVersion),
+		}
+	}
+	return nil
+}
+
+// versionedResult is returned by handlers of requests with result layouts
+// which differ by result version.  It maps the result version introducing
+// each layout to the result in that layout, and must include the legacy
+// layout.  A layout which can not describe the result, such as a legacy layout
+// without a member for a partial failure, may instead be an *hcjson.RPCError
+// which is returned as the error of the request.
+type versionedResult map[int]interface{}
+
+// forVersion returns the result in the layout of a result version, which is
+// the layout introduced by the latest version not newer than it.
+func (r versionedResult) forVersion(version int) interface{} {
+	for v := version; v > resultVersionLegacy; v-- {
+		if res, ok := r[v]; ok {
+			return res
+		}
+	}
+	return r[resultVersionLegacy]
+}
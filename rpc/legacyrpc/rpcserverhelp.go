@@ -4,81 +4,233 @@ package legacyrpc
 
 func helpDescsEnUS() map[string]string {
 	return map[string]string{
-		"accountaddressindex":     "accountaddressindex \"account\" branch\n\nGet the current address index for some account branch\n\nArguments:\n1. account (string, required)  String for the account\n2. branch  (numeric, required) Number for the branch (0=external, 1=internal)\n\nResult:\nn (numeric) The address index for this account branch\n",
-		"accountsyncaddressindex": "accountsyncaddressindex \"account\" branch index\n\nSynchronize an account branch to some passed address index\n\nArguments:\n1. account (string, required)  String for the account\n2. branch  (numeric, required) Number for the branch (0=external, 1=internal)\n3. index   (numeric, required) The address index to synchronize to\n\nResult:\nNothing\n",
-		"addmultisigaddress":      "addmultisigaddress nrequired [\"key\",...] (\"account\")\n\nGenerates and imports a multisig address and redeeming script to the 'imported' account.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address\n3. account   (string, optional)          DEPRECATED -- Unused (all imported addresses belong to the imported account)\n\nResult:\n\"value\" (string) The imported pay-to-script-hash address\n",
-		"consolidate":             "consolidate inputs (\"account\" \"address\")\n\nConsolidate n many UTXOs into a single output in the wallet.\n\nArguments:\n1. inputs  (numeric, required) Number of UTXOs to consolidate as inputs\n2. account (string, optional)  Optional: Account from which unspent outputs are picked. When no address specified, also the account used to obtain an output address.\n3. address (string, optional)  Optional: Address to pay.  Default is obtained via getnewaddress from the account's address pool.\n\nResult:\n\"value\" (string) Transaction hash for the consolidation transaction\n",
-		"createmultisig":          "createmultisig nrequired [\"key\",...]\n\nGenerate a multisig address and redeem script.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address\n\nResult:\n{\n \"address\": \"value\",      (string) The generated pay-to-script-hash address\n \"redeemScript\": \"value\", (string) The script required to redeem outputs paid to the multisig address\n}                         \n",
-		"dumpprivkey":             "dumpprivkey \"address\"\n\nReturns the private key in WIF encoding that controls some wallet address.\n\nArguments:\n1. address (string, required) The address to return a private key for\n\nResult:\n\"value\" (string) The WIF-encoded private key\n",
-		"getaccount":              "getaccount \"address\"\n\nDEPRECATED -- Lookup the account name that some wallet address belongs to.\n\nArguments:\n1. address (string, required) The address to query the account for\n\nResult:\n\"value\" (string) The name of the account that 'address' belongs to\n",
-		"getaccountaddress":       "getaccountaddress \"account\"\n\nDEPRECATED -- Returns the most recent external payment address for an account that has not been seen publicly.\nA new address is generated for the account if the most recently generated address has been seen on the blockchain or in mempool.\n\nArguments:\n1. account (string, required) The account of the returned address\n\nResult:\n\"value\" (string) The unused address for 'account'\n",
-		"getaddressesbyaccount":   "getaddressesbyaccount \"account\"\n\nDEPRECATED -- Returns all addresses strings controlled by a single account.\n\nArguments:\n1. account (string, required) Account name to fetch addresses for\n\nResult:\n[\"value\",...] (array of string) All addresses controlled by 'account'\n",
-		"getbalance":              "getbalance (\"account\" minconf=1)\n\nCalculates and returns the balance of one or all accounts.\n\nArguments:\n1. account (string, optional)             DEPRECATED -- The account name to query the balance for, or \"*\" to consider all accounts (default=\"*\")\n2. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an unspent output's value is included in the balance\n\nResult (account != \"*\"):\nn.nnn (numeric) The balance of 'account' valued in HC\n\nResult (account = \"*\"):\nn.nnn (numeric) The balance of all accounts valued in HC\n",
-		"getbestblockhash":        "getbestblockhash\n\nReturns the hash of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The hash of the most recent synced-to block\n",
-		"getblockcount":           "getblockcount\n\nReturns the blockchain height of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) The blockchain height of the most recent synced-to block\n",
-		"getinfo":                 "getinfo\n\nReturns a JSON object containing various state info.\n\nArguments:\nNone\n\nResult:\n{\n \"version\": n,          (numeric) The version of the server\n \"protocolversion\": n,  (numeric) The latest supported protocol version\n \"walletversion\": n,    (numeric) The version of the address manager database\n \"balance\": n.nnn,      (numeric) The balance of all accounts calculated with one block confirmation\n \"blocks\": n,           (numeric) The number of blocks processed\n \"timeoffset\": n,       (numeric) The time offset\n \"connections\": n,      (numeric) The number of connected peers\n \"proxy\": \"value\",      (string)  The proxy used by the server\n \"difficulty\": n.nnn,   (numeric) The current target difficulty\n \"testnet\": true|false, (boolean) Whether or not server is using testnet\n \"keypoololdest\": n,    (numeric) Unset\n \"keypoolsize\": n,      (numeric) Unset\n \"unlocked_until\": n,   (numeric) Unset\n \"paytxfee\": n.nnn,     (numeric) The fee per kB of the serialized tx size used each time more fee is required for an authored transaction\n \"relayfee\": n.nnn,     (numeric) The minimum relay fee for non-free transactions in HC/KB\n \"errors\": \"value\",     (string)  Any current errors\n}                       \n",
-		"getmasterpubkey":         "getmasterpubkey (\"account\")\n\nRequests the master pubkey from the wallet.\n\nArguments:\n1. account (string, optional) The account to get the master pubkey for\n\nResult:\n\"value\" (string) The master pubkey for the wallet\n",
-		"getmultisigoutinfo":      "getmultisigoutinfo \"hash\" index\n\nReturns information about a multisignature output.\n\nArguments:\n1. hash  (string, required)  Input hash to check.\n2. index (numeric, required) Index of input.\n\nResult:\n{\n \"address\": \"value\",       (string)          Script address.\n \"redeemscript\": \"value\",  (string)          Hex of the redeeming script.\n \"m\": n,                   (numeric)         m (in m-of-n)\n \"n\": n,                   (numeric)         n (in m-of-n)\n \"pubkeys\": [\"value\",...], (array of string) Associated pubkeys.\n \"txhash\": \"value\",        (string)          txhash\n \"blockheight\": n,         (numeric)         Height of the containing block.\n \"blockhash\": \"value\",     (string)          Hash of the containing block.\n \"spent\": true|false,      (boolean)         If it has been spent.\n \"spentby\": \"value\",       (string)          Hash of spending tx.\n \"spentbyindex\": n,        (numeric)         Index of spending tx.\n \"amount\": n.nnn,          (numeric)         Amount of coins contained.\n}                          \n",
-		"getnewaddress":           "getnewaddress (\"account\" \"gappolicy\")\n\nGenerates and returns a new payment address.\n\nArguments:\n1. account   (string, optional) Account name the new address will belong to (default=\"default\")\n2. gappolicy (string, optional) String defining the policy to use when the BIP0044 gap limit would be violated, may be \"error\", \"ignore\", or \"wrap\"\n\nResult:\n\"value\" (string) The payment address\n",
-		"getrawchangeaddress":     "getrawchangeaddress (\"account\")\n\nGenerates and returns a new internal payment address for use as a change address in raw transactions.\n\nArguments:\n1. account (string, optional) Account name the new internal address will belong to (default=\"default\")\n\nResult:\n\"value\" (string) The internal payment address\n",
-		"getreceivedbyaccount":    "getreceivedbyaccount \"account\" (minconf=1)\n\nDEPRECATED -- Returns the total amount received by addresses of some account, including spent outputs.\n\nArguments:\n1. account (string, required)             Account name to query total received amount for\n2. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an output's value is included in the total\n\nResult:\nn.nnn (numeric) The total received amount valued in HC\n",
-		"getreceivedbyaddress":    "getreceivedbyaddress \"address\" (minconf=1)\n\nReturns the total amount received by a single address, including spent outputs.\n\nArguments:\n1. address (string, required)             Payment address which received outputs to include in total\n2. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an output's value is included in the total\n\nResult:\nn.nnn (numeric) The total received amount valued in HC\n",
-		"gettickets":              "gettickets includeimmature\n\nReturning the hashes of the tickets currently owned by wallet.\n\nArguments:\n1. includeimmature (boolean, required) If true include immature tickets in the results.\n\nResult:\n{\n \"hashes\": [\"value\",...], (array of string) Hashes of the tickets owned by the wallet encoded as strings\n}                         \n",
-		"gettransaction":          "gettransaction \"txid\" (includewatchonly=false)\n\nReturns a JSON object with details regarding a transaction relevant to this wallet.\n\nArguments:\n1. txid             (string, required)                 Hash of the transaction to query\n2. includewatchonly (boolean, optional, default=false) Also consider transactions involving watched addresses\n\nResult:\n{\n \"amount\": n.nnn,                  (numeric)         The total amount this transaction credits to the wallet, valued in HC\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value, or 0 if 'txid' is not a sent transaction\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"txid\": \"value\",                  (string)          The transaction hash\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"details\": [{                     (array of object) Additional details for each recorded wallet credit and debit\n  \"account\": \"value\",              (string)          DEPRECATED -- Unset\n  \"address\": \"value\",              (string)          The address an output was paid to, or the empty string if the output is nonstandard or this detail is regarding a transaction input\n  \"amount\": n.nnn,                 (numeric)         The amount of a received output\n  \"category\": \"value\",             (string)          The kind of detail: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs\n  \"involveswatchonly\": true|false, (boolean)         Unset\n  \"fee\": n.nnn,                    (numeric)         The included fee for a sent transaction\n  \"vout\": n,                       (numeric)         The transaction output index\n },...],                                             \n \"hex\": \"value\",                   (string)          The transaction encoded as a hexadecimal string\n}                                  \n",
-		"getvotechoices":          "getvotechoices\n\nRetrieve the currently configured vote choices for the latest supported stake agendas\n\nArguments:\nNone\n\nResult:\n{\n \"version\": n,                  (numeric)         The latest stake version supported by the software and the version of the included agendas\n \"choices\": [{                  (array of object) The currently configured agenda vote choices, including abstaining votes\n  \"agendaid\": \"value\",          (string)          The ID for the agenda the choice concerns\n  \"agendadescription\": \"value\", (string)          A description of the agenda the choice concerns\n  \"choiceid\": \"value\",          (string)          The ID of the current choice for this agenda\n  \"choicedescription\": \"value\", (string)          A description of the current choice for this agenda\n },...],                                          \n}                               \n",
-		"help":                    "help (\"command\")\n\nReturns a list of all commands or help for a specified command.\n\nArguments:\n1. command (string, optional) The command to retrieve help for\n\nResult (no command provided):\n\"value\" (string) List of commands\n\nResult (command specified):\n\"value\" (string) Help for specified command\n",
-		"importprivkey":           "importprivkey \"privkey\" (\"label\" rescan=true scanfrom)\n\nImports a WIF-encoded private key to the 'imported' account.\n\nArguments:\n1. privkey  (string, required)                The WIF-encoded private key\n2. label    (string, optional)                Unused (must be unset or 'imported')\n3. rescan   (boolean, optional, default=true) Rescan the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the imported key\n4. scanfrom (numeric, optional)               Block number for where to start rescan from\n\nResult:\nNothing\n",
-		"importscript":            "importscript \"hex\" (rescan=true scanfrom)\n\nImport a redeem script.\n\nArguments:\n1. hex      (string, required)                Hex encoded script to import\n2. rescan   (boolean, optional, default=true) Rescansfdsfd the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the imported key\n3. scanfrom (numeric, optional)               Block number for where to start rescan from\n\nResult:\nNothing\n",
-		"keypoolrefill":           "keypoolrefill (newsize=100)\n\nDEPRECATED -- This request does nothing since no keypool is maintained.\n\nArguments:\n1. newsize (numeric, optional, default=100) Unused\n\nResult:\nNothing\n",
-		"listaccounts":            "listaccounts (minconf=1)\n\nDEPRECATED -- Returns a JSON object of all accounts and their balances.\n\nArguments:\n1. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an unspent output's value is included in the balance\n\nResult:\n{\n \"The account name\": The account balance valued in HC, (object) JSON object with account names as keys and HC amounts as values\n ...\n}\n",
-		"listlockunspent":         "listlockunspent\n\nReturns a JSON array of outpoints marked as locked (with lockunspent) for this wallet session.\n\nArguments:\nNone\n\nResult:\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output\n \"vout\": n,       (numeric) The output index of the referenced output\n \"tree\": n,       (numeric) The tree to generate transaction for\n},...]\n",
-		"listreceivedbyaccount":   "listreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\n\nDEPRECATED -- Returns a JSON array of objects listing all accounts and the total amount received by each account.\n\nArguments:\n1. minconf          (numeric, optional, default=1)     Minimum number of block confirmations required before a transaction is considered\n2. includeempty     (boolean, optional, default=false) Unused\n3. includewatchonly (boolean, optional, default=false) Unused\n\nResult:\n[{\n \"account\": \"value\", (string)  The name of the account\n \"amount\": n.nnn,    (numeric) Total amount received by payment addresses of the account valued in HC\n \"confirmations\": n, (numeric) Number of block confirmations of the most recent transaction relevant to the account\n},...]\n",
-		"listreceivedbyaddress":   "listreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\n\nReturns a JSON array of objects listing wallet payment addresses and their total received amounts.\n\nArguments:\n1. minconf          (numeric, optional, default=1)     Minimum number of block confirmations required before a transaction is considered\n2. includeempty     (boolean, optional, default=false) Unused\n3. includewatchonly (boolean, optional, default=false) Unused\n\nResult:\n[{\n \"account\": \"value\",              (string)          DEPRECATED -- Unset\n \"address\": \"value\",              (string)          The payment address\n \"amount\": n.nnn,                 (numeric)         Total amount received by the payment address valued in HC\n \"confirmations\": n,              (numeric)         Number of block confirmations of the most recent transaction relevant to the address\n \"txids\": [\"value\",...],          (array of string) Transaction hashes of all transactions involving this address\n \"involvesWatchonly\": true|false, (boolean)         Unset\n},...]\n",
-		"listsinceblock":          "listsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\n\nReturns a JSON array of objects listing details of all wallet transactions after some block.\n\nArguments:\n1. blockhash           (string, optional)                 Hash of the parent block of the first block to consider transactions from, or unset to list all transactions\n2. targetconfirmations (numeric, optional, default=1)     Minimum number of block confirmations of the last block in the result object.  Must be 1 or greater.  Note: The transactions array in the result object is not affected by this parameter\n3. includewatchonly    (boolean, optional, default=false) Unused\n\nResult:\n{\n \"transactions\": [{                 (array of object) JSON array of objects containing verbose details of the each transaction\n  \"account\": \"value\",               (string)          DEPRECATED -- Unset\n  \"address\": \"value\",               (string)          Payment address for a transaction output\n  \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n  \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n  \"blockindex\": n,                  (numeric)         Unset\n  \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n  \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n  \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n  \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n  \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n  \"involveswatchonly\": true|false,  (boolean)         Unset\n  \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n  \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n  \"txid\": \"value\",                  (string)          The hash of the transaction\n  \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n  \"vout\": n,                        (numeric)         The transaction output index\n  \"walletconflicts\": [\"value\",...], (array of string) Unset\n  \"comment\": \"value\",               (string)          Unset\n  \"otheraccount\": \"value\",          (string)          Unset\n },...],                                              \n \"lastblock\": \"value\",              (string)          Hash of the latest-synced block to be used in later calls to listsinceblock\n}                                   \n",
-		"listtransactions":        "listtransactions (\"account\" count=10 from=0 includewatchonly=false)\n\nReturns a JSON array of objects containing verbose details for wallet transactions.\n\nArguments:\n1. account          (string, optional)                 DEPRECATED -- Unused (must be unset or \"*\")\n2. count            (numeric, optional, default=10)    Maximum number of transactions to create results from\n3. from             (numeric, optional, default=0)     Number of transactions to skip before results are created\n4. includewatchonly (boolean, optional, default=false) Unused\n\nResult:\n[{\n \"account\": \"value\",               (string)          DEPRECATED -- Unset\n \"address\": \"value\",               (string)          Payment address for a transaction output\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n \"involveswatchonly\": true|false,  (boolean)         Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"txid\": \"value\",                  (string)          The hash of the transaction\n \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n \"vout\": n,                        (numeric)         The transaction output index\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"comment\": \"value\",               (string)          Unset\n \"otheraccount\": \"value\",          (string)          Unset\n},...]\n",
-		"listunspent":             "listunspent (minconf=1 maxconf=9999999 [\"address\",...])\n\nReturns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.\n\nArguments:\n1. minconf   (numeric, optional, default=1)       Minimum number of block confirmations required before a transaction output is considered\n2. maxconf   (numeric, optional, default=9999999) Maximum number of block confirmations required before a transaction output is excluded\n3. addresses (array of string, optional)          If set, limits the returned details to unspent outputs received by any of these payment addresses\n\nResult:\n{\n \"txid\": \"value\",         (string)  The transaction hash of the referenced output\n \"vout\": n,               (numeric) The output index of the referenced output\n \"tree\": n,               (numeric) The tree the transaction comes from\n \"txtype\": n,             (numeric) The type of the transaction\n \"address\": \"value\",      (string)  The payment address that received the output\n \"account\": \"value\",      (string)  The account associated with the receiving payment address\n \"scriptPubKey\": \"value\", (string)  The output script encoded as a hexadecimal string\n \"redeemScript\": \"value\", (string)  Unset\n \"amount\": n.nnn,         (numeric) The amount of the output valued in HC\n \"confirmations\": n,      (numeric) The number of block confirmations of the transaction\n \"spendable\": true|false, (boolean) Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses)\n}                         \n",
-		"lockunspent":             "lockunspent unlock [{\"txid\":\"value\",\"vout\":n,\"tree\":n},...]\n\nLocks or unlocks an unspent output.\nLocked outputs are not chosen for transaction inputs of authored transactions and are not included in 'listunspent' results.\nLocked outputs are volatile and are not saved across wallet restarts.\nIf unlock is true and no transaction outputs are specified, all locked outputs are marked unlocked.\n\nArguments:\n1. unlock       (boolean, required)         True to unlock outputs, false to lock\n2. transactions (array of object, required) Transaction outputs to lock or unlock\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output\n \"vout\": n,       (numeric) The output index of the referenced output\n \"tree\": n,       (numeric) The tree to generate transaction for\n},...]\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
-		"redeemmultisigout":       "redeemmultisigout \"hash\" index tree (\"address\")\n\nTakes the input and constructs a P2PKH paying to the specified address.\n\nArguments:\n1. hash    (string, required)  Hash of the input transaction\n2. index   (numeric, required) Idx of the input transaction\n3. tree    (numeric, required) Tree the transaction is on.\n4. address (string, optional)  Address to pay to.\n\nResult:\n{\n \"hex\": \"value\",         (string)          Resulting hash.\n \"complete\": true|false, (boolean)         Shows if opperation was completed.\n \"errors\": [{            (array of object) Any errors generated.\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n}                        \n",
-		"redeemmultisigouts":      "redeemmultisigouts \"fromscraddress\" (\"toaddress\" number)\n\nTakes a hash, looks up all unspent outpoints and generates list artially signed transactions spending to either an address specified or internal addresses\n\nArguments:\n1. fromscraddress (string, required)  Input script hash address.\n2. toaddress      (string, optional)  Address to look for (if not internal addresses).\n3. number         (numeric, optional) Number of outpoints found.\n\nResult:\n{\n \"hex\": \"value\",         (string)          Resulting hash.\n \"complete\": true|false, (boolean)         Shows if opperation was completed.\n \"errors\": [{            (array of object) Any errors generated.\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n}                        \n",
-		"rescanwallet":            "rescanwallet (beginheight=0)\n\nRescan the block chain for wallet data, blocking until the rescan completes or exits with an error\n\nArguments:\n1. beginheight (numeric, optional, default=0) The height of the first block to begin the rescan from\n\nResult:\nNothing\n",
-		"revoketickets":           "revoketickets\n\nRequests the wallet create revovactions for any previously missed tickets.  Wallet must be unlocked.\n\nArguments:\nNone\n\nResult:\nNothing\n",
-		"sendfrom":                "sendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 \"comment\" \"commentto\")\n\nDEPRECATED -- Authors, signs, and sends a transaction that outputs some amount to a payment address.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required)             Account to pick unspent outputs from\n2. toaddress   (string, required)             Address to pay\n3. amount      (numeric, required)            Amount to send to the payment address valued in HC\n4. minconf     (numeric, optional, default=1) Minimum number of block confirmations required before a transaction output is eligible to be spent\n5. comment     (string, optional)             Unused\n6. commentto   (string, optional)             Unused\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
-		"sendmany":                "sendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 \"comment\")\n\nAuthors, signs, and sends a transaction that outputs to many payment addresses.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required) DEPRECATED -- Account to pick unspent outputs from\n2. amounts     (object, required) Pairs of payment addresses and the output amount to pay each\n{\n \"Address to pay\": Amount to send to the payment address valued in HC, (object) JSON object using payment addresses as keys and output amounts valued in HC to send to each address\n ...\n}\n3. minconf (numeric, optional, default=1) Minimum number of block confirmations required before a transaction output is eligible to be spent\n4. comment (string, optional)             Unused\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
-		"sendtoaddress":           "sendtoaddress \"address\" amount (\"comment\" \"commentto\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. address   (string, required)  Address to pay\n2. amount    (numeric, required) Amount to send to the payment address valued in HC\n3. comment   (string, optional)  Unused\n4. commentto (string, optional)  Unused\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
-		"sendtomultisig":          "sendtomultisig \"fromaccount\" amount [\"pubkey\",...] (nrequired=1 minconf=1 \"comment\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a multisig address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required)             Unused\n2. amount      (numeric, required)            Amount to send to the payment address valued in HC\n3. pubkeys     (array of string, required)    Pubkey to send to.\n4. nrequired   (numeric, optional, default=1) The number of signatures required to redeem outputs paid to this address\n5. minconf     (numeric, optional, default=1) Minimum number of block confirmations required\n6. comment     (string, optional)             Unused\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
-		"settxfee":                "settxfee amount\n\nModify the fee per kB of the serialized tx size used each time more fee is required for an authored transaction.\n\nArguments:\n1. amount (numeric, required) The new fee per kB of the serialized tx size valued in HC\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
-		"setvotechoice":           "setvotechoice \"agendaid\" \"choiceid\"\n\nSets choices for defined agendas in the latest stake version supported by this software\n\nArguments:\n1. agendaid (string, required) The ID for the agenda to modify\n2. choiceid (string, required) The ID for the choice to choose\n\nResult:\nNothing\n",
-		"signmessage":             "signmessage \"address\" \"message\"\n\nSigns a message using the private key of a payment address.\n\nArguments:\n1. address (string, required) Payment address of private key used to sign the message with\n2. message (string, required) Message to sign\n\nResult:\n\"value\" (string) The signed message encoded as a base64 string\n",
-		"signrawtransaction":      "signrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\n\nSigns transaction inputs using private keys from this wallet and request.\nThe valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.\n\nArguments:\n1. rawtx    (string, required)                Unsigned or partially unsigned transaction to sign encoded as a hexadecimal string\n2. inputs   (array of object, optional)       Additional data regarding inputs that this wallet may not be tracking\n3. privkeys (array of string, optional)       Additional WIF-encoded private keys to use when creating signatures\n4. flags    (string, optional, default=\"ALL\") Sighash flags\n\nResult:\n{\n \"hex\": \"value\",         (string)          The resulting transaction encoded as a hexadecimal string\n \"complete\": true|false, (boolean)         Whether all input signatures have been created\n \"errors\": [{            (array of object) Script verification errors (if exists)\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n}                        \n",
-		"signrawtransactions":     "signrawtransactions [\"rawtx\",...] (send=true)\n\nSigns transaction inputs using private keys from this wallet and request for a list of transactions.\n\n\nArguments:\n1. rawtxs (array of string, required)       A list of transactions to sign (and optionally send).\n2. send   (boolean, optional, default=true) Set true to send the transactions after signing.\n\nResult:\n{\n \"results\": [{             (array of object) Returned values from the signrawtransactions command.\n  \"signingresult\": {       (object)          Success or failure of signing.\n   \"hex\": \"value\",         (string)          The resulting transaction encoded as a hexadecimal string\n   \"complete\": true|false, (boolean)         Whether all input signatures have been created\n   \"errors\": [{            (array of object) Script verification errors (if exists)\n    \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n    \"vout\": n,             (numeric)         The output index of the referenced previous output\n    \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n    \"sequence\": n,         (numeric)         Script sequence number\n    \"error\": \"value\",      (string)          Verification or signing error related to the input\n   },...],                                   \n  },                                         \n  \"sent\": true|false,      (boolean)         Tells if the transaction was sent.\n  \"txhash\": \"value\",       (string)          The hash of the signed tx.\n },...],                                     \n}                          \n",
-		"validateaddress":         "validateaddress \"address\"\n\nVerify that an address is valid.\nExtra details are returned if the address is controlled by this wallet.\nThe following fields are valid only when the address is controlled by this wallet (ismine=true): isscript, pubkey, iscompressed, account, addresses, hex, script, and sigsrequired.\nThe following fields are only valid when address has an associated public key: pubkey, iscompressed.\nThe following fields are only valid when address is a pay-to-script-hash address: addresses, hex, and script.\nIf the address is a multisig address controlled by this wallet, the multisig fields will be left unset if the wallet is locked since the redeem script cannot be decrypted.\n\nArguments:\n1. address (string, required) Address to validate\n\nResult:\n{\n \"isvalid\": true|false,      (boolean)         Whether or not the address is valid\n \"address\": \"value\",         (string)          The payment address (only when isvalid is true)\n \"ismine\": true|false,       (boolean)         Whether this address is controlled by the wallet (only when isvalid is true)\n \"iswatchonly\": true|false,  (boolean)         Unset\n \"isscript\": true|false,     (boolean)         Whether the payment address is a pay-to-script-hash address (only when isvalid is true)\n \"pubkeyaddr\": \"value\",      (string)          The pubkey for this payment address (only when isvalid is true)\n \"pubkey\": \"value\",          (string)          The associated public key of the payment address, if any (only when isvalid is true)\n \"iscompressed\": true|false, (boolean)         Whether the address was created by hashing a compressed public key, if any (only when isvalid is true)\n \"account\": \"value\",         (string)          The account this payment address belongs to (only when isvalid is true)\n \"addresses\": [\"value\",...], (array of string) All associated payment addresses of the script if address is a multisig address (only when isvalid is true)\n \"hex\": \"value\",             (string)          The redeem script \n \"script\": \"value\",          (string)          The class of redeem script for a multisig address\n \"sigsrequired\": n,          (numeric)         The number of required signatures to redeem outputs to the multisig address\n}                            \n",
-		"verifymessage":           "verifymessage \"address\" \"signature\" \"message\"\n\nVerify a message was signed with the associated private key of some address.\n\nArguments:\n1. address   (string, required) Address used to sign message\n2. signature (string, required) The signature to verify\n3. message   (string, required) The message to verify\n\nResult:\ntrue|false (boolean) Whether the message was signed with the private key of 'address'\n",
-		"version":                 "version\n\nReturns application and API versions (semver) keyed by their names\n\nArguments:\nNone\n\nResult:\n{\n \"Program or API name\": Object containing the semantic version, (object) Version objects keyed by the program or API name\n ...\n}\n",
-		"walletlock":              "walletlock\n\nLock the wallet.\n\nArguments:\nNone\n\nResult:\nNothing\n",
-		"walletpassphrase":        "walletpassphrase \"passphrase\" timeout\n\nUnlock the wallet.\n\nArguments:\n1. passphrase (string, required)  The wallet passphrase\n2. timeout    (numeric, required) The number of seconds to wait before the wallet automatically locks\n\nResult:\nNothing\n",
-		"walletpassphrasechange":  "walletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\n\nChange the wallet passphrase.\n\nArguments:\n1. oldpassphrase (string, required) The old wallet passphrase\n2. newpassphrase (string, required) The new wallet passphrase\n\nResult:\nNothing\n",
-		"createnewaccount":        "createnewaccount \"account\"\n\nCreates a new account.\nThe wallet must be unlocked for this request to succeed.\n\nArguments:\n1. account (string, required) Name of the new account\n\nResult:\nNothing\n",
-		"exportwatchingwallet":    "exportwatchingwallet (\"account\" download=false)\n\nCreates and returns a duplicate of the wallet database without any private keys to be used as a watching-only wallet.\n\nArguments:\n1. account  (string, optional)                 Unused (must be unset or \"*\")\n2. download (boolean, optional, default=false) Unused\n\nResult:\n\"value\" (string) The watching-only database encoded as a base64 string\n",
-		"getbestblock":            "getbestblock\n\nReturns the hash and height of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\n{\n \"hash\": \"value\", (string)  The hash of the block\n \"height\": n,     (numeric) The blockchain height of the block\n}                 \n",
-		"getunconfirmedbalance":   "getunconfirmedbalance (\"account\")\n\nCalculates the unspent output value of all unmined transaction outputs for an account.\n\nArguments:\n1. account (string, optional) The account to query the unconfirmed balance for (default=\"default\")\n\nResult:\nn.nnn (numeric) Total amount of all unmined unspent outputs of the account valued in HC.\n",
-		"listaddresstransactions": "listaddresstransactions [\"address\",...] (\"account\")\n\nReturns a JSON array of objects containing verbose details for wallet transactions pertaining some addresses.\n\nArguments:\n1. addresses (array of string, required) Addresses to filter transaction results by\n2. account   (string, optional)          Unused (must be unset or \"*\")\n\nResult:\n[{\n \"account\": \"value\",               (string)          DEPRECATED -- Unset\n \"address\": \"value\",               (string)          Payment address for a transaction output\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n \"involveswatchonly\": true|false,  (boolean)         Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"txid\": \"value\",                  (string)          The hash of the transaction\n \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n \"vout\": n,                        (numeric)         The transaction output index\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"comment\": \"value\",               (string)          Unset\n \"otheraccount\": \"value\",          (string)          Unset\n},...]\n",
-		"listalltransactions":     "listalltransactions (\"account\")\n\nReturns a JSON array of objects in the same format as 'listtransactions' without limiting the number of returned objects.\n\nArguments:\n1. account (string, optional) Unused (must be unset or \"*\")\n\nResult:\n[{\n \"account\": \"value\",               (string)          DEPRECATED -- Unset\n \"address\": \"value\",               (string)          Payment address for a transaction output\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n \"involveswatchonly\": true|false,  (boolean)         Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"txid\": \"value\",                  (string)          The hash of the transaction\n \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n \"vout\": n,                        (numeric)         The transaction output index\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"comment\": \"value\",               (string)          Unset\n \"otheraccount\": \"value\",          (string)          Unset\n},...]\n",
-		"renameaccount":           "renameaccount \"oldaccount\" \"newaccount\"\n\nRenames an account.\n\nArguments:\n1. oldaccount (string, required) The old account name to rename\n2. newaccount (string, required) The new name for the account\n\nResult:\nNothing\n",
-		"walletislocked":          "walletislocked\n\nReturns whether or not the wallet is locked.\n\nArguments:\nNone\n\nResult:\ntrue|false (boolean) Whether the wallet is locked\n",
-		"walletinfo":              "walletinfo\n\nReturns global information about the wallet\n\nArguments:\nNone\n\nResult:\n{\n \"daemonconnected\": true|false,  (boolean) Whether or not the wallet is currently connected to the daemon RPC\n \"unlocked\": true|false,         (boolean) Whether or not the wallet is unlocked\n \"txfee\": n.nnn,                 (numeric) Transaction fee per kB of the serialized tx size in coins\n \"ticketfee\": n.nnn,             (numeric) Ticket fee per kB of the serialized tx size in coins\n \"ticketpurchasing\": true|false, (boolean) Whether or not the wallet is currently purchasing tickets\n \"votebits\": n,                  (numeric) Vote bits setting\n \"votebitsextended\": \"value\",    (string)  Extended vote bits setting\n \"voteversion\": n,               (numeric) Version of votes that will be generated\n \"voting\": true|false,           (boolean) Whether or not the wallet is currently voting tickets\n}                                \n",
-		"purchaseticket":          "purchaseticket \"fromaccount\" spendlimit (minconf=1 \"ticketaddress\" numtickets \"pooladdress\" poolfees expiry \"comment\")\n\nPurchase ticket using available funds.\n\nArguments:\n1. fromaccount   (string, required)             The account to use for purchase (default=\"default\")\n2. spendlimit    (numeric, required)            Limit on the amount to spend on ticket\n3. minconf       (numeric, optional, default=1) Minimum number of block confirmations required\n4. ticketaddress (string, optional)             Override the ticket address to which voting rights are given\n5. numtickets    (numeric, optional)            The number of tickets to purchase\n6. pooladdress   (string, optional)             The address to pay stake pool fees to\n7. poolfees      (numeric, optional)            The amount of fees to pay to the stake pool\n8. expiry        (numeric, optional)            Height at which the purchase tickets expire\n9. comment       (string, optional)             Unused\n\nResult:\n\"value\" (string) Hash of the resulting ticket\n",
-		"sendtossrtx":             "sendtossrtx \"fromaccount\" \"tickethash\" (\"comment\")\n\nSend to SS Revocation transaction\n\nArguments:\n1. fromaccount (string, required) The account to spend a stake ticket from (default=\"default\")\n2. tickethash  (string, required) Hash of the ticket to be revoked\n3. comment     (string, optional) Unused\n\nResult:\n\"value\" (string) txid of the resulting transaction\n",
-		"sendtosstx":              "sendtosstx \"fromaccount\" amounts [{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"amt\":n},...] [{\"addr\":\"value\",\"commitamt\":n,\"changeaddr\":\"value\",\"changeamt\":n},...] (minconf=1 \"comment\")\n\nSend to SStx\n\nArguments:\n1. fromaccount (string, required) The account sent from\n2. amounts     (object, required) Amounts to send\n{\n \"Key\": Value, (object) Unused\n ...\n}\n3. inputs (array of object, required) Inputs for the tx\n[{\n \"txid\": \"value\", (string)  Txid to use\n \"vout\": n,       (numeric) Vout for the input tx\n \"tree\": n,       (numeric) Input tree\n \"amt\": n,        (numeric) Amount\n},...]\n4. couts (array of object, required) Couts for the tx\n[{\n \"addr\": \"value\",       (string)  Address to use\n \"commitamt\": n,        (numeric) Amount to commit\n \"changeaddr\": \"value\", (string)  Change address to use\n \"changeamt\": n,        (numeric) Change amount\n},...]\n5. minconf (numeric, optional, default=1) Minimum number of block confirmations required\n6. comment (string, optional)             Unused\n\nResult:\n\"value\" (string) txid of the resulting transaction\n",
-		"sendtossgen":             "sendtossgen \"fromaccount\" \"tickethash\" \"blockhash\" height votebits (\"comment\")\n\nGenerate a vote tx\n\nArguments:\n1. fromaccount (string, required)  The account to use (default=\"default\")\n2. tickethash  (string, required)  Hash of the ticket used for vote\n3. blockhash   (string, required)  Hash for the block being voted on\n4. height      (numeric, required) Blockheight for vote\n5. votebits    (numeric, required) Votebits to set\n6. comment     (string, optional)  Unused\n\nResult:\n\"value\" (string) txid of the resulting transaction\n",
-		"generatevote":            "generatevote \"blockhash\" height \"tickethash\" votebits \"votebitsext\"\n\nReturns the vote transaction encoded as a hexadecimal string\n\nArguments:\n1. blockhash   (string, required)  Block hash for the ticket\n2. height      (numeric, required) Block height for the ticket\n3. tickethash  (string, required)  The hash of the ticket\n4. votebits    (numeric, required) The voteBits to set for the ticket\n5. votebitsext (string, required)  The extended voteBits to set for the ticket\n\nResult:\n{\n \"hex\": \"value\", (string) The hex encoded transaction\n}                \n",
-		"getstakeinfo":            "getstakeinfo\n\nReturns statistics about staking from the wallet.\n\nArguments:\nNone\n\nResult:\n{\n \"blockheight\": n,          (numeric) Current block height for stake info.\n \"poolsize\": n,             (numeric) Number of live tickets in the ticket pool.\n \"difficulty\": n.nnn,       (numeric) Current stake difficulty.\n \"allmempooltix\": n,        (numeric) Number of tickets currently in the mempool\n \"ownmempooltix\": n,        (numeric) Number of tickets submitted by this wallet currently in mempool\n \"immature\": n,             (numeric) Number of tickets from this wallet that are in the blockchain but which are not yet mature\n \"live\": n,                 (numeric) Number of mature, active tickets owned by this wallet\n \"proportionlive\": n.nnn,   (numeric) (Live / PoolSize)\n \"voted\": n,                (numeric) Number of votes cast by this wallet\n \"totalsubsidy\": n.nnn,     (numeric) Total amount of coins earned by stake mining\n \"missed\": n,               (numeric) Number of missed tickets (failure to vote, not including expired)\n \"proportionmissed\": n.nnn, (numeric) (Missed / (Missed + Voted))\n \"revoked\": n,              (numeric) Number of missed tickets that were missed and then revoked\n \"expired\": n,              (numeric) Number of tickets that have expired\n}                           \n",
-		"getticketfee":            "getticketfee\n\nGet the current fee per kB of the serialized tx size used for an authored stake transaction.\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) The current fee\n",
-		"setticketfee":            "setticketfee fee\n\nModify the fee per kB of the serialized tx size used each time more fee is required for an authored stake transaction.\n\nArguments:\n1. fee (numeric, required) The new fee per kB of the serialized tx size valued in HC\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
-		"getwalletfee":            "getwalletfee\n\nGet currently set transaction fee for the wallet\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) Current tx fee (in HC)\n",
-		"addticket":               "addticket \"tickethex\"\n\nAdd a ticket to the wallet for vote and revocation creation.  Added tickets are auxiliary to transaction history and do not appear in getstakeinfo stats.\n\nArguments:\n1. tickethex (string, required) Hex-encoded serialized transaction\n\nResult:\nNothing\n",
-		"listscripts":             "listscripts\n\nList all scripts that have been added to wallet\n\nArguments:\nNone\n\nResult:\n{\n \"scripts\": [{             (array of object) A list of the imported scripts\n  \"hash160\": \"value\",      (string)          The script hash\n  \"address\": \"value\",      (string)          The script address\n  \"redeemscript\": \"value\", (string)          The redeem script\n },...],                                     \n}                          \n",
-		"stakepooluserinfo":       "stakepooluserinfo \"user\"\n\nGet user info for stakepool\n\nArguments:\n1. user (string, required) The id of the user to be looked up\n\nResult:\n{\n \"tickets\": [{             (array of object) A list of valid tickets that the user has added\n  \"status\": \"value\",       (string)          The current status of the added ticket\n  \"ticket\": \"value\",       (string)          The hash of the added ticket\n  \"ticketheight\": n,       (numeric)         The height in which the ticket was added\n  \"spentby\": \"value\",      (string)          The vote in which the ticket was spent\n  \"spentbyheight\": n,      (numeric)         The height in which the ticket was spent\n },...],                                     \n \"invalid\": [\"value\",...], (array of string) A list of invalid tickets that the user has added\n}                          \n",
-		"ticketsforaddress":       "ticketsforaddress \"address\"\n\nRequest all the tickets for an address.\n\nArguments:\n1. address (string, required) Address to look for.\n\nResult:\ntrue|false (boolean) Tickets owned by the specified address.\n",
+		"abandontransaction":                     "abandontransaction \"txhash\"\n\nRemoves an unmined transaction, and any unmined transactions spending its outputs, from the wallet.\nOutputs spent by the removed transactions become spendable again, and their pending omni balance changes are reversed. Mined transactions can not be abandoned.\n\nArguments:\n1. txhash (string, required) Hash of the unmined transaction to abandon\n\nResult:\nNothing\n",
+		"accountaddressindex":                    "accountaddressindex \"account\" branch\n\nGet the current address index for some account branch\n\nArguments:\n1. account (string, required)  String for the account\n2. branch  (numeric, required) Number for the branch (0=external, 1=internal)\n\nResult:\nn (numeric) The address index for this account branch\n",
+		"accountsyncaddressindex":                "accountsyncaddressindex \"account\" branch index\n\nSynchronize an account branch to some passed address index\n\nArguments:\n1. account (string, required)  String for the account\n2. branch  (numeric, required) Number for the branch (0=external, 1=internal)\n3. index   (numeric, required) The address index to synchronize to\n\nResult:\nNothing\n",
+		"addmultisigaddress":                     "addmultisigaddress nrequired [\"key\",...] (\"account\")\n\nGenerates and imports a multisig address and redeeming script to the 'imported' account.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address\n3. account   (string, optional)          DEPRECATED -- Unused (all imported addresses belong to the imported account)\n\nResult:\n\"value\" (string) The imported pay-to-script-hash address\n",
+		"bumpfee":                                "bumpfee \"txhash\" (feerate)\n\nReplaces an unmined wallet transaction with a transaction spending the same inputs to the same destinations and paying a higher fee, deducted from its change output.\nhcd does not replace transactions in its mempool, so the transaction may only be replaced after it has left hcd's mempool, for example by expiring or being evicted; an error is returned while it remains there.\nThe original transaction is removed from the wallet and recorded as replaced. The consensus server must accept the replacement, or the wallet is left unchanged.\n\nArguments:\n1. txhash  (string, required)  Hash of the unmined transaction to replace\n2. feerate (numeric, optional) Fee per kB of the replacement (defaults to the relay fee); the fee always increases by at least the relay fee for the transaction's size\n\nResult:\n{\n \"txid\": \"value\",     (string)  Hash of the replacement transaction\n \"origtxid\": \"value\", (string)  Hash of the replaced transaction\n \"origfee\": n.nnn,    (numeric) Fee paid by the replaced transaction\n \"fee\": n.nnn,        (numeric) Fee paid by the replacement transaction\n}                     \n",
+		"canceltransactiondraft":                 "canceltransactiondraft \"draftid\"\n\nRemoves a transaction draft created by createtransactiondraft, releasing the outputs reserved by it.\n\nArguments:\n1. draftid (string, required) The id of the transaction draft\n\nResult:\nNothing\n",
+		"checkaddressreuse":                      "checkaddressreuse (lookahead=0 startheight=0)\n\nReports external addresses paid by more than one transaction recorded by the wallet.\nWith a positive lookahead, the main chain is also scanned for payments to the next addresses of each account which the wallet has not yet returned, which suggests a leaked extended public key or a cloned wallet.\nThe scanned addresses are added to the transaction filter, so later payments to them are recorded by the wallet.\n\nArguments:\n1. lookahead   (numeric, optional, default=0) Number of unreturned external addresses of each account to scan the main chain for (0 skips the scan)\n2. startheight (numeric, optional, default=0) Main chain height to begin scanning from\n\nResult:\n{\n \"reused\": [{                    (array of object) External addresses paid by more than one transaction\n  \"address\": \"value\",            (string)          The external address\n  \"account\": \"value\",            (string)          The account of the address\n  \"index\": n,                    (numeric)         The child index of the address in the account's external branch\n  \"transactions\": [\"value\",...], (array of string) Hashes of the transactions paying to the address\n },...],                                           \n \"usedbeforereturned\": [{        (array of object) External addresses paid before the wallet returned them\n  \"address\": \"value\",            (string)          The external address\n  \"account\": \"value\",            (string)          The account of the address\n  \"index\": n,                    (numeric)         The child index of the address in the account's external branch\n  \"transactions\": [\"value\",...], (array of string) Hashes of the transactions paying to the address\n },...],                                           \n}                                \n",
+		"committransactiondraft":                 "committransactiondraft \"draftid\"\n\nSigns and publishes the transaction of a draft created by createtransactiondraft, removing the draft.\nThe draft remains, and its outputs reserved, if the transaction can not be signed or published.\nRequires the wallet to be unlocked.\n\nArguments:\n1. draftid (string, required) The id of the transaction draft\n\nResult:\n\"value\" (string) The hash of the published transaction, which is the draft id\n",
+		"compactdb":                              "compactdb\n\nChecks the integrity of the wallet database and, if no inconsistencies are found, rewrites the database without the space held by deleted data.\nDatabase access is blocked while compacting.\n\nArguments:\nNone\n\nResult:\n{\n \"issues\": [\"value\",...], (array of string) Descriptions of each inconsistency found between the buckets of the transaction store\n \"compacted\": true|false, (boolean)         Whether the database was compacted, which is skipped when any inconsistency is found\n}                         \n",
+		"consolidate":                            "consolidate inputs (\"account\" \"address\" feerate maxfee)\n\nConsolidate n many UTXOs of an account into a single output in the wallet.\nThe consolidation is split into multiple transactions when splittxs is enabled and a single transaction would exceed maxtxinputs or maxtxsize.\nWith result version 1, an error after some transactions were published is returned with the hashes of the published transactions in its message.\n\nArguments:\n1. inputs  (numeric, required) Number of UTXOs to consolidate as inputs\n2. account (string, optional)  Optional: Account from which unspent outputs are picked. When no address specified, also the account used to obtain an output address.\n3. address (string, optional)  Optional: Address to pay.  Default is obtained via getnewaddress from the account's address pool.\n4. feerate (numeric, optional) Optional: Fee per kB paid by the transactions (default: the relay fee of the account)\n5. maxfee  (numeric, optional) Optional: Highest fee paid by a single transaction; the consolidation fails if it would pay more\n\nResult (resultversion=1 and a single transaction was created):\n\"value\" (string) Transaction hash for the consolidation transaction\n\nResult (resultversion=1 and the consolidation was split into multiple transactions):\n[\"value\",...] (array of string) Transaction hashes for each consolidation transaction\n\nResult (resultversion=2):\n{\n \"transactions\": [{ (array of object) The published consolidation transactions\n  \"txid\": \"value\",  (string)          The hash of the transaction\n  \"inputs\": n,      (numeric)         The number of UTXOs consumed by the transaction\n  \"fee\": n.nnn,     (numeric)         The fee paid by the transaction\n  \"amount\": n.nnn,  (numeric)         The value of the consolidated output\n },...],                              \n \"inputs\": n,       (numeric)         The total number of consumed UTXOs\n \"fee\": n.nnn,      (numeric)         The total fee paid\n \"amount\": n.nnn,   (numeric)         The total value of the consolidated outputs\n \"error\": \"value\",  (string)          The error which stopped the consolidation after the listed transactions were published, if any\n}                   \n",
+		"createmultisig":                         "createmultisig nrequired [\"key\",...]\n\nGenerate a multisig address and redeem script.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address\n\nResult:\n{\n \"address\": \"value\",      (string) The generated pay-to-script-hash address\n \"redeemScript\": \"value\", (string) The script required to redeem outputs paid to the multisig address\n}                         \n",
+		"createtransactiondraft":                 "createtransactiondraft {\"address\":amount,...} (account=\"default\" minconf=1)\n\nAuthors an unsigned transaction paying each address and records it as a transaction draft.\nThe inputs of the draft are selected and reserved in a single step, and are never selected for other transactions until the draft is committed with committransactiondraft or canceled with canceltransactiondraft.\nReservations are saved in the wallet database and persist across restarts.\n\nArguments:\n1. amounts (object, required) Pairs of payment addresses and the output amount to pay each\n{\n \"Address to pay\": Amount to send to the payment address valued in HC, (object) JSON object using payment addresses as keys and output amounts valued in HC to send to each address\n ...\n}\n2. account (string, optional, default=\"default\") The account to spend outputs of\n3. minconf (numeric, optional, default=1)        Minimum number of block confirmations required before a transaction output is eligible to be spent\n\nResult:\n{\n \"draftid\": \"value\", (string)  The id of the transaction draft, which is also the hash of the transaction once it is signed\n \"hex\": \"value\",     (string)  The serialized unsigned transaction\n \"fee\": n.nnn,       (numeric) The fee paid by the transaction\n \"changeindex\": n,   (numeric) The output index of the change output, or -1 if there is no change\n}                    \n",
+		"dumpprivkey":                            "dumpprivkey \"address\"\n\nReturns the private key in WIF encoding that controls some wallet address.\n\nArguments:\n1. address (string, required) The address to return a private key for\n\nResult:\n\"value\" (string) The WIF-encoded private key\n",
+		"exporttransactions":                     "exporttransactions (\"account\" format=\"csv\" fiat=false)\n\nExports the effect of every transaction recorded by the wallet on the balance of each account it affects, with running account balances, for accounting and tax reporting.\nEntries are ordered by block, followed by unmined transactions, and running balances do not include transactions removed by history pruning.\nCategories are send, receive, transfer, coinbase, ticket, vote (whose amount is the staking income of the ticket), and revocation.\n\nArguments:\n1. account (string, optional)                 Only export the entries of this account (running balances are unaffected)\n2. format  (string, optional, default=\"csv\")  The export format (csv or json)\n3. fiat    (boolean, optional, default=false) Value each entry in the currency of the configured price source (requires fiatpriceurl)\n\nResult (format is csv):\n\"value\" (string) CSV with a header row and the columns of the JSON result, with times in RFC 3339 format\n\nResult (format is json):\n[{\n \"txid\": \"value\",         (string)  The hash of the transaction\n \"blockheight\": n,        (numeric) The height of the block mining the transaction, or -1 if unmined\n \"time\": n,               (numeric) The time of the block mining the transaction, or the time the transaction was received if unmined\n \"txtype\": \"value\",       (string)  The transaction type (regular, ticket, vote, or revocation)\n \"category\": \"value\",     (string)  The classification of the effect on the account\n \"account\": \"value\",      (string)  The account affected by the transaction\n \"amount\": n.nnn,         (numeric) The change of the account balance excluding the fee\n \"fee\": n.nnn,            (numeric) The fee paid by the account\n \"net\": n.nnn,            (numeric) The change of the account balance including the fee\n \"balance\": n.nnn,        (numeric) The running balance of the account after the transaction\n \"fiatcurrency\": \"value\", (string)  The fiat currency of the valuation\n \"fiatprice\": n.nnn,      (numeric) The price of a coin at the time of the transaction\n \"fiatvalue\": n.nnn,      (numeric) The fiat value of the net change of the account balance\n},...]\n",
+		"exportvotechoices":                      "exportvotechoices\n\nReturns the configured vote choices for the latest supported stake agendas in the form accepted by importvotechoices.\n\nArguments:\nNone\n\nResult:\n{\n \"version\": n,         (numeric)         The latest stake version supported by the software and the version of the included agendas\n \"choices\": [{         (array of object) The configured choice of each agenda, including abstaining votes\n  \"agendaid\": \"value\", (string)          The ID for the agenda the choice concerns\n  \"choiceid\": \"value\", (string)          The ID of the choice for this agenda\n },...],                                 \n}                      \n",
+		"getaccount":                             "getaccount \"address\"\n\nDEPRECATED -- Lookup the account name that some wallet address belongs to.\n\nArguments:\n1. address (string, required) The address to query the account for\n\nResult:\n\"value\" (string) The name of the account that 'address' belongs to\n",
+		"getaccountaddress":                      "getaccountaddress \"account\"\n\nDEPRECATED -- Returns the most recent external payment address for an account that has not been seen publicly.\nA new address is generated for the account if the most recently generated address has been seen on the blockchain or in mempool.\n\nArguments:\n1. account (string, required) The account of the returned address\n\nResult:\n\"value\" (string) The unused address for 'account'\n",
+		"getaccountdefaultaddress":               "getaccountdefaultaddress (account=\"default\")\n\nReturns the default address of an account, which is used as the change and sending address of transactions from the account which do not specify one.\nThis is the address set by setaccountdefaultaddress, or the first address of the account's external branch.\n\nArguments:\n1. account (string, optional, default=\"default\") The account of the returned address\n\nResult:\n\"value\" (string) The default address of 'account'\n",
+		"getaddressesbyaccount":                  "getaddressesbyaccount \"account\"\n\nDEPRECATED -- Returns all addresses strings controlled by a single account.\n\nArguments:\n1. account (string, required) Account name to fetch addresses for\n\nResult:\n[\"value\",...] (array of string) All addresses controlled by 'account'\n",
+		"getbalance":                             "getbalance (\"account\" minconf=2 \"currency\")\n\nCalculates and returns the balance of one or all accounts.\n\nArguments:\n1. account  (string, optional)             DEPRECATED -- The account name to query the balance for, or \"*\" to consider all accounts (default=\"*\")\n2. minconf  (numeric, optional, default=2) Minimum number of block confirmations required before an unspent output's value is included in the balance\n3. currency (string, optional)             Also value the total balances in this fiat currency at the current price (requires fiatpriceurl; an empty string selects the configured fiatcurrency)\n\nResult:\n{\n \"balances\": [{                         (array of object) The balances of the account, or of every account\n  \"accountname\": \"value\",               (string)          The name of the account\n  \"immaturecoinbaserewards\": n.nnn,     (numeric)         Immature coinbase rewards\n  \"immaturestakegeneration\": n.nnn,     (numeric)         Immature stake generation (votes and revocations)\n  \"lockedbytickets\": n.nnn,             (numeric)         The value locked by tickets\n  \"spendable\": n.nnn,                   (numeric)         The spendable balance\n  \"total\": n.nnn,                       (numeric)         The total balance\n  \"unconfirmed\": n.nnn,                 (numeric)         The unconfirmed balance\n  \"votingauthority\": n.nnn,             (numeric)         The value of tickets with voting authority\n  \"fiat\": {                             (object)          The fiat valuation of the total balance, if a currency was requested\n   \"currency\": \"value\",                 (string)          The fiat currency code\n   \"price\": n.nnn,                      (numeric)         The price of a coin\n   \"value\": n.nnn,                      (numeric)         The fiat value of the amount\n  },                                                      \n },...],                                                  \n \"blockhash\": \"value\",                  (string)          The hash of the block the balances were calculated at\n \"totalimmaturecoinbaserewards\": n.nnn, (numeric)         The total immature coinbase rewards of all accounts\n \"totalimmaturestakegeneration\": n.nnn, (numeric)         The total immature stake generation of all accounts\n \"totallockedbytickets\": n.nnn,         (numeric)         The total value locked by tickets of all accounts\n \"totalspendable\": n.nnn,               (numeric)         The total spendable balance of all accounts\n \"cumulativetotal\": n.nnn,              (numeric)         The total balance of all accounts\n \"totalunconfirmed\": n.nnn,             (numeric)         The total unconfirmed balance of all accounts\n \"totalvotingauthority\": n.nnn,         (numeric)         The total value of tickets with voting authority of all accounts\n \"cumulativefiat\": {                    (object)          The fiat valuation of the total balance of all accounts, if a currency was requested\n  \"currency\": \"value\",                  (string)          The fiat currency code\n  \"price\": n.nnn,                       (numeric)         The price of a coin\n  \"value\": n.nnn,                       (numeric)         The fiat value of the amount\n },                                                       \n}                                       \n",
+		"getbestblockhash":                       "getbestblockhash\n\nReturns the hash of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The hash of the most recent synced-to block\n",
+		"getblockcount":                          "getblockcount\n\nReturns the blockchain height of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) The blockchain height of the most recent synced-to block\n",
+		"getinfo":                                "getinfo\n\nReturns a JSON object containing various state info.\nFields describing the consensus server are zero when hcd is unreachable.\nWith result version 2, these fields are null when hcd is unreachable, and the wallet sync progress is included.\n\nArguments:\nNone\n\nResult (resultversion=1):\n{\n \"version\": n,          (numeric) The version of the server\n \"protocolversion\": n,  (numeric) The latest supported protocol version\n \"walletversion\": n,    (numeric) The version of the address manager database\n \"balance\": n.nnn,      (numeric) The balance of all accounts calculated with one block confirmation\n \"blocks\": n,           (numeric) The number of blocks processed\n \"timeoffset\": n,       (numeric) The time offset\n \"connections\": n,      (numeric) The number of connected peers\n \"proxy\": \"value\",      (string)  The proxy used by the server\n \"difficulty\": n.nnn,   (numeric) The current target difficulty\n \"testnet\": true|false, (boolean) Whether or not server is using testnet\n \"keypoololdest\": n,    (numeric) Unset\n \"keypoolsize\": n,      (numeric) Unset\n \"unlocked_until\": n,   (numeric) Unset\n \"paytxfee\": n.nnn,     (numeric) The fee per kB of the serialized tx size used each time more fee is required for an authored transaction\n \"relayfee\": n.nnn,     (numeric) The minimum relay fee for non-free transactions in HC/KB\n \"errors\": \"value\",     (string)  Any current errors\n}                       \n\nResult (resultversion=2):\n{\n \"version\": n,           (numeric) The version of the server, or null when hcd is unreachable\n \"protocolversion\": n,   (numeric) The latest supported protocol version, or null when hcd is unreachable\n \"walletversion\": n,     (numeric) The version of the address manager database\n \"balance\": n.nnn,       (numeric) The balance of all accounts calculated with one block confirmation\n \"blocks\": n,            (numeric) The number of blocks processed by hcd, or null when hcd is unreachable\n \"timeoffset\": n,        (numeric) The time offset, or null when hcd is unreachable\n \"connections\": n,       (numeric) The number of connected peers, or null when hcd is unreachable\n \"proxy\": \"value\",       (string)  The proxy used by the server, or null when hcd is unreachable\n \"difficulty\": n.nnn,    (numeric) The current target difficulty, or null when hcd is unreachable\n \"testnet\": true|false,  (boolean) Whether or not the wallet is using a test network\n \"unlocked\": true|false, (boolean) Whether the wallet is unlocked\n \"paytxfee\": n.nnn,      (numeric) The fee per kB of the serialized tx size used each time more fee is required for an authored transaction\n \"relayfee\": n.nnn,      (numeric) The minimum relay fee of hcd for non-free transactions in HC/KB, or null when hcd is unreachable\n \"errors\": \"value\",      (string)  Any current errors of hcd, or null when hcd is unreachable\n \"syncheight\": n,        (numeric) The height of the most recent block the wallet has synced to\n \"headerheight\": n,      (numeric) The height of the best block known to hcd, or null when hcd is unreachable\n \"progress\": n.nnn,      (numeric) The wallet sync progress, from 0 to 1, as the sync height divided by the header height, or null when hcd is unreachable\n}                        \n",
+		"getmasterpubkey":                        "getmasterpubkey (\"account\")\n\nRequests the master pubkey from the wallet.\n\nArguments:\n1. account (string, optional) The account to get the master pubkey for\n\nResult:\n\"value\" (string) The master pubkey for the wallet\n",
+		"getmultisigoutinfo":                     "getmultisigoutinfo \"hash\" index\n\nReturns information about a multisignature output.\n\nArguments:\n1. hash  (string, required)  Input hash to check.\n2. index (numeric, required) Index of input.\n\nResult:\n{\n \"address\": \"value\",       (string)          Script address.\n \"redeemscript\": \"value\",  (string)          Hex of the redeeming script.\n \"m\": n,                   (numeric)         m (in m-of-n)\n \"n\": n,                   (numeric)         n (in m-of-n)\n \"pubkeys\": [\"value\",...], (array of string) Associated pubkeys.\n \"txhash\": \"value\",        (string)          txhash\n \"blockheight\": n,         (numeric)         Height of the containing block.\n \"blockhash\": \"value\",     (string)          Hash of the containing block.\n \"spent\": true|false,      (boolean)         If it has been spent.\n \"spentby\": \"value\",       (string)          Hash of spending tx.\n \"spentbyindex\": n,        (numeric)         Index of spending tx.\n \"amount\": n.nnn,          (numeric)         Amount of coins contained.\n}                          \n",
+		"getnewaddress":                          "getnewaddress (\"account\" \"gappolicy\" \"branch\")\n\nGenerates and returns a new payment address.\n\nArguments:\n1. account   (string, optional) Account name the new address will belong to (default=\"default\")\n2. gappolicy (string, optional) String defining the policy to use when the BIP0044 gap limit would be violated, may be \"error\", \"ignore\", or \"wrap\"\n3. branch    (string, optional) Name of an external branch of the account created by createexternalbranch to return the address from (default: the account's BIP0044 external branch)\n\nResult:\n\"value\" (string) The payment address\n",
+		"getrawchangeaddress":                    "getrawchangeaddress (\"account\")\n\nGenerates and returns a new internal payment address for use as a change address in raw transactions.\n\nArguments:\n1. account (string, optional) Account name the new internal address will belong to (default=\"default\")\n\nResult:\n\"value\" (string) The internal payment address\n",
+		"getreceivedbyaccount":                   "getreceivedbyaccount \"account\" (minconf=2)\n\nDEPRECATED -- Returns the total amount received by addresses of some account, including spent outputs.\n\nArguments:\n1. account (string, required)             Account name to query total received amount for\n2. minconf (numeric, optional, default=2) Minimum number of block confirmations required before an output's value is included in the total\n\nResult:\nn.nnn (numeric) The total received amount valued in HC\n",
+		"getreceivedbyaddress":                   "getreceivedbyaddress \"address\" (minconf=2)\n\nReturns the total amount received by a single address, including spent outputs.\n\nArguments:\n1. address (string, required)             Payment address which received outputs to include in total\n2. minconf (numeric, optional, default=2) Minimum number of block confirmations required before an output's value is included in the total\n\nResult:\nn.nnn (numeric) The total received amount valued in HC\n",
+		"gettickets":                             "gettickets includeimmature\n\nReturning the hashes of the tickets currently owned by wallet.\n\nArguments:\n1. includeimmature (boolean, required) If true include immature tickets in the results.\n\nResult:\n{\n \"hashes\": [\"value\",...], (array of string) Hashes of the tickets owned by the wallet encoded as strings\n}                         \n",
+		"gettransaction":                         "gettransaction \"txid\" (includewatchonly=false \"currency\" verbose=false)\n\nReturns a JSON object with details regarding a transaction relevant to this wallet.\n\nArguments:\n1. txid             (string, required)                 Hash of the transaction to query\n2. includewatchonly (boolean, optional, default=false) Also consider transactions involving watched addresses\n3. currency         (string, optional)                 Also value the amount in this fiat currency at the price when the transaction was mined, or received if unmined (requires fiatpriceurl; an empty string selects the configured fiatcurrency)\n4. verbose          (boolean, optional, default=false) Also include the decoded inputs and outputs with their addresses, accounts, and spentness\n\nResult:\n{\n \"amount\": n.nnn,                  (numeric)         The total amount this transaction credits to the wallet, valued in HC\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value, or 0 if 'txid' is not a sent transaction\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"txid\": \"value\",                  (string)          The transaction hash\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"details\": [{                     (array of object) Additional details for each recorded wallet credit and debit\n  \"account\": \"value\",              (string)          DEPRECATED -- Unset\n  \"address\": \"value\",              (string)          The address an output was paid to, or the empty string if the output is nonstandard or this detail is regarding a transaction input\n  \"amount\": n.nnn,                 (numeric)         The amount of a received output\n  \"category\": \"value\",             (string)          The kind of detail: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs\n  \"involveswatchonly\": true|false, (boolean)         Unset\n  \"fee\": n.nnn,                    (numeric)         The included fee for a sent transaction\n  \"vout\": n,                       (numeric)         The transaction output index\n },...],                                             \n \"hex\": \"value\",                   (string)          The transaction encoded as a hexadecimal string\n \"fiat\": {                         (object)          The fiat valuation of the amount, if a currency was requested\n  \"currency\": \"value\",             (string)          The fiat currency code\n  \"price\": n.nnn,                  (numeric)         The price of a coin\n  \"value\": n.nnn,                  (numeric)         The fiat value of the amount\n },                                                  \n \"vin\": [{                         (array of object) The transaction inputs, if verbose\n  \"txid\": \"value\",                 (string)          The hash of the previous transaction\n  \"vout\": n,                       (numeric)         The output index of the previous transaction\n  \"tree\": n,                       (numeric)         The tree of the previous transaction\n  \"sequence\": n,                   (numeric)         The input sequence number\n  \"amountin\": n.nnn,               (numeric)         The value of the previous output as committed to by the input\n  \"stakebase\": true|false,         (boolean)         Whether the input is the stakebase of a vote\n  \"prevout\": {                     (object)          The previous output, if the previous transaction is recorded by the wallet\n   \"value\": n.nnn,                 (numeric)         The output value\n   \"n\": n,                         (numeric)         The output index\n   \"scripttype\": \"value\",          (string)          The type of the output script\n   \"addresses\": [\"value\",...],     (array of string) The addresses paid by the output, or the committed address of a ticket commitment\n   \"mine\": true|false,             (boolean)         Whether the output pays a wallet address\n   \"account\": \"value\",             (string)          The account of the wallet address\n   \"label\": \"value\",               (string)          The name of the additional external branch the wallet address is derived from\n   \"change\": true|false,           (boolean)         Whether the output pays an internal (change) address\n   \"data\": \"value\",                (string)          The hex-encoded data carried by an OP_RETURN output\n   \"spent\": true|false,            (boolean)         Whether the wallet output has been spent, only set by verbose gettransaction\n   \"spentby\": \"value\",             (string)          The hash of the transaction spending the wallet output\n  },                                                 \n },...],                                             \n \"vout\": [{                        (array of object) The transaction outputs, if verbose\n  \"value\": n.nnn,                  (numeric)         The output value\n  \"n\": n,                          (numeric)         The output index\n  \"scripttype\": \"value\",           (string)          The type of the output script\n  \"addresses\": [\"value\",...],      (array of string) The addresses paid by the output, or the committed address of a ticket commitment\n  \"mine\": true|false,              (boolean)         Whether the output pays a wallet address\n  \"account\": \"value\",              (string)          The account of the wallet address\n  \"label\": \"value\",                (string)          The name of the additional external branch the wallet address is derived from\n  \"change\": true|false,            (boolean)         Whether the output pays an internal (change) address\n  \"data\": \"value\",                 (string)          The hex-encoded data carried by an OP_RETURN output\n  \"spent\": true|false,             (boolean)         Whether the wallet output has been spent, only set by verbose gettransaction\n  \"spentby\": \"value\",              (string)          The hash of the transaction spending the wallet output\n },...],                                             \n}                                  \n",
+		"getvotechoices":                         "getvotechoices\n\nRetrieve the currently configured vote choices for the latest supported stake agendas\n\nArguments:\nNone\n\nResult:\n{\n \"version\": n,                  (numeric)         The latest stake version supported by the software and the version of the included agendas\n \"choices\": [{                  (array of object) The currently configured agenda vote choices, including abstaining votes\n  \"agendaid\": \"value\",          (string)          The ID for the agenda the choice concerns\n  \"agendadescription\": \"value\", (string)          A description of the agenda the choice concerns\n  \"choiceid\": \"value\",          (string)          The ID of the current choice for this agenda\n  \"choicedescription\": \"value\", (string)          A description of the current choice for this agenda\n },...],                                          \n}                               \n",
+		"help":                                   "help (\"command\")\n\nReturns a list of all commands or help for a specified command.\n\nArguments:\n1. command (string, optional) The command to retrieve help for\n\nResult (no command provided):\n\"value\" (string) List of commands\n\nResult (command specified):\n\"value\" (string) Help for specified command\n",
+		"importprivkey":                          "importprivkey \"privkey\" (\"label\" rescan=true scanfrom)\n\nImports a WIF-encoded private key to the 'imported' account.\n\nArguments:\n1. privkey  (string, required)                The WIF-encoded private key\n2. label    (string, optional)                Unused (must be unset or 'imported')\n3. rescan   (boolean, optional, default=true) Rescan the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the imported key\n4. scanfrom (numeric, optional)               Block number for where to start rescan from\n\nResult:\nNothing\n",
+		"importscript":                           "importscript \"hex\" (rescan=true scanfrom)\n\nImport a redeem script.\n\nArguments:\n1. hex      (string, required)                Hex encoded script to import\n2. rescan   (boolean, optional, default=true) Rescansfdsfd the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the imported key\n3. scanfrom (numeric, optional)               Block number for where to start rescan from\n\nResult:\nNothing\n",
+		"importvotechoices":                      "importvotechoices [{\"agendaid\":\"value\",\"choiceid\":\"value\"},...] (version)\n\nSets the vote choices of multiple agendas of the latest supported stake version, such as those returned by exportvotechoices. No choice is set if any of them is invalid.\n\nArguments:\n1. choices (array of object, required) The choices to set for each agenda\n[{\n \"agendaid\": \"value\", (string) The ID for the agenda the choice concerns\n \"choiceid\": \"value\", (string) The ID of the choice for this agenda\n},...]\n2. version (numeric, optional) The stake version the choices were exported for, which must be the latest supported version when set\n\nResult:\nNothing\n",
+		"keypoolrefill":                          "keypoolrefill (newsize=100)\n\nDEPRECATED -- This request does nothing since no keypool is maintained.\n\nArguments:\n1. newsize (numeric, optional, default=100) Unused\n\nResult:\nNothing\n",
+		"listaccounts":                           "listaccounts (minconf=2)\n\nDEPRECATED -- Returns a JSON object of all accounts and their balances.\n\nArguments:\n1. minconf (numeric, optional, default=2) Minimum number of block confirmations required before an unspent output's value is included in the balance\n\nResult:\n{\n \"The account name\": The account balance valued in HC, (object) JSON object with account names as keys and HC amounts as values\n ...\n}\n",
+		"listlockunspent":                        "listlockunspent\n\nReturns a JSON array of outpoints marked as locked (with lockunspent) for this wallet session.\n\nArguments:\nNone\n\nResult:\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output\n \"vout\": n,       (numeric) The output index of the referenced output\n \"tree\": n,       (numeric) The tree to generate transaction for\n},...]\n",
+		"listreceivedbyaccount":                  "listreceivedbyaccount (minconf=2 includeempty=false includewatchonly=false)\n\nDEPRECATED -- Returns a JSON array of objects listing all accounts and the total amount received by each account.\n\nArguments:\n1. minconf          (numeric, optional, default=2)     Minimum number of block confirmations required before a transaction is considered\n2. includeempty     (boolean, optional, default=false) Unused\n3. includewatchonly (boolean, optional, default=false) Unused\n\nResult:\n[{\n \"account\": \"value\", (string)  The name of the account\n \"amount\": n.nnn,    (numeric) Total amount received by payment addresses of the account valued in HC\n \"confirmations\": n, (numeric) Number of block confirmations of the most recent transaction relevant to the account\n},...]\n",
+		"listreceivedbyaddress":                  "listreceivedbyaddress (minconf=2 includeempty=false includewatchonly=false)\n\nReturns a JSON array of objects listing wallet payment addresses and their total received amounts.\n\nArguments:\n1. minconf          (numeric, optional, default=2)     Minimum number of block confirmations required before a transaction is considered\n2. includeempty     (boolean, optional, default=false) Unused\n3. includewatchonly (boolean, optional, default=false) Unused\n\nResult:\n[{\n \"account\": \"value\",              (string)          DEPRECATED -- Unset\n \"address\": \"value\",              (string)          The payment address\n \"amount\": n.nnn,                 (numeric)         Total amount received by the payment address valued in HC\n \"confirmations\": n,              (numeric)         Number of block confirmations of the most recent transaction relevant to the address\n \"txids\": [\"value\",...],          (array of string) Transaction hashes of all transactions involving this address\n \"involvesWatchonly\": true|false, (boolean)         Unset\n \"reused\": true|false,            (boolean)         Whether the external address was paid by more than one transaction (only set when addressreusepolicy is flag or refuse)\n},...]\n",
+		"listsinceblock":                         "listsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false \"account\")\n\nReturns a JSON array of objects listing details of all wallet transactions after some block.\n\nArguments:\n1. blockhash           (string, optional)                 Hash of the parent block of the first block to consider transactions from, or unset to list all transactions\n2. targetconfirmations (numeric, optional, default=1)     Minimum number of block confirmations of the last block in the result object.  Must be 1 or greater.  Note: The transactions array in the result object is not affected by this parameter\n3. includewatchonly    (boolean, optional, default=false) Unused\n4. account             (string, optional)                 Only list transactions which credit or debit this account\n\nResult:\n{\n \"transactions\": [{                 (array of object) JSON array of objects containing verbose details of the each transaction\n  \"account\": \"value\",               (string)          DEPRECATED -- Unset\n  \"address\": \"value\",               (string)          Payment address for a transaction output\n  \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n  \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n  \"blockindex\": n,                  (numeric)         Unset\n  \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n  \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n  \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n  \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n  \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n  \"involveswatchonly\": true|false,  (boolean)         Unset\n  \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n  \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n  \"txid\": \"value\",                  (string)          The hash of the transaction\n  \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n  \"vout\": n,                        (numeric)         The transaction output index\n  \"walletconflicts\": [\"value\",...], (array of string) Unset\n  \"comment\": \"value\",               (string)          Unset\n  \"otheraccount\": \"value\",          (string)          Unset\n },...],                                              \n \"lastblock\": \"value\",              (string)          Hash of the latest-synced block to be used in later calls to listsinceblock\n}                                   \n",
+		"liststaleomnipending":                   "liststaleomnipending (expiry)\n\nLists the pending omni balance changes recorded by the wallet for transactions which have been mined, are no longer recorded by the wallet, or remain unmined after the pending expiry.\nStale entries are removed as blocks are connected, and the balance changes of transactions which will never be mined are reversed.\nEntries are only listed before the next block is connected, when the omni engine failed to reverse them, or when the expiry differs from the configured one.\n\nArguments:\n1. expiry (numeric, optional) Number of blocks after which a pending entry of an unmined transaction is stale (default: the configured omnipendingexpiry, 0 to never consider unmined transactions stale)\n\nResult:\n[{\n \"txid\": \"value\",   (string)  The hash of the transaction\n \"height\": n,       (numeric) The main chain height when the pending entry was added\n \"reason\": \"value\", (string)  Why the entry is stale (mined, removed, or expired)\n},...]\n",
+		"listtransactions":                       "listtransactions (\"account\" count=10 from=0 includewatchonly=false \"cursor\")\n\nReturns a JSON array of objects containing verbose details for wallet transactions.\n\nArguments:\n1. account          (string, optional)                 DEPRECATED -- Unused (must be unset or \"*\")\n2. count            (numeric, optional, default=10)    Maximum number of transactions to create results from\n3. from             (numeric, optional, default=0)     Number of transactions to skip before results are created\n4. includewatchonly (boolean, optional, default=false) Unused\n5. cursor           (string, optional)                 Return a page of at most count transactions following this cursor from a previous page, or the newest transactions for the empty string, instead of using from\n\nResult (cursor unset):\n[{\n \"account\": \"value\",               (string)          DEPRECATED -- Unset\n \"address\": \"value\",               (string)          Payment address for a transaction output\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n \"involveswatchonly\": true|false,  (boolean)         Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"txid\": \"value\",                  (string)          The hash of the transaction\n \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n \"vout\": n,                        (numeric)         The transaction output index\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"comment\": \"value\",               (string)          Unset\n \"otheraccount\": \"value\",          (string)          Unset\n},...]\n\nResult (cursor set):\n{\n \"transactions\": [{                 (array of object) Verbose details of the transactions of the page, newest first\n  \"account\": \"value\",               (string)          DEPRECATED -- Unset\n  \"address\": \"value\",               (string)          Payment address for a transaction output\n  \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n  \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n  \"blockindex\": n,                  (numeric)         Unset\n  \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n  \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n  \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n  \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n  \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n  \"involveswatchonly\": true|false,  (boolean)         Unset\n  \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n  \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n  \"txid\": \"value\",                  (string)          The hash of the transaction\n  \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n  \"vout\": n,                        (numeric)         The transaction output index\n  \"walletconflicts\": [\"value\",...], (array of string) Unset\n  \"comment\": \"value\",               (string)          Unset\n  \"otheraccount\": \"value\",          (string)          Unset\n },...],                                              \n \"cursor\": \"value\",                 (string)          The cursor of the following page, unset after the last page\n}                                   \n",
+		"listunspent":                            "listunspent (minconf=2 maxconf=9999999 [\"address\",...] includelocked=false \"cursor\" count=100)\n\nReturns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.\n\nArguments:\n1. minconf       (numeric, optional, default=2)       Minimum number of block confirmations required before a transaction output is considered\n2. maxconf       (numeric, optional, default=9999999) Maximum number of block confirmations required before a transaction output is excluded\n3. addresses     (array of string, optional)          If set, limits the returned details to unspent outputs received by any of these payment addresses\n4. includelocked (boolean, optional, default=false)   Also include outputs locked by lockunspent or transaction drafts, and immature outputs\n5. cursor        (string, optional)                   Return a page of at most count outputs following this cursor from a previous page, or the first outputs for the empty string\n6. count         (numeric, optional, default=100)     Maximum number of outputs returned in a page (only used with cursor)\n\nResult (cursor unset):\n{\n \"txid\": \"value\",            (string)  The transaction hash of the referenced output\n \"vout\": n,                  (numeric) The output index of the referenced output\n \"tree\": n,                  (numeric) The tree the transaction comes from\n \"txtype\": n,                (numeric) The type of the transaction\n \"address\": \"value\",         (string)  The payment address that received the output\n \"account\": \"value\",         (string)  The account associated with the receiving payment address\n \"accountnumber\": n,         (numeric) The number of the account associated with the receiving payment address\n \"scriptPubKey\": \"value\",    (string)  The output script encoded as a hexadecimal string\n \"scriptclass\": \"value\",     (string)  The class of the output script\n \"redeemScript\": \"value\",    (string)  The redeem script of a P2SH output, if the script is known and the wallet is unlocked\n \"amount\": n.nnn,            (numeric) The amount of the output valued in HC\n \"confirmations\": n,         (numeric) The number of block confirmations of the transaction\n \"spendable\": true|false,    (boolean) Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses)\n \"reused\": true|false,       (boolean) Whether the external address was paid by more than one transaction (only set when addressreusepolicy is flag or refuse)\n \"locked\": true|false,       (boolean) Whether the output is locked by lockunspent or reserved by a transaction draft\n \"ticketlocked\": true|false, (boolean) Whether the output is the stake submission of a ticket, locked until the ticket is voted or revoked\n \"blockstomaturity\": n,      (numeric) The number of blocks until an immature coinbase or stake output may be spent\n}                            \n\nResult (cursor set):\n{\n \"unspent\": [{                (array of value) The unspent outputs of the page, in a stable order of their outpoints which outputs keep when they are mined\n  \"txid\": \"value\",            (string)         The transaction hash of the referenced output\n  \"vout\": n,                  (numeric)        The output index of the referenced output\n  \"tree\": n,                  (numeric)        The tree the transaction comes from\n  \"txtype\": n,                (numeric)        The type of the transaction\n  \"address\": \"value\",         (string)         The payment address that received the output\n  \"account\": \"value\",         (string)         The account associated with the receiving payment address\n  \"accountnumber\": n,         (numeric)        The number of the account associated with the receiving payment address\n  \"scriptPubKey\": \"value\",    (string)         The output script encoded as a hexadecimal string\n  \"scriptclass\": \"value\",     (string)         The class of the output script\n  \"redeemScript\": \"value\",    (string)         The redeem script of a P2SH output, if the script is known and the wallet is unlocked\n  \"amount\": n.nnn,            (numeric)        The amount of the output valued in HC\n  \"confirmations\": n,         (numeric)        The number of block confirmations of the transaction\n  \"spendable\": true|false,    (boolean)        Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses)\n  \"reused\": true|false,       (boolean)        Whether the external address was paid by more than one transaction (only set when addressreusepolicy is flag or refuse)\n  \"locked\": true|false,       (boolean)        Whether the output is locked by lockunspent or reserved by a transaction draft\n  \"ticketlocked\": true|false, (boolean)        Whether the output is the stake submission of a ticket, locked until the ticket is voted or revoked\n  \"blockstomaturity\": n,      (numeric)        The number of blocks until an immature coinbase or stake output may be spent\n },...],                                       \n \"cursor\": \"value\",           (string)         The cursor of the following page, unset after the last page\n}                             \n",
+		"lockunspent":                            "lockunspent unlock [{\"txid\":\"value\",\"vout\":n,\"tree\":n},...]\n\nLocks or unlocks an unspent output.\nLocked outputs are not chosen for transaction inputs of authored transactions and are not included in 'listunspent' results.\nLocked outputs are volatile and are not saved across wallet restarts.\nIf unlock is true and no transaction outputs are specified, all locked outputs are marked unlocked.\n\nArguments:\n1. unlock       (boolean, required)         True to unlock outputs, false to lock\n2. transactions (array of object, required) Transaction outputs to lock or unlock\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output\n \"vout\": n,       (numeric) The output index of the referenced output\n \"tree\": n,       (numeric) The tree to generate transaction for\n},...]\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
+		"omni_estimatefee":                       "omni_estimatefee \"fromaddress\" \"payload\" (\"toaddress\" \"changeaddress\")\n\nEstimates the fee of an omni transaction sending a payload.\nThe transaction is constructed as by the wallet's omni sends, with a reference output paying the recipient (or the sending address when no recipient is provided), the payload output, and inputs funded by the sending address, but it is neither signed nor published.\n\nArguments:\n1. fromaddress   (string, required) The address to send from\n2. payload       (string, required) The hex-encoded omni payload of the transaction\n3. toaddress     (string, optional) The address of the recipient (default: the sending address)\n4. changeaddress (string, optional) The address receiving change (default: the default address of the sending account)\n\nResult:\n{\n \"type\": n,                (numeric) The omni transaction type of the payload\n \"fee\": n.nnn,             (numeric) The estimated fee valued in HC\n \"relayfee\": n.nnn,        (numeric) The relay fee per kB of the sending account\n \"size\": n,                (numeric) The estimated size of the signed transaction in bytes\n \"inputs\": n,              (numeric) The number of inputs selected to fund the transaction\n \"totalinput\": n.nnn,      (numeric) The total value of the selected inputs valued in HC\n \"referenceamount\": n.nnn, (numeric) The value of the reference output valued in HC\n \"change\": n.nnn,          (numeric) The value of the change output valued in HC, or 0 if there is no change\n}                          \n",
+		"omni_getdustthreshold":                  "omni_getdustthreshold (\"address\" \"fromaddress\")\n\nReturns the minimum value of an output paying an address which is relayed under the relay fee of the sending account.\nOmni reference outputs pay this value to the recipient of a transaction.\n\nArguments:\n1. address     (string, optional) The address paid by the output (default: the threshold of a pay-to-pubkey-hash output)\n2. fromaddress (string, optional) The address sending the transaction, whose account's relay fee is used (default: the relay fee of the default account)\n\nResult:\n{\n \"threshold\": n.nnn, (numeric) The dust threshold valued in HC\n \"relayfee\": n.nnn,  (numeric) The relay fee per kB the threshold is calculated with\n \"scriptsize\": n,    (numeric) The size of the output script paying the address\n}                    \n",
+		"redeemmultisigout":                      "redeemmultisigout \"hash\" index tree (\"address\")\n\nTakes the input and constructs a P2PKH paying to the specified address.\n\nArguments:\n1. hash    (string, required)  Hash of the input transaction\n2. index   (numeric, required) Idx of the input transaction\n3. tree    (numeric, required) Tree the transaction is on.\n4. address (string, optional)  Address to pay to.\n\nResult:\n{\n \"hex\": \"value\",         (string)          Resulting hash.\n \"complete\": true|false, (boolean)         Shows if opperation was completed.\n \"errors\": [{            (array of object) Any errors generated.\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n}                        \n",
+		"redeemmultisigouts":                     "redeemmultisigouts \"fromscraddress\" (\"toaddress\" number)\n\nTakes a hash, looks up all unspent outpoints and generates list artially signed transactions spending to either an address specified or internal addresses\n\nArguments:\n1. fromscraddress (string, required)  Input script hash address.\n2. toaddress      (string, optional)  Address to look for (if not internal addresses).\n3. number         (numeric, optional) Number of outpoints found.\n\nResult:\n{\n \"hex\": \"value\",         (string)          Resulting hash.\n \"complete\": true|false, (boolean)         Shows if opperation was completed.\n \"errors\": [{            (array of object) Any errors generated.\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n}                        \n",
+		"rescanwallet":                           "rescanwallet (beginheight=0)\n\nRescan the block chain for wallet data, blocking until the rescan completes or exits with an error\n\nArguments:\n1. beginheight (numeric, optional, default=0) The height of the first block to begin the rescan from (0 begins at the wallet birthday when the wallet has one)\n\nResult:\nn (numeric) The height of the block the rescan began from, which is the wallet birthday height when beginheight is 0 and the wallet has a birthday\n",
+		"reserveoutputs":                         "reserveoutputs amount (account=\"default\" minconf=1 expiry=60)\n\nSelects spendable outputs of an account totaling at least an amount and locks them in a single step, so concurrent requests never reserve the same outputs.\nOutputs are selected largest first, and locked outputs are never selected.\nReserved outputs are unlocked when the expiry elapses, or earlier with lockunspent.\n\nArguments:\n1. amount  (numeric, required)                   The minimum total amount of the reserved outputs\n2. account (string, optional, default=\"default\") The account to reserve outputs of\n3. minconf (numeric, optional, default=1)        Minimum number of block confirmations of the reserved outputs\n4. expiry  (numeric, optional, default=60)       Number of seconds until the outputs are unlocked (0 to keep them locked until unlocked with lockunspent)\n\nResult:\n{\n \"outputs\": [{             (array of object) The reserved outputs\n  \"txid\": \"value\",         (string)          The hash of the transaction of the output\n  \"vout\": n,               (numeric)         The index of the output\n  \"tree\": n,               (numeric)         The tree of the transaction of the output\n  \"address\": \"value\",      (string)          The address paid by the output\n  \"scriptPubKey\": \"value\", (string)          The output script of the output\n  \"amount\": n.nnn,         (numeric)         The amount of the output\n },...],                                     \n \"total\": n.nnn,           (numeric)         The total amount of the reserved outputs\n \"expires\": n,             (numeric)         The Unix time when the outputs are unlocked, or 0 if they remain locked\n}                          \n",
+		"revoketickets":                          "revoketickets (allowhighfees feerate [\"ticket\",...] dryrun=false)\n\nRequests the wallet create revocations for previously missed and expired tickets, returning each revoked ticket with the hash of its revocation.  Wallet must be unlocked unless dryrun is set.\n\nArguments:\n1. allowhighfees (boolean, optional)                Allow sending revocations with high fees (default=true, as revocation fees are paid from the ticket).\n2. feerate       (numeric, optional)                Fee per kB paid by each revocation (default is the relay fee)\n3. tickets       (array of string, optional)        Hashes of the missed or expired tickets to revoke (default is every unrevoked missed or expired ticket)\n4. dryrun        (boolean, optional, default=false) Return the revocations which would be created without signing or publishing them\n\nResult:\n{\n \"allowhighfees\": true|false, (boolean)         Whether high fees were allowed when sending the revocations.\n \"dryrun\": true|false,        (boolean)         Whether the revocations were only created without being signed or published\n \"revocations\": [{            (array of object) The revoked tickets and their revocations\n  \"ticket\": \"value\",          (string)          The hash of the revoked ticket\n  \"revocation\": \"value\",      (string)          The hash of the revocation transaction\n },...],                                        \n}                             \n",
+		"sendfrom":                               "sendfrom \"fromaccount\" \"toaddress\" amount (minconf=2 \"comment\" \"commentto\" \"idempotencykey\")\n\nDEPRECATED -- Authors, signs, and sends a transaction that outputs some amount to a payment address.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount    (string, required)             Account to pick unspent outputs from\n2. toaddress      (string, required)             Address to pay\n3. amount         (numeric, required)            Amount to send to the payment address valued in HC\n4. minconf        (numeric, optional, default=2) Minimum number of block confirmations required before a transaction output is eligible to be spent\n5. comment        (string, optional)             Unused\n6. commentto      (string, optional)             Unused\n7. idempotencykey (string, optional)             Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
+		"sendmany":                               "sendmany \"fromaccount\" {\"address\":amount,...} (minconf=2 \"comment\" \"idempotencykey\")\n\nAuthors, signs, and sends a transaction that outputs to many payment addresses.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required) DEPRECATED -- Account to pick unspent outputs from\n2. amounts     (object, required) Pairs of payment addresses and the output amount to pay each\n{\n \"Address to pay\": Amount to send to the payment address valued in HC, (object) JSON object using payment addresses as keys and output amounts valued in HC to send to each address\n ...\n}\n3. minconf        (numeric, optional, default=2) Minimum number of block confirmations required before a transaction output is eligible to be spent\n4. comment        (string, optional)             Unused\n5. idempotencykey (string, optional)             Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
+		"sendmanyv2":                             "sendmanyv2 \"fromaccount\" {\"address\":amount,...} (\"changeaddr\" minconf=2 \"idempotencykey\")\n\nAuthors, signs, and sends a transaction that outputs to many payment addresses.\nA change addr is set or automatically included to send extra output value back to the original account first addr.\n\nArguments:\n1. fromaccount (string, required) DEPRECATED -- Account to pick unspent outputs from\n2. amounts     (object, required) Pairs of payment addresses and the output amount to pay each\n{\n \"Address to pay\": Amount to send to the payment address valued in HC, (object) JSON object using payment addresses as keys and output amounts valued in HC to send to each address\n ...\n}\n3. changeaddr     (string, optional)             change addr, if not set, use account first first addr\n4. minconf        (numeric, optional, default=2) Minimum number of block confirmations required before a transaction output is eligible to be spent\n5. idempotencykey (string, optional)             Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
+		"sendbatch":                              "sendbatch \"fromaccount\" \"payments\" (minconf=1 atomic=false)\n\nPays many addresses with as few transactions as the transaction size limits allow.\nPayments are assigned in order to transactions sized by estimating their signed size, each returning change to the account.\nIn atomic mode, every transaction is created before any is published, so none is published unless every payment can be funded. The batch is not atomic once publishing begins: if the consensus server rejects a transaction, the earlier transactions remain published. Otherwise, transactions are published as they are created and an error stops the batch.\nAn error is only returned when no transaction was published. Once a transaction is published, the error field of the result describes why any remaining payments were not made.\n\nArguments:\n1. fromaccount (string, required)                 The account funding the transactions\n2. payments    (string, required)                 CSV records of an address and an amount valued in HC, one payment per line; blank lines and lines beginning with '#' are ignored\n3. minconf     (numeric, optional, default=1)     Minimum number of block confirmations required of spent outputs\n4. atomic      (boolean, optional, default=false) Create every transaction before publishing any; transactions published before the consensus server rejects a later one remain published\n\nResult:\n{\n \"txids\": [\"value\",...], (array of string) The hashes of the published transactions, in the order they were published\n \"fee\": n.nnn,           (numeric)         The total fee paid by the published transactions\n \"outputs\": [{           (array of object) The output paying each payment, in the order of the payments\n  \"address\": \"value\",    (string)          The payment address\n  \"amount\": n.nnn,       (numeric)         The payment amount\n  \"txid\": \"value\",       (string)          The hash of the transaction paying the payment, or empty if the payment was not made\n  \"vout\": n,             (numeric)         The index of the output paying the payment\n },...],                                   \n \"error\": \"value\",       (string)          Why the remaining payments were not made, if the batch stopped after publishing transactions\n}                        \n",
+		"senddata":                               "senddata \"data\" (account=\"default\" minconf=1)\n\nAuthors, signs, and sends a transaction with an OP_RETURN output carrying arbitrary data.\nThe data may not exceed 1024 bytes, the largest OP_RETURN payload relayed by hcd. A change output returns the remaining input value, less the fee, to the account.\n\nArguments:\n1. data    (string, required)                    The hex-encoded data\n2. account (string, optional, default=\"default\") The account funding the transaction\n3. minconf (numeric, optional, default=1)        Minimum number of block confirmations required of spent outputs\n\nResult:\n{\n \"txid\": \"value\", (string)  The hash of the sent transaction\n \"fee\": n.nnn,    (numeric) The fee paid by the transaction\n}                 \n",
+		"sendrawtransaction":                     "sendrawtransaction \"hextx\" (allowhighfees=false)\n\nSubmits a serialized transaction to hcd after checking it does not conflict with the wallet.\nTransactions spending outpoints which are locked or reserved by the wallet, or double spending unmined wallet transactions, are rejected.\nTransactions relevant to the wallet are recorded by the wallet before they are sent.\nUnless the wallet was started with --skippreflight, the scripts of each input are first validated against the outputs they spend, and invalid transactions error with code -25 instead of being sent.\n\nArguments:\n1. hextx         (string, required)                 Serialized transaction to send, encoded as a hexadecimal string\n2. allowhighfees (boolean, optional, default=false) Allow the transaction to pay a fee above the high fee limit of hcd\n\nResult:\n\"value\" (string) The hash of the sent transaction\n",
+		"sendtoaddress":                          "sendtoaddress \"address\" amount (\"comment\" \"commentto\" \"idempotencykey\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. address        (string, required)  Address to pay\n2. amount         (numeric, required) Amount to send to the payment address valued in HC\n3. comment        (string, optional)  Unused\n4. commentto      (string, optional)  Unused\n5. idempotencykey (string, optional)  Optional key identifying the request; retrying with the same key and parameters returns the hash of the transaction already sent with it rather than sending another, and reusing the key with different parameters is an error\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
+		"sendfromaddresstoaddress":               "sendfromaddresstoaddress \"fromaddress\" \"address\" amount\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaddress (string, required)  Address to send\n2. address     (string, required)  Address to pay\n3. amount      (numeric, required) Amount to send to the payment address valued in HC\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
+		"sendtomultisig":                         "sendtomultisig \"fromaccount\" amount [\"pubkey\",...] (nrequired=1 minconf=2 \"comment\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a multisig address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required)             Unused\n2. amount      (numeric, required)            Amount to send to the payment address valued in HC\n3. pubkeys     (array of string, required)    Pubkey to send to.\n4. nrequired   (numeric, optional, default=1) The number of signatures required to redeem outputs paid to this address\n5. minconf     (numeric, optional, default=2) Minimum number of block confirmations required\n6. comment     (string, optional)             Unused\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction\n",
+		"settxfee":                               "settxfee amount (\"account\")\n\nModify the fee per kB of the serialized tx size used each time more fee is required for an authored transaction.\n\nArguments:\n1. amount  (numeric, required) The new fee per kB of the serialized tx size valued in HC\n2. account (string, optional)  Set the fee for this account only rather than the wallet's default fee\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
+		"setvotechoice":                          "setvotechoice \"agendaid\" \"choiceid\"\n\nSets choices for defined agendas in the latest stake version supported by this software\n\nArguments:\n1. agendaid (string, required) The ID for the agenda to modify\n2. choiceid (string, required) The ID for the choice to choose\n\nResult:\nNothing\n",
+		"signmessage":                            "signmessage \"address\" \"message\"\n\nSigns a message using the private key of a payment address.\n\nArguments:\n1. address (string, required) Payment address of private key used to sign the message with\n2. message (string, required) Message to sign\n\nResult:\n\"value\" (string) The signed message encoded as a base64 string (signatures by bliss addresses use an extended format which includes the bliss public key)\n",
+		"signmessagewithaccount":                 "signmessagewithaccount \"account\" \"message\"\n\nSigns a message with the account proof key of an account, proving control of the whole account rather than a single address.\nThe proof key is derived from the account extended key at branch 2, index 0, which is never used for addresses.\nRequires an unlocked wallet, and only secp256k1 accounts sign account proofs.\n\nArguments:\n1. account (string, required) The account signing the message\n2. message (string, required) Message to sign\n\nResult:\n{\n \"xpub\": \"value\",      (string) The extended public key of the account, which verifies the proof\n \"signature\": \"value\", (string) The signed message encoded as a base64 string\n}                      \n",
+		"signrawtransaction":                     "signrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\",\"amount\":amount},...] [\"privkey\",...] flags=\"ALL\")\n\nSigns transaction inputs using private keys from this wallet and request.\nThe valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.\n\nArguments:\n1. rawtx    (string, required)                Unsigned or partially unsigned transaction to sign encoded as a hexadecimal string\n2. inputs   (array of object, optional)       Additional data regarding inputs that this wallet may not be tracking, optionally including the amount of each previous output which is checked against the wallet and blockchain\n3. privkeys (array of string, optional)       Additional WIF-encoded secp256k1, edwards, schnorr or bliss private keys to use when creating signatures\n4. flags    (string, optional, default=\"ALL\") Sighash flags\n\nResult:\n{\n \"hex\": \"value\",         (string)          The resulting transaction encoded as a hexadecimal string\n \"complete\": true|false, (boolean)         Whether all input signatures have been created\n \"errors\": [{            (array of object) Script verification errors (if exists)\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n \"fee\": n.nnn,           (numeric)         The fee paid by the transaction, when the value of every input is known\n \"inputs\": [{            (array of object) The value of each input of the transaction\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"amount\": n.nnn,       (numeric)         The value of the previous output, omitted when unknown\n  \"source\": \"value\",     (string)          Where the value was determined from: wallet or chain (verified), request or transaction (unverified), or stakebase\n },...],                                   \n}                        \n",
+		"signrawtransactions":                    "signrawtransactions [\"rawtx\",...] (send=true allowhighfees)\n\nSigns transaction inputs using private keys from this wallet and request for a list of transactions.\n\n\nArguments:\n1. rawtxs        (array of string, required)       A list of transactions to sign (and optionally send).\n2. send          (boolean, optional, default=true) Set true to send the transactions after signing.\n3. allowhighfees (boolean, optional)               Allow sending transactions with high fees (default is the wallet's --allowhighfees setting).\n\nResult:\n{\n \"results\": [{                (array of object) Returned values from the signrawtransactions command.\n  \"signingresult\": {          (object)          Success or failure of signing.\n   \"hex\": \"value\",            (string)          The resulting transaction encoded as a hexadecimal string\n   \"complete\": true|false,    (boolean)         Whether all input signatures have been created\n   \"errors\": [{               (array of object) Script verification errors (if exists)\n    \"txid\": \"value\",          (string)          The transaction hash of the referenced previous output\n    \"vout\": n,                (numeric)         The output index of the referenced previous output\n    \"scriptSig\": \"value\",     (string)          The hex-encoded signature script\n    \"sequence\": n,            (numeric)         Script sequence number\n    \"error\": \"value\",         (string)          Verification or signing error related to the input\n   },...],                                      \n   \"fee\": n.nnn,              (numeric)         The fee paid by the transaction, when the value of every input is known\n   \"inputs\": [{               (array of object) The value of each input of the transaction\n    \"txid\": \"value\",          (string)          The transaction hash of the referenced previous output\n    \"vout\": n,                (numeric)         The output index of the referenced previous output\n    \"amount\": n.nnn,          (numeric)         The value of the previous output, omitted when unknown\n    \"source\": \"value\",        (string)          Where the value was determined from: wallet or chain (verified), request or transaction (unverified), or stakebase\n   },...],                                      \n  },                                            \n  \"sent\": true|false,         (boolean)         Tells if the transaction was sent.\n  \"txhash\": \"value\",          (string)          The hash of the signed tx.\n },...],                                        \n \"allowhighfees\": true|false, (boolean)         Whether high fees were allowed when sending the transactions.\n}                             \n",
+		"validateaddress":                        "validateaddress \"address\"\n\nVerify that an address is valid.\nExtra details are returned if the address is controlled by this wallet.\nThe following fields are valid only when the address is controlled by this wallet (ismine=true): isscript, pubkey, iscompressed, account, addresses, hex, script, and sigsrequired.\nThe following fields are only valid when address has an associated public key: pubkey, iscompressed.\nThe following fields are only valid when address is a pay-to-script-hash address: addresses, hex, and script.\nIf the address is a multisig address controlled by this wallet, the multisig fields will be left unset if the wallet is locked since the redeem script cannot be decrypted.\n\nArguments:\n1. address (string, required) Address to validate\n\nResult:\n{\n \"isvalid\": true|false,      (boolean)         Whether or not the address is valid\n \"address\": \"value\",         (string)          The payment address (only when isvalid is true)\n \"ismine\": true|false,       (boolean)         Whether this address is controlled by the wallet (only when isvalid is true)\n \"iswatchonly\": true|false,  (boolean)         Unset\n \"isscript\": true|false,     (boolean)         Whether the payment address is a pay-to-script-hash address (only when isvalid is true)\n \"pubkeyaddr\": \"value\",      (string)          The pubkey for this payment address (only when isvalid is true)\n \"pubkey\": \"value\",          (string)          The associated public key of the payment address, if any (only when isvalid is true)\n \"iscompressed\": true|false, (boolean)         Whether the address was created by hashing a compressed public key, if any (only when isvalid is true)\n \"account\": \"value\",         (string)          The account this payment address belongs to (only when isvalid is true)\n \"addresses\": [\"value\",...], (array of string) All associated payment addresses of the script if address is a multisig address (only when isvalid is true)\n \"hex\": \"value\",             (string)          The redeem script \n \"script\": \"value\",          (string)          The class of redeem script for a multisig address\n \"sigsrequired\": n,          (numeric)         The number of required signatures to redeem outputs to the multisig address\n}                            \n",
+		"verifyaccountproof":                     "verifyaccountproof \"xpub\" \"signature\" \"message\"\n\nVerify a message was signed by signmessagewithaccount with the account of an extended public key.\n\nArguments:\n1. xpub      (string, required) The extended public key of the account\n2. signature (string, required) The signature to verify\n3. message   (string, required) The message to verify\n\nResult:\ntrue|false (boolean) Whether the message was signed by the account of 'xpub'\n",
+		"verifymessage":                          "verifymessage \"address\" \"signature\" \"message\"\n\nVerify a message was signed with the associated private key of some address.\n\nArguments:\n1. address   (string, required) Address used to sign message\n2. signature (string, required) The signature to verify, in the extended format for bliss addresses\n3. message   (string, required) The message to verify\n\nResult:\ntrue|false (boolean) Whether the message was signed with the private key of 'address'\n",
+		"verifyseedbackup":                       "verifyseedbackup ([\"word\",...])\n\nReturns the seed word positions of the wallet's seed backup challenge, and when words are provided, checks them against the seed the wallet was created from without revealing it.\nThe seed backup is recorded as verified once all words match.\nChecking is intentionally slow, does not reveal which words did not match, and requires the wallet to be unlocked.\n\nArguments:\n1. words (array of string, optional) Mnemonic seed words at each of the challenge positions, in order (omit to only return the challenge)\n\nResult:\n{\n \"positions\": [n,...],   (array of numeric) One-based positions of the seed words checked by the challenge\n \"verified\": true|false, (boolean)          Whether a backup of the seed has been verified\n \"match\": true|false,    (boolean)          Whether the provided words match the seed (only when words are provided)\n}                        \n",
+		"version":                                "version\n\nReturns application and API versions (semver) keyed by their names.\nThe hcwalletresultversion major version is the latest result version, which clients may request with the resultversion member of request objects to receive extended result layouts (results default to the legacy layouts of version 1)\n\nArguments:\nNone\n\nResult:\n{\n \"Program or API name\": Object containing the semantic version, (object) Version objects keyed by the program or API name\n ...\n}\n",
+		"walletlock":                             "walletlock\n\nLock the wallet.\n\nArguments:\nNone\n\nResult:\nNothing\n",
+		"walletpassphrase":                       "walletpassphrase \"passphrase\" timeout\n\nUnlock the wallet.\n\nArguments:\n1. passphrase (string, required)  The wallet passphrase\n2. timeout    (numeric, required) The number of seconds to wait before the wallet automatically locks\n\nResult:\nNothing\n",
+		"walletpassphrasechange":                 "walletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\n\nChange the wallet passphrase.\n\nArguments:\n1. oldpassphrase (string, required) The old wallet passphrase\n2. newpassphrase (string, required) The new wallet passphrase\n\nResult:\nNothing\n",
+		"createnewaccount":                       "createnewaccount \"account\" \"accounttype\"\n\nCreates a new account.\nThe wallet must be unlocked for this request to succeed.\n\nArguments:\n1. account     (string, required) Name of the new account\n2. accounttype (string, required) The type of the new account\n\nResult:\nNothing\n",
+		"exportwatchingwallet":                   "exportwatchingwallet (\"account\" download=false)\n\nCreates and returns a duplicate of the wallet database without any private keys to be used as a watching-only wallet.\n\nArguments:\n1. account  (string, optional)                 Unused (must be unset or \"*\")\n2. download (boolean, optional, default=false) Unused\n\nResult:\n\"value\" (string) The watching-only database encoded as a base64 string\n",
+		"getbestblock":                           "getbestblock\n\nReturns the hash and height of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\n{\n \"hash\": \"value\", (string)  The hash of the block\n \"height\": n,     (numeric) The blockchain height of the block\n}                 \n",
+		"getunconfirmedbalance":                  "getunconfirmedbalance (\"account\")\n\nCalculates the unspent output value of all unmined transaction outputs for an account.\n\nArguments:\n1. account (string, optional) The account to query the unconfirmed balance for (default=\"default\")\n\nResult:\nn.nnn (numeric) Total amount of all unmined unspent outputs of the account valued in HC.\n",
+		"listaddresstransactions":                "listaddresstransactions [\"address\",...] (\"account\")\n\nReturns a JSON array of objects containing verbose details for wallet transactions pertaining some addresses.\n\nArguments:\n1. addresses (array of string, required) Addresses to filter transaction results by\n2. account   (string, optional)          Unused (must be unset or \"*\")\n\nResult:\n[{\n \"account\": \"value\",               (string)          DEPRECATED -- Unset\n \"address\": \"value\",               (string)          Payment address for a transaction output\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n \"involveswatchonly\": true|false,  (boolean)         Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"txid\": \"value\",                  (string)          The hash of the transaction\n \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n \"vout\": n,                        (numeric)         The transaction output index\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"comment\": \"value\",               (string)          Unset\n \"otheraccount\": \"value\",          (string)          Unset\n},...]\n",
+		"listalltransactions":                    "listalltransactions (\"account\" \"cursor\" count=100)\n\nReturns a JSON array of objects in the same format as 'listtransactions' without limiting the number of returned objects, unless a cursor is used to page through them.\n\nArguments:\n1. account (string, optional)               Unused (must be unset or \"*\")\n2. cursor  (string, optional)               Return a page of at most count transactions following this cursor from a previous page, or the newest transactions for the empty string\n3. count   (numeric, optional, default=100) Maximum number of transactions returned in a page (only used with cursor)\n\nResult (cursor unset):\n[{\n \"account\": \"value\",               (string)          DEPRECATED -- Unset\n \"address\": \"value\",               (string)          Payment address for a transaction output\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n \"blockindex\": n,                  (numeric)         Unset\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n \"involveswatchonly\": true|false,  (boolean)         Unset\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n \"txid\": \"value\",                  (string)          The hash of the transaction\n \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n \"vout\": n,                        (numeric)         The transaction output index\n \"walletconflicts\": [\"value\",...], (array of string) Unset\n \"comment\": \"value\",               (string)          Unset\n \"otheraccount\": \"value\",          (string)          Unset\n},...]\n\nResult (cursor set):\n{\n \"transactions\": [{                 (array of object) Verbose details of the transactions of the page, newest first\n  \"account\": \"value\",               (string)          DEPRECATED -- Unset\n  \"address\": \"value\",               (string)          Payment address for a transaction output\n  \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in HC\n  \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined\n  \"blockindex\": n,                  (numeric)         Unset\n  \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined\n  \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n  \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction\n  \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions\n  \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output\n  \"involveswatchonly\": true|false,  (boolean)         Unset\n  \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist\n  \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist\n  \"txid\": \"value\",                  (string)          The hash of the transaction\n  \"txtype\": \"value\",                (string)          The type of tx (regular tx, stake tx)\n  \"vout\": n,                        (numeric)         The transaction output index\n  \"walletconflicts\": [\"value\",...], (array of string) Unset\n  \"comment\": \"value\",               (string)          Unset\n  \"otheraccount\": \"value\",          (string)          Unset\n },...],                                              \n \"cursor\": \"value\",                 (string)          The cursor of the following page, unset after the last page\n}                                   \n",
+		"notifytipchanges":                       "notifytipchanges\n\nSend a tipchanged notification, with the hashes of attached and detached blocks and the new main chain height, each time the wallet processes a change to the main chain tip (websocket clients only).\n\nArguments:\nNone\n\nResult:\nNothing\n",
+		"renameaccount":                          "renameaccount \"oldaccount\" \"newaccount\"\n\nRenames an account.\n\nArguments:\n1. oldaccount (string, required) The old account name to rename\n2. newaccount (string, required) The new name for the account\n\nResult:\nNothing\n",
+		"stopnotifytipchanges":                   "stopnotifytipchanges\n\nStop sending tipchanged notifications requested by notifytipchanges (websocket clients only).\n\nArguments:\nNone\n\nResult:\nNothing\n",
+		"walletislocked":                         "walletislocked\n\nReturns whether or not the wallet is locked.\n\nArguments:\nNone\n\nResult:\ntrue|false (boolean) Whether the wallet is locked\n",
+		"walletinfo":                             "walletinfo\n\nReturns global information about the wallet\n\nArguments:\nNone\n\nResult:\n{\n \"daemonconnected\": true|false,     (boolean) Whether or not the wallet is currently connected to the daemon RPC\n \"unlocked\": true|false,            (boolean) Whether or not the wallet is unlocked\n \"txfee\": n.nnn,                    (numeric) Transaction fee per kB of the serialized tx size in coins\n \"ticketfee\": n.nnn,                (numeric) Ticket fee per kB of the serialized tx size in coins\n \"ticketpurchasing\": true|false,    (boolean) Whether or not the wallet is currently purchasing tickets\n \"votebits\": n,                     (numeric) Vote bits setting\n \"votebitsextended\": \"value\",       (string)  Extended vote bits setting\n \"voteversion\": n,                  (numeric) Version of votes that will be generated\n \"voting\": true|false,              (boolean) Whether or not the wallet is currently voting tickets\n \"rescanning\": true|false,          (boolean) Whether or not a rescan is running\n \"rescanprogress\": n.nnn,           (numeric) The fraction of the blocks from the rescan start height through the main chain tip which have been rescanned (only when rescanning)\n \"omnienabled\": true|false,         (boolean) Whether or not omni is enabled\n \"omniwaterline\": n,                (numeric) The height of the last block processed by omni (only when omni is enabled and responding)\n \"accounts\": n,                     (numeric) The number of accounts, including the imported account\n \"watchedaddresses\": n,             (numeric) The number of active addresses watched for relevant transactions\n \"birthdayheight\": n,               (numeric) The height of the wallet birthday block, or 0 when the birthday is unset or unresolved\n \"networkstakeversion\": n,          (numeric) Highest stake version of the blocks connected since the wallet was started\n \"voteversionoutdated\": true|false, (boolean) Whether the stake version of the majority of recent blocks is newer than the vote version of the wallet\n \"skipstalevotes\": true|false,      (boolean) Whether votes are not created while the vote version is outdated\n}                                   \n",
+		"prunewallethistory":                     "prunewallethistory (depth)\n\nRemoves the records of fully spent regular transactions mined at least depth blocks below the main chain tip, recording their totals in a history checkpoint.\nTickets, votes, revocations, transactions funding tickets, and transactions with multisig outputs are never pruned.\nPruned transactions no longer appear in the transaction history.\n\nArguments:\n1. depth (numeric, optional) Number of blocks below the main chain tip at and below which transactions are pruned (default: the configured prunehistory depth, minimum 1024)\n\nResult:\n{\n \"height\": n,       (numeric) The block height of the history checkpoint\n \"transactions\": n, (numeric) The number of transactions pruned at and below the checkpoint height\n \"credits\": n.nnn,  (numeric) The total amount of the credits of the pruned transactions\n \"debits\": n.nnn,   (numeric) The total amount of the debits of the pruned transactions\n}                   \n",
+		"purchaseticket":                         "purchaseticket \"fromaccount\" spendlimit (minconf=2 \"ticketaddress\" numtickets \"pooladdress\" poolfees expiry \"comment\" ticketfee)\n\nPurchase tickets using available funds.\nA split transaction is published to fund each ticket with an output of the exact ticket cost.\nTickets which fail to be purchased after the split transaction is published are reported in the result rather than as an error.\n\nArguments:\n1.  fromaccount   (string, required)             The account to use for purchase (default=\"default\")\n2.  spendlimit    (numeric, required)            Limit on the amount to spend on ticket\n3.  minconf       (numeric, optional, default=2) Minimum number of block confirmations required\n4.  ticketaddress (string, optional)             Override the ticket address to which voting rights are given\n5.  numtickets    (numeric, optional)            The number of tickets to purchase\n6.  pooladdress   (string, optional)             The address to pay stake pool fees to\n7.  poolfees      (numeric, optional)            The amount of fees to pay to the stake pool\n8.  expiry        (numeric, optional)            Height at which the purchase tickets expire\n9.  comment       (string, optional)             Unused\n10. ticketfee     (numeric, optional)            The ticket fee in coins per kB (default is the wallet's ticket fee)\n\nResult:\n{\n \"splittx\": \"value\",       (string)          The hash of the split transaction funding the tickets\n \"tickets\": [\"value\",...], (array of string) The hashes of the published tickets\n \"failures\": [{            (array of object) Each ticket which was not purchased\n  \"index\": n,              (numeric)         The zero-based index of the ticket among the requested tickets\n  \"error\": \"value\",        (string)          The reason the ticket was not purchased\n },...],                                     \n \"totalspent\": n.nnn,      (numeric)         The amount spent by the published tickets, including ticket and stake pool fees, and the fee of the split transaction\n}                          \n",
+		"sendtossrtx":                            "sendtossrtx \"fromaccount\" \"tickethash\" (\"comment\" allowhighfees)\n\nSend to SS Revocation transaction\n\nArguments:\n1. fromaccount   (string, required)  The account to spend a stake ticket from (default=\"default\")\n2. tickethash    (string, required)  Hash of the ticket to be revoked\n3. comment       (string, optional)  Unused\n4. allowhighfees (boolean, optional) Allow sending the revocation with high fees (default=true, as revocation fees are paid from the ticket).\n\nResult:\n\"value\" (string) txid of the resulting transaction\n",
+		"sendtosstx":                             "sendtosstx \"fromaccount\" amounts [{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"amt\":n},...] [{\"addr\":\"value\",\"commitamt\":n,\"changeaddr\":\"value\",\"changeamt\":n},...] (minconf=2 \"comment\" allowhighfees)\n\nSend to SStx\n\nArguments:\n1. fromaccount (string, required) The account sent from\n2. amounts     (object, required) Amounts to send\n{\n \"Key\": Value, (object) Unused\n ...\n}\n3. inputs (array of object, required) Inputs for the tx\n[{\n \"txid\": \"value\", (string)  Txid to use\n \"vout\": n,       (numeric) Vout for the input tx\n \"tree\": n,       (numeric) Input tree\n \"amt\": n,        (numeric) Amount\n},...]\n4. couts (array of object, required) Couts for the tx\n[{\n \"addr\": \"value\",       (string)  Address to use\n \"commitamt\": n,        (numeric) Amount to commit\n \"changeaddr\": \"value\", (string)  Change address to use\n \"changeamt\": n,        (numeric) Change amount\n},...]\n5. minconf       (numeric, optional, default=2) Minimum number of block confirmations required\n6. comment       (string, optional)             Unused\n7. allowhighfees (boolean, optional)            Allow sending the transaction with high fees (default is the wallet's --allowhighfees setting).\n\nResult (resultversion=1):\n\"value\" (string) txid of the resulting transaction\n\nResult (resultversion=2):\n{\n \"txhash\": \"value\",           (string)  txid of the resulting transaction\n \"allowhighfees\": true|false, (boolean) Whether high fees were allowed when sending the transaction.\n}                             \n",
+		"sendtossgen":                            "sendtossgen \"fromaccount\" \"tickethash\" \"blockhash\" height votebits (\"comment\")\n\nGenerate a vote tx\n\nArguments:\n1. fromaccount (string, required)  The account to use (default=\"default\")\n2. tickethash  (string, required)  Hash of the ticket used for vote\n3. blockhash   (string, required)  Hash for the block being voted on\n4. height      (numeric, required) Blockheight for vote\n5. votebits    (numeric, required) Votebits to set\n6. comment     (string, optional)  Unused\n\nResult:\n\"value\" (string) txid of the resulting transaction\n",
+		"generatevote":                           "generatevote \"blockhash\" height \"tickethash\" votebits \"votebitsext\"\n\nReturns the vote transaction encoded as a hexadecimal string\n\nArguments:\n1. blockhash   (string, required)  Block hash for the ticket\n2. height      (numeric, required) Block height for the ticket\n3. tickethash  (string, required)  The hash of the ticket\n4. votebits    (numeric, required) The voteBits to set for the ticket\n5. votebitsext (string, required)  The extended voteBits to set for the ticket\n\nResult:\n{\n \"hex\": \"value\", (string) The hex encoded transaction\n}                \n",
+		"generateticketproof":                    "generateticketproof \"tickethash\" \"challenge\"\n\nSigns a challenge with the key of a commitment address or the voting address of a ticket, proving ownership of the ticket without revealing keys.\nCommitment addresses are preferred, as they remain controlled by the ticket owner when voting is delegated to a stake pool.\nRequires the wallet to be unlocked.\n\nArguments:\n1. tickethash (string, required) The hash of the ticket\n2. challenge  (string, required) The challenge to sign, chosen by the verifier\n\nResult:\n{\n \"address\": \"value\",   (string) The commitment or voting address of the ticket which signed the challenge\n \"signature\": \"value\", (string) The signature encoded as a base64 string\n}                      \n",
+		"verifyticketproof":                      "verifyticketproof \"tickethash\" \"address\" \"signature\" \"challenge\"\n\nVerify a challenge was signed by generateticketproof with the key of a commitment or voting address of a ticket.\nTickets not recorded by the wallet are queried from the consensus server.\n\nArguments:\n1. tickethash (string, required) The hash of the ticket\n2. address    (string, required) The address which signed the challenge\n3. signature  (string, required) The signature to verify\n4. challenge  (string, required) The challenge to verify\n\nResult:\ntrue|false (boolean) Whether 'address' is a commitment or voting address of the ticket and signed the challenge\n",
+		"decodewallettransaction":                "decodewallettransaction \"hextx\"\n\nDecodes a hex-encoded transaction and annotates its inputs and outputs with the wallet's knowledge of the addresses they pay.\nPrevious outputs are only described when the spent transaction is recorded by the wallet, and the omni engine's decoding is included when omni is enabled and the transaction carries an omni payload.\n\nArguments:\n1. hextx (string, required) The hex-encoded transaction\n\nResult:\n{\n \"txid\": \"value\",              (string)           The hash of the transaction\n \"version\": n,                 (numeric)          The transaction version\n \"locktime\": n,                (numeric)          The transaction lock time\n \"expiry\": n,                  (numeric)          The height after which the transaction may not be mined\n \"type\": \"value\",              (string)           The transaction type (regular, ticket, vote, or revocation)\n \"vin\": [{                     (array of object)  The annotated transaction inputs\n  \"txid\": \"value\",             (string)           The hash of the previous transaction\n  \"vout\": n,                   (numeric)          The output index of the previous transaction\n  \"tree\": n,                   (numeric)          The tree of the previous transaction\n  \"sequence\": n,               (numeric)          The input sequence number\n  \"amountin\": n.nnn,           (numeric)          The value of the previous output as committed to by the input\n  \"stakebase\": true|false,     (boolean)          Whether the input is the stakebase of a vote\n  \"prevout\": {                 (object)           The previous output, if the previous transaction is recorded by the wallet\n   \"value\": n.nnn,             (numeric)          The output value\n   \"n\": n,                     (numeric)          The output index\n   \"scripttype\": \"value\",      (string)           The type of the output script\n   \"addresses\": [\"value\",...], (array of string)  The addresses paid by the output, or the committed address of a ticket commitment\n   \"mine\": true|false,         (boolean)          Whether the output pays a wallet address\n   \"account\": \"value\",         (string)           The account of the wallet address\n   \"label\": \"value\",           (string)           The name of the additional external branch the wallet address is derived from\n   \"change\": true|false,       (boolean)          Whether the output pays an internal (change) address\n   \"data\": \"value\",            (string)           The hex-encoded data carried by an OP_RETURN output\n   \"spent\": true|false,        (boolean)          Whether the wallet output has been spent, only set by verbose gettransaction\n   \"spentby\": \"value\",         (string)           The hash of the transaction spending the wallet output\n  },                                              \n },...],                                          \n \"vout\": [{                    (array of object)  The annotated transaction outputs\n  \"value\": n.nnn,              (numeric)          The output value\n  \"n\": n,                      (numeric)          The output index\n  \"scripttype\": \"value\",       (string)           The type of the output script\n  \"addresses\": [\"value\",...],  (array of string)  The addresses paid by the output, or the committed address of a ticket commitment\n  \"mine\": true|false,          (boolean)          Whether the output pays a wallet address\n  \"account\": \"value\",          (string)           The account of the wallet address\n  \"label\": \"value\",            (string)           The name of the additional external branch the wallet address is derived from\n  \"change\": true|false,        (boolean)          Whether the output pays an internal (change) address\n  \"data\": \"value\",             (string)           The hex-encoded data carried by an OP_RETURN output\n  \"spent\": true|false,         (boolean)          Whether the wallet output has been spent, only set by verbose gettransaction\n  \"spentby\": \"value\",          (string)           The hash of the transaction spending the wallet output\n },...],                                          \n \"omni\": [n,...],              (array of numeric) The omni engine's decoding of the transaction, if any\n}                              \n",
+		"delegatedtickets":                       "delegatedtickets\n\nReturns the tickets recorded by the wallet whose voting rights are delegated to an address controlled by another wallet.\n\nArguments:\nNone\n\nResult:\n[{\n \"ticket\": \"value\",        (string)  The hash of the ticket purchase transaction\n \"votingaddress\": \"value\", (string)  The address with the voting rights of the ticket\n \"price\": n.nnn,           (numeric) The amount of coins paid for the ticket\n \"status\": \"value\",        (string)  The ticket status (unmined, immature, live, voted, missed, expired, revoked, or unknown)\n \"spentby\": \"value\",       (string)  The hash of the vote or revocation spending the ticket, if any\n},...]\n",
+		"exportaccount":                          "exportaccount \"account\" (private=false)\n\nReturns the BIP0044 extended key of an account, which importaccount uses to recreate the account at the same account number in another wallet.\n\nArguments:\n1. account (string, required)                 The name of the account to export\n2. private (boolean, optional, default=false) Export the extended private key rather than the extended public key (requires an unlocked wallet, and is required for bliss accounts)\n\nResult:\n\"value\" (string) The extended key of the account\n",
+		"getreplicationinfo":                     "getreplicationinfo\n\nReturns whether the wallet is a primary or a read-only replica, and how far a replica lags behind its primary.\n\nArguments:\nNone\n\nResult:\n{\n \"mode\": \"value\",      (string)  Either \"primary\" or \"follower\" for read-only replicas\n \"height\": n,          (numeric) Main chain height of the wallet\n \"primary\": \"value\",   (string)  URL of the primary wallet's RPC server (followers only)\n \"snapshottime\": n,    (numeric) Unix time the latest replicated snapshot was created by the primary\n \"snapshotheight\": n,  (numeric) Main chain height of the primary when the latest replicated snapshot was created\n \"lag\": n,             (numeric) Seconds since the primary committed the earliest change which is not yet replicated (0 for primaries and caught up replicas, -1 when no snapshot has been replicated)\n \"snapshots\": n,       (numeric) Number of snapshots which replaced the replica database\n \"applied\": n,         (numeric) Number of commits of the primary applied to the replica database without a snapshot\n \"lastattempt\": n,     (numeric) Unix time of the latest replication attempt\n \"lasterror\": \"value\", (string)  Error of the latest replication attempt, if it failed\n}                      \n",
+		"promotereplica":                         "promotereplica\n\nStops replicating the primary wallet and serves the replica as a primary wallet which may be modified and is synced with the consensus server.\nThe latest replicated database is kept.\n\nArguments:\nNone\n\nResult:\nNothing\n",
+		"getaddressforinvoice":                   "getaddressforinvoice \"invoiceid\" (account=\"default\")\n\nReturns the address of an account which receives payments for an invoice.\nThe address is derived deterministically from the invoice id under a dedicated account branch, and the mapping is recorded so payments can be looked up with getinvoicepayments.\nRequesting the address of an invoice again returns the same address. Invoice addresses are only supported by ECDSA accounts.\n\nArguments:\n1. invoiceid (string, required)                    The unique id of the invoice\n2. account   (string, optional, default=\"default\") The account the address belongs to\n\nResult:\n\"value\" (string) The payment address of the invoice\n",
+		"getauditlog":                            "getauditlog (from=0 count=100)\n\nReturns a page of the append-only audit log of balance-affecting events.\nEntries are returned in the order they were recorded. Pass the returned 'next' value as 'from' to fetch the following page.\n\nArguments:\n1. from  (numeric, optional, default=0)   The sequence number of the first entry to return\n2. count (numeric, optional, default=100) The maximum number of entries to return\n\nResult:\n{\n \"entries\": [{          (array of object) The audit log entries\n  \"seq\": n,             (numeric)         The sequence number of the entry\n  \"time\": n,            (numeric)         The time the entry was recorded, as a Unix timestamp\n  \"event\": \"value\",     (string)          The event (credit, debit, votereward, revocation, rollback, abandon, or prune)\n  \"txid\": \"value\",      (string)          The hash of the crediting, debiting, or removed transaction, omitted for rollbacks\n  \"amount\": n.nnn,      (numeric)         The total amount credited or debited by the transaction, or the net amount credited by a removed transaction (negative if it debited more)\n  \"blockhash\": \"value\", (string)          The hash of the block the transaction is mined in, omitted if unmined\n  \"blockheight\": n,     (numeric)         The height of the block the transaction is mined in (-1 if unmined), the first removed height of a rollback, or the height the history was pruned at\n  \"trigger\": \"value\",   (string)          The notification or wallet operation which caused the event\n },...],                                  \n \"next\": n,             (numeric)         The sequence number to request the following page with\n}                       \n",
+		"getbalanceathash":                       "getbalanceathash \"blockhash\" (account=\"*\")\n\nReturns the balances of one or all accounts recorded by the latest balance snapshot at or below the height of a main chain block.\nSnapshots are only recorded when the balancesnapshots option is set, as blocks are connected at heights which are multiples of it.\n\nArguments:\n1. blockhash (string, required)              The hash of the main chain block\n2. account   (string, optional, default=\"*\") The account name to query the balance for, or \"*\" to consider all accounts\n\nResult:\n{\n \"height\": n,                           (numeric)         The height of the block the snapshot was recorded at, which is below the requested block when no snapshot was recorded there\n \"blockhash\": \"value\",                  (string)          The hash of the block the snapshot was recorded at\n \"balances\": [{                         (array of object) The mined balances of the account, or of every account holding mined outputs\n  \"accountname\": \"value\",               (string)          The name of the account\n  \"immaturecoinbaserewards\": n.nnn,     (numeric)         Immature coinbase rewards\n  \"immaturestakegeneration\": n.nnn,     (numeric)         Immature stake generation (votes and revocations)\n  \"lockedbytickets\": n.nnn,             (numeric)         The value locked by tickets\n  \"spendable\": n.nnn,                   (numeric)         The spendable balance\n  \"total\": n.nnn,                       (numeric)         The total balance\n  \"unconfirmed\": n.nnn,                 (numeric)         The unconfirmed balance\n  \"votingauthority\": n.nnn,             (numeric)         The value of tickets with voting authority\n  \"fiat\": {                             (object)          The fiat valuation of the total balance, if a currency was requested\n   \"currency\": \"value\",                 (string)          The fiat currency code\n   \"price\": n.nnn,                      (numeric)         The price of a coin\n   \"value\": n.nnn,                      (numeric)         The fiat value of the amount\n  },                                                      \n },...],                                                  \n \"totalimmaturecoinbaserewards\": n.nnn, (numeric)         The total immature coinbase rewards of the accounts\n \"totalimmaturestakegeneration\": n.nnn, (numeric)         The total immature stake generation of the accounts\n \"totallockedbytickets\": n.nnn,         (numeric)         The total value locked by tickets of the accounts\n \"totalspendable\": n.nnn,               (numeric)         The total spendable balance of the accounts\n \"cumulativetotal\": n.nnn,              (numeric)         The total balance of the accounts\n \"totalvotingauthority\": n.nnn,         (numeric)         The total value of tickets with voting authority of the accounts\n}                                       \n",
+		"getbalanceatheight":                     "getbalanceatheight height (account=\"*\")\n\nReturns the balances of one or all accounts recorded by the latest balance snapshot at or below a main chain height.\nSnapshots are only recorded when the balancesnapshots option is set, as blocks are connected at heights which are multiples of it.\n\nArguments:\n1. height  (numeric, required)             The main chain height\n2. account (string, optional, default=\"*\") The account name to query the balance for, or \"*\" to consider all accounts\n\nResult:\n{\n \"height\": n,                           (numeric)         The height of the block the snapshot was recorded at, which is below the requested block when no snapshot was recorded there\n \"blockhash\": \"value\",                  (string)          The hash of the block the snapshot was recorded at\n \"balances\": [{                         (array of object) The mined balances of the account, or of every account holding mined outputs\n  \"accountname\": \"value\",               (string)          The name of the account\n  \"immaturecoinbaserewards\": n.nnn,     (numeric)         Immature coinbase rewards\n  \"immaturestakegeneration\": n.nnn,     (numeric)         Immature stake generation (votes and revocations)\n  \"lockedbytickets\": n.nnn,             (numeric)         The value locked by tickets\n  \"spendable\": n.nnn,                   (numeric)         The spendable balance\n  \"total\": n.nnn,                       (numeric)         The total balance\n  \"unconfirmed\": n.nnn,                 (numeric)         The unconfirmed balance\n  \"votingauthority\": n.nnn,             (numeric)         The value of tickets with voting authority\n  \"fiat\": {                             (object)          The fiat valuation of the total balance, if a currency was requested\n   \"currency\": \"value\",                 (string)          The fiat currency code\n   \"price\": n.nnn,                      (numeric)         The price of a coin\n   \"value\": n.nnn,                      (numeric)         The fiat value of the amount\n  },                                                      \n },...],                                                  \n \"totalimmaturecoinbaserewards\": n.nnn, (numeric)         The total immature coinbase rewards of the accounts\n \"totalimmaturestakegeneration\": n.nnn, (numeric)         The total immature stake generation of the accounts\n \"totallockedbytickets\": n.nnn,         (numeric)         The total value locked by tickets of the accounts\n \"totalspendable\": n.nnn,               (numeric)         The total spendable balance of the accounts\n \"cumulativetotal\": n.nnn,              (numeric)         The total balance of the accounts\n \"totalvotingauthority\": n.nnn,         (numeric)         The total value of tickets with voting authority of the accounts\n}                                       \n",
+		"getinvoicepayments":                     "getinvoicepayments \"invoiceid\" (minconf=1)\n\nReturns the payments received by the address derived for an invoice by getaddressforinvoice.\n\nArguments:\n1. invoiceid (string, required)             The unique id of the invoice\n2. minconf   (numeric, optional, default=1) Minimum number of confirmations of payments included in the received amount\n\nResult:\n{\n \"invoiceid\": \"value\",  (string)          The unique id of the invoice\n \"address\": \"value\",    (string)          The payment address of the invoice\n \"account\": \"value\",    (string)          The account the address belongs to\n \"created\": n,          (numeric)         The Unix time the address was derived\n \"received\": n.nnn,     (numeric)         The total amount of payments with at least minconf confirmations\n \"payments\": [{         (array of object) Every transaction paying the address, including those with fewer than minconf confirmations\n  \"txid\": \"value\",      (string)          The hash of the transaction\n  \"amount\": n.nnn,      (numeric)         The amount paid to the invoice address by the transaction\n  \"blockhash\": \"value\", (string)          The hash of the block the transaction is mined in, or empty if unmined\n  \"blockheight\": n,     (numeric)         The height of the block the transaction is mined in, or -1 if unmined\n  \"confirmations\": n,   (numeric)         The number of confirmations of the transaction\n },...],                                  \n}                       \n",
+		"gethealth":                              "gethealth (maxblocksbehind=6)\n\nReports the health of the wallet and the services it depends on.\nEach check and the overall result have a status code: 0 (ok), 1 (degraded, the wallet continues to serve most requests), or 2 (unavailable, the wallet should be restarted or alerted on).\nThe overall status is the most severe status of all checks.\n\nArguments:\n1. maxblocksbehind (numeric, optional, default=6) Maximum number of blocks the wallet may be behind hcd while reporting ready\n\nResult:\n{\n \"status\": \"value\",            (string)          The overall status (ok, degraded, or unavailable)\n \"code\": n,                    (numeric)         The overall status code\n \"ready\": true|false,          (boolean)         Whether the wallet is loaded, not unavailable, and synced to hcd\n \"walletloaded\": true|false,   (boolean)         Whether a wallet is loaded\n \"chainconnected\": true|false, (boolean)         Whether hcd answered a request for its best block\n \"walletheight\": n,            (numeric)         The height of the wallet's main chain tip\n \"chainheight\": n,             (numeric)         The height of hcd's best block\n \"blocksbehind\": n,            (numeric)         The number of blocks the wallet is behind hcd, or -1 if unknown\n \"dbreadable\": true|false,     (boolean)         Whether the wallet database could be read\n \"unlocked\": true|false,       (boolean)         Whether the wallet is unlocked (omitted by the unauthenticated /health/ready endpoint)\n \"omniresponsive\": true|false, (boolean)         Whether the omni engine answered a request (false if omni is disabled)\n \"checks\": [{                  (array of object) The results of the individual checks\n  \"name\": \"value\",             (string)          The checked component (wallet, chain, sync, database, unlocked, or omni)\n  \"status\": \"value\",           (string)          The status of the component (ok, degraded, or unavailable)\n  \"code\": n,                   (numeric)         The status code of the component\n  \"detail\": \"value\",           (string)          A description of the problem, if any\n },...],                                         \n}                              \n",
+		"getrecoverystate":                       "getrecoverystate\n\nReturns how many automatic rescans were started to recover from errors processing consensus server notifications, and the most recent errors.\n\nArguments:\nNone\n\nResult:\n{\n \"autorescans\": n,         (numeric)         Number of automatic rescans started since the wallet was loaded\n \"errors\": n,              (numeric)         Number of errors processing notifications since the wallet was loaded\n \"backoff\": n,             (numeric)         Minimum number of seconds between automatic rescans, doubling after each rescan\n \"pending\": true|false,    (boolean)         Whether a rescan is scheduled to start once the backoff expires\n \"halted\": true|false,     (boolean)         Whether automatic rescans are disabled after an error describing inconsistent wallet data or invalid chain data from the consensus server\n \"events\": [{              (array of object) The most recent notification errors, oldest first\n  \"time\": n,               (numeric)         Unix time of the error\n  \"notification\": \"value\", (string)          The notification being processed\n  \"error\": \"value\",        (string)          The error\n  \"transient\": true|false, (boolean)         Whether the error may be resolved by rescanning\n  \"action\": \"value\",       (string)          The response of the wallet (\"rescan\", \"deferred\", \"skipped\", or \"halted\")\n },...],                                     \n}                          \n",
+		"getrescaninfo":                          "getrescaninfo\n\nReturns whether a rescan is running and how it is being throttled by the configured rescan limits.\n\nArguments:\nNone\n\nResult:\n{\n \"scanning\": true|false,        (boolean) Whether a rescan is in progress\n \"blockspersecond\": n,          (numeric) Maximum average number of blocks rescanned per second (0 for no limit)\n \"pauserpcload\": n,             (numeric) Number of RPC requests in progress which pauses a rescan (0 to never pause)\n \"rpcload\": n,                  (numeric) Number of RPC requests currently being handled, including this one\n \"ratelimited\": true|false,     (boolean) Whether the rescan is waiting to stay within the blocks per second limit\n \"paused\": true|false,          (boolean) Whether the rescan is paused until the RPC load drops\n \"blocksperbatch\": n,           (numeric) Configured number of blocks requested in each batch, or in the first batch of adaptive rescans (0 for the default of 2000)\n \"adaptivebatches\": true|false, (boolean) Whether batches are resized from the time taken and transactions discovered in the previous batch\n \"batchsize\": n,                (numeric) Number of blocks requested in the current batch (0 when not scanning)\n}                               \n",
+		"getspendableconfs":                      "getspendableconfs (account=\"default\")\n\nReturns the confirmations an account requires before its outputs are considered spendable by balances and input selection.\n\nArguments:\n1. account (string, optional, default=\"default\") The account to query\n\nResult:\n{\n \"account\": \"value\",    (string)  The account\n \"regular\": n,          (numeric) The confirmations required of every output (0 for only the requested minimum)\n \"coinbase\": n,         (numeric) The confirmations required of coinbase, vote and revocation outputs (0 for only the chain maturity)\n \"coinbasematurity\": n, (numeric) The confirmations coinbase, vote and revocation outputs of the account must reach, including the chain maturity\n}                       \n",
+		"getstakeinfo":                           "getstakeinfo (\"account\")\n\nReturns statistics about staking from the wallet.\nWhen an account is provided, the ticket statistics only include tickets funded by the account.\nTickets purchased before funding accounts were recorded are only included when they were mined and spent outputs recording their account.\n\nArguments:\n1. account (string, optional) Only include tickets funded by this account\n\nResult:\n{\n \"blockheight\": n,                  (numeric) Current block height for stake info.\n \"poolsize\": n,                     (numeric) Number of live tickets in the ticket pool.\n \"difficulty\": n.nnn,               (numeric) Current stake difficulty.\n \"allmempooltix\": n,                (numeric) Number of tickets currently in the mempool\n \"ownmempooltix\": n,                (numeric) Number of tickets submitted by this wallet currently in mempool\n \"immature\": n,                     (numeric) Number of tickets from this wallet that are in the blockchain but which are not yet mature\n \"live\": n,                         (numeric) Number of mature, active tickets owned by this wallet\n \"proportionlive\": n.nnn,           (numeric) (Live / PoolSize)\n \"voted\": n,                        (numeric) Number of votes cast by this wallet\n \"totalsubsidy\": n.nnn,             (numeric) Total amount of coins earned by stake mining\n \"missed\": n,                       (numeric) Number of missed tickets (failure to vote, not including expired)\n \"proportionmissed\": n.nnn,         (numeric) (Missed / (Missed + Voted))\n \"revoked\": n,                      (numeric) Number of missed tickets that were missed and then revoked\n \"expired\": n,                      (numeric) Number of tickets that have expired\n \"networkstakeversion\": n,          (numeric) Highest stake version of the blocks connected since the wallet was started\n \"voteversionoutdated\": true|false, (boolean) Whether the stake version of the majority of recent blocks is newer than the vote version of the wallet\n}                                   \n",
+		"getstakerewards":                        "getstakerewards (period=\"month\" since until)\n\nReturns the votes, missed tickets, revocations and stake subsidy of wallet tickets, grouped by period.\nPeriods begin at midnight UTC and weeks begin on Monday.  Periods without any votes or revocations are omitted.\n\nArguments:\n1. period (string, optional, default=\"month\") The length of each period (day, week, or month)\n2. since  (numeric, optional)                 Only include rewards mined at or after this Unix time\n3. until  (numeric, optional)                 Only include rewards mined before this Unix time\n\nResult:\n[{\n \"start\": n,            (numeric) Unix time of the beginning of the period\n \"voted\": n,            (numeric) Number of votes cast by wallet tickets\n \"missed\": n,           (numeric) Number of revoked tickets which were missed rather than expired\n \"revoked\": n,          (numeric) Number of wallet tickets revoked\n \"totalsubsidy\": n.nnn, (numeric) Total amount of coins earned by votes\n},...]\n",
+		"getticketfee":                           "getticketfee\n\nGet the current fee per kB of the serialized tx size used for an authored stake transaction.\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) The current fee\n",
+		"setloglevel":                            "setloglevel \"levelspec\"\n\nChanges the logging level of all or individual subsystems without restarting the wallet.\n\nArguments:\n1. levelspec (string, required) The level for all subsystems, e.g. 'debug', or comma-separated subsystem=level pairs, e.g. 'CHNS=debug,WLLT=trace'.\nLevels are {trace, debug, info, warn, error, critical, off}\n\nResult:\n{\n \"The subsystem\": The logging level of the subsystem, (object) JSON object with subsystems as keys and their logging levels as values\n ...\n}\n",
+		"setlogrotation":                         "setlogrotation maxsize maxrolls\n\nChanges the log file rotation.  The current log file is kept and rolled once it reaches the new size.\n\nArguments:\n1. maxsize  (numeric, required) The size in kilobytes after which the log file is rolled\n2. maxrolls (numeric, required) The maximum number of rolled log files to keep\n\nResult:\nNothing\n",
+		"setspendableconfs":                      "setspendableconfs \"account\" regular (coinbase=0)\n\nSets the confirmations an account requires before its outputs are considered spendable by balances and input selection.\nThese only add to the requested minimum confirmations and the maturities of the chain. Setting both to 0 removes the requirements.\n\nArguments:\n1. account  (string, required)             The account to configure\n2. regular  (numeric, required)            The confirmations required of every output\n3. coinbase (numeric, optional, default=0) The confirmations required of coinbase, vote and revocation outputs\n\nResult:\nNothing\n",
+		"setticketfee":                           "setticketfee fee (\"account\")\n\nModify the fee per kB of the serialized tx size used each time more fee is required for an authored stake transaction.\n\nArguments:\n1. fee     (numeric, required) The new fee per kB of the serialized tx size valued in HC\n2. account (string, optional)  Set the fee for tickets purchased from this account only rather than the wallet's default fee\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
+		"getwalletfee":                           "getwalletfee\n\nGet currently set transaction fee for the wallet\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) Current tx fee (in HC)\n",
+		"importaccount":                          "importaccount \"account\" \"key\" (rescan=true scanfrom)\n\nRecreates an account from an extended key returned by exportaccount. The key's account number must be the next account number of the wallet. Extended private keys require an unlocked wallet, while extended public keys may only be imported by watching-only wallets.\n\nArguments:\n1. account  (string, required)                The name of the new account\n2. key      (string, required)                The extended private or public key of the account\n3. rescan   (boolean, optional, default=true) Rescan the blockchain (since the genesis block, or scanfrom block) for outputs controlled by the account\n4. scanfrom (numeric, optional)               Block number for where to start rescan from\n\nResult:\nn (numeric) The account number of the new account\n",
+		"createexternalbranch":                   "createexternalbranch \"account\" \"branch\"\n\nCreates an additional external branch of an account, used to segregate the addresses given out for a purpose (such as donations or invoices) from the account's other receiving addresses.\nAddresses of the branch are returned by getnewaddress with the branch name. Branches are only supported by ECDSA accounts and are not discovered when restoring a wallet from its seed.\n\nArguments:\n1. account (string, required) The name of the account\n2. branch  (string, required) The name of the new branch, unique to the account\n\nResult:\nn (numeric) The BIP0044 branch number of the new branch\n",
+		"listexternalbranches":                   "listexternalbranches (account=\"default\")\n\nLists the additional external branches of an account created by createexternalbranch.\n\nArguments:\n1. account (string, optional, default=\"default\") The name of the account\n\nResult:\n[{\n \"branch\": n,       (numeric) The BIP0044 branch number\n \"name\": \"value\",   (string)  The name of the branch\n \"lastused\": n,     (numeric) The index of the last used address of the branch, or -1 if none are used\n \"lastreturned\": n, (numeric) The index of the last address of the branch returned by the wallet, or -1 if none were returned\n},...]\n",
+		"createmultisigaccount":                  "createmultisigaccount \"name\" nrequired [\"xpub\",...] (account=\"default\")\n\nCreates an m-of-n multisig account shared with cosigners identified by their extended public keys.\nThe wallet's keys of the account are derived from a new external branch of an account, and the returned extended public key of the branch must be recorded by each cosigner.\nEach address index pays to a P2SH multisig script of the child keys at that index of every extended public key, sorted as described by BIP0067.\n\nArguments:\n1. name      (string, required)                    The name of the multisig account\n2. nrequired (numeric, required)                   The number of signatures required to spend outputs of the account\n3. cosigners (array of string, required)           The extended public keys of the cosigners, excluding the wallet's key\n4. account   (string, optional, default=\"default\") The account from which the wallet's keys are derived\n\nResult:\n{\n \"name\": \"value\", (string)  The name of the multisig account\n \"xpub\": \"value\", (string)  The extended public key of the wallet which the cosigners must record\n \"nrequired\": n,  (numeric) The number of signatures required to spend outputs of the account\n \"nkeys\": n,      (numeric) The total number of keys, including the wallet's key\n}                 \n",
+		"getnewmultisigaddress":                  "getnewmultisigaddress \"name\"\n\nDerives the next P2SH address of a multisig account created by createmultisigaccount. The wallet must be unlocked to record the redeem script.\n\nArguments:\n1. name (string, required) The name of the multisig account\n\nResult:\n\"value\" (string) The P2SH address\n",
+		"getmultisigaccountinfo":                 "getmultisigaccountinfo \"name\"\n\nReturns the keys, derived addresses, and balance of a multisig account created by createmultisigaccount.\n\nArguments:\n1. name (string, required) The name of the multisig account\n\nResult:\n{\n \"name\": \"value\",            (string)          The name of the multisig account\n \"account\": \"value\",         (string)          The account from which the wallet's keys are derived\n \"nrequired\": n,             (numeric)         The number of signatures required to spend outputs of the account\n \"nkeys\": n,                 (numeric)         The total number of keys, including the wallet's key\n \"xpub\": \"value\",            (string)          The extended public key of the wallet which the cosigners must record\n \"cosigners\": [\"value\",...], (array of string) The extended public keys of the cosigners\n \"created\": n,               (numeric)         The Unix time the account was created\n \"balance\": n.nnn,           (numeric)         The total amount of unspent outputs of the account\n \"addresses\": [{             (array of object) The derived addresses of the account\n  \"index\": n,                (numeric)         The address index\n  \"address\": \"value\",        (string)          The P2SH address\n  \"redeemscript\": \"value\",   (string)          The hex-encoded redeem script\n  \"balance\": n.nnn,          (numeric)         The total amount of unspent outputs paying the address\n },...],                                       \n}                            \n",
+		"createmultisigspend":                    "createmultisigspend \"name\" {\"address\":amount,...}\n\nCreates a transaction spending unspent outputs of a multisig account and signs it with the wallet's keys. Change is paid to a new address of the account.\nTransactions which are not complete must be signed by further cosigners with signrawtransaction before they are published.\n\nArguments:\n1. name    (string, required) The name of the multisig account\n2. amounts (object, required) Pairs of payment addresses and the output amount to pay each\n{\n \"Address to pay\": Amount to send to the payment address valued in HC, (object) JSON object using payment addresses as keys and output amounts valued in HC to send to each address\n ...\n}\n\nResult:\n{\n \"hex\": \"value\",         (string)  The hex-encoded transaction\n \"fee\": n.nnn,           (numeric) The fee paid by the transaction\n \"complete\": true|false, (boolean) Whether the transaction holds the required signatures of every input\n}                        \n",
+		"addticket":                              "addticket \"tickethex\"\n\nAdd a ticket to the wallet for vote and revocation creation.  Added tickets are auxiliary to transaction history and do not appear in getstakeinfo stats.\n\nArguments:\n1. tickethex (string, required) Hex-encoded serialized transaction\n\nResult:\nNothing\n",
+		"listscripts":                            "listscripts\n\nList all scripts that have been added to wallet\n\nArguments:\nNone\n\nResult:\n{\n \"scripts\": [{             (array of object) A list of the imported scripts\n  \"hash160\": \"value\",      (string)          The script hash\n  \"address\": \"value\",      (string)          The script address\n  \"redeemscript\": \"value\", (string)          The redeem script\n },...],                                     \n}                          \n",
+		"listrefundablescripts":                  "listrefundablescripts\n\nLists the imported scripts with an OP_CHECKLOCKTIMEVERIFY or OP_CHECKSEQUENCEVERIFY refund path paying a wallet key, such as atomic swap contracts, and their unspent outputs.\n\nArguments:\nNone\n\nResult:\n[{\n \"address\": \"value\",       (string)          The P2SH address of the script\n \"redeemscript\": \"value\",  (string)          The hex-encoded redeem script\n \"refundaddress\": \"value\", (string)          The wallet address paid by the refund path\n \"locktime\": n,            (numeric)         The lock time of the refund path, as a block height or Unix time for absolute lock times, or an input sequence number for relative lock times\n \"relative\": true|false,   (boolean)         Whether the lock time is relative to the block of each output (OP_CHECKSEQUENCEVERIFY)\n \"outputs\": [{             (array of object) The unspent outputs paying the script\n  \"txid\": \"value\",         (string)          The hash of the transaction\n  \"vout\": n,               (numeric)         The output index\n  \"tree\": n,               (numeric)         The transaction tree\n  \"amount\": n.nnn,         (numeric)         The output amount\n  \"blockheight\": n,        (numeric)         The height of the block mining the output, or -1 if unmined\n  \"matured\": true|false,   (boolean)         Whether the output may be spent by the refund path in the next block\n },...],                                     \n},...]\n",
+		"refundscript":                           "refundscript \"address\" (\"toaddress\")\n\nSpends every matured unspent output of a refundable script listed by listrefundablescripts by its refund path, paying the total less the relay fee, and publishes the transaction.\nThe wallet must be unlocked to sign the refund.\n\nArguments:\n1. address   (string, required) The P2SH address of the script\n2. toaddress (string, optional) The address to pay (default: a new internal address of the account of the refund key)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"initiateswap":                           "initiateswap \"address\" amount (account=\"default\" locktime)\n\nGenerates a secret and publishes a transaction funding an atomic swap contract which pays the participant when redeemed with the secret, or may be refunded to the wallet after the lock time.\nThe contract and contract transaction must be given to the participant to audit. The secret is recorded by the wallet and listed by listswaps.\n\nArguments:\n1. address  (string, required)                    The participant's secp256k1 pubkey hash address\n2. amount   (numeric, required)                   The amount paid to the contract\n3. account  (string, optional, default=\"default\") The account funding the contract and receiving refunds\n4. locktime (numeric, optional)                   The lock time of the refund path, as a block height or Unix time (default: 48 hours from now)\n\nResult:\n{\n \"contract\": \"value\",     (string)  The hex-encoded contract script\n \"contractp2sh\": \"value\", (string)  The P2SH address of the contract\n \"contracttx\": \"value\",   (string)  The hex-encoded transaction funding the contract\n \"contracttxid\": \"value\", (string)  The hash of the transaction funding the contract\n \"vout\": n,               (numeric) The index of the contract output\n \"secrethash\": \"value\",   (string)  The hex-encoded RIPEMD160 secret hash\n \"secret\": \"value\",       (string)  The hex-encoded secret (initiateswap only)\n \"locktime\": n,           (numeric) The lock time of the refund path\n}                         \n",
+		"participateswap":                        "participateswap \"address\" amount \"secrethash\" (account=\"default\" locktime)\n\nPublishes a transaction funding an atomic swap contract which pays the initiator when redeemed with the preimage of the initiator's secret hash, or may be refunded to the wallet after the lock time.\nThe secret revealed by the initiator's redemption of this contract is recorded by the wallet and listed by listswaps.\n\nArguments:\n1. address    (string, required)                    The initiator's secp256k1 pubkey hash address\n2. amount     (numeric, required)                   The amount paid to the contract\n3. secrethash (string, required)                    The hex-encoded RIPEMD160 secret hash of the initiator's contract\n4. account    (string, optional, default=\"default\") The account funding the contract and receiving refunds\n5. locktime   (numeric, optional)                   The lock time of the refund path, as a block height or Unix time (default: 24 hours from now)\n\nResult:\n{\n \"contract\": \"value\",     (string)  The hex-encoded contract script\n \"contractp2sh\": \"value\", (string)  The P2SH address of the contract\n \"contracttx\": \"value\",   (string)  The hex-encoded transaction funding the contract\n \"contracttxid\": \"value\", (string)  The hash of the transaction funding the contract\n \"vout\": n,               (numeric) The index of the contract output\n \"secrethash\": \"value\",   (string)  The hex-encoded RIPEMD160 secret hash\n \"secret\": \"value\",       (string)  The hex-encoded secret (initiateswap only)\n \"locktime\": n,           (numeric) The lock time of the refund path\n}                         \n",
+		"redeemswap":                             "redeemswap \"contract\" \"contracttx\" \"secret\" (\"toaddress\")\n\nPublishes a transaction redeeming the outputs of a counterparty's atomic swap contract paying a wallet key using the secret.\n\nArguments:\n1. contract   (string, required) The hex-encoded contract script\n2. contracttx (string, required) The hex-encoded transaction funding the contract\n3. secret     (string, required) The hex-encoded secret\n4. toaddress  (string, optional) The address to pay (default: a new internal address of the account of the recipient key)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"refundswap":                             "refundswap \"contract\" \"contracttx\" (\"toaddress\")\n\nPublishes a transaction refunding the outputs of an atomic swap contract to a wallet key after the contract's lock time.\n\nArguments:\n1. contract   (string, required) The hex-encoded contract script\n2. contracttx (string, required) The hex-encoded transaction funding the contract\n3. toaddress  (string, optional) The address to pay (default: a new internal address of the account of the refund key)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"listswaps":                              "listswaps\n\nLists the atomic swap contracts funded by the wallet with initiateswap and participateswap, and the secret of each swap when known.\n\nArguments:\nNone\n\nResult:\n[{\n \"contractp2sh\": \"value\", (string)  The P2SH address of the contract\n \"contract\": \"value\",     (string)  The hex-encoded contract script\n \"contracttxid\": \"value\", (string)  The hash of the transaction funding the contract\n \"vout\": n,               (numeric) The index of the contract output\n \"initiator\": true|false, (boolean) Whether the wallet initiated the swap\n \"secrethash\": \"value\",   (string)  The hex-encoded RIPEMD160 secret hash\n \"secret\": \"value\",       (string)  The hex-encoded secret, if known (secrets of initiated swaps which are not yet redeemed require the wallet to be unlocked)\n \"locktime\": n,           (numeric) The lock time of the refund path\n \"created\": n,            (numeric) The Unix time the contract was funded\n},...]\n",
+		"stakepooluserinfo":                      "stakepooluserinfo \"user\"\n\nGet user info for stakepool\n\nArguments:\n1. user (string, required) The id of the user to be looked up\n\nResult:\n{\n \"tickets\": [{             (array of object) A list of valid tickets that the user has added\n  \"status\": \"value\",       (string)          The current status of the added ticket\n  \"ticket\": \"value\",       (string)          The hash of the added ticket\n  \"ticketheight\": n,       (numeric)         The height in which the ticket was added\n  \"spentby\": \"value\",      (string)          The vote in which the ticket was spent\n  \"spentbyheight\": n,      (numeric)         The height in which the ticket was spent\n },...],                                     \n \"invalid\": [\"value\",...], (array of string) A list of invalid tickets that the user has added\n \"invaliddetails\": [{      (array of object) Why each invalid ticket was rejected, in the same order as invalid\n  \"ticket\": \"value\",       (string)          The hash of the rejected ticket\n  \"reason\": \"value\",       (string)          Why the ticket was rejected (\"feetoolow\", \"unknowncommitmentaddress\", \"parsefailure\", or \"unknown\" for tickets rejected before reasons were recorded)\n  \"ticketheight\": n,       (numeric)         The height of the block which mined the rejected ticket\n  \"found\": n.nnn,          (numeric)         The pool fee committed by the ticket, for tickets with fees too low\n  \"required\": n.nnn,       (numeric)         The pool fee required of the ticket, for tickets with fees too low\n  \"detail\": \"value\",       (string)          The commitment address of tickets with unknown commitment addresses, or the error of tickets which failed to parse\n },...],                                     \n}                          \n",
+		"listpoolfeeexemptions":                  "listpoolfeeexemptions\n\nLists the stake pool users exempt from the pool fee check.\n\nArguments:\nNone\n\nResult:\n[\"value\",...] (array of string) The voting addresses of the exempt users\n",
+		"reevaluatepooltickets":                  "reevaluatepooltickets \"user\"\n\nEvaluates the rejected tickets of a stake pool user again, readmitting tickets which are now acceptable, such as after the user was exempted from the pool fee check.\nThe rejection reasons of the remaining tickets are updated.\n\nArguments:\n1. user (string, required) The voting address identifying the stake pool user\n\nResult:\n[\"value\",...] (array of string) The hashes of the readmitted tickets\n",
+		"setpoolfeeexemption":                    "setpoolfeeexemption \"user\" (exempt=true)\n\nExempts a stake pool user from the pool fee check, or removes the exemption.\nTickets of exempt users which commit less than the required pool fee are accepted. Use reevaluatepooltickets to readmit previously rejected tickets.\n\nArguments:\n1. user   (string, required)                The voting address identifying the stake pool user\n2. exempt (boolean, optional, default=true) Whether the user is exempt from the pool fee check\n\nResult:\nNothing\n",
+		"setaccountdefaultaddress":               "setaccountdefaultaddress \"account\" (\"address\")\n\nSets the default address of an account, which is used as the change and sending address of transactions from the account which do not specify one.\n\nArguments:\n1. account (string, required) The account to configure\n2. address (string, optional) An address of the account, or omitted to use the first address of the account's external branch\n\nResult:\nNothing\n",
+		"syncaccountaddresses":                   "syncaccountaddresses\n\nExtends the addresses watched for transactions to the gap limit and lookahead past the last used address of every account branch,\nand returns the used, returned, and watched child indexes of each account. Addresses are watched ahead when usage approaches the last watched address.\n\nArguments:\nNone\n\nResult:\n[{\n \"account\": \"value\", (string)  The name of the account\n \"accountnumber\": n, (numeric) The number of the account\n \"external\": {       (object)  The child indexes of the external branch\n  \"lastused\": n,     (numeric) The index of the last used address, or -1 if none are used\n  \"lastreturned\": n, (numeric) The index of the last address returned by the wallet, or -1 if none were returned\n  \"lastwatched\": n,  (numeric) The index of the last address watched for transactions, or -1 if none are watched\n },                            \n \"internal\": {       (object)  The child indexes of the internal (change) branch\n  \"lastused\": n,     (numeric) The index of the last used address, or -1 if none are used\n  \"lastreturned\": n, (numeric) The index of the last address returned by the wallet, or -1 if none were returned\n  \"lastwatched\": n,  (numeric) The index of the last address watched for transactions, or -1 if none are watched\n },                            \n},...]\n",
+		"ticketsforaddress":                      "ticketsforaddress \"address\"\n\nRequest all the tickets for an address.\n\nArguments:\n1. address (string, required) Address to look for.\n\nResult:\ntrue|false (boolean) Tickets owned by the specified address.\n",
+		"triggerconsolidation":                   "triggerconsolidation (account=\"default\" force=false)\n\nConsolidates the dust outputs of an account, valued below the configured dustthreshold, into a new internal address of the account.\nUnless forced, dust is only consolidated when the account holds more than dustconsolidatecount dust outputs and the median mempool fee rate does not exceed dustconsolidatemaxfeerate.\nRequires the wallet to be unlocked.\n\nArguments:\n1. account (string, optional, default=\"default\") Account whose dust outputs are consolidated\n2. force   (boolean, optional, default=false)    Consolidate every dust output regardless of their number and the mempool fee rate\n\nResult:\n[\"value\",...] (array of string) Hashes of the consolidation transactions, empty if the account does not qualify for consolidation\n",
+		"omni_send":                              "omni_send \"fromaddress\" \"toaddress\" propertyid \"amount\" (\"redeemaddress\" \"referenceamount\")\n\nCreates and publishes a simple send transaction of omni tokens.\n\nArguments:\n1. fromaddress     (string, required)  The address to send from\n2. toaddress       (string, required)  The address of the recipient\n3. propertyid      (numeric, required) The identifier of the tokens to send\n4. amount          (string, required)  The amount of tokens to send\n5. redeemaddress   (string, optional)  An address that can spend the transaction dust (default: the sending address)\n6. referenceamount (string, optional)  The value of the reference output paying the recipient valued in HC (default: the dust threshold)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_senddexsell":                       "omni_senddexsell \"fromaddress\" propertyidforsale \"amountforsale\" \"amountdesired\" paymentwindow \"minacceptfee\" action\n\nPlaces, updates or cancels a sell offer on the distributed OMNI/HC exchange.\n\nArguments:\n1. fromaddress       (string, required)  The address to send from\n2. propertyidforsale (numeric, required) The identifier of the tokens to list for sale (1 for OMNI, 2 for TOMNI)\n3. amountforsale     (string, required)  The amount of tokens to list for sale\n4. amountdesired     (string, required)  The amount desired valued in HC\n5. paymentwindow     (numeric, required) The number of blocks a buyer has to pay after accepting the offer\n6. minacceptfee      (string, required)  The minimum fee a buyer has to pay to accept the offer\n7. action            (numeric, required) The action to take (1 for new offers, 2 to update, 3 to cancel)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_senddexaccept":                     "omni_senddexaccept \"fromaddress\" \"toaddress\" propertyid \"amount\" (override)\n\nAccepts an offer on the distributed OMNI/HC exchange.\n\nArguments:\n1. fromaddress (string, required)  The address to send from\n2. toaddress   (string, required)  The address of the seller\n3. propertyid  (numeric, required) The identifier of the tokens to purchase\n4. amount      (string, required)  The amount of tokens to accept\n5. override    (boolean, optional) Override the minimum accept fee and payment window checks\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendissuancecrowdsale":             "omni_sendissuancecrowdsale \"fromaddress\" ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" propertyiddesired \"tokensperunit\" deadline earlybonus issuerpercentage\n\nCreates new tokens issued by a crowdsale.\n\nArguments:\n1.  fromaddress       (string, required)  The address to send from\n2.  ecosystem         (numeric, required) The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)\n3.  typo              (numeric, required) The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)\n4.  previousid        (numeric, required) The identifier of a predecessor token (0 for new crowdsales)\n5.  category          (string, required)  The category of the new tokens (can be empty)\n6.  subcategory       (string, required)  The subcategory of the new tokens (can be empty)\n7.  name              (string, required)  The name of the new tokens\n8.  url               (string, required)  A URL with further information about the new tokens (can be empty)\n9.  data              (string, required)  A description of the new tokens (can be empty)\n10. propertyiddesired (numeric, required) The identifier of the tokens eligible to participate in the crowdsale\n11. tokensperunit     (string, required)  The amount of tokens granted per unit invested in the crowdsale\n12. deadline          (numeric, required) The Unix time of the crowdsale deadline\n13. earlybonus        (numeric, required) The early bird bonus for participants in percent per week\n14. issuerpercentage  (numeric, required) The percentage of tokens granted to the issuer\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendissuancefixed":                 "omni_sendissuancefixed \"fromaddress\" ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" \"amount\"\n\nCreates new tokens with a fixed supply.\n\nArguments:\n1.  fromaddress (string, required)  The address to send from\n2.  ecosystem   (numeric, required) The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)\n3.  typo        (numeric, required) The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)\n4.  previousid  (numeric, required) The identifier of a predecessor token (0 for new tokens)\n5.  category    (string, required)  The category of the new tokens (can be empty)\n6.  subcategory (string, required)  The subcategory of the new tokens (can be empty)\n7.  name        (string, required)  The name of the new tokens\n8.  url         (string, required)  A URL with further information about the new tokens (can be empty)\n9.  data        (string, required)  A description of the new tokens (can be empty)\n10. amount      (string, required)  The number of tokens to create\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendissuancemanaged":               "omni_sendissuancemanaged \"fromaddress\" ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\"\n\nCreates new tokens with a supply managed by the issuer.\n\nArguments:\n1. fromaddress (string, required)  The address to send from\n2. ecosystem   (numeric, required) The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)\n3. typo        (numeric, required) The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)\n4. previousid  (numeric, required) The identifier of a predecessor token (0 for new tokens)\n5. category    (string, required)  The category of the new tokens (can be empty)\n6. subcategory (string, required)  The subcategory of the new tokens (can be empty)\n7. name        (string, required)  The name of the new tokens\n8. url         (string, required)  A URL with further information about the new tokens (can be empty)\n9. data        (string, required)  A description of the new tokens (can be empty)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendsto":                           "omni_sendsto \"fromaddress\" propertyid \"amount\" (\"redeemaddress\" distributionproperty)\n\nDistributes tokens to the holders of a property in proportion to their holdings.\n\nArguments:\n1. fromaddress          (string, required)  The address to send from\n2. propertyid           (numeric, required) The identifier of the tokens to distribute\n3. amount               (string, required)  The amount of tokens to distribute\n4. redeemaddress        (string, optional)  An address that can spend the transaction dust (default: the sending address)\n5. distributionproperty (numeric, optional) The identifier of the property whose holders receive the tokens (default: the distributed property)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendgrant":                         "omni_sendgrant \"fromaddress\" \"toaddress\" propertyid \"amount\" (\"memo\")\n\nIssues new units of managed tokens.\n\nArguments:\n1. fromaddress (string, required)  The address to send from\n2. toaddress   (string, required)  The address receiving the tokens (empty for the sending address)\n3. propertyid  (numeric, required) The identifier of the tokens to grant\n4. amount      (string, required)  The amount of tokens to create\n5. memo        (string, optional)  A note attached to the transaction\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendrevoke":                        "omni_sendrevoke \"fromaddress\" propertyid \"amount\" (\"memo\")\n\nRevokes units of managed tokens.\n\nArguments:\n1. fromaddress (string, required)  The address to send from\n2. propertyid  (numeric, required) The identifier of the tokens to revoke\n3. amount      (string, required)  The amount of tokens to revoke\n4. memo        (string, optional)  A note attached to the transaction\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendclosecrowdsale":                "omni_sendclosecrowdsale \"fromaddress\" propertyid\n\nCloses a crowdsale before its deadline.\n\nArguments:\n1. fromaddress (string, required)  The address of the crowdsale issuer\n2. propertyid  (numeric, required) The identifier of the crowdsale to close\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendtrade":                         "omni_sendtrade \"fromaddress\" propertyidforsale \"amountforsale\" propertiddesired \"amountdesired\"\n\nPlaces a trade offer on the distributed token exchange.\n\nArguments:\n1. fromaddress       (string, required)  The address to trade with\n2. propertyidforsale (numeric, required) The identifier of the tokens to list for sale\n3. amountforsale     (string, required)  The amount of tokens to list for sale\n4. propertiddesired  (numeric, required) The identifier of the tokens desired in exchange\n5. amountdesired     (string, required)  The amount of tokens desired in exchange\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendcanceltradesbyprice":           "omni_sendcanceltradesbyprice \"fromaddress\" propertyidforsale \"amountforsale\" propertiddesired \"amountdesired\"\n\nCancels the offers on the distributed token exchange with the given currency pair and price.\n\nArguments:\n1. fromaddress       (string, required)  The address to trade with\n2. propertyidforsale (numeric, required) The identifier of the tokens listed for sale\n3. amountforsale     (string, required)  The amount of tokens listed for sale\n4. propertiddesired  (numeric, required) The identifier of the tokens desired in exchange\n5. amountdesired     (string, required)  The amount of tokens desired in exchange\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendcanceltradesbypair":            "omni_sendcanceltradesbypair \"fromaddress\" propertyidforsale propertiddesired\n\nCancels all offers on the distributed token exchange with the given currency pair.\n\nArguments:\n1. fromaddress       (string, required)  The address to trade with\n2. propertyidforsale (numeric, required) The identifier of the tokens listed for sale\n3. propertiddesired  (numeric, required) The identifier of the tokens desired in exchange\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendcancelalltrades":               "omni_sendcancelalltrades \"fromaddress\" ecosystem\n\nCancels all offers of an address on the distributed token exchange.\n\nArguments:\n1. fromaddress (string, required)  The address to trade with\n2. ecosystem   (numeric, required) The ecosystem of the offers to cancel (1 for the main ecosystem, 2 for the test ecosystem)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendchangeissuer":                  "omni_sendchangeissuer \"fromaddress\" \"toaddress\" propertyid\n\nTransfers administrative control of tokens to another address.\n\nArguments:\n1. fromaddress (string, required)  The address of the current issuer\n2. toaddress   (string, required)  The address receiving administrative control\n3. propertyid  (numeric, required) The identifier of the tokens\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendall":                           "omni_sendall \"fromaddress\" \"toaddress\" ecosystem (\"redeemaddress\" \"referenceamount\")\n\nTransfers all tokens of an ecosystem held by an address.\n\nArguments:\n1. fromaddress     (string, required)  The address to send from\n2. toaddress       (string, required)  The address of the recipient\n3. ecosystem       (numeric, required) The ecosystem of the tokens to send (1 for the main ecosystem, 2 for the test ecosystem)\n4. redeemaddress   (string, optional)  An address that can spend the transaction dust (default: the sending address)\n5. referenceamount (string, optional)  The value of the reference output paying the recipient valued in HC (default: the dust threshold)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendenablefreezing":                "omni_sendenablefreezing \"fromaddress\" propertyid\n\nEnables address freezing for managed tokens.\n\nArguments:\n1. fromaddress (string, required)  The address of the issuer\n2. propertyid  (numeric, required) The identifier of the tokens\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_senddisablefreezing":               "omni_senddisablefreezing \"fromaddress\" propertyid\n\nDisables address freezing for managed tokens, unfreezing all frozen addresses.\n\nArguments:\n1. fromaddress (string, required)  The address of the issuer\n2. propertyid  (numeric, required) The identifier of the tokens\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendfreeze":                        "omni_sendfreeze \"fromaddress\" \"toaddress\" propertyid \"amount\"\n\nFreezes an address for managed tokens with freezing enabled.\n\nArguments:\n1. fromaddress (string, required)  The address of the issuer\n2. toaddress   (string, required)  The address to freeze\n3. propertyid  (numeric, required) The identifier of the tokens to freeze\n4. amount      (string, required)  Unused, frozen addresses can not transact the tokens\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendunfreeze":                      "omni_sendunfreeze \"fromaddress\" \"toaddress\" propertyid \"amount\"\n\nUnfreezes an address for managed tokens with freezing enabled.\n\nArguments:\n1. fromaddress (string, required)  The address of the issuer\n2. toaddress   (string, required)  The address to unfreeze\n3. propertyid  (numeric, required) The identifier of the tokens to unfreeze\n4. amount      (string, required)  Unused\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_sendrawtx":                         "omni_sendrawtx \"fromaddress\" \"rawtransaction\" (\"referenceaddress\" \"redeemaddress\" \"referenceamount\")\n\nPublishes a raw omni transaction.\n\nArguments:\n1. fromaddress      (string, required) The address to send from\n2. rawtransaction   (string, required) The hex-encoded raw transaction\n3. referenceaddress (string, optional) A reference address (default: none)\n4. redeemaddress    (string, optional) An address that can spend the transaction dust (default: the sending address)\n5. referenceamount  (string, optional) The value of the reference output valued in HC (default: the dust threshold)\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_funded_send":                       "omni_funded_send \"fromaddress\" \"toaddress\" propertyid \"amount\" \"feeaddress\"\n\nCreates and publishes a simple send transaction of omni tokens whose fee is paid by another address.\n\nArguments:\n1. fromaddress (string, required)  The address to send from\n2. toaddress   (string, required)  The address of the recipient\n3. propertyid  (numeric, required) The identifier of the tokens to send\n4. amount      (string, required)  The amount of tokens to send\n5. feeaddress  (string, required)  The address paying the transaction fee and receiving change\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_funded_sendall":                    "omni_funded_sendall \"fromaddress\" \"toaddress\" ecosystem \"feeaddress\"\n\nTransfers all tokens of an ecosystem held by an address, with the fee paid by another address.\n\nArguments:\n1. fromaddress (string, required)  The address to send from\n2. toaddress   (string, required)  The address of the recipient\n3. ecosystem   (numeric, required) The ecosystem of the tokens to send (1 for the main ecosystem, 2 for the test ecosystem)\n4. feeaddress  (string, required)  The address paying the transaction fee and receiving change\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"omni_getinfo":                           "omni_getinfo\n\nReturns the state of the omni engine, including its version and the last processed block.\n\nArguments:\nNone\n\nResult:\n{\n \"The name of the state\": The value of the state, (object) JSON object describing the omni engine\n ...\n}\n",
+		"omni_getbalance":                        "omni_getbalance \"address\" propertyid\n\nReturns the token balance of an address.\n\nArguments:\n1. address    (string, required)  The address\n2. propertyid (numeric, required) The identifier of the tokens\n\nResult:\n{\n \"The kind of balance (balance, reserved, or frozen)\": The balance as a string, (object) JSON object with the available, reserved and frozen balances\n ...\n}\n",
+		"omni_getallbalancesforid":               "omni_getallbalancesforid propertyid\n\nReturns the balances of every address holding tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with an address and its available, reserved and frozen balances\n ...\n},...]\n",
+		"omni_getallbalancesforaddress":          "omni_getallbalancesforaddress \"address\"\n\nReturns the balances of every token held by an address.\n\nArguments:\n1. address (string, required) The address\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with a property identifier, its name, and its available, reserved and frozen balances\n ...\n},...]\n",
+		"omni_getwalletbalances":                 "omni_getwalletbalances\n\nReturns the total balances of every token held by the wallet's addresses.\n\nArguments:\nNone\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with a property identifier, its name, and its available, reserved and frozen balances\n ...\n},...]\n",
+		"omni_getwalletaddressbalances":          "omni_getwalletaddressbalances\n\nReturns the token balances of every wallet address.\n\nArguments:\nNone\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with an address and the balances of every token it holds\n ...\n},...]\n",
+		"omni_gettransaction":                    "omni_gettransaction (\"txid\")\n\nReturns detailed information about an omni transaction.\n\nArguments:\n1. txid (string, optional) The hash of the transaction\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the transaction, including its sender, reference address, validity and type specific fields\n ...\n}\n",
+		"omni_listtransactions":                  "omni_listtransactions (\"txid\" count skip startblock endblock)\n\nLists omni transactions.\n\nArguments:\n1. txid       (string, optional)  The address to filter transactions by (default: \"*\" for all addresses)\n2. count      (numeric, optional) The maximum number of transactions to list (default: 10)\n3. skip       (numeric, optional) The number of transactions to skip (default: 0)\n4. startblock (numeric, optional) The first block to search (default: 0)\n5. endblock   (numeric, optional) The last block to search (default: 999999999)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing a transaction, as returned by omni_gettransaction\n ...\n},...]\n",
+		"omni_listwallettransactions":            "omni_listwallettransactions [\"addrlist\",...] (count skip startblock endblock)\n\nLists the omni transactions of the wallet's addresses.\n\nArguments:\n1. addrlist   (array of string, required) Unused, the transactions of every wallet address are listed\n2. count      (numeric, optional)         The maximum number of transactions to list (default: 10)\n3. skip       (numeric, optional)         The number of transactions to skip (default: 0)\n4. startblock (numeric, optional)         The first block to search (default: 0)\n5. endblock   (numeric, optional)         The last block to search (default: 999999999)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing a transaction, as returned by omni_gettransaction\n ...\n},...]\n",
+		"omni_listblocktransactions":             "omni_listblocktransactions height\n\nLists the omni transactions of the wallet's addresses in a block.\n\nArguments:\n1. height (numeric, required) The height of the block\n\nResult:\n[\"value\",...] (array of string) The hashes of the transactions\n",
+		"omni_listpendingtransactions":           "omni_listpendingtransactions (\"address\")\n\nLists unconfirmed omni transactions.\nThe validity of pending transactions is uncertain, and they should be considered invalid until they are mined.\n\nArguments:\n1. address (string, optional) The address to filter transactions by (default: every wallet address)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing a transaction, as returned by omni_gettransaction\n ...\n},...]\n",
+		"omni_getactivedexsells":                 "omni_getactivedexsells\n\nLists the active offers on the distributed OMNI/HC exchange.\n\nArguments:\nNone\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing an offer and the accepts of it\n ...\n},...]\n",
+		"omni_listproperties":                    "omni_listproperties\n\nLists all tokens and smart properties.\n\nArguments:\nNone\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with a property identifier, its name, category, URL, data and divisibility\n ...\n},...]\n",
+		"omni_getproperty":                       "omni_getproperty propertyid (currentheight)\n\nReturns details of tokens or a smart property.\n\nArguments:\n1. propertyid    (numeric, required) The identifier of the tokens or property\n2. currentheight (numeric, optional) Unused, the height of the main chain tip is used\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the property, including its issuer, creation transaction and total tokens\n ...\n}\n",
+		"omni_getactivecrowdsales":               "omni_getactivecrowdsales\n\nLists the active crowdsales.\n\nArguments:\nNone\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing a crowdsale, including its issuer, desired property, rate and deadline\n ...\n},...]\n",
+		"omni_getcrowdsale":                      "omni_getcrowdsale propertyid (verbose)\n\nReturns information about a crowdsale.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the crowdsale\n2. verbose    (boolean, optional) List the participants of the crowdsale (default: false)\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the crowdsale, including its issuer, desired property, rate, deadline and raised tokens\n ...\n}\n",
+		"omni_getgrants":                         "omni_getgrants propertyid\n\nReturns the grants and revocations of managed tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the managed tokens\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the tokens, their issuer, total tokens, and the list of grants and revocations\n ...\n}\n",
+		"omni_getsto":                            "omni_getsto \"txid\" (\"recipientfilter\")\n\nReturns information and recipients of a send to owners transaction.\n\nArguments:\n1. txid            (string, required) The hash of the transaction\n2. recipientfilter (string, optional) The recipients to list (default: the wallet's addresses, \"*\" for all)\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the transaction and its recipients\n ...\n}\n",
+		"omni_gettrade":                          "omni_gettrade \"txid\"\n\nReturns information about an order on the distributed token exchange.\n\nArguments:\n1. txid (string, required) The hash of the order\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the order, its status and its matches\n ...\n}\n",
+		"omni_getorderbook":                      "omni_getorderbook salepropertyid (desiredpropertyid)\n\nLists the active offers on the distributed token exchange.\n\nArguments:\n1. salepropertyid    (numeric, required) The identifier of the tokens for sale\n2. desiredpropertyid (numeric, optional) The identifier of the tokens desired (default: any)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing an order, as returned by omni_gettrade\n ...\n},...]\n",
+		"omni_gettradehistoryforpair":            "omni_gettradehistoryforpair firstpropertyid secondpropertyid (count)\n\nLists the trades of a currency pair on the distributed token exchange.\n\nArguments:\n1. firstpropertyid  (numeric, required) The identifier of the first side of the traded pair\n2. secondpropertyid (numeric, required) The identifier of the second side of the traded pair\n3. count            (numeric, optional) The maximum number of trades to list (default: 10)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing a trade, including the amounts sold and bought by each side\n ...\n},...]\n",
+		"omni_gettradehistoryforaddress":         "omni_gettradehistoryforaddress \"address\" (count propertyid)\n\nLists the orders of an address on the distributed token exchange.\n\nArguments:\n1. address    (string, required)  The address\n2. count      (numeric, optional) The maximum number of orders to list (default: 10)\n3. propertyid (numeric, optional) The identifier of the traded tokens to filter orders by (default: no filter)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing an order, as returned by omni_gettrade\n ...\n},...]\n",
+		"omni_getactivations":                    "omni_getactivations\n\nReturns the pending and completed feature activations.\n\nArguments:\nNone\n\nResult:\n{\n \"The kind of activation (pendingactivations or completedactivations)\": The activations, (object) JSON object with the lists of pending and completed activations\n ...\n}\n",
+		"omni_getpayload":                        "omni_getpayload \"txhash\"\n\nReturns the payload of an omni transaction.\n\nArguments:\n1. txhash (string, required) The hash of the transaction\n\nResult:\n{\n \"The name of the field (payload or payloadsize)\": The value of the field, (object) JSON object with the hex-encoded payload and its size\n ...\n}\n",
+		"omni_getseedblocks":                     "omni_getseedblocks startblock endblock\n\nReturns the blocks containing omni transactions in a range of heights.\n\nArguments:\n1. startblock (numeric, required) The first block to search (inclusive)\n2. endblock   (numeric, required) The last block to search (inclusive)\n\nResult:\n[n,...] (array of numeric) The heights of the blocks\n",
+		"omni_getcurrentconsensushash":           "omni_getcurrentconsensushash\n\nReturns the consensus hash of the omni state at the last processed block.\n\nArguments:\nNone\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object with the block height, block hash and consensus hash\n ...\n}\n",
+		"omni_decodetransaction":                 "omni_decodetransaction \"rawtx\" (\"prevtxs\" height)\n\nDecodes an omni transaction.\n\nArguments:\n1. rawtx   (string, required)  The hex-encoded raw transaction\n2. prevtxs (string, optional)  A JSON array of the outputs spent by the transaction (default: none)\n3. height  (numeric, optional) The height to decode the transaction at (default: 0 for the chain height)\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the transaction, as returned by omni_gettransaction\n ...\n}\n",
+		"omni_createrawtx_opreturn":              "omni_createrawtx_opreturn \"rawtx\" \"payload\"\n\nAdds a payload output to a transaction.\n\nArguments:\n1. rawtx   (string, required) The hex-encoded raw transaction to extend\n2. payload (string, required) The hex-encoded payload to add\n\nResult:\n\"value\" (string) The hex-encoded modified transaction\n",
+		"omni_createrawtx_multisig":              "omni_createrawtx_multisig \"rawtx\" \"addpayload\" \"seed\" \"redemptionpayload\"\n\nAdds a payload with bare multisig encoding to a transaction.\n\nArguments:\n1. rawtx             (string, required) The hex-encoded raw transaction to extend (empty to create a new transaction)\n2. addpayload        (string, required) The hex-encoded payload to add\n3. seed              (string, required) The seed of the obfuscation\n4. redemptionpayload (string, required) A public key or address which can redeem the dust\n\nResult:\n\"value\" (string) The hex-encoded modified transaction\n",
+		"omni_createrawtx_input":                 "omni_createrawtx_input \"rawtx\" \"txid\" n\n\nAdds an input to a transaction.\n\nArguments:\n1. rawtx (string, required)  The hex-encoded raw transaction to extend (empty to create a new transaction)\n2. txid  (string, required)  The hash of the transaction whose output is spent\n3. n     (numeric, required) The index of the spent output\n\nResult:\n\"value\" (string) The hex-encoded modified transaction\n",
+		"omni_createrawtx_reference":             "omni_createrawtx_reference \"rawtx\" \"destination\" (amount)\n\nAdds a reference output paying the recipient to a transaction.\n\nArguments:\n1. rawtx       (string, required)  The hex-encoded raw transaction to extend (empty to create a new transaction)\n2. destination (string, required)  The address of the recipient\n3. amount      (numeric, optional) The value of the output in atoms, which may not be below the dust threshold of the default account's relay fee (default: the dust threshold)\n\nResult:\n\"value\" (string) The hex-encoded modified transaction\n",
+		"omni_createrawtx_change":                "omni_createrawtx_change \"rawtx\" \"prevtxs\" \"destination\" fee (position)\n\nAdds the inputs and a change output to a transaction.\n\nArguments:\n1. rawtx       (string, required)  The hex-encoded raw transaction to extend (empty to create a new transaction)\n2. prevtxs     (string, required)  A JSON array of the outputs to spend, as objects with the txid and vout of the output\n3. destination (string, required)  The address receiving the change\n4. fee         (numeric, required) The value of the change output in atoms\n5. position    (numeric, optional) Unused, the change output is added last\n\nResult:\n\"value\" (string) The hex-encoded modified transaction\n",
+		"omni_createpayload_simplesend":          "omni_createpayload_simplesend propertyid \"amount\"\n\nCreates the payload of a simple send transaction.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens to send\n2. amount     (string, required)  The amount of tokens to send\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_sendall":             "omni_createpayload_sendall\n\nCreates the payload of a send all transaction.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_dexsell":             "omni_createpayload_dexsell propertyidforsale \"amountforsale\" \"amountdesired\" paymentwindow \"minacceptfee\" action\n\nCreates the payload of an offer on the distributed OMNI/HC exchange.\n\nArguments:\n1. propertyidforsale (numeric, required) The identifier of the tokens to list for sale (1 for OMNI, 2 for TOMNI)\n2. amountforsale     (string, required)  The amount of tokens to list for sale\n3. amountdesired     (string, required)  The amount desired valued in HC\n4. paymentwindow     (numeric, required) The number of blocks a buyer has to pay after accepting the offer\n5. minacceptfee      (string, required)  The minimum fee a buyer has to pay to accept the offer\n6. action            (numeric, required) The action to take (1 for new offers, 2 to update, 3 to cancel)\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_dexaccept":           "omni_createpayload_dexaccept propertyid \"amount\"\n\nCreates the payload of an accept of an offer on the distributed OMNI/HC exchange.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens to purchase\n2. amount     (string, required)  The amount of tokens to accept\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_sto":                 "omni_createpayload_sto propertyid \"amount\" (distributionproperty)\n\nCreates the payload of a send to owners transaction.\n\nArguments:\n1. propertyid           (numeric, required) The identifier of the tokens to distribute\n2. amount               (string, required)  The amount of tokens to distribute\n3. distributionproperty (numeric, optional) The identifier of the property whose holders receive the tokens (default: the distributed property)\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_issuancefixed":       "omni_createpayload_issuancefixed ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" \"amount\"\n\nCreates the payload of an issuance of tokens with a fixed supply.\n\nArguments:\n1. ecosystem   (numeric, required) The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)\n2. typo        (numeric, required) The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)\n3. previousid  (numeric, required) The identifier of a predecessor token (0 for new tokens)\n4. category    (string, required)  The category of the new tokens (can be empty)\n5. subcategory (string, required)  The subcategory of the new tokens (can be empty)\n6. name        (string, required)  The name of the new tokens\n7. url         (string, required)  A URL with further information about the new tokens (can be empty)\n8. data        (string, required)  A description of the new tokens (can be empty)\n9. amount      (string, required)  The number of tokens to create\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_issuancecrowdsale":   "omni_createpayload_issuancecrowdsale ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" propertyiddesired \"tokensperunit\" deadline earlybonus issuerpercentage\n\nCreates the payload of an issuance of tokens by a crowdsale.\n\nArguments:\n1.  ecosystem         (numeric, required) The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)\n2.  typo              (numeric, required) The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)\n3.  previousid        (numeric, required) The identifier of a predecessor token (0 for new crowdsales)\n4.  category          (string, required)  The category of the new tokens (can be empty)\n5.  subcategory       (string, required)  The subcategory of the new tokens (can be empty)\n6.  name              (string, required)  The name of the new tokens\n7.  url               (string, required)  A URL with further information about the new tokens (can be empty)\n8.  data              (string, required)  A description of the new tokens (can be empty)\n9.  propertyiddesired (numeric, required) The identifier of the tokens eligible to participate in the crowdsale\n10. tokensperunit     (string, required)  The amount of tokens granted per unit invested in the crowdsale\n11. deadline          (numeric, required) The Unix time of the crowdsale deadline\n12. earlybonus        (numeric, required) The early bird bonus for participants in percent per week\n13. issuerpercentage  (numeric, required) The percentage of tokens granted to the issuer\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_issuancemanaged":     "omni_createpayload_issuancemanaged ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\"\n\nCreates the payload of an issuance of tokens with a supply managed by the issuer.\n\nArguments:\n1. ecosystem   (numeric, required) The ecosystem to create the tokens in (1 for the main ecosystem, 2 for the test ecosystem)\n2. typo        (numeric, required) The type of the tokens to create (1 for indivisible tokens, 2 for divisible tokens)\n3. previousid  (numeric, required) The identifier of a predecessor token (0 for new tokens)\n4. category    (string, required)  The category of the new tokens (can be empty)\n5. subcategory (string, required)  The subcategory of the new tokens (can be empty)\n6. name        (string, required)  The name of the new tokens\n7. url         (string, required)  A URL with further information about the new tokens (can be empty)\n8. data        (string, required)  A description of the new tokens (can be empty)\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_closecrowdsale":      "omni_createpayload_closecrowdsale\n\nCreates the payload of a transaction closing a crowdsale.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_grant":               "omni_createpayload_grant propertyid \"amount\" (\"memo\")\n\nCreates the payload of an issuance of new units of managed tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens to grant\n2. amount     (string, required)  The amount of tokens to create\n3. memo       (string, optional)  A note attached to the transaction\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_revoke":              "omni_createpayload_revoke propertyid \"amount\" (\"memo\")\n\nCreates the payload of a revocation of units of managed tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens to revoke\n2. amount     (string, required)  The amount of tokens to revoke\n3. memo       (string, optional)  A note attached to the transaction\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_changeissuer":        "omni_createpayload_changeissuer propertyid\n\nCreates the payload of a transfer of administrative control of tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_trade":               "omni_createpayload_trade propertyidforsale \"amountforsale\" propertyiddesired \"amountdesired\"\n\nCreates the payload of a trade offer on the distributed token exchange.\n\nArguments:\n1. propertyidforsale (numeric, required) The identifier of the tokens to list for sale\n2. amountforsale     (string, required)  The amount of tokens to list for sale\n3. propertyiddesired (numeric, required) The identifier of the tokens desired in exchange\n4. amountdesired     (string, required)  The amount of tokens desired in exchange\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_canceltradesbyprice": "omni_createpayload_canceltradesbyprice propertyidforsale \"amountforsale\" propertyiddesired \"amountdesired\"\n\nCreates the payload of a cancellation of offers with the given currency pair and price.\n\nArguments:\n1. propertyidforsale (numeric, required) The identifier of the tokens listed for sale\n2. amountforsale     (string, required)  The amount of tokens listed for sale\n3. propertyiddesired (numeric, required) The identifier of the tokens desired in exchange\n4. amountdesired     (string, required)  The amount of tokens desired in exchange\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_canceltradesbypair":  "omni_createpayload_canceltradesbypair propertyidforsale propertyiddesired\n\nCreates the payload of a cancellation of all offers with the given currency pair.\n\nArguments:\n1. propertyidforsale (numeric, required) The identifier of the tokens listed for sale\n2. propertyiddesired (numeric, required) The identifier of the tokens desired in exchange\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_cancelalltrades":     "omni_createpayload_cancelalltrades\n\nCreates the payload of a cancellation of all offers of an address.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_enablefreezing":      "omni_createpayload_enablefreezing propertyid\n\nCreates the payload of a transaction enabling address freezing for managed tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_disablefreezing":     "omni_createpayload_disablefreezing propertyid\n\nCreates the payload of a transaction disabling address freezing for managed tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_freeze":              "omni_createpayload_freeze \"toaddress\" propertyid \"amount\"\n\nCreates the payload of a transaction freezing an address.\n\nArguments:\n1. toaddress  (string, required)  The address to freeze\n2. propertyid (numeric, required) The identifier of the managed tokens with freezing enabled\n3. amount     (string, required)  Unused, frozen addresses can not transact the tokens\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_createpayload_unfreeze":            "omni_createpayload_unfreeze \"toaddress\" propertyid \"amount\"\n\nCreates the payload of a transaction unfreezing an address.\n\nArguments:\n1. toaddress  (string, required)  The address to unfreeze\n2. propertyid (numeric, required) The identifier of the managed tokens with freezing enabled\n3. amount     (string, required)  Unused\n\nResult:\n\"value\" (string) The hex-encoded payload\n",
+		"omni_getfeecache":                       "omni_getfeecache propertyid\n\nReturns the fees cached for distribution.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the tokens to filter the cache by (0 for all)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with a property identifier and its cached fees\n ...\n},...]\n",
+		"omni_getfeetrigger":                     "omni_getfeetrigger\n\nReturns the amounts of cached fees which trigger a distribution.\n\nArguments:\nNone\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with a property identifier and its fee trigger\n ...\n},...]\n",
+		"omni_getfeeshare":                       "omni_getfeeshare (\"address\" ecosystem)\n\nReturns the share of distributed fees an address would receive.\n\nArguments:\n1. address   (string, optional)  The address to filter shares by (default: every address)\n2. ecosystem (numeric, optional) The ecosystem of the fees (1 for the main ecosystem, 2 for the test ecosystem)\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object with an address and its share of the fees in percent\n ...\n},...]\n",
+		"omni_getfeedistribution":                "omni_getfeedistribution distributionid\n\nReturns the details of a fee distribution.\n\nArguments:\n1. distributionid (numeric, required) The identifier of the distribution\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the distribution and its recipients\n ...\n}\n",
+		"omni_getfeedistributions":               "omni_getfeedistributions propertyid\n\nReturns the details of the fee distributions of tokens.\n\nArguments:\n1. propertyid (numeric, required) The identifier of the distributed tokens\n\nResult:\n[{\n \"The name of the field\": The value of the field, (object) JSON object describing a distribution and its recipients\n ...\n},...]\n",
+		"omni_setautocommit":                     "omni_setautocommit autocommit\n\nSets whether the omni engine automatically commits and publishes the transactions it creates.\n\nArguments:\n1. autocommit (boolean, required) Whether transactions are automatically committed\n\nResult:\ntrue|false (boolean) The new value of the flag\n",
+		"omni_rollback":                          "omni_rollback height ([\"hash\",...])\n\nRolls the omni state back to a block height.\n\nArguments:\n1. height (numeric, required)         The height to roll back to\n2. hashs  (array of string, optional) Unused\n\nResult:\n\"value\" (string) An empty string\n",
+		"omni_getblockinfo":                      "omni_getblockinfo height\n\nReturns the omni state of a block.\n\nArguments:\n1. height (numeric, required) The height of the block\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the omni state of the block\n ...\n}\n",
+		"omni_createagreement":                   "omni_createagreement \"agttype\" \"agtcontent\" \"agtnonencryptedcontent\" \"agtkey\" \"agtencryptedcontent\"\n\nCreates an agreement with the omni engine.\n\nArguments:\n1. agttype                (string, required) The type of the agreement\n2. agtcontent             (string, required) The content of the agreement\n3. agtnonencryptedcontent (string, required) The part of the content which is not encrypted\n4. agtkey                 (string, required) The key encrypting the content\n5. agtencryptedcontent    (string, required) The encrypted part of the content\n\nResult:\n{\n \"The name of the field\": The value of the field, (object) JSON object describing the created agreement\n ...\n}\n",
+		"omni_sendagreement":                     "omni_sendagreement \"fromaddress\" \"toaddress\" \"agtid\" \"agtnonencryptedcontent\" \"agtkey\" \"agtencryptedcontent\"\n\nCreates and publishes a transaction sending an agreement to an address.\n\nArguments:\n1. fromaddress            (string, required) The address to send from\n2. toaddress              (string, required) The address of the recipient\n3. agtid                  (string, required) The identifier of the agreement\n4. agtnonencryptedcontent (string, required) The part of the content which is not encrypted\n5. agtkey                 (string, required) The key encrypting the content\n6. agtencryptedcontent    (string, required) The encrypted part of the content\n\nResult:\n\"value\" (string) The hash of the published transaction\n",
+		"getstraightpubkey":                      "getstraightpubkey \"srcaddress\"\n\nReturns the public key of a wallet pay-to-pubkey-hash address encoded as a pay-to-pubkey address.\n\nArguments:\n1. srcaddress (string, required) The pay-to-pubkey-hash address\n\nResult:\n{\n \"StraightPubKey\": \"value\", (string) The pay-to-pubkey address of the public key\n}                           \n",
 	}
 }
 
@@ -86,4 +238,4 @@ var localeHelpDescs = map[string]func() map[string]string{
 	"en_US": helpDescsEnUS,
 }
 
-var requestUsages = "accountaddressindex \"account\" branch\naccountsyncaddressindex \"account\" branch index\naddmultisigaddress nrequired [\"key\",...] (\"account\")\nconsolidate inputs (\"account\" \"address\")\ncreatemultisig nrequired [\"key\",...]\ndumpprivkey \"address\"\ngetaccount \"address\"\ngetaccountaddress \"account\"\ngetaddressesbyaccount \"account\"\ngetbalance (\"account\" minconf=1)\ngetbestblockhash\ngetblockcount\ngetinfo\ngetmasterpubkey (\"account\")\ngetmultisigoutinfo \"hash\" index\ngetnewaddress (\"account\" \"gappolicy\")\ngetrawchangeaddress (\"account\")\ngetreceivedbyaccount \"account\" (minconf=1)\ngetreceivedbyaddress \"address\" (minconf=1)\ngettickets includeimmature\ngettransaction \"txid\" (includewatchonly=false)\ngetvotechoices\nhelp (\"command\")\nimportprivkey \"privkey\" (\"label\" rescan=true scanfrom)\nimportscript \"hex\" (rescan=true scanfrom)\nkeypoolrefill (newsize=100)\nlistaccounts (minconf=1)\nlistlockunspent\nlistreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\nlistreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\nlistsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\nlisttransactions (\"account\" count=10 from=0 includewatchonly=false)\nlistunspent (minconf=1 maxconf=9999999 [\"address\",...])\nlockunspent unlock [{\"txid\":\"value\",\"vout\":n,\"tree\":n},...]\nredeemmultisigout \"hash\" index tree (\"address\")\nredeemmultisigouts \"fromscraddress\" (\"toaddress\" number)\nrescanwallet (beginheight=0)\nrevoketickets\nsendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 \"comment\" \"commentto\")\nsendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 \"comment\")\nsendtoaddress \"address\" amount (\"comment\" \"commentto\")\nsendtomultisig \"fromaccount\" amount [\"pubkey\",...] (nrequired=1 minconf=1 \"comment\")\nsettxfee amount\nsetvotechoice \"agendaid\" \"choiceid\"\nsignmessage \"address\" \"message\"\nsignrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\nsignrawtransactions [\"rawtx\",...] (send=true)\nvalidateaddress \"address\"\nverifymessage \"address\" \"signature\" \"message\"\nversion\nwalletlock\nwalletpassphrase \"passphrase\" timeout\nwalletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\ncreatenewaccount \"account\"\nexportwatchingwallet (\"account\" download=false)\ngetbestblock\ngetunconfirmedbalance (\"account\")\nlistaddresstransactions [\"address\",...] (\"account\")\nlistalltransactions (\"account\")\nrenameaccount \"oldaccount\" \"newaccount\"\nwalletislocked\nwalletinfo\npurchaseticket \"fromaccount\" spendlimit (minconf=1 \"ticketaddress\" numtickets \"pooladdress\" poolfees expiry \"comment\")\nsendtossrtx \"fromaccount\" \"tickethash\" (\"comment\")\nsendtosstx \"fromaccount\" amounts [{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"amt\":n},...] [{\"addr\":\"value\",\"commitamt\":n,\"changeaddr\":\"value\",\"changeamt\":n},...] (minconf=1 \"comment\")\nsendtossgen \"fromaccount\" \"tickethash\" \"blockhash\" height votebits (\"comment\")\ngeneratevote \"blockhash\" height \"tickethash\" votebits \"votebitsext\"\ngetstakeinfo\ngetticketfee\nsetticketfee fee\ngetwalletfee\naddticket \"tickethex\"\nlistscripts\nstakepooluserinfo \"user\"\nticketsforaddress \"address\""
+var requestUsages = "abandontransaction \"txhash\"\naccountaddressindex \"account\" branch\naccountsyncaddressindex \"account\" branch index\naddmultisigaddress nrequired [\"key\",...] (\"account\")\nbumpfee \"txhash\" (feerate)\ncanceltransactiondraft \"draftid\"\ncheckaddressreuse (lookahead=0 startheight=0)\ncommittransactiondraft \"draftid\"\ncompactdb\nconsolidate inputs (\"account\" \"address\" feerate maxfee)\ncreatemultisig nrequired [\"key\",...]\ncreatetransactiondraft {\"address\":amount,...} (account=\"default\" minconf=1)\ndumpprivkey \"address\"\nexporttransactions (\"account\" format=\"csv\" fiat=false)\nexportvotechoices\ngetaccount \"address\"\ngetaccountaddress \"account\"\ngetaccountdefaultaddress (account=\"default\")\ngetaddressesbyaccount \"account\"\ngetbalance (\"account\" minconf=2 \"currency\")\ngetbestblockhash\ngetblockcount\ngetinfo\ngetmasterpubkey (\"account\")\ngetmultisigoutinfo \"hash\" index\ngetnewaddress (\"account\" \"gappolicy\" \"branch\")\ngetrawchangeaddress (\"account\")\ngetreceivedbyaccount \"account\" (minconf=2)\ngetreceivedbyaddress \"address\" (minconf=2)\ngettickets includeimmature\ngettransaction \"txid\" (includewatchonly=false \"currency\" verbose=false)\ngetvotechoices\nhelp (\"command\")\nimportprivkey \"privkey\" (\"label\" rescan=true scanfrom)\nimportscript \"hex\" (rescan=true scanfrom)\nimportvotechoices [{\"agendaid\":\"value\",\"choiceid\":\"value\"},...] (version)\nkeypoolrefill (newsize=100)\nlistaccounts (minconf=2)\nlistlockunspent\nlistreceivedbyaccount (minconf=2 includeempty=false includewatchonly=false)\nlistreceivedbyaddress (minconf=2 includeempty=false includewatchonly=false)\nlistsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false \"account\")\nliststaleomnipending (expiry)\nlisttransactions (\"account\" count=10 from=0 includewatchonly=false \"cursor\")\nlistunspent (minconf=2 maxconf=9999999 [\"address\",...] includelocked=false \"cursor\" count=100)\nlockunspent unlock [{\"txid\":\"value\",\"vout\":n,\"tree\":n},...]\nomni_estimatefee \"fromaddress\" \"payload\" (\"toaddress\" \"changeaddress\")\nomni_getdustthreshold (\"address\" \"fromaddress\")\nredeemmultisigout \"hash\" index tree (\"address\")\nredeemmultisigouts \"fromscraddress\" (\"toaddress\" number)\nrescanwallet (beginheight=0)\nreserveoutputs amount (account=\"default\" minconf=1 expiry=60)\nrevoketickets (allowhighfees feerate [\"ticket\",...] dryrun=false)\nsendfrom \"fromaccount\" \"toaddress\" amount (minconf=2 \"comment\" \"commentto\" \"idempotencykey\")\nsendmany \"fromaccount\" {\"address\":amount,...} (minconf=2 \"comment\" \"idempotencykey\")\nsendmanyv2 \"fromaccount\" {\"address\":amount,...} (\"changeaddr\" minconf=2 \"idempotencykey\")\nsendbatch \"fromaccount\" \"payments\" (minconf=1 atomic=false)\nsenddata \"data\" (account=\"default\" minconf=1)\nsendrawtransaction \"hextx\" (allowhighfees=false)\nsendtoaddress \"address\" amount (\"comment\" \"commentto\" \"idempotencykey\")\nsendfromaddresstoaddress \"fromaddress\" \"address\" amount\nsendtomultisig \"fromaccount\" amount [\"pubkey\",...] (nrequired=1 minconf=2 \"comment\")\nsettxfee amount (\"account\")\nsetvotechoice \"agendaid\" \"choiceid\"\nsignmessage \"address\" \"message\"\nsignmessagewithaccount \"account\" \"message\"\nsignrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\",\"amount\":amount},...] [\"privkey\",...] flags=\"ALL\")\nsignrawtransactions [\"rawtx\",...] (send=true allowhighfees)\nvalidateaddress \"address\"\nverifyaccountproof \"xpub\" \"signature\" \"message\"\nverifymessage \"address\" \"signature\" \"message\"\nverifyseedbackup ([\"word\",...])\nversion\nwalletlock\nwalletpassphrase \"passphrase\" timeout\nwalletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\ncreatenewaccount \"account\" \"accounttype\"\nexportwatchingwallet (\"account\" download=false)\ngetbestblock\ngetunconfirmedbalance (\"account\")\nlistaddresstransactions [\"address\",...] (\"account\")\nlistalltransactions (\"account\" \"cursor\" count=100)\nnotifytipchanges\nrenameaccount \"oldaccount\" \"newaccount\"\nstopnotifytipchanges\nwalletislocked\nwalletinfo\nprunewallethistory (depth)\npurchaseticket \"fromaccount\" spendlimit (minconf=2 \"ticketaddress\" numtickets \"pooladdress\" poolfees expiry \"comment\" ticketfee)\nsendtossrtx \"fromaccount\" \"tickethash\" (\"comment\" allowhighfees)\nsendtosstx \"fromaccount\" amounts [{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"amt\":n},...] [{\"addr\":\"value\",\"commitamt\":n,\"changeaddr\":\"value\",\"changeamt\":n},...] (minconf=2 \"comment\" allowhighfees)\nsendtossgen \"fromaccount\" \"tickethash\" \"blockhash\" height votebits (\"comment\")\ngeneratevote \"blockhash\" height \"tickethash\" votebits \"votebitsext\"\ngenerateticketproof \"tickethash\" \"challenge\"\nverifyticketproof \"tickethash\" \"address\" \"signature\" \"challenge\"\ndecodewallettransaction \"hextx\"\ndelegatedtickets\nexportaccount \"account\" (private=false)\ngetreplicationinfo\npromotereplica\ngetaddressforinvoice \"invoiceid\" (account=\"default\")\ngetauditlog (from=0 count=100)\ngetbalanceathash \"blockhash\" (account=\"*\")\ngetbalanceatheight height (account=\"*\")\ngetinvoicepayments \"invoiceid\" (minconf=1)\ngethealth (maxblocksbehind=6)\ngetrecoverystate\ngetrescaninfo\ngetspendableconfs (account=\"default\")\ngetstakeinfo (\"account\")\ngetstakerewards (period=\"month\" since until)\ngetticketfee\nsetloglevel \"levelspec\"\nsetlogrotation maxsize maxrolls\nsetspendableconfs \"account\" regular (coinbase=0)\nsetticketfee fee (\"account\")\ngetwalletfee\nimportaccount \"account\" \"key\" (rescan=true scanfrom)\ncreateexternalbranch \"account\" \"branch\"\nlistexternalbranches (account=\"default\")\ncreatemultisigaccount \"name\" nrequired [\"xpub\",...] (account=\"default\")\ngetnewmultisigaddress \"name\"\ngetmultisigaccountinfo \"name\"\ncreatemultisigspend \"name\" {\"address\":amount,...}\naddticket \"tickethex\"\nlistscripts\nlistrefundablescripts\nrefundscript \"address\" (\"toaddress\")\ninitiateswap \"address\" amount (account=\"default\" locktime)\nparticipateswap \"address\" amount \"secrethash\" (account=\"default\" locktime)\nredeemswap \"contract\" \"contracttx\" \"secret\" (\"toaddress\")\nrefundswap \"contract\" \"contracttx\" (\"toaddress\")\nlistswaps\nstakepooluserinfo \"user\"\nlistpoolfeeexemptions\nreevaluatepooltickets \"user\"\nsetpoolfeeexemption \"user\" (exempt=true)\nsetaccountdefaultaddress \"account\" (\"address\")\nsyncaccountaddresses\nticketsforaddress \"address\"\ntriggerconsolidation (account=\"default\" force=false)\nomni_send \"fromaddress\" \"toaddress\" propertyid \"amount\" (\"redeemaddress\" \"referenceamount\")\nomni_senddexsell \"fromaddress\" propertyidforsale \"amountforsale\" \"amountdesired\" paymentwindow \"minacceptfee\" action\nomni_senddexaccept \"fromaddress\" \"toaddress\" propertyid \"amount\" (override)\nomni_sendissuancecrowdsale \"fromaddress\" ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" propertyiddesired \"tokensperunit\" deadline earlybonus issuerpercentage\nomni_sendissuancefixed \"fromaddress\" ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" \"amount\"\nomni_sendissuancemanaged \"fromaddress\" ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\"\nomni_sendsto \"fromaddress\" propertyid \"amount\" (\"redeemaddress\" distributionproperty)\nomni_sendgrant \"fromaddress\" \"toaddress\" propertyid \"amount\" (\"memo\")\nomni_sendrevoke \"fromaddress\" propertyid \"amount\" (\"memo\")\nomni_sendclosecrowdsale \"fromaddress\" propertyid\nomni_sendtrade \"fromaddress\" propertyidforsale \"amountforsale\" propertiddesired \"amountdesired\"\nomni_sendcanceltradesbyprice \"fromaddress\" propertyidforsale \"amountforsale\" propertiddesired \"amountdesired\"\nomni_sendcanceltradesbypair \"fromaddress\" propertyidforsale propertiddesired\nomni_sendcancelalltrades \"fromaddress\" ecosystem\nomni_sendchangeissuer \"fromaddress\" \"toaddress\" propertyid\nomni_sendall \"fromaddress\" \"toaddress\" ecosystem (\"redeemaddress\" \"referenceamount\")\nomni_sendenablefreezing \"fromaddress\" propertyid\nomni_senddisablefreezing \"fromaddress\" propertyid\nomni_sendfreeze \"fromaddress\" \"toaddress\" propertyid \"amount\"\nomni_sendunfreeze \"fromaddress\" \"toaddress\" propertyid \"amount\"\nomni_sendrawtx \"fromaddress\" \"rawtransaction\" (\"referenceaddress\" \"redeemaddress\" \"referenceamount\")\nomni_funded_send \"fromaddress\" \"toaddress\" propertyid \"amount\" \"feeaddress\"\nomni_funded_sendall \"fromaddress\" \"toaddress\" ecosystem \"feeaddress\"\nomni_getinfo\nomni_getbalance \"address\" propertyid\nomni_getallbalancesforid propertyid\nomni_getallbalancesforaddress \"address\"\nomni_getwalletbalances\nomni_getwalletaddressbalances\nomni_gettransaction (\"txid\")\nomni_listtransactions (\"txid\" count skip startblock endblock)\nomni_listwallettransactions [\"addrlist\",...] (count skip startblock endblock)\nomni_listblocktransactions height\nomni_listpendingtransactions (\"address\")\nomni_getactivedexsells\nomni_listproperties\nomni_getproperty propertyid (currentheight)\nomni_getactivecrowdsales\nomni_getcrowdsale propertyid (verbose)\nomni_getgrants propertyid\nomni_getsto \"txid\" (\"recipientfilter\")\nomni_gettrade \"txid\"\nomni_getorderbook salepropertyid (desiredpropertyid)\nomni_gettradehistoryforpair firstpropertyid secondpropertyid (count)\nomni_gettradehistoryforaddress \"address\" (count propertyid)\nomni_getactivations\nomni_getpayload \"txhash\"\nomni_getseedblocks startblock endblock\nomni_getcurrentconsensushash\nomni_decodetransaction \"rawtx\" (\"prevtxs\" height)\nomni_createrawtx_opreturn \"rawtx\" \"payload\"\nomni_createrawtx_multisig \"rawtx\" \"addpayload\" \"seed\" \"redemptionpayload\"\nomni_createrawtx_input \"rawtx\" \"txid\" n\nomni_createrawtx_reference \"rawtx\" \"destination\" (amount)\nomni_createrawtx_change \"rawtx\" \"prevtxs\" \"destination\" fee (position)\nomni_createpayload_simplesend propertyid \"amount\"\nomni_createpayload_sendall\nomni_createpayload_dexsell propertyidforsale \"amountforsale\" \"amountdesired\" paymentwindow \"minacceptfee\" action\nomni_createpayload_dexaccept propertyid \"amount\"\nomni_createpayload_sto propertyid \"amount\" (distributionproperty)\nomni_createpayload_issuancefixed ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" \"amount\"\nomni_createpayload_issuancecrowdsale ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\" propertyiddesired \"tokensperunit\" deadline earlybonus issuerpercentage\nomni_createpayload_issuancemanaged ecosystem typo previousid \"category\" \"subcategory\" \"name\" \"url\" \"data\"\nomni_createpayload_closecrowdsale\nomni_createpayload_grant propertyid \"amount\" (\"memo\")\nomni_createpayload_revoke propertyid \"amount\" (\"memo\")\nomni_createpayload_changeissuer propertyid\nomni_createpayload_trade propertyidforsale \"amountforsale\" propertyiddesired \"amountdesired\"\nomni_createpayload_canceltradesbyprice propertyidforsale \"amountforsale\" propertyiddesired \"amountdesired\"\nomni_createpayload_canceltradesbypair propertyidforsale propertyiddesired\nomni_createpayload_cancelalltrades\nomni_createpayload_enablefreezing propertyid\nomni_createpayload_disablefreezing propertyid\nomni_createpayload_freeze \"toaddress\" propertyid \"amount\"\nomni_createpayload_unfreeze \"toaddress\" propertyid \"amount\"\nomni_getfeecache propertyid\nomni_getfeetrigger\nomni_getfeeshare (\"address\" ecosystem)\nomni_getfeedistribution distributionid\nomni_getfeedistributions propertyid\nomni_setautocommit autocommit\nomni_rollback height ([\"hash\",...])\nomni_getblockinfo height\nomni_createagreement \"agttype\" \"agtcontent\" \"agtnonencryptedcontent\" \"agtkey\" \"agtencryptedcontent\"\nomni_sendagreement \"fromaddress\" \"toaddress\" \"agtid\" \"agtnonencryptedcontent\" \"agtkey\" \"agtencryptedcontent\"\ngetstraightpubkey \"srcaddress\""
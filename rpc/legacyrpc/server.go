@@ -17,19 +17,23 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/btcsuite/websocket"
 	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
 	"github.com/HcashOrg/hcd/hcjson"
 	"github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/chain"
 	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
 )
 
 type websocketClient struct {
+	outstanding   int32 // Requests being handled; accessed atomically.
 	conn          *websocket.Conn
 	authenticated bool
 	allRequests   chan []byte
@@ -60,17 +64,23 @@ func (c *websocketClient) send(b []byte) error {
 // Server holds the items the RPC server may need to access (auth,
 // config, shutdown, etc.)
 type Server struct {
-	httpServer   http.Server
-	walletLoader *loader.Loader
-	chainClient  *chain.RPCClient
-	handlerMu    sync.Mutex
+	httpServer    http.Server
+	walletLoader  *loader.Loader
+	walletLoaders *loader.Collection
+	chainClient   *chain.RPCClient
+	handlerMu     sync.Mutex
 
 	listeners []net.Listener
 	authsha   [sha256.Size]byte
 	upgrader  websocket.Upgrader
 
-	maxPostClients      int64 // Max concurrent HTTP POST clients.
-	maxWebsocketClients int64 // Max concurrent websocket clients.
+	maxPostClients      int64         // Max concurrent HTTP POST clients.
+	maxWebsocketClients int64         // Max concurrent websocket clients.
+	maxClientRequests   int32         // Max outstanding requests per websocket client.
+	idleTimeout         time.Duration // Disconnect idle websocket clients.
+	readOnly            bool          // Reject methods modifying the wallet (protected by handlerMu).
+	clientAuth          ClientAuthorizer
+	logControl          LogControl
 
 	wg      sync.WaitGroup
 	quit    chan struct{}
@@ -89,7 +99,11 @@ func jsonAuthFail(w http.ResponseWriter) {
 
 // NewServer creates a new server for serving legacy RPC client connections,
 // both HTTP POST and websocket.
-func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoader *loader.Loader, listeners []net.Listener) *Server {
+//
+// Requests are served by the default wallet of the collection, unless they are
+// made to the /wallet/<name> (HTTP POST) or /wallet/<name>/ws (websocket) paths
+// to be served by the named wallet.
+func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoaders *loader.Collection, listeners []net.Listener) *Server {
 	serveMux := http.NewServeMux()
 	const rpcAuthTimeoutSeconds = 10
 
@@ -101,9 +115,15 @@ func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoader *loader.L
 			// handshake within the allowed timeframe.
 			ReadTimeout: time.Second * rpcAuthTimeoutSeconds,
 		},
-		walletLoader:        walletLoader,
+		walletLoader:        walletLoaders.Default(),
+		walletLoaders:       walletLoaders,
 		maxPostClients:      opts.MaxPOSTClients,
 		maxWebsocketClients: opts.MaxWebsocketClients,
+		maxClientRequests:   int32(opts.MaxClientRequests),
+		idleTimeout:         opts.IdleTimeout,
+		readOnly:            opts.ReadOnly,
+		clientAuth:          opts.ClientAuth,
+		logControl:          opts.LogControl,
 		listeners:           listeners,
 		// A hash of the HTTP basic auth string is used for a constant
 		// time comparison.
@@ -117,7 +137,7 @@ func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoader *loader.L
 		activeNet:           activeNet,
 	}
 
-	serveMux.Handle("/", throttledFn(opts.MaxPOSTClients,
+	postHandler := throttledFn(opts.MaxPOSTClients,
 		func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Connection", "close")
 			w.Header().Set("Content-Type", "application/json")
@@ -129,12 +149,16 @@ func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoader *loader.L
 				jsonAuthFail(w)
 				return
 			}
+			r, ok := server.authorizeClientCert(w, r)
+			if !ok {
+				return
+			}
 			server.wg.Add(1)
 			server.postClientRPC(w, r)
 			server.wg.Done()
-		}))
+		})
 
-	serveMux.Handle("/ws", throttledFn(opts.MaxWebsocketClients,
+	wsHandler := throttledFn(opts.MaxWebsocketClients,
 		func(w http.ResponseWriter, r *http.Request) {
 			ctx := withRemoteAddr(r.Context(), r.RemoteAddr)
 			authenticated := false
@@ -151,6 +175,11 @@ func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoader *loader.L
 				jsonAuthFail(w)
 				return
 			}
+			r, ok := server.authorizeClientCert(w, r)
+			if !ok {
+				return
+			}
+			ctx = withRemoteAddr(r.Context(), r.RemoteAddr)
 
 			conn, err := server.upgrader.Upgrade(w, r, nil)
 			if err != nil {
@@ -160,7 +189,51 @@ func NewServer(opts *Options, activeNet *chaincfg.Params, walletLoader *loader.L
 			}
 			wsc := newWebsocketClient(conn, authenticated)
 			server.websocketClientRPC(ctx, wsc)
-		}))
+		})
+
+	serveMux.Handle("/", postHandler)
+	serveMux.Handle("/ws", wsHandler)
+	if opts.ServeSnapshots {
+		// Snapshots contain the entire wallet database, and are only
+		// served with the journal to clients which are not read-only.
+		replicationHandler := func(serve http.HandlerFunc) http.Handler {
+			return throttledFn(opts.MaxPOSTClients,
+				func(w http.ResponseWriter, r *http.Request) {
+					if err := server.checkAuthHeader(r); err != nil {
+						log.Warnf("Failed authentication attempt from client %s",
+							r.RemoteAddr)
+						jsonAuthFail(w)
+						return
+					}
+					r, ok := server.authorizeClientCert(w, r)
+					if !ok || readOnlyClient(r.Context()) {
+						if ok {
+							http.Error(w, "403 Forbidden.", http.StatusForbidden)
+						}
+						return
+					}
+					server.wg.Add(1)
+					serve(w, r)
+					server.wg.Done()
+				})
+		}
+		serveMux.Handle(loader.SnapshotPath, replicationHandler(server.serveSnapshot))
+		serveMux.Handle(loader.JournalPath, replicationHandler(server.serveJournal))
+	}
+	serveMux.HandleFunc("/wallet/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/wallet/")
+		h := postHandler
+		if strings.HasSuffix(name, "/ws") {
+			name = strings.TrimSuffix(name, "/ws")
+			h = wsHandler
+		}
+		l, ok := walletLoaders.Loader(name)
+		if !ok || name == loader.DefaultWalletName {
+			http.Error(w, "404 Wallet Not Found.", http.StatusNotFound)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(withWalletLoader(r.Context(), l)))
+	})
 
 	for _, lis := range listeners {
 		server.serve(lis)
@@ -213,10 +286,14 @@ func (s *Server) Stop() {
 	default:
 	}
 
-	// Stop the connected wallet and chain server, if any.
-	wallet, ok := s.walletLoader.LoadedWallet()
-	if ok {
-		wallet.Stop()
+	// Stop the connected wallets and chain server, if any.
+	var wallets []*wallet.Wallet
+	for _, name := range s.walletLoaders.Names() {
+		l, _ := s.walletLoaders.Loader(name)
+		if w, ok := l.LoadedWallet(); ok {
+			w.Stop()
+			wallets = append(wallets, w)
+		}
 	}
 	s.handlerMu.Lock()
 	chainClient := s.chainClient
@@ -238,10 +315,10 @@ func (s *Server) Stop() {
 	close(s.quit)
 	s.quitMtx.Unlock()
 
-	// First wait for the wallet and chain server to stop, if they
+	// First wait for the wallets and chain server to stop, if they
 	// were ever set.
-	if wallet != nil {
-		wallet.WaitForShutdown()
+	for _, w := range wallets {
+		w.WaitForShutdown()
 	}
 	if chainClient != nil {
 		chainClient.WaitForShutdown()
@@ -261,6 +338,23 @@ func (s *Server) SetChainServer(chainClient *chain.RPCClient) {
 	s.handlerMu.Unlock()
 }
 
+// SetReadOnly sets whether the server rejects requests for methods which
+// modify the wallet, such as after a replica wallet is promoted.
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.handlerMu.Lock()
+	s.readOnly = readOnly
+	s.handlerMu.Unlock()
+}
+
+// walletRPCClient returns the consensus RPC client synchronizing a wallet, or
+// nil when the wallet is not synchronized by a consensus RPC client.
+func walletRPCClient(w *wallet.Wallet) *hcrpcclient.Client {
+	if c, ok := w.ChainClient().(*chain.RPCClient); ok {
+		return c.Client
+	}
+	return nil
+}
+
 // handlerClosure creates a closure function for handling requests of the given
 // method.  This may be a request that is handled directly by hcwallet, or
 // a chain server request that is handled by passing the request down to hcd.
@@ -271,13 +365,45 @@ func (s *Server) SetChainServer(chainClient *chain.RPCClient) {
 func (s *Server) handlerClosure(ctx context.Context, request *hcjson.Request) lazyHandler {
 	log.Infof("RPC method %v invoked by client %v", request.Method, remoteAddr(ctx))
 
-	wallet, _ := s.walletLoader.LoadedWallet()
+	l := walletLoader(ctx)
+	if l == nil {
+		l = s.walletLoader
+	}
+	return func() (interface{}, *hcjson.RPCError) {
+		// The wallet is looked up and used while it is held, so replica
+		// wallets are not replaced while serving requests.  Promotions
+		// wait for the follower, which may be waiting to replace the
+		// wallet, and are served without holding it.
+		if request.Method != "promotereplica" {
+			release := l.HoldWallet()
+			defer release()
+		}
+		return s.walletHandler(ctx, request)()
+	}
+}
+
+// walletHandler creates the handler of a request for the loaded wallet.  See
+// handlerClosure for details.
+func (s *Server) walletHandler(ctx context.Context, request *hcjson.Request) lazyHandler {
 	s.handlerMu.Lock()
 	chainClient := s.chainClient
+	readOnly := s.readOnly
 	s.handlerMu.Unlock()
 
+	var wallet *wallet.Wallet
 	var rpcClient *hcrpcclient.Client
-	if chainClient != nil {
+	if l := walletLoader(ctx); l != nil {
+		// Named wallets are synchronized by their own chain client,
+		// which must also be used by their handlers so notifications,
+		// such as those of rescans, are delivered to the right wallet.
+		wallet, _ = l.LoadedWallet()
+		if wallet != nil && request.Method != "help" {
+			rpcClient = walletRPCClient(wallet)
+		}
+	} else {
+		wallet, _ = s.walletLoader.LoadedWallet()
+	}
+	if rpcClient == nil && chainClient != nil {
 		// The "help" RPC must use an HTTP POST client when calling down to hcd
 		// for additional help methods.  This is required to avoid including
 		// websocket-only requests in the help, which are not callable by wallet
@@ -290,7 +416,53 @@ func (s *Server) handlerClosure(ctx context.Context, request *hcjson.Request) la
 		}
 	}
 
-	return lazyApplyHandler(request, wallet, rpcClient)
+	if readOnly && !readOnlyMethods[request.Method] {
+		return func() (interface{}, *hcjson.RPCError) {
+			return nil, &ErrReadOnlyReplica
+		}
+	}
+	if readOnlyClient(ctx) && !readOnlyClientMethods[request.Method] {
+		return func() (interface{}, *hcjson.RPCError) {
+			return nil, &ErrReadOnlyClient
+		}
+	}
+	version := resultVersion(ctx)
+	if jsonErr := checkResultVersion(version); jsonErr != nil {
+		return func() (interface{}, *hcjson.RPCError) {
+			return nil, jsonErr
+		}
+	}
+
+	l := walletLoader(ctx)
+	if l == nil {
+		l = s.walletLoader
+	}
+	handler := lazyApplyHandler(request, wallet, rpcClient, l, s.logControl)
+
+	// Results with layouts which differ by result version are returned in
+	// the layout of the version requested by the client.
+	applied := handler
+	handler = func() (interface{}, *hcjson.RPCError) {
+		res, jsonErr := applied()
+		if r, ok := res.(versionedResult); ok {
+			res = r.forVersion(version)
+			if e, ok := res.(*hcjson.RPCError); ok {
+				return nil, e
+			}
+		}
+		return res, jsonErr
+	}
+	if wallet == nil {
+		return handler
+	}
+
+	// Record the request as in progress so rescans can be paused while the
+	// wallet is busy serving clients.
+	return func() (interface{}, *hcjson.RPCError) {
+		wallet.RPCStarted()
+		defer wallet.RPCFinished()
+		return handler()
+	}
 }
 
 // ErrNoAuth represents an error where authentication could not succeed
@@ -317,6 +489,39 @@ func (s *Server) checkAuthHeader(r *http.Request) error {
 	return nil
 }
 
+// authorizeClientCert checks the TLS client certificate of a request when
+// client certificates are authorized.  It responds with an HTTP 403 and
+// returns false if the certificate is not permitted.  Otherwise, the request
+// is returned with a context recording whether the client is restricted to the
+// read-only methods.  Requests made over unix domain sockets, which are served
+// without TLS, are not checked.
+func (s *Server) authorizeClientCert(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if s.clientAuth == nil {
+		return r, true
+	}
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok &&
+		addr.Network() == "unix" {
+		return r, true
+	}
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		log.Warnf("Client %s presented no TLS certificate", r.RemoteAddr)
+		http.Error(w, "403 Forbidden.", http.StatusForbidden)
+		return nil, false
+	}
+	cert := r.TLS.PeerCertificates[0]
+	readOnly, err := s.clientAuth.AuthorizeClient(cert)
+	if err != nil {
+		log.Warnf("Rejected TLS certificate %q of client %s: %v",
+			cert.Subject.CommonName, r.RemoteAddr, err)
+		http.Error(w, "403 Forbidden.", http.StatusForbidden)
+		return nil, false
+	}
+	if readOnly {
+		r = r.WithContext(withReadOnlyClient(r.Context()))
+	}
+	return r, true
+}
+
 // throttledFn wraps an http.HandlerFunc with throttling of concurrent active
 // clients by responding with an HTTP 429 when the threshold is crossed.
 func throttledFn(threshold int64, f http.HandlerFunc) http.Handler {
@@ -373,17 +578,27 @@ func (s *Server) invalidAuth(req *hcjson.Request) bool {
 }
 
 func (s *Server) websocketClientRead(ctx context.Context, wsc *websocketClient) {
+	defer close(wsc.allRequests)
 	for {
 		_, request, err := wsc.conn.ReadMessage()
 		if err != nil {
-			if err != io.EOF && err != io.ErrUnexpectedEOF {
-				log.Warnf("Websocket receive failed from client %s: %v",
-					remoteAddr(ctx), err)
+			select {
+			case <-wsc.quit:
+				// The connection was closed after disconnecting
+				// the client.
+			default:
+				if err != io.EOF && err != io.ErrUnexpectedEOF {
+					log.Warnf("Websocket receive failed from client %s: %v",
+						remoteAddr(ctx), err)
+				}
 			}
-			close(wsc.allRequests)
-			break
+			return
+		}
+		select {
+		case wsc.allRequests <- request:
+		case <-wsc.quit:
+			return
 		}
-		wsc.allRequests <- request
 	}
 }
 
@@ -393,6 +608,19 @@ func (s *Server) websocketClientRespond(ctx context.Context, wsc *websocketClien
 	// WebsocketClientRead (which sends to the allRequests chan) not closing
 	// allRequests during shutdown if the remote websocket client is still
 	// connected.
+	//
+	// tipChanges is closed to stop the client's tip change notifications.
+	var tipChanges chan struct{}
+
+	// The idle timer is restarted by each request, and disconnects the
+	// client when it expires while no requests are outstanding.
+	var idle <-chan time.Time
+	var idleTimer *time.Timer
+	if s.idleTimeout > 0 {
+		idleTimer = time.NewTimer(s.idleTimeout)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
 out:
 	for {
 		select {
@@ -401,6 +629,12 @@ out:
 				// client disconnected
 				break out
 			}
+			if idleTimer != nil {
+				if !idleTimer.Stop() {
+					<-idleTimer.C
+				}
+				idleTimer.Reset(s.idleTimeout)
+			}
 
 			var req hcjson.Request
 			err := json.Unmarshal(reqBytes, &req)
@@ -463,6 +697,19 @@ out:
 			case "stop":
 				log.Infof("RPC method stop invoked by client %s",
 					remoteAddr(ctx))
+				if readOnlyClient(ctx) {
+					resp := makeResponse(req.ID, nil, &ErrReadOnlyClient)
+					mresp, err := json.Marshal(resp)
+					// Expected to never fail.
+					if err != nil {
+						panic(err)
+					}
+					err = wsc.send(mresp)
+					if err != nil {
+						break out
+					}
+					continue
+				}
 				resp := makeResponse(req.ID,
 					"hcwallet stopping.", nil)
 				mresp, err := json.Marshal(resp)
@@ -477,9 +724,61 @@ out:
 				s.requestProcessShutdown()
 				break out
 
+			case "notifytipchanges", "stopnotifytipchanges":
+				var err error
+				switch {
+				case req.Method == "stopnotifytipchanges":
+					if tipChanges != nil {
+						close(tipChanges)
+						tipChanges = nil
+					}
+				case tipChanges != nil:
+					// Already subscribed.
+				default:
+					w, ok := s.websocketWallet(ctx)
+					if !ok {
+						err = &ErrUnloadedWallet
+						break
+					}
+					// Register the client before responding so no
+					// change following the response is missed.
+					n := w.NtfnServer.MainTipChangedNotifications()
+					tipChanges = make(chan struct{})
+					wsc.wg.Add(1)
+					go s.websocketNotifyTipChanges(ctx, wsc, n, tipChanges)
+				}
+				resp := makeResponse(req.ID, nil, err)
+				mresp, err := json.Marshal(resp)
+				// Expected to never fail.
+				if err != nil {
+					panic(err)
+				}
+				err = wsc.send(mresp)
+				if err != nil {
+					break out
+				}
+
 			default:
+				if s.maxClientRequests > 0 &&
+					atomic.LoadInt32(&wsc.outstanding) >= s.maxClientRequests {
+					log.Warnf("Reached limit of %d outstanding requests "+
+						"from client %s", s.maxClientRequests, remoteAddr(ctx))
+					resp := makeResponse(req.ID, nil, &ErrTooManyRequests)
+					mresp, err := json.Marshal(resp)
+					// Expected to never fail.
+					if err != nil {
+						panic(err)
+					}
+					err = wsc.send(mresp)
+					if err != nil {
+						break out
+					}
+					continue
+				}
+
 				req := req // Copy for the closure
-				f := s.handlerClosure(ctx, &req)
+				f := s.handlerClosure(withRequestOptions(ctx, reqBytes), &req)
+				atomic.AddInt32(&wsc.outstanding, 1)
 				wsc.wg.Add(1)
 				go func() {
 					resp, jsonErr := f()
@@ -490,21 +789,88 @@ out:
 					} else {
 						_ = wsc.send(mresp)
 					}
+					atomic.AddInt32(&wsc.outstanding, -1)
 					wsc.wg.Done()
 				}()
 			}
 
+		case <-idle:
+			if atomic.LoadInt32(&wsc.outstanding) != 0 {
+				idleTimer.Reset(s.idleTimeout)
+				continue
+			}
+			log.Infof("Disconnecting websocket client %s after %v idle",
+				remoteAddr(ctx), s.idleTimeout)
+			break out
+
 		case <-s.quit:
 			break out
 		}
 	}
 
+	if tipChanges != nil {
+		close(tipChanges)
+	}
+
 	// allow client to disconnect after all handler goroutines are done
 	wsc.wg.Wait()
 	close(wsc.responses)
 	s.wg.Done()
 }
 
+// websocketWallet returns the wallet served to a websocket client: the named
+// wallet of the connection's path, or the wallet of the default loader.
+func (s *Server) websocketWallet(ctx context.Context) (*wallet.Wallet, bool) {
+	if l := walletLoader(ctx); l != nil {
+		return l.LoadedWallet()
+	}
+	return s.walletLoader.LoadedWallet()
+}
+
+// websocketNotifyTipChanges sends tipchanged notifications to a websocket
+// client for each change to the main chain tip until stop is closed or the
+// client disconnects.
+func (s *Server) websocketNotifyTipChanges(ctx context.Context, wsc *websocketClient,
+	n wallet.MainTipChangedNotificationsClient, stop <-chan struct{}) {
+
+	defer wsc.wg.Done()
+	defer n.Done()
+
+	hashStrings := func(hashes []*chainhash.Hash) []string {
+		strs := make([]string, len(hashes))
+		for i, h := range hashes {
+			strs[i] = h.String()
+		}
+		return strs
+	}
+
+	for {
+		select {
+		case v, ok := <-n.C:
+			if !ok {
+				log.Warnf("Stopped tipchanged notifications to client %s "+
+					"which fell behind", remoteAddr(ctx))
+				return
+			}
+			ntfn := hcjson.NewTipChangedNtfn(hashStrings(v.AttachedBlocks),
+				hashStrings(v.DetachedBlocks), v.NewHeight)
+			mntfn, err := hcjson.MarshalCmd(nil, ntfn)
+			if err != nil {
+				log.Errorf("Unable to marshal tipchanged notification to "+
+					"client %s: %v", remoteAddr(ctx), err)
+				continue
+			}
+			err = wsc.send(mntfn)
+			if err != nil {
+				return
+			}
+
+		case <-stop:
+			return
+		}
+	}
+}
+
 func (s *Server) websocketClientSend(ctx context.Context, wsc *websocketClient) {
 	const deadline time.Duration = 2 * time.Second
 out:
@@ -559,6 +925,14 @@ func (s *Server) websocketClientRPC(ctx context.Context, wsc *websocketClient) {
 	go s.websocketClientSend(ctx, wsc)
 
 	<-wsc.quit
+
+	// Close the connection so the read goroutine does not outlive clients
+	// disconnected by the server.
+	err := wsc.conn.Close()
+	if err != nil {
+		log.Warnf("Cannot close websocket connection of client %s: %v",
+			remoteAddr(ctx), err)
+	}
 }
 
 // maxRequestSize specifies the maximum number of bytes in the request body
@@ -621,9 +995,14 @@ func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	case "stop":
 		log.Infof("RPC method stop invoked by client %s", r.RemoteAddr)
+		if readOnlyClient(ctx) {
+			jsonErr = &ErrReadOnlyClient
+			break
+		}
 		stop = true
 		res = "hcwallet stopping"
 	default:
+		ctx = withRequestOptions(ctx, rpcRequest)
 		res, jsonErr = s.handlerClosure(ctx, &req)()
 	}
 
@@ -0,0 +1,297 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+)
+
+// testLoader returns a loader of a new simnet wallet.
+func testLoader(t *testing.T) (*loader.Loader, func()) {
+	dir, err := ioutil.TempDir("", "legacyrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := loader.NewLoader(&chaincfg.SimNetParams, dir, &loader.StakeOptions{},
+		20, false, txauthor.TxLimits{}, false, 0.001, false)
+	_, err = l.CreateNewWallet([]byte("public"), []byte("private"),
+		bytes.Repeat([]byte{1}, 32), nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return l, func() {
+		l.UnloadWallet()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestTipChangesWebsocketOnly(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+
+	s := &Server{walletLoader: l}
+	for _, method := range []string{"notifytipchanges", "stopnotifytipchanges"} {
+		req := &hcjson.Request{Jsonrpc: "1.0", Method: method}
+		_, jsonErr := s.handlerClosure(context.Background(), req)()
+		if jsonErr == nil || *jsonErr != ErrWebsocketOnly {
+			t.Errorf("%s: expected websocket only error, got %v", method,
+				jsonErr)
+		}
+	}
+}
+
+// waitNotifyTipChanges waits for a websocketNotifyTipChanges call to return.
+func waitNotifyTipChanges(t *testing.T, wsc *websocketClient) {
+	done := make(chan struct{})
+	go func() {
+		wsc.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tip change notifications were not stopped")
+	}
+}
+
+func TestWebsocketNotifyTipChanges(t *testing.T) {
+	w, teardown := testOmniWallet(t)
+	defer teardown()
+
+	s := new(Server)
+	ctx := withRemoteAddr(context.Background(), "test")
+
+	wsc := newWebsocketClient(nil, true)
+	n := w.NtfnServer.MainTipChangedNotifications()
+	stop := make(chan struct{})
+	wsc.wg.Add(1)
+	go s.websocketNotifyTipChanges(ctx, wsc, n, stop)
+
+	attached := chainhash.Hash{1}
+	detached := chainhash.Hash{2}
+	n.C <- &wallet.MainTipChangedNotification{
+		AttachedBlocks: []*chainhash.Hash{&attached},
+		DetachedBlocks: []*chainhash.Hash{&detached},
+		NewHeight:      7,
+	}
+	var mntfn []byte
+	select {
+	case mntfn = <-wsc.responses:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no tipchanged notification was sent")
+	}
+	var req hcjson.Request
+	if err := json.Unmarshal(mntfn, &req); err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := hcjson.UnmarshalCmd(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hcjson.NewTipChangedNtfn([]string{attached.String()},
+		[]string{detached.String()}, 7)
+	if !reflect.DeepEqual(cmd, want) {
+		t.Errorf("notification %+v, want %+v", cmd, want)
+	}
+
+	close(stop)
+	waitNotifyTipChanges(t, wsc)
+
+	// Clients which fell behind are disconnected by the notification
+	// server, closing their channel, which stops the notifications.
+	wsc = newWebsocketClient(nil, true)
+	n = w.NtfnServer.MainTipChangedNotifications()
+	disconnected := n
+	disconnected.Done()
+	wsc.wg.Add(1)
+	go s.websocketNotifyTipChanges(ctx, wsc, n, make(chan struct{}))
+	waitNotifyTipChanges(t, wsc)
+}
+
+// readResponse reads the next response sent to a websocket client.
+func readResponse(t *testing.T, wsc *websocketClient) *hcjson.Response {
+	select {
+	case mresp, ok := <-wsc.responses:
+		if !ok {
+			t.Fatal("websocket client was disconnected")
+		}
+		var resp hcjson.Response
+		if err := json.Unmarshal(mresp, &resp); err != nil {
+			t.Fatal(err)
+		}
+		return &resp
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response was sent")
+	}
+	return nil
+}
+
+func TestWebsocketClientRequestLimit(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+
+	s := &Server{
+		walletLoader:      l,
+		maxClientRequests: 1,
+		quit:              make(chan struct{}),
+	}
+	ctx := withRemoteAddr(context.Background(), "test")
+	wsc := newWebsocketClient(nil, true)
+	s.wg.Add(1)
+	go s.websocketClientRespond(ctx, wsc)
+	defer func() {
+		close(s.quit)
+		s.wg.Wait()
+	}()
+
+	request := []byte(`{"jsonrpc":"1.0","method":"walletislocked","params":[],"id":1}`)
+
+	// Requests over the limit are answered without being handled.
+	atomic.StoreInt32(&wsc.outstanding, 1)
+	wsc.allRequests <- request
+	resp := readResponse(t, wsc)
+	if resp.Error == nil || *resp.Error != ErrTooManyRequests {
+		t.Errorf("request over the limit returned error %v, want %v",
+			resp.Error, &ErrTooManyRequests)
+	}
+
+	atomic.StoreInt32(&wsc.outstanding, 0)
+	wsc.allRequests <- request
+	resp = readResponse(t, wsc)
+	if resp.Error != nil || string(resp.Result) != "false" {
+		t.Errorf("request within the limit returned result %s and error %v",
+			resp.Result, resp.Error)
+	}
+}
+
+func TestWebsocketClientIdleTimeout(t *testing.T) {
+	l, teardown := testLoader(t)
+	defer teardown()
+
+	const idleTimeout = 50 * time.Millisecond
+	s := &Server{
+		walletLoader: l,
+		idleTimeout:  idleTimeout,
+		quit:         make(chan struct{}),
+	}
+	ctx := withRemoteAddr(context.Background(), "test")
+	wsc := newWebsocketClient(nil, true)
+	s.wg.Add(1)
+	go s.websocketClientRespond(ctx, wsc)
+
+	// Clients are not disconnected while their requests are outstanding.
+	atomic.StoreInt32(&wsc.outstanding, 1)
+	select {
+	case <-wsc.responses:
+		t.Fatal("client with an outstanding request was disconnected")
+	case <-time.After(4 * idleTimeout):
+	}
+
+	atomic.StoreInt32(&wsc.outstanding, 0)
+	select {
+	case _, ok := <-wsc.responses:
+		if ok {
+			t.Fatal("unexpected response to idle client")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle client was not disconnected")
+	}
+	s.wg.Wait()
+}
+
+// clientAuthFunc implements ClientAuthorizer with a function.
+type clientAuthFunc func(*x509.Certificate) (bool, error)
+
+func (f clientAuthFunc) AuthorizeClient(cert *x509.Certificate) (bool, error) {
+	return f(cert)
+}
+
+func TestAuthorizeClientCert(t *testing.T) {
+	auth := clientAuthFunc(func(cert *x509.Certificate) (bool, error) {
+		switch cert.Subject.CommonName {
+		case "admin":
+			return false, nil
+		case "monitor":
+			return true, nil
+		}
+		return false, errors.New("unknown certificate")
+	})
+	request := func(name string, unix bool) *http.Request {
+		r := httptest.NewRequest("POST", "/", nil)
+		if unix {
+			addr := &net.UnixAddr{Name: "/tmp/hcwallet.sock", Net: "unix"}
+			r = r.WithContext(context.WithValue(r.Context(),
+				http.LocalAddrContextKey, addr))
+		}
+		if name != "" {
+			r.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{
+					Subject: pkix.Name{CommonName: name},
+				}},
+			}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		auth       ClientAuthorizer
+		cert       string
+		unix       bool
+		authorized bool
+		readOnly   bool
+	}{
+		{"no client authorization", nil, "", false, true, false},
+		{"full", auth, "admin", false, true, false},
+		{"read-only", auth, "monitor", false, true, true},
+		{"unknown certificate", auth, "guest", false, false, false},
+		{"no certificate", auth, "", false, false, false},
+		{"unix domain socket", auth, "", true, true, false},
+	}
+	for _, test := range tests {
+		s := &Server{clientAuth: test.auth}
+		rec := httptest.NewRecorder()
+		r, ok := s.authorizeClientCert(rec, request(test.cert, test.unix))
+		if ok != test.authorized {
+			t.Errorf("%s: authorized %v, want %v", test.name, ok,
+				test.authorized)
+			continue
+		}
+		if !ok {
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("%s: status %d, want %d", test.name, rec.Code,
+					http.StatusForbidden)
+			}
+			continue
+		}
+		if readOnlyClient(r.Context()) != test.readOnly {
+			t.Errorf("%s: read-only client %v, want %v", test.name,
+				readOnlyClient(r.Context()), test.readOnly)
+		}
+	}
+}
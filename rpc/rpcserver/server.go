@@ -60,9 +60,9 @@ import (
 
 // Public API version constants
 const (
-	semverString = "4.25.0"
+	semverString = "4.31.0"
 	semverMajor  = 4
-	semverMinor  = 25
+	semverMinor  = 31
 	semverPatch  = 0
 )
 
@@ -105,6 +105,8 @@ func errorCode(err error) codes.Code {
 	switch err.(type) {
 	case txauthor.InputSourceError:
 		return codes.ResourceExhausted
+	case *wallet.ScriptValidationError:
+		return codes.FailedPrecondition
 	}
 
 	switch err {
@@ -194,6 +196,13 @@ type votingServer struct {
 	wallet *wallet.Wallet
 }
 
+// ticketServer provides RPC clients with notifications of the lifecycle events
+// of wallet tickets.
+type ticketServer struct {
+	ready  uint32 // atomic
+	wallet *wallet.Wallet
+}
+
 // messageVerificationServer provides RPC clients with the ability to verify
 // that a message was signed using the private key of a particular address.
 type messageVerificationServer struct{}
@@ -212,6 +221,7 @@ var (
 	ticketBuyerService         ticketbuyerServer
 	agendaService              agendaServer
 	votingService              votingServer
+	ticketService              ticketServer
 	messageVerificationService messageVerificationServer
 	decodeMessageService       decodeMessageServer
 )
@@ -226,6 +236,7 @@ func RegisterServices(server *grpc.Server) {
 	pb.RegisterTicketBuyerServiceServer(server, &ticketBuyerService)
 	pb.RegisterAgendaServiceServer(server, &agendaService)
 	pb.RegisterVotingServiceServer(server, &votingService)
+	pb.RegisterTicketServiceServer(server, &ticketService)
 	pb.RegisterMessageVerificationServiceServer(server, &messageVerificationService)
 	pb.RegisterDecodeMessageServiceServer(server, &decodeMessageService)
 }
@@ -238,6 +249,7 @@ var serviceMap = map[string]interface{}{
 	"walletrpc.TicketBuyerService":         &ticketBuyerService,
 	"walletrpc.AgendaService":              &agendaService,
 	"walletrpc.VotingService":              &votingService,
+	"walletrpc.TicketService":              &ticketService,
 	"walletrpc.MessageVerificationService": &messageVerificationService,
 	"walletrpc.DecodeMessageService":       &decodeMessageService,
 }
@@ -285,7 +297,7 @@ func (s *walletServer) checkReady() bool {
 
 // requireChainClient checks whether the wallet has been associated with the
 // consensus server RPC client, returning a gRPC error when it is not.
-func (s *walletServer) requireChainClient() (*hcrpcclient.Client, error) {
+func (s *walletServer) requireChainClient() (wallet.ChainClient, error) {
 	chainClient := s.wallet.ChainClient()
 	if chainClient == nil {
 		return nil, status.Errorf(codes.FailedPrecondition,
@@ -361,9 +373,18 @@ func (s *walletServer) Rescan(req *pb.RescanRequest, svr pb.WalletService_Rescan
 		return status.Errorf(codes.InvalidArgument, "begin height must be non-negative")
 	}
 
+	// Rescans from the genesis block begin at the wallet birthday instead.
+	beginHeight := req.BeginHeight
+	if beginHeight == 0 {
+		beginHeight, err = s.wallet.BirthdayHeight()
+		if err != nil {
+			return translateError(err)
+		}
+	}
+
 	progress := make(chan wallet.RescanProgress, 1)
 	cancel := make(chan struct{})
-	go s.wallet.RescanProgressFromHeight(chainClient, req.BeginHeight, progress, cancel)
+	go s.wallet.RescanProgressFromHeight(chainClient, beginHeight, progress, cancel)
 
 	ctxDone := svr.Context().Done()
 	for {
@@ -999,6 +1020,50 @@ func (s *walletServer) GetTransactions(req *pb.GetTransactionsRequest,
 	return nil
 }
 
+func (s *walletServer) GetTransactionsStream(req *pb.GetTransactionsStreamRequest,
+	server pb.WalletService_GetTransactionsStreamServer) error {
+
+	if req.StartingBlockHeight < -1 || req.EndingBlockHeight < -1 {
+		return status.Errorf(codes.InvalidArgument,
+			"block heights may not be less than -1")
+	}
+
+	ctx := server.Context()
+	rangeFn := func(block *udb.BlockMeta, txs []wallet.TransactionSummary) (bool, error) {
+		var resp *pb.GetTransactionsStreamResponse
+		if block != nil {
+			resp = &pb.GetTransactionsStreamResponse{
+				MinedTransactions: &pb.BlockDetails{
+					Hash:         block.Hash[:],
+					Height:       block.Height,
+					Timestamp:    block.Time.Unix(),
+					Transactions: marshalTransactionDetailsSlice(txs),
+				},
+			}
+		} else {
+			resp = &pb.GetTransactionsStreamResponse{
+				UnminedTransactions: marshalTransactionDetailsSlice(txs),
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		default:
+			err := server.Send(resp)
+			return err != nil, err
+		}
+	}
+
+	err := wallet.UnstableAPI(s.wallet).RangeTransactionSummaries(
+		req.StartingBlockHeight, req.EndingBlockHeight, req.Accounts, rangeFn)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
 func (s *walletServer) GetTickets(req *pb.GetTicketsRequest,
 	server pb.WalletService_GetTicketsServer) error {
 
@@ -1212,6 +1277,24 @@ func (s *walletServer) PublishTransaction(ctx context.Context, req *pb.PublishTr
 	return &pb.PublishTransactionResponse{TransactionHash: txHash[:]}, nil
 }
 
+// AbandonTransaction removes an unmined transaction and all unmined
+// transactions spending from it from the wallet.
+func (s *walletServer) AbandonTransaction(ctx context.Context, req *pb.AbandonTransactionRequest) (
+	*pb.AbandonTransactionResponse, error) {
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction_hash has invalid length")
+	}
+
+	err = s.wallet.AbandonTransaction(txHash)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.AbandonTransactionResponse{}, nil
+}
+
 // PurchaseTickets purchases tickets from the wallet.
 func (s *walletServer) PurchaseTickets(ctx context.Context,
 	req *pb.PurchaseTicketsRequest) (*pb.PurchaseTicketsResponse, error) {
@@ -1284,7 +1367,7 @@ func (s *walletServer) PurchaseTickets(ctx context.Context,
 		return nil, translateError(err)
 	}
 
-	resp, err := s.wallet.PurchaseTickets(0, spendLimit, minConf,
+	res, err := s.wallet.PurchaseTickets(0, spendLimit, minConf,
 		ticketAddr, req.Account, numTickets, poolAddr, req.PoolFees,
 		expiry, txFee, ticketFee)
 	if err != nil {
@@ -1292,9 +1375,20 @@ func (s *walletServer) PurchaseTickets(ctx context.Context,
 			"Unable to purchase tickets: %v", err)
 	}
 
-	hashes := marshalHashes(resp)
+	failures := make([]*pb.PurchaseTicketsResponse_TicketFailure, len(res.Failures))
+	for i, f := range res.Failures {
+		failures[i] = &pb.PurchaseTicketsResponse_TicketFailure{
+			Index: uint32(f.Index),
+			Error: f.Err.Error(),
+		}
+	}
 
-	return &pb.PurchaseTicketsResponse{TicketHashes: hashes}, nil
+	return &pb.PurchaseTicketsResponse{
+		TicketHashes: marshalHashes(res.TicketHashes),
+		SplitTxHash:  res.SplitTx[:],
+		Failures:     failures,
+		TotalSpent:   int64(res.TotalSpent),
+	}, nil
 }
 
 func (s *walletServer) RevokeTickets(ctx context.Context, req *pb.RevokeTicketsRequest) (*pb.RevokeTicketsResponse, error) {
@@ -1312,7 +1406,9 @@ func (s *walletServer) RevokeTickets(ctx context.Context, req *pb.RevokeTicketsR
 		return nil, translateError(err)
 	}
 
-	err = s.wallet.RevokeTickets(chainClient)
+	// Revocations pay the fee fixed by the ticket, which may exceed the
+	// consensus server's high fee limit.
+	err = s.wallet.RevokeTickets(chainClient, true)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -1597,6 +1693,11 @@ func marshalHashes(v []*chainhash.Hash) [][]byte {
 	return hashes
 }
 
+// errNotificationsBehind is returned by notification streams when the wallet
+// disconnects the stream for falling too far behind its notifications.
+var errNotificationsBehind = status.Errorf(codes.ResourceExhausted,
+	"notification stream fell behind and was disconnected")
+
 func (s *walletServer) TransactionNotifications(req *pb.TransactionNotificationsRequest,
 	svr pb.WalletService_TransactionNotificationsServer) error {
 
@@ -1606,7 +1707,10 @@ func (s *walletServer) TransactionNotifications(req *pb.TransactionNotifications
 	ctxDone := svr.Context().Done()
 	for {
 		select {
-		case v := <-n.C:
+		case v, ok := <-n.C:
+			if !ok {
+				return errNotificationsBehind
+			}
 			resp := pb.TransactionNotificationsResponse{
 				AttachedBlocks:           marshalBlocks(v.AttachedBlocks),
 				DetachedBlocks:           marshalHashes(v.DetachedBlocks),
@@ -1633,7 +1737,10 @@ func (s *walletServer) AccountNotifications(req *pb.AccountNotificationsRequest,
 	ctxDone := svr.Context().Done()
 	for {
 		select {
-		case v := <-n.C:
+		case v, ok := <-n.C:
+			if !ok {
+				return errNotificationsBehind
+			}
 			resp := pb.AccountNotificationsResponse{
 				AccountNumber:    v.AccountNumber,
 				AccountName:      v.AccountName,
@@ -1652,6 +1759,35 @@ func (s *walletServer) AccountNotifications(req *pb.AccountNotificationsRequest,
 	}
 }
 
+func (s *walletServer) MainTipChangedNotifications(req *pb.MainTipChangedNotificationsRequest,
+	svr pb.WalletService_MainTipChangedNotificationsServer) error {
+
+	n := s.wallet.NtfnServer.MainTipChangedNotifications()
+	defer n.Done()
+
+	ctxDone := svr.Context().Done()
+	for {
+		select {
+		case v, ok := <-n.C:
+			if !ok {
+				return errNotificationsBehind
+			}
+			resp := pb.MainTipChangedNotificationsResponse{
+				AttachedBlocks: marshalHashes(v.AttachedBlocks),
+				DetachedBlocks: marshalHashes(v.DetachedBlocks),
+				NewHeight:      v.NewHeight,
+			}
+			err := svr.Send(&resp)
+			if err != nil {
+				return translateError(err)
+			}
+
+		case <-ctxDone:
+			return nil
+		}
+	}
+}
+
 func (s *walletServer) ConfirmationNotifications(svr pb.WalletService_ConfirmationNotificationsServer) error {
 	c := s.wallet.NtfnServer.ConfirmationNotifications(svr.Context())
 	errOut := make(chan error, 2)
@@ -1874,7 +2010,25 @@ func (s *loaderServer) CreateWallet(ctx context.Context, req *pb.CreateWalletReq
 		return nil, status.Errorf(codes.InvalidArgument, "seed is a required parameter")
 	}
 
-	_, err := s.loader.CreateNewWallet(pubPassphrase, req.PrivatePassphrase, req.Seed)
+	// The birthday is optional, and may be given either as a block height or
+	// as the time the seed was created.  Without a birthday, the wallet is
+	// synced from the genesis block.
+	var birthday *udb.Birthday
+	switch {
+	case req.BirthdayHeight < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "negative birthday height")
+	case req.BirthdayTime < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "negative birthday time")
+	case req.BirthdayHeight != 0 && req.BirthdayTime != 0:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"birthday height and time are mutually exclusive")
+	case req.BirthdayHeight != 0:
+		birthday = &udb.Birthday{Height: req.BirthdayHeight}
+	case req.BirthdayTime != 0:
+		birthday = &udb.Birthday{Time: time.Unix(req.BirthdayTime, 0), Height: -1}
+	}
+
+	_, err := s.loader.CreateNewWallet(pubPassphrase, req.PrivatePassphrase, req.Seed, birthday)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -1921,6 +2075,22 @@ func (s *loaderServer) CloseWallet(ctx context.Context, req *pb.CloseWalletReque
 		return nil, translateError(err)
 	}
 
+	// Closing the wallet stops the consensus RPC client it synchronized
+	// with.  Release the client so another may be attached to a wallet
+	// opened later.
+	s.mu.Lock()
+	rpcClient := s.rpcClient
+	s.rpcClient = nil
+	s.mu.Unlock()
+	if rpcClient != nil {
+		rpcClient.Stop()
+		rpcClient.WaitForShutdown()
+		s.loader.SetChainClient(nil)
+		if s.server != nil {
+			s.server.SetChainServer(nil)
+		}
+	}
+
 	return &pb.CloseWalletResponse{}, nil
 }
 
@@ -1936,13 +2106,6 @@ func (s *loaderServer) StartConsensusRpc(ctx context.Context, req *pb.StartConse
 		return nil, status.Errorf(codes.FailedPrecondition, "RPC client already created")
 	}
 
-	networkAddress, err := cfgutil.NormalizeAddress(req.NetworkAddress,
-		s.activeNet.JSONRPCClientPort)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument,
-			"Network address is ill-formed: %v", err)
-	}
-
 	// Error if the wallet is already syncing with the network.
 	wallet, walletLoaded := s.loader.LoadedWallet()
 	if walletLoaded && wallet.SynchronizingToNetwork() {
@@ -1950,8 +2113,88 @@ func (s *loaderServer) StartConsensusRpc(ctx context.Context, req *pb.StartConse
 			"wallet is loaded and already synchronizing")
 	}
 
-	rpcClient, err := chain.NewRPCClient(s.activeNet.Params, networkAddress, req.Username,
-		string(req.Password), req.Certificate, len(req.Certificate) == 0, 1)
+	rpcClient, err := s.startConsensusRPC(req.NetworkAddress, req.Username,
+		req.Password, req.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rpcClient = rpcClient
+	s.loader.SetChainClient(rpcClient.Client)
+	if s.server != nil {
+		s.server.SetChainServer(rpcClient)
+	}
+	wallet.SynchronizeRPC(rpcClient)
+	return &pb.StartConsensusRpcResponse{}, nil
+}
+
+// AttachChainRpc connects a new consensus RPC client and synchronizes the
+// loaded wallet with it, replacing the client of an earlier StartConsensusRpc
+// or AttachChainRpc call.  The previous client is only stopped once the new
+// connection succeeds, and the wallet's handling of its notifications finishes
+// before the new client is associated.  A running ticket buyer is restarted to
+// query the new client, and remains stopped if it can not be restarted.
+func (s *loaderServer) AttachChainRpc(ctx context.Context, req *pb.AttachChainRpcRequest) (
+	*pb.AttachChainRpcResponse, error) {
+
+	defer zero.Bytes(req.Password)
+
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	wallet, ok := s.loader.LoadedWallet()
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "Wallet has not been loaded")
+	}
+
+	// A client started with the process configuration is reconnected by
+	// hcwallet itself and must not be replaced.
+	if s.rpcClient == nil && wallet.SynchronizingToNetwork() {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"wallet is synchronizing with a client not managed by this service")
+	}
+
+	rpcClient, err := s.startConsensusRPC(req.NetworkAddress, req.Username,
+		req.Password, req.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ticket buyer queries the chain client, so it is moved to the new
+	// client before the previous client is stopped.
+	err = s.loader.ReplaceChainClient(rpcClient.Client)
+	if err != nil {
+		log.Errorf("Ticket buyer stopped: failed to restart it with the "+
+			"new consensus RPC client: %v", err)
+	}
+	if s.rpcClient != nil {
+		wallet.DisassociateConsensusRPC()
+		s.rpcClient.Stop()
+		s.rpcClient.WaitForShutdown()
+	}
+
+	s.rpcClient = rpcClient
+	if s.server != nil {
+		s.server.SetChainServer(rpcClient)
+	}
+	wallet.SynchronizeRPC(rpcClient)
+	return &pb.AttachChainRpcResponse{}, nil
+}
+
+// startConsensusRPC connects a new consensus RPC client, translating failures
+// to gRPC errors.
+func (s *loaderServer) startConsensusRPC(address, username string, password, certificate []byte) (
+	*chain.RPCClient, error) {
+
+	networkAddress, err := cfgutil.NormalizeAddress(address,
+		s.activeNet.JSONRPCClientPort)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Network address is ill-formed: %v", err)
+	}
+
+	rpcClient, err := chain.NewRPCClient(s.activeNet.Params, networkAddress, username,
+		string(password), certificate, len(certificate) == 0, 1)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -1965,14 +2208,7 @@ func (s *loaderServer) StartConsensusRpc(ctx context.Context, req *pb.StartConse
 		return nil, status.Errorf(codes.NotFound,
 			"Connection to RPC server failed: %v", err)
 	}
-
-	s.rpcClient = rpcClient
-	s.loader.SetChainClient(rpcClient.Client)
-	if s.server != nil {
-		s.server.SetChainServer(rpcClient)
-	}
-	wallet.SynchronizeRPC(rpcClient)
-	return &pb.StartConsensusRpcResponse{}, nil
+	return rpcClient, nil
 }
 
 func (s *loaderServer) DiscoverAddresses(ctx context.Context, req *pb.DiscoverAddressesRequest) (
@@ -2446,6 +2682,50 @@ func (s *votingServer) SetVoteChoices(ctx context.Context, req *pb.SetVoteChoice
 	return resp, nil
 }
 
+// StartTicketService starts the TicketService.
+func StartTicketService(server *grpc.Server, wallet *wallet.Wallet) {
+	ticketService.wallet = wallet
+	if atomic.SwapUint32(&ticketService.ready, 1) != 0 {
+		panic("service already started")
+	}
+}
+
+func (s *ticketServer) checkReady() bool {
+	return atomic.LoadUint32(&s.ready) != 0
+}
+
+func (s *ticketServer) TicketNotifications(req *pb.TicketNotificationsRequest,
+	svr pb.TicketService_TicketNotificationsServer) error {
+
+	n := s.wallet.NtfnServer.TicketNotifications()
+	defer n.Done()
+
+	ctxDone := svr.Context().Done()
+	for {
+		select {
+		case v, ok := <-n.C:
+			if !ok {
+				return errNotificationsBehind
+			}
+			resp := pb.TicketNotificationsResponse{
+				TicketHash:  v.Ticket[:],
+				Event:       pb.TicketNotificationsResponse_Event(v.Event),
+				BlockHeight: v.BlockHeight,
+			}
+			if v.SpenderHash != nil {
+				resp.SpenderHash = v.SpenderHash[:]
+			}
+			err := svr.Send(&resp)
+			if err != nil {
+				return translateError(err)
+			}
+
+		case <-ctxDone:
+			return nil
+		}
+	}
+}
+
 func (s *messageVerificationServer) VerifyMessage(ctx context.Context, req *pb.VerifyMessageRequest) (
 	*pb.VerifyMessageResponse, error) {
 
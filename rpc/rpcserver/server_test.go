@@ -0,0 +1,83 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/netparams"
+	pb "github.com/HcashOrg/hcwallet/rpc/walletrpc"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	_ "github.com/HcashOrg/hcwallet/walletdb/bdb"
+)
+
+func TestAttachChainRpcPreconditions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpcservertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	activeNet := &netparams.SimNetParams
+	l := loader.NewLoader(activeNet.Params, dir, &loader.StakeOptions{}, 20, false,
+		txauthor.TxLimits{}, false, 0.001, false)
+	s := &loaderServer{loader: l, activeNet: activeNet}
+	ctx := context.Background()
+
+	check := func(desc string, err error, want codes.Code) {
+		if code := status.Code(err); code != want {
+			t.Errorf("%s: AttachChainRpc returned %v, want code %v", desc,
+				err, want)
+		}
+	}
+
+	_, err = s.AttachChainRpc(ctx, &pb.AttachChainRpcRequest{
+		NetworkAddress: "127.0.0.1",
+	})
+	check("no wallet", err, codes.FailedPrecondition)
+
+	seed := make([]byte, 32)
+	w, err := l.CreateNewWallet([]byte("public"), []byte("private"), seed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.UnloadWallet()
+
+	_, err = s.AttachChainRpc(ctx, &pb.AttachChainRpcRequest{
+		NetworkAddress: "[127.0.0.1",
+	})
+	check("ill-formed address", err, codes.InvalidArgument)
+
+	// A client not started by the loader service is not replaced.
+	c := testhelpers.NewMockChainClient()
+	w.SynchronizeRPC(c)
+	_, err = s.AttachChainRpc(ctx, &pb.AttachChainRpcRequest{
+		NetworkAddress: "127.0.0.1",
+	})
+	check("unmanaged client", err, codes.FailedPrecondition)
+	if c.Stopped() {
+		t.Error("unmanaged client was stopped")
+	}
+
+	// Detaching the unmanaged client allows another to be attached, which
+	// fails only on connecting.
+	w.DisassociateConsensusRPC()
+	_, err = s.AttachChainRpc(ctx, &pb.AttachChainRpcRequest{
+		NetworkAddress: "127.0.0.1:1",
+		Certificate:    []byte("not a certificate"),
+	})
+	if code := status.Code(err); code == codes.FailedPrecondition ||
+		code == codes.OK {
+		t.Errorf("attach after detaching returned %v", err)
+	}
+}
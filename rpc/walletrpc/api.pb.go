@@ -39,6 +39,8 @@ It has these top-level messages:
 	GetTransactionResponse
 	GetTransactionsRequest
 	GetTransactionsResponse
+	GetTransactionsStreamRequest
+	GetTransactionsStreamResponse
 	GetTicketsRequest
 	GetTicketsResponse
 	TicketPriceRequest
@@ -59,6 +61,8 @@ It has these top-level messages:
 	CreateSignatureResponse
 	PublishTransactionRequest
 	PublishTransactionResponse
+	AbandonTransactionRequest
+	AbandonTransactionResponse
 	PurchaseTicketsRequest
 	PurchaseTicketsResponse
 	RevokeTicketsRequest
@@ -71,6 +75,10 @@ It has these top-level messages:
 	TransactionNotificationsResponse
 	AccountNotificationsRequest
 	AccountNotificationsResponse
+	TicketNotificationsRequest
+	TicketNotificationsResponse
+	MainTipChangedNotificationsRequest
+	MainTipChangedNotificationsResponse
 	ConfirmationNotificationsRequest
 	ConfirmationNotificationsResponse
 	CreateWalletRequest
@@ -83,6 +91,8 @@ It has these top-level messages:
 	WalletExistsResponse
 	StartConsensusRpcRequest
 	StartConsensusRpcResponse
+	AttachChainRpcRequest
+	AttachChainRpcResponse
 	DiscoverAddressesRequest
 	DiscoverAddressesResponse
 	SubscribeToBlockNotificationsRequest
@@ -383,6 +393,43 @@ func (CreateSignatureRequest_SigHashType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor0, []int{47, 0}
 }
 
+type TicketNotificationsResponse_Event int32
+
+const (
+	TicketNotificationsResponse_PURCHASED TicketNotificationsResponse_Event = 0
+	TicketNotificationsResponse_LIVE      TicketNotificationsResponse_Event = 1
+	TicketNotificationsResponse_VOTED     TicketNotificationsResponse_Event = 2
+	TicketNotificationsResponse_MISSED    TicketNotificationsResponse_Event = 3
+	TicketNotificationsResponse_EXPIRED   TicketNotificationsResponse_Event = 4
+	TicketNotificationsResponse_REVOKED   TicketNotificationsResponse_Event = 5
+)
+
+var TicketNotificationsResponse_Event_name = map[int32]string{
+	0: "PURCHASED",
+	1: "LIVE",
+	2: "VOTED",
+	3: "MISSED",
+	4: "EXPIRED",
+	5: "REVOKED",
+}
+
+var TicketNotificationsResponse_Event_value = map[string]int32{
+	"PURCHASED": 0,
+	"LIVE":      1,
+	"VOTED":     2,
+	"MISSED":    3,
+	"EXPIRED":   4,
+	"REVOKED":   5,
+}
+
+func (x TicketNotificationsResponse_Event) String() string {
+	return proto.EnumName(TicketNotificationsResponse_Event_name, int32(x))
+}
+
+func (TicketNotificationsResponse_Event) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{68, 0}
+}
+
 type DecodedTransaction_Input_TreeType int32
 
 const (
@@ -1509,6 +1556,68 @@ func (m *GetTransactionsResponse) GetUnminedTransactions() []*TransactionDetails
 	return nil
 }
 
+type GetTransactionsStreamRequest struct {
+	StartingBlockHeight int32    `protobuf:"zigzag32,1,opt,name=starting_block_height,json=startingBlockHeight" json:"starting_block_height,omitempty"`
+	EndingBlockHeight   int32    `protobuf:"zigzag32,2,opt,name=ending_block_height,json=endingBlockHeight" json:"ending_block_height,omitempty"`
+	Accounts            []uint32 `protobuf:"varint,3,rep,packed,name=accounts" json:"accounts,omitempty"`
+}
+
+func (m *GetTransactionsStreamRequest) Reset() { *m = GetTransactionsStreamRequest{} }
+
+func (m *GetTransactionsStreamRequest) String() string { return proto.CompactTextString(m) }
+
+func (*GetTransactionsStreamRequest) ProtoMessage() {}
+
+func (*GetTransactionsStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *GetTransactionsStreamRequest) GetStartingBlockHeight() int32 {
+	if m != nil {
+		return m.StartingBlockHeight
+	}
+	return 0
+}
+
+func (m *GetTransactionsStreamRequest) GetEndingBlockHeight() int32 {
+	if m != nil {
+		return m.EndingBlockHeight
+	}
+	return 0
+}
+
+func (m *GetTransactionsStreamRequest) GetAccounts() []uint32 {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+type GetTransactionsStreamResponse struct {
+	MinedTransactions   *BlockDetails         `protobuf:"bytes,1,opt,name=mined_transactions,json=minedTransactions" json:"mined_transactions,omitempty"`
+	UnminedTransactions []*TransactionDetails `protobuf:"bytes,2,rep,name=unmined_transactions,json=unminedTransactions" json:"unmined_transactions,omitempty"`
+}
+
+func (m *GetTransactionsStreamResponse) Reset() { *m = GetTransactionsStreamResponse{} }
+
+func (m *GetTransactionsStreamResponse) String() string { return proto.CompactTextString(m) }
+
+func (*GetTransactionsStreamResponse) ProtoMessage() {}
+
+func (*GetTransactionsStreamResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *GetTransactionsStreamResponse) GetMinedTransactions() *BlockDetails {
+	if m != nil {
+		return m.MinedTransactions
+	}
+	return nil
+}
+
+func (m *GetTransactionsStreamResponse) GetUnminedTransactions() []*TransactionDetails {
+	if m != nil {
+		return m.UnminedTransactions
+	}
+	return nil
+}
+
 type GetTicketsRequest struct {
 	StartingBlockHash   []byte `protobuf:"bytes,1,opt,name=starting_block_hash,json=startingBlockHash,proto3" json:"starting_block_hash,omitempty"`
 	StartingBlockHeight int32  `protobuf:"zigzag32,2,opt,name=starting_block_height,json=startingBlockHeight" json:"starting_block_height,omitempty"`
@@ -2380,6 +2489,36 @@ func (m *PublishTransactionResponse) GetTransactionHash() []byte {
 	return nil
 }
 
+type AbandonTransactionRequest struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+}
+
+func (m *AbandonTransactionRequest) Reset() { *m = AbandonTransactionRequest{} }
+
+func (m *AbandonTransactionRequest) String() string { return proto.CompactTextString(m) }
+
+func (*AbandonTransactionRequest) ProtoMessage() {}
+
+func (*AbandonTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *AbandonTransactionRequest) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+type AbandonTransactionResponse struct {
+}
+
+func (m *AbandonTransactionResponse) Reset() { *m = AbandonTransactionResponse{} }
+
+func (m *AbandonTransactionResponse) String() string { return proto.CompactTextString(m) }
+
+func (*AbandonTransactionResponse) ProtoMessage() {}
+
+func (*AbandonTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
 type PurchaseTicketsRequest struct {
 	Passphrase            []byte  `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 	Account               uint32  `protobuf:"varint,2,opt,name=account" json:"account,omitempty"`
@@ -2477,13 +2616,16 @@ func (m *PurchaseTicketsRequest) GetTicketFee() int64 {
 }
 
 type PurchaseTicketsResponse struct {
-	TicketHashes [][]byte `protobuf:"bytes,1,rep,name=ticket_hashes,json=ticketHashes,proto3" json:"ticket_hashes,omitempty"`
+	TicketHashes [][]byte                                 `protobuf:"bytes,1,rep,name=ticket_hashes,json=ticketHashes,proto3" json:"ticket_hashes,omitempty"`
+	SplitTxHash  []byte                                   `protobuf:"bytes,2,opt,name=split_tx_hash,json=splitTxHash,proto3" json:"split_tx_hash,omitempty"`
+	Failures     []*PurchaseTicketsResponse_TicketFailure `protobuf:"bytes,3,rep,name=failures" json:"failures,omitempty"`
+	TotalSpent   int64                                    `protobuf:"varint,4,opt,name=total_spent,json=totalSpent" json:"total_spent,omitempty"`
 }
 
 func (m *PurchaseTicketsResponse) Reset()                    { *m = PurchaseTicketsResponse{} }
 func (m *PurchaseTicketsResponse) String() string            { return proto.CompactTextString(m) }
 func (*PurchaseTicketsResponse) ProtoMessage()               {}
-func (*PurchaseTicketsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+func (*PurchaseTicketsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
 
 func (m *PurchaseTicketsResponse) GetTicketHashes() [][]byte {
 	if m != nil {
@@ -2492,6 +2634,56 @@ func (m *PurchaseTicketsResponse) GetTicketHashes() [][]byte {
 	return nil
 }
 
+func (m *PurchaseTicketsResponse) GetSplitTxHash() []byte {
+	if m != nil {
+		return m.SplitTxHash
+	}
+	return nil
+}
+
+func (m *PurchaseTicketsResponse) GetFailures() []*PurchaseTicketsResponse_TicketFailure {
+	if m != nil {
+		return m.Failures
+	}
+	return nil
+}
+
+func (m *PurchaseTicketsResponse) GetTotalSpent() int64 {
+	if m != nil {
+		return m.TotalSpent
+	}
+	return 0
+}
+
+type PurchaseTicketsResponse_TicketFailure struct {
+	Index uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *PurchaseTicketsResponse_TicketFailure) Reset() { *m = PurchaseTicketsResponse_TicketFailure{} }
+
+func (m *PurchaseTicketsResponse_TicketFailure) String() string { return proto.CompactTextString(m) }
+
+func (*PurchaseTicketsResponse_TicketFailure) ProtoMessage() {}
+
+func (*PurchaseTicketsResponse_TicketFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{54, 0}
+}
+
+func (m *PurchaseTicketsResponse_TicketFailure) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *PurchaseTicketsResponse_TicketFailure) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type RevokeTicketsRequest struct {
 	Passphrase []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 }
@@ -2875,6 +3067,110 @@ func (m *AccountNotificationsResponse) GetImportedKeyCount() uint32 {
 	return 0
 }
 
+type TicketNotificationsRequest struct {
+}
+
+func (m *TicketNotificationsRequest) Reset() { *m = TicketNotificationsRequest{} }
+
+func (m *TicketNotificationsRequest) String() string { return proto.CompactTextString(m) }
+
+func (*TicketNotificationsRequest) ProtoMessage() {}
+
+func (*TicketNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+type TicketNotificationsResponse struct {
+	TicketHash  []byte                            `protobuf:"bytes,1,opt,name=ticket_hash,json=ticketHash,proto3" json:"ticket_hash,omitempty"`
+	Event       TicketNotificationsResponse_Event `protobuf:"varint,2,opt,name=event,enum=walletrpc.TicketNotificationsResponse_Event" json:"event,omitempty"`
+	SpenderHash []byte                            `protobuf:"bytes,3,opt,name=spender_hash,json=spenderHash,proto3" json:"spender_hash,omitempty"`
+	BlockHeight int32                             `protobuf:"varint,4,opt,name=block_height,json=blockHeight" json:"block_height,omitempty"`
+}
+
+func (m *TicketNotificationsResponse) Reset() { *m = TicketNotificationsResponse{} }
+
+func (m *TicketNotificationsResponse) String() string { return proto.CompactTextString(m) }
+
+func (*TicketNotificationsResponse) ProtoMessage() {}
+
+func (*TicketNotificationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+func (m *TicketNotificationsResponse) GetTicketHash() []byte {
+	if m != nil {
+		return m.TicketHash
+	}
+	return nil
+}
+
+func (m *TicketNotificationsResponse) GetEvent() TicketNotificationsResponse_Event {
+	if m != nil {
+		return m.Event
+	}
+	return TicketNotificationsResponse_PURCHASED
+}
+
+func (m *TicketNotificationsResponse) GetSpenderHash() []byte {
+	if m != nil {
+		return m.SpenderHash
+	}
+	return nil
+}
+
+func (m *TicketNotificationsResponse) GetBlockHeight() int32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+type MainTipChangedNotificationsRequest struct {
+}
+
+func (m *MainTipChangedNotificationsRequest) Reset() { *m = MainTipChangedNotificationsRequest{} }
+
+func (m *MainTipChangedNotificationsRequest) String() string { return proto.CompactTextString(m) }
+
+func (*MainTipChangedNotificationsRequest) ProtoMessage() {}
+
+func (*MainTipChangedNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{69}
+}
+
+type MainTipChangedNotificationsResponse struct {
+	AttachedBlocks [][]byte `protobuf:"bytes,1,rep,name=attached_blocks,json=attachedBlocks,proto3" json:"attached_blocks,omitempty"`
+	DetachedBlocks [][]byte `protobuf:"bytes,2,rep,name=detached_blocks,json=detachedBlocks,proto3" json:"detached_blocks,omitempty"`
+	NewHeight      int32    `protobuf:"varint,3,opt,name=new_height,json=newHeight" json:"new_height,omitempty"`
+}
+
+func (m *MainTipChangedNotificationsResponse) Reset() { *m = MainTipChangedNotificationsResponse{} }
+
+func (m *MainTipChangedNotificationsResponse) String() string { return proto.CompactTextString(m) }
+
+func (*MainTipChangedNotificationsResponse) ProtoMessage() {}
+
+func (*MainTipChangedNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{70}
+}
+
+func (m *MainTipChangedNotificationsResponse) GetAttachedBlocks() [][]byte {
+	if m != nil {
+		return m.AttachedBlocks
+	}
+	return nil
+}
+
+func (m *MainTipChangedNotificationsResponse) GetDetachedBlocks() [][]byte {
+	if m != nil {
+		return m.DetachedBlocks
+	}
+	return nil
+}
+
+func (m *MainTipChangedNotificationsResponse) GetNewHeight() int32 {
+	if m != nil {
+		return m.NewHeight
+	}
+	return 0
+}
+
 type ConfirmationNotificationsRequest struct {
 	TxHashes  [][]byte `protobuf:"bytes,1,rep,name=tx_hashes,json=txHashes,proto3" json:"tx_hashes,omitempty"`
 	StopAfter int32    `protobuf:"varint,2,opt,name=stop_after,json=stopAfter" json:"stop_after,omitempty"`
@@ -2969,12 +3265,14 @@ type CreateWalletRequest struct {
 	PublicPassphrase  []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
 	PrivatePassphrase []byte `protobuf:"bytes,2,opt,name=private_passphrase,json=privatePassphrase,proto3" json:"private_passphrase,omitempty"`
 	Seed              []byte `protobuf:"bytes,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	BirthdayTime      int64  `protobuf:"varint,4,opt,name=birthday_time,json=birthdayTime" json:"birthday_time,omitempty"`
+	BirthdayHeight    int32  `protobuf:"varint,5,opt,name=birthday_height,json=birthdayHeight" json:"birthday_height,omitempty"`
 }
 
 func (m *CreateWalletRequest) Reset()                    { *m = CreateWalletRequest{} }
 func (m *CreateWalletRequest) String() string            { return proto.CompactTextString(m) }
 func (*CreateWalletRequest) ProtoMessage()               {}
-func (*CreateWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+func (*CreateWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
 
 func (m *CreateWalletRequest) GetPublicPassphrase() []byte {
 	if m != nil {
@@ -2997,6 +3295,20 @@ func (m *CreateWalletRequest) GetSeed() []byte {
 	return nil
 }
 
+func (m *CreateWalletRequest) GetBirthdayTime() int64 {
+	if m != nil {
+		return m.BirthdayTime
+	}
+	return 0
+}
+
+func (m *CreateWalletRequest) GetBirthdayHeight() int32 {
+	if m != nil {
+		return m.BirthdayHeight
+	}
+	return 0
+}
+
 type CreateWalletResponse struct {
 }
 
@@ -3125,6 +3437,60 @@ func (m *StartConsensusRpcResponse) String() string            { return proto.Co
 func (*StartConsensusRpcResponse) ProtoMessage()               {}
 func (*StartConsensusRpcResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
 
+type AttachChainRpcRequest struct {
+	NetworkAddress string `protobuf:"bytes,1,opt,name=network_address,json=networkAddress" json:"network_address,omitempty"`
+	Username       string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Password       []byte `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Certificate    []byte `protobuf:"bytes,4,opt,name=certificate,proto3" json:"certificate,omitempty"`
+}
+
+func (m *AttachChainRpcRequest) Reset() { *m = AttachChainRpcRequest{} }
+
+func (m *AttachChainRpcRequest) String() string { return proto.CompactTextString(m) }
+
+func (*AttachChainRpcRequest) ProtoMessage() {}
+
+func (*AttachChainRpcRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *AttachChainRpcRequest) GetNetworkAddress() string {
+	if m != nil {
+		return m.NetworkAddress
+	}
+	return ""
+}
+
+func (m *AttachChainRpcRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *AttachChainRpcRequest) GetPassword() []byte {
+	if m != nil {
+		return m.Password
+	}
+	return nil
+}
+
+func (m *AttachChainRpcRequest) GetCertificate() []byte {
+	if m != nil {
+		return m.Certificate
+	}
+	return nil
+}
+
+type AttachChainRpcResponse struct {
+}
+
+func (m *AttachChainRpcResponse) Reset() { *m = AttachChainRpcResponse{} }
+
+func (m *AttachChainRpcResponse) String() string { return proto.CompactTextString(m) }
+
+func (*AttachChainRpcResponse) ProtoMessage() {}
+
+func (*AttachChainRpcResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
 type DiscoverAddressesRequest struct {
 	DiscoverAccounts  bool   `protobuf:"varint,1,opt,name=discover_accounts,json=discoverAccounts" json:"discover_accounts,omitempty"`
 	PrivatePassphrase []byte `protobuf:"bytes,2,opt,name=private_passphrase,json=privatePassphrase,proto3" json:"private_passphrase,omitempty"`
@@ -4743,6 +5109,8 @@ func init() {
 	proto.RegisterType((*GetTransactionResponse)(nil), "walletrpc.GetTransactionResponse")
 	proto.RegisterType((*GetTransactionsRequest)(nil), "walletrpc.GetTransactionsRequest")
 	proto.RegisterType((*GetTransactionsResponse)(nil), "walletrpc.GetTransactionsResponse")
+	proto.RegisterType((*GetTransactionsStreamRequest)(nil), "walletrpc.GetTransactionsStreamRequest")
+	proto.RegisterType((*GetTransactionsStreamResponse)(nil), "walletrpc.GetTransactionsStreamResponse")
 	proto.RegisterType((*GetTicketsRequest)(nil), "walletrpc.GetTicketsRequest")
 	proto.RegisterType((*GetTicketsResponse)(nil), "walletrpc.GetTicketsResponse")
 	proto.RegisterType((*GetTicketsResponse_TicketDetails)(nil), "walletrpc.GetTicketsResponse.TicketDetails")
@@ -4768,8 +5136,11 @@ func init() {
 	proto.RegisterType((*CreateSignatureResponse)(nil), "walletrpc.CreateSignatureResponse")
 	proto.RegisterType((*PublishTransactionRequest)(nil), "walletrpc.PublishTransactionRequest")
 	proto.RegisterType((*PublishTransactionResponse)(nil), "walletrpc.PublishTransactionResponse")
+	proto.RegisterType((*AbandonTransactionRequest)(nil), "walletrpc.AbandonTransactionRequest")
+	proto.RegisterType((*AbandonTransactionResponse)(nil), "walletrpc.AbandonTransactionResponse")
 	proto.RegisterType((*PurchaseTicketsRequest)(nil), "walletrpc.PurchaseTicketsRequest")
 	proto.RegisterType((*PurchaseTicketsResponse)(nil), "walletrpc.PurchaseTicketsResponse")
+	proto.RegisterType((*PurchaseTicketsResponse_TicketFailure)(nil), "walletrpc.PurchaseTicketsResponse.TicketFailure")
 	proto.RegisterType((*RevokeTicketsRequest)(nil), "walletrpc.RevokeTicketsRequest")
 	proto.RegisterType((*RevokeTicketsResponse)(nil), "walletrpc.RevokeTicketsResponse")
 	proto.RegisterType((*LoadActiveDataFiltersRequest)(nil), "walletrpc.LoadActiveDataFiltersRequest")
@@ -4784,6 +5155,10 @@ func init() {
 	proto.RegisterType((*TransactionNotificationsResponse)(nil), "walletrpc.TransactionNotificationsResponse")
 	proto.RegisterType((*AccountNotificationsRequest)(nil), "walletrpc.AccountNotificationsRequest")
 	proto.RegisterType((*AccountNotificationsResponse)(nil), "walletrpc.AccountNotificationsResponse")
+	proto.RegisterType((*TicketNotificationsRequest)(nil), "walletrpc.TicketNotificationsRequest")
+	proto.RegisterType((*TicketNotificationsResponse)(nil), "walletrpc.TicketNotificationsResponse")
+	proto.RegisterType((*MainTipChangedNotificationsRequest)(nil), "walletrpc.MainTipChangedNotificationsRequest")
+	proto.RegisterType((*MainTipChangedNotificationsResponse)(nil), "walletrpc.MainTipChangedNotificationsResponse")
 	proto.RegisterType((*ConfirmationNotificationsRequest)(nil), "walletrpc.ConfirmationNotificationsRequest")
 	proto.RegisterType((*ConfirmationNotificationsResponse)(nil), "walletrpc.ConfirmationNotificationsResponse")
 	proto.RegisterType((*ConfirmationNotificationsResponse_TransactionConfirmations)(nil), "walletrpc.ConfirmationNotificationsResponse.TransactionConfirmations")
@@ -4797,6 +5172,8 @@ func init() {
 	proto.RegisterType((*WalletExistsResponse)(nil), "walletrpc.WalletExistsResponse")
 	proto.RegisterType((*StartConsensusRpcRequest)(nil), "walletrpc.StartConsensusRpcRequest")
 	proto.RegisterType((*StartConsensusRpcResponse)(nil), "walletrpc.StartConsensusRpcResponse")
+	proto.RegisterType((*AttachChainRpcRequest)(nil), "walletrpc.AttachChainRpcRequest")
+	proto.RegisterType((*AttachChainRpcResponse)(nil), "walletrpc.AttachChainRpcResponse")
 	proto.RegisterType((*DiscoverAddressesRequest)(nil), "walletrpc.DiscoverAddressesRequest")
 	proto.RegisterType((*DiscoverAddressesResponse)(nil), "walletrpc.DiscoverAddressesResponse")
 	proto.RegisterType((*SubscribeToBlockNotificationsRequest)(nil), "walletrpc.SubscribeToBlockNotificationsRequest")
@@ -4863,6 +5240,7 @@ func init() {
 	proto.RegisterEnum("walletrpc.ChangePassphraseRequest_Key", ChangePassphraseRequest_Key_name, ChangePassphraseRequest_Key_value)
 	proto.RegisterEnum("walletrpc.ConstructTransactionRequest_OutputSelectionAlgorithm", ConstructTransactionRequest_OutputSelectionAlgorithm_name, ConstructTransactionRequest_OutputSelectionAlgorithm_value)
 	proto.RegisterEnum("walletrpc.CreateSignatureRequest_SigHashType", CreateSignatureRequest_SigHashType_name, CreateSignatureRequest_SigHashType_value)
+	proto.RegisterEnum("walletrpc.TicketNotificationsResponse_Event", TicketNotificationsResponse_Event_name, TicketNotificationsResponse_Event_value)
 	proto.RegisterEnum("walletrpc.DecodedTransaction_Input_TreeType", DecodedTransaction_Input_TreeType_name, DecodedTransaction_Input_TreeType_value)
 	proto.RegisterEnum("walletrpc.DecodedTransaction_Output_ScriptClass", DecodedTransaction_Output_ScriptClass_name, DecodedTransaction_Output_ScriptClass_value)
 	proto.RegisterEnum("walletrpc.ValidateAddressResponse_ScriptType", ValidateAddressResponse_ScriptType_name, ValidateAddressResponse_ScriptType_value)
@@ -4951,6 +5329,7 @@ type WalletServiceClient interface {
 	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
 	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
 	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (WalletService_GetTransactionsClient, error)
+	GetTransactionsStream(ctx context.Context, in *GetTransactionsStreamRequest, opts ...grpc.CallOption) (WalletService_GetTransactionsStreamClient, error)
 	GetTickets(ctx context.Context, in *GetTicketsRequest, opts ...grpc.CallOption) (WalletService_GetTicketsClient, error)
 	TicketPrice(ctx context.Context, in *TicketPriceRequest, opts ...grpc.CallOption) (*TicketPriceResponse, error)
 	StakeInfo(ctx context.Context, in *StakeInfoRequest, opts ...grpc.CallOption) (*StakeInfoResponse, error)
@@ -4960,6 +5339,7 @@ type WalletServiceClient interface {
 	TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error)
 	AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error)
 	ConfirmationNotifications(ctx context.Context, opts ...grpc.CallOption) (WalletService_ConfirmationNotificationsClient, error)
+	MainTipChangedNotifications(ctx context.Context, in *MainTipChangedNotificationsRequest, opts ...grpc.CallOption) (WalletService_MainTipChangedNotificationsClient, error)
 	// Control
 	ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error)
 	RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error)
@@ -4973,6 +5353,7 @@ type WalletServiceClient interface {
 	SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error)
 	CreateSignature(ctx context.Context, in *CreateSignatureRequest, opts ...grpc.CallOption) (*CreateSignatureResponse, error)
 	PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error)
+	AbandonTransaction(ctx context.Context, in *AbandonTransactionRequest, opts ...grpc.CallOption) (*AbandonTransactionResponse, error)
 	PurchaseTickets(ctx context.Context, in *PurchaseTicketsRequest, opts ...grpc.CallOption) (*PurchaseTicketsResponse, error)
 	RevokeTickets(ctx context.Context, in *RevokeTicketsRequest, opts ...grpc.CallOption) (*RevokeTicketsResponse, error)
 	LoadActiveDataFilters(ctx context.Context, in *LoadActiveDataFiltersRequest, opts ...grpc.CallOption) (*LoadActiveDataFiltersResponse, error)
@@ -5076,8 +5457,40 @@ func (x *walletServiceGetTransactionsClient) Recv() (*GetTransactionsResponse, e
 	return m, nil
 }
 
+func (c *walletServiceClient) GetTransactionsStream(ctx context.Context, in *GetTransactionsStreamRequest, opts ...grpc.CallOption) (WalletService_GetTransactionsStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[1], c.cc, "/walletrpc.WalletService/GetTransactionsStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceGetTransactionsStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_GetTransactionsStreamClient interface {
+	Recv() (*GetTransactionsStreamResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceGetTransactionsStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceGetTransactionsStreamClient) Recv() (*GetTransactionsStreamResponse, error) {
+	m := new(GetTransactionsStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *walletServiceClient) GetTickets(ctx context.Context, in *GetTicketsRequest, opts ...grpc.CallOption) (WalletService_GetTicketsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[1], c.cc, "/walletrpc.WalletService/GetTickets", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[2], c.cc, "/walletrpc.WalletService/GetTickets", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5145,7 +5558,7 @@ func (c *walletServiceClient) BestBlock(ctx context.Context, in *BestBlockReques
 }
 
 func (c *walletServiceClient) TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[2], c.cc, "/walletrpc.WalletService/TransactionNotifications", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[3], c.cc, "/walletrpc.WalletService/TransactionNotifications", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5177,7 +5590,7 @@ func (x *walletServiceTransactionNotificationsClient) Recv() (*TransactionNotifi
 }
 
 func (c *walletServiceClient) AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[3], c.cc, "/walletrpc.WalletService/AccountNotifications", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[4], c.cc, "/walletrpc.WalletService/AccountNotifications", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5209,7 +5622,7 @@ func (x *walletServiceAccountNotificationsClient) Recv() (*AccountNotificationsR
 }
 
 func (c *walletServiceClient) ConfirmationNotifications(ctx context.Context, opts ...grpc.CallOption) (WalletService_ConfirmationNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[4], c.cc, "/walletrpc.WalletService/ConfirmationNotifications", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[5], c.cc, "/walletrpc.WalletService/ConfirmationNotifications", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5239,6 +5652,38 @@ func (x *walletServiceConfirmationNotificationsClient) Recv() (*ConfirmationNoti
 	return m, nil
 }
 
+func (c *walletServiceClient) MainTipChangedNotifications(ctx context.Context, in *MainTipChangedNotificationsRequest, opts ...grpc.CallOption) (WalletService_MainTipChangedNotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[6], c.cc, "/walletrpc.WalletService/MainTipChangedNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceMainTipChangedNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_MainTipChangedNotificationsClient interface {
+	Recv() (*MainTipChangedNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceMainTipChangedNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceMainTipChangedNotificationsClient) Recv() (*MainTipChangedNotificationsResponse, error) {
+	m := new(MainTipChangedNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *walletServiceClient) ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error) {
 	out := new(ChangePassphraseResponse)
 	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ChangePassphrase", in, out, c.cc, opts...)
@@ -5258,7 +5703,7 @@ func (c *walletServiceClient) RenameAccount(ctx context.Context, in *RenameAccou
 }
 
 func (c *walletServiceClient) Rescan(ctx context.Context, in *RescanRequest, opts ...grpc.CallOption) (WalletService_RescanClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[5], c.cc, "/walletrpc.WalletService/Rescan", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[7], c.cc, "/walletrpc.WalletService/Rescan", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5370,6 +5815,15 @@ func (c *walletServiceClient) PublishTransaction(ctx context.Context, in *Publis
 	return out, nil
 }
 
+func (c *walletServiceClient) AbandonTransaction(ctx context.Context, in *AbandonTransactionRequest, opts ...grpc.CallOption) (*AbandonTransactionResponse, error) {
+	out := new(AbandonTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/AbandonTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletServiceClient) PurchaseTickets(ctx context.Context, in *PurchaseTicketsRequest, opts ...grpc.CallOption) (*PurchaseTicketsResponse, error) {
 	out := new(PurchaseTicketsResponse)
 	err := grpc.Invoke(ctx, "/walletrpc.WalletService/PurchaseTickets", in, out, c.cc, opts...)
@@ -5443,6 +5897,7 @@ type WalletServiceServer interface {
 	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
 	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error)
 	GetTransactions(*GetTransactionsRequest, WalletService_GetTransactionsServer) error
+	GetTransactionsStream(*GetTransactionsStreamRequest, WalletService_GetTransactionsStreamServer) error
 	GetTickets(*GetTicketsRequest, WalletService_GetTicketsServer) error
 	TicketPrice(context.Context, *TicketPriceRequest) (*TicketPriceResponse, error)
 	StakeInfo(context.Context, *StakeInfoRequest) (*StakeInfoResponse, error)
@@ -5452,6 +5907,7 @@ type WalletServiceServer interface {
 	TransactionNotifications(*TransactionNotificationsRequest, WalletService_TransactionNotificationsServer) error
 	AccountNotifications(*AccountNotificationsRequest, WalletService_AccountNotificationsServer) error
 	ConfirmationNotifications(WalletService_ConfirmationNotificationsServer) error
+	MainTipChangedNotifications(*MainTipChangedNotificationsRequest, WalletService_MainTipChangedNotificationsServer) error
 	// Control
 	ChangePassphrase(context.Context, *ChangePassphraseRequest) (*ChangePassphraseResponse, error)
 	RenameAccount(context.Context, *RenameAccountRequest) (*RenameAccountResponse, error)
@@ -5465,6 +5921,7 @@ type WalletServiceServer interface {
 	SignTransaction(context.Context, *SignTransactionRequest) (*SignTransactionResponse, error)
 	CreateSignature(context.Context, *CreateSignatureRequest) (*CreateSignatureResponse, error)
 	PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error)
+	AbandonTransaction(context.Context, *AbandonTransactionRequest) (*AbandonTransactionResponse, error)
 	PurchaseTickets(context.Context, *PurchaseTicketsRequest) (*PurchaseTicketsResponse, error)
 	RevokeTickets(context.Context, *RevokeTicketsRequest) (*RevokeTicketsResponse, error)
 	LoadActiveDataFilters(context.Context, *LoadActiveDataFiltersRequest) (*LoadActiveDataFiltersResponse, error)
@@ -5607,6 +6064,27 @@ func (x *walletServiceGetTransactionsServer) Send(m *GetTransactionsResponse) er
 	return x.ServerStream.SendMsg(m)
 }
 
+func _WalletService_GetTransactionsStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).GetTransactionsStream(m, &walletServiceGetTransactionsStreamServer{stream})
+}
+
+type WalletService_GetTransactionsStreamServer interface {
+	Send(*GetTransactionsStreamResponse) error
+	grpc.ServerStream
+}
+
+type walletServiceGetTransactionsStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceGetTransactionsStreamServer) Send(m *GetTransactionsStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WalletService_GetTickets_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(GetTicketsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -5768,6 +6246,27 @@ func (x *walletServiceConfirmationNotificationsServer) Recv() (*ConfirmationNoti
 	return m, nil
 }
 
+func _WalletService_MainTipChangedNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MainTipChangedNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).MainTipChangedNotifications(m, &walletServiceMainTipChangedNotificationsServer{stream})
+}
+
+type WalletService_MainTipChangedNotificationsServer interface {
+	Send(*MainTipChangedNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type walletServiceMainTipChangedNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceMainTipChangedNotificationsServer) Send(m *MainTipChangedNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WalletService_ChangePassphrase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChangePassphraseRequest)
 	if err := dec(in); err != nil {
@@ -5987,6 +6486,24 @@ func _WalletService_PublishTransaction_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletService_AbandonTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbandonTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).AbandonTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/AbandonTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).AbandonTransaction(ctx, req.(*AbandonTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletService_PurchaseTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PurchaseTicketsRequest)
 	if err := dec(in); err != nil {
@@ -6201,6 +6718,10 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PublishTransaction",
 			Handler:    _WalletService_PublishTransaction_Handler,
 		},
+		{
+			MethodName: "AbandonTransaction",
+			Handler:    _WalletService_AbandonTransaction_Handler,
+		},
 		{
 			MethodName: "PurchaseTickets",
 			Handler:    _WalletService_PurchaseTickets_Handler,
@@ -6236,6 +6757,11 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _WalletService_GetTransactions_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetTransactionsStream",
+			Handler:       _WalletService_GetTransactionsStream_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "GetTickets",
 			Handler:       _WalletService_GetTickets_Handler,
@@ -6257,6 +6783,11 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "MainTipChangedNotifications",
+			Handler:       _WalletService_MainTipChangedNotifications_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Rescan",
 			Handler:       _WalletService_Rescan_Handler,
@@ -6274,6 +6805,7 @@ type WalletLoaderServiceClient interface {
 	OpenWallet(ctx context.Context, in *OpenWalletRequest, opts ...grpc.CallOption) (*OpenWalletResponse, error)
 	CloseWallet(ctx context.Context, in *CloseWalletRequest, opts ...grpc.CallOption) (*CloseWalletResponse, error)
 	StartConsensusRpc(ctx context.Context, in *StartConsensusRpcRequest, opts ...grpc.CallOption) (*StartConsensusRpcResponse, error)
+	AttachChainRpc(ctx context.Context, in *AttachChainRpcRequest, opts ...grpc.CallOption) (*AttachChainRpcResponse, error)
 	DiscoverAddresses(ctx context.Context, in *DiscoverAddressesRequest, opts ...grpc.CallOption) (*DiscoverAddressesResponse, error)
 	SubscribeToBlockNotifications(ctx context.Context, in *SubscribeToBlockNotificationsRequest, opts ...grpc.CallOption) (*SubscribeToBlockNotificationsResponse, error)
 	FetchHeaders(ctx context.Context, in *FetchHeadersRequest, opts ...grpc.CallOption) (*FetchHeadersResponse, error)
@@ -6332,6 +6864,15 @@ func (c *walletLoaderServiceClient) StartConsensusRpc(ctx context.Context, in *S
 	return out, nil
 }
 
+func (c *walletLoaderServiceClient) AttachChainRpc(ctx context.Context, in *AttachChainRpcRequest, opts ...grpc.CallOption) (*AttachChainRpcResponse, error) {
+	out := new(AttachChainRpcResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletLoaderService/AttachChainRpc", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletLoaderServiceClient) DiscoverAddresses(ctx context.Context, in *DiscoverAddressesRequest, opts ...grpc.CallOption) (*DiscoverAddressesResponse, error) {
 	out := new(DiscoverAddressesResponse)
 	err := grpc.Invoke(ctx, "/walletrpc.WalletLoaderService/DiscoverAddresses", in, out, c.cc, opts...)
@@ -6367,6 +6908,7 @@ type WalletLoaderServiceServer interface {
 	OpenWallet(context.Context, *OpenWalletRequest) (*OpenWalletResponse, error)
 	CloseWallet(context.Context, *CloseWalletRequest) (*CloseWalletResponse, error)
 	StartConsensusRpc(context.Context, *StartConsensusRpcRequest) (*StartConsensusRpcResponse, error)
+	AttachChainRpc(context.Context, *AttachChainRpcRequest) (*AttachChainRpcResponse, error)
 	DiscoverAddresses(context.Context, *DiscoverAddressesRequest) (*DiscoverAddressesResponse, error)
 	SubscribeToBlockNotifications(context.Context, *SubscribeToBlockNotificationsRequest) (*SubscribeToBlockNotificationsResponse, error)
 	FetchHeaders(context.Context, *FetchHeadersRequest) (*FetchHeadersResponse, error)
@@ -6466,6 +7008,24 @@ func _WalletLoaderService_StartConsensusRpc_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletLoaderService_AttachChainRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachChainRpcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletLoaderServiceServer).AttachChainRpc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletLoaderService/AttachChainRpc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletLoaderServiceServer).AttachChainRpc(ctx, req.(*AttachChainRpcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletLoaderService_DiscoverAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DiscoverAddressesRequest)
 	if err := dec(in); err != nil {
@@ -6544,6 +7104,10 @@ var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StartConsensusRpc",
 			Handler:    _WalletLoaderService_StartConsensusRpc_Handler,
 		},
+		{
+			MethodName: "AttachChainRpc",
+			Handler:    _WalletLoaderService_AttachChainRpc_Handler,
+		},
 		{
 			MethodName: "DiscoverAddresses",
 			Handler:    _WalletLoaderService_DiscoverAddresses_Handler,
@@ -7246,6 +7810,97 @@ var _VotingService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "api.proto",
 }
 
+// Client API for TicketService service
+
+type TicketServiceClient interface {
+	TicketNotifications(ctx context.Context, in *TicketNotificationsRequest, opts ...grpc.CallOption) (TicketService_TicketNotificationsClient, error)
+}
+
+type ticketServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTicketServiceClient(cc *grpc.ClientConn) TicketServiceClient {
+	return &ticketServiceClient{cc}
+}
+
+func (c *ticketServiceClient) TicketNotifications(ctx context.Context, in *TicketNotificationsRequest, opts ...grpc.CallOption) (TicketService_TicketNotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_TicketService_serviceDesc.Streams[0], c.cc, "/walletrpc.TicketService/TicketNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ticketServiceTicketNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TicketService_TicketNotificationsClient interface {
+	Recv() (*TicketNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type ticketServiceTicketNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *ticketServiceTicketNotificationsClient) Recv() (*TicketNotificationsResponse, error) {
+	m := new(TicketNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for TicketService service
+
+type TicketServiceServer interface {
+	TicketNotifications(*TicketNotificationsRequest, TicketService_TicketNotificationsServer) error
+}
+
+func RegisterTicketServiceServer(s *grpc.Server, srv TicketServiceServer) {
+	s.RegisterService(&_TicketService_serviceDesc, srv)
+}
+
+func _TicketService_TicketNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TicketNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TicketServiceServer).TicketNotifications(m, &ticketServiceTicketNotificationsServer{stream})
+}
+
+type TicketService_TicketNotificationsServer interface {
+	Send(*TicketNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type ticketServiceTicketNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *ticketServiceTicketNotificationsServer) Send(m *TicketNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TicketService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "walletrpc.TicketService",
+	HandlerType: (*TicketServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TicketNotifications",
+			Handler:       _TicketService_TicketNotifications_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
+
 // Client API for MessageVerificationService service
 
 type MessageVerificationServiceClient interface {
@@ -109,7 +109,9 @@ func generateRPCKeyPair(writeKey bool) (tls.Certificate, error) {
 	return keyPair, nil
 }
 
-func startRPCServers(walletLoader *loader.Loader) (*grpc.Server, *legacyrpc.Server, error) {
+func startRPCServers(walletLoaders *loader.Collection) (*grpc.Server, *legacyrpc.Server, error) {
+	walletLoader := walletLoaders.Default()
+
 	var jsonrpcAddrNotifier jsonrpcListenerEventServer
 	var grpcAddrNotifier grpcListenerEventServer
 	if cfg.RPCListenerEvents {
@@ -125,6 +127,15 @@ func startRPCServers(walletLoader *loader.Loader) (*grpc.Server, *legacyrpc.Serv
 		err          error
 	)
 
+	// Client certificates are mapped to permissions when required.
+	var clientPerms *clientCertPerms
+	if cfg.ClientCertPerms != "" {
+		clientPerms, err = loadClientCertPerms(cfg.ClientCertPerms)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if !cfg.DisableServerTLS {
 		keyPair, err = openRPCKeyPair()
 		if err != nil {
@@ -137,6 +148,10 @@ func startRPCServers(walletLoader *loader.Loader) (*grpc.Server, *legacyrpc.Serv
 			MinVersion:   tls.VersionTLS12,
 			NextProtos:   []string{"h2"}, // HTTP/2 over TLS
 		}
+		err = clientTLSConfig(tlsConfig)
+		if err != nil {
+			return nil, nil, err
+		}
 		legacyListen = func(net string, laddr string) (net.Listener, error) {
 			return tls.Listen(net, laddr, tlsConfig)
 		}
@@ -144,39 +159,76 @@ func startRPCServers(walletLoader *loader.Loader) (*grpc.Server, *legacyrpc.Serv
 
 	if cfg.Username == "" || cfg.Password == "" {
 		log.Info("Legacy RPC server disabled (requires username and password)")
-	} else if len(cfg.LegacyRPCListeners) != 0 {
-		listeners := makeListeners(cfg.LegacyRPCListeners, legacyListen)
-		if len(listeners) == 0 {
-			err := errors.New("failed to create listeners for legacy RPC server")
+	} else if len(cfg.LegacyRPCListeners) != 0 || len(cfg.LegacyRPCUnixListeners) != 0 {
+		var listeners []net.Listener
+		if len(cfg.LegacyRPCListeners) != 0 {
+			listeners = makeListeners(cfg.LegacyRPCListeners, legacyListen)
+			if len(listeners) == 0 {
+				err := errors.New("failed to create listeners for legacy RPC server")
+				return nil, nil, err
+			}
+		}
+		unixListeners, err := makeUnixListeners(cfg.LegacyRPCUnixListeners)
+		if err != nil {
 			return nil, nil, err
 		}
+		listeners = append(listeners, unixListeners...)
 		opts := legacyrpc.Options{
 			Username:            cfg.Username,
 			Password:            cfg.Password,
 			MaxPOSTClients:      cfg.LegacyRPCMaxClients,
 			MaxWebsocketClients: cfg.LegacyRPCMaxWebsockets,
+			MaxClientRequests:   cfg.LegacyRPCMaxClientReqs,
+			IdleTimeout:         cfg.LegacyRPCIdleTimeout,
+			ReadOnly:            cfg.Replicate != "",
+			ServeSnapshots:      cfg.AllowReplicas,
+			LogControl:          logControl{},
 		}
-		legacyServer = legacyrpc.NewServer(&opts, activeNet.Params, walletLoader, listeners)
+		if cfg.ClientCAFile != "" {
+			opts.ClientAuth = clientPerms
+		}
+		legacyServer = legacyrpc.NewServer(&opts, activeNet.Params, walletLoaders, listeners)
 		for _, lis := range listeners {
 			jsonrpcAddrNotifier.notify(lis.Addr().String())
 		}
 	}
 
-	if cfg.DisableServerTLS {
+	if cfg.DisableServerTLS && len(cfg.GRPCUnixListeners) == 0 {
 		log.Info("Server TLS is disabled.  Only legacy RPC may be used")
+	} else if cfg.Replicate != "" {
+		log.Info("gRPC server disabled for read-only replica wallets")
 	} else {
-		if len(cfg.GRPCListeners) != 0 {
-			listeners := makeListeners(cfg.GRPCListeners, net.Listen)
+		var listeners []net.Listener
+		if cfg.DisableServerTLS {
+			log.Info("Server TLS is disabled.  gRPC is only served over " +
+				"unix domain sockets")
+		} else if len(cfg.GRPCListeners) != 0 {
+			listeners = makeListeners(cfg.GRPCListeners, net.Listen)
 			if len(listeners) == 0 {
 				err := errors.New("failed to create listeners for RPC server")
 				return nil, nil, err
 			}
-			creds := credentials.NewServerTLSFromCert(&keyPair)
-			server = grpc.NewServer(
-				grpc.Creds(creds),
-				grpc.StreamInterceptor(interceptStreaming),
-				grpc.UnaryInterceptor(interceptUnary),
-			)
+		}
+		unixListeners, err := makeUnixListeners(cfg.GRPCUnixListeners)
+		if err != nil {
+			return nil, nil, err
+		}
+		listeners = append(listeners, unixListeners...)
+		if len(listeners) != 0 {
+			serverOpts := []grpc.ServerOption{
+				grpc.StreamInterceptor(streamInterceptor(clientPerms)),
+				grpc.UnaryInterceptor(unaryInterceptor(walletLoader, clientPerms)),
+			}
+			if !cfg.DisableServerTLS {
+				tlsConfig := &tls.Config{Certificates: []tls.Certificate{keyPair}}
+				err = clientTLSConfig(tlsConfig)
+				if err != nil {
+					return nil, nil, err
+				}
+				creds := unixSocketCreds{credentials.NewTLS(tlsConfig)}
+				serverOpts = append(serverOpts, grpc.Creds(creds))
+			}
+			server = grpc.NewServer(serverOpts...)
 			rpcserver.RegisterServices(server)
 			rpcserver.StartWalletLoaderService(server, legacyServer ,walletLoader, activeNet)
 			rpcserver.StartTicketBuyerService(server, walletLoader, &cfg.tbCfg)
@@ -212,6 +264,18 @@ func serviceName(method string) string {
 	return method[:strings.IndexRune(method, '/')]
 }
 
+// streamInterceptor returns an interceptor for streaming requests which checks
+// the permission of the client certificate, if required.
+func streamInterceptor(perms *clientCertPerms) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := authorizeGRPCClient(ss.Context(), perms, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return interceptStreaming(srv, ss, info, handler)
+	}
+}
+
 func interceptStreaming(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 	p, ok := peer.FromContext(ss.Context())
 	if ok {
@@ -230,6 +294,24 @@ func interceptStreaming(srv interface{}, ss grpc.ServerStream, info *grpc.Stream
 	return err
 }
 
+// unaryInterceptor returns an interceptor for unary requests which checks the
+// permission of the client certificate, if required, and records requests as
+// in progress with the loaded wallet, if any, so rescans may be paused under
+// high RPC load.
+func unaryInterceptor(walletLoader *loader.Loader, perms *clientCertPerms) grpc.UnaryServerInterceptor {
+	return func(ctx xcontext.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		err := authorizeGRPCClient(ctx, perms, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if w, ok := walletLoader.LoadedWallet(); ok {
+			w.RPCStarted()
+			defer w.RPCFinished()
+		}
+		return interceptUnary(ctx, req, info, handler)
+	}
+}
+
 func interceptUnary(ctx xcontext.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 	p, ok := peer.FromContext(ctx)
 	if ok {
@@ -15,12 +15,27 @@
 //      balance.
 //
 // Multiple harnesses may be run concurrently. Temporary folders are created for
-// each harness, and cleaned up on shutdown.
+// each harness, and cleaned up on shutdown. The nodes of several harnesses may
+// be connected with ConnectNode to test behavior across a network, and
+// JoinNodes waits until the connected harnesses share the same best block or
+// mempool.
 //
 // The default settings for a harness wallet are:
 //    1. Ticket buyer enabled (--enableticketbuyer).
 //    2. Zero max ticket price.
 //    3. High balance to maintain (2000000 HC).
 // Thus, a harness wallet will automatically vote on owned tickets, but not
-// automatically purchase tickets.
+// automatically purchase tickets.  (*Harness).MineBlocksWithVotes advances the
+// chain while purchasing tickets with the harness wallet and waiting for its
+// votes, so staking can be tested past stake validation height.  The wallet
+// must first be unlocked with its default passphrase, "password".
+//
+// The harness wallet is created from a random seed unless one is provided with
+// (*Harness).SetWalletSeed before SetUp, in which case the addresses of the
+// wallet are returned by (*Harness).WalletAddresses.
+//
+// Tests of wallet logic which do not need the RPC server may instead create a
+// wallet in the test process with (*Harness).NewInProcessWallet.  The returned
+// InProcessWallet provides the *wallet.Wallet, synced to the harness node, and
+// must be shut down with its TearDown method before the harness.
 package rpctest
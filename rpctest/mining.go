@@ -0,0 +1,157 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+)
+
+// voteWaitTimeout is the time MineBlocksWithVotes waits for the harness
+// wallet to sync to a block, and for the votes on a block to reach the
+// mempool, before failing.
+const voteWaitTimeout = 30 * time.Second
+
+// MineBlocksWithVotes advances the chain n blocks.  Before each block at or
+// above stake enabled height, the harness wallet purchases the maximum number
+// of fresh tickets allowed in a block so the ticket pool remains large enough
+// for voting to continue.  Blocks are still generated when the wallet can not
+// afford the tickets, as its funds are expected to be replenished by the
+// coinbases and votes of later blocks.  Once
+// the chain reaches stake validation height, each block is only generated
+// after a majority of the votes on its parent have reached the node's mempool.
+// The harness wallet votes automatically, and must be unlocked to purchase
+// tickets.  The hashes of the generated blocks are returned.
+func (h *Harness) MineBlocksWithVotes(n uint32) ([]*chainhash.Hash, error) {
+	params := h.ActiveNet
+	minConf := 1
+	numTickets := int(params.MaxFreshStakePerBlock)
+	majority := int(params.TicketsPerBlock)/2 + 1
+
+	hashes := make([]*chainhash.Hash, 0, n)
+	for i := uint32(0); i < n; i++ {
+		tipHash, tipHeight, err := h.Node.GetBestBlock()
+		if err != nil {
+			return hashes, err
+		}
+		err = h.waitForWalletHeight(tipHeight)
+		if err != nil {
+			return hashes, err
+		}
+
+		// Tickets are not accepted in blocks below stake enabled height.
+		if tipHeight+1 >= params.StakeEnabledHeight {
+			err = h.purchaseTickets(numTickets, minConf)
+			if err != nil {
+				return hashes, err
+			}
+		}
+
+		if tipHeight+1 >= params.StakeValidationHeight {
+			err = h.waitForVotes(tipHash, majority)
+			if err != nil {
+				return hashes, err
+			}
+		}
+
+		blockHashes, err := h.GenerateBlock(uint32(tipHeight))
+		if err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, blockHashes...)
+	}
+	return hashes, nil
+}
+
+// purchaseTickets purchases up to n tickets with the harness wallet at the
+// next stake difficulty.  Running out of funds is not an error.
+func (h *Harness) purchaseTickets(n, minConf int) error {
+	stakeDiff, err := h.WalletRPC.GetStakeDifficulty()
+	if err != nil {
+		return err
+	}
+	priceLimit, err := hcutil.NewAmount(2 * stakeDiff.NextStakeDifficulty)
+	if err != nil {
+		return err
+	}
+	_, err = h.WalletRPC.PurchaseTicket("default", priceLimit, &minConf,
+		nil, &n, nil, nil, nil, nil)
+	if isInsufficientFunds(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to purchase tickets: %v", err)
+	}
+	return nil
+}
+
+// isInsufficientFunds returns whether an RPC error reports that the wallet
+// does not have the funds to create a transaction.
+func isInsufficientFunds(err error) bool {
+	rpcErr, ok := err.(*hcjson.RPCError)
+	if !ok {
+		return false
+	}
+	return rpcErr.Code == hcjson.ErrRPCWalletInsufficientFunds ||
+		strings.Contains(rpcErr.Message, txauthor.InsufficientFundsError{}.Error())
+}
+
+// waitForWalletHeight waits until the harness wallet has synced to a block
+// height.
+func (h *Harness) waitForWalletHeight(height int64) error {
+	deadline := time.Now().Add(voteWaitTimeout)
+	for {
+		count, err := h.WalletRPC.GetBlockCount()
+		if err != nil {
+			return err
+		}
+		if count >= height {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wallet did not sync to height %d", height)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// waitForVotes waits until the node's mempool contains at least n votes on a
+// block.
+func (h *Harness) waitForVotes(block *chainhash.Hash, n int) error {
+	deadline := time.Now().Add(voteWaitTimeout)
+	for {
+		votes, err := h.Node.GetRawMempool(hcjson.GRMVotes)
+		if err != nil {
+			return err
+		}
+		var found int
+		for _, hash := range votes {
+			tx, err := h.Node.GetRawTransaction(hash)
+			if err != nil {
+				// The vote may have been removed from the mempool.
+				continue
+			}
+			votedOn, _, err := stake.SSGenBlockVotedOn(tx.MsgTx())
+			if err == nil && votedOn == *block {
+				found++
+			}
+		}
+		if found >= n {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("only %d of %d votes on block %v reached "+
+				"the mempool", found, n, block)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
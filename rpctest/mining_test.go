@@ -0,0 +1,40 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+)
+
+func TestIsInsufficientFunds(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		insufficient bool
+	}{
+		{"no error", nil, false},
+		{"other error", errors.New("insufficient funds"), false},
+		{"insufficient funds code", &hcjson.RPCError{
+			Code: hcjson.ErrRPCWalletInsufficientFunds}, true},
+		{"insufficient funds message", &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWallet,
+			Message: "purchase failed: " + txauthor.InsufficientFundsError{}.Error(),
+		}, true},
+		{"other RPC error", &hcjson.RPCError{
+			Code:    hcjson.ErrRPCWallet,
+			Message: "wallet is locked",
+		}, false},
+	}
+	for _, test := range tests {
+		if got := isInsufficientFunds(test.err); got != test.insufficient {
+			t.Errorf("%s: insufficient funds %v, want %v", test.name, got,
+				test.insufficient)
+		}
+	}
+}
@@ -81,6 +81,7 @@ type Harness struct {
 	maxConnRetries int
 	nodeNum        int
 	miningAddr     hcutil.Address
+	walletSeed     []byte
 }
 
 // NewHarness creates and initializes a new instance of the rpc test harness.
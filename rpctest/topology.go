@@ -0,0 +1,165 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	rpc "github.com/HcashOrg/hcrpcclient"
+)
+
+// JoinType is an enum representing a particular type of "node join".  A node
+// join is a synchronization tool used to wait until a subset of nodes have a
+// consistent state with respect to an attribute.
+type JoinType uint8
+
+const (
+	// Blocks is a JoinType which waits until all nodes share the same
+	// block height and best block, and each harness wallet has synced to
+	// it.
+	Blocks JoinType = iota
+
+	// Mempools is a JoinType which blocks until all nodes have identical
+	// mempools.
+	Mempools
+)
+
+// joinTimeout is the time JoinNodes and ConnectNode wait for the harnesses to
+// reach a consistent state before failing.
+const joinTimeout = time.Minute
+
+// JoinNodes is a synchronization tool used to block until all passed nodes
+// are fully synced with respect to an attribute.  This function will block
+// until the nodes reach the consistent state, or return an error if they do
+// not before a timeout.
+func JoinNodes(nodes []*Harness, joinType JoinType) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	switch joinType {
+	case Blocks:
+		return syncBlocks(nodes)
+	case Mempools:
+		return syncMempools(nodes)
+	}
+	return fmt.Errorf("unknown join type %d", joinType)
+}
+
+// syncMempools blocks until all nodes have identical mempools.
+func syncMempools(nodes []*Harness) error {
+	deadline := time.Now().Add(joinTimeout)
+	for {
+		firstPool, err := nodes[0].Node.GetRawMempool(hcjson.GRMAll)
+		if err != nil {
+			return err
+		}
+		poolsMatch := true
+		for _, node := range nodes[1:] {
+			nodePool, err := node.Node.GetRawMempool(hcjson.GRMAll)
+			if err != nil {
+				return err
+			}
+			if !sameHashes(firstPool, nodePool) {
+				poolsMatch = false
+				break
+			}
+		}
+		if poolsMatch {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("mempools did not sync")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// syncBlocks blocks until all nodes report the same best block, and each
+// harness wallet has synced to its height.
+func syncBlocks(nodes []*Harness) error {
+	deadline := time.Now().Add(joinTimeout)
+	for {
+		firstHash, firstHeight, err := nodes[0].Node.GetBestBlock()
+		if err != nil {
+			return err
+		}
+		blocksMatch := true
+		for _, node := range nodes[1:] {
+			hash, height, err := node.Node.GetBestBlock()
+			if err != nil {
+				return err
+			}
+			if height != firstHeight || *hash != *firstHash {
+				blocksMatch = false
+				break
+			}
+		}
+		if blocksMatch {
+			for _, node := range nodes {
+				err := node.waitForWalletHeight(firstHeight)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("best blocks did not sync")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// sameHashes returns whether two slices contain the same hashes, ignoring
+// their order.
+func sameHashes(a, b []*chainhash.Hash) bool {
+	setA := make(map[chainhash.Hash]struct{}, len(a))
+	for _, hash := range a {
+		setA[*hash] = struct{}{}
+	}
+	setB := make(map[chainhash.Hash]struct{}, len(b))
+	for _, hash := range b {
+		setB[*hash] = struct{}{}
+	}
+	return reflect.DeepEqual(setA, setB)
+}
+
+// ConnectNode establishes a new peer-to-peer connection between the "from"
+// harness and the "to" harness.  The connection made is flagged as persistent,
+// therefore in the case of disconnects, "from" will attempt to reestablish a
+// connection to the "to" harness.  This function blocks until "from" reports
+// the new peer.
+func ConnectNode(from *Harness, to *Harness) error {
+	peerInfo, err := from.Node.GetPeerInfo()
+	if err != nil {
+		return err
+	}
+	numPeers := len(peerInfo)
+
+	targetAddr := to.node.config.listen
+	if err := from.Node.AddNode(targetAddr, rpc.ANAdd); err != nil {
+		return err
+	}
+
+	// Block until a new connection has been established.
+	deadline := time.Now().Add(joinTimeout)
+	for {
+		peerInfo, err = from.Node.GetPeerInfo()
+		if err != nil {
+			return err
+		}
+		if len(peerInfo) > numPeers {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("unable to connect to %s", targetAddr)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+)
+
+func TestSameHashes(t *testing.T) {
+	a, b, c := &chainhash.Hash{1}, &chainhash.Hash{2}, &chainhash.Hash{3}
+	tests := []struct {
+		name string
+		x, y []*chainhash.Hash
+		same bool
+	}{
+		{"empty", nil, []*chainhash.Hash{}, true},
+		{"same order", []*chainhash.Hash{a, b}, []*chainhash.Hash{a, b}, true},
+		{"other order", []*chainhash.Hash{a, b}, []*chainhash.Hash{b, a}, true},
+		{"copied hashes", []*chainhash.Hash{a}, []*chainhash.Hash{{1}}, true},
+		{"missing hash", []*chainhash.Hash{a, b}, []*chainhash.Hash{a}, false},
+		{"other hash", []*chainhash.Hash{a, b}, []*chainhash.Hash{a, c}, false},
+	}
+	for _, test := range tests {
+		if same := sameHashes(test.x, test.y); same != test.same {
+			t.Errorf("%s: same %v, want %v", test.name, same, test.same)
+		}
+	}
+}
+
+func TestJoinNodesUnknownType(t *testing.T) {
+	if err := JoinNodes(nil, Blocks); err != nil {
+		t.Errorf("joining no nodes: %v", err)
+	}
+	nodes := []*Harness{{ActiveNet: &chaincfg.SimNetParams}}
+	if err := JoinNodes(nodes, Mempools+1); err == nil {
+		t.Error("joined nodes with an unknown join type")
+	}
+}
+
+// TestConnectedHarnesses connects two harnesses, mines blocks with votes on
+// one, and waits for the other and its wallet to sync to them.
+func TestConnectedHarnesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping RPC harness tests in short mode")
+	}
+
+	params := &chaincfg.SimNetParams
+	nodes := make([]*Harness, 2)
+	for i := range nodes {
+		h, err := NewHarness(params, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer h.TearDown()
+		if err := h.SetUp(true, 25); err != nil {
+			t.Fatal(err)
+		}
+		nodes[i] = h
+	}
+	if err := ConnectNode(nodes[1], nodes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := JoinNodes(nodes, Blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mining through stake validation height requires votes.
+	if err := nodes[0].WalletRPC.WalletPassphrase("password", 0); err != nil {
+		t.Fatal(err)
+	}
+	_, height, err := nodes[0].Node.GetBestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := uint32(params.StakeValidationHeight - height + 2)
+	hashes, err := nodes[0].MineBlocksWithVotes(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint32(len(hashes)) != n {
+		t.Errorf("mined %d blocks, want %d", len(hashes), n)
+	}
+	if err := JoinNodes(nodes, Blocks); err != nil {
+		t.Fatal(err)
+	}
+	if err := JoinNodes(nodes, Mempools); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcwallet/chain"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+)
+
+// InProcessWallet is a wallet running in the test process, connected to the
+// node of a Harness.  Unlike the harness wallet, which is a separate hcwallet
+// process driven over RPC, the in-process wallet allows tests to call the
+// wallet package directly.
+type InProcessWallet struct {
+	Wallet *wallet.Wallet
+
+	seed        []byte
+	params      *chaincfg.Params
+	loader      *loader.Loader
+	chainClient *chain.RPCClient
+	dataDir     string
+}
+
+// NewInProcessWallet creates a new temporary wallet with a random seed and the
+// passed passphrases, connects it to the harness node, and waits for it to sync
+// to the node's best block.  The harness must be set up, and the returned
+// wallet must be shut down with TearDown.
+func (h *Harness) NewInProcessWallet(pubPass, privPass []byte) (*InProcessWallet, error) {
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	if err != nil {
+		return nil, err
+	}
+	return h.NewInProcessWalletFromSeed(seed, pubPass, privPass)
+}
+
+// NewInProcessWalletFromSeed creates a new temporary wallet from a seed, as
+// with NewInProcessWallet, so that the addresses of the wallet are the same in
+// every run.
+func (h *Harness) NewInProcessWalletFromSeed(seed, pubPass, privPass []byte) (*InProcessWallet, error) {
+	if h.Node == nil {
+		return nil, fmt.Errorf("harness is not set up")
+	}
+
+	dataDir, err := ioutil.TempDir(h.testWalletDir, "inproc")
+	if err != nil {
+		return nil, err
+	}
+	iw := &InProcessWallet{seed: seed, params: h.ActiveNet, dataDir: dataDir}
+
+	stakeOptions := &loader.StakeOptions{}
+	iw.loader = loader.NewLoader(h.ActiveNet, dataDir, stakeOptions,
+		wallet.DefaultGapLimit, false, txauthor.TxLimits{}, false,
+		txrules.DefaultRelayFeePerKb.ToCoin(), false)
+	iw.Wallet, err = iw.loader.CreateNewWallet(pubPass, privPass, seed, nil)
+	if err != nil {
+		iw.TearDown()
+		return nil, err
+	}
+
+	// Connect to the node with the same settings as the harness wallet.
+	conf := h.node.config
+	iw.chainClient, err = chain.NewRPCClient(h.ActiveNet, conf.rpcListen,
+		conf.rpcUser, conf.rpcPass, conf.certificates, false, 0)
+	if err != nil {
+		iw.TearDown()
+		return nil, err
+	}
+	err = iw.chainClient.Start()
+	if err != nil {
+		iw.TearDown()
+		return nil, err
+	}
+	iw.Wallet.SynchronizeRPC(iw.chainClient)
+	iw.loader.SetChainClient(iw.chainClient.Client)
+
+	err = iw.WaitForSync(h, time.Minute)
+	if err != nil {
+		iw.TearDown()
+		return nil, err
+	}
+	return iw, nil
+}
+
+// WaitForSync waits until the main chain tip of the wallet is the best block
+// of the harness node, returning an error if the wallet has not synced before
+// the timeout.
+func (iw *InProcessWallet) WaitForSync(h *Harness, timeout time.Duration) error {
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		bestHash, _, err := h.Node.GetBestBlock()
+		if err != nil {
+			return err
+		}
+		tipHash, _ := iw.Wallet.MainChainTip()
+		if tipHash == *bestHash {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return fmt.Errorf("wallet did not sync to block %v", bestHash)
+		}
+	}
+}
+
+// Addresses returns count addresses of the default account of the wallet,
+// beginning at index start of the external (udb.ExternalBranch) or internal
+// (udb.InternalBranch) branch.
+func (iw *InProcessWallet) Addresses(branch, start, count uint32) ([]hcutil.Address, error) {
+	return deriveSeedAddresses(iw.seed, iw.params, branch, start, count)
+}
+
+// TearDown stops the wallet and its connection to the node, and removes the
+// temporary wallet directory.
+func (iw *InProcessWallet) TearDown() error {
+	if iw.chainClient != nil {
+		iw.chainClient.Stop()
+		iw.chainClient.WaitForShutdown()
+	}
+	if iw.Wallet != nil {
+		err := iw.loader.UnloadWallet()
+		if err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(iw.dataDir)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestInProcessWalletRequiresSetUp(t *testing.T) {
+	h := &Harness{ActiveNet: &chaincfg.SimNetParams}
+	if _, err := h.NewInProcessWallet([]byte("public"), []byte("private")); err == nil {
+		t.Error("created an in-process wallet without a harness node")
+	}
+}
+
+func TestInProcessWallet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping RPC harness tests in short mode")
+	}
+
+	h, err := NewHarness(&chaincfg.SimNetParams, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.TearDown()
+	if err := h.SetUp(true, 25); err != nil {
+		t.Fatal(err)
+	}
+
+	seed := bytes.Repeat([]byte{1}, 32)
+	iw, err := h.NewInProcessWalletFromSeed(seed, []byte("public"),
+		[]byte("private"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iw.TearDown()
+
+	addrs, err := iw.Addresses(udb.ExternalBranch, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := iw.Wallet.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if addrs[i].EncodeAddress() != want[i].EncodeAddress() {
+			t.Errorf("address %d is %v, want %v", i, addrs[i], want[i])
+		}
+	}
+
+	// The wallet follows blocks generated by the harness node.
+	_, height, err := h.Node.GetBestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.GenerateBlock(uint32(height)); err != nil {
+		t.Fatal(err)
+	}
+	if err := iw.WaitForSync(h, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, tipHeight := iw.Wallet.MainChainTip(); int64(tipHeight) != height+1 {
+		t.Errorf("wallet synced to height %d, want %d", tipHeight, height+1)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SetWalletSeed creates the harness wallet from a seed, rather than from the
+// random seed used by hcwallet's --createtemp, so that the addresses of the
+// wallet are the same in every run.  The wallet keeps the simulation
+// passphrases.  It must be called before SetUp.
+func (h *Harness) SetWalletSeed(seed []byte) error {
+	if h.WalletRPC != nil {
+		return fmt.Errorf("harness wallet has already been started")
+	}
+
+	// hcwallet loads an existing wallet in its data directory instead of
+	// creating a temporary wallet.
+	netDir := filepath.Join(h.wallet.config.dataDir, h.ActiveNet.Name)
+	err := os.MkdirAll(netDir, 0700)
+	if err != nil {
+		return err
+	}
+	db, err := walletdb.Create("bdb", filepath.Join(netDir, "wallet.db"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	err = wallet.Create(db, []byte(wallet.InsecurePubPassphrase),
+		wallet.SimulationPassphrase, seed, h.ActiveNet)
+	if err != nil {
+		return err
+	}
+
+	h.walletSeed = seed
+	return nil
+}
+
+// WalletAddresses returns count addresses of the default account of the
+// harness wallet, beginning at index start of the external (udb.ExternalBranch)
+// or internal (udb.InternalBranch) branch.  The wallet seed must have been set
+// with SetWalletSeed.
+func (h *Harness) WalletAddresses(branch, start, count uint32) ([]hcutil.Address, error) {
+	if h.walletSeed == nil {
+		return nil, fmt.Errorf("harness wallet seed is not set")
+	}
+	return deriveSeedAddresses(h.walletSeed, h.ActiveNet, branch, start, count)
+}
+
+// deriveSeedAddresses derives addresses of the default account of a wallet
+// created from seed, following the BIP0044 hierarchy used by the address
+// manager:
+//
+//	m/44'/<coin type>'/0'/<branch>/<address index>
+func deriveSeedAddresses(seed []byte, params *chaincfg.Params, branch, start, count uint32) ([]hcutil.Address, error) {
+	root, err := hdkeychain.NewMaster(seed, params)
+	if err != nil {
+		return nil, err
+	}
+	purpose, err := root.Child(44 + hdkeychain.HardenedKeyStart)
+	if err != nil {
+		return nil, err
+	}
+	coinType, err := purpose.Child(params.HDCoinType + hdkeychain.HardenedKeyStart)
+	if err != nil {
+		return nil, err
+	}
+	account, err := coinType.SwitchChild(udb.DefaultAccountNum+hdkeychain.HardenedKeyStart,
+		udb.AcctypeEc)
+	if err != nil {
+		return nil, err
+	}
+	branchKey, err := account.Child(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]hcutil.Address, 0, count)
+	for i := start; i < start+count; i++ {
+		child, err := branchKey.Child(i)
+		if err == hdkeychain.ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		addr, err := child.Address(params, udb.AcctypeEc)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	rpc "github.com/HcashOrg/hcrpcclient"
+	"github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestDeriveSeedAddresses(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpctest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	params := &chaincfg.SimNetParams
+	seed := bytes.Repeat([]byte{1}, 32)
+	l := loader.NewLoader(params, dir, &loader.StakeOptions{},
+		wallet.DefaultGapLimit, false, txauthor.TxLimits{}, false, 0.001, false)
+	w, err := l.CreateNewWallet([]byte(wallet.InsecurePubPassphrase),
+		wallet.SimulationPassphrase, seed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.UnloadWallet()
+
+	// Derived addresses are those of a wallet created from the seed.
+	for _, branch := range []uint32{udb.ExternalBranch, udb.InternalBranch} {
+		want, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+			branch, 3, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs, err := deriveSeedAddresses(seed, params, branch, 3, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(addrs) != len(want) {
+			t.Fatalf("branch %d: derived %d addresses, want %d", branch,
+				len(addrs), len(want))
+		}
+		for i := range addrs {
+			if addrs[i].EncodeAddress() != want[i].EncodeAddress() {
+				t.Errorf("branch %d: address %d is %v, want %v", branch,
+					3+i, addrs[i], want[i])
+			}
+		}
+	}
+}
+
+func TestWalletSeedErrors(t *testing.T) {
+	h := &Harness{ActiveNet: &chaincfg.SimNetParams}
+	if _, err := h.WalletAddresses(udb.ExternalBranch, 0, 1); err == nil {
+		t.Error("derived harness wallet addresses without a seed")
+	}
+
+	h.WalletRPC = &rpc.Client{}
+	if err := h.SetWalletSeed(bytes.Repeat([]byte{1}, 32)); err == nil {
+		t.Error("set the seed of a started harness wallet")
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/internal/prompt"
+	"github.com/HcashOrg/hcwallet/internal/zero"
+	ldr "github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	flags "github.com/jessevdk/go-flags"
+)
+
+// offlineCommands are the subcommands of hcwallet which operate directly on
+// the wallet database and exit, without connecting to hcd or starting the RPC
+// servers.  They allow air-gapped machines to create wallets and sign
+// transactions.
+type offlineCommands struct {
+	Create createCommand
+	Sign   signCommand
+	Dump   dumpCommand
+}
+
+type createCommand struct {
+	FromMnemonic bool `long:"from-mnemonic" description:"Restore the wallet from the mnemonic or hex encoding of an existing seed, entered at a prompt"`
+}
+
+type signCommand struct {
+	Tx  string `long:"tx" description:"Path of the JSON file describing the transaction to sign"`
+	Out string `long:"out" description:"Write the signed transaction to this file rather than standard output"`
+}
+
+type dumpCommand struct {
+	Accounts bool `long:"accounts" description:"Dump the names, numbers, extended public keys, address indexes and balances of all accounts"`
+}
+
+// addOfflineCommands adds the offline subcommands to a command line parser.
+// The subcommands are optional, and hcwallet runs normally when none is
+// given.
+func addOfflineCommands(parser *flags.Parser, cmds *offlineCommands) error {
+	parser.SubcommandsOptional = true
+	_, err := parser.AddCommand("create", "Create a wallet and exit",
+		"Create the wallet database as with --create and exit.",
+		&cmds.Create)
+	if err != nil {
+		return err
+	}
+	_, err = parser.AddCommand("sign", "Sign a transaction and exit",
+		"Sign the wallet inputs of a transaction read from a JSON file of the "+
+			"form {\"hex\":\"<unsigned tx>\",\"inputs\":[...]}, where inputs "+
+			"are described as with signrawtransaction, and write the "+
+			"signrawtransaction result.",
+		&cmds.Sign)
+	if err != nil {
+		return err
+	}
+	_, err = parser.AddCommand("dump", "Dump wallet data and exit",
+		"Write wallet data read from the wallet database as JSON.",
+		&cmds.Dump)
+	return err
+}
+
+// offlineSignRequest is the JSON encoding of a transaction to be signed by the
+// sign subcommand.  Inputs provide the previous output scripts of inputs which
+// do not spend wallet outputs, as the transaction store of an air-gapped
+// wallet may not record them.
+type offlineSignRequest struct {
+	Hex    string              `json:"hex"`
+	Inputs []hcjson.RawTxInput `json:"inputs"`
+}
+
+// dumpedAccount is the JSON encoding of an account written by the dump
+// subcommand.
+type dumpedAccount struct {
+	Account          uint32  `json:"account"`
+	Name             string  `json:"name"`
+	ExtendedPubKey   string  `json:"xpub,omitempty"`
+	LastUsedExternal uint32  `json:"lastusedexternal"`
+	LastUsedInternal uint32  `json:"lastusedinternal"`
+	ImportedKeys     uint32  `json:"importedkeys,omitempty"`
+	Balance          float64 `json:"balance"`
+}
+
+// runOfflineCommand opens the wallet database and runs the offline subcommand
+// selected on the command line.
+func runOfflineCommand(cfg *config) error {
+	reader := bufio.NewReader(os.Stdin)
+	pubPass := []byte(cfg.WalletPass)
+	if cfg.PromptPublicPass {
+		var err error
+		pubPass, err = prompt.PassPrompt(reader, "Enter public wallet passphrase", false)
+		if err != nil {
+			return err
+		}
+	}
+	defer zero.Bytes(pubPass)
+
+	// The private passphrase is only required to sign transactions.
+	privPass := []byte(cfg.Pass)
+	if cfg.offlineCmd == "sign" && len(privPass) == 0 {
+		var err error
+		privPass, err = prompt.PassPrompt(reader, "Enter private wallet passphrase", false)
+		if err != nil {
+			return err
+		}
+	}
+	defer zero.Bytes(privPass)
+
+	dbDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
+	loader := ldr.NewLoader(activeNet.Params, dbDir, &ldr.StakeOptions{},
+		cfg.GapLimit, cfg.AllowHighFees, txauthor.TxLimits{
+			MaxInputs:        cfg.MaxTxInputs,
+			MaxSerializeSize: cfg.MaxTxSize,
+		}, cfg.SplitTxs,
+		cfg.RelayFee.ToCoin(), cfg.EnableOmni)
+	loader.SetDatabaseEncryption(cfg.EncryptDB)
+	loader.SetDatabaseDriver(cfg.DBDriver)
+	loader.SetGapLimitLookahead(cfg.GapLimitLookahead)
+	w, err := loader.OpenExistingWallet(pubPass, privPass)
+	if err != nil {
+		return err
+	}
+	defer loader.UnloadWallet()
+
+	switch cfg.offlineCmd {
+	case "sign":
+		return offlineSign(cfg, w, privPass)
+	case "dump":
+		return offlineDumpAccounts(w)
+	}
+	return fmt.Errorf("unknown command %q", cfg.offlineCmd)
+}
+
+// offlineSign signs the wallet inputs of the transaction described by the file
+// of the sign subcommand.
+func offlineSign(cfg *config, w *wallet.Wallet, privPass []byte) error {
+	b, err := ioutil.ReadFile(cfg.offlineCmds.Sign.Tx)
+	if err != nil {
+		return err
+	}
+	var req offlineSignRequest
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s: %v", cfg.offlineCmds.Sign.Tx, err)
+	}
+	serializedTx, err := hex.DecodeString(req.Hex)
+	if err != nil {
+		return fmt.Errorf("invalid transaction hex: %v", err)
+	}
+	tx := wire.NewMsgTx()
+	err = tx.Deserialize(bytes.NewReader(serializedTx))
+	if err != nil {
+		return fmt.Errorf("invalid transaction: %v", err)
+	}
+
+	prevScripts := make(map[wire.OutPoint][]byte, len(req.Inputs))
+	for _, in := range req.Inputs {
+		hash, err := chainhash.NewHashFromStr(in.Txid)
+		if err != nil {
+			return err
+		}
+		script, err := hex.DecodeString(in.ScriptPubKey)
+		if err != nil {
+			return fmt.Errorf("invalid script of input %v:%d: %v",
+				hash, in.Vout, err)
+		}
+		op := wire.OutPoint{Hash: *hash, Index: in.Vout, Tree: in.Tree}
+		prevScripts[op] = script
+	}
+
+	err = w.Unlock(privPass, nil)
+	if err != nil {
+		return err
+	}
+	signErrs, err := w.SignTransaction(tx, txscript.SigHashAll, prevScripts,
+		nil, nil)
+	w.Lock()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializeSize())
+	err = tx.Serialize(&buf)
+	if err != nil {
+		return err
+	}
+	result := hcjson.SignRawTransactionResult{
+		Hex:      hex.EncodeToString(buf.Bytes()),
+		Complete: len(signErrs) == 0,
+	}
+	for _, e := range signErrs {
+		input := tx.TxIn[e.InputIndex]
+		result.Errors = append(result.Errors, hcjson.SignRawTransactionError{
+			TxID:      input.PreviousOutPoint.Hash.String(),
+			Vout:      input.PreviousOutPoint.Index,
+			ScriptSig: hex.EncodeToString(input.SignatureScript),
+			Sequence:  input.Sequence,
+			Error:     e.Error.Error(),
+		})
+	}
+	return writeOfflineResult(result, cfg.offlineCmds.Sign.Out)
+}
+
+// offlineDumpAccounts writes the accounts of the wallet.
+func offlineDumpAccounts(w *wallet.Wallet) error {
+	res, err := w.Accounts()
+	if err != nil {
+		return err
+	}
+	accounts := make([]dumpedAccount, 0, len(res.Accounts))
+	for _, a := range res.Accounts {
+		d := dumpedAccount{
+			Account:          a.AccountNumber,
+			Name:             a.AccountName,
+			LastUsedExternal: a.LastUsedExternalIndex,
+			LastUsedInternal: a.LastUsedInternalIndex,
+			ImportedKeys:     a.ImportedKeyCount,
+			Balance:          a.TotalBalance.ToCoin(),
+		}
+		if a.AccountNumber != udb.ImportedAddrAccount {
+			d.ExtendedPubKey, err = w.MasterPubKey(a.AccountNumber)
+			if err != nil {
+				return err
+			}
+		}
+		accounts = append(accounts, d)
+	}
+	return writeOfflineResult(accounts, "")
+}
+
+// writeOfflineResult writes the JSON encoding of the result of an offline
+// subcommand to path, or to standard output when path is empty.
+func writeOfflineResult(result interface{}, path string) error {
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if path != "" {
+		return ioutil.WriteFile(path, b, 0600)
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
+// checkOfflineCommand validates the options of the offline subcommand selected
+// on the command line.
+func checkOfflineCommand(cfg *config) error {
+	switch cfg.offlineCmd {
+	case "create":
+		if cfg.CreateTemp || cfg.CreateWatchingOnly {
+			return errors.New("the create command may not be used with " +
+				"--createtemp or --createwatchingonly")
+		}
+	case "sign":
+		if cfg.offlineCmds.Sign.Tx == "" {
+			return errors.New("the sign command requires --tx")
+		}
+		cfg.offlineCmds.Sign.Tx = cleanAndExpandPath(cfg.offlineCmds.Sign.Tx)
+		if cfg.offlineCmds.Sign.Out != "" {
+			cfg.offlineCmds.Sign.Out = cleanAndExpandPath(cfg.offlineCmds.Sign.Out)
+		}
+	case "dump":
+		if !cfg.offlineCmds.Dump.Accounts {
+			return errors.New("the dump command requires --accounts")
+		}
+	}
+	if cfg.offlineCmd != "create" && (cfg.Create || cfg.CreateTemp ||
+		cfg.CreateWatchingOnly) {
+		return fmt.Errorf("the %s command may not be used when creating "+
+			"a wallet", cfg.offlineCmd)
+	}
+	if cfg.CheckDB {
+		return fmt.Errorf("the %s command may not be used with --checkdb",
+			cfg.offlineCmd)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	ldr "github.com/HcashOrg/hcwallet/loader"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	flags "github.com/jessevdk/go-flags"
+)
+
+func TestParseOfflineCommands(t *testing.T) {
+	var opts struct {
+		Create bool `long:"create"`
+	}
+	var cmds offlineCommands
+	parser := flags.NewParser(&opts, flags.Default)
+	if err := addOfflineCommands(parser, &cmds); err != nil {
+		t.Fatal(err)
+	}
+
+	// Subcommands are optional.
+	if _, err := parser.ParseArgs(nil); err != nil {
+		t.Fatal(err)
+	}
+	if parser.Active != nil {
+		t.Errorf("command %q is active without a subcommand",
+			parser.Active.Name)
+	}
+
+	_, err := parser.ParseArgs([]string{"sign", "--tx", "tx.json", "--out",
+		"signed.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parser.Active == nil || parser.Active.Name != "sign" {
+		t.Fatalf("active command %v, want sign", parser.Active)
+	}
+	if cmds.Sign.Tx != "tx.json" || cmds.Sign.Out != "signed.json" {
+		t.Errorf("sign options %+v", cmds.Sign)
+	}
+}
+
+func TestCheckOfflineCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config
+		valid bool
+	}{
+		{"create", config{offlineCmd: "create"}, true},
+		{"create with createtemp", config{offlineCmd: "create",
+			CreateTemp: true}, false},
+		{"sign", config{offlineCmd: "sign", offlineCmds: offlineCommands{
+			Sign: signCommand{Tx: "/tmp/tx.json"}}}, true},
+		{"sign without tx", config{offlineCmd: "sign"}, false},
+		{"sign when creating", config{offlineCmd: "sign", Create: true,
+			offlineCmds: offlineCommands{
+				Sign: signCommand{Tx: "/tmp/tx.json"}}}, false},
+		{"dump", config{offlineCmd: "dump", offlineCmds: offlineCommands{
+			Dump: dumpCommand{Accounts: true}}}, true},
+		{"dump without accounts", config{offlineCmd: "dump"}, false},
+		{"dump with checkdb", config{offlineCmd: "dump", CheckDB: true,
+			offlineCmds: offlineCommands{
+				Dump: dumpCommand{Accounts: true}}}, false},
+	}
+	for _, test := range tests {
+		err := checkOfflineCommand(&test.cfg)
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: invalid options were accepted", test.name)
+		}
+	}
+}
+
+func TestOfflineSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subcommands")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The log rotator is not initialized by tests.
+	setLogLevels("off")
+	defer setLogLevels(defaultLogLevel)
+
+	loader := ldr.NewLoader(&chaincfg.SimNetParams, dir, &ldr.StakeOptions{},
+		20, false, txauthor.TxLimits{}, false, 0.001, false)
+	privPass := []byte("private")
+	w, err := loader.CreateNewWallet([]byte("public"), privPass,
+		bytes.Repeat([]byte{1}, 32), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loader.UnloadWallet()
+
+	// The signing address must be recorded by the address manager.
+	err = w.ExtendWatchedAddresses(udb.DefaultAccountNum, udb.ExternalBranch, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.NewExternalAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The spent output is not recorded by the wallet, so its script is
+	// provided by the request.
+	prevHash := chainhash.Hash{1}
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 0, wire.TxTreeRegular), nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	req, err := json.Marshal(&offlineSignRequest{
+		Hex: hex.EncodeToString(buf.Bytes()),
+		Inputs: []hcjson.RawTxInput{{
+			Txid:         prevHash.String(),
+			ScriptPubKey: hex.EncodeToString(pkScript),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{}
+	cfg.offlineCmds.Sign.Tx = filepath.Join(dir, "tx.json")
+	cfg.offlineCmds.Sign.Out = filepath.Join(dir, "signed.json")
+	if err := ioutil.WriteFile(cfg.offlineCmds.Sign.Tx, req, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := offlineSign(cfg, w, privPass); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Locked() {
+		t.Error("wallet was left unlocked after signing")
+	}
+	b, err := ioutil.ReadFile(cfg.offlineCmds.Sign.Out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result hcjson.SignRawTransactionResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Complete || len(result.Errors) != 0 {
+		t.Errorf("signing was incomplete: %+v", result.Errors)
+	}
+	if result.Hex == hex.EncodeToString(buf.Bytes()) {
+		t.Error("signed transaction is unchanged")
+	}
+
+	// Signing requires the private passphrase.
+	if err := offlineSign(cfg, w, []byte("wrong")); err == nil {
+		t.Error("signed with the wrong private passphrase")
+	}
+}
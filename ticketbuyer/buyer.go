@@ -826,7 +826,7 @@ func (t *TicketPurchaser) Purchase(height int64) (*PurchaseStats, error) {
 
 	// Ticket purchase requires 2 blocks to confirm
 	expiry := int32(int(height) + t.ExpiryDelta() + 2)
-	hashes, purchaseErr := t.wallet.PurchaseTickets(0,
+	res, purchaseErr := t.wallet.PurchaseTickets(0,
 		maxPriceAmt,
 		0, // 0 minconf is used so tickets can be bought from split outputs
 		ticketAddress,
@@ -838,14 +838,20 @@ func (t *TicketPurchaser) Purchase(height int64) (*PurchaseStats, error) {
 		t.wallet.RelayFee(),
 		t.wallet.TicketFeeIncrement(),
 	)
-	for i := range hashes {
-		log.Infof("Purchased ticket %v at stake difficulty %v (%v "+
-			"fees per KB used)", hashes[i], nextStakeDiff.ToCoin(),
-			feeToUse.ToCoin())
+	if res != nil {
+		for _, hash := range res.TicketHashes {
+			log.Infof("Purchased ticket %v at stake difficulty %v (%v "+
+				"fees per KB used)", hash, nextStakeDiff.ToCoin(),
+				feeToUse.ToCoin())
+		}
+		for _, f := range res.Failures {
+			log.Errorf("Ticket %d could not be purchased: %v", f.Index+1,
+				f.Err)
+		}
+		ps.Purchased = len(res.TicketHashes)
 	}
-	ps.Purchased = len(hashes)
 	if purchaseErr != nil {
-		log.Errorf("One or more tickets could not be purchased: %v", purchaseErr)
+		log.Errorf("Tickets could not be purchased: %v", purchaseErr)
 	}
 
 	bal, err = t.wallet.CalculateAccountBalance(account, 0)
@@ -855,7 +861,7 @@ func (t *TicketPurchaser) Purchase(height int64) (*PurchaseStats, error) {
 	log.Debugf("Usable balance for account '%s' after purchases: %v", accountName, bal.Spendable)
 	ps.Balance = bal.Spendable
 
-	if len(hashes) == 0 && purchaseErr != nil {
+	if purchaseErr != nil {
 		return ps, purchaseErr
 	}
 
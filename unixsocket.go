@@ -0,0 +1,86 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// makeUnixListeners listens on each unix domain socket path, setting the
+// socket file permissions from the config.  Socket files remaining from a
+// previous process are removed, but a socket which accepts connections is
+// never replaced.  Listeners are not wrapped with TLS, as access to the
+// sockets is controlled by their file permissions.
+func makeUnixListeners(paths []string) ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(paths))
+	closeAll := func() {
+		for _, lis := range listeners {
+			lis.Close()
+		}
+	}
+	for _, path := range paths {
+		fi, err := os.Lstat(path)
+		if err == nil {
+			if fi.Mode()&os.ModeSocket == 0 {
+				closeAll()
+				return nil, fmt.Errorf("%s exists and is not a unix "+
+					"domain socket", path)
+			}
+			if conn, err := net.Dial("unix", path); err == nil {
+				conn.Close()
+				closeAll()
+				return nil, fmt.Errorf("unix domain socket %s is in use",
+					path)
+			}
+			err = os.Remove(path)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+		}
+
+		lis, err := net.Listen("unix", path)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		listeners = append(listeners, lis)
+		err = os.Chmod(path, cfg.unixSocketPerm)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+	}
+	return listeners, nil
+}
+
+// isUnixAddr returns whether addr is the address of a unix domain socket.
+func isUnixAddr(addr net.Addr) bool {
+	return addr != nil && addr.Network() == "unix"
+}
+
+// unixSocketCreds performs TLS handshakes only for connections which were not
+// accepted from unix domain sockets, so a single gRPC server may serve both
+// TLS listeners and unix domain sockets without TLS.
+type unixSocketCreds struct {
+	credentials.TransportCredentials
+}
+
+// ServerHandshake implements the credentials.TransportCredentials interface.
+func (c unixSocketCreds) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if isUnixAddr(conn.LocalAddr()) {
+		return conn, nil, nil
+	}
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+// Clone implements the credentials.TransportCredentials interface.
+func (c unixSocketCreds) Clone() credentials.TransportCredentials {
+	return unixSocketCreds{c.TransportCredentials.Clone()}
+}
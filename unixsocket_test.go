@@ -0,0 +1,99 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakeUnixListeners(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unixsocket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(c *config) { cfg = c }(cfg)
+	cfg = &config{unixSocketPerm: 0640}
+
+	// Socket files remaining from a previous process are replaced.
+	stalePath := filepath.Join(dir, "stale")
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: stalePath, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+
+	paths := []string{filepath.Join(dir, "new"), stalePath}
+	listeners, err := makeUnixListeners(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, lis := range listeners {
+			lis.Close()
+		}
+	}()
+	if len(listeners) != len(paths) {
+		t.Fatalf("%d listeners, want %d", len(listeners), len(paths))
+	}
+	for i, path := range paths {
+		if !isUnixAddr(listeners[i].Addr()) {
+			t.Errorf("%s: listener address %v is not a unix address",
+				path, listeners[i].Addr())
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != cfg.unixSocketPerm {
+			t.Errorf("%s: permissions %v, want %v", path, fi.Mode().Perm(),
+				cfg.unixSocketPerm)
+		}
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		conn.Close()
+	}
+
+	// Sockets accepting connections and other files are never replaced.
+	regularPath := filepath.Join(dir, "regular")
+	if err := ioutil.WriteFile(regularPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{paths[0], regularPath} {
+		if lis, err := makeUnixListeners([]string{path}); err == nil {
+			lis[0].Close()
+			t.Errorf("%s: replaced existing file", path)
+		}
+	}
+	if _, err := os.Stat(regularPath); err != nil {
+		t.Errorf("regular file was removed: %v", err)
+	}
+}
+
+func TestIsUnixAddr(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		unix bool
+	}{
+		{nil, false},
+		{&net.UnixAddr{Name: "/tmp/hcwallet.sock", Net: "unix"}, true},
+		{&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 14009}, false},
+	}
+	for _, test := range tests {
+		if got := isUnixAddr(test.addr); got != test.unix {
+			t.Errorf("isUnixAddr(%v) = %v, want %v", test.addr, got,
+				test.unix)
+		}
+	}
+}
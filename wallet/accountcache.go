@@ -0,0 +1,71 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import "sync"
+
+// accountNameCache memoizes the mapping between account names and numbers so
+// repeated lookups, commonly several per RPC request, do not each open a
+// database transaction.  Only successful lookups are cached, and the cache
+// must be invalidated after any account is created or renamed.
+type accountNameCache struct {
+	mu      sync.RWMutex
+	numbers map[string]uint32
+	names   map[uint32]string
+
+	// generation is incremented by every invalidation.  Lookups record the
+	// generation before reading the database and only cache their result
+	// if no invalidation occurred in the meantime, preventing a lookup
+	// racing with a rename from caching the old name.
+	generation uint64
+}
+
+// gen returns the current generation of the cache.
+func (c *accountNameCache) gen() uint64 {
+	c.mu.RLock()
+	gen := c.generation
+	c.mu.RUnlock()
+	return gen
+}
+
+// number returns the cached account number of an account name.
+func (c *accountNameCache) number(name string) (uint32, bool) {
+	c.mu.RLock()
+	account, ok := c.numbers[name]
+	c.mu.RUnlock()
+	return account, ok
+}
+
+// name returns the cached account name of an account number.
+func (c *accountNameCache) name(account uint32) (string, bool) {
+	c.mu.RLock()
+	name, ok := c.names[account]
+	c.mu.RUnlock()
+	return name, ok
+}
+
+// add caches the name of an account read from the database when the cache
+// generation was gen.  Nothing is cached if the cache was invalidated since.
+func (c *accountNameCache) add(gen uint64, account uint32, name string) {
+	c.mu.Lock()
+	if gen == c.generation {
+		if c.numbers == nil {
+			c.numbers = make(map[string]uint32)
+			c.names = make(map[uint32]string)
+		}
+		c.numbers[name] = account
+		c.names[account] = name
+	}
+	c.mu.Unlock()
+}
+
+// invalidate removes all cached accounts.
+func (c *accountNameCache) invalidate() {
+	c.mu.Lock()
+	c.numbers = nil
+	c.names = nil
+	c.generation++
+	c.mu.Unlock()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+)
+
+func TestAccountFees(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	w.SetRelayFee(1e4)
+	w.SetTicketFeeIncrement(2e4)
+
+	// Accounts use the global settings until they save their own.
+	fee, err := w.AccountRelayFee(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 1e4 {
+		t.Errorf("default account relay fee is %v, want %v", fee, hcutil.Amount(1e4))
+	}
+
+	err = w.SetAccountRelayFee(0, 5e4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee, err = w.AccountRelayFee(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 5e4 {
+		t.Errorf("account relay fee is %v after setting it, want %v", fee,
+			hcutil.Amount(5e4))
+	}
+	if fee := w.RelayFee(); fee != 1e4 {
+		t.Errorf("setting an account relay fee changed the global relay "+
+			"fee to %v", fee)
+	}
+	fee, err = w.AccountTicketFeeIncrement(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 2e4 {
+		t.Errorf("setting the account relay fee changed the account ticket "+
+			"fee to %v", fee)
+	}
+
+	// Fees may not be saved for accounts which do not exist.
+	if err := w.SetAccountRelayFee(99, 5e4); err == nil {
+		t.Error("set the relay fee of a nonexistent account")
+	}
+	if err := w.SetAccountTicketFeeIncrement(99, 5e4); err == nil {
+		t.Error("set the ticket fee of a nonexistent account")
+	}
+}
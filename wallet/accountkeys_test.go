@@ -0,0 +1,100 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestExportImportAccount(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+	if err := w.Unlock(testPrivPass, nil); err != nil {
+		t.Fatal(err)
+	}
+	account, err := w.NextAccount("exported", udb.AcctypeEc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpriv, err := w.ExportAccount(account, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := w.ExportAccount(account, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub0, err := w.ExportAccount(udb.DefaultAccountNum, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpriv0, err := w.ExportAccount(udb.DefaultAccountNum, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Lock()
+	if !w.Locked() {
+		t.Fatal("wallet was not locked")
+	}
+	_, err = w.ExportAccount(account, true)
+	if !apperrors.IsError(err, apperrors.ErrLocked) {
+		t.Errorf("exporting a private key from a locked wallet returned "+
+			"error %v", err)
+	}
+
+	imp, teardown := testWallet(t)
+	defer teardown()
+	if err := imp.Unlock(testPrivPass, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only extended private keys of the next account are imported by
+	// wallets which are not watching-only.
+	if _, err := imp.ImportAccount("restored", xpub); err == nil {
+		t.Error("imported an extended public key")
+	}
+	if _, err := imp.ImportAccount("restored", xpriv0); err == nil {
+		t.Error("imported the key of account 0 as account 1")
+	}
+	if _, err := imp.ImportAccount("restored", "xprv"); err == nil {
+		t.Error("imported an invalid key")
+	}
+
+	imported, err := imp.ImportAccount("restored", xpriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != account {
+		t.Errorf("imported account %d, want %d", imported, account)
+	}
+	got, err := imp.ExportAccount(imported, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != xpub {
+		t.Errorf("imported account has extended public key %s, want %s",
+			got, xpub)
+	}
+	if got == xpub0 {
+		t.Error("imported account has the key of the default account")
+	}
+	addrs, err := imp.AccountBranchAddressRange(imported, udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := w.AccountBranchAddressRange(account, udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addrs[0].EncodeAddress() != want[0].EncodeAddress() {
+		t.Errorf("imported account address %v, want %v", addrs[0], want[0])
+	}
+	if _, err := imp.ImportAccount("restored", xpriv); err == nil {
+		t.Error("imported an account with a duplicate name")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcec/secp256k1"
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AccountProofBranch is the branch of an account extended key which derives
+// the key signing account ownership proofs, at child index 0.  The branch
+// follows the external and internal branches and is below
+// udb.FirstExtraExternalBranch, so it is never used for addresses and the
+// proof key never controls funds.
+const AccountProofBranch uint32 = 2
+
+// accountProofHash returns the hash of msg which is signed to prove ownership
+// of an account.  The prefix differs from signed messages so that account
+// proofs can not be mistaken for message signatures, or the reverse.
+func accountProofHash(msg string) []byte {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, "Hc Account Proof:\n")
+	wire.WriteVarString(&buf, 0, msg)
+	return chainhash.HashB(buf.Bytes())
+}
+
+// accountProofKey derives the account proof key from an account extended key.
+func accountProofKey(acctKey *hdkeychain.ExtendedKey) (*hdkeychain.ExtendedKey, error) {
+	branchKey, err := acctKey.Child(AccountProofBranch)
+	if err != nil {
+		return nil, err
+	}
+	defer branchKey.Zero()
+	return branchKey.Child(0)
+}
+
+// SignMessageWithAccount signs msg with the account proof key of a secp256k1
+// account, proving control of the entire account rather than any single
+// address.  The serialized account extended public key, which verifiers
+// require to check the proof with VerifyAccountProof, is returned with the
+// compact signature.  This method requires the wallet to be unlocked.
+func (w *Wallet) SignMessageWithAccount(msg string, account uint32) (xpub string, sig []byte, err error) {
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to sign account proofs"
+		return "", nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	var proofKey *hdkeychain.ExtendedKey
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		props, err := w.Manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		if props.AccountType != udb.AcctypeEc {
+			const str = "account proofs may only be signed by secp256k1 accounts"
+			return apperrors.E{ErrorCode: apperrors.ErrInvalidAccount, Description: str, Err: nil}
+		}
+
+		acctXpub, err := w.Manager.AccountExtendedPubKey(tx, account)
+		if err != nil {
+			return err
+		}
+		xpub, err = acctXpub.String()
+		if err != nil {
+			return err
+		}
+
+		acctXpriv, err := w.Manager.AccountExtendedPrivKey(tx, account)
+		if err != nil {
+			return err
+		}
+		proofKey, err = accountProofKey(acctXpriv)
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	defer proofKey.Zero()
+
+	privKey, err := proofKey.ECPrivKey()
+	if err != nil {
+		return "", nil, err
+	}
+	pkCast, ok := privKey.(*secp256k1.PrivateKey)
+	if !ok {
+		return "", nil, errors.New("account proof key is not a secp256k1 key")
+	}
+	sig, err = secp256k1.SignCompact(secp256k1.S256(), pkCast,
+		accountProofHash(msg), true)
+	if err != nil {
+		return "", nil, err
+	}
+	return xpub, sig, nil
+}
+
+// VerifyAccountProof verifies that sig is a valid signature of msg created by
+// SignMessageWithAccount using the account of the serialized extended public
+// key xpub.  The extended key must be for the network params.
+func VerifyAccountProof(msg, xpub string, sig []byte, params *chaincfg.Params) (bool, error) {
+	acctKey, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		const str = "invalid extended key"
+		return false, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: err}
+	}
+	if !acctKey.IsForNet(params) {
+		const str = "extended key is for another network"
+		return false, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if acctKey.IsPrivate() {
+		const str = "account proofs must be verified with an extended public key"
+		return false, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if acctKey.GetAlgType() != udb.AcctypeEc {
+		const str = "account proofs are only signed by secp256k1 accounts"
+		return false, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	proofKey, err := accountProofKey(acctKey)
+	if err != nil {
+		return false, err
+	}
+	pubKey, err := proofKey.ECPubKey()
+	if err != nil {
+		return false, err
+	}
+	recovered, _, err := chainec.Secp256k1.RecoverCompact(sig, accountProofHash(msg))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(recovered.SerializeCompressed(), pubKey.SerializeCompressed()), nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestAccountProof(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	w.Lock()
+	if !w.Locked() {
+		t.Fatal("wallet was not locked")
+	}
+	_, _, err := w.SignMessageWithAccount("proof", udb.DefaultAccountNum)
+	if !apperrors.IsError(err, apperrors.ErrLocked) {
+		t.Errorf("signing with a locked wallet returned error %v", err)
+	}
+
+	if err := w.Unlock(testPrivPass, nil); err != nil {
+		t.Fatal(err)
+	}
+	xpub, sig, err := w.SignMessageWithAccount("proof", udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := w.NextAccount("other", udb.AcctypeEc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherXpub, err := w.ExportAccount(other, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		msg  string
+		xpub string
+		ok   bool
+	}{
+		{"signing account", "proof", xpub, true},
+		{"other message", "other proof", xpub, false},
+		{"other account", "proof", otherXpub, false},
+	}
+	for _, test := range tests {
+		ok, err := VerifyAccountProof(test.msg, test.xpub, sig, w.chainParams)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if ok != test.ok {
+			t.Errorf("%s: verified %v, want %v", test.name, ok, test.ok)
+		}
+	}
+
+	// Proofs are only verified with extended public keys of the network.
+	xpriv, err := w.ExportAccount(udb.DefaultAccountNum, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAccountProof("proof", xpriv, sig, w.chainParams); err == nil {
+		t.Error("verified a proof with an extended private key")
+	}
+	if _, err := VerifyAccountProof("proof", xpub, sig, &chaincfg.MainNetParams); err == nil {
+		t.Error("verified a proof with a key for another network")
+	}
+
+	// Truncated signatures do not verify.
+	if ok, _ := VerifyAccountProof("proof", xpub, sig[1:], w.chainParams); ok {
+		t.Error("verified a truncated signature")
+	}
+}
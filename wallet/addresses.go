@@ -33,6 +33,7 @@ const (
 
 type nextAddressCallOptions struct {
 	policy gapPolicy
+	branch uint32
 }
 
 // NextAddressCallOption defines a call option for the NextAddress family of
@@ -76,6 +77,15 @@ func WithGapPolicyWrap() NextAddressCallOption {
 	return withGapPolicy(gapPolicyWrap)
 }
 
+// WithExternalBranch configures NewExternalAddress to return an address of an
+// additional external branch of the account created by NewExternalBranch,
+// rather than the BIP0044 external branch.
+func WithExternalBranch(branch uint32) NextAddressCallOption {
+	return func(o *nextAddressCallOptions) {
+		o.branch = branch
+	}
+}
+
 type addressBuffer struct {
 	branchXpub *hdkeychain.ExtendedKey
 	lastUsed   uint32
@@ -83,11 +93,70 @@ type addressBuffer struct {
 	// warning: this is not decremented after errors, and therefore may refer
 	// to children beyond the last returned child recorded in the database.
 	cursor uint32
+	// watchedAhead is the number of children watched for transactions
+	// beyond lastUsed plus the gap limit.
+	watchedAhead uint32
+}
+
+// watchHorizon returns the child index one past the last child watched for
+// transactions.
+func (alb *addressBuffer) watchHorizon(gapLimit uint32) uint32 {
+	return alb.lastUsed + 1 + gapLimit + alb.watchedAhead
+}
+
+// extendWatched updates the buffer with the last used child recorded in the
+// database and returns the half open range of children which must newly be
+// watched.  At least gapLimit children past the last used child are always
+// watched.  Once fewer than half of the lookahead children remain watched
+// beyond the gap limit, the watched children are extended through the full
+// lookahead, so addresses handed out ahead of the gap limit are found and
+// extensions are batched.
+func (alb *addressBuffer) extendWatched(dbLastUsed, gapLimit, lookahead uint32) (start, end uint32) {
+	horizon := alb.watchHorizon(gapLimit)
+
+	// Last used indexes are ^uint32(0) when no child is used, so they are
+	// compared after adding one.
+	if dbLastUsed+1 > alb.lastUsed+1 {
+		alb.cursor -= minUint32(alb.cursor, dbLastUsed-alb.lastUsed)
+		alb.lastUsed = dbLastUsed
+	}
+
+	need := alb.lastUsed + 1 + gapLimit
+	end = horizon
+	if need+(lookahead+1)/2 > horizon {
+		end = minUint32(need+lookahead, hdkeychain.HardenedKeyStart)
+	}
+	if end > need {
+		alb.watchedAhead = end - need
+	} else {
+		alb.watchedAhead = 0
+	}
+	if end < horizon {
+		end = horizon
+	}
+	return horizon, end
 }
 
 type bip0044AccountData struct {
 	albExternal addressBuffer
 	albInternal addressBuffer
+
+	// albExtra holds the buffers of additional external branches, keyed
+	// by branch number.
+	albExtra map[uint32]*addressBuffer
+}
+
+// branchBuffer returns the address buffer of an account branch, or nil if the
+// account has no such branch.
+func (ad *bip0044AccountData) branchBuffer(branch uint32) *addressBuffer {
+	switch branch {
+	case udb.ExternalBranch:
+		return &ad.albExternal
+	case udb.InternalBranch:
+		return &ad.albInternal
+	default:
+		return ad.albExtra[branch]
+	}
 }
 
 // persistReturnedChildFunc is the function used by nextAddress to update the
@@ -171,14 +240,10 @@ func (w *Wallet) nextAddress(persist persistReturnedChildFunc, accountinfo *udb.
 		return nil, apperrors.E{ErrorCode: apperrors.ErrAccountNotFound, Description: str, Err: nil}
 	}
 
-	var alb *addressBuffer
-	switch branch {
-	case udb.ExternalBranch:
-		alb = &ad.albExternal
-	case udb.InternalBranch:
-		alb = &ad.albInternal
-	default:
-		const str = "branch must be external (0) or internal (1)"
+	alb := ad.branchBuffer(branch)
+	if alb == nil {
+		const str = "branch must be external (0), internal (1), or an " +
+			"external branch of the account"
 		err := apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
 		return nil, err
 	}
@@ -373,15 +438,30 @@ func (w *Wallet) markUsedAddress(dbtx walletdb.ReadWriteTx, addr udb.ManagedAddr
 	if account == udb.ImportedAddrAccount {
 		return nil
 	}
-	props, err := w.Manager.AccountProperties(ns, account)
+	_, branch, _, _, err := w.Manager.AddrBranchChild(ns, addr.Address())
 	if err != nil {
 		return err
 	}
-	lastUsed := props.LastUsedExternalIndex
-	branch := udb.ExternalBranch
-	if addr.Internal() {
-		lastUsed = props.LastUsedInternalIndex
-		branch = udb.InternalBranch
+	var lastUsed uint32
+	switch branch {
+	case udb.ExternalBranch, udb.InternalBranch:
+		props, err := w.Manager.AccountProperties(ns, account)
+		if err != nil {
+			return err
+		}
+		lastUsed = props.LastUsedExternalIndex
+		if branch == udb.InternalBranch {
+			lastUsed = props.LastUsedInternalIndex
+		}
+	case udb.InvoiceBranch:
+		// Invoice addresses are not subject to the gap limit.
+		return nil
+	default:
+		props, err := w.Manager.ExternalBranchProperties(ns, account, branch)
+		if err != nil {
+			return err
+		}
+		lastUsed = props.LastUsedIndex
 	}
 	return w.Manager.SyncAccountToAddrIndex(ns, account,
 		minUint32(hdkeychain.HardenedKeyStart-1, lastUsed+uint32(w.gapLimit)),
@@ -404,6 +484,7 @@ func (w *Wallet) watchFutureAddresses(dbtx walletdb.ReadTx) error {
 	type children struct {
 		external uint32
 		internal uint32
+		extra    map[uint32]uint32
 	}
 	ns := dbtx.ReadBucket(waddrmgrNamespaceKey)
 	lastAccount, err := w.Manager.LastAccount(ns)
@@ -425,7 +506,15 @@ func (w *Wallet) watchFutureAddresses(dbtx walletdb.ReadTx) error {
 				lastUsedInt = props.LastUsedInternalIndex
 			}
 		}
-		dbLastUsedChildren[account] = children{lastUsedExt, lastUsedInt}
+		extra := make(map[uint32]uint32)
+		err := w.Manager.ForEachExternalBranch(ns, account, func(props *udb.BranchProperties) error {
+			extra[props.Branch] = props.LastUsedIndex
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		dbLastUsedChildren[account] = children{lastUsedExt, lastUsedInt, extra}
 	}
 
 	// Update the buffer's last used child if it was updated, and then update
@@ -435,29 +524,36 @@ func (w *Wallet) watchFutureAddresses(dbtx walletdb.ReadTx) error {
 	errs := make(chan error, lastAccount+1)
 	defer w.addressBuffersMu.Unlock()
 	w.addressBuffersMu.Lock()
+	lookahead := w.gapLimitLookahead
 	for account, a := range w.addressBuffers {
-		// startExt/Int are the indexes of the next child after the last
-		// currently watched address.
-		startExt := a.albExternal.lastUsed + 1 + gapLimit
-		startInt := a.albInternal.lastUsed + 1 + gapLimit
-
-		// endExt/Int are the end indexes for newly watched addresses.  Because
-		// addresses ranges are described using a half open range, these indexes
-		// are one beyond the last address that will be watched.
+		// startExt/Int and endExt/Int describe the half open ranges of
+		// children which must newly be watched.
 		dbLastUsed := dbLastUsedChildren[account]
-		endExt := dbLastUsed.external + 1 + gapLimit
-		endInt := dbLastUsed.internal + 1 + gapLimit
+		startExt, endExt := a.albExternal.extendWatched(dbLastUsed.external,
+			gapLimit, lookahead)
+		startInt, endInt := a.albInternal.extendWatched(dbLastUsed.internal,
+			gapLimit, lookahead)
 
 		xpubBranchExt := a.albExternal.branchXpub
 		xpubBranchInt := a.albInternal.branchXpub
 
-		// Create a slice of all new addresses that must be watched.
-		var totalAddrs uint32
-		if endExt > startExt {
-			totalAddrs += endExt - startExt
+		// Additional external branches are only created for ECDSA
+		// accounts.
+		type extraRange struct {
+			alb        *addressBuffer
+			start, end uint32
 		}
-		if endInt > startInt {
-			totalAddrs += endInt - startInt
+		extraRanges := make([]extraRange, 0, len(a.albExtra))
+		for branch, alb := range a.albExtra {
+			start, end := alb.extendWatched(dbLastUsed.extra[branch],
+				gapLimit, lookahead)
+			extraRanges = append(extraRanges, extraRange{alb, start, end})
+		}
+
+		// Create a slice of all new addresses that must be watched.
+		totalAddrs := (endExt - startExt) + (endInt - startInt)
+		for _, r := range extraRanges {
+			totalAddrs += r.end - r.start
 		}
 		if totalAddrs == 0 {
 			errs <- nil
@@ -475,27 +571,30 @@ func (w *Wallet) watchFutureAddresses(dbtx walletdb.ReadTx) error {
 			if err != nil {
 				return err
 			}
-		} else if xpubBranchExt.GetAlgType() == udb.AcctypeBliss {
-			addrsEx, err := w.Manager.LoadBlissAddrs(ns, account, udb.ExternalBranch, startExt, endExt-startExt)
-			if err != nil {
-				return err
+			for _, r := range extraRanges {
+				err = appendChildAddrsRange(&addrs, r.alb.branchXpub,
+					r.start, r.end, w.chainParams)
+				if err != nil {
+					return err
+				}
 			}
-			addrsIn, err := w.Manager.LoadBlissAddrs(ns, account, udb.InternalBranch, startInt, endInt-startInt)
-			if err != nil {
-				return err
+		} else if xpubBranchExt.GetAlgType() == udb.AcctypeBliss {
+			for _, r := range []struct {
+				branch, start, end uint32
+			}{
+				{udb.ExternalBranch, startExt, endExt},
+				{udb.InternalBranch, startInt, endInt},
+			} {
+				if r.end <= r.start {
+					continue
+				}
+				branchAddrs, err := w.Manager.LoadBlissAddrs(ns, account,
+					r.branch, r.start, r.end-r.start)
+				if err != nil {
+					return err
+				}
+				addrs = append(addrs, branchAddrs...)
 			}
-			addrs = append(addrsEx, addrsIn[:]...)
-		}
-
-		// Update the in-memory address buffers with the latest last used
-		// indexes retreived from the db.
-		if endExt > startExt {
-			a.albExternal.lastUsed = dbLastUsed.external
-			a.albExternal.cursor -= minUint32(a.albExternal.cursor, endExt-startExt)
-		}
-		if endInt > startInt {
-			a.albInternal.lastUsed = dbLastUsed.internal
-			a.albInternal.cursor -= minUint32(a.albInternal.cursor, endInt-startInt)
 		}
 
 		go func() {
@@ -512,8 +611,19 @@ func (w *Wallet) watchFutureAddresses(dbtx walletdb.ReadTx) error {
 	return nil
 }
 
-// NewExternalAddress returns an external address.
+// NewExternalAddress returns an external address.  The address is of the
+// BIP0044 external branch unless another external branch of the account is
+// selected using WithExternalBranch.
 func (w *Wallet) NewExternalAddress(account uint32, callOpts ...NextAddressCallOption) (hcutil.Address, error) {
+	var opts nextAddressCallOptions
+	for _, c := range callOpts {
+		c(&opts)
+	}
+	if opts.branch == udb.InternalBranch {
+		const str = "the internal branch is not an external branch"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
+	}
+
 	var accountinfo *udb.AccountProperties
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		var err error
@@ -530,7 +640,7 @@ func (w *Wallet) NewExternalAddress(account uint32, callOpts ...NextAddressCallO
 	if w.Manager.IsLocked() && accountinfo.AccountType == udb.AcctypeBliss {
 		return nil, fmt.Errorf("wallet is locked")
 	}
-	return w.nextAddress(w.persistReturnedChild(nil), accountinfo, udb.ExternalBranch, nil, callOpts...)
+	return w.nextAddress(w.persistReturnedChild(nil), accountinfo, opts.branch, nil, callOpts...)
 }
 
 // NewInternalAddress returns an internal address.
@@ -701,13 +811,8 @@ func (w *Wallet) AccountBranchAddressRange(account, branch, start, end uint32) (
 		return nil, apperrors.E{ErrorCode: apperrors.ErrAccountNotFound, Description: str, Err: nil}
 	}
 
-	var buf *addressBuffer
-	switch branch {
-	case udb.ExternalBranch:
-		buf = &acctBufs.albExternal
-	case udb.InternalBranch:
-		buf = &acctBufs.albInternal
-	default:
+	buf := acctBufs.branchBuffer(branch)
+	if buf == nil {
 		const str = "unknown branch"
 		return nil, apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
 	}
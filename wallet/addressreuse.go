@@ -0,0 +1,397 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AddressUse describes the transactions paying to an external address of a
+// BIP0044 account.
+type AddressUse struct {
+	Address      hcutil.Address
+	Account      uint32
+	Index        uint32
+	Transactions []chainhash.Hash
+}
+
+// AddressReuseReport describes the external addresses which were paid by more
+// than one transaction, and the external addresses which were paid before the
+// wallet returned them.  Use of addresses which were never handed out by the
+// wallet suggests that the account extended public key has leaked or that the
+// wallet has been cloned.
+type AddressReuseReport struct {
+	Reused             []AddressUse
+	UsedBeforeReturned []AddressUse
+}
+
+// addressUses collects the transactions paying to external addresses.
+type addressUses map[string]*AddressUse
+
+func (u addressUses) add(addr hcutil.Address, account, child uint32, txHash *chainhash.Hash) {
+	key := addr.EncodeAddress()
+	use, ok := u[key]
+	if !ok {
+		use = &AddressUse{Address: addr, Account: account, Index: child}
+		u[key] = use
+	}
+	for i := range use.Transactions {
+		if use.Transactions[i] == *txHash {
+			return
+		}
+	}
+	use.Transactions = append(use.Transactions, *txHash)
+}
+
+// sorted returns the uses ordered by account and child index.
+func (u addressUses) sorted() []*AddressUse {
+	uses := make([]*AddressUse, 0, len(u))
+	for _, use := range u {
+		uses = append(uses, use)
+	}
+	sort.Slice(uses, func(i, j int) bool {
+		if uses[i].Account != uses[j].Account {
+			return uses[i].Account < uses[j].Account
+		}
+		return uses[i].Index < uses[j].Index
+	})
+	return uses
+}
+
+// AddressReuse reports the reuse of external addresses by the transactions
+// recorded by the wallet, which are the transactions matched by the wallet's
+// transaction filter during syncs and rescans.
+//
+// When chainClient is non-nil and lookahead is positive, the main chain is
+// additionally scanned from scanFrom for transactions paying to the next
+// lookahead external addresses of each account which have not yet been
+// returned by the wallet.  These addresses are added to the transaction filter
+// of the chain client, so later transactions paying to them are recorded by
+// the wallet as well.
+func (w *Wallet) AddressReuse(chainClient ChainClient, scanFrom int32, lookahead uint32) (*AddressReuseReport, error) {
+	uses := make(addressUses)
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+		rangeFn := func(details []udb.TxDetails) (bool, error) {
+			for i := range details {
+				d := &details[i]
+				for _, c := range d.Credits {
+					if c.Change {
+						continue
+					}
+					out := d.MsgTx.TxOut[c.Index]
+					_, addrs, _, err := txscript.ExtractPkScriptAddrs(out.Version,
+						out.PkScript, w.chainParams)
+					if err != nil {
+						continue
+					}
+					for _, addr := range addrs {
+						account, branch, child, ok, err := w.Manager.AddrBranchChild(addrmgrNs, addr)
+						if err != nil {
+							// Missing addresses are skipped.
+							// Other errors should be propagated.
+							if !apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+								return false, err
+							}
+							continue
+						}
+						if ok && branch == udb.ExternalBranch {
+							uses.add(addr, account, child, &d.Hash)
+						}
+					}
+				}
+			}
+			return false, nil
+		}
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1, rangeFn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unreturned map[string]struct{}
+	if chainClient != nil && lookahead > 0 {
+		unreturned, err = w.scanUnreturnedAddresses(chainClient, scanFrom,
+			lookahead, uses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := new(AddressReuseReport)
+	for _, use := range uses.sorted() {
+		if len(use.Transactions) > 1 {
+			report.Reused = append(report.Reused, *use)
+		}
+		if _, ok := unreturned[use.Address.EncodeAddress()]; ok {
+			report.UsedBeforeReturned = append(report.UsedBeforeReturned, *use)
+		}
+	}
+	return report, nil
+}
+
+// scanUnreturnedAddresses rescans the main chain from scanFrom for
+// transactions paying to the next lookahead external addresses of each account
+// after the last returned address, adding their uses to uses.  The set of
+// encoded addresses which were found to be used is returned.
+func (w *Wallet) scanUnreturnedAddresses(chainClient ChainClient, scanFrom int32,
+	lookahead uint32, uses addressUses) (map[string]struct{}, error) {
+
+	type derivation struct {
+		account, child uint32
+	}
+
+	var accounts []uint32
+	var nextChildren []uint32
+	var startHash chainhash.Hash
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		err := w.Manager.ForEachAccount(addrmgrNs, func(account uint32) error {
+			if account == udb.ImportedAddrAccount {
+				return nil
+			}
+			props, err := w.Manager.AccountProperties(addrmgrNs, account)
+			if err != nil {
+				return err
+			}
+			// The addition works correctly even when no address has
+			// been returned and the last returned index is ^uint32(0).
+			accounts = append(accounts, account)
+			nextChildren = append(nextChildren, props.LastReturnedExternalIndex+1)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		startHash, err = w.TxStore.GetMainChainBlockHashForHeight(txmgrNs, scanFrom)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	derived := make(map[string]derivation)
+	var watch []hcutil.Address
+	for i, account := range accounts {
+		start := nextChildren[i]
+		addrs, err := w.AccountBranchAddressRange(account, udb.ExternalBranch,
+			start, start+lookahead)
+		if err != nil {
+			return nil, err
+		}
+		for j, addr := range addrs {
+			derived[addr.EncodeAddress()] = derivation{account, start + uint32(j)}
+		}
+		watch = append(watch, addrs...)
+	}
+	if len(watch) == 0 {
+		return nil, nil
+	}
+	err = chainClient.LoadTxFilter(false, watch, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]struct{})
+	blockHashStorage := make([]chainhash.Hash, maxBlocksPerRescan)
+	scanFromHash := startHash
+	inclusive := true
+	for {
+		var blocks []chainhash.Hash
+		err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+			var err error
+			blocks, err = w.TxStore.GetMainChainBlockHashes(txmgrNs,
+				&scanFromHash, inclusive, blockHashStorage)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			return used, nil
+		}
+
+		log.Infof("Scanning %d blocks for unreturned address use", len(blocks))
+		rescanResults, err := chainClient.Rescan(blocks)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rescanResults.DiscoveredData {
+			for _, hexTx := range r.Transactions {
+				serTx, err := hex.DecodeString(hexTx)
+				if err != nil {
+					return nil, err
+				}
+				var tx wire.MsgTx
+				err = tx.Deserialize(bytes.NewReader(serTx))
+				if err != nil {
+					return nil, err
+				}
+				txHash := tx.TxHash()
+				for _, out := range tx.TxOut {
+					_, addrs, _, err := txscript.ExtractPkScriptAddrs(out.Version,
+						out.PkScript, w.chainParams)
+					if err != nil {
+						continue
+					}
+					for _, addr := range addrs {
+						key := addr.EncodeAddress()
+						d, ok := derived[key]
+						if !ok {
+							continue
+						}
+						uses.add(addr, d.account, d.child, &txHash)
+						used[key] = struct{}{}
+					}
+				}
+			}
+		}
+
+		scanFromHash = blocks[len(blocks)-1]
+		inclusive = false
+	}
+}
+
+// AddressReusePolicy defines how the wallet handles external addresses which
+// are paid by more than one transaction.  Each policy includes the behavior of
+// the previous ones.
+type AddressReusePolicy string
+
+const (
+	// AddressReuseIgnore does not report address reuse.
+	AddressReuseIgnore AddressReusePolicy = "ignore"
+
+	// AddressReuseWarn logs a warning when a transaction pays to an
+	// external address already paid by another transaction.
+	AddressReuseWarn AddressReusePolicy = "warn"
+
+	// AddressReuseFlag flags reused addresses in listings of unspent
+	// outputs and received amounts.
+	AddressReuseFlag AddressReusePolicy = "flag"
+
+	// AddressReuseRefuse never returns an external address which has
+	// already been paid as the current address of an account.
+	AddressReuseRefuse AddressReusePolicy = "refuse"
+)
+
+// SetAddressReusePolicy sets the policy of handling reused external addresses.
+func (w *Wallet) SetAddressReusePolicy(policy AddressReusePolicy) {
+	w.addressReusePolicyMu.Lock()
+	w.addressReusePolicy = policy
+	w.addressReusePolicyMu.Unlock()
+}
+
+// AddressReusePolicy returns the policy of handling reused external addresses.
+func (w *Wallet) AddressReusePolicy() AddressReusePolicy {
+	w.addressReusePolicyMu.Lock()
+	policy := w.addressReusePolicy
+	w.addressReusePolicyMu.Unlock()
+	return policy
+}
+
+// FlagReusedAddresses returns whether reused addresses are flagged in listings
+// of unspent outputs and received amounts.
+func (w *Wallet) FlagReusedAddresses() bool {
+	switch w.AddressReusePolicy() {
+	case AddressReuseFlag, AddressReuseRefuse:
+		return true
+	default:
+		return false
+	}
+}
+
+// isExternalAddress returns whether an address is derived from a non-internal
+// branch of a BIP0044 account.
+func (w *Wallet) isExternalAddress(addrmgrNs walletdb.ReadBucket, addr hcutil.Address) (bool, error) {
+	_, branch, _, ok, err := w.Manager.AddrBranchChild(addrmgrNs, addr)
+	if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return ok && branch != udb.InternalBranch, nil
+}
+
+// warnAddressReuse logs a warning when a transaction being recorded pays to an
+// external address already paid by other transactions.  It must be called
+// before the credit of the transaction is added to the transaction store.
+func (w *Wallet) warnAddressReuse(dbtx walletdb.ReadTx, addr hcutil.Address, txHash *chainhash.Hash) error {
+	if w.AddressReusePolicy() == AddressReuseIgnore {
+		return nil
+	}
+	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+	external, err := w.isExternalAddress(addrmgrNs, addr)
+	if err != nil || !external {
+		return err
+	}
+	hashes := w.TxStore.AddressCreditTxHashes(txmgrNs, addr)
+	if len(hashes) == 0 {
+		return nil
+	}
+	for i := range hashes {
+		if hashes[i] == *txHash {
+			return nil
+		}
+	}
+	log.Warnf("Address %v is reused by transaction %v (previously paid by "+
+		"%d transactions)", addr, txHash, len(hashes))
+	return nil
+}
+
+// ReusedAddresses returns the set of encoded external addresses, out of the
+// passed encoded addresses, which were paid by more than one transaction.
+// Addresses which can not be decoded or are not wallet addresses are ignored.
+func (w *Wallet) ReusedAddresses(addrs []string) (map[string]struct{}, error) {
+	reused := make(map[string]struct{})
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		for _, s := range addrs {
+			if _, ok := reused[s]; ok {
+				continue
+			}
+			addr, err := hcutil.DecodeAddress(s)
+			if err != nil {
+				continue
+			}
+			external, err := w.isExternalAddress(addrmgrNs, addr)
+			if err != nil {
+				return err
+			}
+			if external && len(w.TxStore.AddressCreditTxHashes(txmgrNs, addr)) > 1 {
+				reused[s] = struct{}{}
+			}
+		}
+		return nil
+	})
+	return reused, err
+}
+
+// addressPaid returns whether any recorded transaction pays to an address.
+func (w *Wallet) addressPaid(addr hcutil.Address) (bool, error) {
+	var paid bool
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		paid = len(w.TxStore.AddressCreditTxHashes(txmgrNs, addr)) != 0
+		return nil
+	})
+	return paid, err
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestAddressReuse(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	// Both the funding transaction and a second transaction pay external
+	// address 0, while the spend pays change to an internal address.
+	spend := addSpendWithChange(t, w)
+	reuse := wire.NewMsgTx()
+	reuse.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{2}}, foreignSigScript))
+	reuse.AddTxOut(wire.NewTxOut(1e8, walletPkScript(t, w, udb.ExternalBranch, 0)))
+	addUnminedTx(t, w, reuse)
+
+	report, err := w.AddressReuse(nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Reused) != 1 {
+		t.Fatalf("reported %d reused addresses, want 1", len(report.Reused))
+	}
+	use := report.Reused[0]
+	if use.Account != udb.DefaultAccountNum || use.Index != 0 ||
+		len(use.Transactions) != 2 {
+		t.Errorf("reported use %+v, want 2 transactions paying external "+
+			"address 0", use)
+	}
+	if len(report.UsedBeforeReturned) != 0 {
+		t.Errorf("reported %d addresses used before they were returned "+
+			"without scanning", len(report.UsedBeforeReturned))
+	}
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(spend.TxOut[1].Version,
+		spend.TxOut[1].PkScript, w.chainParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change := addrs[0].EncodeAddress()
+	reused, err := w.ReusedAddresses([]string{use.Address.EncodeAddress(),
+		change, "invalid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reused[use.Address.EncodeAddress()]; !ok || len(reused) != 1 {
+		t.Errorf("reused addresses %v, want only %v", reused, use.Address)
+	}
+
+	policies := map[AddressReusePolicy]bool{
+		AddressReuseIgnore: false,
+		AddressReuseWarn:   false,
+		AddressReuseFlag:   true,
+		AddressReuseRefuse: true,
+	}
+	for policy, flag := range policies {
+		w.SetAddressReusePolicy(policy)
+		if w.FlagReusedAddresses() != flag {
+			t.Errorf("policy %s flags reused addresses: %v, want %v",
+				policy, !flag, flag)
+		}
+	}
+}
+
+func TestCurrentAddressRefusesPaid(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	// Record the first external addresses so transactions paying them are
+	// relevant to the wallet.
+	walletPkScript(t, w, udb.ExternalBranch, 1)
+	addr, err := w.CurrentAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, foreignSigScript))
+	tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	addUnminedTx(t, w, tx)
+
+	w.SetAddressReusePolicy(AddressReuseWarn)
+	current, err := w.CurrentAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.EncodeAddress() != addr.EncodeAddress() {
+		t.Errorf("current address changed from %v to %v without refusing "+
+			"reuse", addr, current)
+	}
+
+	w.SetAddressReusePolicy(AddressReuseRefuse)
+	current, err = w.CurrentAddress(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.EncodeAddress() == addr.EncodeAddress() {
+		t.Errorf("paid address %v returned as the current address", addr)
+	}
+}
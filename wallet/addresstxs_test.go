@@ -0,0 +1,71 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestAddressTransactionsIndexed(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	fundingHash := spend.TxIn[0].PreviousOutPoint.Hash
+	spendHash := spend.TxHash()
+
+	ext, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err := w.ListAddressTransactions(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) == 0 {
+		t.Fatal("no transactions listed for the funded address")
+	}
+	for _, tx := range txs {
+		if tx.TxID != fundingHash.String() {
+			t.Errorf("listed transaction %v does not pay the address", tx.TxID)
+		}
+	}
+
+	received, err := w.ReceivedByAddresses(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := received[ext[0].EncodeAddress()]
+	if r == nil || r.Amount != 10e8 || len(r.TxHashes) != 1 ||
+		r.TxHashes[0] != fundingHash {
+		t.Errorf("external address received %+v, want 10 coins in %v", r,
+			fundingHash)
+	}
+	internal, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.InternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = received[internal[0].EncodeAddress()]
+	if r == nil || r.Amount != 699e6 || len(r.TxHashes) != 1 ||
+		r.TxHashes[0] != spendHash {
+		t.Errorf("change address received %+v, want 6.99 coins in %v", r,
+			spendHash)
+	}
+
+	// Unmined credits do not meet a single confirmation, but active
+	// addresses are still reported.
+	received, err = w.ReceivedByAddresses(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = received[ext[0].EncodeAddress()]
+	if r == nil || r.Amount != 0 || len(r.TxHashes) != 0 {
+		t.Errorf("external address received %+v with one confirmation", r)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SetGapLimitLookahead sets the number of addresses of each account branch
+// watched for transactions beyond the gap limit.  Watching addresses ahead of
+// the gap limit finds funds sent to addresses which third parties derived
+// far ahead from an account extended public key.
+func (w *Wallet) SetGapLimitLookahead(n uint32) {
+	w.addressBuffersMu.Lock()
+	w.gapLimitLookahead = n
+	w.addressBuffersMu.Unlock()
+}
+
+// AddressBranchUsage describes the child indexes of an account branch which
+// are used, returned, and watched for transactions.  Indexes are -1 when no
+// child is used, returned, or watched.
+type AddressBranchUsage struct {
+	LastUsed     int64
+	LastReturned int64
+	LastWatched  int64
+}
+
+// AccountAddressUsage describes the address usage of both branches of a
+// BIP0044 account.
+type AccountAddressUsage struct {
+	Account     uint32
+	AccountName string
+	External    AddressBranchUsage
+	Internal    AddressBranchUsage
+}
+
+func childIndex(child uint32) int64 {
+	if child == ^uint32(0) {
+		return -1
+	}
+	return int64(child)
+}
+
+// SyncAccountAddresses extends the addresses watched for transactions to the
+// gap limit and lookahead past the last used address of each account branch,
+// and returns the used, returned and watched child indexes of each account.
+// This requires the wallet to be connected to a consensus server.
+func (w *Wallet) SyncAccountAddresses() ([]AccountAddressUsage, error) {
+	var usage []AccountAddressUsage
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		err := w.watchFutureAddresses(dbtx)
+		if err != nil {
+			return err
+		}
+
+		ns := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		gapLimit := uint32(w.gapLimit)
+		defer w.addressBuffersMu.Unlock()
+		w.addressBuffersMu.Lock()
+		lastAccount, err := w.Manager.LastAccount(ns)
+		if err != nil {
+			return err
+		}
+		for account := uint32(0); account <= lastAccount; account++ {
+			a, ok := w.addressBuffers[account]
+			if !ok {
+				continue
+			}
+			props, err := w.Manager.AccountProperties(ns, account)
+			if err != nil {
+				return err
+			}
+			usage = append(usage, AccountAddressUsage{
+				Account:     account,
+				AccountName: props.AccountName,
+				External: AddressBranchUsage{
+					LastUsed:     childIndex(props.LastUsedExternalIndex),
+					LastReturned: childIndex(props.LastReturnedExternalIndex),
+					LastWatched:  childIndex(a.albExternal.watchHorizon(gapLimit) - 1),
+				},
+				Internal: AddressBranchUsage{
+					LastUsed:     childIndex(props.LastUsedInternalIndex),
+					LastReturned: childIndex(props.LastReturnedInternalIndex),
+					LastWatched:  childIndex(a.albInternal.watchHorizon(gapLimit) - 1),
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
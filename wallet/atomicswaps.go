@@ -0,0 +1,562 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// AtomicSwapSecretSize is the size of secrets generated when initiating
+	// atomic swaps.
+	AtomicSwapSecretSize = 32
+
+	// DefaultInitiatorLockTime and DefaultParticipantLockTime are the
+	// default durations before the refund paths of swap contracts may be
+	// used.  The participant's contract must be refundable first, so the
+	// initiator can not wait for the participant's refund before revealing
+	// the secret.
+	DefaultInitiatorLockTime   = 48 * time.Hour
+	DefaultParticipantLockTime = 24 * time.Hour
+)
+
+// AtomicSwapContract describes a published transaction funding an atomic swap
+// contract.  The counterparty must be given the contract and the contract
+// transaction to audit the swap.
+type AtomicSwapContract struct {
+	Contract    []byte
+	Address     *hcutil.AddressScriptHash
+	ContractTx  *wire.MsgTx
+	ContractOut uint32
+	SecretHash  []byte
+	Secret      []byte // nil for participants
+	LockTime    int64
+}
+
+// AtomicSwap describes an atomic swap contract funded by the wallet.
+type AtomicSwap struct {
+	Contract    []byte
+	Address     *hcutil.AddressScriptHash
+	ContractTx  chainhash.Hash
+	ContractOut uint32
+	Initiator   bool
+	SecretHash  []byte
+	Secret      []byte // nil if not yet known, or initiated while locked
+	LockTime    int64
+	Created     time.Time
+}
+
+func atomicSwapSecretHash(secret []byte) []byte {
+	h := ripemd160.New()
+	h.Write(secret)
+	return h.Sum(nil)
+}
+
+// atomicSwapContract returns the atomic swap contract paying the recipient
+// with the preimage of the secret hash, or paying the refund address after
+// the lock time.  This is the contract recognized by
+// txscript.ExtractAtomicSwapDataPushes.
+func atomicSwapContract(recipient, refund *hcutil.AddressPubKeyHash,
+	secretHash []byte, lockTime int64) ([]byte, error) {
+
+	b := txscript.NewScriptBuilder()
+	b.AddOp(txscript.OP_IF)
+	{
+		b.AddOp(txscript.OP_RIPEMD160)
+		b.AddData(secretHash)
+		b.AddOp(txscript.OP_EQUALVERIFY)
+		b.AddOp(txscript.OP_DUP)
+		b.AddOp(txscript.OP_HASH160)
+		b.AddData(recipient.Hash160()[:])
+	}
+	b.AddOp(txscript.OP_ELSE)
+	{
+		b.AddInt64(lockTime)
+		b.AddOp(txscript.OP_CHECKLOCKTIMEVERIFY)
+		b.AddOp(txscript.OP_DROP)
+		b.AddOp(txscript.OP_DUP)
+		b.AddOp(txscript.OP_HASH160)
+		b.AddData(refund.Hash160()[:])
+	}
+	b.AddOp(txscript.OP_ENDIF)
+	b.AddOp(txscript.OP_EQUALVERIFY)
+	b.AddOp(txscript.OP_CHECKSIG)
+	return b.Script()
+}
+
+// InitiateSwap generates a secret and publishes a transaction from an account
+// funding an atomic swap contract which pays the participant when redeemed
+// with the secret, or may be refunded to the wallet after the lock time.
+func (w *Wallet) InitiateSwap(account uint32, participant hcutil.Address,
+	amount hcutil.Amount, lockTime int64) (*AtomicSwapContract, error) {
+
+	secret := make([]byte, AtomicSwapSecretSize)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+	return w.fundSwap(account, participant, amount, atomicSwapSecretHash(secret),
+		secret, lockTime)
+}
+
+// ParticipateSwap publishes a transaction from an account funding an atomic
+// swap contract which pays the initiator when redeemed with the preimage of
+// the initiator's secret hash, or may be refunded to the wallet after the
+// lock time.
+func (w *Wallet) ParticipateSwap(account uint32, initiator hcutil.Address,
+	amount hcutil.Amount, secretHash []byte, lockTime int64) (*AtomicSwapContract, error) {
+
+	if len(secretHash) != ripemd160.Size {
+		const str = "secret hash must be 20 bytes"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	return w.fundSwap(account, initiator, amount, secretHash, nil, lockTime)
+}
+
+func (w *Wallet) fundSwap(account uint32, counterparty hcutil.Address,
+	amount hcutil.Amount, secretHash, secret []byte, lockTime int64) (*AtomicSwapContract, error) {
+
+	recipient, ok := counterparty.(*hcutil.AddressPubKeyHash)
+	if !ok || recipient.DSA(w.chainParams) != chainec.ECTypeSecp256k1 {
+		const str = "counterparty address must be a secp256k1 pubkey hash address"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if lockTime <= 0 || lockTime > 0xffffffff {
+		const str = "lock time is out of range"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to fund atomic swaps"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	refundAddr, err := w.NewInternalAddress(account, WithGapPolicyWrap())
+	if err != nil {
+		return nil, err
+	}
+	refund, ok := refundAddr.(*hcutil.AddressPubKeyHash)
+	if !ok {
+		const str = "account does not derive pubkey hash addresses"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	contract, err := atomicSwapContract(recipient, refund, secretHash, lockTime)
+	if err != nil {
+		return nil, err
+	}
+	p2shAddr, err := hcutil.NewAddressScriptHash(contract, w.chainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	pkScript, err := txscript.PayToAddrScript(p2shAddr)
+	if err != nil {
+		return nil, err
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(int64(amount), pkScript)}
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, err
+	}
+	err = txrules.CheckOutput(outputs[0], relayFee)
+	if err != nil {
+		return nil, err
+	}
+
+	// The secret of an initiated swap is not public until the contract is
+	// redeemed, and is only recorded encrypted.
+	var encryptedSecret []byte
+	if secret != nil {
+		encryptedSecret, err = w.Manager.Encrypt(udb.CKTPrivate, secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The contract is imported and the swap, with its secret, is recorded
+	// before the contract is funded, so the secret is never lost for a
+	// published contract.  Importing the contract records the contract
+	// output as a credit, refundable with refundscript and autorefund, and
+	// observes its redemption to record the secret.
+	swap := &udb.AtomicSwap{
+		Contract:        contract,
+		Initiator:       secret != nil,
+		Secret:          encryptedSecret,
+		SecretEncrypted: secret != nil,
+		Created:         time.Now(),
+	}
+	swapKey := p2shAddr.Hash160()[:]
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		err := w.insertTxScript(txmgrNs, contract)
+		if err != nil {
+			return err
+		}
+		_, err = w.Manager.ImportScript(addrmgrNs, contract)
+		if err != nil && !apperrors.IsError(err, apperrors.ErrDuplicateAddress) {
+			return err
+		}
+		return w.Manager.PutAtomicSwap(addrmgrNs, swapKey, swap)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if client := w.ChainClient(); client != nil {
+		err := client.LoadTxFilter(false, []hcutil.Address{p2shAddr}, nil)
+		if err != nil {
+			derr := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+				addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+				return w.Manager.DeleteAtomicSwap(addrmgrNs, swapKey)
+			})
+			if derr != nil {
+				log.Errorf("Failed to remove unfunded atomic swap "+
+					"contract %v: %v", p2shAddr, derr)
+			}
+			return nil, err
+		}
+	}
+
+	// The swap remains recorded if funding fails, since the contract
+	// transaction may have been published even when an error is returned.
+	atx, err := w.CreateSimpleTx(account, outputs, 1, "", "")
+	if err != nil {
+		return nil, err
+	}
+	c := &AtomicSwapContract{
+		Contract:   contract,
+		Address:    p2shAddr,
+		ContractTx: atx.Tx,
+		SecretHash: secretHash,
+		Secret:     secret,
+		LockTime:   lockTime,
+	}
+	for i, out := range atx.Tx.TxOut {
+		if bytes.Equal(out.PkScript, pkScript) {
+			c.ContractOut = uint32(i)
+			break
+		}
+	}
+
+	contractHash := atx.Tx.TxHash()
+	swap.ContractTx = contractHash
+	swap.ContractOut = c.ContractOut
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.PutAtomicSwap(addrmgrNs, swapKey, swap)
+	})
+	if err != nil {
+		// The contract was already published, and the secret is
+		// recorded and the contract remains refundable, so only the
+		// contract transaction is missing from the recorded swap.
+		log.Errorf("Failed to record the contract transaction %v of atomic "+
+			"swap contract %v: %v", &contractHash, p2shAddr, err)
+	}
+	log.Infof("Funded atomic swap contract %v in transaction %v", p2shAddr,
+		&contractHash)
+	return c, nil
+}
+
+// contractOutputs returns the outputs of a transaction paying a P2SH script.
+func contractOutputs(tx *wire.MsgTx, p2shAddr *hcutil.AddressScriptHash) []RefundableOutput {
+	pkScript, err := txscript.PayToAddrScript(p2shAddr)
+	if err != nil {
+		return nil
+	}
+	txHash := tx.TxHash()
+	var outputs []RefundableOutput
+	for i, out := range tx.TxOut {
+		if !bytes.Equal(out.PkScript, pkScript) {
+			continue
+		}
+		outputs = append(outputs, RefundableOutput{
+			OutPoint:    *wire.NewOutPoint(&txHash, uint32(i), wire.TxTreeRegular),
+			Amount:      hcutil.Amount(out.Value),
+			BlockHeight: -1,
+		})
+	}
+	return outputs
+}
+
+// RedeemSwap publishes a transaction redeeming the outputs of a contract
+// transaction paying an atomic swap contract to a wallet key with the secret.
+// If to is nil, the outputs are paid to a new internal address of the
+// account of the recipient key, or the default account for imported keys.
+func (w *Wallet) RedeemSwap(contract []byte, contractTx *wire.MsgTx, secret []byte,
+	to hcutil.Address) (*chainhash.Hash, error) {
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	pushes, err := txscript.ExtractAtomicSwapDataPushes(txscript.DefaultScriptVersion, contract)
+	if err != nil || pushes == nil {
+		const str = "script is not an atomic swap contract"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if !bytes.Equal(atomicSwapSecretHash(secret), pushes.SecretHash[:]) {
+		const str = "secret does not match the secret hash of the contract"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	recipient, err := hcutil.NewAddressPubKeyHash(pushes.RecipientHash160[:],
+		w.chainParams, chainec.ECTypeSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	p2shAddr, err := hcutil.NewAddressScriptHash(contract, w.chainParams)
+	if err != nil {
+		return nil, err
+	}
+	outputs := contractOutputs(contractTx, p2shAddr)
+	if len(outputs) == 0 {
+		const str = "transaction does not pay the contract"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to redeem atomic swaps"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	var account uint32
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		account, err = w.keySpendAccount(addrmgrNs, recipient)
+		if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+			const str = "contract does not pay a wallet key"
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		to, err = w.NewInternalAddress(account, WithGapPolicyWrap())
+		if err != nil {
+			return nil, err
+		}
+	}
+	pkScript, err := txscript.PayToAddrScript(to)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx()
+	var total hcutil.Amount
+	for _, out := range outputs {
+		txIn := wire.NewTxIn(&out.OutPoint, nil)
+		txIn.ValueIn = int64(out.Amount)
+		tx.AddTxIn(txIn)
+		total += out.Amount
+	}
+	// Signature, public key, secret, and branch selector.
+	inputSize := p2shInputSize(1+73+1+65+1+len(secret)+1, contract)
+	fee := FeeForSize(w.RelayFee(), sweepTxSize(len(tx.TxIn), len(tx.TxIn)*inputSize, pkScript))
+	if txrules.IsDustAmount(total-fee, len(pkScript), w.RelayFee()) {
+		const str = "redeemed amount is too small to pay the fee"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	tx.AddTxOut(wire.NewTxOut(int64(total-fee), pkScript))
+
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		key, pubKey, done, err := w.scriptSigningKey(addrmgrNs, recipient)
+		if err != nil {
+			return err
+		}
+		defer done()
+		for i, txIn := range tx.TxIn {
+			sig, err := txscript.RawTxInSignature(tx, i, contract,
+				txscript.SigHashAll, key)
+			if err != nil {
+				return err
+			}
+			txIn.SignatureScript, err = txscript.NewScriptBuilder().
+				AddData(sig).AddData(pubKey).AddData(secret).
+				AddOp(txscript.OP_TRUE).AddData(contract).Script()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializeSize())
+	err = tx.Serialize(&buf)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := w.PublishTransaction(tx, buf.Bytes(), chainClient)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Redeemed %v from atomic swap contract %v in transaction %v",
+		total-fee, p2shAddr, txHash)
+	return txHash, nil
+}
+
+// RefundSwap publishes a transaction refunding the outputs of a contract
+// transaction paying an atomic swap contract after the contract's lock time.
+// Unlike RefundScript, the contract need not be imported.  If to is nil, the
+// outputs are paid to a new internal address of the account of the refund
+// key, or the default account for imported keys.
+func (w *Wallet) RefundSwap(contract []byte, contractTx *wire.MsgTx, to hcutil.Address) (*chainhash.Hash, error) {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	pushes, err := txscript.ExtractAtomicSwapDataPushes(txscript.DefaultScriptVersion, contract)
+	s := parseRefundableScript(contract, w.chainParams)
+	if err != nil || pushes == nil || s == nil {
+		const str = "script is not an atomic swap contract"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	s.Outputs = contractOutputs(contractTx, s.Address)
+	if len(s.Outputs) == 0 {
+		const str = "transaction does not pay the contract"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to sign refunds"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	var account uint32
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		account, err = w.keySpendAccount(addrmgrNs, s.RefundAddress)
+		if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+			const str = "contract does not refund to a wallet key"
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+		if err != nil {
+			return err
+		}
+		tipHash, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		tipHeader, err := w.TxStore.GetBlockHeader(dbtx, &tipHash)
+		if err != nil {
+			return err
+		}
+		for i := range s.Outputs {
+			s.Outputs[i].Matured = s.matured(-1, time.Time{}, tipHeight,
+				tipHeader.Timestamp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w.publishRefund(chainClient, s, account, to)
+}
+
+// AtomicSwaps returns the atomic swap contracts funded by the wallet.  The
+// secrets of swaps initiated by the wallet are only returned when the wallet
+// is unlocked, unless the secret was already revealed by a redemption.
+func (w *Wallet) AtomicSwaps() ([]*AtomicSwap, error) {
+	var swaps []*AtomicSwap
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		return w.Manager.ForEachAtomicSwap(addrmgrNs, func(s *udb.AtomicSwap) error {
+			pushes, err := txscript.ExtractAtomicSwapDataPushes(
+				txscript.DefaultScriptVersion, s.Contract)
+			if err != nil {
+				return err
+			}
+			if pushes == nil {
+				return apperrors.E{ErrorCode: apperrors.ErrData,
+					Description: "recorded atomic swap has an invalid contract", Err: nil}
+			}
+			p2shAddr, err := hcutil.NewAddressScriptHash(s.Contract, w.chainParams)
+			if err != nil {
+				return err
+			}
+			secret := s.Secret
+			if s.SecretEncrypted {
+				secret, err = w.Manager.Decrypt(udb.CKTPrivate, s.Secret)
+				if apperrors.IsError(err, apperrors.ErrLocked) {
+					secret, err = nil, nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+			swaps = append(swaps, &AtomicSwap{
+				Contract:    s.Contract,
+				Address:     p2shAddr,
+				ContractTx:  s.ContractTx,
+				ContractOut: s.ContractOut,
+				Initiator:   s.Initiator,
+				SecretHash:  pushes.SecretHash[:],
+				Secret:      secret,
+				LockTime:    pushes.LockTime,
+				Created:     s.Created,
+			})
+			return nil
+		})
+	})
+	return swaps, err
+}
+
+// recordSwapSecrets records the secrets revealed by inputs of a transaction
+// redeeming atomic swap contracts funded by the wallet, so participants may
+// use the secret to redeem the initiator's contract.  Revealed secrets are
+// public and are recorded without encryption.
+func (w *Wallet) recordSwapSecrets(addrmgrNs walletdb.ReadWriteBucket, tx *wire.MsgTx) error {
+	for _, in := range tx.TxIn {
+		// Redemptions push the signature, public key, secret, branch
+		// selector, and contract.
+		toks, err := tokenizeScript(in.SignatureScript)
+		if err != nil || len(toks) != 5 {
+			continue
+		}
+		contract := toks[4].data
+		swap, err := w.Manager.FetchAtomicSwap(addrmgrNs, hcutil.Hash160(contract))
+		if err != nil {
+			return err
+		}
+		if swap == nil || (swap.Secret != nil && !swap.SecretEncrypted) {
+			// Secrets of initiated swaps are recorded in plaintext
+			// once revealed.
+			continue
+		}
+		pushes, err := txscript.ExtractAtomicSwapDataPushes(
+			txscript.DefaultScriptVersion, contract)
+		if err != nil || pushes == nil {
+			continue
+		}
+		secret := toks[2].data
+		if !bytes.Equal(atomicSwapSecretHash(secret), pushes.SecretHash[:]) {
+			continue
+		}
+		swap.Secret = append([]byte(nil), secret...)
+		swap.SecretEncrypted = false
+		err = w.Manager.PutAtomicSwap(addrmgrNs, hcutil.Hash160(contract), swap)
+		if err != nil {
+			return err
+		}
+		log.Infof("Recorded the secret of atomic swap contract %x "+
+			"redeemed by transaction %v", hcutil.Hash160(contract), tx.TxHash())
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// testSwapAddress returns a secp256k1 pubkey hash address with a hash160 of
+// repeated b bytes.
+func testSwapAddress(t *testing.T, b byte) *hcutil.AddressPubKeyHash {
+	addr, err := hcutil.NewAddressPubKeyHash(bytes.Repeat([]byte{b}, 20),
+		testParams, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+// testSwapContract returns an atomic swap contract with the secret hash of
+// secret.
+func testSwapContract(t *testing.T, secret []byte) []byte {
+	contract, err := atomicSwapContract(testSwapAddress(t, 1),
+		testSwapAddress(t, 2), atomicSwapSecretHash(secret), 500000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contract
+}
+
+// redemptionTx returns a transaction redeeming a contract with secret.
+func redemptionTx(t *testing.T, contract, secret []byte) *wire.MsgTx {
+	sigScript, err := txscript.NewScriptBuilder().AddData(make([]byte, 71)).
+		AddData(make([]byte, 33)).AddData(secret).AddOp(txscript.OP_TRUE).
+		AddData(contract).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, sigScript))
+	tx.AddTxOut(wire.NewTxOut(1e8, foreignPkScript))
+	return tx
+}
+
+func TestAtomicSwapContract(t *testing.T) {
+	recipient, refund := testSwapAddress(t, 1), testSwapAddress(t, 2)
+	secretHash := atomicSwapSecretHash([]byte("secret"))
+	const lockTime = 1500000000
+	contract, err := atomicSwapContract(recipient, refund, secretHash, lockTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pushes, err := txscript.ExtractAtomicSwapDataPushes(
+		txscript.DefaultScriptVersion, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushes == nil {
+		t.Fatal("contract is not recognized as an atomic swap contract")
+	}
+	if !bytes.Equal(pushes.RecipientHash160[:], recipient.Hash160()[:]) {
+		t.Errorf("recipient hash160 %x, want %x", pushes.RecipientHash160,
+			recipient.Hash160()[:])
+	}
+	if !bytes.Equal(pushes.RefundHash160[:], refund.Hash160()[:]) {
+		t.Errorf("refund hash160 %x, want %x", pushes.RefundHash160,
+			refund.Hash160()[:])
+	}
+	if !bytes.Equal(pushes.SecretHash[:], secretHash) {
+		t.Errorf("secret hash %x, want %x", pushes.SecretHash, secretHash)
+	}
+	if pushes.LockTime != lockTime {
+		t.Errorf("lock time %d, want %d", pushes.LockTime, lockTime)
+	}
+}
+
+func TestRecordSwapSecrets(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		return w.Manager.Unlock(dbtx.ReadBucket(waddrmgrNamespaceKey),
+			testPrivPass)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	participantSecret := []byte("participant secret")
+	participantContract := testSwapContract(t, participantSecret)
+	initiatorSecret := []byte("initiator secret")
+	initiatorContract := testSwapContract(t, initiatorSecret)
+	encryptedSecret, err := w.Manager.Encrypt(udb.CKTPrivate, initiatorSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+		err := w.Manager.PutAtomicSwap(ns, hcutil.Hash160(participantContract),
+			&udb.AtomicSwap{Contract: participantContract, Created: time.Now()})
+		if err != nil {
+			return err
+		}
+		return w.Manager.PutAtomicSwap(ns, hcutil.Hash160(initiatorContract),
+			&udb.AtomicSwap{
+				Contract:        initiatorContract,
+				Initiator:       true,
+				Secret:          encryptedSecret,
+				SecretEncrypted: true,
+				Created:         time.Now(),
+			})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := func(tx *wire.MsgTx) {
+		err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			return w.recordSwapSecrets(dbtx.ReadWriteBucket(waddrmgrNamespaceKey), tx)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	secrets := func() map[bool][]byte {
+		swaps, err := w.AtomicSwaps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(swaps) != 2 {
+			t.Fatalf("wallet records %d swaps, want 2", len(swaps))
+		}
+		m := make(map[bool][]byte)
+		for _, s := range swaps {
+			m[s.Initiator] = s.Secret
+		}
+		return m
+	}
+
+	// The initiator's secret is only stored encrypted, and is only
+	// returned while the wallet is unlocked.
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		ns := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		s, err := w.Manager.FetchAtomicSwap(ns, hcutil.Hash160(initiatorContract))
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(s.Secret, initiatorSecret) {
+			t.Error("initiator secret is stored in plaintext")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := secrets()[true]; !bytes.Equal(got, initiatorSecret) {
+		t.Errorf("unlocked wallet returned initiator secret %q", got)
+	}
+	w.Manager.Lock()
+	if got := secrets()[true]; got != nil {
+		t.Errorf("locked wallet returned initiator secret %q", got)
+	}
+
+	// Redemptions revealing the wrong secret are ignored.
+	record(redemptionTx(t, participantContract, []byte("wrong secret")))
+	if got := secrets()[false]; got != nil {
+		t.Fatalf("recorded secret %q of a mismatched redemption", got)
+	}
+
+	// Revealed secrets are recorded, even while the wallet is locked.
+	record(redemptionTx(t, participantContract, participantSecret))
+	record(redemptionTx(t, initiatorContract, initiatorSecret))
+	s := secrets()
+	if !bytes.Equal(s[false], participantSecret) {
+		t.Errorf("recorded participant secret %q, want %q", s[false],
+			participantSecret)
+	}
+	if !bytes.Equal(s[true], initiatorSecret) {
+		t.Errorf("recorded initiator secret %q, want %q", s[true],
+			initiatorSecret)
+	}
+
+	// Transactions not redeeming a recorded swap are ignored.
+	record(redemptionTx(t, testSwapContract(t, []byte("other")), []byte("other")))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AuditLog returns at most count entries of the audit log of balance-affecting
+// events, in the order they were recorded, beginning with the entry with
+// sequence number from.
+func (w *Wallet) AuditLog(from uint64, count int) ([]udb.AuditEntry, error) {
+	var entries []udb.AuditEntry
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		entries, err = w.TxStore.AuditLog(txmgrNs, from, count)
+		return err
+	})
+	return entries, err
+}
+
+// auditTransaction appends audit log entries for the credits and debits of a
+// transaction recorded by the wallet.  Each transaction is audited once, when
+// it is first recorded, so transactions recorded as unmined are not audited
+// again when they are mined.
+func (w *Wallet) auditTransaction(txmgrNs walletdb.ReadWriteBucket, hash *chainhash.Hash, trigger string) error {
+	_, _, audited, err := w.TxStore.AuditedTx(txmgrNs, hash)
+	if err != nil || audited {
+		return err
+	}
+	details, err := w.TxStore.TxDetails(txmgrNs, hash)
+	if err != nil || details == nil {
+		return err
+	}
+
+	var credited, debited hcutil.Amount
+	for i := range details.Credits {
+		credited += details.Credits[i].Amount
+	}
+	for i := range details.Debits {
+		debited += details.Debits[i].Amount
+	}
+
+	now := time.Now()
+	entry := udb.AuditEntry{
+		Time:        now,
+		TxHash:      *hash,
+		BlockHash:   details.Block.Hash,
+		BlockHeight: details.Block.Height,
+		Trigger:     trigger,
+	}
+	if credited != 0 {
+		entry.Event = udb.AuditCredit
+		switch {
+		case isVote(&details.MsgTx):
+			entry.Event = udb.AuditVoteReward
+		case isRevocation(&details.MsgTx):
+			entry.Event = udb.AuditRevocation
+		}
+		entry.Amount = credited
+		err := w.TxStore.AppendAuditEntry(txmgrNs, &entry)
+		if err != nil {
+			return err
+		}
+	}
+	if debited != 0 {
+		entry.Event = udb.AuditDebit
+		entry.Amount = debited
+		err := w.TxStore.AppendAuditEntry(txmgrNs, &entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditRemoval appends an abandon or prune audit log entry for a transaction
+// removed from the wallet, reversing the net amount of its audited credits and
+// debits.  Transactions which were never audited are ignored.  height is the
+// height recorded by the entry.
+func (w *Wallet) auditRemoval(txmgrNs walletdb.ReadWriteBucket, hash *chainhash.Hash,
+	event udb.AuditEvent, height int32, trigger string) error {
+
+	credited, debited, audited, err := w.TxStore.AuditedTx(txmgrNs, hash)
+	if err != nil || !audited {
+		return err
+	}
+	return w.TxStore.AppendAuditEntry(txmgrNs, &udb.AuditEntry{
+		Time:        time.Now(),
+		Event:       event,
+		TxHash:      *hash,
+		Amount:      credited - debited,
+		BlockHeight: height,
+		Trigger:     trigger,
+	})
+}
+
+// auditRollback appends an audit log entry for the removal of all blocks at
+// and above height from the main chain.
+func (w *Wallet) auditRollback(txmgrNs walletdb.ReadWriteBucket, height int32, trigger string) error {
+	return w.TxStore.AppendAuditEntry(txmgrNs, &udb.AuditEntry{
+		Time:        time.Now(),
+		Event:       udb.AuditRollback,
+		BlockHeight: height,
+		Trigger:     trigger,
+	})
+}
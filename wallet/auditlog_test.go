@@ -0,0 +1,94 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// auditLog returns all entries of the wallet's audit log.
+func auditLog(t *testing.T, w *Wallet) []udb.AuditEntry {
+	entries, err := w.AuditLog(0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestAuditMinedOnce(t *testing.T) {
+	w, headers, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, foreignSigScript))
+	funding.AddTxOut(wire.NewTxOut(10e8, walletPkScript(t, w, udb.ExternalBranch, 0)))
+	addUnminedTx(t, w, funding)
+
+	// Mining the unmined transaction does not audit it again.
+	rec, err := udb.NewTxRecordFromMsgTx(funding, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &headers[1]
+	blockMeta := &udb.BlockMeta{
+		Block: udb.Block{Hash: header.BlockHash, Height: 1},
+		Time:  time.Now(),
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.processTransactionRecord(dbtx, rec, &header.SerializedHeader,
+			blockMeta, "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := auditLog(t, w)
+	if len(entries) != 1 {
+		t.Fatalf("%d audit log entries, want 1", len(entries))
+	}
+	e := &entries[0]
+	if e.Event != udb.AuditCredit || e.TxHash != rec.Hash || e.Amount != 10e8 {
+		t.Errorf("entry %+v does not audit the credit of %v", e, &rec.Hash)
+	}
+}
+
+func TestAuditAbandon(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	hash := spend.TxHash()
+	before := len(auditLog(t, w))
+
+	err := w.AbandonTransaction(&hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := auditLog(t, w)
+	if len(entries) != before+1 {
+		t.Fatalf("%d audit log entries after abandoning, want %d",
+			len(entries), before+1)
+	}
+	e := &entries[before]
+	if e.Event != udb.AuditAbandon || e.TxHash != hash ||
+		e.Amount != hcutil.Amount(699e6-10e8) || e.Trigger != "abandontransaction" {
+		t.Errorf("entry %+v does not audit abandoning %v", e, &hash)
+	}
+
+	// The abandoned transaction is audited again if it is recorded again.
+	addUnminedTx(t, w, spend)
+	entries = auditLog(t, w)
+	if len(entries) != before+3 {
+		t.Errorf("%d audit log entries after recording the abandoned "+
+			"transaction again, want %d", len(entries), before+3)
+	}
+}
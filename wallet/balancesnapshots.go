@@ -0,0 +1,95 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SetBalanceSnapshotInterval sets the number of blocks between snapshots of
+// the account balances, which are recorded as blocks are connected at heights
+// which are multiples of the interval.  Zero disables snapshots.
+func (w *Wallet) SetBalanceSnapshotInterval(blocks int32) {
+	w.balanceSnapshotMu.Lock()
+	w.balanceSnapshotInterval = blocks
+	w.balanceSnapshotMu.Unlock()
+}
+
+// BalanceSnapshotInterval returns the number of blocks between snapshots of
+// the account balances, or zero if snapshots are disabled.
+func (w *Wallet) BalanceSnapshotInterval() int32 {
+	w.balanceSnapshotMu.Lock()
+	blocks := w.balanceSnapshotInterval
+	w.balanceSnapshotMu.Unlock()
+	return blocks
+}
+
+// autoSnapshotBalances records a snapshot of the account balances when a block
+// is connected at a multiple of the snapshot interval.  Blocks connected while
+// the wallet is not running, or while a reorganization is processed, are not
+// snapshotted.
+func (w *Wallet) autoSnapshotBalances(height int32) {
+	interval := w.BalanceSnapshotInterval()
+	if interval <= 0 || height%interval != 0 {
+		return
+	}
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		// The tip may have moved since the block was connected.
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		if tipHeight != height {
+			return nil
+		}
+		_, err := w.TxStore.PutBalanceSnapshot(txmgrNs, addrmgrNs)
+		return err
+	})
+	if err != nil {
+		log.Errorf("Failed to snapshot balances at height %d: %v", height, err)
+	}
+}
+
+// BalanceSnapshotAtHeight returns the latest snapshot of the account balances
+// at or below a main chain height.  The height of the returned snapshot is
+// below the requested height when no snapshot was recorded at that height.
+func (w *Wallet) BalanceSnapshotAtHeight(height int32) (*udb.BalanceSnapshot, error) {
+	var snapshot *udb.BalanceSnapshot
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		if height > tipHeight {
+			const str = "height is above the main chain tip"
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+		var err error
+		snapshot, err = w.TxStore.BalanceSnapshotAt(txmgrNs, height)
+		return err
+	})
+	return snapshot, err
+}
+
+// BalanceSnapshotAtHash returns the latest snapshot of the account balances at
+// or below the height of a main chain block.
+func (w *Wallet) BalanceSnapshotAtHash(blockHash *chainhash.Hash) (*udb.BalanceSnapshot, error) {
+	var snapshot *udb.BalanceSnapshot
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if inMainChain, _ := w.TxStore.BlockInMainChain(dbtx, blockHash); !inMainChain {
+			const str = "block is not in the main chain"
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+		header, err := w.TxStore.GetBlockHeader(dbtx, blockHash)
+		if err != nil {
+			return err
+		}
+		snapshot, err = w.TxStore.BalanceSnapshotAt(txmgrNs, int32(header.Height))
+		return err
+	})
+	return snapshot, err
+}
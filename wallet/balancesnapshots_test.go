@@ -0,0 +1,68 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+)
+
+func TestAutoSnapshotBalances(t *testing.T) {
+	w, headers, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	tipHash, tipHeight := mainChainTip(t, w)
+	if tipHash != headers[len(headers)-1].BlockHash {
+		t.Fatalf("main chain tip %v is not the last test block", &tipHash)
+	}
+
+	// Snapshots are disabled by default, and are otherwise only recorded
+	// at multiples of the interval, and of blocks which remain the tip.
+	w.autoSnapshotBalances(tipHeight)
+	w.SetBalanceSnapshotInterval(2)
+	w.autoSnapshotBalances(tipHeight)
+	w.autoSnapshotBalances(tipHeight - 1)
+	_, err := w.BalanceSnapshotAtHeight(tipHeight)
+	if !apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		t.Errorf("snapshot returned %v when none should have been "+
+			"recorded, want ErrValueNoExists", err)
+	}
+
+	w.SetBalanceSnapshotInterval(tipHeight)
+	w.autoSnapshotBalances(tipHeight)
+	snapshot, err := w.BalanceSnapshotAtHeight(tipHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Height != tipHeight || snapshot.BlockHash != tipHash {
+		t.Errorf("snapshot at block %v (height %d), want %v (height %d)",
+			&snapshot.BlockHash, snapshot.Height, &tipHash, tipHeight)
+	}
+	snapshot, err = w.BalanceSnapshotAtHash(&tipHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.BlockHash != tipHash {
+		t.Errorf("snapshot of block %v is at block %v", &tipHash,
+			&snapshot.BlockHash)
+	}
+
+	_, err = w.BalanceSnapshotAtHeight(tipHeight - 1)
+	if !apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		t.Errorf("snapshot below the only recorded snapshot returned %v, "+
+			"want ErrValueNoExists", err)
+	}
+	_, err = w.BalanceSnapshotAtHeight(tipHeight + 1)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("snapshot above the tip returned %v, want ErrInput", err)
+	}
+	_, err = w.BalanceSnapshotAtHash(&chainhash.Hash{1})
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("snapshot of a block outside the main chain returned %v, "+
+			"want ErrInput", err)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"time"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// birthdayMargin is subtracted from a birthday time before it is resolved to a
+// block height, since block timestamps may run ahead of the actual time and a
+// seed may be written down some time before the wallet is created.
+const birthdayMargin = 48 * time.Hour
+
+// Birthday returns the wallet birthday, or nil if the wallet has none and is
+// synced from the genesis block.
+func (w *Wallet) Birthday() (*udb.Birthday, error) {
+	var b *udb.Birthday
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		var err error
+		b, err = w.TxStore.Birthday(dbtx.ReadBucket(wtxmgrNamespaceKey))
+		return err
+	})
+	return b, err
+}
+
+// SetBirthday records the wallet birthday.  Blocks before the birthday are
+// skipped by the initial sync and by rescans which begin at the genesis block.
+func (w *Wallet) SetBirthday(b *udb.Birthday) error {
+	return walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.TxStore.SetBirthday(dbtx.ReadWriteBucket(wtxmgrNamespaceKey), b)
+	})
+}
+
+// BirthdayHeight returns the height of the wallet birthday block.  Zero is
+// returned when the wallet has no birthday or it has not yet been resolved.
+func (w *Wallet) BirthdayHeight() (int32, error) {
+	b, err := w.Birthday()
+	if err != nil || b == nil || !b.Resolved() {
+		return 0, err
+	}
+	return b.Height, nil
+}
+
+// skipToBirthday resolves the wallet birthday using the fetched main chain
+// headers and advances the processed transactions block marker to the block
+// before the birthday, so syncing never rescans blocks mined before the wallet
+// existed.  The marker is never moved backwards.
+func (w *Wallet) skipToBirthday() error {
+	return walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		b, err := w.TxStore.Birthday(ns)
+		if err != nil || b == nil {
+			return err
+		}
+		if !b.Resolved() {
+			b.Height, err = w.TxStore.FirstMainChainBlockAfter(ns,
+				b.Time.Add(-birthdayMargin))
+			if err != nil {
+				return err
+			}
+			err = w.TxStore.SetBirthday(ns, b)
+			if err != nil {
+				return err
+			}
+			log.Infof("Wallet birthday %v is block height %v",
+				b.Time.Format(time.RFC3339), b.Height)
+		}
+		if b.Height == 0 {
+			return nil
+		}
+
+		_, tipHeight := w.TxStore.MainChainTip(ns)
+		height := b.Height - 1
+		if height > tipHeight {
+			height = tipHeight
+		}
+		hash, err := w.TxStore.GetMainChainBlockHashForHeight(ns, height)
+		if err != nil {
+			return err
+		}
+		return w.TxStore.UpdateProcessedTxsBlockMarker(dbtx, &hash)
+	})
+}
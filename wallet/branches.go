@@ -0,0 +1,126 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// NewExternalBranch creates an additional external branch of an account, used
+// to segregate the addresses given out for a purpose (such as donations or
+// invoices) from the account's other receiving addresses, and returns the
+// branch number.  Addresses of the branch are returned by NewExternalAddress
+// using the WithExternalBranch option, and are watched for transactions the
+// same as the account's BIP0044 branches.
+//
+// Additional branches are only supported by ECDSA accounts, and are not
+// discovered when restoring a wallet from its seed.
+func (w *Wallet) NewExternalBranch(account uint32, name string) (uint32, error) {
+	gapLimit := uint32(w.gapLimit)
+
+	var branch uint32
+	var branchXpub *hdkeychain.ExtendedKey
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		var err error
+		branch, err = w.Manager.NewExternalBranch(ns, account, name)
+		if err != nil {
+			return err
+		}
+		acctXpub, err := w.Manager.AccountExtendedPubKey(tx, account)
+		if err != nil {
+			return err
+		}
+		branchXpub, err = acctXpub.Child(branch)
+		if err != nil {
+			return err
+		}
+
+		// Record the addresses through the gap limit so transactions
+		// paying them are recognized as the wallet's.
+		return w.Manager.SyncAccountToAddrIndex(ns, account, gapLimit-1, branch)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	w.addressBuffersMu.Lock()
+	if ad, ok := w.addressBuffers[account]; ok {
+		if ad.albExtra == nil {
+			ad.albExtra = make(map[uint32]*addressBuffer)
+		}
+		ad.albExtra[branch] = &addressBuffer{
+			branchXpub: branchXpub,
+			lastUsed:   ^uint32(0),
+		}
+	}
+	w.addressBuffersMu.Unlock()
+
+	if client := w.ChainClient(); client != nil {
+		addrs, err := deriveChildAddresses(branchXpub, 0, gapLimit, w.chainParams)
+		if err != nil {
+			return 0, err
+		}
+		err = client.LoadTxFilter(false, addrs, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	log.Infof("Created external branch %d (%q) of account %d", branch, name,
+		account)
+	return branch, nil
+}
+
+// ExternalBranches returns the properties of every additional external branch
+// of an account, in order of branch number.
+func (w *Wallet) ExternalBranches(account uint32) ([]udb.BranchProperties, error) {
+	var branches []udb.BranchProperties
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		return w.Manager.ForEachExternalBranch(ns, account, func(props *udb.BranchProperties) error {
+			branches = append(branches, *props)
+			return nil
+		})
+	})
+	return branches, err
+}
+
+// ExternalBranchNumber returns the branch number of the additional external
+// branch of an account with the given name.
+func (w *Wallet) ExternalBranchNumber(account uint32, name string) (uint32, error) {
+	var branch uint32
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		branch, err = w.Manager.LookupExternalBranch(ns, account, name)
+		return err
+	})
+	return branch, err
+}
+
+// loadExternalBranchBuffers adds the address buffers of the additional
+// external branches of an account recorded in the database to ad.
+func (w *Wallet) loadExternalBranchBuffers(ns walletdb.ReadBucket, account uint32,
+	acctXpub *hdkeychain.ExtendedKey, ad *bip0044AccountData) error {
+
+	return w.Manager.ForEachExternalBranch(ns, account, func(props *udb.BranchProperties) error {
+		branchXpub, err := acctXpub.Child(props.Branch)
+		if err != nil {
+			return err
+		}
+		if ad.albExtra == nil {
+			ad.albExtra = make(map[uint32]*addressBuffer)
+		}
+		ad.albExtra[props.Branch] = &addressBuffer{
+			branchXpub: branchXpub,
+			lastUsed:   props.LastUsedIndex,
+			cursor:     props.LastReturnedIndex - props.LastUsedIndex,
+		}
+		return nil
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// TestExternalBranchesAvoidAccountProofKey checks that additional external
+// branches never derive the account proof key, which would otherwise control
+// funds paid to the branch's addresses.
+func TestExternalBranchesAvoidAccountProofKey(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	if AccountProofBranch >= udb.FirstExtraExternalBranch {
+		t.Fatalf("account proof branch %d is in the additional branch range "+
+			"starting at %d", AccountProofBranch, udb.FirstExtraExternalBranch)
+	}
+
+	var proofAddr string
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		acctXpub, err := w.Manager.AccountExtendedPubKey(dbtx,
+			udb.DefaultAccountNum)
+		if err != nil {
+			return err
+		}
+		proofKey, err := accountProofKey(acctXpub)
+		if err != nil {
+			return err
+		}
+		addr, err := proofKey.Address(w.chainParams, proofKey.GetAlgType())
+		if err != nil {
+			return err
+		}
+		proofAddr = addr.EncodeAddress()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, name := range []string{"invoices", "donations", "refunds"} {
+		branch, err := w.NewExternalBranch(udb.DefaultAccountNum, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := udb.FirstExtraExternalBranch + uint32(i); branch != want {
+			t.Errorf("branch %q: got branch %d, want %d", name, branch, want)
+		}
+		if branch == AccountProofBranch {
+			t.Fatalf("branch %q collides with the account proof branch", name)
+		}
+		addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+			branch, 0, uint32(w.gapLimit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range addrs {
+			if a.EncodeAddress() == proofAddr {
+				t.Fatalf("branch %q derives the account proof key", name)
+			}
+		}
+	}
+}
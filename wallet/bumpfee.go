@@ -0,0 +1,212 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// BumpFeeResult describes an unmined transaction replaced by BumpFee.
+type BumpFeeResult struct {
+	Original    chainhash.Hash
+	Replacement *wire.MsgTx
+	OldFee      hcutil.Amount
+	NewFee      hcutil.Amount
+}
+
+// BumpFee replaces an unmined transaction authored by the wallet with a
+// transaction spending the same inputs to the same destinations and paying a
+// higher fee, which is deducted from the change output.  The new fee is the
+// fee for the transaction's size at feePerKb, or the relay fee when feePerKb is
+// zero, and is raised if necessary to exceed the original fee by at least the
+// relay fee for the transaction's size.
+//
+// hcd does not replace transactions in its mempool, so a replacement would only
+// be rejected as a double spend while the original remains there.  Transactions
+// may only be replaced once they have left hcd's mempool, for example after
+// expiring or being evicted, and an error with code ErrInput is returned while
+// the original is in the mempool of the consensus RPC server.
+//
+// The original transaction is removed from the wallet and recorded as replaced,
+// the replacement is recorded as an unmined transaction, and the replacement
+// is sent to the consensus RPC server.  If the server rejects the replacement,
+// the wallet is left unchanged.  The wallet must be unlocked.
+//
+// An error with code ErrValueNoExists is returned if hash does not identify an
+// unmined wallet transaction, and errors with code ErrInput describe
+// transactions which can not be replaced.
+func (w *Wallet) BumpFee(hash *chainhash.Hash, feePerKb hcutil.Amount, chainClient ChainClient) (*BumpFeeResult, error) {
+	relayFee := w.RelayFee()
+	if feePerKb == 0 {
+		feePerKb = relayFee
+	}
+
+	var res *BumpFeeResult
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		var err error
+		res, err = w.replacementTx(dbtx, hash, feePerKb, relayFee)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mempool, err := chainClient.GetRawMempool(hcjson.GRMRegular)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range mempool {
+		if *h == *hash {
+			str := fmt.Sprintf("transaction %v can not be replaced while it "+
+				"is in hcd's mempool, as hcd does not accept replacements "+
+				"of mempool transactions", hash)
+			return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+	}
+
+	signErrs, err := w.SignTransaction(res.Replacement, txscript.SigHashAll,
+		nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(signErrs) != 0 {
+		return nil, fmt.Errorf("failed to sign replacement input %d: %v",
+			signErrs[0].InputIndex, signErrs[0].Error)
+	}
+
+	rec, err := udb.NewTxRecordFromMsgTx(res.Replacement, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		// The original may have been mined or removed since the
+		// replacement was created.
+		removed, err := w.TxStore.RemoveUnminedTx(txmgrNs, hash)
+		if err != nil {
+			return err
+		}
+		for _, r := range removed {
+			err := w.auditRemoval(txmgrNs, &r.Hash, udb.AuditAbandon,
+				-1, "bumpfee")
+			if err != nil {
+				return err
+			}
+		}
+		err = w.processTransactionRecord(dbtx, rec, nil, nil, "bumpfee")
+		if err != nil {
+			return err
+		}
+		err = w.TxStore.PutReplacedTx(txmgrNs, hash, &rec.Hash)
+		if err != nil {
+			return err
+		}
+		_, err = w.sendRawTransaction(txmgrNs, chainClient, res.Replacement,
+			w.AllowHighFees())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Replaced unmined transaction %v with %v, increasing the fee "+
+		"from %v to %v", hash, &rec.Hash, res.OldFee, res.NewFee)
+	return res, nil
+}
+
+// replacementTx creates the unsigned replacement of an unmined transaction
+// for BumpFee.
+func (w *Wallet) replacementTx(dbtx walletdb.ReadTx, hash *chainhash.Hash,
+	feePerKb, relayFee hcutil.Amount) (*BumpFeeResult, error) {
+
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+	details, err := w.TxStore.TxDetails(txmgrNs, hash)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil || details.Block.Height != -1 {
+		str := fmt.Sprintf("no unmined transaction %v", hash)
+		return nil, apperrors.E{ErrorCode: apperrors.ErrValueNoExists, Description: str, Err: nil}
+	}
+	tx := &details.MsgTx
+
+	inputError := func(reason string) error {
+		str := fmt.Sprintf("transaction %v can not be replaced: %s", hash, reason)
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if stake.DetermineTxType(tx) != stake.TxTypeRegular {
+		return nil, inputError("only regular transactions may be replaced")
+	}
+	if len(details.Debits) != len(tx.TxIn) {
+		return nil, inputError("it spends outputs not controlled by the wallet")
+	}
+	for i := range tx.TxOut {
+		op := wire.OutPoint{Hash: *hash, Index: uint32(i), Tree: wire.TxTreeRegular}
+		if spender := w.TxStore.UnminedSpender(dbtx, &op); spender != nil {
+			return nil, inputError(fmt.Sprintf("output %d is spent by "+
+				"unmined transaction %v", i, spender))
+		}
+	}
+	changeIndex := -1
+	for _, c := range details.Credits {
+		if c.Change {
+			if changeIndex != -1 {
+				return nil, inputError("it has multiple change outputs")
+			}
+			changeIndex = int(c.Index)
+		}
+	}
+	if changeIndex == -1 {
+		return nil, inputError("it has no change output to deduct the fee from")
+	}
+
+	var inputAmount, outputAmount hcutil.Amount
+	for _, d := range details.Debits {
+		inputAmount += d.Amount
+	}
+	for _, out := range tx.TxOut {
+		outputAmount += hcutil.Amount(out.Value)
+	}
+	oldFee := inputAmount - outputAmount
+
+	size := tx.SerializeSize()
+	newFee := feeForSize(feePerKb, size)
+	if minFee := oldFee + feeForSize(relayFee, size); newFee < minFee {
+		newFee = minFee
+	}
+
+	replacement := tx.Copy()
+	for _, in := range replacement.TxIn {
+		in.SignatureScript = nil
+	}
+	change := replacement.TxOut[changeIndex]
+	change.Value -= int64(newFee - oldFee)
+	if change.Value < 0 || txrules.IsDustAmount(hcutil.Amount(change.Value),
+		len(change.PkScript), relayFee) {
+		return nil, inputError(fmt.Sprintf("change of %v can not pay the "+
+			"increased fee of %v", hcutil.Amount(tx.TxOut[changeIndex].Value),
+			newFee))
+	}
+
+	return &BumpFeeResult{
+		Original:    *hash,
+		Replacement: replacement,
+		OldFee:      oldFee,
+		NewFee:      newFee,
+	}, nil
+}
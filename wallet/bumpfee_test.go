@@ -0,0 +1,187 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// foreignPkScript is a P2PKH output script paying an address the test wallets
+// do not control.
+var foreignPkScript = func() []byte {
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).AddData(make([]byte, 20)).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		panic(err)
+	}
+	return script
+}()
+
+// foreignSigScript is a signature script with a placeholder signature and the
+// public key of a key the test wallets do not control.  Recorded transactions
+// must have input scripts from which an address can be parsed.
+var foreignSigScript = func() []byte {
+	pubKey, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	script, err := txscript.NewScriptBuilder().AddData(make([]byte, 71)).
+		AddData(pubKey).Script()
+	if err != nil {
+		panic(err)
+	}
+	return script
+}()
+
+// walletPkScript returns the output script paying the address of the default
+// account's branch at index.  The address is recorded by the address manager
+// so that transactions paying it are relevant to the wallet.
+func walletPkScript(t *testing.T, w *Wallet, branch, index uint32) []byte {
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SyncAccountToAddrIndex(ns, udb.DefaultAccountNum,
+			index, branch)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum, branch,
+		index, index+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addrs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// addUnminedTx records tx as an unmined wallet transaction.
+func addUnminedTx(t *testing.T, w *Wallet, tx *wire.MsgTx) {
+	rec, err := udb.NewTxRecordFromMsgTx(tx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.processTransactionRecord(dbtx, rec, nil, nil, "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addSpendWithChange records an unmined transaction paying the wallet 10 coins
+// and an unmined transaction spending it, which pays 3 coins to a foreign
+// address and returns change to the wallet with a fee of 0.01 coins.  The
+// spending transaction is returned.
+func addSpendWithChange(t *testing.T, w *Wallet) *wire.MsgTx {
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, foreignSigScript))
+	funding.AddTxOut(wire.NewTxOut(10e8, walletPkScript(t, w, udb.ExternalBranch, 0)))
+	addUnminedTx(t, w, funding)
+
+	spend := wire.NewMsgTx()
+	spend.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: funding.TxHash()}, foreignSigScript))
+	spend.AddTxOut(wire.NewTxOut(3e8, foreignPkScript))
+	spend.AddTxOut(wire.NewTxOut(699e6, walletPkScript(t, w, udb.InternalBranch, 0)))
+	addUnminedTx(t, w, spend)
+	return spend
+}
+
+func TestBumpFeeRefusesMempoolTx(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+	if err := w.Unlock(testPrivPass, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	spend := addSpendWithChange(t, w)
+	hash := spend.TxHash()
+	c := testhelpers.NewMockChainClient()
+	c.Mempool[hcjson.GRMRegular] = []*chainhash.Hash{&hash}
+
+	_, err := w.BumpFee(&hash, 0, c)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Fatalf("bumping the fee of a mempool transaction returned %v, want "+
+			"an ErrInput error", err)
+	}
+	if sent := c.SentTransactions(); len(sent) != 0 {
+		t.Errorf("%d transactions were published", len(sent))
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if !w.TxStore.ExistsTx(txmgrNs, &hash) {
+			t.Error("refused transaction was removed from the wallet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+	if err := w.Unlock(testPrivPass, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	spend := addSpendWithChange(t, w)
+	hash := spend.TxHash()
+	c := testhelpers.NewMockChainClient()
+
+	res, err := w.BumpFee(&hash, 0, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.OldFee != 1e6 {
+		t.Errorf("old fee %v, want %v", res.OldFee, hcutil.Amount(1e6))
+	}
+	minFee := res.OldFee + feeForSize(w.RelayFee(), spend.SerializeSize())
+	if res.NewFee < minFee {
+		t.Errorf("new fee %v is less than the minimum %v", res.NewFee, minFee)
+	}
+	if v := res.Replacement.TxOut[1].Value; v != 699e6-int64(res.NewFee-res.OldFee) {
+		t.Errorf("replacement change %v does not pay the increased fee", v)
+	}
+	sent := c.SentTransactions()
+	if len(sent) != 1 || sent[0].TxHash() != res.Replacement.TxHash() {
+		t.Fatalf("replacement was not published")
+	}
+
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if w.TxStore.ExistsTx(txmgrNs, &hash) {
+			t.Error("replaced transaction remains in the wallet")
+		}
+		replacementHash := res.Replacement.TxHash()
+		if !w.TxStore.ExistsTx(txmgrNs, &replacementHash) {
+			t.Error("replacement was not recorded")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The replaced transaction no longer identifies an unmined transaction.
+	_, err = w.BumpFee(&hash, 0, c)
+	if !apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		t.Errorf("bumping the fee of a replaced transaction returned %v, "+
+			"want an ErrValueNoExists error", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/json"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcrpcclient"
+	"github.com/HcashOrg/hcwallet/chain"
+)
+
+// ChainClient describes the methods of the consensus RPC client used by the
+// wallet to query the blockchain, publish transactions, and load transaction
+// filters.  It is implemented by *hcrpcclient.Client, and allows the wallet to
+// be tested without a running hcd.
+type ChainClient interface {
+	Disconnected() bool
+	GetBestBlock() (*chainhash.Hash, int64, error)
+	GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error)
+	GetBlockHash(blockHeight int64) (*chainhash.Hash, error)
+	GetHeaders(blockLocators []chainhash.Hash, hashStop *chainhash.Hash) (*hcjson.GetHeadersResult, error)
+	GetRawMempool(txType hcjson.GetRawMempoolTxTypeCmd) ([]*chainhash.Hash, error)
+	GetRawTransaction(txHash *chainhash.Hash) (*hcutil.Tx, error)
+	GetRawTransactionVerbose(txHash *chainhash.Hash) (*hcjson.TxRawResult, error)
+	GetStakeDifficulty() (*hcjson.GetStakeDifficultyResult, error)
+	GetTxOut(txHash *chainhash.Hash, index uint32, mempool bool) (*hcjson.GetTxOutResult, error)
+	SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error)
+	TxFeeInfo(blocks *uint32, start *uint32, end *uint32) (*hcjson.TxFeeInfoResult, error)
+	RawRequest(method string, params []json.RawMessage) (json.RawMessage, error)
+
+	ExistsAddress(address hcutil.Address) (bool, error)
+	ExistsAddresses(addresses []hcutil.Address) (string, error)
+	ExistsLiveTicket(hash *chainhash.Hash) (bool, error)
+	ExistsLiveTickets(hashes []*chainhash.Hash) (string, error)
+	ExistsExpiredTickets(hashes []*chainhash.Hash) (string, error)
+	ExistsMissedTickets(hashes []*chainhash.Hash) (string, error)
+
+	LoadTxFilter(reload bool, addresses []hcutil.Address, outPoints []wire.OutPoint) error
+	NotifyBlocks() error
+	Rescan(blockHashes []chainhash.Hash) (*hcjson.RescanResult, error)
+	SetParams(enableOmni bool) error
+}
+
+// ConsensusClient is a ChainClient which also delivers the notifications of
+// the consensus server used to keep the wallet synchronized.  It is implemented
+// by *chain.RPCClient.
+type ConsensusClient interface {
+	ChainClient
+
+	Notifications() <-chan interface{}
+	NotificationsVoting() <-chan interface{}
+	NotifyWinningTickets() error
+	NotifySpentAndMissedTickets() error
+
+	Stop()
+	WaitForShutdown()
+}
+
+var (
+	_ ChainClient     = (*hcrpcclient.Client)(nil)
+	_ ConsensusClient = (*chain.RPCClient)(nil)
+)
@@ -0,0 +1,112 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+var _ ConsensusClient = (*testhelpers.MockChainClient)(nil)
+
+func TestLoadActiveDataFilters(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := testhelpers.NewMockChainClient()
+	err = w.LoadActiveDataFilters(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range addrs {
+		if !c.FilterContainsAddress(addr) {
+			t.Errorf("address %v was not loaded into the transaction filter", addr)
+		}
+	}
+}
+
+func TestAssociateConsensusClient(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	c := testhelpers.NewMockChainClient()
+	w.AssociateConsensusRPC(c)
+	if w.ChainClient() != ChainClient(c) {
+		t.Fatal("wallet is not associated with the client")
+	}
+	if _, err := w.requireChainClient(); err != nil {
+		t.Fatal(err)
+	}
+
+	w.DisassociateConsensusRPC()
+	if !c.Stopped() {
+		t.Error("disassociated client was not stopped")
+	}
+	if w.ChainClient() != nil {
+		t.Error("wallet remains associated with the stopped client")
+	}
+	if _, err := w.requireChainClient(); err == nil {
+		t.Error("chain client is required after disassociating the client")
+	}
+
+	// A new client may be associated after the previous client's
+	// notification handler exits.
+	c2 := testhelpers.NewMockChainClient()
+	w.AssociateConsensusRPC(c2)
+	if w.ChainClient() != ChainClient(c2) {
+		t.Fatal("wallet is not associated with the new client")
+	}
+	w.DisassociateConsensusRPC()
+}
+
+func TestWalletLifecycle(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	w.Start()
+	c := testhelpers.NewMockChainClient()
+	w.SynchronizeRPC(c)
+	if !w.SynchronizingToNetwork() {
+		t.Fatal("wallet is not synchronizing after associating a client")
+	}
+
+	// Stopping the wallet stops its client, and the client's notification
+	// handlers exit before the wallet finishes shutting down.
+	w.Stop()
+	w.WaitForShutdown()
+	if !w.ShuttingDown() {
+		t.Error("stopped wallet is not shutting down")
+	}
+	if !c.Stopped() {
+		t.Error("client of the stopped wallet was not stopped")
+	}
+	if w.SynchronizingToNetwork() {
+		t.Error("stopped wallet remains synchronizing")
+	}
+
+	// The wallet may be restarted and synchronized with a new client.
+	w.Start()
+	if w.ShuttingDown() {
+		t.Fatal("restarted wallet is shutting down")
+	}
+	c2 := testhelpers.NewMockChainClient()
+	w.SynchronizeRPC(c2)
+	if w.ChainClient() != ChainClient(c2) {
+		t.Error("restarted wallet is not associated with the new client")
+	}
+	w.Stop()
+	w.WaitForShutdown()
+	if !c2.Stopped() {
+		t.Error("client of the restarted wallet was not stopped")
+	}
+}
@@ -8,9 +8,9 @@ package wallet
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"encoding/hex"
@@ -29,7 +29,7 @@ import (
 	"github.com/HcashOrg/hcwallet/walletdb"
 )
 
-func (w *Wallet) handleConsensusRPCNotifications(chainClient *chain.RPCClient) {
+func (w *Wallet) handleConsensusRPCNotifications(chainClient ConsensusClient) {
 	for n := range chainClient.Notifications() {
 		var notificationName string
 		var err error
@@ -56,7 +56,8 @@ func (w *Wallet) handleConsensusRPCNotifications(chainClient *chain.RPCClient) {
 				break
 			}
 			err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-				return w.processSerializedTransaction(dbtx, n.Transaction, nil, nil)
+				return w.processSerializedTransaction(dbtx, n.Transaction, nil, nil,
+					notificationName)
 			})
 			if err == nil {
 				err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
@@ -70,16 +71,7 @@ func (w *Wallet) handleConsensusRPCNotifications(chainClient *chain.RPCClient) {
 		if err != nil {
 			log.Errorf("Failed to process consensus server notification "+
 				"(name: `%s`, detail: `%v`)", notificationName, err)
-			//refresh wallet data
-			var height int32 = 0
-			err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
-				ns := dbtx.ReadBucket(wtxmgrNamespaceKey)
-				_, height = w.TxStore.MainChainTip(ns)
-				return nil
-			})
-			if err == nil && !w.IsScanning() {
-				w.RescanFromHeight(w.chainClient.Client, height)
-			}
+			w.recoverFromNotificationError(notificationName, err)
 		}
 	}
 }
@@ -88,7 +80,7 @@ func (w *Wallet) handleConsensusRPCNotifications(chainClient *chain.RPCClient) {
 // server and begins handling all notifications in a background goroutine.  Any
 // previously associated client, if it is a different instance than the passed
 // client, is stopped.
-func (w *Wallet) AssociateConsensusRPC(chainClient *chain.RPCClient) {
+func (w *Wallet) AssociateConsensusRPC(chainClient ConsensusClient) {
 	w.chainClientLock.Lock()
 	defer w.chainClientLock.Unlock()
 	if w.chainClient != nil {
@@ -100,25 +92,49 @@ func (w *Wallet) AssociateConsensusRPC(chainClient *chain.RPCClient) {
 	w.chainClient = chainClient
 
 	w.wg.Add(1)
+	w.chainNtfnsWg.Add(1)
 	go func() {
 		w.handleConsensusRPCNotifications(chainClient)
+		w.chainNtfnsWg.Done()
 		w.wg.Done()
 	}()
 }
 
+// DisassociateConsensusRPC stops the consensus RPC client associated with the
+// wallet, if any, and blocks until the client has disconnected and the
+// goroutines handling its notifications have exited.  The wallet remains
+// running, and a new client may afterwards be associated with SynchronizeRPC
+// or AssociateConsensusRPC.
+func (w *Wallet) DisassociateConsensusRPC() {
+	w.chainClientLock.Lock()
+	chainClient := w.chainClient
+	w.chainClient = nil
+	w.chainClientLock.Unlock()
+	if chainClient == nil {
+		return
+	}
+
+	// The lock must not be held while waiting, as the notification handlers
+	// acquire it through requireChainClient.
+	chainClient.Stop()
+	chainClient.WaitForShutdown()
+	w.chainNtfnsWg.Wait()
+}
+
 // handleChainNotifications is the major chain notification handler that
 // receives websocket notifications about the blockchain.
-func (w *Wallet) handleChainNotifications(chainClient *chain.RPCClient) {
+func (w *Wallet) handleChainNotifications(chainClient ConsensusClient) {
 	// At the moment there is no recourse if the rescan fails for
 	// some reason, however, the wallet will not be marked synced
 	// and many methods will error early since the wallet is known
 	// to be out of date.
-	err := w.syncWithChain(chainClient.Client)
+	err := w.syncWithChain(chainClient)
 	if err != nil && !w.ShuttingDown() {
 		log.Warnf("Unable to synchronize wallet to chain: %v", err)
 	}
 
 	w.handleConsensusRPCNotifications(chainClient)
+	w.chainNtfnsWg.Done()
 	w.wg.Done()
 }
 
@@ -150,24 +166,55 @@ func (w *Wallet) extendMainChain(dbtx walletdb.ReadWriteTx, block *udb.BlockHead
 	}
 
 	for _, serializedTx := range transactions {
-		err = w.processSerializedTransaction(dbtx, serializedTx, &block.SerializedHeader, &blockMeta)
+		err = w.processSerializedTransaction(dbtx, serializedTx,
+			&block.SerializedHeader, &blockMeta, "blockconnected")
 		if err != nil {
 			return err
 		}
 	}
+	err = w.notifyLiveTickets(dbtx, blockMeta.Block.Height)
+	if err != nil {
+		return err
+	}
 	w.BlockConnectEnd(&blockMeta)
 	return nil
 }
 
+// notifyLiveTickets notifies clients of the wallet tickets which mature and
+// become live in the main chain block at height.
+func (w *Wallet) notifyLiveTickets(dbtx walletdb.ReadTx, height int32) error {
+	ticketHeight := height - int32(w.chainParams.TicketMaturity)
+	if ticketHeight < 0 {
+		return nil
+	}
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+	return w.TxStore.RangeTransactions(txmgrNs, ticketHeight, ticketHeight,
+		func(details []udb.TxDetails) (bool, error) {
+			for i := range details {
+				if details[i].TxType != stake.TxTypeSStx ||
+					!w.TxStore.OwnTicket(dbtx, &details[i].Hash) {
+					continue
+				}
+				w.NtfnServer.notifyTicket(&TicketNotification{
+					Ticket:      details[i].Hash,
+					Event:       TicketEventLive,
+					BlockHeight: height,
+				})
+			}
+			return false, nil
+		})
+}
+
 // BlockConnectEnd used to clear some expire data after block connected
 func (w *Wallet) BlockConnectEnd(blockMeta *udb.BlockMeta) {
 	req := omnilib.Request{
 		Method: "omni_onblockconnected",
 		Params: []interface{}{blockMeta.Block.Height, blockMeta.Block.Hash.String(), blockMeta.Time.Unix()},
 	}
-	bytes, err := json.Marshal(req)
-	if err == nil {
-		omnilib.JsonCmdReqHcToOm(string(bytes))
+	_, err := omnilib.Send(&req)
+	if err != nil {
+		log.Errorf("Omni engine failed to process connected block %v: %v",
+			&blockMeta.Block.Hash, err)
 	}
 }
 
@@ -217,7 +264,7 @@ func (w *Wallet) switchToSideChain(dbtx walletdb.ReadWriteTx) (*MainTipChangedNo
 
 	// Remove blocks on the current main chain that are at or above the
 	// height of the block that begins the side chain.
-	err := w.RollBack(dbtx, sideChainForkHeight, hashs)
+	err := w.RollBack(dbtx, sideChainForkHeight, hashs, "blockconnected")
 	if err != nil {
 		return nil, err
 	}
@@ -259,14 +306,35 @@ func (w *Wallet) switchToSideChain(dbtx walletdb.ReadWriteTx) (*MainTipChangedNo
 	return chainTipChanges, nil
 }
 
-func (w *Wallet) RollBack(dbtx walletdb.ReadWriteTx, sideChainForkHeight int32, hashs []chainhash.Hash) error {
+func (w *Wallet) RollBack(dbtx walletdb.ReadWriteTx, sideChainForkHeight int32, hashs []chainhash.Hash, trigger string) error {
 	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
 	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+	stakemgrNs := dbtx.ReadWriteBucket(wstakemgrNamespaceKey)
 
 	err := w.TxStore.Rollback(txmgrNs, addrmgrNs, sideChainForkHeight)
 	if err != nil {
 		return err
 	}
+	err = w.auditRollback(txmgrNs, sideChainForkHeight, trigger)
+	if err != nil {
+		return err
+	}
+	err = w.TxStore.RollbackBalanceSnapshots(txmgrNs, sideChainForkHeight)
+	if err != nil {
+		return err
+	}
+	err = w.TxStore.RollbackWebhookHeight(txmgrNs, sideChainForkHeight)
+	if err != nil {
+		return err
+	}
+	err = w.TxStore.RollbackSyncCheckpoint(dbtx, sideChainForkHeight)
+	if err != nil {
+		return err
+	}
+	err = w.StakeMgr.RollbackStakeRewards(stakemgrNs, sideChainForkHeight)
+	if err != nil {
+		return err
+	}
 	if w.EnableOmni() {
 		err = w.RollBackOminiTransaction(uint32(sideChainForkHeight), hashs)
 		if err != nil {
@@ -275,6 +343,16 @@ func (w *Wallet) RollBack(dbtx walletdb.ReadWriteTx, sideChainForkHeight int32,
 	}
 	return nil
 }
+
+// chainFault logs a chainfault event when err describes chain data from the
+// consensus RPC server which failed validation, and returns err.
+func chainFault(source string, err error) error {
+	if apperrors.IsError(err, apperrors.ErrChainFault) {
+		log.Errorf("chainfault: invalid chain data from %s: %v", source, err)
+	}
+	return err
+}
+
 func copyHeaderSliceToArray(array *udb.RawBlockHeader, slice []byte) error {
 	if len(array) != len(udb.RawBlockHeader{}) {
 		return errors.New("block header has unexpected size")
@@ -297,6 +375,22 @@ func (w *Wallet) onBlockConnected(serializedBlockHeader []byte, transactions [][
 	if err != nil {
 		return err
 	}
+	err = w.TxStore.CheckProofOfWork(&block)
+	if err != nil {
+		return chainFault("blockconnected notification", err)
+	}
+	if chainClient := w.ChainClient(); chainClient != nil {
+		w.healthProbes.blockConnected(chainClient, int64(blockHeader.Height))
+	}
+
+	// Transactions which are not proven to be included in the block are
+	// only recorded as unmined when mined transactions are verified.
+	var unverified [][]byte
+	if w.VerifyCredits() {
+		transactions, unverified = w.verifyMinedTransactions(
+			&block.BlockHash, transactions)
+		defer w.processUnverifiedTransactions(&block.BlockHash, unverified)
+	}
 
 	var chainTipChanges *MainTipChangedNotification
 
@@ -304,7 +398,7 @@ func (w *Wallet) onBlockConnected(serializedBlockHeader []byte, transactions [][
 	reorg, reorgToHash := w.reorganizing, w.reorganizeToHash
 	w.reorganizingLock.Unlock()
 
-	w.NtfnServerMutex.Lock()
+	w.blockConnectMu.Lock()
 	if reorg {
 		// add to side chain
 		scBlock := sideChainBlock{
@@ -316,20 +410,46 @@ func (w *Wallet) onBlockConnected(serializedBlockHeader []byte, transactions [][
 			block.BlockHash, block.SerializedHeader.Height())
 
 		if block.BlockHash != reorgToHash {
-			// Nothing left to do until the later blocks are
-			// received.
-			w.NtfnServerMutex.Unlock()
+			// Record the block, and the fork point with the first
+			// side chain block, so an interrupted chain switch can
+			// be resumed after a restart.  Nothing is left to do
+			// until the later blocks are received.
+			err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+				txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+				if len(w.sideChain) == 1 {
+					err := w.TxStore.PutPendingReorg(txmgrNs, &udb.PendingReorg{
+						Target:     reorgToHash,
+						ForkHeight: block.SerializedHeader.Height(),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return w.TxStore.PutPendingReorgBlock(txmgrNs, &udb.PendingReorgBlock{
+					Header:       block,
+					Transactions: transactions,
+				})
+			})
+			w.blockConnectMu.Unlock()
+			if err != nil {
+				return fmt.Errorf("failed to record side chain block %v: %v",
+					&block.BlockHash, err)
+			}
 			return nil
 		}
 
 		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
 			var err error
 			chainTipChanges, err = w.switchToSideChain(dbtx)
-			return err
+			if err != nil {
+				return err
+			}
+			txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+			return w.TxStore.RemovePendingReorg(txmgrNs)
 		})
 		if err != nil {
-			w.NtfnServerMutex.Unlock()
-			return err
+			w.blockConnectMu.Unlock()
+			return chainFault("blockconnected notification", err)
 		}
 
 		w.sideChain = nil
@@ -342,8 +462,8 @@ func (w *Wallet) onBlockConnected(serializedBlockHeader []byte, transactions [][
 			return w.extendMainChain(dbtx, &block, transactions)
 		})
 		if err != nil {
-			w.NtfnServerMutex.Unlock()
-			return err
+			w.blockConnectMu.Unlock()
+			return chainFault("blockconnected notification", err)
 		}
 		chainTipChanges = &MainTipChangedNotification{
 			AttachedBlocks: []*chainhash.Hash{&block.BlockHash},
@@ -360,7 +480,19 @@ func (w *Wallet) onBlockConnected(serializedBlockHeader []byte, transactions [][
 	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
 		//	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
 		//	return w.TxStore.PruneUnconfirmed(txmgrNs, height, blockHeader.SBits)
-		return w.TxStore.PruneUnmined(dbtx, blockHeader.SBits)
+		removed, err := w.TxStore.PruneUnmined(dbtx, blockHeader.SBits)
+		if err != nil {
+			return err
+		}
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		for _, hash := range removed {
+			err := w.auditRemoval(txmgrNs, hash, udb.AuditPrune, -1,
+				"blockconnected")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		log.Errorf("Failed to prune unconfirmed transactions when "+
@@ -369,15 +501,72 @@ func (w *Wallet) onBlockConnected(serializedBlockHeader []byte, transactions [][
 
 	w.NtfnServer.notifyMainChainTipChanged(chainTipChanges)
 	w.NtfnServer.sendAttachedBlockNotification()
-	w.NtfnServerMutex.Unlock()
-	if voteVersion(w.chainParams) < blockHeader.StakeVersion {
-		log.Warnf("Old vote version detected (v%v), please update your "+
-			"wallet to the latest version.", voteVersion(w.chainParams))
-	}
+	w.blockConnectMu.Unlock()
+
+	w.queuePostBlockTasks(height)
+
+	w.recordStakeVersion(height, blockHeader.StakeVersion)
 
 	return nil
 }
 
+// queuePostBlockTasks queues the maintenance of a connected block for
+// postBlockWorker, so that the notification handler does not wait on database
+// updates and omni engine requests before processing the next block.
+func (w *Wallet) queuePostBlockTasks(height int32) {
+	w.postBlockMu.Lock()
+	w.postBlockHeights = append(w.postBlockHeights, height)
+	w.postBlockMu.Unlock()
+
+	select {
+	case w.postBlockWake <- struct{}{}:
+	default:
+	}
+}
+
+// postBlockWorker runs the maintenance of connected blocks in the order the
+// blocks were connected.  Tasks which only act on the current state of the
+// wallet are run once for every batch of blocks connected while the previous
+// batch was processed.  It must be run as a goroutine.
+func (w *Wallet) postBlockWorker() {
+	defer w.wg.Done()
+
+	quit := w.quitChan()
+	for {
+		select {
+		case <-w.postBlockWake:
+		case <-quit:
+			return
+		}
+
+		w.postBlockMu.Lock()
+		heights := w.postBlockHeights
+		w.postBlockHeights = nil
+		w.postBlockMu.Unlock()
+		if len(heights) == 0 {
+			continue
+		}
+
+		for _, height := range heights {
+			w.autoSnapshotBalances(height)
+			w.autoPruneHistory(height)
+		}
+		height := heights[len(heights)-1]
+
+		// Remove the pending omni entries of mined transactions and
+		// reverse those of transactions pruned from the unmined set or
+		// expired.
+		if w.EnableOmni() {
+			w.pruneOmniPending()
+		}
+
+		w.queueWebhooks(height)
+		w.autoRefundScripts()
+		w.autoConsolidateDust()
+		w.autoDenominateTicketFunds(height)
+	}
+}
+
 // handleReorganizing handles a blockchain reorganization notification. It
 // sets the chain server to indicate that currently the wallet state is in
 // reorganizing, and what the final block of the reorganization is by hash.
@@ -403,15 +592,208 @@ func (w *Wallet) handleReorganizing(oldHash, newHash *chainhash.Hash, oldHeight,
 	log.Infof("Old top block height: %v", oldHeight)
 	log.Infof("New top block hash: %v", newHash)
 	log.Infof("New top block height: %v", newHeight)
+
+	// Record the fork point so an interrupted chain switch can be resumed
+	// after a restart, even if no side chain block is received.  The fork
+	// height is left unknown when it can not be found, and is found again
+	// when the pending reorg is resolved.
+	forkHeight := int32(-1)
+	chainClient, err := w.requireChainClient()
+	if err == nil {
+		forkHeight, err = w.reorgForkHeight(chainClient, int32(newHeight))
+	}
+	if err != nil {
+		log.Warnf("Unable to find the fork point of the reorganization to "+
+			"block %v: %v", newHash, err)
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.PutPendingReorg(txmgrNs, &udb.PendingReorg{
+			Target:     *newHash,
+			ForkHeight: forkHeight,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record reorganization to block %v: %v",
+			newHash, err)
+	}
 	return nil
 }
 
+// reorgForkHeight returns the height of the first block of the consensus
+// server's main chain which is not in the wallet's main chain, searching down
+// from the lower of the wallet's tip height and maxHeight.
+func (w *Wallet) reorgForkHeight(chainClient ChainClient, maxHeight int32) (int32, error) {
+	var tipHeight int32
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight = w.TxStore.MainChainTip(txmgrNs)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	height := tipHeight
+	if maxHeight < height {
+		height = maxHeight
+	}
+	for ; height >= 0; height-- {
+		serverHash, err := chainClient.GetBlockHash(int64(height))
+		if err != nil {
+			return 0, err
+		}
+		var walletHash chainhash.Hash
+		err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+			var err error
+			walletHash, err = w.TxStore.GetMainChainBlockHashForHeight(txmgrNs, height)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		if walletHash == *serverHash {
+			return height + 1, nil
+		}
+	}
+	return 0, errors.New("no common block with the consensus server")
+}
+
+// resolvePendingReorg resolves a chain switch which was in progress when the
+// wallet was last synced.  The wallet switches to the side chain blocks which
+// were received and recorded before the switch was interrupted, and the rest
+// of the side chain is synced from the consensus server.  When none of the
+// recorded blocks connect to the main chain at the fork point, blocks of the
+// main chain at and above the fork point are removed so that the side chain,
+// which may be shorter than the removed blocks, is resynced along with its
+// transactions.  An unknown fork point is found from the consensus server.
+// This must be called before syncing with the consensus server.
+func (w *Wallet) resolvePendingReorg(chainClient ChainClient) error {
+	w.reorganizingLock.Lock()
+	w.reorganizing = false
+	w.reorganizeToHash = chainhash.Hash{}
+	w.reorganizingLock.Unlock()
+	w.blockConnectMu.Lock()
+	defer w.blockConnectMu.Unlock()
+	w.sideChain = nil
+
+	var r *udb.PendingReorg
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		r, err = w.TxStore.PendingReorg(txmgrNs)
+		return err
+	})
+	if err != nil || r == nil {
+		return err
+	}
+	log.Infof("Resolving interrupted reorganization to block %v", &r.Target)
+	if r.ForkHeight == -1 {
+		r.ForkHeight, err = w.reorgForkHeight(chainClient, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+	}
+
+	var chainTipChanges *MainTipChangedNotification
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		blocks, err := w.TxStore.PendingReorgBlocks(txmgrNs)
+		if err != nil {
+			return err
+		}
+		sideChain, err := w.connectingSideChain(txmgrNs, r.ForkHeight, blocks)
+		if err != nil {
+			return err
+		}
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		switch {
+		case len(sideChain) != 0:
+			log.Infof("Switching to %d received blocks of the side chain",
+				len(sideChain))
+			w.sideChain = sideChain
+			chainTipChanges, err = w.switchToSideChain(dbtx)
+			w.sideChain = nil
+			if err != nil {
+				return err
+			}
+			chainTipChanges.NewHeight = sideChain[len(sideChain)-1].headerData.SerializedHeader.Height()
+		case r.ForkHeight > 0 && r.ForkHeight <= tipHeight:
+			var hashes []chainhash.Hash
+			for i := tipHeight; i >= r.ForkHeight; i-- {
+				hash, err := w.TxStore.GetMainChainBlockHashForHeight(txmgrNs, i)
+				if err != nil {
+					return err
+				}
+				hashes = append(hashes, hash)
+			}
+			err = w.RollBack(dbtx, r.ForkHeight, hashes, "sync")
+			if err != nil {
+				return err
+			}
+		}
+		return w.TxStore.RemovePendingReorg(txmgrNs)
+	})
+	if err != nil {
+		return err
+	}
+	if chainTipChanges != nil {
+		w.NtfnServer.notifyMainChainTipChanged(chainTipChanges)
+	}
+	return nil
+}
+
+// connectingSideChain returns the leading recorded side chain blocks which
+// begin at the fork height, connecting to the main chain block below it, and
+// connect to each other.
+func (w *Wallet) connectingSideChain(txmgrNs walletdb.ReadBucket, forkHeight int32,
+	blocks []*udb.PendingReorgBlock) ([]sideChainBlock, error) {
+
+	_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+	if forkHeight <= 0 || forkHeight > tipHeight+1 {
+		return nil, nil
+	}
+	prev, err := w.TxStore.GetMainChainBlockHashForHeight(txmgrNs, forkHeight-1)
+	if err != nil {
+		return nil, err
+	}
+	var sideChain []sideChainBlock
+	for _, b := range blocks {
+		var header wire.BlockHeader
+		err := header.Deserialize(bytes.NewReader(b.Header.SerializedHeader[:]))
+		if err != nil {
+			return nil, err
+		}
+		height := forkHeight + int32(len(sideChain))
+		if header.PrevBlock != prev || int32(header.Height) != height {
+			break
+		}
+		sideChain = append(sideChain, sideChainBlock{
+			transactions: b.Transactions,
+			headerData:   b.Header,
+		})
+		prev = b.Header.BlockHash
+	}
+	return sideChain, nil
+}
+
 // evaluateStakePoolTicket evaluates a stake pool ticket to see if it's
 // acceptable to the stake pool. The ticket must pay out to the stake
-// pool cold wallet, and must have a sufficient fee.
-func (w *Wallet) evaluateStakePoolTicket(rec *udb.TxRecord,
-	blockHeight int32, poolUser hcutil.Address) (bool, error) {
+// pool cold wallet, and must have a sufficient fee unless the pool user is
+// exempt from the fee check.  A description of why the ticket was rejected is
+// returned for unacceptable tickets, or nil if the ticket is acceptable.
+func (w *Wallet) evaluateStakePoolTicket(stakemgrNs walletdb.ReadBucket, rec *udb.TxRecord,
+	blockHeight int32, poolUser hcutil.Address) *udb.TicketRejection {
 	tx := rec.MsgTx
+	rejection := &udb.TicketRejection{
+		Ticket: rec.Hash,
+		Height: uint32(blockHeight),
+	}
+	parseFailure := func(err error) *udb.TicketRejection {
+		rejection.Reason = udb.TicketRejectedParseFailure
+		rejection.Detail = err.Error()
+		return rejection
+	}
 
 	// Check the first commitment output (txOuts[1])
 	// and ensure that the address found there exists
@@ -422,8 +804,8 @@ func (w *Wallet) evaluateStakePoolTicket(rec *udb.TxRecord,
 	commitAddr, err := stake.AddrFromSStxPkScrCommitment(
 		commitmentOut.PkScript, w.chainParams)
 	if err != nil {
-		return false, fmt.Errorf("Failed to parse commit out addr: %s",
-			err.Error())
+		return parseFailure(fmt.Errorf("Failed to parse commit out addr: %s",
+			err.Error()))
 	}
 
 	// Extract the fee from the ticket.
@@ -433,8 +815,8 @@ func (w *Wallet) evaluateStakePoolTicket(rec *udb.TxRecord,
 			commitAmt, err := stake.AmountFromSStxPkScrCommitment(
 				tx.TxOut[i].PkScript)
 			if err != nil {
-				return false, fmt.Errorf("Failed to parse commit "+
-					"out amt for commit in vout %v: %s", i, err.Error())
+				return parseFailure(fmt.Errorf("Failed to parse commit "+
+					"out amt for commit in vout %v: %s", i, err.Error()))
 			}
 			in += commitAmt
 		}
@@ -450,8 +832,8 @@ func (w *Wallet) evaluateStakePoolTicket(rec *udb.TxRecord,
 		commitAmt, err := stake.AmountFromSStxPkScrCommitment(
 			commitmentOut.PkScript)
 		if err != nil {
-			return false, fmt.Errorf("failed to parse commit "+
-				"out amt: %s", err.Error())
+			return parseFailure(fmt.Errorf("failed to parse commit "+
+				"out amt: %s", err.Error()))
 		}
 
 		// Calculate the fee required based on the current
@@ -459,7 +841,17 @@ func (w *Wallet) evaluateStakePoolTicket(rec *udb.TxRecord,
 		feeNeeded := txrules.StakePoolTicketFee(hcutil.Amount(
 			tx.TxOut[0].Value), fees, blockHeight, w.PoolFees(),
 			w.ChainParams())
-		if commitAmt < feeNeeded {
+		exempt, err := w.StakeMgr.StakePoolFeeExempt(stakemgrNs, poolUser)
+		if err != nil {
+			log.Warnf("Failed to check fee exemption of pool user %v: %v",
+				poolUser.EncodeAddress(), err)
+		}
+		if commitAmt < feeNeeded && exempt {
+			log.Infof("Accepting ticket %v of fee exempt user %v which "+
+				"commits less than the required pool fee (required: %v, "+
+				"found %v)", tx.TxHash(), poolUser.EncodeAddress(),
+				feeNeeded, commitAmt)
+		} else if commitAmt < feeNeeded {
 			log.Warnf("User %s submitted ticket %v which "+
 				"has less fees than are required to use this "+
 				"stake pool and is being skipped (required: %v"+
@@ -468,21 +860,26 @@ func (w *Wallet) evaluateStakePoolTicket(rec *udb.TxRecord,
 
 			// Reject the entire transaction if it didn't
 			// pay the pool server fees.
-			return false, nil
+			rejection.Reason = udb.TicketRejectedFeeTooLow
+			rejection.Found = commitAmt
+			rejection.Required = feeNeeded
+			return rejection
 		}
 	} else {
 		log.Warnf("Unknown pool commitment address %s for ticket %v",
 			commitAddr.EncodeAddress(), tx.TxHash())
-		return false, nil
+		rejection.Reason = udb.TicketRejectedUnknownCommitment
+		rejection.Detail = commitAddr.EncodeAddress()
+		return rejection
 	}
 
 	log.Debugf("Accepted valid stake pool ticket %v committing %v in fees",
 		tx.TxHash(), tx.TxOut[0].Value)
 
-	return true, nil
+	return nil
 }
 
-func (w *Wallet) processSerializedTransaction(dbtx walletdb.ReadWriteTx, serializedTx []byte, serializedHeader *udb.RawBlockHeader, blockMeta *udb.BlockMeta) error {
+func (w *Wallet) processSerializedTransaction(dbtx walletdb.ReadWriteTx, serializedTx []byte, serializedHeader *udb.RawBlockHeader, blockMeta *udb.BlockMeta, trigger string) error {
 	rec, err := udb.NewTxRecord(serializedTx, time.Now())
 	if err != nil {
 		return err
@@ -493,7 +890,7 @@ func (w *Wallet) processSerializedTransaction(dbtx walletdb.ReadWriteTx, seriali
 			fmt.Println(tempOut)
 		}
 	}
-	return w.processTransactionRecord(dbtx, rec, serializedHeader, blockMeta)
+	return w.processTransactionRecord(dbtx, rec, serializedHeader, blockMeta, trigger)
 }
 
 func getPayLoadData(pkScript []byte) (bool, []byte) {
@@ -534,23 +931,8 @@ func (w *Wallet) RollBackOminiTransaction(height uint32, hashs []chainhash.Hash)
 		Hashs:  &strHashs,
 	}
 
-	byteCmd, err := hcjson.MarshalCmd(1, &cmd)
-	if err != nil {
-		return err
-	}
-
-	strRsp := omnilib.JsonCmdReqHcToOm(string(byteCmd))
-
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
-	if err != nil {
-		return err
-	}
-	if response.Error != nil {
-		return response.Error
-	} else {
-		return nil
-	}
+	_, err := omnilib.SendCmd(&cmd)
+	return err
 }
 
 func (w *Wallet) OmniClear() error {
@@ -558,13 +940,9 @@ func (w *Wallet) OmniClear() error {
 	if err != nil {
 		return err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, cmd)
-	if err != nil {
-		return err
-	}
 	//construct omni variables
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON))
-	return nil
+	_, err = omnilib.SendCmd(cmd)
+	return err
 }
 
 func (w *Wallet) ProcessOminiTransaction(rec *udb.TxRecord, blockMeta *udb.BlockMeta) error {
@@ -668,13 +1046,19 @@ func (w *Wallet) ProcessOminiTransaction(rec *udb.TxRecord, blockMeta *udb.Block
 			if err != nil {
 				return err
 			}
-			marshalledJSON, err := hcjson.MarshalCmd(1, cmd)
+			//construct omni variables
+			err = omniSendTxCmd(cmd, &rec.Hash)
 			if err != nil {
 				return err
 			}
-			//construct omni variables
-			omnilib.JsonCmdReqHcToOm(string(marshalledJSON))
 		}
+		w.NtfnServer.notifyOmniTransaction(&OmniTransactionNotification{
+			Hash:        rec.Hash,
+			Sender:      sendor,
+			Receiver:    toAddress,
+			BlockHash:   blockMeta.Hash,
+			BlockHeight: blockMeta.Height,
+		})
 	}
 	return nil
 }
@@ -697,7 +1081,59 @@ func getFee(w *Wallet, rec *udb.TxRecord) (int64, error) {
 	fee := amountIn - amountOut
 	return fee, nil
 }
-func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.TxRecord, serializedHeader *udb.RawBlockHeader, blockMeta *udb.BlockMeta) error {
+
+// recordTicketAccount records the account which funded a ticket purchase, as
+// the account of the first wallet output it spends.  Tickets which spend no
+// wallet outputs are not recorded.
+func (w *Wallet) recordTicketAccount(addrmgrNs walletdb.ReadBucket, stakemgrNs walletdb.ReadWriteBucket,
+	txmgrNs walletdb.ReadBucket, rec *udb.TxRecord, blockMeta *udb.BlockMeta) error {
+
+	var block *udb.Block
+	if blockMeta != nil {
+		block = &blockMeta.Block
+	}
+	pkScripts, err := w.TxStore.PreviousPkScripts(txmgrNs, rec, block)
+	if err != nil {
+		return err
+	}
+	for _, pkScript := range pkScripts {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txscript.DefaultScriptVersion, pkScript, w.chainParams)
+		if err != nil || len(addrs) == 0 {
+			continue
+		}
+		account, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
+		if err != nil {
+			continue
+		}
+		return w.StakeMgr.PutTicketAccount(stakemgrNs, &rec.Hash, account)
+	}
+	return nil
+}
+
+// minedTicketHeight returns the height of the block mining a ticket recorded
+// by the transaction store, or -1 if the ticket is not recorded or not mined.
+func (w *Wallet) minedTicketHeight(dbtx walletdb.ReadTx, ticketHash *chainhash.Hash) (int32, error) {
+	height, err := w.TxStore.TxBlockHeight(dbtx, ticketHash)
+	if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		return -1, nil
+	}
+	return height, err
+}
+
+// processTransactionRecord records a relevant transaction, appending entries
+// for its credits and debits to the audit log.  The trigger names the
+// notification or wallet operation which the transaction is processed for.
+func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.TxRecord, serializedHeader *udb.RawBlockHeader, blockMeta *udb.BlockMeta, trigger string) error {
+	err := w.recordTransaction(dbtx, rec, serializedHeader, blockMeta)
+	if err != nil {
+		return err
+	}
+	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+	return w.auditTransaction(txmgrNs, &rec.Hash, trigger)
+}
+
+func (w *Wallet) recordTransaction(dbtx walletdb.ReadWriteTx, rec *udb.TxRecord, serializedHeader *udb.RawBlockHeader, blockMeta *udb.BlockMeta) error {
 
 	addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
 	stakemgrNs := dbtx.ReadWriteBucket(wstakemgrNamespaceKey)
@@ -713,6 +1149,10 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 		if err != nil {
 			return err
 		}
+		err = w.omniPendingMined(txmgrNs, &rec.Hash)
+		if err != nil {
+			return err
+		}
 	}
 
 	isMineTx, err := w.IsReleventTransaction(dbtx, rec, blockMeta)
@@ -740,6 +1180,13 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 		}
 	}
 
+	// Record secrets revealed by redemptions of the wallet's atomic swap
+	// contracts.
+	err = w.recordSwapSecrets(addrmgrNs, &rec.MsgTx)
+	if err != nil {
+		return err
+	}
+
 	// Handle incoming SStx; store them in the stake manager if we own
 	// the OP_SSTX tagged out, except if we're operating as a stake pool
 	// server. In that case, additionally consider the first commitment
@@ -771,9 +1218,9 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 				break
 			}
 
-			valid, errEval := w.evaluateStakePoolTicket(rec, height,
-				addr)
-			if valid {
+			rejection := w.evaluateStakePoolTicket(stakemgrNs, rec,
+				height, addr)
+			if rejection == nil {
 				// Be sure to insert this into the user's stake
 				// pool entry into the stake manager.
 				poolTicket := &udb.PoolTicket{
@@ -794,15 +1241,15 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 				break
 			}
 
-			// Log errors if there were any. At this point the ticket
-			// must be invalid, so insert it into the list of invalid
-			// user tickets.
-			if errEval != nil {
+			// Log parse errors if there were any. At this point the
+			// ticket must be invalid, so insert it into the list of
+			// invalid user tickets along with why it was rejected.
+			if rejection.Reason == udb.TicketRejectedParseFailure {
 				log.Warnf("Ticket %v failed ticket evaluation for "+
-					"the stake pool: %s", &rec.Hash, errEval)
+					"the stake pool: %s", &rec.Hash, rejection.Detail)
 			}
 			err := w.StakeMgr.UpdateStakePoolUserInvalTickets(
-				stakemgrNs, addr, &rec.Hash)
+				stakemgrNs, addr, rejection)
 			if err != nil {
 				log.Warnf("Failed to update pool user %v with "+
 					"invalid ticket %v", addr.EncodeAddress(),
@@ -817,6 +1264,20 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 					"into the stake store.", &rec.Hash)
 			}
 		}
+
+		err = w.recordTicketAccount(addrmgrNs, stakemgrNs, txmgrNs, rec,
+			blockMeta)
+		if err != nil {
+			return err
+		}
+
+		if w.TxStore.OwnTicket(dbtx, &rec.Hash) {
+			w.NtfnServer.notifyTicket(&TicketNotification{
+				Ticket:      rec.Hash,
+				Event:       TicketEventPurchased,
+				BlockHeight: height,
+			})
+		}
 	}
 
 	// Handle incoming votes.  Save a stake manager record for them if we own
@@ -830,6 +1291,21 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 			if err != nil {
 				return err
 			}
+			ticketHeight, err := w.minedTicketHeight(dbtx, ticketHash)
+			if err != nil {
+				return err
+			}
+			err = w.StakeMgr.RecordStakeReward(stakemgrNs, rec, blockMeta,
+				ticketHeight)
+			if err != nil {
+				return err
+			}
+			w.NtfnServer.notifyTicket(&TicketNotification{
+				Ticket:      *ticketHash,
+				Event:       TicketEventVoted,
+				SpenderHash: &rec.Hash,
+				BlockHeight: height,
+			})
 		}
 
 		// If we're running as a stake pool, insert
@@ -875,6 +1351,21 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 			if err != nil {
 				return err
 			}
+			ticketHeight, err := w.minedTicketHeight(dbtx, txInHash)
+			if err != nil {
+				return err
+			}
+			err = w.StakeMgr.RecordStakeReward(stakemgrNs, rec, blockMeta,
+				ticketHeight)
+			if err != nil {
+				return err
+			}
+			w.NtfnServer.notifyTicket(&TicketNotification{
+				Ticket:      *txInHash,
+				Event:       TicketEventRevoked,
+				SpenderHash: &rec.Hash,
+				BlockHeight: height,
+			})
 		}
 
 		// If we're running as a stake pool, insert
@@ -949,7 +1440,7 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 			// Add the script to the script databases.
 			// TODO Markused script address? cj
 			if isRelevant {
-				err = w.TxStore.InsertTxScript(txmgrNs, rs)
+				err = w.insertTxScript(txmgrNs, rs)
 				if err != nil {
 					return err
 				}
@@ -1027,6 +1518,10 @@ func (w *Wallet) processTransactionRecord(dbtx walletdb.ReadWriteTx, rec *udb.Tx
 		for _, addr := range addrs {
 			ma, err := w.Manager.Address(addrmgrNs, addr)
 			if err == nil {
+				err = w.warnAddressReuse(dbtx, addr, &rec.Hash)
+				if err != nil {
+					return err
+				}
 				err = w.TxStore.AddCredit(txmgrNs, rec, blockMeta,
 					uint32(i), ma.Internal(), ma.Account())
 				if err != nil {
@@ -1201,7 +1696,7 @@ func (w *Wallet) IsReleventTransaction(dbtx walletdb.ReadWriteTx, rec *udb.TxRec
 	return false, nil
 }
 
-func (w *Wallet) handleChainVotingNotifications(chainClient *chain.RPCClient) {
+func (w *Wallet) handleChainVotingNotifications(chainClient ConsensusClient) {
 	for n := range chainClient.NotificationsVoting() {
 		var err error
 		strErrType := ""
@@ -1218,6 +1713,7 @@ func (w *Wallet) handleChainVotingNotifications(chainClient *chain.RPCClient) {
 				"notification %v: %v", strErrType, err)
 		}
 	}
+	w.chainNtfnsWg.Done()
 	w.wg.Done()
 }
 
@@ -1249,6 +1745,13 @@ func (w *Wallet) handleWinningTickets(blockHash *chainhash.Hash, blockHeight int
 		return err
 	}
 
+	if status := w.VoteVersionStatus(); status.Outdated && status.SkipStaleVotes {
+		log.Warnf("Not voting on block %v with vote version v%v, as the "+
+			"network uses stake version v%v", blockHash,
+			status.WalletVersion, status.NetworkVersion)
+		return nil
+	}
+
 	// TODO The behavior of this is not quite right if tons of blocks
 	// are coming in quickly, because the transaction store will end up
 	// out of sync with the voting channel here. This should probably
@@ -1316,11 +1819,22 @@ func (w *Wallet) handleWinningTickets(blockHash *chainhash.Hash, blockHeight int
 		log.Errorf("View failed: %v", err)
 	}
 
+	voteDelay := w.VoteDelay()
 	for i, vote := range votes {
 		go func(i int, vote *wire.MsgTx) {
 			if vote == nil {
 				return
 			}
+			if voteDelay > 0 {
+				delay := randomVoteDelay(voteDelay)
+				log.Debugf("Delaying vote for ticket %v by %v",
+					ticketHashes[i], delay)
+				select {
+				case <-time.After(delay):
+				case <-w.quitChan():
+					return
+				}
+			}
 			txRec, err := udb.NewTxRecordFromMsgTx(vote, time.Now())
 			if err != nil {
 				log.Errorf("Failed to create transaction record for vote %v: %v",
@@ -1329,7 +1843,7 @@ func (w *Wallet) handleWinningTickets(blockHash *chainhash.Hash, blockHeight int
 			}
 			voteHash := &txRec.Hash
 			err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-				err := w.processTransactionRecord(dbtx, txRec, nil, nil)
+				err := w.processTransactionRecord(dbtx, txRec, nil, nil, "winningtickets")
 				if err != nil {
 					return err
 				}
@@ -1339,7 +1853,8 @@ func (w *Wallet) handleWinningTickets(blockHash *chainhash.Hash, blockHeight int
 					return err
 				}
 
-				_, err = chainClient.SendRawTransaction(vote, true)
+				txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+				_, err = w.sendRawTransaction(txmgrNs, chainClient, vote, true)
 				return err
 			})
 			if err != nil {
@@ -1350,6 +1865,13 @@ func (w *Wallet) handleWinningTickets(blockHash *chainhash.Hash, blockHeight int
 			log.Infof("Voted on block %v (height %v) using ticket %v "+
 				"(vote hash: %v bits: %v)", blockHash, blockHeight,
 				ticketHashes[i], voteHash, voteBits.Bits)
+			w.NtfnServer.notifyVote(&VoteNotification{
+				Ticket:      *ticketHashes[i],
+				Vote:        *voteHash,
+				BlockHash:   *blockHash,
+				BlockHeight: blockHeight,
+				VoteBits:    voteBits.Bits,
+			})
 		}(i, vote)
 	}
 	return nil
@@ -1380,6 +1902,12 @@ func (w *Wallet) handleMissedTickets(blockHash *chainhash.Hash, blockHeight int3
 		if len(ticketHashes) == 0 {
 			return nil
 		}
+		w.notifyMissedTickets(dbtx, blockHeight, ticketHashes)
+		if !w.AutoRevoke() {
+			log.Debugf("Not revoking %d missed tickets: automatic "+
+				"revocation is disabled", len(ticketHashes))
+			return nil
+		}
 
 		revocations = make([]*wire.MsgTx, len(ticketHashes))
 
@@ -1443,7 +1971,7 @@ func (w *Wallet) handleMissedTickets(blockHash *chainhash.Hash, blockHeight int3
 		}
 		revocationHash := &txRec.Hash
 		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-			err := w.processTransactionRecord(dbtx, txRec, nil, nil)
+			err := w.processTransactionRecord(dbtx, txRec, nil, nil, "missedtickets")
 			if err != nil {
 				return err
 			}
@@ -1452,7 +1980,8 @@ func (w *Wallet) handleMissedTickets(blockHash *chainhash.Hash, blockHeight int3
 			if err != nil {
 				return err
 			}
-			_, err = chainClient.SendRawTransaction(revocation, true)
+			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+			_, err = w.sendRawTransaction(txmgrNs, chainClient, revocation, true)
 			return err
 		})
 		if err != nil {
@@ -1467,6 +1996,29 @@ func (w *Wallet) handleMissedTickets(blockHash *chainhash.Hash, blockHeight int3
 	return nil
 }
 
+// notifyMissedTickets notifies clients of wallet tickets reported missed by the
+// consensus server in the block at blockHeight.  The consensus server does not
+// distinguish expired tickets from missed ones, so tickets which reached their
+// expiry height by this block are reported as expired.
+func (w *Wallet) notifyMissedTickets(dbtx walletdb.ReadTx, blockHeight int32,
+	ticketHashes []*chainhash.Hash) {
+
+	expiry := int32(w.chainParams.TicketMaturity) +
+		int32(w.chainParams.TicketExpiry)
+	for _, ticketHash := range ticketHashes {
+		event := TicketEventMissed
+		ticketHeight, err := w.TxStore.TxBlockHeight(dbtx, ticketHash)
+		if err == nil && ticketHeight >= 0 && blockHeight >= ticketHeight+expiry {
+			event = TicketEventExpired
+		}
+		w.NtfnServer.notifyTicket(&TicketNotification{
+			Ticket:      *ticketHash,
+			Event:       event,
+			BlockHeight: blockHeight,
+		})
+	}
+}
+
 func (w *Wallet) omniTXExodusFundraiser(rec *udb.TxRecord, sendor string, blockMeta *udb.BlockMeta) error {
 	amount := int64(0)
 	for _, out := range rec.MsgTx.TxOut {
@@ -1488,13 +2040,8 @@ func (w *Wallet) omniTXExodusFundraiser(rec *udb.TxRecord, sendor string, blockM
 	if err != nil {
 		return err
 	}
-	marshalledJSON, err := hcjson.MarshalCmd(1, cmd)
-	if err != nil {
-		return err
-	}
 	//construct omni variables
-	omnilib.JsonCmdReqHcToOm(string(marshalledJSON))
-	return nil
+	return omniSendTxCmd(cmd, &rec.Hash)
 }
 
 func (w *Wallet) omniProcessPayment(rec *udb.TxRecord, sendor string, blockMeta *udb.BlockMeta) error {
@@ -1524,16 +2071,28 @@ func (w *Wallet) omniProcessPayment(rec *udb.TxRecord, sendor string, blockMeta
 		if err != nil {
 			return err
 		}
-		marshalledJSON, err := hcjson.MarshalCmd(1, cmd)
+		//construct omni variables
+		err = omniSendTxCmd(cmd, &rec.Hash)
 		if err != nil {
 			return err
 		}
-		//construct omni variables
-		omnilib.JsonCmdReqHcToOm(string(marshalledJSON))
 	}
 	return nil
 }
 
+// omniSendTxCmd hands a transaction processing command to the omni engine.
+// A transaction the engine refuses is logged and otherwise ignored so that it
+// does not stall block processing, but an error is returned when the engine
+// could not be reached at all.
+func omniSendTxCmd(cmd interface{}, txHash *chainhash.Hash) error {
+	_, err := omnilib.SendCmd(cmd)
+	if err != nil && !omnilib.IsBridgeError(err) {
+		log.Warnf("Omni engine rejected transaction %v: %v", txHash, err)
+		return nil
+	}
+	return err
+}
+
 func (w *Wallet) checkValidateOmniTransaction(rec *udb.TxRecord) bool {
 	hasOpreturn := false
 	hasExodusAddress := false
@@ -0,0 +1,79 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestPostBlockWorker(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+	w.SetBalanceSnapshotInterval(1)
+
+	// Tasks queued before the worker runs are not lost.
+	_, tipHeight := w.MainChainTip()
+	w.queuePostBlockTasks(tipHeight)
+
+	w.wg.Add(1)
+	go w.postBlockWorker()
+	defer func() {
+		w.Stop()
+		w.WaitForShutdown()
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		snapshot, err := w.BalanceSnapshotAtHeight(tipHeight)
+		if err == nil && snapshot != nil && snapshot.Height == tipHeight {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no balance snapshot at height %d: %v", tipHeight, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	w.postBlockMu.Lock()
+	queued := len(w.postBlockHeights)
+	w.postBlockMu.Unlock()
+	if queued != 0 {
+		t.Errorf("%d heights remain queued", queued)
+	}
+}
+
+func TestRecordUnminedTicketAccount(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	walletPkScript(t, w, udb.ExternalBranch, 0)
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket := addTestTicket(t, w, 1, addrs[0], addrs[0])
+	hash := ticket.TxHash()
+
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		stakemgrNs := dbtx.ReadBucket(wstakemgrNamespaceKey)
+		account, ok, err := w.StakeMgr.TicketAccount(stakemgrNs, &hash)
+		if err != nil {
+			return err
+		}
+		if !ok || account != udb.DefaultAccountNum {
+			t.Errorf("recorded funding account (%d, %v), want (%d, true)",
+				account, ok, udb.DefaultAccountNum)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
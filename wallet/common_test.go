@@ -0,0 +1,60 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/walletdb"
+	_ "github.com/HcashOrg/hcwallet/walletdb/bdb"
+)
+
+var (
+	testPubPass  = []byte(InsecurePubPassphrase)
+	testPrivPass = []byte("private")
+	testSeed     = []byte{
+		0xb4, 0x6b, 0xc6, 0x50, 0x2a, 0x30, 0xbe, 0xb9, 0x2f, 0x0a,
+		0xeb, 0xc7, 0x76, 0x40, 0x3c, 0x3d, 0xbf, 0x11, 0xbf, 0xb6,
+		0x83, 0x05, 0x96, 0x7c, 0x36, 0xda, 0xc9, 0xef, 0x8d, 0x64,
+		0x15, 0x67,
+	}
+	testParams = &chaincfg.SimNetParams
+)
+
+// testWallet creates and opens a simnet wallet from testSeed in a temporary
+// directory.  The returned function closes the wallet and removes its files.
+func testWallet(t *testing.T) (*Wallet, func()) {
+	dir, err := ioutil.TempDir("", "wallettest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := walletdb.Create("bdb", filepath.Join(dir, "wallet.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	teardown := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+	err = Create(db, testPubPass, testPrivPass, testSeed, testParams)
+	if err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+	w, err := Open(db, testPubPass, testPrivPass, false, false, nil, nil, nil,
+		0, 0.01, 20, "", false, txauthor.TxLimits{}, false, 0.001, false,
+		testParams)
+	if err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+	return w, teardown
+}
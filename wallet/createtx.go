@@ -23,8 +23,8 @@ import (
 	"github.com/HcashOrg/hcd/mempool"
 	"github.com/HcashOrg/hcd/txscript"
 	"github.com/HcashOrg/hcd/wire"
-	"github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/apperrors"
+	h "github.com/HcashOrg/hcwallet/internal/helpers"
 	"github.com/HcashOrg/hcwallet/wallet/internal/txsizes"
 	"github.com/HcashOrg/hcwallet/wallet/txauthor"
 	"github.com/HcashOrg/hcwallet/wallet/txrules"
@@ -176,6 +176,11 @@ var ErrSStxPriceExceedsSpendLimit = errors.New("ticket price exceeds spend limit
 // to compress.
 var ErrNoOutsToConsolidate = errors.New("no outputs to consolidate")
 
+// ErrConsolidationDust indicates that the total value of the outputs to
+// consolidate does not exceed the fee of the consolidation by more than the
+// dust limit.
+var ErrConsolidationDust = errors.New("consolidated value would be dust after fees")
+
 // ErrBlockchainReorganizing indicates that the blockchain is currently
 // reorganizing.
 var ErrBlockchainReorganizing = errors.New("blockchain is currently " +
@@ -285,7 +290,8 @@ func (w *Wallet) NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb hcu
 
 		var err error
 		authoredTx, err = txauthor.NewUnsignedTransaction(outputs, relayFeePerKb,
-			inputSource, changeSource, udb.AcctypeEc, w.chainParams, getScript, "")
+			inputSource, changeSource, udb.AcctypeEc, w.chainParams, getScript, "",
+			w.txLimits)
 		return err
 	})
 	if err != nil {
@@ -305,6 +311,82 @@ func (w *Wallet) NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb hcu
 	return authoredTx, err
 }
 
+// EstimateOutputs creates, but does not sign or publish, the transaction which
+// SendOutputs would create to pay outputs from an account, so that its inputs
+// and fee may be reported before the transaction is sent.  Change is assumed
+// to be paid to changeAddr, or to the default address of the account when
+// changeAddr is empty, rather than deriving a new change address.
+func (w *Wallet) EstimateOutputs(outputs []*wire.TxOut, account uint32, minconf int32,
+	changeAddr string, fromAddress string) (*txauthor.AuthoredTx, error) {
+
+	var change hcutil.Address
+	var err error
+	if changeAddr != "" {
+		change, err = hcutil.DecodeAddress(changeAddr)
+	} else {
+		change, err = w.AccountDefaultAddress(account)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, err
+	}
+
+	var doneFuncs []func()
+	defer func() {
+		for _, f := range doneFuncs {
+			f()
+		}
+	}()
+
+	var authoredTx *txauthor.AuthoredTx
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+		accprop, err := w.Manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		inputSource := w.TxStore.MakeInputSource(txmgrNs, addrmgrNs, account,
+			minconf, tipHeight)
+		changeSource := w.changeSource(nil, account, change)
+
+		getScript := txscript.ScriptClosure(func(addr hcutil.Address) ([]byte, error) {
+			// First check tx manager script store.
+			scrTxStore, err := w.TxStore.GetTxScript(txmgrNs, addr.ScriptAddress())
+			if err != nil {
+				return nil, err
+			}
+			if scrTxStore != nil {
+				return scrTxStore, nil
+			}
+
+			// Then check the address manager.
+			script, done, err := w.Manager.RedeemScript(addrmgrNs, addr)
+			if err != nil {
+				return nil, err
+			}
+			doneFuncs = append(doneFuncs, done)
+			return script, nil
+		})
+
+		authoredTx, err = txauthor.NewUnsignedTransaction(outputs, relayFee,
+			inputSource.SelectInputs, changeSource, accprop.AccountType,
+			w.chainParams, getScript, fromAddress, w.txLimits)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return authoredTx, nil
+}
+
 // secretSource is an implementation of txauthor.SecretSource for the wallet's
 // address manager.
 type secretSource struct {
@@ -422,8 +504,13 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int3
 		return nil, err
 	}
 
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, err
+	}
+
 	return w.txToOutputsInternal(outputs, account, minconf, chainClient,
-		randomizeChangeIdx, w.RelayFee(), changeAddr, fromAddress)
+		randomizeChangeIdx, relayFee, changeAddr, fromAddress)
 }
 
 // txToOutputsInternal creates a signed transaction which includes each output
@@ -438,7 +525,7 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int3
 // into the database, rather than delegating this work to the caller as
 // btcwallet does.
 func (w *Wallet) txToOutputsInternal(outputs []*wire.TxOut, account uint32, minconf int32,
-	chainClient *hcrpcclient.Client, randomizeChangeIdx bool, txFee hcutil.Amount,
+	chainClient ChainClient, randomizeChangeIdx bool, txFee hcutil.Amount,
 	changeAddrStr string, fromAddress string) (*txauthor.AuthoredTx, error) {
 
 	var doneFuncs []func()
@@ -498,7 +585,8 @@ func (w *Wallet) txToOutputsInternal(outputs []*wire.TxOut, account uint32, minc
 		})
 
 		atx, err = txauthor.NewUnsignedTransaction(outputs, txFee,
-			inputSource.SelectInputs, changeSource, accType, w.chainParams, getScript, fromAddress)
+			inputSource.SelectInputs, changeSource, accType, w.chainParams, getScript, fromAddress,
+			w.txLimits)
 		if err != nil {
 			return err
 		}
@@ -551,12 +639,13 @@ func (w *Wallet) txToOutputsInternal(outputs []*wire.TxOut, account uint32, minc
 
 		// TODO: this can be improved by not using the same codepath as notified
 		// relevant transactions, since this does a lot of extra work.
-		err = w.processTransactionRecord(dbtx, rec, nil, nil)
+		err = w.processTransactionRecord(dbtx, rec, nil, nil, "send")
 		if err != nil {
 			return err
 		}
 
-		_, err = chainClient.SendRawTransaction(atx.Tx, w.AllowHighFees)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, err = w.sendRawTransaction(txmgrNs, chainClient, atx.Tx, w.allowHighFees)
 		return err
 	})
 	if err != nil {
@@ -674,7 +763,7 @@ func (w *Wallet) txToMultisigInternal(dbtx walletdb.ReadWriteTx, account uint32,
 			return txToMultisigError(err)
 		}
 	}
-	err = w.TxStore.InsertTxScript(txmgrNs, msScript)
+	err = w.insertTxScript(txmgrNs, msScript)
 	if err != nil {
 		return txToMultisigError(err)
 	}
@@ -694,7 +783,7 @@ func (w *Wallet) txToMultisigInternal(dbtx walletdb.ReadWriteTx, account uint32,
 	// we don't need to add a change output in this
 	// case.
 	feeSize := estimateTxSize(numInputs, 2, account)
-	feeIncrement := w.RelayFee()
+	feeIncrement := w.accountFee(dbtx, account, udb.AccountRelayFee, w.RelayFee())
 
 	feeEst := feeForSize(feeIncrement, feeSize)
 
@@ -716,7 +805,7 @@ func (w *Wallet) txToMultisigInternal(dbtx walletdb.ReadWriteTx, account uint32,
 		return txToMultisigError(err)
 	}
 
-	_, err = chainClient.SendRawTransaction(msgtx, w.AllowHighFees)
+	_, err = w.sendRawTransaction(txmgrNs, chainClient, msgtx, w.allowHighFees)
 	if err != nil {
 		return txToMultisigError(err)
 	}
@@ -770,20 +859,69 @@ func validateMsgTxCredits(tx *wire.MsgTx, prevCredits []udb.Credit) error {
 	return validateMsgTx(tx, prevScripts)
 }
 
+// ConsolidationResult describes a published consolidation transaction.
+type ConsolidationResult struct {
+	Hash   chainhash.Hash
+	Inputs int           // number of spent outputs
+	Fee    hcutil.Amount // fee paid by the transaction
+	Amount hcutil.Amount // value of the consolidated output
+}
+
+// consolidateOptions modifies the selection of consolidated outputs and the
+// fee of consolidation transactions.
+type consolidateOptions struct {
+	// dustLimit restricts the consolidated outputs to those of a lower
+	// value when non-zero.
+	dustLimit hcutil.Amount
+
+	// feeRate is the fee per kB paid by the transaction, or zero for the
+	// relay fee of the account.
+	feeRate hcutil.Amount
+
+	// maxFee is the highest fee paid by a single transaction, or zero for
+	// no limit.
+	maxFee hcutil.Amount
+}
+
 // compressWallet compresses all the utxos in a wallet into a single change
 // address. For use when it becomes dusty.
-func (w *Wallet) compressWallet(maxNumIns int, account uint32, changeAddr hcutil.Address) (*chainhash.Hash, error) {
-	var hash *chainhash.Hash
-	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-		var err error
-		hash, err = w.compressWalletInternal(dbtx, maxNumIns, account, changeAddr)
-		return err
-	})
-	return hash, err
+//
+// If consolidating maxNumIns outputs would exceed the wallet's transaction
+// limits and splitting transactions is enabled, the outputs are consolidated
+// by multiple transactions which each remain within the limits.  All published
+// transactions are returned, even if an error occurs after some have been
+// published.
+func (w *Wallet) compressWallet(maxNumIns int, account uint32, changeAddr hcutil.Address,
+	opts *consolidateOptions) ([]ConsolidationResult, error) {
+
+	var results []ConsolidationResult
+	for maxNumIns > 0 {
+		var res *ConsolidationResult
+		err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			var err error
+			res, err = w.compressWalletInternal(dbtx, maxNumIns, account,
+				changeAddr, opts)
+			return err
+		})
+		if err == ErrNoOutsToConsolidate && len(results) != 0 {
+			break
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, *res)
+		maxNumIns -= res.Inputs
+		if !w.splitTxs {
+			break
+		}
+	}
+	return results, nil
 }
 
+// compressWalletInternal creates and publishes a single consolidation
+// transaction spending at most maxNumIns outputs.
 func (w *Wallet) compressWalletInternal(dbtx walletdb.ReadWriteTx, maxNumIns int, account uint32,
-	changeAddr hcutil.Address) (*chainhash.Hash, error) {
+	changeAddr hcutil.Address, opts *consolidateOptions) (*ConsolidationResult, error) {
 
 	addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
 	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
@@ -808,6 +946,9 @@ func (w *Wallet) compressWalletInternal(dbtx walletdb.ReadWriteTx, maxNumIns int
 	if err != nil {
 		return nil, err
 	}
+	if opts.dustLimit != 0 {
+		eligible = dustOutputs(eligible, opts.dustLimit)
+	}
 
 	if len(eligible) == 0 {
 		return nil, ErrNoOutsToConsolidate
@@ -818,12 +959,41 @@ func (w *Wallet) compressWalletInternal(dbtx walletdb.ReadWriteTx, maxNumIns int
 		txInCount = maxNumIns
 	}
 
+	// Enforce the wallet's transaction limits.  When splitting is enabled,
+	// only spend as many outputs as fit within the limits, leaving the rest
+	// to be consolidated by later transactions.
+	err = w.txLimits.Check(txInCount, estimateTxSize(txInCount, 1, account))
+	if err != nil {
+		if !w.splitTxs {
+			return nil, err
+		}
+		if w.txLimits.MaxInputs > 0 && txInCount > w.txLimits.MaxInputs {
+			txInCount = w.txLimits.MaxInputs
+		}
+		for txInCount > 0 && w.txLimits.Check(txInCount,
+			estimateTxSize(txInCount, 1, account)) != nil {
+			txInCount--
+		}
+		if txInCount == 0 {
+			return nil, err
+		}
+		maxNumIns = txInCount
+	}
+
 	// Get an initial fee estimate based on the number of selected inputs
 	// and added outputs, with no change.
 	szEst := estimateTxSize(txInCount, 1, account)
-	feeIncrement := w.RelayFee()
+	feeIncrement := opts.feeRate
+	if feeIncrement == 0 {
+		feeIncrement = w.accountFee(dbtx, account, udb.AccountRelayFee, w.RelayFee())
+	}
 
 	feeEst := feeForSize(feeIncrement, szEst)
+	if opts.maxFee != 0 && feeEst > opts.maxFee {
+		str := fmt.Sprintf("consolidation fee %v exceeds the maximum fee %v",
+			feeEst, opts.maxFee)
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
 
 	// Check if output address is default, and generate a new adress if needed
 	if changeAddr == nil {
@@ -865,6 +1035,9 @@ func (w *Wallet) compressWalletInternal(dbtx walletdb.ReadWriteTx, maxNumIns int
 	}
 
 	msgtx.TxOut[0].Value = int64(totalAdded - feeEst)
+	if txrules.IsDustOutput(msgtx.TxOut[0], feeIncrement) {
+		return nil, ErrConsolidationDust
+	}
 
 	if err = signMsgTx(msgtx, forSigning, w.Manager, addrmgrNs,
 		w.chainParams); err != nil {
@@ -874,7 +1047,7 @@ func (w *Wallet) compressWalletInternal(dbtx walletdb.ReadWriteTx, maxNumIns int
 		return nil, err
 	}
 
-	txSha, err := chainClient.SendRawTransaction(msgtx, w.AllowHighFees)
+	txSha, err := w.sendRawTransaction(txmgrNs, chainClient, msgtx, w.allowHighFees)
 	if err != nil {
 		return nil, err
 	}
@@ -891,7 +1064,12 @@ func (w *Wallet) compressWalletInternal(dbtx walletdb.ReadWriteTx, maxNumIns int
 
 	log.Infof("Successfully consolidated funds in transaction %v", txSha)
 
-	return txSha, nil
+	return &ConsolidationResult{
+		Hash:   *txSha,
+		Inputs: count,
+		Fee:    feeEst,
+		Amount: hcutil.Amount(msgtx.TxOut[0].Value),
+	}, nil
 }
 
 // makeTicket creates a ticket from a split transaction output. It can optionally
@@ -1050,13 +1228,40 @@ func (w *Wallet) FirstAddr(account uint32) (*hcutil.AddressPubKeyHash, error) {
 	return addr, err
 }
 
+// TicketPurchaseFailure describes a ticket which could not be purchased after
+// the split transaction funding it was published.  The split transaction
+// output intended for the ticket remains spendable by the wallet.
+type TicketPurchaseFailure struct {
+	// Index is the position of the ticket among the requested tickets.
+	Index int
+	Err   error
+}
+
+// PurchaseTicketsResult describes the transactions published by a ticket
+// purchase.
+type PurchaseTicketsResult struct {
+	// SplitTx is the hash of the split transaction which funds each ticket
+	// with an output of the exact ticket cost.
+	SplitTx chainhash.Hash
+
+	// TicketHashes holds the hashes of all published tickets.
+	TicketHashes []*chainhash.Hash
+
+	// Failures describes each requested ticket which was not published.
+	Failures []TicketPurchaseFailure
+
+	// TotalSpent is the amount spent by the published tickets, including
+	// ticket and stake pool fees, and the fee of the split transaction.
+	TotalSpent hcutil.Amount
+}
+
 // purchaseTickets indicates to the wallet that a ticket should be purchased
 // using all currently available funds.  The ticket address parameter in the
 // request can be nil in which case the ticket address associated with the
 // wallet instance will be used.  Also, when the spend limit in the request is
 // greater than or equal to 0, tickets that cost more than that limit will
 // return an error that not enough funds are available.
-func (w *Wallet) purchaseTickets(req purchaseTicketRequest) ([]*chainhash.Hash, error) {
+func (w *Wallet) purchaseTickets(req purchaseTicketRequest) (*PurchaseTicketsResult, error) {
 	chainClient, err := w.requireChainClient()
 	if err != nil {
 		return nil, err
@@ -1155,10 +1360,13 @@ func (w *Wallet) purchaseTickets(req purchaseTicketRequest) ([]*chainhash.Hash,
 	// Make sure that we have enough funds. Calculate different
 	// ticket required amounts depending on whether or not a
 	// pool output is needed. If the ticket fee increment is
-	// unset in the request, use the global ticket fee increment.
+	// unset in the request, use the account's ticket fee increment.
 	ticketFeeIncrement := req.ticketFee
 	if ticketFeeIncrement == 0 {
-		ticketFeeIncrement = w.TicketFeeIncrement()
+		ticketFeeIncrement, err = w.AccountTicketFeeIncrement(account)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	ticketFee, neededPerTicket, err := w.getTicketFeeAndNeededTicketPrice(account, poolAddress != nil, ticketPrice, ticketFeeIncrement)
@@ -1247,7 +1455,10 @@ func (w *Wallet) purchaseTickets(req purchaseTicketRequest) ([]*chainhash.Hash,
 
 	txFeeIncrement := req.txFee
 	if txFeeIncrement == 0 {
-		txFeeIncrement = w.RelayFee()
+		txFeeIncrement, err = w.AccountRelayFee(account)
+		if err != nil {
+			return nil, err
+		}
 	}
 	splitTx, err := w.txToOutputsInternal(splitOuts, account, req.minConf,
 		chainClient, false, txFeeIncrement, "", "")
@@ -1267,9 +1478,16 @@ func (w *Wallet) purchaseTickets(req purchaseTicketRequest) ([]*chainhash.Hash,
 		}
 	}()
 
-	// Generate the tickets individually.
-	ticketHashes := make([]*chainhash.Hash, 0, req.numTickets)
-	for i := 0; i < req.numTickets; i++ {
+	res := &PurchaseTicketsResult{
+		SplitTx:    splitTx.Tx.TxHash(),
+		TotalSpent: splitTx.TotalInput - h.SumOutputValues(splitTx.Tx.TxOut),
+	}
+
+	// purchaseTicket creates, signs, records and publishes the ticket funded
+	// by the i'th split output (or pair of outputs when using a stake pool).
+	// The published ticket hash and the amount spent by the ticket are
+	// returned.
+	purchaseTicket := func(i int) (*chainhash.Hash, hcutil.Amount, error) {
 		// Generate the extended outpoints that we need to use for ticket
 		// inputs. There are two inputs for pool tickets corresponding to the
 		// fees and the user subsidy, while user-handled tickets have only one
@@ -1340,13 +1558,13 @@ func (w *Wallet) purchaseTickets(req purchaseTicketRequest) ([]*chainhash.Hash,
 			return nil
 		})
 		if err != nil {
-			return ticketHashes, err
+			return nil, 0, err
 		}
 
 		// Generate the ticket msgTx and sign it.
 		ticket, err := makeTicket(w.chainParams, eopPool, eop, addrVote, addrSubsidy, int64(ticketPrice), poolAddress)
 		if err != nil {
-			return ticketHashes, err
+			return nil, 0, err
 		}
 		var forSigning []udb.Credit
 		if eopPool != nil {
@@ -1378,37 +1596,63 @@ func (w *Wallet) purchaseTickets(req purchaseTicketRequest) ([]*chainhash.Hash,
 			return signMsgTx(ticket, forSigning, w.Manager, ns, w.chainParams)
 		})
 		if err != nil {
-			return ticketHashes, err
+			return nil, 0, err
 		}
 		err = validateMsgTxCredits(ticket, forSigning)
 		if err != nil {
-			return ticketHashes, err
+			return nil, 0, err
 		}
 
 		rec, err := udb.NewTxRecordFromMsgTx(ticket, time.Now())
 		if err != nil {
-			return ticketHashes, err
+			return nil, 0, err
 		}
 
 		// Open a DB update to insert and publish the transaction.  If
 		// publishing fails, the update is rolled back.
 		var ticketHash *chainhash.Hash
 		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-			err = w.processTransactionRecord(dbtx, rec, nil, nil)
+			err = w.processTransactionRecord(dbtx, rec, nil, nil, "purchaseticket")
 			if err != nil {
 				return err
 			}
-			ticketHash, err = chainClient.SendRawTransaction(ticket, w.AllowHighFees)
+			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+			ticketHash, err = w.sendRawTransaction(txmgrNs, chainClient, ticket,
+				w.allowHighFees)
 			return err
 		})
 		if err != nil {
-			return ticketHashes, err
+			return nil, 0, err
 		}
-		ticketHashes = append(ticketHashes, ticketHash)
 		log.Infof("Successfully sent SStx purchase transaction %v", ticketHash)
+
+		cost := hcutil.Amount(eop.amt)
+		if eopPool != nil {
+			cost += hcutil.Amount(eopPool.amt)
+		}
+		return ticketHash, cost, nil
+	}
+
+	// Generate the tickets individually.  A failure to purchase one ticket
+	// does not prevent purchasing the remaining tickets, as each ticket
+	// spends its own split transaction output.
+	res.TicketHashes = make([]*chainhash.Hash, 0, req.numTickets)
+	for i := 0; i < req.numTickets; i++ {
+		ticketHash, cost, err := purchaseTicket(i)
+		if err != nil {
+			log.Errorf("Failed to purchase ticket %d of %d: %v", i+1,
+				req.numTickets, err)
+			res.Failures = append(res.Failures, TicketPurchaseFailure{
+				Index: i,
+				Err:   err,
+			})
+			continue
+		}
+		res.TicketHashes = append(res.TicketHashes, ticketHash)
+		res.TotalSpent += cost
 	}
 
-	return ticketHashes, nil
+	return res, nil
 }
 
 // txToSStx creates a raw SStx transaction sending the amounts for each
@@ -1655,6 +1899,15 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, account uint32, minco
 		return nil, err
 	}
 
+	// Accounts may require more confirmations than requested, or more than
+	// the chain requires for coinbase and stakebase outputs to mature.
+	confs, err := w.TxStore.SpendableConfs(txmgrNs, account)
+	if err != nil {
+		return nil, err
+	}
+	minconf = confs.MinConf(minconf)
+	coinbaseMaturity := confs.CoinbaseMaturity(w.chainParams)
+
 	// TODO: Eventually all of these filters (except perhaps output locking)
 	// should be handled by the call to UnspentOutputs (or similar).
 	// Because one of these filters requires matching the output script to
@@ -1671,8 +1924,10 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, account uint32, minco
 			continue
 		}
 
-		// Locked unspent outputs are skipped.
-		if w.LockedOutpoint(output.OutPoint) {
+		// Locked unspent outputs, and outputs reserved by transaction
+		// drafts, are skipped.
+		if w.LockedOutpoint(output.OutPoint) ||
+			w.TxStore.TxDraftReserving(txmgrNs, &output.OutPoint) != nil {
 			continue
 		}
 
@@ -1692,13 +1947,11 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, account uint32, minco
 		case class == txscript.StakeSubmissionTy:
 			continue
 		case class == txscript.StakeGenTy:
-			target := int32(w.chainParams.CoinbaseMaturity)
-			if !confirmed(target, output.Height, currentHeight) {
+			if !confirmed(coinbaseMaturity, output.Height, currentHeight) {
 				continue
 			}
 		case class == txscript.StakeRevocationTy:
-			target := int32(w.chainParams.CoinbaseMaturity)
-			if !confirmed(target, output.Height, currentHeight) {
+			if !confirmed(coinbaseMaturity, output.Height, currentHeight) {
 				continue
 			}
 		case class == txscript.StakeSubChangeTy:
@@ -1708,8 +1961,7 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, account uint32, minco
 			}
 		case class == txscript.PubKeyHashTy:
 			if output.FromCoinBase {
-				target := int32(w.chainParams.CoinbaseMaturity)
-				if !confirmed(target, output.Height, currentHeight) {
+				if !confirmed(coinbaseMaturity, output.Height, currentHeight) {
 					continue
 				}
 			}
@@ -1763,8 +2015,10 @@ func (w *Wallet) findEligibleOutputsAmount(dbtx walletdb.ReadTx, account uint32,
 	for i := range unspent {
 		output := unspent[i]
 
-		// Locked unspent outputs are skipped.
-		if w.LockedOutpoint(output.OutPoint) {
+		// Locked unspent outputs, and outputs reserved by transaction
+		// drafts, are skipped.
+		if w.LockedOutpoint(output.OutPoint) ||
+			w.TxStore.TxDraftReserving(txmgrNs, &output.OutPoint) != nil {
 			continue
 		}
 
@@ -0,0 +1,143 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// VerifyDB checks the integrity of the transaction store of a wallet database
+// and returns a description of each inconsistency found.  The database does
+// not need to be loaded as a wallet, allowing it to be checked before opening.
+func VerifyDB(db walletdb.DB) ([]string, error) {
+	var issues []string
+	err := walletdb.View(db, func(dbtx walletdb.ReadTx) error {
+		ns := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if ns == nil {
+			const str = "missing transaction store namespace"
+			return apperrors.E{ErrorCode: apperrors.ErrData, Description: str, Err: nil}
+		}
+		var err error
+		issues, err = udb.VerifyIntegrity(ns)
+		return err
+	})
+	return issues, err
+}
+
+// CheckDB checks the integrity of the wallet's transaction store.  See
+// VerifyDB for details.
+func (w *Wallet) CheckDB() ([]string, error) {
+	return VerifyDB(w.db)
+}
+
+// CompactDB rewrites the wallet database without the space held by deleted
+// data.  Database access blocks while the database is compacted.
+func (w *Wallet) CompactDB() error {
+	return walletdb.Compact(w.db)
+}
+
+// MinHistoryPruneDepth is the minimum number of blocks below the main chain tip
+// at which the transaction history may be pruned.  This keeps pruned
+// transactions out of the reach of reorganizations.
+const MinHistoryPruneDepth = 1024
+
+// historyPruneInterval is the number of blocks between automatic prunes of the
+// transaction history.
+const historyPruneInterval = 144
+
+// SetHistoryPruneDepth sets the depth below the main chain tip at which the
+// transaction history is automatically pruned as blocks are connected.  Zero
+// disables automatic pruning.
+func (w *Wallet) SetHistoryPruneDepth(depth int32) {
+	w.historyPruneMu.Lock()
+	w.historyPruneDepth = depth
+	w.historyPruneMu.Unlock()
+}
+
+// HistoryPruneDepth returns the depth below the main chain tip at which the
+// transaction history is automatically pruned, or zero if it is not.
+func (w *Wallet) HistoryPruneDepth() int32 {
+	w.historyPruneMu.Lock()
+	depth := w.historyPruneDepth
+	w.historyPruneMu.Unlock()
+	return depth
+}
+
+// PruneHistory removes the records of fully spent regular transactions mined
+// at least depth blocks below the main chain tip.  The totals of all
+// transactions pruned at and below this height are recorded as a checkpoint,
+// which is returned.  See udb.Store.PruneHistory for the transactions which
+// are kept.
+//
+// Pruned transactions no longer appear in the transaction history.  A rescan
+// over the pruned blocks records them again until they are next pruned.
+func (w *Wallet) PruneHistory(depth int32) (*udb.HistoryCheckpoint, error) {
+	if depth < MinHistoryPruneDepth {
+		str := fmt.Sprintf("prune depth must be at least %d blocks",
+			MinHistoryPruneDepth)
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	var cp *udb.HistoryCheckpoint
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		if tipHeight-depth < 0 {
+			str := fmt.Sprintf("main chain height %d is not deeper than "+
+				"the prune depth", tipHeight)
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+		height := tipHeight - depth
+		var pruned []chainhash.Hash
+		var err error
+		cp, pruned, err = w.TxStore.PruneHistory(txmgrNs, height)
+		if err != nil {
+			return err
+		}
+		for i := range pruned {
+			err := w.auditRemoval(txmgrNs, &pruned[i], udb.AuditPrune,
+				height, "prunehistory")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return cp, err
+}
+
+// HistoryCheckpoints returns the checkpoints recorded by pruning the
+// transaction history, ordered by increasing height.
+func (w *Wallet) HistoryCheckpoints() ([]udb.HistoryCheckpoint, error) {
+	var checkpoints []udb.HistoryCheckpoint
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		checkpoints, err = w.TxStore.HistoryCheckpoints(txmgrNs)
+		return err
+	})
+	return checkpoints, err
+}
+
+// autoPruneHistory prunes the transaction history at the configured depth
+// every historyPruneInterval blocks.
+func (w *Wallet) autoPruneHistory(height int32) {
+	depth := w.HistoryPruneDepth()
+	if depth == 0 || height%historyPruneInterval != 0 || height < depth {
+		return
+	}
+	cp, err := w.PruneHistory(depth)
+	if err != nil {
+		log.Errorf("Failed to prune transaction history: %v", err)
+		return
+	}
+	log.Infof("Pruned transaction history at height %d (%d transactions "+
+		"pruned at or below this height)", cp.Height, cp.Transactions)
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/omnilib"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// OutputAnnotation describes a transaction output and the wallet's knowledge
+// of it.
+type OutputAnnotation struct {
+	Amount      hcutil.Amount
+	PkScript    []byte
+	ScriptClass txscript.ScriptClass
+	Addresses   []hcutil.Address
+
+	// Mine is true when the output pays a wallet address.  The account,
+	// account name, and label (the name of the additional external branch
+	// the address is derived from, if any) are only set for these outputs.
+	// Change is true for outputs paying an internal branch address.
+	Mine        bool
+	Account     uint32
+	AccountName string
+	Label       string
+	Change      bool
+
+	// Data is the payload of null data outputs.
+	Data []byte
+
+	// Spent records whether a wallet output of a recorded transaction has
+	// been spent, and SpentBy the hash of the spending transaction.  They
+	// are only set by AnnotateRecordedTransaction.
+	Spent   bool
+	SpentBy *chainhash.Hash
+}
+
+// InputAnnotation describes a transaction input and the wallet's knowledge of
+// the previous output it spends.
+type InputAnnotation struct {
+	PreviousOutPoint wire.OutPoint
+	Sequence         uint32
+	ValueIn          hcutil.Amount
+	Stakebase        bool
+
+	// PreviousOutput is nil when the previous transaction is not recorded
+	// by the wallet.
+	PreviousOutput *OutputAnnotation
+}
+
+// AnnotatedTx is a transaction annotated with the wallet's knowledge of its
+// inputs and outputs.
+type AnnotatedTx struct {
+	Tx      *wire.MsgTx
+	Type    stake.TxType
+	Inputs  []InputAnnotation
+	Outputs []OutputAnnotation
+
+	// Omni is the omni engine's decoding of the transaction, when omni is
+	// enabled and the transaction carries an omni payload.
+	Omni json.RawMessage
+}
+
+// annotateOutput returns the annotation of output index of a transaction of
+// some stake type.
+func (w *Wallet) annotateOutput(addrmgrNs walletdb.ReadBucket, out *wire.TxOut,
+	txType stake.TxType, index int) (*OutputAnnotation, error) {
+
+	a := &OutputAnnotation{
+		Amount:   hcutil.Amount(out.Value),
+		PkScript: out.PkScript,
+	}
+	a.ScriptClass, a.Addresses, _, _ = txscript.ExtractPkScriptAddrs(out.Version,
+		out.PkScript, w.chainParams)
+	switch {
+	case txType == stake.TxTypeSStx && index%2 == 1:
+		// Ticket commitments pay out to the committed address when the
+		// ticket is voted or revoked.
+		addr, err := stake.AddrFromSStxPkScrCommitment(out.PkScript, w.chainParams)
+		if err == nil {
+			a.Addresses = []hcutil.Address{addr}
+		}
+	case a.ScriptClass == txscript.NullDataTy:
+		pushes, err := txscript.PushedData(out.PkScript)
+		if err == nil && len(pushes) != 0 {
+			a.Data = pushes[0]
+		}
+	}
+
+	for _, addr := range a.Addresses {
+		ma, err := w.Manager.Address(addrmgrNs, addr)
+		if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		a.Mine = true
+		a.Account = ma.Account()
+		a.Change = ma.Internal()
+		a.AccountName, err = w.Manager.AccountName(addrmgrNs, a.Account)
+		if err != nil {
+			return nil, err
+		}
+		_, branch, _, ok, err := w.Manager.AddrBranchChild(addrmgrNs, addr)
+		if err != nil {
+			return nil, err
+		}
+		if ok && branch >= udb.FirstExtraExternalBranch &&
+			branch != udb.InvoiceBranch {
+			props, err := w.Manager.ExternalBranchProperties(addrmgrNs,
+				a.Account, branch)
+			if err != nil {
+				return nil, err
+			}
+			a.Label = props.BranchName
+		}
+		break
+	}
+	return a, nil
+}
+
+// AnnotateTransaction decodes a transaction and annotates its inputs and
+// outputs with the accounts, branch labels, and change status of the wallet
+// addresses they pay, and classifies it as a regular or stake transaction.
+// The transaction need not be recorded by the wallet.
+func (w *Wallet) AnnotateTransaction(tx *wire.MsgTx) (*AnnotatedTx, error) {
+	atx := &AnnotatedTx{
+		Tx:      tx,
+		Type:    stake.DetermineTxType(tx),
+		Inputs:  make([]InputAnnotation, len(tx.TxIn)),
+		Outputs: make([]OutputAnnotation, len(tx.TxOut)),
+	}
+	var prevTxs []omniPrevTx
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+		for i, in := range tx.TxIn {
+			a := &atx.Inputs[i]
+			a.PreviousOutPoint = in.PreviousOutPoint
+			a.Sequence = in.Sequence
+			a.ValueIn = hcutil.Amount(in.ValueIn)
+			a.Stakebase = i == 0 && atx.Type == stake.TxTypeSSGen
+			if a.Stakebase {
+				continue
+			}
+			prevOut := &in.PreviousOutPoint
+			if !w.TxStore.ExistsTx(txmgrNs, &prevOut.Hash) {
+				continue
+			}
+			prevTx, err := w.TxStore.Tx(txmgrNs, &prevOut.Hash)
+			if err != nil {
+				return err
+			}
+			if int(prevOut.Index) >= len(prevTx.TxOut) {
+				continue
+			}
+			a.PreviousOutput, err = w.annotateOutput(addrmgrNs,
+				prevTx.TxOut[prevOut.Index], stake.DetermineTxType(prevTx),
+				int(prevOut.Index))
+			if err != nil {
+				return err
+			}
+			prevTxs = append(prevTxs, omniPrevTx{
+				TxID:         prevOut.Hash.String(),
+				Vout:         prevOut.Index,
+				ScriptPubKey: hex.EncodeToString(a.PreviousOutput.PkScript),
+				Value:        a.PreviousOutput.Amount.ToCoin(),
+			})
+		}
+		for i, out := range tx.TxOut {
+			a, err := w.annotateOutput(addrmgrNs, out, atx.Type, i)
+			if err != nil {
+				return err
+			}
+			atx.Outputs[i] = *a
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if w.EnableOmni() {
+		atx.Omni = omniDecodeTransaction(tx, prevTxs)
+	}
+	return atx, nil
+}
+
+// AnnotateRecordedTransaction annotates a transaction recorded by the wallet
+// like AnnotateTransaction, and additionally records whether each wallet output
+// has been spent and by which transaction.
+func (w *Wallet) AnnotateRecordedTransaction(details *udb.TxDetails) (*AnnotatedTx, error) {
+	atx, err := w.AnnotateTransaction(&details.MsgTx)
+	if err != nil {
+		return nil, err
+	}
+	var block *udb.Block
+	if details.Block.Height != -1 {
+		block = &details.Block.Block
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		for _, cred := range details.Credits {
+			if !cred.Spent || int(cred.Index) >= len(atx.Outputs) {
+				continue
+			}
+			a := &atx.Outputs[cred.Index]
+			a.Spent = true
+			var err error
+			a.SpentBy, err = w.TxStore.CreditSpender(txmgrNs, &details.Hash,
+				cred.Index, block)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return atx, nil
+}
+
+// omniPrevTx describes a previous output for the omni engine's transaction
+// decoding.
+type omniPrevTx struct {
+	TxID         string  `json:"txid"`
+	Vout         uint32  `json:"vout"`
+	ScriptPubKey string  `json:"scriptPubKey"`
+	Value        float64 `json:"value"`
+}
+
+// omniDecodeTransaction returns the omni engine's decoding of a transaction
+// carrying an omni payload, or nil if the transaction carries no payload or
+// can not be decoded.
+func omniDecodeTransaction(tx *wire.MsgTx, prevTxs []omniPrevTx) json.RawMessage {
+	hasPayload := false
+	for _, out := range tx.TxOut {
+		if ok, _ := getPayLoadData(out.PkScript); ok {
+			hasPayload = true
+			break
+		}
+	}
+	if !hasPayload {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializeSize())
+	err := tx.Serialize(&buf)
+	if err != nil {
+		return nil
+	}
+	var prevTxsJSON *string
+	if len(prevTxs) != 0 {
+		b, err := json.Marshal(prevTxs)
+		if err != nil {
+			return nil
+		}
+		s := string(b)
+		prevTxsJSON = &s
+	}
+	cmd := hcjson.NewOmniDecodetransactionCmd(hex.EncodeToString(buf.Bytes()),
+		prevTxsJSON, nil)
+	res, err := omnilib.SendCmd(cmd)
+	if err != nil {
+		log.Debugf("Omni engine failed to decode transaction %v: %v",
+			tx.TxHash(), err)
+		return nil
+	}
+	return res
+}
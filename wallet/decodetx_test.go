@@ -0,0 +1,144 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestAnnotateTransaction(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	fundingHash := spend.TxIn[0].PreviousOutPoint.Hash
+
+	data := []byte("annotated")
+	out, err := nulldataOutput(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := spend.Copy()
+	tx.AddTxOut(out)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, nil))
+
+	atx, err := w.AnnotateTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atx.Type != stake.TxTypeRegular {
+		t.Errorf("transaction type %v, want regular", atx.Type)
+	}
+
+	// The first input spends the wallet's external address, while the
+	// previous transaction of the second is not recorded.
+	prev := atx.Inputs[0].PreviousOutput
+	if prev == nil || !prev.Mine || prev.Change || prev.Amount != 10e8 ||
+		prev.Account != udb.DefaultAccountNum {
+		t.Errorf("first input previous output annotated as %+v", prev)
+	}
+	if atx.Inputs[1].PreviousOutput != nil {
+		t.Errorf("unrecorded previous output annotated as %+v",
+			atx.Inputs[1].PreviousOutput)
+	}
+
+	outs := atx.Outputs
+	if len(outs) != 3 {
+		t.Fatalf("annotated %d outputs, want 3", len(outs))
+	}
+	if outs[0].Mine {
+		t.Errorf("foreign output annotated as a wallet output")
+	}
+	if !outs[1].Mine || !outs[1].Change || outs[1].AccountName != "default" {
+		t.Errorf("change output annotated as %+v", outs[1])
+	}
+	if outs[2].ScriptClass != txscript.NullDataTy || !bytes.Equal(outs[2].Data, data) {
+		t.Errorf("null data output annotated as %+v", outs[2])
+	}
+
+	// Recorded transactions are annotated with the spenders of their
+	// outputs.
+	var details *udb.TxDetails
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		details, err = w.TxStore.TxDetails(txmgrNs, &fundingHash)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	atx, err = w.AnnotateRecordedTransaction(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spendHash := spend.TxHash()
+	a := atx.Outputs[0]
+	if !a.Mine || !a.Spent || a.SpentBy == nil || *a.SpentBy != spendHash {
+		t.Errorf("spent output annotated as spent %v by %v, want spent by %v",
+			a.Spent, a.SpentBy, &spendHash)
+	}
+}
+
+func TestRecordedTxDetails(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	fundingHash := spend.TxIn[0].PreviousOutPoint.Hash
+	spendHash := spend.TxHash()
+
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+		// Details carry the stored serialization of the transaction.
+		details, err := w.TxStore.TxDetails(txmgrNs, &spendHash)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := spend.Serialize(&buf); err != nil {
+			return err
+		}
+		if !bytes.Equal(details.SerializedTx, buf.Bytes()) {
+			t.Errorf("serialized transaction %x, want %x",
+				details.SerializedTx, buf.Bytes())
+		}
+
+		tests := []struct {
+			hash    *chainhash.Hash
+			index   uint32
+			spender *chainhash.Hash
+		}{
+			{&fundingHash, 0, &spendHash},
+			{&spendHash, 1, nil},
+			{&spendHash, 0, nil},
+		}
+		for _, test := range tests {
+			spender, err := w.TxStore.CreditSpender(txmgrNs, test.hash,
+				test.index, nil)
+			if err != nil {
+				return err
+			}
+			if (spender == nil) != (test.spender == nil) ||
+				spender != nil && *spender != *test.spender {
+				t.Errorf("output %v:%d spent by %v, want %v", test.hash,
+					test.index, spender, test.spender)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
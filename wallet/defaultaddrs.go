@@ -0,0 +1,132 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"sync"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// defaultAddrCache memoizes the default address of each account, which is
+// otherwise derived or read from the database for every transaction sent
+// without an explicit change or sending address.  The cache must be
+// invalidated after the default address of any account is set.
+type defaultAddrCache struct {
+	mu    sync.RWMutex
+	addrs map[uint32]hcutil.Address
+
+	// generation is incremented by every invalidation, so lookups racing
+	// with a change of a default address do not cache the old address.
+	generation uint64
+}
+
+// gen returns the current generation of the cache.
+func (c *defaultAddrCache) gen() uint64 {
+	c.mu.RLock()
+	gen := c.generation
+	c.mu.RUnlock()
+	return gen
+}
+
+// address returns the cached default address of an account.
+func (c *defaultAddrCache) address(account uint32) (hcutil.Address, bool) {
+	c.mu.RLock()
+	addr, ok := c.addrs[account]
+	c.mu.RUnlock()
+	return addr, ok
+}
+
+// add caches the default address of an account looked up when the cache
+// generation was gen.  Nothing is cached if the cache was invalidated since.
+func (c *defaultAddrCache) add(gen uint64, account uint32, addr hcutil.Address) {
+	c.mu.Lock()
+	if gen == c.generation {
+		if c.addrs == nil {
+			c.addrs = make(map[uint32]hcutil.Address)
+		}
+		c.addrs[account] = addr
+	}
+	c.mu.Unlock()
+}
+
+// invalidate removes all cached default addresses.
+func (c *defaultAddrCache) invalidate() {
+	c.mu.Lock()
+	c.addrs = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+// AccountDefaultAddress returns the default address of an account, which is
+// used as the change address and the sending address of transactions from the
+// account which do not specify one.  This is the address set by
+// SetAccountDefaultAddress, or the first address of the account's external
+// branch if none was set.
+func (w *Wallet) AccountDefaultAddress(account uint32) (hcutil.Address, error) {
+	if addr, ok := w.defaultAddrs.address(account); ok {
+		return addr, nil
+	}
+
+	gen := w.defaultAddrs.gen()
+	var encoded string
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		_, err := w.Manager.AccountName(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		encoded = w.Manager.AccountDefaultAddress(addrmgrNs, account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var addr hcutil.Address
+	if encoded != "" {
+		addr, err = hcutil.DecodeAddress(encoded)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		firstAddr, err := w.FirstAddr(account)
+		if err != nil {
+			return nil, err
+		}
+		addr = firstAddr
+	}
+	w.defaultAddrs.add(gen, account, addr)
+	return addr, nil
+}
+
+// SetAccountDefaultAddress sets the default address of an account.  The
+// address must belong to the account.  A nil address reverts the account to
+// the first address of its external branch.
+func (w *Wallet) SetAccountDefaultAddress(account uint32, addr hcutil.Address) error {
+	defer w.defaultAddrs.invalidate()
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		_, err := w.Manager.AccountName(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		var encoded string
+		if addr != nil {
+			addrAccount, err := w.Manager.AddrAccount(addrmgrNs, addr)
+			if err != nil {
+				return err
+			}
+			if addrAccount != account {
+				const str = "address does not belong to the account"
+				return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+			}
+			encoded = addr.EncodeAddress()
+		}
+		return w.Manager.PutAccountDefaultAddress(addrmgrNs, account, encoded)
+	})
+}
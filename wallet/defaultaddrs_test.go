@@ -0,0 +1,131 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestDefaultAddrCache(t *testing.T) {
+	var c defaultAddrCache
+	addr, err := hcutil.NewAddressPubKeyHash(make([]byte, 20),
+		testParams, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gen := c.gen()
+	c.add(gen, 0, addr)
+	if cached, ok := c.address(0); !ok || cached != addr {
+		t.Errorf("cached address (%v, %v), want (%v, true)", cached, ok, addr)
+	}
+
+	// Lookups racing with an invalidation are not cached.
+	gen = c.gen()
+	c.invalidate()
+	if _, ok := c.address(0); ok {
+		t.Error("address remains cached after invalidation")
+	}
+	c.add(gen, 0, addr)
+	if _, ok := c.address(0); ok {
+		t.Error("address looked up before invalidation was cached")
+	}
+}
+
+func TestAccountDefaultAddress(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	walletPkScript(t, w, udb.ExternalBranch, 2)
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultAddr := func() hcutil.Address {
+		addr, err := w.AccountDefaultAddress(udb.DefaultAccountNum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr
+	}
+
+	// Accounts default to their first address.
+	if addr := defaultAddr(); addr.EncodeAddress() != addrs[0].EncodeAddress() {
+		t.Errorf("default address %v, want the first address %v", addr,
+			addrs[0])
+	}
+
+	// Setting the default address invalidates the cached address, and
+	// records the address in the database.
+	err = w.SetAccountDefaultAddress(udb.DefaultAccountNum, addrs[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := defaultAddr(); addr.EncodeAddress() != addrs[2].EncodeAddress() {
+		t.Errorf("default address %v, want %v", addr, addrs[2])
+	}
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		recorded := w.Manager.AccountDefaultAddress(addrmgrNs,
+			udb.DefaultAccountNum)
+		if recorded != addrs[2].EncodeAddress() {
+			t.Errorf("recorded default address %q, want %v", recorded,
+				addrs[2])
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Addresses of other accounts and addresses unknown to the wallet are
+	// rejected.
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SyncAccountToAddrIndex(ns, 1, 0, udb.ExternalBranch)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAddrs, err := w.AccountBranchAddressRange(1, udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.SetAccountDefaultAddress(udb.DefaultAccountNum, otherAddrs[0])
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("setting the address of another account returned %v, "+
+			"want ErrInput", err)
+	}
+	foreign, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), testParams, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetAccountDefaultAddress(udb.DefaultAccountNum, foreign); err == nil {
+		t.Error("set an address unknown to the wallet as the default address")
+	}
+	if addr := defaultAddr(); addr.EncodeAddress() != addrs[2].EncodeAddress() {
+		t.Errorf("default address %v after rejections, want %v", addr,
+			addrs[2])
+	}
+	if _, err := w.AccountDefaultAddress(2); err == nil {
+		t.Error("returned the default address of a nonexistent account")
+	}
+
+	// A nil address reverts the account to its first address.
+	err = w.SetAccountDefaultAddress(udb.DefaultAccountNum, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := defaultAddr(); addr.EncodeAddress() != addrs[0].EncodeAddress() {
+		t.Errorf("default address %v after reverting, want %v", addr,
+			addrs[0])
+	}
+}
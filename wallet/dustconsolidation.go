@@ -0,0 +1,198 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// DustConsolidationPolicy describes when the small outputs of an account, such
+// as the change of ticket purchases, are consolidated into a single output.
+type DustConsolidationPolicy struct {
+	// Automatic enables consolidating the dust outputs of every account as
+	// blocks are connected.  Dust is only consolidated automatically while
+	// the wallet is unlocked.
+	Automatic bool
+
+	// Threshold is the value below which an output is considered dust.
+	Threshold hcutil.Amount
+
+	// MinCount is the number of dust outputs an account must exceed before
+	// they are consolidated.
+	MinCount int
+
+	// MaxFeeRate is the highest median fee rate (per kB) of the transactions
+	// in the mempool at which dust is consolidated.  Zero selects the relay
+	// fee of the account, so dust is only consolidated when the mempool is
+	// not congested with higher fee transactions.
+	MaxFeeRate hcutil.Amount
+}
+
+// Defaults of the dust consolidation policy.
+const (
+	DefaultDustThreshold hcutil.Amount = 1e6
+	DefaultDustMinCount                = 50
+)
+
+// SetDustConsolidation sets the policy of dust output consolidation.
+func (w *Wallet) SetDustConsolidation(p DustConsolidationPolicy) {
+	w.dustConsolidationMu.Lock()
+	w.dustConsolidation = p
+	w.dustConsolidationMu.Unlock()
+}
+
+// DustConsolidation returns the policy of dust output consolidation.
+func (w *Wallet) DustConsolidation() DustConsolidationPolicy {
+	w.dustConsolidationMu.Lock()
+	p := w.dustConsolidation
+	w.dustConsolidationMu.Unlock()
+	return p
+}
+
+// dustOutputs filters credits to those valued below a threshold.
+func dustOutputs(credits []udb.Credit, threshold hcutil.Amount) []udb.Credit {
+	dust := credits[:0]
+	for _, c := range credits {
+		if c.Amount < threshold {
+			dust = append(dust, c)
+		}
+	}
+	return dust
+}
+
+// dustCount returns the number of spendable outputs of an account valued below
+// a threshold.
+func (w *Wallet) dustCount(account uint32, threshold hcutil.Amount) (int, error) {
+	var n int
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		_, err := w.Manager.AccountName(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		eligible, err := w.findEligibleOutputs(dbtx, account, 1, tipHeight)
+		if err != nil {
+			return err
+		}
+		n = len(dustOutputs(eligible, threshold))
+		return nil
+	})
+	return n, err
+}
+
+// lowFeePeriod returns whether the median fee rate of the transactions in the
+// mempool of the consensus RPC server does not exceed maxFeeRate.
+func (w *Wallet) lowFeePeriod(maxFeeRate hcutil.Amount) (bool, error) {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return false, err
+	}
+	info, err := chainClient.TxFeeInfo(nil, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	if info.FeeInfoMempool.Number == 0 {
+		return true, nil
+	}
+	median, err := hcutil.NewAmount(info.FeeInfoMempool.Median)
+	if err != nil {
+		return false, err
+	}
+	return median <= maxFeeRate, nil
+}
+
+// ConsolidateDust consolidates the outputs of an account valued below the
+// threshold of the dust consolidation policy into a new internal address of
+// the account, returning the hashes of the published transactions.  Unless
+// force is true, dust is only consolidated when the account holds more than
+// the policy's minimum number of dust outputs and the mempool fee rate does
+// not exceed the policy's maximum, and no transactions are returned otherwise.
+// The wallet must be unlocked.
+func (w *Wallet) ConsolidateDust(account uint32, force bool) ([]*chainhash.Hash, error) {
+	if account == udb.ImportedAddrAccount {
+		const str = "dust of the imported account can not be consolidated"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	p := w.DustConsolidation()
+	if p.Threshold <= 0 {
+		const str = "no dust threshold is configured"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	n, err := w.dustCount(account, p.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 || !force && n <= p.MinCount {
+		return nil, nil
+	}
+	if !force {
+		maxFeeRate := p.MaxFeeRate
+		if maxFeeRate == 0 {
+			maxFeeRate, err = w.AccountRelayFee(account)
+			if err != nil {
+				return nil, err
+			}
+		}
+		low, err := w.lowFeePeriod(maxFeeRate)
+		if err != nil {
+			return nil, err
+		}
+		if !low {
+			log.Debugf("Deferring consolidation of %d dust outputs of "+
+				"account %d until mempool fees decrease", n, account)
+			return nil, nil
+		}
+	}
+
+	results, err := w.consolidate(n, account, nil,
+		consolidateOptions{dustLimit: p.Threshold})
+	hashes := consolidationHashes(results)
+	if err == ErrConsolidationDust {
+		log.Debugf("Dust outputs of account %d are not worth the fee "+
+			"of consolidating them", account)
+		return hashes, nil
+	}
+	return hashes, err
+}
+
+// autoConsolidateDust consolidates the dust outputs of every account when
+// automatic consolidation is enabled and the wallet is unlocked.
+func (w *Wallet) autoConsolidateDust() {
+	if !w.DustConsolidation().Automatic {
+		return
+	}
+	if w.Manager.IsLocked() {
+		log.Debugf("Skipping automatic dust consolidation of a locked wallet")
+		return
+	}
+	var accounts []uint32
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		return w.Manager.ForEachAccount(addrmgrNs, func(account uint32) error {
+			if account != udb.ImportedAddrAccount {
+				accounts = append(accounts, account)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		log.Errorf("Failed to list accounts for dust consolidation: %v", err)
+		return
+	}
+	for _, account := range accounts {
+		_, err := w.ConsolidateDust(account, false)
+		if err != nil {
+			log.Errorf("Failed to consolidate dust of account %d: %v",
+				account, err)
+		}
+	}
+}
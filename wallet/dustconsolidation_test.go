@@ -0,0 +1,116 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestDustOutputs(t *testing.T) {
+	credits := []udb.Credit{
+		{Amount: 1e5},
+		{Amount: 1e6},
+		{Amount: 999999},
+		{Amount: 1e8},
+	}
+	dust := dustOutputs(credits, 1e6)
+	if len(dust) != 2 || dust[0].Amount != 1e5 || dust[1].Amount != 999999 {
+		t.Errorf("dust outputs %+v, want the outputs of 100000 and 999999 atoms",
+			dust)
+	}
+}
+
+func TestConsolidateDust(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	// Consolidation requires a threshold.
+	w.SetDustConsolidation(DustConsolidationPolicy{})
+	_, err := w.ConsolidateDust(udb.DefaultAccountNum, true)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("consolidating without a threshold returned %v, want "+
+			"ErrInput", err)
+	}
+
+	p := DustConsolidationPolicy{
+		Threshold: DefaultDustThreshold,
+		MinCount:  DefaultDustMinCount,
+	}
+	w.SetDustConsolidation(p)
+	if got := w.DustConsolidation(); got != p {
+		t.Errorf("dust consolidation policy %+v, want %+v", got, p)
+	}
+
+	_, err = w.ConsolidateDust(udb.ImportedAddrAccount, true)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("consolidating the imported account returned %v, want "+
+			"ErrInput", err)
+	}
+	_, err = w.ConsolidateDust(2, true)
+	if err == nil {
+		t.Error("consolidated the dust of a nonexistent account")
+	}
+
+	// Accounts without dust consolidate nothing, even when forced.
+	for _, force := range []bool{false, true} {
+		hashes, err := w.ConsolidateDust(udb.DefaultAccountNum, force)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hashes) != 0 {
+			t.Errorf("force %v: consolidated the dust of an account without "+
+				"dust in %d transactions", force, len(hashes))
+		}
+	}
+}
+
+func TestLowFeePeriod(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	c := testhelpers.NewMockChainClient()
+	w.chainClientLock.Lock()
+	w.chainClient = c
+	w.chainClientLock.Unlock()
+
+	if _, err := w.lowFeePeriod(1e5); err == nil {
+		t.Error("no error when the consensus server reported no fee info")
+	}
+
+	tests := []struct {
+		number uint32
+		median float64
+		max    hcutil.Amount
+		low    bool
+	}{
+		{0, 0, 1e5, true},
+		{3, 0.0009, 1e5, true},
+		{3, 0.001, 1e5, true},
+		{3, 0.0011, 1e5, false},
+	}
+	for _, test := range tests {
+		c.FeeInfo = &hcjson.TxFeeInfoResult{
+			FeeInfoMempool: hcjson.FeeInfoMempool{
+				Number: test.number,
+				Median: test.median,
+			},
+		}
+		low, err := w.lowFeePeriod(test.max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if low != test.low {
+			t.Errorf("median %v of %d transactions with maximum %v: low "+
+				"fee period %v, want %v", test.median, test.number,
+				test.max, low, test.low)
+		}
+	}
+}
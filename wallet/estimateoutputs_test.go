@@ -0,0 +1,98 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestEstimateOutputs(t *testing.T) {
+	w, headers, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	// Fund the first external address with a transaction mined in the
+	// first block.
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, foreignSigScript))
+	funding.AddTxOut(wire.NewTxOut(10e8, walletPkScript(t, w, udb.ExternalBranch, 0)))
+	rec, err := udb.NewTxRecordFromMsgTx(funding, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &headers[1]
+	blockMeta := &udb.BlockMeta{
+		Block: udb.Block{Hash: header.BlockHash, Height: 1},
+		Time:  time.Now(),
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.processTransactionRecord(dbtx, rec, &header.SerializedHeader,
+			blockMeta, "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := addrs[0].EncodeAddress()
+
+	outputs := []*wire.TxOut{wire.NewTxOut(1e8, foreignPkScript)}
+	atx, err := w.EstimateOutputs(outputs, udb.DefaultAccountNum, 1, "", from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atx.Tx.TxIn) != 1 || atx.Tx.TxIn[0].PreviousOutPoint.Hash != rec.Hash {
+		t.Fatalf("estimate does not spend the funding transaction: %v inputs",
+			len(atx.Tx.TxIn))
+	}
+	if atx.TotalInput != 10e8 {
+		t.Errorf("total input %v, want 10 HC", atx.TotalInput)
+	}
+	if atx.ChangeIndex < 0 {
+		t.Fatal("estimate has no change output")
+	}
+	var outputSum hcutil.Amount
+	for _, out := range atx.Tx.TxOut {
+		outputSum += hcutil.Amount(out.Value)
+	}
+	fee := atx.TotalInput - outputSum
+	if fee <= 0 {
+		t.Errorf("estimated fee %v is not positive", fee)
+	}
+	change := hcutil.Amount(atx.Tx.TxOut[atx.ChangeIndex].Value)
+	if change != 9e8-fee {
+		t.Errorf("change %v, want the input less the payment and fee %v",
+			change, 9e8-fee)
+	}
+
+	// The estimate is not recorded or published, so the funding output
+	// remains unspent and is selected again.
+	again, err := w.EstimateOutputs(outputs, udb.DefaultAccountNum, 1, "", from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Tx.TxIn) != 1 || again.Tx.TxIn[0].PreviousOutPoint != atx.Tx.TxIn[0].PreviousOutPoint {
+		t.Error("estimating again did not select the same input")
+	}
+
+	// Paying more than the sending address holds is an insufficient funds
+	// error.
+	outputs = []*wire.TxOut{wire.NewTxOut(20e8, foreignPkScript)}
+	_, err = w.EstimateOutputs(outputs, udb.DefaultAccountNum, 1, "", from)
+	if _, ok := err.(txauthor.InsufficientAddressFundsError); !ok {
+		t.Errorf("estimating an unfunded payment returned %v, want "+
+			"InsufficientAddressFundsError", err)
+	}
+}
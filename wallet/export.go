@@ -0,0 +1,206 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/HcashOrg/hcd/blockchain"
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// ExportCategory classifies the effect of an exported transaction on an
+// account.
+type ExportCategory string
+
+// Export categories.  Regular transactions are sends, receives, or transfers
+// (which move funds within the account and only pay a fee).  The amount of a
+// vote is the staking income of the voted ticket, and the amounts of ticket
+// purchases and revocations are their fees.
+const (
+	ExportSend       ExportCategory = "send"
+	ExportReceive    ExportCategory = "receive"
+	ExportTransfer   ExportCategory = "transfer"
+	ExportCoinbase   ExportCategory = "coinbase"
+	ExportTicket     ExportCategory = "ticket"
+	ExportVote       ExportCategory = "vote"
+	ExportRevocation ExportCategory = "revocation"
+)
+
+// ExportedTransaction describes the effect of a transaction on the balance of
+// a single account.
+type ExportedTransaction struct {
+	Hash        chainhash.Hash
+	Height      int32 // -1 for unmined transactions
+	Time        time.Time
+	Type        stake.TxType
+	Category    ExportCategory
+	Account     uint32
+	AccountName string
+
+	// Amount is the change of the account balance excluding the fee paid by
+	// the account, and Balance is the running balance of the account after
+	// the transaction.
+	Amount  hcutil.Amount
+	Fee     hcutil.Amount
+	Balance hcutil.Amount
+
+	// FiatPrice is the price of a coin in FiatCurrency at the time of the
+	// transaction, and FiatValue the value of the balance change (Amount -
+	// Fee).  They are only set when fiat valuation is requested.
+	FiatCurrency string
+	FiatPrice    float64
+	FiatValue    float64
+}
+
+// Net returns the change of the account balance including fees.
+func (e *ExportedTransaction) Net() hcutil.Amount {
+	return e.Amount - e.Fee
+}
+
+// exportDetails appends the exported transactions of each account affected by
+// a transaction, updating the running balances of the accounts.
+func (w *Wallet) exportDetails(dbtx walletdb.ReadTx, details *udb.TxDetails,
+	balances map[uint32]hcutil.Amount, exports []ExportedTransaction) []ExportedTransaction {
+
+	changes := make(map[uint32]hcutil.Amount)
+	for _, deb := range details.Debits {
+		changes[lookupInputAccount(dbtx, w, details, deb)] -= deb.Amount
+	}
+	for _, cred := range details.Credits {
+		account, _, _, _, _ := lookupOutputChain(dbtx, w, details, cred)
+		changes[account] += cred.Amount
+	}
+
+	// The fee is only known when every input is a debit, and is paid by
+	// the account of the first input.
+	var fee hcutil.Amount
+	var feeAccount uint32
+	if len(details.Debits) != 0 && len(details.Debits) == len(details.MsgTx.TxIn) {
+		for _, deb := range details.Debits {
+			fee += deb.Amount
+		}
+		for _, out := range details.MsgTx.TxOut {
+			fee -= hcutil.Amount(out.Value)
+		}
+		feeAccount = lookupInputAccount(dbtx, w, details, details.Debits[0])
+	}
+
+	accounts := make([]uint32, 0, len(changes))
+	for account := range changes {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i] < accounts[j] })
+
+	t := details.Received
+	if details.Block.Height != -1 {
+		t = details.Block.Time
+	}
+	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	for _, account := range accounts {
+		e := ExportedTransaction{
+			Hash:    details.Hash,
+			Height:  details.Block.Height,
+			Time:    t,
+			Type:    details.TxType,
+			Account: account,
+			Amount:  changes[account],
+		}
+		if fee != 0 && account == feeAccount {
+			e.Fee = fee
+			e.Amount += fee
+		}
+		balances[account] += e.Net()
+		e.Balance = balances[account]
+		e.AccountName, _ = w.Manager.AccountName(addrmgrNs, account)
+
+		switch {
+		case details.TxType == stake.TxTypeSStx:
+			e.Category = ExportTicket
+		case details.TxType == stake.TxTypeSSGen:
+			e.Category = ExportVote
+		case details.TxType == stake.TxTypeSSRtx:
+			e.Category = ExportRevocation
+		case blockchain.IsCoinBaseTx(&details.MsgTx):
+			e.Category = ExportCoinbase
+		case e.Amount > 0:
+			e.Category = ExportReceive
+		case e.Amount < 0:
+			e.Category = ExportSend
+		default:
+			e.Category = ExportTransfer
+		}
+		exports = append(exports, e)
+	}
+	return exports
+}
+
+// ExportTransactions returns the effects of every transaction recorded by the
+// wallet on the balances of the accounts, in the order the transactions were
+// mined followed by unmined transactions.  Running balances are computed over
+// the full history, which does not include transactions removed by history
+// pruning.  If account is non-nil, only the effects on this account are
+// returned.  When fiat is true, each effect is valued with the price of a coin
+// at the time of the transaction, in the default currency of the wallet's price
+// source.
+func (w *Wallet) ExportTransactions(account *uint32, fiat bool) ([]ExportedTransaction, error) {
+	if fiat && w.PriceSource() == nil {
+		const str = "no price source is configured for fiat valuation"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	var exports []ExportedTransaction
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		if account != nil {
+			_, err := w.Manager.AccountName(dbtx.ReadBucket(waddrmgrNamespaceKey), *account)
+			if err != nil {
+				return err
+			}
+		}
+
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		balances := make(map[uint32]hcutil.Amount)
+		rangeFn := func(details []udb.TxDetails) (bool, error) {
+			for i := range details {
+				exports = w.exportDetails(dbtx, &details[i], balances, exports)
+			}
+			return false, nil
+		}
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1, rangeFn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if account != nil {
+		filtered := exports[:0]
+		for _, e := range exports {
+			if e.Account == *account {
+				filtered = append(filtered, e)
+			}
+		}
+		exports = filtered
+	}
+
+	// Prices are queried outside of the database transaction as the price
+	// source may be slow to respond.
+	if fiat {
+		for i := range exports {
+			e := &exports[i]
+			e.FiatPrice, e.FiatCurrency, err = w.FiatPrice("", e.Time)
+			if err != nil {
+				return nil, err
+			}
+			e.FiatValue = e.Net().ToCoin() * e.FiatPrice
+		}
+	}
+	return exports, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+// fixedPriceSource is a PriceSource with the same price at all times.
+type fixedPriceSource float64
+
+func (fixedPriceSource) Currency() string { return "USD" }
+
+func (s fixedPriceSource) Price(string, time.Time) (float64, error) {
+	return float64(s), nil
+}
+
+func TestExportTransactions(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	_, err := w.ExportTransactions(nil, true)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("fiat valuation without a price source returned error %v", err)
+	}
+
+	spend := addSpendWithChange(t, w)
+	w.SetPriceSource(fixedPriceSource(2))
+	account := uint32(udb.DefaultAccountNum)
+	exports, err := w.ExportTransactions(&account, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The funding transaction spends no wallet outputs, so its fee is
+	// unknown.  The spend pays 3 coins and a fee of 0.01 coins.  Unmined
+	// transactions are not exported in any particular order, but the final
+	// balance includes both.
+	want := map[chainhash.Hash]struct {
+		category ExportCategory
+		amount   hcutil.Amount
+		fee      hcutil.Amount
+	}{
+		spend.TxIn[0].PreviousOutPoint.Hash: {ExportReceive, 10e8, 0},
+		spend.TxHash():                      {ExportSend, -3e8, 1e6},
+	}
+	if len(exports) != len(want) {
+		t.Fatalf("exported %d transactions, want %d", len(exports), len(want))
+	}
+	for i, e := range exports {
+		x, ok := want[e.Hash]
+		if !ok {
+			t.Errorf("exported unknown transaction %v", &e.Hash)
+			continue
+		}
+		if e.Category != x.category || e.Amount != x.amount || e.Fee != x.fee {
+			t.Errorf("export %d is %v %v fee %v, want %v %v fee %v", i,
+				e.Category, e.Amount, e.Fee, x.category, x.amount, x.fee)
+		}
+		if e.Height != -1 || e.AccountName != "default" {
+			t.Errorf("export %d has height %d and account %q", i,
+				e.Height, e.AccountName)
+		}
+		if e.FiatCurrency != "USD" || e.FiatPrice != 2 ||
+			e.FiatValue != e.Net().ToCoin()*2 {
+			t.Errorf("export %d valued at %v %v (%v)", i, e.FiatValue,
+				e.FiatCurrency, e.FiatPrice)
+		}
+	}
+	if balance := exports[1].Balance; balance != 699e6 {
+		t.Errorf("final balance is %v, want %v", balance, hcutil.Amount(699e6))
+	}
+
+	other := uint32(2)
+	if _, err := w.ExportTransactions(&other, false); err == nil {
+		t.Error("exported the transactions of a nonexistent account")
+	}
+}
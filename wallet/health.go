@@ -0,0 +1,271 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/omnilib"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// HealthCheckTimeout is the maximum duration a health check waits on hcd or the
+// omni engine before reporting it as unresponsive.
+const HealthCheckTimeout = 5 * time.Second
+
+// healthProbeCacheDuration is the duration the results of querying hcd and
+// the omni engine are reused by later health checks.  Health checks may be
+// polled frequently by monitoring services, and every poll would otherwise
+// issue an RPC to hcd and probe the omni engine.
+const healthProbeCacheDuration = 3 * time.Second
+
+// HealthStatus describes the result of a health check.  Statuses are ordered by
+// severity, and the numeric values are stable so they may be used as
+// machine-readable status codes.
+type HealthStatus int
+
+// Health statuses.
+const (
+	// HealthOK reports that the checked component is working.
+	HealthOK HealthStatus = 0
+
+	// HealthDegraded reports that the checked component is impaired, but
+	// the wallet can continue to serve most requests.
+	HealthDegraded HealthStatus = 1
+
+	// HealthUnavailable reports that the checked component is not working
+	// and the wallet should be restarted or alerted on.
+	HealthUnavailable HealthStatus = 2
+)
+
+// String returns the name of the status.
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	case HealthUnavailable:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthCheck is the result of checking a single component of the wallet.
+type HealthCheck struct {
+	Name   string
+	Status HealthStatus
+	Detail string
+}
+
+// HealthReport describes the health of the wallet and the services it depends
+// on.  Status is the most severe status of all checks.
+type HealthReport struct {
+	Status         HealthStatus
+	ChainConnected bool
+	WalletHeight   int32
+	ChainHeight    int64
+	BlocksBehind   int64 // -1 if the chain height is unknown
+	Synced         bool
+	DBReadable     bool
+	Unlocked       bool
+	OmniResponsive bool // false if omni is disabled
+	Checks         []HealthCheck
+}
+
+func (r *HealthReport) add(name string, status HealthStatus, detail string) {
+	r.Checks = append(r.Checks, HealthCheck{name, status, detail})
+	if status > r.Status {
+		r.Status = status
+	}
+}
+
+// healthProbes caches the results of the health checks that query hcd, write
+// to the database, and probe the omni engine.
+type healthProbes struct {
+	mu sync.Mutex
+
+	// bestBlockClient is the client the cached best block was received
+	// from, so the result is not reused after the client is replaced.
+	// bestBlockNotified is set when the height was recorded from a
+	// blockconnected notification, which is kept until the next
+	// notification instead of expiring.
+	bestBlockClient   ChainClient
+	bestBlockHeight   int64
+	bestBlockErr      error
+	bestBlockTime     time.Time
+	bestBlockNotified bool
+
+	dbErr  error
+	dbTime time.Time
+
+	omniErr  error
+	omniTime time.Time
+}
+
+// blockConnected records the height of a block connected to hcd's main chain
+// as reported by a notification from chainClient.
+func (p *healthProbes) blockConnected(chainClient ChainClient, height int64) {
+	p.mu.Lock()
+	p.bestBlockClient = chainClient
+	p.bestBlockHeight, p.bestBlockErr = height, nil
+	p.bestBlockTime = time.Now()
+	p.bestBlockNotified = true
+	p.mu.Unlock()
+}
+
+// chainHeight returns the height of hcd's best block.  The height recorded from
+// the most recent blockconnected notification of chainClient is used when
+// there is one.  Otherwise, such as before any block has been connected since
+// the client connected, chainClient is queried unless a result from the same
+// client is recent enough to be reused.
+func (p *healthProbes) chainHeight(chainClient ChainClient) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.bestBlockClient == chainClient && (p.bestBlockNotified ||
+		time.Since(p.bestBlockTime) < healthProbeCacheDuration) {
+		return p.bestBlockHeight, p.bestBlockErr
+	}
+
+	type bestBlock struct {
+		height int64
+		err    error
+	}
+	c := make(chan bestBlock, 1)
+	go func() {
+		var b bestBlock
+		_, b.height, b.err = chainClient.GetBestBlock()
+		c <- b
+	}()
+	var b bestBlock
+	select {
+	case b = <-c:
+	case <-time.After(HealthCheckTimeout):
+		b.err = errors.New("timed out querying the best block from hcd")
+	}
+
+	p.bestBlockClient = chainClient
+	p.bestBlockHeight, p.bestBlockErr = b.height, b.err
+	p.bestBlockTime = time.Now()
+	p.bestBlockNotified = false
+	return b.height, b.err
+}
+
+// probeDB checks that the wallet's namespaces can be read from db unless a
+// recent result can be reused.  Health checks must never write to the
+// database: every commit advances the replication journal, which would make
+// replica followers copy the database after each poll.
+func (p *healthProbes) probeDB(db walletdb.DB) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Since(p.dbTime) < healthProbeCacheDuration {
+		return p.dbErr
+	}
+	p.dbErr = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		for _, key := range [][]byte{waddrmgrNamespaceKey, wtxmgrNamespaceKey} {
+			if tx.ReadBucket(key) == nil {
+				return fmt.Errorf("missing %s namespace", key)
+			}
+		}
+		return nil
+	})
+	p.dbTime = time.Now()
+	return p.dbErr
+}
+
+// probeOmni probes the omni engine unless a recent result can be reused.
+func (p *healthProbes) probeOmni() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Since(p.omniTime) < healthProbeCacheDuration {
+		return p.omniErr
+	}
+	p.omniErr = omnilib.Probe(HealthCheckTimeout)
+	p.omniTime = time.Now()
+	return p.omniErr
+}
+
+// CheckHealth checks the connection to hcd, how far the wallet is behind hcd's
+// best block, whether the database accepts writes, whether the wallet is
+// unlocked, and the responsiveness of the omni engine if it is enabled.  The
+// wallet is reported as synced when it is at most maxBlocksBehind blocks behind
+// hcd.
+//
+// hcd's best block height is taken from blockconnected notifications, and hcd
+// is only queried before the first notification is received.  The results of
+// querying hcd, writing to the database, and probing the omni engine are
+// reused for a few seconds, so frequent health checks add little load and do
+// not contend with the wallet's own database writers.
+func (w *Wallet) CheckHealth(maxBlocksBehind int64) *HealthReport {
+	r := &HealthReport{BlocksBehind: -1}
+	_, r.WalletHeight = w.MainChainTip()
+
+	chainClient := w.ChainClient()
+	if chainClient == nil || chainClient.Disconnected() {
+		r.add("chain", HealthUnavailable, "not connected to hcd")
+	} else {
+		height, err := w.healthProbes.chainHeight(chainClient)
+		if err != nil {
+			r.add("chain", HealthUnavailable, err.Error())
+		} else {
+			r.ChainConnected = true
+			r.ChainHeight = height
+			r.add("chain", HealthOK, "")
+		}
+	}
+
+	if r.ChainConnected {
+		r.BlocksBehind = r.ChainHeight - int64(r.WalletHeight)
+		if r.BlocksBehind < 0 {
+			r.BlocksBehind = 0
+		}
+		r.Synced = r.BlocksBehind <= maxBlocksBehind
+		if r.Synced {
+			r.add("sync", HealthOK, "")
+		} else {
+			r.add("sync", HealthDegraded, fmt.Sprintf("%d blocks behind hcd",
+				r.BlocksBehind))
+		}
+	}
+
+	err := w.healthProbes.probeDB(w.db)
+	if err != nil {
+		r.add("database", HealthUnavailable, err.Error())
+	} else {
+		r.DBReadable = true
+		r.add("database", HealthOK, "")
+	}
+
+	// Votes can not be signed by a locked wallet, so a locked voting wallet
+	// misses its tickets.
+	r.Unlocked = !w.Locked()
+	switch {
+	case r.Unlocked:
+		r.add("unlocked", HealthOK, "")
+	case w.VotingEnabled():
+		r.add("unlocked", HealthDegraded, "voting wallet is locked")
+	default:
+		r.add("unlocked", HealthOK, "locked")
+	}
+
+	if w.EnableOmni() {
+		err := w.healthProbes.probeOmni()
+		if err != nil {
+			r.add("omni", HealthDegraded, err.Error())
+		} else {
+			r.OmniResponsive = true
+			r.add("omni", HealthOK, "")
+		}
+	}
+
+	return r
+}
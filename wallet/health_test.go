@@ -0,0 +1,116 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestCheckHealthCachesBestBlock(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	c := testhelpers.NewMockChainClient()
+	c.BlockHashes = []chainhash.Hash{{0}, {1}}
+	w.chainClientLock.Lock()
+	w.chainClient = c
+	w.chainClientLock.Unlock()
+
+	r := w.CheckHealth(6)
+	if !r.ChainConnected || r.ChainHeight != 1 {
+		t.Fatalf("chain connected %v at height %d, want connected at height 1",
+			r.ChainConnected, r.ChainHeight)
+	}
+	if !r.DBReadable {
+		t.Error("database is not readable")
+	}
+
+	// The best block is not queried again until the cached result expires.
+	c.BlockHashes = append(c.BlockHashes, chainhash.Hash{2})
+	r = w.CheckHealth(6)
+	if r.ChainHeight != 1 {
+		t.Errorf("chain height %d, want cached height 1", r.ChainHeight)
+	}
+
+	// A result from a replaced client is not reused.
+	c2 := testhelpers.NewMockChainClient()
+	c2.BlockHashes = c.BlockHashes
+	w.chainClientLock.Lock()
+	w.chainClient = c2
+	w.chainClientLock.Unlock()
+	r = w.CheckHealth(6)
+	if r.ChainHeight != 2 {
+		t.Errorf("chain height %d after replacing the client, want 2",
+			r.ChainHeight)
+	}
+}
+
+func TestCheckHealthNotifiedBestBlock(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	c := testhelpers.NewMockChainClient()
+	c.BlockHashes = []chainhash.Hash{{0}, {1}}
+	w.chainClientLock.Lock()
+	w.chainClient = c
+	w.chainClientLock.Unlock()
+
+	// The height of a connected block is used instead of querying hcd, even
+	// after a queried result would have expired.
+	w.healthProbes.blockConnected(c, 5)
+	w.healthProbes.mu.Lock()
+	w.healthProbes.bestBlockTime = time.Now().Add(-2 * healthProbeCacheDuration)
+	w.healthProbes.mu.Unlock()
+	r := w.CheckHealth(6)
+	if !r.ChainConnected || r.ChainHeight != 5 {
+		t.Fatalf("chain connected %v at height %d, want connected at the "+
+			"notified height 5", r.ChainConnected, r.ChainHeight)
+	}
+
+	// A height notified by a replaced client is not reused.
+	c2 := testhelpers.NewMockChainClient()
+	c2.BlockHashes = c.BlockHashes
+	w.chainClientLock.Lock()
+	w.chainClient = c2
+	w.chainClientLock.Unlock()
+	r = w.CheckHealth(6)
+	if r.ChainHeight != 1 {
+		t.Errorf("chain height %d after replacing the client, want queried "+
+			"height 1", r.ChainHeight)
+	}
+}
+
+// writeCountingDB counts the write transactions begun on a database.
+type writeCountingDB struct {
+	walletdb.DB
+	writes int
+}
+
+func (db *writeCountingDB) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	db.writes++
+	return db.DB.BeginReadWriteTx()
+}
+
+func TestCheckHealthDoesNotWrite(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	db := &writeCountingDB{DB: w.db}
+	w.db = db
+	defer func() { w.db = db.DB }()
+
+	r := w.CheckHealth(6)
+	if !r.DBReadable {
+		t.Error("database is not readable")
+	}
+	if db.writes != 0 {
+		t.Errorf("health check began %d write transactions", db.writes)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SendIdempotent calls send to create and publish a transaction, recording the
+// hash of the sent transaction and the serialized send parameters params for
+// the client-provided idempotency key.  If a transaction was already sent with
+// the key and the same parameters, send is not called and the hash of the
+// original transaction is returned instead, so that clients may safely retry
+// requests which timed out without paying twice.  Reusing the key with
+// different parameters errors with code ErrInput.  Concurrent sends with the
+// same key are serialized.
+//
+// The key is recorded after the transaction is published, so a send which is
+// interrupted by a crash between the two may be repeated by a retry.
+func (w *Wallet) SendIdempotent(key string, params []byte, send func() (*chainhash.Hash, error)) (*chainhash.Hash, error) {
+	// Wait for any send in progress with the same key to finish before
+	// checking whether the key is recorded.
+	for {
+		w.idempotentSendsMu.Lock()
+		inProgress, ok := w.idempotentSends[key]
+		if !ok {
+			done := make(chan struct{})
+			w.idempotentSends[key] = done
+			w.idempotentSendsMu.Unlock()
+			defer func() {
+				w.idempotentSendsMu.Lock()
+				delete(w.idempotentSends, key)
+				w.idempotentSendsMu.Unlock()
+				close(done)
+			}()
+			break
+		}
+		w.idempotentSendsMu.Unlock()
+		<-inProgress
+	}
+
+	var sent *chainhash.Hash
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		sent, err = w.TxStore.IdempotencyKeyTx(txmgrNs, key, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sent != nil {
+		log.Infof("Transaction %v was already sent with idempotency key %q",
+			sent, key)
+		return sent, nil
+	}
+
+	txHash, err := send()
+	if err != nil {
+		return nil, err
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.PutIdempotencyKey(txmgrNs, key, params, txHash)
+	})
+	if err != nil {
+		// The transaction was sent, so its hash is still returned.
+		log.Errorf("Failed to record idempotency key %q of transaction %v: %v",
+			key, txHash, err)
+	}
+	return txHash, nil
+}
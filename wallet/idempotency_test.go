@@ -0,0 +1,49 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+)
+
+func TestSendIdempotent(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	var sends int
+	sent := chainhash.Hash{1}
+	send := func() (*chainhash.Hash, error) {
+		sends++
+		return &sent, nil
+	}
+
+	params := []byte(`["sendtoaddress",{"addr":1}]`)
+	for i := 0; i < 2; i++ {
+		txHash, err := w.SendIdempotent("key", params, send)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *txHash != sent {
+			t.Errorf("send %d returned %v, want %v", i, txHash, &sent)
+		}
+	}
+	if sends != 1 {
+		t.Errorf("transaction sent %d times, want once", sends)
+	}
+
+	// Reusing the key with different parameters does not send another
+	// transaction.
+	_, err := w.SendIdempotent("key", []byte(`["sendtoaddress",{"addr":2}]`), send)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("reusing the key with different parameters returned %v, "+
+			"want ErrInput", err)
+	}
+	if sends != 1 {
+		t.Errorf("transaction sent %d times, want once", sends)
+	}
+}
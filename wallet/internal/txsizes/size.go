@@ -74,8 +74,47 @@ const (
 	P2PKHOutputSize = 8 + 2 + 1 + P2PKHPkScriptSize
 
 	P2PKHAltOutputSize = 8 + 2 + 1 + P2PKHAltScriptSize
+
+	// P2SHPkScriptSize is the size of a transaction output script that
+	// pays to a script hash.  It is calculated as:
+	//
+	//   - OP_HASH160
+	//   - OP_DATA_20
+	//   - 20 bytes script hash
+	//   - OP_EQUAL
+	P2SHPkScriptSize = 1 + 1 + 20 + 1
+
+	// P2SHOutputSize is the serialize size of a transaction output with a
+	// P2SH output script.  It is calculated as:
+	//
+	//   - 8 bytes output value
+	//   - 2 bytes version
+	//   - 1 byte compact int encoding value 23
+	//   - 23 bytes P2SH output script
+	P2SHOutputSize = 8 + 2 + 1 + P2SHPkScriptSize
 )
 
+// RedeemP2SHMultisigInputSize returns the worst case (largest) serialize size
+// of a transaction input redeeming a P2SH multisig output with nRequired
+// signatures and a redeem script of redeemScriptSize bytes.  The signature
+// script is calculated as:
+//
+//   - nRequired times OP_DATA_73 and 72 bytes DER signature + 1 byte sighash
+//   - the redeem script push opcode, up to OP_PUSHDATA2 and 2 bytes length
+//   - the redeem script
+func RedeemP2SHMultisigInputSize(nRequired, redeemScriptSize int) int {
+	push := 1
+	switch {
+	case redeemScriptSize > 0xff:
+		push = 3
+	case redeemScriptSize >= txscript.OP_PUSHDATA1:
+		push = 2
+	}
+	sigScriptSize := nRequired*(1+73) + push + redeemScriptSize
+	return 32 + 4 + 1 + 8 + 4 + 4 +
+		wire.VarIntSerializeSize(uint64(sigScriptSize)) + sigScriptSize + 4
+}
+
 // EstimateSerializeSize returns a worst case serialize size estimate for a
 // signed transaction that spends input Scripts
 // and contains each transaction output from txOuts.  The estimated size is
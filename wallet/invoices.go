@@ -0,0 +1,228 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// invoiceChild returns the invoice branch child index for an invoice id.  The
+// index is taken from an HMAC-SHA256 of the id and a counter, keyed by the
+// serialized invoice branch extended public key, so that watching-only wallets
+// derive the same addresses.  The counter is incremented to resolve collisions
+// with children already derived for other invoices.
+func invoiceChild(key []byte, id string, counter uint32) uint32 {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	var c [4]byte
+	binary.BigEndian.PutUint32(c[:], counter)
+	mac.Write(c[:])
+	return binary.BigEndian.Uint32(mac.Sum(nil)) &^ hdkeychain.HardenedKeyStart
+}
+
+// invoiceBranchXpub returns the extended public key of an account's invoice
+// branch.
+func (w *Wallet) invoiceBranchXpub(dbtx walletdb.ReadTx, account uint32) (*hdkeychain.ExtendedKey, error) {
+	acctXpub, err := w.Manager.AccountExtendedPubKey(dbtx, account)
+	if err != nil {
+		return nil, err
+	}
+	return acctXpub.Child(udb.InvoiceBranch)
+}
+
+// InvoiceAddress returns the address of an account which receives payments for
+// an invoice.  The address is derived deterministically from the invoice id
+// under the account's invoice branch, and the mapping is recorded so payments
+// can later be looked up by invoice id using InvoicePayments.  Requesting the
+// address of an invoice again returns the recorded address.
+//
+// Invoice addresses are only supported by ECDSA accounts.
+func (w *Wallet) InvoiceAddress(account uint32, id string) (hcutil.Address, error) {
+	var addr *hcutil.AddressPubKeyHash
+	var created bool
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		inv, err := w.Manager.FetchInvoice(ns, id)
+		if err != nil {
+			return err
+		}
+		if inv != nil && inv.Account != account {
+			const str = "invoice address was derived for another account"
+			return apperrors.E{ErrorCode: apperrors.ErrDuplicate, Description: str, Err: nil}
+		}
+		branchXpub, err := w.invoiceBranchXpub(tx, account)
+		if err != nil {
+			return err
+		}
+		if inv != nil {
+			addr, err = deriveChildAddress(branchXpub, inv.Child, w.chainParams)
+			return err
+		}
+
+		key, err := branchXpub.String()
+		if err != nil {
+			return err
+		}
+		for counter := uint32(0); ; counter++ {
+			child := invoiceChild([]byte(key), id, counter)
+			addr, err = deriveChildAddress(branchXpub, child, w.chainParams)
+			if err == hdkeychain.ErrInvalidChild {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if w.Manager.ExistsAddress(ns, addr) {
+				continue
+			}
+			created = true
+			return w.Manager.PutInvoice(ns, &udb.Invoice{
+				ID:      id,
+				Account: account,
+				Child:   child,
+				Created: time.Now(),
+			}, addr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		if client := w.ChainClient(); client != nil {
+			err := client.LoadTxFilter(false, []hcutil.Address{addr}, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return addr, nil
+}
+
+// InvoicePayment describes a transaction paying an invoice address.
+type InvoicePayment struct {
+	TxHash        chainhash.Hash
+	Amount        hcutil.Amount
+	BlockHash     chainhash.Hash
+	BlockHeight   int32 // -1 if unmined
+	Confirmations int32
+}
+
+// InvoicePayments describes the payments received for an invoice.  Received is
+// the total amount of the payments with at least the requested number of
+// confirmations, while Payments includes every payment.
+type InvoicePayments struct {
+	Invoice  udb.Invoice
+	Address  hcutil.Address
+	Received hcutil.Amount
+	Payments []InvoicePayment
+}
+
+// InvoicePayments returns the payments received by the address of an invoice
+// returned by InvoiceAddress.
+func (w *Wallet) InvoicePayments(id string, minConf int32) (*InvoicePayments, error) {
+	var res *InvoicePayments
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+
+		inv, err := w.Manager.FetchInvoice(addrmgrNs, id)
+		if err != nil {
+			return err
+		}
+		if inv == nil {
+			const str = "no address was derived for the invoice"
+			return apperrors.E{ErrorCode: apperrors.ErrValueNoExists, Description: str, Err: nil}
+		}
+		branchXpub, err := w.invoiceBranchXpub(tx, inv.Account)
+		if err != nil {
+			return err
+		}
+		addr, err := deriveChildAddress(branchXpub, inv.Child, w.chainParams)
+		if err != nil {
+			return err
+		}
+		res = &InvoicePayments{Invoice: *inv, Address: addr}
+
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		details, err := w.TxStore.AddressTxDetails(txmgrNs, addr)
+		if err != nil {
+			return err
+		}
+		addrStr := addr.EncodeAddress()
+		for _, detail := range details {
+			var amount hcutil.Amount
+			for _, cred := range detail.Credits {
+				pkVersion := detail.MsgTx.TxOut[cred.Index].Version
+				pkScript := detail.MsgTx.TxOut[cred.Index].PkScript
+				_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkVersion,
+					pkScript, w.chainParams)
+				// An error creating addresses from the output script only
+				// indicates a non-standard script, so ignore this credit.
+				if err != nil {
+					continue
+				}
+				for _, a := range addrs {
+					if addrStr == a.EncodeAddress() {
+						amount += cred.Amount
+						break
+					}
+				}
+			}
+			if amount == 0 {
+				continue
+			}
+			confs := confirms(detail.Block.Height, tipHeight)
+			res.Payments = append(res.Payments, InvoicePayment{
+				TxHash:        detail.Hash,
+				Amount:        amount,
+				BlockHash:     detail.Block.Hash,
+				BlockHeight:   detail.Block.Height,
+				Confirmations: confs,
+			})
+			if confs >= minConf {
+				res.Received += amount
+			}
+		}
+		return nil
+	})
+	return res, err
+}
+
+// invoiceAddresses returns the addresses derived for every recorded invoice.
+func (w *Wallet) invoiceAddresses(dbtx walletdb.ReadTx) ([]hcutil.Address, error) {
+	ns := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	branchXpubs := make(map[uint32]*hdkeychain.ExtendedKey)
+	var addrs []hcutil.Address
+	err := w.Manager.ForEachInvoice(ns, func(inv *udb.Invoice) error {
+		branchXpub, ok := branchXpubs[inv.Account]
+		if !ok {
+			var err error
+			branchXpub, err = w.invoiceBranchXpub(dbtx, inv.Account)
+			if err != nil {
+				return err
+			}
+			branchXpubs[inv.Account] = branchXpub
+		}
+		addr, err := deriveChildAddress(branchXpub, inv.Child, w.chainParams)
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, addr)
+		return nil
+	})
+	return addrs, err
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// MigratedAccount describes a BIP0044 account exported from another HD wallet,
+// such as dcrwallet or btcwallet, to be recreated in this wallet.
+type MigratedAccount struct {
+	Number uint32
+	Name   string
+
+	// LastUsedExternal and LastUsedInternal are the last used child indexes
+	// of the account's branches, or ^uint32(0) when no addresses were used.
+	LastUsedExternal uint32
+	LastUsedInternal uint32
+}
+
+// ImportAccountMetadata recreates the names and address indexes of accounts
+// exported from another HD wallet.  Accounts are processed in increasing
+// account number.  Existing accounts are renamed, and missing accounts are
+// created, so exported account numbers must not skip any accounts.  Address
+// indexes of each branch are synced so that new addresses are not returned
+// before the last address used by the exporting wallet.
+//
+// The wallet must be unlocked to create accounts.
+func (w *Wallet) ImportAccountMetadata(accounts []MigratedAccount) error {
+	accounts = append([]MigratedAccount(nil), accounts...)
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Number < accounts[j].Number
+	})
+
+	for i := range accounts {
+		a := &accounts[i]
+		if a.Number > udb.MaxAccountNum {
+			const str = "account number exceeds the maximum BIP0044 account"
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+
+		var lastAcct uint32
+		err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+			var err error
+			lastAcct, err = w.Manager.LastAccount(dbtx.ReadBucket(waddrmgrNamespaceKey))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case a.Number <= lastAcct:
+			name, err := w.AccountName(a.Number)
+			if err != nil {
+				return err
+			}
+			if a.Name != "" && a.Name != name {
+				err = w.RenameAccount(a.Number, a.Name)
+				if err != nil {
+					return err
+				}
+			}
+		case a.Number == lastAcct+1:
+			name := a.Name
+			if name == "" {
+				name = fmt.Sprintf("account%d", a.Number)
+			}
+			_, err = w.NextAccount(name, udb.AcctypeEc)
+			if err != nil {
+				return err
+			}
+		default:
+			str := fmt.Sprintf("exported accounts skip account %d", lastAcct+1)
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+
+		err = w.syncMigratedBranch(a.Number, udb.ExternalBranch, a.LastUsedExternal)
+		if err != nil {
+			return err
+		}
+		err = w.syncMigratedBranch(a.Number, udb.InternalBranch, a.LastUsedInternal)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncMigratedBranch records lastUsed as the last used child index of an
+// account branch, deriving the addresses through the gap limit after it.
+func (w *Wallet) syncMigratedBranch(account, branch, lastUsed uint32) error {
+	if lastUsed >= hdkeychain.HardenedKeyStart {
+		return nil
+	}
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		gapLimit := uint32(w.gapLimit)
+		err := w.Manager.SyncAccountToAddrIndex(ns, account,
+			minUint32(lastUsed+gapLimit, hdkeychain.HardenedKeyStart-1),
+			branch)
+		if err != nil {
+			return err
+		}
+		err = w.Manager.MarkUsedChildIndex(tx, account, branch, lastUsed)
+		if err != nil {
+			return err
+		}
+
+		props, err := w.Manager.AccountProperties(ns, account)
+		if err != nil {
+			return err
+		}
+		lastReturned := props.LastReturnedExternalIndex
+
+		w.addressBuffersMu.Lock()
+		defer w.addressBuffersMu.Unlock()
+		acctData, ok := w.addressBuffers[account]
+		if !ok {
+			const str = "account not found"
+			return apperrors.E{ErrorCode: apperrors.ErrAccountNotFound, Description: str, Err: nil}
+		}
+		buf := &acctData.albExternal
+		if branch == udb.InternalBranch {
+			buf = &acctData.albInternal
+			lastReturned = props.LastReturnedInternalIndex
+		}
+		buf.lastUsed = lastUsed
+		buf.cursor = lastReturned - lastUsed
+		return nil
+	})
+}
@@ -34,7 +34,7 @@ func (w *Wallet) ImportP2SHRedeemScript(script []byte) (*hcutil.AddressScriptHas
 		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
 		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
 
-		err := w.TxStore.InsertTxScript(txmgrNs, script)
+		err := w.insertTxScript(txmgrNs, script)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,475 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	h "github.com/HcashOrg/hcwallet/internal/helpers"
+	"github.com/HcashOrg/hcwallet/wallet/internal/txsizes"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// MaxMultisigKeys is the maximum number of keys, including the wallet's own,
+// of a multisig account.  Redeem scripts of more compressed pubkeys exceed the
+// maximum size of a script element and could never be spent.
+const MaxMultisigKeys = 15
+
+// multisigBranchName returns the name of the additional external branch from
+// which the wallet's keys of a multisig account are derived.
+func multisigBranchName(name string) string {
+	return "multisig:" + name
+}
+
+// CreateMultisigAccount records a new m-of-n multisig account shared with
+// cosigners identified by their extended public keys.  The wallet's keys of
+// the account are derived from a new additional external branch of account,
+// whose extended public key must in turn be given to each cosigner.  Every
+// address index of the multisig account pays to a script requiring nRequired
+// signatures from the child keys at that index of the wallet's branch and
+// each cosigner key, sorted as described by BIP0067 so that all cosigners
+// derive identical addresses.
+func (w *Wallet) CreateMultisigAccount(name string, account uint32, nRequired int,
+	cosigners []string) (*udb.MultisigAccount, error) {
+
+	if len(cosigners) == 0 {
+		const str = "multisig accounts require at least one cosigner"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if len(cosigners)+1 > MaxMultisigKeys {
+		const str = "too many multisig cosigners"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if nRequired < 1 || nRequired > len(cosigners)+1 {
+		const str = "required signatures must be between one and the " +
+			"number of keys"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	seen := make(map[string]struct{}, len(cosigners))
+	for _, xpub := range cosigners {
+		key, err := hdkeychain.NewKeyFromString(xpub)
+		if err != nil {
+			return nil, apperrors.E{ErrorCode: apperrors.ErrInput,
+				Description: "invalid cosigner extended public key", Err: err}
+		}
+		switch {
+		case key.IsPrivate():
+			const str = "cosigner keys must be extended public keys"
+			return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		case !key.IsForNet(w.chainParams):
+			const str = "cosigner extended public key is for another network"
+			return nil, apperrors.E{ErrorCode: apperrors.ErrWrongNet, Description: str, Err: nil}
+		case key.GetAlgType() != udb.AcctypeEc:
+			const str = "cosigner keys must be ECDSA extended public keys"
+			return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+		if _, ok := seen[xpub]; ok {
+			const str = "duplicate cosigner extended public key"
+			return nil, apperrors.E{ErrorCode: apperrors.ErrDuplicate, Description: str, Err: nil}
+		}
+		seen[xpub] = struct{}{}
+	}
+
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		a, err := w.Manager.FetchMultisigAccount(ns, name)
+		if err != nil {
+			return err
+		}
+		if a != nil {
+			const str = "multisig account already exists"
+			return apperrors.E{ErrorCode: apperrors.ErrDuplicate, Description: str, Err: nil}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := w.NewExternalBranch(account, multisigBranchName(name))
+	if err != nil {
+		return nil, err
+	}
+	a := &udb.MultisigAccount{
+		Name:      name,
+		Account:   account,
+		Branch:    branch,
+		Required:  uint8(nRequired),
+		Cosigners: cosigners,
+		Created:   time.Now(),
+	}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		ourXpub, err := w.multisigBranchXpub(tx, a)
+		if err != nil {
+			return err
+		}
+		ourXpubStr, err := ourXpub.String()
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[ourXpubStr]; ok {
+			const str = "cosigner keys may not include the wallet's key"
+			return apperrors.E{ErrorCode: apperrors.ErrDuplicate, Description: str, Err: nil}
+		}
+		return w.Manager.PutMultisigAccount(ns, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Created %d-of-%d multisig account %q", nRequired,
+		len(cosigners)+1, name)
+	return a, nil
+}
+
+// multisigBranchXpub returns the extended public key of the branch from which
+// the wallet's keys of a multisig account are derived.
+func (w *Wallet) multisigBranchXpub(dbtx walletdb.ReadTx, a *udb.MultisigAccount) (*hdkeychain.ExtendedKey, error) {
+	acctXpub, err := w.Manager.AccountExtendedPubKey(dbtx, a.Account)
+	if err != nil {
+		return nil, err
+	}
+	return acctXpub.Child(a.Branch)
+}
+
+// multisigScript returns the redeem script of a multisig account at an address
+// index.  hdkeychain.ErrInvalidChild is returned if any key is invalid at the
+// index, which must then be skipped.
+func (w *Wallet) multisigScript(ourXpub *hdkeychain.ExtendedKey, a *udb.MultisigAccount,
+	index uint32) ([]byte, error) {
+
+	keys := make([][]byte, 0, len(a.Cosigners)+1)
+	addChild := func(xpub *hdkeychain.ExtendedKey) error {
+		child, err := xpub.Child(index)
+		if err != nil {
+			return err
+		}
+		pk, err := child.ECPubKey()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, pk.SerializeCompressed())
+		return nil
+	}
+	err := addChild(ourXpub)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range a.Cosigners {
+		xpub, err := hdkeychain.NewKeyFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		err = addChild(xpub)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	addrs := make([]hcutil.Address, len(keys))
+	for i, k := range keys {
+		addrs[i], err = hcutil.NewAddressSecpPubKey(k, w.chainParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return txscript.MultiSigScript(addrs, int(a.Required))
+}
+
+// NewMultisigAddress derives the next address of a multisig account.  The
+// redeem script is imported so payments to the address are recognized as
+// multisig outputs of the wallet, and the address manager must be unlocked to
+// record it.
+func (w *Wallet) NewMultisigAddress(name string) (*hcutil.AddressScriptHash, error) {
+	var p2shAddr *hcutil.AddressScriptHash
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		a, err := w.Manager.FetchMultisigAccount(addrmgrNs, name)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			const str = "multisig account does not exist"
+			return apperrors.E{ErrorCode: apperrors.ErrValueNoExists, Description: str, Err: nil}
+		}
+		ourXpub, err := w.multisigBranchXpub(tx, a)
+		if err != nil {
+			return err
+		}
+
+		var script []byte
+		index := a.NextIndex
+		for ; index < hdkeychain.HardenedKeyStart; index++ {
+			script, err = w.multisigScript(ourXpub, a, index)
+			if err != hdkeychain.ErrInvalidChild {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if index >= hdkeychain.HardenedKeyStart {
+			return apperrors.E{ErrorCode: apperrors.ErrExhaustedAccount,
+				Description: "multisig account is exhausted", Err: nil}
+		}
+
+		// The wallet's key must be recorded for multisig outputs paying
+		// the script to be recognized, and for the wallet to sign their
+		// spends.
+		err = w.Manager.SyncAccountToAddrIndex(addrmgrNs, a.Account, index,
+			a.Branch)
+		if err != nil {
+			return err
+		}
+		err = w.insertTxScript(txmgrNs, script)
+		if err != nil {
+			return err
+		}
+		_, err = w.Manager.ImportScript(addrmgrNs, script)
+		if err != nil && !apperrors.IsError(err, apperrors.ErrDuplicateAddress) {
+			return err
+		}
+		p2shAddr, err = hcutil.NewAddressScriptHash(script, w.chainParams)
+		if err != nil {
+			return err
+		}
+
+		a.NextIndex = index + 1
+		return w.Manager.PutMultisigAccount(addrmgrNs, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if client := w.ChainClient(); client != nil {
+		err := client.LoadTxFilter(false, []hcutil.Address{p2shAddr}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p2shAddr, nil
+}
+
+// MultisigAddress describes an address derived for a multisig account.
+type MultisigAddress struct {
+	Index        uint32
+	Address      *hcutil.AddressScriptHash
+	RedeemScript []byte
+	Balance      hcutil.Amount
+}
+
+// MultisigAccountInfo describes a multisig account.  Xpub is the extended
+// public key of the wallet's branch, which cosigners must record.
+type MultisigAccountInfo struct {
+	udb.MultisigAccount
+	Xpub      string
+	Addresses []MultisigAddress
+	Balance   hcutil.Amount
+}
+
+// MultisigAccountInfo returns the keys of a multisig account and the derived
+// addresses with the amounts of their unspent outputs.
+func (w *Wallet) MultisigAccountInfo(name string) (*MultisigAccountInfo, error) {
+	var info *MultisigAccountInfo
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		a, err := w.Manager.FetchMultisigAccount(addrmgrNs, name)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			const str = "multisig account does not exist"
+			return apperrors.E{ErrorCode: apperrors.ErrValueNoExists, Description: str, Err: nil}
+		}
+		ourXpub, err := w.multisigBranchXpub(tx, a)
+		if err != nil {
+			return err
+		}
+		xpub, err := ourXpub.String()
+		if err != nil {
+			return err
+		}
+		info = &MultisigAccountInfo{MultisigAccount: *a, Xpub: xpub}
+
+		return w.forEachMultisigAddress(tx, ourXpub, a, func(addr *MultisigAddress,
+			credits []*udb.MultisigCredit) error {
+
+			for _, c := range credits {
+				addr.Balance += c.Amount
+			}
+			info.Addresses = append(info.Addresses, *addr)
+			info.Balance += addr.Balance
+			return nil
+		})
+	})
+	return info, err
+}
+
+// forEachMultisigAddress calls fn with each derived address of a multisig
+// account and its unspent multisig credits.
+func (w *Wallet) forEachMultisigAddress(dbtx walletdb.ReadTx, ourXpub *hdkeychain.ExtendedKey,
+	a *udb.MultisigAccount, fn func(*MultisigAddress, []*udb.MultisigCredit) error) error {
+
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+	for i := uint32(0); i < a.NextIndex; i++ {
+		script, err := w.multisigScript(ourXpub, a, i)
+		if err == hdkeychain.ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		p2shAddr, err := hcutil.NewAddressScriptHash(script, w.chainParams)
+		if err != nil {
+			return err
+		}
+		credits, err := w.TxStore.UnspentMultisigCreditsForAddress(txmgrNs, p2shAddr)
+		if err != nil {
+			return err
+		}
+		err = fn(&MultisigAddress{
+			Index:        i,
+			Address:      p2shAddr,
+			RedeemScript: script,
+		}, credits)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MultisigSpend is a transaction spending outputs of a multisig account.  The
+// transaction is complete when it holds the required signatures of every
+// input, otherwise it must be signed by further cosigners before publishing.
+type MultisigSpend struct {
+	Tx       *wire.MsgTx
+	Fee      hcutil.Amount
+	Complete bool
+}
+
+// CreateMultisigSpend creates and signs with the wallet's keys a transaction
+// paying outputs from the unspent outputs of a multisig account.  Change is
+// paid to a new address of the multisig account, and the relay fee is paid
+// for the worst case size of the fully signed transaction.
+func (w *Wallet) CreateMultisigSpend(name string, outputs []*wire.TxOut) (*MultisigSpend, error) {
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to sign multisig spends"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	relayFee := w.RelayFee()
+	var target hcutil.Amount
+	for _, out := range outputs {
+		err := txrules.CheckOutput(out, relayFee)
+		if err != nil {
+			return nil, err
+		}
+		target += hcutil.Amount(out.Value)
+	}
+
+	tx := wire.NewMsgTx()
+	for _, out := range outputs {
+		tx.AddTxOut(out)
+	}
+	prevScripts := make(map[wire.OutPoint][]byte)
+	var a *udb.MultisigAccount
+	var total, fee hcutil.Amount
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		a, err = w.Manager.FetchMultisigAccount(addrmgrNs, name)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			const str = "multisig account does not exist"
+			return apperrors.E{ErrorCode: apperrors.ErrValueNoExists, Description: str, Err: nil}
+		}
+		ourXpub, err := w.multisigBranchXpub(dbtx, a)
+		if err != nil {
+			return err
+		}
+
+		// Select unspent outputs in order of address index until the
+		// outputs and the fee of a transaction with change are paid.
+		inputSizes := 0
+		baseSize := func(nIn int) int {
+			return 12 + 2*wire.VarIntSerializeSize(uint64(nIn)) +
+				wire.VarIntSerializeSize(uint64(len(outputs)+1)) +
+				h.SumOutputSerializeSizes(outputs) + txsizes.P2SHOutputSize
+		}
+		errDone := errors.New("done")
+		err = w.forEachMultisigAddress(dbtx, ourXpub, a, func(addr *MultisigAddress,
+			credits []*udb.MultisigCredit) error {
+
+			pkScript, err := txscript.PayToAddrScript(addr.Address)
+			if err != nil {
+				return err
+			}
+			for _, c := range credits {
+				tx.AddTxIn(wire.NewTxIn(c.OutPoint, nil))
+				prevScripts[*c.OutPoint] = pkScript
+				total += c.Amount
+				inputSizes += txsizes.RedeemP2SHMultisigInputSize(
+					int(a.Required), len(addr.RedeemScript))
+				fee = FeeForSize(relayFee, baseSize(len(tx.TxIn))+inputSizes)
+				if total >= target+fee {
+					return errDone
+				}
+			}
+			return nil
+		})
+		if err == errDone {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return apperrors.E{ErrorCode: apperrors.ErrInput,
+			Description: "insufficient multisig account balance", Err: nil}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	change := total - target - fee
+	if !txrules.IsDustAmount(change, txsizes.P2SHPkScriptSize, relayFee) {
+		changeAddr, err := w.NewMultisigAddress(name)
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(changeAddr)
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(change), pkScript))
+	} else {
+		fee += change
+	}
+
+	sigErrs, err := w.SignTransaction(tx, txscript.SigHashAll, prevScripts,
+		nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultisigSpend{Tx: tx, Fee: fee, Complete: len(sigErrs) == 0}, nil
+}
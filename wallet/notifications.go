@@ -19,7 +19,6 @@ import (
 	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
 	"github.com/HcashOrg/hcd/txscript"
 	"github.com/HcashOrg/hcd/wire"
-	hcrpcclient "github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/apperrors"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
@@ -46,11 +45,21 @@ type NotificationServer struct {
 	currentTxNtfn     *TransactionNotifications
 	accountClients    []chan *AccountNotification
 	tipChangedClients []chan *MainTipChangedNotification
+	ticketClients     []chan *TicketNotification
+	voteClients       []chan *VoteNotification
+	omniClients       []chan *OmniTransactionNotification
 	confClients       []*ConfirmationNotificationsClient
 	mu                sync.Mutex // Only protects registered clients
 	wallet            *Wallet    // smells like hacks
+
 }
 
+// maxQueuedNotifications is the number of notifications buffered for each
+// client.  Notifications are queued without waiting on clients, so slow
+// clients never block the processing of blocks and transactions, and a client
+// which falls this far behind is disconnected by closing its channel.
+const maxQueuedNotifications = 256
+
 func newNotificationServer(wallet *Wallet) *NotificationServer {
 	return &NotificationServer{
 		wallet: wallet,
@@ -179,7 +188,7 @@ func makeTxSummary(dbtx walletdb.ReadTx, w *Wallet, details *udb.TxDetails) Tran
 	}
 }
 
-func makeTicketSummary(chainClient *hcrpcclient.Client, dbtx walletdb.ReadTx, w *Wallet, details *udb.TicketDetails) *TicketSummary {
+func makeTicketSummary(chainClient ChainClient, dbtx walletdb.ReadTx, w *Wallet, details *udb.TicketDetails) *TicketSummary {
 	var ticketStatus = TicketStatusLive
 
 	ticketTransactionDetails := makeTxSummary(dbtx, w, details.Ticket)
@@ -281,10 +290,10 @@ func (s *NotificationServer) notifyUnminedTransaction(dbtx walletdb.ReadTx, deta
 		log.Tracef("Notifying unmined tx notification while creating notification for blocks")
 	}
 
-	defer s.mu.Unlock()
 	s.mu.Lock()
-	clients := s.transactions
-	if len(clients) == 0 {
+	clients := len(s.transactions)
+	s.mu.Unlock()
+	if clients == 0 {
 		return
 	}
 
@@ -306,9 +315,7 @@ func (s *NotificationServer) notifyUnminedTransaction(dbtx walletdb.ReadTx, deta
 		UnminedTransactionHashes: unminedHashes,
 		NewBalances:              flattenBalanceMap(bals),
 	}
-	for _, c := range clients {
-		c <- n
-	}
+	s.sendTransactionNotification(n)
 }
 
 func (s *NotificationServer) notifyDetachedBlock(hash *chainhash.Hash) {
@@ -393,11 +400,8 @@ func (s *NotificationServer) sendAttachedBlockNotification() {
 	s.currentTxNtfn.UnminedTransactionHashes = unminedHashes
 	s.currentTxNtfn.NewBalances = flattenBalanceMap(bals)
 
-	s.mu.Lock()
-	for _, c := range s.transactions {
-		c <- s.currentTxNtfn
-	}
-	s.mu.Unlock()
+	n := s.currentTxNtfn
+	s.sendTransactionNotification(n)
 	s.currentTxNtfn = nil
 }
 
@@ -555,13 +559,14 @@ type TransactionNotificationsClient struct {
 }
 
 // TransactionNotifications returns a client for receiving
-// TransactionNotifiations notifications over a channel.  The channel is
-// unbuffered.
+// TransactionNotifiations notifications over a channel.  The channel buffers
+// up to maxQueuedNotifications notifications, and is closed if the client falls
+// further behind.
 //
 // When finished, the Done method should be called on the client to disassociate
 // it from the server.
 func (s *NotificationServer) TransactionNotifications() TransactionNotificationsClient {
-	c := make(chan *TransactionNotifications)
+	c := make(chan *TransactionNotifications, maxQueuedNotifications)
 	s.mu.Lock()
 	s.transactions = append(s.transactions, c)
 	s.mu.Unlock()
@@ -571,30 +576,40 @@ func (s *NotificationServer) TransactionNotifications() TransactionNotifications
 	}
 }
 
-// Done deregisters the client from the server and drains any remaining
-// messages.  It must be called exactly once when the client is finished
-// receiving notifications.
+// Done deregisters the client from the server and closes its channel, unless
+// the client was already disconnected for falling behind.  It must be called
+// exactly once when the client is finished receiving notifications.
 func (c *TransactionNotificationsClient) Done() {
-	go func() {
-		// Drain notifications until the client channel is removed from
-		// the server and closed.
-		for range c.C {
+	s := c.server
+	s.mu.Lock()
+	clients := s.transactions
+	for i, ch := range clients {
+		if c.C == ch {
+			clients[i] = clients[len(clients)-1]
+			s.transactions = clients[:len(clients)-1]
+			close(ch)
+			break
 		}
-	}()
-	go func() {
-		s := c.server
-		s.mu.Lock()
-		clients := s.transactions
-		for i, ch := range clients {
-			if c.C == ch {
-				clients[i] = clients[len(clients)-1]
-				s.transactions = clients[:len(clients)-1]
-				close(ch)
-				break
-			}
+	}
+	s.mu.Unlock()
+}
+
+// sendTransactionNotification queues n for every transaction notifications
+// client, disconnecting clients whose queues are full.
+func (s *NotificationServer) sendTransactionNotification(n *TransactionNotifications) {
+	s.mu.Lock()
+	clients := s.transactions[:0]
+	for _, c := range s.transactions {
+		select {
+		case c <- n:
+			clients = append(clients, c)
+		default:
+			log.Warnf("Disconnecting slow transaction notifications client")
+			close(c)
 		}
-		s.mu.Unlock()
-	}()
+	}
+	s.transactions = clients
+	s.mu.Unlock()
 }
 
 // AccountNotification contains properties regarding an account, such as its
@@ -609,10 +624,10 @@ type AccountNotification struct {
 }
 
 func (s *NotificationServer) notifyAccountProperties(props *udb.AccountProperties) {
-	defer s.mu.Unlock()
 	s.mu.Lock()
-	clients := s.accountClients
-	if len(clients) == 0 {
+	clients := len(s.accountClients)
+	s.mu.Unlock()
+	if clients == 0 {
 		return
 	}
 	n := &AccountNotification{
@@ -632,9 +647,7 @@ func (s *NotificationServer) notifyAccountProperties(props *udb.AccountPropertie
 		n.InternalKeyCount = minUint32(hdkeychain.HardenedKeyStart,
 			props.LastUsedInternalIndex+uint32(s.wallet.gapLimit))
 	}
-	for _, c := range clients {
-		c <- n
-	}
+	s.sendAccountNotification(n)
 }
 
 // AccountNotificationsClient receives AccountNotifications over the channel C.
@@ -644,10 +657,12 @@ type AccountNotificationsClient struct {
 }
 
 // AccountNotifications returns a client for receiving AccountNotifications over
-// a channel.  The channel is unbuffered.  When finished, the client's Done
-// method should be called to disassociate the client from the server.
+// a channel.  The channel buffers up to maxQueuedNotifications notifications,
+// and is closed if the client falls further behind.  When finished, the
+// client's Done method should be called to disassociate the client from the
+// server.
 func (s *NotificationServer) AccountNotifications() AccountNotificationsClient {
-	c := make(chan *AccountNotification)
+	c := make(chan *AccountNotification, maxQueuedNotifications)
 	s.mu.Lock()
 	s.accountClients = append(s.accountClients, c)
 	s.mu.Unlock()
@@ -657,28 +672,40 @@ func (s *NotificationServer) AccountNotifications() AccountNotificationsClient {
 	}
 }
 
-// Done deregisters the client from the server and drains any remaining
-// messages.  It must be called exactly once when the client is finished
-// receiving notifications.
+// Done deregisters the client from the server and closes its channel, unless
+// the client was already disconnected for falling behind.  It must be called
+// exactly once when the client is finished receiving notifications.
 func (c *AccountNotificationsClient) Done() {
-	go func() {
-		for range c.C {
+	s := c.server
+	s.mu.Lock()
+	clients := s.accountClients
+	for i, ch := range clients {
+		if c.C == ch {
+			clients[i] = clients[len(clients)-1]
+			s.accountClients = clients[:len(clients)-1]
+			close(ch)
+			break
 		}
-	}()
-	go func() {
-		s := c.server
-		s.mu.Lock()
-		clients := s.accountClients
-		for i, ch := range clients {
-			if c.C == ch {
-				clients[i] = clients[len(clients)-1]
-				s.accountClients = clients[:len(clients)-1]
-				close(ch)
-				break
-			}
+	}
+	s.mu.Unlock()
+}
+
+// sendAccountNotification queues n for every account notifications client,
+// disconnecting clients whose queues are full.
+func (s *NotificationServer) sendAccountNotification(n *AccountNotification) {
+	s.mu.Lock()
+	clients := s.accountClients[:0]
+	for _, c := range s.accountClients {
+		select {
+		case c <- n:
+			clients = append(clients, c)
+		default:
+			log.Warnf("Disconnecting slow account notifications client")
+			close(c)
 		}
-		s.mu.Unlock()
-	}()
+	}
+	s.accountClients = clients
+	s.mu.Unlock()
 }
 
 // MainTipChangedNotification describes processed changes to the main chain tip
@@ -700,11 +727,12 @@ type MainTipChangedNotificationsClient struct {
 }
 
 // MainTipChangedNotifications returns a client for receiving
-// MainTipChangedNotification over a channel.  The channel is unbuffered.  When
-// finished, the client's Done method should be called to disassociate the
-// client from the server.
+// MainTipChangedNotification over a channel.  The channel buffers up to
+// maxQueuedNotifications notifications, and is closed if the client falls
+// further behind.  When finished, the client's Done method should be called to
+// disassociate the client from the server.
 func (s *NotificationServer) MainTipChangedNotifications() MainTipChangedNotificationsClient {
-	c := make(chan *MainTipChangedNotification)
+	c := make(chan *MainTipChangedNotification, maxQueuedNotifications)
 	s.mu.Lock()
 	s.tipChangedClients = append(s.tipChangedClients, c)
 	s.mu.Unlock()
@@ -714,59 +742,308 @@ func (s *NotificationServer) MainTipChangedNotifications() MainTipChangedNotific
 	}
 }
 
-// Done deregisters the client from the server and drains any remaining
-// messages.  It must be called exactly once when the client is finished
-// receiving notifications.
+// Done deregisters the client from the server and closes its channel, unless
+// the client was already disconnected for falling behind.  It must be called
+// exactly once when the client is finished receiving notifications.
 func (c *MainTipChangedNotificationsClient) Done() {
-	go func() {
-		for range c.C {
+	s := c.server
+	s.mu.Lock()
+	clients := s.tipChangedClients
+	for i, ch := range clients {
+		if c.C == ch {
+			clients[i] = clients[len(clients)-1]
+			s.tipChangedClients = clients[:len(clients)-1]
+			close(ch)
+			break
 		}
-	}()
-	go func() {
-		s := c.server
-		s.mu.Lock()
-		clients := s.tipChangedClients
-		for i, ch := range clients {
-			if c.C == ch {
-				clients[i] = clients[len(clients)-1]
-				s.tipChangedClients = clients[:len(clients)-1]
-				close(ch)
-				break
-			}
+	}
+	s.mu.Unlock()
+}
+
+// sendMainTipChangedNotification queues n for every main chain tip
+// notifications client, disconnecting clients whose queues are full.
+func (s *NotificationServer) sendMainTipChangedNotification(n *MainTipChangedNotification) {
+	s.mu.Lock()
+	clients := s.tipChangedClients[:0]
+	for _, c := range s.tipChangedClients {
+		select {
+		case c <- n:
+			clients = append(clients, c)
+		default:
+			log.Warnf("Disconnecting slow main chain tip notifications client")
+			close(c)
 		}
-		s.mu.Unlock()
-	}()
+	}
+	s.tipChangedClients = clients
+	s.mu.Unlock()
 }
 
 func (s *NotificationServer) notifyMainChainTipChanged(n *MainTipChangedNotification) {
+	s.sendMainTipChangedNotification(n)
+
 	s.mu.Lock()
+	for _, c := range s.confClients {
+		// Confirmations are counted from the latest tip height only, so
+		// replace any height the client has not processed yet.
+		select {
+		case <-c.tips:
+		default:
+		}
+		c.tips <- n.NewHeight
+	}
+	s.mu.Unlock()
+}
 
-	for _, c := range s.tipChangedClients {
-		c <- n
+// TicketEvent describes a change in the lifecycle of a ticket.
+type TicketEvent int8
+
+// These constants describe the ticket lifecycle events.
+const (
+	// TicketEventPurchased is sent when a ticket purchase is first seen
+	// unmined and again when it is mined.
+	TicketEventPurchased TicketEvent = iota
+
+	// TicketEventLive is sent when a ticket matures and becomes eligible
+	// for selection.
+	TicketEventLive
+
+	// TicketEventVoted is sent when a vote spending the ticket is mined.
+	TicketEventVoted
+
+	// TicketEventMissed is sent when a selected ticket fails to vote.
+	TicketEventMissed
+
+	// TicketEventExpired is sent when a ticket expires without being
+	// selected.
+	TicketEventExpired
+
+	// TicketEventRevoked is sent when a revocation spending the ticket is
+	// mined.
+	TicketEventRevoked
+)
+
+// TicketNotification describes a lifecycle event of a ticket owned by the
+// wallet.
+type TicketNotification struct {
+	Ticket      chainhash.Hash
+	Event       TicketEvent
+	SpenderHash *chainhash.Hash // Vote or revocation, nil for other events
+	BlockHeight int32           // -1 for unmined purchases
+}
+
+// TicketNotificationsClient receives TicketNotifications over the channel C.
+type TicketNotificationsClient struct {
+	C      chan *TicketNotification
+	server *NotificationServer
+}
+
+// TicketNotifications returns a client for receiving TicketNotifications over
+// a channel.  The channel buffers up to maxQueuedNotifications notifications,
+// and is closed if the client falls further behind.  When finished, the
+// client's Done method should be called to disassociate the client from the
+// server.
+func (s *NotificationServer) TicketNotifications() TicketNotificationsClient {
+	c := make(chan *TicketNotification, maxQueuedNotifications)
+	s.mu.Lock()
+	s.ticketClients = append(s.ticketClients, c)
+	s.mu.Unlock()
+	return TicketNotificationsClient{
+		C:      c,
+		server: s,
 	}
+}
 
-	if len(s.confClients) > 0 {
-		var wg sync.WaitGroup
-		wg.Add(len(s.confClients))
-		for _, c := range s.confClients {
-			c := c
-			go func() {
-				c.process(n.NewHeight)
-				wg.Done()
-			}()
+// Done deregisters the client from the server and closes its channel, unless
+// the client was already disconnected for falling behind.  It must be called
+// exactly once when the client is finished receiving notifications.
+func (c *TicketNotificationsClient) Done() {
+	s := c.server
+	s.mu.Lock()
+	clients := s.ticketClients
+	for i, ch := range clients {
+		if c.C == ch {
+			clients[i] = clients[len(clients)-1]
+			s.ticketClients = clients[:len(clients)-1]
+			close(ch)
+			break
 		}
-		wg.Wait()
 	}
+	s.mu.Unlock()
+}
 
+// sendTicketNotification queues n for every ticket notifications client,
+// disconnecting clients whose queues are full.
+func (s *NotificationServer) sendTicketNotification(n *TicketNotification) {
+	s.mu.Lock()
+	clients := s.ticketClients[:0]
+	for _, c := range s.ticketClients {
+		select {
+		case c <- n:
+			clients = append(clients, c)
+		default:
+			log.Warnf("Disconnecting slow ticket notifications client")
+			close(c)
+		}
+	}
+	s.ticketClients = clients
+	s.mu.Unlock()
+}
+
+func (s *NotificationServer) notifyTicket(n *TicketNotification) {
+	s.sendTicketNotification(n)
+}
+
+// VoteNotification describes a vote created by the wallet for a winning
+// ticket and sent to the network.
+type VoteNotification struct {
+	Ticket      chainhash.Hash
+	Vote        chainhash.Hash
+	BlockHash   chainhash.Hash // Block voted on
+	BlockHeight int32
+	VoteBits    uint16
+}
+
+// VoteNotificationsClient receives VoteNotifications over the channel C.
+type VoteNotificationsClient struct {
+	C      chan *VoteNotification
+	server *NotificationServer
+}
+
+// VoteNotifications returns a client for receiving VoteNotifications over a
+// channel.  The channel buffers up to maxQueuedNotifications notifications, and
+// is closed if the client falls further behind.  When finished, the client's
+// Done method should be called to disassociate the client from the server.
+func (s *NotificationServer) VoteNotifications() VoteNotificationsClient {
+	c := make(chan *VoteNotification, maxQueuedNotifications)
+	s.mu.Lock()
+	s.voteClients = append(s.voteClients, c)
+	s.mu.Unlock()
+	return VoteNotificationsClient{
+		C:      c,
+		server: s,
+	}
+}
+
+// Done deregisters the client from the server and closes its channel, unless
+// the client was already disconnected for falling behind.  It must be called
+// exactly once when the client is finished receiving notifications.
+func (c *VoteNotificationsClient) Done() {
+	s := c.server
+	s.mu.Lock()
+	clients := s.voteClients
+	for i, ch := range clients {
+		if c.C == ch {
+			clients[i] = clients[len(clients)-1]
+			s.voteClients = clients[:len(clients)-1]
+			close(ch)
+			break
+		}
+	}
+	s.mu.Unlock()
+}
+
+// sendVoteNotification queues n for every vote notifications client,
+// disconnecting clients whose queues are full.
+func (s *NotificationServer) sendVoteNotification(n *VoteNotification) {
+	s.mu.Lock()
+	clients := s.voteClients[:0]
+	for _, c := range s.voteClients {
+		select {
+		case c <- n:
+			clients = append(clients, c)
+		default:
+			log.Warnf("Disconnecting slow vote notifications client")
+			close(c)
+		}
+	}
+	s.voteClients = clients
+	s.mu.Unlock()
+}
+
+func (s *NotificationServer) notifyVote(n *VoteNotification) {
+	s.sendVoteNotification(n)
+}
+
+// OmniTransactionNotification describes a mined omni transaction which was
+// passed to the omni engine for processing.
+type OmniTransactionNotification struct {
+	Hash        chainhash.Hash
+	Sender      string
+	Receiver    string // Empty if the transaction has no reference output
+	BlockHash   chainhash.Hash
+	BlockHeight int32
+}
+
+// OmniTransactionNotificationsClient receives OmniTransactionNotifications
+// over the channel C.
+type OmniTransactionNotificationsClient struct {
+	C      chan *OmniTransactionNotification
+	server *NotificationServer
+}
+
+// OmniTransactionNotifications returns a client for receiving
+// OmniTransactionNotifications over a channel.  The channel buffers up to
+// maxQueuedNotifications notifications, and is closed if the client falls
+// further behind.  When finished, the client's Done method should be called to
+// disassociate the client from the server.
+func (s *NotificationServer) OmniTransactionNotifications() OmniTransactionNotificationsClient {
+	c := make(chan *OmniTransactionNotification, maxQueuedNotifications)
+	s.mu.Lock()
+	s.omniClients = append(s.omniClients, c)
+	s.mu.Unlock()
+	return OmniTransactionNotificationsClient{
+		C:      c,
+		server: s,
+	}
+}
+
+// Done deregisters the client from the server and closes its channel, unless
+// the client was already disconnected for falling behind.  It must be called
+// exactly once when the client is finished receiving notifications.
+func (c *OmniTransactionNotificationsClient) Done() {
+	s := c.server
+	s.mu.Lock()
+	clients := s.omniClients
+	for i, ch := range clients {
+		if c.C == ch {
+			clients[i] = clients[len(clients)-1]
+			s.omniClients = clients[:len(clients)-1]
+			close(ch)
+			break
+		}
+	}
+	s.mu.Unlock()
+}
+
+// sendOmniTransactionNotification queues n for every omni transaction
+// notifications client, disconnecting clients whose queues are full.
+func (s *NotificationServer) sendOmniTransactionNotification(n *OmniTransactionNotification) {
+	s.mu.Lock()
+	clients := s.omniClients[:0]
+	for _, c := range s.omniClients {
+		select {
+		case c <- n:
+			clients = append(clients, c)
+		default:
+			log.Warnf("Disconnecting slow omni transaction notifications client")
+			close(c)
+		}
+	}
+	s.omniClients = clients
 	s.mu.Unlock()
 }
 
+func (s *NotificationServer) notifyOmniTransaction(n *OmniTransactionNotification) {
+	s.sendOmniTransactionNotification(n)
+}
+
 // ConfirmationNotifications registers a client for confirmation notifications
 // from the notification server.
 func (s *NotificationServer) ConfirmationNotifications(ctx context.Context) *ConfirmationNotificationsClient {
 	c := &ConfirmationNotificationsClient{
 		watched: make(map[chainhash.Hash]int32),
 		r:       make(chan *confNtfnResult),
+		tips:    make(chan int32, 1),
 		ctx:     ctx,
 		s:       s,
 	}
@@ -776,22 +1053,7 @@ func (s *NotificationServer) ConfirmationNotifications(ctx context.Context) *Con
 	s.confClients = append(s.confClients, c)
 	s.mu.Unlock()
 
-	// Cleanup when caller signals done.
-	go func() {
-		<-ctx.Done()
-
-		// Remove item from notification server's slice
-		s.mu.Lock()
-		slice := &s.confClients
-		for i, sc := range *slice {
-			if c == sc {
-				(*slice)[i] = (*slice)[len(*slice)-1]
-				*slice = (*slice)[:len(*slice)-1]
-				break
-			}
-		}
-		s.mu.Unlock()
-	}()
+	go c.run(s.wallet.quitChan())
 
 	return c
 }
@@ -803,9 +1065,10 @@ type ConfirmationNotificationsClient struct {
 	watched map[chainhash.Hash]int32
 	mu      sync.Mutex
 
-	r   chan *confNtfnResult
-	ctx context.Context
-	s   *NotificationServer
+	r    chan *confNtfnResult
+	tips chan int32 // Latest unprocessed main chain tip height
+	ctx  context.Context
+	s    *NotificationServer
 }
 
 type confNtfnResult struct {
@@ -902,12 +1165,37 @@ func (c *ConfirmationNotificationsClient) Recv() ([]ConfirmationNotification, er
 	}
 }
 
-func (c *ConfirmationNotificationsClient) process(tipHeight int32) {
-	select {
-	case <-c.ctx.Done():
-		return
-	default:
+// run processes main chain tip changes for the client until the caller's
+// context is done or the wallet shuts down, and then deregisters the client
+// from the server.
+func (c *ConfirmationNotificationsClient) run(quit <-chan struct{}) {
+	defer func() {
+		s := c.s
+		s.mu.Lock()
+		slice := &s.confClients
+		for i, sc := range *slice {
+			if c == sc {
+				(*slice)[i] = (*slice)[len(*slice)-1]
+				*slice = (*slice)[:len(*slice)-1]
+				break
+			}
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case tipHeight := <-c.tips:
+			c.process(tipHeight, quit)
+		case <-c.ctx.Done():
+			return
+		case <-quit:
+			return
+		}
 	}
+}
+
+func (c *ConfirmationNotificationsClient) process(tipHeight int32, quit <-chan struct{}) {
 
 	c.mu.Lock()
 	w := c.s.wallet
@@ -965,5 +1253,6 @@ func (c *ConfirmationNotificationsClient) process(tipHeight int32) {
 	select {
 	case c.r <- r:
 	case <-c.ctx.Done():
+	case <-quit:
 	}
 }
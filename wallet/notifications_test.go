@@ -0,0 +1,97 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSlowNotificationClientDisconnected(t *testing.T) {
+	s := newNotificationServer(&Wallet{quit: make(chan struct{})})
+	slow := s.TicketNotifications()
+	fast := s.TicketNotifications()
+	defer fast.Done()
+
+	// Notifying never waits on clients, and the slow client is
+	// disconnected once its queue is full.
+	for i := 0; i < maxQueuedNotifications+1; i++ {
+		n := &TicketNotification{BlockHeight: int32(i)}
+		s.notifyTicket(n)
+		if got := <-fast.C; got != n {
+			t.Fatalf("fast client received notification %d out of order", i)
+		}
+	}
+	for i := 0; i < maxQueuedNotifications; i++ {
+		n, ok := <-slow.C
+		if !ok {
+			t.Fatalf("slow client disconnected after %d notifications", i)
+		}
+		if n.BlockHeight != int32(i) {
+			t.Fatalf("slow client received height %d, want %d",
+				n.BlockHeight, i)
+		}
+	}
+	if _, ok := <-slow.C; ok {
+		t.Fatal("slow client was not disconnected")
+	}
+	s.mu.Lock()
+	clients := len(s.ticketClients)
+	s.mu.Unlock()
+	if clients != 1 {
+		t.Fatalf("server has %d ticket clients, want 1", clients)
+	}
+
+	// Done must not close the channel of a disconnected client again.
+	slow.Done()
+}
+
+func TestNotificationClientDone(t *testing.T) {
+	s := newNotificationServer(&Wallet{quit: make(chan struct{})})
+	c := s.MainTipChangedNotifications()
+	s.notifyMainChainTipChanged(&MainTipChangedNotification{NewHeight: 1})
+	c.Done()
+
+	// Notifications after Done are not sent to the client, and the channel
+	// is closed after any undelivered notifications.
+	s.notifyMainChainTipChanged(&MainTipChangedNotification{NewHeight: 2})
+	for n := range c.C {
+		if n.NewHeight != 1 {
+			t.Fatalf("received notification for height %d after Done",
+				n.NewHeight)
+		}
+	}
+	s.mu.Lock()
+	clients := len(s.tipChangedClients)
+	s.mu.Unlock()
+	if clients != 0 {
+		t.Fatalf("server has %d main chain tip clients after Done", clients)
+	}
+}
+
+func TestConfirmationClientStopsOnQuit(t *testing.T) {
+	w := &Wallet{quit: make(chan struct{})}
+	s := newNotificationServer(w)
+	s.ConfirmationNotifications(context.Background())
+
+	confClients := func() int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.confClients)
+	}
+	if n := confClients(); n != 1 {
+		t.Fatalf("server has %d confirmation clients, want 1", n)
+	}
+
+	w.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for confClients() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("confirmation client was not removed after wallet shutdown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
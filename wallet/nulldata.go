@@ -0,0 +1,90 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+)
+
+// MaxNulldataSize is the maximum size of the data carried by an OP_RETURN
+// output of a transaction relayed by hcd.
+const MaxNulldataSize = txscript.MaxDataCarrierSize
+
+// nulldataOutput returns a zero value OP_RETURN output carrying data.
+func nulldataOutput(data []byte) (*wire.TxOut, error) {
+	if len(data) > MaxNulldataSize {
+		str := fmt.Sprintf("data size %d exceeds the relayed maximum of %d bytes",
+			len(data), MaxNulldataSize)
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	script, err := txscript.GenerateProvablyPruneableOut(data)
+	if err != nil {
+		return nil, err
+	}
+	return wire.NewTxOut(0, script), nil
+}
+
+// checkNulldataOutputs returns an error if transaction outputs include more
+// than the single OP_RETURN output relayed by hcd.
+func checkNulldataOutputs(outputs []*wire.TxOut) error {
+	n := 0
+	for _, out := range outputs {
+		if txscript.GetScriptClass(out.Version, out.PkScript) == txscript.NullDataTy {
+			n++
+		}
+	}
+	if n > 1 {
+		const str = "transactions may only carry a single OP_RETURN output"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	return nil
+}
+
+type sendOutputsCallOptions struct {
+	nulldata    []byte
+	hasNulldata bool
+}
+
+// SendOutputsCallOption defines a call option for SendOutputs.
+type SendOutputsCallOption func(*sendOutputsCallOptions)
+
+// WithNulldata configures SendOutputs to add an OP_RETURN output carrying data
+// to the transaction.  The data may not exceed MaxNulldataSize bytes.
+func WithNulldata(data []byte) SendOutputsCallOption {
+	return func(o *sendOutputsCallOptions) {
+		o.nulldata = data
+		o.hasNulldata = true
+	}
+}
+
+// SendData creates and publishes a transaction from an account with a single
+// OP_RETURN output carrying data, returning the transaction hash and the fee
+// paid.  The data may not exceed MaxNulldataSize bytes.
+func (w *Wallet) SendData(account uint32, data []byte, minconf int32) (*chainhash.Hash, hcutil.Amount, error) {
+	if len(data) == 0 {
+		const str = "no data to send"
+		return nil, 0, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	out, err := nulldataOutput(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	atx, err := w.CreateSimpleTx(account, []*wire.TxOut{out}, minconf, "", "")
+	if err != nil {
+		return nil, 0, err
+	}
+	fee := atx.TotalInput
+	for _, out := range atx.Tx.TxOut {
+		fee -= hcutil.Amount(out.Value)
+	}
+	hash := atx.Tx.TxHash()
+	return &hash, fee, nil
+}
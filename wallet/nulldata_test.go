@@ -0,0 +1,60 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestNulldataOutput(t *testing.T) {
+	data := bytes.Repeat([]byte{0xaa}, MaxNulldataSize)
+	out, err := nulldataOutput(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != 0 {
+		t.Errorf("OP_RETURN output pays %d", out.Value)
+	}
+	pushes, err := txscript.PushedData(out.PkScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txscript.GetScriptClass(out.Version, out.PkScript) != txscript.NullDataTy ||
+		len(pushes) != 1 || !bytes.Equal(pushes[0], data) {
+		t.Errorf("output script %x does not carry the data", out.PkScript)
+	}
+
+	_, err = nulldataOutput(append(data, 0))
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("oversized data returned error %v", err)
+	}
+
+	outputs := []*wire.TxOut{out, wire.NewTxOut(1e8, foreignPkScript)}
+	if err := checkNulldataOutputs(outputs); err != nil {
+		t.Errorf("single OP_RETURN output was rejected: %v", err)
+	}
+	err = checkNulldataOutputs(append(outputs, out))
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("two OP_RETURN outputs returned error %v", err)
+	}
+}
+
+func TestSendDataSize(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	for _, size := range []int{0, MaxNulldataSize + 1} {
+		_, _, err := w.SendData(udb.DefaultAccountNum, make([]byte, size), 1)
+		if !apperrors.IsError(err, apperrors.ErrInput) {
+			t.Errorf("sending %d bytes returned error %v", size, err)
+		}
+	}
+}
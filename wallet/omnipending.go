@@ -0,0 +1,230 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"strings"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/omnilib"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// Reasons a pending omni entry is stale.
+const (
+	// OmniPendingMined describes an entry for a transaction that has been
+	// mined and processed by the omni engine.
+	OmniPendingMined = "mined"
+
+	// OmniPendingRemoved describes an entry for a transaction that is no
+	// longer recorded by the wallet, e.g. because it expired, was double
+	// spent, or was abandoned.
+	OmniPendingRemoved = "removed"
+
+	// OmniPendingExpired describes an entry for a transaction which remains
+	// unmined after the pending expiry.
+	OmniPendingExpired = "expired"
+)
+
+// StaleOmniPending describes a pending balance change the wallet added to the
+// omni engine which should no longer be pending.
+type StaleOmniPending struct {
+	Hash chainhash.Hash
+
+	// Height is the main chain height when the entry was added.
+	Height int32
+
+	// Reason is one of OmniPendingMined, OmniPendingRemoved, or
+	// OmniPendingExpired.
+	Reason string
+}
+
+// SetOmniPendingExpiry sets the number of blocks after which a pending omni
+// entry for an unmined transaction is considered stale.  Zero disables the
+// expiry.
+func (w *Wallet) SetOmniPendingExpiry(blocks int32) {
+	w.omniPendingMu.Lock()
+	w.omniPendingExpiry = blocks
+	w.omniPendingMu.Unlock()
+}
+
+// OmniPendingExpiry returns the number of blocks after which a pending omni
+// entry for an unmined transaction is considered stale.
+func (w *Wallet) OmniPendingExpiry() int32 {
+	w.omniPendingMu.Lock()
+	expiry := w.omniPendingExpiry
+	w.omniPendingMu.Unlock()
+	return expiry
+}
+
+// sendOmniCmd sends a command to the omni engine.  Tests replace it to observe
+// the commands of pending omni entries without an engine.
+var sendOmniCmd = omnilib.SendCmd
+
+// OmniPendingAdd records the pending balance change of a published
+// transaction with the omni engine.  The entry is recorded by the wallet until
+// the transaction is mined, so the change can be reversed if the transaction
+// will never be mined.
+func (w *Wallet) OmniPendingAdd(cmd *hcjson.OmniPendingAddCmd) error {
+	hash, err := chainhash.NewHashFromStr(cmd.TxId)
+	if err != nil {
+		return err
+	}
+	_, err = sendOmniCmd(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, height := w.MainChainTip()
+	return walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.PutOmniPending(txmgrNs, &udb.OmniPending{
+			Hash:       *hash,
+			Height:     height,
+			Sender:     cmd.Sender,
+			MscType:    int32(cmd.MscType),
+			PropertyID: cmd.Propertyid,
+			Amount:     cmd.Amount,
+			Subtract:   cmd.Subtract,
+		})
+	})
+}
+
+// omniPendingMined removes the pending entry of a transaction added by the
+// wallet once the transaction is mined.  The omni engine removes the pending
+// entry of every transaction it processes in a block.
+func (w *Wallet) omniPendingMined(txmgrNs walletdb.ReadWriteBucket, hash *chainhash.Hash) error {
+	return w.TxStore.RemoveOmniPending(txmgrNs, hash)
+}
+
+// StaleOmniPending returns the pending omni entries added by the wallet which
+// are for mined transactions, transactions no longer recorded by the wallet,
+// or transactions which remain unmined after expiry blocks.  An expiry of zero
+// never considers unmined transactions stale.
+//
+// Stale entries are removed as blocks are connected, so any entries returned
+// are those added since the latest block, those the omni engine failed to
+// reverse, or unmined entries which are not expired with the configured
+// expiry.
+func (w *Wallet) StaleOmniPending(expiry int32) ([]StaleOmniPending, error) {
+	stale, err := w.staleOmniPending(expiry)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]StaleOmniPending, len(stale))
+	for i := range stale {
+		result[i] = StaleOmniPending{
+			Hash:   stale[i].Hash,
+			Height: stale[i].Height,
+			Reason: stale[i].reason,
+		}
+	}
+	return result, nil
+}
+
+type staleOmniPending struct {
+	udb.OmniPending
+	reason string
+}
+
+func (w *Wallet) staleOmniPending(expiry int32) ([]staleOmniPending, error) {
+	var stale []staleOmniPending
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+
+		pending, err := w.TxStore.OmniPending(txmgrNs)
+		if err != nil {
+			return err
+		}
+		for i := range pending {
+			p := &pending[i]
+			details, err := w.TxStore.TxDetails(txmgrNs, &p.Hash)
+			if err != nil {
+				return err
+			}
+			var reason string
+			switch {
+			case details == nil:
+				reason = OmniPendingRemoved
+			case details.Block.Height != -1:
+				reason = OmniPendingMined
+			case expiry > 0 && tipHeight-p.Height >= expiry:
+				reason = OmniPendingExpired
+			default:
+				continue
+			}
+			stale = append(stale, staleOmniPending{OmniPending: *p, reason: reason})
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// negateOmniAmount returns the amount of a pending balance change reversing a
+// change of amount.
+func negateOmniAmount(amount string) string {
+	if strings.HasPrefix(amount, "-") {
+		return amount[1:]
+	}
+	return "-" + amount
+}
+
+// reverseOmniPending reverses the pending balance change of an entry for a
+// transaction which will never be mined, and removes the entry.  The omni
+// engine provides no way to delete a pending entry, so the change is reversed
+// by adding the opposite change for the transaction.
+func (w *Wallet) reverseOmniPending(p *udb.OmniPending, reason string) error {
+	_, err := sendOmniCmd(&hcjson.OmniPendingAddCmd{
+		TxId:       p.Hash.String(),
+		Sender:     p.Sender,
+		MscType:    int(p.MscType),
+		Propertyid: p.PropertyID,
+		Amount:     negateOmniAmount(p.Amount),
+		Subtract:   p.Subtract,
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Reversed the pending omni balance change of %s transaction %v",
+		reason, &p.Hash)
+	return walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.RemoveOmniPending(txmgrNs, &p.Hash)
+	})
+}
+
+// pruneOmniPending removes the stale pending omni entries added by the wallet,
+// reversing the balance changes of transactions which were removed from the
+// wallet or expired.  Entries whose changes could not be reversed remain
+// recorded and are retried when the next block is connected.
+func (w *Wallet) pruneOmniPending() {
+	stale, err := w.staleOmniPending(w.OmniPendingExpiry())
+	if err != nil {
+		log.Errorf("Failed to find stale pending omni transactions: %v", err)
+		return
+	}
+	for i := range stale {
+		s := &stale[i]
+		if s.reason != OmniPendingMined {
+			err := w.reverseOmniPending(&s.OmniPending, s.reason)
+			if err != nil {
+				log.Warnf("Failed to reverse the pending omni balance "+
+					"change of %s transaction %v: %v", s.reason, &s.Hash, err)
+			}
+			continue
+		}
+		err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+			return w.TxStore.RemoveOmniPending(txmgrNs, &s.Hash)
+		})
+		if err != nil {
+			log.Errorf("Failed to remove pending omni transaction %v: %v",
+				&s.Hash, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestStaleOmniPending(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	unmined := spend.TxHash()
+	removed := chainhash.Hash{1}
+	_, tipHeight := w.MainChainTip()
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		for _, p := range []udb.OmniPending{
+			{Hash: unmined, Height: tipHeight - 5, Amount: "1"},
+			{Hash: removed, Height: tipHeight, Amount: "2"},
+		} {
+			p := p
+			err := w.TxStore.PutOmniPending(txmgrNs, &p)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expiry int32
+		stale  map[chainhash.Hash]string
+	}{
+		{0, map[chainhash.Hash]string{removed: OmniPendingRemoved}},
+		{6, map[chainhash.Hash]string{removed: OmniPendingRemoved}},
+		{5, map[chainhash.Hash]string{
+			removed: OmniPendingRemoved,
+			unmined: OmniPendingExpired,
+		}},
+	}
+	for _, test := range tests {
+		stale, err := w.StaleOmniPending(test.expiry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stale) != len(test.stale) {
+			t.Errorf("expiry %d: %d stale entries, want %d", test.expiry,
+				len(stale), len(test.stale))
+		}
+		for _, s := range stale {
+			if test.stale[s.Hash] != s.Reason {
+				t.Errorf("expiry %d: entry %v is %q, want %q", test.expiry,
+					&s.Hash, s.Reason, test.stale[s.Hash])
+			}
+		}
+	}
+
+	// Entries of mined transactions are removed.
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		err := w.omniPendingMined(txmgrNs, &removed)
+		if err != nil {
+			return err
+		}
+		pending, err := w.TxStore.OmniPending(txmgrNs)
+		if err != nil {
+			return err
+		}
+		if len(pending) != 1 || pending[0].Hash != unmined {
+			t.Errorf("pending entries %+v after the mined entry was "+
+				"removed", pending)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for amount, want := range map[string]string{"1.5": "-1.5", "-20": "20"} {
+		if got := negateOmniAmount(amount); got != want {
+			t.Errorf("negated amount %q is %q, want %q", amount, got, want)
+		}
+	}
+}
+
+func TestAbandonReversesOmniPending(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+	w.enableOmni = true
+
+	var sent []*hcjson.OmniPendingAddCmd
+	defer func(send func(interface{}) (json.RawMessage, error)) {
+		sendOmniCmd = send
+	}(sendOmniCmd)
+	sendOmniCmd = func(cmd interface{}) (json.RawMessage, error) {
+		sent = append(sent, cmd.(*hcjson.OmniPendingAddCmd))
+		return nil, nil
+	}
+
+	spend := addSpendWithChange(t, w)
+	hash := spend.TxHash()
+	err := w.OmniPendingAdd(&hcjson.OmniPendingAddCmd{
+		TxId:       hash.String(),
+		Sender:     "Ssender",
+		Propertyid: 3,
+		Amount:     "1.5",
+		Subtract:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.AbandonTransaction(&hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %d commands to the omni engine, want 2", len(sent))
+	}
+	want := hcjson.OmniPendingAddCmd{
+		TxId:       hash.String(),
+		Sender:     "Ssender",
+		Propertyid: 3,
+		Amount:     "-1.5",
+		Subtract:   true,
+	}
+	if *sent[1] != want {
+		t.Errorf("abandoning sent %+v, want %+v", *sent[1], want)
+	}
+	stale, err := w.StaleOmniPending(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("stale entries %+v remain after abandoning", stale)
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// TxCursor is the position of a transaction in the newest-first order in
+// which transactions are listed: the height of the block the transaction is
+// mined in, or -1 for unmined transactions, and the index of the transaction
+// among the wallet's transactions at that height.  Unmined transactions are
+// indexed in the order of their hashes, so their positions may shift as
+// unmined transactions are added and removed between pages.
+type TxCursor struct {
+	Height int32
+	Index  int32
+}
+
+// String returns the opaque encoding of the cursor, which is parsed by
+// ParseTxCursor.
+func (c *TxCursor) String() string {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(c.Height))
+	binary.BigEndian.PutUint32(b[4:], uint32(c.Index))
+	return hex.EncodeToString(b[:])
+}
+
+// ParseTxCursor parses the opaque encoding of a transaction cursor.
+func ParseTxCursor(s string) (*TxCursor, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 8 {
+		const str = "invalid transaction cursor"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: err}
+	}
+	return &TxCursor{
+		Height: int32(binary.BigEndian.Uint32(b[:4])),
+		Index:  int32(binary.BigEndian.Uint32(b[4:])),
+	}, nil
+}
+
+// TransactionIterator pages through the listtransactions results of every
+// recorded transaction, newest first, without holding the results of all
+// transactions in memory at once.
+type TransactionIterator struct {
+	w      *Wallet
+	cursor *TxCursor
+	done   bool
+}
+
+// IterateTransactions returns an iterator over the listtransactions results of
+// the transactions following a cursor, or of all transactions when after is
+// nil.
+func (w *Wallet) IterateTransactions(after *TxCursor) *TransactionIterator {
+	return &TransactionIterator{w: w, cursor: after}
+}
+
+// Next returns the listtransactions results of at most count transactions
+// following the cursor of the iterator, and advances the cursor past them.
+// No results are returned after the last transaction has been reached.
+func (it *TransactionIterator) Next(count int) ([]hcjson.ListTransactionsResult, error) {
+	txList := []hcjson.ListTransactionsResult{}
+	if it.done || count <= 0 {
+		return txList, nil
+	}
+	w := it.w
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+
+		begin := int32(-1)
+		if it.cursor != nil {
+			begin = it.cursor.Height
+		}
+		n := 0
+		more := false
+		rangeFn := func(details []udb.TxDetails) (bool, error) {
+			height := details[0].Block.Height
+			i := int32(len(details)) - 1
+			if it.cursor != nil && it.cursor.Height == height &&
+				it.cursor.Index <= i {
+				i = it.cursor.Index - 1
+			}
+			for ; i >= 0; i-- {
+				if n >= count {
+					more = true
+					return true, nil
+				}
+				jsonResults := listTransactions(dbtx, &details[i],
+					w.Manager, tipHeight, w.chainParams)
+				txList = append(txList, jsonResults...)
+				it.cursor = &TxCursor{Height: height, Index: i}
+				n++
+			}
+			return false, nil
+		}
+		err := w.TxStore.RangeTransactions(txmgrNs, begin, 0, rangeFn)
+		if err == nil && !more {
+			it.done = true
+		}
+		return err
+	})
+	return txList, err
+}
+
+// Cursor returns the position of the last transaction returned by the
+// iterator, or nil if no transactions have been returned.
+func (it *TransactionIterator) Cursor() *TxCursor {
+	return it.cursor
+}
+
+// Done returns whether the iterator has returned the oldest transaction.
+func (it *TransactionIterator) Done() bool {
+	return it.done
+}
+
+// UnspentCursor is the position of an output in the order in which unspent
+// outputs are listed: the order of the database keys of their outpoints.
+// Mined and unmined outputs share this order, so outputs keep their positions
+// when they are mined.
+type UnspentCursor struct {
+	OutPoint wire.OutPoint
+}
+
+// String returns the opaque encoding of the cursor, which is parsed by
+// ParseUnspentCursor.
+func (c *UnspentCursor) String() string {
+	var b [36]byte
+	copy(b[:32], c.OutPoint.Hash[:])
+	binary.BigEndian.PutUint32(b[32:], c.OutPoint.Index)
+	return hex.EncodeToString(b[:])
+}
+
+// ParseUnspentCursor parses the opaque encoding of an unspent output cursor.
+func ParseUnspentCursor(s string) (*UnspentCursor, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 36 {
+		const str = "invalid unspent output cursor"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: err}
+	}
+	c := new(UnspentCursor)
+	copy(c.OutPoint.Hash[:], b[:32])
+	c.OutPoint.Index = binary.BigEndian.Uint32(b[32:])
+	return c, nil
+}
+
+// UnspentIterator pages through the listunspent results of the unspent
+// outputs fitting the criteria described by ListUnspent.
+type UnspentIterator struct {
+	w             *Wallet
+	minconf       int32
+	maxconf       int32
+	addresses     map[string]struct{}
+	includeLocked bool
+	cursor        *UnspentCursor
+	done          bool
+}
+
+// IterateUnspent returns an iterator over the listunspent results of the
+// unspent outputs following a cursor, or of all unspent outputs when after is
+// nil, which fit the criteria described by ListUnspent.
+func (w *Wallet) IterateUnspent(minconf, maxconf int32, addresses map[string]struct{},
+	includeLocked bool, after *UnspentCursor) *UnspentIterator {
+
+	return &UnspentIterator{
+		w:             w,
+		minconf:       minconf,
+		maxconf:       maxconf,
+		addresses:     addresses,
+		includeLocked: includeLocked,
+		cursor:        after,
+	}
+}
+
+// Next returns the listunspent results of at most count unspent outputs
+// following the cursor of the iterator, and advances the cursor past them.
+// Outputs are read with a database cursor positioned after the cursor of the
+// iterator, so only the outputs of the page and those not fitting the criteria
+// are read.
+func (it *UnspentIterator) Next(count int) ([]*hcjson.ListUnspentResult, error) {
+	var results []*hcjson.ListUnspentResult
+	if it.done || count <= 0 {
+		return results, nil
+	}
+	w := it.w
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+
+		defaultAccountName, err := w.Manager.AccountName(
+			addrmgrNs, udb.DefaultAccountNum)
+		if err != nil {
+			return err
+		}
+
+		var after *wire.OutPoint
+		if it.cursor != nil {
+			after = &it.cursor.OutPoint
+		}
+		more := false
+		err = w.TxStore.ForEachUnspentOutput(txmgrNs, after, func(c *udb.Credit) (bool, error) {
+			if len(results) >= count {
+				more = true
+				return false, nil
+			}
+			result, err := w.unspentResult(addrmgrNs, txmgrNs, c,
+				tipHeight, defaultAccountName, it.minconf, it.maxconf,
+				it.addresses, it.includeLocked)
+			if err != nil {
+				return false, err
+			}
+			it.cursor = &UnspentCursor{OutPoint: c.OutPoint}
+			if result != nil {
+				results = append(results, result)
+			}
+			return true, nil
+		})
+		if err == nil && !more {
+			it.done = true
+		}
+		return err
+	})
+	return results, err
+}
+
+// Cursor returns the position of the last output considered by the iterator,
+// or nil if no outputs have been considered.
+func (it *UnspentIterator) Cursor() *UnspentCursor {
+	return it.cursor
+}
+
+// Done returns whether the iterator has considered every unspent output.
+func (it *UnspentIterator) Done() bool {
+	return it.done
+}
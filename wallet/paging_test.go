@@ -0,0 +1,102 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestUnspentCursorEncoding(t *testing.T) {
+	c := &UnspentCursor{OutPoint: wire.OutPoint{Hash: chainhash.Hash{1, 2}, Index: 7}}
+	parsed, err := ParseUnspentCursor(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.OutPoint.Hash != c.OutPoint.Hash || parsed.OutPoint.Index != 7 {
+		t.Errorf("parsed cursor %v, want %v", parsed.OutPoint, c.OutPoint)
+	}
+	for _, s := range []string{"", "zz", c.String()[:70]} {
+		if _, err := ParseUnspentCursor(s); err == nil {
+			t.Errorf("parsed invalid cursor %q", s)
+		}
+	}
+}
+
+func TestIterateUnspent(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	// Record three transactions with two wallet outputs each.
+	for i := byte(0); i < 3; i++ {
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{i + 1}},
+			foreignSigScript))
+		tx.AddTxOut(wire.NewTxOut(1e8, walletPkScript(t, w,
+			udb.ExternalBranch, uint32(2*i))))
+		tx.AddTxOut(wire.NewTxOut(1e8, walletPkScript(t, w,
+			udb.ExternalBranch, uint32(2*i+1))))
+		addUnminedTx(t, w, tx)
+	}
+
+	all, err := w.ListUnspent(0, 9999999, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 6 {
+		t.Fatalf("wallet lists %d unspent outputs, want 6", len(all))
+	}
+
+	// Pages list every output exactly once, and the cursor of a page
+	// resumes the listing in a new iterator.
+	seen := make(map[string]int)
+	var cursor *UnspentCursor
+	pages := 0
+	for {
+		it := w.IterateUnspent(0, 9999999, nil, true, cursor)
+		results, err := it.Next(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, r := range results {
+			seen[r.TxID]++
+		}
+		if it.Done() {
+			break
+		}
+		if pages > 2 {
+			t.Fatal("iterator did not finish after listing every output")
+		}
+		cursor = it.Cursor()
+	}
+	if pages != 2 {
+		t.Errorf("listed outputs in %d pages, want 2", pages)
+	}
+	if len(seen) != 3 {
+		t.Errorf("listed outputs of %d transactions, want 3", len(seen))
+	}
+	for txid, n := range seen {
+		if n != 2 {
+			t.Errorf("listed %d outputs of %s, want 2", n, txid)
+		}
+	}
+
+	// An iterator past the last output returns nothing.
+	it := w.IterateUnspent(0, 9999999, nil, true, cursor)
+	if _, err := it.Next(4); err != nil {
+		t.Fatal(err)
+	}
+	results, err := it.Next(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 || !it.Done() {
+		t.Errorf("finished iterator returned %d results", len(results))
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// testChain returns n headers extending the block prev at height, which are
+// distinguished from the headers of other chains by nonce.
+func testChain(t *testing.T, prev chainhash.Hash, height uint32, nonce uint32, n int) []udb.BlockHeaderData {
+	headers := make([]udb.BlockHeaderData, 0, n)
+	for i := 0; i < n; i++ {
+		h := wire.BlockHeader{
+			PrevBlock: prev,
+			VoteBits:  hcutil.BlockValid,
+			Height:    height + uint32(i) + 1,
+			Nonce:     nonce,
+		}
+		var buf bytes.Buffer
+		err := h.Serialize(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := udb.BlockHeaderData{BlockHash: h.BlockHash()}
+		copy(d.SerializedHeader[:], buf.Bytes())
+		headers = append(headers, d)
+		prev = d.BlockHash
+	}
+	return headers
+}
+
+// reorgTestWallet returns a wallet with a main chain of five blocks after the
+// genesis block, and the headers of the main chain indexed by height.
+func reorgTestWallet(t *testing.T) (*Wallet, []udb.BlockHeaderData, func()) {
+	w, teardown := testWallet(t)
+	genesis := udb.BlockHeaderData{BlockHash: *w.chainParams.GenesisHash}
+	headers := append([]udb.BlockHeaderData{genesis},
+		testChain(t, genesis.BlockHash, 0, 0, 5)...)
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		for i := 1; i < len(headers); i++ {
+			err := w.TxStore.ExtendMainChain(txmgrNs, &headers[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+	return w, headers, teardown
+}
+
+// mainChainTip returns the hash and height of the wallet's main chain tip.
+func mainChainTip(t *testing.T, w *Wallet) (chainhash.Hash, int32) {
+	var hash chainhash.Hash
+	var height int32
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		hash, height = w.TxStore.MainChainTip(dbtx.ReadBucket(wtxmgrNamespaceKey))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash, height
+}
+
+// serverChain returns a mock chain client whose main chain is the wallet's
+// main chain up to and including height fork-1, followed by side.
+func serverChain(main []udb.BlockHeaderData, fork int32, side []udb.BlockHeaderData) *testhelpers.MockChainClient {
+	c := testhelpers.NewMockChainClient()
+	for _, h := range main[:fork] {
+		c.BlockHashes = append(c.BlockHashes, h.BlockHash)
+	}
+	for _, h := range side {
+		c.BlockHashes = append(c.BlockHashes, h.BlockHash)
+	}
+	return c
+}
+
+// putPendingReorg records a pending reorg and its received side chain blocks.
+func putPendingReorg(t *testing.T, w *Wallet, r *udb.PendingReorg, blocks []udb.BlockHeaderData) {
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		err := w.TxStore.PutPendingReorg(txmgrNs, r)
+		if err != nil {
+			return err
+		}
+		for i := range blocks {
+			err := w.TxStore.PutPendingReorgBlock(txmgrNs,
+				&udb.PendingReorgBlock{Header: blocks[i]})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkNoPendingReorg checks that no pending reorg or side chain blocks remain
+// recorded.
+func checkNoPendingReorg(t *testing.T, w *Wallet) {
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		r, err := w.TxStore.PendingReorg(txmgrNs)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			t.Error("pending reorg remains recorded")
+		}
+		blocks, err := w.TxStore.PendingReorgBlocks(txmgrNs)
+		if err != nil {
+			return err
+		}
+		if len(blocks) != 0 {
+			t.Errorf("%d pending reorg blocks remain recorded", len(blocks))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReorgForkHeight(t *testing.T) {
+	w, main, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	side := testChain(t, main[3].BlockHash, 3, 1, 3)
+	c := serverChain(main, 4, side)
+	for _, maxHeight := range []int32{6, 5, 4} {
+		fork, err := w.reorgForkHeight(c, maxHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fork != 4 {
+			t.Errorf("max height %d: fork height %d, want 4", maxHeight, fork)
+		}
+	}
+
+	// A server chain which extends the wallet's main chain forks above
+	// the wallet's tip.
+	c = serverChain(main, 6, testChain(t, main[5].BlockHash, 5, 0, 1))
+	fork, err := w.reorgForkHeight(c, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fork != 6 {
+		t.Errorf("fork height %d, want 6", fork)
+	}
+}
+
+func TestHandleReorganizingRecordsForkHeight(t *testing.T) {
+	w, main, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	side := testChain(t, main[2].BlockHash, 2, 1, 4)
+	c := serverChain(main, 3, side)
+	w.chainClientLock.Lock()
+	w.chainClient = c
+	w.chainClientLock.Unlock()
+
+	target := side[len(side)-1].BlockHash
+	err := w.handleReorganizing(&main[5].BlockHash, &target, 5, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		r, err := w.TxStore.PendingReorg(dbtx.ReadBucket(wtxmgrNamespaceKey))
+		if err != nil {
+			return err
+		}
+		if r == nil || r.Target != target || r.ForkHeight != 3 {
+			t.Errorf("recorded pending reorg %+v, want target %v fork "+
+				"height 3", r, &target)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolvePendingReorg(t *testing.T) {
+	// The recorded side chain blocks which connect at the fork point are
+	// switched to.
+	w, main, teardown := reorgTestWallet(t)
+	side := testChain(t, main[3].BlockHash, 3, 1, 3)
+	putPendingReorg(t, w, &udb.PendingReorg{Target: side[2].BlockHash,
+		ForkHeight: 4}, side[:2])
+	err := w.resolvePendingReorg(serverChain(main, 4, side))
+	if err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+	tipHash, tipHeight := mainChainTip(t, w)
+	if tipHash != side[1].BlockHash || tipHeight != 5 {
+		t.Errorf("tip %v height %d after resuming the switch, want %v "+
+			"height 5", &tipHash, tipHeight, &side[1].BlockHash)
+	}
+	checkNoPendingReorg(t, w)
+	teardown()
+
+	// Without connecting side chain blocks, the main chain is rolled back
+	// to the fork point, which is found from the server when unknown.
+	w, main, teardown = reorgTestWallet(t)
+	defer teardown()
+	side = testChain(t, main[3].BlockHash, 3, 1, 3)
+	putPendingReorg(t, w, &udb.PendingReorg{Target: side[2].BlockHash,
+		ForkHeight: -1}, side[1:2])
+	err = w.resolvePendingReorg(serverChain(main, 4, side))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tipHash, tipHeight = mainChainTip(t, w)
+	if tipHash != main[3].BlockHash || tipHeight != 3 {
+		t.Errorf("tip %v height %d after rolling back, want %v height 3",
+			&tipHash, tipHeight, &main[3].BlockHash)
+	}
+	checkNoPendingReorg(t, w)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// InputScriptError describes a transaction input whose signature script
+// failed pre-flight validation against the script of the output it spends.
+type InputScriptError struct {
+	InputIndex       uint32
+	PreviousOutPoint wire.OutPoint
+	Err              error
+}
+
+// ScriptValidationError describes a transaction which was not sent to the
+// consensus RPC server as the scripts of one or more of its inputs failed
+// pre-flight validation.
+type ScriptValidationError struct {
+	TxHash chainhash.Hash
+	Inputs []InputScriptError
+}
+
+// Error satisfies the error interface.
+func (e *ScriptValidationError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "transaction %v failed script validation:", &e.TxHash)
+	for i := range e.Inputs {
+		in := &e.Inputs[i]
+		if i != 0 {
+			buf.WriteByte(';')
+		}
+		fmt.Fprintf(&buf, " input %d (%v): %v", in.InputIndex,
+			&in.PreviousOutPoint, in.Err)
+	}
+	return buf.String()
+}
+
+// errUnknownPrevOut describes an input spending an output which is neither
+// recorded by the wallet nor unspent according to the consensus RPC server.
+var errUnknownPrevOut = errors.New("previous output is unknown or spent")
+
+// SkipPreflight returns whether transactions are sent to the consensus RPC
+// server without first validating their scripts.
+func (w *Wallet) SkipPreflight() bool {
+	w.skipPreflightMu.Lock()
+	skip := w.skipPreflight
+	w.skipPreflightMu.Unlock()
+	return skip
+}
+
+// SetSkipPreflight sets whether transactions are sent to the consensus RPC
+// server without first validating their scripts.  Skipping the pre-flight
+// allows publishing transactions whose previous outputs can not be looked up,
+// leaving the consensus server to reject any that are invalid.
+func (w *Wallet) SetSkipPreflight(skip bool) {
+	w.skipPreflightMu.Lock()
+	w.skipPreflight = skip
+	w.skipPreflightMu.Unlock()
+}
+
+// prevOutScript returns the script version and script of a previous output,
+// looked up from the wallet's transactions or queried from the consensus RPC
+// server when they are not recorded.
+func (w *Wallet) prevOutScript(txmgrNs walletdb.ReadBucket, chainClient ChainClient,
+	op *wire.OutPoint) (uint16, []byte, error) {
+
+	if w.TxStore.ExistsTx(txmgrNs, &op.Hash) {
+		tx, err := w.TxStore.Tx(txmgrNs, &op.Hash)
+		if err != nil {
+			return 0, nil, err
+		}
+		if op.Index >= uint32(len(tx.TxOut)) {
+			return 0, nil, errUnknownPrevOut
+		}
+		out := tx.TxOut[op.Index]
+		return out.Version, out.PkScript, nil
+	}
+
+	txOut, err := chainClient.GetTxOut(&op.Hash, op.Index, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	if txOut == nil {
+		return 0, nil, errUnknownPrevOut
+	}
+	pkScript, err := hex.DecodeString(txOut.ScriptPubKey.Hex)
+	if err != nil {
+		return 0, nil, err
+	}
+	return uint16(txOut.Version), pkScript, nil
+}
+
+// verifyTxScripts executes the signature script of each input of tx against
+// the script of the output it spends, returning a *ScriptValidationError
+// describing every input that fails.  The stakebase input of votes, which
+// spends no previous output, is not checked.
+func (w *Wallet) verifyTxScripts(txmgrNs walletdb.ReadBucket, chainClient ChainClient,
+	tx *wire.MsgTx) error {
+
+	var failed []InputScriptError
+	isVote, _ := stake.IsSSGen(tx)
+	for i, in := range tx.TxIn {
+		if isVote && i == 0 {
+			continue
+		}
+		version, pkScript, err := w.prevOutScript(txmgrNs, chainClient,
+			&in.PreviousOutPoint)
+		if err == nil {
+			var vm *txscript.Engine
+			vm, err = txscript.NewEngine(pkScript, tx, i,
+				sanityVerifyFlags, version, nil)
+			if err == nil {
+				err = vm.Execute()
+			}
+		}
+		if err != nil {
+			failed = append(failed, InputScriptError{
+				InputIndex:       uint32(i),
+				PreviousOutPoint: in.PreviousOutPoint,
+				Err:              err,
+			})
+		}
+	}
+	if len(failed) != 0 {
+		return &ScriptValidationError{TxHash: tx.TxHash(), Inputs: failed}
+	}
+	return nil
+}
+
+// VerifyTransactionScripts performs the pre-flight validation of the scripts
+// of a transaction which precedes sending it to the consensus RPC server.  A
+// *ScriptValidationError is returned describing every input which fails.
+func (w *Wallet) VerifyTransactionScripts(tx *wire.MsgTx, chainClient ChainClient) error {
+	return walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		return w.verifyTxScripts(txmgrNs, chainClient, tx)
+	})
+}
+
+// sendRawTransaction sends tx to the consensus RPC server after validating its
+// scripts, unless the pre-flight is skipped.
+func (w *Wallet) sendRawTransaction(txmgrNs walletdb.ReadBucket, chainClient ChainClient,
+	tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
+
+	if !w.SkipPreflight() {
+		err := w.verifyTxScripts(txmgrNs, chainClient, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return chainClient.SendRawTransaction(tx, allowHighFees)
+}
+
+// SendRawTransaction sends a transaction to the consensus RPC server without
+// recording it in the wallet.  Unless the pre-flight is skipped, the scripts
+// of the transaction are first validated and a *ScriptValidationError is
+// returned instead of sending a transaction which would be rejected.
+func (w *Wallet) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool, chainClient ChainClient) (*chainhash.Hash, error) {
+	var txHash *chainhash.Hash
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		txHash, err = w.sendRawTransaction(txmgrNs, chainClient, tx, allowHighFees)
+		return err
+	})
+	return txHash, err
+}
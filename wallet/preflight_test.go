@@ -0,0 +1,63 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+)
+
+func TestPreflight(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	// The first input spends a wallet output with an invalid signature
+	// script, the second an unspent output known to the consensus server
+	// whose script succeeds without a signature, and the third an unknown
+	// output.
+	spend := addSpendWithChange(t, w)
+	c := testhelpers.NewMockChainClient()
+	anyone := wire.OutPoint{Hash: chainhash.Hash{5}}
+	c.TxOuts[anyone] = &hcjson.GetTxOutResult{
+		ScriptPubKey: hcjson.ScriptPubKeyResult{Hex: "51"},
+	}
+	tx := spend.Copy()
+	tx.AddTxIn(wire.NewTxIn(&anyone, nil))
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{6}}, nil))
+
+	_, err := w.SendRawTransaction(tx, false, c)
+	verr, ok := err.(*ScriptValidationError)
+	if !ok {
+		t.Fatalf("sending an invalid transaction returned %v, want a "+
+			"*ScriptValidationError", err)
+	}
+	if verr.TxHash != tx.TxHash() || len(verr.Inputs) != 2 ||
+		verr.Inputs[0].InputIndex != 0 || verr.Inputs[1].InputIndex != 2 {
+		t.Errorf("validation error %v, want failures of inputs 0 and 2", verr)
+	} else if verr.Inputs[1].Err != errUnknownPrevOut {
+		t.Errorf("input spending an unknown output failed with %v, want %v",
+			verr.Inputs[1].Err, errUnknownPrevOut)
+	}
+	if sent := c.SentTransactions(); len(sent) != 0 {
+		t.Fatalf("sent %d transactions which failed validation", len(sent))
+	}
+
+	// Transactions are sent unvalidated when the pre-flight is skipped.
+	if w.SkipPreflight() {
+		t.Fatal("pre-flight is skipped by default")
+	}
+	w.SetSkipPreflight(true)
+	if _, err := w.SendRawTransaction(tx, false, c); err != nil {
+		t.Fatal(err)
+	}
+	if sent := c.SentTransactions(); len(sent) != 1 {
+		t.Errorf("sent %d transactions with the pre-flight skipped, want 1",
+			len(sent))
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+)
+
+// PriceSource provides current and historical prices of a coin in fiat
+// currencies, used to value balances and transactions.
+type PriceSource interface {
+	// Currency returns the code of the default fiat currency of the
+	// source.
+	Currency() string
+
+	// Price returns the price of a single coin in a currency at a time.
+	Price(currency string, t time.Time) (float64, error)
+}
+
+const (
+	// priceSourceTimeout is the time limit of a single price request.
+	priceSourceTimeout = 30 * time.Second
+
+	// currentPriceTTL is the duration prices of the current day are cached
+	// for.  Prices of previous days are cached for the lifetime of the
+	// source.
+	currentPriceTTL = 5 * time.Minute
+)
+
+// DefaultPriceJSONPath is the default path of the price in the JSON responses
+// of an HTTPPriceSource.
+const DefaultPriceJSONPath = "price"
+
+type cachedPrice struct {
+	price   float64
+	expires time.Time // zero for no expiry
+}
+
+// HTTPPriceSource is a PriceSource querying daily prices from HTTP servers
+// responding with JSON.  The {currency} and {date} placeholders of each URL are
+// replaced by the currency code and the UTC date (YYYY-MM-DD) of the requested
+// price, and the price is read from the dot-separated path of JSON object
+// fields, in which {currency} is also replaced.  URLs are queried in order
+// until one responds with a price.
+type HTTPPriceSource struct {
+	urls     []string
+	jsonPath string
+	currency string
+	client   *http.Client
+
+	mu     sync.Mutex
+	prices map[string]cachedPrice
+}
+
+// NewHTTPPriceSource creates an HTTPPriceSource querying URL templates for
+// prices, read from a JSON path, with a default currency.  An empty JSON path
+// selects DefaultPriceJSONPath.
+func NewHTTPPriceSource(urls []string, jsonPath, currency string) *HTTPPriceSource {
+	if jsonPath == "" {
+		jsonPath = DefaultPriceJSONPath
+	}
+	return &HTTPPriceSource{
+		urls:     urls,
+		jsonPath: jsonPath,
+		currency: currency,
+		client:   &http.Client{Timeout: priceSourceTimeout},
+		prices:   make(map[string]cachedPrice),
+	}
+}
+
+// Currency returns the code of the default fiat currency of the source.
+func (s *HTTPPriceSource) Currency() string {
+	return s.currency
+}
+
+// Price returns the price of a single coin in a currency on the UTC date of
+// t.
+func (s *HTTPPriceSource) Price(currency string, t time.Time) (float64, error) {
+	now := time.Now()
+	date := t.UTC().Format("2006-01-02")
+	key := currency + " " + date
+
+	s.mu.Lock()
+	cached, ok := s.prices[key]
+	s.mu.Unlock()
+	if ok && (cached.expires.IsZero() || now.Before(cached.expires)) {
+		return cached.price, nil
+	}
+
+	var price float64
+	var err error
+	for _, url := range s.urls {
+		price, err = s.query(url, currency, date)
+		if err == nil {
+			break
+		}
+		log.Debugf("Price source %s failed: %v", url, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cached = cachedPrice{price: price}
+	if date == now.UTC().Format("2006-01-02") {
+		cached.expires = now.Add(currentPriceTTL)
+	}
+	s.mu.Lock()
+	s.prices[key] = cached
+	s.mu.Unlock()
+	return price, nil
+}
+
+// query requests the price in a currency on a date from a single URL.
+func (s *HTTPPriceSource) query(url, currency, date string) (float64, error) {
+	r := strings.NewReplacer("{currency}", currency, "{date}", date)
+	resp, err := s.client.Get(r.Replace(url))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("price source responded with status %s for %s %s",
+			resp.Status, currency, date)
+	}
+	var body interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price source response for %s %s: %v",
+			currency, date, err)
+	}
+	path := r.Replace(s.jsonPath)
+	for _, field := range strings.Split(path, ".") {
+		obj, ok := body.(map[string]interface{})
+		if !ok {
+			body = nil
+			break
+		}
+		body = obj[field]
+	}
+	price, ok := body.(float64)
+	if !ok {
+		return 0, fmt.Errorf("price source response for %s %s has no "+
+			"numeric %q field", currency, date, path)
+	}
+	return price, nil
+}
+
+// validCurrency returns whether a currency code is safe to substitute in
+// price source URLs.
+func validCurrency(currency string) bool {
+	if len(currency) == 0 || len(currency) > 10 {
+		return false
+	}
+	for _, c := range currency {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPriceSource sets the source of fiat prices used to value balances and
+// transactions.  A nil source disables fiat valuation.
+func (w *Wallet) SetPriceSource(s PriceSource) {
+	w.priceSourceMu.Lock()
+	w.priceSource = s
+	w.priceSourceMu.Unlock()
+}
+
+// PriceSource returns the source of fiat prices used to value balances and
+// transactions, or nil if none is set.
+func (w *Wallet) PriceSource() PriceSource {
+	w.priceSourceMu.Lock()
+	s := w.priceSource
+	w.priceSourceMu.Unlock()
+	return s
+}
+
+// FiatPrice returns the price of a single coin in a currency at a time from
+// the wallet's price source.  An empty currency selects the default currency
+// of the source, and the currency of the price is returned.
+func (w *Wallet) FiatPrice(currency string, t time.Time) (float64, string, error) {
+	s := w.PriceSource()
+	if s == nil {
+		const str = "no price source is configured for fiat valuation"
+		return 0, "", apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if currency == "" {
+		currency = s.Currency()
+	}
+	if !validCurrency(currency) {
+		str := fmt.Sprintf("invalid currency code %q", currency)
+		return 0, "", apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	price, err := s.Price(currency, t)
+	if err != nil {
+		return 0, "", err
+	}
+	return price, currency, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+)
+
+func TestHTTPPriceSource(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/down":
+			http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+		case "/price/EUR/2019-03-04":
+			fmt.Fprint(rw, `{"data":{"EUR":{"close":1.25}}}`)
+		default:
+			fmt.Fprint(rw, `{"data":{}}`)
+		}
+	}))
+	defer server.Close()
+
+	// Sources are queried in order until one responds with a price.
+	s := NewHTTPPriceSource([]string{server.URL + "/down",
+		server.URL + "/price/{currency}/{date}"}, "data.{currency}.close", "EUR")
+	when := time.Date(2019, 3, 4, 23, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		price, err := s.Price("EUR", when)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price != 1.25 {
+			t.Errorf("price is %v, want 1.25", price)
+		}
+	}
+	// Prices of previous days are cached.
+	mu.Lock()
+	if len(requests) != 2 {
+		t.Errorf("made %d requests %v, want 2", len(requests), requests)
+	}
+	mu.Unlock()
+
+	if _, err := s.Price("USD", when); err == nil {
+		t.Error("read a price missing from the response")
+	}
+
+	w, teardown := testWallet(t)
+	defer teardown()
+	if _, _, err := w.FiatPrice("", when); !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("price without a price source returned error %v", err)
+	}
+	w.SetPriceSource(s)
+	price, currency, err := w.FiatPrice("", when)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 1.25 || currency != "EUR" {
+		t.Errorf("default currency price is %v %s, want 1.25 EUR", price, currency)
+	}
+	for _, currency := range []string{"E/R", "EUR?x=1", "averyverylongcurrency"} {
+		_, _, err := w.FiatPrice(currency, when)
+		if !apperrors.IsError(err, apperrors.ErrInput) {
+			t.Errorf("currency %q returned error %v", currency, err)
+		}
+	}
+}
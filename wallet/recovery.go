@@ -0,0 +1,206 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// Automatic rescans started to recover from notification errors are spaced by
+// a backoff which begins at minRecoveryBackoff and doubles after each rescan
+// up to maxRecoveryBackoff.  The backoff is reset once no error occurs for
+// maxRecoveryBackoff.
+const (
+	minRecoveryBackoff = 30 * time.Second
+	maxRecoveryBackoff = 30 * time.Minute
+)
+
+// maxRecoveryEvents is the number of recent recovery events which are kept.
+const maxRecoveryEvents = 20
+
+// RecoveryAction describes how the wallet responded to an error processing a
+// consensus server notification.
+type RecoveryAction string
+
+// These constants describe the responses to notification errors.
+const (
+	// RecoveryRescan indicates a rescan was started.
+	RecoveryRescan RecoveryAction = "rescan"
+
+	// RecoveryDeferred indicates a rescan was scheduled to start once the
+	// backoff since the previous rescan expires.
+	RecoveryDeferred RecoveryAction = "deferred"
+
+	// RecoverySkipped indicates no rescan was started, as a rescan was
+	// already running or scheduled.
+	RecoverySkipped RecoveryAction = "skipped"
+
+	// RecoveryHalted indicates the error describes inconsistent wallet data
+	// or invalid chain data which rescans can not repair, and automatic
+	// rescans were disabled.
+	RecoveryHalted RecoveryAction = "halted"
+)
+
+// RecoveryEvent describes an error processing a consensus server notification
+// and the response of the wallet.
+type RecoveryEvent struct {
+	Time         time.Time
+	Notification string
+	Error        string
+	Transient    bool
+	Action       RecoveryAction
+}
+
+// RecoveryState describes the automatic rescans started to recover from errors
+// processing consensus server notifications.
+type RecoveryState struct {
+	// Rescans is the number of automatic rescans which were started.
+	Rescans int
+
+	// Errors is the number of notification errors which occurred.
+	Errors int
+
+	// Backoff is the minimum time between automatic rescans.
+	Backoff time.Duration
+
+	// Pending is set while a rescan is scheduled to start after the
+	// backoff expires.
+	Pending bool
+
+	// Halted is set after an error describing inconsistent wallet data or
+	// a chain fault.  No further automatic rescans are started.
+	Halted bool
+
+	// Events describes the most recent errors, oldest first.
+	Events []RecoveryEvent
+}
+
+// recoveryManager decides when notification errors start automatic rescans.
+type recoveryManager struct {
+	mu         sync.Mutex
+	state      RecoveryState
+	lastError  time.Time
+	lastRescan time.Time
+}
+
+// transientError returns whether an error processing a notification may be
+// resolved by rescanning.  Errors describing inconsistent data in the
+// transaction store are not, and require the transaction history to be
+// rebuilt.  Nor are chain faults, as rescanning requests the same invalid
+// chain data from the consensus server.
+func transientError(err error) bool {
+	return !apperrors.IsError(err, apperrors.ErrData) &&
+		!apperrors.IsError(err, apperrors.ErrChainFault)
+}
+
+// RecoveryState returns the automatic rescans started to recover from errors
+// processing consensus server notifications.
+func (w *Wallet) RecoveryState() RecoveryState {
+	m := &w.recovery
+	m.mu.Lock()
+	state := m.state
+	if state.Backoff == 0 {
+		state.Backoff = minRecoveryBackoff
+	}
+	state.Events = append([]RecoveryEvent(nil), m.state.Events...)
+	m.mu.Unlock()
+	return state
+}
+
+// recoverFromNotificationError responds to an error processing a consensus
+// server notification by rescanning the main chain tip block, refreshing
+// wallet data.  Rescans are spaced by an exponential backoff, and errors which
+// occur during the backoff schedule a single rescan once it expires.
+func (w *Wallet) recoverFromNotificationError(notification string, err error) {
+	m := &w.recovery
+	now := time.Now()
+	event := RecoveryEvent{
+		Time:         now,
+		Notification: notification,
+		Error:        err.Error(),
+		Transient:    transientError(err),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state.Errors++
+	if m.state.Backoff == 0 || now.Sub(m.lastError) >= maxRecoveryBackoff {
+		m.state.Backoff = minRecoveryBackoff
+	}
+	m.lastError = now
+
+	var wait time.Duration
+	switch {
+	case m.state.Halted:
+		event.Action = RecoverySkipped
+	case !event.Transient:
+		log.Errorf("Automatic rescans are disabled until the wallet is "+
+			"restarted: %v", err)
+		m.state.Halted = true
+		event.Action = RecoveryHalted
+	case m.state.Pending || w.IsScanning():
+		event.Action = RecoverySkipped
+	default:
+		wait = m.state.Backoff - now.Sub(m.lastRescan)
+		if wait > 0 {
+			m.state.Pending = true
+			event.Action = RecoveryDeferred
+		} else {
+			event.Action = RecoveryRescan
+		}
+	}
+
+	m.state.Events = append(m.state.Events, event)
+	if len(m.state.Events) > maxRecoveryEvents {
+		m.state.Events = m.state.Events[len(m.state.Events)-maxRecoveryEvents:]
+	}
+
+	switch event.Action {
+	case RecoveryRescan:
+		w.startRecoveryRescan()
+	case RecoveryDeferred:
+		log.Infof("Rescanning after notification error in %v", wait)
+		time.AfterFunc(wait, func() {
+			m.mu.Lock()
+			m.state.Pending = false
+			w.startRecoveryRescan()
+			m.mu.Unlock()
+		})
+	}
+}
+
+// startRecoveryRescan rescans the main chain tip block and advances the
+// backoff.  It must be called with the recovery manager mutex held.
+func (w *Wallet) startRecoveryRescan() {
+	m := &w.recovery
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		log.Errorf("Unable to rescan after notification error: %v", err)
+		return
+	}
+	var height int32
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		ns := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, height = w.TxStore.MainChainTip(ns)
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Unable to rescan after notification error: %v", err)
+		return
+	}
+
+	m.state.Rescans++
+	m.lastRescan = time.Now()
+	m.state.Backoff *= 2
+	if m.state.Backoff > maxRecoveryBackoff {
+		m.state.Backoff = maxRecoveryBackoff
+	}
+	w.RescanFromHeight(chainClient, height)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+)
+
+func TestTransientError(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{errors.New("connection reset"), true},
+		{apperrors.E{ErrorCode: apperrors.ErrDatabase}, true},
+		{apperrors.E{ErrorCode: apperrors.ErrData}, false},
+		{apperrors.E{ErrorCode: apperrors.ErrChainFault}, false},
+	}
+	for _, test := range tests {
+		if got := transientError(test.err); got != test.transient {
+			t.Errorf("transientError(%v) = %v, want %v", test.err, got,
+				test.transient)
+		}
+	}
+}
+
+// waitRescans waits for any rescan started by the recovery manager to return.
+func waitRescans(t *testing.T, w *Wallet) {
+	deadline := time.Now().Add(10 * time.Second)
+	for w.IsScanning() {
+		if time.Now().After(deadline) {
+			t.Fatal("rescan did not return")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// lastRecoveryAction returns the action of the most recent recovery event.
+func lastRecoveryAction(t *testing.T, s RecoveryState) RecoveryAction {
+	if len(s.Events) == 0 {
+		t.Fatal("no recovery events")
+	}
+	return s.Events[len(s.Events)-1].Action
+}
+
+func TestRecoveryBackoff(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	w.chainClientLock.Lock()
+	w.chainClient = testhelpers.NewMockChainClient()
+	w.chainClientLock.Unlock()
+
+	// The first error rescans immediately and doubles the backoff.
+	w.recoverFromNotificationError("blockconnected", errors.New("first"))
+	s := w.RecoveryState()
+	if a := lastRecoveryAction(t, s); a != RecoveryRescan {
+		t.Fatalf("first error action %v, want %v", a, RecoveryRescan)
+	}
+	if s.Rescans != 1 || s.Backoff != 2*minRecoveryBackoff {
+		t.Fatalf("%d rescans with backoff %v after the first error, want 1 "+
+			"with backoff %v", s.Rescans, s.Backoff, 2*minRecoveryBackoff)
+	}
+	waitRescans(t, w)
+
+	// An error during the backoff defers a single rescan until it expires,
+	// and later errors while it is pending are skipped.  Shorten the
+	// remaining backoff so the deferred rescan runs during the test.
+	w.recovery.mu.Lock()
+	w.recovery.lastRescan = time.Now().Add(-s.Backoff + 100*time.Millisecond)
+	w.recovery.mu.Unlock()
+	w.recoverFromNotificationError("blockconnected", errors.New("second"))
+	s = w.RecoveryState()
+	if a := lastRecoveryAction(t, s); a != RecoveryDeferred || !s.Pending {
+		t.Fatalf("error during backoff action %v pending %v, want %v and "+
+			"pending", a, s.Pending, RecoveryDeferred)
+	}
+	w.recoverFromNotificationError("blockconnected", errors.New("third"))
+	s = w.RecoveryState()
+	if a := lastRecoveryAction(t, s); a != RecoverySkipped {
+		t.Fatalf("error with a pending rescan action %v, want %v", a,
+			RecoverySkipped)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for s = w.RecoveryState(); s.Pending; s = w.RecoveryState() {
+		if time.Now().After(deadline) {
+			t.Fatal("deferred rescan did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s.Rescans != 2 || s.Backoff != 4*minRecoveryBackoff || s.Errors != 3 {
+		t.Fatalf("%d rescans with backoff %v after %d errors, want 2 with "+
+			"backoff %v after 3", s.Rescans, s.Backoff, s.Errors,
+			4*minRecoveryBackoff)
+	}
+	waitRescans(t, w)
+
+	// The backoff is capped.
+	w.recovery.mu.Lock()
+	w.recovery.state.Backoff = maxRecoveryBackoff
+	w.recovery.lastRescan = time.Now().Add(-2 * maxRecoveryBackoff)
+	w.recovery.mu.Unlock()
+	w.recoverFromNotificationError("blockconnected", errors.New("fourth"))
+	if s = w.RecoveryState(); s.Backoff != maxRecoveryBackoff {
+		t.Fatalf("backoff %v exceeds the maximum %v", s.Backoff,
+			maxRecoveryBackoff)
+	}
+	waitRescans(t, w)
+
+	// The backoff is reset once no error occurs for the maximum backoff.
+	w.recovery.mu.Lock()
+	w.recovery.lastError = time.Now().Add(-maxRecoveryBackoff)
+	w.recovery.lastRescan = time.Now().Add(-maxRecoveryBackoff)
+	w.recovery.mu.Unlock()
+	w.recoverFromNotificationError("blockconnected", errors.New("fifth"))
+	s = w.RecoveryState()
+	if a := lastRecoveryAction(t, s); a != RecoveryRescan {
+		t.Fatalf("error after a quiet period action %v, want %v", a,
+			RecoveryRescan)
+	}
+	if s.Backoff != 2*minRecoveryBackoff {
+		t.Fatalf("backoff %v after a quiet period, want %v", s.Backoff,
+			2*minRecoveryBackoff)
+	}
+	waitRescans(t, w)
+}
+
+func TestRecoveryHalted(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	w.chainClientLock.Lock()
+	w.chainClient = testhelpers.NewMockChainClient()
+	w.chainClientLock.Unlock()
+
+	// Inconsistent wallet data halts automatic rescans, and later errors,
+	// even transient ones, are skipped.
+	dataErr := apperrors.E{ErrorCode: apperrors.ErrData, Description: "bad"}
+	w.recoverFromNotificationError("blockconnected", dataErr)
+	s := w.RecoveryState()
+	if a := lastRecoveryAction(t, s); a != RecoveryHalted || !s.Halted {
+		t.Fatalf("data error action %v halted %v, want %v and halted", a,
+			s.Halted, RecoveryHalted)
+	}
+	if s.Events[0].Transient {
+		t.Error("data error is recorded as transient")
+	}
+	w.recoverFromNotificationError("blockconnected", errors.New("transient"))
+	s = w.RecoveryState()
+	if a := lastRecoveryAction(t, s); a != RecoverySkipped {
+		t.Fatalf("error after halting action %v, want %v", a, RecoverySkipped)
+	}
+	if s.Rescans != 0 {
+		t.Errorf("%d rescans started after halting", s.Rescans)
+	}
+
+	// Only the most recent events are kept.
+	for i := 0; i < 2*maxRecoveryEvents; i++ {
+		w.recoverFromNotificationError("blockconnected", errors.New("transient"))
+	}
+	s = w.RecoveryState()
+	if len(s.Events) != maxRecoveryEvents || s.Errors != 2*maxRecoveryEvents+2 {
+		t.Errorf("%d events kept of %d errors, want %d of %d", len(s.Events),
+			s.Errors, maxRecoveryEvents, 2*maxRecoveryEvents+2)
+	}
+}
@@ -0,0 +1,711 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// RefundableScript describes an imported P2SH redeem script with a timelocked
+// refund path paying a wallet key, such as the refund branch of an atomic swap
+// contract.  The refund path is one branch of a top level OP_IF, beginning
+// with a locktime checked by OP_CHECKLOCKTIMEVERIFY or OP_CHECKSEQUENCEVERIFY
+// and followed by OP_DROP, which together with the script following OP_ENDIF
+// pays to a wallet public key or public key hash.
+type RefundableScript struct {
+	Script        []byte
+	Address       *hcutil.AddressScriptHash
+	RefundAddress hcutil.Address
+
+	// LockTime is the absolute lock time (OP_CHECKLOCKTIMEVERIFY) or the
+	// relative lock time encoded as an input sequence number
+	// (OP_CHECKSEQUENCEVERIFY) of the refund path.
+	LockTime uint32
+	Relative bool
+
+	// Outputs are the unspent outputs paying the script.
+	Outputs []RefundableOutput
+
+	refundIf  bool // refund path is the OP_IF, not the OP_ELSE, branch
+	refundPKH bool // refund path pays a pubkey hash, not a pubkey
+}
+
+// RefundableOutput describes an unspent output paying a refundable script.
+// Matured outputs may be spent by the refund path of the script.
+type RefundableOutput struct {
+	OutPoint    wire.OutPoint
+	Amount      hcutil.Amount
+	BlockHeight int32 // -1 if unmined
+	Matured     bool
+}
+
+// scriptToken is a single opcode of a script and the data it pushes.
+type scriptToken struct {
+	op   byte
+	data []byte
+	raw  []byte
+}
+
+// tokenizeScript splits a script into opcodes.
+func tokenizeScript(script []byte) ([]scriptToken, error) {
+	errShort := errors.New("script is truncated")
+	var toks []scriptToken
+	for i := 0; i < len(script); {
+		op := script[i]
+		start := i
+		i++
+		var n int
+		switch {
+		case op >= txscript.OP_DATA_1 && op <= txscript.OP_DATA_75:
+			n = int(op)
+		case op == txscript.OP_PUSHDATA1:
+			if len(script) < i+1 {
+				return nil, errShort
+			}
+			n = int(script[i])
+			i++
+		case op == txscript.OP_PUSHDATA2:
+			if len(script) < i+2 {
+				return nil, errShort
+			}
+			n = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		case op == txscript.OP_PUSHDATA4:
+			if len(script) < i+4 {
+				return nil, errShort
+			}
+			n = int(binary.LittleEndian.Uint32(script[i:]))
+			i += 4
+		}
+		if n < 0 || len(script)-i < n {
+			return nil, errShort
+		}
+		toks = append(toks, scriptToken{
+			op:   op,
+			data: script[i : i+n],
+			raw:  script[start : i+n],
+		})
+		i += n
+	}
+	return toks, nil
+}
+
+// scriptNumber returns the number pushed by a token, if it is a small integer
+// or a minimally encoded push of up to five bytes.
+func scriptNumber(t scriptToken) (int64, bool) {
+	switch {
+	case t.op == txscript.OP_0:
+		return 0, true
+	case t.op >= txscript.OP_1 && t.op <= txscript.OP_16:
+		return int64(t.op - (txscript.OP_1 - 1)), true
+	case len(t.data) > 0 && len(t.data) <= 5 && int(t.op) == len(t.data):
+		last := len(t.data) - 1
+		if t.data[last]&0x7f == 0 && (last == 0 || t.data[last-1]&0x80 == 0) {
+			return 0, false // not minimally encoded
+		}
+		var v int64
+		for i, b := range t.data {
+			v |= int64(b) << uint(8*i)
+		}
+		if t.data[last]&0x80 != 0 {
+			v &^= int64(0x80) << uint(8*last)
+			v = -v
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+// parseRefundableScript returns the refund path of a script, or nil if the
+// script does not have a timelocked refund path paying a public key or public
+// key hash.
+func parseRefundableScript(script []byte, params *chaincfg.Params) *RefundableScript {
+	toks, err := tokenizeScript(script)
+	if err != nil || len(toks) == 0 || toks[0].op != txscript.OP_IF {
+		return nil
+	}
+	depth := 0
+	elseIdx, endIdx := -1, -1
+loop:
+	for i, t := range toks {
+		switch t.op {
+		case txscript.OP_IF, txscript.OP_NOTIF:
+			depth++
+		case txscript.OP_ELSE:
+			if depth == 1 {
+				if elseIdx != -1 {
+					return nil
+				}
+				elseIdx = i
+			}
+		case txscript.OP_ENDIF:
+			depth--
+			if depth == 0 {
+				endIdx = i
+				break loop
+			}
+		}
+	}
+	if elseIdx == -1 || endIdx == -1 {
+		return nil
+	}
+	tail := toks[endIdx+1:]
+
+	refundPath := func(branch []scriptToken, refundIf bool) *RefundableScript {
+		if len(branch) < 3 || branch[2].op != txscript.OP_DROP {
+			return nil
+		}
+		lock, ok := scriptNumber(branch[0])
+		if !ok || lock < 0 || lock > math.MaxUint32 {
+			return nil
+		}
+		s := &RefundableScript{LockTime: uint32(lock), refundIf: refundIf}
+		switch branch[1].op {
+		case txscript.OP_CHECKLOCKTIMEVERIFY:
+		case txscript.OP_CHECKSEQUENCEVERIFY:
+			if s.LockTime&wire.SequenceLockTimeDisabled != 0 {
+				return nil
+			}
+			s.Relative = true
+		default:
+			return nil
+		}
+
+		var path []byte
+		for _, t := range branch[3:] {
+			path = append(path, t.raw...)
+		}
+		for _, t := range tail {
+			path = append(path, t.raw...)
+		}
+		class, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txscript.DefaultScriptVersion, path, params)
+		if err != nil || len(addrs) != 1 {
+			return nil
+		}
+		switch class {
+		case txscript.PubKeyHashTy:
+			s.refundPKH = true
+		case txscript.PubKeyTy:
+		default:
+			return nil
+		}
+		s.RefundAddress = addrs[0]
+		return s
+	}
+	s := refundPath(toks[elseIdx+1:endIdx], false)
+	if s == nil {
+		s = refundPath(toks[1:elseIdx], true)
+	}
+	if s == nil {
+		return nil
+	}
+	s.Script = script
+	s.Address, err = hcutil.NewAddressScriptHash(script, params)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// matured returns whether an output mined at a height and time may be spent by
+// the refund path in the block following a main chain tip.
+func (s *RefundableScript) matured(height int32, blockTime time.Time,
+	tipHeight int32, tipTime time.Time) bool {
+
+	if !s.Relative {
+		if s.LockTime < txscript.LockTimeThreshold {
+			return int64(s.LockTime) <= int64(tipHeight)
+		}
+		return int64(s.LockTime) < tipTime.Unix()
+	}
+	if height == -1 {
+		return false
+	}
+	lock := s.LockTime & wire.SequenceLockTimeMask
+	if s.LockTime&wire.SequenceLockTimeIsSeconds != 0 {
+		d := time.Duration(lock<<wire.SequenceLockTimeGranularity) * time.Second
+		return !tipTime.Before(blockTime.Add(d))
+	}
+	return confirms(height, tipHeight) >= int32(lock)
+}
+
+// refundInputSize returns the worst case serialize size of an input spending
+// an output of the script by its refund path.
+func (s *RefundableScript) refundInputSize() int {
+	// Signature, the public key when paying a pubkey hash, and the branch
+	// selector.
+	pushesSize := 1 + 73 + 1
+	if s.refundPKH {
+		pushesSize += 1 + 65
+	}
+	return p2shInputSize(pushesSize, s.Script)
+}
+
+// sweepTxSize returns the serialize size of a transaction with nIn inputs,
+// totalling inputsSize bytes, and a single output paying pkScript.
+func sweepTxSize(nIn, inputsSize int, pkScript []byte) int {
+	return 12 + 2*wire.VarIntSerializeSize(uint64(nIn)) +
+		wire.VarIntSerializeSize(1) + inputsSize +
+		8 + 2 + wire.VarIntSerializeSize(uint64(len(pkScript))) + len(pkScript)
+}
+
+// p2shInputSize returns the serialize size of an input redeeming a P2SH
+// output with a signature script of data pushes of pushesSize bytes followed
+// by the redeem script.
+func p2shInputSize(pushesSize int, redeemScript []byte) int {
+	sigScriptSize := pushesSize + 1 + len(redeemScript)
+	switch {
+	case len(redeemScript) > 0xff:
+		sigScriptSize += 2
+	case len(redeemScript) >= txscript.OP_PUSHDATA1:
+		sigScriptSize++
+	}
+	return 32 + 4 + 1 + 8 + 4 + 4 +
+		wire.VarIntSerializeSize(uint64(sigScriptSize)) + sigScriptSize + 4
+}
+
+// refundableScriptIndex returns the parsed refundable scripts of the script
+// store keyed by script hash, reading every stored script only the first time
+// the index is needed.  Scripts stored later are added by insertTxScript.  The
+// returned scripts are shared and must be copied before they are modified.
+func (w *Wallet) refundableScriptIndex(txmgrNs walletdb.ReadBucket) (map[[20]byte]*RefundableScript, error) {
+	w.refundIndexMu.Lock()
+	defer w.refundIndexMu.Unlock()
+
+	if w.refundIndex == nil {
+		scripts, err := w.TxStore.StoredTxScripts(txmgrNs)
+		if err != nil {
+			return nil, err
+		}
+		index := make(map[[20]byte]*RefundableScript)
+		for _, script := range scripts {
+			s := parseRefundableScript(script, w.chainParams)
+			if s != nil {
+				index[*s.Address.Hash160()] = s
+			}
+		}
+		w.refundIndex = index
+	}
+
+	index := make(map[[20]byte]*RefundableScript, len(w.refundIndex))
+	for hash, s := range w.refundIndex {
+		index[hash] = s
+	}
+	return index, nil
+}
+
+// insertTxScript adds a script to the script store, and to the index of
+// refundable scripts when it has a timelocked refund path.
+func (w *Wallet) insertTxScript(txmgrNs walletdb.ReadWriteBucket, script []byte) error {
+	err := w.TxStore.InsertTxScript(txmgrNs, script)
+	if err != nil {
+		return err
+	}
+	s := parseRefundableScript(script, w.chainParams)
+	if s == nil {
+		return nil
+	}
+	w.refundIndexMu.Lock()
+	// A nil index is built from the script store on first use, which will
+	// include this script.
+	if w.refundIndex != nil {
+		w.refundIndex[*s.Address.Hash160()] = s
+	}
+	w.refundIndexMu.Unlock()
+	return nil
+}
+
+// refundableScripts returns the refundable scripts of the script store whose
+// refund path pays a wallet key, keyed by script hash.
+func (w *Wallet) refundableScripts(dbtx walletdb.ReadTx) (map[[20]byte]*RefundableScript, error) {
+	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+	index, err := w.refundableScriptIndex(txmgrNs)
+	if err != nil {
+		return nil, err
+	}
+	refundable := make(map[[20]byte]*RefundableScript)
+	for hash, s := range index {
+		// Scripts are indexed before the transaction storing them is
+		// committed, so skip any whose transaction was rolled back.
+		script, err := w.TxStore.GetTxScript(txmgrNs, hash[:])
+		if err != nil {
+			return nil, err
+		}
+		if script == nil {
+			continue
+		}
+		_, err = w.Manager.Address(addrmgrNs, s.RefundAddress)
+		if err != nil {
+			if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		c := *s
+		refundable[hash] = &c
+	}
+	return refundable, nil
+}
+
+// RefundableScripts returns the imported scripts with timelocked refund paths
+// paying wallet keys, and their unspent outputs.
+func (w *Wallet) RefundableScripts() ([]*RefundableScript, error) {
+	var scripts []*RefundableScript
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		var err error
+		scripts, err = w.refundableScriptOutputs(dbtx)
+		return err
+	})
+	return scripts, err
+}
+
+// refundableScriptOutputs returns the refundable scripts paying wallet keys
+// with their unspent outputs.  Outputs are found from the transactions
+// recorded in the address credits index for each script, without iterating
+// over every unspent output of the wallet.
+func (w *Wallet) refundableScriptOutputs(dbtx walletdb.ReadTx) ([]*RefundableScript, error) {
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+	refundable, err := w.refundableScripts(dbtx)
+	if err != nil {
+		return nil, err
+	}
+	if len(refundable) == 0 {
+		return nil, nil
+	}
+	tipHash, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+	tipHeader, err := w.TxStore.GetBlockHeader(dbtx, &tipHash)
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := make([]*RefundableScript, 0, len(refundable))
+	for _, s := range refundable {
+		p2shScript, err := txscript.PayToAddrScript(s.Address)
+		if err != nil {
+			return nil, err
+		}
+		for _, txHash := range w.TxStore.AddressCreditTxHashes(txmgrNs, s.Address) {
+			details, err := w.TxStore.TxDetails(txmgrNs, &txHash)
+			if err != nil {
+				return nil, err
+			}
+			if details == nil {
+				continue
+			}
+			for _, c := range details.Credits {
+				pkScript := details.MsgTx.TxOut[c.Index].PkScript
+				if c.Spent || !bytes.Equal(pkScript, p2shScript) {
+					continue
+				}
+				op := wire.OutPoint{
+					Hash:  txHash,
+					Index: c.Index,
+					Tree:  wire.TxTreeRegular,
+				}
+				if details.TxType != stake.TxTypeRegular {
+					op.Tree = wire.TxTreeStake
+				}
+				if w.TxStore.UnminedSpender(dbtx, &op) != nil {
+					continue
+				}
+				height, blockTime := details.Block.Height, details.Block.Time
+				if height == -1 {
+					blockTime = details.Received
+				}
+				s.Outputs = append(s.Outputs, RefundableOutput{
+					OutPoint:    op,
+					Amount:      c.Amount,
+					BlockHeight: height,
+					Matured: s.matured(height, blockTime, tipHeight,
+						tipHeader.Timestamp),
+				})
+			}
+		}
+		scripts = append(scripts, s)
+	}
+	return scripts, nil
+}
+
+// RefundScript spends every matured unspent output of a refundable script by
+// its refund path, paying the total less the relay fee to an address, and
+// publishes the transaction.  If to is nil, the outputs are paid to a new
+// internal address of the account of the refund key, or the default account
+// for imported keys.
+func (w *Wallet) RefundScript(addr *hcutil.AddressScriptHash, to hcutil.Address) (*chainhash.Hash, error) {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to sign refunds"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	var s *RefundableScript
+	var account uint32
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		scripts, err := w.refundableScriptOutputs(dbtx)
+		if err != nil {
+			return err
+		}
+		for _, rs := range scripts {
+			if rs.Address.EncodeAddress() == addr.EncodeAddress() {
+				s = rs
+				break
+			}
+		}
+		if s == nil {
+			const str = "address is not a refundable script of the wallet"
+			return apperrors.E{ErrorCode: apperrors.ErrValueNoExists, Description: str, Err: nil}
+		}
+		account, err = w.keySpendAccount(addrmgrNs, s.RefundAddress)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w.publishRefund(chainClient, s, account, to)
+}
+
+// keySpendAccount returns the account which is paid by default when spending
+// outputs with a wallet key: the account of the key, or the default account
+// for imported keys.
+func (w *Wallet) keySpendAccount(addrmgrNs walletdb.ReadBucket, addr hcutil.Address) (uint32, error) {
+	account, err := w.Manager.AddrAccount(addrmgrNs, addr)
+	if err != nil {
+		return 0, err
+	}
+	if account == udb.ImportedAddrAccount {
+		account = udb.DefaultAccountNum
+	}
+	return account, nil
+}
+
+// publishRefund spends the matured outputs of a refundable script by its
+// refund path, paying to, or a new internal address of account when to is nil.
+func (w *Wallet) publishRefund(chainClient ChainClient, s *RefundableScript,
+	account uint32, to hcutil.Address) (*chainhash.Hash, error) {
+
+	tx := wire.NewMsgTx()
+	if s.Relative {
+		// Relative lock times are only enforced for version 2
+		// transactions.
+		tx.Version = 2
+	} else {
+		tx.LockTime = s.LockTime
+	}
+	var total hcutil.Amount
+	for _, out := range s.Outputs {
+		if !out.Matured {
+			continue
+		}
+		txIn := wire.NewTxIn(&out.OutPoint, nil)
+		txIn.ValueIn = int64(out.Amount)
+		if s.Relative {
+			txIn.Sequence = s.LockTime
+		} else {
+			// Lock times are only enforced for inputs which are
+			// not finalized.
+			txIn.Sequence = wire.MaxTxInSequenceNum - 1
+		}
+		tx.AddTxIn(txIn)
+		total += out.Amount
+	}
+	if len(tx.TxIn) == 0 {
+		const str = "no outputs of the script have matured"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	if to == nil {
+		var err error
+		to, err = w.NewInternalAddress(account, WithGapPolicyWrap())
+		if err != nil {
+			return nil, err
+		}
+	}
+	pkScript, err := txscript.PayToAddrScript(to)
+	if err != nil {
+		return nil, err
+	}
+	size := sweepTxSize(len(tx.TxIn), len(tx.TxIn)*s.refundInputSize(), pkScript)
+	fee := FeeForSize(w.RelayFee(), size)
+	if txrules.IsDustAmount(total-fee, len(pkScript), w.RelayFee()) {
+		const str = "refunded amount is too small to pay the fee"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	tx.AddTxOut(wire.NewTxOut(int64(total-fee), pkScript))
+
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		return w.signRefund(addrmgrNs, tx, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializeSize())
+	err = tx.Serialize(&buf)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := w.PublishTransaction(tx, buf.Bytes(), chainClient)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Refunded %v from script %v in transaction %v", total-fee,
+		s.Address, txHash)
+	return txHash, nil
+}
+
+// signRefund adds signature scripts redeeming each input by the refund path
+// of a script.
+func (w *Wallet) signRefund(addrmgrNs walletdb.ReadBucket, tx *wire.MsgTx, s *RefundableScript) error {
+	key, pubKey, done, err := w.scriptSigningKey(addrmgrNs, s.RefundAddress)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	selector := byte(txscript.OP_FALSE)
+	if s.refundIf {
+		selector = txscript.OP_TRUE
+	}
+	for i, txIn := range tx.TxIn {
+		sig, err := txscript.RawTxInSignature(tx, i, s.Script,
+			txscript.SigHashAll, key)
+		if err != nil {
+			return err
+		}
+		b := txscript.NewScriptBuilder().AddData(sig)
+		if s.refundPKH {
+			b.AddData(pubKey)
+		}
+		txIn.SignatureScript, err = b.AddOp(selector).AddData(s.Script).Script()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scriptSigningKey returns the private key and serialized public key of a
+// wallet address, for signing inputs of nonstandard scripts paying the key.
+// done must be called when the private key is no longer needed.
+func (w *Wallet) scriptSigningKey(addrmgrNs walletdb.ReadBucket, addr hcutil.Address) (chainec.PrivateKey, []byte, func(), error) {
+	ma, err := w.Manager.Address(addrmgrNs, addr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pka, ok := ma.(udb.ManagedPubKeyAddress)
+	if !ok {
+		return nil, nil, nil, errors.New("address is not a pubkey address")
+	}
+	var pubKey []byte
+	if pka.Compressed() {
+		pubKey = pka.PubKey().SerializeCompressed()
+	} else {
+		pubKey = pka.PubKey().SerializeUncompressed()
+	}
+	key, done, err := w.Manager.PrivateKey(addrmgrNs, addr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return key, pubKey, done, nil
+}
+
+// SetAutoRefund sets whether matured outputs of refundable scripts are
+// automatically refunded as blocks are connected.
+func (w *Wallet) SetAutoRefund(enabled bool) {
+	w.autoRefundMu.Lock()
+	w.autoRefund = enabled
+	w.autoRefundMu.Unlock()
+}
+
+// AutoRefund returns whether matured outputs of refundable scripts are
+// automatically refunded.
+func (w *Wallet) AutoRefund() bool {
+	w.autoRefundMu.Lock()
+	enabled := w.autoRefund
+	w.autoRefundMu.Unlock()
+	return enabled
+}
+
+// autoRefundScripts refunds the matured outputs of every refundable script
+// when automatic refunds are enabled and the wallet is unlocked.  Refunds are
+// found and published in their own goroutine, so that they do not delay the
+// processing of further blocks, and blocks connected while refunds are still
+// running are skipped.
+func (w *Wallet) autoRefundScripts() {
+	if !w.AutoRefund() {
+		return
+	}
+	if w.Manager.IsLocked() {
+		log.Debugf("Skipping automatic refunds of a locked wallet")
+		return
+	}
+	w.autoRefundMu.Lock()
+	busy := w.autoRefundBusy
+	w.autoRefundBusy = true
+	w.autoRefundMu.Unlock()
+	if busy {
+		return
+	}
+	go func() {
+		defer func() {
+			w.autoRefundMu.Lock()
+			w.autoRefundBusy = false
+			w.autoRefundMu.Unlock()
+		}()
+		w.refundMaturedScripts()
+	}()
+}
+
+// refundMaturedScripts refunds the matured mined outputs of every refundable
+// script.
+func (w *Wallet) refundMaturedScripts() {
+	scripts, err := w.RefundableScripts()
+	if err != nil {
+		log.Errorf("Failed to find refundable scripts: %v", err)
+		return
+	}
+	for _, s := range scripts {
+		matured := false
+		for _, out := range s.Outputs {
+			if out.Matured && out.BlockHeight != -1 {
+				matured = true
+				break
+			}
+		}
+		if !matured {
+			continue
+		}
+		_, err := w.RefundScript(s.Address, nil)
+		if err != nil && !w.ShuttingDown() {
+			log.Errorf("Failed to refund script %v: %v", s.Address, err)
+		}
+	}
+}
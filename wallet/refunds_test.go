@@ -0,0 +1,325 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// testRefundPubKey is the compressed secp256k1 generator point.
+var testRefundPubKey, _ = hex.DecodeString(
+	"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+
+// relativeRefundScript returns a script which pays testRefundPubKey by its
+// OP_ELSE branch after a relative lock time encoded as a sequence number.
+func relativeRefundScript(t *testing.T, sequence int64) []byte {
+	script, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_IF).
+		AddOp(txscript.OP_SHA256).AddData(make([]byte, 32)).
+		AddOp(txscript.OP_EQUALVERIFY).AddData(testRefundPubKey).
+		AddOp(txscript.OP_ELSE).
+		AddInt64(sequence).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+		AddOp(txscript.OP_DROP).AddData(testRefundPubKey).
+		AddOp(txscript.OP_ENDIF).
+		AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestTokenizeScript(t *testing.T) {
+	data := bytes.Repeat([]byte{0xaa}, 80)
+	tests := []struct {
+		name   string
+		script []byte
+		ops    []byte
+		data   [][]byte
+		err    bool
+	}{
+		{
+			name:   "opcodes",
+			script: []byte{txscript.OP_IF, txscript.OP_1, txscript.OP_ENDIF},
+			ops:    []byte{txscript.OP_IF, txscript.OP_1, txscript.OP_ENDIF},
+			data:   [][]byte{{}, {}, {}},
+		},
+		{
+			name:   "data push",
+			script: append([]byte{txscript.OP_DATA_2, 1, 2}, txscript.OP_DROP),
+			ops:    []byte{txscript.OP_DATA_2, txscript.OP_DROP},
+			data:   [][]byte{{1, 2}, {}},
+		},
+		{
+			name:   "pushdata1",
+			script: append([]byte{txscript.OP_PUSHDATA1, 80}, data...),
+			ops:    []byte{txscript.OP_PUSHDATA1},
+			data:   [][]byte{data},
+		},
+		{
+			name:   "pushdata2",
+			script: append([]byte{txscript.OP_PUSHDATA2, 80, 0}, data...),
+			ops:    []byte{txscript.OP_PUSHDATA2},
+			data:   [][]byte{data},
+		},
+		{
+			name:   "pushdata4",
+			script: append([]byte{txscript.OP_PUSHDATA4, 80, 0, 0, 0}, data...),
+			ops:    []byte{txscript.OP_PUSHDATA4},
+			data:   [][]byte{data},
+		},
+		{
+			name:   "truncated data push",
+			script: []byte{txscript.OP_DATA_2, 1},
+			err:    true,
+		},
+		{
+			name:   "truncated pushdata length",
+			script: []byte{txscript.OP_PUSHDATA2, 1},
+			err:    true,
+		},
+		{
+			name:   "truncated pushdata4",
+			script: []byte{txscript.OP_PUSHDATA4, 2, 0, 0, 0, 1},
+			err:    true,
+		},
+	}
+	for _, test := range tests {
+		toks, err := tokenizeScript(test.script)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: truncated script was tokenized", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(toks) != len(test.ops) {
+			t.Errorf("%s: %d tokens, want %d", test.name, len(toks), len(test.ops))
+			continue
+		}
+		var raw []byte
+		for i, tok := range toks {
+			if tok.op != test.ops[i] {
+				t.Errorf("%s: token %d opcode %#x, want %#x", test.name, i,
+					tok.op, test.ops[i])
+			}
+			if !bytes.Equal(tok.data, test.data[i]) {
+				t.Errorf("%s: token %d data %x, want %x", test.name, i,
+					tok.data, test.data[i])
+			}
+			raw = append(raw, tok.raw...)
+		}
+		if !bytes.Equal(raw, test.script) {
+			t.Errorf("%s: raw tokens do not reconstruct the script", test.name)
+		}
+	}
+}
+
+func TestParseRefundableScript(t *testing.T) {
+	refund := testSwapAddress(t, 2)
+	contract, err := atomicSwapContract(testSwapAddress(t, 1), refund,
+		atomicSwapSecretHash([]byte("secret")), 500000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := parseRefundableScript(contract, testParams)
+	if s == nil {
+		t.Fatal("atomic swap contract is not refundable")
+	}
+	if s.LockTime != 500000 || s.Relative {
+		t.Errorf("lock time %d (relative %v), want absolute 500000",
+			s.LockTime, s.Relative)
+	}
+	if s.RefundAddress.EncodeAddress() != refund.EncodeAddress() {
+		t.Errorf("refund address %v, want %v", s.RefundAddress, refund)
+	}
+	if s.refundIf || !s.refundPKH {
+		t.Errorf("refund path if=%v pkh=%v, want the OP_ELSE pubkey hash path",
+			s.refundIf, s.refundPKH)
+	}
+	if !bytes.Equal(s.Script, contract) {
+		t.Error("parsed script does not reference the contract")
+	}
+	p2sh, err := hcutil.NewAddressScriptHash(contract, testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address.EncodeAddress() != p2sh.EncodeAddress() {
+		t.Errorf("script address %v, want %v", s.Address, p2sh)
+	}
+
+	s = parseRefundableScript(relativeRefundScript(t, 144), testParams)
+	if s == nil {
+		t.Fatal("relative refund script is not refundable")
+	}
+	if s.LockTime != 144 || !s.Relative || s.refundPKH {
+		t.Errorf("lock time %d (relative %v, pkh %v), want relative 144 "+
+			"paying a pubkey", s.LockTime, s.Relative, s.refundPKH)
+	}
+
+	relative := relativeRefundScript(t, 144)
+	lock := []byte{txscript.OP_DATA_2, 144, 0}
+	payToPubKey := append([]byte{txscript.OP_DATA_33}, testRefundPubKey...)
+	payToPubKey = append(payToPubKey, txscript.OP_CHECKSIG)
+	notRefundable := map[string][]byte{
+		"disabled sequence lock": relativeRefundScript(t,
+			int64(wire.SequenceLockTimeDisabled|144)),
+		"truncated":     contract[:len(contract)-3],
+		"no branch":     contract[1:],
+		"pay to pubkey": payToPubKey,
+		"missing else":  {txscript.OP_IF, txscript.OP_1, txscript.OP_ENDIF},
+		"missing endif": {txscript.OP_IF, txscript.OP_1, txscript.OP_ELSE,
+			txscript.OP_1},
+		"repeated else": {txscript.OP_IF, txscript.OP_ELSE, txscript.OP_ELSE,
+			txscript.OP_ENDIF},
+		"negative lock": bytes.Replace(relative, lock,
+			[]byte{txscript.OP_1NEGATE}, 1),
+		"non-minimal lock": bytes.Replace(relative, lock,
+			[]byte{txscript.OP_DATA_3, 144, 0, 0}, 1),
+	}
+	for name, script := range notRefundable {
+		if parseRefundableScript(script, testParams) != nil {
+			t.Errorf("%s: script is refundable", name)
+		}
+	}
+}
+
+func TestRefundableScriptMatured(t *testing.T) {
+	blockTime := time.Unix(1500000000, 0)
+	seconds := uint32(wire.SequenceLockTimeIsSeconds | 2)
+	secondsDelay := time.Duration(2<<wire.SequenceLockTimeGranularity) * time.Second
+	tests := []struct {
+		name      string
+		lockTime  uint32
+		relative  bool
+		height    int32
+		tipHeight int32
+		tipTime   time.Time
+		matured   bool
+	}{
+		{"height lock pending", 100, false, 50, 99, blockTime, false},
+		{"height lock matured", 100, false, 50, 100, blockTime, true},
+		{"unmined height lock matured", 100, false, -1, 100, blockTime, true},
+		{"time lock pending", 1500000000, false, 50, 99, blockTime, false},
+		{"time lock matured", 1500000000, false, 50, 99,
+			blockTime.Add(time.Second), true},
+		{"relative blocks pending", 10, true, 100, 108, blockTime, false},
+		{"relative blocks matured", 10, true, 100, 109, blockTime, true},
+		{"relative unmined", 0, true, -1, 109, blockTime, false},
+		{"relative seconds pending", seconds, true, 100, 200,
+			blockTime.Add(secondsDelay - time.Second), false},
+		{"relative seconds matured", seconds, true, 100, 200,
+			blockTime.Add(secondsDelay), true},
+	}
+	for _, test := range tests {
+		s := &RefundableScript{LockTime: test.lockTime, Relative: test.relative}
+		matured := s.matured(test.height, blockTime, test.tipHeight, test.tipTime)
+		if matured != test.matured {
+			t.Errorf("%s: matured=%v, want %v", test.name, matured, test.matured)
+		}
+	}
+}
+
+func TestRefundableScriptIndex(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refund := addrs[0].(*hcutil.AddressPubKeyHash)
+	walletPkScript(t, w, udb.ExternalBranch, 0)
+
+	contract := func(lockTime int64) []byte {
+		c, err := atomicSwapContract(testSwapAddress(t, 1), refund,
+			atomicSwapSecretHash([]byte("secret")), lockTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	// Build the index before any scripts are stored so that later scripts
+	// are added by insertTxScript.
+	scripts, err := w.RefundableScripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 0 {
+		t.Fatalf("%d refundable scripts before any were stored", len(scripts))
+	}
+
+	stored, rolledBack := contract(500000), contract(600000)
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.insertTxScript(dbtx.ReadWriteBucket(wtxmgrNamespaceKey), stored)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errRollback := errors.New("rollback")
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		err := w.insertTxScript(dbtx.ReadWriteBucket(wtxmgrNamespaceKey), rolledBack)
+		if err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatal(err)
+	}
+	// Scripts which are not refundable to the wallet are indexed but not
+	// returned.
+	foreign, err := atomicSwapContract(testSwapAddress(t, 1),
+		testSwapAddress(t, 3), atomicSwapSecretHash([]byte("secret")), 500000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.insertTxScript(dbtx.ReadWriteBucket(wtxmgrNamespaceKey), foreign)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scripts, err = w.RefundableScripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 1 {
+		t.Fatalf("%d refundable scripts, want 1", len(scripts))
+	}
+	if !bytes.Equal(scripts[0].Script, stored) {
+		t.Error("refundable script is not the stored contract")
+	}
+	if len(scripts[0].Outputs) != 0 {
+		t.Errorf("unpaid script has %d outputs", len(scripts[0].Outputs))
+	}
+
+	// Rebuilding the index from the script store finds the same script.
+	w.refundIndexMu.Lock()
+	w.refundIndex = nil
+	w.refundIndexMu.Unlock()
+	rebuilt, err := w.RefundableScripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rebuilt) != 1 || !bytes.Equal(rebuilt[0].Script, stored) {
+		t.Error("rebuilt index does not contain the stored contract")
+	}
+}
@@ -7,32 +7,305 @@
 package wallet
 
 import (
+	"bytes"
 	"encoding/hex"
-	"encoding/json"
-	"fmt"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/HcashOrg/hcd/chaincfg/chainhash"
-	"github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
 	"github.com/HcashOrg/hcwallet/omnilib"
-	"github.com/HcashOrg/hcd/hcjson"
 )
 
+// maxBlocksPerRescan is the default number of blocks rescanned in each batch.
 const maxBlocksPerRescan = 2000
 
-var indexScanning int  = 0
-var isScanning bool  = false
-var mutexOnlyOneChan sync.Mutex
+// Adaptive rescan batches are sized to take about rescanBatchTarget to rescan
+// and to discover no more than rescanBatchTargetTxs transactions, and remain
+// within minRescanBatch and maxRescanBatch blocks.  A batch changes by at most
+// a factor of two from the previous batch.
+const (
+	rescanBatchTarget    = 2 * time.Second
+	rescanBatchTargetTxs = 1000
+	minRescanBatch       = 10
+	maxRescanBatch       = 20000
+)
+
+// rescanManager tracks the rescans of a wallet.  Only one rescan runs at a
+// time: starting a rescan supersedes any rescan in progress, which stops before
+// scanning its next batch of blocks, and waits for it to return.  The zero
+// value is ready for use.
+type rescanManager struct {
+	// running is held by the rescan in progress.
+	running sync.Mutex
+
+	// mu protects latest, the ID of the most recently started rescan, and
+	// scanning, which remains set until the latest rescan returns.
+	mu       sync.Mutex
+	latest   uint64
+	scanning bool
+}
+
+// start registers a new rescan, superseding any rescan in progress, and
+// blocks until the rescan may run.  The returned ID must be passed to finish
+// when the rescan returns.
+func (m *rescanManager) start() uint64 {
+	m.mu.Lock()
+	m.latest++
+	id := m.latest
+	m.mu.Unlock()
+
+	m.running.Lock()
+	m.mu.Lock()
+	m.scanning = true
+	m.mu.Unlock()
+	return id
+}
+
+// finish records that the rescan with ID id returned.
+func (m *rescanManager) finish(id uint64) {
+	m.mu.Lock()
+	if m.latest == id {
+		m.scanning = false
+	}
+	m.mu.Unlock()
+	m.running.Unlock()
+}
+
+// superseded returns whether a rescan started after the rescan with ID id.
+func (m *rescanManager) superseded(id uint64) bool {
+	m.mu.Lock()
+	superseded := m.latest != id
+	m.mu.Unlock()
+	return superseded
+}
+
+// isScanning returns whether a rescan is running or waiting to run.
+func (m *rescanManager) isScanning() bool {
+	m.mu.Lock()
+	scanning := m.scanning
+	m.mu.Unlock()
+	return scanning
+}
+
+// IsScanning returns whether the wallet is rescanning the main chain.
+func (w *Wallet) IsScanning() bool {
+	return w.rescans.isScanning()
+}
+
+// RescanLimits describes the resource budget of rescans.  A zero value places
+// no limits on rescans.
+type RescanLimits struct {
+	// BlocksPerSecond caps the average rate at which blocks are rescanned.
+	// Zero disables the cap.
+	BlocksPerSecond int
+
+	// PauseRPCLoad pauses a rescan while at least this many RPC requests
+	// are being handled.  Zero disables pausing.
+	PauseRPCLoad int
+
+	// BlocksPerBatch is the number of blocks requested from the consensus
+	// server in each batch of a rescan, or in the first batch when
+	// AdaptiveBatches is set.  Zero uses the default of 2000 blocks.
+	BlocksPerBatch int
+
+	// AdaptiveBatches resizes each batch after the first from the time
+	// taken to rescan the previous batch and the number of transactions
+	// discovered in it, so that wallets with many transactions do not
+	// request huge responses and sparse wallets scan faster.
+	AdaptiveBatches bool
+}
+
+// RescanState describes whether a rescan is running and whether it is
+// currently being held back by the configured RescanLimits.
+type RescanState struct {
+	Scanning bool
+
+	// RateLimited is set while the rescan waits to stay under the
+	// configured blocks per second.
+	RateLimited bool
+
+	// Paused is set while the rescan waits for the RPC load to drop.
+	Paused bool
+
+	// BatchSize is the number of blocks requested in the current batch.
+	BatchSize int
+
+	// StartHeight is the height of the first block of the rescan, and
+	// ScannedThrough is the height of the last block it has completed, or
+	// one less than StartHeight before the first batch completes.
+	StartHeight    int32
+	ScannedThrough int32
+}
+
+// rescanPollInterval is how often a paused rescan checks whether the RPC load
+// has dropped.
+const rescanPollInterval = 250 * time.Millisecond
+
+// SetRescanLimits sets the resource budget of rescans.  The new limits apply
+// to any rescan already in progress.
+func (w *Wallet) SetRescanLimits(limits RescanLimits) {
+	w.rescanLimitsMu.Lock()
+	w.rescanLimits = limits
+	w.rescanLimitsMu.Unlock()
+}
+
+// RescanLimits returns the resource budget of rescans.
+func (w *Wallet) RescanLimits() RescanLimits {
+	w.rescanLimitsMu.Lock()
+	limits := w.rescanLimits
+	w.rescanLimitsMu.Unlock()
+	return limits
+}
+
+// RescanState returns the current state of rescans.  Unlike IsScanning, this
+// also describes whether the rescan is being held back by the RescanLimits.
+func (w *Wallet) RescanState() RescanState {
+	w.rescanLimitsMu.Lock()
+	state := w.rescanState
+	if state.Scanning {
+		state.BatchSize = w.rescanBatch
+		state.StartHeight = w.rescanStart
+		state.ScannedThrough = w.rescanThrough
+	}
+	w.rescanLimitsMu.Unlock()
+	return state
+}
+
+func (w *Wallet) setRescanBatch(blocks int) {
+	w.rescanLimitsMu.Lock()
+	w.rescanBatch = blocks
+	w.rescanLimitsMu.Unlock()
+}
+
+func (w *Wallet) setRescanProgress(start, scannedThrough int32) {
+	w.rescanLimitsMu.Lock()
+	w.rescanStart = start
+	w.rescanThrough = scannedThrough
+	w.rescanLimitsMu.Unlock()
+}
+
+func (w *Wallet) setRescanState(state RescanState) {
+	w.rescanLimitsMu.Lock()
+	w.rescanState = state
+	w.rescanLimitsMu.Unlock()
+}
+
+// RPCStarted records that an RPC server began handling a request.  Each call
+// must be paired with a call to RPCFinished.  The number of requests in
+// progress is used to pause rescans under high load.
+func (w *Wallet) RPCStarted() {
+	atomic.AddInt32(&w.rpcLoad, 1)
+}
+
+// RPCFinished records that an RPC server finished handling a request.
+func (w *Wallet) RPCFinished() {
+	atomic.AddInt32(&w.rpcLoad, -1)
+}
+
+// RPCLoad returns the number of RPC requests currently being handled.
+func (w *Wallet) RPCLoad() int {
+	return int(atomic.LoadInt32(&w.rpcLoad))
+}
+
+// initialRescanBatch returns the number of blocks to rescan in the first batch
+// of a rescan.
+func (w *Wallet) initialRescanBatch() int {
+	limits := w.RescanLimits()
+	if limits.BlocksPerBatch > 0 {
+		return limits.BlocksPerBatch
+	}
+	return maxBlocksPerRescan
+}
+
+// nextRescanBatch returns the size of the batch following a batch of blocks
+// which was rescanned in elapsed time and discovered txs transactions.  The
+// size is unchanged unless adaptive batches are enabled.
+func (w *Wallet) nextRescanBatch(blocks, txs int, elapsed time.Duration) int {
+	limits := w.RescanLimits()
+	if !limits.AdaptiveBatches {
+		return w.initialRescanBatch()
+	}
+	next := 2 * blocks
+	if elapsed > 0 {
+		n := int(int64(blocks) * int64(rescanBatchTarget) / int64(elapsed))
+		if n < next {
+			next = n
+		}
+	}
+	if txs > 0 {
+		n := blocks * rescanBatchTargetTxs / txs
+		if n < next {
+			next = n
+		}
+	}
+	if next < blocks/2 {
+		next = blocks / 2
+	}
+	if next < minRescanBatch {
+		next = minRescanBatch
+	}
+	if next > maxRescanBatch {
+		next = maxRescanBatch
+	}
+	return next
+}
 
-func (w *Wallet) IsScanning() bool{
-	mutexOnlyOneChan.Lock()
-	ret := isScanning
-	mutexOnlyOneChan.Unlock()
-	return ret
+// rescanBatchSize returns the number of blocks to rescan in a batch of the
+// requested size so that a single batch does not exceed one second of the
+// rate budget.
+func (w *Wallet) rescanBatchSize(batch int) int {
+	limits := w.RescanLimits()
+	if limits.BlocksPerSecond > 0 && limits.BlocksPerSecond < batch {
+		return limits.BlocksPerSecond
+	}
+	return batch
 }
+
+// waitRescanLoad blocks while the RPC load is at or above the configured
+// limit.  The return value is false if the rescan was cancelled while waiting.
+func (w *Wallet) waitRescanLoad(cancel <-chan struct{}) bool {
+	for {
+		limits := w.RescanLimits()
+		if limits.PauseRPCLoad <= 0 || w.RPCLoad() < limits.PauseRPCLoad {
+			w.setRescanState(RescanState{Scanning: true})
+			return true
+		}
+		w.setRescanState(RescanState{Scanning: true, Paused: true})
+		select {
+		case <-cancel:
+			return false
+		case <-time.After(rescanPollInterval):
+		}
+	}
+}
+
+// waitRescanRate blocks until scanning blocks, which began at start, fits
+// within the configured rate.  The return value is false if the rescan was
+// cancelled while waiting.
+func (w *Wallet) waitRescanRate(blocks int, start time.Time, cancel <-chan struct{}) bool {
+	limits := w.RescanLimits()
+	if limits.BlocksPerSecond <= 0 {
+		return true
+	}
+	budget := time.Duration(blocks) * time.Second / time.Duration(limits.BlocksPerSecond)
+	wait := budget - time.Since(start)
+	if wait <= 0 {
+		return true
+	}
+	w.setRescanState(RescanState{Scanning: true, RateLimited: true})
+	defer w.setRescanState(RescanState{Scanning: true})
+	select {
+	case <-cancel:
+		return false
+	case <-time.After(wait):
+		return true
+	}
+}
+
 // TODO: track whether a rescan is already in progress, and cancel either it or
 // this new rescan, keeping the one that still has the most blocks to scan.
 
@@ -40,26 +313,25 @@ func (w *Wallet) IsScanning() bool{
 // startHash and height up through the recorded main chain tip block.  The
 // progress channel, if non-nil, is sent non-error progress notifications with
 // the heights the rescan has completed through, starting with the start height.
-func (w *Wallet) rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Hash, height int32,
+func (w *Wallet) rescan(chainClient ChainClient, startHash *chainhash.Hash, height int32,
 	p chan<- RescanProgress, cancel <-chan struct{}) error {
 
-	if p == nil && isScanning{
+	if p == nil && w.rescans.isScanning() {
 		return nil
 	}
-	blockHashStorage := make([]chainhash.Hash, maxBlocksPerRescan)
+	var blockHashStorage []chainhash.Hash
 	rescanFrom := *startHash
 	inclusive := true
 
-	indexScanning++
-	index := indexScanning
-	mutexOnlyOneChan.Lock()
-	isScanning = true
+	id := w.rescans.start()
+	batch := w.initialRescanBatch()
+	w.setRescanState(RescanState{Scanning: true})
+	startHeight := height
+	w.setRescanProgress(startHeight, startHeight-1)
 
 	defer func() {
-		if indexScanning == index{
-			isScanning = false
-		}
-		mutexOnlyOneChan.Unlock()
+		w.setRescanState(RescanState{})
+		w.rescans.finish(id)
 	}()
 
 	for {
@@ -69,16 +341,27 @@ func (w *Wallet) rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Ha
 		default:
 		}
 
-		if indexScanning != index{
+		if w.rescans.superseded(id) {
+			return nil
+		}
+
+		if !w.waitRescanLoad(cancel) {
 			return nil
 		}
+		batchStart := time.Now()
+
+		batchSize := w.rescanBatchSize(batch)
+		if len(blockHashStorage) < batchSize {
+			blockHashStorage = make([]chainhash.Hash, batchSize)
+		}
+		w.setRescanBatch(batchSize)
 
 		var rescanBlocks []chainhash.Hash
 		err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
 			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
 			var err error
 			rescanBlocks, err = w.TxStore.GetMainChainBlockHashes(txmgrNs,
-				&rescanFrom, inclusive, blockHashStorage)
+				&rescanFrom, inclusive, blockHashStorage[:batchSize])
 			return err
 		})
 		if err != nil {
@@ -95,6 +378,31 @@ func (w *Wallet) rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Ha
 		if err != nil {
 			return err
 		}
+
+		// Transactions which are not proven to be included in their
+		// blocks are only recorded as unmined when mined transactions
+		// are verified.
+		unverified := make(map[string][][]byte)
+		if w.VerifyCredits() {
+			for _, r := range rescanResults.DiscoveredData {
+				blockHash, err := chainhash.NewHashFromStr(r.Hash)
+				if err != nil {
+					return err
+				}
+				txs := make([][]byte, 0, len(r.Transactions))
+				for _, hexTx := range r.Transactions {
+					serTx, err := hex.DecodeString(hexTx)
+					if err != nil {
+						return err
+					}
+					txs = append(txs, serTx)
+				}
+				_, u := w.verifyMinedTransactions(blockHash, txs)
+				if len(u) != 0 {
+					unverified[r.Hash] = u
+				}
+			}
+		}
 		var rawBlockHeader udb.RawBlockHeader
 		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
 			txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
@@ -117,12 +425,19 @@ func (w *Wallet) rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Ha
 					return err
 				}
 
+			nextTx:
 				for _, hexTx := range r.Transactions {
 					serTx, err := hex.DecodeString(hexTx)
 					if err != nil {
 						return err
 					}
-					err = w.processSerializedTransaction(dbtx, serTx, &rawBlockHeader, &blockMeta)
+					for _, u := range unverified[r.Hash] {
+						if bytes.Equal(serTx, u) {
+							continue nextTx
+						}
+					}
+					err = w.processSerializedTransaction(dbtx, serTx, &rawBlockHeader,
+						&blockMeta, "rescan")
 					if err != nil {
 						return err
 					}
@@ -142,18 +457,34 @@ func (w *Wallet) rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Ha
 		if err != nil {
 			return err
 		}
+		for hash, txs := range unverified {
+			blockHash, _ := chainhash.NewHashFromStr(hash)
+			w.processUnverifiedTransactions(blockHash, txs)
+		}
 		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
 			return w.TxStore.UpdateProcessedTxsBlockMarker(dbtx, &rescanBlocks[len(rescanBlocks)-1])
 		})
 		if err != nil {
 			return err
 		}
+		w.setRescanProgress(startHeight, scanningThrough)
 		if p != nil {
 			p <- RescanProgress{ScannedThrough: scanningThrough}
 		}
 		rescanFrom = rescanBlocks[len(rescanBlocks)-1]
 		height += int32(len(rescanBlocks))
 		inclusive = false
+
+		var discovered int
+		for _, r := range rescanResults.DiscoveredData {
+			discovered += len(r.Transactions)
+		}
+		batch = w.nextRescanBatch(len(rescanBlocks), discovered,
+			time.Since(batchStart))
+
+		if !w.waitRescanRate(len(rescanBlocks), batchStart, cancel) {
+			return nil
+		}
 	}
 }
 
@@ -163,7 +494,7 @@ func (w *Wallet) rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Ha
 // An error channel is returned for consumers of this API, but it is not
 // required to be read.  If the error can not be immediately written to the
 // returned channel, the error will be logged and the channel will be closed.
-func (w *Wallet) Rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Hash) <-chan error {
+func (w *Wallet) Rescan(chainClient ChainClient, startHash *chainhash.Hash) <-chan error {
 	errc := make(chan error)
 	go func() (err error) {
 		defer func() {
@@ -199,7 +530,7 @@ func (w *Wallet) Rescan(chainClient *hcrpcclient.Client, startHash *chainhash.Ha
 
 // RescanFromHeight is an alternative to Rescan that takes a block height
 // instead of a hash.  See Rescan for more details.
-func (w *Wallet) RescanFromHeight(chainClient *hcrpcclient.Client, startHeight int32) <-chan error {
+func (w *Wallet) RescanFromHeight(chainClient ChainClient, startHeight int32) <-chan error {
 	errc := make(chan error)
 
 	go func() (err error) {
@@ -220,21 +551,12 @@ func (w *Wallet) RescanFromHeight(chainClient *hcrpcclient.Client, startHeight i
 			req := omnilib.Request{
 				Method: "omni_getwaterline",
 			}
-			bytes, err := json.Marshal(req)
-			if err != nil {
-				return err
-			}
-			strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-			var response hcjson.Response
-			err = json.Unmarshal([]byte(strRsp), &response)
+			result, err := omnilib.Send(&req)
 			if err != nil {
 				return err
 			}
-			if response.Error != nil {
-				return fmt.Errorf(response.Error.Message)
-			}
-			omni_height, err := strconv.Atoi(string(response.Result))
-			if(omni_height <= 0){//need scanwallet from 0
+			omni_height, err := strconv.Atoi(string(result))
+			if omni_height <= 0 { //need scanwallet from 0
 				omni_height = int(startHeight)
 			}
 			startHeight = int32(omni_height)
@@ -268,7 +590,7 @@ type RescanProgress struct {
 // the main chain starting at startHeight.  Progress notifications and any
 // errors are sent to the channel p.  This function blocks until the rescan
 // completes or ends in an error.  p is closed before returning.
-func (w *Wallet) RescanProgressFromHeight(chainClient *hcrpcclient.Client, startHeight int32, p chan<- RescanProgress, cancel <-chan struct{}) {
+func (w *Wallet) RescanProgressFromHeight(chainClient ChainClient, startHeight int32, p chan<- RescanProgress, cancel <-chan struct{}) {
 	defer close(p)
 
 	var startHash chainhash.Hash
@@ -0,0 +1,91 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRescanManager(t *testing.T) {
+	var m rescanManager
+	if m.isScanning() {
+		t.Fatal("zero value rescan manager is scanning")
+	}
+
+	first := m.start()
+	if !m.isScanning() {
+		t.Fatal("not scanning after starting a rescan")
+	}
+	if m.superseded(first) {
+		t.Fatal("only rescan is superseded")
+	}
+
+	// A second rescan supersedes the first, and waits for it to finish.
+	started := make(chan uint64)
+	go func() {
+		started <- m.start()
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for !m.superseded(first) {
+		if time.Now().After(deadline) {
+			t.Fatal("first rescan was not superseded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-started:
+		t.Fatal("second rescan started before the first finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Finishing the superseded rescan lets the second run, and the manager
+	// remains scanning until the second finishes.
+	m.finish(first)
+	var second uint64
+	select {
+	case second = <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second rescan did not start after the first finished")
+	}
+	if second == first {
+		t.Fatalf("rescans share ID %d", first)
+	}
+	if !m.isScanning() {
+		t.Error("not scanning after the superseded rescan finished")
+	}
+	if m.superseded(second) {
+		t.Error("latest rescan is superseded")
+	}
+	m.finish(second)
+	if m.isScanning() {
+		t.Error("scanning after all rescans finished")
+	}
+}
+
+func TestRescanStateProgress(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	w.setRescanProgress(10, 9)
+	if state := w.RescanState(); state.Scanning || state.StartHeight != 0 ||
+		state.ScannedThrough != 0 {
+		t.Errorf("rescan state %+v reports progress without a rescan", state)
+	}
+
+	w.setRescanState(RescanState{Scanning: true})
+	w.setRescanBatch(50)
+	w.setRescanProgress(10, 9)
+	state := w.RescanState()
+	if !state.Scanning || state.BatchSize != 50 || state.StartHeight != 10 ||
+		state.ScannedThrough != 9 {
+		t.Errorf("rescan state %+v before the first batch", state)
+	}
+	w.setRescanProgress(10, 59)
+	if state := w.RescanState(); state.ScannedThrough != 59 {
+		t.Errorf("rescan state %+v after the first batch", state)
+	}
+	w.setRescanState(RescanState{})
+}
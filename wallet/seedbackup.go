@@ -0,0 +1,56 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/pgpwordlist"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SeedBackupChallenge returns the challenge to verify a backup of the wallet
+// seed.  Wallets which were not created from a seed by this software have no
+// challenge, and an error with code ErrValueNoExists is returned.
+func (w *Wallet) SeedBackupChallenge() (*udb.SeedBackupChallenge, error) {
+	var c *udb.SeedBackupChallenge
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		var err error
+		c, err = w.Manager.SeedBackupChallenge(dbtx.ReadBucket(waddrmgrNamespaceKey))
+		return err
+	})
+	return c, err
+}
+
+// VerifySeedBackup checks the mnemonic words of a seed backup at each of the
+// seed backup challenge positions, in order, against the seed the wallet was
+// created from.  The seed backup is recorded as verified when all words match.
+// Which words did not match is never revealed.  The wallet must be unlocked.
+func (w *Wallet) VerifySeedBackup(words []string) (bool, error) {
+	c, err := w.SeedBackupChallenge()
+	if err != nil {
+		return false, err
+	}
+	if len(words) != len(c.Positions) {
+		str := "number of words does not match the seed backup challenge"
+		return false, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	decoded := make([]byte, len(words))
+	for i, word := range words {
+		decoded[i], err = pgpwordlist.MnemonicToByte(word, c.Positions[i])
+		if err != nil {
+			return false, apperrors.E{ErrorCode: apperrors.ErrInput, Description: err.Error(), Err: nil}
+		}
+	}
+
+	ok, err := w.Manager.CheckSeedBackup(c, decoded)
+	if err != nil || !ok {
+		return false, err
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.Manager.MarkSeedBackupVerified(dbtx.ReadWriteBucket(waddrmgrNamespaceKey))
+	})
+	return err == nil, err
+}
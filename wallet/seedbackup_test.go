@@ -0,0 +1,93 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/pgpwordlist"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestVerifySeedBackup(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	c, err := w.SeedBackupChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Verified {
+		t.Fatal("new wallet reports a verified seed backup")
+	}
+	words := make([]string, len(c.Positions))
+	for i, pos := range c.Positions {
+		words[i] = pgpwordlist.ByteToMnemonic(testSeed[pos], pos)
+	}
+
+	// The checked seed bytes must not be recoverable from the database
+	// without the private passphrase.
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		v := dbtx.ReadBucket(waddrmgrNamespaceKey).NestedReadBucket(
+			[]byte("main")).Get([]byte("seedbackup"))
+		if v == nil {
+			t.Fatal("seed backup challenge is not recorded")
+		}
+		checked := make([]byte, len(c.Positions))
+		for i, pos := range c.Positions {
+			checked[i] = testSeed[pos]
+		}
+		if bytes.Contains(v, checked) {
+			t.Error("challenge records the checked seed bytes")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w.Manager.Lock()
+	_, err = w.VerifySeedBackup(words)
+	if !apperrors.IsError(err, apperrors.ErrLocked) {
+		t.Fatalf("verifying a locked wallet returned %v, want ErrLocked", err)
+	}
+
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		return w.Manager.Unlock(dbtx.ReadBucket(waddrmgrNamespaceKey),
+			testPrivPass)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrong := append([]string(nil), words...)
+	last := len(wrong) - 1
+	pos := c.Positions[last]
+	wrong[last] = pgpwordlist.ByteToMnemonic(testSeed[pos]+1, pos)
+	ok, err := w.VerifySeedBackup(wrong)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("wrong seed words were verified")
+	}
+
+	ok, err = w.VerifySeedBackup(words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("seed words were not verified")
+	}
+	c, err = w.SeedBackupChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Verified {
+		t.Fatal("verified seed backup is not recorded")
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/internal/txsizes"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// BatchTx describes a transaction published by SendBatch.
+type BatchTx struct {
+	Hash chainhash.Hash
+	Fee  hcutil.Amount
+}
+
+// BatchOutputAssignment describes the transaction output paying an output of
+// a batch.  Tx is the index of the transaction in the Txs of the batch result,
+// or -1 if the output was not paid.
+type BatchOutputAssignment struct {
+	Tx   int
+	Vout uint32
+}
+
+// SendBatchResult describes the transactions published by SendBatch.
+type SendBatchResult struct {
+	// Txs describes each published transaction, in the order they were
+	// published.
+	Txs []BatchTx
+
+	// Outputs describes the transaction output paying each output of the
+	// batch, in the order of the requested outputs.
+	Outputs []BatchOutputAssignment
+
+	// Err describes why the remaining outputs were not paid after some
+	// transactions were published.
+	Err error
+}
+
+// batchTx is a signed transaction paying outputs of a batch.
+type batchTx struct {
+	tx      *wire.MsgTx
+	rec     *udb.TxRecord
+	fee     hcutil.Amount
+	outputs []int
+	vouts   []uint32
+}
+
+// SendBatch pays many outputs from an account with as few transactions as the
+// transaction size limits allow.  Outputs are assigned, in order, to
+// transactions sized using estimates of their signed serialize size, and
+// transactions which exceed the size or input limits once their inputs are
+// selected are shrunk until they do not.
+//
+// When createFirst is set, every transaction is created and signed before any
+// is published, and no transaction is published unless all outputs can be
+// funded.  This is not atomic: the consensus server may still reject a
+// transaction after accepting earlier ones, which remain published, and only
+// the rejected and later transactions are removed from the wallet.  Otherwise,
+// transactions are published as they are created, and an error creating or
+// publishing a transaction stops the batch with the earlier transactions
+// published.  In both modes an error is only returned when no transaction was
+// published.  Once a transaction is published, the result describes the
+// published transactions and why any remaining outputs were not paid.
+func (w *Wallet) SendBatch(outputs []*wire.TxOut, account uint32, minconf int32,
+	createFirst bool) (*SendBatchResult, error) {
+
+	if len(outputs) == 0 {
+		const str = "batch has no outputs"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, err
+	}
+	for _, output := range outputs {
+		err := txrules.CheckOutput(output, relayFee)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &SendBatchResult{
+		Outputs: make([]BatchOutputAssignment, len(outputs)),
+	}
+	for i := range res.Outputs {
+		res.Outputs[i].Tx = -1
+	}
+	pending := make([]int, len(outputs))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	if createFirst {
+		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			var txs []*batchTx
+			for len(pending) != 0 {
+				btx, err := w.authorBatchTx(dbtx, outputs, pending, account,
+					minconf, relayFee)
+				if err != nil {
+					return err
+				}
+				txs = append(txs, btx)
+				pending = pending[len(btx.outputs):]
+			}
+
+			txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+			for i, btx := range txs {
+				_, err := w.sendRawTransaction(txmgrNs, chainClient, btx.tx,
+					w.AllowHighFees())
+				if err == nil {
+					res.addTx(btx)
+					continue
+				}
+				if i == 0 {
+					return err
+				}
+
+				// Earlier transactions were accepted and can not be
+				// revoked, so only the unpublished transactions are
+				// removed.
+				res.Err = err
+				for j := len(txs) - 1; j >= i; j-- {
+					removed, err := w.TxStore.RemoveUnminedTx(txmgrNs,
+						&txs[j].rec.Hash)
+					if err != nil {
+						return err
+					}
+					for _, r := range removed {
+						err := w.auditRemoval(txmgrNs, &r.Hash,
+							udb.AuditAbandon, -1, "sendbatch")
+						if err != nil {
+							return err
+						}
+					}
+				}
+				break
+			}
+			return nil
+		})
+	} else {
+		for len(pending) != 0 {
+			var btx *batchTx
+			err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+				var err error
+				btx, err = w.authorBatchTx(dbtx, outputs, pending, account,
+					minconf, relayFee)
+				if err != nil {
+					return err
+				}
+				txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+				_, err = w.sendRawTransaction(txmgrNs, chainClient, btx.tx,
+					w.AllowHighFees())
+				return err
+			})
+			if err != nil {
+				break
+			}
+			res.addTx(btx)
+			pending = pending[len(btx.outputs):]
+		}
+		if err != nil && len(res.Txs) != 0 {
+			res.Err = err
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range res.Txs {
+		log.Infof("Published batch transaction %v", &res.Txs[i].Hash)
+	}
+	if res.Err != nil {
+		paid := 0
+		for _, a := range res.Outputs {
+			if a.Tx != -1 {
+				paid++
+			}
+		}
+		log.Errorf("Batch stopped after %d of %d outputs: %v", paid,
+			len(outputs), res.Err)
+	}
+
+	// Watch for future address usage.
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		return w.watchFutureAddresses(dbtx)
+	})
+	if err != nil {
+		log.Errorf("Failed to watch for future address usage after publishing "+
+			"batch transactions: %v", err)
+	}
+	return res, nil
+}
+
+// addTx records the outputs paid by a published batch transaction.
+func (res *SendBatchResult) addTx(btx *batchTx) {
+	for i, output := range btx.outputs {
+		res.Outputs[output] = BatchOutputAssignment{
+			Tx:   len(res.Txs),
+			Vout: btx.vouts[i],
+		}
+	}
+	res.Txs = append(res.Txs, BatchTx{Hash: btx.rec.Hash, Fee: btx.fee})
+}
+
+// batchTxMaxSize returns the maximum estimated serialize size of batch
+// transactions, which must be standard and within the wallet's size limit.
+func (w *Wallet) batchTxMaxSize() int {
+	maxSize := maxStandardTxSize
+	if w.txLimits.MaxSerializeSize > 0 && w.txLimits.MaxSerializeSize < maxSize {
+		maxSize = w.txLimits.MaxSerializeSize
+	}
+	return maxSize
+}
+
+// authorBatchTx creates, signs, and records a transaction paying a prefix of
+// the pending batch outputs.  The prefix begins as the most outputs which fit
+// the size limit of a transaction spending a single input, and is shrunk
+// while the transaction exceeds the limits after selecting its inputs.
+func (w *Wallet) authorBatchTx(dbtx walletdb.ReadWriteTx, outputs []*wire.TxOut,
+	pending []int, account uint32, minconf int32, relayFee hcutil.Amount) (*batchTx, error) {
+
+	var doneFuncs []func()
+	defer func() {
+		for _, f := range doneFuncs {
+			f()
+		}
+	}()
+
+	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+	accprop, err := w.Manager.AccountProperties(addrmgrNs, account)
+	if err != nil {
+		return nil, err
+	}
+	accType := accprop.AccountType
+	if accType != udb.AcctypeBliss {
+		accType = udb.AcctypeEc
+	}
+
+	limits := w.txLimits
+	limits.MaxSerializeSize = w.batchTxMaxSize()
+
+	chunk := make([]*wire.TxOut, 0, len(pending))
+	for _, output := range pending {
+		chunk = append(chunk, outputs[output])
+		size, _ := txsizes.EstimateSerializeSizeByAccount(1, chunk, true, accType)
+		if size > limits.MaxSerializeSize {
+			chunk = chunk[:len(chunk)-1]
+			break
+		}
+	}
+
+	_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+	getScript := txscript.ScriptClosure(func(addr hcutil.Address) ([]byte, error) {
+		// First check tx manager script store.
+		scrTxStore, err := w.TxStore.GetTxScript(txmgrNs, addr.ScriptAddress())
+		if err != nil {
+			return nil, err
+		}
+		if scrTxStore != nil {
+			return scrTxStore, nil
+		}
+
+		// Then check the address manager.
+		script, done, err := w.Manager.RedeemScript(addrmgrNs, addr)
+		if err != nil {
+			return nil, err
+		}
+		doneFuncs = append(doneFuncs, done)
+		return script, nil
+	})
+
+	var atx *txauthor.AuthoredTx
+	var changeSourceUpdates []func(walletdb.ReadWriteTx) error
+	for {
+		if len(chunk) == 0 {
+			const str = "batch output can not be paid by a transaction " +
+				"within the size limits"
+			return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+
+		// Change addresses are derived with the update transaction, since
+		// the address pool would otherwise begin a nested update.
+		changeSourceUpdates = changeSourceUpdates[:0]
+		persist := w.deferPersistReturnedChild(&changeSourceUpdates)
+		changeSource := w.changeSource(persist, account, nil)
+		fetchChange := func(walletdb.ReadWriteTx) ([]byte, uint16, error) {
+			return changeSource(dbtx)
+		}
+		inputSource := w.TxStore.MakeInputSource(txmgrNs, addrmgrNs, account,
+			minconf, tipHeight)
+		atx, err = txauthor.NewUnsignedTransaction(chunk, relayFee,
+			inputSource.SelectInputs, fetchChange, accType, w.chainParams,
+			getScript, "", limits)
+		switch e := err.(type) {
+		case nil:
+		case txauthor.TxTooLargeError:
+			// Remove outputs until their serialized size covers the
+			// excess.
+			excess := e.Size - e.MaxSize
+			for excess > 0 && len(chunk) != 0 {
+				excess -= chunk[len(chunk)-1].SerializeSize()
+				chunk = chunk[:len(chunk)-1]
+			}
+			continue
+		case txauthor.TooManyInputsError:
+			chunk = chunk[:len(chunk)/2]
+			continue
+		default:
+			return nil, err
+		}
+		break
+	}
+
+	vouts := make([]uint32, len(chunk))
+	for i := range vouts {
+		vouts[i] = uint32(i)
+	}
+	if atx.ChangeIndex >= 0 {
+		changeIndex := atx.ChangeIndex
+		atx.RandomizeChangePosition()
+		if atx.ChangeIndex != changeIndex {
+			vouts[atx.ChangeIndex] = uint32(changeIndex)
+		}
+	}
+
+	secrets := &secretSource{Manager: w.Manager, addrmgrNs: addrmgrNs}
+	err = atx.AddAllInputScripts(secrets)
+	for _, done := range secrets.doneFuncs {
+		done()
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = validateMsgTx(atx.Tx, atx.PrevScripts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, up := range changeSourceUpdates {
+		err := up(dbtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rec, err := udb.NewTxRecordFromMsgTx(atx.Tx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = w.processTransactionRecord(dbtx, rec, nil, nil, "sendbatch")
+	if err != nil {
+		return nil, err
+	}
+
+	var outputAmount hcutil.Amount
+	for _, out := range atx.Tx.TxOut {
+		outputAmount += hcutil.Amount(out.Value)
+	}
+	return &batchTx{
+		tx:      atx.Tx,
+		rec:     rec,
+		fee:     atx.TotalInput - outputAmount,
+		outputs: pending[:len(chunk)],
+		vouts:   vouts,
+	}, nil
+}
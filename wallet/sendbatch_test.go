@@ -0,0 +1,188 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// batchTestWallet returns an unlocked wallet with ten unmined 10 coin credits,
+// associated with a mock chain client, whose transactions are limited to a
+// size which requires a batch of ten outputs to be split into several
+// transactions.
+func batchTestWallet(t *testing.T) (*Wallet, *testhelpers.MockChainClient, func()) {
+	w, teardown := testWallet(t)
+	if err := w.Unlock(testPrivPass, nil); err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, foreignSigScript))
+	for i := uint32(0); i < 10; i++ {
+		funding.AddTxOut(wire.NewTxOut(10e8,
+			walletPkScript(t, w, udb.ExternalBranch, i)))
+	}
+	addUnminedTx(t, w, funding)
+
+	c := testhelpers.NewMockChainClient()
+	w.chainClientLock.Lock()
+	w.chainClient = c
+	w.chainClientLock.Unlock()
+	w.txLimits.MaxSerializeSize = 500
+
+	return w, c, teardown
+}
+
+func batchOutputs(n int) []*wire.TxOut {
+	outputs := make([]*wire.TxOut, n)
+	for i := range outputs {
+		outputs[i] = wire.NewTxOut(1e8, foreignPkScript)
+	}
+	return outputs
+}
+
+// unminedTxCount returns the number of unmined transactions recorded by the
+// wallet.
+func unminedTxCount(t *testing.T, w *Wallet) int {
+	var n int
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		hashes, err := w.TxStore.UnminedTxHashes(dbtx.ReadBucket(wtxmgrNamespaceKey))
+		n = len(hashes)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// checkBatchResult checks that each published transaction of res was sent to
+// c and that exactly the first paid outputs of the batch are assigned to
+// published transactions.
+func checkBatchResult(t *testing.T, res *SendBatchResult, c *testhelpers.MockChainClient, paid int) {
+	sent := c.SentTransactions()
+	if len(sent) != len(res.Txs) {
+		t.Fatalf("%d transactions were sent for %d published transactions",
+			len(sent), len(res.Txs))
+	}
+	for i := range res.Txs {
+		if sent[i].TxHash() != res.Txs[i].Hash {
+			t.Errorf("transaction %d of the result is not the one sent", i)
+		}
+	}
+	for i, a := range res.Outputs {
+		if i >= paid {
+			if a.Tx != -1 {
+				t.Errorf("output %d was assigned after the batch stopped", i)
+			}
+			continue
+		}
+		if a.Tx < 0 || a.Tx >= len(res.Txs) {
+			t.Errorf("paid output %d is assigned to transaction %d", i, a.Tx)
+			continue
+		}
+		out := sent[a.Tx].TxOut[a.Vout]
+		if out.Value != 1e8 {
+			t.Errorf("output %d is assigned to an output paying %v", i,
+				out.Value)
+		}
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	for _, createFirst := range []bool{false, true} {
+		w, c, teardown := batchTestWallet(t)
+
+		res, err := w.SendBatch(batchOutputs(10), udb.DefaultAccountNum, 0,
+			createFirst)
+		if err != nil {
+			teardown()
+			t.Fatalf("createFirst=%v: %v", createFirst, err)
+		}
+		if len(res.Txs) < 2 {
+			t.Errorf("createFirst=%v: batch was paid by %d transactions, "+
+				"want several", createFirst, len(res.Txs))
+		}
+		if res.Err != nil {
+			t.Errorf("createFirst=%v: batch stopped: %v", createFirst, res.Err)
+		}
+		checkBatchResult(t, res, c, 10)
+		teardown()
+	}
+}
+
+func TestSendBatchRejected(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	for _, createFirst := range []bool{false, true} {
+		w, c, teardown := batchTestWallet(t)
+		c.SendErr = errRejected
+		c.SendErrAfter = 1
+
+		res, err := w.SendBatch(batchOutputs(10), udb.DefaultAccountNum, 0,
+			createFirst)
+		if err != nil {
+			teardown()
+			t.Fatalf("createFirst=%v: %v", createFirst, err)
+		}
+
+		// The first transaction remains published after the consensus
+		// server rejects the second in both modes.
+		if len(res.Txs) != 1 {
+			t.Errorf("createFirst=%v: %d transactions were published, want 1",
+				createFirst, len(res.Txs))
+		}
+		if res.Err != errRejected {
+			t.Errorf("createFirst=%v: batch error %v, want %v", createFirst,
+				res.Err, errRejected)
+		}
+		paid := 0
+		for _, a := range res.Outputs {
+			if a.Tx != -1 {
+				paid++
+			}
+		}
+		if paid == 0 || paid == 10 {
+			t.Errorf("createFirst=%v: %d of 10 outputs paid", createFirst, paid)
+		}
+		checkBatchResult(t, res, c, paid)
+
+		// Only the funding and published transactions remain recorded.
+		if n := unminedTxCount(t, w); n != 2 {
+			t.Errorf("createFirst=%v: wallet records %d unmined transactions, "+
+				"want 2", createFirst, n)
+		}
+		teardown()
+	}
+}
+
+func TestSendBatchFirstRejected(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	for _, createFirst := range []bool{false, true} {
+		w, c, teardown := batchTestWallet(t)
+		c.SendErr = errRejected
+
+		_, err := w.SendBatch(batchOutputs(10), udb.DefaultAccountNum, 0,
+			createFirst)
+		if err != errRejected {
+			t.Errorf("createFirst=%v: error %v, want %v", createFirst, err,
+				errRejected)
+		}
+		if n := unminedTxCount(t, w); n != 1 {
+			t.Errorf("createFirst=%v: wallet records %d unmined transactions, "+
+				"want only the funding transaction", createFirst, n)
+		}
+		teardown()
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AccountSpendableConfs returns the confirmations an account requires before
+// its outputs are considered spendable by balance calculations and input
+// selection.  The zero value is returned for accounts which only require the
+// requested confirmations and the maturities of the chain.
+func (w *Wallet) AccountSpendableConfs(account uint32) (udb.SpendableConfs, error) {
+	var confs udb.SpendableConfs
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, err := w.Manager.AccountName(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		confs, err = w.TxStore.SpendableConfs(txmgrNs, account)
+		return err
+	})
+	return confs, err
+}
+
+// SetAccountSpendableConfs sets the confirmations an account requires before
+// its outputs are considered spendable.  Outputs must meet both these
+// requirements and those of the request and chain, so the requirements may
+// only delay spending.  Setting the zero value removes the requirements.
+func (w *Wallet) SetAccountSpendableConfs(account uint32, confs *udb.SpendableConfs) error {
+	return walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		_, err := w.Manager.AccountName(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		return w.TxStore.SetSpendableConfs(txmgrNs, account, confs)
+	})
+}
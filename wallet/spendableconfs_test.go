@@ -0,0 +1,61 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestAccountSpendableConfs(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	addSpendWithChange(t, w)
+	eligible := func() int {
+		var n int
+		err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+			_, tipHeight := w.MainChainTip()
+			credits, err := w.findEligibleOutputs(dbtx,
+				udb.DefaultAccountNum, 0, tipHeight)
+			n = len(credits)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if n := eligible(); n != 1 {
+		t.Fatalf("%d eligible outputs, want the unmined change output", n)
+	}
+
+	// Requiring a confirmation excludes the unmined change.
+	confs := udb.SpendableConfs{Regular: 1}
+	err := w.SetAccountSpendableConfs(udb.DefaultAccountNum, &confs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := w.AccountSpendableConfs(udb.DefaultAccountNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != confs {
+		t.Errorf("account requires %+v, want %+v", got, confs)
+	}
+	if n := eligible(); n != 0 {
+		t.Errorf("%d eligible outputs when a confirmation is required", n)
+	}
+
+	if err := w.SetAccountSpendableConfs(2, &confs); err == nil {
+		t.Error("set requirements of a nonexistent account")
+	}
+	if _, err := w.AccountSpendableConfs(2); err == nil {
+		t.Error("read requirements of a nonexistent account")
+	}
+}
@@ -8,7 +8,9 @@ package wallet
 import (
 	"errors"
 
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
 	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
 )
@@ -32,3 +34,90 @@ func (w *Wallet) StakePoolUserInfo(userAddress hcutil.Address) (*udb.StakePoolUs
 	})
 	return user, err
 }
+
+// SetStakePoolFeeExemption exempts a stake pool user, identified by their
+// voting address, from the pool fee check, or removes the exemption.  Tickets
+// of exempt users which commit less than the required pool fee are accepted.
+func (w *Wallet) SetStakePoolFeeExemption(user hcutil.Address, exempt bool) error {
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		stakemgrNs := tx.ReadWriteBucket(wstakemgrNamespaceKey)
+		return w.StakeMgr.SetStakePoolFeeExemption(stakemgrNs, user, exempt)
+	})
+}
+
+// StakePoolFeeExemptions returns the stake pool users exempt from the pool fee
+// check.
+func (w *Wallet) StakePoolFeeExemptions() ([]hcutil.Address, error) {
+	var users []hcutil.Address
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		stakemgrNs := tx.ReadBucket(wstakemgrNamespaceKey)
+		var err error
+		users, err = w.StakeMgr.StakePoolFeeExemptions(stakemgrNs)
+		return err
+	})
+	return users, err
+}
+
+// ReevaluateStakePoolTickets evaluates the previously rejected tickets of a
+// stake pool user again, such as after the user was exempted from the pool fee
+// check or pool fees were lowered.  Tickets which are now acceptable are
+// readmitted as valid tickets of the user and tracked for voting, and the
+// rejections of the remaining tickets are updated.  The hashes of the
+// readmitted tickets are returned.
+func (w *Wallet) ReevaluateStakePoolTickets(user hcutil.Address) ([]chainhash.Hash, error) {
+	if !w.stakePoolEnabled {
+		const str = "wallet is not operating as a stake pool"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	var readmitted []chainhash.Hash
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		stakemgrNs := tx.ReadWriteBucket(wstakemgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+
+		info, err := w.StakeMgr.StakePoolUserInfo(stakemgrNs, user)
+		if err != nil {
+			return err
+		}
+		for _, r := range info.Rejections {
+			details, err := w.TxStore.TxDetails(txmgrNs, &r.Ticket)
+			if err != nil {
+				return err
+			}
+			// Only mined tickets are evaluated.
+			if details == nil || details.Block.Height == -1 {
+				continue
+			}
+			height := details.Block.Height
+
+			rejection := w.evaluateStakePoolTicket(stakemgrNs,
+				&details.TxRecord, height, user)
+			if rejection != nil {
+				err = w.StakeMgr.UpdateStakePoolUserInvalTickets(
+					stakemgrNs, user, rejection)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			err = w.updateStakePoolInvalidTicket(stakemgrNs, user,
+				&r.Ticket, int64(height))
+			if err != nil {
+				return err
+			}
+			err = w.StakeMgr.InsertSStx(stakemgrNs, hcutil.NewTx(&details.MsgTx))
+			if err != nil {
+				return err
+			}
+			log.Infof("Readmitted stake pool ticket %v for user %v",
+				&r.Ticket, user.EncodeAddress())
+			readmitted = append(readmitted, r.Ticket)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return readmitted, nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+)
+
+func TestStakePoolFeeExemptions(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	user, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), w.chainParams, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.SetStakePoolFeeExemption(user, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := w.StakePoolFeeExemptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].EncodeAddress() != user.EncodeAddress() {
+		t.Errorf("exempt users %v, want %v", users, user)
+	}
+
+	// Tickets are only reevaluated by stake pools.
+	_, err = w.ReevaluateStakePoolTickets(user)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("reevaluating tickets without a stake pool returned %v, "+
+			"want ErrInput", err)
+	}
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/HcashOrg/bitset"
 	"github.com/HcashOrg/hcd/hcutil"
 	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
-	hcrpcclient "github.com/HcashOrg/hcrpcclient"
+	"github.com/HcashOrg/hcwallet/apperrors"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
 )
@@ -27,7 +27,7 @@ type result struct {
 	err     error
 }
 
-func (w *Wallet) findLastUsedAccount(client *hcrpcclient.Client, coinTypeXpriv *hdkeychain.ExtendedKey) (uint32, uint32, map[uint32]result, error) {
+func (w *Wallet) findLastUsedAccount(client ChainClient, coinTypeXpriv *hdkeychain.ExtendedKey) (uint32, uint32, map[uint32]result, error) {
 	const scanLen = 100
 
 	var lastRecorded uint32
@@ -144,7 +144,7 @@ Bsearch:
 	return lastRecorded, lastUsed, requestAccount, nil
 }
 
-func (w *Wallet) findLastUsedAccountForTest(client *hcrpcclient.Client, coinTypeXpriv *hdkeychain.ExtendedKey) (uint32, uint32, map[uint32]result, bool, error) {
+func (w *Wallet) findLastUsedAccountForTest(client ChainClient, coinTypeXpriv *hdkeychain.ExtendedKey) (uint32, uint32, map[uint32]result, bool, error) {
 	var lastRecorded uint32
 	var accountinfo *udb.AccountProperties
 	var havebliss bool
@@ -256,7 +256,7 @@ Bsearch:
 	return lastRecorded, lastUsed, requestAccount, havebliss, nil
 }
 
-func (w *Wallet) newAcctAndUsed(client *hcrpcclient.Client, coinTypeXpriv *hdkeychain.ExtendedKey, account uint32, acctype uint8) (bool, error) {
+func (w *Wallet) newAcctAndUsed(client ChainClient, coinTypeXpriv *hdkeychain.ExtendedKey, account uint32, acctype uint8) (bool, error) {
 	xpriv, err := coinTypeXpriv.SwitchChild(hdkeychain.HardenedKeyStart+account, acctype)
 	if err != nil {
 		return false, err
@@ -276,7 +276,7 @@ func (w *Wallet) newAcctAndUsed(client *hcrpcclient.Client, coinTypeXpriv *hdkey
 	return used, nil
 }
 
-func (w *Wallet) accountUsed(client *hcrpcclient.Client, xpub, xpriv *hdkeychain.ExtendedKey) (bool, error) {
+func (w *Wallet) accountUsed(client ChainClient, xpub, xpriv *hdkeychain.ExtendedKey) (bool, error) {
 	var err error
 	var extKey, intKey, intKeypriv, extKeypriv *hdkeychain.ExtendedKey
 	if xpub.GetAlgType() == udb.AcctypeEc {
@@ -318,7 +318,7 @@ func (w *Wallet) accountUsed(client *hcrpcclient.Client, xpub, xpriv *hdkeychain
 	return false, nil
 }
 
-func (w *Wallet) branchUsed(client *hcrpcclient.Client, branchXpub, branchXpriv *hdkeychain.ExtendedKey) (bool, error) {
+func (w *Wallet) branchUsed(client ChainClient, branchXpub, branchXpriv *hdkeychain.ExtendedKey) (bool, error) {
 	var err error
 	addrs := make([]hcutil.Address, 0, w.gapLimit)
 	if branchXpub.GetAlgType() == udb.AcctypeEc {
@@ -344,7 +344,7 @@ func (w *Wallet) branchUsed(client *hcrpcclient.Client, branchXpub, branchXpriv
 // findLastUsedAddress returns the child index of the last used child address
 // derived from a branch key.  If no addresses are found, ^uint32(0) is
 // returned.
-func (w *Wallet) findLastUsedAddress(client *hcrpcclient.Client, branchkey *hdkeychain.ExtendedKey, account, branch uint32) (uint32, error) {
+func (w *Wallet) findLastUsedAddress(client ChainClient, branchkey *hdkeychain.ExtendedKey, account, branch uint32) (uint32, error) {
 	var (
 		lastUsed        = ^uint32(0)
 		scanLen         = uint32(w.gapLimit)
@@ -411,11 +411,18 @@ func (w *Wallet) FindactiveAddressesForBliss(account, branch, start, count uint3
 // account extended pubkeys.
 //
 // A transaction filter (re)load and rescan should be performed after discovery.
-func (w *Wallet) DiscoverActiveAddresses(chainClient *hcrpcclient.Client, discoverAccts bool) error {
+func (w *Wallet) DiscoverActiveAddresses(chainClient ChainClient, discoverAccts bool) error {
+	return w.discoverActiveAddresses(chainClient, discoverAccts, nil)
+}
+
+// discoverActiveAddresses implements DiscoverActiveAddresses.  If ckpt is
+// non-nil, discovery already recorded by the checkpoint is skipped and the
+// results of any new discovery are added to it.
+func (w *Wallet) discoverActiveAddresses(chainClient ChainClient, discoverAccts bool, ckpt *syncCheckpointer) error {
 	// Start by rescanning the accounts and determining what the
 	// current account index is. This scan should only ever be
 	// performed if we're restoring our wallet from seed.
-	if discoverAccts {
+	if discoverAccts && !ckpt.accountsDiscovered() {
 		log.Infof("Discovering used accounts")
 		var coinTypePrivKey *hdkeychain.ExtendedKey
 		defer func() {
@@ -485,6 +492,7 @@ func (w *Wallet) DiscoverActiveAddresses(chainClient *hcrpcclient.Client, discov
 				w.addressBuffersMu.Unlock()
 				return err
 			}
+			w.accountNames.invalidate()
 			for acct := lastRecorded + 1; acct <= lastUsed; acct++ {
 				_, ok := w.addressBuffers[acct]
 				if !ok {
@@ -529,6 +537,11 @@ func (w *Wallet) DiscoverActiveAddresses(chainClient *hcrpcclient.Client, discov
 			}
 			w.addressBuffersMu.Unlock()
 		}
+
+		err = ckpt.recordAccounts()
+		if err != nil {
+			return err
+		}
 	}
 
 	var lastAcct uint32
@@ -575,10 +588,13 @@ func (w *Wallet) DiscoverActiveAddresses(chainClient *hcrpcclient.Client, discov
 					return
 				}
 
-				lastUsed, err := w.findLastUsedAddress(chainClient, branchkey, acct, branch)
-				if err != nil {
-					errs <- err
-					return
+				lastUsed, ok := ckpt.discoveredBranch(acct, branch)
+				if !ok {
+					lastUsed, err = w.findLastUsedAddress(chainClient, branchkey, acct, branch)
+					if err != nil {
+						errs <- err
+						return
+					}
 				}
 
 				// Save discovered addresses for the account plus additional
@@ -640,6 +656,13 @@ func (w *Wallet) DiscoverActiveAddresses(chainClient *hcrpcclient.Client, discov
 					errs <- err
 					return
 				}
+				if !ok {
+					err = ckpt.recordBranch(acct, branch, lastUsed)
+					if err != nil {
+						errs <- err
+						return
+					}
+				}
 			}
 		}()
 	}
@@ -662,3 +685,169 @@ func (w *Wallet) DiscoverActiveAddresses(chainClient *hcrpcclient.Client, discov
 		return nil
 	}
 }
+
+// syncCheckpointer records the progress of the initial sync performed by
+// syncWithChain in the database, allowing a sync that is interrupted (for
+// example, by shutting down the wallet) to resume address discovery from the
+// last checkpoint instead of starting over.  Progress is recorded at the main
+// chain tip when the sync began, and is no longer recorded once that block is
+// removed from the main chain.  A nil *syncCheckpointer records nothing and
+// reports no previous progress.
+type syncCheckpointer struct {
+	w  *Wallet
+	mu sync.Mutex
+	cp udb.SyncCheckpoint
+}
+
+// loadSyncCheckpoint returns a checkpointer for a sync beginning now.  If a
+// previous sync was interrupted and the block it was last checkpointed at is
+// still in the main chain, its progress is carried over.  The recorded
+// checkpoint is consumed, and is only recorded again as the new sync makes
+// progress.
+func (w *Wallet) loadSyncCheckpoint() (*syncCheckpointer, error) {
+	c := &syncCheckpointer{w: w}
+	var cp *udb.SyncCheckpoint
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		c.cp.Hash, c.cp.Height = w.TxStore.MainChainTip(txmgrNs)
+
+		var err error
+		cp, err = w.TxStore.SyncCheckpoint(dbtx)
+		if err != nil || cp == nil {
+			return err
+		}
+		// The checkpoint is discarded when the main chain has no block or
+		// a different block at its height.  Other errors are returned.
+		inMainChain, err := w.syncCheckpointInMainChain(txmgrNs, cp)
+		if err != nil {
+			return err
+		}
+		if !inMainChain {
+			log.Infof("Discarding sync checkpoint at block %v (height %d) "+
+				"no longer in the main chain", &cp.Hash, cp.Height)
+			return nil
+		}
+		log.Infof("Resuming interrupted sync from checkpoint at block %v "+
+			"(height %d)", &cp.Hash, cp.Height)
+		c.cp.AccountsDiscovered = cp.AccountsDiscovered
+		c.cp.Branches = cp.Branches
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cp != nil {
+		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			return w.TxStore.DeleteSyncCheckpoint(dbtx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// syncCheckpointInMainChain returns whether the block of a sync checkpoint is
+// in the main chain.
+func (w *Wallet) syncCheckpointInMainChain(txmgrNs walletdb.ReadBucket, cp *udb.SyncCheckpoint) (bool, error) {
+	mainHash, err := w.TxStore.GetMainChainBlockHashForHeight(txmgrNs, cp.Height)
+	if apperrors.IsError(err, apperrors.ErrValueNoExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return mainHash == cp.Hash, nil
+}
+
+// accountsDiscovered returns whether account discovery has already completed.
+func (c *syncCheckpointer) accountsDiscovered() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cp.AccountsDiscovered
+}
+
+// discoveredBranch returns the last used child index recorded for an account
+// branch and whether address discovery has already completed for it.
+func (c *syncCheckpointer) discoveredBranch(account, branch uint32) (uint32, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, b := range c.cp.Branches {
+		if b.Account == account && b.Branch == branch {
+			return b.LastUsed, true
+		}
+	}
+	return 0, false
+}
+
+// recordAccounts records that account discovery has completed.
+func (c *syncCheckpointer) recordAccounts() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cp.AccountsDiscovered = true
+	return c.put()
+}
+
+// recordBranch records the discovered last used child index of an account
+// branch.
+func (c *syncCheckpointer) recordBranch(account, branch, lastUsed uint32) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cp.Branches = append(c.cp.Branches, udb.DiscoveredBranch{
+		Account:  account,
+		Branch:   branch,
+		LastUsed: lastUsed,
+	})
+	return c.put()
+}
+
+// checkpoint records the progress carried over from an interrupted sync
+// without adding any progress.
+func (c *syncCheckpointer) checkpoint() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.put()
+}
+
+// put writes the checkpoint at the block the sync began at, unless the block
+// was since removed from the main chain.  c.mu must be held.
+func (c *syncCheckpointer) put() error {
+	return walletdb.Update(c.w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		inMainChain, err := c.w.syncCheckpointInMainChain(txmgrNs, &c.cp)
+		if err != nil {
+			return err
+		}
+		if !inMainChain {
+			log.Debugf("Not recording sync checkpoint at block %v (height "+
+				"%d) no longer in the main chain", &c.cp.Hash, c.cp.Height)
+			return nil
+		}
+		return c.w.TxStore.PutSyncCheckpoint(dbtx, &c.cp)
+	})
+}
+
+// finish removes the checkpoint after the sync has completed.
+func (c *syncCheckpointer) finish() error {
+	if c == nil {
+		return nil
+	}
+	return walletdb.Update(c.w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return c.w.TxStore.DeleteSyncCheckpoint(dbtx)
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestLoadSyncCheckpoint(t *testing.T) {
+	w, headers, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	tests := []struct {
+		name   string
+		cp     udb.SyncCheckpoint
+		resume bool
+	}{
+		{"main chain block", udb.SyncCheckpoint{Height: 3, Hash: headers[3].BlockHash, AccountsDiscovered: true}, true},
+		{"side chain block", udb.SyncCheckpoint{Height: 3, Hash: chainhash.Hash{3}, AccountsDiscovered: true}, false},
+		{"above the tip", udb.SyncCheckpoint{Height: 10, Hash: chainhash.Hash{10}, AccountsDiscovered: true}, false},
+	}
+	for _, test := range tests {
+		err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			return w.TxStore.PutSyncCheckpoint(dbtx, &test.cp)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := w.loadSyncCheckpoint()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if c.accountsDiscovered() != test.resume {
+			t.Errorf("%s: resumed %v, want %v", test.name,
+				c.accountsDiscovered(), test.resume)
+		}
+		if cp := syncCheckpoint(t, w); cp != nil {
+			t.Errorf("%s: checkpoint %+v was not consumed", test.name, cp)
+		}
+	}
+}
+
+// syncCheckpoint returns the recorded sync checkpoint.
+func syncCheckpoint(t *testing.T, w *Wallet) *udb.SyncCheckpoint {
+	var cp *udb.SyncCheckpoint
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		var err error
+		cp, err = w.TxStore.SyncCheckpoint(dbtx)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cp
+}
+
+func TestSyncCheckpointBlock(t *testing.T) {
+	w, headers, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	c, err := w.loadSyncCheckpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Progress is recorded at the tip the sync began at, even after the
+	// main chain is extended.
+	next := testChain(t, headers[5].BlockHash, 5, 0, 1)
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.ExtendMainChain(txmgrNs, &next[0])
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.recordAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp := syncCheckpoint(t, w)
+	if cp == nil || cp.Height != 5 || cp.Hash != headers[5].BlockHash {
+		t.Fatalf("checkpoint %+v, want block %v at height 5", cp,
+			&headers[5].BlockHash)
+	}
+
+	// Rolling back the block removes the checkpoint, and further progress
+	// is not recorded.
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.RollBack(dbtx, 5, nil, "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp := syncCheckpoint(t, w); cp != nil {
+		t.Errorf("checkpoint %+v remains after its block was rolled back", cp)
+	}
+	err = c.recordBranch(0, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp := syncCheckpoint(t, w); cp != nil {
+		t.Errorf("recorded checkpoint %+v at a rolled back block", cp)
+	}
+}
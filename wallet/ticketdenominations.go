@@ -0,0 +1,318 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// TicketDenominationPolicy describes the automatic splitting and merging of the
+// outputs of a ticket purchasing account into outputs which each fund a single
+// ticket at the current ticket price.
+type TicketDenominationPolicy struct {
+	// Enabled enables denominating the outputs of Account as blocks are
+	// connected, such as the change of ticket purchases and the matured
+	// rewards of votes.  Outputs are only denominated while the wallet is
+	// unlocked.
+	Enabled bool
+	Account uint32
+}
+
+// SetTicketDenominations sets the policy of ticket output denomination.
+func (w *Wallet) SetTicketDenominations(p TicketDenominationPolicy) {
+	w.ticketDenominationsMu.Lock()
+	w.ticketDenominations = p
+	w.ticketDenominationsHeight = 0
+	w.ticketDenominationsMu.Unlock()
+}
+
+// TicketDenominations returns the policy of ticket output denomination.
+func (w *Wallet) TicketDenominations() TicketDenominationPolicy {
+	w.ticketDenominationsMu.Lock()
+	p := w.ticketDenominations
+	w.ticketDenominationsMu.Unlock()
+	return p
+}
+
+// TicketDenomination returns the value of an output of an account which funds
+// the purchase of a single ticket at the next ticket price, including the fees
+// of the ticket and of the split transaction spending the output.
+func (w *Wallet) TicketDenomination(account uint32) (hcutil.Amount, error) {
+	ticketPrice, err := w.StakeDifficulty()
+	if err != nil {
+		return 0, err
+	}
+	ticketFeeIncrement, err := w.AccountTicketFeeIncrement(account)
+	if err != nil {
+		return 0, err
+	}
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return 0, err
+	}
+	usePool := w.PoolAddress() != nil
+	_, neededPerTicket, err := w.getTicketFeeAndNeededTicketPrice(account,
+		usePool, ticketPrice, ticketFeeIncrement)
+	if err != nil {
+		return 0, err
+	}
+
+	// The split transaction pays a single output, or a pool fee and a user
+	// output when purchasing through a stake pool, and change.
+	splitOutputs := 2
+	if usePool {
+		splitOutputs = 3
+	}
+	splitFee := feeForSize(relayFee, estimateTxSize(1, splitOutputs, account))
+	return neededPerTicket + splitFee, nil
+}
+
+// DenominateTicketFunds splits and merges the spendable outputs of an account
+// which are not already denominated for a single ticket purchase into as many
+// outputs of the ticket denomination as possible, with the remainder returned
+// as change.  Outputs funding a single ticket (valued at least the
+// denomination but less than twice it) are left untouched.  The hash of the
+// published transaction is returned, or nil if the outputs of the account can
+// not be denominated any further.  The wallet must be unlocked.
+func (w *Wallet) DenominateTicketFunds(account uint32) (*chainhash.Hash, error) {
+	denomination, err := w.TicketDenomination(account)
+	if err != nil {
+		return nil, err
+	}
+	req := denominateRequest{
+		account:      account,
+		denomination: denomination,
+		resp:         make(chan denominateResponse),
+	}
+	select {
+	case w.denominateRequests <- req:
+	case <-w.quitChan():
+		return nil, errors.New("wallet is shutting down")
+	}
+	resp := <-req.resp
+	return resp.txHash, resp.err
+}
+
+// denominateTicketFunds creates and publishes a transaction denominating the
+// outputs of an account, returning nil if there is nothing to denominate.
+func (w *Wallet) denominateTicketFunds(account uint32, denomination hcutil.Amount) (*chainhash.Hash, error) {
+	var hash *chainhash.Hash
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		var err error
+		hash, err = w.denominateTicketFundsInternal(dbtx, account, denomination)
+		return err
+	})
+	return hash, err
+}
+
+func (w *Wallet) denominateTicketFundsInternal(dbtx walletdb.ReadWriteTx, account uint32,
+	denomination hcutil.Amount) (*chainhash.Hash, error) {
+
+	addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+
+	w.reorganizingLock.Lock()
+	reorg := w.reorganizing
+	w.reorganizingLock.Unlock()
+	if reorg {
+		return nil, ErrBlockchainReorganizing
+	}
+
+	_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+	eligible, err := w.findEligibleOutputs(dbtx, account, 1, tipHeight)
+	if err != nil {
+		return nil, err
+	}
+	inputs := eligible[:0]
+	for _, c := range eligible {
+		if c.Amount >= denomination && c.Amount < 2*denomination {
+			continue
+		}
+		inputs = append(inputs, c)
+	}
+
+	feeIncrement := w.accountFee(dbtx, account, udb.AccountRelayFee, w.RelayFee())
+
+	// denominate returns the number of denominated outputs paid by the
+	// first n inputs, and the fee of a transaction paying them and change.
+	denominate := func(n int) (int, hcutil.Amount) {
+		var total hcutil.Amount
+		for _, c := range inputs[:n] {
+			total += c.Amount
+		}
+		outputs := int(total / denomination)
+		for ; outputs > 0; outputs-- {
+			fee := feeForSize(feeIncrement, estimateTxSize(n, outputs+1, account))
+			if hcutil.Amount(outputs)*denomination+fee <= total {
+				return outputs, fee
+			}
+		}
+		return 0, 0
+	}
+
+	// Spend only as many inputs as fit within the wallet's transaction
+	// limits.
+	n := len(inputs)
+	outputs, fee := denominate(n)
+	for n > 0 && w.txLimits.Check(n, estimateTxSize(n, outputs+1, account)) != nil {
+		n--
+		outputs, fee = denominate(n)
+	}
+
+	// Denominating a single output only changes the wallet's outputs when
+	// it is split into multiple outputs.
+	if outputs == 0 || n == 1 && outputs == 1 {
+		return nil, nil
+	}
+	inputs = inputs[:n]
+
+	msgtx := wire.NewMsgTx()
+	var total hcutil.Amount
+	for _, c := range inputs {
+		msgtx.AddTxIn(wire.NewTxIn(&c.OutPoint, nil))
+		total += c.Amount
+	}
+	txOuts, err := w.denominatedOutputs(dbtx, account, outputs, denomination)
+	if err != nil {
+		return nil, err
+	}
+	for _, txOut := range txOuts {
+		msgtx.AddTxOut(txOut)
+	}
+	change := total - hcutil.Amount(outputs)*denomination - fee
+	if !txrules.IsDustAmount(change, len(txOuts[0].PkScript), feeIncrement) {
+		changeAddr, err := w.newChangeAddress(w.persistReturnedChild(dbtx), account, nil)
+		if err != nil {
+			return nil, err
+		}
+		changeScript, err := txscript.PayToAddrScript(changeAddr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create txout script: %s", err)
+		}
+		msgtx.AddTxOut(wire.NewTxOut(int64(change), changeScript))
+	}
+
+	err = signMsgTx(msgtx, inputs, w.Manager, addrmgrNs, w.chainParams)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMsgTxCredits(msgtx, inputs); err != nil {
+		return nil, err
+	}
+
+	txHash, err := w.sendRawTransaction(txmgrNs, chainClient, msgtx, w.allowHighFees)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := w.insertIntoTxMgr(txmgrNs, msgtx)
+	if err != nil {
+		return nil, err
+	}
+	err = w.insertCreditsIntoTxMgr(dbtx, msgtx, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Denominated %d outputs into %d ticket outputs of %v in "+
+		"transaction %v", len(inputs), outputs, denomination, txHash)
+
+	return txHash, nil
+}
+
+// denominatedOutputs returns n outputs of an account, each valued at the
+// denomination and paying a distinct change address, so that the tickets they
+// later fund can not be linked by a shared address.
+func (w *Wallet) denominatedOutputs(dbtx walletdb.ReadWriteTx, account uint32,
+	n int, denomination hcutil.Amount) ([]*wire.TxOut, error) {
+
+	persist := w.persistReturnedChild(dbtx)
+	txOuts := make([]*wire.TxOut, 0, n)
+	for i := 0; i < n; i++ {
+		addr, err := w.newChangeAddress(persist, account, nil)
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create txout script: %s", err)
+		}
+		txOuts = append(txOuts, wire.NewTxOut(int64(denomination), pkScript))
+	}
+	return txOuts, nil
+}
+
+// ticketDenominationInterval is the minimum number of blocks between
+// automatic denominations of the ticket purchasing account.  Denominated
+// outputs and change require a confirmation before they are spent again, so
+// attempting on every block only repeats the scan of the account's outputs.
+const ticketDenominationInterval = 6
+
+// beginAutoDenomination returns whether automatic denomination should be
+// attempted for a newly connected block at height, and if so records the
+// attempt.  Attempts are not made while a previous attempt is still running or
+// within ticketDenominationInterval blocks of the last attempt, unless the
+// chain was reorganized below the height of the last attempt.  Each true
+// return must be followed by a call to endAutoDenomination.
+func (w *Wallet) beginAutoDenomination(height int32) (TicketDenominationPolicy, bool) {
+	w.ticketDenominationsMu.Lock()
+	defer w.ticketDenominationsMu.Unlock()
+
+	p := w.ticketDenominations
+	if !p.Enabled || w.ticketDenominationsBusy {
+		return p, false
+	}
+	last := w.ticketDenominationsHeight
+	if last != 0 && height >= last && height-last < ticketDenominationInterval {
+		return p, false
+	}
+	w.ticketDenominationsBusy = true
+	w.ticketDenominationsHeight = height
+	return p, true
+}
+
+// endAutoDenomination marks the running automatic denomination as finished.
+func (w *Wallet) endAutoDenomination() {
+	w.ticketDenominationsMu.Lock()
+	w.ticketDenominationsBusy = false
+	w.ticketDenominationsMu.Unlock()
+}
+
+// autoDenominateTicketFunds denominates the outputs of the ticket purchasing
+// account when automatic denomination is enabled and the wallet is unlocked.
+// The denomination runs in its own goroutine so that creating and publishing
+// the transaction does not delay the processing of further blocks.
+func (w *Wallet) autoDenominateTicketFunds(height int32) {
+	p, ok := w.beginAutoDenomination(height)
+	if !ok {
+		return
+	}
+	if w.Manager.IsLocked() {
+		log.Debugf("Skipping ticket output denomination of a locked wallet")
+		w.endAutoDenomination()
+		return
+	}
+	go func() {
+		defer w.endAutoDenomination()
+		_, err := w.DenominateTicketFunds(p.Account)
+		if err != nil && !w.ShuttingDown() {
+			log.Errorf("Failed to denominate outputs of account %d: %v",
+				p.Account, err)
+		}
+	}()
+}
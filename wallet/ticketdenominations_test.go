@@ -0,0 +1,108 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestDenominatedOutputsUseDistinctAddresses(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	const n = 5
+	var txOuts []*wire.TxOut
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		var err error
+		txOuts, err = w.denominatedOutputs(dbtx, udb.DefaultAccountNum, n, 2e8)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txOuts) != n {
+		t.Fatalf("created %d outputs, want %d", len(txOuts), n)
+	}
+
+	// Each output pays the next change address of the account.
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.InternalBranch, 0, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, txOut := range txOuts {
+		if txOut.Value != 2e8 {
+			t.Errorf("output %d pays %d, want %d", i, txOut.Value, int64(2e8))
+		}
+		script, err := txscript.PayToAddrScript(addrs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(txOut.PkScript, script) {
+			t.Errorf("output %d does not pay change address %d", i, i)
+		}
+	}
+}
+
+func TestBeginAutoDenomination(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	if _, ok := w.beginAutoDenomination(100); ok {
+		t.Fatal("attempted denomination with the policy disabled")
+	}
+
+	w.SetTicketDenominations(TicketDenominationPolicy{Enabled: true, Account: 1})
+	p, ok := w.beginAutoDenomination(100)
+	if !ok {
+		t.Fatal("first attempt was not made")
+	}
+	if p.Account != 1 {
+		t.Errorf("attempt uses account %d, want 1", p.Account)
+	}
+	if _, ok := w.beginAutoDenomination(100 + ticketDenominationInterval); ok {
+		t.Error("attempted while a previous attempt is running")
+	}
+	w.endAutoDenomination()
+
+	tests := []struct {
+		height int32
+		want   bool
+	}{
+		{101, false},
+		{100 + ticketDenominationInterval - 1, false},
+		{100 + ticketDenominationInterval, true},
+		{100 + ticketDenominationInterval + 1, false},
+		// A reorganization below the last attempt allows a new attempt.
+		{90, true},
+		{91, false},
+	}
+	for _, test := range tests {
+		_, ok := w.beginAutoDenomination(test.height)
+		if ok {
+			w.endAutoDenomination()
+		}
+		if ok != test.want {
+			t.Errorf("height %d: attempted=%v, want %v", test.height, ok, test.want)
+		}
+	}
+
+	// Changing the policy applies it on the next block.
+	w.SetTicketDenominations(TicketDenominationPolicy{Enabled: true, Account: 2})
+	p, ok = w.beginAutoDenomination(92)
+	if !ok {
+		t.Fatal("attempt after a policy change was not made")
+	}
+	w.endAutoDenomination()
+	if p.Account != 2 {
+		t.Errorf("attempt uses account %d, want 2", p.Account)
+	}
+}
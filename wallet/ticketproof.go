@@ -0,0 +1,197 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcec/secp256k1"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// ticketProofHash returns the hash of a challenge which is signed to prove
+// ownership of a ticket.  The ticket hash is committed to so that a proof of
+// one ticket can not be replayed for another ticket paying the same address,
+// and the prefix differs from signed messages and account proofs.
+func ticketProofHash(ticketHash *chainhash.Hash, challenge string) []byte {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, "Hc Ticket Proof:\n")
+	buf.Write(ticketHash[:])
+	wire.WriteVarString(&buf, 0, challenge)
+	return chainhash.HashB(buf.Bytes())
+}
+
+// ticketProofAddresses returns the addresses which may prove ownership of a
+// ticket: the commitment addresses the ticket pays out to, followed by the
+// voting address.  Only P2PKH addresses are returned as proofs are signed by a
+// single key.
+func ticketProofAddresses(ticket *wire.MsgTx, w *Wallet) ([]hcutil.Address, error) {
+	if stake.DetermineTxType(ticket) != stake.TxTypeSStx {
+		const str = "transaction is not a ticket purchase"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	var addrs []hcutil.Address
+	for i := 1; i < len(ticket.TxOut); i += 2 {
+		addr, err := stake.AddrFromSStxPkScrCommitment(ticket.TxOut[i].PkScript,
+			w.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := addr.(*hcutil.AddressPubKeyHash); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	_, voting, _, err := txscript.ExtractPkScriptAddrs(ticket.TxOut[0].Version,
+		ticket.TxOut[0].PkScript, w.chainParams)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range voting {
+		if _, ok := addr.(*hcutil.AddressPubKeyHash); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// ticketTx returns a ticket recorded by the wallet, or queried from the
+// consensus RPC server when it is not recorded.
+func (w *Wallet) ticketTx(ticketHash *chainhash.Hash) (*wire.MsgTx, error) {
+	var ticket *wire.MsgTx
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if !w.TxStore.ExistsTx(txmgrNs, ticketHash) {
+			return nil
+		}
+		var err error
+		ticket, err = w.TxStore.Tx(txmgrNs, ticketHash)
+		return err
+	})
+	if err != nil || ticket != nil {
+		return ticket, err
+	}
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := chainClient.GetRawTransaction(ticketHash)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MsgTx(), nil
+}
+
+// GenerateTicketProof signs a challenge with the key of a commitment address
+// or the voting address of a ticket, proving ownership of the ticket without
+// revealing keys to the verifier.  Commitment addresses are preferred as they
+// remain controlled by the ticket owner when voting is delegated to a stake
+// pool.  The signing address is returned with the compact signature, which is
+// checked by VerifyTicketProof.  This method requires the wallet to be
+// unlocked.
+func (w *Wallet) GenerateTicketProof(ticketHash *chainhash.Hash, challenge string) (hcutil.Address, []byte, error) {
+	ticket, err := w.ticketTx(ticketHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	addrs, err := ticketProofAddresses(ticket, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var addr hcutil.Address
+	var privKey chainec.PrivateKey
+	var done func()
+	defer func() {
+		if done != nil {
+			done()
+		}
+	}()
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		for _, a := range addrs {
+			if !w.Manager.ExistsAddress(addrmgrNs, a) {
+				continue
+			}
+			var err error
+			privKey, done, err = w.Manager.PrivateKey(addrmgrNs, a)
+			if err != nil {
+				return err
+			}
+			addr = a
+			return nil
+		}
+		const str = "wallet does not control a commitment or voting address of the ticket"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pk, ok := privKey.(*secp256k1.PrivateKey)
+	if !ok {
+		const str = "ticket proofs may only be signed by secp256k1 keys"
+		return nil, nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	sig, err := secp256k1.SignCompact(secp256k1.S256(), pk,
+		ticketProofHash(ticketHash, challenge), true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return addr, sig, nil
+}
+
+// VerifyTicketProof verifies that sig is a valid signature of challenge created
+// by GenerateTicketProof with the key of addr, and that addr is a commitment
+// or voting address of the ticket.  Tickets not recorded by the wallet are
+// queried from the consensus RPC server.
+func (w *Wallet) VerifyTicketProof(ticketHash *chainhash.Hash, challenge string,
+	addr hcutil.Address, sig []byte) (bool, error) {
+
+	ticket, err := w.ticketTx(ticketHash)
+	if err != nil {
+		return false, err
+	}
+	addrs, err := ticketProofAddresses(ticket, w)
+	if err != nil {
+		return false, err
+	}
+	owner := false
+	for _, a := range addrs {
+		if a.EncodeAddress() == addr.EncodeAddress() {
+			owner = true
+			break
+		}
+	}
+	if !owner {
+		return false, nil
+	}
+
+	// As with signed messages, signatures from which no public key can be
+	// recovered are invalid rather than an error.
+	pk, wasCompressed, err := chainec.Secp256k1.RecoverCompact(sig,
+		ticketProofHash(ticketHash, challenge))
+	if err != nil {
+		return false, nil
+	}
+	var serializedPK []byte
+	if wasCompressed {
+		serializedPK = pk.SerializeCompressed()
+	} else {
+		serializedPK = pk.SerializeUncompressed()
+	}
+	recoveredAddr, err := hcutil.NewAddressSecpPubKey(serializedPK, w.chainParams)
+	if err != nil {
+		return false, err
+	}
+	return recoveredAddr.EncodeAddress() == addr.EncodeAddress(), nil
+}
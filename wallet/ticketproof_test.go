@@ -0,0 +1,86 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestTicketProof(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	walletPkScript(t, w, udb.ExternalBranch, 2)
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), w.chainParams, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Voting is delegated to a foreign address, so the proof is signed by
+	// the commitment address.
+	ticket := addTestTicket(t, w, 1, foreign, addrs[1])
+	ticketHash := ticket.TxHash()
+	const challenge = "prove it"
+	addr, sig, err := w.GenerateTicketProof(&ticketHash, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.EncodeAddress() != addrs[1].EncodeAddress() {
+		t.Errorf("proof signed by %v, want the commitment address %v",
+			addr, addrs[1])
+	}
+
+	tests := []struct {
+		name      string
+		challenge string
+		addr      hcutil.Address
+		sig       []byte
+		valid     bool
+	}{
+		{"valid", challenge, addr, sig, true},
+		{"other challenge", "other", addr, sig, false},
+		{"voting address", challenge, foreign, sig, false},
+		{"unrelated address", challenge, addrs[2], sig, false},
+		{"truncated signature", challenge, addr, sig[:10], false},
+	}
+	for _, test := range tests {
+		valid, err := w.VerifyTicketProof(&ticketHash, test.challenge,
+			test.addr, test.sig)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if valid != test.valid {
+			t.Errorf("%s: verified %v, want %v", test.name, valid, test.valid)
+		}
+	}
+
+	// Only tickets can be proven.
+	fundingHash := ticket.TxIn[0].PreviousOutPoint.Hash
+	_, _, err = w.GenerateTicketProof(&fundingHash, challenge)
+	if !apperrors.IsError(err, apperrors.ErrInput) {
+		t.Errorf("proving a regular transaction returned %v, want ErrInput",
+			err)
+	}
+
+	// Signing requires the unlocked wallet.
+	w.Lock()
+	if !w.Locked() {
+		t.Fatal("wallet did not lock")
+	}
+	_, _, err = w.GenerateTicketProof(&ticketHash, challenge)
+	if !apperrors.IsError(err, apperrors.ErrLocked) {
+		t.Errorf("proving with a locked wallet returned %v, want ErrLocked",
+			err)
+	}
+}
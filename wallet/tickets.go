@@ -15,7 +15,6 @@ import (
 	"github.com/HcashOrg/hcd/hcutil"
 	"github.com/HcashOrg/hcd/txscript"
 	"github.com/HcashOrg/hcd/wire"
-	hcrpcclient "github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/apperrors"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
@@ -50,7 +49,7 @@ func (w *Wallet) GenerateVoteTx(blockHash *chainhash.Hash, height int32, ticketH
 
 // LiveTicketHashes returns the hashes of live tickets that the wallet has
 // purchased or has voting authority for.
-func (w *Wallet) LiveTicketHashes(chainClient *hcrpcclient.Client, includeImmature bool) ([]chainhash.Hash, error) {
+func (w *Wallet) LiveTicketHashes(chainClient ChainClient, includeImmature bool) ([]chainhash.Hash, error) {
 	var ticketHashes []chainhash.Hash
 	var maybeLive []*chainhash.Hash
 
@@ -217,6 +216,119 @@ func (w *Wallet) TicketHashesForVotingAddress(votingAddr hcutil.Address) ([]chai
 	return ticketHashes, err
 }
 
+// DelegatedTicket describes a ticket recorded by the wallet with voting rights
+// delegated to an address the wallet does not control.
+type DelegatedTicket struct {
+	Hash          chainhash.Hash
+	VotingAddress hcutil.Address
+	Price         hcutil.Amount
+	Status        TicketStatus
+	SpenderHash   *chainhash.Hash // Vote or revocation, nil if unspent
+}
+
+// DelegatedTickets returns all tickets recorded by the wallet whose voting
+// rights are delegated to an address not controlled by the wallet, such as
+// tickets purchased with a ticket address of a separate voting wallet while
+// the commitment remains with this wallet.
+//
+// This wallet does not own output 0 of these tickets, so spent tickets are
+// identified by the stake reward index.  The chain client is queried to
+// determine whether mature unspent tickets are live or missed.
+func (w *Wallet) DelegatedTickets(chainClient ChainClient) ([]DelegatedTicket, error) {
+	var tickets []DelegatedTicket
+	var maybeLive []*chainhash.Hash
+	var maybeLiveIdx []int
+
+	expiryConfs := int32(w.chainParams.TicketExpiry) +
+		int32(w.chainParams.TicketMaturity) + 1
+
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		stakemgrNs := dbtx.ReadBucket(wstakemgrNamespaceKey)
+
+		spenders := make(map[chainhash.Hash]udb.StakeReward)
+		err := w.StakeMgr.ForEachStakeReward(stakemgrNs, func(r *udb.StakeReward) error {
+			spenders[r.Ticket] = *r
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+
+		it := w.TxStore.IterateTickets(dbtx)
+		for it.Next() {
+			owned, err := w.hasVotingAuthority(addrmgrNs, &it.MsgTx)
+			if err != nil {
+				return err
+			}
+			if owned {
+				continue
+			}
+
+			out := it.MsgTx.TxOut[0]
+			t := DelegatedTicket{
+				Hash:  it.Hash,
+				Price: hcutil.Amount(out.Value),
+			}
+			_, addrs, _, _ := txscript.ExtractPkScriptAddrs(out.Version,
+				out.PkScript, w.chainParams)
+			if len(addrs) != 0 {
+				t.VotingAddress = addrs[0]
+			}
+
+			switch r, ok := spenders[it.Hash]; {
+			case ok:
+				t.Status = TicketStatusVoted
+				if r.Type == udb.StakeRewardRevocation {
+					t.Status = TicketStatusRevoked
+				}
+				spender := r.TxHash
+				t.SpenderHash = &spender
+			case it.Block.Height == -1:
+				t.Status = TicketStatusUnmined
+			case !confirmed(int32(w.chainParams.TicketMaturity)+1,
+				it.Block.Height, tipHeight):
+				t.Status = TicketStatusImmature
+			case confirmed(expiryConfs, it.Block.Height, tipHeight):
+				t.Status = TicketStatusExpired
+			default:
+				// The ticket is either live or missed, which must be
+				// queried over RPC below.
+				t.Status = TicketStatusLive
+				hash := it.Hash
+				maybeLive = append(maybeLive, &hash)
+				maybeLiveIdx = append(maybeLiveIdx, len(tickets))
+			}
+			tickets = append(tickets, t)
+		}
+		return it.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(maybeLive) == 0 {
+		return tickets, nil
+	}
+	liveBitsetHex, err := chainClient.ExistsLiveTickets(maybeLive)
+	if err != nil {
+		return nil, err
+	}
+	liveBitset, err := hex.DecodeString(liveBitsetHex)
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range maybeLiveIdx {
+		if !bitset.Bytes(liveBitset).Get(i) {
+			tickets[idx].Status = TicketStatusMissed
+		}
+	}
+	return tickets, nil
+}
+
 // updateStakePoolInvalidTicket properly updates a previously marked Invalid pool ticket,
 // it then creates a new entry in the validly tracked pool ticket db.
 func (w *Wallet) updateStakePoolInvalidTicket(stakemgrNs walletdb.ReadWriteBucket,
@@ -287,10 +399,69 @@ func (w *Wallet) AddTicket(ticket *wire.MsgTx) error {
 	})
 }
 
+// SetAutoRevoke sets whether missed and expired tickets are automatically
+// revoked as blocks are connected.
+func (w *Wallet) SetAutoRevoke(enabled bool) {
+	w.disableAutoRevokeMu.Lock()
+	w.disableAutoRevoke = !enabled
+	w.disableAutoRevokeMu.Unlock()
+}
+
+// AutoRevoke returns whether missed and expired tickets are automatically
+// revoked.
+func (w *Wallet) AutoRevoke() bool {
+	w.disableAutoRevokeMu.Lock()
+	enabled := !w.disableAutoRevoke
+	w.disableAutoRevokeMu.Unlock()
+	return enabled
+}
+
+// RevokeTicketsOptions describes which tickets are revoked by
+// RevokeTicketsWithOptions and how their revocations are created.
+type RevokeTicketsOptions struct {
+	// FeeRate is the fee per kB paid by each revocation.  The relay fee of
+	// the wallet is used when zero.
+	FeeRate hcutil.Amount
+
+	// Tickets limits revocations to these tickets, each of which must be a
+	// missed or expired ticket of the wallet.  All missed and expired
+	// tickets are revoked when nil.
+	Tickets []chainhash.Hash
+
+	// DryRun creates the revocations without signing, recording, or
+	// publishing them.  As transaction hashes do not commit to signature
+	// scripts, the returned hashes are those of the revocations which would
+	// be published.
+	DryRun bool
+
+	// AllowHighFees is passed to the consensus server when each revocation
+	// is sent.
+	AllowHighFees bool
+}
+
+// RevokedTicket pairs a revoked ticket with the hash of its revocation.
+type RevokedTicket struct {
+	Ticket     chainhash.Hash
+	Revocation chainhash.Hash
+}
+
 // RevokeTickets creates and sends revocation transactions for any unrevoked
 // missed and expired tickets.  The wallet must be unlocked to generate any
-// revocations.
-func (w *Wallet) RevokeTickets(chainClient *hcrpcclient.Client) error {
+// revocations.  allowHighFees is passed to the consensus server when each
+// revocation is sent.
+func (w *Wallet) RevokeTickets(chainClient ChainClient, allowHighFees bool) error {
+	_, err := w.RevokeTicketsWithOptions(chainClient, &RevokeTicketsOptions{
+		AllowHighFees: allowHighFees,
+	})
+	return err
+}
+
+// RevokeTicketsWithOptions creates and sends revocation transactions for
+// unrevoked missed and expired tickets as described by opts, returning each
+// revoked ticket with the hash of its revocation.  Tickets the wallet does not
+// have voting authority for are skipped.  Unless opts.DryRun is set, the wallet
+// must be unlocked to generate any revocations.
+func (w *Wallet) RevokeTicketsWithOptions(chainClient ChainClient, opts *RevokeTicketsOptions) ([]RevokedTicket, error) {
 	var ticketHashes []chainhash.Hash
 	var tipHash chainhash.Hash
 	var tipHeight int32
@@ -302,30 +473,28 @@ func (w *Wallet) RevokeTickets(chainClient *hcrpcclient.Client) error {
 		return err
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ticketHashPtrs := make([]*chainhash.Hash, len(ticketHashes))
 	for i := range ticketHashes {
 		ticketHashPtrs[i] = &ticketHashes[i]
 	}
-	expiredFuture := chainClient.ExistsExpiredTicketsAsync(ticketHashPtrs)
-	missedFuture := chainClient.ExistsMissedTicketsAsync(ticketHashPtrs)
-	expiredBitsHex, err := expiredFuture.Receive()
+	expiredBitsHex, err := chainClient.ExistsExpiredTickets(ticketHashPtrs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	missedBitsHex, err := missedFuture.Receive()
+	missedBitsHex, err := chainClient.ExistsMissedTickets(ticketHashPtrs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	expiredBits, err := hex.DecodeString(expiredBitsHex)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	missedBits, err := hex.DecodeString(missedBitsHex)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	revokableTickets := make([]*chainhash.Hash, 0, len(ticketHashes))
 	for i, p := range ticketHashPtrs {
@@ -333,8 +502,28 @@ func (w *Wallet) RevokeTickets(chainClient *hcrpcclient.Client) error {
 			revokableTickets = append(revokableTickets, p)
 		}
 	}
-	feePerKb := w.RelayFee()
+	if opts.Tickets != nil {
+		revokable := make(map[chainhash.Hash]struct{}, len(revokableTickets))
+		for _, h := range revokableTickets {
+			revokable[*h] = struct{}{}
+		}
+		revokableTickets = revokableTickets[:0]
+		for i := range opts.Tickets {
+			h := &opts.Tickets[i]
+			if _, ok := revokable[*h]; !ok {
+				str := "ticket " + h.String() + " is not an unrevoked missed or expired ticket"
+				return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+			}
+			revokableTickets = append(revokableTickets, h)
+		}
+	}
+
+	feePerKb := opts.FeeRate
+	if feePerKb == 0 {
+		feePerKb = w.RelayFee()
+	}
 	revocations := make([]*wire.MsgTx, 0, len(revokableTickets))
+	revoked := make([]RevokedTicket, 0, len(revokableTickets))
 	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
 		for _, ticketHash := range revokableTickets {
 			addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
@@ -359,44 +548,55 @@ func (w *Wallet) RevokeTickets(chainClient *hcrpcclient.Client) error {
 			if err != nil {
 				return err
 			}
-			err = w.signRevocation(addrmgrNs, ticketPurchase, revocation)
-			if err != nil {
-				return err
+			if !opts.DryRun {
+				err = w.signRevocation(addrmgrNs, ticketPurchase, revocation)
+				if err != nil {
+					return err
+				}
 			}
 			revocations = append(revocations, revocation)
+			revoked = append(revoked, RevokedTicket{
+				Ticket:     *ticketHash,
+				Revocation: revocation.TxHash(),
+			})
 		}
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if opts.DryRun {
+		return revoked, nil
 	}
 
 	for i, revocation := range revocations {
 		rec, err := udb.NewTxRecordFromMsgTx(revocation, time.Now())
 		if err != nil {
-			return err
+			return revoked[:i], err
 		}
 		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-			err = w.StakeMgr.StoreRevocationInfo(dbtx, revokableTickets[i],
+			err = w.StakeMgr.StoreRevocationInfo(dbtx, &revoked[i].Ticket,
 				&rec.Hash, &tipHash, tipHeight)
 			if err != nil {
 				return err
 			}
 			// Could be more efficient by avoiding processTransaction, as we
 			// know it is a revocation.
-			err = w.processTransactionRecord(dbtx, rec, nil, nil)
+			err = w.processTransactionRecord(dbtx, rec, nil, nil, "revoketickets")
 			if err != nil {
 				return err
 			}
-			_, err = chainClient.SendRawTransaction(revocation, true)
+			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+			_, err = w.sendRawTransaction(txmgrNs, chainClient, revocation,
+				opts.AllowHighFees)
 			return err
 		})
 		if err != nil {
-			return err
+			return revoked[:i], err
 		}
-		log.Infof("Revoked ticket %v with revocation %v", revokableTickets[i],
+		log.Infof("Revoked ticket %v with revocation %v", &revoked[i].Ticket,
 			&rec.Hash)
 	}
 
-	return nil
+	return revoked, nil
 }
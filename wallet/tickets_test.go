@@ -0,0 +1,154 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/internal/testhelpers"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+// addTestTicket records an unmined transaction paying addrSubsidy and an
+// unmined ticket purchase spending it, which votes with addrVote and commits
+// the ticket price to addrSubsidy.  The ticket is returned.
+func addTestTicket(t *testing.T, w *Wallet, prev byte, addrVote, addrSubsidy hcutil.Address) *wire.MsgTx {
+	pkScript, err := txscript.PayToAddrScript(addrSubsidy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{prev}}, foreignSigScript))
+	funding.AddTxOut(wire.NewTxOut(2e8, pkScript))
+	addUnminedTx(t, w, funding)
+
+	input := &extendedOutPoint{
+		op:  &wire.OutPoint{Hash: funding.TxHash()},
+		amt: 2e8,
+	}
+	ticket, err := makeTicket(w.chainParams, nil, input, addrVote, addrSubsidy,
+		2e8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := w.PubKeyForAddress(addrSubsidy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket.TxIn[0].SignatureScript, err = txscript.NewScriptBuilder().
+		AddData(make([]byte, 71)).AddData(pubKey.SerializeCompressed()).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addUnminedTx(t, w, ticket)
+	return ticket
+}
+
+func TestDelegatedTickets(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	walletPkScript(t, w, udb.ExternalBranch, 1)
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), w.chainParams, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the ticket voting with an address the wallet does not control
+	// is delegated.
+	addTestTicket(t, w, 1, addrs[0], addrs[1])
+	delegated := addTestTicket(t, w, 2, foreign, addrs[1])
+
+	tickets, err := w.DelegatedTickets(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 1 {
+		t.Fatalf("found %d delegated tickets, want 1", len(tickets))
+	}
+	ticket := tickets[0]
+	if ticket.Hash != delegated.TxHash() {
+		t.Errorf("delegated ticket is %v, want %v", &ticket.Hash,
+			delegated.TxHash())
+	}
+	if ticket.VotingAddress == nil ||
+		ticket.VotingAddress.EncodeAddress() != foreign.EncodeAddress() {
+		t.Errorf("voting address is %v, want %v", ticket.VotingAddress, foreign)
+	}
+	if ticket.Price != 2e8 {
+		t.Errorf("ticket price is %v, want %v", ticket.Price, hcutil.Amount(2e8))
+	}
+	if ticket.Status != TicketStatusUnmined {
+		t.Errorf("ticket status is %v, want %v", ticket.Status,
+			TicketStatusUnmined)
+	}
+	if ticket.SpenderHash != nil {
+		t.Errorf("unspent ticket has spender %v", ticket.SpenderHash)
+	}
+}
+
+func TestAutoRevoke(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	walletPkScript(t, w, udb.ExternalBranch, 0)
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket := addTestTicket(t, w, 1, addrs[0], addrs[0])
+	ticketHash := ticket.TxHash()
+
+	c := testhelpers.NewMockChainClient()
+	w.chainClientLock.Lock()
+	w.chainClient = c
+	w.chainClientLock.Unlock()
+
+	if !w.AutoRevoke() {
+		t.Fatal("automatic revocation is disabled by default")
+	}
+	height := int32(w.chainParams.StakeValidationHeight)
+
+	// Missed tickets are not revoked while automatic revocation is
+	// disabled.
+	w.SetAutoRevoke(false)
+	if w.AutoRevoke() {
+		t.Fatal("automatic revocation was not disabled")
+	}
+	err = w.handleMissedTickets(&chainhash.Hash{2}, height,
+		[]*chainhash.Hash{&ticketHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent := c.SentTransactions(); len(sent) != 0 {
+		t.Fatalf("sent %d revocations with automatic revocation disabled",
+			len(sent))
+	}
+
+	w.SetAutoRevoke(true)
+	err = w.handleMissedTickets(&chainhash.Hash{2}, height,
+		[]*chainhash.Hash{&ticketHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := c.SentTransactions()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d revocations, want 1", len(sent))
+	}
+	if sent[0].TxIn[0].PreviousOutPoint.Hash != ticketHash {
+		t.Errorf("revocation spends %v, want the missed ticket %v",
+			&sent[0].TxIn[0].PreviousOutPoint.Hash, &ticketHash)
+	}
+}
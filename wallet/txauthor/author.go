@@ -9,6 +9,7 @@ package txauthor
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
@@ -63,6 +64,90 @@ func (InsufficientFundsError) Error() string {
 	return "insufficient funds available to construct transaction"
 }
 
+// InsufficientAddressFundsError describes the failure to fund a transaction
+// sent from a single address with the outputs paying to that address.
+type InsufficientAddressFundsError struct {
+	Address string
+}
+
+// InputSourceError generates the InputSourceError interface for an
+// InsufficientAddressFundsError.
+func (InsufficientAddressFundsError) InputSourceError() {}
+
+// Error satistifies the error interface.
+func (e InsufficientAddressFundsError) Error() string {
+	return fmt.Sprintf("insufficient funds available paying to address %s "+
+		"to construct transaction", e.Address)
+}
+
+// checkFromAddress returns an error if any previous output script does not pay
+// only to fromAddress.  Transactions sent from an address must only redeem
+// outputs paying to it, as protocols layered on the chain such as Omni infer
+// the sender of a transaction from the outputs redeemed by its inputs.
+func checkFromAddress(scripts [][]byte, fromAddress string, params *chaincfg.Params) error {
+	for i, script := range scripts {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txscript.DefaultScriptVersion, script, params)
+		if err != nil {
+			return err
+		}
+		if len(addrs) != 1 || addrs[0].EncodeAddress() != fromAddress {
+			return fmt.Errorf("input %d does not redeem an output paying "+
+				"to address %s", i, fromAddress)
+		}
+	}
+	return nil
+}
+
+// TxLimits describes wallet policy limits on the transactions it authors.  A
+// zero value for any limit disables it.
+type TxLimits struct {
+	// MaxInputs is the maximum number of inputs a transaction may spend.
+	MaxInputs int
+
+	// MaxSerializeSize is the maximum estimated serialize size, in bytes,
+	// of a signed transaction.
+	MaxSerializeSize int
+}
+
+// TooManyInputsError describes a transaction which would spend more inputs
+// than allowed by the MaxInputs limit.
+type TooManyInputsError struct {
+	Inputs    int
+	MaxInputs int
+}
+
+// Error satistifies the error interface.
+func (e TooManyInputsError) Error() string {
+	return fmt.Sprintf("transaction requires %d inputs which exceeds the "+
+		"limit of %d inputs", e.Inputs, e.MaxInputs)
+}
+
+// TxTooLargeError describes a transaction with an estimated serialize size
+// larger than allowed by the MaxSerializeSize limit.
+type TxTooLargeError struct {
+	Size    int
+	MaxSize int
+}
+
+// Error satistifies the error interface.
+func (e TxTooLargeError) Error() string {
+	return fmt.Sprintf("estimated transaction size of %d bytes exceeds the "+
+		"limit of %d bytes", e.Size, e.MaxSize)
+}
+
+// Check returns a TooManyInputsError or TxTooLargeError if a transaction with
+// the given number of inputs and estimated serialize size exceeds the limits.
+func (l TxLimits) Check(inputs, serializeSize int) error {
+	if l.MaxInputs > 0 && inputs > l.MaxInputs {
+		return TooManyInputsError{Inputs: inputs, MaxInputs: l.MaxInputs}
+	}
+	if l.MaxSerializeSize > 0 && serializeSize > l.MaxSerializeSize {
+		return TxTooLargeError{Size: serializeSize, MaxSize: l.MaxSerializeSize}
+	}
+	return nil
+}
+
 // AuthoredTx holds the state of a newly-created transaction and the change
 // output (if one was added).
 type AuthoredTx struct {
@@ -82,7 +167,9 @@ type ChangeSource func(dbtx walletdb.ReadWriteTx) ([]byte, uint16, error)
 // transaction size.
 //
 // Transaction inputs are chosen from repeated calls to fetchInputs with
-// increasing targets amounts.
+// increasing targets amounts.  When fromAddress is set, fetchInputs must only
+// provide inputs redeeming outputs paying to the address, and the transaction
+// is rejected if any input does not.
 //
 // If any remaining output value can be returned to the wallet via a change
 // output without violating mempool dust rules, a P2PKH change output is
@@ -94,11 +181,13 @@ type ChangeSource func(dbtx walletdb.ReadWriteTx) ([]byte, uint16, error)
 // If successful, the transaction, total input value spent, and all previous
 // output scripts are returned.  If the input source was unable to provide
 // enough input value to pay for every output any any necessary fees, an
-// InputSourceError is returned.
+// InputSourceError is returned.  If the transaction would exceed any of the
+// limits, a TooManyInputsError or TxTooLargeError is returned.
 //
 // BUGS: Fee estimation may be off when redeeming non-compressed P2PKH outputs.
 func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb hcutil.Amount,
-	fetchInputs InputSource, fetchChange ChangeSource, accType uint8, params *chaincfg.Params, sdb txscript.ScriptDB, fromAddress string) (*AuthoredTx, error) {
+	fetchInputs InputSource, fetchChange ChangeSource, accType uint8, params *chaincfg.Params, sdb txscript.ScriptDB, fromAddress string,
+	limits TxLimits) (*AuthoredTx, error) {
 
 	targetAmount := h.SumOutputValues(outputs)
 	if accType != udb.AcctypeBliss && accType != udb.AcctypeEc {
@@ -115,10 +204,23 @@ func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb hcutil.Amount,
 			return nil, err
 		}
 		if inputAmount < targetAmount+targetFee {
+			if fromAddress != "" {
+				return nil, InsufficientAddressFundsError{fromAddress}
+			}
 			return nil, InsufficientFundsError{}
 		}
+		if fromAddress != "" {
+			err = checkFromAddress(scripts, fromAddress, params)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		maxSignedSize, _ := txsizes.EstimateSerializeSizeByInputStripts(scripts, outputs, true, params, sdb)
+		err = limits.Check(len(inputs), maxSignedSize)
+		if err != nil {
+			return nil, err
+		}
 		maxRequiredFee := txrules.FeeForSerializeSize(relayFeePerKb, maxSignedSize)
 		remainingAmount := inputAmount - targetAmount
 		if remainingAmount < maxRequiredFee {
@@ -9,14 +9,35 @@ package txauthor_test
 import (
 	"testing"
 
-	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
 	. "github.com/HcashOrg/hcwallet/wallet/txauthor"
 	"github.com/HcashOrg/hcwallet/wallet/txrules"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
 
 	"github.com/HcashOrg/hcwallet/wallet/internal/txsizes"
 )
 
+// p2pkhScript is the output script of the outputs spent by the tests.  The
+// signature types of the inputs are determined from it.
+var p2pkhScript = []byte{txscript.OP_DUP, txscript.OP_HASH160, txscript.OP_DATA_20,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	txscript.OP_EQUALVERIFY, txscript.OP_CHECKSIG}
+
+// estimateSerializeSize returns the worst case serialize size of a transaction
+// spending inputCount P2PKH outputs.
+func estimateSerializeSize(inputCount int, txOuts []*wire.TxOut, addChangeOutput bool) int {
+	size, err := txsizes.EstimateSerializeSizeByAccount(inputCount, txOuts,
+		addChangeOutput, udb.AcctypeEc)
+	if err != nil {
+		panic(err)
+	}
+	return size
+}
+
 func p2pkhOutputs(amounts ...hcutil.Amount) []*wire.TxOut {
 	v := make([]*wire.TxOut, 0, len(amounts))
 	for _, a := range amounts {
@@ -30,15 +51,17 @@ func makeInputSource(unspents []*wire.TxOut) InputSource {
 	// Return outputs in order.
 	currentTotal := hcutil.Amount(0)
 	currentInputs := make([]*wire.TxIn, 0, len(unspents))
-	f := func(target hcutil.Amount) (hcutil.Amount, []*wire.TxIn, [][]byte, error) {
+	currentScripts := make([][]byte, 0, len(unspents))
+	f := func(target hcutil.Amount, fromAddress string) (hcutil.Amount, []*wire.TxIn, [][]byte, error) {
 		for currentTotal < target && len(unspents) != 0 {
 			u := unspents[0]
 			unspents = unspents[1:]
 			nextInput := wire.NewTxIn(&wire.OutPoint{}, nil)
 			currentTotal += hcutil.Amount(u.Value)
 			currentInputs = append(currentInputs, nextInput)
+			currentScripts = append(currentScripts, p2pkhScript)
 		}
-		return currentTotal, currentInputs, make([][]byte, len(currentInputs)), nil
+		return currentTotal, currentInputs, currentScripts, nil
 	}
 	return InputSource(f)
 }
@@ -63,7 +86,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 			Outputs:        p2pkhOutputs(1e6),
 			RelayFee:       1e3,
 			ChangeAmount: 1e8 - 1e6 - txrules.FeeForSerializeSize(1e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(1e6), true)),
+				estimateSerializeSize(1, p2pkhOutputs(1e6), true)),
 			InputCount: 1,
 		},
 		2: {
@@ -71,7 +94,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 			Outputs:        p2pkhOutputs(1e6),
 			RelayFee:       1e4,
 			ChangeAmount: 1e8 - 1e6 - txrules.FeeForSerializeSize(1e4,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(1e6), true)),
+				estimateSerializeSize(1, p2pkhOutputs(1e6), true)),
 			InputCount: 1,
 		},
 		3: {
@@ -79,7 +102,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 			Outputs:        p2pkhOutputs(1e6, 1e6, 1e6),
 			RelayFee:       1e4,
 			ChangeAmount: 1e8 - 3e6 - txrules.FeeForSerializeSize(1e4,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(1e6, 1e6, 1e6), true)),
+				estimateSerializeSize(1, p2pkhOutputs(1e6, 1e6, 1e6), true)),
 			InputCount: 1,
 		},
 		4: {
@@ -87,7 +110,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 			Outputs:        p2pkhOutputs(1e6, 1e6, 1e6),
 			RelayFee:       2.55e3,
 			ChangeAmount: 1e8 - 3e6 - txrules.FeeForSerializeSize(2.55e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(1e6, 1e6, 1e6), true)),
+				estimateSerializeSize(1, p2pkhOutputs(1e6, 1e6, 1e6), true)),
 			InputCount: 1,
 		},
 
@@ -95,7 +118,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		5: {
 			UnspentOutputs: p2pkhOutputs(1e8),
 			Outputs: p2pkhOutputs(1e8 - 602 - txrules.FeeForSerializeSize(1e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(0), true))),
+				estimateSerializeSize(1, p2pkhOutputs(0), true))),
 			RelayFee:     1e3,
 			ChangeAmount: 0,
 			InputCount:   1,
@@ -103,7 +126,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		6: {
 			UnspentOutputs: p2pkhOutputs(1e8),
 			Outputs: p2pkhOutputs(1e8 - 603 - txrules.FeeForSerializeSize(1e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(0), true))),
+				estimateSerializeSize(1, p2pkhOutputs(0), true))),
 			RelayFee:     1e3,
 			ChangeAmount: 603,
 			InputCount:   1,
@@ -113,7 +136,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		7: {
 			UnspentOutputs: p2pkhOutputs(1e8),
 			Outputs: p2pkhOutputs(1e8 - 1537 - txrules.FeeForSerializeSize(2.55e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(0), true))),
+				estimateSerializeSize(1, p2pkhOutputs(0), true))),
 			RelayFee:     2.55e3,
 			ChangeAmount: 0,
 			InputCount:   1,
@@ -121,7 +144,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		8: {
 			UnspentOutputs: p2pkhOutputs(1e8),
 			Outputs: p2pkhOutputs(1e8 - 1538 - txrules.FeeForSerializeSize(2.55e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(0), true))),
+				estimateSerializeSize(1, p2pkhOutputs(0), true))),
 			RelayFee:     2.55e3,
 			ChangeAmount: 1538,
 			InputCount:   1,
@@ -133,7 +156,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		9: {
 			UnspentOutputs: p2pkhOutputs(1e8, 1e8),
 			Outputs: p2pkhOutputs(1e8 - 603 - txrules.FeeForSerializeSize(1e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(0), true))),
+				estimateSerializeSize(1, p2pkhOutputs(0), true))),
 			RelayFee:     1e3,
 			ChangeAmount: 603,
 			InputCount:   1,
@@ -147,7 +170,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		10: {
 			UnspentOutputs: p2pkhOutputs(1e8, 1e8),
 			Outputs: p2pkhOutputs(1e8 - 545 - txrules.FeeForSerializeSize(1e3,
-				txsizes.EstimateSerializeSize(1, p2pkhOutputs(0), true))),
+				estimateSerializeSize(1, p2pkhOutputs(0), true))),
 			RelayFee:     1e3,
 			ChangeAmount: 0,
 			InputCount:   1,
@@ -159,7 +182,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 			Outputs:        p2pkhOutputs(1e8),
 			RelayFee:       1e3,
 			ChangeAmount: 1e8 - txrules.FeeForSerializeSize(1e3,
-				txsizes.EstimateSerializeSize(2, p2pkhOutputs(1e8), true)),
+				estimateSerializeSize(2, p2pkhOutputs(1e8), true)),
 			InputCount: 2,
 		},
 
@@ -174,14 +197,15 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		},
 	}
 
-	changeSource := func() ([]byte, uint16, error) {
+	changeSource := func(walletdb.ReadWriteTx) ([]byte, uint16, error) {
 		// Only length matters for these tests.
 		return make([]byte, txsizes.P2PKHPkScriptSize), 0, nil
 	}
 
 	for i, test := range tests {
 		inputSource := makeInputSource(test.UnspentOutputs)
-		tx, err := NewUnsignedTransaction(test.Outputs, test.RelayFee, inputSource, changeSource)
+		tx, err := NewUnsignedTransaction(test.Outputs, test.RelayFee, inputSource,
+			changeSource, udb.AcctypeEc, &chaincfg.SimNetParams, nil, "", TxLimits{})
 		switch e := err.(type) {
 		case nil:
 		case InputSourceError:
@@ -219,3 +243,92 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		}
 	}
 }
+
+func TestTxLimits(t *testing.T) {
+	checks := []struct {
+		limits TxLimits
+		inputs int
+		size   int
+		err    error
+	}{
+		{TxLimits{}, 1000, 1e6, nil},
+		{TxLimits{MaxInputs: 2}, 2, 1e6, nil},
+		{TxLimits{MaxInputs: 2}, 3, 100, TooManyInputsError{Inputs: 3, MaxInputs: 2}},
+		{TxLimits{MaxSerializeSize: 1000}, 1000, 1000, nil},
+		{TxLimits{MaxSerializeSize: 1000}, 1, 1001, TxTooLargeError{Size: 1001, MaxSize: 1000}},
+		{TxLimits{MaxInputs: 2, MaxSerializeSize: 1000}, 3, 1001,
+			TooManyInputsError{Inputs: 3, MaxInputs: 2}},
+	}
+	for i, test := range checks {
+		err := test.limits.Check(test.inputs, test.size)
+		if err != test.err {
+			t.Errorf("Check %d: got error %v, want %v", i, err, test.err)
+		}
+	}
+
+	changeSource := func(walletdb.ReadWriteTx) ([]byte, uint16, error) {
+		return make([]byte, txsizes.P2PKHPkScriptSize), 0, nil
+	}
+	twoInputSize := estimateSerializeSize(2, p2pkhOutputs(1e8), true)
+	authors := []struct {
+		limits TxLimits
+		err    error
+	}{
+		{TxLimits{MaxInputs: 2, MaxSerializeSize: twoInputSize}, nil},
+		{TxLimits{MaxInputs: 1}, TooManyInputsError{Inputs: 2, MaxInputs: 1}},
+		{TxLimits{MaxSerializeSize: twoInputSize - 1},
+			TxTooLargeError{Size: twoInputSize, MaxSize: twoInputSize - 1}},
+	}
+	for i, test := range authors {
+		// Paying the output and fee requires both unspent outputs.
+		inputSource := makeInputSource(p2pkhOutputs(1e8, 1e8))
+		_, err := NewUnsignedTransaction(p2pkhOutputs(1e8), 1e3, inputSource,
+			changeSource, udb.AcctypeEc, &chaincfg.SimNetParams, nil, "",
+			test.limits)
+		if err != test.err {
+			t.Errorf("Author %d: got error %v, want %v", i, err, test.err)
+		}
+	}
+}
+
+func TestFromAddress(t *testing.T) {
+	params := &chaincfg.SimNetParams
+	from, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), params, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromAddress := from.EncodeAddress()
+	changeSource := func(walletdb.ReadWriteTx) ([]byte, uint16, error) {
+		return make([]byte, txsizes.P2PKHPkScriptSize), 0, nil
+	}
+
+	// The outputs of the input source pay to the sending address.
+	inputSource := makeInputSource(p2pkhOutputs(2e8))
+	_, err = NewUnsignedTransaction(p2pkhOutputs(1e8), 1e3, inputSource,
+		changeSource, udb.AcctypeEc, params, nil, fromAddress, TxLimits{})
+	if err != nil {
+		t.Errorf("Funding from the sending address: %v", err)
+	}
+
+	inputSource = makeInputSource(p2pkhOutputs(1e8))
+	_, err = NewUnsignedTransaction(p2pkhOutputs(2e8), 1e3, inputSource,
+		changeSource, udb.AcctypeEc, params, nil, fromAddress, TxLimits{})
+	want := InsufficientAddressFundsError{Address: fromAddress}
+	if err != want {
+		t.Errorf("Insufficient funds of the sending address: got error %v, "+
+			"want %v", err, want)
+	}
+
+	// Inputs redeeming outputs paying to other addresses are rejected.
+	otherScript := append([]byte(nil), p2pkhScript...)
+	otherScript[3] = 1
+	otherSource := func(hcutil.Amount, string) (hcutil.Amount, []*wire.TxIn, [][]byte, error) {
+		inputs := []*wire.TxIn{wire.NewTxIn(&wire.OutPoint{}, nil)}
+		return 2e8, inputs, [][]byte{otherScript}, nil
+	}
+	_, err = NewUnsignedTransaction(p2pkhOutputs(1e8), 1e3, otherSource,
+		changeSource, udb.AcctypeEc, params, nil, fromAddress, TxLimits{})
+	if err == nil {
+		t.Error("Funded from outputs paying to other addresses")
+	}
+}
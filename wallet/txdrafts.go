@@ -0,0 +1,194 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// CreateTransactionDraft authors an unsigned transaction paying outputs from
+// the account's outputs with at least minconf confirmations and records it as
+// a transaction draft, reserving its inputs.  Inputs are selected and reserved
+// in a single database transaction, so concurrent callers never draft
+// transactions spending the same outputs, and reserved outputs are never
+// selected for other transactions until the draft is committed with
+// CommitTransactionDraft or canceled with CancelTransactionDraft.
+// Reservations are saved in the database and persist across restarts.
+//
+// The draft is identified by the hash of the returned unsigned transaction.
+func (w *Wallet) CreateTransactionDraft(outputs []*wire.TxOut, account uint32, minconf int32) (*txauthor.AuthoredTx, error) {
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, err
+	}
+
+	var doneFuncs []func()
+	defer func() {
+		for _, f := range doneFuncs {
+			f()
+		}
+	}()
+
+	var atx *txauthor.AuthoredTx
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		accprop, err := w.Manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		inputSource := w.TxStore.MakeInputSource(txmgrNs, addrmgrNs, account,
+			minconf, tipHeight)
+
+		// Change addresses are derived with the update transaction, since
+		// the address pool would otherwise begin a nested update.
+		var changeSourceUpdates []func(walletdb.ReadWriteTx) error
+		persist := w.deferPersistReturnedChild(&changeSourceUpdates)
+		changeSource := w.changeSource(persist, account, nil)
+		fetchChange := func(walletdb.ReadWriteTx) ([]byte, uint16, error) {
+			return changeSource(dbtx)
+		}
+
+		getScript := txscript.ScriptClosure(func(addr hcutil.Address) ([]byte, error) {
+			// First check tx manager script store.
+			scrTxStore, err := w.TxStore.GetTxScript(txmgrNs, addr.ScriptAddress())
+			if err != nil {
+				return nil, err
+			}
+			if scrTxStore != nil {
+				return scrTxStore, nil
+			}
+
+			// Then check the address manager.
+			script, done, err := w.Manager.RedeemScript(addrmgrNs, addr)
+			if err != nil {
+				return nil, err
+			}
+			doneFuncs = append(doneFuncs, done)
+			return script, nil
+		})
+
+		atx, err = txauthor.NewUnsignedTransaction(outputs, relayFee,
+			inputSource.SelectInputs, fetchChange, accprop.AccountType,
+			w.chainParams, getScript, "", w.txLimits)
+		if err != nil {
+			return err
+		}
+		if atx.ChangeIndex >= 0 {
+			atx.RandomizeChangePosition()
+		}
+
+		for _, up := range changeSourceUpdates {
+			err := up(dbtx)
+			if err != nil {
+				return err
+			}
+		}
+
+		draft := &udb.TxDraft{
+			Tx:      *atx.Tx,
+			Account: account,
+			Created: time.Now(),
+		}
+		return w.TxStore.PutTxDraft(txmgrNs, draft)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Created transaction draft %v", atx.Tx.TxHash())
+	return atx, nil
+}
+
+// CommitTransactionDraft signs the unsigned transaction of a draft created by
+// CreateTransactionDraft and publishes it, removing the draft.  The hash of the
+// published transaction, which is the draft hash, is returned.  The draft
+// remains if the transaction can not be signed or published.  This method
+// requires the wallet to be unlocked.
+func (w *Wallet) CommitTransactionDraft(hash *chainhash.Hash) (*chainhash.Hash, error) {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	if w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to commit transaction drafts"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	var draft *udb.TxDraft
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		draft, err = w.TxStore.TxDraft(txmgrNs, hash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &draft.Tx
+	sigErrs, err := w.SignTransaction(tx, txscript.SigHashAll, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigErrs) != 0 {
+		e := sigErrs[0]
+		return nil, fmt.Errorf("failed to sign input %d of transaction "+
+			"draft %v: %v", e.InputIndex, hash, e.Error)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializeSize())
+	err = tx.Serialize(&buf)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := w.publishTransaction(tx, buf.Bytes(), w.allowHighFees,
+		chainClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// The published transaction now spends the reserved outputs, so the
+	// draft is no longer needed to keep them from being selected.
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.DeleteTxDraft(txmgrNs, hash)
+	})
+	if err != nil {
+		log.Errorf("Failed to remove committed transaction draft %v: %v",
+			hash, err)
+	}
+	return txHash, nil
+}
+
+// CancelTransactionDraft removes a draft created by CreateTransactionDraft,
+// releasing the reservations of its inputs.  An error with code
+// ErrValueNoExists is returned if there is no such draft.
+func (w *Wallet) CancelTransactionDraft(hash *chainhash.Hash) error {
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.DeleteTxDraft(txmgrNs, hash)
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Canceled transaction draft %v", hash)
+	return nil
+}
@@ -21,20 +21,31 @@ const DefaultRelayFeePerKb hcutil.Amount = 1e5
 // cause the output to be considered dust.  Transactions with dust outputs are
 // not standard and are rejected by mempools with default policies.
 func IsDustAmount(amount hcutil.Amount, scriptSize int, relayFeePerKb hcutil.Amount) bool {
-	// Calculate the total (estimated) cost to the network.  This is
-	// calculated using the serialize size of the output plus the serial
-	// size of a transaction input which redeems it.  The output is assumed
-	// to be compressed P2PKH as this is the most common script type.  Use
-	// the average size of a compressed P2PKH redeem input (165) rather than
-	// the largest possible (txsizes.RedeemP2PKHInputSize).
-	totalSize := 8 + 2 + wire.VarIntSerializeSize(uint64(scriptSize)) +
-		scriptSize + 165
+	totalSize := dustCostSize(scriptSize)
 
 	// Dust is defined as an output value where the total cost to the network
 	// (output size + input size) is greater than 1/3 of the relay fee.
 	return int64(amount)*1000/(3*int64(totalSize)) < int64(relayFeePerKb)
 }
 
+// dustCostSize returns the total (estimated) cost to the network of an output
+// with a script of scriptSize bytes.  This is calculated using the serialize
+// size of the output plus the serial size of a transaction input which redeems
+// it.  The output is assumed to be compressed P2PKH as this is the most common
+// script type.  Use the average size of a compressed P2PKH redeem input (165)
+// rather than the largest possible (txsizes.RedeemP2PKHInputSize).
+func dustCostSize(scriptSize int) int {
+	return 8 + 2 + wire.VarIntSerializeSize(uint64(scriptSize)) +
+		scriptSize + 165
+}
+
+// DustThreshold returns the smallest output value which is not considered dust
+// by IsDustAmount for an output script of scriptSize bytes.
+func DustThreshold(scriptSize int, relayFeePerKb hcutil.Amount) hcutil.Amount {
+	cost := 3 * int64(dustCostSize(scriptSize)) * int64(relayFeePerKb)
+	return hcutil.Amount((cost + 999) / 1000)
+}
+
 // IsDustOutput determines whether a transaction output is considered dust.
 // Transactions with dust outputs are not standard and are rejected by mempools
 // with default policies.
@@ -0,0 +1,39 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrules_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	. "github.com/HcashOrg/hcwallet/wallet/txrules"
+)
+
+func TestDustThreshold(t *testing.T) {
+	tests := []struct {
+		ScriptSize int
+		RelayFee   hcutil.Amount
+		Expected   hcutil.Amount
+	}{
+		0: {25, 1e5, 60300},
+		1: {25, 1e4, 6030},
+		2: {23, 1e5, 59700},
+		3: {25, 1234, 745},
+		4: {25, 0, 0},
+	}
+	for i, test := range tests {
+		threshold := DustThreshold(test.ScriptSize, test.RelayFee)
+		if threshold != test.Expected {
+			t.Errorf("Test %d: Got %v: Want %v", i, threshold, test.Expected)
+		}
+		if IsDustAmount(threshold, test.ScriptSize, test.RelayFee) {
+			t.Errorf("Test %d: threshold %v is dust", i, threshold)
+		}
+		if threshold > 0 && !IsDustAmount(threshold-1, test.ScriptSize, test.RelayFee) {
+			t.Errorf("Test %d: amount %v below threshold is not dust", i,
+				threshold-1)
+		}
+	}
+}
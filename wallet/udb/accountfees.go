@@ -0,0 +1,79 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AccountFeeType identifies which fee setting of an account is being
+// described.
+type AccountFeeType byte
+
+// These constants describe the fee settings which may be recorded for an
+// account.
+const (
+	// AccountRelayFee is the fee per kB of serialized transaction used when
+	// authoring regular transactions.
+	AccountRelayFee AccountFeeType = iota
+
+	// AccountTicketFee is the fee per kB of serialized transaction used when
+	// authoring ticket purchases.
+	AccountTicketFee
+)
+
+type accountFeesTy struct {
+}
+
+var accountFees accountFeesTy
+
+var accountFeesRootBucketKey = []byte("acctfees")
+
+func (accountFeesTy) rootBucketKey() []byte { return accountFeesRootBucketKey }
+
+// key returns the key of an account fee setting.  It is serialized as the
+// account number (4 bytes) followed by the fee type (1 byte).
+func (accountFeesTy) key(account uint32, feeType AccountFeeType) []byte {
+	k := make([]byte, 5)
+	byteOrder.PutUint32(k, account)
+	k[4] = byte(feeType)
+	return k
+}
+
+func (t accountFeesTy) setFee(tx walletdb.ReadWriteTx, account uint32, feeType AccountFeeType, fee hcutil.Amount) error {
+	b := tx.ReadWriteBucket(t.rootBucketKey())
+	v := make([]byte, 8)
+	byteOrder.PutUint64(v, uint64(fee))
+	return b.Put(t.key(account, feeType), v)
+}
+
+func (t accountFeesTy) fee(tx walletdb.ReadTx, account uint32, feeType AccountFeeType) (hcutil.Amount, bool) {
+	b := tx.ReadBucket(t.rootBucketKey())
+	v := b.Get(t.key(account, feeType))
+	if len(v) != 8 {
+		return 0, false
+	}
+	return hcutil.Amount(byteOrder.Uint64(v)), true
+}
+
+// SetAccountFee saves a fee setting for an account, overriding the wallet's
+// global setting when authoring transactions for it.
+func SetAccountFee(tx walletdb.ReadWriteTx, account uint32, feeType AccountFeeType, fee hcutil.Amount) error {
+	err := accountFees.setFee(tx, account, feeType, fee)
+	if err != nil {
+		const str = "failed to put account fee"
+		return apperrors.E{ErrorCode: apperrors.ErrDatabase, Description: str, Err: err}
+	}
+	return nil
+}
+
+// AccountFee returns the saved fee setting, if any, for an account.  The
+// boolean return is false if no fee has been saved and the wallet's global
+// setting should be used instead.
+func AccountFee(tx walletdb.ReadTx, account uint32, feeType AccountFeeType) (hcutil.Amount, bool) {
+	return accountFees.fee(tx, account, feeType)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestAccountFees(t *testing.T) {
+	db, _, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket(accountFees.rootBucketKey())
+		if err != nil {
+			return err
+		}
+
+		// Accounts without saved fees use the wallet's global settings.
+		if fee, ok := AccountFee(tx, 1, AccountRelayFee); ok {
+			t.Errorf("new database has relay fee %v for account 1", fee)
+		}
+
+		// The relay and ticket fees of an account are saved separately.
+		err = SetAccountFee(tx, 1, AccountRelayFee, 1e5)
+		if err != nil {
+			return err
+		}
+		err = SetAccountFee(tx, 1, AccountTicketFee, 2e5)
+		if err != nil {
+			return err
+		}
+		tests := []struct {
+			account uint32
+			feeType AccountFeeType
+			fee     hcutil.Amount
+			ok      bool
+		}{
+			{1, AccountRelayFee, 1e5, true},
+			{1, AccountTicketFee, 2e5, true},
+			{0, AccountRelayFee, 0, false},
+			{0, AccountTicketFee, 0, false},
+		}
+		for _, test := range tests {
+			fee, ok := AccountFee(tx, test.account, test.feeType)
+			if fee != test.fee || ok != test.ok {
+				t.Errorf("account %d fee type %d: got (%v, %v), want (%v, %v)",
+					test.account, test.feeType, fee, ok, test.fee, test.ok)
+			}
+		}
+
+		// Saving a fee again replaces it.
+		err = SetAccountFee(tx, 1, AccountRelayFee, 3e5)
+		if err != nil {
+			return err
+		}
+		if fee, _ := AccountFee(tx, 1, AccountRelayFee); fee != 3e5 {
+			t.Errorf("replaced relay fee is %v, want %v", fee, hcutil.Amount(3e5))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -172,6 +172,31 @@ var (
 
 	blissaddrIdxBucketName = []byte("blissaddridx")
 
+	// externalBranchesBucketName is used to record the additional external
+	// branches of accounts, their names, and their last used and returned
+	// child indexes.  This was added by database version 15.
+	externalBranchesBucketName = []byte("extbranches")
+
+	// invoicesBucketName is used to record the invoice branch child of the
+	// address derived for each invoice id.  This was added by database
+	// version 16.
+	invoicesBucketName = []byte("invoices")
+
+	// multisigAccountsBucketName is used to record the cosigners and address
+	// derivation state of multisig accounts, keyed by name.  This was added
+	// by database version 18.
+	multisigAccountsBucketName = []byte("msaccounts")
+
+	// atomicSwapsBucketName is used to record the atomic swap contracts
+	// created by the wallet, keyed by the hash160 of the contract.  This was
+	// added by database version 19.
+	atomicSwapsBucketName = []byte("atomicswaps")
+
+	// defaultAddrsBucketName is used to record the default address set for
+	// accounts, keyed by account number.  This was added by database
+	// version 24.
+	defaultAddrsBucketName = []byte("defaultaddrs")
+
 	// addrPoolMetaKeyLen is the byte length of the address pool
 	// prefixes. It is 11 bytes for the prefix and 4 bytes for
 	// the account number.
@@ -878,6 +878,22 @@ func (m *Manager) AddrAccount(ns walletdb.ReadBucket, address hcutil.Address) (u
 	return account, nil
 }
 
+// AddrBranchChild returns the account, branch, and child index of an address
+// derived from a BIP0044 account branch.  ok is false for addresses which are
+// not derived from an account branch, such as imported addresses.
+func (m *Manager) AddrBranchChild(ns walletdb.ReadBucket, address hcutil.Address) (account, branch, child uint32, ok bool, err error) {
+	address = normalizeAddress(address)
+	dbAddr, err := fetchAddress(ns, address.ScriptAddress())
+	if err != nil {
+		return 0, 0, 0, false, maybeConvertDbError(err)
+	}
+	row, ok := dbAddr.(*dbChainAddressRow)
+	if !ok {
+		return 0, 0, 0, false, nil
+	}
+	return row.account, row.branch, row.index, true, nil
+}
+
 // ExistsAddress returns whether or not the address id exists in the database.
 func (m *Manager) ExistsAddress(ns walletdb.ReadBucket, address hcutil.Address) bool {
 	address = normalizeAddress(address)
@@ -1446,11 +1462,18 @@ func (m *Manager) MarkUsed(ns walletdb.ReadWriteBucket, address hcutil.Address)
 	}
 	lastUsedExtIndex := row.lastUsedExternalIndex
 	lastUsedIntIndex := row.lastUsedInternalIndex
-	switch bip0044Addr.branch {
-	case ExternalBranch:
+	switch {
+	case bip0044Addr.branch == ExternalBranch:
 		lastUsedExtIndex = bip0044Addr.index
-	case InternalBranch:
+	case bip0044Addr.branch == InternalBranch:
 		lastUsedIntIndex = bip0044Addr.index
+	case bip0044Addr.branch == InvoiceBranch:
+		// Invoice addresses are not handed out in order, so there is no
+		// last used child to record.
+		return nil
+	case existsExternalBranch(ns, bip0044Addr.account, bip0044Addr.branch):
+		return markExternalBranchChild(ns, bip0044Addr.account,
+			bip0044Addr.branch, bip0044Addr.index, true)
 	default:
 		const str = "address row records unsupported account branch"
 		return apperrors.E{ErrorCode: apperrors.ErrData, Description: str, Err: nil}
@@ -1485,11 +1508,13 @@ func (m *Manager) MarkUsedChildIndex(tx walletdb.ReadWriteTx, account, branch, c
 	}
 	lastUsedExtIndex := row.lastUsedExternalIndex
 	lastUsedIntIndex := row.lastUsedInternalIndex
-	switch branch {
-	case ExternalBranch:
+	switch {
+	case branch == ExternalBranch:
 		lastUsedExtIndex = child
-	case InternalBranch:
+	case branch == InternalBranch:
 		lastUsedIntIndex = child
+	case existsExternalBranch(ns, account, branch):
+		return markExternalBranchChild(ns, account, branch, child, true)
 	default:
 		const str = "unsupported account branch"
 		return apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
@@ -1527,11 +1552,13 @@ func (m *Manager) MarkReturnedChildIndex(tx walletdb.ReadWriteTx, account, branc
 	}
 	lastRetExtIndex := row.lastReturnedExternalIndex
 	lastRetIntIndex := row.lastReturnedInternalIndex
-	switch branch {
-	case ExternalBranch:
+	switch {
+	case branch == ExternalBranch:
 		lastRetExtIndex = child
-	case InternalBranch:
+	case branch == InternalBranch:
 		lastRetIntIndex = child
+	case existsExternalBranch(ns, account, branch):
+		return markExternalBranchChild(ns, account, branch, child, false)
 	default:
 		const str = "unsupported account branch"
 		return apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
@@ -1581,10 +1608,12 @@ func (m *Manager) syncAccountToAddrIndex(ns walletdb.ReadWriteBucket, account ui
 	}
 
 	// Derive the account branch xpub, and if the account is unlocked, also
-	// derive the xpriv.
+	// derive the xpriv.  Additional external branches are only recorded for
+	// ECDSA accounts.
 	var xpubBranch, xprivBranch *hdkeychain.ExtendedKey
-	switch branch {
-	case ExternalBranch, InternalBranch:
+	switch {
+	case branch == ExternalBranch, branch == InternalBranch,
+		existsExternalBranch(ns, account, branch):
 		if acctInfo.acctType == AcctypeEc {
 			xpubBranch, err = acctInfo.acctKeyPub.Child(branch)
 		} else if acctInfo.acctType == AcctypeBliss {
@@ -1841,6 +1870,135 @@ func (m *Manager) NewAccount(ns walletdb.ReadWriteBucket, name string, acctype u
 	return account, nil
 }
 
+// ImportAccount records a BIP0044 account extended key, exported from another
+// wallet, as the next account of the manager and returns the account number.
+// The key must be at the account depth of the BIP0044 hierarchy for the
+// manager's network, and its account index must be the next account number so
+// the account is recreated at the same index it was exported from.  Both ECDSA
+// and bliss account keys are supported.
+//
+// Extended private keys may only be imported by unlocked managers, while
+// extended public keys may only be imported by watching-only managers, as
+// every account of a manager must have the same key capabilities.
+func (m *Manager) ImportAccount(ns walletdb.ReadWriteBucket, name string, acctKey *hdkeychain.ExtendedKey) (uint32, error) {
+	if acctKey.IsPrivate() && m.watchingOnly {
+		return 0, managerError(apperrors.ErrWatchingOnly, errWatchingOnly, nil)
+	}
+	if !acctKey.IsPrivate() && !m.watchingOnly {
+		str := "extended public keys may only be imported by " +
+			"watching-only wallets"
+		return 0, managerError(apperrors.ErrInput, str, nil)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if acctKey.IsPrivate() && m.locked {
+		return 0, managerError(apperrors.ErrLocked, errLocked, nil)
+	}
+
+	if !acctKey.IsForNet(m.chainParams) {
+		str := fmt.Sprintf("the extended key is not for %s",
+			m.chainParams.Name)
+		return 0, managerError(apperrors.ErrWrongNet, str, nil)
+	}
+	acctType := acctKey.GetAlgType()
+	switch {
+	case acctType != AcctypeEc && acctType != AcctypeBliss:
+		str := fmt.Sprintf("unknown account key type %d", acctType)
+		return 0, managerError(apperrors.ErrInvalidKeyType, str, nil)
+	case acctType == AcctypeBliss && !acctKey.IsPrivate():
+		// Bliss addresses can only be derived from private keys.
+		str := "bliss accounts require an extended private key"
+		return 0, managerError(apperrors.ErrInvalidKeyType, str, nil)
+	}
+
+	// Validate account name
+	if err := ValidateAccountName(name); err != nil {
+		return 0, err
+	}
+
+	// Check that account with the same name does not exist
+	_, err := fetchAccountByName(ns, name)
+	if err == nil {
+		str := fmt.Sprintf("account with the same name already exists")
+		return 0, managerError(apperrors.ErrDuplicateAccount, str, err)
+	}
+
+	account, err := fetchLastAccount(ns)
+	if err != nil {
+		return 0, err
+	}
+	account++
+	if account > MaxAccountNum {
+		return 0, managerError(apperrors.ErrAccountNumTooHigh, errAcctTooHigh, nil)
+	}
+
+	// The key must be the hardened account child of a BIP0044 coin type key,
+	// m/44'/<coin type>'/<account>', with the next account number.
+	if acctKey.Depth() != 3 || acctKey.ChildNum() < hdkeychain.HardenedKeyStart {
+		str := "the extended key is not a BIP0044 account key"
+		return 0, managerError(apperrors.ErrKeyChain, str, nil)
+	}
+	if keyAccount := acctKey.ChildNum() - hdkeychain.HardenedKeyStart; keyAccount != account {
+		str := fmt.Sprintf("the extended key is for account %d but the "+
+			"next account is %d", keyAccount, account)
+		return 0, managerError(apperrors.ErrInvalidAccount, str, nil)
+	}
+	if acctType == AcctypeEc {
+		if err := checkBranchKeys(acctKey); err != nil {
+			str := "the extended key is unusable"
+			return 0, managerError(apperrors.ErrKeyChain, str, err)
+		}
+	}
+
+	acctKeyPub := acctKey
+	if acctKey.IsPrivate() {
+		acctKeyPub, err = acctKey.Neuter()
+		if err != nil {
+			str := "failed to convert public key for account"
+			return 0, managerError(apperrors.ErrKeyChain, str, err)
+		}
+	}
+	apes, err := acctKeyPub.String()
+	if err != nil {
+		str := "failed to get public key string for account"
+		return 0, managerError(apperrors.ErrCrypto, str, err)
+	}
+	acctPubEnc, err := m.cryptoKeyPub.Encrypt([]byte(apes))
+	if err != nil {
+		str := "failed to encrypt public key for account"
+		return 0, managerError(apperrors.ErrCrypto, str, err)
+	}
+	var acctPrivEnc []byte
+	if acctKey.IsPrivate() {
+		apes, err = acctKey.String()
+		if err != nil {
+			str := "failed to get private key string for account"
+			return 0, managerError(apperrors.ErrCrypto, str, err)
+		}
+		acctPrivEnc, err = m.cryptoKeyPriv.Encrypt([]byte(apes))
+		if err != nil {
+			str := "failed to encrypt private key for account"
+			return 0, managerError(apperrors.ErrCrypto, str, err)
+		}
+	}
+
+	row := bip0044AccountInfo(acctPubEnc, acctPrivEnc, 0, 0,
+		^uint32(0), ^uint32(0), ^uint32(0), ^uint32(0), name, acctType, DBVersion)
+	err = putAccountInfo(ns, account, row)
+	if err != nil {
+		return 0, err
+	}
+
+	// Save last account metadata
+	if err := PutLastAccount(ns, account); err != nil {
+		return 0, err
+	}
+
+	return account, nil
+}
+
 // RenameAccount renames an account stored in the manager based on the
 // given account number with the given name.  If an account with the same name
 // already exists, ErrDuplicateAccount will be returned.
@@ -2610,6 +2768,17 @@ func createAddressManager(ns walletdb.ReadWriteBucket, seed, pubPassphrase, priv
 			return err
 		}
 
+		// Save a challenge to verify backups of the seed, which is not
+		// itself saved.
+		challenge, err := newSeedBackupChallenge(seed, config, cryptoKeyPriv)
+		if err != nil {
+			return err
+		}
+		err = putSeedBackupChallenge(ns, challenge)
+		if err != nil {
+			return err
+		}
+
 		// Set the next to use addresses as empty for the address pool.
 		err = putNextToUseAddrPoolIdx(ns, false, DefaultAccountNum, 0)
 		if err != nil {
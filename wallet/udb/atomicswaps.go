@@ -0,0 +1,141 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AtomicSwap records an atomic swap contract funded by the wallet, either as
+// the initiator (who chose the secret) or the participant of the swap.
+type AtomicSwap struct {
+	Contract    []byte
+	ContractTx  chainhash.Hash
+	ContractOut uint32
+	Initiator   bool
+
+	// Secret is the preimage of the contract's secret hash.  It is known
+	// when creating the swap as the initiator, and otherwise recorded once
+	// a redemption of the contract is observed.  Secrets chosen by the
+	// initiator are not yet public, and are recorded encrypted with the
+	// private crypto key (SecretEncrypted is true).
+	Secret          []byte
+	SecretEncrypted bool
+
+	Created time.Time
+}
+
+const (
+	atomicSwapInitiatorFlag       = 1 << 0
+	atomicSwapSecretEncryptedFlag = 1 << 1
+)
+
+// The atomic swaps bucket records atomic swap contracts keyed by the hash160
+// of the contract.  The value is serialized as such:
+//
+//   [0:32]  Contract transaction hash (32 bytes)
+//   [32:36] Contract output index (4 bytes)
+//   [36]    Flags (1 byte)
+//   [37:45] Creation time (8 bytes)
+//   [45]    Secret length (1 byte)
+//   [46:]   Secret followed by the contract script
+
+func valueAtomicSwap(s *AtomicSwap) []byte {
+	v := make([]byte, 46, 46+len(s.Secret)+len(s.Contract))
+	copy(v, s.ContractTx[:])
+	binary.LittleEndian.PutUint32(v[32:], s.ContractOut)
+	if s.Initiator {
+		v[36] |= atomicSwapInitiatorFlag
+	}
+	if s.SecretEncrypted {
+		v[36] |= atomicSwapSecretEncryptedFlag
+	}
+	binary.LittleEndian.PutUint64(v[37:], uint64(s.Created.Unix()))
+	v[45] = uint8(len(s.Secret))
+	v = append(v, s.Secret...)
+	v = append(v, s.Contract...)
+	return v
+}
+
+func readAtomicSwap(k, v []byte, s *AtomicSwap) error {
+	if len(v) < 46 || len(v) < 46+int(v[45]) {
+		str := fmt.Sprintf("%s: short read for atomic swap %x",
+			atomicSwapsBucketName, k)
+		return apperrors.E{ErrorCode: apperrors.ErrData, Description: str, Err: nil}
+	}
+	copy(s.ContractTx[:], v)
+	s.ContractOut = binary.LittleEndian.Uint32(v[32:])
+	s.Initiator = v[36]&atomicSwapInitiatorFlag != 0
+	s.SecretEncrypted = v[36]&atomicSwapSecretEncryptedFlag != 0
+	s.Created = time.Unix(int64(binary.LittleEndian.Uint64(v[37:])), 0)
+	secretEnd := 46 + int(v[45])
+	s.Secret = nil
+	if secretEnd != 46 {
+		s.Secret = append([]byte(nil), v[46:secretEnd]...)
+	}
+	s.Contract = append([]byte(nil), v[secretEnd:]...)
+	return nil
+}
+
+// FetchAtomicSwap returns the atomic swap with a contract hash160, or nil if
+// no such swap exists.
+func (m *Manager) FetchAtomicSwap(ns walletdb.ReadBucket, hash160 []byte) (*AtomicSwap, error) {
+	v := ns.NestedReadBucket(atomicSwapsBucketName).Get(hash160)
+	if v == nil {
+		return nil, nil
+	}
+	s := new(AtomicSwap)
+	err := readAtomicSwap(hash160, v, s)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ForEachAtomicSwap calls fn with every atomic swap, in order of contract
+// hash160.
+func (m *Manager) ForEachAtomicSwap(ns walletdb.ReadBucket, fn func(*AtomicSwap) error) error {
+	return ns.NestedReadBucket(atomicSwapsBucketName).ForEach(func(k, v []byte) error {
+		var s AtomicSwap
+		err := readAtomicSwap(k, v, &s)
+		if err != nil {
+			return err
+		}
+		return fn(&s)
+	})
+}
+
+// PutAtomicSwap records an atomic swap keyed by the hash160 of its contract,
+// replacing any swap with the same contract.
+func (m *Manager) PutAtomicSwap(ns walletdb.ReadWriteBucket, hash160 []byte, s *AtomicSwap) error {
+	if len(s.Secret) > 0xff {
+		const str = "atomic swap secret is too long"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	b := ns.NestedReadWriteBucket(atomicSwapsBucketName)
+	err := b.Put(hash160, valueAtomicSwap(s))
+	if err != nil {
+		const str = "failed to store atomic swap"
+		return apperrors.E{ErrorCode: apperrors.ErrDatabase, Description: str, Err: err}
+	}
+	return nil
+}
+
+// DeleteAtomicSwap removes the atomic swap with a contract hash160.
+func (m *Manager) DeleteAtomicSwap(ns walletdb.ReadWriteBucket, hash160 []byte) error {
+	b := ns.NestedReadWriteBucket(atomicSwapsBucketName)
+	err := b.Delete(hash160)
+	if err != nil {
+		const str = "failed to delete atomic swap"
+		return apperrors.E{ErrorCode: apperrors.ErrDatabase, Description: str, Err: err}
+	}
+	return nil
+}
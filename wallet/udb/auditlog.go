@@ -0,0 +1,235 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// AuditEvent describes the kind of a balance-affecting event recorded in the
+// audit log.
+type AuditEvent uint8
+
+// Audit log events.
+const (
+	// AuditCredit records outputs of a transaction credited to the wallet.
+	AuditCredit AuditEvent = iota
+
+	// AuditDebit records wallet outputs spent by a transaction.
+	AuditDebit
+
+	// AuditVoteReward records outputs of a vote credited to the wallet.
+	AuditVoteReward
+
+	// AuditRevocation records outputs of a revocation credited to the
+	// wallet.
+	AuditRevocation
+
+	// AuditRollback records the removal of blocks, and the transactions
+	// mined in them, from the main chain.
+	AuditRollback
+
+	// AuditAbandon records the removal of an unmined transaction which
+	// was abandoned or replaced.
+	AuditAbandon
+
+	// AuditPrune records the removal of an expired or invalid unmined
+	// transaction, or of a mined transaction by pruning the transaction
+	// history.
+	AuditPrune
+)
+
+// String returns the name of the event as used by the getauditlog RPC.
+func (e AuditEvent) String() string {
+	switch e {
+	case AuditCredit:
+		return "credit"
+	case AuditDebit:
+		return "debit"
+	case AuditVoteReward:
+		return "votereward"
+	case AuditRevocation:
+		return "revocation"
+	case AuditRollback:
+		return "rollback"
+	case AuditAbandon:
+		return "abandon"
+	case AuditPrune:
+		return "prune"
+	default:
+		return "unknown"
+	}
+}
+
+// AuditEntry is a single balance-affecting event of the audit log.
+type AuditEntry struct {
+	// Seq is the position of the entry in the log, assigned when the entry
+	// is appended.
+	Seq uint64
+
+	Time  time.Time
+	Event AuditEvent
+
+	// TxHash and Amount are the transaction and the total amount credited
+	// or debited by the event.  Both are zero for rollbacks.  Abandons and
+	// prunes record the net amount of the removed transaction's audited
+	// credits and debits, which is negative when it debited more than it
+	// credited.
+	TxHash chainhash.Hash
+	Amount hcutil.Amount
+
+	// BlockHash and BlockHeight identify the block the transaction is
+	// mined in, with a zero hash and height -1 for unmined transactions.
+	// Rollbacks record the first removed height, and history prunes the
+	// height the history was pruned at.
+	BlockHash   chainhash.Hash
+	BlockHeight int32
+
+	// Trigger names the notification or wallet operation which caused the
+	// event.
+	Trigger string
+}
+
+// The audit log bucket records entries keyed by their sequence number:
+//
+//   [0:8]   Sequence number (8 bytes)
+//
+// The value is serialized as such:
+//
+//   [0:8]   Time (8 bytes)
+//   [8]     Event (1 byte)
+//   [9:41]  Transaction hash (32 bytes)
+//   [41:49] Amount (8 bytes)
+//   [49:81] Block hash (32 bytes)
+//   [81:85] Block height (4 bytes)
+//   [85:]   Trigger
+//
+// Entries are never modified or removed once appended.
+//
+// The audited transactions bucket indexes the transactions with credit and
+// debit entries in the log, keyed by transaction hash, so each transaction is
+// audited once regardless of how many times it is recorded, e.g. as unmined
+// and then mined.  The value is serialized as such:
+//
+//   [0:8]   Total amount of the credit entries (8 bytes)
+//   [8:16]  Total amount of the debit entries (8 bytes)
+//
+// Abandon and prune entries remove the transaction from the index.
+
+func keyAuditEntry(seq uint64) []byte {
+	k := make([]byte, 8)
+	byteOrder.PutUint64(k, seq)
+	return k
+}
+
+func valueAuditEntry(e *AuditEntry) []byte {
+	v := make([]byte, 85+len(e.Trigger))
+	byteOrder.PutUint64(v, uint64(e.Time.Unix()))
+	v[8] = byte(e.Event)
+	copy(v[9:41], e.TxHash[:])
+	byteOrder.PutUint64(v[41:49], uint64(e.Amount))
+	copy(v[49:81], e.BlockHash[:])
+	byteOrder.PutUint32(v[81:85], uint32(e.BlockHeight))
+	copy(v[85:], e.Trigger)
+	return v
+}
+
+func readRawAuditEntry(k, v []byte, e *AuditEntry) error {
+	if len(k) < 8 || len(v) < 85 {
+		str := fmt.Sprintf("%s: short read for audit log entry",
+			bucketAuditLog)
+		return storeError(apperrors.ErrData, str, nil)
+	}
+	e.Seq = byteOrder.Uint64(k)
+	e.Time = time.Unix(int64(byteOrder.Uint64(v)), 0)
+	e.Event = AuditEvent(v[8])
+	copy(e.TxHash[:], v[9:41])
+	e.Amount = hcutil.Amount(byteOrder.Uint64(v[41:49]))
+	copy(e.BlockHash[:], v[49:81])
+	e.BlockHeight = int32(byteOrder.Uint32(v[81:85]))
+	e.Trigger = string(v[85:])
+	return nil
+}
+
+// indexAuditEntry updates the audited transactions index for an entry.
+func indexAuditEntry(ns walletdb.ReadWriteBucket, e *AuditEntry) error {
+	b := ns.NestedReadWriteBucket(bucketAuditedTxs)
+	var err error
+	switch e.Event {
+	case AuditCredit, AuditDebit, AuditVoteReward, AuditRevocation:
+		v := make([]byte, 16)
+		copy(v, b.Get(e.TxHash[:]))
+		offset := 0
+		if e.Event == AuditDebit {
+			offset = 8
+		}
+		amount := int64(byteOrder.Uint64(v[offset:])) + int64(e.Amount)
+		byteOrder.PutUint64(v[offset:], uint64(amount))
+		err = b.Put(e.TxHash[:], v)
+	case AuditAbandon, AuditPrune:
+		err = b.Delete(e.TxHash[:])
+	}
+	if err != nil {
+		str := "failed to index audited transaction"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// AppendAuditEntry appends an entry to the audit log, assigning its sequence
+// number, and updates the audited transactions index.
+func (s *Store) AppendAuditEntry(ns walletdb.ReadWriteBucket, e *AuditEntry) error {
+	b := ns.NestedReadWriteBucket(bucketAuditLog)
+	e.Seq = 0
+	if k, _ := b.ReadCursor().Last(); len(k) == 8 {
+		e.Seq = byteOrder.Uint64(k) + 1
+	}
+	err := b.Put(keyAuditEntry(e.Seq), valueAuditEntry(e))
+	if err != nil {
+		str := "failed to append audit log entry"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return indexAuditEntry(ns, e)
+}
+
+// AuditedTx returns the total amounts of the credit and debit entries of a
+// transaction in the audit log, and whether the transaction is audited.
+// Transactions are no longer audited after they are abandoned or pruned.
+func (s *Store) AuditedTx(ns walletdb.ReadBucket, txHash *chainhash.Hash) (credited, debited hcutil.Amount, audited bool, err error) {
+	v := ns.NestedReadBucket(bucketAuditedTxs).Get(txHash[:])
+	if v == nil {
+		return 0, 0, false, nil
+	}
+	if len(v) != 16 {
+		str := fmt.Sprintf("%s: invalid value length for audited "+
+			"transaction %v", bucketAuditedTxs, txHash)
+		return 0, 0, false, storeError(apperrors.ErrData, str, nil)
+	}
+	credited = hcutil.Amount(byteOrder.Uint64(v))
+	debited = hcutil.Amount(byteOrder.Uint64(v[8:]))
+	return credited, debited, true, nil
+}
+
+// AuditLog returns at most count audit log entries, in order, beginning with
+// the entry with sequence number from.
+func (s *Store) AuditLog(ns walletdb.ReadBucket, from uint64, count int) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	c := ns.NestedReadBucket(bucketAuditLog).ReadCursor()
+	for k, v := c.Seek(keyAuditEntry(from)); k != nil && len(entries) < count; k, v = c.Next() {
+		var e AuditEntry
+		err := readRawAuditEntry(k, v, &e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
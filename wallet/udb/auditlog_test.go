@@ -0,0 +1,80 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestAuditedTxs(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := chainhash.Hash{1}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		checkAudited := func(desc string, credited, debited hcutil.Amount, audited bool) {
+			c, d, ok, err := s.AuditedTx(ns, &hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c != credited || d != debited || ok != audited {
+				t.Errorf("%s: audited %v (credited %v, debited %v), "+
+					"want %v (credited %v, debited %v)", desc, ok, c,
+					d, audited, credited, debited)
+			}
+		}
+		checkAudited("unaudited", 0, 0, false)
+
+		for _, e := range []AuditEntry{
+			{Event: AuditCredit, Amount: 5},
+			{Event: AuditDebit, Amount: 8},
+			{Event: AuditRollback},
+		} {
+			e.Time = time.Now()
+			if e.Event != AuditRollback {
+				e.TxHash = hash
+			}
+			err := s.AppendAuditEntry(ns, &e)
+			if err != nil {
+				return err
+			}
+		}
+		checkAudited("credit and debit", 5, 8, true)
+
+		err := s.AppendAuditEntry(ns, &AuditEntry{
+			Time:   time.Now(),
+			Event:  AuditAbandon,
+			TxHash: hash,
+			Amount: -3,
+		})
+		if err != nil {
+			return err
+		}
+		checkAudited("abandoned", 0, 0, false)
+
+		entries, err := s.AuditLog(ns, 0, 10)
+		if err != nil {
+			return err
+		}
+		if len(entries) != 4 || entries[3].Event != AuditAbandon ||
+			entries[3].Amount != -3 {
+			t.Errorf("audit log %+v does not end with the abandon entry",
+				entries)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
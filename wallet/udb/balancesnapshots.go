@@ -0,0 +1,172 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// BalanceSnapshot records the balances of every account holding mined
+// outputs at a main chain block.  Balances of accounts without mined outputs
+// are omitted, and the Unconfirmed field of each balance is always zero.
+type BalanceSnapshot struct {
+	Height    int32
+	BlockHash chainhash.Hash
+	Balances  []Balances
+}
+
+// The balance snapshots bucket records snapshots keyed by the height of their
+// block:
+//
+//   [0:4]   Block height (4 bytes)
+//
+// The value is serialized as such:
+//
+//   [0:32]  Block hash (32 bytes)
+//   [32:]   Account balances (52 bytes each)
+//
+// Each account balance is serialized as such:
+//
+//   [0:4]   Account (4 bytes)
+//   [4:12]  Immature coinbase rewards (8 bytes)
+//   [12:20] Immature stake generation (8 bytes)
+//   [20:28] Locked by tickets (8 bytes)
+//   [28:36] Spendable (8 bytes)
+//   [36:44] Total (8 bytes)
+//   [44:52] Voting authority (8 bytes)
+
+const balanceSnapshotEntrySize = 52
+
+func keyBalanceSnapshot(height int32) []byte {
+	k := make([]byte, 4)
+	byteOrder.PutUint32(k, uint32(height))
+	return k
+}
+
+func valueBalanceSnapshot(s *BalanceSnapshot) []byte {
+	v := make([]byte, 32+balanceSnapshotEntrySize*len(s.Balances))
+	copy(v, s.BlockHash[:])
+	off := 32
+	for i := range s.Balances {
+		b := &s.Balances[i]
+		byteOrder.PutUint32(v[off:], b.Account)
+		byteOrder.PutUint64(v[off+4:], uint64(b.ImmatureCoinbaseRewards))
+		byteOrder.PutUint64(v[off+12:], uint64(b.ImmatureStakeGeneration))
+		byteOrder.PutUint64(v[off+20:], uint64(b.LockedByTickets))
+		byteOrder.PutUint64(v[off+28:], uint64(b.Spendable))
+		byteOrder.PutUint64(v[off+36:], uint64(b.Total))
+		byteOrder.PutUint64(v[off+44:], uint64(b.VotingAuthority))
+		off += balanceSnapshotEntrySize
+	}
+	return v
+}
+
+func readRawBalanceSnapshot(k, v []byte, s *BalanceSnapshot) error {
+	if len(k) < 4 || len(v) < 32 || (len(v)-32)%balanceSnapshotEntrySize != 0 {
+		str := fmt.Sprintf("%s: short read for balance snapshot",
+			bucketBalanceSnapshots)
+		return storeError(apperrors.ErrData, str, nil)
+	}
+	s.Height = int32(byteOrder.Uint32(k))
+	copy(s.BlockHash[:], v)
+	s.Balances = make([]Balances, (len(v)-32)/balanceSnapshotEntrySize)
+	off := 32
+	for i := range s.Balances {
+		b := &s.Balances[i]
+		b.Account = byteOrder.Uint32(v[off:])
+		b.ImmatureCoinbaseRewards = hcutil.Amount(byteOrder.Uint64(v[off+4:]))
+		b.ImmatureStakeGeneration = hcutil.Amount(byteOrder.Uint64(v[off+12:]))
+		b.LockedByTickets = hcutil.Amount(byteOrder.Uint64(v[off+20:]))
+		b.Spendable = hcutil.Amount(byteOrder.Uint64(v[off+28:]))
+		b.Total = hcutil.Amount(byteOrder.Uint64(v[off+36:]))
+		b.VotingAuthority = hcutil.Amount(byteOrder.Uint64(v[off+44:]))
+		off += balanceSnapshotEntrySize
+	}
+	return nil
+}
+
+// PutBalanceSnapshot records a snapshot of the mined balances of every account
+// at the current main chain tip.  The snapshot replaces any previous snapshot
+// at the same height.
+func (s *Store) PutBalanceSnapshot(ns walletdb.ReadWriteBucket, addrmgrNs walletdb.ReadBucket) (*BalanceSnapshot, error) {
+	tipHash, tipHeight := s.MainChainTip(ns)
+	balances, err := s.AccountBalances(ns, addrmgrNs, 1)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &BalanceSnapshot{
+		Height:    tipHeight,
+		BlockHash: tipHash,
+		Balances:  make([]Balances, 0, len(balances)),
+	}
+	for _, b := range balances {
+		bal := *b
+		bal.Total -= bal.Unconfirmed
+		bal.Unconfirmed = 0
+		snapshot.Balances = append(snapshot.Balances, bal)
+	}
+
+	err = ns.NestedReadWriteBucket(bucketBalanceSnapshots).Put(
+		keyBalanceSnapshot(tipHeight), valueBalanceSnapshot(snapshot))
+	if err != nil {
+		str := "failed to put balance snapshot"
+		return nil, storeError(apperrors.ErrDatabase, str, err)
+	}
+	return snapshot, nil
+}
+
+// BalanceSnapshotAt returns the latest balance snapshot of a block at or below
+// height.  ErrValueNoExists is returned when there is no such snapshot.
+func (s *Store) BalanceSnapshotAt(ns walletdb.ReadBucket, height int32) (*BalanceSnapshot, error) {
+	if height < 0 {
+		const str = "no balance snapshot at or below a negative height"
+		return nil, storeError(apperrors.ErrValueNoExists, str, nil)
+	}
+	c := ns.NestedReadBucket(bucketBalanceSnapshots).ReadCursor()
+	k, v := c.Seek(keyBalanceSnapshot(height))
+	if k == nil || int32(byteOrder.Uint32(k)) != height {
+		// The cursor is positioned at the first snapshot above height, or
+		// past the last snapshot.
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+	}
+	if k == nil {
+		str := fmt.Sprintf("no balance snapshot at or below height %d", height)
+		return nil, storeError(apperrors.ErrValueNoExists, str, nil)
+	}
+	snapshot := new(BalanceSnapshot)
+	err := readRawBalanceSnapshot(k, v, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// RollbackBalanceSnapshots removes the balance snapshots of blocks at or above
+// height, which are no longer in the main chain after a reorganization.
+func (s *Store) RollbackBalanceSnapshots(ns walletdb.ReadWriteBucket, height int32) error {
+	b := ns.NestedReadWriteBucket(bucketBalanceSnapshots)
+	var keys [][]byte
+	c := b.ReadCursor()
+	for k, _ := c.Seek(keyBalanceSnapshot(height)); k != nil; k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		err := b.Delete(k)
+		if err != nil {
+			str := "failed to delete balance snapshot"
+			return storeError(apperrors.ErrDatabase, str, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestBalanceSnapshots(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := []BalanceSnapshot{
+		{Height: 10, BlockHash: chainhash.Hash{10}, Balances: []Balances{
+			{Account: 0, Spendable: 5e8, Total: 7e8, LockedByTickets: 2e8,
+				VotingAuthority: 2e8},
+			{Account: 3, ImmatureCoinbaseRewards: 1e8,
+				ImmatureStakeGeneration: 3e8, Total: 4e8},
+		}},
+		{Height: 20, BlockHash: chainhash.Hash{20}, Balances: []Balances{}},
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		b := ns.NestedReadWriteBucket(bucketBalanceSnapshots)
+		for i := range snapshots {
+			err := b.Put(keyBalanceSnapshot(snapshots[i].Height),
+				valueBalanceSnapshot(&snapshots[i]))
+			if err != nil {
+				return err
+			}
+		}
+
+		tests := []struct {
+			height int32
+			want   *BalanceSnapshot
+		}{
+			{-1, nil},
+			{5, nil},
+			{10, &snapshots[0]},
+			{15, &snapshots[0]},
+			{20, &snapshots[1]},
+			{25, &snapshots[1]},
+		}
+		for _, test := range tests {
+			snapshot, err := s.BalanceSnapshotAt(ns, test.height)
+			if test.want == nil {
+				if !apperrors.IsError(err, apperrors.ErrValueNoExists) {
+					t.Errorf("height %d: snapshot %+v with error %v, "+
+						"want ErrValueNoExists", test.height, snapshot,
+						err)
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(snapshot, test.want) {
+				t.Errorf("height %d: snapshot %+v, want %+v", test.height,
+					snapshot, test.want)
+			}
+		}
+
+		// Rolling back removes snapshots at and above the height.
+		err = s.RollbackBalanceSnapshots(ns, 20)
+		if err != nil {
+			return err
+		}
+		snapshot, err := s.BalanceSnapshotAt(ns, 25)
+		if err != nil {
+			return err
+		}
+		if snapshot.Height != 10 {
+			t.Errorf("snapshot at height %d after rollback, want 10",
+				snapshot.Height)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
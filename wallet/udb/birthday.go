@@ -0,0 +1,118 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// Birthday describes the earliest block which may contain transactions
+// relevant to the wallet.  It is recorded either as a block height, or as a
+// time which is resolved to a block height once the main chain headers
+// through that time are known.
+type Birthday struct {
+	// Time is the creation time of the wallet or its seed.  It is the zero
+	// time for birthdays which were recorded as a block height.
+	Time time.Time
+
+	// Height is the birthday block height, or -1 when the birthday has not
+	// yet been resolved from Time.
+	Height int32
+}
+
+// Resolved returns whether the birthday block height is known.
+func (b *Birthday) Resolved() bool {
+	return b.Height >= 0
+}
+
+// The root bucket's birthday k/v pair records the wallet birthday.  The key is
+// absent when the wallet has no birthday and must be synced from the genesis
+// block.  The value is serialized as such:
+//
+//   [0:8]  Unix time, or 0 when recorded as a height (8 bytes)
+//   [8:12] Block height, or 0xffffffff when unresolved (4 bytes)
+
+func valueBirthday(b *Birthday) []byte {
+	v := make([]byte, 12)
+	if !b.Time.IsZero() {
+		byteOrder.PutUint64(v[0:8], uint64(b.Time.Unix()))
+	}
+	byteOrder.PutUint32(v[8:12], uint32(b.Height))
+	return v
+}
+
+func fetchBirthday(ns walletdb.ReadBucket) (*Birthday, error) {
+	v := ns.Get(rootBirthday)
+	if v == nil {
+		return nil, nil
+	}
+	if len(v) != 12 {
+		str := fmt.Sprintf("birthday: short read (expected 12 bytes, "+
+			"read %v)", len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	b := &Birthday{Height: int32(byteOrder.Uint32(v[8:12]))}
+	if unix := int64(byteOrder.Uint64(v[0:8])); unix != 0 {
+		b.Time = time.Unix(unix, 0)
+	}
+	return b, nil
+}
+
+func putBirthday(ns walletdb.ReadWriteBucket, b *Birthday) error {
+	err := ns.Put(rootBirthday, valueBirthday(b))
+	if err != nil {
+		str := "failed to put birthday"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// Birthday returns the wallet birthday, or nil if the wallet has none.
+func (s *Store) Birthday(ns walletdb.ReadBucket) (*Birthday, error) {
+	return fetchBirthday(ns)
+}
+
+// SetBirthday records the wallet birthday.
+func (s *Store) SetBirthday(ns walletdb.ReadWriteBucket, b *Birthday) error {
+	if b.Time.IsZero() && !b.Resolved() {
+		str := "birthday requires a time or block height"
+		return storeError(apperrors.ErrInput, str, nil)
+	}
+	return putBirthday(ns, b)
+}
+
+// FirstMainChainBlockAfter returns the height of the first main chain block
+// with a timestamp at or after t.  Block timestamps are only roughly ordered,
+// so callers should allow a margin before t.  If no block with a later
+// timestamp is recorded, the height after the main chain tip is returned.
+func (s *Store) FirstMainChainBlockAfter(ns walletdb.ReadBucket, t time.Time) (int32, error) {
+	_, tipHeight := s.MainChainTip(ns)
+	unix := t.Unix()
+
+	// Binary search for the first block in [0, tipHeight+1) with a timestamp
+	// at or after t.
+	lo, hi := int32(0), tipHeight+1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		hash, err := s.GetMainChainBlockHashForHeight(ns, mid)
+		if err != nil {
+			return 0, err
+		}
+		header, err := fetchRawBlockHeader(ns, keyBlockHeader(&hash))
+		if err != nil {
+			return 0, err
+		}
+		if ExtractBlockHeaderTime(header) >= unix {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// FirstExtraExternalBranch is the child number of the first additional
+// external branch of an account.  BIP0044 reserves branches 0 and 1 for the
+// external and internal branches, and the branches up to here are reserved
+// for keys with fixed purposes, such as the account proof key.  Additional
+// external branches, which segregate addresses given out for different
+// purposes, are numbered upwards from here and end before InvoiceBranch.
+//
+// Additional branches are only recorded in the wallet database, and are not
+// discovered when restoring a wallet from its seed.
+const FirstExtraExternalBranch uint32 = 1 << 16
+
+// BranchProperties contains properties associated with an additional external
+// branch of an account.
+type BranchProperties struct {
+	AccountNumber     uint32
+	Branch            uint32
+	BranchName        string
+	LastUsedIndex     uint32
+	LastReturnedIndex uint32
+}
+
+// The external branches bucket records the additional external branches of
+// accounts keyed by account and branch:
+//
+//   [0:4]   Account (4 bytes)
+//   [4:8]   Branch (4 bytes)
+//
+// The value is serialized as such:
+//
+//   [0:4]   Last used child index (4 bytes)
+//   [4:8]   Last returned child index (4 bytes)
+//   [8:]    Branch name
+
+func keyExternalBranch(account, branch uint32) []byte {
+	k := make([]byte, 8)
+	binary.LittleEndian.PutUint32(k, account)
+	binary.LittleEndian.PutUint32(k[4:], branch)
+	return k
+}
+
+func valueExternalBranch(props *BranchProperties) []byte {
+	v := make([]byte, 8+len(props.BranchName))
+	binary.LittleEndian.PutUint32(v, props.LastUsedIndex)
+	binary.LittleEndian.PutUint32(v[4:], props.LastReturnedIndex)
+	copy(v[8:], props.BranchName)
+	return v
+}
+
+func readExternalBranch(k, v []byte, props *BranchProperties) error {
+	if len(k) != 8 || len(v) < 8 {
+		str := fmt.Sprintf("%s: short read for external branch",
+			externalBranchesBucketName)
+		return apperrors.E{ErrorCode: apperrors.ErrData, Description: str, Err: nil}
+	}
+	props.AccountNumber = binary.LittleEndian.Uint32(k)
+	props.Branch = binary.LittleEndian.Uint32(k[4:])
+	props.LastUsedIndex = binary.LittleEndian.Uint32(v)
+	props.LastReturnedIndex = binary.LittleEndian.Uint32(v[4:])
+	props.BranchName = string(v[8:])
+	return nil
+}
+
+func fetchExternalBranch(ns walletdb.ReadBucket, account, branch uint32) (*BranchProperties, error) {
+	k := keyExternalBranch(account, branch)
+	v := ns.NestedReadBucket(externalBranchesBucketName).Get(k)
+	if v == nil {
+		str := fmt.Sprintf("account %d has no external branch %d",
+			account, branch)
+		return nil, apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
+	}
+	props := new(BranchProperties)
+	err := readExternalBranch(k, v, props)
+	return props, err
+}
+
+func putExternalBranch(ns walletdb.ReadWriteBucket, props *BranchProperties) error {
+	b := ns.NestedReadWriteBucket(externalBranchesBucketName)
+	err := b.Put(keyExternalBranch(props.AccountNumber, props.Branch),
+		valueExternalBranch(props))
+	if err != nil {
+		const str = "failed to store external branch"
+		return apperrors.E{ErrorCode: apperrors.ErrDatabase, Description: str, Err: err}
+	}
+	return nil
+}
+
+func existsExternalBranch(ns walletdb.ReadBucket, account, branch uint32) bool {
+	b := ns.NestedReadBucket(externalBranchesBucketName)
+	return b.Get(keyExternalBranch(account, branch)) != nil
+}
+
+// ForEachExternalBranch calls fn with the properties of each additional
+// external branch of an account, in order of branch number.
+func (m *Manager) ForEachExternalBranch(ns walletdb.ReadBucket, account uint32,
+	fn func(*BranchProperties) error) error {
+
+	c := ns.NestedReadBucket(externalBranchesBucketName).ReadCursor()
+	prefix := uint32ToBytes(account)
+	for k, v := c.Seek(prefix); k != nil && len(k) == 8 &&
+		binary.LittleEndian.Uint32(k) == account; k, v = c.Next() {
+
+		var props BranchProperties
+		err := readExternalBranch(k, v, &props)
+		if err != nil {
+			return err
+		}
+		err = fn(&props)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExternalBranchProperties returns the properties of an additional external
+// branch of an account.
+func (m *Manager) ExternalBranchProperties(ns walletdb.ReadBucket, account, branch uint32) (*BranchProperties, error) {
+	return fetchExternalBranch(ns, account, branch)
+}
+
+// LookupExternalBranch returns the branch number of the additional external
+// branch of an account with the given name.
+func (m *Manager) LookupExternalBranch(ns walletdb.ReadBucket, account uint32, name string) (uint32, error) {
+	var branch uint32
+	found := false
+	err := m.ForEachExternalBranch(ns, account, func(props *BranchProperties) error {
+		if props.BranchName == name {
+			branch = props.Branch
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		str := fmt.Sprintf("account %d has no external branch named %q",
+			account, name)
+		return 0, apperrors.E{ErrorCode: apperrors.ErrBranch, Description: str, Err: nil}
+	}
+	return branch, nil
+}
+
+// NewExternalBranch records a new additional external branch of a BIP0044
+// account with a name unique to the account, and returns the branch number.
+// Branches are numbered in order of creation beginning with
+// FirstExtraExternalBranch.  Addresses of the branch must be derived and
+// recorded using SyncAccountToAddrIndex before they are recognized as wallet
+// addresses.
+//
+// Additional branches are not supported by imported and bliss accounts.
+func (m *Manager) NewExternalBranch(ns walletdb.ReadWriteBucket, account uint32, name string) (uint32, error) {
+	if account == ImportedAddrAccount {
+		const str = "cannot create branches of the imported account"
+		return 0, apperrors.E{ErrorCode: apperrors.ErrInvalidAccount, Description: str, Err: nil}
+	}
+	if name == "" {
+		const str = "branch names may not be empty"
+		return 0, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	acctInfo, err := m.loadAccountInfo(ns, account)
+	if err != nil {
+		return 0, err
+	}
+	if acctInfo.acctType != AcctypeEc {
+		const str = "additional external branches are only supported by " +
+			"ECDSA accounts"
+		return 0, apperrors.E{ErrorCode: apperrors.ErrInvalidAccount, Description: str, Err: nil}
+	}
+
+	branch := FirstExtraExternalBranch
+	err = m.ForEachExternalBranch(ns, account, func(props *BranchProperties) error {
+		if props.BranchName == name {
+			str := fmt.Sprintf("account %d already has an external "+
+				"branch named %q", account, name)
+			return apperrors.E{ErrorCode: apperrors.ErrDuplicate, Description: str, Err: nil}
+		}
+		branch = props.Branch + 1
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if branch >= InvoiceBranch {
+		const str = "no more branches can be derived for the account"
+		return 0, apperrors.E{ErrorCode: apperrors.ErrExhaustedAccount, Description: str, Err: nil}
+	}
+
+	err = putExternalBranch(ns, &BranchProperties{
+		AccountNumber:     account,
+		Branch:            branch,
+		BranchName:        name,
+		LastUsedIndex:     ^uint32(0),
+		LastReturnedIndex: ^uint32(0),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return branch, nil
+}
+
+// markExternalBranchChild records the last used or last returned child of an
+// additional external branch.  As with the external and internal branches,
+// the last used child never decreases and the last returned child is never
+// less than the last used child.
+func markExternalBranchChild(ns walletdb.ReadWriteBucket, account, branch, child uint32, used bool) error {
+	props, err := fetchExternalBranch(ns, account, branch)
+	if err != nil {
+		return err
+	}
+	if used {
+		if child+1 < props.LastUsedIndex+1 {
+			// More recent addresses have already been marked used,
+			// nothing to update.
+			return nil
+		}
+		props.LastUsedIndex = child
+		props.LastReturnedIndex = maxUint32(child+1, props.LastReturnedIndex+1) - 1
+	} else {
+		props.LastReturnedIndex = maxUint32(props.LastUsedIndex+1, child+1) - 1
+	}
+	return putExternalBranch(ns, props)
+}
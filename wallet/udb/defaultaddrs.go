@@ -0,0 +1,50 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// The default addresses bucket records the address set as the default address
+// of an account, which is used as the change and sending address when
+// transactions from the account do not specify one.  Keys are serialized
+// account numbers (4 bytes) and values are encoded addresses.  Accounts with
+// no recorded address default to the first address of their external branch.
+
+func keyDefaultAddress(account uint32) []byte {
+	k := make([]byte, 4)
+	byteOrder.PutUint32(k, account)
+	return k
+}
+
+// AccountDefaultAddress returns the encoded address set as the default address
+// of an account, or the empty string if no default address was set.
+func (m *Manager) AccountDefaultAddress(ns walletdb.ReadBucket, account uint32) string {
+	v := ns.NestedReadBucket(defaultAddrsBucketName).Get(keyDefaultAddress(account))
+	return string(v)
+}
+
+// PutAccountDefaultAddress sets the default address of an account.  An empty
+// address removes the setting, reverting the account to its first address.
+// Callers must check that the address belongs to the account.
+func (m *Manager) PutAccountDefaultAddress(ns walletdb.ReadWriteBucket, account uint32, addr string) error {
+	b := ns.NestedReadWriteBucket(defaultAddrsBucketName)
+	var err error
+	if addr == "" {
+		err = b.Delete(keyDefaultAddress(account))
+	} else {
+		err = b.Put(keyDefaultAddress(account), []byte(addr))
+	}
+	if err != nil {
+		str := fmt.Sprintf("failed to put default address of account %d",
+			account)
+		return managerError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
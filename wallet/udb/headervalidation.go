@@ -0,0 +1,98 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/blockchain"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// checkHeaderHash ensures the block hash recorded with a header is the hash of
+// the serialized header, returning the deserialized header.
+func checkHeaderHash(header *BlockHeaderData) (*wire.BlockHeader, error) {
+	var h wire.BlockHeader
+	err := h.Deserialize(bytes.NewReader(header.SerializedHeader[:]))
+	if err != nil {
+		const str = "failed to deserialize block header"
+		return nil, storeError(apperrors.ErrInput, str, err)
+	}
+	if hash := h.BlockHash(); hash != header.BlockHash {
+		str := fmt.Sprintf("header of block %v hashes to %v",
+			&header.BlockHash, &hash)
+		return nil, storeError(apperrors.ErrChainFault, str, nil)
+	}
+	return &h, nil
+}
+
+// CheckProofOfWork validates the proof of work of a block header received from
+// the consensus RPC server.  The target difficulty claimed by the header must
+// be positive and within the proof of work limit of the chain, and the block
+// hash must not exceed the target.  Headers which fail validation are
+// described by an error with code ErrChainFault.
+func (s *Store) CheckProofOfWork(header *BlockHeaderData) error {
+	h, err := checkHeaderHash(header)
+	if err != nil {
+		return err
+	}
+
+	target := blockchain.CompactToBig(h.Bits)
+	if target.Sign() <= 0 || target.Cmp(s.chainParams.PowLimit) > 0 {
+		str := fmt.Sprintf("target difficulty %064x of block %v is "+
+			"outside the proof of work limit", target, &header.BlockHash)
+		return storeError(apperrors.ErrChainFault, str, nil)
+	}
+	if blockchain.HashToBig(&header.BlockHash).Cmp(target) > 0 {
+		str := fmt.Sprintf("hash of block %v is higher than its target "+
+			"difficulty %064x", &header.BlockHash, target)
+		return storeError(apperrors.ErrChainFault, str, nil)
+	}
+	return nil
+}
+
+// checkHeaderLinkage ensures headers to be inserted into the main chain form a
+// chain: each header must hash to its recorded block hash and be the child of
+// the previous header, or of its recorded parent for the first header, with a
+// height one more than its parent.  Headers which do not link are described by
+// an error with code ErrChainFault.
+func checkHeaderLinkage(ns walletdb.ReadBucket, headers []BlockHeaderData) error {
+	for i := range headers {
+		h, err := checkHeaderHash(&headers[i])
+		if err != nil {
+			return err
+		}
+
+		var parentHeight int32
+		if i == 0 {
+			parent := existsBlockHeader(ns, h.PrevBlock[:])
+			if parent == nil {
+				// The parent of already recorded headers may be
+				// missing, and is checked by the caller otherwise.
+				continue
+			}
+			parentHeight = extractBlockHeaderHeight(parent)
+		} else {
+			prev := &headers[i-1]
+			if h.PrevBlock != prev.BlockHash {
+				str := fmt.Sprintf("block %v does not connect to "+
+					"previous header %v", &headers[i].BlockHash,
+					&prev.BlockHash)
+				return storeError(apperrors.ErrChainFault, str, nil)
+			}
+			parentHeight = prev.SerializedHeader.Height()
+		}
+		if int32(h.Height) != parentHeight+1 {
+			str := fmt.Sprintf("block %v has height %d but its parent "+
+				"has height %d", &headers[i].BlockHash, h.Height,
+				parentHeight)
+			return storeError(apperrors.ErrChainFault, str, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/blockchain"
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// solveHeader searches for a nonce for which the hash of h does or does not
+// meet its target difficulty.
+func solveHeader(h *wire.BlockHeader, meetsTarget bool) {
+	target := blockchain.CompactToBig(h.Bits)
+	for {
+		hash := h.BlockHash()
+		if (blockchain.HashToBig(&hash).Cmp(target) <= 0) == meetsTarget {
+			return
+		}
+		h.Nonce++
+	}
+}
+
+func TestCheckProofOfWork(t *testing.T) {
+	s := &Store{chainParams: &chaincfg.SimNetParams}
+	powLimitBits := chaincfg.SimNetParams.PowLimitBits
+
+	valid := &wire.BlockHeader{Bits: powLimitBits, Height: 1}
+	solveHeader(valid, true)
+	highHash := &wire.BlockHeader{Bits: powLimitBits, Height: 1}
+	solveHeader(highHash, false)
+	mismatched := makeHeaderData(valid)
+	mismatched.BlockHash = chainhash.Hash{1}
+
+	tests := []struct {
+		name   string
+		header BlockHeaderData
+		valid  bool
+	}{
+		{"valid", makeHeaderData(valid), true},
+		{"hash above target", makeHeaderData(highHash), false},
+		{"zero target", makeHeaderData(&wire.BlockHeader{Height: 1}), false},
+		{"target above limit", makeHeaderData(&wire.BlockHeader{
+			Bits: 0x2100ffff, Height: 1}), false},
+		{"mismatched hash", mismatched, false},
+	}
+	for _, test := range tests {
+		err := s.CheckProofOfWork(&test.header)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		if !apperrors.IsError(err, apperrors.ErrChainFault) {
+			t.Errorf("%s: error %v, want ErrChainFault", test.name, err)
+		}
+	}
+}
+
+func TestInsertUnlinkedHeaders(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := makeBlockGenerator()
+	block1 := g.generate(hcutil.BlockValid)
+	block2 := g.generate(hcutil.BlockValid)
+	block3 := g.generate(hcutil.BlockValid)
+
+	mismatched := makeHeaderData(block2)
+	mismatched.BlockHash = chainhash.Hash{1}
+	genesis := chaincfg.TestNet2Params.GenesisHash
+
+	tests := []struct {
+		name    string
+		headers []BlockHeaderData
+	}{
+		{"disconnected", makeHeaderDataSlice(block1, &wire.BlockHeader{
+			PrevBlock: chainhash.Hash{1}, Height: 2})},
+		{"skipped height", makeHeaderDataSlice(&wire.BlockHeader{
+			PrevBlock: *genesis, Height: 2})},
+		{"skipped height of next header", makeHeaderDataSlice(block1,
+			&wire.BlockHeader{PrevBlock: block1.BlockHash(), Height: 3})},
+		{"mismatched hash", append(makeHeaderDataSlice(block1), mismatched)},
+	}
+	for _, test := range tests {
+		err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+			addrmgrNs := tx.ReadBucket(waddrmgrBucketKey)
+			return s.InsertMainChainHeaders(ns, addrmgrNs, test.headers)
+		})
+		if !apperrors.IsError(err, apperrors.ErrChainFault) {
+			t.Errorf("%s: error %v, want ErrChainFault", test.name, err)
+		}
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		addrmgrNs := tx.ReadBucket(waddrmgrBucketKey)
+		err := s.InsertMainChainHeaders(ns, addrmgrNs,
+			makeHeaderDataSlice(block1, block2))
+		if err != nil {
+			return err
+		}
+
+		// The main chain is only extended by headers hashing to their
+		// recorded block hash.
+		d := makeHeaderData(block3)
+		d.BlockHash = chainhash.Hash{3}
+		err = s.ExtendMainChain(ns, &d)
+		if !apperrors.IsError(err, apperrors.ErrChainFault) {
+			t.Errorf("extending the main chain with a mismatched hash "+
+				"returned %v, want ErrChainFault", err)
+		}
+		d = makeHeaderData(block3)
+		return s.ExtendMainChain(ns, &d)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// MaxIdempotencyKeyLen is the maximum length of an idempotency key.
+const MaxIdempotencyKeyLen = 256
+
+// The idempotency keys bucket records the transaction sent for each
+// client-provided idempotency key, keyed by the key bytes.  The value is
+// serialized as such:
+//
+//   [0:32]  Transaction hash (32 bytes)
+//   [32:40] Time the key was recorded (8 bytes)
+//   [40:72] SHA-256 digest of the send parameters (32 bytes)
+//
+// Keys recorded before the parameters were recorded omit the digest.
+
+func checkIdempotencyKey(key string) error {
+	if len(key) == 0 || len(key) > MaxIdempotencyKeyLen {
+		str := fmt.Sprintf("idempotency key must be between 1 and %d bytes",
+			MaxIdempotencyKeyLen)
+		return storeError(apperrors.ErrInput, str, nil)
+	}
+	return nil
+}
+
+// PutIdempotencyKey records txHash as the transaction sent for key with the
+// serialized send parameters params.  An error with code ErrDuplicate is
+// returned if the key was already recorded.
+func (s *Store) PutIdempotencyKey(ns walletdb.ReadWriteBucket, key string, params []byte, txHash *chainhash.Hash) error {
+	err := checkIdempotencyKey(key)
+	if err != nil {
+		return err
+	}
+	b := ns.NestedReadWriteBucket(bucketIdempotencyKeys)
+	if b.Get([]byte(key)) != nil {
+		str := fmt.Sprintf("idempotency key %q is already recorded", key)
+		return storeError(apperrors.ErrDuplicate, str, nil)
+	}
+	v := make([]byte, 72)
+	copy(v, txHash[:])
+	byteOrder.PutUint64(v[32:40], uint64(time.Now().Unix()))
+	digest := sha256.Sum256(params)
+	copy(v[40:72], digest[:])
+	err = b.Put([]byte(key), v)
+	if err != nil {
+		str := "failed to put idempotency key"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// IdempotencyKeyTx returns the hash of the transaction recorded as sent for
+// key, or nil if the key is not recorded.  An error with code ErrInput is
+// returned if the key was recorded for different send parameters.
+func (s *Store) IdempotencyKeyTx(ns walletdb.ReadBucket, key string, params []byte) (*chainhash.Hash, error) {
+	err := checkIdempotencyKey(key)
+	if err != nil {
+		return nil, err
+	}
+	v := ns.NestedReadBucket(bucketIdempotencyKeys).Get([]byte(key))
+	if v == nil {
+		return nil, nil
+	}
+	if len(v) != 40 && len(v) != 72 {
+		str := fmt.Sprintf("%s: invalid value length for idempotency key",
+			bucketIdempotencyKeys)
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	digest := sha256.Sum256(params)
+	if len(v) == 72 && !bytes.Equal(v[40:72], digest[:]) {
+		str := fmt.Sprintf("idempotency key %q was used to send a "+
+			"transaction with different parameters", key)
+		return nil, storeError(apperrors.ErrInput, str, nil)
+	}
+	var hash chainhash.Hash
+	copy(hash[:], v)
+	return &hash, nil
+}
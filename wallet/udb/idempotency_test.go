@@ -0,0 +1,83 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestIdempotencyKeyParams(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := chainhash.Hash{1, 2, 3}
+	params := []byte(`["sendtoaddress",{"addr":1}]`)
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		err := s.PutIdempotencyKey(ns, "key", params, &hash)
+		if err != nil {
+			return err
+		}
+		// Keys recorded before the parameters were recorded are
+		// 40 bytes long.
+		legacy := make([]byte, 40)
+		copy(legacy, hash[:])
+		return ns.NestedReadWriteBucket(bucketIdempotencyKeys).Put(
+			[]byte("legacy"), legacy)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(wtxmgrBucketKey)
+
+		// Retrying with the same parameters returns the sent transaction.
+		got, err := s.IdempotencyKeyTx(ns, "key", params)
+		if err != nil {
+			return err
+		}
+		if got == nil || *got != hash {
+			t.Errorf("key with the same parameters returned %v, want %v",
+				got, &hash)
+		}
+
+		// Reusing the key with different parameters is rejected.
+		got, err = s.IdempotencyKeyTx(ns, "key", []byte(`["sendtoaddress",{"addr":2}]`))
+		if !apperrors.IsError(err, apperrors.ErrInput) {
+			t.Errorf("key with different parameters returned %v, %v; "+
+				"want ErrInput", got, err)
+		}
+
+		// Legacy keys match any parameters.
+		got, err = s.IdempotencyKeyTx(ns, "legacy", []byte("anything"))
+		if err != nil {
+			return err
+		}
+		if got == nil || *got != hash {
+			t.Errorf("legacy key returned %v, want %v", got, &hash)
+		}
+
+		// Unrecorded keys return nil.
+		got, err = s.IdempotencyKeyTx(ns, "unrecorded", params)
+		if err != nil {
+			return err
+		}
+		if got != nil {
+			t.Errorf("unrecorded key returned %v", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
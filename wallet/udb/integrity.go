@@ -0,0 +1,186 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// VerifyIntegrity checks the invariants between the buckets of the
+// transaction store and returns a description of each violation found.  The
+// following invariants are verified:
+//
+//   - Every transaction hash recorded by a block resolves to a transaction
+//     record mined in that block
+//   - Every mined credit and debit belongs to a recorded transaction
+//   - Every spent credit is spent by a debit which references the credit and
+//     debits the credit's amount
+//   - Every debit spends a credit which is marked spent by that debit
+//   - Every unspent output refers to an unspent credit
+//   - Every unmined credit belongs to a recorded unmined transaction
+//
+// Spent credits and debits at or below the height of the most recent history
+// prune may reference the debits and credits of pruned transactions, so
+// missing references at or below this height are not reported.
+//
+// A nil slice is returned when the store is consistent.  Errors are only
+// returned when the store can not be read.
+func VerifyIntegrity(ns walletdb.ReadBucket) ([]string, error) {
+	var issues []string
+	report := func(format string, args ...interface{}) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	txRecords := ns.NestedReadBucket(bucketTxRecords)
+	credits := ns.NestedReadBucket(bucketCredits)
+	debits := ns.NestedReadBucket(bucketDebits)
+	prunedHeight := fetchPrunedHeight(ns)
+
+	err := ns.NestedReadBucket(bucketBlocks).ForEach(func(k, v []byte) error {
+		var block blockRecord
+		err := readRawBlockRecord(k, v, &block)
+		if err != nil {
+			report("block record %x is malformed: %v", k, err)
+			return nil
+		}
+		for i := range block.transactions {
+			txHash := &block.transactions[i]
+			if existsRawTxRecord(ns, keyTxRecord(txHash, &block.Block)) == nil {
+				report("transaction %v recorded by block %v (height %d) "+
+					"has no transaction record", txHash, &block.Hash,
+					block.Height)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = credits.ForEach(func(k, v []byte) error {
+		if len(k) < 72 || len(v) < 9 {
+			report("credit %x is malformed", k)
+			return nil
+		}
+		txHash := extractRawCreditTxHash(k)
+		index := extractRawCreditIndex(k)
+		if txRecords.Get(extractRawCreditTxRecordKey(k)) == nil {
+			report("credit %v:%d has no transaction record", &txHash, index)
+		}
+		if v[8]&(1<<0) == 0 {
+			return nil
+		}
+		if len(v) < 81 {
+			report("spent credit %v:%d does not record its spender",
+				&txHash, index)
+			return nil
+		}
+		debitKey := extractRawCreditSpenderDebitKey(v)
+		debitValue := debits.Get(debitKey)
+		if debitValue == nil && extractRawCreditHeight(debitKey) <= prunedHeight {
+			return nil
+		}
+		switch {
+		case len(debitValue) < 80:
+			report("spent credit %v:%d is not debited by its spender",
+				&txHash, index)
+		case !bytes.Equal(extractRawDebitCreditKey(debitValue), k):
+			report("spender of credit %v:%d debits a different credit",
+				&txHash, index)
+		case extractRawDebitAmount(debitValue) != extractRawCreditAmount(v):
+			report("debit of credit %v:%d does not match the credit amount",
+				&txHash, index)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = debits.ForEach(func(k, v []byte) error {
+		if len(k) < 72 || len(v) < 80 {
+			report("debit %x is malformed", k)
+			return nil
+		}
+		var txHash chainhash.Hash
+		copy(txHash[:], extractRawDebitHash(k))
+		index := byteOrder.Uint32(k[68:72])
+		if txRecords.Get(k[:68]) == nil {
+			report("debit %v:%d has no transaction record", &txHash, index)
+		}
+		credKey := extractRawDebitCreditKey(v)
+		credValue := credits.Get(credKey)
+		if credValue == nil && extractRawCreditHeight(credKey) <= prunedHeight {
+			return nil
+		}
+		switch {
+		case len(credValue) < 9:
+			report("debit %v:%d spends a missing credit", &txHash, index)
+		case credValue[8]&(1<<0) == 0 || len(credValue) < 81:
+			report("debit %v:%d spends a credit which is not marked spent",
+				&txHash, index)
+		case !bytes.Equal(extractRawCreditSpenderDebitKey(credValue), k):
+			report("debit %v:%d spends a credit spent by another debit",
+				&txHash, index)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ns.NestedReadBucket(bucketUnspent).ForEach(func(k, v []byte) error {
+		credKey := existsRawUnspent(ns, k)
+		if credKey == nil {
+			report("unspent output %x is malformed", k)
+			return nil
+		}
+		var op chainhash.Hash
+		copy(op[:], k[:32])
+		credValue := credits.Get(credKey)
+		switch {
+		case credValue == nil:
+			report("unspent output %v:%d has no credit", &op,
+				byteOrder.Uint32(k[32:36]))
+		case len(credValue) >= 9 && credValue[8]&(1<<0) != 0:
+			report("unspent output %v:%d refers to a spent credit", &op,
+				byteOrder.Uint32(k[32:36]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ns.NestedReadBucket(bucketUnminedCredits).ForEach(func(k, v []byte) error {
+		if len(k) < 36 {
+			report("unmined credit %x is malformed", k)
+			return nil
+		}
+		if existsRawUnmined(ns, extractRawUnminedCreditTxHash(k)) == nil {
+			var txHash chainhash.Hash
+			copy(txHash[:], k[:32])
+			report("unmined credit %v:%d has no unmined transaction",
+				&txHash, byteOrder.Uint32(k[32:36]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+// extractRawCreditAmount returns the amount of a credit value of at least 8
+// bytes.
+func extractRawCreditAmount(v []byte) hcutil.Amount {
+	return hcutil.Amount(byteOrder.Uint64(v[:8]))
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// InvoiceBranch is the child number of the account branch from which invoice
+// addresses are derived.  It is the last non-hardened child, so it never
+// conflicts with the additional external branches, which are numbered upwards
+// from FirstExtraExternalBranch.  Invoice addresses are not subject to the gap
+// limit, as their child indexes are derived from invoice ids rather than
+// handed out in order.
+const InvoiceBranch uint32 = hdkeychain.HardenedKeyStart - 1
+
+// Invoice records the address derived for an invoice.
+type Invoice struct {
+	ID      string
+	Account uint32
+	Child   uint32
+	Created time.Time
+}
+
+// The invoices bucket records the account and invoice branch child of the
+// address derived for each invoice, keyed by invoice id.  The value is
+// serialized as such:
+//
+//   [0:4]   Account (4 bytes)
+//   [4:8]   Child index (4 bytes)
+//   [8:16]  Creation time (8 bytes)
+
+func valueInvoice(inv *Invoice) []byte {
+	v := make([]byte, 16)
+	binary.LittleEndian.PutUint32(v, inv.Account)
+	binary.LittleEndian.PutUint32(v[4:], inv.Child)
+	binary.LittleEndian.PutUint64(v[8:], uint64(inv.Created.Unix()))
+	return v
+}
+
+func readInvoice(k, v []byte, inv *Invoice) error {
+	if len(v) < 16 {
+		str := fmt.Sprintf("%s: short read for invoice %q",
+			invoicesBucketName, k)
+		return apperrors.E{ErrorCode: apperrors.ErrData, Description: str, Err: nil}
+	}
+	inv.ID = string(k)
+	inv.Account = binary.LittleEndian.Uint32(v)
+	inv.Child = binary.LittleEndian.Uint32(v[4:])
+	inv.Created = time.Unix(int64(binary.LittleEndian.Uint64(v[8:])), 0)
+	return nil
+}
+
+// FetchInvoice returns the recorded address derivation of an invoice, or nil
+// if no address was derived for the invoice.
+func (m *Manager) FetchInvoice(ns walletdb.ReadBucket, id string) (*Invoice, error) {
+	v := ns.NestedReadBucket(invoicesBucketName).Get([]byte(id))
+	if v == nil {
+		return nil, nil
+	}
+	inv := new(Invoice)
+	err := readInvoice([]byte(id), v, inv)
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// ForEachInvoice calls fn with every recorded invoice.
+func (m *Manager) ForEachInvoice(ns walletdb.ReadBucket, fn func(*Invoice) error) error {
+	return ns.NestedReadBucket(invoicesBucketName).ForEach(func(k, v []byte) error {
+		var inv Invoice
+		err := readInvoice(k, v, &inv)
+		if err != nil {
+			return err
+		}
+		return fn(&inv)
+	})
+}
+
+// PutInvoice records the address derived for an invoice, and records the
+// address as a child of the account's invoice branch so transactions paying
+// it are recognized as the wallet's.  Invoice ids must be unique, and
+// addresses are only derived for invoices of ECDSA accounts.
+func (m *Manager) PutInvoice(ns walletdb.ReadWriteBucket, inv *Invoice, addr *hcutil.AddressPubKeyHash) error {
+	if inv.ID == "" {
+		const str = "invoice ids may not be empty"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if inv.Account == ImportedAddrAccount {
+		const str = "cannot derive invoice addresses of the imported account"
+		return apperrors.E{ErrorCode: apperrors.ErrInvalidAccount, Description: str, Err: nil}
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	acctInfo, err := m.loadAccountInfo(ns, inv.Account)
+	if err != nil {
+		return err
+	}
+	if acctInfo.acctType != AcctypeEc {
+		const str = "invoice addresses are only supported by ECDSA accounts"
+		return apperrors.E{ErrorCode: apperrors.ErrInvalidAccount, Description: str, Err: nil}
+	}
+
+	b := ns.NestedReadWriteBucket(invoicesBucketName)
+	if b.Get([]byte(inv.ID)) != nil {
+		str := fmt.Sprintf("an address was already derived for invoice %q",
+			inv.ID)
+		return apperrors.E{ErrorCode: apperrors.ErrDuplicate, Description: str, Err: nil}
+	}
+	err = putChainedAddress(ns, addr, inv.Account, SSFull, InvoiceBranch, inv.Child)
+	if err != nil {
+		return err
+	}
+	err = b.Put([]byte(inv.ID), valueInvoice(inv))
+	if err != nil {
+		const str = "failed to store invoice"
+		return apperrors.E{ErrorCode: apperrors.ErrDatabase, Description: str, Err: err}
+	}
+	return nil
+}
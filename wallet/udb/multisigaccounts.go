@@ -0,0 +1,147 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// MultisigAccount records an m-of-n multisig account shared with cosigners.
+// The wallet's keys are derived from an additional external branch of one of
+// its accounts, and the child at each index of the wallet's branch and every
+// cosigner's extended public key together form the multisig script at that
+// index.
+type MultisigAccount struct {
+	Name      string
+	Account   uint32
+	Branch    uint32
+	Required  uint8
+	Cosigners []string // serialized extended public keys
+	NextIndex uint32   // number of derived address indexes
+	Created   time.Time
+}
+
+// The multisig accounts bucket records multisig accounts keyed by name.  The
+// value is serialized as such:
+//
+//   [0:4]   Account (4 bytes)
+//   [4:8]   Branch (4 bytes)
+//   [8]     Required signatures (1 byte)
+//   [9:13]  Next address index (4 bytes)
+//   [13:21] Creation time (8 bytes)
+//   [21]    Number of cosigners (1 byte)
+//   [22:]   For each cosigner, the length of its extended public key
+//           (2 bytes) followed by the key
+
+func valueMultisigAccount(a *MultisigAccount) []byte {
+	size := 22
+	for _, xpub := range a.Cosigners {
+		size += 2 + len(xpub)
+	}
+	v := make([]byte, 22, size)
+	binary.LittleEndian.PutUint32(v, a.Account)
+	binary.LittleEndian.PutUint32(v[4:], a.Branch)
+	v[8] = a.Required
+	binary.LittleEndian.PutUint32(v[9:], a.NextIndex)
+	binary.LittleEndian.PutUint64(v[13:], uint64(a.Created.Unix()))
+	v[21] = uint8(len(a.Cosigners))
+	for _, xpub := range a.Cosigners {
+		var l [2]byte
+		binary.LittleEndian.PutUint16(l[:], uint16(len(xpub)))
+		v = append(v, l[:]...)
+		v = append(v, xpub...)
+	}
+	return v
+}
+
+func readMultisigAccount(k, v []byte, a *MultisigAccount) error {
+	shortRead := func() error {
+		str := fmt.Sprintf("%s: short read for multisig account %q",
+			multisigAccountsBucketName, k)
+		return apperrors.E{ErrorCode: apperrors.ErrData, Description: str, Err: nil}
+	}
+	if len(v) < 22 {
+		return shortRead()
+	}
+	a.Name = string(k)
+	a.Account = binary.LittleEndian.Uint32(v)
+	a.Branch = binary.LittleEndian.Uint32(v[4:])
+	a.Required = v[8]
+	a.NextIndex = binary.LittleEndian.Uint32(v[9:])
+	a.Created = time.Unix(int64(binary.LittleEndian.Uint64(v[13:])), 0)
+	a.Cosigners = make([]string, v[21])
+	v = v[22:]
+	for i := range a.Cosigners {
+		if len(v) < 2 {
+			return shortRead()
+		}
+		l := int(binary.LittleEndian.Uint16(v))
+		if len(v) < 2+l {
+			return shortRead()
+		}
+		a.Cosigners[i] = string(v[2 : 2+l])
+		v = v[2+l:]
+	}
+	return nil
+}
+
+// FetchMultisigAccount returns the multisig account with a name, or nil if no
+// such account exists.
+func (m *Manager) FetchMultisigAccount(ns walletdb.ReadBucket, name string) (*MultisigAccount, error) {
+	v := ns.NestedReadBucket(multisigAccountsBucketName).Get([]byte(name))
+	if v == nil {
+		return nil, nil
+	}
+	a := new(MultisigAccount)
+	err := readMultisigAccount([]byte(name), v, a)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ForEachMultisigAccount calls fn with every multisig account, in order of
+// name.
+func (m *Manager) ForEachMultisigAccount(ns walletdb.ReadBucket, fn func(*MultisigAccount) error) error {
+	return ns.NestedReadBucket(multisigAccountsBucketName).ForEach(func(k, v []byte) error {
+		var a MultisigAccount
+		err := readMultisigAccount(k, v, &a)
+		if err != nil {
+			return err
+		}
+		return fn(&a)
+	})
+}
+
+// PutMultisigAccount records a multisig account, replacing any account with
+// the same name.
+func (m *Manager) PutMultisigAccount(ns walletdb.ReadWriteBucket, a *MultisigAccount) error {
+	if a.Name == "" {
+		const str = "multisig account names may not be empty"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	if len(a.Cosigners) > 0xff {
+		const str = "too many multisig cosigners"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	for _, xpub := range a.Cosigners {
+		if len(xpub) > 0xffff {
+			const str = "cosigner extended public key is too long"
+			return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+		}
+	}
+	b := ns.NestedReadWriteBucket(multisigAccountsBucketName)
+	err := b.Put([]byte(a.Name), valueMultisigAccount(a))
+	if err != nil {
+		const str = "failed to store multisig account"
+		return apperrors.E{ErrorCode: apperrors.ErrDatabase, Description: str, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// OmniPending describes a pending balance change of a published transaction
+// which the wallet added to the omni engine.  The fields other than Hash and
+// Height are those of the omni_pending_add request, so the change can be
+// reversed if the transaction will never be mined.
+type OmniPending struct {
+	Hash chainhash.Hash
+
+	// Height is the main chain height when the entry was added.
+	Height int32
+
+	Sender     string
+	MscType    int32
+	PropertyID uint32
+	Amount     string
+	Subtract   bool
+}
+
+// The omni pending bucket records the pending omni entries added by the wallet
+// keyed by the transaction hash.  It is created with the first entry, as
+// wallets which never enable omni transactions have no use for it.  Values are
+// serialized as such:
+//
+//   [0:4]   Main chain height when the entry was added (4 bytes)
+//   [4:8]   Message type (4 bytes)
+//   [8:12]  Property ID (4 bytes)
+//   [12]    Subtract flag (1 byte)
+//   [13]    Sender length (1 byte)
+//   [14:]   Sender, followed by the amount
+
+func valueOmniPending(p *OmniPending) []byte {
+	v := make([]byte, 14, 14+len(p.Sender)+len(p.Amount))
+	byteOrder.PutUint32(v, uint32(p.Height))
+	byteOrder.PutUint32(v[4:], uint32(p.MscType))
+	byteOrder.PutUint32(v[8:], p.PropertyID)
+	if p.Subtract {
+		v[12] = 1
+	}
+	v[13] = byte(len(p.Sender))
+	v = append(v, p.Sender...)
+	v = append(v, p.Amount...)
+	return v
+}
+
+func readOmniPending(k, v []byte) (*OmniPending, error) {
+	if len(k) != chainhash.HashSize || len(v) < 14 || len(v) < 14+int(v[13]) {
+		str := fmt.Sprintf("%s: short read (key %d bytes, value %d bytes)",
+			bucketOmniPending, len(k), len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	p := &OmniPending{
+		Height:     int32(byteOrder.Uint32(v)),
+		MscType:    int32(byteOrder.Uint32(v[4:])),
+		PropertyID: byteOrder.Uint32(v[8:]),
+		Subtract:   v[12] != 0,
+		Sender:     string(v[14 : 14+int(v[13])]),
+		Amount:     string(v[14+int(v[13]):]),
+	}
+	copy(p.Hash[:], k)
+	return p, nil
+}
+
+// PutOmniPending records a pending omni entry added by the wallet, replacing
+// any entry recorded for the same transaction.
+func (s *Store) PutOmniPending(ns walletdb.ReadWriteBucket, p *OmniPending) error {
+	if len(p.Sender) > 255 {
+		str := fmt.Sprintf("sender %q of pending omni transaction %v is too long",
+			p.Sender, &p.Hash)
+		return storeError(apperrors.ErrInput, str, nil)
+	}
+	b, err := ns.CreateBucketIfNotExists(bucketOmniPending)
+	if err != nil {
+		str := "failed to create omni pending bucket"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	err = b.Put(p.Hash[:], valueOmniPending(p))
+	if err != nil {
+		str := fmt.Sprintf("failed to put pending omni transaction %v", &p.Hash)
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// RemoveOmniPending removes the pending omni entry of a transaction, if any.
+func (s *Store) RemoveOmniPending(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash) error {
+	b := ns.NestedReadWriteBucket(bucketOmniPending)
+	if b == nil {
+		return nil
+	}
+	err := b.Delete(txHash[:])
+	if err != nil {
+		str := fmt.Sprintf("failed to remove pending omni transaction %v", txHash)
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// FetchOmniPending returns the pending omni entry of a transaction, or nil if
+// none is recorded.
+func (s *Store) FetchOmniPending(ns walletdb.ReadBucket, txHash *chainhash.Hash) (*OmniPending, error) {
+	b := ns.NestedReadBucket(bucketOmniPending)
+	if b == nil {
+		return nil, nil
+	}
+	v := b.Get(txHash[:])
+	if v == nil {
+		return nil, nil
+	}
+	return readOmniPending(txHash[:], v)
+}
+
+// OmniPending returns the recorded pending omni entries.
+func (s *Store) OmniPending(ns walletdb.ReadBucket) ([]OmniPending, error) {
+	b := ns.NestedReadBucket(bucketOmniPending)
+	if b == nil {
+		return nil, nil
+	}
+	var pending []OmniPending
+	err := b.ForEach(func(k, v []byte) error {
+		p, err := readOmniPending(k, v)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, *p)
+		return nil
+	})
+	return pending, err
+}
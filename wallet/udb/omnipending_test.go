@@ -0,0 +1,88 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestOmniPending(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+		// Databases without recorded entries have none, and removing
+		// entries from them succeeds.
+		pending, err := s.OmniPending(ns)
+		if err != nil {
+			return err
+		}
+		if len(pending) != 0 {
+			t.Errorf("new database has %d pending omni entries", len(pending))
+		}
+		err = s.RemoveOmniPending(ns, &chainhash.Hash{1})
+		if err != nil {
+			return err
+		}
+
+		entries := []OmniPending{
+			{Hash: chainhash.Hash{1}, Height: 10, Sender: "Ssender", MscType: 0,
+				PropertyID: 3, Amount: "1.5", Subtract: true},
+			{Hash: chainhash.Hash{2}, Height: -1, MscType: 4,
+				PropertyID: 2147483651, Amount: "20"},
+		}
+		for i := range entries {
+			err := s.PutOmniPending(ns, &entries[i])
+			if err != nil {
+				return err
+			}
+		}
+		pending, err = s.OmniPending(ns)
+		if err != nil {
+			return err
+		}
+		if len(pending) != len(entries) {
+			t.Fatalf("read %d pending omni entries, want %d", len(pending),
+				len(entries))
+		}
+		for i := range entries {
+			if pending[i] != entries[i] {
+				t.Errorf("read pending omni entry %+v, want %+v",
+					pending[i], entries[i])
+			}
+		}
+
+		err = s.RemoveOmniPending(ns, &entries[0].Hash)
+		if err != nil {
+			return err
+		}
+		pending, err = s.OmniPending(ns)
+		if err != nil {
+			return err
+		}
+		if len(pending) != 1 || pending[0] != entries[1] {
+			t.Errorf("pending omni entries %+v after removing %v",
+				pending, &entries[0].Hash)
+		}
+
+		long := entries[1]
+		long.Sender = string(make([]byte, 256))
+		if err := s.PutOmniPending(ns, &long); err == nil {
+			t.Error("recorded a pending omni entry with a 256 byte sender")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
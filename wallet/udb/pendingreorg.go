@@ -0,0 +1,184 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// PendingReorg describes a chain switch which was notified by the consensus
+// server but not yet completed.  The blocks of the side chain received before
+// the switch completes are recorded with PutPendingReorgBlock, so that a
+// pending reorg recorded when the wallet starts can be resolved by switching to
+// the received blocks and resyncing the rest of the side chain.
+type PendingReorg struct {
+	// Target is the tip block of the side chain being switched to.
+	Target chainhash.Hash
+
+	// ForkHeight is the height of the first side chain block, or -1 when it
+	// could not be determined.
+	ForkHeight int32
+}
+
+// The root bucket's pending reorg k/v pair records a chain switch in progress.
+// The key is absent when no chain switch is in progress.  The value is
+// serialized as such:
+//
+//   [0:32]  Target block hash (32 bytes)
+//   [32:36] Fork height, or 0xffffffff when unknown (4 bytes)
+
+func valuePendingReorg(r *PendingReorg) []byte {
+	v := make([]byte, 36)
+	copy(v, r.Target[:])
+	byteOrder.PutUint32(v[32:36], uint32(r.ForkHeight))
+	return v
+}
+
+// PendingReorg returns the recorded chain switch in progress, or nil if there
+// is none.
+func (s *Store) PendingReorg(ns walletdb.ReadBucket) (*PendingReorg, error) {
+	v := ns.Get(rootPendingReorg)
+	if v == nil {
+		return nil, nil
+	}
+	if len(v) != 36 {
+		str := fmt.Sprintf("pending reorg: short read (expected 36 bytes, "+
+			"read %v)", len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	r := &PendingReorg{ForkHeight: int32(byteOrder.Uint32(v[32:36]))}
+	copy(r.Target[:], v)
+	return r, nil
+}
+
+// PutPendingReorg records a chain switch in progress, replacing any previously
+// recorded switch.
+func (s *Store) PutPendingReorg(ns walletdb.ReadWriteBucket, r *PendingReorg) error {
+	err := ns.Put(rootPendingReorg, valuePendingReorg(r))
+	if err != nil {
+		str := "failed to put pending reorg"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// RemovePendingReorg removes the record of a chain switch in progress and the
+// side chain blocks received for it.
+func (s *Store) RemovePendingReorg(ns walletdb.ReadWriteBucket) error {
+	err := ns.Delete(rootPendingReorg)
+	if err != nil {
+		str := "failed to remove pending reorg"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	if ns.NestedReadWriteBucket(bucketPendingReorgBlocks) == nil {
+		return nil
+	}
+	err = ns.DeleteNestedBucket(bucketPendingReorgBlocks)
+	if err != nil {
+		str := "failed to remove pending reorg blocks"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// PendingReorgBlock is a side chain block received for a chain switch in
+// progress, with the serialized transactions relevant to the wallet.
+type PendingReorgBlock struct {
+	Header       BlockHeaderData
+	Transactions [][]byte
+}
+
+// The pending reorg blocks bucket records the side chain blocks received for
+// the chain switch in progress.  It is created with the first received block
+// and removed with the pending reorg.  Keys are the block height (4 bytes), and
+// values are serialized as such:
+//
+//   [0:32]    Block hash (32 bytes)
+//   [32:212]  Serialized block header (180 bytes)
+//   [212:]    For each transaction:
+//               [0:4]  Serialized transaction size (4 bytes)
+//               [4:]   Serialized transaction
+
+func valuePendingReorgBlock(b *PendingReorgBlock) []byte {
+	size := 32 + len(b.Header.SerializedHeader)
+	for _, tx := range b.Transactions {
+		size += 4 + len(tx)
+	}
+	v := make([]byte, 32+len(b.Header.SerializedHeader), size)
+	copy(v, b.Header.BlockHash[:])
+	copy(v[32:], b.Header.SerializedHeader[:])
+	for _, tx := range b.Transactions {
+		v = append(v, 0, 0, 0, 0)
+		byteOrder.PutUint32(v[len(v)-4:], uint32(len(tx)))
+		v = append(v, tx...)
+	}
+	return v
+}
+
+func readPendingReorgBlock(v []byte) (*PendingReorgBlock, error) {
+	b := new(PendingReorgBlock)
+	headerEnd := 32 + len(b.Header.SerializedHeader)
+	if len(v) < headerEnd {
+		str := fmt.Sprintf("pending reorg block: short read (expected at "+
+			"least %d bytes, read %d)", headerEnd, len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	copy(b.Header.BlockHash[:], v)
+	copy(b.Header.SerializedHeader[:], v[32:])
+	v = v[headerEnd:]
+	for len(v) != 0 {
+		if len(v) < 4 || uint32(len(v)-4) < byteOrder.Uint32(v) {
+			str := "pending reorg block: truncated transaction"
+			return nil, storeError(apperrors.ErrData, str, nil)
+		}
+		n := byteOrder.Uint32(v)
+		tx := make([]byte, n)
+		copy(tx, v[4:4+n])
+		b.Transactions = append(b.Transactions, tx)
+		v = v[4+n:]
+	}
+	return b, nil
+}
+
+// PutPendingReorgBlock records a side chain block received for the chain
+// switch in progress, replacing any recorded block at the same height.
+func (s *Store) PutPendingReorgBlock(ns walletdb.ReadWriteBucket, b *PendingReorgBlock) error {
+	k := make([]byte, 4)
+	byteOrder.PutUint32(k, uint32(b.Header.SerializedHeader.Height()))
+	blocks, err := ns.CreateBucketIfNotExists(bucketPendingReorgBlocks)
+	if err != nil {
+		str := "failed to create pending reorg blocks bucket"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	err = blocks.Put(k, valuePendingReorgBlock(b))
+	if err != nil {
+		str := "failed to put pending reorg block"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// PendingReorgBlocks returns the recorded side chain blocks of the chain switch
+// in progress in order of increasing height.
+func (s *Store) PendingReorgBlocks(ns walletdb.ReadBucket) ([]*PendingReorgBlock, error) {
+	bucket := ns.NestedReadBucket(bucketPendingReorgBlocks)
+	if bucket == nil {
+		return nil, nil
+	}
+	var blocks []*PendingReorgBlock
+	err := bucket.ForEach(func(k, v []byte) error {
+		b, err := readPendingReorgBlock(v)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, b)
+		return nil
+	})
+	return blocks, err
+}
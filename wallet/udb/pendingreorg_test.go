@@ -0,0 +1,126 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestPendingReorgBlocks(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := makeBlockGenerator()
+	headers := makeHeaderDataSlice(g.generate(hcutil.BlockValid),
+		g.generate(hcutil.BlockValid), g.generate(hcutil.BlockValid))
+	blocks := []*PendingReorgBlock{
+		{Header: headers[0], Transactions: [][]byte{{1, 2, 3}, {4}}},
+		{Header: headers[1]},
+		{Header: headers[2], Transactions: [][]byte{make([]byte, 300)}},
+	}
+	reorg := &PendingReorg{Target: headers[2].BlockHash, ForkHeight: 1}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		err := s.PutPendingReorg(ns, reorg)
+		if err != nil {
+			return err
+		}
+		// Blocks are returned by height regardless of the order they
+		// are recorded in.
+		for _, i := range []int{2, 0, 1} {
+			err := s.PutPendingReorgBlock(ns, blocks[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(wtxmgrBucketKey)
+		r, err := s.PendingReorg(ns)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(r, reorg) {
+			t.Errorf("pending reorg %+v, want %+v", r, reorg)
+		}
+		got, err := s.PendingReorgBlocks(ns)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(got, blocks) {
+			t.Errorf("pending reorg blocks do not match the recorded blocks")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		err := s.RemovePendingReorg(ns)
+		if err != nil {
+			return err
+		}
+		r, err := s.PendingReorg(ns)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			t.Error("pending reorg was not removed")
+		}
+		got, err := s.PendingReorgBlocks(ns)
+		if err != nil {
+			return err
+		}
+		if len(got) != 0 {
+			t.Errorf("%d pending reorg blocks remain after removal", len(got))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a pending reorg without received blocks succeeds.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		err := s.PutPendingReorg(ns, reorg)
+		if err != nil {
+			return err
+		}
+		return s.RemovePendingReorg(ns)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadPendingReorgBlockTruncated(t *testing.T) {
+	g := makeBlockGenerator()
+	b := &PendingReorgBlock{
+		Header:       makeHeaderData(g.generate(hcutil.BlockValid)),
+		Transactions: [][]byte{{1, 2, 3}},
+	}
+	v := valuePendingReorgBlock(b)
+	for _, n := range []int{0, 100, len(v) - 5, len(v) - 1} {
+		if _, err := readPendingReorgBlock(v[:n]); err == nil {
+			t.Errorf("read a pending reorg block truncated to %d bytes", n)
+		}
+	}
+}
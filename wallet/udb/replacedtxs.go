@@ -0,0 +1,49 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// The replaced transactions bucket records unmined transactions which were
+// removed from the wallet and replaced by a transaction spending the same
+// inputs with a higher fee.  Keys are the hash of the replaced transaction and
+// values are the hash of its replacement:
+//
+//   [0:32]  Replacement transaction hash (32 bytes)
+
+// PutReplacedTx records that the unmined transaction original was replaced by
+// the transaction replacement.
+func (s *Store) PutReplacedTx(ns walletdb.ReadWriteBucket, original, replacement *chainhash.Hash) error {
+	err := ns.NestedReadWriteBucket(bucketReplacedTxs).Put(original[:], replacement[:])
+	if err != nil {
+		str := fmt.Sprintf("failed to record replacement of transaction %v",
+			original)
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// ReplacedBy returns the hash of the transaction which replaced a transaction,
+// or nil if the transaction was not replaced.
+func (s *Store) ReplacedBy(ns walletdb.ReadBucket, txHash *chainhash.Hash) (*chainhash.Hash, error) {
+	v := ns.NestedReadBucket(bucketReplacedTxs).Get(txHash[:])
+	if v == nil {
+		return nil, nil
+	}
+	if len(v) != chainhash.HashSize {
+		str := fmt.Sprintf("%s: short read for transaction %v",
+			bucketReplacedTxs, txHash)
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	var replacement chainhash.Hash
+	copy(replacement[:], v)
+	return &replacement, nil
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+	"golang.org/x/crypto/scrypt"
+)
+
+// SeedBackupWords is the number of seed words checked to verify a backup of
+// the wallet seed.
+const SeedBackupWords = 4
+
+// seedBackupName is the key of the seed backup challenge in the address
+// manager's main bucket.  The key is absent for wallets which were not created
+// from a seed known to this software, such as watching-only wallets and
+// wallets created before challenges were recorded.
+var seedBackupName = []byte("seedbackup")
+
+// SeedBackupChallenge records a few randomly chosen positions of the wallet
+// seed and a verifier of the seed bytes at those positions, so that a backup
+// of the seed can be checked without the seed being saved.  The verifier is
+// derived with scrypt and stored encrypted with the private crypto key.  The
+// few checked seed bytes can be brute forced from a plaintext verifier in
+// little time, and would reduce the strength of the remaining seed, so the
+// wallet must be unlocked to check a backup.
+type SeedBackupChallenge struct {
+	// Positions are the zero-based indexes of the checked seed bytes, or
+	// mnemonic words, in increasing order.
+	Positions []int
+
+	// Verified records whether a backup of the seed was verified.
+	Verified bool
+
+	scryptN, scryptR, scryptP int
+	salt                      [32]byte
+	encryptedVerifier         []byte
+}
+
+// seedBackupVerifierSize is the size of the verifier derived from the checked
+// seed bytes before it is encrypted.
+const seedBackupVerifierSize = 32
+
+// newSeedBackupChallenge creates a challenge for random positions of seed,
+// encrypting the verifier with cryptoKeyPriv.
+func newSeedBackupChallenge(seed []byte, config *ScryptOptions,
+	cryptoKeyPriv EncryptorDecryptor) (*SeedBackupChallenge, error) {
+
+	if len(seed) < SeedBackupWords {
+		str := "seed is too short for a backup challenge"
+		return nil, managerError(apperrors.ErrInput, str, nil)
+	}
+
+	c := &SeedBackupChallenge{
+		scryptN: config.N,
+		scryptR: config.R,
+		scryptP: config.P,
+	}
+	chosen := make(map[int]struct{}, SeedBackupWords)
+	for len(chosen) < SeedBackupWords {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(seed))))
+		if err != nil {
+			return nil, managerError(apperrors.ErrCrypto, "failed to choose positions", err)
+		}
+		chosen[int(n.Int64())] = struct{}{}
+	}
+	for pos := range chosen {
+		c.Positions = append(c.Positions, pos)
+	}
+	sort.Ints(c.Positions)
+	_, err := rand.Read(c.salt[:])
+	if err != nil {
+		return nil, managerError(apperrors.ErrCrypto, "failed to read salt", err)
+	}
+
+	words := make([]byte, len(c.Positions))
+	for i, pos := range c.Positions {
+		words[i] = seed[pos]
+	}
+	verifier, err := c.derive(words)
+	if err != nil {
+		return nil, err
+	}
+	c.encryptedVerifier, err = cryptoKeyPriv.Encrypt(verifier)
+	if err != nil {
+		return nil, managerError(apperrors.ErrCrypto, "failed to encrypt verifier", err)
+	}
+	return c, nil
+}
+
+func (c *SeedBackupChallenge) derive(words []byte) ([]byte, error) {
+	key, err := scrypt.Key(words, c.salt[:], c.scryptN, c.scryptR, c.scryptP,
+		seedBackupVerifierSize)
+	if err != nil {
+		return nil, managerError(apperrors.ErrCrypto, "failed to derive verifier", err)
+	}
+	return key, nil
+}
+
+// CheckSeedBackup returns whether words are the seed bytes at each of the
+// positions of challenge c, in order.  Checking is intentionally slow, and
+// requires the manager to be unlocked to decrypt the verifier.
+func (m *Manager) CheckSeedBackup(c *SeedBackupChallenge, words []byte) (bool, error) {
+	if len(words) != len(c.Positions) {
+		str := fmt.Sprintf("seed backup challenge requires %d words, got %d",
+			len(c.Positions), len(words))
+		return false, managerError(apperrors.ErrInput, str, nil)
+	}
+	verifier, err := m.Decrypt(CKTPrivate, c.encryptedVerifier)
+	if err != nil {
+		return false, err
+	}
+	derived, err := c.derive(words)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(derived, verifier) == 1, nil
+}
+
+// The seed backup challenge is serialized as such:
+//
+//   [0:4]     Scrypt N (4 bytes)
+//   [4:8]     Scrypt r (4 bytes)
+//   [8:12]    Scrypt p (4 bytes)
+//   [12:44]   Salt (32 bytes)
+//   [44]      Verified flag (1 byte)
+//   [45]      Number of positions (1 byte)
+//   [46:46+n] Positions (1 byte each)
+//   [46+n:]   Encrypted verifier
+
+func serializeSeedBackupChallenge(c *SeedBackupChallenge) []byte {
+	n := len(c.Positions)
+	v := make([]byte, 46+n+len(c.encryptedVerifier))
+	byteOrder.PutUint32(v[0:4], uint32(c.scryptN))
+	byteOrder.PutUint32(v[4:8], uint32(c.scryptR))
+	byteOrder.PutUint32(v[8:12], uint32(c.scryptP))
+	copy(v[12:44], c.salt[:])
+	if c.Verified {
+		v[44] = 1
+	}
+	v[45] = byte(n)
+	for i, pos := range c.Positions {
+		v[46+i] = byte(pos)
+	}
+	copy(v[46+n:], c.encryptedVerifier)
+	return v
+}
+
+func deserializeSeedBackupChallenge(v []byte) (*SeedBackupChallenge, error) {
+	if len(v) < 46 || len(v) <= 46+int(v[45]) {
+		str := fmt.Sprintf("seed backup challenge: bad length %d", len(v))
+		return nil, managerError(apperrors.ErrDatabase, str, nil)
+	}
+	n := int(v[45])
+	c := &SeedBackupChallenge{
+		Verified:          v[44] != 0,
+		Positions:         make([]int, n),
+		scryptN:           int(byteOrder.Uint32(v[0:4])),
+		scryptR:           int(byteOrder.Uint32(v[4:8])),
+		scryptP:           int(byteOrder.Uint32(v[8:12])),
+		encryptedVerifier: append([]byte(nil), v[46+n:]...),
+	}
+	copy(c.salt[:], v[12:44])
+	for i := range c.Positions {
+		c.Positions[i] = int(v[46+i])
+	}
+	return c, nil
+}
+
+func putSeedBackupChallenge(ns walletdb.ReadWriteBucket, c *SeedBackupChallenge) error {
+	bucket := ns.NestedReadWriteBucket(mainBucketName)
+	err := bucket.Put(seedBackupName, serializeSeedBackupChallenge(c))
+	if err != nil {
+		str := "failed to store seed backup challenge"
+		return managerError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// SeedBackupChallenge returns the challenge to verify a backup of the wallet
+// seed.  An error with code ErrValueNoExists is returned if the wallet has no
+// challenge.
+func (m *Manager) SeedBackupChallenge(ns walletdb.ReadBucket) (*SeedBackupChallenge, error) {
+	v := ns.NestedReadBucket(mainBucketName).Get(seedBackupName)
+	if v == nil {
+		str := "wallet has no seed backup challenge"
+		return nil, managerError(apperrors.ErrValueNoExists, str, nil)
+	}
+	return deserializeSeedBackupChallenge(v)
+}
+
+// MarkSeedBackupVerified records that a backup of the wallet seed was verified.
+func (m *Manager) MarkSeedBackupVerified(ns walletdb.ReadWriteBucket) error {
+	c, err := m.SeedBackupChallenge(ns)
+	if err != nil {
+		return err
+	}
+	if c.Verified {
+		return nil
+	}
+	c.Verified = true
+	return putSeedBackupChallenge(ns, c)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SpendableConfs describes the confirmations an account requires before its
+// outputs are considered spendable, in addition to the maturities required by
+// consensus.  Requirements below the minimum confirmations of a request or the
+// maturities of the chain have no effect.
+type SpendableConfs struct {
+	// Regular is the number of confirmations required of every output.
+	Regular int32
+
+	// Coinbase is the number of confirmations required of coinbase outputs
+	// and of outputs of votes and revocations.
+	Coinbase int32
+}
+
+// MinConf returns the confirmations required of an output when minConf
+// confirmations are requested.
+func (c *SpendableConfs) MinConf(minConf int32) int32 {
+	if c.Regular > minConf {
+		return c.Regular
+	}
+	return minConf
+}
+
+// CoinbaseMaturity returns the confirmations required of coinbase, vote and
+// revocation outputs, which must also meet the requirement of every output.
+func (c *SpendableConfs) CoinbaseMaturity(params *chaincfg.Params) int32 {
+	maturity := c.MinConf(int32(params.CoinbaseMaturity))
+	if c.Coinbase > maturity {
+		return c.Coinbase
+	}
+	return maturity
+}
+
+// The spendable confirmations bucket records the requirements of accounts
+// keyed by the account number:
+//
+//   [0:4]   Account (4 bytes)
+//
+// The value is serialized as such:
+//
+//   [0:4]   Regular confirmations (4 bytes)
+//   [4:8]   Coinbase confirmations (4 bytes)
+
+func keySpendableConfs(account uint32) []byte {
+	k := make([]byte, 4)
+	byteOrder.PutUint32(k, account)
+	return k
+}
+
+func valueSpendableConfs(c *SpendableConfs) []byte {
+	v := make([]byte, 8)
+	byteOrder.PutUint32(v, uint32(c.Regular))
+	byteOrder.PutUint32(v[4:], uint32(c.Coinbase))
+	return v
+}
+
+func fetchSpendableConfs(ns walletdb.ReadBucket, account uint32) (SpendableConfs, error) {
+	var c SpendableConfs
+	v := ns.NestedReadBucket(bucketSpendableConfs).Get(keySpendableConfs(account))
+	if v == nil {
+		return c, nil
+	}
+	if len(v) != 8 {
+		str := fmt.Sprintf("%s: short read for account %d",
+			bucketSpendableConfs, account)
+		return c, storeError(apperrors.ErrData, str, nil)
+	}
+	c.Regular = int32(byteOrder.Uint32(v))
+	c.Coinbase = int32(byteOrder.Uint32(v[4:]))
+	return c, nil
+}
+
+// spendableConfsCache memoizes the spendable confirmation requirements of
+// accounts for the duration of a scan over many outputs.
+type spendableConfsCache struct {
+	ns walletdb.ReadBucket
+	m  map[uint32]*SpendableConfs
+}
+
+func newSpendableConfsCache(ns walletdb.ReadBucket) *spendableConfsCache {
+	return &spendableConfsCache{ns: ns, m: make(map[uint32]*SpendableConfs)}
+}
+
+func (c *spendableConfsCache) account(account uint32) (*SpendableConfs, error) {
+	confs, ok := c.m[account]
+	if ok {
+		return confs, nil
+	}
+	v, err := fetchSpendableConfs(c.ns, account)
+	if err != nil {
+		return nil, err
+	}
+	confs = &v
+	c.m[account] = confs
+	return confs, nil
+}
+
+// SpendableConfs returns the spendable confirmation requirements of an
+// account.  Accounts without recorded requirements return the zero value.
+func (s *Store) SpendableConfs(ns walletdb.ReadBucket, account uint32) (SpendableConfs, error) {
+	return fetchSpendableConfs(ns, account)
+}
+
+// SetSpendableConfs records the spendable confirmation requirements of an
+// account.  Setting the zero value removes any recorded requirements.
+func (s *Store) SetSpendableConfs(ns walletdb.ReadWriteBucket, account uint32, confs *SpendableConfs) error {
+	if confs.Regular < 0 || confs.Coinbase < 0 {
+		const str = "spendable confirmations may not be negative"
+		return apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	b := ns.NestedReadWriteBucket(bucketSpendableConfs)
+	var err error
+	if *confs == (SpendableConfs{}) {
+		err = b.Delete(keySpendableConfs(account))
+	} else {
+		err = b.Put(keySpendableConfs(account), valueSpendableConfs(confs))
+	}
+	if err != nil {
+		str := "failed to put spendable confirmations"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
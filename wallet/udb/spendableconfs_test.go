@@ -0,0 +1,96 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestSpendableConfsRequirements(t *testing.T) {
+	params := &chaincfg.TestNet2Params
+	maturity := int32(params.CoinbaseMaturity)
+	tests := []struct {
+		confs    SpendableConfs
+		minConf  int32
+		regular  int32
+		coinbase int32
+	}{
+		{SpendableConfs{}, 1, 1, maturity},
+		{SpendableConfs{Regular: 6}, 1, 6, maturity},
+		{SpendableConfs{Regular: 6}, 10, 10, maturity},
+		{SpendableConfs{Regular: maturity + 5}, 1, maturity + 5, maturity + 5},
+		{SpendableConfs{Coinbase: maturity + 10}, 1, 1, maturity + 10},
+		{SpendableConfs{Coinbase: 1}, 1, 1, maturity},
+	}
+	for _, test := range tests {
+		if got := test.confs.MinConf(test.minConf); got != test.regular {
+			t.Errorf("%+v: %d confirmations required with minconf %d, "+
+				"want %d", test.confs, got, test.minConf, test.regular)
+		}
+		if got := test.confs.CoinbaseMaturity(params); got != test.coinbase {
+			t.Errorf("%+v: coinbase maturity %d, want %d", test.confs,
+				got, test.coinbase)
+		}
+	}
+}
+
+func TestSpendableConfs(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+		confs, err := s.SpendableConfs(ns, 0)
+		if err != nil {
+			return err
+		}
+		if confs != (SpendableConfs{}) {
+			t.Errorf("new store requires %+v", confs)
+		}
+
+		set := SpendableConfs{Regular: 6, Coinbase: 512}
+		err = s.SetSpendableConfs(ns, 1, &set)
+		if err != nil {
+			return err
+		}
+		for account, want := range map[uint32]SpendableConfs{0: {}, 1: set} {
+			confs, err := s.SpendableConfs(ns, account)
+			if err != nil {
+				return err
+			}
+			if confs != want {
+				t.Errorf("account %d requires %+v, want %+v", account,
+					confs, want)
+			}
+		}
+
+		err = s.SetSpendableConfs(ns, 1, &SpendableConfs{Regular: -1})
+		if !apperrors.IsError(err, apperrors.ErrInput) {
+			t.Errorf("setting negative confirmations returned %v, want "+
+				"ErrInput", err)
+		}
+
+		// The zero value removes the requirements.
+		err = s.SetSpendableConfs(ns, 1, &SpendableConfs{})
+		if err != nil {
+			return err
+		}
+		if ns.NestedReadBucket(bucketSpendableConfs).Get(keySpendableConfs(1)) != nil {
+			t.Error("zero requirements remain recorded")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
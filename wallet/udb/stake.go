@@ -85,11 +85,69 @@ type PoolTicket struct {
 	SpentBy      chainhash.Hash
 }
 
+// TicketRejectionReason describes why a stake pool rejected a user ticket.
+type TicketRejectionReason uint8
+
+// These constants describe the reasons stake pool user tickets are rejected.
+const (
+	// TicketRejectedUnknown describes tickets which were rejected before
+	// rejection reasons were recorded.
+	TicketRejectedUnknown TicketRejectionReason = iota
+
+	// TicketRejectedFeeTooLow describes tickets which commit less to the
+	// pool fee address than the required pool fee.
+	TicketRejectedFeeTooLow
+
+	// TicketRejectedUnknownCommitment describes tickets with a first
+	// commitment to an address which is not a pool fee address.
+	TicketRejectedUnknownCommitment
+
+	// TicketRejectedParseFailure describes tickets with commitment outputs
+	// which could not be parsed.
+	TicketRejectedParseFailure
+)
+
+var ticketRejectionReasonStrings = [...]string{
+	TicketRejectedUnknown:           "unknown",
+	TicketRejectedFeeTooLow:         "feetoolow",
+	TicketRejectedUnknownCommitment: "unknowncommitmentaddress",
+	TicketRejectedParseFailure:      "parsefailure",
+}
+
+// String returns the reason as a lowercase string.
+func (r TicketRejectionReason) String() string {
+	if int(r) < len(ticketRejectionReasonStrings) {
+		return ticketRejectionReasonStrings[r]
+	}
+	return ticketRejectionReasonStrings[TicketRejectedUnknown]
+}
+
+// TicketRejection describes why a stake pool user ticket is invalid.
+type TicketRejection struct {
+	Ticket chainhash.Hash
+	Reason TicketRejectionReason
+
+	// Height is the block height of the ticket.
+	Height uint32
+
+	// Found and Required are the committed and required pool fees of
+	// tickets rejected with TicketRejectedFeeTooLow.
+	Found    hcutil.Amount
+	Required hcutil.Amount
+
+	// Detail is the commitment address of tickets rejected with
+	// TicketRejectedUnknownCommitment, or the parse error of tickets
+	// rejected with TicketRejectedParseFailure.
+	Detail string
+}
+
 // StakePoolUser is a list of tickets for a given user (P2SH
-// address) in the stake pool.
+// address) in the stake pool.  Rejections describes each invalid ticket, in
+// the same order as InvalidTickets.
 type StakePoolUser struct {
 	Tickets        []*PoolTicket
 	InvalidTickets []*chainhash.Hash
+	Rejections     []*TicketRejection
 }
 
 // StakeStore represents a safely accessible database of
@@ -432,7 +490,11 @@ func (s *StakeStore) removeStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket
 	scriptHash := new([20]byte)
 	copy(scriptHash[:], scriptHashB)
 
-	return removeStakePoolInvalUserTickets(ns, *scriptHash, ticket)
+	err := removeStakePoolInvalUserTickets(ns, *scriptHash, ticket)
+	if err != nil {
+		return err
+	}
+	return deleteStakePoolTicketRejection(ns, ticket)
 }
 
 // RemoveStakePoolUserInvalTickets is the exported and concurrency safe form of
@@ -443,9 +505,9 @@ func (s *StakeStore) RemoveStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket
 }
 
 // updateStakePoolUserInvalTickets updates the list of invalid stake pool
-// tickets for a given user. If the ticket does not currently exist in the
-// database, it adds it.
-func (s *StakeStore) updateStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket, user hcutil.Address, ticket *chainhash.Hash) error {
+// tickets for a given user and records why the ticket was rejected. If the
+// ticket does not currently exist in the database, it adds it.
+func (s *StakeStore) updateStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket, user hcutil.Address, rejection *TicketRejection) error {
 	_, isScriptHash := user.(*hcutil.AddressScriptHash)
 	_, isP2PKH := user.(*hcutil.AddressPubKeyHash)
 	if !(isScriptHash || isP2PKH) {
@@ -456,13 +518,17 @@ func (s *StakeStore) updateStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket
 	scriptHash := new([20]byte)
 	copy(scriptHash[:], scriptHashB)
 
-	return updateStakePoolInvalUserTickets(ns, *scriptHash, ticket)
+	err := updateStakePoolInvalUserTickets(ns, *scriptHash, &rejection.Ticket)
+	if err != nil {
+		return err
+	}
+	return putStakePoolTicketRejection(ns, rejection)
 }
 
 // UpdateStakePoolUserInvalTickets is the exported and concurrency safe form of
 // updateStakePoolUserInvalTickets.
-func (s *StakeStore) UpdateStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket, user hcutil.Address, ticket *chainhash.Hash) error {
-	return s.updateStakePoolUserInvalTickets(ns, user, ticket)
+func (s *StakeStore) UpdateStakePoolUserInvalTickets(ns walletdb.ReadWriteBucket, user hcutil.Address, rejection *TicketRejection) error {
+	return s.updateStakePoolUserInvalTickets(ns, user, rejection)
 }
 
 func stakePoolUserInfo(ns walletdb.ReadBucket, user hcutil.Address) (*StakePoolUser, error) {
@@ -512,8 +578,18 @@ func stakePoolUserInfo(ns walletdb.ReadBucket, user hcutil.Address) (*StakePoolU
 		invalTickets = make([]*chainhash.Hash, 0)
 	}
 
+	rejections := make([]*TicketRejection, len(invalTickets))
+	for i, ticket := range invalTickets {
+		var err error
+		rejections[i], err = fetchStakePoolTicketRejection(ns, ticket)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	stakePoolUser.Tickets = userTickets
 	stakePoolUser.InvalidTickets = invalTickets
+	stakePoolUser.Rejections = rejections
 
 	return stakePoolUser, nil
 }
@@ -524,6 +600,60 @@ func (s *StakeStore) StakePoolUserInfo(ns walletdb.ReadBucket, user hcutil.Addre
 	return stakePoolUserInfo(ns, user)
 }
 
+// stakePoolUserScriptHash returns the script hash keying the records of a
+// stake pool user.
+func stakePoolUserScriptHash(user hcutil.Address) ([20]byte, error) {
+	var scriptHash [20]byte
+	_, isScriptHash := user.(*hcutil.AddressScriptHash)
+	_, isP2PKH := user.(*hcutil.AddressPubKeyHash)
+	if !(isScriptHash || isP2PKH) {
+		str := fmt.Sprintf("user %v is invalid", user.EncodeAddress())
+		return scriptHash, stakeStoreError(apperrors.ErrBadPoolUserAddr, str, nil)
+	}
+	copy(scriptHash[:], user.ScriptAddress())
+	return scriptHash, nil
+}
+
+// SetStakePoolFeeExemption exempts a stake pool user from the pool fee check,
+// or removes the exemption, so that tickets of exempt users which commit less
+// than the required pool fee are accepted.
+func (s *StakeStore) SetStakePoolFeeExemption(ns walletdb.ReadWriteBucket, user hcutil.Address, exempt bool) error {
+	scriptHash, err := stakePoolUserScriptHash(user)
+	if err != nil {
+		return err
+	}
+	if exempt {
+		return putStakePoolExemption(ns, scriptHash, user.EncodeAddress())
+	}
+	return deleteStakePoolExemption(ns, scriptHash)
+}
+
+// StakePoolFeeExempt returns whether a stake pool user is exempt from the pool
+// fee check.
+func (s *StakeStore) StakePoolFeeExempt(ns walletdb.ReadBucket, user hcutil.Address) (bool, error) {
+	scriptHash, err := stakePoolUserScriptHash(user)
+	if err != nil {
+		return false, err
+	}
+	return existsStakePoolExemption(ns, scriptHash), nil
+}
+
+// StakePoolFeeExemptions returns the stake pool users exempt from the pool fee
+// check.
+func (s *StakeStore) StakePoolFeeExemptions(ns walletdb.ReadBucket) ([]hcutil.Address, error) {
+	var users []hcutil.Address
+	err := forEachStakePoolExemption(ns, func(encoded string) error {
+		user, err := hcutil.DecodeAddress(encoded)
+		if err != nil {
+			str := fmt.Sprintf("invalid exempt pool user %q", encoded)
+			return stakeStoreError(apperrors.ErrDatabase, str, err)
+		}
+		users = append(users, user)
+		return nil
+	})
+	return users, err
+}
+
 // loadManager returns a new stake manager that results from loading it from
 // the passed opened database.  The public passphrase is required to decrypt the
 // public keys.
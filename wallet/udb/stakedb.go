@@ -72,6 +72,15 @@ var (
 	// stakePoolInvalidPrefix is the byte slice prefix for invalid
 	// tickets in the stake pool for a given user.
 	stakePoolInvalidPrefix = []byte("invld")
+
+	// stakePoolRejectionPrefix is the byte slice prefix for the reasons
+	// invalid stake pool tickets were rejected, keyed by ticket hash.
+	// Tickets marked invalid before reasons were recorded have no key.
+	stakePoolRejectionPrefix = []byte("rejct")
+
+	// stakePoolExemptionPrefix is the byte slice prefix for stake pool
+	// users exempt from the pool fee check, keyed by user script hash.
+	stakePoolExemptionPrefix = []byte("exmpt")
 )
 
 // Key names for various database fields.
@@ -81,12 +90,20 @@ var (
 // ssgenRecords
 //     key: sstx tx hash
 //     val: serialized slice of ssgenRecords
+// stakeRewards
+//     key: block height + vote or revocation tx hash
+//     val: serialized StakeReward
+// ticketAccounts
+//     key: sstx tx hash
+//     val: funding account
 //
 var (
 	// Bucket names.
-	sstxRecordsBucketName  = []byte("sstxrecords")
-	ssgenRecordsBucketName = []byte("ssgenrecords")
-	ssrtxRecordsBucketName = []byte("ssrtxrecords")
+	sstxRecordsBucketName    = []byte("sstxrecords")
+	ssgenRecordsBucketName   = []byte("ssgenrecords")
+	ssrtxRecordsBucketName   = []byte("ssrtxrecords")
+	stakeRewardsBucketName   = []byte("stakerewards")
+	ticketAccountsBucketName = []byte("ticketaccounts")
 
 	// Db related key names (main bucket).
 	stakeStoreCreateDateName = []byte("stakestorecreated")
@@ -1099,6 +1116,140 @@ func updateStakePoolInvalUserTickets(ns walletdb.ReadWriteBucket, scriptHash [20
 	return nil
 }
 
+// keyStakePoolRejection returns the meta bucket key of the rejection reason of
+// an invalid stake pool ticket.
+func keyStakePoolRejection(ticket *chainhash.Hash) []byte {
+	key := make([]byte, len(stakePoolRejectionPrefix)+chainhash.HashSize)
+	copy(key, stakePoolRejectionPrefix)
+	copy(key[len(stakePoolRejectionPrefix):], ticket[:])
+	return key
+}
+
+// The rejection of an invalid stake pool ticket is serialized as such:
+//
+//   [0]     Reason (1 byte)
+//   [1:5]   Ticket block height (4 bytes)
+//   [5:13]  Amount found (8 bytes)
+//   [13:21] Amount required (8 bytes)
+//   [21:]   Detail string
+
+func serializeTicketRejection(r *TicketRejection) []byte {
+	buf := make([]byte, 21+len(r.Detail))
+	buf[0] = byte(r.Reason)
+	binary.LittleEndian.PutUint32(buf[1:5], r.Height)
+	binary.LittleEndian.PutUint64(buf[5:13], uint64(r.Found))
+	binary.LittleEndian.PutUint64(buf[13:21], uint64(r.Required))
+	copy(buf[21:], r.Detail)
+	return buf
+}
+
+func deserializeTicketRejection(ticket *chainhash.Hash, v []byte) (*TicketRejection, error) {
+	if len(v) < 21 {
+		str := fmt.Sprintf("stake pool ticket rejection: short read "+
+			"(expected at least 21 bytes, read %v)", len(v))
+		return nil, stakeStoreError(apperrors.ErrDatabase, str, nil)
+	}
+	return &TicketRejection{
+		Ticket:   *ticket,
+		Reason:   TicketRejectionReason(v[0]),
+		Height:   binary.LittleEndian.Uint32(v[1:5]),
+		Found:    hcutil.Amount(binary.LittleEndian.Uint64(v[5:13])),
+		Required: hcutil.Amount(binary.LittleEndian.Uint64(v[13:21])),
+		Detail:   string(v[21:]),
+	}, nil
+}
+
+// fetchStakePoolTicketRejection returns the rejection of an invalid stake pool
+// ticket, or a rejection with an unknown reason if none was recorded.
+func fetchStakePoolTicketRejection(ns walletdb.ReadBucket, ticket *chainhash.Hash) (*TicketRejection, error) {
+	bucket := ns.NestedReadBucket(metaBucketName)
+	v := bucket.Get(keyStakePoolRejection(ticket))
+	if v == nil {
+		return &TicketRejection{Ticket: *ticket}, nil
+	}
+	return deserializeTicketRejection(ticket, v)
+}
+
+func putStakePoolTicketRejection(ns walletdb.ReadWriteBucket, r *TicketRejection) error {
+	bucket := ns.NestedReadWriteBucket(metaBucketName)
+	err := bucket.Put(keyStakePoolRejection(&r.Ticket), serializeTicketRejection(r))
+	if err != nil {
+		str := fmt.Sprintf("failed to store rejection of pool ticket %v",
+			&r.Ticket)
+		return stakeStoreError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func deleteStakePoolTicketRejection(ns walletdb.ReadWriteBucket, ticket *chainhash.Hash) error {
+	bucket := ns.NestedReadWriteBucket(metaBucketName)
+	err := bucket.Delete(keyStakePoolRejection(ticket))
+	if err != nil {
+		str := fmt.Sprintf("failed to delete rejection of pool ticket %v",
+			ticket)
+		return stakeStoreError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// keyStakePoolExemption returns the meta bucket key of the fee exemption of a
+// stake pool user.
+func keyStakePoolExemption(scriptHash [20]byte) []byte {
+	key := make([]byte, len(stakePoolExemptionPrefix)+scriptHashSize)
+	copy(key, stakePoolExemptionPrefix)
+	copy(key[len(stakePoolExemptionPrefix):], scriptHash[:])
+	return key
+}
+
+// The fee exemption of a stake pool user is serialized as the encoded user
+// address, so exempt users can be listed by address.
+
+func existsStakePoolExemption(ns walletdb.ReadBucket, scriptHash [20]byte) bool {
+	bucket := ns.NestedReadBucket(metaBucketName)
+	return bucket.Get(keyStakePoolExemption(scriptHash)) != nil
+}
+
+func putStakePoolExemption(ns walletdb.ReadWriteBucket, scriptHash [20]byte, user string) error {
+	bucket := ns.NestedReadWriteBucket(metaBucketName)
+	err := bucket.Put(keyStakePoolExemption(scriptHash), []byte(user))
+	if err != nil {
+		str := fmt.Sprintf("failed to store fee exemption of pool user '%x'",
+			scriptHash)
+		return stakeStoreError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func deleteStakePoolExemption(ns walletdb.ReadWriteBucket, scriptHash [20]byte) error {
+	bucket := ns.NestedReadWriteBucket(metaBucketName)
+	err := bucket.Delete(keyStakePoolExemption(scriptHash))
+	if err != nil {
+		str := fmt.Sprintf("failed to delete fee exemption of pool user '%x'",
+			scriptHash)
+		return stakeStoreError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// forEachStakePoolExemption calls f with the encoded address of each stake
+// pool user exempt from the pool fee check.
+func forEachStakePoolExemption(ns walletdb.ReadBucket, f func(user string) error) error {
+	c := ns.NestedReadBucket(metaBucketName).ReadCursor()
+	prefix := stakePoolExemptionPrefix
+	for k, v := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		if len(k) != len(prefix)+scriptHashSize {
+			str := fmt.Sprintf("stake pool fee exemption: bad key "+
+				"length %d", len(k))
+			return stakeStoreError(apperrors.ErrDatabase, str, nil)
+		}
+		err := f(string(v))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // initialize creates the DB if it doesn't exist, and otherwise
 // loads the database.
 func initializeEmpty(ns walletdb.ReadWriteBucket) error {
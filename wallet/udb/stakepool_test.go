@@ -0,0 +1,218 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+var stakeTestNamespaceKey = []byte("wstakemgr")
+
+// setupStakeStore creates a database with an empty stake store namespace.
+func setupStakeStore(t *testing.T) (walletdb.DB, *StakeStore, func()) {
+	dir, err := ioutil.TempDir("", "udbstaketest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := walletdb.Create("bdb", filepath.Join(dir, "db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	teardown := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns, err := tx.CreateTopLevelBucket(stakeTestNamespaceKey)
+		if err != nil {
+			return err
+		}
+		return initializeEmpty(ns)
+	})
+	if err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+	return db, newStakeStore(&chaincfg.TestNet2Params, nil), teardown
+}
+
+// stakePoolUser returns a P2PKH stake pool user address.
+func stakePoolUser(t *testing.T, b byte) hcutil.Address {
+	hash := make([]byte, 20)
+	hash[0] = b
+	addr, err := hcutil.NewAddressPubKeyHash(hash, &chaincfg.TestNet2Params,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func TestStakePoolTicketRejections(t *testing.T) {
+	db, s, teardown := setupStakeStore(t)
+	defer teardown()
+
+	user := stakePoolUser(t, 1)
+	rejections := []TicketRejection{
+		{Ticket: chainhash.Hash{1}, Reason: TicketRejectedFeeTooLow,
+			Height: 100, Found: 1e5, Required: 2e5},
+		{Ticket: chainhash.Hash{2}, Reason: TicketRejectedUnknownCommitment,
+			Height: 101, Detail: "Tsaddress"},
+	}
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(stakeTestNamespaceKey)
+		for i := range rejections {
+			err := s.UpdateStakePoolUserInvalTickets(ns, user, &rejections[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		info, err := s.StakePoolUserInfo(ns, user)
+		if err != nil {
+			return err
+		}
+		if len(info.Rejections) != len(rejections) ||
+			len(info.InvalidTickets) != len(rejections) {
+			t.Fatalf("read %d invalid tickets and %d rejections, want %d",
+				len(info.InvalidTickets), len(info.Rejections),
+				len(rejections))
+		}
+		for i := range rejections {
+			if *info.InvalidTickets[i] != rejections[i].Ticket {
+				t.Errorf("invalid ticket %d is %v, want %v", i,
+					info.InvalidTickets[i], &rejections[i].Ticket)
+			}
+			if *info.Rejections[i] != rejections[i] {
+				t.Errorf("rejection %d is %+v, want %+v", i,
+					*info.Rejections[i], rejections[i])
+			}
+		}
+
+		// Removing an invalid ticket removes its rejection.  Invalid
+		// tickets recorded without a rejection have an unknown reason.
+		err = s.RemoveStakePoolUserInvalTickets(ns, user, &rejections[0].Ticket)
+		if err != nil {
+			return err
+		}
+		err = updateStakePoolInvalUserTickets(ns, [20]byte{1},
+			&rejections[0].Ticket)
+		if err != nil {
+			return err
+		}
+		info, err = s.StakePoolUserInfo(ns, user)
+		if err != nil {
+			return err
+		}
+		if len(info.Rejections) != 2 {
+			t.Fatalf("read %d rejections after removing and re-adding a "+
+				"ticket, want 2", len(info.Rejections))
+		}
+		for _, r := range info.Rejections {
+			if r.Ticket == rejections[0].Ticket &&
+				r.Reason != TicketRejectedUnknown {
+				t.Errorf("ticket recorded without a rejection has "+
+					"reason %v", r.Reason)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons := map[TicketRejectionReason]string{
+		TicketRejectedFeeTooLow:    "feetoolow",
+		TicketRejectedParseFailure: "parsefailure",
+		255:                        "unknown",
+	}
+	for reason, want := range reasons {
+		if reason.String() != want {
+			t.Errorf("reason %d is %q, want %q", reason, reason.String(), want)
+		}
+	}
+}
+
+func TestStakePoolFeeExemptions(t *testing.T) {
+	db, s, teardown := setupStakeStore(t)
+	defer teardown()
+
+	users := []hcutil.Address{stakePoolUser(t, 1), stakePoolUser(t, 2)}
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(stakeTestNamespaceKey)
+
+		exempt, err := s.StakePoolFeeExempt(ns, users[0])
+		if err != nil {
+			return err
+		}
+		if exempt {
+			t.Error("new store exempts a pool user")
+		}
+
+		for _, user := range users {
+			err := s.SetStakePoolFeeExemption(ns, user, true)
+			if err != nil {
+				return err
+			}
+		}
+		exemptions, err := s.StakePoolFeeExemptions(ns)
+		if err != nil {
+			return err
+		}
+		if len(exemptions) != len(users) {
+			t.Fatalf("read %d exemptions, want %d", len(exemptions),
+				len(users))
+		}
+		for i := range users {
+			if exemptions[i].EncodeAddress() != users[i].EncodeAddress() {
+				t.Errorf("exemption %d is %v, want %v", i, exemptions[i],
+					users[i])
+			}
+		}
+
+		// Removing an exemption leaves other users exempt.
+		err = s.SetStakePoolFeeExemption(ns, users[0], false)
+		if err != nil {
+			return err
+		}
+		for i, want := range []bool{false, true} {
+			exempt, err := s.StakePoolFeeExempt(ns, users[i])
+			if err != nil {
+				return err
+			}
+			if exempt != want {
+				t.Errorf("user %v exempt %v, want %v", users[i], exempt,
+					want)
+			}
+		}
+
+		// Only P2PKH and P2SH users may be exempted.
+		serializedPK, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b0" +
+			"7029bfcdb2dce28d959f2815b16f81798")
+		pk, err := hcutil.NewAddressSecpPubKey(serializedPK,
+			&chaincfg.TestNet2Params)
+		if err != nil {
+			return err
+		}
+		if s.SetStakePoolFeeExemption(ns, pk, true) == nil {
+			t.Error("exempted a pubkey user address")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// StakeRewardType describes the ticket spending transaction recorded by a
+// stake reward.
+type StakeRewardType byte
+
+// These constants describe the kinds of stake rewards.
+const (
+	// StakeRewardVote is a vote spending a wallet ticket.
+	StakeRewardVote StakeRewardType = iota
+
+	// StakeRewardRevocation is a revocation of a missed or expired wallet
+	// ticket.
+	StakeRewardRevocation
+)
+
+// StakeReward describes a mined vote or revocation of a ticket owned by the
+// wallet.
+type StakeReward struct {
+	Type    StakeRewardType
+	TxHash  chainhash.Hash
+	Ticket  chainhash.Hash
+	Height  int32
+	Time    time.Time
+	Subsidy hcutil.Amount // Stakebase of votes, zero for revocations
+
+	// Missed is set for revocations of tickets which were selected but
+	// failed to vote, rather than expiring.
+	Missed bool
+}
+
+// Stake rewards are indexed in the stakeRewardsBucketName bucket and keyed
+// as such:
+//
+//   [0:4]  Block height (4 bytes)
+//   [4:36] Vote or revocation transaction hash (32 bytes)
+//
+// The leading block height allows the index to be iterated in block order and
+// rolled back from a height without scanning unaffected entries.
+//
+// The value is serialized as such:
+//
+//   [0:1]   Reward type (1 byte)
+//   [1:2]   Flags (1 byte)
+//             0x01: Missed
+//   [2:34]  Ticket hash (32 bytes)
+//   [34:42] Block time (8 bytes)
+//   [42:50] Subsidy (8 bytes)
+
+const (
+	stakeRewardKeySize   = 4 + 32
+	stakeRewardValueSize = 1 + 1 + 32 + 8 + 8
+
+	stakeRewardFlagMissed = 1 << 0
+)
+
+func keyStakeReward(height int32, txHash *chainhash.Hash) []byte {
+	k := make([]byte, stakeRewardKeySize)
+	byteOrder.PutUint32(k, uint32(height))
+	copy(k[4:], txHash[:])
+	return k
+}
+
+func valueStakeReward(r *StakeReward) []byte {
+	v := make([]byte, stakeRewardValueSize)
+	v[0] = byte(r.Type)
+	if r.Missed {
+		v[1] |= stakeRewardFlagMissed
+	}
+	copy(v[2:34], r.Ticket[:])
+	byteOrder.PutUint64(v[34:42], uint64(r.Time.Unix()))
+	byteOrder.PutUint64(v[42:50], uint64(r.Subsidy))
+	return v
+}
+
+func readStakeReward(k, v []byte, r *StakeReward) error {
+	if len(k) != stakeRewardKeySize || len(v) != stakeRewardValueSize {
+		str := fmt.Sprintf("%s: malformed stake reward (key %d bytes, "+
+			"value %d bytes)", stakeRewardsBucketName, len(k), len(v))
+		return stakeStoreError(apperrors.ErrData, str, nil)
+	}
+	r.Height = int32(byteOrder.Uint32(k))
+	copy(r.TxHash[:], k[4:])
+	r.Type = StakeRewardType(v[0])
+	r.Missed = v[1]&stakeRewardFlagMissed != 0
+	copy(r.Ticket[:], v[2:34])
+	r.Time = time.Unix(int64(byteOrder.Uint64(v[34:42])), 0)
+	r.Subsidy = hcutil.Amount(byteOrder.Uint64(v[42:50]))
+	return nil
+}
+
+func putStakeReward(ns walletdb.ReadWriteBucket, r *StakeReward) error {
+	bucket := ns.NestedReadWriteBucket(stakeRewardsBucketName)
+	err := bucket.Put(keyStakeReward(r.Height, &r.TxHash), valueStakeReward(r))
+	if err != nil {
+		str := fmt.Sprintf("failed to store stake reward %v", &r.TxHash)
+		return stakeStoreError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// newStakeReward creates the stake reward for a vote or revocation mined at
+// height.  The boolean return is false if the transaction is neither.
+//
+// The wallet does not learn when a ticket was selected, so revocations are
+// classified as missed when they are mined before the ticket, mined at
+// ticketHeight, could have expired.  Revocations of tickets with an unknown
+// mined height (-1) are classified as missed.
+func newStakeReward(tx *wire.MsgTx, txHash *chainhash.Hash, height, ticketHeight int32,
+	blockTime time.Time, params *chaincfg.Params) (*StakeReward, bool) {
+
+	r := &StakeReward{
+		TxHash: *txHash,
+		Height: height,
+		Time:   blockTime,
+	}
+	switch stake.DetermineTxType(tx) {
+	case stake.TxTypeSSGen:
+		r.Type = StakeRewardVote
+		r.Ticket = tx.TxIn[1].PreviousOutPoint.Hash
+		r.Subsidy = hcutil.Amount(tx.TxIn[0].ValueIn)
+	case stake.TxTypeSSRtx:
+		r.Type = StakeRewardRevocation
+		r.Ticket = tx.TxIn[0].PreviousOutPoint.Hash
+		r.Missed = true
+		if ticketHeight >= 0 {
+			expiry := int64(ticketHeight) + int64(params.TicketMaturity) +
+				int64(params.TicketExpiry)
+			r.Missed = int64(height) <= expiry
+		}
+	default:
+		return nil, false
+	}
+	return r, true
+}
+
+// RecordStakeReward adds a mined vote or revocation of a wallet ticket to the
+// stake reward index.  Transactions which are neither are ignored.  The mined
+// height of the spent ticket, or -1 if unknown, classifies revocations as
+// missed or expired.
+func (s *StakeStore) RecordStakeReward(ns walletdb.ReadWriteBucket, rec *TxRecord,
+	block *BlockMeta, ticketHeight int32) error {
+
+	r, ok := newStakeReward(&rec.MsgTx, &rec.Hash, block.Height, ticketHeight,
+		block.Time, s.Params)
+	if !ok {
+		return nil
+	}
+	return putStakeReward(ns, r)
+}
+
+// RollbackStakeRewards removes all stake rewards recorded in blocks at height
+// onwards.
+func (s *StakeStore) RollbackStakeRewards(ns walletdb.ReadWriteBucket, height int32) error {
+	bucket := ns.NestedReadWriteBucket(stakeRewardsBucketName)
+
+	var keys [][]byte
+	seek := make([]byte, 4)
+	byteOrder.PutUint32(seek, uint32(height))
+	c := bucket.ReadCursor()
+	for k, _ := c.Seek(seek); k != nil; k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		err := bucket.Delete(k)
+		if err != nil {
+			str := "failed to remove stake reward"
+			return stakeStoreError(apperrors.ErrDatabase, str, err)
+		}
+	}
+	return nil
+}
+
+// ForEachStakeReward calls f with every recorded stake reward in increasing
+// block order.  Iteration stops early if f returns an error, and the error is
+// returned.
+func (s *StakeStore) ForEachStakeReward(ns walletdb.ReadBucket, f func(*StakeReward) error) error {
+	var r StakeReward
+	return ns.NestedReadBucket(stakeRewardsBucketName).ForEach(func(k, v []byte) error {
+		err := readStakeReward(k, v, &r)
+		if err != nil {
+			return err
+		}
+		return f(&r)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+func TestRevocationStakeRewardExpiry(t *testing.T) {
+	params := &chaincfg.TestNet2Params
+	addr, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), params,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToSSRtx(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticketHash := chainhash.Hash{1}
+	revocation := wire.NewMsgTx()
+	txIn := wire.NewTxIn(wire.NewOutPoint(&ticketHash, 0, wire.TxTreeStake), nil)
+	// The input block height is not the ticket's mined height and must
+	// not be used to determine expiry.
+	txIn.BlockHeight = 0
+	revocation.AddTxIn(txIn)
+	revocation.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	revocationHash := revocation.TxHash()
+
+	const ticketHeight = 1000
+	expiry := int32(ticketHeight + int32(params.TicketMaturity) +
+		int32(params.TicketExpiry))
+	tests := []struct {
+		name         string
+		height       int32
+		ticketHeight int32
+		missed       bool
+	}{
+		{"missed", expiry - 10, ticketHeight, true},
+		{"last block before expiry", expiry, ticketHeight, true},
+		{"expired", expiry + 1, ticketHeight, false},
+		{"unknown ticket height", expiry + 1, -1, true},
+	}
+	for _, test := range tests {
+		r, ok := newStakeReward(revocation, &revocationHash, test.height,
+			test.ticketHeight, time.Now(), params)
+		if !ok {
+			t.Fatalf("%s: revocation was not recognized", test.name)
+		}
+		if r.Type != StakeRewardRevocation || r.Ticket != ticketHash {
+			t.Errorf("%s: recorded type %v for ticket %v", test.name,
+				r.Type, &r.Ticket)
+		}
+		if r.Missed != test.missed {
+			t.Errorf("%s: missed %v, want %v", test.name, r.Missed,
+				test.missed)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// The ticket accounts bucket records the account which funded each ticket
+// purchase, so stake statistics may be reported per account.  Keys are ticket
+// hashes and values are serialized as such:
+//
+//   [0:4]  Funding account (4 bytes)
+
+func valueTicketAccount(account uint32) []byte {
+	v := make([]byte, 4)
+	byteOrder.PutUint32(v, account)
+	return v
+}
+
+// PutTicketAccount records the account which funded a ticket purchase.
+func (s *StakeStore) PutTicketAccount(ns walletdb.ReadWriteBucket, ticket *chainhash.Hash, account uint32) error {
+	err := ns.NestedReadWriteBucket(ticketAccountsBucketName).Put(ticket[:],
+		valueTicketAccount(account))
+	if err != nil {
+		str := fmt.Sprintf("failed to record funding account of ticket %v",
+			ticket)
+		return stakeStoreError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// TicketAccount returns the account which funded a ticket purchase.  The
+// boolean is false if no funding account was recorded for the ticket.
+func (s *StakeStore) TicketAccount(ns walletdb.ReadBucket, ticket *chainhash.Hash) (uint32, bool, error) {
+	v := ns.NestedReadBucket(ticketAccountsBucketName).Get(ticket[:])
+	if v == nil {
+		return 0, false, nil
+	}
+	if len(v) != 4 {
+		str := fmt.Sprintf("%s: short read for ticket %v",
+			ticketAccountsBucketName, ticket)
+		return 0, false, stakeStoreError(apperrors.ErrData, str, nil)
+	}
+	return byteOrder.Uint32(v), true, nil
+}
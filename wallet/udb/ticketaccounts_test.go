@@ -0,0 +1,66 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestTicketAccounts(t *testing.T) {
+	db, s, teardown := setupStakeStore(t)
+	defer teardown()
+
+	recorded := chainhash.Hash{1}
+	unrecorded := chainhash.Hash{2}
+	corrupt := chainhash.Hash{3}
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(stakeTestNamespaceKey)
+		// The bucket is created by the database upgrade.
+		b, err := ns.CreateBucket(ticketAccountsBucketName)
+		if err != nil {
+			return err
+		}
+		err = s.PutTicketAccount(ns, &recorded, 3)
+		if err != nil {
+			return err
+		}
+		return b.Put(corrupt[:], []byte{1, 2})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(stakeTestNamespaceKey)
+		account, ok, err := s.TicketAccount(ns, &recorded)
+		if err != nil {
+			return err
+		}
+		if !ok || account != 3 {
+			t.Errorf("recorded funding account (%d, %v), want (3, true)",
+				account, ok)
+		}
+		_, ok, err = s.TicketAccount(ns, &unrecorded)
+		if err != nil {
+			return err
+		}
+		if ok {
+			t.Error("found funding account of unrecorded ticket")
+		}
+		_, _, err = s.TicketAccount(ns, &corrupt)
+		if !apperrors.IsError(err, apperrors.ErrData) {
+			t.Errorf("reading short funding account returned %v, want "+
+				"ErrData", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -55,6 +55,28 @@ func setup() (db walletdb.DB, s *Store, teardown func(), err error) {
 	if err != nil {
 		return
 	}
+	// Buckets added by database upgrades are not created by createStore.
+	upgradeBuckets := [][]byte{
+		bucketTickets,
+		bucketAddrCredits,
+		bucketHistoryCheckpoints,
+		bucketPrunedTxs,
+		bucketTxDrafts,
+		bucketTxDraftInputs,
+		bucketIdempotencyKeys,
+		bucketAuditLog,
+		bucketAuditedTxs,
+		bucketWebhooks,
+		bucketBalanceSnapshots,
+		bucketSpendableConfs,
+		bucketReplacedTxs,
+	}
+	for _, b := range upgradeBuckets {
+		_, err = ns.CreateBucket(b)
+		if err != nil {
+			return
+		}
+	}
 	acctLookup := func(walletdb.ReadBucket, hcutil.Address) (uint32, error) { return 0, nil }
 	s = &Store{chainParams: &chaincfg.TestNet2Params, acctLookupFunc: acctLookup}
 	return
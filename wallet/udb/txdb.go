@@ -145,6 +145,20 @@ var (
 	bucketMultisigUsp             = []byte("mu")
 	bucketStakeInvalidatedCredits = []byte("ic")
 	bucketStakeInvalidatedDebits  = []byte("id")
+	bucketAddrCredits             = []byte("ac")
+	bucketHistoryCheckpoints      = []byte("hc")
+	bucketPrunedTxs               = []byte("pt")
+	bucketTxDrafts                = []byte("dr")
+	bucketTxDraftInputs           = []byte("di")
+	bucketIdempotencyKeys         = []byte("ik")
+	bucketAuditLog                = []byte("al")
+	bucketAuditedTxs              = []byte("at")
+	bucketWebhooks                = []byte("wh")
+	bucketBalanceSnapshots        = []byte("bs")
+	bucketSpendableConfs          = []byte("cf")
+	bucketReplacedTxs             = []byte("rp")
+	bucketPendingReorgBlocks      = []byte("pr")
+	bucketOmniPending             = []byte("op")
 )
 
 // Root (namespace) bucket keys
@@ -154,6 +168,12 @@ var (
 	rootMinedBalance = []byte("bal")
 	rootTipBlock     = []byte("tip")
 	rootLastTxsBlock = []byte("lasttxsblock")
+
+	rootSyncCheckpoint = []byte("synccheckpoint")
+	rootBirthday       = []byte("birthday")
+	rootWebhookHeight  = []byte("webhookheight")
+	rootWebhookSeq     = []byte("webhookseq")
+	rootPendingReorg   = []byte("pendingreorg")
 )
 
 // The root bucket's mined balance k/v pair records the total balance for all
@@ -182,6 +202,83 @@ func putMinedBalance(ns walletdb.ReadWriteBucket, amt hcutil.Amount) error {
 	return nil
 }
 
+// The root bucket's sync checkpoint k/v pair records the progress of an
+// unfinished initial sync.  The key is absent when no sync is in progress.
+// The value is serialized as such:
+//
+//   [0:4]   Block height (4 bytes)
+//   [4:36]  Block hash (32 bytes)
+//   [36:37] Accounts discovered (1 byte, 0 or 1)
+//   [37:41] Discovered branch count N (4 bytes)
+//   [41:]   N discovered branches, each containing:
+//             [0:4]  Account (4 bytes)
+//             [4:8]  Branch (4 bytes)
+//             [8:12] Last used child index (4 bytes)
+
+func fetchSyncCheckpoint(ns walletdb.ReadBucket) (*SyncCheckpoint, error) {
+	v := ns.Get(rootSyncCheckpoint)
+	if v == nil {
+		return nil, nil
+	}
+	if len(v) < 41 {
+		str := fmt.Sprintf("sync checkpoint: short read (expected at least "+
+			"41 bytes, read %v)", len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	n := byteOrder.Uint32(v[37:41])
+	if uint32(len(v)-41) != n*12 {
+		str := fmt.Sprintf("sync checkpoint: expected %v bytes for %v "+
+			"branches, read %v", n*12, n, len(v)-41)
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	cp := &SyncCheckpoint{
+		Height:             int32(byteOrder.Uint32(v[0:4])),
+		AccountsDiscovered: v[36] != 0,
+		Branches:           make([]DiscoveredBranch, n),
+	}
+	copy(cp.Hash[:], v[4:36])
+	for i := range cp.Branches {
+		off := 41 + i*12
+		cp.Branches[i] = DiscoveredBranch{
+			Account:  byteOrder.Uint32(v[off : off+4]),
+			Branch:   byteOrder.Uint32(v[off+4 : off+8]),
+			LastUsed: byteOrder.Uint32(v[off+8 : off+12]),
+		}
+	}
+	return cp, nil
+}
+
+func putSyncCheckpoint(ns walletdb.ReadWriteBucket, cp *SyncCheckpoint) error {
+	v := make([]byte, 41+len(cp.Branches)*12)
+	byteOrder.PutUint32(v[0:4], uint32(cp.Height))
+	copy(v[4:36], cp.Hash[:])
+	if cp.AccountsDiscovered {
+		v[36] = 1
+	}
+	byteOrder.PutUint32(v[37:41], uint32(len(cp.Branches)))
+	for i, b := range cp.Branches {
+		off := 41 + i*12
+		byteOrder.PutUint32(v[off:off+4], b.Account)
+		byteOrder.PutUint32(v[off+4:off+8], b.Branch)
+		byteOrder.PutUint32(v[off+8:off+12], b.LastUsed)
+	}
+	err := ns.Put(rootSyncCheckpoint, v)
+	if err != nil {
+		str := "failed to put sync checkpoint"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func deleteSyncCheckpoint(ns walletdb.ReadWriteBucket) error {
+	err := ns.Delete(rootSyncCheckpoint)
+	if err != nil {
+		str := "failed to delete sync checkpoint"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // Several data structures are given canonical serialization formats as either
 // keys or values.  These common formats allow keys and values to be reused
 // across different buckets.
@@ -272,6 +369,31 @@ func appendRawBlockRecord(v []byte, txHash *chainhash.Hash) ([]byte, error) {
 	return newv, nil
 }
 
+// removeRawBlockRecordTxs returns a new block record value without the
+// transaction hashes in remove and with a decremented number of transactions
+// for each removed hash.
+func removeRawBlockRecordTxs(v []byte, remove map[chainhash.Hash]struct{}) ([]byte, error) {
+	if len(v) < 47 {
+		str := fmt.Sprintf("%s: removeRawBlockRecordTxs short read "+
+			"(expected %d bytes, read %d)", bucketBlocks, 47, len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	n := byteOrder.Uint32(v[43:47])
+	newv := make([]byte, 47, len(v))
+	copy(newv, v[:47])
+	for off := 47; off+chainhash.HashSize <= len(v); off += chainhash.HashSize {
+		var hash chainhash.Hash
+		copy(hash[:], v[off:])
+		if _, ok := remove[hash]; ok {
+			n--
+			continue
+		}
+		newv = append(newv, hash[:]...)
+	}
+	byteOrder.PutUint32(newv[43:47], n)
+	return newv, nil
+}
+
 func putRawBlockRecord(ns walletdb.ReadWriteBucket, k, v []byte) error {
 	err := ns.NestedReadWriteBucket(bucketBlocks).Put(k, v)
 	if err != nil {
@@ -1721,6 +1843,163 @@ func existsTxScript(ns walletdb.ReadBucket, hash []byte) []byte {
 	return v
 }
 
+// The address credits bucket indexes the transactions with credits paying to
+// each address.  Keys are the encoded address, prefixed by its length, followed
+// by the transaction hash:
+//
+//   [0]          Length of encoded address (uint8)
+//   [1:n+1]      Encoded address (n bytes)
+//   [n+1:n+33]   Transaction hash (32 bytes)
+//
+// Values are empty.  Since the hash of a transaction does not change when it is
+// mined or rolled back to the unmined bucket, entries are only removed when the
+// transaction is removed from the store.  The credits themselves must be looked
+// up from the transaction's record.
+
+func keyAddrCreditPrefix(addr string) []byte {
+	k := make([]byte, 1+len(addr))
+	k[0] = byte(len(addr))
+	copy(k[1:], addr)
+	return k
+}
+
+func keyAddrCredit(addr string, txHash *chainhash.Hash) []byte {
+	k := make([]byte, 1+len(addr)+32)
+	k[0] = byte(len(addr))
+	copy(k[1:], addr)
+	copy(k[1+len(addr):], txHash[:])
+	return k
+}
+
+func putAddrCredit(ns walletdb.ReadWriteBucket, addr string, txHash *chainhash.Hash) error {
+	k := keyAddrCredit(addr, txHash)
+	err := ns.NestedReadWriteBucket(bucketAddrCredits).Put(k, nil)
+	if err != nil {
+		str := "failed to put address credit"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func deleteAddrCredit(ns walletdb.ReadWriteBucket, addr string, txHash *chainhash.Hash) error {
+	k := keyAddrCredit(addr, txHash)
+	err := ns.NestedReadWriteBucket(bucketAddrCredits).Delete(k)
+	if err != nil {
+		str := "failed to delete address credit"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// addrCreditTxHashes returns the hashes of every transaction indexed with a
+// credit paying to the encoded address.
+func addrCreditTxHashes(ns walletdb.ReadBucket, addr string) []chainhash.Hash {
+	prefix := keyAddrCreditPrefix(addr)
+	var hashes []chainhash.Hash
+	c := ns.NestedReadBucket(bucketAddrCredits).ReadCursor()
+	for k, _ := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		if len(k) != len(prefix)+32 {
+			continue
+		}
+		var hash chainhash.Hash
+		copy(hash[:], k[len(prefix):])
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// The history checkpoints bucket summarizes the transaction records removed by
+// pruning the transaction history.  Checkpoints are keyed by the block height
+// at and below which transactions were pruned:
+//
+//   [0:4]   Block height (4 bytes)
+//
+// The value is serialized as such:
+//
+//   [0:4]   Number of pruned transactions (4 bytes)
+//   [4:12]  Total amount of pruned credits (8 bytes)
+//   [12:20] Total amount of pruned debits (8 bytes)
+//
+// Mined credits and debits at or below the greatest checkpoint height may
+// reference the debits and credits of pruned transactions.
+
+func keyHistoryCheckpoint(height int32) []byte {
+	k := make([]byte, 4)
+	byteOrder.PutUint32(k, uint32(height))
+	return k
+}
+
+func valueHistoryCheckpoint(cp *HistoryCheckpoint) []byte {
+	v := make([]byte, 20)
+	byteOrder.PutUint32(v, cp.Transactions)
+	byteOrder.PutUint64(v[4:12], uint64(cp.Credits))
+	byteOrder.PutUint64(v[12:20], uint64(cp.Debits))
+	return v
+}
+
+func readRawHistoryCheckpoint(k, v []byte, cp *HistoryCheckpoint) error {
+	if len(k) < 4 || len(v) < 20 {
+		str := fmt.Sprintf("%s: short read for history checkpoint",
+			bucketHistoryCheckpoints)
+		return storeError(apperrors.ErrData, str, nil)
+	}
+	cp.Height = int32(byteOrder.Uint32(k))
+	cp.Transactions = byteOrder.Uint32(v)
+	cp.Credits = hcutil.Amount(byteOrder.Uint64(v[4:12]))
+	cp.Debits = hcutil.Amount(byteOrder.Uint64(v[12:20]))
+	return nil
+}
+
+func putHistoryCheckpoint(ns walletdb.ReadWriteBucket, cp *HistoryCheckpoint) error {
+	k := keyHistoryCheckpoint(cp.Height)
+	v := valueHistoryCheckpoint(cp)
+	err := ns.NestedReadWriteBucket(bucketHistoryCheckpoints).Put(k, v)
+	if err != nil {
+		str := "failed to put history checkpoint"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func fetchHistoryCheckpoint(ns walletdb.ReadBucket, height int32) (*HistoryCheckpoint, error) {
+	k := keyHistoryCheckpoint(height)
+	v := ns.NestedReadBucket(bucketHistoryCheckpoints).Get(k)
+	if v == nil {
+		return nil, nil
+	}
+	cp := new(HistoryCheckpoint)
+	err := readRawHistoryCheckpoint(k, v, cp)
+	return cp, err
+}
+
+// fetchPrunedHeight returns the greatest height at and below which
+// transactions have been pruned, or -1 if the history has never been pruned.
+func fetchPrunedHeight(ns walletdb.ReadBucket) int32 {
+	k, _ := ns.NestedReadBucket(bucketHistoryCheckpoints).ReadCursor().Last()
+	if len(k) < 4 {
+		return -1
+	}
+	return int32(byteOrder.Uint32(k))
+}
+
+// The pruned transactions bucket records the transaction records removed by
+// pruning the transaction history so they are not inserted again when their
+// blocks are rescanned.  Keys use the same format as the transaction records
+// bucket.  Values are empty.
+
+func existsPrunedTx(ns walletdb.ReadBucket, k []byte) bool {
+	return ns.NestedReadBucket(bucketPrunedTxs).Get(k) != nil
+}
+
+func putPrunedTx(ns walletdb.ReadWriteBucket, k []byte) error {
+	err := ns.NestedReadWriteBucket(bucketPrunedTxs).Put(k, []byte{})
+	if err != nil {
+		str := "failed to put pruned transaction"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // The multisig bucket stores utxos that are P2SH output scripts to the user.
 // These are handled separately and less efficiently than the more typical
 // P2PKH types.
@@ -0,0 +1,189 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// TxDraft is an unsigned transaction authored by the wallet whose inputs are
+// reserved until the draft is committed or canceled.  Reserved outputs are
+// never selected as inputs of other transactions authored by the wallet.
+//
+// Signatures are not committed to by the transaction hash, so the hash of the
+// unsigned transaction, which identifies the draft, is also the hash of the
+// transaction once it is signed.
+type TxDraft struct {
+	Hash    chainhash.Hash
+	Tx      wire.MsgTx
+	Account uint32
+	Created time.Time
+}
+
+// The transaction drafts bucket records each draft keyed by the draft
+// transaction hash.  The value is serialized as such:
+//
+//   [0:8]   Creation time (8 bytes)
+//   [8:12]  Account (4 bytes)
+//   [12:]   Serialized unsigned transaction
+//
+// The transaction draft inputs bucket records each outpoint reserved by a
+// draft keyed by the canonical outpoint.  The value is the hash of the draft.
+
+func valueTxDraft(d *TxDraft) ([]byte, error) {
+	v := make([]byte, 12, 12+d.Tx.SerializeSize())
+	byteOrder.PutUint64(v, uint64(d.Created.Unix()))
+	byteOrder.PutUint32(v[8:12], d.Account)
+	buf := bytes.NewBuffer(v)
+	err := d.Tx.Serialize(buf)
+	if err != nil {
+		str := "failed to serialize transaction draft"
+		return nil, storeError(apperrors.ErrInput, str, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func readRawTxDraft(k, v []byte, d *TxDraft) error {
+	if len(k) < 32 || len(v) < 12 {
+		str := fmt.Sprintf("%s: short read for transaction draft",
+			bucketTxDrafts)
+		return storeError(apperrors.ErrData, str, nil)
+	}
+	copy(d.Hash[:], k)
+	d.Created = time.Unix(int64(byteOrder.Uint64(v)), 0)
+	d.Account = byteOrder.Uint32(v[8:12])
+	err := d.Tx.Deserialize(bytes.NewReader(v[12:]))
+	if err != nil {
+		str := fmt.Sprintf("%s: failed to deserialize transaction draft %v",
+			bucketTxDrafts, &d.Hash)
+		return storeError(apperrors.ErrData, str, err)
+	}
+	return nil
+}
+
+func existsRawTxDraftInput(ns walletdb.ReadBucket, k []byte) (v []byte) {
+	return ns.NestedReadBucket(bucketTxDraftInputs).Get(k)
+}
+
+// PutTxDraft records a transaction draft and reserves each of its inputs.  An
+// error with code ErrDuplicate is returned if any input is already reserved by
+// another draft.
+func (s *Store) PutTxDraft(ns walletdb.ReadWriteBucket, d *TxDraft) error {
+	d.Hash = d.Tx.TxHash()
+	if ns.NestedReadBucket(bucketTxDrafts).Get(d.Hash[:]) != nil {
+		str := fmt.Sprintf("transaction draft %v already exists", &d.Hash)
+		return storeError(apperrors.ErrDuplicate, str, nil)
+	}
+
+	inputs := ns.NestedReadWriteBucket(bucketTxDraftInputs)
+	for _, in := range d.Tx.TxIn {
+		op := &in.PreviousOutPoint
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		if v := inputs.Get(k); v != nil {
+			var draft chainhash.Hash
+			copy(draft[:], v)
+			str := fmt.Sprintf("outpoint %v is reserved by transaction "+
+				"draft %v", op, &draft)
+			return storeError(apperrors.ErrDuplicate, str, nil)
+		}
+		err := inputs.Put(k, d.Hash[:])
+		if err != nil {
+			str := "failed to put transaction draft input"
+			return storeError(apperrors.ErrDatabase, str, err)
+		}
+	}
+
+	v, err := valueTxDraft(d)
+	if err != nil {
+		return err
+	}
+	err = ns.NestedReadWriteBucket(bucketTxDrafts).Put(d.Hash[:], v)
+	if err != nil {
+		str := "failed to put transaction draft"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// TxDraft returns the transaction draft identified by hash.  An error with
+// code ErrValueNoExists is returned if there is no such draft.
+func (s *Store) TxDraft(ns walletdb.ReadBucket, hash *chainhash.Hash) (*TxDraft, error) {
+	v := ns.NestedReadBucket(bucketTxDrafts).Get(hash[:])
+	if v == nil {
+		str := fmt.Sprintf("no transaction draft %v", hash)
+		return nil, storeError(apperrors.ErrValueNoExists, str, nil)
+	}
+	d := new(TxDraft)
+	err := readRawTxDraft(hash[:], v, d)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// TxDrafts returns every recorded transaction draft.
+func (s *Store) TxDrafts(ns walletdb.ReadBucket) ([]*TxDraft, error) {
+	var drafts []*TxDraft
+	err := ns.NestedReadBucket(bucketTxDrafts).ForEach(func(k, v []byte) error {
+		d := new(TxDraft)
+		err := readRawTxDraft(k, v, d)
+		if err != nil {
+			return err
+		}
+		drafts = append(drafts, d)
+		return nil
+	})
+	return drafts, err
+}
+
+// DeleteTxDraft removes the transaction draft identified by hash and releases
+// the reservations of its inputs.  An error with code ErrValueNoExists is
+// returned if there is no such draft.
+func (s *Store) DeleteTxDraft(ns walletdb.ReadWriteBucket, hash *chainhash.Hash) error {
+	d, err := s.TxDraft(ns, hash)
+	if err != nil {
+		return err
+	}
+
+	inputs := ns.NestedReadWriteBucket(bucketTxDraftInputs)
+	for _, in := range d.Tx.TxIn {
+		op := &in.PreviousOutPoint
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		if !bytes.Equal(inputs.Get(k), hash[:]) {
+			continue
+		}
+		err := inputs.Delete(k)
+		if err != nil {
+			str := "failed to delete transaction draft input"
+			return storeError(apperrors.ErrDatabase, str, err)
+		}
+	}
+
+	err = ns.NestedReadWriteBucket(bucketTxDrafts).Delete(hash[:])
+	if err != nil {
+		str := "failed to delete transaction draft"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// TxDraftReserving returns the hash of the transaction draft reserving op, or
+// nil if op is not reserved by any draft.
+func (s *Store) TxDraftReserving(ns walletdb.ReadBucket, op *wire.OutPoint) *chainhash.Hash {
+	v := existsRawTxDraftInput(ns, canonicalOutPoint(&op.Hash, op.Index))
+	if len(v) != 32 {
+		return nil
+	}
+	var hash chainhash.Hash
+	copy(hash[:], v)
+	return &hash
+}
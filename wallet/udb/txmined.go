@@ -186,13 +186,17 @@ func (s *Store) MainChainTip(ns walletdb.ReadBucket) (chainhash.Hash, int32) {
 // the existing tip block.
 //
 // If the block is already inserted and part of the main chain, an error with
-// code ErrDuplicate is returned.
+// code ErrDuplicate is returned.  If the block hash is not the hash of the
+// header, an error with code ErrChainFault is returned.
 func (s *Store) ExtendMainChain(ns walletdb.ReadWriteBucket, header *BlockHeaderData) error {
 	height := header.SerializedHeader.Height()
 	if height < 1 {
 		const str = "can not extend main chain with genesis block 0"
 		return storeError(apperrors.ErrInput, str, nil)
 	}
+	if _, err := checkHeaderHash(header); err != nil {
+		return err
+	}
 
 	headerBucket := ns.NestedReadWriteBucket(bucketHeaders)
 
@@ -657,6 +661,10 @@ func stakeInvalidate(ns walletdb.ReadWriteBucket, height int32) error {
 // After inserting headers, if the existing recorded tip block is behind the
 // last main chain block header that was inserted, a chain switch occurs and the
 // new tip block is recorded.
+//
+// Headers which do not form a chain connecting to the recorded parent of the
+// first header are not inserted, and an error with code ErrChainFault is
+// returned.
 func (s *Store) InsertMainChainHeaders(ns walletdb.ReadWriteBucket, addrmgrNs walletdb.ReadBucket,
 	headers []BlockHeaderData) error {
 
@@ -664,6 +672,11 @@ func (s *Store) InsertMainChainHeaders(ns walletdb.ReadWriteBucket, addrmgrNs wa
 		return nil
 	}
 
+	err := checkHeaderLinkage(ns, headers)
+	if err != nil {
+		return err
+	}
+
 	// If the first header is not yet saved, make sure the parent is.
 	if existsBlockHeader(ns, keyBlockHeader(&headers[0].BlockHash)) == nil {
 		parentHash := extractBlockHeaderParentHash(headers[0].SerializedHeader[:])
@@ -1106,6 +1119,13 @@ func (s *Store) InsertMinedTx(ns walletdb.ReadWriteBucket, addrmgrNs walletdb.Re
 
 	// Add a debit record for each unspent credit spent by this tx.
 	block := blockMetaFromHeader(blockHash, blockHeader)
+
+	// Transactions removed by pruning the history are not inserted again
+	// when their block is rescanned.
+	if existsPrunedTx(ns, keyTxRecord(&rec.Hash, &block.Block)) {
+		return nil
+	}
+
 	spender := indexedIncidence{
 		incidence: incidence{
 			txHash: rec.Hash,
@@ -1263,6 +1283,12 @@ func (s *Store) AddCredit(ns walletdb.ReadWriteBucket, rec *TxRecord, block *Blo
 		return storeError(apperrors.ErrInput, str, nil)
 	}
 
+	// The outputs of pruned transactions were all spent and must not be
+	// added back as unspent credits.
+	if block != nil && existsPrunedTx(ns, keyTxRecord(&rec.Hash, &block.Block)) {
+		return nil
+	}
+
 	invalidated := false
 	if rec.TxType == stake.TxTypeRegular && block != nil {
 		blockHeader := existsBlockHeader(ns, block.Hash[:])
@@ -1297,11 +1323,53 @@ func (s *Store) AddCredit(ns walletdb.ReadWriteBucket, rec *TxRecord, block *Blo
 			const str = "failed to write invalidated credit"
 			return storeError(apperrors.ErrDatabase, str, err)
 		}
+		return indexCreditAddrs(ns, &rec.Hash, rec.MsgTx.TxOut[index], s.chainParams)
+	}
+
+	added, err := s.addCredit(ns, rec, block, index, change, account)
+	if err != nil || !added {
+		return err
+	}
+	return indexCreditAddrs(ns, &rec.Hash, rec.MsgTx.TxOut[index], s.chainParams)
+}
+
+// indexCreditAddrs records the transaction in the address credits index for
+// each address paid by the output of a credit.  Outputs with non-standard
+// scripts are not indexed.
+func indexCreditAddrs(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash, txOut *wire.TxOut,
+	params *chaincfg.Params) error {
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.Version,
+		txOut.PkScript, params)
+	if err != nil {
 		return nil
 	}
+	for _, a := range addrs {
+		err := putAddrCredit(ns, a.EncodeAddress(), txHash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	_, err := s.addCredit(ns, rec, block, index, change, account)
-	return err
+// unindexTxAddrs removes a transaction which is being removed from the store
+// from the address credits index.
+func (s *Store) unindexTxAddrs(ns walletdb.ReadWriteBucket, tx *wire.MsgTx, txHash *chainhash.Hash) error {
+	for _, txOut := range tx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.Version,
+			txOut.PkScript, s.chainParams)
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			err := deleteAddrCredit(ns, a.EncodeAddress(), txHash)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // getP2PKHOpCode returns opNonstake for non-stake transactions, or
@@ -1715,6 +1783,11 @@ func (s *Store) rollback(ns walletdb.ReadWriteBucket, addrmgrNs walletdb.ReadBuc
 					}
 				}
 
+				err = s.unindexTxAddrs(ns, &rec.MsgTx, &rec.Hash)
+				if err != nil {
+					return err
+				}
+
 				continue
 			}
 
@@ -2166,6 +2239,73 @@ func (s *Store) unspentOutputs(ns walletdb.ReadBucket) ([]*Credit, error) {
 	return unspent, nil
 }
 
+// ForEachUnspentOutput calls f with each unspent output following the outpoint
+// after, or with every unspent output when after is nil, until f returns false
+// or an error.  Outputs are visited in the order of their canonical outpoint
+// keys, merging mined and unmined outputs, so an output keeps its position in
+// the order when it is mined or its block is reorganized out.  Outputs spent by
+// unmined transactions are skipped.
+func (s *Store) ForEachUnspentOutput(ns walletdb.ReadBucket, after *wire.OutPoint,
+	f func(*Credit) (bool, error)) error {
+
+	minedCursor := ns.NestedReadBucket(bucketUnspent).ReadCursor()
+	unminedCursor := ns.NestedReadBucket(bucketUnminedCredits).ReadCursor()
+	var minedK, minedV, unminedK []byte
+	if after == nil {
+		minedK, minedV = minedCursor.First()
+		unminedK, _ = unminedCursor.First()
+	} else {
+		seek := canonicalOutPoint(&after.Hash, after.Index)
+		minedK, minedV = minedCursor.Seek(seek)
+		if bytes.Equal(minedK, seek) {
+			minedK, minedV = minedCursor.Next()
+		}
+		unminedK, _ = unminedCursor.Seek(seek)
+		if bytes.Equal(unminedK, seek) {
+			unminedK, _ = unminedCursor.Next()
+		}
+	}
+
+	var op wire.OutPoint
+	var block Block
+	for minedK != nil || unminedK != nil {
+		// Visit the lower key of the two buckets.
+		k, mined := unminedK, false
+		if unminedK == nil || (minedK != nil && bytes.Compare(minedK, unminedK) < 0) {
+			k, mined = minedK, true
+		}
+		err := readCanonicalOutPoint(k, &op)
+		if err != nil {
+			return err
+		}
+		var blockPtr *Block
+		if mined {
+			err = readUnspentBlock(minedV, &block)
+			if err != nil {
+				return err
+			}
+			blockPtr = &block
+			minedK, minedV = minedCursor.Next()
+		} else {
+			unminedK, _ = unminedCursor.Next()
+		}
+
+		if existsRawUnminedInput(ns, k) != nil {
+			// Output is spent by an unmined transaction.
+			continue
+		}
+		cred, err := s.outputCreditInfo(ns, op, blockPtr)
+		if err != nil {
+			return err
+		}
+		more, err := f(cred)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
+
 // UnspentOutpoints returns all unspent received transaction outpoints.
 // The order is undefined.
 func (s *Store) UnspentOutpoints(ns walletdb.ReadBucket) ([]wire.OutPoint, error) {
@@ -2831,6 +2971,18 @@ func (s *Store) unspentOutputsForAmount(ns, addrmgrNs walletdb.ReadBucket, neede
 	var unspent []*Credit
 	found := hcutil.Amount(0)
 
+	// The spendable confirmations of the account apply when outputs of a
+	// single account are selected.
+	coinbaseMaturity := int32(s.chainParams.CoinbaseMaturity)
+	if !all {
+		confs, err := fetchSpendableConfs(ns, account)
+		if err != nil {
+			return nil, err
+		}
+		coinbaseMaturity = confs.CoinbaseMaturity(s.chainParams)
+		minConf = confs.MinConf(minConf)
+	}
+
 	err := ns.NestedReadBucket(bucketUnspent).ForEach(func(k, v []byte) error {
 		if found >= needed {
 			return errForEachBreakout
@@ -2894,14 +3046,12 @@ func (s *Store) unspentOutputsForAmount(ns, addrmgrNs walletdb.ReadBucket, neede
 
 		// Skip outputs that are not mature.
 		if opcode == opNonstake && fetchRawCreditIsCoinbase(cVal) {
-			if !confirmed(int32(s.chainParams.CoinbaseMaturity), txHeight,
-				syncHeight) {
+			if !confirmed(coinbaseMaturity, txHeight, syncHeight) {
 				return nil
 			}
 		}
 		if opcode == txscript.OP_SSGEN || opcode == txscript.OP_SSRTX {
-			if !confirmed(int32(s.chainParams.CoinbaseMaturity), txHeight,
-				syncHeight) {
+			if !confirmed(coinbaseMaturity, txHeight, syncHeight) {
 				return nil
 			}
 		}
@@ -3074,10 +3224,24 @@ func (s *InputSource) SelectInputs(target hcutil.Amount, fromAddress string) (hc
 	return s.source(target, fromAddress)
 }
 
+// pkScriptPaysAddress returns whether an output script pays only to the
+// encoded address addr.
+func (s *Store) pkScriptPaysAddress(pkScript []byte, addr string) bool {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+		txscript.DefaultScriptVersion, pkScript, s.chainParams)
+	return err == nil && len(addrs) == 1 && addrs[0].EncodeAddress() == addr
+}
+
 // MakeInputSource creates an InputSource to redeem unspent outputs from an
-// account.  The minConf and syncHeight parameters are used to filter outputs
-// based on some spendable policy.
+// account.  The minConf and syncHeight parameters, along with the spendable
+// confirmations of the account, are used to filter outputs based on some
+// spendable policy.  When the input source is called with a sending address,
+// only outputs paying to that address are redeemed.
 func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint32, minConf, syncHeight int32) InputSource {
+	confs, confsErr := fetchSpendableConfs(ns, account)
+	coinbaseMaturity := confs.CoinbaseMaturity(s.chainParams)
+	minConf = confs.MinConf(minConf)
+
 	// Cursors to iterate over the (mined) unspent and unmined credit
 	// buckets.  These are closed over by the returned input source and
 	// reused across multiple calls.
@@ -3099,6 +3263,9 @@ func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint3
 	)
 
 	f := func(target hcutil.Amount, fromAddress string) (hcutil.Amount, []*wire.TxIn, [][]byte, error) {
+		if confsErr != nil {
+			return 0, nil, nil, confsErr
+		}
 		for currentTotal < target || target == 0 {
 			var k, v []byte
 			if bucketUnspentCursor == nil {
@@ -3116,6 +3283,10 @@ func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint3
 				// Skip to next unmined credit.
 				continue
 			}
+			if existsRawTxDraftInput(ns, k) != nil {
+				// Output is reserved by a transaction draft.
+				continue
+			}
 
 			cKey := make([]byte, 72)
 			copy(cKey[0:32], k[0:32])   // Tx hash
@@ -3170,14 +3341,12 @@ func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint3
 
 			// Skip outputs that are not mature.
 			if opcode == opNonstake && fetchRawCreditIsCoinbase(cVal) {
-				if !confirmed(int32(s.chainParams.CoinbaseMaturity), txHeight,
-					syncHeight) {
+				if !confirmed(coinbaseMaturity, txHeight, syncHeight) {
 					continue
 				}
 			}
 			if opcode == txscript.OP_SSGEN || opcode == txscript.OP_SSRTX {
-				if !confirmed(int32(s.chainParams.CoinbaseMaturity), txHeight,
-					syncHeight) {
+				if !confirmed(coinbaseMaturity, txHeight, syncHeight) {
 					continue
 				}
 			}
@@ -3202,28 +3371,17 @@ func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint3
 			}
 			op.Tree = tree
 
-			input := wire.NewTxIn(&op, nil)
-
-			var pubkeys []string
-			_, pubkeyAddrs, _, err := txscript.ExtractPkScriptAddrs(
-				txscript.DefaultScriptVersion, pkScript,
-				s.chainParams)
-
-			for _, pka := range pubkeyAddrs {
-				pubkeys = append(pubkeys, pka.String())
+			// Only outputs paying to the sending address may be
+			// redeemed when one is set.
+			if fromAddress != "" && !s.pkScriptPaysAddress(pkScript, fromAddress) {
+				continue
 			}
 
-			if len(fromAddress) >0 {//only fromAddress can pass
-				if len(pubkeys) == 1 && pubkeys[0] == fromAddress{
-					currentTotal += amt
-					currentInputs = append(currentInputs, input)
-					currentScripts = append(currentScripts, pkScript)
-				}
-			}else{
-				currentTotal += amt
-				currentInputs = append(currentInputs, input)
-				currentScripts = append(currentScripts, pkScript)
-			}
+			input := wire.NewTxIn(&op, nil)
+
+			currentTotal += amt
+			currentInputs = append(currentInputs, input)
+			currentScripts = append(currentScripts, pkScript)
 		}
 
 		// Return the current results if the target amount was reached
@@ -3253,6 +3411,11 @@ func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint3
 				continue
 			}
 
+			// Skip outputs reserved by a transaction draft.
+			if existsRawTxDraftInput(ns, k) != nil {
+				continue
+			}
+
 			// Check the account first.
 			pkScript, err := s.fastCreditPkScriptLookup(ns, nil, k)
 			if err != nil {
@@ -3265,6 +3428,9 @@ func (s *Store) MakeInputSource(ns, addrmgrNs walletdb.ReadBucket, account uint3
 			if account != thisAcct {
 				continue
 			}
+			if fromAddress != "" && !s.pkScriptPaysAddress(pkScript, fromAddress) {
+				continue
+			}
 
 			amt, err := fetchRawUnminedCreditAmount(v)
 			if err != nil {
@@ -3318,6 +3484,7 @@ func (s *Store) balanceFullScan(ns, addrmgrNs walletdb.ReadBucket, minConf int32
 	syncHeight int32) (map[uint32]*Balances, error) {
 
 	accountBalances := make(map[uint32]*Balances)
+	confsCache := newSpendableConfsCache(ns)
 	err := ns.NestedReadBucket(bucketUnspent).ForEach(func(k, v []byte) error {
 		if existsRawUnminedInput(ns, k) != nil {
 			// Output is spent by an unmined transaction.
@@ -3366,12 +3533,18 @@ func (s *Store) balanceFullScan(ns, addrmgrNs walletdb.ReadBucket, minConf int32
 			ab.Total += utxoAmt
 		}
 
+		confs, err := confsCache.account(thisAcct)
+		if err != nil {
+			return err
+		}
+		coinbaseMaturity := confs.CoinbaseMaturity(s.chainParams)
+
 		switch opcode {
 		case opNonstake:
-			isConfirmed := confirmed(minConf, height, syncHeight)
+			isConfirmed := confirmed(confs.MinConf(minConf), height, syncHeight)
 			creditFromCoinbase := fetchRawCreditIsCoinbase(cVal)
 			matureCoinbase := (creditFromCoinbase &&
-				confirmed(int32(s.chainParams.CoinbaseMaturity),
+				confirmed(coinbaseMaturity,
 					height,
 					syncHeight))
 
@@ -3464,8 +3637,7 @@ func (s *Store) balanceFullScan(ns, addrmgrNs walletdb.ReadBucket, minConf int32
 		case txscript.OP_SSGEN:
 			fallthrough
 		case txscript.OP_SSRTX:
-			if confirmed(int32(s.chainParams.CoinbaseMaturity),
-				height, syncHeight) {
+			if confirmed(coinbaseMaturity, height, syncHeight) {
 				ab.Spendable += utxoAmt
 			} else {
 				ab.ImmatureStakeGeneration += utxoAmt
@@ -3525,9 +3697,14 @@ func (s *Store) balanceFullScan(ns, addrmgrNs walletdb.ReadBucket, minConf int32
 		// Skip ticket outputs, as only SSGen can spend these.
 		opcode := fetchRawUnminedCreditTagOpcode(v)
 
+		confs, err := confsCache.account(thisAcct)
+		if err != nil {
+			return err
+		}
+
 		switch opcode {
 		case opNonstake:
-			if minConf == 0 {
+			if confs.MinConf(minConf) == 0 {
 				ab.Spendable += utxoAmt
 			} else if !fetchRawCreditIsCoinbase(v) {
 				ab.Unconfirmed += utxoAmt
@@ -3766,6 +3943,57 @@ func (s *Store) UpdateProcessedTxsBlockMarker(dbtx walletdb.ReadWriteTx, hash *c
 	return nil
 }
 
+// DiscoveredBranch describes the result of address discovery for a single
+// account branch.
+type DiscoveredBranch struct {
+	Account  uint32
+	Branch   uint32
+	LastUsed uint32 // ^uint32(0) when no child addresses were used
+}
+
+// SyncCheckpoint records the progress of an unfinished initial sync so that an
+// interrupted sync can resume rather than restart address discovery.  Height
+// and Hash identify the main chain block the checkpoint was recorded at; the
+// checkpoint is only valid while this block remains in the main chain.
+type SyncCheckpoint struct {
+	Height             int32
+	Hash               chainhash.Hash
+	AccountsDiscovered bool
+	Branches           []DiscoveredBranch
+}
+
+// SyncCheckpoint returns the recorded sync checkpoint, or nil if there is no
+// sync in progress.
+func (s *Store) SyncCheckpoint(dbtx walletdb.ReadTx) (*SyncCheckpoint, error) {
+	ns := dbtx.ReadBucket(wtxmgrBucketKey)
+	return fetchSyncCheckpoint(ns)
+}
+
+// PutSyncCheckpoint records cp as the current sync checkpoint, replacing any
+// existing checkpoint.
+func (s *Store) PutSyncCheckpoint(dbtx walletdb.ReadWriteTx, cp *SyncCheckpoint) error {
+	ns := dbtx.ReadWriteBucket(wtxmgrBucketKey)
+	return putSyncCheckpoint(ns, cp)
+}
+
+// DeleteSyncCheckpoint removes the current sync checkpoint.  This should be
+// called once a sync has finished.
+func (s *Store) DeleteSyncCheckpoint(dbtx walletdb.ReadWriteTx) error {
+	ns := dbtx.ReadWriteBucket(wtxmgrBucketKey)
+	return deleteSyncCheckpoint(ns)
+}
+
+// RollbackSyncCheckpoint removes the sync checkpoint when its block at or
+// above height is removed from the main chain.
+func (s *Store) RollbackSyncCheckpoint(dbtx walletdb.ReadWriteTx, height int32) error {
+	ns := dbtx.ReadWriteBucket(wtxmgrBucketKey)
+	cp, err := fetchSyncCheckpoint(ns)
+	if err != nil || cp == nil || cp.Height < height {
+		return err
+	}
+	return deleteSyncCheckpoint(ns)
+}
+
 // GetBlockHeader returns the block header for the block specified by its hash.
 func (s *Store) GetBlockHeader(dbtx walletdb.ReadTx, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
 	ns := dbtx.ReadBucket(wtxmgrBucketKey)
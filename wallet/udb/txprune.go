@@ -0,0 +1,256 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/blockchain/stake"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// HistoryCheckpoint summarizes the transaction records removed from the store
+// by pruning the transaction history at and below a block height.
+type HistoryCheckpoint struct {
+	Height       int32
+	Transactions uint32
+
+	// Credits and Debits are the total amounts of the credits and debits
+	// of the pruned transactions.
+	Credits hcutil.Amount
+	Debits  hcutil.Amount
+}
+
+// prunableTx is a mined transaction record selected for pruning.
+type prunableTx struct {
+	key []byte
+	rec TxRecord
+}
+
+// PruneHistory removes the records of mined transactions at and below height
+// which no longer affect the wallet's balances or its ability to create and
+// spend transactions.  A transaction is only pruned when:
+//
+//   - It is a regular transaction in a block which was not stake invalidated
+//   - Every credit is spent by a regular transaction mined at or below height
+//   - None of its outputs are tracked multisig outputs
+//
+// Tickets, votes, revocations, and any transaction with a credit spent by a
+// ticket purchase are never pruned.  Debits and credits of the remaining
+// transactions may continue to reference the pruned records.  Pruned
+// transactions are recorded so that rescanning their blocks does not insert
+// them, or their already spent outputs, again.
+//
+// The totals of the pruned transactions are added to the history checkpoint
+// at height, which is returned with the hashes of the pruned transactions.  The height must be below the main chain tip
+// so rollbacks never reach pruned transactions; callers should additionally
+// keep it far enough below the tip that reorganizations can not reach it.
+func (s *Store) PruneHistory(ns walletdb.ReadWriteBucket, height int32) (*HistoryCheckpoint, []chainhash.Hash, error) {
+	_, tipHeight := s.MainChainTip(ns)
+	if height < 0 || height >= tipHeight {
+		str := fmt.Sprintf("prune height %d is not below the main chain "+
+			"tip height %d", height, tipHeight)
+		return nil, nil, storeError(apperrors.ErrInput, str, nil)
+	}
+	prunedHeight := fetchPrunedHeight(ns)
+
+	// Select the transactions to prune before modifying any records so the
+	// block iterator is not invalidated.
+	pruneBlocks := make(map[int32]map[chainhash.Hash]struct{})
+	var prune []prunableTx
+	blockIter := makeReadBlockIterator(ns, 0)
+	for blockIter.next() {
+		block := &blockIter.elem
+		if block.Height > height {
+			break
+		}
+		if extractRawBlockRecordStakeInvalid(blockIter.cv) {
+			continue
+		}
+		for i := range block.transactions {
+			txHash := &block.transactions[i]
+			k := keyTxRecord(txHash, &block.Block)
+			v := existsRawTxRecord(ns, k)
+			if v == nil {
+				continue
+			}
+			var rec TxRecord
+			err := readRawTxRecord(txHash, v, &rec)
+			if err != nil {
+				return nil, nil, err
+			}
+			ok, err := historyPrunable(ns, k, &rec, height, prunedHeight)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				continue
+			}
+			prune = append(prune, prunableTx{key: k, rec: rec})
+			if pruneBlocks[block.Height] == nil {
+				pruneBlocks[block.Height] = make(map[chainhash.Hash]struct{})
+			}
+			pruneBlocks[block.Height][*txHash] = struct{}{}
+		}
+	}
+	if blockIter.err != nil {
+		return nil, nil, blockIter.err
+	}
+
+	cp, err := fetchHistoryCheckpoint(ns, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cp == nil {
+		cp = &HistoryCheckpoint{Height: height}
+	}
+
+	pruned := make([]chainhash.Hash, 0, len(prune))
+	for i := range prune {
+		p := &prune[i]
+		credits, debits, err := s.deleteMinedTx(ns, p.key, &p.rec)
+		if err != nil {
+			return nil, nil, err
+		}
+		pruned = append(pruned, p.rec.Hash)
+		cp.Transactions++
+		cp.Credits += credits
+		cp.Debits += debits
+	}
+	for blockHeight, txHashes := range pruneBlocks {
+		k, v := existsBlockRecord(ns, blockHeight)
+		newv, err := removeRawBlockRecordTxs(v, txHashes)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = putRawBlockRecord(ns, k, newv)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = putHistoryCheckpoint(ns, cp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cp, pruned, nil
+}
+
+// historyPrunable returns whether the mined transaction record with key k may
+// be pruned when pruning at and below height.  See PruneHistory for the
+// conditions checked.
+func historyPrunable(ns walletdb.ReadBucket, k []byte, rec *TxRecord, height, prunedHeight int32) (bool, error) {
+	if rec.TxType != stake.TxTypeRegular {
+		return false, nil
+	}
+	for i := range rec.MsgTx.TxOut {
+		if existsMultisigOut(ns, keyMultisigOut(rec.Hash, uint32(i))) != nil {
+			return false, nil
+		}
+	}
+
+	c := ns.NestedReadBucket(bucketCredits).ReadCursor()
+	for ck, cv := c.Seek(k); bytes.HasPrefix(ck, k); ck, cv = c.Next() {
+		if len(cv) < 81 || cv[8]&(1<<0) == 0 {
+			return false, nil
+		}
+		debitKey := extractRawCreditSpenderDebitKey(cv)
+		spenderHeight := extractRawCreditHeight(debitKey)
+		if spenderHeight > height {
+			return false, nil
+		}
+		v := existsRawTxRecord(ns, debitKey[:68])
+		if v == nil {
+			// The spender may only be missing when it was pruned
+			// earlier, in which case it was a regular transaction.
+			if spenderHeight <= prunedHeight {
+				continue
+			}
+			return false, nil
+		}
+		var spenderHash chainhash.Hash
+		copy(spenderHash[:], debitKey[:32])
+		var spender wire.MsgTx
+		err := readRawTxRecordMsgTx(&spenderHash, v, &spender)
+		if err != nil {
+			return false, err
+		}
+		if stake.DetermineTxType(&spender) != stake.TxTypeRegular {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// deleteMinedTx removes a mined transaction record, its credits and debits,
+// and its address credit index entries, returning the total amounts of the
+// removed credits and debits.  The record key is added to the pruned
+// transactions bucket.  The transaction hash is not removed from its block
+// record.
+func (s *Store) deleteMinedTx(ns walletdb.ReadWriteBucket, k []byte, rec *TxRecord) (credits, debits hcutil.Amount, err error) {
+	var creditKeys, debitKeys [][]byte
+	c := ns.NestedReadBucket(bucketCredits).ReadCursor()
+	for ck, cv := c.Seek(k); bytes.HasPrefix(ck, k); ck, cv = c.Next() {
+		amount, err := fetchRawCreditAmount(cv)
+		if err != nil {
+			return 0, 0, err
+		}
+		credits += amount
+		creditKeys = append(creditKeys, append([]byte(nil), ck...))
+	}
+	c = ns.NestedReadBucket(bucketDebits).ReadCursor()
+	for dk, dv := c.Seek(k); bytes.HasPrefix(dk, k); dk, dv = c.Next() {
+		debits += extractRawDebitAmount(dv)
+		debitKeys = append(debitKeys, append([]byte(nil), dk...))
+	}
+
+	for _, ck := range creditKeys {
+		err := deleteRawCredit(ns, ck)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	for _, dk := range debitKeys {
+		err := deleteRawDebit(ns, dk)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	err = s.unindexTxAddrs(ns, &rec.MsgTx, &rec.Hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = ns.NestedReadWriteBucket(bucketTxRecords).Delete(k)
+	if err != nil {
+		str := "failed to delete transaction record"
+		return 0, 0, storeError(apperrors.ErrDatabase, str, err)
+	}
+	err = putPrunedTx(ns, k)
+	if err != nil {
+		return 0, 0, err
+	}
+	return credits, debits, nil
+}
+
+// HistoryCheckpoints returns the checkpoints recorded by pruning the
+// transaction history, ordered by increasing height.
+func (s *Store) HistoryCheckpoints(ns walletdb.ReadBucket) ([]HistoryCheckpoint, error) {
+	var checkpoints []HistoryCheckpoint
+	err := ns.NestedReadBucket(bucketHistoryCheckpoints).ForEach(func(k, v []byte) error {
+		var cp HistoryCheckpoint
+		err := readRawHistoryCheckpoint(k, v, &cp)
+		if err != nil {
+			return err
+		}
+		checkpoints = append(checkpoints, cp)
+		return nil
+	})
+	return checkpoints, err
+}
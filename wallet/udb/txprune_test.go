@@ -0,0 +1,281 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/walletdb"
+	_ "github.com/HcashOrg/hcwallet/walletdb/bdb"
+)
+
+func TestPruneHistory(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := &chaincfg.TestNet2Params
+	addr, err := hcutil.NewAddressPubKeyHash(make([]byte, 20), params,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := make([]byte, 33)
+	pubKey[0] = 0x02
+	multisigScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_1).
+		AddData(pubKey).AddOp(txscript.OP_1).
+		AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHash(multisigScript, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2shScript, err := txscript.PayToAddrScript(p2sh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticketScript, err := txscript.PayToSStx(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitmentScript, err := txscript.GenerateSStxAddrPush(addr, 1e8, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticketChangeScript, err := txscript.PayToSStxChange(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks 1 and 2 are below the prune height.  Block 3 invalidates the
+	// regular transaction tree of block 2.
+	g := makeBlockGenerator()
+	block1Header := g.generate(hcutil.BlockValid)
+	block2Header := g.generate(hcutil.BlockValid)
+	block3Header := g.generate(0)
+	const pruneHeight = 2
+
+	spend := func(prev *wire.MsgTx, value int64) *wire.MsgTx {
+		return &wire.MsgTx{
+			TxIn: []*wire.TxIn{
+				{PreviousOutPoint: wire.OutPoint{Hash: prev.TxHash(), Index: 0, Tree: 0}},
+			},
+			TxOut: []*wire.TxOut{{Value: value, PkScript: pkScript}},
+		}
+	}
+
+	// fundedTx is spent by the regular transaction spentTx in block 1.
+	// Both are pruned.
+	fundedTx := &wire.MsgTx{TxOut: []*wire.TxOut{{Value: 1e8, PkScript: pkScript}}}
+	spentTx := spend(fundedTx, 1e8-1e4)
+
+	// ticketFundingTx is spent by the ticket purchase ticketTx.  Neither
+	// is pruned.
+	ticketFundingTx := &wire.MsgTx{TxOut: []*wire.TxOut{{Value: 2e8, PkScript: pkScript}}}
+	ticketTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{
+			{PreviousOutPoint: wire.OutPoint{Hash: ticketFundingTx.TxHash(), Index: 0, Tree: 0}},
+		},
+		TxOut: []*wire.TxOut{
+			{Value: 2e8 - 1e4, PkScript: ticketScript},
+			{Value: 0, PkScript: commitmentScript},
+			{Value: 0, PkScript: ticketChangeScript},
+		},
+	}
+
+	// multisigTx has a tracked multisig output and is not pruned, even
+	// though its only credit is spent by multisigSpendTx, which is.
+	multisigTx := &wire.MsgTx{
+		TxOut: []*wire.TxOut{
+			{Value: 3e8, PkScript: pkScript},
+			{Value: 1e8, PkScript: p2shScript},
+		},
+	}
+	multisigSpendTx := spend(multisigTx, 3e8-1e4)
+
+	// lateSpentTx is spent above the prune height and neither it nor its
+	// spender is pruned.
+	lateSpentTx := &wire.MsgTx{TxOut: []*wire.TxOut{{Value: 4e8, PkScript: pkScript}}}
+	lateSpendTx := spend(lateSpentTx, 4e8-1e4)
+
+	// invalidatedTx would be pruned if block 2 had not been stake
+	// invalidated.
+	invalidatedTx := &wire.MsgTx{TxOut: []*wire.TxOut{{Value: 5e8, PkScript: pkScript}}}
+
+	type minedTx struct {
+		tx       *wire.MsgTx
+		header   *wire.BlockHeader
+		credit   bool
+		multisig bool
+		pruned   bool
+	}
+	txs := []minedTx{
+		{tx: fundedTx, header: block1Header, credit: true, pruned: true},
+		{tx: spentTx, header: block1Header, pruned: true},
+		{tx: ticketFundingTx, header: block1Header, credit: true},
+		{tx: ticketTx, header: block1Header},
+		{tx: multisigTx, header: block1Header, credit: true, multisig: true},
+		{tx: multisigSpendTx, header: block1Header, pruned: true},
+		{tx: lateSpentTx, header: block1Header, credit: true},
+		{tx: lateSpendTx, header: block3Header},
+		{tx: invalidatedTx, header: block2Header},
+	}
+	wantCheckpoint := HistoryCheckpoint{
+		Height:       pruneHeight,
+		Transactions: 3,
+		Credits:      1e8,
+		Debits:       1e8 + 3e8,
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		addrmgrNs := tx.ReadBucket(waddrmgrBucketKey)
+
+		headerData := makeHeaderDataSlice(block1Header, block2Header, block3Header)
+		err := s.InsertMainChainHeaders(ns, addrmgrNs, headerData)
+		if err != nil {
+			return err
+		}
+		err = s.InsertTxScript(ns, multisigScript)
+		if err != nil {
+			return err
+		}
+
+		insertTxs := func(multisig bool) error {
+			for _, m := range txs {
+				rec, err := NewTxRecordFromMsgTx(m.tx, time.Time{})
+				if err != nil {
+					return err
+				}
+				blockHash := m.header.BlockHash()
+				err = s.InsertMinedTx(ns, addrmgrNs, rec, &blockHash)
+				if err != nil {
+					return err
+				}
+				if m.credit {
+					err = s.AddCredit(ns, rec, makeBlockMeta(m.header), 0, false, 0)
+					if err != nil {
+						return err
+					}
+				}
+				if m.multisig && multisig {
+					err = s.AddMultisigOut(ns, rec, makeBlockMeta(m.header), 1)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		err = insertTxs(true)
+		if err != nil {
+			return err
+		}
+
+		balBefore, err := s.AccountBalance(ns, addrmgrNs, 1, 0)
+		if err != nil {
+			return err
+		}
+
+		// Pruning at or above the tip is an error.
+		_, _, err = s.PruneHistory(ns, g.lastHeight)
+		if err == nil {
+			t.Errorf("Pruning at the tip height did not error")
+		}
+
+		// Prune twice.  Nothing is prunable by the second prune, so the
+		// checkpoint totals must not change.
+		for i := 0; i < 2; i++ {
+			cp, pruned, err := s.PruneHistory(ns, pruneHeight)
+			if err != nil {
+				return err
+			}
+			wantPruned := int(wantCheckpoint.Transactions)
+			if i != 0 {
+				wantPruned = 0
+			}
+			if len(pruned) != wantPruned {
+				t.Errorf("Prune %d: %d transactions pruned, expected %d",
+					i, len(pruned), wantPruned)
+			}
+			if *cp != wantCheckpoint {
+				t.Errorf("Prune %d: wrong checkpoint: expected %+v got %+v",
+					i, wantCheckpoint, *cp)
+			}
+		}
+
+		checkPruned := func(desc string) {
+			for i, m := range txs {
+				hash := m.tx.TxHash()
+				if s.ExistsTx(ns, &hash) == m.pruned {
+					t.Errorf("%s: transaction %d (%v): expected pruned=%v",
+						desc, i, &hash, m.pruned)
+				}
+			}
+		}
+		checkPruned("Prune")
+
+		checkpoints, err := s.HistoryCheckpoints(ns)
+		if err != nil {
+			return err
+		}
+		if len(checkpoints) != 1 || checkpoints[0] != wantCheckpoint {
+			t.Errorf("Wrong checkpoints: expected [%+v] got %+v",
+				wantCheckpoint, checkpoints)
+		}
+		if h := fetchPrunedHeight(ns); h != pruneHeight {
+			t.Errorf("Wrong pruned height: expected %v got %v", pruneHeight, h)
+		}
+
+		balAfter, err := s.AccountBalance(ns, addrmgrNs, 1, 0)
+		if err != nil {
+			return err
+		}
+		if balAfter != balBefore {
+			t.Errorf("Balance changed by pruning: before %+v after %+v",
+				balBefore, balAfter)
+		}
+
+		// Rescanning the pruned blocks must not insert the pruned
+		// transactions again or add their spent outputs as unspent.
+		err = insertTxs(false)
+		if err != nil {
+			return err
+		}
+		checkPruned("Rescan")
+		fundedOp := wire.OutPoint{Hash: fundedTx.TxHash(), Index: 0, Tree: 0}
+		if _, credKey := existsUnspent(ns, &fundedOp); credKey != nil {
+			t.Errorf("Rescan added spent output %v of a pruned transaction "+
+				"as unspent", &fundedOp)
+		}
+		balRescan, err := s.AccountBalance(ns, addrmgrNs, 1, 0)
+		if err != nil {
+			return err
+		}
+		if balRescan != balBefore {
+			t.Errorf("Balance changed by rescan: before %+v after %+v",
+				balBefore, balRescan)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -176,24 +176,95 @@ func (s *Store) unminedTxDetails(ns walletdb.ReadBucket, txHash *chainhash.Hash,
 // hash.  In case of a hash collision, the most recent transaction with a
 // matching hash is returned.
 //
+// The serialized transaction of the returned details is set from the stored
+// record, so it does not need to be reserialized.
+//
 // Not finding a transaction with this hash is not an error.  In this case,
 // a nil TxDetails is returned.
 func (s *Store) TxDetails(ns walletdb.ReadBucket, txHash *chainhash.Hash) (*TxDetails, error) {
 	// First, check whether there exists an unmined transaction with this
 	// hash.  Use it if found.
+	var details *TxDetails
+	var err error
 	v := existsRawUnmined(ns, txHash[:])
 	if v != nil {
-		return s.unminedTxDetails(ns, txHash, v)
+		details, err = s.unminedTxDetails(ns, txHash, v)
+	} else {
+		// Otherwise, if there exists a mined transaction with this
+		// matching hash, skip over to the newest and begin fetching
+		// all details.
+		var k []byte
+		k, v = latestTxRecord(ns, txHash[:])
+		if v == nil {
+			// not found
+			return nil, nil
+		}
+		details, err = s.minedTxDetails(ns, txHash, k, v)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Otherwise, if there exists a mined transaction with this matching
-	// hash, skip over to the newest and begin fetching all details.
-	k, v := latestTxRecord(ns, txHash[:])
+	// The record value is only valid during the database transaction, so
+	// the serialized transaction must be copied.
+	details.SerializedTx = append([]byte(nil), v[8:]...)
+	return details, nil
+}
+
+// CreditSpender returns the hash of the transaction spending output index of a
+// transaction mined in block, or unmined if block is nil.  A nil hash is
+// returned if the output is not a recorded credit or is unspent.
+func (s *Store) CreditSpender(ns walletdb.ReadBucket, txHash *chainhash.Hash,
+	index uint32, block *Block) (*chainhash.Hash, error) {
+
+	// Credits spent by unmined transactions remain marked unspent, and
+	// their spenders are recorded by the unmined inputs bucket.
+	if v := existsRawUnminedInput(ns, canonicalOutPoint(txHash, index)); v != nil {
+		return chainhash.NewHash(v)
+	}
+	if block == nil {
+		return nil, nil
+	}
+	_, v := existsCredit(ns, txHash, index, block)
 	if v == nil {
-		// not found
 		return nil, nil
 	}
-	return s.minedTxDetails(ns, txHash, k, v)
+	_, spent, err := fetchRawCreditAmountSpent(v)
+	if err != nil || !spent {
+		return nil, err
+	}
+	if len(v) < 81 {
+		str := fmt.Sprintf("%s: short read for raw credit spender "+
+			"(expected %d bytes, read %d)", bucketCredits, 81, len(v))
+		return nil, storeError(apperrors.ErrData, str, nil)
+	}
+	return chainhash.NewHash(extractRawCreditSpenderDebitKey(v)[:32])
+}
+
+// AddressTxDetails looks up the details of every transaction with a credit
+// paying to an address using the address credits index, without iterating over
+// all recorded transactions.  Credits paying to other addresses are included
+// in the details, and must be filtered by the caller.
+func (s *Store) AddressTxDetails(ns walletdb.ReadBucket, addr hcutil.Address) ([]*TxDetails, error) {
+	hashes := addrCreditTxHashes(ns, addr.EncodeAddress())
+	details := make([]*TxDetails, 0, len(hashes))
+	for i := range hashes {
+		d, err := s.TxDetails(ns, &hashes[i])
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			continue
+		}
+		details = append(details, d)
+	}
+	return details, nil
+}
+
+// AddressCreditTxHashes returns the hashes of every transaction with a credit
+// paying to an address, as recorded by the address credits index.
+func (s *Store) AddressCreditTxHashes(ns walletdb.ReadBucket, addr hcutil.Address) []chainhash.Hash {
+	return addrCreditTxHashes(ns, addr.EncodeAddress())
 }
 
 // TicketDetails is intended to provide callers with access to rich details
@@ -309,6 +380,19 @@ func (s *Store) ExistsUTXO(dbtx walletdb.ReadTx, op *wire.OutPoint) bool {
 	return existsRawUnminedCredit(ns, k) != nil
 }
 
+// UnminedSpender returns the hash of the unmined transaction recorded as
+// spending op, or nil if op is not spent by any unmined transaction.
+func (s *Store) UnminedSpender(dbtx walletdb.ReadTx, op *wire.OutPoint) *chainhash.Hash {
+	ns := dbtx.ReadBucket(wtxmgrBucketKey)
+	v := existsRawUnminedInput(ns, canonicalOutPoint(&op.Hash, op.Index))
+	if v == nil {
+		return nil
+	}
+	var spender chainhash.Hash
+	readRawUnminedInputSpenderHash(v, &spender)
+	return &spender
+}
+
 // UniqueTxDetails looks up all recorded details for a transaction recorded
 // mined in some particular block, or an unmined transaction if block is nil.
 //
@@ -599,6 +683,7 @@ func (s *Store) PreviousPkScripts(ns walletdb.ReadBucket, rec *TxRecord, block *
 				pkScripts = append(pkScripts, pkScript)
 			}
 		}
+		return pkScripts, nil
 	}
 
 	recKey := keyTxRecord(&rec.Hash, block)
@@ -228,9 +228,68 @@ func (s *Store) removeUnconfirmed(ns walletdb.ReadWriteBucket, tx *wire.MsgTx, t
 		}
 	}
 
+	err := s.unindexTxAddrs(ns, tx, txHash)
+	if err != nil {
+		return err
+	}
+
 	return deleteRawUnmined(ns, txHash[:])
 }
 
+// RemoveUnminedTx removes the unmined transaction with hash txHash, and every
+// unmined transaction which spends from it, from the store.  All credits and
+// spends recorded by the removed transactions are removed as well, so any
+// outputs they spent become unspent again.  The records of all removed
+// transactions are returned, beginning with the record for txHash.
+//
+// An error with the code ErrValueNoExists is returned if txHash is not a known
+// unmined transaction.
+func (s *Store) RemoveUnminedTx(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash) ([]*TxRecord, error) {
+	v := existsRawUnmined(ns, txHash[:])
+	if v == nil {
+		str := fmt.Sprintf("no unmined transaction %v", txHash)
+		return nil, storeError(apperrors.ErrValueNoExists, str, nil)
+	}
+	rec := new(TxRecord)
+	err := readRawTxRecord(txHash, v, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the spend chain before it is removed so the caller learns of
+	// every transaction that is no longer recorded.
+	removed := []*TxRecord{rec}
+	seen := map[chainhash.Hash]struct{}{rec.Hash: {}}
+	for i := 0; i < len(removed); i++ {
+		r := removed[i]
+		for j := range r.MsgTx.TxOut {
+			k := canonicalOutPoint(&r.Hash, uint32(j))
+			spenderHash := existsRawUnminedInput(ns, k)
+			if spenderHash == nil {
+				continue
+			}
+			spender := new(TxRecord)
+			copy(spender.Hash[:], spenderHash)
+			if _, ok := seen[spender.Hash]; ok {
+				continue
+			}
+			spenderVal := existsRawUnmined(ns, spenderHash)
+			err := readRawTxRecord(&spender.Hash, spenderVal, spender)
+			if err != nil {
+				return nil, err
+			}
+			seen[spender.Hash] = struct{}{}
+			removed = append(removed, spender)
+		}
+	}
+
+	err = s.removeUnconfirmed(ns, &rec.MsgTx, &rec.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
 // UnminedTxs returns the underlying transactions for all unmined transactions
 // which are not known to have been mined in a block.  Transactions are
 // guaranteed to be sorted by their dependency order.
@@ -294,16 +353,15 @@ func (s *Store) unminedTxHashes(ns walletdb.ReadBucket) ([]*chainhash.Hash, erro
 //   * Ticket purchases with a different ticket price than the passed stake
 //     difficulty
 //   * Votes that do not vote on the tip block
-func (s *Store) PruneUnmined(dbtx walletdb.ReadWriteTx, stakeDiff int64) error {
+//
+// Unmined transactions spending from the removed transactions are removed as
+// well.  The hashes of all removed transactions are returned.
+func (s *Store) PruneUnmined(dbtx walletdb.ReadWriteTx, stakeDiff int64) ([]*chainhash.Hash, error) {
 	ns := dbtx.ReadWriteBucket(wtxmgrBucketKey)
 
 	_, tipHeight := s.MainChainTip(ns)
 
-	type removeTx struct {
-		tx   wire.MsgTx
-		hash *chainhash.Hash
-	}
-	var toRemove []*removeTx
+	var toRemove []*chainhash.Hash
 
 	c := ns.NestedReadBucket(bucketUnmined).ReadCursor()
 	for k, v := c.First(); k != nil; k, v = c.Next() {
@@ -311,7 +369,7 @@ func (s *Store) PruneUnmined(dbtx walletdb.ReadWriteTx, stakeDiff int64) error {
 		err := tx.Deserialize(bytes.NewReader(extractRawUnminedTx(v)))
 		if err != nil {
 			str := fmt.Sprintf("deserialize error")
-			return apperrors.Wrap(err, apperrors.ErrData, str)
+			return nil, apperrors.Wrap(err, apperrors.ErrData, str)
 		}
 
 		var expired, isTicketPurchase, isVote bool
@@ -339,7 +397,7 @@ func (s *Store) PruneUnmined(dbtx walletdb.ReadWriteTx, stakeDiff int64) error {
 		txHash, err := chainhash.NewHash(k)
 		if err != nil {
 			str := fmt.Sprintf("unexpected hash string")
-			return apperrors.Wrap(err, apperrors.ErrData, str)
+			return nil, apperrors.Wrap(err, apperrors.ErrData, str)
 		}
 
 		if expired {
@@ -350,15 +408,24 @@ func (s *Store) PruneUnmined(dbtx walletdb.ReadWriteTx, stakeDiff int64) error {
 			log.Infof("Removing missed or invalid vote %v", txHash)
 		}
 
-		toRemove = append(toRemove, &removeTx{tx, txHash})
+		toRemove = append(toRemove, txHash)
 	}
 
-	for _, r := range toRemove {
-		err := s.removeUnconfirmed(ns, &r.tx, r.hash)
+	removed := make([]*chainhash.Hash, 0, len(toRemove))
+	for _, txHash := range toRemove {
+		// The transaction may already be removed as a spender of an
+		// earlier removed transaction.
+		if existsRawUnmined(ns, txHash[:]) == nil {
+			continue
+		}
+		recs, err := s.RemoveUnminedTx(ns, txHash)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		for _, rec := range recs {
+			removed = append(removed, &rec.Hash)
 		}
 	}
 
-	return nil
+	return removed, nil
 }
@@ -0,0 +1,145 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestForEachUnspentOutput(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := hcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.TestNet2Params, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx := func(prev byte) *TxRecord {
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{prev}}, nil))
+		tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+		tx.AddTxOut(wire.NewTxOut(2e8, pkScript))
+		rec, err := NewTxRecordFromMsgTx(tx, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+	unmined, mined := newTx(1), newTx(2)
+
+	g := makeBlockGenerator()
+	header := g.generate(hcutil.BlockValid)
+	blockHash := header.BlockHash()
+	insertMined := func(ns walletdb.ReadWriteBucket, addrmgrNs walletdb.ReadBucket,
+		rec *TxRecord) error {
+
+		err := s.InsertMinedTx(ns, addrmgrNs, rec, &blockHash)
+		if err != nil {
+			return err
+		}
+		for i := range rec.MsgTx.TxOut {
+			err = s.AddCredit(ns, rec, makeBlockMeta(header), uint32(i), false, 0)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		addrmgrNs := tx.ReadBucket(waddrmgrBucketKey)
+		err := s.InsertMainChainHeaders(ns, addrmgrNs, makeHeaderDataSlice(header))
+		if err != nil {
+			return err
+		}
+		err = s.InsertMemPoolTx(ns, unmined)
+		if err != nil {
+			return err
+		}
+		for i := range unmined.MsgTx.TxOut {
+			err = s.AddCredit(ns, unmined, nil, uint32(i), false, 0)
+			if err != nil {
+				return err
+			}
+		}
+		return insertMined(ns, addrmgrNs, mined)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outpoints := func(after *wire.OutPoint, limit int) []wire.OutPoint {
+		var ops []wire.OutPoint
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(wtxmgrBucketKey)
+			return s.ForEachUnspentOutput(ns, after, func(c *Credit) (bool, error) {
+				op := c.OutPoint
+				op.Tree = 0
+				ops = append(ops, op)
+				return len(ops) < limit, nil
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ops
+	}
+
+	// Mined and unmined outputs are merged in the order of their keys.
+	all := outpoints(nil, 100)
+	if len(all) != 4 {
+		t.Fatalf("visited %d outputs, want 4", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		prev := canonicalOutPoint(&all[i-1].Hash, all[i-1].Index)
+		k := canonicalOutPoint(&all[i].Hash, all[i].Index)
+		if bytes.Compare(prev, k) >= 0 {
+			t.Fatalf("outputs %v and %v are out of order", all[i-1], all[i])
+		}
+	}
+
+	// Iteration resumes after an outpoint, and stops when requested.
+	if ops := outpoints(&all[1], 100); !reflect.DeepEqual(ops, all[2:]) {
+		t.Errorf("outputs after %v: got %v, want %v", all[1], ops, all[2:])
+	}
+	if ops := outpoints(nil, 1); len(ops) != 1 {
+		t.Errorf("visited %d outputs after stopping, want 1", len(ops))
+	}
+
+	// Mining the unmined outputs does not change the order.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return insertMined(tx.ReadWriteBucket(wtxmgrBucketKey),
+			tx.ReadBucket(waddrmgrBucketKey), unmined)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops := outpoints(nil, 100); !reflect.DeepEqual(ops, all) {
+		t.Errorf("outputs after mining: got %v, want %v", ops, all)
+	}
+	if ops := outpoints(&all[0], 100); !reflect.DeepEqual(ops, all[1:]) {
+		t.Errorf("outputs after %v after mining: got %v, want %v", all[0],
+			ops, all[1:])
+	}
+}
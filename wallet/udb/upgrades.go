@@ -6,13 +6,16 @@
 package udb
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
+	"time"
 
 	"github.com/HcashOrg/hcd/blockchain/stake"
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainhash"
 	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
+	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcwallet/apperrors"
 	"github.com/HcashOrg/hcwallet/snacl"
 	"github.com/HcashOrg/hcwallet/walletdb"
@@ -68,10 +71,98 @@ const (
 	// from properly-synced wallets.
 	lastProcessedTxsBlockVersion = 7
 
+	// accountFeesVersion is the eighth version of the database.  It adds a
+	// top level bucket for recording per-account relay and ticket fee
+	// settings which override the global fees set for the wallet.
+	accountFeesVersion = 8
+
+	// stakeRewardsVersion is the ninth version of the database.  It adds a
+	// stake manager bucket indexing the votes and revocations of wallet
+	// tickets by block height.  During upgrade, the index is populated from
+	// the mined transaction history.
+	stakeRewardsVersion = 9
+
+	// addrCreditsVersion is the tenth version of the database.  It adds a
+	// transaction store bucket indexing the transactions with credits paying
+	// to each address.  During upgrade, the index is populated from the
+	// mined, unmined, and stake invalidated credits.
+	addrCreditsVersion = 10
+
+	// historyCheckpointsVersion is the eleventh version of the database.  It
+	// adds transaction store buckets recording summaries of the transactions
+	// removed by pruning the transaction history and the removed transaction
+	// records.
+	historyCheckpointsVersion = 11
+
+	// txDraftsVersion is the twelfth version of the database.  It adds
+	// transaction store buckets recording unsigned transaction drafts and the
+	// outpoints reserved by each draft.
+	txDraftsVersion = 12
+
+	// idempotencyKeysVersion is the thirteenth version of the database.  It
+	// adds a transaction store bucket recording the hash of the transaction
+	// sent for each client-provided idempotency key.
+	idempotencyKeysVersion = 13
+
+	// auditLogVersion is the fourteenth version of the database.  It adds
+	// transaction store buckets for the append-only log of balance-affecting
+	// events and the index of the transactions with credit and debit entries
+	// in the log.
+	auditLogVersion = 14
+
+	// externalBranchesVersion is the fifteenth version of the database.  It
+	// adds an address manager bucket recording the additional external
+	// branches of accounts.
+	externalBranchesVersion = 15
+
+	// invoicesVersion is the sixteenth version of the database.  It adds an
+	// address manager bucket recording the addresses derived for invoices.
+	invoicesVersion = 16
+
+	// webhooksVersion is the seventeenth version of the database.  It adds a
+	// transaction store bucket recording the queued webhook notifications
+	// and their delivery state.
+	webhooksVersion = 17
+
+	// multisigAccountsVersion is the eighteenth version of the database.  It
+	// adds an address manager bucket recording the cosigners of multisig
+	// accounts.
+	multisigAccountsVersion = 18
+
+	// atomicSwapsVersion is the nineteenth version of the database.  It adds
+	// an address manager bucket recording the atomic swap contracts created
+	// by the wallet and the secrets of their redemptions.
+	atomicSwapsVersion = 19
+
+	// balanceSnapshotsVersion is the twentieth version of the database.  It
+	// adds a transaction store bucket recording periodic snapshots of
+	// account balances.
+	balanceSnapshotsVersion = 20
+
+	// spendableConfsVersion is the twenty-first version of the database.  It
+	// adds a transaction store bucket recording the confirmations accounts
+	// require before their outputs are considered spendable.
+	spendableConfsVersion = 21
+
+	// replacedTxsVersion is the twenty-second version of the database.  It
+	// adds a transaction store bucket recording unmined transactions which
+	// were replaced by transactions paying a higher fee.
+	replacedTxsVersion = 22
+
+	// ticketAccountsVersion is the twenty-third version of the database.  It
+	// adds a stake manager bucket recording the account which funded each
+	// ticket purchase.
+	ticketAccountsVersion = 23
+
+	// defaultAddrsVersion is the twenty-fourth version of the database.  It
+	// adds an address manager bucket recording the default address set for
+	// accounts.
+	defaultAddrsVersion = 24
+
 	// DBVersion is the latest version of the database that is understood by the
 	// program.  Databases with recorded versions higher than this will fail to
 	// open (meaning any upgrades prevent reverting to older software).
-	DBVersion = lastProcessedTxsBlockVersion
+	DBVersion = defaultAddrsVersion
 )
 
 // upgrades maps between old database versions and the upgrade function to
@@ -84,6 +175,23 @@ var upgrades = [...]func(walletdb.ReadWriteTx, []byte, []byte, *chaincfg.Params)
 	lastReturnedAddressVersion - 1:   lastReturnedAddressUpgrade,
 	ticketBucketVersion - 1:          ticketBucketUpgrade,
 	lastProcessedTxsBlockVersion - 1: lastProcessedTxsBlockUpgrade,
+	accountFeesVersion - 1:           accountFeesUpgrade,
+	stakeRewardsVersion - 1:          stakeRewardsUpgrade,
+	addrCreditsVersion - 1:           addrCreditsUpgrade,
+	historyCheckpointsVersion - 1:    historyCheckpointsUpgrade,
+	txDraftsVersion - 1:              txDraftsUpgrade,
+	idempotencyKeysVersion - 1:       idempotencyKeysUpgrade,
+	auditLogVersion - 1:              auditLogUpgrade,
+	externalBranchesVersion - 1:      externalBranchesUpgrade,
+	invoicesVersion - 1:              invoicesUpgrade,
+	webhooksVersion - 1:              webhooksUpgrade,
+	multisigAccountsVersion - 1:      multisigAccountsUpgrade,
+	atomicSwapsVersion - 1:           atomicSwapsUpgrade,
+	balanceSnapshotsVersion - 1:      balanceSnapshotsUpgrade,
+	spendableConfsVersion - 1:        spendableConfsUpgrade,
+	replacedTxsVersion - 1:           replacedTxsUpgrade,
+	ticketAccountsVersion - 1:        ticketAccountsUpgrade,
+	defaultAddrsVersion - 1:          defaultAddrsUpgrade,
 }
 
 func lastUsedAddressIndexUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
@@ -577,6 +685,621 @@ func lastProcessedTxsBlockUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, pri
 	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
 }
 
+func accountFeesUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 7
+	const newVersion = 8
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+
+	// Assert that this function is only called on version 7 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "accountFeesUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	// Create the top level bucket for account fee settings.
+	_, err = tx.CreateTopLevelBucket(accountFees.rootBucketKey())
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func stakeRewardsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 8
+	const newVersion = 9
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadBucket(wtxmgrBucketKey)
+	stakemgrBucket := tx.ReadWriteBucket(wstakemgrBucketKey)
+
+	// Assert that this function is only called on version 8 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "stakeRewardsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = stakemgrBucket.CreateBucket(stakeRewardsBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Index every mined vote and revocation which spends a ticket recorded by
+	// either the transaction store or the stake manager.
+	sstxBucket := stakemgrBucket.NestedReadBucket(sstxRecordsBucketName)
+	c := txmgrBucket.NestedReadBucket(bucketTxRecords).ReadCursor()
+	for k, v := c.First(); v != nil; k, v = c.Next() {
+		var hash chainhash.Hash
+		err := readRawTxRecordHash(k, &hash)
+		if err != nil {
+			return err
+		}
+		var rec TxRecord
+		err = readRawTxRecord(&hash, v, &rec)
+		if err != nil {
+			return err
+		}
+		if rec.TxType != stake.TxTypeSSGen && rec.TxType != stake.TxTypeSSRtx {
+			continue
+		}
+		var block Block
+		err = readRawTxRecordBlock(k, &block)
+		if err != nil {
+			return err
+		}
+		header, err := fetchRawBlockHeader(txmgrBucket, keyBlockHeader(&block.Hash))
+		if err != nil {
+			return err
+		}
+		blockTime := time.Unix(int64(extractBlockHeaderUnixTime(header)), 0)
+		ticket := &rec.MsgTx.TxIn[0].PreviousOutPoint.Hash
+		if rec.TxType == stake.TxTypeSSGen {
+			ticket = &rec.MsgTx.TxIn[1].PreviousOutPoint.Hash
+		}
+		ticketHeight := int32(-1)
+		if k, _ := latestTxRecord(txmgrBucket, ticket[:]); k != nil {
+			err := readRawTxRecordBlockHeight(k, &ticketHeight)
+			if err != nil {
+				return err
+			}
+		}
+		r, ok := newStakeReward(&rec.MsgTx, &hash, block.Height, ticketHeight,
+			blockTime, params)
+		if !ok {
+			continue
+		}
+		if existsRawTicketRecord(txmgrBucket, r.Ticket[:]) == nil &&
+			sstxBucket.Get(r.Ticket[:]) == nil {
+			continue
+		}
+		err = putStakeReward(stakemgrBucket, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func addrCreditsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 9
+	const newVersion = 10
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 9 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "addrCreditsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketAddrCredits)
+	if err != nil {
+		return err
+	}
+
+	// Index the mined and stake invalidated credits.  Both are keyed by the
+	// transaction record key followed by the output index.  Credits of the
+	// same transaction are adjacent, so each transaction is only
+	// deserialized once.
+	for _, bucketKey := range [][]byte{bucketCredits, bucketStakeInvalidatedCredits} {
+		var recKey []byte
+		var msgTx wire.MsgTx
+		c := txmgrBucket.NestedReadBucket(bucketKey).ReadCursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if len(k) < 72 {
+				continue
+			}
+			hash := extractRawCreditTxHash(k)
+			if !bytes.Equal(recKey, extractRawCreditTxRecordKey(k)) {
+				recKey = append(recKey[:0], extractRawCreditTxRecordKey(k)...)
+				recVal := existsRawTxRecord(txmgrBucket, recKey)
+				if recVal == nil {
+					recKey = nil
+					continue
+				}
+				err := readRawTxRecordMsgTx(&hash, recVal, &msgTx)
+				if err != nil {
+					return err
+				}
+			}
+			index := extractRawCreditIndex(k)
+			if int(index) >= len(msgTx.TxOut) {
+				continue
+			}
+			err := indexCreditAddrs(txmgrBucket, &hash, msgTx.TxOut[index], params)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Index the unmined credits, which are keyed by outpoint.
+	var unminedHash []byte
+	var msgTx wire.MsgTx
+	c := txmgrBucket.NestedReadBucket(bucketUnminedCredits).ReadCursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		if len(k) < unconfCreditKeySize {
+			continue
+		}
+		var hash chainhash.Hash
+		copy(hash[:], k[:32])
+		if !bytes.Equal(unminedHash, k[:32]) {
+			unminedHash = append(unminedHash[:0], k[:32]...)
+			v := existsRawUnmined(txmgrBucket, k[:32])
+			if v == nil {
+				unminedHash = nil
+				continue
+			}
+			err := readRawTxRecordMsgTx(&hash, v, &msgTx)
+			if err != nil {
+				return err
+			}
+		}
+		index := byteOrder.Uint32(k[32:36])
+		if int(index) >= len(msgTx.TxOut) {
+			continue
+		}
+		err := indexCreditAddrs(txmgrBucket, &hash, msgTx.TxOut[index], params)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func historyCheckpointsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 10
+	const newVersion = 11
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 10 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "historyCheckpointsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketHistoryCheckpoints)
+	if err != nil {
+		return err
+	}
+	_, err = txmgrBucket.CreateBucket(bucketPrunedTxs)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func txDraftsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 11
+	const newVersion = 12
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 11 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "txDraftsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketTxDrafts)
+	if err != nil {
+		return err
+	}
+	_, err = txmgrBucket.CreateBucket(bucketTxDraftInputs)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func idempotencyKeysUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 12
+	const newVersion = 13
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 12 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "idempotencyKeysUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketIdempotencyKeys)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func auditLogUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 13
+	const newVersion = 14
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 13 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "auditLogUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketAuditLog)
+	if err != nil {
+		return err
+	}
+	_, err = txmgrBucket.CreateBucket(bucketAuditedTxs)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func externalBranchesUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 14
+	const newVersion = 15
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	addrmgrBucket := tx.ReadWriteBucket(waddrmgrBucketKey)
+
+	// Assert that this function is only called on version 14 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "externalBranchesUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = addrmgrBucket.CreateBucket(externalBranchesBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func invoicesUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 15
+	const newVersion = 16
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	addrmgrBucket := tx.ReadWriteBucket(waddrmgrBucketKey)
+
+	// Assert that this function is only called on version 15 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "invoicesUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = addrmgrBucket.CreateBucket(invoicesBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func webhooksUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 16
+	const newVersion = 17
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 16 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "webhooksUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketWebhooks)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func multisigAccountsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 17
+	const newVersion = 18
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	addrmgrBucket := tx.ReadWriteBucket(waddrmgrBucketKey)
+
+	// Assert that this function is only called on version 17 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "multisigAccountsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = addrmgrBucket.CreateBucket(multisigAccountsBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func atomicSwapsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 18
+	const newVersion = 19
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	addrmgrBucket := tx.ReadWriteBucket(waddrmgrBucketKey)
+
+	// Assert that this function is only called on version 18 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "atomicSwapsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = addrmgrBucket.CreateBucket(atomicSwapsBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func balanceSnapshotsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 19
+	const newVersion = 20
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 19 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "balanceSnapshotsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketBalanceSnapshots)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func spendableConfsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 20
+	const newVersion = 21
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 20 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "spendableConfsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketSpendableConfs)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func replacedTxsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 21
+	const newVersion = 22
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+	// Assert that this function is only called on version 21 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "replacedTxsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = txmgrBucket.CreateBucket(bucketReplacedTxs)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func ticketAccountsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 22
+	const newVersion = 23
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	txmgrBucket := tx.ReadBucket(wtxmgrBucketKey)
+	stakemgrBucket := tx.ReadWriteBucket(wstakemgrBucketKey)
+
+	// Assert that this function is only called on version 22 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "ticketAccountsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	ticketAccounts, err := stakemgrBucket.CreateBucket(ticketAccountsBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Record the funding account of every mined ticket purchase which spends
+	// a wallet credit recording its account.  Unmined tickets are recorded
+	// when they are mined.
+	c := txmgrBucket.NestedReadBucket(bucketTxRecords).ReadCursor()
+	for k, v := c.First(); v != nil; k, v = c.Next() {
+		var hash chainhash.Hash
+		err := readRawTxRecordHash(k, &hash)
+		if err != nil {
+			return err
+		}
+		var rec TxRecord
+		err = readRawTxRecord(&hash, v, &rec)
+		if err != nil {
+			return err
+		}
+		if rec.TxType != stake.TxTypeSStx {
+			continue
+		}
+
+		it := makeReadDebitIterator(txmgrBucket, k)
+		if !it.next() {
+			if it.err != nil {
+				return it.err
+			}
+			continue
+		}
+		credVal := existsRawCredit(txmgrBucket, extractRawDebitCreditKey(it.cv))
+		account, err := fetchRawCreditAccount(credVal)
+		if err != nil {
+			continue
+		}
+		err = ticketAccounts.Put(hash[:], valueTicketAccount(account))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
+func defaultAddrsUpgrade(tx walletdb.ReadWriteTx, publicPassphrase, privatePassphrase []byte, params *chaincfg.Params) error {
+	const oldVersion = 23
+	const newVersion = 24
+
+	metadataBucket := tx.ReadWriteBucket(unifiedDBMetadata{}.rootBucketKey())
+	addrmgrBucket := tx.ReadWriteBucket(waddrmgrBucketKey)
+
+	// Assert that this function is only called on version 23 databases.
+	dbVersion, err := unifiedDBMetadata{}.getVersion(metadataBucket)
+	if err != nil {
+		return err
+	}
+	if dbVersion != oldVersion {
+		const str = "defaultAddrsUpgrade inappropriately called"
+		return apperrors.E{ErrorCode: apperrors.ErrUpgrade, Description: str, Err: nil}
+	}
+
+	_, err = addrmgrBucket.CreateBucket(defaultAddrsBucketName)
+	if err != nil {
+		return err
+	}
+
+	// Write the new database version.
+	return unifiedDBMetadata{}.putVersion(metadataBucket, newVersion)
+}
+
 // Upgrade checks whether the any upgrades are necessary before the database is
 // ready for application usage.  If any are, they are performed.
 func Upgrade(db walletdb.DB, publicPassphrase, privPhrasePassphrase []byte, params *chaincfg.Params) error {
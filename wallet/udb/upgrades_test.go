@@ -33,7 +33,10 @@ var dbUpgradeTests = [...]struct {
 	{verifyV6Upgrade, "v5.db.gz"},
 }
 
-var pubPass = []byte("public")
+var (
+	pubPass  = []byte("public")
+	privPass = []byte("private")
+)
 
 func TestUpgrades(t *testing.T) {
 	t.Parallel()
@@ -73,7 +76,7 @@ func TestUpgrades(t *testing.T) {
 					t.Fatal(err)
 				}
 				defer db.Close()
-				err = Upgrade(db, pubPass)
+				err = Upgrade(db, pubPass, privPass, &chaincfg.TestNet2Params)
 				if err != nil {
 					t.Fatalf("Upgrade failed: %v", err)
 				}
@@ -0,0 +1,212 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// WebhookStatus describes the delivery state of a webhook notification.
+type WebhookStatus uint8
+
+// Webhook delivery states.
+const (
+	// WebhookPending describes a notification which has not yet been
+	// delivered and will be attempted again.
+	WebhookPending WebhookStatus = iota
+
+	// WebhookDelivered describes a notification which was accepted by the
+	// webhook URL.  Delivered notifications are removed from the store.
+	WebhookDelivered
+
+	// WebhookFailed describes a notification which was not delivered after
+	// the maximum number of attempts and will not be attempted again.  The
+	// next attempt time of failed notifications records the time of the
+	// last attempt.
+	WebhookFailed
+)
+
+// String returns the name of the delivery state.
+func (s WebhookStatus) String() string {
+	switch s {
+	case WebhookPending:
+		return "pending"
+	case WebhookDelivered:
+		return "delivered"
+	case WebhookFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// WebhookDelivery is a notification queued for delivery to a webhook URL.
+type WebhookDelivery struct {
+	// Seq identifies the delivery, and is assigned when the delivery is
+	// queued.
+	Seq uint64
+
+	URL     string
+	Payload []byte
+
+	Status      WebhookStatus
+	Attempts    uint32
+	Created     time.Time
+	NextAttempt time.Time
+}
+
+// The webhooks bucket records queued deliveries keyed by their sequence
+// number:
+//
+//   [0:8]   Sequence number (8 bytes)
+//
+// The value is serialized as such:
+//
+//   [0]     Status (1 byte)
+//   [1:5]   Attempts (4 bytes)
+//   [5:13]  Creation time (8 bytes)
+//   [13:21] Next attempt time (8 bytes)
+//   [21:23] URL length (2 bytes)
+//   [23:]   URL followed by the payload
+//
+// Deliveries are removed once delivered, and failed deliveries are removed by
+// the wallet some time after the last attempt.  The root bucket's webhook
+// sequence k/v pair records the sequence number of the next queued delivery (8
+// bytes), so that the numbers of removed deliveries are never reused.
+// Databases which queued deliveries before it was recorded continue after the
+// last recorded delivery.
+//
+// The root bucket's webhook height k/v pair records the height of the last
+// block whose transactions were queued for delivery (4 bytes).
+
+func keyWebhookDelivery(seq uint64) []byte {
+	k := make([]byte, 8)
+	byteOrder.PutUint64(k, seq)
+	return k
+}
+
+func valueWebhookDelivery(d *WebhookDelivery) []byte {
+	v := make([]byte, 23+len(d.URL)+len(d.Payload))
+	v[0] = byte(d.Status)
+	byteOrder.PutUint32(v[1:5], d.Attempts)
+	byteOrder.PutUint64(v[5:13], uint64(d.Created.Unix()))
+	byteOrder.PutUint64(v[13:21], uint64(d.NextAttempt.Unix()))
+	byteOrder.PutUint16(v[21:23], uint16(len(d.URL)))
+	copy(v[23:], d.URL)
+	copy(v[23+len(d.URL):], d.Payload)
+	return v
+}
+
+func readRawWebhookDelivery(k, v []byte, d *WebhookDelivery) error {
+	if len(k) < 8 || len(v) < 23 || len(v) < 23+int(byteOrder.Uint16(v[21:23])) {
+		str := fmt.Sprintf("%s: short read for webhook delivery",
+			bucketWebhooks)
+		return storeError(apperrors.ErrData, str, nil)
+	}
+	urlLen := int(byteOrder.Uint16(v[21:23]))
+	d.Seq = byteOrder.Uint64(k)
+	d.Status = WebhookStatus(v[0])
+	d.Attempts = byteOrder.Uint32(v[1:5])
+	d.Created = time.Unix(int64(byteOrder.Uint64(v[5:13])), 0)
+	d.NextAttempt = time.Unix(int64(byteOrder.Uint64(v[13:21])), 0)
+	d.URL = string(v[23 : 23+urlLen])
+	d.Payload = append([]byte(nil), v[23+urlLen:]...)
+	return nil
+}
+
+// QueueWebhookDelivery records a new webhook delivery, assigning its sequence
+// number.
+func (s *Store) QueueWebhookDelivery(ns walletdb.ReadWriteBucket, d *WebhookDelivery) error {
+	if len(d.URL) > 0xffff {
+		str := "webhook URL is too long"
+		return storeError(apperrors.ErrInput, str, nil)
+	}
+	b := ns.NestedReadWriteBucket(bucketWebhooks)
+	d.Seq = 0
+	if v := ns.Get(rootWebhookSeq); len(v) == 8 {
+		d.Seq = byteOrder.Uint64(v)
+	} else if k, _ := b.ReadCursor().Last(); len(k) == 8 {
+		d.Seq = byteOrder.Uint64(k) + 1
+	}
+	err := ns.Put(rootWebhookSeq, keyWebhookDelivery(d.Seq+1))
+	if err != nil {
+		str := "failed to put webhook sequence number"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return s.PutWebhookDelivery(ns, d)
+}
+
+// PutWebhookDelivery updates the recorded state of a webhook delivery.
+func (s *Store) PutWebhookDelivery(ns walletdb.ReadWriteBucket, d *WebhookDelivery) error {
+	b := ns.NestedReadWriteBucket(bucketWebhooks)
+	err := b.Put(keyWebhookDelivery(d.Seq), valueWebhookDelivery(d))
+	if err != nil {
+		str := "failed to store webhook delivery"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// RemoveWebhookDelivery removes the delivery with sequence number seq.
+func (s *Store) RemoveWebhookDelivery(ns walletdb.ReadWriteBucket, seq uint64) error {
+	b := ns.NestedReadWriteBucket(bucketWebhooks)
+	err := b.Delete(keyWebhookDelivery(seq))
+	if err != nil {
+		str := "failed to remove webhook delivery"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// ForEachWebhookDelivery calls fn with every recorded webhook delivery, in
+// order of sequence number.
+func (s *Store) ForEachWebhookDelivery(ns walletdb.ReadBucket, fn func(*WebhookDelivery) error) error {
+	return ns.NestedReadBucket(bucketWebhooks).ForEach(func(k, v []byte) error {
+		var d WebhookDelivery
+		err := readRawWebhookDelivery(k, v, &d)
+		if err != nil {
+			return err
+		}
+		return fn(&d)
+	})
+}
+
+// WebhookHeight returns the height of the last block whose transactions were
+// queued for webhook delivery.  ok is false if no blocks have been queued.
+func (s *Store) WebhookHeight(ns walletdb.ReadBucket) (height int32, ok bool) {
+	v := ns.Get(rootWebhookHeight)
+	if len(v) != 4 {
+		return 0, false
+	}
+	return int32(byteOrder.Uint32(v)), true
+}
+
+// PutWebhookHeight records the height of the last block whose transactions
+// were queued for webhook delivery.
+func (s *Store) PutWebhookHeight(ns walletdb.ReadWriteBucket, height int32) error {
+	v := make([]byte, 4)
+	byteOrder.PutUint32(v, uint32(height))
+	err := ns.Put(rootWebhookHeight, v)
+	if err != nil {
+		str := "failed to put webhook height"
+		return storeError(apperrors.ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// RollbackWebhookHeight rewinds the webhook height below height when blocks at
+// or above height are removed from the main chain, so the transactions of the
+// blocks replacing them are queued for delivery.
+func (s *Store) RollbackWebhookHeight(ns walletdb.ReadWriteBucket, height int32) error {
+	last, ok := s.WebhookHeight(ns)
+	if !ok || last < height {
+		return nil
+	}
+	return s.PutWebhookHeight(ns, height-1)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package udb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestWebhookDeliverySeq(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+		queue := func() uint64 {
+			d := &WebhookDelivery{URL: "http://localhost", Created: time.Now()}
+			err := s.QueueWebhookDelivery(ns, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return d.Seq
+		}
+
+		// Databases without a recorded sequence number continue after
+		// the last recorded delivery.
+		err := s.PutWebhookDelivery(ns, &WebhookDelivery{Seq: 4})
+		if err != nil {
+			return err
+		}
+		if seq := queue(); seq != 5 {
+			t.Errorf("queued delivery %d after delivery 4, want 5", seq)
+		}
+
+		// Sequence numbers of removed deliveries are not reused.
+		for _, seq := range []uint64{4, 5} {
+			err := s.RemoveWebhookDelivery(ns, seq)
+			if err != nil {
+				return err
+			}
+		}
+		if seq := queue(); seq != 6 {
+			t.Errorf("queued delivery %d after removing delivery 5, "+
+				"want 6", seq)
+		}
+
+		var seqs []uint64
+		err = s.ForEachWebhookDelivery(ns, func(d *WebhookDelivery) error {
+			seqs = append(seqs, d.Seq)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(seqs) != 1 || seqs[0] != 6 {
+			t.Errorf("recorded deliveries %v, want [6]", seqs)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRollbackWebhookHeight(t *testing.T) {
+	db, s, teardown, err := setup()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrBucketKey)
+
+		// Nothing is recorded when no blocks were queued.
+		err := s.RollbackWebhookHeight(ns, 10)
+		if err != nil {
+			return err
+		}
+		if _, ok := s.WebhookHeight(ns); ok {
+			t.Errorf("rollback recorded a webhook height")
+		}
+
+		err = s.PutWebhookHeight(ns, 20)
+		if err != nil {
+			return err
+		}
+		tests := []struct {
+			rollback, want int32
+		}{
+			{21, 20}, // Above the queued blocks
+			{20, 19},
+			{15, 14},
+		}
+		for _, test := range tests {
+			err := s.RollbackWebhookHeight(ns, test.rollback)
+			if err != nil {
+				return err
+			}
+			height, _ := s.WebhookHeight(ns)
+			if height != test.want {
+				t.Errorf("rollback to %d: webhook height %d, want %d",
+					test.rollback, height, test.want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
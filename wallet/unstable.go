@@ -44,6 +44,58 @@ func (u unstableAPI) RangeTransactions(begin, end int32, f func([]udb.TxDetails)
 	})
 }
 
+// RangeTransactionSummaries calls udb.Store.RangeTransactions under a single
+// database view transaction and summarizes the transactions of each block.
+// When accounts is not empty, only transactions debiting or crediting one of
+// the accounts are summarized, and blocks without any such transactions are
+// skipped.  Unmined transactions are passed with a nil block.
+func (u unstableAPI) RangeTransactionSummaries(begin, end int32, accounts []uint32,
+	f func(*udb.BlockMeta, []TransactionSummary) (bool, error)) error {
+
+	w := u.w
+	return walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		rangeFn := func(details []udb.TxDetails) (bool, error) {
+			txs := make([]TransactionSummary, 0, len(details))
+			for i := range details {
+				s := makeTxSummary(dbtx, w, &details[i])
+				if len(accounts) != 0 && !summaryInvolvesAccounts(&s, accounts) {
+					continue
+				}
+				txs = append(txs, s)
+			}
+			if len(txs) == 0 {
+				return false, nil
+			}
+			var block *udb.BlockMeta
+			if details[0].Block.Height != -1 {
+				b := details[0].Block
+				block = &b
+			}
+			return f(block, txs)
+		}
+		return w.TxStore.RangeTransactions(txmgrNs, begin, end, rangeFn)
+	})
+}
+
+// summaryInvolvesAccounts returns whether a transaction summary records an
+// input or output of any of the accounts.
+func summaryInvolvesAccounts(s *TransactionSummary, accounts []uint32) bool {
+	for _, a := range accounts {
+		for i := range s.MyInputs {
+			if s.MyInputs[i].PreviousAccount == a {
+				return true
+			}
+		}
+		for i := range s.MyOutputs {
+			if s.MyOutputs[i].Account == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // UnspentMultisigCreditsForAddress calls
 // udb.Store.UnspentMultisigCreditsForAddress under a single database view
 // transaction.
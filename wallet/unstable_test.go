@@ -0,0 +1,81 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+)
+
+func TestSummaryInvolvesAccounts(t *testing.T) {
+	s := &TransactionSummary{
+		MyInputs:  []TransactionSummaryInput{{PreviousAccount: 1}},
+		MyOutputs: []TransactionSummaryOutput{{Account: 3}},
+	}
+	tests := []struct {
+		accounts []uint32
+		involved bool
+	}{
+		{[]uint32{1}, true},
+		{[]uint32{3}, true},
+		{[]uint32{0, 2}, false},
+		{[]uint32{2, 3}, true},
+	}
+	for _, test := range tests {
+		if got := summaryInvolvesAccounts(s, test.accounts); got != test.involved {
+			t.Errorf("accounts %v: involved %v, want %v", test.accounts,
+				got, test.involved)
+		}
+	}
+}
+
+func TestRangeTransactionSummaries(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	fundingHash := spend.TxIn[0].PreviousOutPoint.Hash
+	spendHash := spend.TxHash()
+
+	tests := []struct {
+		accounts []uint32
+		txs      int
+	}{
+		{nil, 2},
+		{[]uint32{udb.DefaultAccountNum}, 2},
+		{[]uint32{1}, 0},
+	}
+	for _, test := range tests {
+		var calls, txs int
+		err := UnstableAPI(w).RangeTransactionSummaries(0, -1, test.accounts,
+			func(block *udb.BlockMeta, summaries []TransactionSummary) (bool, error) {
+				calls++
+				if block != nil {
+					t.Errorf("accounts %v: unmined transactions passed "+
+						"with block %v", test.accounts, &block.Hash)
+				}
+				for _, s := range summaries {
+					if *s.Hash != fundingHash && *s.Hash != spendHash {
+						t.Errorf("accounts %v: summarized unexpected "+
+							"transaction %v", test.accounts, s.Hash)
+					}
+				}
+				txs += len(summaries)
+				return false, nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if txs != test.txs {
+			t.Errorf("accounts %v: summarized %d transactions, want %d",
+				test.accounts, txs, test.txs)
+		}
+		if test.txs == 0 && calls != 0 {
+			t.Errorf("accounts %v: called with %d empty blocks",
+				test.accounts, calls)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/HcashOrg/hcd/blockchain"
@@ -9,6 +10,7 @@ import (
 	"github.com/HcashOrg/hcd/txscript"
 	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcwallet/apperrors"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
 )
@@ -130,6 +132,67 @@ func (w *Wallet) SelectInputs(targetAmount hcutil.Amount, policy OutputSelection
 	return
 }
 
+// ReserveOutputs selects spendable outputs of the policy's account which total
+// at least targetAmount and locks them in a single step, so concurrent callers
+// never reserve overlapping outputs.  Outputs are selected largest first and
+// outputs which are already locked are never selected.  The outputs are
+// unlocked when expiry elapses, or earlier by UnlockOutpoint.  An expiry of
+// zero keeps the outputs locked until they are unlocked.
+//
+// The reserved outputs and the time the reservation expires (the zero time if
+// it does not) are returned.  txauthor.InsufficientFundsError is returned if
+// the unlocked outputs do not total targetAmount.
+func (w *Wallet) ReserveOutputs(targetAmount hcutil.Amount, policy OutputSelectionPolicy,
+	expiry time.Duration) ([]udb.Credit, time.Time, error) {
+
+	var eligible []udb.Credit
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+		var err error
+		eligible, err = w.findEligibleOutputs(dbtx, policy.Account,
+			policy.RequiredConfirmations, tipHeight)
+		return err
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	sort.Slice(eligible, func(i, j int) bool {
+		return eligible[i].Amount > eligible[j].Amount
+	})
+
+	// Outputs may have been locked since they were found eligible, so the
+	// locks are checked again while selecting.
+	w.lockedOutpointsMu.Lock()
+	defer w.lockedOutpointsMu.Unlock()
+
+	now := time.Now()
+	var selected []udb.Credit
+	var total hcutil.Amount
+	for i := range eligible {
+		if total >= targetAmount {
+			break
+		}
+		if w.lockedOutpoint(eligible[i].OutPoint, now) {
+			continue
+		}
+		selected = append(selected, eligible[i])
+		total += eligible[i].Amount
+	}
+	if total < targetAmount || len(selected) == 0 {
+		return nil, time.Time{}, txauthor.InsufficientFundsError{}
+	}
+
+	var expires time.Time
+	if expiry > 0 {
+		expires = now.Add(expiry)
+	}
+	for i := range selected {
+		w.lockedOutpoints[selected[i].OutPoint] = expires
+	}
+	return selected, expires, nil
+}
+
 // OutputInfo describes additional info about an output which can be queried
 // using an outpoint.
 type OutputInfo struct {
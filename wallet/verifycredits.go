@@ -0,0 +1,136 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+
+	"github.com/HcashOrg/hcd/blockchain"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// SetVerifyCredits sets whether the merkle inclusion of mined transactions
+// relevant to the wallet is verified against their blocks fetched from the
+// consensus server before the transactions are recorded as mined.  This
+// protects the wallet from notifications of fabricated transactions sent by a
+// malicious or faulty server, at the cost of fetching every block which
+// includes a relevant transaction.
+func (w *Wallet) SetVerifyCredits(verify bool) {
+	w.verifyCreditsMu.Lock()
+	w.verifyCredits = verify
+	w.verifyCreditsMu.Unlock()
+}
+
+// VerifyCredits returns whether the merkle inclusion of mined transactions is
+// verified before they are recorded as mined.
+func (w *Wallet) VerifyCredits() bool {
+	w.verifyCreditsMu.Lock()
+	verify := w.verifyCredits
+	w.verifyCreditsMu.Unlock()
+	return verify
+}
+
+// verifyMinedTransactions checks that each serialized transaction is included
+// in the block with the hash, which is fetched from the consensus server, by
+// the merkle root of the block's regular or stake transaction tree.
+// Transactions which are included are returned as verified.  All others,
+// including every transaction when the block can not be fetched or does not
+// match its hash, are returned as unverified.
+func (w *Wallet) verifyMinedTransactions(blockHash *chainhash.Hash, transactions [][]byte) (verified, unverified [][]byte) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		log.Errorf("Unable to verify transactions mined in block %v: %v",
+			blockHash, err)
+		return nil, transactions
+	}
+	block, err := chainClient.GetBlock(blockHash)
+	if err != nil {
+		log.Errorf("Unable to fetch block %v to verify mined "+
+			"transactions: %v", blockHash, err)
+		return nil, transactions
+	}
+
+	// The fetched header must hash to the block hash, and the fetched
+	// transactions must be committed to by its merkle roots.  Blocks which
+	// do not match are never trusted.
+	fetchedHash := block.Header.BlockHash()
+	if fetchedHash != *blockHash {
+		log.Errorf("Fetched block %v does not match requested block %v",
+			&fetchedHash, blockHash)
+		return nil, transactions
+	}
+	regular := merkleLeaves(block.Transactions, &block.Header.MerkleRoot)
+	stake := merkleLeaves(block.STransactions, &block.Header.StakeRoot)
+	if regular == nil || stake == nil {
+		log.Errorf("Transactions of fetched block %v do not match its "+
+			"merkle roots", blockHash)
+		return nil, transactions
+	}
+
+	for _, serializedTx := range transactions {
+		var tx wire.MsgTx
+		err := tx.Deserialize(bytes.NewReader(serializedTx))
+		if err != nil {
+			unverified = append(unverified, serializedTx)
+			continue
+		}
+		h := tx.TxHashFull()
+		_, inRegular := regular[h]
+		_, inStake := stake[h]
+		if inRegular || inStake {
+			verified = append(verified, serializedTx)
+		} else {
+			unverified = append(unverified, serializedTx)
+		}
+	}
+	return verified, unverified
+}
+
+// merkleLeaves returns the set of full transaction hashes of the merkle tree
+// of transactions, or nil if the root of the tree is not root.
+func merkleLeaves(transactions []*wire.MsgTx, root *chainhash.Hash) map[chainhash.Hash]struct{} {
+	txs := make([]*hcutil.Tx, len(transactions))
+	for i, tx := range transactions {
+		txs[i] = hcutil.NewTx(tx)
+	}
+	merkles := blockchain.BuildMerkleTreeStore(txs)
+	if *merkles[len(merkles)-1] != *root {
+		return nil
+	}
+	leaves := make(map[chainhash.Hash]struct{}, len(transactions))
+	for i := range transactions {
+		leaves[*merkles[i]] = struct{}{}
+	}
+	return leaves
+}
+
+// processUnverifiedTransactions records transactions which were notified as
+// mined in a block, but which could not be verified to be included in it, as
+// unmined transactions.  Their credits remain unconfirmed until the
+// transactions are verified to be mined, such as by a later rescan.
+func (w *Wallet) processUnverifiedTransactions(blockHash *chainhash.Hash, transactions [][]byte) {
+	for _, serializedTx := range transactions {
+		err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			return w.processSerializedTransaction(dbtx, serializedTx, nil, nil,
+				"verifycredits")
+		})
+		if err != nil {
+			log.Errorf("Failed to record unverified transaction of "+
+				"block %v: %v", blockHash, err)
+		}
+	}
+	if len(transactions) != 0 {
+		log.Warnf("Recorded %d transaction(s) of block %v as unmined "+
+			"since their inclusion could not be verified",
+			len(transactions), blockHash)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// MaxVoteDelay is the longest accepted vote delay.  Votes must be received by
+// the miner of the next block before it begins mining, and blocks are often
+// found well before the target block time, so the delay is limited to a small
+// fraction of it on every network.
+const MaxVoteDelay = 10 * time.Second
+
+// SetVoteDelay sets the longest randomized delay before each vote is
+// published.  Votes are published as soon as they are created when zero, and
+// delays longer than MaxVoteDelay are limited to it.
+func (w *Wallet) SetVoteDelay(max time.Duration) {
+	if max > MaxVoteDelay {
+		max = MaxVoteDelay
+	}
+	w.voteDelayMu.Lock()
+	w.voteDelay = max
+	w.voteDelayMu.Unlock()
+}
+
+// VoteDelay returns the longest randomized delay before each vote is
+// published.
+func (w *Wallet) VoteDelay() time.Duration {
+	w.voteDelayMu.Lock()
+	max := w.voteDelay
+	w.voteDelayMu.Unlock()
+	return max
+}
+
+// randomVoteDelay returns a uniformly random delay in [0, max).  Each vote
+// published for a block is delayed independently, so when the wallet votes
+// with several tickets, the order the votes propagate in is random as well as
+// their timing.  Delays are read from a CSPRNG so that peers can not predict
+// the delays of later votes from those already observed.
+func randomVoteDelay(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	var buf [8]byte
+	_, err := rand.Read(buf[:])
+	if err != nil {
+		log.Errorf("Failed to read random vote delay: %v", err)
+		return 0
+	}
+	return time.Duration(binary.LittleEndian.Uint64(buf[:]) % uint64(max))
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+// VoteVersionStatus describes whether the wallet's vote version is older than
+// the stake version of the network.
+type VoteVersionStatus struct {
+	// WalletVersion is the version of the votes created by the wallet.
+	WalletVersion uint32
+
+	// NetworkVersion is the highest stake version of a majority of the
+	// blocks in the last stake version interval connected since the wallet
+	// was started, and NetworkHeight is the height of the block at which
+	// the majority first reached that version.  Both are zero until the
+	// majority of an interval of blocks has been connected.
+	NetworkVersion uint32
+	NetworkHeight  int32
+
+	// Outdated is set while the stake version of the network majority is
+	// newer than the wallet's vote version, and is cleared once the newer
+	// version is no longer the majority of recent blocks.
+	Outdated bool
+
+	// SkipStaleVotes is set when votes are not created while the vote
+	// version is outdated.
+	SkipStaleVotes bool
+}
+
+// SetSkipStaleVotes sets whether votes are withheld while the stake version of
+// the network majority is newer than the wallet's vote version.  The network may
+// reject outdated votes, and operators may prefer to let the tickets be missed
+// and revoked rather than vote with an outdated version.
+func (w *Wallet) SetSkipStaleVotes(skip bool) {
+	w.voteVersionMu.Lock()
+	w.skipStaleVotes = skip
+	w.voteVersionMu.Unlock()
+}
+
+// VoteVersionStatus returns whether the wallet's vote version is older than
+// the stake version of the network.
+func (w *Wallet) VoteVersionStatus() VoteVersionStatus {
+	walletVersion := voteVersion(w.chainParams)
+	w.voteVersionMu.Lock()
+	status := VoteVersionStatus{
+		WalletVersion:  walletVersion,
+		NetworkVersion: w.networkStakeVersion,
+		NetworkHeight:  w.networkStakeVersionHeight,
+		Outdated:       w.networkStakeVersion > walletVersion,
+		SkipStaleVotes: w.skipStaleVotes,
+	}
+	w.voteVersionMu.Unlock()
+	return status
+}
+
+// stakeVersionWindow records the stake versions of the most recently connected
+// blocks, up to a stake version interval of them, and the number of those
+// blocks with each version.
+type stakeVersionWindow struct {
+	versions []uint32
+	next     int
+	counts   map[uint32]int
+}
+
+// add records the stake version of a connected block, replacing the oldest
+// version once the window is full.
+func (sw *stakeVersionWindow) add(version uint32, size int) {
+	if sw.counts == nil {
+		sw.counts = make(map[uint32]int)
+	}
+	if len(sw.versions) < size {
+		sw.versions = append(sw.versions, version)
+	} else {
+		old := sw.versions[sw.next]
+		sw.counts[old]--
+		if sw.counts[old] == 0 {
+			delete(sw.counts, old)
+		}
+		sw.versions[sw.next] = version
+		sw.next = (sw.next + 1) % size
+	}
+	sw.counts[version]++
+}
+
+// majority returns the highest stake version which at least needed blocks of
+// the window have reached, or zero if there is no such version.
+func (sw *stakeVersionWindow) majority(needed int) uint32 {
+	var best uint32
+	for v := range sw.counts {
+		if v <= best {
+			continue
+		}
+		n := 0
+		for v2, count := range sw.counts {
+			if v2 >= v {
+				n += count
+			}
+		}
+		if n >= needed {
+			best = v
+		}
+	}
+	return best
+}
+
+// recordStakeVersion records the stake version of a connected block, and
+// updates the stake version of the network majority, warning when it becomes
+// newer than the wallet's vote version.
+func (w *Wallet) recordStakeVersion(height int32, stakeVersion uint32) {
+	interval := int(w.chainParams.StakeVersionInterval)
+	needed := interval * int(w.chainParams.StakeMajorityMultiplier) /
+		int(w.chainParams.StakeMajorityDivisor)
+
+	w.voteVersionMu.Lock()
+	w.stakeVersions.add(stakeVersion, interval)
+	majority := w.stakeVersions.majority(needed)
+	prev := w.networkStakeVersion
+	if majority == prev {
+		w.voteVersionMu.Unlock()
+		return
+	}
+	w.networkStakeVersion = majority
+	w.networkStakeVersionHeight = height
+	if majority == 0 {
+		w.networkStakeVersionHeight = 0
+	}
+	skip := w.skipStaleVotes
+	w.voteVersionMu.Unlock()
+
+	walletVersion := voteVersion(w.chainParams)
+	switch {
+	case majority > walletVersion && prev <= walletVersion:
+		log.Warnf("Old vote version detected (v%v, the network majority "+
+			"reached stake version v%v at block %v), please update your "+
+			"wallet to the latest version.", walletVersion, majority, height)
+		if skip {
+			log.Warnf("Votes will not be created until the wallet is updated")
+		}
+	case majority <= walletVersion && prev > walletVersion:
+		log.Infof("Stake version of the network majority dropped to v%v "+
+			"at block %v, vote version v%v is current", majority, height,
+			walletVersion)
+	}
+}
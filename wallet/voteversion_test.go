@@ -0,0 +1,88 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import "testing"
+
+func TestStakeVersionWindowMajority(t *testing.T) {
+	var sw stakeVersionWindow
+	for _, v := range []uint32{1, 2, 2, 3} {
+		sw.add(v, 4)
+	}
+	tests := []struct {
+		needed int
+		want   uint32
+	}{
+		{1, 3},
+		{2, 2},
+		{3, 2},
+		{4, 1},
+		{5, 0},
+	}
+	for _, test := range tests {
+		if got := sw.majority(test.needed); got != test.want {
+			t.Errorf("majority(%d) = %d, want %d", test.needed, got, test.want)
+		}
+	}
+
+	// Adding to a full window replaces the oldest version.
+	sw.add(3, 4)
+	if len(sw.versions) != 4 || sw.counts[1] != 0 || sw.counts[3] != 2 {
+		t.Errorf("window versions %v counts %v after replacing the oldest "+
+			"version", sw.versions, sw.counts)
+	}
+	if got := sw.majority(4); got != 2 {
+		t.Errorf("majority(4) = %d after replacement, want 2", got)
+	}
+}
+
+func TestRecordStakeVersion(t *testing.T) {
+	w := &Wallet{chainParams: testParams}
+	interval := int(testParams.StakeVersionInterval)
+	needed := interval * int(testParams.StakeMajorityMultiplier) /
+		int(testParams.StakeMajorityDivisor)
+	walletVersion := voteVersion(testParams)
+
+	height := int32(0)
+	connect := func(n int, version uint32) {
+		for i := 0; i < n; i++ {
+			height++
+			w.recordStakeVersion(height, version)
+		}
+	}
+
+	// A minority of blocks with a newer version does not outdate the
+	// wallet's vote version.
+	connect(needed-1, walletVersion+1)
+	if status := w.VoteVersionStatus(); status.Outdated {
+		t.Fatalf("outdated after %d of %d newer blocks", needed-1, needed)
+	}
+
+	connect(1, walletVersion+1)
+	status := w.VoteVersionStatus()
+	if !status.Outdated {
+		t.Fatal("not outdated after a majority of newer blocks")
+	}
+	if status.NetworkVersion != walletVersion+1 || status.NetworkHeight != height {
+		t.Errorf("network version v%d at height %d, want v%d at height %d",
+			status.NetworkVersion, status.NetworkHeight, walletVersion+1, height)
+	}
+
+	// Fill the window with older blocks.  The newer blocks remain the
+	// majority until the first of them leaves the window.
+	connect(interval-needed, walletVersion)
+	if !w.VoteVersionStatus().Outdated {
+		t.Fatal("outdated status cleared while the majority is newer")
+	}
+	connect(1, walletVersion)
+	status = w.VoteVersionStatus()
+	if status.Outdated {
+		t.Fatal("outdated status was not cleared when the majority dropped")
+	}
+	if status.NetworkVersion != walletVersion || status.NetworkHeight != height {
+		t.Errorf("network version v%d at height %d, want v%d at height %d",
+			status.NetworkVersion, status.NetworkHeight, walletVersion, height)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
@@ -26,20 +25,20 @@ import (
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
 	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	bs "github.com/HcashOrg/hcd/crypto/bliss"
 	"github.com/HcashOrg/hcd/hcec/secp256k1"
 	"github.com/HcashOrg/hcd/hcjson"
 	"github.com/HcashOrg/hcd/hcutil"
 	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
 	"github.com/HcashOrg/hcd/txscript"
 	"github.com/HcashOrg/hcd/wire"
-	"github.com/HcashOrg/hcrpcclient"
 	"github.com/HcashOrg/hcwallet/apperrors"
-	"github.com/HcashOrg/hcwallet/chain"
 	"github.com/HcashOrg/hcwallet/omnilib"
 	"github.com/HcashOrg/hcwallet/wallet/txauthor"
 	"github.com/HcashOrg/hcwallet/wallet/txrules"
 	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
+	"github.com/HcashOrg/hcwallet/walletdb/edb"
 )
 
 const (
@@ -100,17 +99,59 @@ type Wallet struct {
 	initiallyUnlocked bool
 	gapLimit          int
 
-	chainClient     *chain.RPCClient
+	// gapLimitLookahead is the number of addresses watched beyond the gap
+	// limit.  It is protected by addressBuffersMu.
+	gapLimitLookahead uint32
+
+	chainClient     ConsensusClient
 	chainClientLock sync.Mutex
 
-	lockedOutpoints map[wire.OutPoint]struct{}
+	// chainNtfnsWg tracks the goroutines handling the notifications of
+	// chainClient, so the client may be replaced while the wallet runs.
+	chainNtfnsWg sync.WaitGroup
+
+	// healthProbes caches the chain, database, and omni results of
+	// CheckHealth.
+	healthProbes healthProbes
+
+	// Locked outpoints map to the time their lock expires, or the zero time
+	// if they remain locked until explicitly unlocked.
+	lockedOutpoints   map[wire.OutPoint]time.Time
+	lockedOutpointsMu sync.Mutex
+
+	// Idempotency keys of sends in progress map to a channel closed when
+	// the send finishes.
+	idempotentSends   map[string]chan struct{}
+	idempotentSendsMu sync.Mutex
 
 	relayFee               hcutil.Amount
 	relayFeeMu             sync.Mutex
 	ticketFeeIncrementLock sync.Mutex
 	ticketFeeIncrement     hcutil.Amount
 	DisallowFree           bool
-	AllowHighFees          bool
+	allowHighFees          bool
+	txLimits               txauthor.TxLimits
+	splitTxs               bool
+
+	// skipPreflight disables the validation of transaction scripts before
+	// transactions are sent to the consensus RPC server.
+	skipPreflight   bool
+	skipPreflightMu sync.Mutex
+
+	// rescans serializes the wallet's rescans.
+	rescans rescanManager
+
+	// recovery tracks the rescans started after notification errors.
+	recovery recoveryManager
+
+	// Rescan throttling.  rpcLoad must be accessed atomically.
+	rescanLimits   RescanLimits
+	rescanState    RescanState
+	rescanBatch    int
+	rescanStart    int32
+	rescanThrough  int32
+	rescanLimitsMu sync.Mutex
+	rpcLoad        int32
 
 	// Channel for transaction creation requests.
 	consolidateRequests      chan consolidateRequest
@@ -122,6 +163,7 @@ type Wallet struct {
 	createSSGenRequests    chan createSSGenRequest
 	createSSRtxRequests    chan createSSRtxRequest
 	purchaseTicketRequests chan purchaseTicketRequest
+	denominateRequests     chan denominateRequest
 
 	// Internal address handling.
 	addressReuse     bool
@@ -143,8 +185,13 @@ type Wallet struct {
 	sideChain        []sideChainBlock
 	reorganizing     bool
 
-	NtfnServer      *NotificationServer
-	NtfnServerMutex sync.Mutex
+	// blockConnectMu serializes the processing of connected blocks, which
+	// appends to the side chain during reorganizations and coalesces the
+	// transaction notification of each block.  Notifications are only
+	// queued while it is held and are delivered by the notification server.
+	blockConnectMu sync.Mutex
+
+	NtfnServer *NotificationServer
 
 	chainParams *chaincfg.Params
 
@@ -159,14 +206,98 @@ type Wallet struct {
 
 	//Omini  enable omini function
 	enableOmni bool
+
+	// Blocks after which pending omni entries of unmined transactions are
+	// stale.
+	omniPendingExpiry int32
+	omniPendingMu     sync.Mutex
+
+	// Automatic transaction history pruning.
+	historyPruneDepth int32
+	historyPruneMu    sync.Mutex
+
+	// Memoized account names and numbers.
+	accountNames accountNameCache
+
+	// Memoized default addresses of accounts.
+	defaultAddrs defaultAddrCache
+
+	// Merkle inclusion verification of mined transactions.
+	verifyCredits   bool
+	verifyCreditsMu sync.Mutex
+
+	// Automatic refunds of matured timelocked scripts, and whether
+	// automatic refunds are running.
+	autoRefund     bool
+	autoRefundBusy bool
+	autoRefundMu   sync.Mutex
+
+	// Parsed refundable scripts of the script store keyed by script hash,
+	// or nil before the index is first used.
+	refundIndex   map[[20]byte]*RefundableScript
+	refundIndexMu sync.Mutex
+
+	// Disables automatic revocation of missed and expired tickets.
+	disableAutoRevoke   bool
+	disableAutoRevokeMu sync.Mutex
+
+	// Longest randomized delay before publishing each vote.
+	voteDelay   time.Duration
+	voteDelayMu sync.Mutex
+
+	// Stake versions of recently connected block headers, the stake
+	// version of their majority, and whether votes are withheld while it
+	// exceeds the wallet's vote version.
+	stakeVersions             stakeVersionWindow
+	networkStakeVersion       uint32
+	networkStakeVersionHeight int32
+	skipStaleVotes            bool
+	voteVersionMu             sync.Mutex
+
+	// Number of blocks between snapshots of the account balances.
+	balanceSnapshotInterval int32
+	balanceSnapshotMu       sync.Mutex
+
+	// Fiat valuation of exported transactions.
+	priceSource   PriceSource
+	priceSourceMu sync.Mutex
+
+	// Handling of reused external addresses.
+	addressReusePolicy   AddressReusePolicy
+	addressReusePolicyMu sync.Mutex
+
+	// Consolidation of dust outputs.
+	dustConsolidation   DustConsolidationPolicy
+	dustConsolidationMu sync.Mutex
+
+	// Automatic splitting of ticket purchase funds into ticket sized
+	// outputs, the height of the last automatic attempt, and whether an
+	// attempt is running.
+	ticketDenominations       TicketDenominationPolicy
+	ticketDenominationsHeight int32
+	ticketDenominationsBusy   bool
+	ticketDenominationsMu     sync.Mutex
+
+	// Received payment webhooks.  webhookWake is signaled when new
+	// notifications are queued or the configuration changes.
+	webhooks    *WebhookConfig
+	webhooksMu  sync.Mutex
+	webhookWake chan struct{}
+
+	// Heights of connected blocks whose maintenance has not yet been run
+	// by postBlockWorker.  postBlockWake is signaled when heights are
+	// queued.
+	postBlockHeights []int32
+	postBlockMu      sync.Mutex
+	postBlockWake    chan struct{}
 }
 
 // newWallet creates a new Wallet structure with the provided address manager
 // and transaction store.
 func newWallet(votingEnabled bool, addressReuse bool, ticketAddress, subsidyAddress hcutil.Address,
 	poolAddress hcutil.Address, pf float64, relayFee, ticketFee hcutil.Amount,
-	gapLimit int, stakePoolColdAddrs map[string]struct{}, AllowHighFees bool,
-	mgr *udb.Manager, txs *udb.Store, smgr *udb.StakeStore, db *walletdb.DB,
+	gapLimit int, stakePoolColdAddrs map[string]struct{}, allowHighFees bool,
+	txLimits txauthor.TxLimits, splitTxs bool, mgr *udb.Manager, txs *udb.Store, smgr *udb.StakeStore, db *walletdb.DB,
 	params *chaincfg.Params, privpass []byte, enableOmni bool) (*Wallet, error) {
 
 	w := &Wallet{
@@ -175,10 +306,13 @@ func newWallet(votingEnabled bool, addressReuse bool, ticketAddress, subsidyAddr
 		TxStore:                  txs,
 		StakeMgr:                 smgr,
 		votingEnabled:            votingEnabled,
-		lockedOutpoints:          map[wire.OutPoint]struct{}{},
+		lockedOutpoints:          make(map[wire.OutPoint]time.Time),
+		idempotentSends:          make(map[string]chan struct{}),
 		relayFee:                 relayFee,
 		ticketFeeIncrement:       ticketFee,
-		AllowHighFees:            AllowHighFees,
+		allowHighFees:            allowHighFees,
+		txLimits:                 txLimits,
+		splitTxs:                 splitTxs,
 		consolidateRequests:      make(chan consolidateRequest),
 		createTxRequests:         make(chan createTxRequest),
 		createMultisigTxRequests: make(chan createMultisigTxRequest),
@@ -186,7 +320,9 @@ func newWallet(votingEnabled bool, addressReuse bool, ticketAddress, subsidyAddr
 		createSSGenRequests:      make(chan createSSGenRequest),
 		createSSRtxRequests:      make(chan createSSRtxRequest),
 		purchaseTicketRequests:   make(chan purchaseTicketRequest),
+		denominateRequests:       make(chan denominateRequest),
 		addressReuse:             addressReuse,
+		addressReusePolicy:       AddressReuseWarn,
 		ticketAddress:            ticketAddress,
 		subsidyAddress:           subsidyAddress,
 		addressBuffers:           make(map[uint32]*bip0044AccountData),
@@ -204,7 +340,13 @@ func newWallet(votingEnabled bool, addressReuse bool, ticketAddress, subsidyAddr
 		changePassphrase:         make(chan changePassphraseRequest),
 		chainParams:              params,
 		enableOmni:               enableOmni,
+		webhookWake:              make(chan struct{}, 1),
+		postBlockWake:            make(chan struct{}, 1),
 		quit:                     make(chan struct{}),
+		dustConsolidation: DustConsolidationPolicy{
+			Threshold: DefaultDustThreshold,
+			MinCount:  DefaultDustMinCount,
+		},
 	}
 
 	w.wg.Add(1)
@@ -280,6 +422,13 @@ func newWallet(votingEnabled bool, addressReuse bool, ticketAddress, subsidyAddr
 					cursor:     props.LastReturnedInternalIndex - props.LastUsedInternalIndex,
 				},
 			}
+			if props.AccountType == udb.AcctypeEc {
+				err = w.loadExternalBranchBuffers(ns, acct, xpub,
+					w.addressBuffers[acct])
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		vb = w.readDBVoteBits(tx)
@@ -555,6 +704,22 @@ func (w *Wallet) EnableOmni() bool {
 	return w.enableOmni
 }
 
+// OmniWaterline returns the height of the last block processed by omni.
+func (w *Wallet) OmniWaterline() (int32, error) {
+	req := omnilib.Request{
+		Method: "omni_getwaterline",
+	}
+	result, err := omnilib.Send(&req)
+	if err != nil {
+		return 0, err
+	}
+	height, err := strconv.Atoi(string(result))
+	if err != nil {
+		return 0, err
+	}
+	return int32(height), nil
+}
+
 // Start starts the goroutines necessary to manage a wallet.
 func (w *Wallet) Start() {
 	w.quitMu.Lock()
@@ -573,8 +738,10 @@ func (w *Wallet) Start() {
 	}
 	w.quitMu.Unlock()
 
-	w.wg.Add(1)
+	w.wg.Add(3)
 	go w.txCreator()
+	go w.webhookDispatcher()
+	go w.postBlockWorker()
 }
 
 func (w *Wallet) ReconnectStart() {
@@ -594,9 +761,11 @@ func (w *Wallet) ReconnectStart() {
 	}
 	w.quitMu.Unlock()
 
-	w.wg.Add(2)
+	w.wg.Add(4)
 	go w.txCreator()
 	go w.walletLocker()
+	go w.webhookDispatcher()
+	go w.postBlockWorker()
 }
 
 // SynchronizeRPC associates the wallet with the consensus RPC client,
@@ -605,7 +774,7 @@ func (w *Wallet) ReconnectStart() {
 //
 // This method is unstable and will be removed when all syncing logic is moved
 // outside of the wallet package.
-func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
+func (w *Wallet) SynchronizeRPC(chainClient ConsensusClient) {
 	w.quitMu.Lock()
 	select {
 	case <-w.quit:
@@ -635,8 +804,8 @@ func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
 	} else {
 		log.Infof("Transactions synced through block %v height %d", &tipHash, tipHeight)
 	}
-	// TODO: Ignoring the new client when one is already set breaks callers
-	// who are replacing the client, perhaps after a disconnect.
+	// Callers replacing the client must first detach the previous client
+	// with DisassociateConsensusRPC.
 	w.chainClientLock.Lock()
 	if w.chainClient != nil {
 		w.chainClientLock.Unlock()
@@ -650,6 +819,7 @@ func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
 	// make changes from the RPC client) and not have to stop and
 	// restart them each time the client disconnects and reconnets.
 	w.wg.Add(2)
+	w.chainNtfnsWg.Add(2)
 	go w.handleChainNotifications(chainClient)
 	go w.handleChainVotingNotifications(chainClient)
 
@@ -686,14 +856,14 @@ func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
 // consensus RPC server is set.  This function and all functions that call it
 // are unstable and will need to be moved when the syncing code is moved out of
 // the wallet.
-func (w *Wallet) requireChainClient() (*hcrpcclient.Client, error) {
+func (w *Wallet) requireChainClient() (ChainClient, error) {
 	w.chainClientLock.Lock()
 	chainClient := w.chainClient
 	w.chainClientLock.Unlock()
 	if chainClient == nil {
 		return nil, errors.New("blockchain RPC is inactive")
 	}
-	return chainClient.Client, nil
+	return chainClient, nil
 }
 
 // ChainClient returns the optional consensus RPC client associated with the
@@ -701,14 +871,14 @@ func (w *Wallet) requireChainClient() (*hcrpcclient.Client, error) {
 //
 // This function is unstable and will be removed once sync logic is moved out of
 // the wallet.
-func (w *Wallet) ChainClient() *hcrpcclient.Client {
+func (w *Wallet) ChainClient() ChainClient {
 	w.chainClientLock.Lock()
 	chainClient := w.chainClient
 	w.chainClientLock.Unlock()
 	if chainClient == nil {
 		return nil
 	}
-	return chainClient.Client
+	return chainClient
 }
 
 // RelayFee returns the current minimum relay fee (per kB of serialized
@@ -728,6 +898,56 @@ func (w *Wallet) SetRelayFee(relayFee hcutil.Amount) {
 	w.relayFeeMu.Unlock()
 }
 
+// accountFee returns the fee setting of feeType saved for an account, or def
+// if the account does not override the wallet's global setting.
+func (w *Wallet) accountFee(dbtx walletdb.ReadTx, account uint32,
+	feeType udb.AccountFeeType, def hcutil.Amount) hcutil.Amount {
+
+	fee, ok := udb.AccountFee(dbtx, account, feeType)
+	if !ok {
+		return def
+	}
+	return fee
+}
+
+// setAccountFee saves a fee setting for an existing account.
+func (w *Wallet) setAccountFee(account uint32, feeType udb.AccountFeeType, fee hcutil.Amount) error {
+	return walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		_, err := w.Manager.AccountName(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		return udb.SetAccountFee(dbtx, account, feeType, fee)
+	})
+}
+
+// AccountRelayFee returns the minimum relay fee (per kB of serialized
+// transaction) used when constructing transactions for an account.  This is
+// the wallet's global relay fee unless the account has its own relay fee set.
+func (w *Wallet) AccountRelayFee(account uint32) (hcutil.Amount, error) {
+	fee := w.RelayFee()
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		fee = w.accountFee(dbtx, account, udb.AccountRelayFee, fee)
+		return nil
+	})
+	return fee, err
+}
+
+// SetAccountRelayFee sets the minimum relay fee (per kB of serialized
+// transaction) used when constructing transactions for an account, overriding
+// the wallet's global relay fee.
+func (w *Wallet) SetAccountRelayFee(account uint32, relayFee hcutil.Amount) error {
+	return w.setAccountFee(account, udb.AccountRelayFee, relayFee)
+}
+
+// AllowHighFees returns the default for whether transactions sent to the
+// consensus RPC server may pay fees that would otherwise be rejected as too
+// high.  RPC requests may override this default.
+func (w *Wallet) AllowHighFees() bool {
+	return w.allowHighFees
+}
+
 // TicketFeeIncrement is used to get the current feeIncrement for the wallet.
 func (w *Wallet) TicketFeeIncrement() hcutil.Amount {
 	w.ticketFeeIncrementLock.Lock()
@@ -744,6 +964,25 @@ func (w *Wallet) SetTicketFeeIncrement(fee hcutil.Amount) {
 	w.ticketFeeIncrementLock.Unlock()
 }
 
+// AccountTicketFeeIncrement returns the ticket fee increment used when
+// purchasing tickets from an account.  This is the wallet's global ticket fee
+// increment unless the account has its own ticket fee set.
+func (w *Wallet) AccountTicketFeeIncrement(account uint32) (hcutil.Amount, error) {
+	fee := w.TicketFeeIncrement()
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		fee = w.accountFee(dbtx, account, udb.AccountTicketFee, fee)
+		return nil
+	})
+	return fee, err
+}
+
+// SetAccountTicketFeeIncrement sets the ticket fee increment used when
+// purchasing tickets from an account, overriding the wallet's global ticket
+// fee increment.
+func (w *Wallet) SetAccountTicketFeeIncrement(account uint32, fee hcutil.Amount) error {
+	return w.setAccountFee(account, udb.AccountTicketFee, fee)
+}
+
 // quitChan atomically reads the quit channel.
 func (w *Wallet) quitChan() <-chan struct{} {
 	w.quitMu.Lock()
@@ -850,7 +1089,7 @@ func (w *Wallet) BlockHeader(blockHash *chainhash.Hash) (*wire.BlockHeader, erro
 // loadActiveAddrs loads the consensus RPC server with active addresses for
 // transaction notifications.  For logging purposes, it returns the total number
 // of addresses loaded.
-func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.Client) (uint64, error) {
+func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient ChainClient) (uint64, error) {
 	pool := sync.Pool{New: func() interface{} { return make([]hcutil.Address, 0, 256) }}
 	recycleAddrs := func(addrs []hcutil.Address) { pool.Put(addrs[:0]) }
 	getAddrs := func() []hcutil.Address { return pool.Get().([]hcutil.Address) }
@@ -874,9 +1113,9 @@ func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.
 					}
 					addrs = append(addrs, addr)
 				}
-				future := chainClient.LoadTxFilterAsync(false, addrs, nil)
+				err := chainClient.LoadTxFilter(false, addrs, nil)
 				recycleAddrs(addrs)
-				jobErrs <- future.Receive()
+				jobErrs <- err
 			}(child)
 		}
 		for i := 0; i < cap(jobErrs); i++ {
@@ -919,9 +1158,9 @@ func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.
 					}
 					addrs = append(addrs, addr)
 				}
-				future := chainClient.LoadTxFilterAsync(false, addrs, nil)
+				err := chainClient.LoadTxFilter(false, addrs, nil)
 				recycleAddrs(addrs)
-				jobErrs <- future.Receive()
+				jobErrs <- err
 			}(child)
 		}
 		for i := 0; i < cap(jobErrs); i++ {
@@ -940,6 +1179,14 @@ func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.
 	}
 	errs := make(chan error, int(lastAcct+1)*2+1)
 	var bip0044AddrCount, importedAddrCount uint64
+
+	// Additional external branches are only created for ECDSA accounts,
+	// and are loaded after the BIP0044 branches.
+	type extraBranch struct {
+		key *hdkeychain.ExtendedKey
+		n   uint32
+	}
+	var extraBranches []extraBranch
 	for acct := uint32(0); acct <= lastAcct; acct++ {
 		var xpub, xpriv, extKey, intKey, intKeypriv, extKeypriv *hdkeychain.ExtendedKey
 		props, err := w.Manager.AccountProperties(addrmgrNs, acct)
@@ -992,7 +1239,39 @@ func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.
 		// number of watched addresses is one more for each branch due to zero
 		// indexing.
 		bip0044AddrCount += uint64(extn) + uint64(intn) + 2
+
+		if props.AccountType != udb.AcctypeEc {
+			continue
+		}
+		err = w.Manager.ForEachExternalBranch(addrmgrNs, acct, func(b *udb.BranchProperties) error {
+			key, err := xpub.Child(b.Branch)
+			if err != nil {
+				return err
+			}
+			key.ECPubKey()
+			n := minUint32(b.LastReturnedIndex+gapLimit, hdkeychain.HardenedKeyStart-1)
+			extraBranches = append(extraBranches, extraBranch{key, n})
+			bip0044AddrCount += uint64(n) + 1
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	// Invoice addresses are derived from invoice ids rather than handed out
+	// in order, so each is loaded individually.
+	invoiceAddrs, err := w.invoiceAddresses(dbtx)
+	if err != nil {
+		return 0, err
+	}
+	bip0044AddrCount += uint64(len(invoiceAddrs))
+	extraErrs := make(chan error, len(extraBranches)+1)
+	for _, b := range extraBranches {
+		go loadBranchAddrs(b.key, b.n, extraErrs)
 	}
+	go func() {
+		extraErrs <- chainClient.LoadTxFilter(false, invoiceAddrs, nil)
+	}()
 	go func() {
 		// Imported addresses are still sent as a single slice for now.  Could
 		// use the optimization above to avoid appends and reallocations.
@@ -1015,6 +1294,12 @@ func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.
 			return bip0044AddrCount + importedAddrCount, err
 		}
 	}
+	for i := 0; i < cap(extraErrs); i++ {
+		err := <-extraErrs
+		if err != nil {
+			return bip0044AddrCount + importedAddrCount, err
+		}
+	}
 
 	return bip0044AddrCount + importedAddrCount, nil
 }
@@ -1022,7 +1307,7 @@ func (w *Wallet) loadActiveAddrs(dbtx walletdb.ReadTx, chainClient *hcrpcclient.
 // LoadActiveDataFilters loads the consensus RPC server's websocket client
 // transaction filter with all active addresses and unspent outpoints for this
 // wallet.
-func (w *Wallet) LoadActiveDataFilters(chainClient *hcrpcclient.Client) error {
+func (w *Wallet) LoadActiveDataFilters(chainClient ChainClient) error {
 	log.Infof("Loading active addresses and unspent outputs...")
 
 	var addrCount, utxoCount uint64
@@ -1075,7 +1360,7 @@ func createHeaderData(headers []string) ([]udb.BlockHeaderData, error) {
 	return data, nil
 }
 
-func (w *Wallet) fetchHeaders(chainClient *hcrpcclient.Client) (int, error) {
+func (w *Wallet) fetchHeaders(chainClient ChainClient) (int, error) {
 	fetchedHeaders := 0
 
 	var blockLocators []chainhash.Hash
@@ -1104,6 +1389,12 @@ func (w *Wallet) fetchHeaders(chainClient *hcrpcclient.Client) (int, error) {
 		if err != nil {
 			return 0, err
 		}
+		for i := range headerData {
+			err := w.TxStore.CheckProofOfWork(&headerData[i])
+			if err != nil {
+				return 0, chainFault("getheaders", err)
+			}
+		}
 
 		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
 			addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
@@ -1117,7 +1408,7 @@ func (w *Wallet) fetchHeaders(chainClient *hcrpcclient.Client) (int, error) {
 			return nil
 		})
 		if err != nil {
-			return 0, err
+			return 0, chainFault("getheaders", err)
 		}
 
 		fetchedHeaders += len(response.Headers)
@@ -1129,7 +1420,7 @@ func (w *Wallet) fetchHeaders(chainClient *hcrpcclient.Client) (int, error) {
 // returned, along with the hash of the first previously-unseen block hash now
 // in the main chain.  This is the block a rescan should begin at (inclusive),
 // and is only relevant when the number of fetched headers is not zero.
-func (w *Wallet) FetchHeaders(chainClient *hcrpcclient.Client) (count int, rescanFrom chainhash.Hash, rescanFromHeight int32,
+func (w *Wallet) FetchHeaders(chainClient ChainClient) (count int, rescanFrom chainhash.Hash, rescanFromHeight int32,
 	mainChainTipBlockHash chainhash.Hash, mainChainTipBlockHeight int32, err error) {
 
 	// Unfortunately, getheaders is broken and needs a workaround when wallet's
@@ -1175,7 +1466,7 @@ func (w *Wallet) FetchHeaders(chainClient *hcrpcclient.Client) (count int, resca
 		// now begin here, avoiding any issues with calling getheaders with
 		// side chain hashes.
 		log.Infof("rollback from current height %n to height %n", commonAncestorHeight, height+1)
-		return w.RollBack(tx, height+1, hashs)
+		return w.RollBack(tx, height+1, hashs, "sync")
 	})
 	if err != nil {
 		return
@@ -1254,15 +1545,25 @@ func (w *Wallet) FetchHeaders(chainClient *hcrpcclient.Client) (count int, resca
 // syncWithChain brings the wallet up to date with the current chain server
 // connection.  It creates a rescan request and blocks until the rescan has
 // finished.
-func (w *Wallet) syncWithChain(chainClient *hcrpcclient.Client) error {
+//
+// Progress is checkpointed in the database as the sync proceeds.  If a
+// previous sync was interrupted, address discovery resumes from its last
+// checkpoint, and the rescan resumes from the last block with processed
+// transactions.  Blocks before the wallet birthday are never rescanned.
+func (w *Wallet) syncWithChain(chainClient ChainClient) error {
 	// Request notifications for connected and disconnected blocks.
 	err := chainClient.NotifyBlocks()
 	if err != nil {
 		return err
 	}
 
+	ckpt, err := w.loadSyncCheckpoint()
+	if err != nil {
+		return err
+	}
+
 	// Discover any addresses for this wallet that have not yet been created.
-	err = w.DiscoverActiveAddresses(chainClient, w.initiallyUnlocked)
+	err = w.discoverActiveAddresses(chainClient, w.initiallyUnlocked, ckpt)
 	if err != nil {
 		return err
 	}
@@ -1273,10 +1574,22 @@ func (w *Wallet) syncWithChain(chainClient *hcrpcclient.Client) error {
 		return err
 	}
 	err = chainClient.SetParams(w.EnableOmni())
+	err = w.resolvePendingReorg(chainClient)
+	if err != nil {
+		return err
+	}
 	_, err = w.fetchHeaders(chainClient)
 	if err != nil {
 		return err
 	}
+	err = w.skipToBirthday()
+	if err != nil {
+		return err
+	}
+	err = ckpt.checkpoint()
+	if err != nil {
+		return err
+	}
 
 	rescanHeight, rescanPoint, err := w.GetWalletSyncHeight()
 	if err != nil {
@@ -1288,6 +1601,11 @@ func (w *Wallet) syncWithChain(chainClient *hcrpcclient.Client) error {
 		return err
 	}
 
+	err = ckpt.finish()
+	if err != nil {
+		return err
+	}
+
 	w.resendUnminedTxs(chainClient)
 
 	// Send winning and missed ticket notifications out so that the wallet
@@ -1337,20 +1655,11 @@ func (w *Wallet) GetWalletSyncHeight() (uint32, *chainhash.Hash, error) {
 	req := omnilib.Request{
 		Method: "omni_getwaterline",
 	}
-	bytes, err := json.Marshal(req)
-	if err != nil {
-		return 0, nil, err
-	}
-	strRsp := omnilib.JsonCmdReqHcToOm(string(bytes))
-	var response hcjson.Response
-	err = json.Unmarshal([]byte(strRsp), &response)
+	result, err := omnilib.Send(&req)
 	if err != nil {
 		return 0, nil, err
 	}
-	if response.Error != nil {
-		return 0, nil, fmt.Errorf(response.Error.Message)
-	}
-	omniRollbackHeight, err := strconv.Atoi(string(response.Result))
+	omniRollbackHeight, err := strconv.Atoi(string(result))
 
 	if omniRollbackHeight > int(rescanHeight) {
 		omniRollbackHeight = int(rescanHeight)
@@ -1391,6 +1700,7 @@ type (
 		inputs  int
 		account uint32
 		address hcutil.Address
+		opts    consolidateOptions
 		resp    chan consolidateResponse
 	}
 	createTxRequest struct {
@@ -1442,10 +1752,15 @@ type (
 		ticketFee   hcutil.Amount
 		resp        chan purchaseTicketResponse
 	}
+	denominateRequest struct {
+		account      uint32
+		denomination hcutil.Amount
+		resp         chan denominateResponse
+	}
 
 	consolidateResponse struct {
-		txHash *chainhash.Hash
-		err    error
+		results []ConsolidationResult
+		err     error
 	}
 	createTxResponse struct {
 		tx  *txauthor.AuthoredTx
@@ -1470,9 +1785,13 @@ type (
 		err error
 	}
 	purchaseTicketResponse struct {
-		data []*chainhash.Hash
+		data *PurchaseTicketsResult
 		err  error
 	}
+	denominateResponse struct {
+		txHash *chainhash.Hash
+		err    error
+	}
 )
 
 // txCreator is responsible for the input selection and creation of
@@ -1496,9 +1815,10 @@ out:
 				txr.resp <- consolidateResponse{nil, err}
 				continue
 			}
-			txh, err := w.compressWallet(txr.inputs, txr.account, txr.address)
+			results, err := w.compressWallet(txr.inputs, txr.account, txr.address,
+				&txr.opts)
 			heldUnlock.release()
-			txr.resp <- consolidateResponse{txh, err}
+			txr.resp <- consolidateResponse{results, err}
 
 		case txr := <-w.createTxRequests:
 			heldUnlock, err := w.holdUnlock()
@@ -1571,6 +1891,16 @@ out:
 			heldUnlock.release()
 			txr.resp <- purchaseTicketResponse{data, err}
 
+		case txr := <-w.denominateRequests:
+			heldUnlock, err := w.holdUnlock()
+			if err != nil {
+				txr.resp <- denominateResponse{nil, err}
+				continue
+			}
+			txHash, err := w.denominateTicketFunds(txr.account, txr.denomination)
+			heldUnlock.release()
+			txr.resp <- denominateResponse{txHash, err}
+
 		case <-quit:
 			break out
 		}
@@ -1581,17 +1911,61 @@ out:
 // Consolidate consolidates as many UTXOs as are passed in the inputs argument.
 // If that many UTXOs can not be found, it will use the maximum it finds. This
 // will only compress UTXOs in the default account
+//
+// A single transaction is created unless it would exceed the wallet's
+// transaction limits and splitting transactions is enabled, in which case the
+// UTXOs are consolidated by multiple transactions.  The hashes of all
+// published transactions are returned.
 func (w *Wallet) Consolidate(inputs int, account uint32,
-	address hcutil.Address) (*chainhash.Hash, error) {
+	address hcutil.Address) ([]*chainhash.Hash, error) {
+	results, err := w.consolidate(inputs, account, address, consolidateOptions{})
+	return consolidationHashes(results), err
+}
+
+// ConsolidateAccount consolidates at most inputs UTXOs of an account into a
+// single output paying address, or a new internal address of the account if
+// address is nil.  The transactions pay feeRate per kB, or the relay fee of
+// the account if feeRate is zero, and creating a transaction fails if its fee
+// would exceed a non-zero maxFee.  As with Consolidate, multiple transactions
+// are created when splitting transactions is enabled.  All published
+// transactions are returned, even if an error occurs after some have been
+// published.
+func (w *Wallet) ConsolidateAccount(inputs int, account uint32, address hcutil.Address,
+	feeRate, maxFee hcutil.Amount) ([]ConsolidationResult, error) {
+
+	if feeRate < 0 || maxFee < 0 {
+		const str = "fee rate and maximum fee may not be negative"
+		return nil, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: nil}
+	}
+	return w.consolidate(inputs, account, address, consolidateOptions{
+		feeRate: feeRate,
+		maxFee:  maxFee,
+	})
+}
+
+// consolidate serializes the consolidation of at most inputs UTXOs of an
+// account with other transaction creation.
+func (w *Wallet) consolidate(inputs int, account uint32, address hcutil.Address,
+	opts consolidateOptions) ([]ConsolidationResult, error) {
 	req := consolidateRequest{
 		inputs:  inputs,
 		account: account,
 		address: address,
+		opts:    opts,
 		resp:    make(chan consolidateResponse),
 	}
 	w.consolidateRequests <- req
 	resp := <-req.resp
-	return resp.txHash, resp.err
+	return resp.results, resp.err
+}
+
+// consolidationHashes returns the transaction hashes of consolidations.
+func consolidationHashes(results []ConsolidationResult) []*chainhash.Hash {
+	hashes := make([]*chainhash.Hash, len(results))
+	for i := range results {
+		hashes[i] = &results[i].Hash
+	}
+	return hashes
 }
 
 // CreateSimpleTx creates a new signed transaction spending unspent P2PKH
@@ -1689,12 +2063,14 @@ func (w *Wallet) CreateSSRtx(ticketHash chainhash.Hash) (*CreatedTx, error) {
 }
 
 // PurchaseTickets receives a request from the RPC and ships it to txCreator
-// to purchase a new ticket. It returns a slice of the hashes of the purchased
-// tickets.
+// to purchase new tickets.  An error is only returned when no transactions
+// were published.  Once the split transaction is published, the result
+// describes every published ticket and every ticket which failed to be
+// purchased, as some tickets may be published before others fail.
 func (w *Wallet) PurchaseTickets(minBalance, spendLimit hcutil.Amount,
 	minConf int32, ticketAddr hcutil.Address, account uint32,
 	numTickets int, poolAddress hcutil.Address, poolFees float64,
-	expiry int32, txFee hcutil.Amount, ticketFee hcutil.Amount) ([]*chainhash.Hash,
+	expiry int32, txFee hcutil.Amount, ticketFee hcutil.Amount) (*PurchaseTicketsResult,
 	error) {
 
 	req := purchaseTicketRequest{
@@ -1898,11 +2274,23 @@ func (w *Wallet) ChangePrivatePassphrase(old, new []byte) error {
 	return <-err
 }
 
-// ChangePublicPassphrase modifies the public passphrase of the wallet.
+// ChangePublicPassphrase modifies the public passphrase of the wallet.  The
+// wallet database is reencrypted with the new passphrase when it was created
+// with database encryption.
 func (w *Wallet) ChangePublicPassphrase(old, new []byte) error {
 	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
 		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
-		return w.Manager.ChangePassphrase(addrmgrNs, old, new, false)
+		err := w.Manager.ChangePassphrase(addrmgrNs, old, new, false)
+		if err != nil {
+			return err
+		}
+		// Databases encrypted with the public passphrase must be
+		// reencrypted with the new passphrase.
+		err = edb.ChangePassphrase(tx, new)
+		if err == edb.ErrNotEncrypted {
+			return nil
+		}
+		return err
 	})
 }
 
@@ -1924,7 +2312,8 @@ func (w *Wallet) CalculateAccountBalance(account uint32, confirms int32) (udb.Ba
 
 // CalculateAccountBalances calculates the values for the wtxmgr struct Balance,
 // which includes the total balance, the spendable balance, and the balance
-// which has yet to mature.
+// which has yet to mature.  Outputs are only spendable once they also meet the
+// spendable confirmations of their account.
 func (w *Wallet) CalculateAccountBalances(confirms int32) (map[uint32]*udb.Balances, error) {
 	balances := make(map[uint32]*udb.Balances)
 
@@ -1950,6 +2339,35 @@ func (w *Wallet) CalculateAccountBalances(confirms int32) (map[uint32]*udb.Balan
 // spending to it in the blockchain or hcd mempool), the next chained address
 // is returned.
 func (w *Wallet) CurrentAddress(account uint32) (hcutil.Address, error) {
+	addr, err := w.currentAddress(account)
+	if err != nil || w.AddressReusePolicy() != AddressReuseRefuse {
+		return addr, err
+	}
+
+	// The address buffers are only updated after recorded transactions
+	// are processed, so the current address may already be paid.  Skip
+	// over paid addresses, which are returned once more by
+	// NewExternalAddress before advancing.
+	for {
+		paid, err := w.addressPaid(addr)
+		if err != nil || !paid {
+			return addr, err
+		}
+		log.Infof("Skipping paid address %v of account %d", addr, account)
+		_, err = w.NewExternalAddress(account, WithGapPolicyIgnore())
+		if err != nil {
+			return nil, err
+		}
+		addr, err = w.currentAddress(account)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// currentAddress returns the next external address of an account from the
+// address buffers.
+func (w *Wallet) currentAddress(account uint32) (hcutil.Address, error) {
 	var child *hdkeychain.ExtendedKey
 	var err error
 	defer w.addressBuffersMu.Unlock()
@@ -2012,13 +2430,31 @@ func (w *Wallet) PubKeyForAddress(a hcutil.Address) (chainec.PublicKey, error) {
 	return pubKey, err
 }
 
-// SignMessage returns the signature of a signed message using an address'
-// associated private key.
-func (w *Wallet) SignMessage(msg string, addr hcutil.Address) (sig []byte, err error) {
+// messageHash returns the hash of msg which is signed to sign a message.
+func messageHash(msg string) []byte {
 	var buf bytes.Buffer
 	wire.WriteVarString(&buf, 0, "Hc Signed Message:\n")
 	wire.WriteVarString(&buf, 0, msg)
-	messageHash := chainhash.HashB(buf.Bytes())
+	return chainhash.HashB(buf.Bytes())
+}
+
+// Signatures of messages signed by bliss keys use an extended format, since
+// bliss public keys can not be recovered from signatures like secp256k1 public
+// keys are recovered from compact signatures.  The extended format is
+// serialized as such:
+//
+//   [0]     Signature type (1 byte, always bs.BSTypeBliss)
+//   [1:898] Bliss public key (bs.BlissPubKeyLen bytes)
+//   [898:]  Bliss signature
+//
+// The first byte of compact secp256k1 signatures is never less than 27, so
+// the formats can not be confused.
+
+// SignMessage returns the signature of a signed message using an address'
+// associated private key.  Messages signed by bliss keys return signatures in
+// the extended format for bliss signatures.
+func (w *Wallet) SignMessage(msg string, addr hcutil.Address) (sig []byte, err error) {
+	hash := messageHash(msg)
 	var privKey chainec.PrivateKey
 	var done func()
 	defer func() {
@@ -2035,23 +2471,35 @@ func (w *Wallet) SignMessage(msg string, addr hcutil.Address) (sig []byte, err e
 	if err != nil {
 		return nil, err
 	}
-	pkCast, ok := privKey.(*secp256k1.PrivateKey)
-	if !ok {
-		return nil, fmt.Errorf("Unable to create secp256k1.PrivateKey" +
-			"from chainec.PrivateKey")
+	switch pk := privKey.(type) {
+	case *secp256k1.PrivateKey:
+		return secp256k1.SignCompact(secp256k1.S256(), pk, hash, true)
+	case bs.PrivateKey:
+		blissSig, err := bs.Bliss.Sign(pk, hash)
+		if err != nil {
+			return nil, err
+		}
+		pubKey := pk.PublicKey().Serialize()
+		sig = make([]byte, 0, 1+len(pubKey)+len(blissSig.Serialize()))
+		sig = append(sig, bs.BSTypeBliss)
+		sig = append(sig, pubKey...)
+		return append(sig, blissSig.Serialize()...), nil
+	default:
+		return nil, fmt.Errorf("Unable to sign messages with private "+
+			"keys of type %T", privKey)
 	}
-	return secp256k1.SignCompact(secp256k1.S256(), pkCast, messageHash, true)
 }
 
 // VerifyMessage verifies that sig is a valid signature of msg and was created
-// using the secp256k1 private key for addr.
+// using the secp256k1 or bliss private key for addr.
 func VerifyMessage(msg string, addr hcutil.Address, sig []byte) (bool, error) {
+	if len(sig) != 0 && sig[0] == bs.BSTypeBliss {
+		return verifyBlissMessage(msg, addr, sig)
+	}
+
 	// Validate the signature - this just shows that it was valid for any pubkey
 	// at all. Whether the pubkey matches is checked below.
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, "Hc Signed Message:\n")
-	wire.WriteVarString(&buf, 0, msg)
-	expectedMessageHash := chainhash.HashB(buf.Bytes())
+	expectedMessageHash := messageHash(msg)
 	pk, wasCompressed, err := chainec.Secp256k1.RecoverCompact(sig,
 		expectedMessageHash)
 	if err != nil {
@@ -2074,6 +2522,36 @@ func VerifyMessage(msg string, addr hcutil.Address, sig []byte) (bool, error) {
 	return recoveredAddr.EncodeAddress() == addr.EncodeAddress(), nil
 }
 
+// verifyBlissMessage verifies that sig is a valid signature of msg in the
+// extended format for bliss signatures and was created using the bliss private
+// key for addr.
+func verifyBlissMessage(msg string, addr hcutil.Address, sig []byte) (bool, error) {
+	if len(sig) <= 1+bs.BlissPubKeyLen {
+		return false, errors.New("bliss message signature is too short")
+	}
+	serializedPK := sig[1 : 1+bs.BlissPubKeyLen]
+
+	// The included public key must be the public key of the address before
+	// the signature is checked.
+	pk, err := bs.Bliss.ParsePubKey(serializedPK)
+	if err != nil {
+		return false, err
+	}
+	pkAddr, err := hcutil.NewAddressBlissPubKey(serializedPK, addr.Net())
+	if err != nil {
+		return false, err
+	}
+	if pkAddr.EncodeAddress() != addr.EncodeAddress() {
+		return false, nil
+	}
+
+	blissSig, err := bs.Bliss.ParseSignature(sig[1+bs.BlissPubKeyLen:])
+	if err != nil {
+		return false, err
+	}
+	return bs.Bliss.Verify(pk, messageHash(msg), blissSig), nil
+}
+
 // existsAddressOnChain checks the chain on daemon to see if the given address
 // has been used before on the main chain.
 func (w *Wallet) existsAddressOnChain(address hcutil.Address) (bool, error) {
@@ -2137,26 +2615,44 @@ func (w *Wallet) AddressInfo(a hcutil.Address) (udb.ManagedAddress, error) {
 
 // AccountNumber returns the account number for an account name.
 func (w *Wallet) AccountNumber(accountName string) (uint32, error) {
-	var account uint32
+	account, ok := w.accountNames.number(accountName)
+	if ok {
+		return account, nil
+	}
+
+	gen := w.accountNames.gen()
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
 		var err error
 		account, err = w.Manager.LookupAccount(addrmgrNs, accountName)
 		return err
 	})
-	return account, err
+	if err != nil {
+		return 0, err
+	}
+	w.accountNames.add(gen, account, accountName)
+	return account, nil
 }
 
 // AccountName returns the name of an account.
 func (w *Wallet) AccountName(accountNumber uint32) (string, error) {
-	var accountName string
+	accountName, ok := w.accountNames.name(accountNumber)
+	if ok {
+		return accountName, nil
+	}
+
+	gen := w.accountNames.gen()
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
 		var err error
 		accountName, err = w.Manager.AccountName(addrmgrNs, accountNumber)
 		return err
 	})
-	return accountName, err
+	if err != nil {
+		return "", err
+	}
+	w.accountNames.add(gen, accountNumber, accountName)
+	return accountName, nil
 }
 
 // AccountProperties returns the properties of an account, including address
@@ -2185,6 +2681,7 @@ func (w *Wallet) RenameAccount(account uint32, newName string) error {
 		props, err = w.Manager.AccountProperties(addrmgrNs, account)
 		return err
 	})
+	w.accountNames.invalidate()
 	if err == nil {
 		w.NtfnServer.notifyAccountProperties(props)
 	}
@@ -2202,7 +2699,6 @@ func (w *Wallet) NextAccount(name string, actype uint8) (uint32, error) {
 	var account uint32
 	var props *udb.AccountProperties
 	var xpub, xpriv *hdkeychain.ExtendedKey
-	var extKey, intKey *hdkeychain.ExtendedKey
 	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
 		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
 
@@ -2267,11 +2763,29 @@ func (w *Wallet) NextAccount(name string, actype uint8) (uint32, error) {
 	if err != nil {
 		return 0, err
 	}
+	w.accountNames.invalidate()
+
+	err = w.watchNewAccount(account, props, xpub, xpriv)
+	if err != nil {
+		return 0, err
+	}
+	return account, nil
+}
+
+// watchNewAccount adds the address buffers of a newly created or imported
+// account, loads the transaction filter with its addresses through the gap
+// limit, and notifies clients of the account.  Bliss accounts derive their
+// branch keys from the account extended private key xpriv, while other
+// accounts use the account extended public key xpub.
+func (w *Wallet) watchNewAccount(account uint32, props *udb.AccountProperties,
+	xpub, xpriv *hdkeychain.ExtendedKey) error {
 
+	var extKey, intKey *hdkeychain.ExtendedKey
+	var err error
 	if props.AccountType == udb.AcctypeEc {
 		extKey, intKey, err = deriveBranches(xpub)
 		if err != nil {
-			return 0, err
+			return err
 		}
 	} else if props.AccountType == udb.AcctypeBliss {
 		intKeypriv, err := xpriv.Child(udb.InternalBranch)
@@ -2282,7 +2796,7 @@ func (w *Wallet) NextAccount(name string, actype uint8) (uint32, error) {
 		extKeypriv.Zero()
 		xpriv.Zero()
 		if err != nil {
-			return 0, err
+			return err
 		}
 	}
 	w.addressBuffersMu.Lock()
@@ -2333,46 +2847,148 @@ func (w *Wallet) NextAccount(name string, actype uint8) (uint32, error) {
 		for i := 0; i < cap(errs); i++ {
 			err := <-errs
 			if err != nil {
-				return 0, err
+				return err
 			}
 		}
 	}
 
 	w.NtfnServer.notifyAccountProperties(props)
 
-	return account, nil
+	return nil
 }
 
-// MasterPubKey returns the BIP0044 master public key for the passed account.
-//
-// TODO: This should not be returning the key as a string.
-func (w *Wallet) MasterPubKey(account uint32) (string, error) {
-	var masterPubKey string
-	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
-		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+// ImportAccount recreates an account exported from another wallet by its
+// serialized BIP0044 account extended key and returns the new account number.
+// The key's account index must be the next account number of this wallet.
+// Extended private keys, including those of bliss accounts, are imported by
+// unlocked wallets, while watching-only wallets import extended public keys.
+func (w *Wallet) ImportAccount(name, acctKey string) (uint32, error) {
+	key, err := hdkeychain.NewKeyFromString(acctKey)
+	if err != nil {
+		const str = "invalid extended key"
+		return 0, apperrors.E{ErrorCode: apperrors.ErrInput, Description: str, Err: err}
+	}
+	defer key.Zero()
+
+	var account uint32
+	var props *udb.AccountProperties
+	var xpub, xpriv *hdkeychain.ExtendedKey
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
 		var err error
-		masterPubKey, err = w.Manager.GetMasterPubkey(addrmgrNs, account)
-		return err
-	})
-	return masterPubKey, err
-}
+		account, err = w.Manager.ImportAccount(addrmgrNs, name, key)
+		if err != nil {
+			return err
+		}
 
-// CreditCategory describes the type of wallet transaction output.  The category
-// of "sent transactions" (debits) is always "send", and is not expressed by
-// this type.
-//
-// TODO: This is a requirement of the RPC server and should be moved.
-type CreditCategory byte
+		props, err = w.Manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
 
-// These constants define the possible credit categories.
-const (
-	CreditReceive CreditCategory = iota
-	CreditGenerate
-	CreditImmature
-)
+		if props.AccountType == udb.AcctypeEc {
+			xpub, err = w.Manager.AccountExtendedPubKey(tx, account)
+		} else {
+			xpriv, err = w.Manager.AccountExtendedPrivKey(tx, account)
+		}
+		if err != nil {
+			return err
+		}
 
-// String returns the category as a string.  This string may be used as the
-// JSON string for categories as part of listtransactions and gettransaction
+		gapLimit := uint32(w.gapLimit)
+		err = w.Manager.SyncAccountToAddrIndex(addrmgrNs, account,
+			gapLimit, udb.ExternalBranch)
+		if err != nil {
+			return err
+		}
+		return w.Manager.SyncAccountToAddrIndex(addrmgrNs, account,
+			gapLimit, udb.InternalBranch)
+	})
+	if err != nil {
+		return 0, err
+	}
+	w.accountNames.invalidate()
+
+	err = w.watchNewAccount(account, props, xpub, xpriv)
+	if err != nil {
+		return 0, err
+	}
+	return account, nil
+}
+
+// ExportAccount returns the serialized BIP0044 extended key of an account so
+// that it can be recreated by ImportAccount in another wallet.  The extended
+// private key is returned when private is true, which requires the wallet to
+// be unlocked, and the extended public key otherwise.  Bliss accounts may only
+// be exported with their private key.
+func (w *Wallet) ExportAccount(account uint32, private bool) (string, error) {
+	if private && w.Manager.IsLocked() {
+		const str = "wallet must be unlocked to export private keys"
+		return "", apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+	}
+
+	var key string
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		props, err := w.Manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+
+		var xkey *hdkeychain.ExtendedKey
+		switch {
+		case private:
+			xkey, err = w.Manager.AccountExtendedPrivKey(tx, account)
+			if err != nil {
+				return err
+			}
+		case props.AccountType == udb.AcctypeBliss:
+			const str = "bliss accounts may only be exported with their " +
+				"extended private key"
+			return apperrors.E{ErrorCode: apperrors.ErrInvalidAccount, Description: str, Err: nil}
+		default:
+			xkey, err = w.Manager.AccountExtendedPubKey(tx, account)
+			if err != nil {
+				return err
+			}
+		}
+		key, err = xkey.String()
+		return err
+	})
+	return key, err
+}
+
+// MasterPubKey returns the BIP0044 master public key for the passed account.
+//
+// TODO: This should not be returning the key as a string.
+func (w *Wallet) MasterPubKey(account uint32) (string, error) {
+	var masterPubKey string
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		masterPubKey, err = w.Manager.GetMasterPubkey(addrmgrNs, account)
+		return err
+	})
+	return masterPubKey, err
+}
+
+// CreditCategory describes the type of wallet transaction output.  The category
+// of "sent transactions" (debits) is always "send", and is not expressed by
+// this type.
+//
+// TODO: This is a requirement of the RPC server and should be moved.
+type CreditCategory byte
+
+// These constants define the possible credit categories.
+const (
+	CreditReceive CreditCategory = iota
+	CreditGenerate
+	CreditImmature
+)
+
+// String returns the category as a string.  This string may be used as the
+// JSON string for categories as part of listtransactions and gettransaction
 // RPC responses.
 func (c CreditCategory) String() string {
 	switch c {
@@ -2547,17 +3163,46 @@ outputs:
 
 // ListSinceBlock returns a slice of objects with details about transactions
 // since the given block. If the block is -1 then all transactions are included.
+// When account is non-nil, only the results which credit the account, and the
+// send results of transactions debiting the account, are included.
 // This is intended to be used for listsinceblock RPC replies.
-func (w *Wallet) ListSinceBlock(start, end, syncHeight int32) ([]hcjson.ListTransactionsResult, error) {
+func (w *Wallet) ListSinceBlock(start, end, syncHeight int32, account *uint32) ([]hcjson.ListTransactionsResult, error) {
 	txList := []hcjson.ListTransactionsResult{}
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
 
+		var accountName string
+		if account != nil {
+			var err error
+			accountName, err = w.Manager.AccountName(
+				tx.ReadBucket(waddrmgrNamespaceKey), *account)
+			if err != nil {
+				return err
+			}
+		}
+
 		rangeFn := func(details []udb.TxDetails) (bool, error) {
 			for _, detail := range details {
 				jsonResults := listTransactions(tx, &detail,
 					w.Manager, syncHeight, w.chainParams)
-				txList = append(txList, jsonResults...)
+				if account == nil {
+					txList = append(txList, jsonResults...)
+					continue
+				}
+
+				debitsAccount := false
+				for _, deb := range detail.Debits {
+					if lookupInputAccount(tx, w, &detail, deb) == *account {
+						debitsAccount = true
+						break
+					}
+				}
+				for _, r := range jsonResults {
+					if r.Category == "send" && debitsAccount ||
+						r.Category != "send" && r.Account == accountName {
+						txList = append(txList, r)
+					}
+				}
 			}
 			return false, nil
 		}
@@ -2620,8 +3265,10 @@ func (w *Wallet) ListTransactions(from, count int) ([]hcjson.ListTransactionsRes
 
 // ListAddressTransactions returns a slice of objects with details about
 // recorded transactions to or from any address belonging to a set.  This is
-// intended to be used for listaddresstransactions RPC replies.
-func (w *Wallet) ListAddressTransactions(pkHashes map[string]struct{}) ([]hcjson.ListTransactionsResult, error) {
+// intended to be used for listaddresstransactions RPC replies.  Transactions
+// are looked up with the address credits index rather than by iterating over
+// every recorded transaction.
+func (w *Wallet) ListAddressTransactions(addrs []hcutil.Address) ([]hcjson.ListTransactionsResult, error) {
 	txList := []hcjson.ListTransactionsResult{}
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
@@ -2629,42 +3276,127 @@ func (w *Wallet) ListAddressTransactions(pkHashes map[string]struct{}) ([]hcjson
 		// Get current block.  The block height used for calculating
 		// the number of tx confirmations.
 		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
-		rangeFn := func(details []udb.TxDetails) (bool, error) {
-		loopDetails:
-			for i := range details {
-				detail := &details[i]
 
-				for _, cred := range detail.Credits {
-					pkScript := detail.MsgTx.TxOut[cred.Index].PkScript
-					_, addrs, _, err := txscript.ExtractPkScriptAddrs(
-						txscript.DefaultScriptVersion, pkScript, w.chainParams)
-					if err != nil || len(addrs) != 1 {
-						continue
-					}
-					apkh, ok := addrs[0].(*hcutil.AddressPubKeyHash)
-					if !ok {
-						continue
-					}
-					_, ok = pkHashes[string(apkh.ScriptAddress())]
-					if !ok {
-						continue
-					}
+		details, err := w.addressTxDetails(txmgrNs, addrs)
+		if err != nil {
+			return err
+		}
+		for _, detail := range details {
+			jsonResults := listTransactions(tx, detail,
+				w.Manager, tipHeight, w.chainParams)
+			txList = append(txList, jsonResults...)
+		}
+		return nil
+	})
+	return txList, err
+}
 
-					jsonResults := listTransactions(tx, detail,
-						w.Manager, tipHeight, w.chainParams)
-					if err != nil {
-						return false, err
+// addressTxDetails looks up the details of every transaction with a credit
+// paying to any of the addresses using the address credits index.  Each
+// transaction is returned once, ordered by block height with unmined
+// transactions last, matching the order of RangeTransactions.
+func (w *Wallet) addressTxDetails(txmgrNs walletdb.ReadBucket, addrs []hcutil.Address) ([]*udb.TxDetails, error) {
+	seen := make(map[chainhash.Hash]struct{})
+	var details []*udb.TxDetails
+	for _, addr := range addrs {
+		for _, txHash := range w.TxStore.AddressCreditTxHashes(txmgrNs, addr) {
+			if _, ok := seen[txHash]; ok {
+				continue
+			}
+			seen[txHash] = struct{}{}
+			txHash := txHash
+			detail, err := w.TxStore.TxDetails(txmgrNs, &txHash)
+			if err != nil {
+				return nil, err
+			}
+			if detail == nil {
+				continue
+			}
+			details = append(details, detail)
+		}
+	}
+	sort.SliceStable(details, func(i, j int) bool {
+		hi, hj := details[i].Block.Height, details[j].Block.Height
+		if hi == -1 || hj == -1 {
+			return hj == -1 && hi != -1
+		}
+		return hi < hj
+	})
+	return details, nil
+}
+
+// AddressReceived describes the credits paying to an address.
+type AddressReceived struct {
+	// Amount is the total amount received by the address.
+	Amount hcutil.Amount
+	// Confirmations is the number of confirmations of the most recent
+	// transaction paying to the address.
+	Confirmations int32
+	// TxHashes are the hashes of the transactions paying to the address.
+	TxHashes []chainhash.Hash
+}
+
+// ReceivedByAddresses returns the credits received by every active wallet
+// address, and by any other address paid by the same credit outputs, with at
+// least minConf confirmations.  Active addresses without any credits are
+// included with a zero amount.  This is intended to be used for
+// listreceivedbyaddress RPC replies, and uses the address credits index
+// rather than iterating over every recorded transaction.
+func (w *Wallet) ReceivedByAddresses(minConf int32) (map[string]*AddressReceived, error) {
+	received := make(map[string]*AddressReceived)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+
+		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+
+		var addrs []hcutil.Address
+		err := w.Manager.ForEachActiveAddress(addrmgrNs, func(addr hcutil.Address) error {
+			addrs = append(addrs, addr)
+			received[addr.EncodeAddress()] = &AddressReceived{}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		details, err := w.addressTxDetails(txmgrNs, addrs)
+		if err != nil {
+			return err
+		}
+		for _, detail := range details {
+			if minConf > 0 && !confirmed(minConf, detail.Block.Height, tipHeight) {
+				continue
+			}
+			confirmations := confirms(detail.Block.Height, tipHeight)
+			for _, cred := range detail.Credits {
+				pkVersion := detail.MsgTx.TxOut[cred.Index].Version
+				pkScript := detail.MsgTx.TxOut[cred.Index].PkScript
+				_, creditAddrs, _, err := txscript.ExtractPkScriptAddrs(pkVersion,
+					pkScript, w.chainParams)
+				// An error creating addresses from the output script only
+				// indicates a non-standard script, so ignore this credit.
+				if err != nil {
+					continue
+				}
+				for _, a := range creditAddrs {
+					addrStr := a.EncodeAddress()
+					r, ok := received[addrStr]
+					if !ok {
+						r = &AddressReceived{}
+						received[addrStr] = r
 					}
-					txList = append(txList, jsonResults...)
-					continue loopDetails
+					r.Amount += cred.Amount
+					// Details are ordered oldest first, so this leaves
+					// the confirmations of the most recent transaction.
+					r.Confirmations = confirmations
+					r.TxHashes = append(r.TxHashes, detail.Hash)
 				}
 			}
-			return false, nil
 		}
-
-		return w.TxStore.RangeTransactions(txmgrNs, 0, -1, rangeFn)
+		return nil
 	})
-	return txList, err
+	return received, err
 }
 
 // ListAllTransactions returns a slice of objects with details about a recorded
@@ -2816,7 +3548,7 @@ type GetTicketsResult struct {
 // given startBlock and endBlock.  TicketSummary includes TransactionSummmary
 // for the ticket and the spender (if already spent) and the ticket's current
 // status.
-func (w *Wallet) GetTickets(ctx context.Context, chainClient *hcrpcclient.Client, startBlock, endBlock *BlockIdentifier) (*GetTicketsResult, error) {
+func (w *Wallet) GetTickets(ctx context.Context, chainClient ChainClient, startBlock, endBlock *BlockIdentifier) (*GetTicketsResult, error) {
 	var start, end int32 = 0, -1
 
 	if startBlock != nil {
@@ -3032,6 +3764,27 @@ type AccountsResult struct {
 	CurrentBlockHeight int32
 }
 
+// AddressCounts returns the number of accounts in the wallet, including the
+// imported account, and the number of active addresses watched for relevant
+// transactions.
+func (w *Wallet) AddressCounts() (accounts, addresses int, err error) {
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		err := w.Manager.ForEachAccount(addrmgrNs, func(uint32) error {
+			accounts++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return w.Manager.ForEachActiveAddress(addrmgrNs, func(hcutil.Address) error {
+			addresses++
+			return nil
+		})
+	})
+	return accounts, addresses, err
+}
+
 // Accounts returns the current names, numbers, and total balances of all
 // accounts in the wallet.  The current chain tip is included in the result for
 // atomicity reasons.
@@ -3137,8 +3890,12 @@ func (s creditSlice) Swap(i, j int) {
 // transactions fitting the given criteria. The confirmations will be more than
 // minconf, less than maxconf and if addresses is populated only the addresses
 // contained within it will be considered.  If we know nothing about a
-// transaction an empty array will be returned.
-func (w *Wallet) ListUnspent(minconf, maxconf int32, addresses map[string]struct{}) ([]*hcjson.ListUnspentResult, error) {
+// transaction an empty array will be returned.  Outputs locked by lockunspent
+// or transaction drafts and immature outputs are only included when
+// includeLocked is true.
+func (w *Wallet) ListUnspent(minconf, maxconf int32, addresses map[string]struct{},
+	includeLocked bool) ([]*hcjson.ListUnspentResult, error) {
+
 	var results []*hcjson.ListUnspentResult
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
@@ -3146,7 +3903,6 @@ func (w *Wallet) ListUnspent(minconf, maxconf int32, addresses map[string]struct
 
 		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
 
-		filter := len(addresses) != 0
 		unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
 		if err != nil {
 			return err
@@ -3159,168 +3915,210 @@ func (w *Wallet) ListUnspent(minconf, maxconf int32, addresses map[string]struct
 			return err
 		}
 
-		for i := range unspent {
-			output := unspent[i]
-
-			details, err := w.TxStore.TxDetails(txmgrNs, &output.Hash)
+		for _, output := range unspent {
+			result, err := w.unspentResult(addrmgrNs, txmgrNs, output,
+				tipHeight, defaultAccountName, minconf, maxconf,
+				addresses, includeLocked)
 			if err != nil {
-				return fmt.Errorf("Couldn't get credit details")
+				return err
 			}
-
-			// Outputs with fewer confirmations than the minimum or more
-			// confs than the maximum are excluded.
-			confs := confirms(output.Height, tipHeight)
-			if confs < minconf || confs > maxconf {
-				continue
+			if result != nil {
+				results = append(results, result)
 			}
+		}
+		return nil
+	})
+	return results, err
+}
 
-			// Only mature coinbase outputs are included.
-			if output.FromCoinBase {
-				target := int32(w.ChainParams().CoinbaseMaturity)
-				if !confirmed(target, output.Height, tipHeight) {
-					continue
-				}
-			}
+// unspentResult returns the listunspent result of an unspent output, or nil
+// when the output does not fit the criteria described by ListUnspent.
+func (w *Wallet) unspentResult(addrmgrNs, txmgrNs walletdb.ReadBucket,
+	output *udb.Credit, tipHeight int32, defaultAccountName string,
+	minconf, maxconf int32, addresses map[string]struct{},
+	includeLocked bool) (*hcjson.ListUnspentResult, error) {
 
-			switch details.TxRecord.TxType {
-			case stake.TxTypeSStx:
-				// Ticket commitment, only spendable after ticket maturity.
-				// You can only spent it after TM many blocks has gone past, so
-				// ticket maturity + 1??? Check this HC TODO
-				if output.Index == 0 {
-					if !confirmed(int32(w.chainParams.TicketMaturity+1),
-						details.Height(), tipHeight) {
-						continue
-					}
-				}
-				// Change outputs.
-				if (output.Index > 0) && (output.Index%2 == 0) {
-					if !confirmed(int32(w.chainParams.SStxChangeMaturity),
-						details.Height(), tipHeight) {
-						continue
-					}
-				}
-			case stake.TxTypeSSGen:
-				// All non-OP_RETURN outputs for SSGen tx are only spendable
-				// after coinbase maturity many blocks.
-				if !confirmed(int32(w.chainParams.CoinbaseMaturity),
-					details.Height(), tipHeight) {
-					continue
-				}
-			case stake.TxTypeSSRtx:
-				// All outputs for SSRtx tx are only spendable
-				// after coinbase maturity many blocks.
-				if !confirmed(int32(w.chainParams.CoinbaseMaturity),
-					details.Height(), tipHeight) {
-					continue
-				}
+	filter := len(addresses) != 0
 
-			}
+	details, err := w.TxStore.TxDetails(txmgrNs, &output.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't get credit details")
+	}
 
-			// Exclude locked outputs from the result set.
-			if w.LockedOutpoint(output.OutPoint) {
-				continue
-			}
+	// Outputs with fewer confirmations than the minimum or more
+	// confs than the maximum are excluded.
+	confs := confirms(output.Height, tipHeight)
+	if confs < minconf || confs > maxconf {
+		return nil, nil
+	}
 
-			// Lookup the associated account for the output.  Use the
-			// default account name in case there is no associated account
-			// for some reason, although this should never happen.
-			//
-			// This will be unnecessary once transactions and outputs are
-			// grouped under the associated account in the db.
-			acctName := defaultAccountName
-			sc, addrs, _, err := txscript.ExtractPkScriptAddrs(
-				txscript.DefaultScriptVersion, output.PkScript, w.chainParams)
-			if err != nil {
-				continue
-			}
-			if len(addrs) > 0 {
-				acct, err := w.Manager.AddrAccount(
-					addrmgrNs, addrs[0])
-				if err == nil {
-					s, err := w.Manager.AccountName(
-						addrmgrNs, acct)
-					if err == nil {
-						acctName = s
-					}
-				}
-			}
+	// toMaturity records the number of blocks until an
+	// immature output may be spent.
+	var toMaturity int32
+	mature := func(target, height int32) {
+		if !confirmed(target, height, tipHeight) {
+			toMaturity = target - confirms(height, tipHeight)
+		}
+	}
 
-			if filter {
-				for _, addr := range addrs {
-					_, ok := addresses[addr.EncodeAddress()]
-					if ok {
-						goto include
-					}
-				}
-				continue
-			}
+	// Only mature coinbase outputs are included.
+	if output.FromCoinBase {
+		mature(int32(w.ChainParams().CoinbaseMaturity), output.Height)
+	}
 
-		include:
-			// At the moment watch-only addresses are not supported, so all
-			// recorded outputs that are not multisig are "spendable".
-			// Multisig outputs are only "spendable" if all keys are
-			// controlled by this wallet.
-			//
-			// TODO: Each case will need updates when watch-only addrs
-			// is added.  For P2PK, P2PKH, and P2SH, the address must be
-			// looked up and not be watching-only.  For multisig, all
-			// pubkeys must belong to the manager with the associated
-			// private key (currently it only checks whether the pubkey
-			// exists, since the private key is required at the moment).
-			var spendable bool
-		scSwitch:
-			switch sc {
-			case txscript.PubKeyHashTy:
-				spendable = true
-			case txscript.PubKeyTy:
-				spendable = true
-			case txscript.ScriptHashTy:
-				spendable = true
-			case txscript.StakeGenTy:
-				spendable = true
-			case txscript.StakeRevocationTy:
-				spendable = true
-			case txscript.StakeSubChangeTy:
-				spendable = true
-			case txscript.MultiSigTy:
-				for _, a := range addrs {
-					_, err := w.Manager.Address(addrmgrNs, a)
-					if err == nil {
-						continue
-					}
-					if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
-						break scSwitch
-					}
-					return err
-				}
-				spendable = true
-			}
+	var ticketLocked bool
+	switch details.TxRecord.TxType {
+	case stake.TxTypeSStx:
+		// Ticket commitment, only spendable after ticket maturity.
+		// You can only spent it after TM many blocks has gone past, so
+		// ticket maturity + 1??? Check this HC TODO
+		if output.Index == 0 {
+			ticketLocked = true
+			mature(int32(w.chainParams.TicketMaturity+1),
+				details.Height())
+		}
+		// Change outputs.
+		if (output.Index > 0) && (output.Index%2 == 0) {
+			mature(int32(w.chainParams.SStxChangeMaturity),
+				details.Height())
+		}
+	case stake.TxTypeSSGen:
+		// All non-OP_RETURN outputs for SSGen tx are only spendable
+		// after coinbase maturity many blocks.
+		mature(int32(w.chainParams.CoinbaseMaturity),
+			details.Height())
+	case stake.TxTypeSSRtx:
+		// All outputs for SSRtx tx are only spendable
+		// after coinbase maturity many blocks.
+		mature(int32(w.chainParams.CoinbaseMaturity),
+			details.Height())
+
+	}
+	if toMaturity > 0 && !includeLocked {
+		return nil, nil
+	}
 
-			result := &hcjson.ListUnspentResult{
-				TxID:          output.OutPoint.Hash.String(),
-				Vout:          output.OutPoint.Index,
-				Tree:          output.OutPoint.Tree,
-				Account:       acctName,
-				ScriptPubKey:  hex.EncodeToString(output.PkScript),
-				TxType:        int(details.TxType),
-				Amount:        output.Amount.ToCoin(),
-				Confirmations: int64(confs),
-				Spendable:     spendable,
+	// Exclude locked outputs, and outputs reserved by
+	// transaction drafts, from the result set.
+	locked := w.LockedOutpoint(output.OutPoint) ||
+		w.TxStore.TxDraftReserving(txmgrNs, &output.OutPoint) != nil
+	if locked && !includeLocked {
+		return nil, nil
+	}
+
+	// Lookup the associated account for the output.  Use the
+	// default account name in case there is no associated account
+	// for some reason, although this should never happen.
+	//
+	// This will be unnecessary once transactions and outputs are
+	// grouped under the associated account in the db.
+	acctName := defaultAccountName
+	acctNum := uint32(udb.DefaultAccountNum)
+	sc, addrs, _, err := txscript.ExtractPkScriptAddrs(
+		txscript.DefaultScriptVersion, output.PkScript, w.chainParams)
+	if err != nil {
+		return nil, nil
+	}
+	if len(addrs) > 0 {
+		acct, err := w.Manager.AddrAccount(
+			addrmgrNs, addrs[0])
+		if err == nil {
+			s, err := w.Manager.AccountName(
+				addrmgrNs, acct)
+			if err == nil {
+				acctName = s
+				acctNum = acct
 			}
+		}
+	}
 
-			// BUG: this should be a JSON array so that all
-			// addresses can be included, or removed (and the
-			// caller extracts addresses from the pkScript).
-			if len(addrs) > 0 {
-				result.Address = addrs[0].EncodeAddress()
+	if filter {
+		for _, addr := range addrs {
+			_, ok := addresses[addr.EncodeAddress()]
+			if ok {
+				goto include
 			}
+		}
+		return nil, nil
+	}
 
-			results = append(results, result)
+include:
+	// At the moment watch-only addresses are not supported, so all
+	// recorded outputs that are not multisig are "spendable".
+	// Multisig outputs are only "spendable" if all keys are
+	// controlled by this wallet.
+	//
+	// TODO: Each case will need updates when watch-only addrs
+	// is added.  For P2PK, P2PKH, and P2SH, the address must be
+	// looked up and not be watching-only.  For multisig, all
+	// pubkeys must belong to the manager with the associated
+	// private key (currently it only checks whether the pubkey
+	// exists, since the private key is required at the moment).
+	var spendable bool
+scSwitch:
+	switch sc {
+	case txscript.PubKeyHashTy:
+		spendable = true
+	case txscript.PubKeyTy:
+		spendable = true
+	case txscript.ScriptHashTy:
+		spendable = true
+	case txscript.StakeGenTy:
+		spendable = true
+	case txscript.StakeRevocationTy:
+		spendable = true
+	case txscript.StakeSubChangeTy:
+		spendable = true
+	case txscript.MultiSigTy:
+		for _, a := range addrs {
+			_, err := w.Manager.Address(addrmgrNs, a)
+			if err == nil {
+				continue
+			}
+			if apperrors.IsError(err, apperrors.ErrAddressNotFound) {
+				break scSwitch
+			}
+			return nil, err
 		}
-		return nil
-	})
-	return results, err
+		spendable = true
+	}
+
+	result := &hcjson.ListUnspentResult{
+		TxID:          output.OutPoint.Hash.String(),
+		Vout:          output.OutPoint.Index,
+		Tree:          output.OutPoint.Tree,
+		Account:       acctName,
+		AccountNumber: acctNum,
+		ScriptPubKey:  hex.EncodeToString(output.PkScript),
+		ScriptClass:   sc.String(),
+		TxType:        int(details.TxType),
+		Amount:        output.Amount.ToCoin(),
+		Confirmations: int64(confs),
+		Spendable:     spendable,
+		Locked:        locked,
+		TicketLocked:  ticketLocked,
+		ToMaturity:    int64(toMaturity),
+	}
+
+	// The redeem script of a P2SH output is only known when
+	// the script was imported, and can only be read while the
+	// wallet is unlocked.
+	if sc == txscript.ScriptHashTy && len(addrs) > 0 {
+		script, done, err := w.Manager.RedeemScript(addrmgrNs, addrs[0])
+		if err == nil {
+			result.RedeemScript = hex.EncodeToString(script)
+			done()
+		}
+	}
+
+	// BUG: this should be a JSON array so that all
+	// addresses can be included, or removed (and the
+	// caller extracts addresses from the pkScript).
+	if len(addrs) > 0 {
+		result.Address = addrs[0].EncodeAddress()
+	}
+
+	return result, nil
 }
 
 // DumpWIFPrivateKey returns the WIF encoded private key for a
@@ -3402,7 +4200,7 @@ func (w *Wallet) ImportScript(rs []byte) error {
 		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
 		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
 
-		err := w.TxStore.InsertTxScript(txmgrNs, rs)
+		err := w.insertTxScript(txmgrNs, rs)
 		if err != nil {
 			return err
 		}
@@ -3543,10 +4341,18 @@ func (w *Wallet) hasVotingAuthority(addrmgrNs walletdb.ReadBucket, ticketPurchas
 //
 // Getting this information is extremely costly as in involves a massive
 // number of chain server calls.
-func (w *Wallet) StakeInfo(chainClient *hcrpcclient.Client) (*StakeInfoData, error) {
-	// This is only needed for the total count and can be optimized.
-	mempoolTicketsFuture := chainClient.GetRawMempoolAsync(hcjson.GRMTickets)
+func (w *Wallet) StakeInfo(chainClient ChainClient) (*StakeInfoData, error) {
+	return w.stakeInfo(chainClient, nil)
+}
+
+// AccountStakeInfo returns the staking statistics of StakeInfo for the tickets
+// funded by an account.  The ticket pool and mempool totals are not limited to
+// the account.  Tickets with no recorded funding account are excluded.
+func (w *Wallet) AccountStakeInfo(chainClient ChainClient, account uint32) (*StakeInfoData, error) {
+	return w.stakeInfo(chainClient, &account)
+}
 
+func (w *Wallet) stakeInfo(chainClient ChainClient, account *uint32) (*StakeInfoData, error) {
 	res := &StakeInfoData{}
 
 	// Wallet does not yet know if/when a ticket was selected.  Keep track of
@@ -3556,6 +4362,7 @@ func (w *Wallet) StakeInfo(chainClient *hcrpcclient.Client) (*StakeInfoData, err
 
 	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
 		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		stakemgrNs := dbtx.ReadBucket(wstakemgrNamespaceKey)
 		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
 		tipHash, tipHeight := w.TxStore.MainChainTip(txmgrNs)
 		res.BlockHeight = int64(tipHeight)
@@ -3570,6 +4377,17 @@ func (w *Wallet) StakeInfo(chainClient *hcrpcclient.Client) (*StakeInfoData, err
 				continue
 			}
 
+			// Skip tickets funded by other accounts.
+			if account != nil {
+				funding, ok, err := w.StakeMgr.TicketAccount(stakemgrNs, &it.Hash)
+				if err != nil {
+					return err
+				}
+				if !ok || funding != *account {
+					continue
+				}
+			}
+
 			// Check for tickets in mempool
 			if it.Block.Height == -1 {
 				res.OwnMempoolTix++
@@ -3663,13 +4481,11 @@ func (w *Wallet) StakeInfo(chainClient *hcrpcclient.Client) (*StakeInfoData, err
 	// As the wallet is unaware of when a ticket was selected or missed, this
 	// info must be queried from the consensus server.  If the ticket is neither
 	// live nor expired, it is assumed missed.
-	expiredFuture := chainClient.ExistsExpiredTicketsAsync(liveOrExpiredOrMissed)
-	liveFuture := chainClient.ExistsLiveTicketsAsync(liveOrExpiredOrMissed)
-	expiredBitsetHex, err := expiredFuture.Receive()
+	expiredBitsetHex, err := chainClient.ExistsExpiredTickets(liveOrExpiredOrMissed)
 	if err != nil {
 		return nil, err
 	}
-	liveBitsetHex, err := liveFuture.Receive()
+	liveBitsetHex, err := chainClient.ExistsLiveTickets(liveOrExpiredOrMissed)
 	if err != nil {
 		return nil, err
 	}
@@ -3692,9 +4508,9 @@ func (w *Wallet) StakeInfo(chainClient *hcrpcclient.Client) (*StakeInfoData, err
 		}
 	}
 
-	// Receive the mempool tickets future called at the beginning of the
-	// function and determine the total count of tickets in the mempool.
-	mempoolTickets, err := mempoolTicketsFuture.Receive()
+	// Determine the total count of tickets in the mempool.  This is only
+	// needed for the total count and can be optimized.
+	mempoolTickets, err := chainClient.GetRawMempool(hcjson.GRMTickets)
 	if err != nil {
 		return nil, err
 	}
@@ -3703,43 +4519,153 @@ func (w *Wallet) StakeInfo(chainClient *hcrpcclient.Client) (*StakeInfoData, err
 	return res, nil
 }
 
+// StakeRewardPeriod describes the length of the periods stake rewards are
+// grouped into.
+type StakeRewardPeriod int
+
+// These constants define the supported stake reward periods.  All periods
+// begin at midnight UTC, and weeks begin on Monday.
+const (
+	StakeRewardsDaily StakeRewardPeriod = iota
+	StakeRewardsWeekly
+	StakeRewardsMonthly
+)
+
+// start returns the beginning of the period containing t.
+func (p StakeRewardPeriod) start(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	switch p {
+	case StakeRewardsWeekly:
+		offset := (int(t.UTC().Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, time.UTC)
+	case StakeRewardsMonthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// StakeRewardsData summarizes the votes and revocations of wallet tickets
+// mined during a single period.
+type StakeRewardsData struct {
+	Start        time.Time
+	Voted        uint32
+	Missed       uint32
+	Revoked      uint32
+	TotalSubsidy hcutil.Amount
+}
+
+// StakeRewards returns the number of votes, missed tickets and revocations,
+// and the total subsidy earned by wallet tickets, grouped by period in
+// increasing order.  Only rewards mined at or after since and before until are
+// included, and a zero time leaves that end of the range unbounded.  Periods
+// without any rewards are omitted.
+//
+// As with StakeInfo, the subsidy of a vote is the stakebase sum, which
+// includes the share of any stake pool.
+func (w *Wallet) StakeRewards(period StakeRewardPeriod, since, until time.Time) ([]StakeRewardsData, error) {
+	var res []StakeRewardsData
+	periods := make(map[time.Time]int) // period start -> index into res
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		stakemgrNs := dbtx.ReadBucket(wstakemgrNamespaceKey)
+		return w.StakeMgr.ForEachStakeReward(stakemgrNs, func(r *udb.StakeReward) error {
+			if r.Time.Before(since) || (!until.IsZero() && !r.Time.Before(until)) {
+				return nil
+			}
+			start := period.start(r.Time)
+			i, ok := periods[start]
+			if !ok {
+				i = len(res)
+				periods[start] = i
+				res = append(res, StakeRewardsData{Start: start})
+			}
+			data := &res[i]
+			switch r.Type {
+			case udb.StakeRewardVote:
+				data.Voted++
+				data.TotalSubsidy += r.Subsidy
+			case udb.StakeRewardRevocation:
+				data.Revoked++
+				if r.Missed {
+					data.Missed++
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Rewards are indexed by block height, and block timestamps are not
+	// strictly increasing, so periods may have been found out of order.
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Start.Before(res[j].Start)
+	})
+	return res, nil
+}
+
 // LockedOutpoint returns whether an outpoint has been marked as locked and
 // should not be used as an input for created transactions.
 func (w *Wallet) LockedOutpoint(op wire.OutPoint) bool {
-	_, locked := w.lockedOutpoints[op]
+	w.lockedOutpointsMu.Lock()
+	locked := w.lockedOutpoint(op, time.Now())
+	w.lockedOutpointsMu.Unlock()
+	return locked
+}
+
+// lockedOutpoint returns whether an outpoint is locked at time now, removing
+// the lock if it has expired.  The locked outpoints mutex must be held.
+func (w *Wallet) lockedOutpoint(op wire.OutPoint, now time.Time) bool {
+	expires, locked := w.lockedOutpoints[op]
+	if locked && !expires.IsZero() && !now.Before(expires) {
+		delete(w.lockedOutpoints, op)
+		return false
+	}
 	return locked
 }
 
 // LockOutpoint marks an outpoint as locked, that is, it should not be used as
 // an input for newly created transactions.
 func (w *Wallet) LockOutpoint(op wire.OutPoint) {
-	w.lockedOutpoints[op] = struct{}{}
+	w.lockedOutpointsMu.Lock()
+	w.lockedOutpoints[op] = time.Time{}
+	w.lockedOutpointsMu.Unlock()
 }
 
 // UnlockOutpoint marks an outpoint as unlocked, that is, it may be used as an
 // input for newly created transactions.
 func (w *Wallet) UnlockOutpoint(op wire.OutPoint) {
+	w.lockedOutpointsMu.Lock()
 	delete(w.lockedOutpoints, op)
+	w.lockedOutpointsMu.Unlock()
 }
 
 // ResetLockedOutpoints resets the set of locked outpoints so all may be used
 // as inputs for new transactions.
 func (w *Wallet) ResetLockedOutpoints() {
-	w.lockedOutpoints = map[wire.OutPoint]struct{}{}
+	w.lockedOutpointsMu.Lock()
+	w.lockedOutpoints = make(map[wire.OutPoint]time.Time)
+	w.lockedOutpointsMu.Unlock()
 }
 
 // LockedOutpoints returns a slice of currently locked outpoints.  This is
 // intended to be used by marshaling the result as a JSON array for
 // listlockunspent RPC results.
 func (w *Wallet) LockedOutpoints() []hcjson.TransactionInput {
-	locked := make([]hcjson.TransactionInput, len(w.lockedOutpoints))
-	i := 0
+	w.lockedOutpointsMu.Lock()
+	defer w.lockedOutpointsMu.Unlock()
+
+	now := time.Now()
+	locked := make([]hcjson.TransactionInput, 0, len(w.lockedOutpoints))
 	for op := range w.lockedOutpoints {
-		locked[i] = hcjson.TransactionInput{
+		if !w.lockedOutpoint(op, now) {
+			continue
+		}
+		locked = append(locked, hcjson.TransactionInput{
 			Txid: op.Hash.String(),
 			Vout: op.Index,
-		}
-		i++
+		})
 	}
 	return locked
 }
@@ -3747,7 +4673,7 @@ func (w *Wallet) LockedOutpoints() []hcjson.TransactionInput {
 // resendUnminedTxs iterates through all transactions that spend from wallet
 // credits that are not known to have been mined into a block, and attempts
 // to send each to the chain server for relay.
-func (w *Wallet) resendUnminedTxs(chainClient *hcrpcclient.Client) {
+func (w *Wallet) resendUnminedTxs(chainClient ChainClient) {
 	var txs []*wire.MsgTx
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
@@ -3761,7 +4687,7 @@ func (w *Wallet) resendUnminedTxs(chainClient *hcrpcclient.Client) {
 	}
 
 	for _, tx := range txs {
-		resp, err := chainClient.SendRawTransaction(tx, w.AllowHighFees)
+		resp, err := chainClient.SendRawTransaction(tx, w.allowHighFees)
 		if err != nil {
 			// TODO(jrick): Check error for if this tx is a double spend,
 			// remove it if so.
@@ -3884,9 +4810,9 @@ func (w *Wallet) TotalReceivedForAccounts(minConf int32) ([]AccountTotalReceived
 	return results, err
 }
 
-// TotalReceivedForAddr iterates through a wallet's transaction history,
-// returning the total amount of hcd received for a single wallet
-// address.
+// TotalReceivedForAddr returns the total amount of hcd received for a single
+// wallet address.  Only the transactions recorded in the address credit index
+// for addr are examined.
 func (w *Wallet) TotalReceivedForAddr(addr hcutil.Address, minConf int32) (hcutil.Amount, error) {
 	var amount hcutil.Amount
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
@@ -3894,40 +4820,34 @@ func (w *Wallet) TotalReceivedForAddr(addr hcutil.Address, minConf int32) (hcuti
 
 		_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
 
-		var (
-			addrStr    = addr.EncodeAddress()
-			stopHeight int32
-		)
-
-		if minConf > 0 {
-			stopHeight = tipHeight - minConf + 1
-		} else {
-			stopHeight = -1
+		addrStr := addr.EncodeAddress()
+		details, err := w.TxStore.AddressTxDetails(txmgrNs, addr)
+		if err != nil {
+			return err
 		}
-		rangeFn := func(details []udb.TxDetails) (bool, error) {
-			for i := range details {
-				detail := &details[i]
-				for _, cred := range detail.Credits {
-					pkVersion := detail.MsgTx.TxOut[cred.Index].Version
-					pkScript := detail.MsgTx.TxOut[cred.Index].PkScript
-					_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkVersion,
-						pkScript, w.chainParams)
-					// An error creating addresses from the output script only
-					// indicates a non-standard script, so ignore this credit.
-					if err != nil {
-						continue
-					}
-					for _, a := range addrs {
-						if addrStr == a.EncodeAddress() {
-							amount += cred.Amount
-							break
-						}
+		for _, detail := range details {
+			if minConf > 0 && !confirmed(minConf, detail.Block.Height, tipHeight) {
+				continue
+			}
+			for _, cred := range detail.Credits {
+				pkVersion := detail.MsgTx.TxOut[cred.Index].Version
+				pkScript := detail.MsgTx.TxOut[cred.Index].PkScript
+				_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkVersion,
+					pkScript, w.chainParams)
+				// An error creating addresses from the output script only
+				// indicates a non-standard script, so ignore this credit.
+				if err != nil {
+					continue
+				}
+				for _, a := range addrs {
+					if addrStr == a.EncodeAddress() {
+						amount += cred.Amount
+						break
 					}
 				}
 			}
-			return false, nil
 		}
-		return w.TxStore.RangeTransactions(txmgrNs, 0, stopHeight, rangeFn)
+		return nil
 	})
 	return amount, err
 }
@@ -3935,9 +4855,29 @@ func (w *Wallet) TotalReceivedForAddr(addr hcutil.Address, minConf int32) (hcuti
 // SendOutputs creates and sends payment transactions. It returns the
 // transaction hash upon success
 func (w *Wallet) SendOutputs(outputs []*wire.TxOut, account uint32,
-	minconf int32, changeAddr string, fromAddress string) (*chainhash.Hash, error) {
+	minconf int32, changeAddr string, fromAddress string,
+	options ...SendOutputsCallOption) (*chainhash.Hash, error) {
 
-	relayFee := w.RelayFee()
+	var opts sendOutputsCallOptions
+	for _, o := range options {
+		o(&opts)
+	}
+	if opts.hasNulldata {
+		out, err := nulldataOutput(opts.nulldata)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs[:len(outputs):len(outputs)], out)
+	}
+	err := checkNulldataOutputs(outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	relayFee, err := w.AccountRelayFee(account)
+	if err != nil {
+		return nil, err
+	}
 	for _, output := range outputs {
 		err := txrules.CheckOutput(output, relayFee)
 		if err != nil {
@@ -3958,17 +4898,10 @@ func (w *Wallet) SendOutputs(outputs []*wire.TxOut, account uint32,
 	return &hash, nil
 }
 
+// MakeNulldataOutput returns a zero value OP_RETURN output carrying a payload
+// of at most MaxNulldataSize bytes.
 func (w *Wallet) MakeNulldataOutput(payLoad []byte) (*wire.TxOut, error) {
-	payLoadScript, err := txscript.GenerateProvablyPruneableOut(payLoad)
-	if err == nil {
-		payLoadTx := &wire.TxOut{
-			Value:    int64(0),
-			PkScript: payLoadScript,
-		}
-		return payLoadTx, nil
-	} else {
-		return nil, err
-	}
+	return nulldataOutput(payLoad)
 }
 
 // SignatureError records the underlying error when validating a transaction
@@ -4244,7 +5177,53 @@ func (w *Wallet) isRelevantTx(dbtx walletdb.ReadTx, tx *wire.MsgTx) bool {
 // PublishTransaction saves (if relevant) and sends the transaction to the
 // consensus RPC server so it can be propigated to other nodes and eventually
 // mined.  If the send fails, the transaction is not added to the wallet.
-func (w *Wallet) PublishTransaction(tx *wire.MsgTx, serializedTx []byte, client *hcrpcclient.Client) (*chainhash.Hash, error) {
+func (w *Wallet) PublishTransaction(tx *wire.MsgTx, serializedTx []byte, client ChainClient) (*chainhash.Hash, error) {
+	return w.publishTransaction(tx, serializedTx, w.allowHighFees, client)
+}
+
+// PublishRawTransaction publishes a transaction which was not authored by the
+// wallet like PublishTransaction, after checking that it does not conflict
+// with the wallet.  Transactions spending outpoints which are locked or
+// reserved by the wallet, including by transaction drafts other than the
+// transaction itself, error with code ErrLocked, and transactions which
+// double spend unmined wallet transactions error with code ErrDoubleSpend.
+func (w *Wallet) PublishRawTransaction(tx *wire.MsgTx, serializedTx []byte, allowHighFees bool, client ChainClient) (*chainhash.Hash, error) {
+	txHash := tx.TxHash()
+	for _, in := range tx.TxIn {
+		op := &in.PreviousOutPoint
+		if w.LockedOutpoint(*op) {
+			str := fmt.Sprintf("transaction %v spends locked outpoint %v",
+				&txHash, op)
+			return nil, apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+		}
+	}
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		for _, in := range tx.TxIn {
+			op := &in.PreviousOutPoint
+			draft := w.TxStore.TxDraftReserving(txmgrNs, op)
+			if draft != nil && *draft != txHash {
+				str := fmt.Sprintf("transaction %v spends outpoint %v "+
+					"reserved by transaction draft %v", &txHash, op, draft)
+				return apperrors.E{ErrorCode: apperrors.ErrLocked, Description: str, Err: nil}
+			}
+			spender := w.TxStore.UnminedSpender(dbtx, op)
+			if spender != nil && *spender != txHash {
+				str := fmt.Sprintf("transaction %v is a double spend "+
+					"of unmined wallet transaction %v", &txHash, spender)
+				return apperrors.E{ErrorCode: apperrors.ErrDoubleSpend, Description: str, Err: nil}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return w.publishTransaction(tx, serializedTx, allowHighFees, client)
+}
+
+func (w *Wallet) publishTransaction(tx *wire.MsgTx, serializedTx []byte, allowHighFees bool, client ChainClient) (*chainhash.Hash, error) {
 	var relevant bool
 	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
 		relevant = w.isRelevantTx(dbtx, tx)
@@ -4255,21 +5234,81 @@ func (w *Wallet) PublishTransaction(tx *wire.MsgTx, serializedTx []byte, client
 	}
 
 	if !relevant {
-		return client.SendRawTransaction(tx, w.AllowHighFees)
+		return w.SendRawTransaction(tx, allowHighFees, client)
 	}
 
 	var txHash *chainhash.Hash
 	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-		err := w.processSerializedTransaction(dbtx, serializedTx, nil, nil)
+		err := w.processSerializedTransaction(dbtx, serializedTx, nil, nil, "publish")
 		if err != nil {
 			return err
 		}
-		txHash, err = client.SendRawTransaction(tx, w.AllowHighFees)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		txHash, err = w.sendRawTransaction(txmgrNs, client, tx, allowHighFees)
 		return err
 	})
 	return txHash, err
 }
 
+// AbandonTransaction removes an unmined transaction, and every unmined
+// transaction spending from it, from the wallet.  Outputs spent by the removed
+// transactions become spendable again and their credits no longer contribute
+// to the wallet balance, and pending balance changes the wallet added to the
+// omni engine for them are reversed.  This is intended for transactions that
+// conflict with the main chain or will otherwise never be mined.
+//
+// Mined transactions can not be abandoned.  An error with the code
+// ErrValueNoExists is returned if hash does not identify an unmined wallet
+// transaction.
+func (w *Wallet) AbandonTransaction(hash *chainhash.Hash) error {
+	var removed []*udb.TxRecord
+	var omniPending []*udb.OmniPending
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		var err error
+		removed, err = w.TxStore.RemoveUnminedTx(txmgrNs, hash)
+		if err != nil {
+			return err
+		}
+		for _, rec := range removed {
+			err := w.auditRemoval(txmgrNs, &rec.Hash, udb.AuditAbandon,
+				-1, "abandontransaction")
+			if err != nil {
+				return err
+			}
+			p, err := w.TxStore.FetchOmniPending(txmgrNs, &rec.Hash)
+			if err != nil {
+				return err
+			}
+			if p != nil {
+				omniPending = append(omniPending, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range removed {
+		log.Infof("Abandoned unmined transaction %v", &rec.Hash)
+	}
+
+	// Abandoned transactions will never be mined, so the omni engine must
+	// no longer count their pending balance changes.  Changes which fail
+	// to be reversed are retried when the next block is connected.
+	if w.EnableOmni() {
+		for _, p := range omniPending {
+			err := w.reverseOmniPending(p, OmniPendingRemoved)
+			if err != nil {
+				log.Warnf("Failed to reverse the pending omni balance "+
+					"change of abandoned transaction %v: %v", &p.Hash, err)
+			}
+		}
+	}
+	return nil
+}
+
 // ChainParams returns the network parameters for the blockchain the wallet
 // belongs to.
 func (w *Wallet) ChainParams() *chaincfg.Params {
@@ -4410,6 +5449,7 @@ func decodeStakePoolColdExtKey(encStr string, params *chaincfg.Params) (map[stri
 func Open(db walletdb.DB, pubPass []byte, privPass []byte, votingEnabled bool, addressReuse bool,
 	ticketAddress ,subsidyAddress hcutil.Address, poolAddress hcutil.Address, poolFees float64, ticketFee float64,
 	gapLimit int, stakePoolColdExtKey string, allowHighFees bool,
+	txLimits txauthor.TxLimits, splitTxs bool,
 	relayFee float64, enableOmni bool, params *chaincfg.Params) (*Wallet, error) {
 
 	// Migrate to the unified DB if necessary.
@@ -4465,6 +5505,8 @@ func Open(db walletdb.DB, pubPass []byte, privPass []byte, votingEnabled bool, a
 		gapLimit,
 		stakePoolColdAddrs,
 		allowHighFees,
+		txLimits,
+		splitTxs,
 		addrMgr,
 		txMgr,
 		smgr,
@@ -0,0 +1,187 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/hcutil"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+func TestListUnspentFields(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	spend := addSpendWithChange(t, w)
+	spendHash := spend.TxHash()
+	change := wire.OutPoint{Hash: spendHash, Index: 1}
+
+	find := func(includeLocked bool) *hcjson.ListUnspentResult {
+		results, err := w.ListUnspent(0, 9999999, nil, includeLocked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if r.TxID == spendHash.String() && r.Vout == 1 {
+				return r
+			}
+		}
+		return nil
+	}
+
+	r := find(false)
+	if r == nil {
+		t.Fatal("change output is not listed")
+	}
+	if r.ScriptClass != "pubkeyhash" || r.Account != "default" ||
+		r.AccountNumber != udb.DefaultAccountNum || r.Locked ||
+		r.TicketLocked || r.ToMaturity != 0 || r.RedeemScript != "" {
+		t.Errorf("change output listed as %+v", r)
+	}
+
+	// Locked outputs are only listed when requested, and are reported
+	// as locked.
+	w.LockOutpoint(change)
+	if r := find(false); r != nil {
+		t.Errorf("locked output listed as %+v", r)
+	}
+	r = find(true)
+	if r == nil || !r.Locked {
+		t.Errorf("locked output listed as %+v", r)
+	}
+	w.UnlockOutpoint(change)
+	if r := find(false); r == nil || r.Locked {
+		t.Errorf("unlocked output listed as %+v", r)
+	}
+}
+
+func TestListUnspentTicketMaturity(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	walletPkScript(t, w, udb.ExternalBranch, 0)
+	addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+		udb.ExternalBranch, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket := addTestTicket(t, w, 1, addrs[0], addrs[0])
+	ticketHash := ticket.TxHash()
+
+	find := func(includeLocked bool) *hcjson.ListUnspentResult {
+		results, err := w.ListUnspent(0, 9999999, nil, includeLocked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if r.TxID == ticketHash.String() && r.Vout == 0 {
+				return r
+			}
+		}
+		return nil
+	}
+
+	// The immature ticket output is only listed with locked outputs.
+	if r := find(false); r != nil {
+		t.Errorf("immature ticket output listed as %+v", r)
+	}
+	r := find(true)
+	if r == nil {
+		t.Fatal("immature ticket output is not listed with locked outputs")
+	}
+	want := int64(w.chainParams.TicketMaturity) + 1
+	if !r.TicketLocked || r.ToMaturity != want {
+		t.Errorf("ticket output listed as ticket locked %v with %d blocks "+
+			"to maturity, want ticket locked with %d", r.TicketLocked,
+			r.ToMaturity, want)
+	}
+}
+
+func TestAddressCounts(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	accounts, addresses, err := w.AddressCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The default, postquantum and imported accounts.
+	if accounts != 3 {
+		t.Errorf("%d accounts, want 3", accounts)
+	}
+	if addresses != 0 {
+		t.Errorf("%d watched addresses of a new wallet, want 0", addresses)
+	}
+
+	// Syncing the address index watches every address through it.
+	walletPkScript(t, w, udb.ExternalBranch, 100)
+	_, addresses, err = w.AddressCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addresses != 101 {
+		t.Errorf("%d watched addresses, want 101", addresses)
+	}
+}
+
+func TestInputSourceFromAddress(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	addSpendWithChange(t, w)
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{2}}, foreignSigScript))
+	funding.AddTxOut(wire.NewTxOut(2e8, walletPkScript(t, w, udb.ExternalBranch, 1)))
+	addUnminedTx(t, w, funding)
+
+	addr := func(branch, index uint32) string {
+		addrs, err := w.AccountBranchAddressRange(udb.DefaultAccountNum,
+			branch, index, index+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addrs[0].EncodeAddress()
+	}
+	tests := []struct {
+		name        string
+		fromAddress string
+		total       hcutil.Amount
+		inputs      int
+	}{
+		{"any address", "", 699e6 + 2e8, 2},
+		{"change address", addr(udb.InternalBranch, 0), 699e6, 1},
+		{"receiving address", addr(udb.ExternalBranch, 1), 2e8, 1},
+		{"spent address", addr(udb.ExternalBranch, 0), 0, 0},
+	}
+	for _, test := range tests {
+		err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+			txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+			addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+			_, tipHeight := w.TxStore.MainChainTip(txmgrNs)
+			source := w.TxStore.MakeInputSource(txmgrNs, addrmgrNs,
+				udb.DefaultAccountNum, 0, tipHeight)
+			total, inputs, scripts, err := source.SelectInputs(100e8,
+				test.fromAddress)
+			if err != nil {
+				return err
+			}
+			if total != test.total || len(inputs) != test.inputs ||
+				len(scripts) != test.inputs {
+				t.Errorf("%s: selected %d inputs totaling %v, want %d "+
+					"totaling %v", test.name, len(inputs), total,
+					test.inputs, test.total)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
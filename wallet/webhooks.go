@@ -0,0 +1,423 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HcashOrg/hcd/hcjson"
+	"github.com/HcashOrg/hcd/txscript"
+	"github.com/HcashOrg/hcwallet/omnilib"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+const (
+	// WebhookSignatureHeader is the HTTP header of webhook requests holding
+	// the hex-encoded HMAC-SHA256 of the request body, keyed by the
+	// configured secret.
+	WebhookSignatureHeader = "X-Hcwallet-Signature"
+
+	// WebhookDeliveryHeader is the HTTP header of webhook requests holding
+	// the delivery sequence number.  It is the same for every attempt to
+	// deliver a notification, so receivers may use it to ignore duplicates.
+	WebhookDeliveryHeader = "X-Hcwallet-Delivery"
+
+	// webhookPollInterval is the time between checks for webhook deliveries
+	// due to be attempted again.
+	webhookPollInterval = 5 * time.Second
+
+	// webhookRetryDelay is the delay after the first failed attempt to
+	// deliver a notification.  The delay doubles after each further failed
+	// attempt up to webhookMaxRetryDelay.
+	webhookRetryDelay    = 10 * time.Second
+	webhookMaxRetryDelay = time.Hour
+
+	// webhookTimeout is the time limit of a single delivery attempt.
+	webhookTimeout = 30 * time.Second
+
+	// webhookFailedRetention is the time deliveries which were given up
+	// remain recorded after the last attempt before they are pruned.
+	webhookFailedRetention = 7 * 24 * time.Hour
+)
+
+// Webhook notification events.
+const (
+	// WebhookCredit notifies a confirmed credit to a wallet address.
+	WebhookCredit = "credit"
+
+	// WebhookOmniSend notifies a confirmed omni simple send which was
+	// validated by the omni engine.
+	WebhookOmniSend = "omnisend"
+)
+
+// WebhookConfig configures the delivery of received payment notifications.
+type WebhookConfig struct {
+	// URLs are sent a POST request for every notification.
+	URLs []string
+
+	// Secret keys the HMAC-SHA256 signature of each request body sent in
+	// the WebhookSignatureHeader header.
+	Secret []byte
+
+	// Confirmations is the number of confirmations a transaction must have
+	// before it is notified.
+	Confirmations int32
+
+	// MaxAttempts is the number of attempts to deliver a notification
+	// before giving up, or zero to retry until delivered.
+	MaxAttempts uint32
+}
+
+// WebhookPayload is the JSON body of a webhook request.
+type WebhookPayload struct {
+	Event       string          `json:"event"`
+	TxID        string          `json:"txid"`
+	Vout        *uint32         `json:"vout,omitempty"`
+	Address     string          `json:"address,omitempty"`
+	Account     string          `json:"account,omitempty"`
+	Amount      float64         `json:"amount,omitempty"`
+	BlockHash   string          `json:"blockhash"`
+	BlockHeight int32           `json:"blockheight"`
+	Omni        json.RawMessage `json:"omni,omitempty"`
+}
+
+// SetWebhooks configures the delivery of received payment notifications.  A
+// nil config disables queueing new notifications, while notifications which
+// were already queued remain recorded until webhooks are configured again.
+func (w *Wallet) SetWebhooks(cfg *WebhookConfig) {
+	w.webhooksMu.Lock()
+	w.webhooks = cfg
+	w.webhooksMu.Unlock()
+
+	w.wakeWebhookDispatcher()
+}
+
+// Webhooks returns the configured webhooks, or nil if none are configured.
+func (w *Wallet) Webhooks() *WebhookConfig {
+	w.webhooksMu.Lock()
+	cfg := w.webhooks
+	w.webhooksMu.Unlock()
+	return cfg
+}
+
+func (w *Wallet) wakeWebhookDispatcher() {
+	select {
+	case w.webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// queueWebhooks queues webhook notifications for the transactions of the
+// blocks which reached the configured number of confirmations with the main
+// chain tip at height.  Blocks are queued in order, beginning after the last
+// queued block, or with the block reaching the confirmations at this tip when
+// no blocks were queued before.
+func (w *Wallet) queueWebhooks(height int32) {
+	cfg := w.Webhooks()
+	if cfg == nil {
+		return
+	}
+	end := height - cfg.Confirmations + 1
+	if end < 0 {
+		return
+	}
+
+	var upToDate bool
+	var payloads []*WebhookPayload
+	var omniCandidates []*WebhookPayload
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+		begin := end
+		if last, ok := w.TxStore.WebhookHeight(txmgrNs); ok {
+			begin = last + 1
+		}
+		if begin > end {
+			upToDate = true
+			return nil
+		}
+		return w.TxStore.RangeTransactions(txmgrNs, begin, end, func(details []udb.TxDetails) (bool, error) {
+			for i := range details {
+				d := &details[i]
+				for _, cred := range d.Credits {
+					if cred.Change {
+						continue
+					}
+					p := webhookPayload(WebhookCredit, d)
+					vout := cred.Index
+					p.Vout = &vout
+					p.Amount = cred.Amount.ToCoin()
+					txOut := d.MsgTx.TxOut[cred.Index]
+					_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.Version,
+						txOut.PkScript, w.chainParams)
+					if err == nil && len(addrs) != 0 {
+						p.Address = addrs[0].EncodeAddress()
+						account, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
+						if err == nil {
+							p.Account, _ = w.Manager.AccountName(addrmgrNs, account)
+						}
+					}
+					payloads = append(payloads, p)
+				}
+				if w.EnableOmni() && w.checkValidateOmniTransaction(&d.TxRecord) {
+					omniCandidates = append(omniCandidates,
+						webhookPayload(WebhookOmniSend, d))
+				}
+			}
+			return false, nil
+		})
+	})
+	if err != nil {
+		log.Errorf("Failed to queue webhook notifications: %v", err)
+		return
+	}
+	if upToDate {
+		return
+	}
+
+	for _, p := range omniCandidates {
+		omniTx, err := omniSimpleSend(p.TxID)
+		if err != nil {
+			log.Warnf("Failed to look up omni transaction %v for webhook "+
+				"notification: %v", p.TxID, err)
+			continue
+		}
+		if omniTx != nil {
+			p.Omni = omniTx
+			payloads = append(payloads, p)
+		}
+	}
+
+	now := time.Now()
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		for _, p := range payloads {
+			body, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+			for _, url := range cfg.URLs {
+				err := w.TxStore.QueueWebhookDelivery(txmgrNs, &udb.WebhookDelivery{
+					URL:         url,
+					Payload:     body,
+					Status:      udb.WebhookPending,
+					Created:     now,
+					NextAttempt: now,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return w.TxStore.PutWebhookHeight(txmgrNs, end)
+	})
+	if err != nil {
+		log.Errorf("Failed to queue webhook notifications: %v", err)
+		return
+	}
+	if len(payloads) != 0 {
+		w.wakeWebhookDispatcher()
+	}
+}
+
+func webhookPayload(event string, d *udb.TxDetails) *WebhookPayload {
+	return &WebhookPayload{
+		Event:       event,
+		TxID:        d.Hash.String(),
+		BlockHash:   d.Block.Hash.String(),
+		BlockHeight: d.Block.Height,
+	}
+}
+
+// omniSimpleSend returns the omni engine's description of a transaction if it
+// is a valid simple send, or nil otherwise.
+func omniSimpleSend(txid string) (json.RawMessage, error) {
+	cmd := hcjson.NewOmniGettransactionCmd()
+	cmd.Txid = &txid
+	res, err := omnilib.SendCmd(cmd)
+	if err != nil {
+		return nil, err
+	}
+	var tx struct {
+		TypeInt int  `json:"type_int"`
+		Valid   bool `json:"valid"`
+	}
+	err = json.Unmarshal(res, &tx)
+	if err != nil {
+		return nil, err
+	}
+	if tx.TypeInt != 0 || !tx.Valid {
+		return nil, nil
+	}
+	return res, nil
+}
+
+// webhookDispatcher delivers queued webhook notifications when new
+// notifications are queued, and periodically attempts again the deliveries
+// which previously failed.  It must be run as a goroutine.
+func (w *Wallet) webhookDispatcher() {
+	defer w.wg.Done()
+
+	quit := w.quitChan()
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.webhookWake:
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+		w.deliverWebhooks(quit)
+	}
+}
+
+// deliverWebhooks attempts every pending delivery which is due to a configured
+// URL, removing the deliveries which succeed.  Deliveries to URLs which are no
+// longer configured are left pending.  Deliveries which were given up are
+// pruned webhookFailedRetention after the last attempt.
+func (w *Wallet) deliverWebhooks(quit <-chan struct{}) {
+	cfg := w.Webhooks()
+	if cfg == nil {
+		return
+	}
+	urls := make(map[string]struct{}, len(cfg.URLs))
+	for _, url := range cfg.URLs {
+		urls[url] = struct{}{}
+	}
+
+	now := time.Now()
+	var due []udb.WebhookDelivery
+	var prune []uint64
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		return w.TxStore.ForEachWebhookDelivery(txmgrNs, func(d *udb.WebhookDelivery) error {
+			switch {
+			case d.Status == udb.WebhookDelivered:
+				prune = append(prune, d.Seq)
+				return nil
+			case d.Status == udb.WebhookFailed &&
+				now.Sub(d.NextAttempt) >= webhookFailedRetention:
+				prune = append(prune, d.Seq)
+				return nil
+			}
+			if d.Status != udb.WebhookPending || d.NextAttempt.After(now) {
+				return nil
+			}
+			if _, ok := urls[d.URL]; ok {
+				due = append(due, *d)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		log.Errorf("Failed to read webhook deliveries: %v", err)
+		return
+	}
+
+	// Deliveries recorded as delivered before delivered deliveries were
+	// removed are pruned with the expired failed deliveries.
+	if len(prune) != 0 {
+		err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+			for _, seq := range prune {
+				err := w.TxStore.RemoveWebhookDelivery(txmgrNs, seq)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorf("Failed to prune webhook deliveries: %v", err)
+			return
+		}
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	for i := range due {
+		select {
+		case <-quit:
+			return
+		default:
+		}
+
+		d := &due[i]
+		d.Attempts++
+		err := postWebhook(client, d, cfg.Secret)
+		switch {
+		case err == nil:
+			d.Status = udb.WebhookDelivered
+			log.Debugf("Delivered webhook notification %d to %s", d.Seq, d.URL)
+		case cfg.MaxAttempts != 0 && d.Attempts >= cfg.MaxAttempts:
+			d.Status = udb.WebhookFailed
+			d.NextAttempt = time.Now()
+			log.Warnf("Giving up delivering webhook notification %d to %s "+
+				"after %d attempts: %v", d.Seq, d.URL, d.Attempts, err)
+		default:
+			d.NextAttempt = time.Now().Add(webhookRetryBackoff(d.Attempts))
+			log.Infof("Failed to deliver webhook notification %d to %s "+
+				"(attempt %d): %v", d.Seq, d.URL, d.Attempts, err)
+		}
+		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+			if d.Status == udb.WebhookDelivered {
+				return w.TxStore.RemoveWebhookDelivery(txmgrNs, d.Seq)
+			}
+			return w.TxStore.PutWebhookDelivery(txmgrNs, d)
+		})
+		if err != nil {
+			log.Errorf("Failed to record webhook delivery %d: %v", d.Seq, err)
+			return
+		}
+	}
+}
+
+// webhookRetryBackoff returns the delay before the next attempt to deliver a
+// notification after the given number of failed attempts.
+func webhookRetryBackoff(attempts uint32) time.Duration {
+	delay := webhookRetryDelay
+	for i := uint32(1); i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetryDelay {
+		delay = webhookMaxRetryDelay
+	}
+	return delay
+}
+
+// postWebhook sends a single delivery attempt.  Any response status other than
+// 2xx is an error.
+func postWebhook(client *http.Client, d *udb.WebhookDelivery, secret []byte) error {
+	req, err := http.NewRequest("POST", d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(d.Payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(d.Seq, 10))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
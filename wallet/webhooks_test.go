@@ -0,0 +1,151 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/wallet/udb"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+// webhookDeliveries returns the recorded webhook deliveries.
+func webhookDeliveries(t *testing.T, w *Wallet) []udb.WebhookDelivery {
+	var deliveries []udb.WebhookDelivery
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		return w.TxStore.ForEachWebhookDelivery(txmgrNs, func(d *udb.WebhookDelivery) error {
+			deliveries = append(deliveries, *d)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deliveries
+}
+
+func TestDeliverWebhooksRemovesDelivered(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	w.SetWebhooks(&WebhookConfig{URLs: []string{server.URL}})
+
+	now := time.Now()
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		// A delivery recorded as delivered before delivered deliveries
+		// were removed, and a pending delivery.
+		for _, status := range []udb.WebhookStatus{udb.WebhookDelivered, udb.WebhookPending} {
+			err := w.TxStore.QueueWebhookDelivery(txmgrNs, &udb.WebhookDelivery{
+				URL:         server.URL,
+				Payload:     []byte("{}"),
+				Status:      status,
+				Created:     now,
+				NextAttempt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w.deliverWebhooks(make(chan struct{}))
+	if requests != 1 {
+		t.Errorf("%d webhook requests, want 1", requests)
+	}
+	if d := webhookDeliveries(t, w); len(d) != 0 {
+		t.Errorf("%d deliveries remain after delivering, want none", len(d))
+	}
+}
+
+func TestRollBackRewindsWebhookHeight(t *testing.T) {
+	w, _, teardown := reorgTestWallet(t)
+	defer teardown()
+
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		err := w.TxStore.PutWebhookHeight(txmgrNs, 4)
+		if err != nil {
+			return err
+		}
+		return w.RollBack(dbtx, 3, nil, "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		height, _ := w.TxStore.WebhookHeight(txmgrNs)
+		if height != 2 {
+			t.Errorf("webhook height %d after rolling back to height 3, "+
+				"want 2", height)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeliverWebhooksPrunesFailed(t *testing.T) {
+	w, teardown := testWallet(t)
+	defer teardown()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	w.SetWebhooks(&WebhookConfig{URLs: []string{server.URL}, MaxAttempts: 1})
+
+	now := time.Now()
+	err := walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		// Deliveries given up before and within the retention period, and
+		// a pending delivery which is given up after the next attempt.
+		for _, d := range []udb.WebhookDelivery{
+			{Status: udb.WebhookFailed, NextAttempt: now.Add(-webhookFailedRetention - time.Hour)},
+			{Status: udb.WebhookFailed, NextAttempt: now.Add(-time.Hour)},
+			{Status: udb.WebhookPending, NextAttempt: now},
+		} {
+			d.URL = server.URL
+			d.Payload = []byte("{}")
+			d.Created = now
+			err := w.TxStore.QueueWebhookDelivery(txmgrNs, &d)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w.deliverWebhooks(make(chan struct{}))
+	deliveries := webhookDeliveries(t, w)
+	if len(deliveries) != 2 {
+		t.Fatalf("%d deliveries remain, want 2", len(deliveries))
+	}
+	for i, seq := range []uint64{1, 2} {
+		d := &deliveries[i]
+		if d.Seq != seq || d.Status != udb.WebhookFailed {
+			t.Errorf("delivery %d is %v, want failed delivery %d", d.Seq,
+				d.Status, seq)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bdb
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+	"github.com/boltdb/bolt"
+)
+
+// TestCompactReopenFailure ensures that the original database is restored and
+// reopened when the compacted database can not be opened in its place.
+func TestCompactReopenFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bdbcompacttest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dbPath := filepath.Join(dir, "test.db")
+
+	db, err := openDB(dbPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { db.Close() }()
+	bucketKey := []byte("bucket")
+	key := []byte("key")
+	value := []byte("value")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail opening the compacted database only.
+	errOpen := errors.New("open failed")
+	opens := 0
+	boltOpen = func(path string, mode os.FileMode, options *bolt.Options) (*bolt.DB, error) {
+		opens++
+		if opens == 1 {
+			return nil, errOpen
+		}
+		return bolt.Open(path, mode, options)
+	}
+	defer func() { boltOpen = bolt.Open }()
+
+	err = walletdb.Compact(db)
+	if err != errOpen {
+		t.Errorf("compact returned %v, want %v", err, errOpen)
+	}
+	if opens != 2 {
+		t.Errorf("database opened %d times, want 2", opens)
+	}
+	for _, path := range []string{dbPath + ".compact", dbPath + ".precompact"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s remains after failed compaction", path)
+		}
+	}
+
+	// The original database remains usable.
+	done := make(chan error, 1)
+	go func() {
+		done <- walletdb.View(db, func(tx walletdb.ReadTx) error {
+			v := tx.ReadBucket(bucketKey).Get(key)
+			if !bytes.Equal(v, value) {
+				t.Errorf("Get returned %q, want %q", v, value)
+			}
+			return nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("database was not reopened")
+	}
+}
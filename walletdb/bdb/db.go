@@ -7,13 +7,20 @@
 package bdb
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/HcashOrg/hcwallet/walletdb"
 )
 
+// boltOpen opens bolt databases when compaction reopens the database.  It is
+// replaced by tests to fail reopening.
+var boltOpen = bolt.Open
+
 // convertErr converts some bolt errors to the equivalent walletdb error.
 func convertErr(err error) error {
 	switch err {
@@ -55,6 +62,8 @@ func convertErr(err error) error {
 // provides a root bucket against which all read and writes occur.
 type transaction struct {
 	boltTx *bolt.Tx
+	db     *db
+	closed bool
 }
 
 func (tx *transaction) ReadBucket(key []byte) walletdb.ReadBucket {
@@ -90,7 +99,9 @@ func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
 //
 // This function is part of the walletdb.Tx interface implementation.
 func (tx *transaction) Commit() error {
-	return convertErr(tx.boltTx.Commit())
+	err := tx.boltTx.Commit()
+	tx.release()
+	return convertErr(err)
 }
 
 // Rollback undoes all changes that have been made to the root bucket and all of
@@ -98,7 +109,21 @@ func (tx *transaction) Commit() error {
 //
 // This function is part of the walletdb.Tx interface implementation.
 func (tx *transaction) Rollback() error {
-	return convertErr(tx.boltTx.Rollback())
+	err := tx.boltTx.Rollback()
+	tx.release()
+	return convertErr(err)
+}
+
+// release removes the transaction from the database's count of open
+// transactions the first time the transaction is committed or rolled back.
+func (tx *transaction) release() {
+	if tx.closed {
+		return
+	}
+	tx.closed = true
+	tx.db.mu.Lock()
+	tx.db.openTxs--
+	tx.db.mu.Unlock()
 }
 
 // bucket is an internal type used to represent a collection of key/value pairs
@@ -271,20 +296,42 @@ func (c *cursor) Seek(seek []byte) (key, value []byte) {
 	return (*bolt.Cursor)(c).Seek(seek)
 }
 
+// compactWaitTimeout is the maximum duration Compact waits for open
+// transactions to finish before giving up.
+const compactWaitTimeout = 30 * time.Second
+
 // db represents a collection of namespaces which are persisted and implements
 // the walletdb.Db interface.  All database access is performed through
 // transactions which are obtained through the specific Namespace.
-type db bolt.DB
+type db struct {
+	boltDB *bolt.DB
+	path   string
+
+	// mu protects boltDB and the count of open transactions.  It is held
+	// while the database is compacted to prevent new transactions from
+	// beginning on the database file being replaced.
+	mu      sync.Mutex
+	openTxs int
+}
 
-// Enforce db implements the walletdb.Db interface.
+// Enforce db implements the walletdb.Db and walletdb.Compacter interfaces.
 var _ walletdb.DB = (*db)(nil)
+var _ walletdb.Compacter = (*db)(nil)
 
 func (db *db) beginTx(writable bool) (*transaction, error) {
-	boltTx, err := (*bolt.DB)(db).Begin(writable)
+	db.mu.Lock()
+	boltDB := db.boltDB
+	db.openTxs++
+	db.mu.Unlock()
+
+	tx := &transaction{db: db}
+	boltTx, err := boltDB.Begin(writable)
 	if err != nil {
+		tx.release()
 		return nil, convertErr(err)
 	}
-	return &transaction{boltTx: boltTx}, nil
+	tx.boltTx = boltTx
+	return tx, nil
 }
 
 func (db *db) BeginReadTx() (walletdb.ReadTx, error) {
@@ -300,16 +347,147 @@ func (db *db) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
 //
 // This function is part of the walletdb.Db interface implementation.
 func (db *db) Copy(w io.Writer) error {
-	return convertErr((*bolt.DB)(db).View(func(tx *bolt.Tx) error {
-		return tx.Copy(w)
-	}))
+	tx, err := db.beginTx(false)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return convertErr(tx.boltTx.Copy(w))
 }
 
 // Close cleanly shuts down the database and syncs all data.
 //
 // This function is part of the walletdb.Db interface implementation.
 func (db *db) Close() error {
-	return convertErr((*bolt.DB)(db).Close())
+	db.mu.Lock()
+	boltDB := db.boltDB
+	db.mu.Unlock()
+	return convertErr(boltDB.Close())
+}
+
+// Compact rewrites the database to a new file containing only the data
+// reachable from the root buckets, dropping the pages held by the freelist,
+// and replaces the database file with it.  Compaction waits for all open
+// transactions to finish and new transactions block until it completes.
+//
+// This function is part of the walletdb.Compacter interface implementation.
+func (db *db) Compact() error {
+	deadline := time.Now().Add(compactWaitTimeout)
+	for {
+		db.mu.Lock()
+		if db.openTxs == 0 {
+			break
+		}
+		db.mu.Unlock()
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for open transactions " +
+				"to finish before compacting")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer db.mu.Unlock()
+
+	compactPath := db.path + ".compact"
+	err := os.Remove(compactPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	dst, err := bolt.Open(compactPath, 0600, nil)
+	if err != nil {
+		return convertErr(err)
+	}
+	err = copyDB(dst, db.boltDB)
+	closeErr := dst.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(compactPath)
+		return convertErr(err)
+	}
+
+	err = db.boltDB.Close()
+	if err != nil {
+		os.Remove(compactPath)
+		return convertErr(err)
+	}
+
+	// Keep the original database until the compacted database is opened
+	// in its place, so the original can be restored and reopened if the
+	// compacted database can not be.
+	backupPath := db.path + ".precompact"
+	err = os.Rename(db.path, backupPath)
+	if err != nil {
+		os.Remove(compactPath)
+		if reopenErr := db.reopen(); reopenErr != nil {
+			return reopenErr
+		}
+		return err
+	}
+	err = os.Rename(compactPath, db.path)
+	if err == nil {
+		err = db.reopen()
+		if err == nil {
+			os.Remove(backupPath)
+			return nil
+		}
+	}
+	os.Remove(compactPath)
+	os.Remove(db.path)
+	restoreErr := os.Rename(backupPath, db.path)
+	if restoreErr != nil {
+		return fmt.Errorf("failed to restore the database from %s after "+
+			"compacting failed: %v", backupPath, restoreErr)
+	}
+	if reopenErr := db.reopen(); reopenErr != nil {
+		return reopenErr
+	}
+	return err
+}
+
+// reopen opens the database file as the database's bolt database.
+// The previous bolt database must be closed.  Requires mu to be locked.
+func (db *db) reopen() error {
+	boltDB, err := boltOpen(db.path, 0600, nil)
+	if err != nil {
+		return convertErr(err)
+	}
+	db.boltDB = boltDB
+	return nil
+}
+
+// copyDB copies every top level bucket of src into dst.
+func copyDB(dst, src *bolt.DB) error {
+	return src.View(func(srcTx *bolt.Tx) error {
+		return dst.Update(func(dstTx *bolt.Tx) error {
+			return srcTx.ForEach(func(name []byte, b *bolt.Bucket) error {
+				dstBucket, err := dstTx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(dstBucket, b)
+			})
+		})
+	})
+}
+
+// copyBucket recursively copies the key/value pairs, nested buckets, and
+// sequence of src into dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	err := dst.SetSequence(src.Sequence())
+	if err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		nestedDst, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(nestedDst, src.Bucket(k))
+	})
 }
 
 // filesExists reports whether the named file or directory exists.
@@ -330,5 +508,8 @@ func openDB(dbPath string, create bool) (walletdb.DB, error) {
 	}
 
 	boltDB, err := bolt.Open(dbPath, 0600, nil)
-	return (*db)(boltDB), convertErr(err)
+	if err != nil {
+		return nil, convertErr(err)
+	}
+	return &db{boltDB: boltDB, path: dbPath}, nil
 }
@@ -122,3 +122,137 @@ func TestCreateOpenUnsupported(t *testing.T) {
 		return
 	}
 }
+
+// TestOpenWrongDriver ensures that a database can not be opened by a driver
+// other than the one which created it, unless the database does not record its
+// driver.
+func TestOpenWrongDriver(t *testing.T) {
+	// The aliased driver opens bdb databases, but under a different type.
+	dbType := "bdbalias"
+	openAlias := func(args ...interface{}) (walletdb.DB, error) {
+		return walletdb.Open("bdb", args...)
+	}
+	walletdb.RegisterDriver(walletdb.Driver{
+		DbType: dbType,
+		Create: openAlias,
+		Open:   openAlias,
+	})
+
+	dbPath := "wrongdrivertest.db"
+	db, err := walletdb.Create("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer os.Remove(dbPath)
+	db.Close()
+
+	if db, err := walletdb.Open(dbType, dbPath); err == nil {
+		db.Close()
+		t.Fatalf("opened a bdb database with the %s driver", dbType)
+	}
+	db, err = walletdb.Open("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+
+	// Databases created before the driver was recorded are opened by any
+	// driver.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return tx.DeleteTopLevelBucket([]byte("walletdb"))
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("failed to remove recorded driver: %v", err)
+	}
+	db, err = walletdb.Open(dbType, dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database without a recorded driver: %v", err)
+	}
+	db.Close()
+}
+
+// TestCompact ensures that compacting a database preserves its buckets and
+// key/value pairs while reclaiming the space of deleted data.
+func TestCompact(t *testing.T) {
+	dbPath := "compacttest.db"
+	db, err := walletdb.Create("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	bucketKey := []byte("bucket")
+	nestedKey := []byte("nested")
+	value := make([]byte, 1024)
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		nested, err := b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 4096; i++ {
+			err = nested.Put([]byte(fmt.Sprintf("key%04d", i)), value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to populate database: %v", err)
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		nested := tx.ReadWriteBucket(bucketKey).NestedReadWriteBucket(nestedKey)
+		for i := 1; i < 4096; i++ {
+			err := nested.Delete([]byte(fmt.Sprintf("key%04d", i)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to delete keys: %v", err)
+	}
+
+	fi, err := os.Stat(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizeBefore := fi.Size()
+
+	err = walletdb.Compact(db)
+	if err != nil {
+		t.Fatalf("failed to compact database: %v", err)
+	}
+
+	fi, err = os.Stat(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() >= sizeBefore {
+		t.Errorf("compaction did not shrink the database - before %d "+
+			"bytes, after %d bytes", sizeBefore, fi.Size())
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		nested := tx.ReadBucket(bucketKey).NestedReadBucket(nestedKey)
+		if nested == nil {
+			return fmt.Errorf("nested bucket missing after compaction")
+		}
+		if len(nested.Get([]byte("key0000"))) != len(value) {
+			return fmt.Errorf("value missing after compaction")
+		}
+		if nested.Get([]byte("key0001")) != nil {
+			return fmt.Errorf("deleted value present after compaction")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
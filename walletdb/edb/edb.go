@@ -0,0 +1,611 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package edb implements an encrypting wrapper around a walletdb database.
+
+Every value written through the wrapper is sealed with AES-256-GCM under a
+random master key, bound to the path of the bucket holding it and the key it
+is stored under, so values can not be moved to another key or bucket without
+failing authentication.  The master key is
+itself encrypted with a key derived from a passphrase (the wallet's public
+passphrase) and kept in a dedicated top-level bucket of the wrapped database.
+
+Keys and bucket names are stored in plaintext so that the ordering and seeks
+relied on by the wallet continue to work.  As some wallet buckets are keyed by
+transaction and address hashes, those hashes remain readable from the database
+file.  All other transaction metadata, scripts, and address details are only
+readable with the passphrase.
+
+# Usage
+
+A newly created database is initialized for encryption with Create and later
+reopened with Open:
+
+	db, err := walletdb.Create("bdb", "path/to/database.db")
+	if err != nil {
+		// Handle error
+	}
+	db, err = edb.Create(db, pubPassphrase)
+
+	db, err := walletdb.Open("bdb", "path/to/database.db")
+	if err != nil {
+		// Handle error
+	}
+	encrypted, err := edb.IsEncrypted(db)
+	if err == nil && encrypted {
+		db, err = edb.Open(db, pubPassphrase)
+	}
+*/
+package edb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/HcashOrg/hcwallet/internal/zero"
+	"github.com/HcashOrg/hcwallet/snacl"
+	"github.com/HcashOrg/hcwallet/walletdb"
+)
+
+var (
+	// ErrNotEncrypted describes the error condition of attempting to open a
+	// database which was not initialized for encryption.
+	ErrNotEncrypted = errors.New("database is not encrypted")
+
+	// ErrAlreadyEncrypted describes the error condition of attempting to
+	// initialize encryption of a database which is already encrypted.
+	ErrAlreadyEncrypted = errors.New("database is already encrypted")
+
+	// ErrInvalidPassphrase describes the error condition of opening an
+	// encrypted database with the wrong passphrase.
+	ErrInvalidPassphrase = errors.New("invalid database passphrase")
+
+	// ErrCorruptValue describes the error condition of reading a value
+	// which fails authentication, either because the database file was
+	// modified or because the value was not written through the wrapper.
+	// It is returned by ForEach, and by walletdb.View and walletdb.Update
+	// for the transaction which read the value.
+	ErrCorruptValue = errors.New("database value failed authentication")
+)
+
+var (
+	// metaBucketKey is the key of the top-level bucket holding the
+	// encryption parameters.  It is not encrypted itself.
+	metaBucketKey = []byte("edb")
+
+	// paramsKey keys the marshaled parameters used to derive the key
+	// encrypting the master key.
+	paramsKey = []byte("params")
+
+	// masterKeyKey keys the encrypted master key.
+	masterKeyKey = []byte("masterkey")
+)
+
+// db wraps a walletdb.DB, encrypting all values written to it and decrypting
+// all values read from it.
+type db struct {
+	inner     walletdb.DB
+	masterKey *snacl.CryptoKey
+	aead      cipher.AEAD
+}
+
+// Enforce db implements the walletdb DB and Compacter interfaces.
+var (
+	_ walletdb.DB        = (*db)(nil)
+	_ walletdb.Compacter = (*db)(nil)
+)
+
+func newDB(inner walletdb.DB, masterKey *snacl.CryptoKey) (*db, error) {
+	block, err := aes.NewCipher(masterKey[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &db{inner: inner, masterKey: masterKey, aead: aead}, nil
+}
+
+// sealMasterKey encrypts the master key with a new key derived from
+// passphrase and writes it, along with the derivation parameters, to the meta
+// bucket.
+func sealMasterKey(meta walletdb.ReadWriteBucket, masterKey *snacl.CryptoKey, passphrase []byte) error {
+	sk, err := snacl.NewSecretKey(&passphrase, snacl.DefaultN, snacl.DefaultR,
+		snacl.DefaultP)
+	if err != nil {
+		return err
+	}
+	defer sk.Zero()
+	encKey, err := sk.Encrypt(masterKey[:])
+	if err != nil {
+		return err
+	}
+	err = meta.Put(paramsKey, sk.Marshal())
+	if err != nil {
+		return err
+	}
+	return meta.Put(masterKeyKey, encKey)
+}
+
+// IsEncrypted returns whether the database has been initialized for
+// encryption by Create.
+func IsEncrypted(inner walletdb.DB) (bool, error) {
+	var encrypted bool
+	err := walletdb.View(inner, func(tx walletdb.ReadTx) error {
+		encrypted = tx.ReadBucket(metaBucketKey) != nil
+		return nil
+	})
+	return encrypted, err
+}
+
+// Create initializes encryption of a newly created database, deriving the key
+// encrypting the database from passphrase, and returns the database wrapped
+// for encrypted access.  Values already written to the database are not
+// encrypted and will not be readable through the returned database, so this
+// must be called before any other data is written.
+func Create(inner walletdb.DB, passphrase []byte) (walletdb.DB, error) {
+	masterKey, err := snacl.GenerateCryptoKey()
+	if err != nil {
+		return nil, err
+	}
+	err = walletdb.Update(inner, func(tx walletdb.ReadWriteTx) error {
+		if tx.ReadBucket(metaBucketKey) != nil {
+			return ErrAlreadyEncrypted
+		}
+		meta, err := tx.CreateTopLevelBucket(metaBucketKey)
+		if err != nil {
+			return err
+		}
+		return sealMasterKey(meta, masterKey, passphrase)
+	})
+	if err != nil {
+		masterKey.Zero()
+		return nil, err
+	}
+	return newDB(inner, masterKey)
+}
+
+// Open decrypts the master key of an encrypted database using passphrase and
+// returns the database wrapped for encrypted access.  ErrNotEncrypted is
+// returned if the database was not initialized by Create, and
+// ErrInvalidPassphrase if the passphrase is incorrect.
+func Open(inner walletdb.DB, passphrase []byte) (walletdb.DB, error) {
+	var masterKey snacl.CryptoKey
+	err := walletdb.View(inner, func(tx walletdb.ReadTx) error {
+		meta := tx.ReadBucket(metaBucketKey)
+		if meta == nil {
+			return ErrNotEncrypted
+		}
+		var sk snacl.SecretKey
+		err := sk.Unmarshal(meta.Get(paramsKey))
+		if err != nil {
+			return err
+		}
+		err = sk.DeriveKey(&passphrase)
+		if err == snacl.ErrInvalidPassword {
+			return ErrInvalidPassphrase
+		}
+		if err != nil {
+			return err
+		}
+		defer sk.Zero()
+		decrypted, err := sk.Decrypt(meta.Get(masterKeyKey))
+		if err != nil {
+			return err
+		}
+		defer zero.Bytes(decrypted)
+		if len(decrypted) != len(masterKey) {
+			return snacl.ErrMalformed
+		}
+		copy(masterKey[:], decrypted)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newDB(inner, &masterKey)
+}
+
+// Unwrapper is implemented by the transactions of databases which wrap
+// another database, such as an encrypted database, so that ChangePassphrase can
+// reach the transaction of the encrypted database.
+type Unwrapper interface {
+	Unwrap() walletdb.ReadWriteTx
+}
+
+// ChangePassphrase reencrypts the master key of the database accessed by tx
+// with a key derived from a new passphrase.  As the master key is unchanged,
+// no values need to be reencrypted.  Transactions of wrapping databases are
+// unwrapped through the Unwrapper interface.  ErrNotEncrypted is returned for
+// transactions of databases which are not encrypted, and an error is returned
+// for transactions of encrypted databases which can not be unwrapped.
+func ChangePassphrase(tx walletdb.ReadWriteTx, passphrase []byte) error {
+	inner := tx
+	for {
+		u, ok := inner.(Unwrapper)
+		if !ok {
+			break
+		}
+		inner = u.Unwrap()
+	}
+	t, ok := inner.(*transaction)
+	if !ok {
+		if tx.ReadBucket(metaBucketKey) == nil {
+			return ErrNotEncrypted
+		}
+		return fmt.Errorf("edb: unrecognized transaction type %T of an "+
+			"encrypted database", inner)
+	}
+	innerTx := t.inner.(walletdb.ReadWriteTx)
+	meta := innerTx.ReadWriteBucket(metaBucketKey)
+	if meta == nil {
+		return ErrNotEncrypted
+	}
+	return sealMasterKey(meta, t.db.masterKey, passphrase)
+}
+
+// appendPathElem appends a bucket name or key, prefixed by its length, to the
+// additional authenticated data of a bucket path.
+func appendPathElem(path, elem []byte) []byte {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(elem)))
+	path = append(path, l[:]...)
+	return append(path, elem...)
+}
+
+// seal encrypts value, binding it to the bucket path and the key it is stored
+// under, which are encoded together as the additional authenticated data ad.
+// The nonce is prepended to the ciphertext.
+func (db *db) seal(ad, value []byte) ([]byte, error) {
+	nonceSize := db.aead.NonceSize()
+	sealed := make([]byte, nonceSize, nonceSize+len(value)+db.aead.Overhead())
+	_, err := io.ReadFull(rand.Reader, sealed)
+	if err != nil {
+		return nil, err
+	}
+	return db.aead.Seal(sealed, sealed, value, ad), nil
+}
+
+// open decrypts a value sealed with the additional authenticated data ad.  Nil
+// values, which describe nested buckets or missing keys, are returned as nil.
+// ErrCorruptValue is returned for values which fail authentication.
+func (db *db) open(ad, sealed []byte) ([]byte, error) {
+	if sealed == nil {
+		return nil, nil
+	}
+	nonceSize := db.aead.NonceSize()
+	if len(sealed) < nonceSize+db.aead.Overhead() {
+		return nil, ErrCorruptValue
+	}
+	// The destination is allocated so that empty values are returned
+	// non-nil and remain valid after the transaction ends.
+	dst := make([]byte, 0, len(sealed)-nonceSize-db.aead.Overhead())
+	value, err := db.aead.Open(dst, sealed[:nonceSize], sealed[nonceSize:], ad)
+	if err != nil {
+		return nil, ErrCorruptValue
+	}
+	return value, nil
+}
+
+// BeginReadTx opens a database read transaction.
+//
+// This function is part of the walletdb.DB interface implementation.
+func (db *db) BeginReadTx() (walletdb.ReadTx, error) {
+	tx, err := db.inner.BeginReadTx()
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{inner: tx, db: db}, nil
+}
+
+// BeginReadWriteTx opens a database read+write transaction.
+//
+// This function is part of the walletdb.DB interface implementation.
+func (db *db) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	tx, err := db.inner.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{inner: tx, db: db}, nil
+}
+
+// Copy writes a copy of the encrypted database to the provided writer.
+//
+// This function is part of the walletdb.DB interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	return db.inner.Copy(w)
+}
+
+// Close closes the wrapped database and zeros the master key.
+//
+// This function is part of the walletdb.DB interface implementation.
+func (db *db) Close() error {
+	err := db.inner.Close()
+	db.masterKey.Zero()
+	return err
+}
+
+// Compact compacts the wrapped database.
+//
+// This function is part of the walletdb.Compacter interface implementation.
+func (db *db) Compact() error {
+	return walletdb.Compact(db.inner)
+}
+
+// transaction wraps a read-only or read-write transaction of the wrapped
+// database.  Write operations return ErrTxNotWritable when the wrapped
+// transaction is read-only.
+//
+// As the walletdb interfaces do not return errors from reads, a value which
+// fails authentication is read as missing and the error is recorded in err.
+// The transaction then fails: the error is returned by Err, which walletdb.View
+// and walletdb.Update return in place of a nil error, and Commit rolls back the
+// wrapped transaction and returns the error, which Rollback also returns.
+type transaction struct {
+	inner walletdb.ReadTx
+	db    *db
+	err   error
+}
+
+// Enforce transaction implements the walletdb ErrRecorder interface.
+var _ walletdb.ErrRecorder = (*transaction)(nil)
+
+// open decrypts a value read by the transaction, recording the first value
+// which fails authentication.
+func (tx *transaction) open(ad, sealed []byte) []byte {
+	value, err := tx.db.open(ad, sealed)
+	if err != nil && tx.err == nil {
+		tx.err = err
+	}
+	return value
+}
+
+// Err returns the error of the first value read by the transaction which
+// failed authentication, or nil.
+//
+// This function is part of the walletdb.ErrRecorder interface implementation.
+func (tx *transaction) Err() error {
+	return tx.err
+}
+
+// bucket returns the wrapper of a bucket of the wrapped database with the
+// path path.
+func (tx *transaction) bucket(inner walletdb.ReadBucket, path []byte) *bucket {
+	return &bucket{inner: inner, tx: tx, path: path}
+}
+
+func (tx *transaction) ReadBucket(key []byte) walletdb.ReadBucket {
+	b := tx.inner.ReadBucket(key)
+	if b == nil {
+		return nil
+	}
+	return tx.bucket(b, appendPathElem(nil, key))
+}
+
+func (tx *transaction) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	innerTx, ok := tx.inner.(walletdb.ReadWriteTx)
+	if !ok {
+		return nil
+	}
+	b := innerTx.ReadWriteBucket(key)
+	if b == nil {
+		return nil
+	}
+	return tx.bucket(b, appendPathElem(nil, key))
+}
+
+func (tx *transaction) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	innerTx, ok := tx.inner.(walletdb.ReadWriteTx)
+	if !ok {
+		return nil, walletdb.ErrTxNotWritable
+	}
+	b, err := innerTx.CreateTopLevelBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	return tx.bucket(b, appendPathElem(nil, key)), nil
+}
+
+func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
+	innerTx, ok := tx.inner.(walletdb.ReadWriteTx)
+	if !ok {
+		return walletdb.ErrTxNotWritable
+	}
+	return innerTx.DeleteTopLevelBucket(key)
+}
+
+func (tx *transaction) Commit() error {
+	innerTx, ok := tx.inner.(walletdb.ReadWriteTx)
+	if !ok {
+		return walletdb.ErrTxNotWritable
+	}
+	if tx.err != nil {
+		innerTx.Rollback()
+		return tx.err
+	}
+	return innerTx.Commit()
+}
+
+func (tx *transaction) Rollback() error {
+	err := tx.inner.Rollback()
+	if tx.err != nil {
+		return tx.err
+	}
+	return err
+}
+
+// bucket wraps a bucket of the wrapped database, encrypting values on writes
+// and decrypting them on reads.  path encodes the names of the buckets from
+// the top-level bucket down to and including this bucket.
+type bucket struct {
+	inner walletdb.ReadBucket
+	tx    *transaction
+	path  []byte
+}
+
+// ad returns the additional authenticated data of the value stored under key,
+// which is also the path of the nested bucket named key.
+func (b *bucket) ad(key []byte) []byte {
+	ad := make([]byte, 0, len(b.path)+4+len(key))
+	ad = append(ad, b.path...)
+	return appendPathElem(ad, key)
+}
+
+// nested returns the wrapper of the nested bucket with name key.
+func (b *bucket) nested(inner walletdb.ReadBucket, key []byte) *bucket {
+	return b.tx.bucket(inner, b.ad(key))
+}
+
+// Enforce bucket implements the walletdb Bucket interfaces.
+var _ walletdb.ReadWriteBucket = (*bucket)(nil)
+
+func (b *bucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
+	nested := b.inner.NestedReadBucket(key)
+	if nested == nil {
+		return nil
+	}
+	return b.nested(nested, key)
+}
+
+func (b *bucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		return nil
+	}
+	nested := inner.NestedReadWriteBucket(key)
+	if nested == nil {
+		return nil
+	}
+	return b.nested(nested, key)
+}
+
+func (b *bucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		return nil, walletdb.ErrTxNotWritable
+	}
+	nested, err := inner.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	return b.nested(nested, key), nil
+}
+
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.ReadWriteBucket, error) {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		return nil, walletdb.ErrTxNotWritable
+	}
+	nested, err := inner.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, err
+	}
+	return b.nested(nested, key), nil
+}
+
+func (b *bucket) DeleteNestedBucket(key []byte) error {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		return walletdb.ErrTxNotWritable
+	}
+	return inner.DeleteNestedBucket(key)
+}
+
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	return b.inner.ForEach(func(k, v []byte) error {
+		if v != nil {
+			v = b.tx.open(b.ad(k), v)
+			if v == nil {
+				return b.tx.err
+			}
+		}
+		return fn(k, v)
+	})
+}
+
+func (b *bucket) Get(key []byte) []byte {
+	return b.tx.open(b.ad(key), b.inner.Get(key))
+}
+
+func (b *bucket) Put(key, value []byte) error {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		return walletdb.ErrTxNotWritable
+	}
+	sealed, err := b.tx.db.seal(b.ad(key), value)
+	if err != nil {
+		return err
+	}
+	return inner.Put(key, sealed)
+}
+
+func (b *bucket) Delete(key []byte) error {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		return walletdb.ErrTxNotWritable
+	}
+	return inner.Delete(key)
+}
+
+func (b *bucket) ReadCursor() walletdb.ReadCursor {
+	return &cursor{inner: b.inner.ReadCursor(), bucket: b}
+}
+
+func (b *bucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	inner, ok := b.inner.(walletdb.ReadWriteBucket)
+	if !ok {
+		// Read-only cursors error on deletes.
+		return &cursor{inner: b.inner.ReadCursor(), bucket: b}
+	}
+	return &cursor{inner: inner.ReadWriteCursor(), bucket: b}
+}
+
+// cursor wraps a cursor of the wrapped database, decrypting the values of
+// each key/value pair.  Nil values of nested buckets are returned unchanged.
+type cursor struct {
+	inner  walletdb.ReadCursor
+	bucket *bucket
+}
+
+func (c *cursor) pair(k, v []byte) ([]byte, []byte) {
+	if v != nil {
+		v = c.bucket.tx.open(c.bucket.ad(k), v)
+	}
+	return k, v
+}
+
+func (c *cursor) First() (key, value []byte) {
+	return c.pair(c.inner.First())
+}
+
+func (c *cursor) Last() (key, value []byte) {
+	return c.pair(c.inner.Last())
+}
+
+func (c *cursor) Next() (key, value []byte) {
+	return c.pair(c.inner.Next())
+}
+
+func (c *cursor) Prev() (key, value []byte) {
+	return c.pair(c.inner.Prev())
+}
+
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	return c.pair(c.inner.Seek(seek))
+}
+
+func (c *cursor) Delete() error {
+	inner, ok := c.inner.(walletdb.ReadWriteCursor)
+	if !ok {
+		return walletdb.ErrTxNotWritable
+	}
+	return inner.Delete()
+}
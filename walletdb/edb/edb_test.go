@@ -0,0 +1,311 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edb_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+	_ "github.com/HcashOrg/hcwallet/walletdb/bdb"
+	"github.com/HcashOrg/hcwallet/walletdb/edb"
+)
+
+// TestEncryptedDB ensures that values written through an encrypted database
+// are not stored in plaintext, are readable after reopening the database with
+// the passphrase, and remain readable after changing the passphrase.
+func TestEncryptedDB(t *testing.T) {
+	dbPath := "edbtest.db"
+	defer os.Remove(dbPath)
+
+	bucketKey := []byte("bucket")
+	nestedKey := []byte("nested")
+	key := []byte("key")
+	value := []byte("sensitive transaction metadata")
+	emptyKey := []byte("empty")
+	pass := []byte("public")
+	newPass := []byte("new public")
+
+	inner, err := walletdb.Create("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	db, err := edb.Create(inner, pass)
+	if err != nil {
+		inner.Close()
+		t.Fatalf("failed to initialize encryption: %v", err)
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		_, err = b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		err = b.Put(emptyKey, nil)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	})
+	if err != nil {
+		db.Close()
+		t.Fatalf("failed to populate database: %v", err)
+	}
+	db.Close()
+
+	// The raw database must not contain the plaintext value.
+	inner, err = walletdb.Open("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { inner.Close() }()
+	err = walletdb.View(inner, func(tx walletdb.ReadTx) error {
+		v := tx.ReadBucket(bucketKey).Get(key)
+		if v == nil || bytes.Contains(v, value) {
+			t.Errorf("value stored as %x, want ciphertext", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := edb.IsEncrypted(inner)
+	if err != nil || !encrypted {
+		t.Fatalf("IsEncrypted returned %v, %v, want true", encrypted, err)
+	}
+
+	_, err = edb.Open(inner, []byte("wrong"))
+	if err != edb.ErrInvalidPassphrase {
+		t.Fatalf("open with wrong passphrase returned %v, want %v", err,
+			edb.ErrInvalidPassphrase)
+	}
+	db, err = edb.Open(inner, pass)
+	if err != nil {
+		t.Fatalf("failed to open encrypted database: %v", err)
+	}
+	checkValues := func() {
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			b := tx.ReadBucket(bucketKey)
+			if v := b.Get(key); !bytes.Equal(v, value) {
+				t.Errorf("Get returned %q, want %q", v, value)
+			}
+			if v := b.Get(emptyKey); v == nil || len(v) != 0 {
+				t.Errorf("Get of empty value returned %v, want empty non-nil "+
+					"slice", v)
+			}
+			if b.NestedReadBucket(nestedKey) == nil {
+				t.Errorf("missing nested bucket")
+			}
+			var pairs int
+			c := b.ReadCursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				pairs++
+				switch {
+				case bytes.Equal(k, key) && !bytes.Equal(v, value):
+					t.Errorf("cursor returned %q, want %q", v, value)
+				case bytes.Equal(k, nestedKey) && v != nil:
+					t.Errorf("cursor returned non-nil value for nested " +
+						"bucket")
+				}
+			}
+			if pairs != 3 {
+				t.Errorf("cursor iterated %d pairs, want 3", pairs)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkValues()
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return edb.ChangePassphrase(tx, newPass)
+	})
+	if err != nil {
+		t.Fatalf("failed to change passphrase: %v", err)
+	}
+	_, err = edb.Open(inner, pass)
+	if err != edb.ErrInvalidPassphrase {
+		t.Fatalf("open with old passphrase returned %v, want %v", err,
+			edb.ErrInvalidPassphrase)
+	}
+	db, err = edb.Open(inner, newPass)
+	if err != nil {
+		t.Fatalf("failed to open with new passphrase: %v", err)
+	}
+	checkValues()
+}
+
+// TestCorruptValue ensures that reading a value whose ciphertext was modified
+// fails the transaction which read it instead of returning the value as
+// missing.
+func TestCorruptValue(t *testing.T) {
+	dbPath := "edbcorrupttest.db"
+	defer os.Remove(dbPath)
+
+	bucketKey := []byte("bucket")
+	key := []byte("key")
+	otherKey := []byte("other")
+	value := []byte("sensitive transaction metadata")
+	pass := []byte("public")
+
+	inner, err := walletdb.Create("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer func() { inner.Close() }()
+	db, err := edb.Create(inner, pass)
+	if err != nil {
+		t.Fatalf("failed to initialize encryption: %v", err)
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	})
+	if err != nil {
+		t.Fatalf("failed to populate database: %v", err)
+	}
+
+	// Flip a byte of the ciphertext in the wrapped database.
+	err = walletdb.Update(inner, func(tx walletdb.ReadWriteTx) error {
+		b := tx.ReadWriteBucket(bucketKey)
+		v := append([]byte(nil), b.Get(key)...)
+		v[len(v)-1] ^= 0x01
+		return b.Put(key, v)
+	})
+	if err != nil {
+		t.Fatalf("failed to modify ciphertext: %v", err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		if v := tx.ReadBucket(bucketKey).Get(key); v != nil {
+			t.Errorf("Get of corrupt value returned %q", v)
+		}
+		return nil
+	})
+	if err != edb.ErrCorruptValue {
+		t.Errorf("view reading a corrupt value returned %v, want %v", err,
+			edb.ErrCorruptValue)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		return tx.ReadBucket(bucketKey).ForEach(func(k, v []byte) error {
+			return nil
+		})
+	})
+	if err != edb.ErrCorruptValue {
+		t.Errorf("ForEach over a corrupt value returned %v, want %v", err,
+			edb.ErrCorruptValue)
+	}
+
+	// Writes of a transaction which read a corrupt value are not committed.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b := tx.ReadWriteBucket(bucketKey)
+		b.ReadCursor().First()
+		return b.Put(otherKey, value)
+	})
+	if err != edb.ErrCorruptValue {
+		t.Errorf("update reading a corrupt value returned %v, want %v", err,
+			edb.ErrCorruptValue)
+	}
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		if tx.ReadBucket(bucketKey).Get(otherKey) != nil {
+			t.Error("update reading a corrupt value was committed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestValueBoundToBucket ensures that a value copied to the same key of a
+// different bucket fails authentication.
+func TestValueBoundToBucket(t *testing.T) {
+	dbPath := "edbbucketboundtest.db"
+	defer os.Remove(dbPath)
+
+	bucketKey := []byte("bucket")
+	nestedKey := []byte("nested")
+	otherKey := []byte("other")
+	key := []byte("key")
+	value := []byte("sensitive transaction metadata")
+	pass := []byte("public")
+
+	inner, err := walletdb.Create("bdb", dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer func() { inner.Close() }()
+	db, err := edb.Create(inner, pass)
+	if err != nil {
+		t.Fatalf("failed to initialize encryption: %v", err)
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		nested, err := b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateTopLevelBucket(otherKey)
+		if err != nil {
+			return err
+		}
+		return nested.Put(key, value)
+	})
+	if err != nil {
+		t.Fatalf("failed to populate database: %v", err)
+	}
+
+	// Copy the sealed value to the same key of the parent bucket and of
+	// another top-level bucket in the wrapped database.
+	err = walletdb.Update(inner, func(tx walletdb.ReadWriteTx) error {
+		b := tx.ReadWriteBucket(bucketKey)
+		v := append([]byte(nil), b.NestedReadBucket(nestedKey).Get(key)...)
+		err := b.Put(key, v)
+		if err != nil {
+			return err
+		}
+		return tx.ReadWriteBucket(otherKey).Put(key, v)
+	})
+	if err != nil {
+		t.Fatalf("failed to copy ciphertext: %v", err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		v := tx.ReadBucket(bucketKey).NestedReadBucket(nestedKey).Get(key)
+		if !bytes.Equal(v, value) {
+			t.Errorf("Get returned %q, want %q", v, value)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range [][]byte{bucketKey, otherKey} {
+		err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+			if v := tx.ReadBucket(path).Get(key); v != nil {
+				t.Errorf("Get of value copied to bucket %s returned %q",
+					path, v)
+			}
+			return nil
+		})
+		if err != edb.ErrCorruptValue {
+			t.Errorf("view reading a value copied to bucket %s returned "+
+				"%v, want %v", path, err, edb.ErrCorruptValue)
+		}
+	}
+}
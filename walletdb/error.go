@@ -39,6 +39,10 @@ var (
 
 	// ErrInvalid is returned if the specified database is not valid.
 	ErrInvalid = errors.New("invalid database")
+
+	// ErrCompactNotSupported is returned by Compact when the database driver
+	// does not implement the Compacter interface.
+	ErrCompactNotSupported = errors.New("database compaction not supported")
 )
 
 // Errors that can occur when beginning or committing a transaction.
@@ -9,7 +9,10 @@
 
 package walletdb
 
-import "io"
+import (
+	"fmt"
+	"io"
+)
 
 // ReadTx represents a database transaction that can only be used for reads.  If
 // a database update must occur, use a ReadWriteTx.
@@ -186,10 +189,45 @@ type DB interface {
 	Close() error
 }
 
+// Compacter is implemented by databases which are able to rewrite their
+// backing storage to reclaim the space of deleted data.
+type Compacter interface {
+	// Compact rewrites the database without unused space.  Open
+	// transactions are allowed to finish before compacting, and new
+	// transactions block until compaction completes.
+	Compact() error
+}
+
+// Compact compacts the database.  ErrCompactNotSupported is returned if the
+// database does not implement Compacter.
+func Compact(db DB) error {
+	c, ok := db.(Compacter)
+	if !ok {
+		return ErrCompactNotSupported
+	}
+	return c.Compact()
+}
+
+// ErrRecorder is implemented by transactions which record errors of operations
+// that can not return them, such as reads of values which fail authentication.
+type ErrRecorder interface {
+	// Err returns the first error recorded by the transaction, or nil.
+	Err() error
+}
+
+// txErr returns the error recorded by tx, if it is an ErrRecorder.
+func txErr(tx ReadTx) error {
+	if r, ok := tx.(ErrRecorder); ok {
+		return r.Err()
+	}
+	return nil
+}
+
 // View opens a database read transaction and executes the function f with the
 // transaction passed as a parameter.  After f exits, the transaction is rolled
 // back.  If f errors, its error is returned, not a rollback error (if any
-// occur).
+// occur).  If f does not error, any error recorded by the transaction is
+// returned.
 func View(db DB, f func(tx ReadTx) error) error {
 	tx, err := db.BeginReadTx()
 	if err != nil {
@@ -203,7 +241,11 @@ func View(db DB, f func(tx ReadTx) error) error {
 			err = rollbackErr
 		}
 	}()
-	return f(tx)
+	err = f(tx)
+	if err == nil {
+		err = txErr(tx)
+	}
+	return err
 }
 
 // Update opens a database read/write transaction and executes the function f
@@ -211,7 +253,8 @@ func View(db DB, f func(tx ReadTx) error) error {
 // error, the transaction is committed.  Otherwise, if f did error, the
 // transaction is rolled back.  If the rollback fails, the original error
 // returned by f is still returned.  If the commit fails, the commit error is
-// returned.
+// returned.  If f does not error but the transaction recorded an error, the
+// transaction is rolled back and the recorded error is returned.
 func Update(db DB, f func(tx ReadWriteTx) error) error {
 	tx, err := db.BeginReadWriteTx()
 	if err != nil {
@@ -229,6 +272,9 @@ func Update(db DB, f func(tx ReadWriteTx) error) error {
 	}()
 	
 	err = f(tx)
+	if err == nil {
+		err = txErr(tx)
+	}
 	panicked = false
 	return err
 }
@@ -276,9 +322,18 @@ func SupportedDrivers() []string {
 	return supportedDBs
 }
 
+// driverBucketKey is the key of the top-level bucket recording the type of
+// the driver which created a database under driverKey, so that the database is
+// never opened by a different driver.
+var (
+	driverBucketKey = []byte("walletdb")
+	driverKey       = []byte("driver")
+)
+
 // Create intializes and opens a database for the specified type.  The arguments
 // are specific to the database type driver.  See the documentation for the
-// database driver for further details.
+// database driver for further details.  The database type is recorded in the
+// created database and checked when it is opened.
 //
 // ErrDbUnknownType will be returned if the the database type is not registered.
 func Create(dbType string, args ...interface{}) (DB, error) {
@@ -287,7 +342,22 @@ func Create(dbType string, args ...interface{}) (DB, error) {
 		return nil, ErrDbUnknownType
 	}
 
-	return drv.Create(args...)
+	db, err := drv.Create(args...)
+	if err != nil {
+		return nil, err
+	}
+	err = Update(db, func(tx ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(driverBucketKey)
+		if err != nil {
+			return err
+		}
+		return b.Put(driverKey, []byte(dbType))
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
 // Open opens an existing database for the specified type.  The arguments are
@@ -295,11 +365,32 @@ func Create(dbType string, args ...interface{}) (DB, error) {
 // driver for further details.
 //
 // ErrDbUnknownType will be returned if the the database type is not registered.
+// Opening a database created for a different type is an error, while databases
+// which do not record their type may be opened by any driver able to.
 func Open(dbType string, args ...interface{}) (DB, error) {
 	drv, exists := drivers[dbType]
 	if !exists {
 		return nil, ErrDbUnknownType
 	}
 
-	return drv.Open(args...)
+	db, err := drv.Open(args...)
+	if err != nil {
+		return nil, err
+	}
+	var createdType []byte
+	err = View(db, func(tx ReadTx) error {
+		if b := tx.ReadBucket(driverBucketKey); b != nil {
+			createdType = append([]byte(nil), b.Get(driverKey)...)
+		}
+		return nil
+	})
+	if err == nil && createdType != nil && string(createdType) != dbType {
+		err = fmt.Errorf("database was created by the %s driver and can "+
+			"not be opened by the %s driver", createdType, dbType)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
 }
@@ -0,0 +1,726 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+	_ "github.com/mattn/go-sqlite3" // Register the database/sql driver
+)
+
+// schema creates the tables used to store buckets.  Every bucket other than
+// the implicit root bucket, which holds the top level buckets, is assigned a
+// row in the buckets table.  The key/value pairs of all buckets are held by the
+// kv table.  Nested buckets are recorded as a key with a NULL value and the ID
+// of the nested bucket as the child.
+const schema = `
+CREATE TABLE IF NOT EXISTS buckets (
+	id INTEGER PRIMARY KEY
+);
+CREATE TABLE IF NOT EXISTS kv (
+	bucket INTEGER NOT NULL,
+	key BLOB NOT NULL,
+	value BLOB,
+	child INTEGER,
+	PRIMARY KEY (bucket, key)
+) WITHOUT ROWID;
+`
+
+// rootBucketID is the ID of the bucket containing the top level buckets.
+const rootBucketID = 0
+
+// busyTimeout is the number of milliseconds a connection waits for a lock held
+// by another process before returning an error.
+const busyTimeout = "10000"
+
+// forEachBatchSize is the number of key/value pairs read at a time by
+// bucket.ForEach.
+const forEachBatchSize = 256
+
+// convertErr converts some database/sql errors to the equivalent walletdb
+// error.
+func convertErr(err error) error {
+	switch err {
+	case sql.ErrTxDone:
+		return walletdb.ErrTxClosed
+	}
+
+	// Return the original error if none of the above applies.
+	return err
+}
+
+// transaction represents a database transaction.  It can either by read-only or
+// read-write and implements the walletdb Tx interfaces.  The transaction
+// provides a root bucket against which all read and writes occur.
+type transaction struct {
+	sqlTx    *sql.Tx
+	db       *db
+	writable bool
+
+	// err records the first error encountered by a method which is unable
+	// to return it.  Committing the transaction fails with this error.
+	err error
+}
+
+// setErr records the first error of a method which is unable to return it.
+func (tx *transaction) setErr(err error) {
+	if tx.err == nil {
+		tx.err = convertErr(err)
+	}
+}
+
+// lookup returns the value or nested bucket ID of a key of a bucket.  The value
+// of a nested bucket is nil.
+func (tx *transaction) lookup(bucketID int64, key []byte) (value []byte, child sql.NullInt64, found bool, err error) {
+	row := tx.sqlTx.QueryRow(`SELECT value, child FROM kv WHERE bucket = ? AND key = ?`,
+		bucketID, key)
+	err = row.Scan(&value, &child)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, child, false, nil
+	case err != nil:
+		return nil, child, false, convertErr(err)
+	}
+	if !child.Valid && value == nil {
+		value = []byte{}
+	}
+	return value, child, true, nil
+}
+
+// nestedBucket returns the nested bucket of a parent bucket, or nil if the
+// bucket does not exist.
+func (tx *transaction) nestedBucket(parentID int64, key []byte) *bucket {
+	_, child, found, err := tx.lookup(parentID, key)
+	if err != nil {
+		tx.setErr(err)
+		return nil
+	}
+	if !found || !child.Valid {
+		return nil
+	}
+	return &bucket{tx: tx, id: child.Int64}
+}
+
+// createBucket creates a nested bucket of a parent bucket.
+func (tx *transaction) createBucket(parentID int64, key []byte) (*bucket, error) {
+	if !tx.writable {
+		return nil, walletdb.ErrTxNotWritable
+	}
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+	_, child, found, err := tx.lookup(parentID, key)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if child.Valid {
+			return nil, walletdb.ErrBucketExists
+		}
+		return nil, walletdb.ErrIncompatibleValue
+	}
+
+	res, err := tx.sqlTx.Exec(`INSERT INTO buckets DEFAULT VALUES`)
+	if err != nil {
+		return nil, convertErr(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.sqlTx.Exec(`INSERT INTO kv (bucket, key, child) VALUES (?, ?, ?)`,
+		parentID, key, id)
+	if err != nil {
+		return nil, convertErr(err)
+	}
+	return &bucket{tx: tx, id: id}, nil
+}
+
+// deleteBucket removes a nested bucket of a parent bucket and every bucket
+// nested within it.
+func (tx *transaction) deleteBucket(parentID int64, key []byte) error {
+	if !tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	_, child, found, err := tx.lookup(parentID, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return walletdb.ErrBucketNotFound
+	}
+	if !child.Valid {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	// Collect the IDs of the bucket and every bucket nested within it
+	// before removing any of their rows.
+	rows, err := tx.sqlTx.Query(`WITH RECURSIVE sub(id) AS (
+		SELECT ?
+		UNION ALL
+		SELECT kv.child FROM kv, sub WHERE kv.bucket = sub.id AND kv.child IS NOT NULL
+	) SELECT id FROM sub`, child.Int64)
+	if err != nil {
+		return convertErr(err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return convertErr(err)
+		}
+		ids = append(ids, id)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return convertErr(err)
+	}
+
+	for _, id := range ids {
+		_, err = tx.sqlTx.Exec(`DELETE FROM kv WHERE bucket = ?`, id)
+		if err != nil {
+			return convertErr(err)
+		}
+		_, err = tx.sqlTx.Exec(`DELETE FROM buckets WHERE id = ?`, id)
+		if err != nil {
+			return convertErr(err)
+		}
+	}
+	_, err = tx.sqlTx.Exec(`DELETE FROM kv WHERE bucket = ? AND key = ?`, parentID, key)
+	return convertErr(err)
+}
+
+func (tx *transaction) ReadBucket(key []byte) walletdb.ReadBucket {
+	return tx.ReadWriteBucket(key)
+}
+
+func (tx *transaction) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	b := tx.nestedBucket(rootBucketID, key)
+	// Don't return a non-nil interface to a nil pointer.
+	if b == nil {
+		return nil
+	}
+	return b
+}
+
+func (tx *transaction) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	b, err := tx.createBucket(rootBucketID, key)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
+	return tx.deleteBucket(rootBucketID, key)
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets to persistent storage.  If any operation of the
+// transaction failed, the transaction is rolled back and the error is returned.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Commit() error {
+	if !tx.writable {
+		tx.sqlTx.Rollback()
+		return walletdb.ErrTxNotWritable
+	}
+	if tx.err != nil {
+		tx.sqlTx.Rollback()
+		return tx.err
+	}
+	return convertErr(tx.sqlTx.Commit())
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of
+// its sub-buckets.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Rollback() error {
+	return convertErr(tx.sqlTx.Rollback())
+}
+
+// bucket is an internal type used to represent a collection of key/value pairs
+// and implements the walletdb Bucket interfaces.
+type bucket struct {
+	tx *transaction
+	id int64
+}
+
+// Enforce bucket implements the walletdb Bucket interfaces.
+var _ walletdb.ReadWriteBucket = (*bucket)(nil)
+
+// NestedReadWriteBucket retrieves a nested bucket with the given key.  Returns
+// nil if the bucket does not exist.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	nested := b.tx.nestedBucket(b.id, key)
+	// Don't return a non-nil interface to a nil pointer.
+	if nested == nil {
+		return nil
+	}
+	return nested
+}
+
+func (b *bucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
+	return b.NestedReadWriteBucket(key)
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key value is otherwise
+// invalid.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	nested, err := b.tx.createBucket(b.id, key)
+	if err != nil {
+		return nil, err
+	}
+	return nested, nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.  Returns ErrBucketNameRequired if the
+// key is empty or ErrIncompatibleValue if the key value is otherwise invalid.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.ReadWriteBucket, error) {
+	nested, err := b.tx.createBucket(b.id, key)
+	if err == walletdb.ErrBucketExists {
+		return b.NestedReadWriteBucket(key), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nested, nil
+}
+
+// DeleteNestedBucket removes a nested bucket with the given key.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction and
+// ErrBucketNotFound if the specified bucket does not exist.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) DeleteNestedBucket(key []byte) error {
+	return b.tx.deleteBucket(b.id, key)
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket.
+// This includes nested buckets, in which case the value is nil, but it does not
+// include the key/value pairs within those nested buckets.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	type pair struct {
+		k, v []byte
+	}
+	pairs := make([]pair, 0, forEachBatchSize)
+	var after []byte
+	for {
+		// Pairs are read in batches and the query is finished before
+		// calling fn, so that fn may modify the bucket.
+		query := `SELECT key, value, child FROM kv WHERE bucket = ? ORDER BY key LIMIT ?`
+		args := []interface{}{b.id, forEachBatchSize}
+		if after != nil {
+			query = `SELECT key, value, child FROM kv WHERE bucket = ? AND key > ? ORDER BY key LIMIT ?`
+			args = []interface{}{b.id, after, forEachBatchSize}
+		}
+		rows, err := b.tx.sqlTx.Query(query, args...)
+		if err != nil {
+			return convertErr(err)
+		}
+		pairs = pairs[:0]
+		for rows.Next() {
+			var p pair
+			var child sql.NullInt64
+			err := rows.Scan(&p.k, &p.v, &child)
+			if err != nil {
+				rows.Close()
+				return convertErr(err)
+			}
+			if !child.Valid && p.v == nil {
+				p.v = []byte{}
+			}
+			pairs = append(pairs, p)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return convertErr(err)
+		}
+
+		for _, p := range pairs {
+			err := fn(p.k, p.v)
+			if err != nil {
+				return err
+			}
+		}
+		if len(pairs) < forEachBatchSize {
+			return nil
+		}
+		after = pairs[len(pairs)-1].k
+	}
+}
+
+// Put saves the specified key/value pair to the bucket.  Keys that do not
+// already exist are added and keys that already exist are overwritten.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	if !b.tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	if len(key) == 0 {
+		return walletdb.ErrKeyRequired
+	}
+	_, child, found, err := b.tx.lookup(b.id, key)
+	if err != nil {
+		return err
+	}
+	if found && child.Valid {
+		return walletdb.ErrIncompatibleValue
+	}
+	// A nil slice is bound as NULL, which is reserved for nested buckets.
+	if value == nil {
+		value = []byte{}
+	}
+	_, err = b.tx.sqlTx.Exec(`INSERT OR REPLACE INTO kv (bucket, key, value) VALUES (?, ?, ?)`,
+		b.id, key, value)
+	return convertErr(err)
+}
+
+// Get returns the value for the given key.  Returns nil if the key does
+// not exist in this bucket (or nested buckets).
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	value, _, _, err := b.tx.lookup(b.id, key)
+	if err != nil {
+		b.tx.setErr(err)
+		return nil
+	}
+	return value
+}
+
+// Delete removes the specified key from the bucket.  Deleting a key that does
+// not exist does not return an error.  Returns ErrTxNotWritable if attempted
+// against a read-only transaction.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	if !b.tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	_, child, found, err := b.tx.lookup(b.id, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	if child.Valid {
+		return walletdb.ErrIncompatibleValue
+	}
+	_, err = b.tx.sqlTx.Exec(`DELETE FROM kv WHERE bucket = ? AND key = ?`, b.id, key)
+	return convertErr(err)
+}
+
+func (b *bucket) ReadCursor() walletdb.ReadCursor {
+	return b.ReadWriteCursor()
+}
+
+// ReadWriteCursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	return &cursor{bucket: b}
+}
+
+// cursorPosition describes where a cursor is positioned relative to the keys
+// of its bucket.
+type cursorPosition int
+
+const (
+	beforeFirst cursorPosition = iota
+	atKey
+	afterLast
+)
+
+// cursor represents a cursor over key/value pairs and nested buckets of a
+// bucket.
+//
+// The cursor records the key it is positioned at and each movement queries for
+// the adjacent key, so, unlike the cursors of the bdb driver, modifications to
+// the bucket do not invalidate the cursor.
+type cursor struct {
+	bucket   *bucket
+	position cursorPosition
+	key      []byte
+}
+
+// Enforce cursor implements the walletdb Cursor interfaces.
+var _ walletdb.ReadWriteCursor = (*cursor)(nil)
+
+// move positions the cursor at the first key/value pair of the query, or at
+// the passed position when no pair is selected.
+func (c *cursor) move(query string, end cursorPosition, args ...interface{}) (key, value []byte) {
+	args = append([]interface{}{c.bucket.id}, args...)
+	var child sql.NullInt64
+	err := c.bucket.tx.sqlTx.QueryRow(`SELECT key, value, child FROM kv WHERE bucket = ? `+query,
+		args...).Scan(&key, &value, &child)
+	switch {
+	case err == sql.ErrNoRows:
+		c.position = end
+		return nil, nil
+	case err != nil:
+		c.bucket.tx.setErr(err)
+		return nil, nil
+	}
+	if !child.Valid && value == nil {
+		value = []byte{}
+	}
+	c.position = atKey
+	c.key = key
+	return key, value
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor. Returns ErrTxNotWritable if attempted on a read-only
+// transaction, or ErrIncompatibleValue if attempted when the cursor points to a
+// nested bucket.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Delete() error {
+	if !c.bucket.tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	if c.position != atKey {
+		return nil
+	}
+	return c.bucket.Delete(c.key)
+}
+
+// First positions the cursor at the first key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) First() (key, value []byte) {
+	return c.move(`ORDER BY key ASC LIMIT 1`, afterLast)
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Last() (key, value []byte) {
+	return c.move(`ORDER BY key DESC LIMIT 1`, beforeFirst)
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Next() (key, value []byte) {
+	switch c.position {
+	case beforeFirst:
+		return c.First()
+	case afterLast:
+		return nil, nil
+	}
+	return c.move(`AND key > ? ORDER BY key ASC LIMIT 1`, afterLast, c.key)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Prev() (key, value []byte) {
+	switch c.position {
+	case beforeFirst:
+		return nil, nil
+	case afterLast:
+		return c.Last()
+	}
+	return c.move(`AND key < ? ORDER BY key DESC LIMIT 1`, beforeFirst, c.key)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not exist,
+// the cursor is moved to the next key after seek. Returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	if seek == nil {
+		seek = []byte{}
+	}
+	return c.move(`AND key >= ? ORDER BY key ASC LIMIT 1`, afterLast, seek)
+}
+
+// db represents a collection of namespaces which are persisted and implements
+// the walletdb.Db interface.  All database access is performed through
+// transactions which are obtained through the specific Namespace.
+type db struct {
+	// rw is limited to a single connection and begins transactions with an
+	// immediate lock, so at most one read-write transaction is open at a
+	// time.  Read-only transactions use the connections of ro and are not
+	// blocked by the writer.
+	rw   *sql.DB
+	ro   *sql.DB
+	path string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Enforce db implements the walletdb.Db and walletdb.Compacter interfaces.
+var _ walletdb.DB = (*db)(nil)
+var _ walletdb.Compacter = (*db)(nil)
+
+func (db *db) beginTx(writable bool) (*transaction, error) {
+	db.mu.Lock()
+	closed := db.closed
+	db.mu.Unlock()
+	if closed {
+		return nil, walletdb.ErrDbNotOpen
+	}
+
+	pool := db.ro
+	if writable {
+		pool = db.rw
+	}
+	sqlTx, err := pool.Begin()
+	if err != nil {
+		return nil, convertErr(err)
+	}
+	return &transaction{sqlTx: sqlTx, db: db, writable: writable}, nil
+}
+
+func (db *db) BeginReadTx() (walletdb.ReadTx, error) {
+	return db.beginTx(false)
+}
+
+func (db *db) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	return db.beginTx(true)
+}
+
+// Copy writes a copy of the database to the provided writer.  The copy is
+// created with VACUUM INTO in a temporary file next to the database, which
+// does not block writers.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	db.mu.Lock()
+	closed := db.closed
+	db.mu.Unlock()
+	if closed {
+		return walletdb.ErrDbNotOpen
+	}
+
+	// VACUUM INTO requires the destination file to not exist or be empty.
+	f, err := ioutil.TempFile(filepath.Dir(db.path), filepath.Base(db.path)+".copy")
+	if err != nil {
+		return err
+	}
+	copyPath := f.Name()
+	defer os.Remove(copyPath)
+	defer f.Close()
+
+	_, err = db.ro.Exec(`VACUUM INTO ?`, copyPath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Close cleanly shuts down the database and syncs all data.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Close() error {
+	db.mu.Lock()
+	db.closed = true
+	db.mu.Unlock()
+	err := db.ro.Close()
+	if rwErr := db.rw.Close(); err == nil {
+		err = rwErr
+	}
+	return err
+}
+
+// Compact rebuilds the database file without unused pages and truncates the
+// write-ahead log.  Compaction waits for an open read-write transaction to
+// finish and new read-write transactions block until it completes.
+//
+// This function is part of the walletdb.Compacter interface implementation.
+func (db *db) Compact() error {
+	db.mu.Lock()
+	closed := db.closed
+	db.mu.Unlock()
+	if closed {
+		return walletdb.ErrDbNotOpen
+	}
+
+	_, err := db.rw.Exec(`VACUUM`)
+	if err != nil {
+		return err
+	}
+	_, err = db.rw.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`)
+	return err
+}
+
+// filesExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// openDB opens the database at the provided path.  walletdb.ErrDbDoesNotExist
+// is returned if the database doesn't exist and the create flag is not set.
+func openDB(dbPath string, create bool) (walletdb.DB, error) {
+	if !create && !fileExists(dbPath) {
+		return nil, walletdb.ErrDbDoesNotExist
+	}
+
+	rw, err := sql.Open("sqlite3", dbPath+"?_txlock=immediate&_busy_timeout="+
+		busyTimeout+"&_journal_mode=WAL&_synchronous=FULL")
+	if err != nil {
+		return nil, err
+	}
+	rw.SetMaxOpenConns(1)
+	// Creating the schema opens the first connection, which creates the
+	// database file and switches it to write-ahead logging before any
+	// read-only connection is opened.
+	_, err = rw.Exec(schema)
+	if err != nil {
+		rw.Close()
+		return nil, err
+	}
+	err = os.Chmod(dbPath, 0600)
+	if err != nil {
+		rw.Close()
+		return nil, err
+	}
+
+	ro, err := sql.Open("sqlite3", dbPath+"?_busy_timeout="+busyTimeout)
+	if err != nil {
+		rw.Close()
+		return nil, err
+	}
+	return &db{rw: rw, ro: ro, path: dbPath}, nil
+}
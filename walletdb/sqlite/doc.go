@@ -0,0 +1,40 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package sqlite implements an instance of walletdb that uses SQLite for the
+backing datastore.
+
+Unlike the bolt database used by the bdb driver, which holds an exclusive lock
+on the database file, the SQLite database is opened in write-ahead logging
+mode.  Other processes may read the database while the wallet is running, and
+read transactions do not block, or get blocked by, the single writer.  Copies
+of the database are made online with VACUUM INTO.
+
+Buckets are stored in a single key/value table keyed by the ID of the
+containing bucket.  Nested buckets are rows without a value which reference the
+ID of the nested bucket.
+
+# Usage
+
+This package is only a driver to the walletdb package and provides the database
+type of "sqlite".  The driver requires cgo, and is only built with the sqlite
+build tag:
+
+	go build -tags sqlite
+
+The only parameter the Open and Create functions take is the database path as
+a string:
+
+	db, err := walletdb.Open("sqlite", "path/to/database.db")
+	if err != nil {
+		// Handle error
+	}
+
+	db, err := walletdb.Create("sqlite", "path/to/database.db")
+	if err != nil {
+		// Handle error
+	}
+*/
+package sqlite
@@ -0,0 +1,366 @@
+// Copyright (c) 2018-2020 The Hc developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build sqlite
+// +build sqlite
+
+package sqlite_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HcashOrg/hcwallet/walletdb"
+	_ "github.com/HcashOrg/hcwallet/walletdb/sqlite"
+)
+
+// dbType is the database type name for this driver.
+const dbType = "sqlite"
+
+// TestCreateOpen ensures that a database which does not exist is not created
+// by Open, and that data written to a created database is readable after
+// reopening it.
+func TestCreateOpen(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "sqlitetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	dbPath := filepath.Join(tmpDir, "wallet.db")
+
+	_, err = walletdb.Open(dbType, dbPath)
+	if err != walletdb.ErrDbDoesNotExist {
+		t.Fatalf("Open: got error %v, want %v", err, walletdb.ErrDbDoesNotExist)
+	}
+
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		db.Close()
+		t.Fatalf("Update: %v", err)
+	}
+	err = db.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	_, err = db.BeginReadTx()
+	if err != walletdb.ErrDbNotOpen {
+		t.Fatalf("BeginReadTx after Close: got error %v, want %v", err,
+			walletdb.ErrDbNotOpen)
+	}
+
+	db, err = walletdb.Open(dbType, dbPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		b := tx.ReadBucket([]byte("bucket"))
+		if b == nil {
+			t.Fatal("missing bucket after reopening database")
+		}
+		if v := b.Get([]byte("key")); !bytes.Equal(v, []byte("value")) {
+			t.Errorf("Get: got %q, want %q", v, "value")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestInterface exercises the bucket and cursor semantics which the wallet
+// relies on from the bdb driver.
+func TestInterface(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "sqlitetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := walletdb.Create(dbType, filepath.Join(tmpDir, "wallet.db"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer db.Close()
+
+	bucketKey := []byte("bucket")
+	nestedKey := []byte("nested")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(bucketKey); err != walletdb.ErrBucketExists {
+			t.Errorf("CreateTopLevelBucket: got error %v, want %v", err,
+				walletdb.ErrBucketExists)
+		}
+		if _, err := b.CreateBucket(nil); err != walletdb.ErrBucketNameRequired {
+			t.Errorf("CreateBucket: got error %v, want %v", err,
+				walletdb.ErrBucketNameRequired)
+		}
+		if err := b.Put(nil, []byte("v")); err != walletdb.ErrKeyRequired {
+			t.Errorf("Put: got error %v, want %v", err, walletdb.ErrKeyRequired)
+		}
+
+		for _, k := range []string{"a", "c", "e"} {
+			if err := b.Put([]byte(k), []byte(k+"v")); err != nil {
+				return err
+			}
+		}
+		if err := b.Put([]byte("empty"), nil); err != nil {
+			return err
+		}
+		nested, err := b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		deeper, err := nested.CreateBucket([]byte("deeper"))
+		if err != nil {
+			return err
+		}
+		if err := deeper.Put([]byte("k"), []byte("v")); err != nil {
+			return err
+		}
+		if _, err := b.CreateBucketIfNotExists(nestedKey); err != nil {
+			t.Errorf("CreateBucketIfNotExists: %v", err)
+		}
+		if _, err := b.CreateBucket([]byte("a")); err != walletdb.ErrIncompatibleValue {
+			t.Errorf("CreateBucket: got error %v, want %v", err,
+				walletdb.ErrIncompatibleValue)
+		}
+		if err := b.Put(nestedKey, []byte("v")); err != walletdb.ErrIncompatibleValue {
+			t.Errorf("Put: got error %v, want %v", err, walletdb.ErrIncompatibleValue)
+		}
+		if err := b.Delete(nestedKey); err != walletdb.ErrIncompatibleValue {
+			t.Errorf("Delete: got error %v, want %v", err, walletdb.ErrIncompatibleValue)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		b := tx.ReadBucket(bucketKey)
+		if v := b.Get([]byte("empty")); v == nil || len(v) != 0 {
+			t.Errorf("Get: got %#v for empty value, want empty non-nil slice", v)
+		}
+		if v := b.Get([]byte("missing")); v != nil {
+			t.Errorf("Get: got %#v for missing key, want nil", v)
+		}
+		if v := b.Get(nestedKey); v != nil {
+			t.Errorf("Get: got %#v for nested bucket, want nil", v)
+		}
+		if b.NestedReadBucket([]byte("a")) != nil {
+			t.Error("NestedReadBucket returned a bucket for a value")
+		}
+
+		var keys []string
+		err := b.ForEach(func(k, v []byte) error {
+			keys = append(keys, string(k))
+			if string(k) == string(nestedKey) && v != nil {
+				t.Errorf("ForEach: got %#v for nested bucket, want nil", v)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		want := []string{"a", "c", "e", "empty", "nested"}
+		if len(keys) != len(want) {
+			t.Fatalf("ForEach: got keys %q, want %q", keys, want)
+		}
+		for i := range keys {
+			if keys[i] != want[i] {
+				t.Fatalf("ForEach: got keys %q, want %q", keys, want)
+			}
+		}
+
+		c := b.ReadCursor()
+		if k, _ := c.Seek([]byte("b")); string(k) != "c" {
+			t.Errorf("Seek: got key %q, want %q", k, "c")
+		}
+		if k, _ := c.Prev(); string(k) != "a" {
+			t.Errorf("Prev: got key %q, want %q", k, "a")
+		}
+		if k, _ := c.Prev(); k != nil {
+			t.Errorf("Prev: got key %q, want nil", k)
+		}
+		if k, v := c.Last(); string(k) != "nested" || v != nil {
+			t.Errorf("Last: got %q/%#v, want nested bucket", k, v)
+		}
+		if k, _ := c.Next(); k != nil {
+			t.Errorf("Next: got key %q, want nil", k)
+		}
+
+		if err := b.(walletdb.ReadWriteBucket).Put([]byte("x"), nil); err != walletdb.ErrTxNotWritable {
+			t.Errorf("Put: got error %v, want %v", err, walletdb.ErrTxNotWritable)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+
+	// Deleting with a cursor must not invalidate it, and deleting a nested
+	// bucket must remove the buckets nested within it.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b := tx.ReadWriteBucket(bucketKey)
+		c := b.ReadWriteCursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if string(k) == "a" || string(k) == "c" {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+		if k, _ := c.First(); string(k) != "e" {
+			t.Errorf("First: got key %q after deletes, want %q", k, "e")
+		}
+		return b.DeleteNestedBucket(nestedKey)
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		b := tx.ReadBucket(bucketKey)
+		if b.NestedReadBucket(nestedKey) != nil {
+			t.Error("nested bucket was not deleted")
+		}
+		if b.Get([]byte("a")) != nil || b.Get([]byte("c")) != nil {
+			t.Error("keys deleted by the cursor still exist")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+}
+
+// TestConcurrentReader ensures that read transactions are not blocked by, and
+// do not observe the changes of, an open read-write transaction.
+func TestConcurrentReader(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "sqlitetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := walletdb.Create(dbType, filepath.Join(tmpDir, "wallet.db"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer db.Close()
+
+	bucketKey := []byte("bucket")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket(bucketKey)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	rwtx, err := db.BeginReadWriteTx()
+	if err != nil {
+		t.Fatalf("BeginReadWriteTx: %v", err)
+	}
+	err = rwtx.ReadWriteBucket(bucketKey).Put([]byte("key"), []byte("value"))
+	if err != nil {
+		rwtx.Rollback()
+		t.Fatalf("Put: %v", err)
+	}
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		if v := tx.ReadBucket(bucketKey).Get([]byte("key")); v != nil {
+			t.Errorf("reader observed uncommitted value %q", v)
+		}
+		return nil
+	})
+	if err != nil {
+		rwtx.Rollback()
+		t.Fatalf("View: %v", err)
+	}
+	err = rwtx.Commit()
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+// TestCopyCompact ensures that a copy of the database is a readable database
+// and that compaction preserves the contents of the database.
+func TestCopyCompact(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "sqlitetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := walletdb.Create(dbType, filepath.Join(tmpDir, "wallet.db"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer db.Close()
+
+	bucketKey := []byte("bucket")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	err = walletdb.Compact(db)
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = db.Copy(&buf)
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	copyPath := filepath.Join(tmpDir, "copy.db")
+	err = ioutil.WriteFile(copyPath, buf.Bytes(), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyDB, err := walletdb.Open(dbType, copyPath)
+	if err != nil {
+		t.Fatalf("Open copy: %v", err)
+	}
+	defer copyDB.Close()
+
+	for _, d := range []walletdb.DB{db, copyDB} {
+		err = walletdb.View(d, func(tx walletdb.ReadTx) error {
+			b := tx.ReadBucket(bucketKey)
+			if b == nil {
+				t.Fatal("missing bucket")
+			}
+			if v := b.Get([]byte("key")); !bytes.Equal(v, []byte("value")) {
+				t.Errorf("Get: got %q, want %q", v, "value")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("View: %v", err)
+		}
+	}
+}
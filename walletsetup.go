@@ -8,11 +8,13 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/hcutil/hdkeychain"
@@ -20,8 +22,11 @@ import (
 	"github.com/HcashOrg/hcwallet/internal/prompt"
 	"github.com/HcashOrg/hcwallet/loader"
 	"github.com/HcashOrg/hcwallet/wallet"
+	"github.com/HcashOrg/hcwallet/wallet/txauthor"
+	"github.com/HcashOrg/hcwallet/wallet/udb"
 	"github.com/HcashOrg/hcwallet/walletdb"
 	_ "github.com/HcashOrg/hcwallet/walletdb/bdb"
+	"github.com/HcashOrg/hcwallet/walletdb/edb"
 	"github.com/HcashOrg/hcwallet/walletseed"
 )
 
@@ -43,6 +48,12 @@ func networkDir(dataDir string, chainParams *chaincfg.Params) string {
 	return filepath.Join(dataDir, netname)
 }
 
+// namedWalletAppDataDir returns the application data directory of a named
+// wallet loaded alongside the default wallet.
+func namedWalletAppDataDir(appDataDir, name string) string {
+	return filepath.Join(appDataDir, "wallets", name)
+}
+
 // createWallet prompts the user for information needed to generate a new wallet
 // and generates the wallet accordingly.  The new wallet will reside at the
 // provided path. The bool passed back gives whether or not the wallet was
@@ -58,26 +69,164 @@ func createWallet(cfg *config) error {
 		TicketFee:      cfg.TicketFee.ToCoin(),
 	}
 	loader := loader.NewLoader(activeNet.Params, dbDir, stakeOptions,
-		cfg.AddrIdxScanLen, cfg.AllowHighFees, cfg.RelayFee.ToCoin(), cfg.EnableOmni)
+		cfg.GapLimit, cfg.AllowHighFees, txauthor.TxLimits{
+			MaxInputs:        cfg.MaxTxInputs,
+			MaxSerializeSize: cfg.MaxTxSize,
+		}, cfg.SplitTxs,
+		cfg.RelayFee.ToCoin(), cfg.EnableOmni)
+	loader.SetDatabaseEncryption(cfg.EncryptDB)
+	loader.SetDatabaseDriver(cfg.DBDriver)
+	loader.SetGapLimitLookahead(cfg.GapLimitLookahead)
+
+	var export *walletExport
+	if cfg.MigrateFrom != "" {
+		var err error
+		export, err = readWalletExport(cfg.MigrateFrom)
+		if err != nil {
+			return err
+		}
+	}
 
 	reader := bufio.NewReader(os.Stdin)
-	privPass, pubPass, seed, err := prompt.Setup(reader,
+	privPass, pubPass, err := prompt.Passphrases(reader,
 		[]byte(wallet.InsecurePubPassphrase), []byte(cfg.createPass), []byte(cfg.WalletPass))
 	if err != nil {
 		return err
 	}
+	var seed []byte
+	var restored bool
+	if export != nil && export.seed != nil {
+		seed, restored = export.seed, true
+	} else if cfg.offlineCmds.Create.FromMnemonic {
+		seed, err = prompt.ExistingSeed(reader)
+		if err != nil {
+			return err
+		}
+		restored = true
+	} else {
+		seed, restored, err = prompt.Seed(reader)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Wallets with newly generated seeds have no history before they are
+	// created, while restored wallets are synced from the birthday of the
+	// seed, if known.
+	birthday := &udb.Birthday{Time: time.Now(), Height: -1}
+	if restored {
+		birthday, err = prompt.Birthday(reader)
+		if err != nil {
+			return err
+		}
+	}
 
 	fmt.Println("Creating the wallet...")
-	_, err = loader.CreateNewWallet(pubPass, privPass, seed)
+	w, err := loader.CreateNewWallet(pubPass, privPass, seed, birthday)
 	if err != nil {
 		return err
 	}
 
+	if export != nil && len(export.accounts) != 0 {
+		fmt.Println("Importing account metadata...")
+		err = w.Unlock(privPass, nil)
+		if err != nil {
+			return err
+		}
+		err = w.ImportAccountMetadata(export.accounts)
+		w.Lock()
+		if err != nil {
+			return err
+		}
+	}
+
 	fmt.Println("The wallet has been created successfully.")
 
 	return nil
 }
 
+// walletExport is a wallet seed and account metadata read from the export of
+// another HD wallet.
+type walletExport struct {
+	seed     []byte
+	accounts []wallet.MigratedAccount
+}
+
+// walletExportJSON is the JSON encoding of a wallet export file.  Seeds may be
+// hex or PGP word list mnemonics as accepted by dcrwallet, and omitted or
+// negative last used indexes describe branches without used addresses.
+//
+//	{
+//	  "seed": "<hex or mnemonic>",
+//	  "accounts": [
+//	    {"account": 0, "name": "default", "lastusedexternal": 25, "lastusedinternal": 10}
+//	  ]
+//	}
+type walletExportJSON struct {
+	Seed     string `json:"seed"`
+	Accounts []struct {
+		Account          uint32 `json:"account"`
+		Name             string `json:"name"`
+		LastUsedExternal *int64 `json:"lastusedexternal"`
+		LastUsedInternal *int64 `json:"lastusedinternal"`
+	} `json:"accounts"`
+}
+
+// readWalletExport reads and validates the dcrwallet or btcwallet style export
+// of a wallet seed and account metadata at path.  Only account names and
+// address indexes are migrated, as keys are derived for this network's coin
+// type.
+func readWalletExport(path string) (*walletExport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var j walletExportJSON
+	err = json.Unmarshal(b, &j)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse wallet export %s: %v", path, err)
+	}
+
+	export := new(walletExport)
+	if seedStr := strings.Join(strings.Fields(j.Seed), " "); seedStr != "" {
+		seed, err := walletseed.DecodeUserInput(seedStr)
+		if err != nil || len(seed) < hdkeychain.MinSeedBytes ||
+			len(seed) > hdkeychain.MaxSeedBytes {
+			return nil, fmt.Errorf("invalid seed in wallet export %s", path)
+		}
+		export.seed = seed
+	}
+
+	lastUsed := func(idx *int64) (uint32, error) {
+		switch {
+		case idx == nil || *idx < 0:
+			return ^uint32(0), nil
+		case *idx >= hdkeychain.HardenedKeyStart:
+			return 0, fmt.Errorf("invalid address index %d in wallet "+
+				"export %s", *idx, path)
+		}
+		return uint32(*idx), nil
+	}
+	export.accounts = make([]wallet.MigratedAccount, 0, len(j.Accounts))
+	for _, a := range j.Accounts {
+		external, err := lastUsed(a.LastUsedExternal)
+		if err != nil {
+			return nil, err
+		}
+		internal, err := lastUsed(a.LastUsedInternal)
+		if err != nil {
+			return nil, err
+		}
+		export.accounts = append(export.accounts, wallet.MigratedAccount{
+			Number:           a.Account,
+			Name:             a.Name,
+			LastUsedExternal: external,
+			LastUsedInternal: internal,
+		})
+	}
+	return export, nil
+}
+
 // createSimulationWallet is intended to be called from the rpcclient
 // and used to create a wallet for actors involved in simulations.
 func createSimulationWallet(cfg *config) error {
@@ -107,8 +256,8 @@ func createSimulationWallet(cfg *config) error {
 	dbPath := filepath.Join(netDir, walletDbName)
 	fmt.Println("Creating the wallet...")
 
-	// Create the wallet database backed by bolt db.
-	db, err := walletdb.Create("bdb", dbPath)
+	// Create the wallet database using the configured driver.
+	db, err := walletdb.Create(cfg.DBDriver, dbPath)
 	if err != nil {
 		return err
 	}
@@ -124,6 +273,69 @@ func createSimulationWallet(cfg *config) error {
 	return nil
 }
 
+// checkWalletDB verifies the integrity of the wallet database at dbPath and
+// compacts it when no inconsistencies are found.  Inconsistencies are printed
+// and leave the database unmodified.  Encrypted databases are opened with the
+// public passphrase.
+func checkWalletDB(driver, dbPath string, pubPass []byte) error {
+	db, err := walletdb.Open(driver, dbPath)
+	if err != nil {
+		return err
+	}
+	encrypted, err := edb.IsEncrypted(db)
+	if err != nil {
+		db.Close()
+		return err
+	}
+	if encrypted {
+		encDB, err := edb.Open(db, pubPass)
+		if err != nil {
+			db.Close()
+			return err
+		}
+		db = encDB
+	}
+	defer db.Close()
+
+	fmt.Println("Checking the wallet database...")
+	issues, err := wallet.VerifyDB(db)
+	if err != nil {
+		return err
+	}
+	if len(issues) != 0 {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		return fmt.Errorf("found %d inconsistencies, the database was not "+
+			"compacted", len(issues))
+	}
+
+	sizeBefore, err := fileSize(dbPath)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Compacting the wallet database...")
+	err = walletdb.Compact(db)
+	if err != nil {
+		return err
+	}
+	sizeAfter, err := fileSize(dbPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The wallet database is consistent and was compacted from "+
+		"%d to %d bytes.\n", sizeBefore, sizeAfter)
+	return nil
+}
+
+func fileSize(path string) (int64, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
+
 // promptHDPublicKey prompts the user for an extended public key.
 func promptHDPublicKey(reader *bufio.Reader) (string, error) {
 	for {
@@ -162,11 +374,24 @@ func createWatchingOnlyWallet(cfg *config) error {
 	dbPath := filepath.Join(netDir, walletDbName)
 	fmt.Println("Creating the wallet...")
 
-	// Create the wallet database backed by bolt db.
-	db, err := walletdb.Create("bdb", dbPath)
+	if cfg.EncryptDB && string(pubPass) == wallet.InsecurePubPassphrase {
+		return loader.ErrInsecureDBEncryption
+	}
+
+	// Create the wallet database using the configured driver.
+	db, err := walletdb.Create(cfg.DBDriver, dbPath)
 	if err != nil {
 		return err
 	}
+	if cfg.EncryptDB {
+		encDB, err := edb.Create(db, pubPass)
+		if err != nil {
+			db.Close()
+			os.Remove(dbPath)
+			return err
+		}
+		db = encDB
+	}
 	defer db.Close()
 
 	err = wallet.CreateWatchOnly(db, pubKeyString, pubPass, activeNet.Params)